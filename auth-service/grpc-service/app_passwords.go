@@ -0,0 +1,158 @@
+package auth_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	"2025_2_a4code/internal/lib/metrics"
+	"2025_2_a4code/internal/lib/session"
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "2025_2_a4code/auth-service/pkg/authproto"
+	"2025_2_a4code/internal/usecase/profile"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) getProfileID(ctx context.Context) (int64, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return 0, status.Error(codes.Unauthenticated, "metadata is not provided")
+	}
+
+	tokens := md.Get("authorization")
+	if len(tokens) == 0 {
+		return 0, status.Error(codes.Unauthenticated, "authorization token is not provided")
+	}
+
+	tokenString := strings.TrimPrefix(tokens[0], "Bearer ")
+	return session.GetProfileIDFromTokenString(tokenString, s.JWTSecret, "access")
+}
+
+func (s *Server) CreateAppPassword(ctx context.Context, req *pb.CreateAppPasswordRequest) (*pb.CreateAppPasswordResponse, error) {
+	const op = "authservice.CreateAppPassword"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle /auth/app-passwords create")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	appPassword, password, err := s.profileUCase.CreateAppPassword(ctx, profileID, req.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidAppPassword):
+			metrics.BusinessErrorsTotal.WithLabelValues("auth-service", "create_app_password", "validation_error").Inc()
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, domain.ErrTooManyAppPasswords):
+			metrics.BusinessErrorsTotal.WithLabelValues("auth-service", "create_app_password", "limit_reached").Inc()
+			return nil, status.Error(codes.InvalidArgument, "too many app passwords")
+		}
+		log.Error(op + ": failed to create app password: " + err.Error())
+		metrics.BusinessErrorsTotal.WithLabelValues("auth-service", "create_app_password", "internal_error").Inc()
+		return nil, status.Error(codes.Internal, "could not create app password")
+	}
+
+	return &pb.CreateAppPasswordResponse{
+		AppPassword: domainAppPasswordToProto(appPassword),
+		Password:    password,
+	}, nil
+}
+
+func (s *Server) ListAppPasswords(ctx context.Context, req *pb.ListAppPasswordsRequest) (*pb.ListAppPasswordsResponse, error) {
+	const op = "authservice.ListAppPasswords"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle /auth/app-passwords list")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	appPasswords, err := s.profileUCase.ListAppPasswords(ctx, profileID)
+	if err != nil {
+		log.Error(op + ": failed to list app passwords: " + err.Error())
+		metrics.BusinessErrorsTotal.WithLabelValues("auth-service", "list_app_passwords", "internal_error").Inc()
+		return nil, status.Error(codes.Internal, "could not list app passwords")
+	}
+
+	resp := &pb.ListAppPasswordsResponse{AppPasswords: make([]*pb.AppPassword, 0, len(appPasswords))}
+	for _, appPassword := range appPasswords {
+		resp.AppPasswords = append(resp.AppPasswords, domainAppPasswordToProto(appPassword))
+	}
+	return resp, nil
+}
+
+func (s *Server) RevokeAppPassword(ctx context.Context, req *pb.RevokeAppPasswordRequest) (*pb.RevokeAppPasswordResponse, error) {
+	const op = "authservice.RevokeAppPassword"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle /auth/app-passwords revoke")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	appPasswordID, err := strconv.ParseInt(req.Id, 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid app password id")
+	}
+
+	if err := s.profileUCase.RevokeAppPassword(ctx, profileID, appPasswordID); err != nil {
+		if errors.Is(err, domain.ErrAppPasswordNotFound) {
+			return nil, status.Error(codes.NotFound, "app password not found")
+		}
+		log.Error(op + ": failed to revoke app password: " + err.Error())
+		metrics.BusinessErrorsTotal.WithLabelValues("auth-service", "revoke_app_password", "internal_error").Inc()
+		return nil, status.Error(codes.Internal, "could not revoke app password")
+	}
+
+	return &pb.RevokeAppPasswordResponse{}, nil
+}
+
+// AuthenticateAppPassword вызывают серверы IMAP, POP3 и отправки почты. Неверный логин или пароль
+// возвращается как Unauthenticated, остальные ошибки - временные, клиенту стоит повторить попытку
+func (s *Server) AuthenticateAppPassword(ctx context.Context, req *pb.AuthenticateAppPasswordRequest) (*pb.AuthenticateAppPasswordResponse, error) {
+	const op = "authservice.AuthenticateAppPassword"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle app password authentication for " + req.Protocol)
+
+	if req.Login == "" || req.Password == "" {
+		metrics.AuthAppPasswordAttempts.WithLabelValues(req.Protocol, "error").Inc()
+		return nil, status.Error(codes.InvalidArgument, "login and password are required")
+	}
+
+	profileID, err := s.profileUCase.AuthenticateAppPassword(ctx, strings.TrimSpace(req.Login), req.Password, req.RemoteIp)
+	if err != nil {
+		metrics.AuthAppPasswordAttempts.WithLabelValues(req.Protocol, "error").Inc()
+		if errors.Is(err, profile.ErrUserNotFound) || errors.Is(err, profile.ErrWrongPassword) {
+			log.Debug(op + ": authentication failed: " + err.Error())
+			return nil, status.Error(codes.Unauthenticated, "invalid login or password")
+		}
+		log.Error(op + ": failed to check app password: " + err.Error())
+		metrics.BusinessErrorsTotal.WithLabelValues("auth-service", "authenticate_app_password", "internal_error").Inc()
+		return nil, status.Error(codes.Internal, "could not check app password")
+	}
+
+	metrics.AuthAppPasswordAttempts.WithLabelValues(req.Protocol, "success").Inc()
+	return &pb.AuthenticateAppPasswordResponse{ProfileId: profileID}, nil
+}
+
+func domainAppPasswordToProto(appPassword domain.AppPassword) *pb.AppPassword {
+	result := &pb.AppPassword{
+		Id:         strconv.FormatInt(appPassword.ID, 10),
+		Name:       appPassword.Name,
+		CreatedAt:  appPassword.CreatedAt.Format(time.RFC3339),
+		LastUsedIp: appPassword.LastUsedIP,
+	}
+	if !appPassword.LastUsedAt.IsZero() {
+		result.LastUsedAt = appPassword.LastUsedAt.Format(time.RFC3339)
+	}
+	return result
+}
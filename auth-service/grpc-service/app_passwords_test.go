@@ -0,0 +1,228 @@
+package auth_service
+
+import (
+	"2025_2_a4code/auth-service/pkg/authproto"
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/usecase/profile"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func createTestContextWithToken(server *Server, userID int64) context.Context {
+	token, err := server.generateAccessToken(userID)
+	if err != nil {
+		panic(err)
+	}
+	md := metadata.New(map[string]string{"authorization": "Bearer " + token})
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+func TestServer_CreateAppPassword(t *testing.T) {
+	createdAt := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		unauthorized bool
+		mockSetup    func(mockProfile *MockProfileUsecase)
+		expectedCode codes.Code
+	}{
+		{
+			name: "Success",
+			mockSetup: func(mockProfile *MockProfileUsecase) {
+				mockProfile.On("CreateAppPassword", mock.Anything, int64(1), "Thunderbird").
+					Return(domain.AppPassword{ID: 3, Name: "Thunderbird", CreatedAt: createdAt}, "abcd efgh ijkl mnop", nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Unauthorized",
+			unauthorized: true,
+			mockSetup:    func(mockProfile *MockProfileUsecase) {},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name: "InvalidName",
+			mockSetup: func(mockProfile *MockProfileUsecase) {
+				mockProfile.On("CreateAppPassword", mock.Anything, int64(1), "Thunderbird").
+					Return(domain.AppPassword{}, "", domain.ErrInvalidAppPassword)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "LimitReached",
+			mockSetup: func(mockProfile *MockProfileUsecase) {
+				mockProfile.On("CreateAppPassword", mock.Anything, int64(1), "Thunderbird").
+					Return(domain.AppPassword{}, "", domain.ErrTooManyAppPasswords)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "UsecaseError",
+			mockSetup: func(mockProfile *MockProfileUsecase) {
+				mockProfile.On("CreateAppPassword", mock.Anything, int64(1), "Thunderbird").
+					Return(domain.AppPassword{}, "", errors.New("db down"))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, mockProfile := setupTestServer()
+			tt.mockSetup(mockProfile)
+
+			ctx := createTestContextWithToken(server, 1)
+			if tt.unauthorized {
+				ctx = createTestContext()
+			}
+			resp, err := server.CreateAppPassword(ctx, &authproto.CreateAppPasswordRequest{Name: "Thunderbird"})
+
+			if tt.expectedCode != codes.OK {
+				assert.Equal(t, tt.expectedCode, status.Code(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "abcd efgh ijkl mnop", resp.Password)
+			assert.Equal(t, "3", resp.AppPassword.Id)
+			assert.Equal(t, "2025-09-01T12:00:00Z", resp.AppPassword.CreatedAt)
+			assert.Empty(t, resp.AppPassword.LastUsedAt)
+			mockProfile.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_ListAppPasswords(t *testing.T) {
+	server, mockProfile := setupTestServer()
+	createdAt := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	usedAt := time.Date(2025, 9, 2, 8, 30, 0, 0, time.UTC)
+	mockProfile.On("ListAppPasswords", mock.Anything, int64(1)).Return([]domain.AppPassword{
+		{ID: 4, Name: "Phone", CreatedAt: createdAt, LastUsedAt: usedAt, LastUsedIP: "203.0.113.7"},
+		{ID: 3, Name: "Laptop", CreatedAt: createdAt},
+	}, nil)
+
+	resp, err := server.ListAppPasswords(createTestContextWithToken(server, 1), &authproto.ListAppPasswordsRequest{})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.AppPasswords, 2)
+	assert.Equal(t, "2025-09-02T08:30:00Z", resp.AppPasswords[0].LastUsedAt)
+	assert.Equal(t, "203.0.113.7", resp.AppPasswords[0].LastUsedIp)
+	assert.Empty(t, resp.AppPasswords[1].LastUsedAt)
+	mockProfile.AssertExpectations(t)
+}
+
+func TestServer_RevokeAppPassword(t *testing.T) {
+	tests := []struct {
+		name         string
+		id           string
+		mockSetup    func(mockProfile *MockProfileUsecase)
+		expectedCode codes.Code
+	}{
+		{
+			name: "Success",
+			id:   "3",
+			mockSetup: func(mockProfile *MockProfileUsecase) {
+				mockProfile.On("RevokeAppPassword", mock.Anything, int64(1), int64(3)).Return(nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "InvalidID",
+			id:           "abc",
+			mockSetup:    func(mockProfile *MockProfileUsecase) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "NotFound",
+			id:   "3",
+			mockSetup: func(mockProfile *MockProfileUsecase) {
+				mockProfile.On("RevokeAppPassword", mock.Anything, int64(1), int64(3)).Return(domain.ErrAppPasswordNotFound)
+			},
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, mockProfile := setupTestServer()
+			tt.mockSetup(mockProfile)
+
+			_, err := server.RevokeAppPassword(createTestContextWithToken(server, 1), &authproto.RevokeAppPasswordRequest{Id: tt.id})
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			mockProfile.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_AuthenticateAppPassword(t *testing.T) {
+	tests := []struct {
+		name         string
+		request      *authproto.AuthenticateAppPasswordRequest
+		mockSetup    func(mockProfile *MockProfileUsecase)
+		expectedCode codes.Code
+	}{
+		{
+			name:    "Success",
+			request: &authproto.AuthenticateAppPasswordRequest{Login: " user ", Password: "abcd efgh ijkl mnop", RemoteIp: "203.0.113.7", Protocol: "imap"},
+			mockSetup: func(mockProfile *MockProfileUsecase) {
+				mockProfile.On("AuthenticateAppPassword", mock.Anything, "user", "abcd efgh ijkl mnop", "203.0.113.7").Return(int64(42), nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "EmptyPassword",
+			request:      &authproto.AuthenticateAppPasswordRequest{Login: "user", Protocol: "pop3"},
+			mockSetup:    func(mockProfile *MockProfileUsecase) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "WrongPassword",
+			request: &authproto.AuthenticateAppPasswordRequest{Login: "user", Password: "secret", Protocol: "submission"},
+			mockSetup: func(mockProfile *MockProfileUsecase) {
+				mockProfile.On("AuthenticateAppPassword", mock.Anything, "user", "secret", "").Return(int64(0), profile.ErrWrongPassword)
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:    "UnknownUser",
+			request: &authproto.AuthenticateAppPasswordRequest{Login: "user@gmail.com", Password: "secret", Protocol: "imap"},
+			mockSetup: func(mockProfile *MockProfileUsecase) {
+				mockProfile.On("AuthenticateAppPassword", mock.Anything, "user@gmail.com", "secret", "").Return(int64(0), profile.ErrUserNotFound)
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:    "UsecaseError",
+			request: &authproto.AuthenticateAppPasswordRequest{Login: "user", Password: "secret", Protocol: "imap"},
+			mockSetup: func(mockProfile *MockProfileUsecase) {
+				mockProfile.On("AuthenticateAppPassword", mock.Anything, "user", "secret", "").Return(int64(0), errors.New("db down"))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, mockProfile := setupTestServer()
+			tt.mockSetup(mockProfile)
+
+			resp, err := server.AuthenticateAppPassword(createTestContext(), tt.request)
+
+			if tt.expectedCode != codes.OK {
+				assert.Equal(t, tt.expectedCode, status.Code(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, int64(42), resp.ProfileId)
+			mockProfile.AssertExpectations(t)
+		})
+	}
+}
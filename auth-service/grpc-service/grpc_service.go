@@ -1,6 +1,7 @@
 package auth_service
 
 import (
+	"2025_2_a4code/internal/domain"
 	"2025_2_a4code/internal/http-server/middleware/logger"
 	"2025_2_a4code/internal/lib/metrics"
 	"2025_2_a4code/internal/lib/session"
@@ -27,6 +28,10 @@ type Server struct {
 type ProfileUsecase interface {
 	Login(ctx context.Context, req profile.LoginRequest) (int64, error)
 	Signup(ctx context.Context, SignupReq profile.SignupRequest) (int64, error)
+	CreateAppPassword(ctx context.Context, profileID int64, name string) (domain.AppPassword, string, error)
+	ListAppPasswords(ctx context.Context, profileID int64) ([]domain.AppPassword, error)
+	RevokeAppPassword(ctx context.Context, profileID, appPasswordID int64) error
+	AuthenticateAppPassword(ctx context.Context, login, password, remoteIP string) (int64, error)
 }
 
 func New(profileUCase ProfileUsecase, secret []byte) *Server {
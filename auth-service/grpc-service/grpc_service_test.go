@@ -2,6 +2,7 @@ package auth_service
 
 import (
 	"2025_2_a4code/auth-service/pkg/authproto"
+	"2025_2_a4code/internal/domain"
 	"2025_2_a4code/internal/lib/session"
 	"2025_2_a4code/internal/usecase/profile"
 	"context"
@@ -30,6 +31,26 @@ func (m *MockProfileUsecase) Signup(ctx context.Context, req profile.SignupReque
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockProfileUsecase) CreateAppPassword(ctx context.Context, profileID int64, name string) (domain.AppPassword, string, error) {
+	args := m.Called(ctx, profileID, name)
+	return args.Get(0).(domain.AppPassword), args.String(1), args.Error(2)
+}
+
+func (m *MockProfileUsecase) ListAppPasswords(ctx context.Context, profileID int64) ([]domain.AppPassword, error) {
+	args := m.Called(ctx, profileID)
+	return args.Get(0).([]domain.AppPassword), args.Error(1)
+}
+
+func (m *MockProfileUsecase) RevokeAppPassword(ctx context.Context, profileID, appPasswordID int64) error {
+	args := m.Called(ctx, profileID, appPasswordID)
+	return args.Error(0)
+}
+
+func (m *MockProfileUsecase) AuthenticateAppPassword(ctx context.Context, login, password, remoteIP string) (int64, error) {
+	args := m.Called(ctx, login, password, remoteIP)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func setupTestServer() (*Server, *MockProfileUsecase) {
 	mockProfileUsecase := &MockProfileUsecase{}
 	jwtSecret := []byte("test-secret-key-very-long-for-testing")
@@ -413,6 +413,453 @@ func (*LogoutResponse) Descriptor() ([]byte, []int) {
 	return file_auth_proto_rawDescGZIP(), []int{7}
 }
 
+type AppPassword struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name      string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CreatedAt string                 `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Пустые, если паролем еще не пользовались
+	LastUsedAt    string `protobuf:"bytes,4,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`
+	LastUsedIp    string `protobuf:"bytes,5,opt,name=last_used_ip,json=lastUsedIp,proto3" json:"last_used_ip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AppPassword) Reset() {
+	*x = AppPassword{}
+	mi := &file_auth_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AppPassword) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppPassword) ProtoMessage() {}
+
+func (x *AppPassword) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppPassword.ProtoReflect.Descriptor instead.
+func (*AppPassword) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AppPassword) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AppPassword) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AppPassword) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *AppPassword) GetLastUsedAt() string {
+	if x != nil {
+		return x.LastUsedAt
+	}
+	return ""
+}
+
+func (x *AppPassword) GetLastUsedIp() string {
+	if x != nil {
+		return x.LastUsedIp
+	}
+	return ""
+}
+
+type CreateAppPasswordRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAppPasswordRequest) Reset() {
+	*x = CreateAppPasswordRequest{}
+	mi := &file_auth_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAppPasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAppPasswordRequest) ProtoMessage() {}
+
+func (x *CreateAppPasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAppPasswordRequest.ProtoReflect.Descriptor instead.
+func (*CreateAppPasswordRequest) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CreateAppPasswordRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CreateAppPasswordResponse struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	AppPassword *AppPassword           `protobuf:"bytes,1,opt,name=app_password,json=appPassword,proto3" json:"app_password,omitempty"`
+	// Показывается один раз, сохраняется только хеш
+	Password      string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAppPasswordResponse) Reset() {
+	*x = CreateAppPasswordResponse{}
+	mi := &file_auth_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAppPasswordResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAppPasswordResponse) ProtoMessage() {}
+
+func (x *CreateAppPasswordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAppPasswordResponse.ProtoReflect.Descriptor instead.
+func (*CreateAppPasswordResponse) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreateAppPasswordResponse) GetAppPassword() *AppPassword {
+	if x != nil {
+		return x.AppPassword
+	}
+	return nil
+}
+
+func (x *CreateAppPasswordResponse) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type ListAppPasswordsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAppPasswordsRequest) Reset() {
+	*x = ListAppPasswordsRequest{}
+	mi := &file_auth_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAppPasswordsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAppPasswordsRequest) ProtoMessage() {}
+
+func (x *ListAppPasswordsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAppPasswordsRequest.ProtoReflect.Descriptor instead.
+func (*ListAppPasswordsRequest) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{11}
+}
+
+type ListAppPasswordsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AppPasswords  []*AppPassword         `protobuf:"bytes,1,rep,name=app_passwords,json=appPasswords,proto3" json:"app_passwords,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAppPasswordsResponse) Reset() {
+	*x = ListAppPasswordsResponse{}
+	mi := &file_auth_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAppPasswordsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAppPasswordsResponse) ProtoMessage() {}
+
+func (x *ListAppPasswordsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAppPasswordsResponse.ProtoReflect.Descriptor instead.
+func (*ListAppPasswordsResponse) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListAppPasswordsResponse) GetAppPasswords() []*AppPassword {
+	if x != nil {
+		return x.AppPasswords
+	}
+	return nil
+}
+
+type RevokeAppPasswordRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAppPasswordRequest) Reset() {
+	*x = RevokeAppPasswordRequest{}
+	mi := &file_auth_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAppPasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAppPasswordRequest) ProtoMessage() {}
+
+func (x *RevokeAppPasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAppPasswordRequest.ProtoReflect.Descriptor instead.
+func (*RevokeAppPasswordRequest) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RevokeAppPasswordRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RevokeAppPasswordResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAppPasswordResponse) Reset() {
+	*x = RevokeAppPasswordResponse{}
+	mi := &file_auth_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAppPasswordResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAppPasswordResponse) ProtoMessage() {}
+
+func (x *RevokeAppPasswordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAppPasswordResponse.ProtoReflect.Descriptor instead.
+func (*RevokeAppPasswordResponse) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{14}
+}
+
+type AuthenticateAppPasswordRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Login    string                 `protobuf:"bytes,1,opt,name=login,proto3" json:"login,omitempty"`
+	Password string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	RemoteIp string                 `protobuf:"bytes,3,opt,name=remote_ip,json=remoteIp,proto3" json:"remote_ip,omitempty"`
+	// imap, pop3 или submission
+	Protocol      string `protobuf:"bytes,4,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthenticateAppPasswordRequest) Reset() {
+	*x = AuthenticateAppPasswordRequest{}
+	mi := &file_auth_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthenticateAppPasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticateAppPasswordRequest) ProtoMessage() {}
+
+func (x *AuthenticateAppPasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticateAppPasswordRequest.ProtoReflect.Descriptor instead.
+func (*AuthenticateAppPasswordRequest) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AuthenticateAppPasswordRequest) GetLogin() string {
+	if x != nil {
+		return x.Login
+	}
+	return ""
+}
+
+func (x *AuthenticateAppPasswordRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *AuthenticateAppPasswordRequest) GetRemoteIp() string {
+	if x != nil {
+		return x.RemoteIp
+	}
+	return ""
+}
+
+func (x *AuthenticateAppPasswordRequest) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+type AuthenticateAppPasswordResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProfileId     int64                  `protobuf:"varint,1,opt,name=profile_id,json=profileId,proto3" json:"profile_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthenticateAppPasswordResponse) Reset() {
+	*x = AuthenticateAppPasswordResponse{}
+	mi := &file_auth_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthenticateAppPasswordResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticateAppPasswordResponse) ProtoMessage() {}
+
+func (x *AuthenticateAppPasswordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticateAppPasswordResponse.ProtoReflect.Descriptor instead.
+func (*AuthenticateAppPasswordResponse) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *AuthenticateAppPasswordResponse) GetProfileId() int64 {
+	if x != nil {
+		return x.ProfileId
+	}
+	return 0
+}
+
 var File_auth_proto protoreflect.FileDescriptor
 
 const file_auth_proto_rawDesc = "" +
@@ -439,12 +886,44 @@ const file_auth_proto_rawDesc = "" +
 	"\x0fRefreshResponse\x12!\n" +
 	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\"\x0f\n" +
 	"\rLogoutRequest\"\x10\n" +
-	"\x0eLogoutResponse2\x89\x02\n" +
+	"\x0eLogoutResponse\"\x94\x01\n" +
+	"\vAppPassword\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\tR\tcreatedAt\x12 \n" +
+	"\flast_used_at\x18\x04 \x01(\tR\n" +
+	"lastUsedAt\x12 \n" +
+	"\flast_used_ip\x18\x05 \x01(\tR\n" +
+	"lastUsedIp\".\n" +
+	"\x18CreateAppPasswordRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"r\n" +
+	"\x19CreateAppPasswordResponse\x129\n" +
+	"\fapp_password\x18\x01 \x01(\v2\x16.authproto.AppPasswordR\vappPassword\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"\x19\n" +
+	"\x17ListAppPasswordsRequest\"W\n" +
+	"\x18ListAppPasswordsResponse\x12;\n" +
+	"\rapp_passwords\x18\x01 \x03(\v2\x16.authproto.AppPasswordR\fappPasswords\"*\n" +
+	"\x18RevokeAppPasswordRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x1b\n" +
+	"\x19RevokeAppPasswordResponse\"\x8b\x01\n" +
+	"\x1eAuthenticateAppPasswordRequest\x12\x14\n" +
+	"\x05login\x18\x01 \x01(\tR\x05login\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\x12\x1b\n" +
+	"\tremote_ip\x18\x03 \x01(\tR\bremoteIp\x12\x1a\n" +
+	"\bprotocol\x18\x04 \x01(\tR\bprotocol\"@\n" +
+	"\x1fAuthenticateAppPasswordResponse\x12\x1d\n" +
+	"\n" +
+	"profile_id\x18\x01 \x01(\x03R\tprofileId2\x98\x05\n" +
 	"\vAuthService\x12:\n" +
 	"\x05Login\x12\x17.authproto.LoginRequest\x1a\x18.authproto.LoginResponse\x12=\n" +
 	"\x06Signup\x12\x18.authproto.SignupRequest\x1a\x19.authproto.SignupResponse\x12@\n" +
 	"\aRefresh\x12\x19.authproto.RefreshRequest\x1a\x1a.authproto.RefreshResponse\x12=\n" +
-	"\x06Logout\x12\x18.authproto.LogoutRequest\x1a\x19.authproto.LogoutResponseB\rZ\v/;authprotob\x06proto3"
+	"\x06Logout\x12\x18.authproto.LogoutRequest\x1a\x19.authproto.LogoutResponse\x12^\n" +
+	"\x11CreateAppPassword\x12#.authproto.CreateAppPasswordRequest\x1a$.authproto.CreateAppPasswordResponse\x12[\n" +
+	"\x10ListAppPasswords\x12\".authproto.ListAppPasswordsRequest\x1a#.authproto.ListAppPasswordsResponse\x12^\n" +
+	"\x11RevokeAppPassword\x12#.authproto.RevokeAppPasswordRequest\x1a$.authproto.RevokeAppPasswordResponse\x12p\n" +
+	"\x17AuthenticateAppPassword\x12).authproto.AuthenticateAppPasswordRequest\x1a*.authproto.AuthenticateAppPasswordResponseB\rZ\v/;authprotob\x06proto3"
 
 var (
 	file_auth_proto_rawDescOnce sync.Once
@@ -458,31 +937,50 @@ func file_auth_proto_rawDescGZIP() []byte {
 	return file_auth_proto_rawDescData
 }
 
-var file_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
 var file_auth_proto_goTypes = []any{
-	(*LoginRequest)(nil),    // 0: authproto.LoginRequest
-	(*LoginResponse)(nil),   // 1: authproto.LoginResponse
-	(*SignupRequest)(nil),   // 2: authproto.SignupRequest
-	(*SignupResponse)(nil),  // 3: authproto.SignupResponse
-	(*RefreshRequest)(nil),  // 4: authproto.RefreshRequest
-	(*RefreshResponse)(nil), // 5: authproto.RefreshResponse
-	(*LogoutRequest)(nil),   // 6: authproto.LogoutRequest
-	(*LogoutResponse)(nil),  // 7: authproto.LogoutResponse
+	(*LoginRequest)(nil),                    // 0: authproto.LoginRequest
+	(*LoginResponse)(nil),                   // 1: authproto.LoginResponse
+	(*SignupRequest)(nil),                   // 2: authproto.SignupRequest
+	(*SignupResponse)(nil),                  // 3: authproto.SignupResponse
+	(*RefreshRequest)(nil),                  // 4: authproto.RefreshRequest
+	(*RefreshResponse)(nil),                 // 5: authproto.RefreshResponse
+	(*LogoutRequest)(nil),                   // 6: authproto.LogoutRequest
+	(*LogoutResponse)(nil),                  // 7: authproto.LogoutResponse
+	(*AppPassword)(nil),                     // 8: authproto.AppPassword
+	(*CreateAppPasswordRequest)(nil),        // 9: authproto.CreateAppPasswordRequest
+	(*CreateAppPasswordResponse)(nil),       // 10: authproto.CreateAppPasswordResponse
+	(*ListAppPasswordsRequest)(nil),         // 11: authproto.ListAppPasswordsRequest
+	(*ListAppPasswordsResponse)(nil),        // 12: authproto.ListAppPasswordsResponse
+	(*RevokeAppPasswordRequest)(nil),        // 13: authproto.RevokeAppPasswordRequest
+	(*RevokeAppPasswordResponse)(nil),       // 14: authproto.RevokeAppPasswordResponse
+	(*AuthenticateAppPasswordRequest)(nil),  // 15: authproto.AuthenticateAppPasswordRequest
+	(*AuthenticateAppPasswordResponse)(nil), // 16: authproto.AuthenticateAppPasswordResponse
 }
 var file_auth_proto_depIdxs = []int32{
-	0, // 0: authproto.AuthService.Login:input_type -> authproto.LoginRequest
-	2, // 1: authproto.AuthService.Signup:input_type -> authproto.SignupRequest
-	4, // 2: authproto.AuthService.Refresh:input_type -> authproto.RefreshRequest
-	6, // 3: authproto.AuthService.Logout:input_type -> authproto.LogoutRequest
-	1, // 4: authproto.AuthService.Login:output_type -> authproto.LoginResponse
-	3, // 5: authproto.AuthService.Signup:output_type -> authproto.SignupResponse
-	5, // 6: authproto.AuthService.Refresh:output_type -> authproto.RefreshResponse
-	7, // 7: authproto.AuthService.Logout:output_type -> authproto.LogoutResponse
-	4, // [4:8] is the sub-list for method output_type
-	0, // [0:4] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	8,  // 0: authproto.CreateAppPasswordResponse.app_password:type_name -> authproto.AppPassword
+	8,  // 1: authproto.ListAppPasswordsResponse.app_passwords:type_name -> authproto.AppPassword
+	0,  // 2: authproto.AuthService.Login:input_type -> authproto.LoginRequest
+	2,  // 3: authproto.AuthService.Signup:input_type -> authproto.SignupRequest
+	4,  // 4: authproto.AuthService.Refresh:input_type -> authproto.RefreshRequest
+	6,  // 5: authproto.AuthService.Logout:input_type -> authproto.LogoutRequest
+	9,  // 6: authproto.AuthService.CreateAppPassword:input_type -> authproto.CreateAppPasswordRequest
+	11, // 7: authproto.AuthService.ListAppPasswords:input_type -> authproto.ListAppPasswordsRequest
+	13, // 8: authproto.AuthService.RevokeAppPassword:input_type -> authproto.RevokeAppPasswordRequest
+	15, // 9: authproto.AuthService.AuthenticateAppPassword:input_type -> authproto.AuthenticateAppPasswordRequest
+	1,  // 10: authproto.AuthService.Login:output_type -> authproto.LoginResponse
+	3,  // 11: authproto.AuthService.Signup:output_type -> authproto.SignupResponse
+	5,  // 12: authproto.AuthService.Refresh:output_type -> authproto.RefreshResponse
+	7,  // 13: authproto.AuthService.Logout:output_type -> authproto.LogoutResponse
+	10, // 14: authproto.AuthService.CreateAppPassword:output_type -> authproto.CreateAppPasswordResponse
+	12, // 15: authproto.AuthService.ListAppPasswords:output_type -> authproto.ListAppPasswordsResponse
+	14, // 16: authproto.AuthService.RevokeAppPassword:output_type -> authproto.RevokeAppPasswordResponse
+	16, // 17: authproto.AuthService.AuthenticateAppPassword:output_type -> authproto.AuthenticateAppPasswordResponse
+	10, // [10:18] is the sub-list for method output_type
+	2,  // [2:10] is the sub-list for method input_type
+	2,  // [2:2] is the sub-list for extension type_name
+	2,  // [2:2] is the sub-list for extension extendee
+	0,  // [0:2] is the sub-list for field type_name
 }
 
 func init() { file_auth_proto_init() }
@@ -496,7 +994,7 @@ func file_auth_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_auth_proto_rawDesc), len(file_auth_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   17,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,10 +19,14 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	AuthService_Login_FullMethodName   = "/authproto.AuthService/Login"
-	AuthService_Signup_FullMethodName  = "/authproto.AuthService/Signup"
-	AuthService_Refresh_FullMethodName = "/authproto.AuthService/Refresh"
-	AuthService_Logout_FullMethodName  = "/authproto.AuthService/Logout"
+	AuthService_Login_FullMethodName                   = "/authproto.AuthService/Login"
+	AuthService_Signup_FullMethodName                  = "/authproto.AuthService/Signup"
+	AuthService_Refresh_FullMethodName                 = "/authproto.AuthService/Refresh"
+	AuthService_Logout_FullMethodName                  = "/authproto.AuthService/Logout"
+	AuthService_CreateAppPassword_FullMethodName       = "/authproto.AuthService/CreateAppPassword"
+	AuthService_ListAppPasswords_FullMethodName        = "/authproto.AuthService/ListAppPasswords"
+	AuthService_RevokeAppPassword_FullMethodName       = "/authproto.AuthService/RevokeAppPassword"
+	AuthService_AuthenticateAppPassword_FullMethodName = "/authproto.AuthService/AuthenticateAppPassword"
 )
 
 // AuthServiceClient is the client API for AuthService service.
@@ -33,6 +37,12 @@ type AuthServiceClient interface {
 	Signup(ctx context.Context, in *SignupRequest, opts ...grpc.CallOption) (*SignupResponse, error)
 	Refresh(ctx context.Context, in *RefreshRequest, opts ...grpc.CallOption) (*RefreshResponse, error)
 	Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error)
+	// Пароли приложений для почтовых клиентов
+	CreateAppPassword(ctx context.Context, in *CreateAppPasswordRequest, opts ...grpc.CallOption) (*CreateAppPasswordResponse, error)
+	ListAppPasswords(ctx context.Context, in *ListAppPasswordsRequest, opts ...grpc.CallOption) (*ListAppPasswordsResponse, error)
+	RevokeAppPassword(ctx context.Context, in *RevokeAppPasswordRequest, opts ...grpc.CallOption) (*RevokeAppPasswordResponse, error)
+	// Проверка пароля приложения серверами IMAP, POP3 и SMTP AUTH
+	AuthenticateAppPassword(ctx context.Context, in *AuthenticateAppPasswordRequest, opts ...grpc.CallOption) (*AuthenticateAppPasswordResponse, error)
 }
 
 type authServiceClient struct {
@@ -83,6 +93,46 @@ func (c *authServiceClient) Logout(ctx context.Context, in *LogoutRequest, opts
 	return out, nil
 }
 
+func (c *authServiceClient) CreateAppPassword(ctx context.Context, in *CreateAppPasswordRequest, opts ...grpc.CallOption) (*CreateAppPasswordResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateAppPasswordResponse)
+	err := c.cc.Invoke(ctx, AuthService_CreateAppPassword_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) ListAppPasswords(ctx context.Context, in *ListAppPasswordsRequest, opts ...grpc.CallOption) (*ListAppPasswordsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAppPasswordsResponse)
+	err := c.cc.Invoke(ctx, AuthService_ListAppPasswords_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) RevokeAppPassword(ctx context.Context, in *RevokeAppPasswordRequest, opts ...grpc.CallOption) (*RevokeAppPasswordResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeAppPasswordResponse)
+	err := c.cc.Invoke(ctx, AuthService_RevokeAppPassword_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) AuthenticateAppPassword(ctx context.Context, in *AuthenticateAppPasswordRequest, opts ...grpc.CallOption) (*AuthenticateAppPasswordResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuthenticateAppPasswordResponse)
+	err := c.cc.Invoke(ctx, AuthService_AuthenticateAppPassword_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AuthServiceServer is the server API for AuthService service.
 // All implementations must embed UnimplementedAuthServiceServer
 // for forward compatibility.
@@ -91,6 +141,12 @@ type AuthServiceServer interface {
 	Signup(context.Context, *SignupRequest) (*SignupResponse, error)
 	Refresh(context.Context, *RefreshRequest) (*RefreshResponse, error)
 	Logout(context.Context, *LogoutRequest) (*LogoutResponse, error)
+	// Пароли приложений для почтовых клиентов
+	CreateAppPassword(context.Context, *CreateAppPasswordRequest) (*CreateAppPasswordResponse, error)
+	ListAppPasswords(context.Context, *ListAppPasswordsRequest) (*ListAppPasswordsResponse, error)
+	RevokeAppPassword(context.Context, *RevokeAppPasswordRequest) (*RevokeAppPasswordResponse, error)
+	// Проверка пароля приложения серверами IMAP, POP3 и SMTP AUTH
+	AuthenticateAppPassword(context.Context, *AuthenticateAppPasswordRequest) (*AuthenticateAppPasswordResponse, error)
 	mustEmbedUnimplementedAuthServiceServer()
 }
 
@@ -113,6 +169,18 @@ func (UnimplementedAuthServiceServer) Refresh(context.Context, *RefreshRequest)
 func (UnimplementedAuthServiceServer) Logout(context.Context, *LogoutRequest) (*LogoutResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Logout not implemented")
 }
+func (UnimplementedAuthServiceServer) CreateAppPassword(context.Context, *CreateAppPasswordRequest) (*CreateAppPasswordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAppPassword not implemented")
+}
+func (UnimplementedAuthServiceServer) ListAppPasswords(context.Context, *ListAppPasswordsRequest) (*ListAppPasswordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAppPasswords not implemented")
+}
+func (UnimplementedAuthServiceServer) RevokeAppPassword(context.Context, *RevokeAppPasswordRequest) (*RevokeAppPasswordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAppPassword not implemented")
+}
+func (UnimplementedAuthServiceServer) AuthenticateAppPassword(context.Context, *AuthenticateAppPasswordRequest) (*AuthenticateAppPasswordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AuthenticateAppPassword not implemented")
+}
 func (UnimplementedAuthServiceServer) mustEmbedUnimplementedAuthServiceServer() {}
 func (UnimplementedAuthServiceServer) testEmbeddedByValue()                     {}
 
@@ -206,6 +274,78 @@ func _AuthService_Logout_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthService_CreateAppPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAppPasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).CreateAppPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_CreateAppPassword_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).CreateAppPassword(ctx, req.(*CreateAppPasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_ListAppPasswords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAppPasswordsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).ListAppPasswords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_ListAppPasswords_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).ListAppPasswords(ctx, req.(*ListAppPasswordsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_RevokeAppPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAppPasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).RevokeAppPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_RevokeAppPassword_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).RevokeAppPassword(ctx, req.(*RevokeAppPasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_AuthenticateAppPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthenticateAppPasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).AuthenticateAppPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_AuthenticateAppPassword_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).AuthenticateAppPassword(ctx, req.(*AuthenticateAppPasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AuthService_ServiceDesc is the grpc.ServiceDesc for AuthService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -229,6 +369,22 @@ var AuthService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Logout",
 			Handler:    _AuthService_Logout_Handler,
 		},
+		{
+			MethodName: "CreateAppPassword",
+			Handler:    _AuthService_CreateAppPassword_Handler,
+		},
+		{
+			MethodName: "ListAppPasswords",
+			Handler:    _AuthService_ListAppPasswords_Handler,
+		},
+		{
+			MethodName: "RevokeAppPassword",
+			Handler:    _AuthService_RevokeAppPassword_Handler,
+		},
+		{
+			MethodName: "AuthenticateAppPassword",
+			Handler:    _AuthService_AuthenticateAppPassword_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "auth.proto",
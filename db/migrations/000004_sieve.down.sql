@@ -0,0 +1,3 @@
+-- +migrate Down
+DROP TABLE IF EXISTS vacation_response;
+DROP TABLE IF EXISTS sieve_script;
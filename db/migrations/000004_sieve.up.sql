@@ -0,0 +1,22 @@
+-- +migrate Up
+-- Sieve-скрипты пользователей (RFC 5228), один активный скрипт на профиль
+CREATE TABLE IF NOT EXISTS sieve_script (
+    id INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    profile_id INTEGER NOT NULL UNIQUE REFERENCES profile(id) ON DELETE CASCADE,
+    script TEXT NOT NULL CHECK (LENGTH(script) <= 65536),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER sieve_script_update_trigger
+BEFORE UPDATE ON sieve_script
+FOR EACH ROW EXECUTE PROCEDURE update_updated_at();
+
+-- Время последнего автоответа отправителю (ограничение :days из RFC 5230)
+CREATE TABLE IF NOT EXISTS vacation_response (
+    profile_id INTEGER NOT NULL REFERENCES profile(id) ON DELETE CASCADE,
+    sender TEXT NOT NULL CHECK (LENGTH(sender) BETWEEN 1 AND 320),
+    handle TEXT NOT NULL DEFAULT '' CHECK (LENGTH(handle) <= 1000),
+    last_sent_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (profile_id, sender, handle)
+);
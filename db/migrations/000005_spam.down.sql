@@ -0,0 +1,8 @@
+-- +migrate Down
+ALTER TABLE profile_message
+    DROP COLUMN IF EXISTS spam_label,
+    DROP COLUMN IF EXISTS spam_reasons,
+    DROP COLUMN IF EXISTS spam_score;
+
+DROP TABLE IF EXISTS spam_stats;
+DROP TABLE IF EXISTS spam_token;
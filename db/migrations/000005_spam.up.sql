@@ -0,0 +1,27 @@
+-- +migrate Up
+-- Статистика обучения спам-фильтра по токенам. profile_id = 0 - глобальная статистика
+CREATE TABLE IF NOT EXISTS spam_token (
+    profile_id INTEGER NOT NULL,
+    token TEXT NOT NULL CHECK (LENGTH(token) BETWEEN 1 AND 100),
+    spam_count INTEGER NOT NULL DEFAULT 0 CHECK (spam_count >= 0),
+    ham_count INTEGER NOT NULL DEFAULT 0 CHECK (ham_count >= 0),
+    PRIMARY KEY (profile_id, token)
+);
+
+-- Число писем, на которых обучен фильтр. profile_id = 0 - глобальная статистика
+CREATE TABLE IF NOT EXISTS spam_stats (
+    profile_id INTEGER PRIMARY KEY,
+    spam_messages INTEGER NOT NULL DEFAULT 0 CHECK (spam_messages >= 0),
+    ham_messages INTEGER NOT NULL DEFAULT 0 CHECK (ham_messages >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER spam_stats_update_trigger
+BEFORE UPDATE ON spam_stats
+FOR EACH ROW EXECUTE PROCEDURE update_updated_at();
+
+-- Оценка спам-фильтра и метка обучения для письма пользователя
+ALTER TABLE profile_message
+    ADD COLUMN IF NOT EXISTS spam_score DOUBLE PRECISION CHECK (spam_score BETWEEN 0 AND 1),
+    ADD COLUMN IF NOT EXISTS spam_reasons TEXT,
+    ADD COLUMN IF NOT EXISTS spam_label TEXT CHECK (spam_label IN ('spam', 'ham'));
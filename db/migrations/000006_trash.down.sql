@@ -0,0 +1,8 @@
+-- +migrate Down
+ALTER TABLE settings
+    DROP COLUMN IF EXISTS trash_retention_days;
+
+DROP INDEX IF EXISTS idx_folder_profile_message_folder_created;
+
+ALTER TABLE folder_profile_message
+    DROP COLUMN IF EXISTS original_folder_id;
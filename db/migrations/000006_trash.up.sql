@@ -0,0 +1,13 @@
+-- +migrate Up
+-- Папка, из которой письмо было перемещено. Нужна, чтобы вернуть письмо из корзины
+ALTER TABLE folder_profile_message
+    ADD COLUMN IF NOT EXISTS original_folder_id INTEGER REFERENCES folder(id) ON DELETE SET NULL;
+
+-- Ускоряет поиск писем корзины и спама, срок хранения которых истек
+CREATE INDEX IF NOT EXISTS idx_folder_profile_message_folder_created
+    ON folder_profile_message (folder_id, created_at);
+
+-- Срок хранения писем в корзине и спаме в днях, 0 - не удалять автоматически
+ALTER TABLE settings
+    ADD COLUMN IF NOT EXISTS trash_retention_days INTEGER NOT NULL DEFAULT 30
+        CHECK (trash_retention_days BETWEEN 0 AND 3650);
@@ -0,0 +1,8 @@
+-- +migrate Down
+DROP INDEX IF EXISTS idx_profile_message_starred;
+
+ALTER TABLE profile_message
+    DROP COLUMN IF EXISTS forwarded,
+    DROP COLUMN IF EXISTS answered,
+    DROP COLUMN IF EXISTS important,
+    DROP COLUMN IF EXISTS starred;
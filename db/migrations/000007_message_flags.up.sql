@@ -0,0 +1,12 @@
+-- +migrate Up
+-- Пользовательские флаги писем. Прочитанность хранится в read_status
+ALTER TABLE profile_message
+    ADD COLUMN IF NOT EXISTS starred BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS important BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS answered BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS forwarded BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Для виртуальной папки помеченных писем
+CREATE INDEX IF NOT EXISTS idx_profile_message_starred
+    ON profile_message (profile_id, message_id)
+    WHERE starred;
@@ -0,0 +1,27 @@
+-- +migrate Up
+-- Пользовательские метки писем. В отличие от папок, на письмо можно повесить несколько меток
+CREATE TABLE IF NOT EXISTS label (
+    id INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    profile_id INTEGER NOT NULL REFERENCES profile(id) ON DELETE CASCADE,
+    name TEXT NOT NULL CHECK (LENGTH(name) BETWEEN 1 AND 50),
+    color TEXT NOT NULL CHECK (color ~ '^#[0-9a-f]{6}$'),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_label_profile_name
+    ON label (profile_id, LOWER(name));
+
+CREATE TRIGGER label_update_trigger
+BEFORE UPDATE ON label
+FOR EACH ROW EXECUTE PROCEDURE update_updated_at();
+
+CREATE TABLE IF NOT EXISTS message_label (
+    label_id INTEGER NOT NULL REFERENCES label(id) ON DELETE CASCADE,
+    message_id INTEGER NOT NULL REFERENCES message(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (label_id, message_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_label_message
+    ON message_label (message_id);
@@ -0,0 +1,24 @@
+-- +migrate Down
+DROP INDEX IF EXISTS idx_folder_parent_lower_name;
+
+-- Вложенные папки становятся корневыми вместе с письмами в них. Если имя уже занято
+-- другой папкой пользователя, к нему добавляется идентификатор папки
+UPDATE folder f
+SET folder_name = LEFT(f.folder_name, 50 - LENGTH(' (' || f.id || ')')) || ' (' || f.id || ')'
+WHERE f.parent_id IS NOT NULL
+  AND EXISTS (
+      SELECT 1 FROM folder other
+      WHERE other.profile_id = f.profile_id
+        AND other.id <> f.id
+        AND lower(other.folder_name) = lower(f.folder_name)
+  );
+
+UPDATE folder SET parent_id = NULL WHERE parent_id IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_folder_profile_lower_name
+    ON folder (profile_id, lower(folder_name));
+
+DROP INDEX IF EXISTS idx_folder_parent;
+
+ALTER TABLE folder
+    DROP COLUMN IF EXISTS parent_id;
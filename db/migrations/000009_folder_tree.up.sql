@@ -0,0 +1,13 @@
+-- +migrate Up
+-- Вложенные папки: корневые папки имеют parent_id = NULL
+ALTER TABLE folder
+    ADD COLUMN IF NOT EXISTS parent_id INTEGER REFERENCES folder(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_folder_parent
+    ON folder (parent_id);
+
+-- Имя папки уникально среди соседей, а не среди всех папок пользователя
+DROP INDEX IF EXISTS idx_folder_profile_lower_name;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_folder_parent_lower_name
+    ON folder (profile_id, COALESCE(parent_id, 0), lower(folder_name));
@@ -0,0 +1,17 @@
+-- +migrate Up
+-- Правило автопересылки входящих писем, одно на профиль
+CREATE TABLE IF NOT EXISTS forwarding_rule (
+    profile_id INTEGER PRIMARY KEY REFERENCES profile(id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    -- Адреса пересылки, разделенные переводом строки
+    addresses TEXT NOT NULL DEFAULT '' CHECK (LENGTH(addresses) <= 4000),
+    keep_copy BOOLEAN NOT NULL DEFAULT TRUE,
+    from_contains TEXT NOT NULL DEFAULT '' CHECK (LENGTH(from_contains) <= 320),
+    subject_contains TEXT NOT NULL DEFAULT '' CHECK (LENGTH(subject_contains) <= 200),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER forwarding_rule_update_trigger
+BEFORE UPDATE ON forwarding_rule
+FOR EACH ROW EXECUTE PROCEDURE update_updated_at();
@@ -0,0 +1,12 @@
+-- +migrate Down
+DROP INDEX IF EXISTS idx_message_sender_base_profile;
+
+ALTER TABLE settings
+    DROP CONSTRAINT IF EXISTS settings_vacation_period_check,
+    DROP COLUMN IF EXISTS vacation_days,
+    DROP COLUMN IF EXISTS vacation_contacts_only,
+    DROP COLUMN IF EXISTS vacation_body,
+    DROP COLUMN IF EXISTS vacation_subject,
+    DROP COLUMN IF EXISTS vacation_end,
+    DROP COLUMN IF EXISTS vacation_start,
+    DROP COLUMN IF EXISTS vacation_enabled;
@@ -0,0 +1,17 @@
+-- +migrate Up
+-- Автоответ "вне офиса". Границы периода необязательны: NULL - без ограничения
+ALTER TABLE settings
+    ADD COLUMN IF NOT EXISTS vacation_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS vacation_start TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS vacation_end TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS vacation_subject TEXT NOT NULL DEFAULT '' CHECK (LENGTH(vacation_subject) <= 200),
+    ADD COLUMN IF NOT EXISTS vacation_body TEXT NOT NULL DEFAULT '' CHECK (LENGTH(vacation_body) <= 5000),
+    ADD COLUMN IF NOT EXISTS vacation_contacts_only BOOLEAN NOT NULL DEFAULT FALSE,
+    -- Не чаще одного автоответа одному отправителю за указанное число дней
+    ADD COLUMN IF NOT EXISTS vacation_days INTEGER NOT NULL DEFAULT 7 CHECK (vacation_days BETWEEN 1 AND 30),
+    ADD CONSTRAINT settings_vacation_period_check
+        CHECK (vacation_start IS NULL OR vacation_end IS NULL OR vacation_start < vacation_end);
+
+-- Ускоряет проверку, писал ли пользователь отправителю раньше
+CREATE INDEX IF NOT EXISTS idx_message_sender_base_profile
+    ON message (sender_base_profile_id);
@@ -0,0 +1,5 @@
+-- +migrate Down
+DROP TABLE IF EXISTS contact_group_member;
+DROP TABLE IF EXISTS contact_group;
+DROP TABLE IF EXISTS contact_email;
+DROP TABLE IF EXISTS contact;
@@ -0,0 +1,59 @@
+-- +migrate Up
+-- Адресная книга пользователя. Контакты создаются вручную или автоматически при отправке писем
+CREATE TABLE IF NOT EXISTS contact (
+    id INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    profile_id INTEGER NOT NULL REFERENCES profile(id) ON DELETE CASCADE,
+    name TEXT NOT NULL DEFAULT '' CHECK (LENGTH(name) <= 100),
+    phone TEXT NOT NULL DEFAULT '' CHECK (LENGTH(phone) <= 20),
+    notes TEXT NOT NULL DEFAULT '' CHECK (LENGTH(notes) <= 2000),
+    avatar_path TEXT NOT NULL DEFAULT '' CHECK (LENGTH(avatar_path) <= 200),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_contact_profile
+    ON contact (profile_id);
+
+CREATE TRIGGER contact_update_trigger
+BEFORE UPDATE ON contact
+FOR EACH ROW EXECUTE PROCEDURE update_updated_at();
+
+-- Адреса контакта. Один адрес принадлежит не более чем одному контакту пользователя,
+-- счетчик и время последнего использования нужны для ранжирования автодополнения
+CREATE TABLE IF NOT EXISTS contact_email (
+    id INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    contact_id INTEGER NOT NULL REFERENCES contact(id) ON DELETE CASCADE,
+    profile_id INTEGER NOT NULL REFERENCES profile(id) ON DELETE CASCADE,
+    email TEXT NOT NULL CHECK (LENGTH(email) BETWEEN 3 AND 320 AND email = LOWER(email)),
+    use_count INTEGER NOT NULL DEFAULT 0 CHECK (use_count >= 0),
+    last_used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (profile_id, email)
+);
+
+CREATE INDEX IF NOT EXISTS idx_contact_email_contact
+    ON contact_email (contact_id);
+
+CREATE TABLE IF NOT EXISTS contact_group (
+    id INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    profile_id INTEGER NOT NULL REFERENCES profile(id) ON DELETE CASCADE,
+    name TEXT NOT NULL CHECK (LENGTH(name) BETWEEN 1 AND 50),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_contact_group_profile_name
+    ON contact_group (profile_id, LOWER(name));
+
+CREATE TRIGGER contact_group_update_trigger
+BEFORE UPDATE ON contact_group
+FOR EACH ROW EXECUTE PROCEDURE update_updated_at();
+
+CREATE TABLE IF NOT EXISTS contact_group_member (
+    group_id INTEGER NOT NULL REFERENCES contact_group(id) ON DELETE CASCADE,
+    contact_id INTEGER NOT NULL REFERENCES contact(id) ON DELETE CASCADE,
+    PRIMARY KEY (group_id, contact_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_contact_group_member_contact
+    ON contact_group_member (contact_id);
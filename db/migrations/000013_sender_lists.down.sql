@@ -0,0 +1,5 @@
+-- +migrate Down
+ALTER TABLE settings
+    DROP COLUMN IF EXISTS blocked_sender_action;
+
+DROP TABLE IF EXISTS sender_rule;
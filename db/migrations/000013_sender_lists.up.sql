@@ -0,0 +1,16 @@
+-- +migrate Up
+-- Списки заблокированных и разрешенных отправителей. Шаблон - точный адрес или домен;
+-- один шаблон может находиться только в одном из списков
+CREATE TABLE IF NOT EXISTS sender_rule (
+    id INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    profile_id INTEGER NOT NULL REFERENCES profile(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('block', 'allow')),
+    pattern TEXT NOT NULL CHECK (LENGTH(pattern) BETWEEN 1 AND 320 AND pattern = LOWER(pattern)),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (profile_id, pattern)
+);
+
+-- Что делать с письмами заблокированных отправителей: помещать в спам или удалять
+ALTER TABLE settings
+    ADD COLUMN IF NOT EXISTS blocked_sender_action TEXT NOT NULL DEFAULT 'spam'
+        CHECK (blocked_sender_action IN ('spam', 'discard'));
@@ -0,0 +1,10 @@
+-- +migrate Down
+DROP TRIGGER IF EXISTS profile_message_counters_trigger ON profile_message;
+DROP FUNCTION IF EXISTS profile_message_counters();
+
+DROP TRIGGER IF EXISTS folder_profile_message_counters_trigger ON folder_profile_message;
+DROP FUNCTION IF EXISTS folder_message_counters();
+
+ALTER TABLE folder
+    DROP COLUMN IF EXISTS message_unread,
+    DROP COLUMN IF EXISTS message_total;
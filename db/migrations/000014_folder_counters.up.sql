@@ -0,0 +1,82 @@
+-- +migrate Up
+-- Счетчики писем папки. Письмо учитывается, когда есть и связь с папкой, и запись
+-- profile_message владельца папки - так же, как в запросе GetFolderMessagesInfo
+ALTER TABLE folder
+    ADD COLUMN IF NOT EXISTS message_total INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS message_unread INTEGER NOT NULL DEFAULT 0;
+
+UPDATE folder f
+SET message_total = c.total, message_unread = c.unread
+FROM (
+    SELECT fpm.folder_id,
+           COUNT(*) AS total,
+           COUNT(*) FILTER (WHERE NOT pm.read_status) AS unread
+    FROM folder_profile_message fpm
+    JOIN folder owner ON owner.id = fpm.folder_id
+    JOIN profile_message pm ON pm.message_id = fpm.message_id AND pm.profile_id = owner.profile_id
+    GROUP BY fpm.folder_id
+) c
+WHERE f.id = c.folder_id;
+
+-- Письмо добавлено в папку или убрано из нее
+CREATE OR REPLACE FUNCTION folder_message_counters()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('DELETE', 'UPDATE') THEN
+        UPDATE folder f
+        SET message_total = f.message_total - 1,
+            message_unread = f.message_unread - CASE WHEN pm.read_status THEN 0 ELSE 1 END
+        FROM profile_message pm
+        WHERE f.id = OLD.folder_id AND pm.profile_id = f.profile_id AND pm.message_id = OLD.message_id;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE folder f
+        SET message_total = f.message_total + 1,
+            message_unread = f.message_unread + CASE WHEN pm.read_status THEN 0 ELSE 1 END
+        FROM profile_message pm
+        WHERE f.id = NEW.folder_id AND pm.profile_id = f.profile_id AND pm.message_id = NEW.message_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER folder_profile_message_counters_trigger
+AFTER INSERT OR DELETE OR UPDATE OF folder_id, message_id ON folder_profile_message
+FOR EACH ROW EXECUTE PROCEDURE folder_message_counters();
+
+-- Письмо появилось у пользователя, удалено или изменился статус прочтения
+CREATE OR REPLACE FUNCTION profile_message_counters()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE'
+        AND OLD.read_status = NEW.read_status
+        AND OLD.profile_id = NEW.profile_id
+        AND OLD.message_id = NEW.message_id THEN
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP IN ('DELETE', 'UPDATE') THEN
+        UPDATE folder f
+        SET message_total = f.message_total - 1,
+            message_unread = f.message_unread - CASE WHEN OLD.read_status THEN 0 ELSE 1 END
+        FROM folder_profile_message fpm
+        WHERE fpm.message_id = OLD.message_id AND fpm.folder_id = f.id AND f.profile_id = OLD.profile_id;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE folder f
+        SET message_total = f.message_total + 1,
+            message_unread = f.message_unread + CASE WHEN NEW.read_status THEN 0 ELSE 1 END
+        FROM folder_profile_message fpm
+        WHERE fpm.message_id = NEW.message_id AND fpm.folder_id = f.id AND f.profile_id = NEW.profile_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER profile_message_counters_trigger
+AFTER INSERT OR DELETE OR UPDATE OF read_status, profile_id, message_id ON profile_message
+FOR EACH ROW EXECUTE PROCEDURE profile_message_counters();
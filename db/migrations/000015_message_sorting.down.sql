@@ -0,0 +1,16 @@
+-- +migrate Down
+DROP INDEX IF EXISTS idx_file_message;
+DROP INDEX IF EXISTS idx_profile_message_unread;
+DROP INDEX IF EXISTS idx_base_profile_address;
+DROP INDEX IF EXISTS idx_message_size;
+DROP INDEX IF EXISTS idx_message_topic;
+DROP INDEX IF EXISTS idx_message_date_of_dispatch;
+
+DROP TRIGGER IF EXISTS file_message_size_trigger ON file;
+DROP FUNCTION IF EXISTS message_file_size();
+
+DROP TRIGGER IF EXISTS message_size_trigger ON message;
+DROP FUNCTION IF EXISTS message_text_size();
+
+ALTER TABLE message
+    DROP COLUMN IF EXISTS size;
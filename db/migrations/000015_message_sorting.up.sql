@@ -0,0 +1,65 @@
+-- +migrate Up
+-- Размер письма: текст и вложения в байтах. Нужен для сортировки списка по размеру
+ALTER TABLE message
+    ADD COLUMN IF NOT EXISTS size BIGINT NOT NULL DEFAULT 0;
+
+UPDATE message m
+SET size = OCTET_LENGTH(COALESCE(m.text, '')) + COALESCE((
+    SELECT SUM(fl.size) FROM file fl WHERE fl.message_id = m.id
+), 0);
+
+-- Текст письма записан или изменен
+CREATE OR REPLACE FUNCTION message_text_size()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.size = OCTET_LENGTH(COALESCE(NEW.text, '')) + COALESCE((
+        SELECT SUM(fl.size) FROM file fl WHERE fl.message_id = NEW.id
+    ), 0);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER message_size_trigger
+BEFORE INSERT OR UPDATE OF text ON message
+FOR EACH ROW EXECUTE PROCEDURE message_text_size();
+
+-- Вложение добавлено к письму, удалено или изменилось
+CREATE OR REPLACE FUNCTION message_file_size()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('DELETE', 'UPDATE') THEN
+        UPDATE message SET size = size - OLD.size WHERE id = OLD.message_id;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE message SET size = size + NEW.size WHERE id = NEW.message_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER file_message_size_trigger
+AFTER INSERT OR DELETE OR UPDATE OF size, message_id ON file
+FOR EACH ROW EXECUTE PROCEDURE message_file_size();
+
+-- Индексы под ключи сортировки списка писем. Второй столбец - id, как в keyset-условии
+CREATE INDEX IF NOT EXISTS idx_message_date_of_dispatch
+    ON message (date_of_dispatch, id);
+
+CREATE INDEX IF NOT EXISTS idx_message_topic
+    ON message ((COALESCE(topic, '')), id);
+
+CREATE INDEX IF NOT EXISTS idx_message_size
+    ON message (size, id);
+
+CREATE INDEX IF NOT EXISTS idx_base_profile_address
+    ON base_profile ((username || '@' || domain));
+
+-- Фильтры "только непрочитанные" и "с вложениями"
+CREATE INDEX IF NOT EXISTS idx_profile_message_unread
+    ON profile_message (profile_id, message_id)
+    WHERE NOT read_status;
+
+CREATE INDEX IF NOT EXISTS idx_file_message
+    ON file (message_id);
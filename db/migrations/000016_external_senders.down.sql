@@ -0,0 +1,3 @@
+-- +migrate Down
+ALTER TABLE base_profile
+    ADD CONSTRAINT base_profile_username_key UNIQUE (username);
@@ -0,0 +1,5 @@
+-- +migrate Up
+-- Внешние отправители хранятся в base_profile без profile, и их логины
+-- могут совпадать с логинами локальных пользователей на другом домене
+ALTER TABLE base_profile
+    DROP CONSTRAINT IF EXISTS base_profile_username_key;
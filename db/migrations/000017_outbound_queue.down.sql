@@ -0,0 +1,4 @@
+-- +migrate Down
+DROP INDEX IF EXISTS idx_outbound_message_due;
+DROP TRIGGER IF EXISTS outbound_message_update_trigger ON outbound_message;
+DROP TABLE IF EXISTS outbound_message;
@@ -0,0 +1,27 @@
+-- +migrate Up
+-- Очередь писем на внешние адреса. Одна строка - одно письмо одному получателю
+CREATE TABLE IF NOT EXISTS outbound_message (
+    id INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    message_id INTEGER NOT NULL REFERENCES message(id) ON DELETE CASCADE,
+    sender_profile_id INTEGER NOT NULL REFERENCES profile(id) ON DELETE CASCADE,
+    recipient TEXT NOT NULL CHECK (LENGTH(recipient) BETWEEN 3 AND 254),
+    status TEXT NOT NULL DEFAULT 'queued'
+        CHECK (status IN ('queued', 'sending', 'deferred', 'sent', 'bounced')),
+    attempts INTEGER NOT NULL DEFAULT 0 CHECK (attempts >= 0),
+    -- Для писем в статусе sending - время окончания аренды обработчиком
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT CHECK (LENGTH(last_error) <= 1000),
+    -- Отправителю уже сообщили, что доставка задерживается
+    delay_notified BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (message_id, recipient)
+);
+
+CREATE TRIGGER outbound_message_update_trigger
+BEFORE UPDATE ON outbound_message
+FOR EACH ROW EXECUTE PROCEDURE update_updated_at();
+
+CREATE INDEX IF NOT EXISTS idx_outbound_message_due
+    ON outbound_message (next_attempt_at)
+    WHERE status IN ('queued', 'sending', 'deferred');
@@ -0,0 +1,4 @@
+-- +migrate Down
+ALTER TABLE message
+    DROP COLUMN IF EXISTS dkim_domain,
+    DROP COLUMN IF EXISTS dkim_result;
@@ -0,0 +1,7 @@
+-- +migrate Up
+-- Результат проверки DKIM-подписи письма, принятого от внешнего сервера.
+-- У писем, отправленных внутри сервиса, остается NULL
+ALTER TABLE message
+    ADD COLUMN IF NOT EXISTS dkim_result TEXT
+        CHECK (dkim_result IN ('none', 'pass', 'fail', 'temperror', 'permerror')),
+    ADD COLUMN IF NOT EXISTS dkim_domain TEXT CHECK (LENGTH(dkim_domain) BETWEEN 1 AND 253);
@@ -0,0 +1,8 @@
+-- +migrate Down
+ALTER TABLE message
+    DROP COLUMN IF EXISTS authentication_results,
+    DROP COLUMN IF EXISTS dmarc_aligned,
+    DROP COLUMN IF EXISTS dmarc_policy,
+    DROP COLUMN IF EXISTS dmarc_result,
+    DROP COLUMN IF EXISTS spf_domain,
+    DROP COLUMN IF EXISTS spf_result;
@@ -0,0 +1,14 @@
+-- +migrate Up
+-- Результаты проверки SPF и DMARC письма, принятого от внешнего сервера, и сводка
+-- всех проверок в формате Authentication-Results. У писем, отправленных внутри
+-- сервиса, остается NULL
+ALTER TABLE message
+    ADD COLUMN IF NOT EXISTS spf_result TEXT
+        CHECK (spf_result IN ('none', 'neutral', 'pass', 'fail', 'softfail', 'temperror', 'permerror')),
+    ADD COLUMN IF NOT EXISTS spf_domain TEXT CHECK (LENGTH(spf_domain) BETWEEN 1 AND 253),
+    ADD COLUMN IF NOT EXISTS dmarc_result TEXT
+        CHECK (dmarc_result IN ('none', 'pass', 'fail', 'temperror', 'permerror')),
+    ADD COLUMN IF NOT EXISTS dmarc_policy TEXT CHECK (dmarc_policy IN ('none', 'quarantine', 'reject')),
+    -- Адрес в From подтвержден DKIM или SPF, даже если домен не публикует DMARC-запись
+    ADD COLUMN IF NOT EXISTS dmarc_aligned BOOLEAN,
+    ADD COLUMN IF NOT EXISTS authentication_results TEXT;
@@ -0,0 +1,6 @@
+-- +migrate Down
+DROP TABLE IF EXISTS pop3_hidden_message;
+
+ALTER TABLE settings
+    DROP COLUMN IF EXISTS pop3_apop_secret,
+    DROP COLUMN IF EXISTS pop3_delete_action;
@@ -0,0 +1,16 @@
+-- +migrate Up
+-- Что делает удаление письма по POP3: переносит его в корзину или только скрывает от POP3.
+-- Секрет APOP хранится открытым: сервер считает по нему MD5-дайджест (RFC 1939, 7),
+-- NULL - вход по APOP выключен
+ALTER TABLE settings
+    ADD COLUMN IF NOT EXISTS pop3_delete_action TEXT NOT NULL DEFAULT 'trash'
+        CHECK (pop3_delete_action IN ('trash', 'hide')),
+    ADD COLUMN IF NOT EXISTS pop3_apop_secret TEXT CHECK (LENGTH(pop3_apop_secret) BETWEEN 8 AND 128);
+
+-- Письма, удаленные по POP3 без переноса в корзину: в веб-интерфейсе они остаются
+CREATE TABLE IF NOT EXISTS pop3_hidden_message (
+    profile_id INTEGER NOT NULL REFERENCES profile(id) ON DELETE CASCADE,
+    message_id INTEGER NOT NULL REFERENCES message(id) ON DELETE CASCADE,
+    hidden_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (profile_id, message_id)
+);
@@ -0,0 +1,16 @@
+-- +migrate Down
+DROP TRIGGER IF EXISTS message_thread_mail_change_trigger ON message;
+DROP TRIGGER IF EXISTS message_delete_mail_change_trigger ON message;
+DROP FUNCTION IF EXISTS message_mail_change();
+
+DROP TRIGGER IF EXISTS profile_message_mail_change_trigger ON profile_message;
+DROP FUNCTION IF EXISTS profile_message_mail_change();
+
+DROP TRIGGER IF EXISTS folder_profile_message_mail_change_trigger ON folder_profile_message;
+DROP FUNCTION IF EXISTS folder_message_mail_change();
+
+DROP TRIGGER IF EXISTS folder_mail_change_trigger ON folder;
+DROP FUNCTION IF EXISTS folder_mail_change();
+
+DROP INDEX IF EXISTS idx_mail_change_profile;
+DROP TABLE IF EXISTS mail_change;
@@ -0,0 +1,160 @@
+-- +migrate Up
+-- Журнал изменений почтового ящика, по которому клиенты JMAP синхронизируются
+-- без полной загрузки (RFC 8620, 5.2). Состояние данных профиля - номер последней
+-- записи нужного типа. Журнал пишут триггеры, поэтому в него попадают изменения
+-- из веб-интерфейса, IMAP, POP3 и доставки почты
+CREATE TABLE IF NOT EXISTS mail_change (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    profile_id INTEGER NOT NULL REFERENCES profile(id) ON DELETE CASCADE,
+    object_type TEXT NOT NULL CHECK (object_type IN ('Mailbox', 'Email')),
+    object_id INTEGER NOT NULL,
+    change_type TEXT NOT NULL CHECK (change_type IN ('created', 'updated', 'destroyed')),
+    -- Цепочка, в которой письмо появилось или из которой пропало: по таким записям
+    -- считаются изменения цепочек. Письмо без цепочки - отдельная цепочка 'm' || id письма
+    thread_key TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_mail_change_profile
+    ON mail_change (profile_id, object_type, id);
+
+-- Папка создана, удалена, переименована, перенесена или изменились счетчики писем
+CREATE OR REPLACE FUNCTION folder_mail_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO mail_change (profile_id, object_type, object_id, change_type)
+        VALUES (NEW.profile_id, 'Mailbox', NEW.id, 'created');
+    ELSIF TG_OP = 'DELETE' THEN
+        INSERT INTO mail_change (profile_id, object_type, object_id, change_type)
+        VALUES (OLD.profile_id, 'Mailbox', OLD.id, 'destroyed');
+    ELSIF (OLD.folder_name, OLD.parent_id, OLD.message_total, OLD.message_unread)
+        IS DISTINCT FROM (NEW.folder_name, NEW.parent_id, NEW.message_total, NEW.message_unread) THEN
+        INSERT INTO mail_change (profile_id, object_type, object_id, change_type)
+        VALUES (NEW.profile_id, 'Mailbox', NEW.id, 'updated');
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER folder_mail_change_trigger
+AFTER INSERT OR DELETE OR UPDATE ON folder
+FOR EACH ROW EXECUTE PROCEDURE folder_mail_change();
+
+-- Письмо добавлено в папку или убрано из нее. Для владельца папки письмо существует,
+-- пока лежит хотя бы в одной его папке
+CREATE OR REPLACE FUNCTION folder_message_mail_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    old_owner INTEGER;
+    new_owner INTEGER;
+    message_thread TEXT;
+    linked BOOLEAN;
+BEGIN
+    IF TG_OP IN ('DELETE', 'UPDATE') THEN
+        SELECT profile_id INTO old_owner FROM folder WHERE id = OLD.folder_id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        SELECT profile_id INTO new_owner FROM folder WHERE id = NEW.folder_id;
+    END IF;
+
+    -- Письмо переложено в другую папку того же пользователя
+    IF TG_OP = 'UPDATE' AND OLD.message_id = NEW.message_id AND old_owner = new_owner THEN
+        INSERT INTO mail_change (profile_id, object_type, object_id, change_type)
+        VALUES (new_owner, 'Email', NEW.message_id, 'updated');
+        RETURN NULL;
+    END IF;
+
+    IF TG_OP IN ('DELETE', 'UPDATE') THEN
+        SELECT COALESCE(m.thread_id::text, 'm' || m.id) INTO message_thread FROM message m WHERE m.id = OLD.message_id;
+        -- Если письмо удалено целиком, запись уже сделал триггер на message
+        IF old_owner IS NOT NULL AND message_thread IS NOT NULL THEN
+            linked := EXISTS (
+                SELECT 1 FROM folder_profile_message fpm
+                JOIN folder f ON f.id = fpm.folder_id
+                WHERE fpm.message_id = OLD.message_id AND f.profile_id = old_owner
+            );
+            INSERT INTO mail_change (profile_id, object_type, object_id, change_type, thread_key)
+            VALUES (old_owner, 'Email', OLD.message_id,
+                    CASE WHEN linked THEN 'updated' ELSE 'destroyed' END,
+                    CASE WHEN linked THEN NULL ELSE message_thread END);
+        END IF;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') AND new_owner IS NOT NULL THEN
+        SELECT COALESCE(m.thread_id::text, 'm' || m.id) INTO message_thread FROM message m WHERE m.id = NEW.message_id;
+        linked := EXISTS (
+            SELECT 1 FROM folder_profile_message fpm
+            JOIN folder f ON f.id = fpm.folder_id
+            WHERE fpm.message_id = NEW.message_id AND f.profile_id = new_owner
+            AND fpm.folder_id <> NEW.folder_id
+        );
+        INSERT INTO mail_change (profile_id, object_type, object_id, change_type, thread_key)
+        VALUES (new_owner, 'Email', NEW.message_id,
+                CASE WHEN linked THEN 'updated' ELSE 'created' END,
+                CASE WHEN linked THEN NULL ELSE message_thread END);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER folder_profile_message_mail_change_trigger
+AFTER INSERT OR DELETE OR UPDATE OF folder_id, message_id ON folder_profile_message
+FOR EACH ROW EXECUTE PROCEDURE folder_message_mail_change();
+
+-- Изменились прочитанность или флаги письма
+CREATE OR REPLACE FUNCTION profile_message_mail_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (OLD.read_status, OLD.starred, OLD.important, OLD.answered, OLD.forwarded)
+        IS DISTINCT FROM (NEW.read_status, NEW.starred, NEW.important, NEW.answered, NEW.forwarded) THEN
+        INSERT INTO mail_change (profile_id, object_type, object_id, change_type)
+        VALUES (NEW.profile_id, 'Email', NEW.message_id, 'updated');
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER profile_message_mail_change_trigger
+AFTER UPDATE OF read_status, starred, important, answered, forwarded ON profile_message
+FOR EACH ROW EXECUTE PROCEDURE profile_message_mail_change();
+
+-- Письмо удаляется целиком или переносится в другую цепочку. Удаление записывается до
+-- каскадного удаления связей с папками, пока цепочка письма еще известна
+CREATE OR REPLACE FUNCTION message_mail_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO mail_change (profile_id, object_type, object_id, change_type, thread_key)
+        SELECT DISTINCT f.profile_id, 'Email', OLD.id, 'destroyed', COALESCE(OLD.thread_id::text, 'm' || OLD.id)
+        FROM folder_profile_message fpm
+        JOIN folder f ON f.id = fpm.folder_id
+        WHERE fpm.message_id = OLD.id;
+        RETURN OLD;
+    END IF;
+
+    INSERT INTO mail_change (profile_id, object_type, object_id, change_type, thread_key)
+    SELECT DISTINCT f.profile_id, 'Email', NEW.id, 'updated', t.thread_key
+    FROM folder_profile_message fpm
+    JOIN folder f ON f.id = fpm.folder_id
+    CROSS JOIN (VALUES
+        (COALESCE(OLD.thread_id::text, 'm' || OLD.id)),
+        (COALESCE(NEW.thread_id::text, 'm' || NEW.id))
+    ) AS t(thread_key)
+    WHERE fpm.message_id = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER message_delete_mail_change_trigger
+BEFORE DELETE ON message
+FOR EACH ROW EXECUTE PROCEDURE message_mail_change();
+
+CREATE TRIGGER message_thread_mail_change_trigger
+AFTER UPDATE OF thread_id ON message
+FOR EACH ROW
+WHEN (OLD.thread_id IS DISTINCT FROM NEW.thread_id)
+EXECUTE PROCEDURE message_mail_change();
@@ -0,0 +1,3 @@
+-- +migrate Down
+DROP TABLE IF EXISTS submitted_message;
+DROP TABLE IF EXISTS profile_alias;
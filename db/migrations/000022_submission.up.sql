@@ -0,0 +1,24 @@
+-- +migrate Up
+-- Дополнительные адреса профиля: на них принимается почта, от их имени
+-- можно отправлять письма из почтовых клиентов
+CREATE TABLE IF NOT EXISTS profile_alias (
+    id INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    profile_id INTEGER NOT NULL REFERENCES profile(id) ON DELETE CASCADE,
+    username TEXT NOT NULL CHECK (LENGTH(username) BETWEEN 1 AND 50),
+    domain TEXT NOT NULL CHECK (LENGTH(domain) BETWEEN 1 AND 50),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS profile_alias_address_idx ON profile_alias (LOWER(username), LOWER(domain));
+CREATE INDEX IF NOT EXISTS profile_alias_profile_idx ON profile_alias (profile_id);
+
+-- Письма, отправленные через сервер отправки (порт 587), по их Message-Id.
+-- Клиент после отправки сам кладет копию в "Отправленные" через IMAP APPEND,
+-- а копия там уже есть, поэтому такой APPEND не сохраняет письмо второй раз
+CREATE TABLE IF NOT EXISTS submitted_message (
+    profile_id INTEGER NOT NULL REFERENCES profile(id) ON DELETE CASCADE,
+    message_id_header TEXT NOT NULL CHECK (LENGTH(message_id_header) BETWEEN 1 AND 998),
+    message_id INTEGER NOT NULL REFERENCES message(id) ON DELETE CASCADE,
+    submitted_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (profile_id, message_id_header)
+);
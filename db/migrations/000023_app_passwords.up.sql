@@ -0,0 +1,14 @@
+-- +migrate Up
+-- Пароли приложений: почтовые клиенты (IMAP, POP3, отправка по SMTP AUTH) входят
+-- только с ними, для входа в веб-интерфейс они не подходят
+CREATE TABLE IF NOT EXISTS app_password (
+    id INTEGER PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    profile_id INTEGER NOT NULL REFERENCES profile(id) ON DELETE CASCADE,
+    name TEXT NOT NULL CHECK (LENGTH(name) BETWEEN 1 AND 100),
+    password_hash TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMPTZ,
+    last_used_ip TEXT
+);
+
+CREATE INDEX IF NOT EXISTS app_password_profile_idx ON app_password (profile_id);
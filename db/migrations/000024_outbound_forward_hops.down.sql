@@ -0,0 +1,2 @@
+-- +migrate Down
+ALTER TABLE outbound_message DROP COLUMN IF EXISTS forward_hops;
@@ -0,0 +1,5 @@
+-- +migrate Up
+-- Число автопересылок письма до постановки в очередь. Уходит наружу в заголовке
+-- X-Forwarded-Count, чтобы петлю пересылок между серверами можно было остановить
+ALTER TABLE outbound_message
+    ADD COLUMN IF NOT EXISTS forward_hops INTEGER NOT NULL DEFAULT 0 CHECK (forward_hops >= 0);
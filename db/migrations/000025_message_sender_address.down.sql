@@ -0,0 +1,19 @@
+-- +migrate Down
+ALTER TABLE message DROP CONSTRAINT IF EXISTS message_sender_check;
+
+INSERT INTO base_profile (username, domain)
+SELECT DISTINCT sender_username, sender_domain
+FROM message
+WHERE sender_base_profile_id IS NULL
+ON CONFLICT DO NOTHING;
+
+UPDATE message m
+SET sender_base_profile_id = bp.id
+FROM base_profile bp
+WHERE m.sender_base_profile_id IS NULL
+  AND bp.username = m.sender_username AND bp.domain = m.sender_domain;
+
+ALTER TABLE message
+    ALTER COLUMN sender_base_profile_id SET NOT NULL,
+    DROP COLUMN IF EXISTS sender_username,
+    DROP COLUMN IF EXISTS sender_domain;
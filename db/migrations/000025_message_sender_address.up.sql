@@ -0,0 +1,25 @@
+-- +migrate Up
+-- Адрес отправителя хранится в письме, если это не основной адрес пользователя сервиса:
+-- у внешнего отправителя нет base_profile (sender_base_profile_id IS NULL), а письмо
+-- пользователя с дополнительного адреса хранит этот адрес. Раньше для внешних отправителей
+-- создавались base_profile без profile, из-за чего идентификаторы base_profile и profile
+-- новых пользователей расходились
+ALTER TABLE message
+    ADD COLUMN IF NOT EXISTS sender_username TEXT CHECK (LENGTH(sender_username) BETWEEN 1 AND 64),
+    ADD COLUMN IF NOT EXISTS sender_domain TEXT CHECK (LENGTH(sender_domain) BETWEEN 1 AND 255),
+    ALTER COLUMN sender_base_profile_id DROP NOT NULL;
+
+UPDATE message m
+SET sender_username = bp.username, sender_domain = bp.domain, sender_base_profile_id = NULL
+FROM base_profile bp
+WHERE bp.id = m.sender_base_profile_id
+  AND NOT EXISTS (SELECT 1 FROM profile p WHERE p.base_profile_id = bp.id);
+
+DELETE FROM base_profile bp
+WHERE NOT EXISTS (SELECT 1 FROM profile p WHERE p.base_profile_id = bp.id);
+
+ALTER TABLE message
+    ADD CONSTRAINT message_sender_check CHECK (
+        (sender_username IS NULL) = (sender_domain IS NULL)
+        AND (sender_base_profile_id IS NOT NULL OR sender_username IS NOT NULL)
+    );
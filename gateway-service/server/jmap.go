@@ -0,0 +1,683 @@
+package gateway_service
+
+import (
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	"2025_2_a4code/messages-service/pkg/messagesproto"
+	"2025_2_a4code/profile-service/pkg/profileproto"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// JMAP (RFC 8620, RFC 8621) - стандартный API почтового ящика поверх тех же методов
+// messages-service, что и /messages/*. Вложения (blob) не поддерживаются: шаблоны
+// downloadUrl и uploadUrl есть в сессии, потому что они обязательны, но не обслуживаются
+
+const (
+	jmapCapabilityCore       = "urn:ietf:params:jmap:core"
+	jmapCapabilityMail       = "urn:ietf:params:jmap:mail"
+	jmapCapabilitySubmission = "urn:ietf:params:jmap:submission"
+)
+
+const (
+	jmapMaxSizeRequest    = 10 * 1024 * 1024
+	jmapMaxCallsInRequest = 16
+	jmapMaxObjectsInGet   = 256
+	jmapMaxObjectsInSet   = 128
+	// jmapSessionState не меняется: состав аккаунтов и возможностей у пользователя один
+	jmapSessionState = "1"
+)
+
+// Ошибки методов (RFC 8620, раздел 3.6.2)
+const (
+	jmapErrUnknownMethod          = "unknownMethod"
+	jmapErrInvalidArguments       = "invalidArguments"
+	jmapErrInvalidResultReference = "invalidResultReference"
+	jmapErrAccountNotFound        = "accountNotFound"
+	jmapErrServerFail             = "serverFail"
+	jmapErrRequestTooLarge        = "requestTooLarge"
+	jmapErrCannotCalculateChanges = "cannotCalculateChanges"
+	jmapErrStateMismatch          = "stateMismatch"
+	jmapErrUnsupportedFilter      = "unsupportedFilter"
+	jmapErrUnsupportedSort        = "unsupportedSort"
+)
+
+type jmapMethodError struct {
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+}
+
+func jmapError(errorType, description string) *jmapMethodError {
+	return &jmapMethodError{Type: errorType, Description: description}
+}
+
+// jmapSetError - ошибка создания, изменения или удаления одного объекта в методах /set
+type jmapSetError struct {
+	Type        string   `json:"type"`
+	Description string   `json:"description,omitempty"`
+	Properties  []string `json:"properties,omitempty"`
+}
+
+// jmapInvocation - вызов метода или ответ на него: массив [имя, аргументы, идентификатор вызова]
+type jmapInvocation struct {
+	Name   string
+	Args   json.RawMessage
+	CallID string
+}
+
+func (inv *jmapInvocation) UnmarshalJSON(data []byte) error {
+	var parts []json.RawMessage
+	if err := json.Unmarshal(data, &parts); err != nil {
+		return err
+	}
+	if len(parts) != 3 {
+		return errors.New("invocation must have 3 elements")
+	}
+	if err := json.Unmarshal(parts[0], &inv.Name); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(parts[2], &inv.CallID); err != nil {
+		return err
+	}
+	inv.Args = parts[1]
+	return nil
+}
+
+func (inv jmapInvocation) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{inv.Name, inv.Args, inv.CallID})
+}
+
+type jmapRequest struct {
+	Using       []string          `json:"using"`
+	MethodCalls []jmapInvocation  `json:"methodCalls"`
+	CreatedIDs  map[string]string `json:"createdIds,omitempty"`
+}
+
+type jmapResponse struct {
+	MethodResponses []jmapInvocation  `json:"methodResponses"`
+	CreatedIDs      map[string]string `json:"createdIds,omitempty"`
+	SessionState    string            `json:"sessionState"`
+}
+
+// jmapResultReference - ссылка на результат предыдущего вызова в том же запросе
+type jmapResultReference struct {
+	ResultOf string `json:"resultOf"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+}
+
+// jmapCall - состояние одного запроса к /jmap/api, общее для всех его вызовов
+type jmapCall struct {
+	ctx       context.Context
+	accountID string
+	// callID - идентификатор текущего вызова, под ним же идут ответы неявных вызовов
+	callID string
+	// createdIDs - идентификаторы объектов, созданных в этом запросе, по ключам клиента
+	createdIDs map[string]string
+	responses  []jmapInvocation
+	// folders - папки пользователя, загружаются при первом обращении
+	folders []*messagesproto.Folder
+}
+
+type jmapMethod struct {
+	capability string
+	handle     func(s *Server, call *jmapCall, args json.RawMessage) (any, *jmapMethodError)
+}
+
+var jmapMethods = map[string]jmapMethod{
+	"Core/echo":           {jmapCapabilityCore, (*Server).jmapEcho},
+	"Mailbox/get":         {jmapCapabilityMail, (*Server).jmapMailboxGet},
+	"Mailbox/changes":     {jmapCapabilityMail, (*Server).jmapMailboxChanges},
+	"Email/query":         {jmapCapabilityMail, (*Server).jmapEmailQuery},
+	"Email/queryChanges":  {jmapCapabilityMail, (*Server).jmapEmailQueryChanges},
+	"Email/get":           {jmapCapabilityMail, (*Server).jmapEmailGet},
+	"Email/changes":       {jmapCapabilityMail, (*Server).jmapEmailChanges},
+	"Email/set":           {jmapCapabilityMail, (*Server).jmapEmailSet},
+	"Thread/get":          {jmapCapabilityMail, (*Server).jmapThreadGet},
+	"Thread/changes":      {jmapCapabilityMail, (*Server).jmapThreadChanges},
+	"EmailSubmission/set": {jmapCapabilitySubmission, (*Server).jmapEmailSubmissionSet},
+}
+
+func (s *Server) jmapWellKnownHandler(w http.ResponseWriter, r *http.Request) {
+	http.Redirect(w, r, "/jmap/session", http.StatusTemporaryRedirect)
+}
+
+func (s *Server) jmapSessionHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getJMAPAccessToken(r)
+	if err != nil {
+		writeJMAPUnauthorized(w)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	profile, err := s.jmapProfile(ctx)
+	if err != nil {
+		writeJMAPGrpcError(w, err)
+		return
+	}
+
+	base := jmapBaseURL(r)
+	accountID := profile.Id
+	session := map[string]any{
+		"capabilities": map[string]any{
+			jmapCapabilityCore: map[string]any{
+				"maxSizeUpload":         0,
+				"maxConcurrentUpload":   1,
+				"maxSizeRequest":        jmapMaxSizeRequest,
+				"maxConcurrentRequests": 4,
+				"maxCallsInRequest":     jmapMaxCallsInRequest,
+				"maxObjectsInGet":       jmapMaxObjectsInGet,
+				"maxObjectsInSet":       jmapMaxObjectsInSet,
+				"collationAlgorithms":   []string{},
+			},
+			jmapCapabilityMail:       map[string]any{},
+			jmapCapabilitySubmission: map[string]any{},
+		},
+		"accounts": map[string]any{
+			accountID: map[string]any{
+				"name":       profile.Username,
+				"isPersonal": true,
+				"isReadOnly": false,
+				"accountCapabilities": map[string]any{
+					jmapCapabilityMail: map[string]any{
+						"maxMailboxesPerEmail":       nil,
+						"maxMailboxDepth":            nil,
+						"maxSizeMailboxName":         255,
+						"maxSizeAttachmentsPerEmail": 0,
+						"emailQuerySortOptions":      []string{"receivedAt", "from", "subject", "size"},
+						"mayCreateTopLevelMailbox":   false,
+					},
+					jmapCapabilitySubmission: map[string]any{
+						"maxDelayedSend":       0,
+						"submissionExtensions": map[string]any{},
+					},
+				},
+			},
+		},
+		"primaryAccounts": map[string]string{
+			jmapCapabilityMail:       accountID,
+			jmapCapabilitySubmission: accountID,
+		},
+		"username":       profile.Username,
+		"apiUrl":         base + "/jmap/api",
+		"downloadUrl":    base + "/jmap/download/{accountId}/{blobId}/{name}?accept={type}",
+		"uploadUrl":      base + "/jmap/upload/{accountId}",
+		"eventSourceUrl": base + "/jmap/eventsource?types={types}&closeafter={closeafter}&ping={ping}",
+		"state":          jmapSessionState,
+	}
+
+	writeJMAPJSON(w, http.StatusOK, session)
+}
+
+func (s *Server) jmapAPIHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getJMAPAccessToken(r)
+	if err != nil {
+		writeJMAPUnauthorized(w)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, jmapMaxSizeRequest))
+	if err != nil {
+		writeJMAPProblem(w, "urn:ietf:params:jmap:error:limit", "request is too large", "maxSizeRequest")
+		return
+	}
+	if !json.Valid(body) {
+		writeJMAPProblem(w, "urn:ietf:params:jmap:error:notJSON", "request body is not JSON", "")
+		return
+	}
+	var req jmapRequest
+	if err := json.Unmarshal(body, &req); err != nil || req.Using == nil || req.MethodCalls == nil {
+		writeJMAPProblem(w, "urn:ietf:params:jmap:error:notRequest", "request does not match the Request object", "")
+		return
+	}
+	for _, capability := range req.Using {
+		if capability != jmapCapabilityCore && capability != jmapCapabilityMail && capability != jmapCapabilitySubmission {
+			writeJMAPProblem(w, "urn:ietf:params:jmap:error:unknownCapability", "unknown capability "+capability, "")
+			return
+		}
+	}
+	if len(req.MethodCalls) > jmapMaxCallsInRequest {
+		writeJMAPProblem(w, "urn:ietf:params:jmap:error:limit", "too many method calls", "maxCallsInRequest")
+		return
+	}
+
+	profile, err := s.jmapProfile(ctx)
+	if err != nil {
+		writeJMAPGrpcError(w, err)
+		return
+	}
+
+	call := &jmapCall{ctx: ctx, accountID: profile.Id, createdIDs: req.CreatedIDs}
+	if call.createdIDs == nil {
+		call.createdIDs = make(map[string]string)
+	}
+
+	for _, inv := range req.MethodCalls {
+		method, ok := jmapMethods[inv.Name]
+		// Метод возможности, не объявленной в using, считается неизвестным
+		if !ok || !slices.Contains(req.Using, method.capability) {
+			call.respondError(inv.CallID, jmapError(jmapErrUnknownMethod, ""))
+			continue
+		}
+
+		args, methodErr := call.resolveReferences(inv.Args)
+		if methodErr != nil {
+			call.respondError(inv.CallID, methodErr)
+			continue
+		}
+
+		// Методы /set могут добавить ответы неявных вызовов, они идут после основного ответа
+		call.callID = inv.CallID
+		start := len(call.responses)
+		result, methodErr := method.handle(s, call, args)
+		if methodErr != nil {
+			call.responses = call.responses[:start]
+			call.respondError(inv.CallID, methodErr)
+			continue
+		}
+		implicit := slices.Clone(call.responses[start:])
+		call.responses = call.responses[:start]
+		call.respond(inv.Name, inv.CallID, result)
+		call.responses = append(call.responses, implicit...)
+	}
+
+	resp := jmapResponse{MethodResponses: call.responses, SessionState: jmapSessionState}
+	if req.CreatedIDs != nil {
+		resp.CreatedIDs = call.createdIDs
+	}
+	writeJMAPJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) jmapEcho(call *jmapCall, args json.RawMessage) (any, *jmapMethodError) {
+	return args, nil
+}
+
+func (s *Server) jmapProfile(ctx context.Context) (*profileproto.Profile, error) {
+	resp, err := s.profileClient.GetProfile(ctx, &profileproto.GetProfileRequest{})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Profile == nil {
+		return nil, status.Error(codes.NotFound, "profile not found")
+	}
+	return resp.Profile, nil
+}
+
+func (call *jmapCall) respond(name, callID string, result any) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		call.respondError(callID, jmapError(jmapErrServerFail, err.Error()))
+		return
+	}
+	call.responses = append(call.responses, jmapInvocation{Name: name, Args: data, CallID: callID})
+}
+
+func (call *jmapCall) respondError(callID string, methodErr *jmapMethodError) {
+	data, _ := json.Marshal(methodErr)
+	call.responses = append(call.responses, jmapInvocation{Name: "error", Args: data, CallID: callID})
+}
+
+// jmapAccountArgs - аргумент accountId, общий для всех методов почты
+type jmapAccountArgs struct {
+	AccountID string `json:"accountId"`
+}
+
+func (args *jmapAccountArgs) account() string {
+	return args.AccountID
+}
+
+// decodeArgs разбирает аргументы метода и проверяет, что они относятся к аккаунту пользователя
+func (call *jmapCall) decodeArgs(args json.RawMessage, target interface{ account() string }) *jmapMethodError {
+	if err := json.Unmarshal(args, target); err != nil {
+		return jmapError(jmapErrInvalidArguments, err.Error())
+	}
+	if target.account() != call.accountID {
+		return jmapError(jmapErrAccountNotFound, "")
+	}
+	return nil
+}
+
+// resolveID заменяет ссылку "#ключ" на идентификатор объекта, созданного в этом запросе
+func (call *jmapCall) resolveID(id string) (string, bool) {
+	key, found := strings.CutPrefix(id, "#")
+	if !found {
+		return id, true
+	}
+	created, ok := call.createdIDs[key]
+	return created, ok
+}
+
+// resolveReferences подставляет результаты предыдущих вызовов в аргументы вида "#имя"
+func (call *jmapCall) resolveReferences(args json.RawMessage) (json.RawMessage, *jmapMethodError) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(args, &fields); err != nil || fields == nil {
+		return nil, jmapError(jmapErrInvalidArguments, "arguments must be an object")
+	}
+
+	resolved := false
+	for name, value := range fields {
+		target, isReference := strings.CutPrefix(name, "#")
+		if !isReference {
+			continue
+		}
+		if _, ok := fields[target]; ok {
+			return nil, jmapError(jmapErrInvalidArguments, "both "+target+" and #"+target+" are set")
+		}
+
+		var ref jmapResultReference
+		if err := json.Unmarshal(value, &ref); err != nil {
+			return nil, jmapError(jmapErrInvalidResultReference, err.Error())
+		}
+		result, ok := call.referencedResult(ref)
+		if !ok {
+			return nil, jmapError(jmapErrInvalidResultReference, "cannot resolve #"+target)
+		}
+		data, err := json.Marshal(result)
+		if err != nil {
+			return nil, jmapError(jmapErrServerFail, err.Error())
+		}
+		delete(fields, name)
+		fields[target] = data
+		resolved = true
+	}
+
+	if !resolved {
+		return args, nil
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, jmapError(jmapErrServerFail, err.Error())
+	}
+	return data, nil
+}
+
+func (call *jmapCall) referencedResult(ref jmapResultReference) (any, bool) {
+	for _, resp := range call.responses {
+		if resp.CallID != ref.ResultOf {
+			continue
+		}
+		if resp.Name != ref.Name {
+			return nil, false
+		}
+		var value any
+		if err := json.Unmarshal(resp.Args, &value); err != nil {
+			return nil, false
+		}
+		return evaluateJSONPointer(value, ref.Path)
+	}
+	return nil, false
+}
+
+// evaluateJSONPointer вычисляет JSON Pointer (RFC 6901) с расширением JMAP: "*" применяет
+// остаток пути к каждому элементу массива, вложенные массивы результатов объединяются
+func evaluateJSONPointer(value any, path string) (any, bool) {
+	if path == "" {
+		return value, true
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, false
+	}
+	return evaluatePointerTokens(value, strings.Split(path[1:], "/"))
+}
+
+func evaluatePointerTokens(value any, tokens []string) (any, bool) {
+	if len(tokens) == 0 {
+		return value, true
+	}
+	token := strings.NewReplacer("~1", "/", "~0", "~").Replace(tokens[0])
+
+	switch v := value.(type) {
+	case map[string]any:
+		next, ok := v[token]
+		if !ok {
+			return nil, false
+		}
+		return evaluatePointerTokens(next, tokens[1:])
+	case []any:
+		if token == "*" {
+			result := []any{}
+			for _, item := range v {
+				itemResult, ok := evaluatePointerTokens(item, tokens[1:])
+				if !ok {
+					return nil, false
+				}
+				if items, isArray := itemResult.([]any); isArray {
+					result = append(result, items...)
+				} else {
+					result = append(result, itemResult)
+				}
+			}
+			return result, true
+		}
+		i, err := strconv.Atoi(token)
+		if err != nil || i < 0 || i >= len(v) {
+			return nil, false
+		}
+		return evaluatePointerTokens(v[i], tokens[1:])
+	}
+	return nil, false
+}
+
+// jmapEventSourceHandler отправляет StateChange, когда меняются данные почтового ящика
+// (RFC 8620, раздел 7.3). Изменения узнаются по событиям messages-service
+func (s *Server) jmapEventSourceHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getJMAPAccessToken(r)
+	if err != nil {
+		writeJMAPUnauthorized(w)
+		return
+	}
+	ctx, cancel := context.WithCancel(s.addTokenToContext(r.Context(), accessToken))
+	defer cancel()
+
+	query := r.URL.Query()
+	types := map[string]bool{"Mailbox": true, "Email": true, "Thread": true}
+	if value := query.Get("types"); value != "" && value != "*" {
+		types = make(map[string]bool)
+		for _, t := range strings.Split(value, ",") {
+			types[strings.TrimSpace(t)] = true
+		}
+	}
+	closeAfterState := query.Get("closeafter") == "state"
+	var ping time.Duration
+	if value := query.Get("ping"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds < 0 {
+			writeJMAPProblem(w, "urn:ietf:params:jmap:error:notRequest", "invalid ping interval", "")
+			return
+		}
+		// Слишком частые пинги ограничиваются снизу (RFC 8620 разрешает серверу поднять интервал)
+		if seconds > 0 {
+			ping = time.Duration(max(seconds, 5)) * time.Second
+		}
+	}
+
+	profile, err := s.jmapProfile(ctx)
+	if err != nil {
+		writeJMAPGrpcError(w, err)
+		return
+	}
+	state, err := s.messageClient.GetMailState(ctx, &messagesproto.GetMailStateRequest{})
+	if err != nil {
+		writeJMAPGrpcError(w, err)
+		return
+	}
+
+	stream, err := s.messageClient.Subscribe(ctx, &messagesproto.SubscribeRequest{})
+	if err != nil {
+		writeJMAPGrpcError(w, err)
+		return
+	}
+	header, err := stream.Header()
+	if err == nil && header == nil {
+		_, err = stream.Recv()
+	}
+	if err != nil {
+		writeJMAPGrpcError(w, err)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		logger.GetLogger(r.Context()).Warn("failed to reset write deadline: " + err.Error())
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	events := make(chan struct{})
+	go func() {
+		defer close(events)
+		for {
+			if _, err := stream.Recv(); err != nil {
+				return
+			}
+			select {
+			case events <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var pingC <-chan time.Time
+	if ping > 0 {
+		ticker := time.NewTicker(ping)
+		defer ticker.Stop()
+		pingC = ticker.C
+	}
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-events:
+			if !ok {
+				return
+			}
+			current, err := s.messageClient.GetMailState(ctx, &messagesproto.GetMailStateRequest{})
+			if err != nil {
+				return
+			}
+			changed := jmapChangedStates(state, current, types)
+			state = current
+			if len(changed) == 0 {
+				continue
+			}
+			data, err := json.Marshal(map[string]any{
+				"@type":   "StateChange",
+				"changed": map[string]any{profile.Id: changed},
+			})
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: state\ndata: %s\n\n", data)
+			if closeAfterState {
+				_ = rc.Flush()
+				return
+			}
+		case <-pingC:
+			fmt.Fprintf(w, "event: ping\ndata: {\"interval\":%d}\n\n", int(ping.Seconds()))
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// jmapChangedStates возвращает новые состояния тех типов данных, которые изменились
+func jmapChangedStates(old, current *messagesproto.GetMailStateResponse, types map[string]bool) map[string]string {
+	changed := make(map[string]string)
+	if types["Mailbox"] && current.MailboxState != old.MailboxState {
+		changed["Mailbox"] = current.MailboxState
+	}
+	if types["Email"] && current.EmailState != old.EmailState {
+		changed["Email"] = current.EmailState
+	}
+	if types["Thread"] && current.ThreadState != old.ThreadState {
+		changed["Thread"] = current.ThreadState
+	}
+	return changed
+}
+
+// getJMAPAccessToken берет токен из заголовка Authorization, который передают почтовые
+// клиенты, или из cookie браузера
+func getJMAPAccessToken(r *http.Request) (string, error) {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && token != "" {
+		return token, nil
+	}
+	return getAccessToken(r)
+}
+
+func jmapBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if forwarded := r.Header.Get("X-Forwarded-Proto"); forwarded != "" {
+		scheme = forwarded
+	}
+	return scheme + "://" + r.Host
+}
+
+func writeJMAPJSON(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeJMAPProblem отвечает на запрос, который нельзя выполнить целиком (RFC 7807)
+func writeJMAPProblem(w http.ResponseWriter, problemType, detail, limit string) {
+	problem := map[string]any{
+		"type":   problemType,
+		"status": http.StatusBadRequest,
+		"detail": detail,
+	}
+	if limit != "" {
+		problem["limit"] = limit
+	}
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(problem)
+}
+
+func writeJMAPUnauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="jmap"`)
+	writeJMAPJSON(w, http.StatusUnauthorized, map[string]any{
+		"type":   "about:blank",
+		"status": http.StatusUnauthorized,
+		"detail": "Access token required",
+	})
+}
+
+func writeJMAPGrpcError(w http.ResponseWriter, err error) {
+	if status.Code(err) == codes.Unauthenticated {
+		writeJMAPUnauthorized(w)
+		return
+	}
+	writeJMAPJSON(w, http.StatusInternalServerError, map[string]any{
+		"type":   "about:blank",
+		"status": http.StatusInternalServerError,
+		"detail": "Failed to get mail account",
+	})
+}
@@ -0,0 +1,1171 @@
+package gateway_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/messages-service/pkg/messagesproto"
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// jmapQueryPage - по сколько писем Email/query запрашивает у GetFolder
+	jmapQueryPage = 100
+	// jmapDefaultQueryLimit - сколько писем отдает Email/query, если клиент не задал limit
+	jmapDefaultQueryLimit = 50
+	jmapPreviewLength     = 256
+)
+
+var (
+	jmapMailboxProperties = []string{"id", "name", "parentId", "role", "sortOrder", "totalEmails", "unreadEmails",
+		"totalThreads", "unreadThreads", "myRights", "isSubscribed"}
+	jmapEmailProperties = []string{"id", "blobId", "threadId", "mailboxIds", "keywords", "size", "receivedAt",
+		"messageId", "inReplyTo", "references", "sender", "from", "to", "cc", "bcc", "replyTo", "subject",
+		"sentAt", "hasAttachment", "preview", "bodyValues", "textBody", "htmlBody", "attachments"}
+	jmapThreadProperties = []string{"id", "emailIds"}
+)
+
+// Порядок папок с ролями в клиенте, пользовательские папки идут после них
+var jmapMailboxSortOrder = map[string]int{"inbox": 1, "drafts": 2, "sent": 3, "junk": 4, "trash": 5}
+
+type jmapGetArgs struct {
+	jmapAccountArgs
+	// IDs - nil означает все объекты
+	IDs        *[]string `json:"ids"`
+	Properties []string  `json:"properties"`
+}
+
+type jmapChangesArgs struct {
+	jmapAccountArgs
+	SinceState string `json:"sinceState"`
+	MaxChanges *int   `json:"maxChanges"`
+}
+
+type jmapEmailQueryArgs struct {
+	jmapAccountArgs
+	Filter          map[string]json.RawMessage `json:"filter"`
+	Sort            []jmapComparator           `json:"sort"`
+	Position        int                        `json:"position"`
+	Anchor          *string                    `json:"anchor"`
+	Limit           *int                       `json:"limit"`
+	CalculateTotal  bool                       `json:"calculateTotal"`
+	CollapseThreads bool                       `json:"collapseThreads"`
+}
+
+type jmapComparator struct {
+	Property    string `json:"property"`
+	IsAscending *bool  `json:"isAscending"`
+}
+
+type jmapEmailGetArgs struct {
+	jmapGetArgs
+	FetchTextBodyValues bool `json:"fetchTextBodyValues"`
+	FetchHTMLBodyValues bool `json:"fetchHTMLBodyValues"`
+	FetchAllBodyValues  bool `json:"fetchAllBodyValues"`
+	MaxBodyValueBytes   int  `json:"maxBodyValueBytes"`
+}
+
+type jmapSetArgs struct {
+	jmapAccountArgs
+	IfInState *string                               `json:"ifInState"`
+	Create    map[string]json.RawMessage            `json:"create"`
+	Update    map[string]map[string]json.RawMessage `json:"update"`
+	Destroy   []string                              `json:"destroy"`
+}
+
+type jmapEmailSubmissionSetArgs struct {
+	jmapSetArgs
+	OnSuccessUpdateEmail  map[string]map[string]json.RawMessage `json:"onSuccessUpdateEmail"`
+	OnSuccessDestroyEmail []string                              `json:"onSuccessDestroyEmail"`
+}
+
+// jmapEmailCreate - черновик, который создает Email/set. Черновик хранит одного получателя
+type jmapEmailCreate struct {
+	MailboxIDs map[string]bool `json:"mailboxIds"`
+	Keywords   map[string]bool `json:"keywords"`
+	Subject    string          `json:"subject"`
+	// From игнорируется: отправитель - всегда владелец аккаунта
+	From     json.RawMessage    `json:"from"`
+	To       []jmapEmailAddress `json:"to"`
+	TextBody []struct {
+		PartID string `json:"partId"`
+		Type   string `json:"type"`
+	} `json:"textBody"`
+	BodyValues map[string]struct {
+		Value string `json:"value"`
+	} `json:"bodyValues"`
+}
+
+type jmapEmailAddress struct {
+	Name  *string `json:"name"`
+	Email string  `json:"email"`
+}
+
+type jmapSetResult struct {
+	AccountID    string                   `json:"accountId"`
+	OldState     *string                  `json:"oldState"`
+	NewState     string                   `json:"newState"`
+	Created      map[string]any           `json:"created"`
+	Updated      map[string]any           `json:"updated"`
+	Destroyed    []string                 `json:"destroyed"`
+	NotCreated   map[string]*jmapSetError `json:"notCreated"`
+	NotUpdated   map[string]*jmapSetError `json:"notUpdated"`
+	NotDestroyed map[string]*jmapSetError `json:"notDestroyed"`
+}
+
+func (result *jmapSetResult) created(id string, object any) {
+	if result.Created == nil {
+		result.Created = make(map[string]any)
+	}
+	result.Created[id] = object
+}
+
+func (result *jmapSetResult) updated(id string) {
+	if result.Updated == nil {
+		result.Updated = make(map[string]any)
+	}
+	result.Updated[id] = nil
+}
+
+func (result *jmapSetResult) notCreated(id string, setErr *jmapSetError) {
+	if result.NotCreated == nil {
+		result.NotCreated = make(map[string]*jmapSetError)
+	}
+	result.NotCreated[id] = setErr
+}
+
+func (result *jmapSetResult) notUpdated(id string, setErr *jmapSetError) {
+	if result.NotUpdated == nil {
+		result.NotUpdated = make(map[string]*jmapSetError)
+	}
+	result.NotUpdated[id] = setErr
+}
+
+func (result *jmapSetResult) notDestroyed(id string, setErr *jmapSetError) {
+	if result.NotDestroyed == nil {
+		result.NotDestroyed = make(map[string]*jmapSetError)
+	}
+	result.NotDestroyed[id] = setErr
+}
+
+func (s *Server) jmapMailboxGet(call *jmapCall, rawArgs json.RawMessage) (any, *jmapMethodError) {
+	var args jmapGetArgs
+	if err := call.decodeArgs(rawArgs, &args); err != nil {
+		return nil, err
+	}
+	if err := validateJMAPProperties(args.Properties, jmapMailboxProperties); err != nil {
+		return nil, err
+	}
+
+	state, methodErr := s.jmapState(call)
+	if methodErr != nil {
+		return nil, methodErr
+	}
+	folders, methodErr := s.jmapFolders(call)
+	if methodErr != nil {
+		return nil, methodErr
+	}
+
+	list := []map[string]any{}
+	notFound := []string{}
+	if args.IDs == nil {
+		for _, folder := range folders {
+			list = append(list, selectJMAPProperties(jmapMailbox(folder), args.Properties))
+		}
+	} else {
+		for _, id := range *args.IDs {
+			index := slices.IndexFunc(folders, func(folder *messagesproto.Folder) bool { return folder.FolderId == id })
+			if index < 0 {
+				notFound = append(notFound, id)
+				continue
+			}
+			list = append(list, selectJMAPProperties(jmapMailbox(folders[index]), args.Properties))
+		}
+	}
+
+	return map[string]any{
+		"accountId": call.accountID,
+		"state":     state.MailboxState,
+		"list":      list,
+		"notFound":  notFound,
+	}, nil
+}
+
+func (s *Server) jmapMailboxChanges(call *jmapCall, args json.RawMessage) (any, *jmapMethodError) {
+	result, err := s.jmapChanges(call, args, domain.MailObjectMailbox)
+	if err != nil {
+		return nil, err
+	}
+	// Меняются только счетчики или сама папка - клиенту проще перечитать папку целиком
+	result["updatedProperties"] = nil
+	return result, nil
+}
+
+func (s *Server) jmapEmailChanges(call *jmapCall, args json.RawMessage) (any, *jmapMethodError) {
+	return s.jmapChanges(call, args, domain.MailObjectEmail)
+}
+
+func (s *Server) jmapThreadChanges(call *jmapCall, args json.RawMessage) (any, *jmapMethodError) {
+	return s.jmapChanges(call, args, domain.MailObjectThread)
+}
+
+func (s *Server) jmapChanges(call *jmapCall, rawArgs json.RawMessage, objectType domain.MailObjectType) (map[string]any, *jmapMethodError) {
+	var args jmapChangesArgs
+	if err := call.decodeArgs(rawArgs, &args); err != nil {
+		return nil, err
+	}
+	if args.SinceState == "" {
+		return nil, jmapError(jmapErrInvalidArguments, "sinceState is required")
+	}
+	var maxChanges int32
+	if args.MaxChanges != nil {
+		if *args.MaxChanges <= 0 {
+			return nil, jmapError(jmapErrInvalidArguments, "maxChanges must be positive")
+		}
+		maxChanges = int32(min(*args.MaxChanges, jmapMaxObjectsInGet))
+	}
+
+	resp, err := s.messageClient.GetMailChanges(call.ctx, &messagesproto.GetMailChangesRequest{
+		Type:       string(objectType),
+		SinceState: args.SinceState,
+		MaxChanges: maxChanges,
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.FailedPrecondition, codes.InvalidArgument:
+			return nil, jmapError(jmapErrCannotCalculateChanges, status.Convert(err).Message())
+		}
+		return nil, jmapError(jmapErrServerFail, status.Convert(err).Message())
+	}
+
+	return map[string]any{
+		"accountId":      call.accountID,
+		"oldState":       resp.OldState,
+		"newState":       resp.NewState,
+		"hasMoreChanges": resp.HasMoreChanges,
+		"created":        nonNilStrings(resp.Created),
+		"updated":        nonNilStrings(resp.Updated),
+		"destroyed":      nonNilStrings(resp.Destroyed),
+	}, nil
+}
+
+// jmapEmailQuery листает папку через GetFolder. Фильтр обязан содержать inMailbox:
+// списка писем по всем папкам у messages-service нет
+func (s *Server) jmapEmailQuery(call *jmapCall, rawArgs json.RawMessage) (any, *jmapMethodError) {
+	var args jmapEmailQueryArgs
+	if err := call.decodeArgs(rawArgs, &args); err != nil {
+		return nil, err
+	}
+	if args.Anchor != nil {
+		return nil, jmapError(jmapErrInvalidArguments, "anchor is not supported")
+	}
+	if args.CollapseThreads {
+		return nil, jmapError(jmapErrInvalidArguments, "collapseThreads is not supported")
+	}
+	limit := jmapDefaultQueryLimit
+	if args.Limit != nil {
+		if *args.Limit < 0 {
+			return nil, jmapError(jmapErrInvalidArguments, "limit must not be negative")
+		}
+		limit = *args.Limit
+	}
+	limitCapped := limit > jmapMaxObjectsInGet
+	limit = min(limit, jmapMaxObjectsInGet)
+
+	req, filtered, methodErr := jmapFolderRequest(args.Filter)
+	if methodErr != nil {
+		return nil, methodErr
+	}
+	if req.Sort, methodErr = jmapFolderSort(args.Sort); methodErr != nil {
+		return nil, methodErr
+	}
+
+	state, methodErr := s.jmapState(call)
+	if methodErr != nil {
+		return nil, methodErr
+	}
+	folders, methodErr := s.jmapFolders(call)
+	if methodErr != nil {
+		return nil, methodErr
+	}
+
+	result := map[string]any{
+		"accountId":           call.accountID,
+		"queryState":          state.EmailState,
+		"canCalculateChanges": false,
+	}
+	if limitCapped {
+		result["limit"] = limit
+	}
+
+	// Письма несуществующей папки - пустой список
+	if !slices.ContainsFunc(folders, func(folder *messagesproto.Folder) bool { return folder.FolderId == req.FolderId }) {
+		result["position"] = 0
+		result["ids"] = []string{}
+		if args.CalculateTotal {
+			result["total"] = 0
+		}
+		return result, nil
+	}
+
+	// Счетчик папки не учитывает фильтры, поэтому для точного total отфильтрованный
+	// список дочитывается до конца. Отрицательная позиция тоже считается от конца списка
+	readAll := args.Position < 0 || (args.CalculateTotal && filtered)
+	need := args.Position + limit
+
+	var ids []string
+	total := -1
+	for {
+		resp, err := s.messageClient.GetFolder(call.ctx, req)
+		if err != nil {
+			if status.Code(err) == codes.InvalidArgument {
+				return nil, jmapError(jmapErrInvalidArguments, status.Convert(err).Message())
+			}
+			return nil, jmapError(jmapErrServerFail, status.Convert(err).Message())
+		}
+		if total < 0 && !filtered {
+			total, _ = strconv.Atoi(resp.MessageTotal)
+		}
+		for _, message := range resp.Messages {
+			ids = append(ids, message.Id)
+		}
+
+		if !readAll && len(ids) >= need {
+			break
+		}
+		if resp.Pagination == nil || resp.Pagination.NextCursor == "" {
+			if filtered {
+				total = len(ids)
+			}
+			break
+		}
+		req.Cursor = resp.Pagination.NextCursor
+	}
+
+	position := args.Position
+	if position < 0 {
+		position = max(0, len(ids)+position)
+	}
+	page := []string{}
+	if position < len(ids) {
+		page = ids[position:min(position+limit, len(ids))]
+	}
+
+	result["position"] = position
+	result["ids"] = page
+	if args.CalculateTotal {
+		result["total"] = total
+	}
+	return result, nil
+}
+
+func (s *Server) jmapEmailQueryChanges(call *jmapCall, rawArgs json.RawMessage) (any, *jmapMethodError) {
+	var args jmapAccountArgs
+	if err := call.decodeArgs(rawArgs, &args); err != nil {
+		return nil, err
+	}
+	return nil, jmapError(jmapErrCannotCalculateChanges, "query changes are not tracked")
+}
+
+// jmapFolderRequest переводит условие фильтра JMAP в параметры GetFolder. filtered
+// сообщает, что кроме папки заданы другие условия
+func jmapFolderRequest(filter map[string]json.RawMessage) (req *messagesproto.GetFolderRequest, filtered bool, methodErr *jmapMethodError) {
+	req = &messagesproto.GetFolderRequest{Limit: strconv.Itoa(jmapQueryPage)}
+	if _, ok := filter["operator"]; ok {
+		return nil, false, jmapError(jmapErrUnsupportedFilter, "filter operators are not supported")
+	}
+
+	for name, raw := range filter {
+		var value any
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, false, jmapError(jmapErrInvalidArguments, err.Error())
+		}
+		text, isString := value.(string)
+
+		switch {
+		case name == "inMailbox" && isString:
+			req.FolderId = text
+			continue
+		case name == "hasKeyword" && isString && strings.EqualFold(text, "$flagged"):
+			req.StarredOnly = "true"
+		case name == "notKeyword" && isString && strings.EqualFold(text, "$seen"):
+			req.UnreadOnly = "true"
+		case name == "from" && isString:
+			req.Sender = text
+		case (name == "after" || name == "before") && isString:
+			date, err := time.Parse(time.RFC3339, text)
+			if err != nil {
+				return nil, false, jmapError(jmapErrInvalidArguments, name+" must be a UTCDate")
+			}
+			if name == "after" {
+				req.After = date.Format(time.RFC3339)
+			} else {
+				req.Before = date.Format(time.RFC3339)
+			}
+		case name == "hasAttachment" && value == true:
+			req.HasAttachments = "true"
+		default:
+			return nil, false, jmapError(jmapErrUnsupportedFilter, "unsupported filter condition "+name)
+		}
+		filtered = true
+	}
+
+	if req.FolderId == "" {
+		return nil, false, jmapError(jmapErrUnsupportedFilter, "inMailbox is required")
+	}
+	return req, filtered, nil
+}
+
+// jmapFolderSort переводит сортировку JMAP в сортировку GetFolder. По адресу и теме
+// список сортируется только по возрастанию, по размеру - только по убыванию
+func jmapFolderSort(sort []jmapComparator) (string, *jmapMethodError) {
+	if len(sort) == 0 {
+		return string(domain.SortDateDesc), nil
+	}
+	if len(sort) > 1 {
+		return "", jmapError(jmapErrUnsupportedSort, "only one sort property is supported")
+	}
+
+	ascending := sort[0].IsAscending == nil || *sort[0].IsAscending
+	switch {
+	case sort[0].Property == "receivedAt" && ascending:
+		return string(domain.SortDateAsc), nil
+	case sort[0].Property == "receivedAt":
+		return string(domain.SortDateDesc), nil
+	case sort[0].Property == "from" && ascending:
+		return string(domain.SortSender), nil
+	case sort[0].Property == "subject" && ascending:
+		return string(domain.SortTopic), nil
+	case sort[0].Property == "size" && !ascending:
+		return string(domain.SortSize), nil
+	}
+	return "", jmapError(jmapErrUnsupportedSort, "unsupported sort "+sort[0].Property)
+}
+
+func (s *Server) jmapEmailGet(call *jmapCall, rawArgs json.RawMessage) (any, *jmapMethodError) {
+	var args jmapEmailGetArgs
+	if err := call.decodeArgs(rawArgs, &args); err != nil {
+		return nil, err
+	}
+	if err := validateJMAPProperties(args.Properties, jmapEmailProperties); err != nil {
+		return nil, err
+	}
+	if args.IDs == nil || len(*args.IDs) > jmapMaxObjectsInGet {
+		return nil, jmapError(jmapErrRequestTooLarge, "")
+	}
+
+	state, methodErr := s.jmapState(call)
+	if methodErr != nil {
+		return nil, methodErr
+	}
+	drafts, methodErr := s.jmapDraftsFolder(call)
+	if methodErr != nil {
+		return nil, methodErr
+	}
+
+	// Текст письма - единственная часть, поэтому все варианты fetch*BodyValues равнозначны
+	fetchBody := args.FetchTextBodyValues || args.FetchHTMLBodyValues || args.FetchAllBodyValues
+
+	list := []map[string]any{}
+	notFound := []string{}
+	for _, id := range *args.IDs {
+		messageID, ok := call.resolveID(id)
+		if !ok {
+			notFound = append(notFound, id)
+			continue
+		}
+		resp, err := s.messageClient.MessagePage(call.ctx, &messagesproto.MessagePageRequest{MessageId: messageID, Peek: true})
+		if err != nil {
+			if jmapObjectMissing(err) {
+				notFound = append(notFound, id)
+				continue
+			}
+			return nil, jmapError(jmapErrServerFail, status.Convert(err).Message())
+		}
+		email := jmapEmail(resp.Message, drafts, fetchBody, args.MaxBodyValueBytes)
+		list = append(list, selectJMAPProperties(email, args.Properties))
+	}
+
+	return map[string]any{
+		"accountId": call.accountID,
+		"state":     state.EmailState,
+		"list":      list,
+		"notFound":  notFound,
+	}, nil
+}
+
+func (s *Server) jmapEmailSet(call *jmapCall, rawArgs json.RawMessage) (any, *jmapMethodError) {
+	var args jmapSetArgs
+	if err := call.decodeArgs(rawArgs, &args); err != nil {
+		return nil, err
+	}
+	if len(args.Create)+len(args.Update)+len(args.Destroy) > jmapMaxObjectsInSet {
+		return nil, jmapError(jmapErrRequestTooLarge, "")
+	}
+
+	state, methodErr := s.jmapState(call)
+	if methodErr != nil {
+		return nil, methodErr
+	}
+	if args.IfInState != nil && *args.IfInState != state.EmailState {
+		return nil, jmapError(jmapErrStateMismatch, "")
+	}
+
+	result := &jmapSetResult{AccountID: call.accountID, OldState: &state.EmailState}
+	for creationID, raw := range args.Create {
+		email, setErr := s.jmapCreateDraft(call, raw)
+		if setErr != nil {
+			result.notCreated(creationID, setErr)
+			continue
+		}
+		call.createdIDs[creationID] = email["id"].(string)
+		result.created(creationID, email)
+	}
+	s.jmapApplyEmailChanges(call, result, args.Update, args.Destroy)
+
+	if methodErr := s.jmapFinishSet(call, result); methodErr != nil {
+		return nil, methodErr
+	}
+	return result, nil
+}
+
+// jmapApplyEmailChanges меняет и удаляет письма для Email/set и неявного Email/set
+// после отправки письма
+func (s *Server) jmapApplyEmailChanges(call *jmapCall, result *jmapSetResult, update map[string]map[string]json.RawMessage, destroy []string) {
+	for id, patch := range update {
+		messageID, ok := call.resolveID(id)
+		if !ok {
+			result.notUpdated(id, &jmapSetError{Type: "notFound"})
+			continue
+		}
+		if setErr := s.jmapUpdateEmail(call, messageID, patch); setErr != nil {
+			result.notUpdated(id, setErr)
+			continue
+		}
+		result.updated(id)
+	}
+
+	for _, id := range destroy {
+		messageID, ok := call.resolveID(id)
+		if !ok {
+			result.notDestroyed(id, &jmapSetError{Type: "notFound"})
+			continue
+		}
+		if _, err := s.messageClient.DeletePermanently(call.ctx, &messagesproto.DeletePermanentlyRequest{MessageId: messageID}); err != nil {
+			result.notDestroyed(id, jmapSetErrorFromGrpc(err))
+			continue
+		}
+		result.Destroyed = append(result.Destroyed, id)
+	}
+}
+
+func (s *Server) jmapFinishSet(call *jmapCall, result *jmapSetResult) *jmapMethodError {
+	state, err := s.messageClient.GetMailState(call.ctx, &messagesproto.GetMailStateRequest{})
+	if err != nil {
+		return jmapError(jmapErrServerFail, status.Convert(err).Message())
+	}
+	result.NewState = state.EmailState
+	return nil
+}
+
+func (s *Server) jmapCreateDraft(call *jmapCall, raw json.RawMessage) (map[string]any, *jmapSetError) {
+	var create jmapEmailCreate
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&create); err != nil {
+		return nil, &jmapSetError{Type: "invalidProperties", Description: err.Error()}
+	}
+
+	drafts, methodErr := s.jmapDraftsFolder(call)
+	if methodErr != nil {
+		return nil, &jmapSetError{Type: jmapErrServerFail, Description: methodErr.Description}
+	}
+	// Письма создаются только как черновики, отправляются они через EmailSubmission/set
+	if drafts == "" || !maps.Equal(create.MailboxIDs, map[string]bool{drafts: true}) {
+		return nil, &jmapSetError{Type: "invalidProperties", Description: "emails can only be created in the drafts mailbox", Properties: []string{"mailboxIds"}}
+	}
+	if len(create.To) > 1 {
+		return nil, &jmapSetError{Type: "invalidProperties", Description: "a draft has a single recipient", Properties: []string{"to"}}
+	}
+	flags := &messagesproto.SetFlagsRequest{}
+	for keyword, set := range create.Keywords {
+		if field := jmapKeywordFlag(flags, keyword); field != nil && set {
+			*field = "true"
+		} else if !strings.EqualFold(keyword, "$draft") {
+			return nil, &jmapSetError{Type: "invalidProperties", Description: "unsupported keyword " + keyword, Properties: []string{"keywords"}}
+		}
+	}
+
+	var text string
+	if len(create.TextBody) > 0 {
+		body, ok := create.BodyValues[create.TextBody[0].PartID]
+		if !ok {
+			return nil, &jmapSetError{Type: "invalidProperties", Description: "text body part has no value", Properties: []string{"bodyValues"}}
+		}
+		text = body.Value
+	}
+
+	req := &messagesproto.SaveDraftRequest{Topic: create.Subject, Text: text}
+	for _, to := range create.To {
+		req.Receivers = append(req.Receivers, &messagesproto.Receiver{Email: to.Email})
+	}
+	resp, err := s.messageClient.SaveDraft(call.ctx, req)
+	if err != nil {
+		return nil, jmapSetErrorFromGrpc(err)
+	}
+
+	if flags.Read != "" || flags.Starred != "" || flags.Important != "" || flags.Answered != "" || flags.Forwarded != "" {
+		flags.MessageId = resp.DraftId
+		if _, err := s.messageClient.SetFlags(call.ctx, flags); err != nil {
+			return nil, jmapSetErrorFromGrpc(err)
+		}
+	}
+
+	email := map[string]any{"id": resp.DraftId, "blobId": nil, "threadId": nil, "size": len(text)}
+	// Цепочку черновику назначает messages-service, клиенту она нужна сразу
+	if page, err := s.messageClient.MessagePage(call.ctx, &messagesproto.MessagePageRequest{MessageId: resp.DraftId, Peek: true}); err == nil {
+		email["threadId"] = jmapThreadID(page.Message)
+		email["size"], _ = strconv.Atoi(page.Message.Size)
+	}
+	return email, nil
+}
+
+// jmapUpdateEmail меняет флаги и папку письма. Письмо лежит ровно в одной папке, поэтому
+// после изменения mailboxIds должна остаться одна папка
+func (s *Server) jmapUpdateEmail(call *jmapCall, messageID string, patch map[string]json.RawMessage) *jmapSetError {
+	flags := &messagesproto.SetFlagsRequest{MessageId: messageID}
+	var mailboxes map[string]bool
+	mailboxPatch := make(map[string]bool)
+
+	for property, raw := range patch {
+		switch {
+		case property == "keywords":
+			var keywords map[string]bool
+			if err := json.Unmarshal(raw, &keywords); err != nil {
+				return &jmapSetError{Type: "invalidProperties", Properties: []string{property}}
+			}
+			for _, keyword := range []string{"$seen", "$flagged", "$important", "$answered", "$forwarded"} {
+				*jmapKeywordFlag(flags, keyword) = "false"
+			}
+			for keyword, set := range keywords {
+				field := jmapKeywordFlag(flags, keyword)
+				if field == nil && strings.EqualFold(keyword, "$draft") {
+					continue
+				}
+				if field == nil || !set {
+					return &jmapSetError{Type: "invalidProperties", Description: "unsupported keyword " + keyword, Properties: []string{property}}
+				}
+				*field = "true"
+			}
+		case strings.HasPrefix(property, "keywords/"):
+			keyword := jmapUnescapePointer(strings.TrimPrefix(property, "keywords/"))
+			set, ok := jmapPatchValue(raw)
+			field := jmapKeywordFlag(flags, keyword)
+			if field == nil && strings.EqualFold(keyword, "$draft") {
+				continue
+			}
+			if field == nil || !ok {
+				return &jmapSetError{Type: "invalidProperties", Description: "unsupported keyword " + keyword, Properties: []string{property}}
+			}
+			*field = strconv.FormatBool(set)
+		case property == "mailboxIds":
+			if err := json.Unmarshal(raw, &mailboxes); err != nil {
+				return &jmapSetError{Type: "invalidProperties", Properties: []string{property}}
+			}
+		case strings.HasPrefix(property, "mailboxIds/"):
+			set, ok := jmapPatchValue(raw)
+			if !ok {
+				return &jmapSetError{Type: "invalidProperties", Properties: []string{property}}
+			}
+			mailboxPatch[jmapUnescapePointer(strings.TrimPrefix(property, "mailboxIds/"))] = set
+		default:
+			return &jmapSetError{Type: "invalidProperties", Description: "property cannot be changed", Properties: []string{property}}
+		}
+	}
+
+	if mailboxes != nil || len(mailboxPatch) > 0 {
+		if setErr := s.jmapMoveEmail(call, messageID, mailboxes, mailboxPatch); setErr != nil {
+			return setErr
+		}
+	}
+
+	if flags.Read != "" || flags.Starred != "" || flags.Important != "" || flags.Answered != "" || flags.Forwarded != "" {
+		if _, err := s.messageClient.SetFlags(call.ctx, flags); err != nil {
+			return jmapSetErrorFromGrpc(err)
+		}
+	}
+	return nil
+}
+
+func (s *Server) jmapMoveEmail(call *jmapCall, messageID string, mailboxes, mailboxPatch map[string]bool) *jmapSetError {
+	page, err := s.messageClient.MessagePage(call.ctx, &messagesproto.MessagePageRequest{MessageId: messageID, Peek: true})
+	if err != nil {
+		return jmapSetErrorFromGrpc(err)
+	}
+	current := make(map[string]bool)
+	for _, id := range page.Message.FolderIds {
+		current[id] = true
+	}
+
+	target := mailboxes
+	if target == nil {
+		target = maps.Clone(current)
+	}
+	for id, set := range mailboxPatch {
+		if set {
+			target[id] = true
+		} else {
+			delete(target, id)
+		}
+	}
+	maps.DeleteFunc(target, func(id string, set bool) bool { return !set })
+
+	if len(target) != 1 {
+		return &jmapSetError{Type: "invalidProperties", Description: "an email must be in exactly one mailbox", Properties: []string{"mailboxIds"}}
+	}
+	if maps.Equal(target, current) {
+		return nil
+	}
+
+	var folderID string
+	for id := range target {
+		folderID = id
+	}
+	if _, err := s.messageClient.MoveToFolder(call.ctx, &messagesproto.MoveToFolderRequest{MessageId: messageID, FolderId: folderID}); err != nil {
+		if status.Code(err) == codes.InvalidArgument {
+			return &jmapSetError{Type: "invalidProperties", Properties: []string{"mailboxIds"}}
+		}
+		return jmapSetErrorFromGrpc(err)
+	}
+	return nil
+}
+
+func (s *Server) jmapThreadGet(call *jmapCall, rawArgs json.RawMessage) (any, *jmapMethodError) {
+	var args jmapGetArgs
+	if err := call.decodeArgs(rawArgs, &args); err != nil {
+		return nil, err
+	}
+	if err := validateJMAPProperties(args.Properties, jmapThreadProperties); err != nil {
+		return nil, err
+	}
+	if args.IDs == nil || len(*args.IDs) > jmapMaxObjectsInGet {
+		return nil, jmapError(jmapErrRequestTooLarge, "")
+	}
+
+	state, methodErr := s.jmapState(call)
+	if methodErr != nil {
+		return nil, methodErr
+	}
+
+	list := []map[string]any{}
+	notFound := []string{}
+	if len(*args.IDs) > 0 {
+		resp, err := s.messageClient.GetThreads(call.ctx, &messagesproto.GetThreadsRequest{ThreadIds: *args.IDs})
+		if err != nil {
+			return nil, jmapError(jmapErrServerFail, status.Convert(err).Message())
+		}
+		for _, thread := range resp.Threads {
+			list = append(list, selectJMAPProperties(map[string]any{
+				"id":       thread.ThreadId,
+				"emailIds": nonNilStrings(thread.MessageIds),
+			}, args.Properties))
+		}
+		notFound = append(notFound, resp.NotFound...)
+	}
+
+	return map[string]any{
+		"accountId": call.accountID,
+		"state":     state.ThreadState,
+		"list":      list,
+		"notFound":  notFound,
+	}, nil
+}
+
+// jmapEmailSubmissionSet отправляет черновики. Отправка сразу окончательная, поэтому
+// отправленные письма не хранятся как объекты EmailSubmission и не меняются
+func (s *Server) jmapEmailSubmissionSet(call *jmapCall, rawArgs json.RawMessage) (any, *jmapMethodError) {
+	var args jmapEmailSubmissionSetArgs
+	if err := call.decodeArgs(rawArgs, &args); err != nil {
+		return nil, err
+	}
+	if len(args.Create)+len(args.Update)+len(args.Destroy) > jmapMaxObjectsInSet {
+		return nil, jmapError(jmapErrRequestTooLarge, "")
+	}
+	// Отправки не хранятся, поэтому их состояние не меняется
+	const submissionState = "0"
+	if args.IfInState != nil && *args.IfInState != submissionState {
+		return nil, jmapError(jmapErrStateMismatch, "")
+	}
+
+	oldState := submissionState
+	result := &jmapSetResult{AccountID: call.accountID, OldState: &oldState, NewState: submissionState}
+	// Отправленные письма по идентификаторам отправок, для onSuccess*Email
+	sent := make(map[string]string)
+
+	for creationID, raw := range args.Create {
+		var create struct {
+			IdentityID string `json:"identityId"`
+			EmailID    string `json:"emailId"`
+		}
+		if err := json.Unmarshal(raw, &create); err != nil || create.EmailID == "" {
+			result.notCreated(creationID, &jmapSetError{Type: "invalidProperties", Properties: []string{"emailId"}})
+			continue
+		}
+		emailID, ok := call.resolveID(create.EmailID)
+		if !ok {
+			result.notCreated(creationID, &jmapSetError{Type: "invalidProperties", Properties: []string{"emailId"}})
+			continue
+		}
+
+		if _, err := s.messageClient.SendDraft(call.ctx, &messagesproto.SendDraftRequest{DraftId: emailID}); err != nil {
+			setErr := jmapSetErrorFromGrpc(err)
+			if setErr.Type == "notFound" {
+				setErr = &jmapSetError{Type: "invalidProperties", Description: "email is not a draft", Properties: []string{"emailId"}}
+			}
+			result.notCreated(creationID, setErr)
+			continue
+		}
+
+		submissionID := "s" + emailID
+		sent["#"+creationID] = emailID
+		sent[submissionID] = emailID
+		call.createdIDs[creationID] = submissionID
+		result.created(creationID, map[string]any{
+			"id":         submissionID,
+			"identityId": create.IdentityID,
+			"emailId":    emailID,
+			"undoStatus": "final",
+			"sendAt":     time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+	for id := range args.Update {
+		result.notUpdated(id, &jmapSetError{Type: "notFound"})
+	}
+	for _, id := range args.Destroy {
+		result.notDestroyed(id, &jmapSetError{Type: "notFound"})
+	}
+
+	// Неявный Email/set над отправленными письмами. SendDraft уже перенес черновик
+	// в отправленные, поэтому обычный патч клиента (из черновиков в отправленные) ничего не меняет
+	update := make(map[string]map[string]json.RawMessage)
+	for submissionID, patch := range args.OnSuccessUpdateEmail {
+		if emailID, ok := sent[submissionID]; ok {
+			update[emailID] = patch
+		}
+	}
+	var destroy []string
+	for _, submissionID := range args.OnSuccessDestroyEmail {
+		if emailID, ok := sent[submissionID]; ok {
+			destroy = append(destroy, emailID)
+		}
+	}
+	if len(update) > 0 || len(destroy) > 0 {
+		emailSet := &jmapSetResult{AccountID: call.accountID}
+		s.jmapApplyEmailChanges(call, emailSet, update, destroy)
+		if methodErr := s.jmapFinishSet(call, emailSet); methodErr != nil {
+			return nil, methodErr
+		}
+		call.respond("Email/set", call.callID, emailSet)
+	}
+
+	return result, nil
+}
+
+func (s *Server) jmapState(call *jmapCall) (*messagesproto.GetMailStateResponse, *jmapMethodError) {
+	state, err := s.messageClient.GetMailState(call.ctx, &messagesproto.GetMailStateRequest{})
+	if err != nil {
+		return nil, jmapError(jmapErrServerFail, status.Convert(err).Message())
+	}
+	return state, nil
+}
+
+// jmapFolders возвращает папки пользователя без виртуальной папки помеченных писем
+func (s *Server) jmapFolders(call *jmapCall) ([]*messagesproto.Folder, *jmapMethodError) {
+	if call.folders != nil {
+		return call.folders, nil
+	}
+	resp, err := s.messageClient.GetFolders(call.ctx, &messagesproto.GetFoldersRequest{})
+	if err != nil {
+		return nil, jmapError(jmapErrServerFail, status.Convert(err).Message())
+	}
+	call.folders = []*messagesproto.Folder{}
+	for _, folder := range resp.Folders {
+		if folder.FolderType != string(domain.FolderStarred) {
+			call.folders = append(call.folders, folder)
+		}
+	}
+	return call.folders, nil
+}
+
+func (s *Server) jmapDraftsFolder(call *jmapCall) (string, *jmapMethodError) {
+	folders, err := s.jmapFolders(call)
+	if err != nil {
+		return "", err
+	}
+	for _, folder := range folders {
+		if jmapMailboxRole(folder.FolderType) == "drafts" {
+			return folder.FolderId, nil
+		}
+	}
+	return "", nil
+}
+
+// jmapMailboxRole возвращает роль папки (RFC 8621, раздел 2). В базе папка черновиков
+// записана как draft, в domain - как drafts
+func jmapMailboxRole(folderType string) any {
+	switch folderType {
+	case string(domain.FolderInbox), string(domain.FolderSent), string(domain.FolderTrash):
+		return folderType
+	case string(domain.FolderDrafts), "draft":
+		return "drafts"
+	case string(domain.FolderSpam):
+		return "junk"
+	}
+	return nil
+}
+
+func jmapMailbox(folder *messagesproto.Folder) map[string]any {
+	role := jmapMailboxRole(folder.FolderType)
+	sortOrder := 10
+	if name, ok := role.(string); ok {
+		sortOrder = jmapMailboxSortOrder[name]
+	}
+	var parentID any
+	if folder.ParentId != "" {
+		parentID = folder.ParentId
+	}
+	total, _ := strconv.Atoi(folder.MessageTotal)
+	unread, _ := strconv.Atoi(folder.MessageUnread)
+
+	return map[string]any{
+		"id":           folder.FolderId,
+		"name":         folder.FolderName,
+		"parentId":     parentID,
+		"role":         role,
+		"sortOrder":    sortOrder,
+		"totalEmails":  total,
+		"unreadEmails": unread,
+		// Цепочки по папкам не считаются, вместо них отдается число писем
+		"totalThreads":  total,
+		"unreadThreads": unread,
+		"myRights": map[string]bool{
+			"mayReadItems":   true,
+			"mayAddItems":    true,
+			"mayRemoveItems": true,
+			"maySetSeen":     true,
+			"maySetKeywords": true,
+			"mayCreateChild": false,
+			"mayRename":      false,
+			"mayDelete":      false,
+			"maySubmit":      role == "drafts",
+		},
+		"isSubscribed": true,
+	}
+}
+
+func jmapEmail(message *messagesproto.FullMessage, draftsFolder string, fetchBody bool, maxBodyBytes int) map[string]any {
+	mailboxIDs := make(map[string]bool)
+	for _, id := range message.FolderIds {
+		mailboxIDs[id] = true
+	}
+	keywords := make(map[string]bool)
+	for keyword, value := range map[string]string{
+		"$seen":      message.IsRead,
+		"$flagged":   message.IsStarred,
+		"$important": message.IsImportant,
+		"$answered":  message.IsAnswered,
+		"$forwarded": message.IsForwarded,
+	} {
+		if value == "true" {
+			keywords[keyword] = true
+		}
+	}
+	if draftsFolder != "" && mailboxIDs[draftsFolder] {
+		keywords["$draft"] = true
+	}
+
+	receivedAt := message.Datetime
+	if date, err := time.Parse(time.RFC3339, message.Datetime); err == nil {
+		receivedAt = date.UTC().Format(time.RFC3339)
+	}
+	size, _ := strconv.Atoi(message.Size)
+
+	var from []jmapEmailAddress
+	if message.Sender != nil {
+		from = append(from, jmapEmailAddress{Name: &message.Sender.Username, Email: message.Sender.Email})
+	}
+	to := []jmapEmailAddress{}
+	for _, receiver := range message.Receivers {
+		to = append(to, jmapEmailAddress{Email: receiver})
+	}
+
+	textPart := map[string]any{
+		"partId":  "1",
+		"blobId":  nil,
+		"size":    len(message.Text),
+		"type":    "text/plain",
+		"charset": "utf-8",
+	}
+	attachments := []map[string]any{}
+	for i, file := range message.Files {
+		fileSize, _ := strconv.Atoi(file.Size)
+		attachments = append(attachments, map[string]any{
+			"partId":      strconv.Itoa(i + 2),
+			"blobId":      nil,
+			"size":        fileSize,
+			"name":        file.Name,
+			"type":        file.FileType,
+			"disposition": "attachment",
+		})
+	}
+
+	bodyValues := map[string]any{}
+	if fetchBody {
+		value, truncated := message.Text, false
+		if maxBodyBytes > 0 && len(value) > maxBodyBytes {
+			value, truncated = truncateUTF8(value, maxBodyBytes), true
+		}
+		bodyValues["1"] = map[string]any{"value": value, "isEncodingProblem": false, "isTruncated": truncated}
+	}
+
+	return map[string]any{
+		"id":            message.MessageId,
+		"blobId":        nil,
+		"threadId":      jmapThreadID(message),
+		"mailboxIds":    mailboxIDs,
+		"keywords":      keywords,
+		"size":          size,
+		"receivedAt":    receivedAt,
+		"messageId":     nil,
+		"inReplyTo":     nil,
+		"references":    nil,
+		"sender":        nil,
+		"from":          from,
+		"to":            to,
+		"cc":            nil,
+		"bcc":           nil,
+		"replyTo":       nil,
+		"subject":       message.Topic,
+		"sentAt":        message.Datetime,
+		"hasAttachment": len(message.Files) > 0,
+		"preview":       jmapPreview(message.Text),
+		"bodyValues":    bodyValues,
+		"textBody":      []map[string]any{textPart},
+		// HTML-версии у писем нет, поэтому htmlBody совпадает с textBody
+		"htmlBody":    []map[string]any{textPart},
+		"attachments": attachments,
+	}
+}
+
+// jmapThreadID возвращает цепочку письма в том же виде, что и журнал изменений
+func jmapThreadID(message *messagesproto.FullMessage) string {
+	threadID, _ := strconv.ParseInt(message.ThreadId, 10, 64)
+	messageID, _ := strconv.ParseInt(message.MessageId, 10, 64)
+	return domain.ThreadKey(threadID, messageID)
+}
+
+func jmapPreview(text string) string {
+	preview := strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(preview) <= jmapPreviewLength {
+		return preview
+	}
+	return string([]rune(preview)[:jmapPreviewLength])
+}
+
+// truncateUTF8 обрезает строку до limit байт, не разрывая символы
+func truncateUTF8(value string, limit int) string {
+	for limit > 0 && !utf8.RuneStart(value[limit]) {
+		limit--
+	}
+	return value[:limit]
+}
+
+// jmapKeywordFlag возвращает поле флага, которому соответствует ключевое слово. Ключевые
+// слова JMAP не различают регистр
+func jmapKeywordFlag(req *messagesproto.SetFlagsRequest, keyword string) *string {
+	switch strings.ToLower(keyword) {
+	case "$seen":
+		return &req.Read
+	case "$flagged":
+		return &req.Starred
+	case "$important":
+		return &req.Important
+	case "$answered":
+		return &req.Answered
+	case "$forwarded":
+		return &req.Forwarded
+	}
+	return nil
+}
+
+// jmapPatchValue разбирает значение патча вида "keywords/$seen": true. Допустимы только
+// true и null, null снимает флаг
+func jmapPatchValue(raw json.RawMessage) (set bool, ok bool) {
+	var value *bool
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return false, false
+	}
+	if value == nil {
+		return false, true
+	}
+	return true, *value
+}
+
+func jmapUnescapePointer(token string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+}
+
+// jmapObjectMissing сообщает, что письма нет или оно чужое
+func jmapObjectMissing(err error) bool {
+	switch status.Code(err) {
+	case codes.NotFound, codes.PermissionDenied, codes.InvalidArgument:
+		return true
+	}
+	return false
+}
+
+func jmapSetErrorFromGrpc(err error) *jmapSetError {
+	switch status.Code(err) {
+	case codes.NotFound, codes.PermissionDenied:
+		return &jmapSetError{Type: "notFound"}
+	case codes.InvalidArgument:
+		return &jmapSetError{Type: "invalidProperties", Description: status.Convert(err).Message()}
+	}
+	return &jmapSetError{Type: jmapErrServerFail, Description: status.Convert(err).Message()}
+}
+
+func validateJMAPProperties(properties, known []string) *jmapMethodError {
+	for _, property := range properties {
+		if !slices.Contains(known, property) {
+			return jmapError(jmapErrInvalidArguments, "unknown property "+property)
+		}
+	}
+	return nil
+}
+
+// selectJMAPProperties оставляет запрошенные свойства объекта, id возвращается всегда
+func selectJMAPProperties(object map[string]any, properties []string) map[string]any {
+	if properties == nil {
+		return object
+	}
+	selected := map[string]any{"id": object["id"]}
+	for _, property := range properties {
+		selected[property] = object[property]
+	}
+	return selected
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
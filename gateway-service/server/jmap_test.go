@@ -0,0 +1,408 @@
+package gateway_service
+
+import (
+	"2025_2_a4code/messages-service/pkg/messagesproto"
+	"2025_2_a4code/profile-service/pkg/profileproto"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const jmapUsingMail = `"using": ["urn:ietf:params:jmap:core", "urn:ietf:params:jmap:mail"]`
+
+var jmapTestFolders = &messagesproto.GetFoldersResponse{Folders: []*messagesproto.Folder{
+	{FolderId: "1", FolderName: "Входящие", FolderType: "inbox", MessageTotal: "2", MessageUnread: "1"},
+	{FolderId: "2", FolderName: "Черновики", FolderType: "draft", MessageTotal: "0", MessageUnread: "0"},
+	{FolderId: "3", FolderName: "Работа", FolderType: "custom", ParentId: "1", MessageTotal: "0", MessageUnread: "0"},
+	{FolderId: "starred", FolderName: "Starred", FolderType: "starred"},
+}}
+
+var jmapTestState = &messagesproto.GetMailStateResponse{MailboxState: "4", EmailState: "17", ThreadState: "17"}
+
+func setupJMAPTestServer() (*Server, *MockMessageClient) {
+	server, _, mockProfile, mockMessage := setupTestServer()
+	mockProfile.On("GetProfile", mock.Anything, mock.Anything).
+		Return(&profileproto.GetProfileResponse{Profile: &profileproto.Profile{Id: "7", Username: "alice"}}, nil)
+	return server, mockMessage
+}
+
+// callJMAP выполняет запрос к /jmap/api и возвращает ответы методов
+func callJMAP(t *testing.T, server *Server, body string) []jmapInvocation {
+	t.Helper()
+	req := createRequestWithToken("POST", "/jmap/api", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	server.jmapAPIHandler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	var resp jmapResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, jmapSessionState, resp.SessionState)
+	return resp.MethodResponses
+}
+
+func jmapArgs(t *testing.T, inv jmapInvocation) map[string]any {
+	t.Helper()
+	var args map[string]any
+	assert.NoError(t, json.Unmarshal(inv.Args, &args))
+	return args
+}
+
+func TestServer_JMAPSessionHandler(t *testing.T) {
+	t.Run("BearerToken", func(t *testing.T) {
+		server, _, mockProfile, _ := setupTestServer()
+		mockProfile.On("GetProfile", mock.Anything, mock.Anything).
+			Return(&profileproto.GetProfileResponse{Profile: &profileproto.Profile{Id: "7", Username: "alice"}}, nil)
+
+		req := httptest.NewRequest("GET", "http://mail.example.com/jmap/session", nil)
+		req.Header.Set("Authorization", "Bearer test-access-token")
+		w := httptest.NewRecorder()
+
+		server.jmapSessionHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		var session map[string]any
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &session))
+		assert.Equal(t, "http://mail.example.com/jmap/api", session["apiUrl"])
+		assert.Equal(t, "alice", session["username"])
+		assert.Equal(t, map[string]any{jmapCapabilityMail: "7", jmapCapabilitySubmission: "7"}, session["primaryAccounts"])
+		assert.Contains(t, session["capabilities"], jmapCapabilityCore)
+		assert.Contains(t, session["eventSourceUrl"], "/jmap/eventsource?")
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		server, _, _, _ := setupTestServer()
+
+		req := httptest.NewRequest("GET", "/jmap/session", nil)
+		w := httptest.NewRecorder()
+
+		server.jmapSessionHandler(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+		assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+	})
+}
+
+func TestServer_JMAPRequestErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		expectedType string
+	}{
+		{name: "NotJSON", body: `{"using": [`, expectedType: "urn:ietf:params:jmap:error:notJSON"},
+		{name: "NotRequest", body: `{"calls": []}`, expectedType: "urn:ietf:params:jmap:error:notRequest"},
+		{name: "UnknownCapability", body: `{"using": ["urn:example:unknown"], "methodCalls": []}`, expectedType: "urn:ietf:params:jmap:error:unknownCapability"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := setupJMAPTestServer()
+			req := createRequestWithToken("POST", "/jmap/api", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			server.jmapAPIHandler(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+			assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+			assert.Contains(t, w.Body.String(), tt.expectedType)
+		})
+	}
+}
+
+func TestServer_JMAPMethodErrors(t *testing.T) {
+	server, _ := setupJMAPTestServer()
+
+	responses := callJMAP(t, server, `{`+jmapUsingMail+`, "methodCalls": [
+		["EmailSubmission/set", {"accountId": "7"}, "a"],
+		["Mailbox/get", {"accountId": "8"}, "b"],
+		["Email/get", {"accountId": "7", "#ids": {"resultOf": "x", "name": "Email/query", "path": "/ids"}}, "c"],
+		["Core/echo", {"hello": true}, "d"]
+	]}`)
+
+	assert.Len(t, responses, 4)
+	assert.Equal(t, "error", responses[0].Name)
+	assert.Equal(t, jmapErrUnknownMethod, jmapArgs(t, responses[0])["type"])
+	assert.Equal(t, jmapErrAccountNotFound, jmapArgs(t, responses[1])["type"])
+	assert.Equal(t, jmapErrInvalidResultReference, jmapArgs(t, responses[2])["type"])
+	assert.Equal(t, "Core/echo", responses[3].Name)
+	assert.JSONEq(t, `{"hello": true}`, string(responses[3].Args))
+}
+
+func TestServer_JMAPMailboxGet(t *testing.T) {
+	server, mockMessage := setupJMAPTestServer()
+	mockMessage.On("GetMailState", mock.Anything, mock.Anything).Return(jmapTestState, nil)
+	mockMessage.On("GetFolders", mock.Anything, mock.Anything).Return(jmapTestFolders, nil)
+
+	responses := callJMAP(t, server, `{`+jmapUsingMail+`, "methodCalls": [
+		["Mailbox/get", {"accountId": "7", "ids": null}, "0"],
+		["Mailbox/get", {"accountId": "7", "ids": ["3", "9"], "properties": ["name", "parentId"]}, "1"]
+	]}`)
+
+	all := jmapArgs(t, responses[0])
+	assert.Equal(t, "4", all["state"])
+	list := all["list"].([]any)
+	// Виртуальная папка помеченных писем в список не попадает
+	assert.Len(t, list, 3)
+	inbox := list[0].(map[string]any)
+	assert.Equal(t, "inbox", inbox["role"])
+	assert.Equal(t, float64(1), inbox["unreadEmails"])
+	assert.Equal(t, "drafts", list[1].(map[string]any)["role"])
+	assert.Nil(t, list[2].(map[string]any)["role"])
+
+	some := jmapArgs(t, responses[1])
+	assert.Equal(t, []any{map[string]any{"id": "3", "name": "Работа", "parentId": "1"}}, some["list"])
+	assert.Equal(t, []any{"9"}, some["notFound"])
+	// Папки загружаются один раз на запрос
+	mockMessage.AssertNumberOfCalls(t, "GetFolders", 1)
+}
+
+func TestServer_JMAPEmailQueryAndGet(t *testing.T) {
+	server, mockMessage := setupJMAPTestServer()
+	mockMessage.On("GetMailState", mock.Anything, mock.Anything).Return(jmapTestState, nil)
+	mockMessage.On("GetFolders", mock.Anything, mock.Anything).Return(jmapTestFolders, nil)
+	mockMessage.On("GetFolder", mock.Anything, &messagesproto.GetFolderRequest{
+		FolderId: "1", Limit: "100", Sort: "date_desc", UnreadOnly: "true",
+	}).Return(&messagesproto.GetFolderResponse{
+		MessageTotal: "2",
+		Messages:     []*messagesproto.Message{{Id: "11"}},
+		Pagination:   &messagesproto.PaginationInfo{NextCursor: "next"},
+	}, nil)
+	mockMessage.On("GetFolder", mock.Anything, &messagesproto.GetFolderRequest{
+		FolderId: "1", Limit: "100", Sort: "date_desc", UnreadOnly: "true", Cursor: "next",
+	}).Return(&messagesproto.GetFolderResponse{
+		MessageTotal: "2",
+		Messages:     []*messagesproto.Message{{Id: "12"}},
+		Pagination:   &messagesproto.PaginationInfo{},
+	}, nil)
+	mockMessage.On("MessagePage", mock.Anything, &messagesproto.MessagePageRequest{MessageId: "11", Peek: true}).
+		Return(&messagesproto.MessagePageResponse{Message: &messagesproto.FullMessage{
+			MessageId: "11",
+			Topic:     "Отчет",
+			Text:      "Текст   письма",
+			Datetime:  "2025-11-02T15:04:05+03:00",
+			Sender:    &messagesproto.Sender{Email: "bob@example.com", Username: "Bob"},
+			Receivers: []string{"alice@example.com"},
+			FolderIds: []string{"1"},
+			IsRead:    "false",
+			IsStarred: "true",
+			Size:      "120",
+		}}, nil)
+	mockMessage.On("MessagePage", mock.Anything, &messagesproto.MessagePageRequest{MessageId: "12", Peek: true}).
+		Return(nil, status.Error(codes.PermissionDenied, "access denied"))
+
+	responses := callJMAP(t, server, `{`+jmapUsingMail+`, "methodCalls": [
+		["Email/query", {"accountId": "7", "filter": {"inMailbox": "1", "notKeyword": "$seen"},
+			"sort": [{"property": "receivedAt", "isAscending": false}], "calculateTotal": true}, "q"],
+		["Email/get", {"accountId": "7", "#ids": {"resultOf": "q", "name": "Email/query", "path": "/ids"},
+			"properties": ["threadId", "keywords", "from", "receivedAt", "preview", "bodyValues"], "fetchTextBodyValues": true}, "g"]
+	]}`)
+
+	query := jmapArgs(t, responses[0])
+	assert.Equal(t, []any{"11", "12"}, query["ids"])
+	assert.Equal(t, float64(2), query["total"])
+	assert.Equal(t, "17", query["queryState"])
+
+	get := jmapArgs(t, responses[1])
+	assert.Equal(t, []any{"12"}, get["notFound"])
+	email := get["list"].([]any)[0].(map[string]any)
+	assert.Equal(t, "11", email["id"])
+	assert.Equal(t, "m11", email["threadId"])
+	assert.Equal(t, map[string]any{"$flagged": true}, email["keywords"])
+	assert.Equal(t, "2025-11-02T12:04:05Z", email["receivedAt"])
+	assert.Equal(t, "Текст письма", email["preview"])
+	assert.Equal(t, []any{map[string]any{"name": "Bob", "email": "bob@example.com"}}, email["from"])
+	assert.Equal(t, "Текст   письма", email["bodyValues"].(map[string]any)["1"].(map[string]any)["value"])
+	assert.NotContains(t, email, "subject")
+}
+
+func TestServer_JMAPEmailQueryUnsupported(t *testing.T) {
+	server, _ := setupJMAPTestServer()
+
+	responses := callJMAP(t, server, `{`+jmapUsingMail+`, "methodCalls": [
+		["Email/query", {"accountId": "7", "filter": {"text": "hello"}}, "0"],
+		["Email/query", {"accountId": "7"}, "1"],
+		["Email/query", {"accountId": "7", "filter": {"inMailbox": "1"}, "sort": [{"property": "size"}]}, "2"]
+	]}`)
+
+	assert.Equal(t, jmapErrUnsupportedFilter, jmapArgs(t, responses[0])["type"])
+	assert.Equal(t, jmapErrUnsupportedFilter, jmapArgs(t, responses[1])["type"])
+	assert.Equal(t, jmapErrUnsupportedSort, jmapArgs(t, responses[2])["type"])
+}
+
+func TestServer_JMAPEmailSet(t *testing.T) {
+	server, mockMessage := setupJMAPTestServer()
+	mockMessage.On("GetMailState", mock.Anything, mock.Anything).Return(jmapTestState, nil).Once()
+	mockMessage.On("GetMailState", mock.Anything, mock.Anything).
+		Return(&messagesproto.GetMailStateResponse{MailboxState: "5", EmailState: "20", ThreadState: "20"}, nil)
+	mockMessage.On("MessagePage", mock.Anything, &messagesproto.MessagePageRequest{MessageId: "11", Peek: true}).
+		Return(&messagesproto.MessagePageResponse{Message: &messagesproto.FullMessage{MessageId: "11", FolderIds: []string{"1"}}}, nil)
+	mockMessage.On("MoveToFolder", mock.Anything, &messagesproto.MoveToFolderRequest{MessageId: "11", FolderId: "3"}).
+		Return(&messagesproto.MoveToFolderResponse{}, nil)
+	mockMessage.On("SetFlags", mock.Anything, &messagesproto.SetFlagsRequest{MessageId: "11", Read: "true"}).
+		Return(&messagesproto.SetFlagsResponse{}, nil)
+	mockMessage.On("SetFlags", mock.Anything, &messagesproto.SetFlagsRequest{
+		MessageId: "12", Read: "false", Starred: "true", Important: "false", Answered: "false", Forwarded: "false",
+	}).Return(&messagesproto.SetFlagsResponse{}, nil)
+	mockMessage.On("DeletePermanently", mock.Anything, &messagesproto.DeletePermanentlyRequest{MessageId: "13"}).
+		Return(&messagesproto.DeletePermanentlyResponse{}, nil)
+	mockMessage.On("DeletePermanently", mock.Anything, &messagesproto.DeletePermanentlyRequest{MessageId: "14"}).
+		Return(nil, status.Error(codes.NotFound, "message not found"))
+
+	responses := callJMAP(t, server, `{`+jmapUsingMail+`, "methodCalls": [
+		["Email/set", {"accountId": "7", "ifInState": "17", "update": {
+			"11": {"mailboxIds/1": null, "mailboxIds/3": true, "keywords/$seen": true},
+			"12": {"keywords": {"$flagged": true}},
+			"15": {"subject": "new"}
+		}, "destroy": ["13", "14"]}, "0"]
+	]}`)
+
+	result := jmapArgs(t, responses[0])
+	assert.Equal(t, "17", result["oldState"])
+	assert.Equal(t, "20", result["newState"])
+	assert.Equal(t, map[string]any{"11": nil, "12": nil}, result["updated"])
+	assert.Equal(t, "invalidProperties", result["notUpdated"].(map[string]any)["15"].(map[string]any)["type"])
+	assert.Equal(t, []any{"13"}, result["destroyed"])
+	assert.Equal(t, "notFound", result["notDestroyed"].(map[string]any)["14"].(map[string]any)["type"])
+	mockMessage.AssertExpectations(t)
+
+	t.Run("StateMismatch", func(t *testing.T) {
+		server, mockMessage := setupJMAPTestServer()
+		mockMessage.On("GetMailState", mock.Anything, mock.Anything).Return(jmapTestState, nil)
+
+		responses := callJMAP(t, server, `{`+jmapUsingMail+`, "methodCalls": [
+			["Email/set", {"accountId": "7", "ifInState": "3", "destroy": ["13"]}, "0"]
+		]}`)
+
+		assert.Equal(t, jmapErrStateMismatch, jmapArgs(t, responses[0])["type"])
+	})
+}
+
+func TestServer_JMAPCreateAndSubmitDraft(t *testing.T) {
+	server, mockMessage := setupJMAPTestServer()
+	mockMessage.On("GetMailState", mock.Anything, mock.Anything).Return(jmapTestState, nil)
+	mockMessage.On("GetFolders", mock.Anything, mock.Anything).Return(jmapTestFolders, nil)
+	mockMessage.On("SaveDraft", mock.Anything, &messagesproto.SaveDraftRequest{
+		Topic: "Привет", Text: "Как дела?", Receivers: []*messagesproto.Receiver{{Email: "bob@example.com"}},
+	}).Return(&messagesproto.SaveDraftResponse{DraftId: "31"}, nil)
+	mockMessage.On("MessagePage", mock.Anything, &messagesproto.MessagePageRequest{MessageId: "31", Peek: true}).
+		Return(&messagesproto.MessagePageResponse{Message: &messagesproto.FullMessage{
+			MessageId: "31", ThreadId: "9", Size: "17", FolderIds: []string{"2"},
+		}}, nil).Once()
+	mockMessage.On("SendDraft", mock.Anything, &messagesproto.SendDraftRequest{DraftId: "31"}).
+		Return(&messagesproto.SendDraftResponse{}, nil)
+	// После отправки письмо уже лежит в отправленных
+	mockMessage.On("MessagePage", mock.Anything, &messagesproto.MessagePageRequest{MessageId: "31", Peek: true}).
+		Return(&messagesproto.MessagePageResponse{Message: &messagesproto.FullMessage{MessageId: "31", FolderIds: []string{"4"}}}, nil)
+
+	responses := callJMAP(t, server, `{"using": ["urn:ietf:params:jmap:core", "urn:ietf:params:jmap:mail", "urn:ietf:params:jmap:submission"],
+		"methodCalls": [
+			["Email/set", {"accountId": "7", "create": {"draft": {
+				"mailboxIds": {"2": true}, "keywords": {"$draft": true}, "subject": "Привет",
+				"to": [{"name": "Bob", "email": "bob@example.com"}],
+				"textBody": [{"partId": "body", "type": "text/plain"}],
+				"bodyValues": {"body": {"value": "Как дела?"}}
+			}}}, "0"],
+			["EmailSubmission/set", {"accountId": "7", "create": {"send": {"identityId": "7", "emailId": "#draft"}},
+				"onSuccessUpdateEmail": {"#send": {"mailboxIds/2": null, "mailboxIds/4": true, "keywords/$draft": null}}}, "1"]
+		]}`)
+
+	assert.Len(t, responses, 3)
+	created := jmapArgs(t, responses[0])["created"].(map[string]any)["draft"].(map[string]any)
+	assert.Equal(t, "31", created["id"])
+	assert.Equal(t, "9", created["threadId"])
+
+	assert.Equal(t, "EmailSubmission/set", responses[1].Name)
+	submission := jmapArgs(t, responses[1])["created"].(map[string]any)["send"].(map[string]any)
+	assert.Equal(t, "31", submission["emailId"])
+	assert.Equal(t, "final", submission["undoStatus"])
+
+	// Неявный Email/set идет следом под тем же идентификатором вызова
+	assert.Equal(t, "Email/set", responses[2].Name)
+	assert.Equal(t, "1", responses[2].CallID)
+	assert.Equal(t, map[string]any{"31": nil}, jmapArgs(t, responses[2])["updated"])
+	mockMessage.AssertNotCalled(t, "MoveToFolder", mock.Anything, mock.Anything)
+	mockMessage.AssertExpectations(t)
+}
+
+func TestServer_JMAPChangesAndThreads(t *testing.T) {
+	server, mockMessage := setupJMAPTestServer()
+	mockMessage.On("GetMailState", mock.Anything, mock.Anything).Return(jmapTestState, nil)
+	mockMessage.On("GetMailChanges", mock.Anything, &messagesproto.GetMailChangesRequest{Type: "Email", SinceState: "10", MaxChanges: 50}).
+		Return(&messagesproto.GetMailChangesResponse{OldState: "10", NewState: "17", Created: []string{"11"}}, nil)
+	mockMessage.On("GetMailChanges", mock.Anything, &messagesproto.GetMailChangesRequest{Type: "Mailbox", SinceState: "99"}).
+		Return(nil, status.Error(codes.FailedPrecondition, "invalid mail state"))
+	mockMessage.On("GetThreads", mock.Anything, &messagesproto.GetThreadsRequest{ThreadIds: []string{"9", "m5"}}).
+		Return(&messagesproto.GetThreadsResponse{
+			Threads:  []*messagesproto.MailThread{{ThreadId: "9", MessageIds: []string{"11", "31"}}},
+			NotFound: []string{"m5"},
+		}, nil)
+
+	responses := callJMAP(t, server, `{`+jmapUsingMail+`, "methodCalls": [
+		["Email/changes", {"accountId": "7", "sinceState": "10", "maxChanges": 50}, "0"],
+		["Mailbox/changes", {"accountId": "7", "sinceState": "99"}, "1"],
+		["Thread/get", {"accountId": "7", "ids": ["9", "m5"]}, "2"]
+	]}`)
+
+	changes := jmapArgs(t, responses[0])
+	assert.Equal(t, "17", changes["newState"])
+	assert.Equal(t, []any{"11"}, changes["created"])
+	assert.Equal(t, []any{}, changes["updated"])
+	assert.Equal(t, jmapErrCannotCalculateChanges, jmapArgs(t, responses[1])["type"])
+
+	threads := jmapArgs(t, responses[2])
+	assert.Equal(t, []any{map[string]any{"id": "9", "emailIds": []any{"11", "31"}}}, threads["list"])
+	assert.Equal(t, []any{"m5"}, threads["notFound"])
+}
+
+func TestEvaluateJSONPointer(t *testing.T) {
+	var value any
+	assert.NoError(t, json.Unmarshal([]byte(`{"list": [{"id": "1", "emailIds": ["a", "b"]}, {"id": "2", "emailIds": ["c"]}], "a/b": 1}`), &value))
+
+	result, ok := evaluateJSONPointer(value, "/list/*/emailIds")
+	assert.True(t, ok)
+	assert.Equal(t, []any{"a", "b", "c"}, result)
+
+	result, ok = evaluateJSONPointer(value, "/list/1/id")
+	assert.True(t, ok)
+	assert.Equal(t, "2", result)
+
+	result, ok = evaluateJSONPointer(value, "/a~1b")
+	assert.True(t, ok)
+	assert.Equal(t, float64(1), result)
+
+	_, ok = evaluateJSONPointer(value, "/list/5")
+	assert.False(t, ok)
+}
+
+func TestServer_JMAPEventSourceHandler(t *testing.T) {
+	server, mockMessage := setupJMAPTestServer()
+	mockMessage.On("GetMailState", mock.Anything, mock.Anything).Return(jmapTestState, nil).Once()
+	mockMessage.On("GetMailState", mock.Anything, mock.Anything).
+		Return(&messagesproto.GetMailStateResponse{MailboxState: "4", EmailState: "18", ThreadState: "18"}, nil)
+	stream := &fakeEventStream{
+		header: metadata.MD{},
+		events: []*messagesproto.MailEvent{{EventId: "3", Type: "new_message", MessageId: "18"}},
+		err:    io.EOF,
+	}
+	mockMessage.On("Subscribe", mock.Anything, &messagesproto.SubscribeRequest{}).Return(stream, nil)
+
+	req := createRequestWithToken("GET", "/jmap/eventsource?types=Email,Mailbox&closeafter=state&ping=0", nil)
+	w := httptest.NewRecorder()
+
+	server.jmapEventSourceHandler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "event: state\ndata: ")
+	assert.Contains(t, w.Body.String(), `"changed":{"7":{"Email":"18"}}`)
+	assert.Contains(t, w.Body.String(), `"@type":"StateChange"`)
+}
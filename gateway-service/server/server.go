@@ -10,10 +10,12 @@ import (
 	"2025_2_a4code/profile-service/pkg/profileproto"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -95,28 +97,77 @@ func (s *Server) Start(ctx context.Context) error {
 	mux.Handle("POST /auth/signup", http.HandlerFunc(s.signupHandler))
 	mux.Handle("POST /auth/refresh", http.HandlerFunc(s.refreshHandler))
 	mux.Handle("POST /auth/logout", http.HandlerFunc(s.logoutHandler))
+	mux.Handle("GET /auth/app-passwords", http.HandlerFunc(s.listAppPasswordsHandler))
+	mux.Handle("POST /auth/app-passwords", http.HandlerFunc(s.createAppPasswordHandler))
+	mux.Handle("DELETE /auth/app-passwords", http.HandlerFunc(s.revokeAppPasswordHandler))
 
 	mux.Handle("GET /user/profile", http.HandlerFunc(s.getProfileHandler))
 	mux.Handle("PUT /user/profile", http.HandlerFunc(s.updateProfileHandler))
 	mux.Handle("GET /user/settings", http.HandlerFunc(s.settingsHandler))
+	mux.Handle("PUT /user/vacation", http.HandlerFunc(s.setVacationHandler))
 	mux.Handle("POST /user/upload/avatar", http.HandlerFunc(s.uploadAvatarHandler))
 	mux.Handle("GET /user/avatar", http.HandlerFunc(s.getAvatarHandler))
 
 	mux.Handle("GET /messages/{message_id}", http.HandlerFunc(s.messagePageHandler))
 	mux.Handle("POST /messages/reply", http.HandlerFunc(s.replyHandler))
 	mux.Handle("POST /messages/send", http.HandlerFunc(s.sendHandler))
+	mux.Handle("POST /messages/forward", http.HandlerFunc(s.forwardHandler))
 	mux.Handle("POST /messages/mark-as-spam", http.HandlerFunc(s.markAsSpamHandler))
+	mux.Handle("POST /messages/mark-as-not-spam", http.HandlerFunc(s.markAsNotSpamHandler))
 	mux.Handle("POST /messages/move-to-folder", http.HandlerFunc(s.moveToFolderHandler))
+	mux.Handle("POST /messages/set-flags", http.HandlerFunc(s.setFlagsHandler))
 	mux.Handle("POST /messages/create-folder", http.HandlerFunc(s.createFolderHandler))
 	mux.Handle("GET /messages/inbox", http.HandlerFunc(s.inboxHandler))
 	mux.Handle("GET /folders/{folder_name}", http.HandlerFunc(s.getFolderHandler))
 	mux.Handle("GET /messages/get-folders", http.HandlerFunc(s.getFoldersHandler))
 	mux.Handle("PUT /messages/rename-folder", http.HandlerFunc(s.renameFolderHandler))
 	mux.Handle("DELETE /messages/delete-folder", http.HandlerFunc(s.deleteFolderHandler))
+	mux.Handle("POST /messages/move-folder", http.HandlerFunc(s.moveFolderHandler))
+	mux.Handle("GET /messages/get-folder-tree", http.HandlerFunc(s.getFolderTreeHandler))
 	mux.Handle("DELETE /messages/delete-message-from-folder", http.HandlerFunc(s.deleteMessageFromFolderHandler))
 	mux.Handle("POST /messages/save-draft", http.HandlerFunc(s.saveDraftHandler))
 	mux.Handle("DELETE /messages/delete-draft", http.HandlerFunc(s.deleteDraftHandler))
 	mux.Handle("POST /messages/send-draft", http.HandlerFunc(s.sendDraftHandler))
+	mux.Handle("GET /messages/sieve-script", http.HandlerFunc(s.getSieveScriptHandler))
+	mux.Handle("PUT /messages/sieve-script", http.HandlerFunc(s.putSieveScriptHandler))
+	mux.Handle("POST /messages/check-sieve-script", http.HandlerFunc(s.checkSieveScriptHandler))
+	mux.Handle("GET /messages/forwarding", http.HandlerFunc(s.getForwardingHandler))
+	mux.Handle("PUT /messages/forwarding", http.HandlerFunc(s.putForwardingHandler))
+	mux.Handle("POST /messages/restore-from-trash", http.HandlerFunc(s.restoreFromTrashHandler))
+	mux.Handle("POST /messages/empty-trash", http.HandlerFunc(s.emptyTrashHandler))
+	mux.Handle("DELETE /messages/delete-permanently", http.HandlerFunc(s.deletePermanentlyHandler))
+	mux.Handle("POST /messages/batch", http.HandlerFunc(s.batchHandler))
+	mux.Handle("POST /messages/create-label", http.HandlerFunc(s.createLabelHandler))
+	mux.Handle("GET /messages/get-labels", http.HandlerFunc(s.getLabelsHandler))
+	mux.Handle("PUT /messages/update-label", http.HandlerFunc(s.updateLabelHandler))
+	mux.Handle("DELETE /messages/delete-label", http.HandlerFunc(s.deleteLabelHandler))
+	mux.Handle("POST /messages/apply-label", http.HandlerFunc(s.applyLabelHandler))
+	mux.Handle("POST /messages/remove-label", http.HandlerFunc(s.removeLabelHandler))
+	mux.Handle("POST /messages/contacts", http.HandlerFunc(s.createContactHandler))
+	mux.Handle("GET /messages/contacts", http.HandlerFunc(s.getContactsHandler))
+	mux.Handle("PUT /messages/contacts", http.HandlerFunc(s.updateContactHandler))
+	mux.Handle("DELETE /messages/contacts", http.HandlerFunc(s.deleteContactHandler))
+	mux.Handle("GET /messages/contacts/suggest", http.HandlerFunc(s.suggestContactsHandler))
+	mux.Handle("POST /messages/contact-groups", http.HandlerFunc(s.createContactGroupHandler))
+	mux.Handle("GET /messages/contact-groups", http.HandlerFunc(s.getContactGroupsHandler))
+	mux.Handle("PUT /messages/contact-groups", http.HandlerFunc(s.renameContactGroupHandler))
+	mux.Handle("DELETE /messages/contact-groups", http.HandlerFunc(s.deleteContactGroupHandler))
+	mux.Handle("GET /messages/sender-lists", http.HandlerFunc(s.getSenderListsHandler))
+	mux.Handle("POST /messages/sender-lists", http.HandlerFunc(s.addSenderRuleHandler))
+	mux.Handle("DELETE /messages/sender-lists", http.HandlerFunc(s.deleteSenderRuleHandler))
+	mux.Handle("PUT /messages/sender-lists/blocked-action", http.HandlerFunc(s.setBlockedSenderActionHandler))
+	mux.Handle("POST /messages/block-sender", http.HandlerFunc(s.blockSenderHandler))
+	mux.Handle("GET /messages/aliases", http.HandlerFunc(s.getAliasesHandler))
+	mux.Handle("POST /messages/aliases", http.HandlerFunc(s.addAliasHandler))
+	mux.Handle("DELETE /messages/aliases", http.HandlerFunc(s.deleteAliasHandler))
+	mux.Handle("GET /messages/pop3", http.HandlerFunc(s.getPop3SettingsHandler))
+	mux.Handle("PUT /messages/pop3", http.HandlerFunc(s.putPop3SettingsHandler))
+	mux.Handle("GET /messages/events", http.HandlerFunc(s.eventsHandler))
+
+	mux.Handle("GET /.well-known/jmap", http.HandlerFunc(s.jmapWellKnownHandler))
+	mux.Handle("GET /jmap/session", http.HandlerFunc(s.jmapSessionHandler))
+	mux.Handle("POST /jmap/api", http.HandlerFunc(s.jmapAPIHandler))
+	mux.Handle("GET /jmap/eventsource", http.HandlerFunc(s.jmapEventSourceHandler))
 
 	var handler http.Handler = mux
 	handler = logger.New(log)(handler)
@@ -226,6 +277,70 @@ func (s *Server) logoutHandler(w http.ResponseWriter, r *http.Request) {
 	respondSuccess(w, resp)
 }
 
+// createAppPasswordHandler возвращает созданный пароль приложения. Он есть только в этом ответе
+func (s *Server) createAppPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req authproto.CreateAppPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.authClient.CreateAppPassword(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to create app password")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) listAppPasswordsHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	resp, err := s.authClient.ListAppPasswords(ctx, &authproto.ListAppPasswordsRequest{})
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to get app passwords")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) revokeAppPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req authproto.RevokeAppPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.authClient.RevokeAppPassword(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to revoke app password")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
 func (s *Server) getProfileHandler(w http.ResponseWriter, r *http.Request) {
 	accessToken, err := getAccessToken(r)
 	if err != nil {
@@ -284,6 +399,30 @@ func (s *Server) settingsHandler(w http.ResponseWriter, r *http.Request) {
 	respondSuccess(w, resp)
 }
 
+func (s *Server) setVacationHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req profileproto.SetVacationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.profileClient.SetVacation(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to set vacation")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
 // Messages handlers
 
 func (s *Server) messagePageHandler(w http.ResponseWriter, r *http.Request) {
@@ -348,6 +487,28 @@ func (s *Server) sendHandler(w http.ResponseWriter, r *http.Request) {
 	respondSuccess(w, map[string]string{"status": "ok"})
 }
 
+func (s *Server) forwardHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.ForwardRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.Forward(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to forward message")
+		return
+	}
+	respondSuccess(w, resp)
+}
+
 func (s *Server) getFolderHandler(w http.ResponseWriter, r *http.Request) {
 	accessToken, err := getAccessToken(r)
 	if err != nil {
@@ -424,14 +585,28 @@ func (s *Server) handleInbox(ctx context.Context, w http.ResponseWriter, r *http
 
 func (s *Server) respondFolder(ctx context.Context, w http.ResponseWriter, r *http.Request, folderID, lastMessageID, lastDatetime, limit, errorMessage string) {
 	req := &messagesproto.GetFolderRequest{
-		FolderId:      folderID,
-		LastMessageId: lastMessageID,
-		LastDatetime:  lastDatetime,
-		Limit:         limit,
+		FolderId:       folderID,
+		LastMessageId:  lastMessageID,
+		LastDatetime:   lastDatetime,
+		Limit:          limit,
+		LabelId:        r.URL.Query().Get("label_id"),
+		Cursor:         r.URL.Query().Get("cursor"),
+		Sort:           r.URL.Query().Get("sort"),
+		UnreadOnly:     r.URL.Query().Get("unread_only"),
+		StarredOnly:    r.URL.Query().Get("starred_only"),
+		HasAttachments: r.URL.Query().Get("has_attachments"),
+		Sender:         r.URL.Query().Get("sender"),
+		After:          r.URL.Query().Get("after"),
+		Before:         r.URL.Query().Get("before"),
 	}
 
 	resp, err := s.messageClient.GetFolder(ctx, req)
 	if err != nil {
+		// Неверные параметры списка, подделанный или просроченный курсор - ошибка клиента
+		if status.Code(err) == codes.InvalidArgument {
+			writeResponse(w, http.StatusBadRequest, status.Convert(err).Message(), nil)
+			return
+		}
 		respondSuccess(w, emptyFolderResponse())
 		return
 	}
@@ -732,6 +907,29 @@ func (s *Server) markAsSpamHandler(w http.ResponseWriter, r *http.Request) {
 	respondSuccess(w, resp)
 }
 
+func (s *Server) markAsNotSpamHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.MarkAsNotSpamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.MarkAsNotSpam(ctx, &req)
+	if err != nil {
+		respondError(w, "Failed to mark as not spam")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
 func (s *Server) moveToFolderHandler(w http.ResponseWriter, r *http.Request) {
 	accessToken, err := getAccessToken(r)
 	if err != nil {
@@ -778,6 +976,897 @@ func (s *Server) createFolderHandler(w http.ResponseWriter, r *http.Request) {
 	respondSuccess(w, resp)
 }
 
+func (s *Server) moveFolderHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.MoveFolderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.MoveFolder(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to move folder")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) getFolderTreeHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	resp, err := s.messageClient.GetFolderTree(ctx, &messagesproto.GetFolderTreeRequest{})
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to get folder tree")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) getSieveScriptHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	resp, err := s.messageClient.GetSieveScript(ctx, &messagesproto.GetSieveScriptRequest{})
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to get sieve script")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) putSieveScriptHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.PutSieveScriptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.PutSieveScript(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to save sieve script")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) getForwardingHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	resp, err := s.messageClient.GetForwarding(ctx, &messagesproto.GetForwardingRequest{})
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to get forwarding rule")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) putForwardingHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.PutForwardingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.PutForwarding(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to save forwarding rule")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) checkSieveScriptHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.CheckSieveScriptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.CheckSieveScript(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to check sieve script")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) restoreFromTrashHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.RestoreFromTrashRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.RestoreFromTrash(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to restore message from trash")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) emptyTrashHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	resp, err := s.messageClient.EmptyTrash(ctx, &messagesproto.EmptyTrashRequest{})
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to empty trash")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) deletePermanentlyHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.DeletePermanentlyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.DeletePermanently(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to delete message")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) setFlagsHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.SetFlagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.SetFlags(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to set message flags")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) batchHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.BatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.Batch(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to apply batch")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) createLabelHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.CreateLabelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.CreateLabel(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to create label")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) getLabelsHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	resp, err := s.messageClient.GetLabels(ctx, &messagesproto.GetLabelsRequest{})
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to get labels")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) updateLabelHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.UpdateLabelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.UpdateLabel(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to update label")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) deleteLabelHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.DeleteLabelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.DeleteLabel(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to delete label")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) applyLabelHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.ApplyLabelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.ApplyLabel(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to apply label")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) removeLabelHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.RemoveLabelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.RemoveLabel(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to remove label")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) createContactHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.CreateContactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.CreateContact(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to create contact")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) getContactsHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	resp, err := s.messageClient.GetContacts(ctx, &messagesproto.GetContactsRequest{
+		GroupId: r.URL.Query().Get("group_id"),
+	})
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to get contacts")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) updateContactHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.UpdateContactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.UpdateContact(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to update contact")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) deleteContactHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.DeleteContactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.DeleteContact(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to delete contact")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) suggestContactsHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var limit int
+	if rawLimit := r.URL.Query().Get("limit"); rawLimit != "" {
+		limit, err = strconv.Atoi(rawLimit)
+		if err != nil {
+			writeResponse(w, http.StatusBadRequest, "Invalid limit", nil)
+			return
+		}
+	}
+
+	resp, err := s.messageClient.SuggestContacts(ctx, &messagesproto.SuggestContactsRequest{
+		Prefix: r.URL.Query().Get("prefix"),
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to suggest contacts")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) createContactGroupHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.CreateContactGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.CreateContactGroup(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to create contact group")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) getContactGroupsHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	resp, err := s.messageClient.GetContactGroups(ctx, &messagesproto.GetContactGroupsRequest{})
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to get contact groups")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) renameContactGroupHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.RenameContactGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.RenameContactGroup(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to rename contact group")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) deleteContactGroupHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.DeleteContactGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.DeleteContactGroup(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to delete contact group")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) getSenderListsHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	resp, err := s.messageClient.GetSenderLists(ctx, &messagesproto.GetSenderListsRequest{})
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to get sender lists")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) addSenderRuleHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.AddSenderRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.AddSenderRule(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to add sender rule")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) deleteSenderRuleHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.DeleteSenderRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.DeleteSenderRule(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to delete sender rule")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) setBlockedSenderActionHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.SetBlockedSenderActionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.SetBlockedSenderAction(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to set blocked sender action")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) blockSenderHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.BlockSenderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.BlockSender(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to block sender")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) getAliasesHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	resp, err := s.messageClient.GetAliases(ctx, &messagesproto.GetAliasesRequest{})
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to get aliases")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) addAliasHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.AddAliasRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.AddAlias(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to add alias")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) deleteAliasHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.DeleteAliasRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.DeleteAlias(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to delete alias")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) getPop3SettingsHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	resp, err := s.messageClient.GetPop3Settings(ctx, &messagesproto.GetPop3SettingsRequest{})
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to get pop3 settings")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+func (s *Server) putPop3SettingsHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx := s.addTokenToContext(r.Context(), accessToken)
+
+	var req messagesproto.PutPop3SettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeResponse(w, http.StatusBadRequest, "Invalid request body", nil)
+		return
+	}
+
+	resp, err := s.messageClient.PutPop3Settings(ctx, &req)
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to save pop3 settings")
+		return
+	}
+
+	respondSuccess(w, resp)
+}
+
+// sseHeartbeatInterval - как часто отправлять комментарий, чтобы прокси не закрывали простаивающее соединение
+const sseHeartbeatInterval = 25 * time.Second
+
+// eventsHandler отдает события почтового ящика в формате server-sent events. После
+// переподключения браузер присылает заголовок Last-Event-ID, и поток продолжается с него
+func (s *Server) eventsHandler(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := getAccessToken(r)
+	if err != nil {
+		writeResponse(w, http.StatusUnauthorized, "Access token required", nil)
+		return
+	}
+	ctx, cancel := context.WithCancel(s.addTokenToContext(r.Context(), accessToken))
+	defer cancel()
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	stream, err := s.messageClient.Subscribe(ctx, &messagesproto.SubscribeRequest{LastEventId: lastEventID})
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to subscribe to events")
+		return
+	}
+	// Ошибки подписки приходят вместо заголовков, до первого события
+	header, err := stream.Header()
+	if err == nil && header == nil {
+		_, err = stream.Recv()
+	}
+	if err != nil {
+		writeGrpcAwareError(w, err, "Failed to subscribe to events")
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	// Поток живет дольше WriteTimeout сервера
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		logger.GetLogger(r.Context()).Warn("failed to reset write deadline: " + err.Error())
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 3000\n\n")
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	events := make(chan *messagesproto.MailEvent)
+	go func() {
+		defer close(events)
+		for {
+			event, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			// Поток закрыт сервисом: браузер переподключится сам с последним идентификатором
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.EventId, event.Type, data)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
 func respondSuccess(w http.ResponseWriter, body interface{}) {
 	writeResponse(w, http.StatusOK, "success", body)
 }
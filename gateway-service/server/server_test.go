@@ -20,6 +20,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -59,6 +60,38 @@ func (m *MockAuthClient) Logout(ctx context.Context, in *authproto.LogoutRequest
 	return args.Get(0).(*authproto.LogoutResponse), args.Error(1)
 }
 
+func (m *MockAuthClient) CreateAppPassword(ctx context.Context, in *authproto.CreateAppPasswordRequest, opts ...grpc.CallOption) (*authproto.CreateAppPasswordResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authproto.CreateAppPasswordResponse), args.Error(1)
+}
+
+func (m *MockAuthClient) ListAppPasswords(ctx context.Context, in *authproto.ListAppPasswordsRequest, opts ...grpc.CallOption) (*authproto.ListAppPasswordsResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authproto.ListAppPasswordsResponse), args.Error(1)
+}
+
+func (m *MockAuthClient) RevokeAppPassword(ctx context.Context, in *authproto.RevokeAppPasswordRequest, opts ...grpc.CallOption) (*authproto.RevokeAppPasswordResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authproto.RevokeAppPasswordResponse), args.Error(1)
+}
+
+func (m *MockAuthClient) AuthenticateAppPassword(ctx context.Context, in *authproto.AuthenticateAppPasswordRequest, opts ...grpc.CallOption) (*authproto.AuthenticateAppPasswordResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authproto.AuthenticateAppPasswordResponse), args.Error(1)
+}
+
 type MockProfileClient struct {
 	mock.Mock
 }
@@ -95,6 +128,14 @@ func (m *MockProfileClient) UploadAvatar(ctx context.Context, in *profileproto.U
 	return args.Get(0).(*profileproto.UploadAvatarResponse), args.Error(1)
 }
 
+func (m *MockProfileClient) SetVacation(ctx context.Context, in *profileproto.SetVacationRequest, opts ...grpc.CallOption) (*profileproto.SetVacationResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*profileproto.SetVacationResponse), args.Error(1)
+}
+
 type MockMessageClient struct {
 	mock.Mock
 }
@@ -227,297 +268,1405 @@ func (m *MockMessageClient) SendDraft(ctx context.Context, in *messagesproto.Sen
 	return args.Get(0).(*messagesproto.SendDraftResponse), args.Error(1)
 }
 
-func setupTestServer() (*Server, *MockAuthClient, *MockProfileClient, *MockMessageClient) {
-	cfg := &config.AppConfig{
-		GatewayPort:        "8080",
-		GatewayMetricsPort: "9090",
+func (m *MockMessageClient) PutSieveScript(ctx context.Context, in *messagesproto.PutSieveScriptRequest, opts ...grpc.CallOption) (*messagesproto.PutSieveScriptResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
 	}
+	return args.Get(0).(*messagesproto.PutSieveScriptResponse), args.Error(1)
+}
 
-	mockAuth := &MockAuthClient{}
-	mockProfile := &MockProfileClient{}
-	mockMessage := &MockMessageClient{}
-
-	server := &Server{
-		cfg:           cfg,
-		authClient:    mockAuth,
-		profileClient: mockProfile,
-		messageClient: mockMessage,
+func (m *MockMessageClient) GetSieveScript(ctx context.Context, in *messagesproto.GetSieveScriptRequest, opts ...grpc.CallOption) (*messagesproto.GetSieveScriptResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
 	}
+	return args.Get(0).(*messagesproto.GetSieveScriptResponse), args.Error(1)
+}
 
-	return server, mockAuth, mockProfile, mockMessage
+func (m *MockMessageClient) CheckSieveScript(ctx context.Context, in *messagesproto.CheckSieveScriptRequest, opts ...grpc.CallOption) (*messagesproto.CheckSieveScriptResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.CheckSieveScriptResponse), args.Error(1)
 }
 
-func createRequestWithToken(method, url string, body io.Reader) *http.Request {
-	req := httptest.NewRequest(method, url, body)
-	req.AddCookie(&http.Cookie{
-		Name:  "access_token",
-		Value: "test-access-token",
-	})
-	return req
+func (m *MockMessageClient) MarkAsNotSpam(ctx context.Context, in *messagesproto.MarkAsNotSpamRequest, opts ...grpc.CallOption) (*messagesproto.MarkAsNotSpamResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.MarkAsNotSpamResponse), args.Error(1)
 }
 
-func TestServer_LoginHandler(t *testing.T) {
-	server, mockAuth, _, _ := setupTestServer()
+func (m *MockMessageClient) RestoreFromTrash(ctx context.Context, in *messagesproto.RestoreFromTrashRequest, opts ...grpc.CallOption) (*messagesproto.RestoreFromTrashResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.RestoreFromTrashResponse), args.Error(1)
+}
 
-	tests := []struct {
-		name           string
-		requestBody    interface{}
-		mockSetup      func()
-		expectedStatus int
-		expectedBody   interface{}
-	}{
-		{
-			name: "Success",
-			requestBody: map[string]interface{}{
-				"login":    "test@example.com",
-				"password": "password",
-			},
-			mockSetup: func() {
-				mockAuth.On("Login", mock.Anything, mock.AnythingOfType("*authproto.LoginRequest")).
-					Return(&authproto.LoginResponse{
-						AccessToken:  "access-token",
-						RefreshToken: "refresh-token",
-					}, nil)
-			},
-			expectedStatus: http.StatusOK,
-			expectedBody: map[string]interface{}{
-				"status":  float64(200),
-				"message": "success",
-				"body": map[string]interface{}{
-					"accessToken":  "access-token",
-					"refreshToken": "refresh-token",
-				},
-			},
-		},
-		{
-			name: "InvalidRequestBody",
-			requestBody: map[string]interface{}{
-				"invalid": "data",
-			},
-			mockSetup:      func() {},
-			expectedStatus: http.StatusBadRequest,
-			expectedBody: map[string]interface{}{
-				"status":  float64(400),
-				"message": "Invalid request body",
-			},
-		},
-		{
-			name: "LoginFailed",
-			requestBody: map[string]interface{}{
-				"login":    "test@example.com",
-				"password": "wrong-password",
-			},
-			mockSetup: func() {
-				mockAuth.On("Login", mock.Anything, mock.AnythingOfType("*authproto.LoginRequest")).
-					Return(nil, errors.New("login failed"))
-			},
-			expectedStatus: http.StatusInternalServerError,
-			expectedBody: map[string]interface{}{
-				"status":  float64(500),
-				"message": "Login failed",
-			},
-		},
+func (m *MockMessageClient) EmptyTrash(ctx context.Context, in *messagesproto.EmptyTrashRequest, opts ...grpc.CallOption) (*messagesproto.EmptyTrashResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
 	}
+	return args.Get(0).(*messagesproto.EmptyTrashResponse), args.Error(1)
+}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			tt.mockSetup()
+func (m *MockMessageClient) DeletePermanently(ctx context.Context, in *messagesproto.DeletePermanentlyRequest, opts ...grpc.CallOption) (*messagesproto.DeletePermanentlyResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.DeletePermanentlyResponse), args.Error(1)
+}
 
-			var body bytes.Buffer
-			json.NewEncoder(&body).Encode(tt.requestBody)
+func (m *MockMessageClient) Batch(ctx context.Context, in *messagesproto.BatchRequest, opts ...grpc.CallOption) (*messagesproto.BatchResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.BatchResponse), args.Error(1)
+}
 
-			req := httptest.NewRequest("POST", "/auth/login", &body)
-			w := httptest.NewRecorder()
+func (m *MockMessageClient) SetFlags(ctx context.Context, in *messagesproto.SetFlagsRequest, opts ...grpc.CallOption) (*messagesproto.SetFlagsResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.SetFlagsResponse), args.Error(1)
+}
 
-			server.loginHandler(w, req)
+func (m *MockMessageClient) CreateLabel(ctx context.Context, in *messagesproto.CreateLabelRequest, opts ...grpc.CallOption) (*messagesproto.CreateLabelResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.CreateLabelResponse), args.Error(1)
+}
 
-			resp := w.Result()
-			defer resp.Body.Close()
+func (m *MockMessageClient) GetLabels(ctx context.Context, in *messagesproto.GetLabelsRequest, opts ...grpc.CallOption) (*messagesproto.GetLabelsResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.GetLabelsResponse), args.Error(1)
+}
 
-			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+func (m *MockMessageClient) UpdateLabel(ctx context.Context, in *messagesproto.UpdateLabelRequest, opts ...grpc.CallOption) (*messagesproto.UpdateLabelResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.UpdateLabelResponse), args.Error(1)
+}
 
-			var responseBody map[string]interface{}
-			json.NewDecoder(resp.Body).Decode(&responseBody)
-			assert.Equal(t, tt.expectedBody, responseBody)
+func (m *MockMessageClient) DeleteLabel(ctx context.Context, in *messagesproto.DeleteLabelRequest, opts ...grpc.CallOption) (*messagesproto.DeleteLabelResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.DeleteLabelResponse), args.Error(1)
+}
 
-			mockAuth.AssertExpectations(t)
-		})
+func (m *MockMessageClient) ApplyLabel(ctx context.Context, in *messagesproto.ApplyLabelRequest, opts ...grpc.CallOption) (*messagesproto.ApplyLabelResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
 	}
+	return args.Get(0).(*messagesproto.ApplyLabelResponse), args.Error(1)
 }
 
-func TestServer_SignupHandler(t *testing.T) {
-	server, mockAuth, _, _ := setupTestServer()
+func (m *MockMessageClient) RemoveLabel(ctx context.Context, in *messagesproto.RemoveLabelRequest, opts ...grpc.CallOption) (*messagesproto.RemoveLabelResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.RemoveLabelResponse), args.Error(1)
+}
 
-	tests := []struct {
-		name           string
-		requestBody    interface{}
-		mockSetup      func()
-		expectedStatus int
-	}{
-		{
-			name: "Success",
-			requestBody: map[string]interface{}{
-				"name":     "New User",
-				"username": "newuser",
-				"birthday": "1990-01-01",
-				"gender":   "male",
-				"password": "password",
-			},
-			mockSetup: func() {
-				mockAuth.On("Signup", mock.Anything, mock.AnythingOfType("*authproto.SignupRequest")).
-					Return(&authproto.SignupResponse{
-						AccessToken:  "access-token",
-						RefreshToken: "refresh-token",
-					}, nil)
-			},
-			expectedStatus: http.StatusOK,
-		},
-		{
-			name: "AlreadyExists",
-			requestBody: map[string]interface{}{
-				"name":     "Existing User",
-				"username": "existinguser",
-				"birthday": "1990-01-01",
-				"gender":   "male",
-				"password": "password",
-			},
-			mockSetup: func() {
-				mockAuth.On("Signup", mock.Anything, mock.AnythingOfType("*authproto.SignupRequest")).
-					Return(nil, status.Error(codes.AlreadyExists, "user already exists"))
-			},
-			expectedStatus: http.StatusBadRequest,
-		},
+func (m *MockMessageClient) MoveFolder(ctx context.Context, in *messagesproto.MoveFolderRequest, opts ...grpc.CallOption) (*messagesproto.MoveFolderResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
 	}
+	return args.Get(0).(*messagesproto.MoveFolderResponse), args.Error(1)
+}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			tt.mockSetup()
+func (m *MockMessageClient) GetFolderTree(ctx context.Context, in *messagesproto.GetFolderTreeRequest, opts ...grpc.CallOption) (*messagesproto.GetFolderTreeResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.GetFolderTreeResponse), args.Error(1)
+}
 
-			var body bytes.Buffer
-			json.NewEncoder(&body).Encode(tt.requestBody)
+func (m *MockMessageClient) Forward(ctx context.Context, in *messagesproto.ForwardRequest, opts ...grpc.CallOption) (*messagesproto.ForwardResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.ForwardResponse), args.Error(1)
+}
 
-			req := httptest.NewRequest("POST", "/auth/signup", &body)
+func (m *MockMessageClient) GetForwarding(ctx context.Context, in *messagesproto.GetForwardingRequest, opts ...grpc.CallOption) (*messagesproto.GetForwardingResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.GetForwardingResponse), args.Error(1)
+}
+
+func (m *MockMessageClient) PutForwarding(ctx context.Context, in *messagesproto.PutForwardingRequest, opts ...grpc.CallOption) (*messagesproto.PutForwardingResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.PutForwardingResponse), args.Error(1)
+}
+
+func (m *MockMessageClient) CreateContact(ctx context.Context, in *messagesproto.CreateContactRequest, opts ...grpc.CallOption) (*messagesproto.CreateContactResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.CreateContactResponse), args.Error(1)
+}
+
+func (m *MockMessageClient) GetContacts(ctx context.Context, in *messagesproto.GetContactsRequest, opts ...grpc.CallOption) (*messagesproto.GetContactsResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.GetContactsResponse), args.Error(1)
+}
+
+func (m *MockMessageClient) UpdateContact(ctx context.Context, in *messagesproto.UpdateContactRequest, opts ...grpc.CallOption) (*messagesproto.UpdateContactResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.UpdateContactResponse), args.Error(1)
+}
+
+func (m *MockMessageClient) DeleteContact(ctx context.Context, in *messagesproto.DeleteContactRequest, opts ...grpc.CallOption) (*messagesproto.DeleteContactResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.DeleteContactResponse), args.Error(1)
+}
+
+func (m *MockMessageClient) SuggestContacts(ctx context.Context, in *messagesproto.SuggestContactsRequest, opts ...grpc.CallOption) (*messagesproto.SuggestContactsResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.SuggestContactsResponse), args.Error(1)
+}
+
+func (m *MockMessageClient) CreateContactGroup(ctx context.Context, in *messagesproto.CreateContactGroupRequest, opts ...grpc.CallOption) (*messagesproto.CreateContactGroupResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.CreateContactGroupResponse), args.Error(1)
+}
+
+func (m *MockMessageClient) GetContactGroups(ctx context.Context, in *messagesproto.GetContactGroupsRequest, opts ...grpc.CallOption) (*messagesproto.GetContactGroupsResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.GetContactGroupsResponse), args.Error(1)
+}
+
+func (m *MockMessageClient) RenameContactGroup(ctx context.Context, in *messagesproto.RenameContactGroupRequest, opts ...grpc.CallOption) (*messagesproto.RenameContactGroupResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.RenameContactGroupResponse), args.Error(1)
+}
+
+func (m *MockMessageClient) DeleteContactGroup(ctx context.Context, in *messagesproto.DeleteContactGroupRequest, opts ...grpc.CallOption) (*messagesproto.DeleteContactGroupResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.DeleteContactGroupResponse), args.Error(1)
+}
+
+func (m *MockMessageClient) GetSenderLists(ctx context.Context, in *messagesproto.GetSenderListsRequest, opts ...grpc.CallOption) (*messagesproto.GetSenderListsResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.GetSenderListsResponse), args.Error(1)
+}
+
+func (m *MockMessageClient) AddSenderRule(ctx context.Context, in *messagesproto.AddSenderRuleRequest, opts ...grpc.CallOption) (*messagesproto.AddSenderRuleResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.AddSenderRuleResponse), args.Error(1)
+}
+
+func (m *MockMessageClient) DeleteSenderRule(ctx context.Context, in *messagesproto.DeleteSenderRuleRequest, opts ...grpc.CallOption) (*messagesproto.DeleteSenderRuleResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.DeleteSenderRuleResponse), args.Error(1)
+}
+
+func (m *MockMessageClient) SetBlockedSenderAction(ctx context.Context, in *messagesproto.SetBlockedSenderActionRequest, opts ...grpc.CallOption) (*messagesproto.SetBlockedSenderActionResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.SetBlockedSenderActionResponse), args.Error(1)
+}
+
+func (m *MockMessageClient) BlockSender(ctx context.Context, in *messagesproto.BlockSenderRequest, opts ...grpc.CallOption) (*messagesproto.BlockSenderResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.BlockSenderResponse), args.Error(1)
+}
+
+func (m *MockMessageClient) Subscribe(ctx context.Context, in *messagesproto.SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[messagesproto.MailEvent], error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(grpc.ServerStreamingClient[messagesproto.MailEvent]), args.Error(1)
+}
+
+// fakeEventStream отдает заранее заданные события, после чего завершает поток ошибкой err
+type fakeEventStream struct {
+	grpc.ClientStream
+	header metadata.MD
+	events []*messagesproto.MailEvent
+	err    error
+}
+
+func (s *fakeEventStream) Header() (metadata.MD, error) {
+	return s.header, nil
+}
+
+func (s *fakeEventStream) Recv() (*messagesproto.MailEvent, error) {
+	if len(s.events) == 0 {
+		return nil, s.err
+	}
+	event := s.events[0]
+	s.events = s.events[1:]
+	return event, nil
+}
+
+func (m *MockMessageClient) GetPop3Settings(ctx context.Context, in *messagesproto.GetPop3SettingsRequest, opts ...grpc.CallOption) (*messagesproto.GetPop3SettingsResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.GetPop3SettingsResponse), args.Error(1)
+}
+
+func (m *MockMessageClient) PutPop3Settings(ctx context.Context, in *messagesproto.PutPop3SettingsRequest, opts ...grpc.CallOption) (*messagesproto.PutPop3SettingsResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.PutPop3SettingsResponse), args.Error(1)
+}
+
+func (m *MockMessageClient) GetMailState(ctx context.Context, in *messagesproto.GetMailStateRequest, opts ...grpc.CallOption) (*messagesproto.GetMailStateResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.GetMailStateResponse), args.Error(1)
+}
+
+func (m *MockMessageClient) GetMailChanges(ctx context.Context, in *messagesproto.GetMailChangesRequest, opts ...grpc.CallOption) (*messagesproto.GetMailChangesResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.GetMailChangesResponse), args.Error(1)
+}
+
+func (m *MockMessageClient) GetThreads(ctx context.Context, in *messagesproto.GetThreadsRequest, opts ...grpc.CallOption) (*messagesproto.GetThreadsResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.GetThreadsResponse), args.Error(1)
+}
+
+func (m *MockMessageClient) GetAliases(ctx context.Context, in *messagesproto.GetAliasesRequest, opts ...grpc.CallOption) (*messagesproto.GetAliasesResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.GetAliasesResponse), args.Error(1)
+}
+
+func (m *MockMessageClient) AddAlias(ctx context.Context, in *messagesproto.AddAliasRequest, opts ...grpc.CallOption) (*messagesproto.AddAliasResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.AddAliasResponse), args.Error(1)
+}
+
+func (m *MockMessageClient) DeleteAlias(ctx context.Context, in *messagesproto.DeleteAliasRequest, opts ...grpc.CallOption) (*messagesproto.DeleteAliasResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*messagesproto.DeleteAliasResponse), args.Error(1)
+}
+
+func setupTestServer() (*Server, *MockAuthClient, *MockProfileClient, *MockMessageClient) {
+	cfg := &config.AppConfig{
+		GatewayPort:        "8080",
+		GatewayMetricsPort: "9090",
+	}
+
+	mockAuth := &MockAuthClient{}
+	mockProfile := &MockProfileClient{}
+	mockMessage := &MockMessageClient{}
+
+	server := &Server{
+		cfg:           cfg,
+		authClient:    mockAuth,
+		profileClient: mockProfile,
+		messageClient: mockMessage,
+	}
+
+	return server, mockAuth, mockProfile, mockMessage
+}
+
+func createRequestWithToken(method, url string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, url, body)
+	req.AddCookie(&http.Cookie{
+		Name:  "access_token",
+		Value: "test-access-token",
+	})
+	return req
+}
+
+func TestServer_LoginHandler(t *testing.T) {
+	server, mockAuth, _, _ := setupTestServer()
+
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   interface{}
+	}{
+		{
+			name: "Success",
+			requestBody: map[string]interface{}{
+				"login":    "test@example.com",
+				"password": "password",
+			},
+			mockSetup: func() {
+				mockAuth.On("Login", mock.Anything, mock.AnythingOfType("*authproto.LoginRequest")).
+					Return(&authproto.LoginResponse{
+						AccessToken:  "access-token",
+						RefreshToken: "refresh-token",
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"status":  float64(200),
+				"message": "success",
+				"body": map[string]interface{}{
+					"accessToken":  "access-token",
+					"refreshToken": "refresh-token",
+				},
+			},
+		},
+		{
+			name: "InvalidRequestBody",
+			requestBody: map[string]interface{}{
+				"invalid": "data",
+			},
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"status":  float64(400),
+				"message": "Invalid request body",
+			},
+		},
+		{
+			name: "LoginFailed",
+			requestBody: map[string]interface{}{
+				"login":    "test@example.com",
+				"password": "wrong-password",
+			},
+			mockSetup: func() {
+				mockAuth.On("Login", mock.Anything, mock.AnythingOfType("*authproto.LoginRequest")).
+					Return(nil, errors.New("login failed"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: map[string]interface{}{
+				"status":  float64(500),
+				"message": "Login failed",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			var body bytes.Buffer
+			json.NewEncoder(&body).Encode(tt.requestBody)
+
+			req := httptest.NewRequest("POST", "/auth/login", &body)
+			w := httptest.NewRecorder()
+
+			server.loginHandler(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			var responseBody map[string]interface{}
+			json.NewDecoder(resp.Body).Decode(&responseBody)
+			assert.Equal(t, tt.expectedBody, responseBody)
+
+			mockAuth.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_SignupHandler(t *testing.T) {
+	server, mockAuth, _, _ := setupTestServer()
+
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		mockSetup      func()
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			requestBody: map[string]interface{}{
+				"name":     "New User",
+				"username": "newuser",
+				"birthday": "1990-01-01",
+				"gender":   "male",
+				"password": "password",
+			},
+			mockSetup: func() {
+				mockAuth.On("Signup", mock.Anything, mock.AnythingOfType("*authproto.SignupRequest")).
+					Return(&authproto.SignupResponse{
+						AccessToken:  "access-token",
+						RefreshToken: "refresh-token",
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "AlreadyExists",
+			requestBody: map[string]interface{}{
+				"name":     "Existing User",
+				"username": "existinguser",
+				"birthday": "1990-01-01",
+				"gender":   "male",
+				"password": "password",
+			},
+			mockSetup: func() {
+				mockAuth.On("Signup", mock.Anything, mock.AnythingOfType("*authproto.SignupRequest")).
+					Return(nil, status.Error(codes.AlreadyExists, "user already exists"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			var body bytes.Buffer
+			json.NewEncoder(&body).Encode(tt.requestBody)
+
+			req := httptest.NewRequest("POST", "/auth/signup", &body)
+			w := httptest.NewRecorder()
+
+			server.signupHandler(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			mockAuth.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_GetProfileHandler(t *testing.T) {
+	server, _, mockProfile, _ := setupTestServer()
+
+	tests := []struct {
+		name           string
+		setupCookies   bool
+		mockSetup      func()
+		expectedStatus int
+	}{
+		{
+			name:         "Success",
+			setupCookies: true,
+			mockSetup: func() {
+				mockProfile.On("GetProfile", mock.Anything, mock.AnythingOfType("*profileproto.GetProfileRequest")).
+					Return(&profileproto.GetProfileResponse{
+						Profile: &profileproto.Profile{
+							Username:   "testuser",
+							Name:       "Test",
+							Surname:    "User",
+							AvatarPath: "/avatars/test.jpg",
+						},
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "NoAccessToken",
+			setupCookies:   false,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:         "GRPCError",
+			setupCookies: true,
+			mockSetup: func() {
+				mockProfile.On("GetProfile", mock.Anything, mock.AnythingOfType("*profileproto.GetProfileRequest")).
+					Return(nil, status.Error(codes.NotFound, "profile not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest("GET", "/user/profile", nil)
+			if tt.setupCookies {
+				req.AddCookie(&http.Cookie{
+					Name:  "access_token",
+					Value: "test-token",
+				})
+			}
+
+			w := httptest.NewRecorder()
+
+			server.getProfileHandler(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			mockProfile.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_UpdateProfileHandler(t *testing.T) {
+	server, _, mockProfile, _ := setupTestServer()
+
+	updateRequest := map[string]interface{}{
+		"name":       "Updated",
+		"surname":    "User",
+		"patronymic": "Middle",
+		"gender":     "male",
+		"birthday":   "1990-01-01",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockProfile.On("UpdateProfile", mock.Anything, mock.AnythingOfType("*profileproto.UpdateProfileRequest")).
+			Return(&profileproto.UpdateProfileResponse{
+				Profile: &profileproto.Profile{
+					Username: "testuser",
+					Name:     "Updated",
+					Surname:  "User",
+				},
+			}, nil)
+
+		var body bytes.Buffer
+		json.NewEncoder(&body).Encode(updateRequest)
+
+		req := createRequestWithToken("PUT", "/user/profile", &body)
+		w := httptest.NewRecorder()
+
+		server.updateProfileHandler(w, req)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		mockProfile.AssertExpectations(t)
+	})
+
+	t.Run("InvalidRequestBody", func(t *testing.T) {
+		req := createRequestWithToken("PUT", "/user/profile", strings.NewReader("invalid json"))
+		w := httptest.NewRecorder()
+
+		server.updateProfileHandler(w, req)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestServer_MessagePageHandler(t *testing.T) {
+	server, _, _, mockMessage := setupTestServer()
+
+	tests := []struct {
+		name           string
+		messageID      string
+		mockSetup      func()
+		expectedStatus int
+	}{
+		{
+			name:      "Success",
+			messageID: "123",
+			mockSetup: func() {
+				mockMessage.On("MessagePage", mock.Anything, mock.AnythingOfType("*messagesproto.MessagePageRequest")).
+					Return(&messagesproto.MessagePageResponse{
+						Message: &messagesproto.FullMessage{
+							Topic: "Test Message",
+							Text:  "Test content",
+						},
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "MessageNotFound",
+			messageID: "999",
+			mockSetup: func() {
+				mockMessage.On("MessagePage", mock.Anything, mock.AnythingOfType("*messagesproto.MessagePageRequest")).
+					Return(nil, status.Error(codes.NotFound, "message not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := createRequestWithToken("GET", "/messages/"+tt.messageID, nil)
 			w := httptest.NewRecorder()
 
-			server.signupHandler(w, req)
+			server.messagePageHandler(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			mockMessage.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_SendHandler(t *testing.T) {
+	server, _, _, mockMessage := setupTestServer()
+
+	sendRequest := map[string]interface{}{
+		"topic": "Test Topic",
+		"text":  "Test Message",
+		"receivers": []map[string]interface{}{
+			{"email": "receiver@example.com"},
+		},
+		"files": []map[string]interface{}{},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockMessage.On("Send", mock.Anything, mock.AnythingOfType("*messagesproto.SendRequest")).
+			Return(&messagesproto.SendResponse{
+				MessageId: "123",
+			}, nil)
+
+		var body bytes.Buffer
+		json.NewEncoder(&body).Encode(sendRequest)
+
+		req := createRequestWithToken("POST", "/messages/send", &body)
+		w := httptest.NewRecorder()
+
+		server.sendHandler(w, req)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var response map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&response)
+		assert.Equal(t, "success", response["message"])
+		mockMessage.AssertExpectations(t)
+	})
+
+	t.Run("SendFailed", func(t *testing.T) {
+		mockMessage.On("Send", mock.Anything, mock.AnythingOfType("*messagesproto.SendRequest")).
+			Return(nil, errors.New("send failed"))
+
+		var body bytes.Buffer
+		json.NewEncoder(&body).Encode(sendRequest)
+
+		req := createRequestWithToken("POST", "/messages/send", &body)
+		w := httptest.NewRecorder()
+
+		server.sendHandler(w, req)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
+}
+
+func TestServer_GetFoldersHandler(t *testing.T) {
+	server, _, _, mockMessage := setupTestServer()
+
+	t.Run("Success", func(t *testing.T) {
+		mockMessage.On("GetFolders", mock.Anything, mock.AnythingOfType("*messagesproto.GetFoldersRequest")).
+			Return(&messagesproto.GetFoldersResponse{
+				Folders: []*messagesproto.Folder{
+					{
+						FolderId:   "1",
+						FolderName: "Inbox",
+						FolderType: "inbox",
+					},
+					{
+						FolderId:   "2",
+						FolderName: "Sent",
+						FolderType: "sent",
+					},
+				},
+			}, nil)
+
+		req := createRequestWithToken("GET", "/messages/get-folders", nil)
+		w := httptest.NewRecorder()
+
+		server.getFoldersHandler(w, req)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
+
+	t.Run("GetFoldersFailed", func(t *testing.T) {
+		mockMessage.On("GetFolders", mock.Anything, mock.AnythingOfType("*messagesproto.GetFoldersRequest")).
+			Return(nil, errors.New("get folders failed"))
+
+		req := createRequestWithToken("GET", "/messages/get-folders", nil)
+		w := httptest.NewRecorder()
+
+		server.getFoldersHandler(w, req)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
+}
+
+func TestServer_CreateFolderHandler(t *testing.T) {
+	server, _, _, mockMessage := setupTestServer()
+
+	createRequest := map[string]interface{}{
+		"folder_name": "New Folder",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockMessage.On("CreateFolder", mock.Anything, mock.AnythingOfType("*messagesproto.CreateFolderRequest")).
+			Return(&messagesproto.CreateFolderResponse{
+				FolderId:   "3",
+				FolderName: "New Folder",
+				FolderType: "custom",
+			}, nil)
+
+		var body bytes.Buffer
+		json.NewEncoder(&body).Encode(createRequest)
+
+		req := createRequestWithToken("POST", "/messages/create-folder", &body)
+		w := httptest.NewRecorder()
+
+		server.createFolderHandler(w, req)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
+
+	t.Run("FolderAlreadyExists", func(t *testing.T) {
+		mockMessage.On("CreateFolder", mock.Anything, mock.AnythingOfType("*messagesproto.CreateFolderRequest")).
+			Return(nil, status.Error(codes.AlreadyExists, "folder already exists"))
+
+		var body bytes.Buffer
+		json.NewEncoder(&body).Encode(createRequest)
+
+		req := createRequestWithToken("POST", "/messages/create-folder", &body)
+		w := httptest.NewRecorder()
+
+		server.createFolderHandler(w, req)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
+}
+
+func TestServer_RenameFolderHandler(t *testing.T) {
+	server, _, _, mockMessage := setupTestServer()
+
+	renameRequest := map[string]interface{}{
+		"folder_id":       "1",
+		"new_folder_name": "Renamed Folder",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockMessage.On("RenameFolder", mock.Anything, mock.AnythingOfType("*messagesproto.RenameFolderRequest")).
+			Return(&messagesproto.RenameFolderResponse{
+				FolderId:   "1",
+				FolderName: "Renamed Folder",
+				FolderType: "custom",
+			}, nil)
+
+		var body bytes.Buffer
+		json.NewEncoder(&body).Encode(renameRequest)
+
+		req := createRequestWithToken("PUT", "/messages/rename-folder", &body)
+		w := httptest.NewRecorder()
+
+		server.renameFolderHandler(w, req)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
+}
+
+func TestServer_SaveDraftHandler(t *testing.T) {
+	server, _, _, mockMessage := setupTestServer()
+
+	saveDraftRequest := map[string]interface{}{
+		"draft_id":  "",
+		"thread_id": "thread123",
+		"topic":     "Draft Topic",
+		"text":      "Draft Text",
+		"receivers": []map[string]interface{}{
+			{"email": "test@example.com"},
+		},
+		"files": []map[string]interface{}{},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockMessage.On("SaveDraft", mock.Anything, mock.AnythingOfType("*messagesproto.SaveDraftRequest")).
+			Return(&messagesproto.SaveDraftResponse{
+				DraftId: "123",
+			}, nil)
+
+		var body bytes.Buffer
+		json.NewEncoder(&body).Encode(saveDraftRequest)
+
+		req := createRequestWithToken("POST", "/messages/save-draft", &body)
+		w := httptest.NewRecorder()
+
+		server.saveDraftHandler(w, req)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
+}
+
+func TestServer_DeleteDraftHandler(t *testing.T) {
+	server, _, _, mockMessage := setupTestServer()
+
+	deleteDraftRequest := map[string]interface{}{
+		"draft_id": "123",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockMessage.On("DeleteDraft", mock.Anything, mock.AnythingOfType("*messagesproto.DeleteDraftRequest")).
+			Return(&messagesproto.DeleteDraftResponse{
+				Success: true,
+			}, nil)
+
+		var body bytes.Buffer
+		json.NewEncoder(&body).Encode(deleteDraftRequest)
+
+		req := createRequestWithToken("DELETE", "/messages/delete-draft", &body)
+		w := httptest.NewRecorder()
+
+		server.deleteDraftHandler(w, req)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
+}
+
+func TestServer_MarkAsSpamHandler(t *testing.T) {
+	server, _, _, mockMessage := setupTestServer()
+
+	spamRequest := map[string]interface{}{
+		"message_id": "123",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockMessage.On("MarkAsSpam", mock.Anything, mock.AnythingOfType("*messagesproto.MarkAsSpamRequest")).
+			Return(&messagesproto.MarkAsSpamResponse{}, nil)
+
+		var body bytes.Buffer
+		json.NewEncoder(&body).Encode(spamRequest)
+
+		req := createRequestWithToken("POST", "/messages/mark-as-spam", &body)
+		w := httptest.NewRecorder()
+
+		server.markAsSpamHandler(w, req)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
+}
+
+func TestServer_MarkAsNotSpamHandler(t *testing.T) {
+	server, _, _, mockMessage := setupTestServer()
+
+	notSpamRequest := map[string]interface{}{
+		"message_id": "123",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockMessage.On("MarkAsNotSpam", mock.Anything, mock.AnythingOfType("*messagesproto.MarkAsNotSpamRequest")).
+			Return(&messagesproto.MarkAsNotSpamResponse{}, nil).Once()
+
+		var body bytes.Buffer
+		json.NewEncoder(&body).Encode(notSpamRequest)
+
+		req := createRequestWithToken("POST", "/messages/mark-as-not-spam", &body)
+		w := httptest.NewRecorder()
+
+		server.markAsNotSpamHandler(w, req)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/messages/mark-as-not-spam", bytes.NewBufferString(`{"message_id":"123"}`))
+		w := httptest.NewRecorder()
+
+		server.markAsNotSpamHandler(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+	})
+}
+
+func TestServer_MoveToFolderHandler(t *testing.T) {
+	server, _, _, mockMessage := setupTestServer()
+
+	moveRequest := map[string]interface{}{
+		"message_id": "123",
+		"folder_id":  "2",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockMessage.On("MoveToFolder", mock.Anything, mock.AnythingOfType("*messagesproto.MoveToFolderRequest")).
+			Return(&messagesproto.MoveToFolderResponse{}, nil)
+
+		var body bytes.Buffer
+		json.NewEncoder(&body).Encode(moveRequest)
+
+		req := createRequestWithToken("POST", "/messages/move-to-folder", &body)
+		w := httptest.NewRecorder()
+
+		server.moveToFolderHandler(w, req)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
+}
+
+func TestServer_UploadAvatarHandler(t *testing.T) {
+	server, _, mockProfile, _ := setupTestServer()
+
+	t.Run("Success", func(t *testing.T) {
+		mockProfile.On("UploadAvatar", mock.Anything, mock.AnythingOfType("*profileproto.UploadAvatarRequest")).
+			Return(&profileproto.UploadAvatarResponse{
+				AvatarPath: "/avatars/new-avatar.jpg",
+			}, nil)
+
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, _ := writer.CreateFormFile("avatar", "test.jpg")
+		part.Write([]byte("fake image data"))
+		writer.Close()
+
+		req := createRequestWithToken("POST", "/user/upload/avatar", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		w := httptest.NewRecorder()
+
+		server.uploadAvatarHandler(w, req)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		mockProfile.AssertExpectations(t)
+	})
+
+	t.Run("NoFileProvided", func(t *testing.T) {
+		req := createRequestWithToken("POST", "/user/upload/avatar", strings.NewReader(""))
+		req.Header.Set("Content-Type", "multipart/form-data")
+		w := httptest.NewRecorder()
+
+		server.uploadAvatarHandler(w, req)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestNormalizeAvatarURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		hasError bool
+	}{
+		{
+			name:     "ValidURL",
+			input:    "http://example.com/avatar.jpg",
+			expected: "http://example.com/avatar.jpg",
+			hasError: false,
+		},
+		{
+			name:     "LocalhostToMinio",
+			input:    "http://localhost:9000/avatar.jpg",
+			expected: "http://minio:9000/avatar.jpg",
+			hasError: false,
+		},
+		{
+			name:     "127.0.0.1ToMinio",
+			input:    "http://127.0.0.1:9000/avatar.jpg",
+			expected: "http://minio:9000/avatar.jpg",
+			hasError: false,
+		},
+		{
+			name:     "InvalidURL",
+			input:    "://invalid",
+			expected: "",
+			hasError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := normalizeAvatarURL(tt.input)
+
+			if tt.hasError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestResolveFolderID(t *testing.T) {
+	folders := []*messagesproto.Folder{
+		{FolderId: "1", FolderName: "Inbox", FolderType: "inbox"},
+		{FolderId: "2", FolderName: "Sent", FolderType: "sent"},
+		{FolderId: "3", FolderName: "Custom", FolderType: "custom"},
+	}
+
+	tests := []struct {
+		name     string
+		target   string
+		expected string
+	}{
+		{
+			name:     "ByFolderID",
+			target:   "1",
+			expected: "1",
+		},
+		{
+			name:     "ByFolderName",
+			target:   "Inbox",
+			expected: "1",
+		},
+		{
+			name:     "ByFolderType",
+			target:   "inbox",
+			expected: "1",
+		},
+		{
+			name:     "NotFound",
+			target:   "nonexistent",
+			expected: "",
+		},
+		{
+			name:     "EmptyTarget",
+			target:   "",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := resolveFolderID(folders, tt.target)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestMapProfile(t *testing.T) {
+	profile := &profileproto.Profile{
+		Username:   "testuser",
+		CreatedAt:  "2023-01-01",
+		Name:       "Test",
+		Surname:    "User",
+		Patronymic: "Middle",
+		Gender:     "male",
+		Birthday:   "1990-01-01",
+		AvatarPath: "/avatars/test.jpg",
+	}
+
+	result := mapProfile(profile)
 
-			resp := w.Result()
-			defer resp.Body.Close()
+	assert.Equal(t, "testuser", result.Username)
+	assert.Equal(t, "Test", result.Name)
+	assert.Equal(t, "User", result.Surname)
+	assert.Equal(t, "Middle", result.Patronymic)
+	assert.Equal(t, "male", result.Gender)
+	assert.Equal(t, "1990-01-01", result.DateOfBirth)
+	assert.Equal(t, "/avatars/test.jpg", result.AvatarPath)
+	assert.Equal(t, "user", result.Role)
+}
 
-			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
-			mockAuth.AssertExpectations(t)
+func TestMapProfile_Nil(t *testing.T) {
+	result := mapProfile(nil)
+
+	assert.Equal(t, "", result.Username)
+	assert.Equal(t, "", result.Name)
+	assert.Equal(t, "", result.Surname)
+}
+
+func TestServer_RefreshHandler(t *testing.T) {
+	server, mockAuth, _, _ := setupTestServer()
+
+	t.Run("Success", func(t *testing.T) {
+		mockAuth.On("Refresh", mock.Anything, mock.AnythingOfType("*authproto.RefreshRequest")).
+			Return(&authproto.RefreshResponse{
+				AccessToken: "new-access-token",
+			}, nil)
+
+		req := httptest.NewRequest("POST", "/auth/refresh", nil)
+		req.AddCookie(&http.Cookie{
+			Name:  "refresh_token",
+			Value: "old-refresh-token",
 		})
-	}
+		w := httptest.NewRecorder()
+
+		server.refreshHandler(w, req)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		mockAuth.AssertExpectations(t)
+	})
+
+	t.Run("NoRefreshToken", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/auth/refresh", nil)
+		w := httptest.NewRecorder()
+
+		server.refreshHandler(w, req)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
 }
 
-func TestServer_GetProfileHandler(t *testing.T) {
-	server, _, mockProfile, _ := setupTestServer()
+func TestServer_LogoutHandler(t *testing.T) {
+	server, mockAuth, _, _ := setupTestServer()
+
+	t.Run("Success", func(t *testing.T) {
+		mockAuth.On("Logout", mock.Anything, mock.AnythingOfType("*authproto.LogoutRequest")).
+			Return(&authproto.LogoutResponse{}, nil)
+
+		req := httptest.NewRequest("POST", "/auth/logout", nil)
+		w := httptest.NewRecorder()
+
+		server.logoutHandler(w, req)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		cookies := resp.Cookies()
+		var accessCookie, refreshCookie *http.Cookie
+		for _, cookie := range cookies {
+			if cookie.Name == "access_token" {
+				accessCookie = cookie
+			} else if cookie.Name == "refresh_token" {
+				refreshCookie = cookie
+			}
+		}
+		assert.NotNil(t, accessCookie)
+		assert.NotNil(t, refreshCookie)
+		assert.Equal(t, -1, accessCookie.MaxAge)
+		assert.Equal(t, -1, refreshCookie.MaxAge)
+
+		mockAuth.AssertExpectations(t)
+	})
+}
 
+func TestWriteGrpcAwareError(t *testing.T) {
 	tests := []struct {
 		name           string
-		setupCookies   bool
-		mockSetup      func()
+		err            error
+		defaultMessage string
 		expectedStatus int
 	}{
 		{
-			name:         "Success",
-			setupCookies: true,
-			mockSetup: func() {
-				mockProfile.On("GetProfile", mock.Anything, mock.AnythingOfType("*profileproto.GetProfileRequest")).
-					Return(&profileproto.GetProfileResponse{
-						Profile: &profileproto.Profile{
-							Username:   "testuser",
-							Name:       "Test",
-							Surname:    "User",
-							AvatarPath: "/avatars/test.jpg",
-						},
-					}, nil)
-			},
-			expectedStatus: http.StatusOK,
+			name:           "Unauthenticated",
+			err:            status.Error(codes.Unauthenticated, "invalid token"),
+			defaultMessage: "Authentication failed",
+			expectedStatus: http.StatusUnauthorized,
 		},
 		{
-			name:           "NoAccessToken",
-			setupCookies:   false,
-			mockSetup:      func() {},
-			expectedStatus: http.StatusUnauthorized,
+			name:           "InvalidArgument",
+			err:            status.Error(codes.InvalidArgument, "invalid input"),
+			defaultMessage: "Invalid request",
+			expectedStatus: http.StatusBadRequest,
 		},
 		{
-			name:         "GRPCError",
-			setupCookies: true,
-			mockSetup: func() {
-				mockProfile.On("GetProfile", mock.Anything, mock.AnythingOfType("*profileproto.GetProfileRequest")).
-					Return(nil, status.Error(codes.NotFound, "profile not found"))
-			},
+			name:           "NotFound",
+			err:            status.Error(codes.NotFound, "not found"),
+			defaultMessage: "Resource not found",
 			expectedStatus: http.StatusNotFound,
 		},
+		{
+			name:           "PermissionDenied",
+			err:            status.Error(codes.PermissionDenied, "access denied"),
+			defaultMessage: "Access denied",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "AlreadyExists",
+			err:            status.Error(codes.AlreadyExists, "already exists"),
+			defaultMessage: "Conflict",
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "GenericError",
+			err:            errors.New("generic error"),
+			defaultMessage: "Internal error",
+			expectedStatus: http.StatusInternalServerError,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.mockSetup()
-
-			req := httptest.NewRequest("GET", "/user/profile", nil)
-			if tt.setupCookies {
-				req.AddCookie(&http.Cookie{
-					Name:  "access_token",
-					Value: "test-token",
-				})
-			}
-
 			w := httptest.NewRecorder()
-
-			server.getProfileHandler(w, req)
+			writeGrpcAwareError(w, tt.err, tt.defaultMessage)
 
 			resp := w.Result()
 			defer resp.Body.Close()
 
 			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
-			mockProfile.AssertExpectations(t)
 		})
 	}
 }
 
-func TestServer_UpdateProfileHandler(t *testing.T) {
-	server, _, mockProfile, _ := setupTestServer()
+func TestServer_ReplyHandler(t *testing.T) {
+	server, _, _, mockMessage := setupTestServer()
 
-	updateRequest := map[string]interface{}{
-		"name":       "Updated",
-		"surname":    "User",
-		"patronymic": "Middle",
-		"gender":     "male",
-		"birthday":   "1990-01-01",
+	replyRequest := map[string]interface{}{
+		"root_message_id": "123",
+		"topic":           "Re: Test",
+		"text":            "Reply text",
+		"thread_root":     "thread123",
+		"receivers": []map[string]interface{}{
+			{"email": "reply@example.com"},
+		},
+		"files": []map[string]interface{}{},
 	}
 
 	t.Run("Success", func(t *testing.T) {
-		mockProfile.On("UpdateProfile", mock.Anything, mock.AnythingOfType("*profileproto.UpdateProfileRequest")).
-			Return(&profileproto.UpdateProfileResponse{
-				Profile: &profileproto.Profile{
-					Username: "testuser",
-					Name:     "Updated",
-					Surname:  "User",
-				},
+		mockMessage.On("Reply", mock.Anything, mock.AnythingOfType("*messagesproto.ReplyRequest")).
+			Return(&messagesproto.ReplyResponse{
+				MessageId: "456",
 			}, nil)
 
 		var body bytes.Buffer
-		json.NewEncoder(&body).Encode(updateRequest)
+		json.NewEncoder(&body).Encode(replyRequest)
 
-		req := createRequestWithToken("PUT", "/user/profile", &body)
+		req := createRequestWithToken("POST", "/messages/reply", &body)
 		w := httptest.NewRecorder()
 
-		server.updateProfileHandler(w, req)
+		server.replyHandler(w, req)
 
 		resp := w.Result()
 		defer resp.Body.Close()
 
 		assert.Equal(t, http.StatusOK, resp.StatusCode)
-		mockProfile.AssertExpectations(t)
+		mockMessage.AssertExpectations(t)
 	})
 
 	t.Run("InvalidRequestBody", func(t *testing.T) {
-		req := createRequestWithToken("PUT", "/user/profile", strings.NewReader("invalid json"))
+		req := createRequestWithToken("POST", "/messages/reply", strings.NewReader("invalid json"))
 		w := httptest.NewRecorder()
 
-		server.updateProfileHandler(w, req)
+		server.replyHandler(w, req)
 
 		resp := w.Result()
 		defer resp.Body.Close()
@@ -526,139 +1675,99 @@ func TestServer_UpdateProfileHandler(t *testing.T) {
 	})
 }
 
-func TestServer_MessagePageHandler(t *testing.T) {
-	server, _, _, mockMessage := setupTestServer()
-
-	tests := []struct {
-		name           string
-		messageID      string
-		mockSetup      func()
-		expectedStatus int
-	}{
-		{
-			name:      "Success",
-			messageID: "123",
-			mockSetup: func() {
-				mockMessage.On("MessagePage", mock.Anything, mock.AnythingOfType("*messagesproto.MessagePageRequest")).
-					Return(&messagesproto.MessagePageResponse{
-						Message: &messagesproto.FullMessage{
-							Topic: "Test Message",
-							Text:  "Test content",
-						},
-					}, nil)
-			},
-			expectedStatus: http.StatusOK,
-		},
-		{
-			name:      "MessageNotFound",
-			messageID: "999",
-			mockSetup: func() {
-				mockMessage.On("MessagePage", mock.Anything, mock.AnythingOfType("*messagesproto.MessagePageRequest")).
-					Return(nil, status.Error(codes.NotFound, "message not found"))
-			},
-			expectedStatus: http.StatusNotFound,
-		},
-	}
+func TestServer_SettingsHandler(t *testing.T) {
+	server, _, mockProfile, _ := setupTestServer()
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			tt.mockSetup()
+	t.Run("Success", func(t *testing.T) {
+		mockProfile.On("Settings", mock.Anything, mock.AnythingOfType("*profileproto.SettingsRequest")).
+			Return(&profileproto.SettingsResponse{
+				Settings: &profileproto.Settings{
+					Theme: "dark",
+				},
+			}, nil)
 
-			req := createRequestWithToken("GET", "/messages/"+tt.messageID, nil)
-			w := httptest.NewRecorder()
+		req := createRequestWithToken("GET", "/user/settings", nil)
+		w := httptest.NewRecorder()
 
-			server.messagePageHandler(w, req)
+		server.settingsHandler(w, req)
 
-			resp := w.Result()
-			defer resp.Body.Close()
+		resp := w.Result()
+		defer resp.Body.Close()
 
-			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
-			mockMessage.AssertExpectations(t)
-		})
-	}
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		mockProfile.AssertExpectations(t)
+	})
 }
 
-func TestServer_SendHandler(t *testing.T) {
+func TestServer_SendDraftHandler(t *testing.T) {
 	server, _, _, mockMessage := setupTestServer()
 
-	sendRequest := map[string]interface{}{
-		"topic": "Test Topic",
-		"text":  "Test Message",
-		"receivers": []map[string]interface{}{
-			{"email": "receiver@example.com"},
-		},
-		"files": []map[string]interface{}{},
+	sendDraftRequest := map[string]interface{}{
+		"draft_id": "123",
 	}
 
 	t.Run("Success", func(t *testing.T) {
-		mockMessage.On("Send", mock.Anything, mock.AnythingOfType("*messagesproto.SendRequest")).
-			Return(&messagesproto.SendResponse{
-				MessageId: "123",
+		mockMessage.On("SendDraft", mock.Anything, mock.AnythingOfType("*messagesproto.SendDraftRequest")).
+			Return(&messagesproto.SendDraftResponse{
+				Success:   true,
+				MessageId: "456",
 			}, nil)
 
 		var body bytes.Buffer
-		json.NewEncoder(&body).Encode(sendRequest)
+		json.NewEncoder(&body).Encode(sendDraftRequest)
 
-		req := createRequestWithToken("POST", "/messages/send", &body)
+		req := createRequestWithToken("POST", "/messages/send-draft", &body)
 		w := httptest.NewRecorder()
 
-		server.sendHandler(w, req)
+		server.sendDraftHandler(w, req)
 
 		resp := w.Result()
 		defer resp.Body.Close()
 
 		assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-		var response map[string]interface{}
-		json.NewDecoder(resp.Body).Decode(&response)
-		assert.Equal(t, "success", response["message"])
 		mockMessage.AssertExpectations(t)
 	})
+}
 
-	t.Run("SendFailed", func(t *testing.T) {
-		mockMessage.On("Send", mock.Anything, mock.AnythingOfType("*messagesproto.SendRequest")).
-			Return(nil, errors.New("send failed"))
+func TestServer_DeleteFolderHandler(t *testing.T) {
+	server, _, _, mockMessage := setupTestServer()
 
-		var body bytes.Buffer
-		json.NewEncoder(&body).Encode(sendRequest)
+	t.Run("Success", func(t *testing.T) {
+		mockMessage.On("DeleteFolder", mock.Anything, mock.AnythingOfType("*messagesproto.DeleteFolderRequest")).
+			Return(&messagesproto.DeleteFolderResponse{}, nil)
 
-		req := createRequestWithToken("POST", "/messages/send", &body)
+		req := createRequestWithToken("DELETE", "/messages/delete-folder?folder_id=1", nil)
 		w := httptest.NewRecorder()
 
-		server.sendHandler(w, req)
+		server.deleteFolderHandler(w, req)
 
 		resp := w.Result()
 		defer resp.Body.Close()
 
-		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
 		mockMessage.AssertExpectations(t)
 	})
 }
 
-func TestServer_GetFoldersHandler(t *testing.T) {
+func TestServer_DeleteMessageFromFolderHandler(t *testing.T) {
 	server, _, _, mockMessage := setupTestServer()
 
+	deleteRequest := map[string]interface{}{
+		"message_id": "123",
+		"folder_id":  "1",
+	}
+
 	t.Run("Success", func(t *testing.T) {
-		mockMessage.On("GetFolders", mock.Anything, mock.AnythingOfType("*messagesproto.GetFoldersRequest")).
-			Return(&messagesproto.GetFoldersResponse{
-				Folders: []*messagesproto.Folder{
-					{
-						FolderId:   "1",
-						FolderName: "Inbox",
-						FolderType: "inbox",
-					},
-					{
-						FolderId:   "2",
-						FolderName: "Sent",
-						FolderType: "sent",
-					},
-				},
-			}, nil)
+		mockMessage.On("DeleteMessageFromFolder", mock.Anything, mock.AnythingOfType("*messagesproto.DeleteMessageFromFolderRequest")).
+			Return(&messagesproto.DeleteMessageFromFolderResponse{}, nil)
 
-		req := createRequestWithToken("GET", "/messages/get-folders", nil)
+		var body bytes.Buffer
+		json.NewEncoder(&body).Encode(deleteRequest)
+
+		req := createRequestWithToken("DELETE", "/messages/delete-message-from-folder", &body)
 		w := httptest.NewRecorder()
 
-		server.getFoldersHandler(w, req)
+		server.deleteMessageFromFolderHandler(w, req)
 
 		resp := w.Result()
 		defer resp.Body.Close()
@@ -666,671 +1775,847 @@ func TestServer_GetFoldersHandler(t *testing.T) {
 		assert.Equal(t, http.StatusOK, resp.StatusCode)
 		mockMessage.AssertExpectations(t)
 	})
+}
 
-	t.Run("GetFoldersFailed", func(t *testing.T) {
-		mockMessage.On("GetFolders", mock.Anything, mock.AnythingOfType("*messagesproto.GetFoldersRequest")).
-			Return(nil, errors.New("get folders failed"))
+func TestServer_SieveScriptHandlers(t *testing.T) {
+	t.Run("Get", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("GetSieveScript", mock.Anything, mock.AnythingOfType("*messagesproto.GetSieveScriptRequest")).
+			Return(&messagesproto.GetSieveScriptResponse{Script: "keep;"}, nil)
 
-		req := createRequestWithToken("GET", "/messages/get-folders", nil)
+		req := createRequestWithToken("GET", "/messages/sieve-script", nil)
 		w := httptest.NewRecorder()
 
-		server.getFoldersHandler(w, req)
+		server.getSieveScriptHandler(w, req)
 
-		resp := w.Result()
-		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
 
-		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	t.Run("PutInvalidScript", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("PutSieveScript", mock.Anything, &messagesproto.PutSieveScriptRequest{Script: "bogus"}).
+			Return(nil, status.Error(codes.InvalidArgument, "invalid sieve script"))
+
+		var body bytes.Buffer
+		json.NewEncoder(&body).Encode(map[string]string{"script": "bogus"})
+
+		req := createRequestWithToken("PUT", "/messages/sieve-script", &body)
+		w := httptest.NewRecorder()
+
+		server.putSieveScriptHandler(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
 		mockMessage.AssertExpectations(t)
 	})
-}
 
-func TestServer_CreateFolderHandler(t *testing.T) {
-	server, _, _, mockMessage := setupTestServer()
+	t.Run("CheckUnauthorized", func(t *testing.T) {
+		server, _, _, _ := setupTestServer()
 
-	createRequest := map[string]interface{}{
-		"folder_name": "New Folder",
-	}
+		req := httptest.NewRequest("POST", "/messages/check-sieve-script", nil)
+		w := httptest.NewRecorder()
 
-	t.Run("Success", func(t *testing.T) {
-		mockMessage.On("CreateFolder", mock.Anything, mock.AnythingOfType("*messagesproto.CreateFolderRequest")).
-			Return(&messagesproto.CreateFolderResponse{
-				FolderId:   "3",
-				FolderName: "New Folder",
-				FolderType: "custom",
-			}, nil)
+		server.checkSieveScriptHandler(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+	})
+}
+
+func TestServer_TrashHandlers(t *testing.T) {
+	t.Run("RestoreFromTrash", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("RestoreFromTrash", mock.Anything, &messagesproto.RestoreFromTrashRequest{MessageId: "123"}).
+			Return(&messagesproto.RestoreFromTrashResponse{FolderId: "10"}, nil)
 
 		var body bytes.Buffer
-		json.NewEncoder(&body).Encode(createRequest)
+		json.NewEncoder(&body).Encode(map[string]string{"message_id": "123"})
 
-		req := createRequestWithToken("POST", "/messages/create-folder", &body)
+		req := createRequestWithToken("POST", "/messages/restore-from-trash", &body)
 		w := httptest.NewRecorder()
 
-		server.createFolderHandler(w, req)
-
-		resp := w.Result()
-		defer resp.Body.Close()
+		server.restoreFromTrashHandler(w, req)
 
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
 		mockMessage.AssertExpectations(t)
 	})
 
-	t.Run("FolderAlreadyExists", func(t *testing.T) {
-		mockMessage.On("CreateFolder", mock.Anything, mock.AnythingOfType("*messagesproto.CreateFolderRequest")).
-			Return(nil, status.Error(codes.AlreadyExists, "folder already exists"))
+	t.Run("RestoreNotInTrash", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("RestoreFromTrash", mock.Anything, mock.AnythingOfType("*messagesproto.RestoreFromTrashRequest")).
+			Return(nil, status.Error(codes.NotFound, "message is not in trash"))
 
-		var body bytes.Buffer
-		json.NewEncoder(&body).Encode(createRequest)
+		req := createRequestWithToken("POST", "/messages/restore-from-trash", bytes.NewBufferString(`{"message_id":"123"}`))
+		w := httptest.NewRecorder()
 
-		req := createRequestWithToken("POST", "/messages/create-folder", &body)
+		server.restoreFromTrashHandler(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+
+	t.Run("EmptyTrash", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("EmptyTrash", mock.Anything, mock.AnythingOfType("*messagesproto.EmptyTrashRequest")).
+			Return(&messagesproto.EmptyTrashResponse{DeletedCount: 3}, nil)
+
+		req := createRequestWithToken("POST", "/messages/empty-trash", nil)
 		w := httptest.NewRecorder()
 
-		server.createFolderHandler(w, req)
+		server.emptyTrashHandler(w, req)
 
-		resp := w.Result()
-		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
 
-		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	t.Run("DeletePermanently", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("DeletePermanently", mock.Anything, &messagesproto.DeletePermanentlyRequest{MessageId: "123"}).
+			Return(&messagesproto.DeletePermanentlyResponse{}, nil)
+
+		req := createRequestWithToken("DELETE", "/messages/delete-permanently", bytes.NewBufferString(`{"message_id":"123"}`))
+		w := httptest.NewRecorder()
+
+		server.deletePermanentlyHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
 		mockMessage.AssertExpectations(t)
 	})
+
+	t.Run("DeletePermanentlyInvalidBody", func(t *testing.T) {
+		server, _, _, _ := setupTestServer()
+
+		req := createRequestWithToken("DELETE", "/messages/delete-permanently", bytes.NewBufferString(`{`))
+		w := httptest.NewRecorder()
+
+		server.deletePermanentlyHandler(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
 }
 
-func TestServer_RenameFolderHandler(t *testing.T) {
-	server, _, _, mockMessage := setupTestServer()
+func TestServer_BatchHandler(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("Batch", mock.Anything, mock.MatchedBy(func(req *messagesproto.BatchRequest) bool {
+			return req.Action == "move" && len(req.MessageIds) == 2 && req.TargetFolderId == "5"
+		})).Return(&messagesproto.BatchResponse{Results: []*messagesproto.BatchItemResult{
+			{MessageId: "1", Success: true},
+			{MessageId: "2", Error: "message not found"},
+		}}, nil)
+
+		body := `{"action":"move","message_ids":["1","2"],"target_folder_id":"5"}`
+		req := createRequestWithToken("POST", "/messages/batch", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
 
-	renameRequest := map[string]interface{}{
-		"folder_id":       "1",
-		"new_folder_name": "Renamed Folder",
-	}
+		server.batchHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("Batch", mock.Anything, mock.AnythingOfType("*messagesproto.BatchRequest")).
+			Return(nil, status.Error(codes.InvalidArgument, "too many messages in batch"))
+
+		req := createRequestWithToken("POST", "/messages/batch", bytes.NewBufferString(`{"action":"mark_as_read","folder_id":"3"}`))
+		w := httptest.NewRecorder()
+
+		server.batchHandler(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+
+	t.Run("InvalidBody", func(t *testing.T) {
+		server, _, _, _ := setupTestServer()
+
+		req := createRequestWithToken("POST", "/messages/batch", bytes.NewBufferString(`{`))
+		w := httptest.NewRecorder()
+
+		server.batchHandler(w, req)
 
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+}
+
+func TestServer_SetFlagsHandler(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
-		mockMessage.On("RenameFolder", mock.Anything, mock.AnythingOfType("*messagesproto.RenameFolderRequest")).
-			Return(&messagesproto.RenameFolderResponse{
-				FolderId:   "1",
-				FolderName: "Renamed Folder",
-				FolderType: "custom",
-			}, nil)
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("SetFlags", mock.Anything, mock.MatchedBy(func(req *messagesproto.SetFlagsRequest) bool {
+			return req.MessageId == "123" && req.Read == "false" && req.Starred == ""
+		})).Return(&messagesproto.SetFlagsResponse{}, nil)
 
-		var body bytes.Buffer
-		json.NewEncoder(&body).Encode(renameRequest)
+		req := createRequestWithToken("POST", "/messages/set-flags", bytes.NewBufferString(`{"message_id":"123","read":"false"}`))
+		w := httptest.NewRecorder()
 
-		req := createRequestWithToken("PUT", "/messages/rename-folder", &body)
+		server.setFlagsHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("SetFlags", mock.Anything, mock.AnythingOfType("*messagesproto.SetFlagsRequest")).
+			Return(nil, status.Error(codes.NotFound, "message not found"))
+
+		req := createRequestWithToken("POST", "/messages/set-flags", bytes.NewBufferString(`{"message_id":"123","starred":"true"}`))
 		w := httptest.NewRecorder()
 
-		server.renameFolderHandler(w, req)
+		server.setFlagsHandler(w, req)
 
-		resp := w.Result()
-		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+}
 
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+func TestServer_ForwardHandler(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("Forward", mock.Anything, mock.MatchedBy(func(req *messagesproto.ForwardRequest) bool {
+			return req.MessageId == "10" && len(req.Receivers) == 1 && req.Receivers[0].Email == "a@a4code.ru" && req.Text == "FYI"
+		})).Return(&messagesproto.ForwardResponse{MessageId: "42"}, nil)
+
+		req := createRequestWithToken("POST", "/messages/forward", bytes.NewBufferString(`{"message_id":"10","receivers":[{"email":"a@a4code.ru"}],"text":"FYI"}`))
+		w := httptest.NewRecorder()
+
+		server.forwardHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		assert.Contains(t, w.Body.String(), "42")
 		mockMessage.AssertExpectations(t)
 	})
+
+	t.Run("AccessDenied", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("Forward", mock.Anything, mock.AnythingOfType("*messagesproto.ForwardRequest")).
+			Return(nil, status.Error(codes.PermissionDenied, "access denied"))
+
+		req := createRequestWithToken("POST", "/messages/forward", bytes.NewBufferString(`{"message_id":"10","receivers":[{"email":"a@a4code.ru"}]}`))
+		w := httptest.NewRecorder()
+
+		server.forwardHandler(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Result().StatusCode)
+	})
 }
 
-func TestServer_SaveDraftHandler(t *testing.T) {
-	server, _, _, mockMessage := setupTestServer()
+func TestServer_ForwardingHandlers(t *testing.T) {
+	t.Run("GetForwarding", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("GetForwarding", mock.Anything, mock.AnythingOfType("*messagesproto.GetForwardingRequest")).
+			Return(&messagesproto.GetForwardingResponse{Rule: &messagesproto.ForwardingRule{Enabled: true, Addresses: []string{"deputy@a4mail.ru"}}}, nil)
 
-	saveDraftRequest := map[string]interface{}{
-		"draft_id":  "",
-		"thread_id": "thread123",
-		"topic":     "Draft Topic",
-		"text":      "Draft Text",
-		"receivers": []map[string]interface{}{
-			{"email": "test@example.com"},
-		},
-		"files": []map[string]interface{}{},
-	}
+		req := createRequestWithToken("GET", "/messages/forwarding", nil)
+		w := httptest.NewRecorder()
 
-	t.Run("Success", func(t *testing.T) {
-		mockMessage.On("SaveDraft", mock.Anything, mock.AnythingOfType("*messagesproto.SaveDraftRequest")).
-			Return(&messagesproto.SaveDraftResponse{
-				DraftId: "123",
-			}, nil)
+		server.getForwardingHandler(w, req)
 
-		var body bytes.Buffer
-		json.NewEncoder(&body).Encode(saveDraftRequest)
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		assert.Contains(t, w.Body.String(), "deputy@a4mail.ru")
+		mockMessage.AssertExpectations(t)
+	})
+
+	t.Run("PutForwarding", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("PutForwarding", mock.Anything, mock.MatchedBy(func(req *messagesproto.PutForwardingRequest) bool {
+			return req.Rule != nil && req.Rule.Enabled && req.Rule.KeepCopy && len(req.Rule.Addresses) == 1
+		})).Return(&messagesproto.PutForwardingResponse{Rule: &messagesproto.ForwardingRule{Enabled: true}}, nil)
+
+		body := `{"rule":{"enabled":true,"keep_copy":true,"addresses":["deputy@a4mail.ru"]}}`
+		req := createRequestWithToken("PUT", "/messages/forwarding", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+
+		server.putForwardingHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
+
+	t.Run("PutForwardingInvalid", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("PutForwarding", mock.Anything, mock.AnythingOfType("*messagesproto.PutForwardingRequest")).
+			Return(nil, status.Error(codes.InvalidArgument, "invalid forwarding rule"))
+
+		req := createRequestWithToken("PUT", "/messages/forwarding", bytes.NewBufferString(`{"rule":{"enabled":true}}`))
+		w := httptest.NewRecorder()
+
+		server.putForwardingHandler(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+}
+
+func TestServer_Pop3SettingsHandlers(t *testing.T) {
+	t.Run("GetPop3Settings", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("GetPop3Settings", mock.Anything, mock.AnythingOfType("*messagesproto.GetPop3SettingsRequest")).
+			Return(&messagesproto.GetPop3SettingsResponse{Settings: &messagesproto.Pop3Settings{DeleteAction: "hide"}}, nil)
 
-		req := createRequestWithToken("POST", "/messages/save-draft", &body)
+		req := createRequestWithToken("GET", "/messages/pop3", nil)
 		w := httptest.NewRecorder()
 
-		server.saveDraftHandler(w, req)
-
-		resp := w.Result()
-		defer resp.Body.Close()
+		server.getPop3SettingsHandler(w, req)
 
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		assert.Contains(t, w.Body.String(), "hide")
 		mockMessage.AssertExpectations(t)
 	})
-}
 
-func TestServer_DeleteDraftHandler(t *testing.T) {
-	server, _, _, mockMessage := setupTestServer()
+	t.Run("PutPop3Settings", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("PutPop3Settings", mock.Anything, mock.MatchedBy(func(req *messagesproto.PutPop3SettingsRequest) bool {
+			return req.Settings != nil && req.Settings.DeleteAction == "hide" && req.Settings.ApopEnabled && req.Settings.ApopSecret == "tanstaaf"
+		})).Return(&messagesproto.PutPop3SettingsResponse{Settings: &messagesproto.Pop3Settings{DeleteAction: "hide", ApopEnabled: true}}, nil)
 
-	deleteDraftRequest := map[string]interface{}{
-		"draft_id": "123",
-	}
+		body := `{"settings":{"delete_action":"hide","apop_enabled":true,"apop_secret":"tanstaaf"}}`
+		req := createRequestWithToken("PUT", "/messages/pop3", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
 
-	t.Run("Success", func(t *testing.T) {
-		mockMessage.On("DeleteDraft", mock.Anything, mock.AnythingOfType("*messagesproto.DeleteDraftRequest")).
-			Return(&messagesproto.DeleteDraftResponse{
-				Success: true,
-			}, nil)
+		server.putPop3SettingsHandler(w, req)
 
-		var body bytes.Buffer
-		json.NewEncoder(&body).Encode(deleteDraftRequest)
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		assert.NotContains(t, w.Body.String(), "tanstaaf")
+		mockMessage.AssertExpectations(t)
+	})
 
-		req := createRequestWithToken("DELETE", "/messages/delete-draft", &body)
-		w := httptest.NewRecorder()
+	t.Run("PutPop3SettingsInvalid", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("PutPop3Settings", mock.Anything, mock.AnythingOfType("*messagesproto.PutPop3SettingsRequest")).
+			Return(nil, status.Error(codes.InvalidArgument, "invalid pop3 settings"))
 
-		server.deleteDraftHandler(w, req)
+		req := createRequestWithToken("PUT", "/messages/pop3", bytes.NewBufferString(`{"settings":{"delete_action":"purge"}}`))
+		w := httptest.NewRecorder()
 
-		resp := w.Result()
-		defer resp.Body.Close()
+		server.putPop3SettingsHandler(w, req)
 
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
-		mockMessage.AssertExpectations(t)
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
 	})
 }
 
-func TestServer_MarkAsSpamHandler(t *testing.T) {
-	server, _, _, mockMessage := setupTestServer()
+func TestServer_AppPasswordHandlers(t *testing.T) {
+	t.Run("Create", func(t *testing.T) {
+		server, mockAuth, _, _ := setupTestServer()
+		mockAuth.On("CreateAppPassword", mock.Anything, mock.MatchedBy(func(req *authproto.CreateAppPasswordRequest) bool {
+			return req.Name == "Thunderbird"
+		})).Return(&authproto.CreateAppPasswordResponse{
+			AppPassword: &authproto.AppPassword{Id: "3", Name: "Thunderbird"},
+			Password:    "abcd efgh ijkl mnop",
+		}, nil)
+
+		req := createRequestWithToken("POST", "/auth/app-passwords", bytes.NewBufferString(`{"name":"Thunderbird"}`))
+		w := httptest.NewRecorder()
 
-	spamRequest := map[string]interface{}{
-		"message_id": "123",
-	}
+		server.createAppPasswordHandler(w, req)
 
-	t.Run("Success", func(t *testing.T) {
-		mockMessage.On("MarkAsSpam", mock.Anything, mock.AnythingOfType("*messagesproto.MarkAsSpamRequest")).
-			Return(&messagesproto.MarkAsSpamResponse{}, nil)
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		assert.Contains(t, w.Body.String(), "abcd efgh ijkl mnop")
+		mockAuth.AssertExpectations(t)
+	})
 
-		var body bytes.Buffer
-		json.NewEncoder(&body).Encode(spamRequest)
+	t.Run("CreateWithoutToken", func(t *testing.T) {
+		server, _, _, _ := setupTestServer()
 
-		req := createRequestWithToken("POST", "/messages/mark-as-spam", &body)
+		req := httptest.NewRequest("POST", "/auth/app-passwords", bytes.NewBufferString(`{"name":"Thunderbird"}`))
 		w := httptest.NewRecorder()
 
-		server.markAsSpamHandler(w, req)
+		server.createAppPasswordHandler(w, req)
 
-		resp := w.Result()
-		defer resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+	})
 
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
-		mockMessage.AssertExpectations(t)
+	t.Run("List", func(t *testing.T) {
+		server, mockAuth, _, _ := setupTestServer()
+		mockAuth.On("ListAppPasswords", mock.Anything, mock.AnythingOfType("*authproto.ListAppPasswordsRequest")).
+			Return(&authproto.ListAppPasswordsResponse{AppPasswords: []*authproto.AppPassword{
+				{Id: "3", Name: "Thunderbird", LastUsedIp: "203.0.113.7"},
+			}}, nil)
+
+		req := createRequestWithToken("GET", "/auth/app-passwords", nil)
+		w := httptest.NewRecorder()
+
+		server.listAppPasswordsHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		assert.Contains(t, w.Body.String(), "203.0.113.7")
+		mockAuth.AssertExpectations(t)
+	})
+
+	t.Run("RevokeNotFound", func(t *testing.T) {
+		server, mockAuth, _, _ := setupTestServer()
+		mockAuth.On("RevokeAppPassword", mock.Anything, mock.MatchedBy(func(req *authproto.RevokeAppPasswordRequest) bool {
+			return req.Id == "3"
+		})).Return(nil, status.Error(codes.NotFound, "app password not found"))
+
+		req := createRequestWithToken("DELETE", "/auth/app-passwords", bytes.NewBufferString(`{"id":"3"}`))
+		w := httptest.NewRecorder()
+
+		server.revokeAppPasswordHandler(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+		mockAuth.AssertExpectations(t)
 	})
 }
 
-func TestServer_MoveToFolderHandler(t *testing.T) {
-	server, _, _, mockMessage := setupTestServer()
+func TestServer_FolderTreeHandlers(t *testing.T) {
+	t.Run("MoveFolder", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("MoveFolder", mock.Anything, &messagesproto.MoveFolderRequest{FolderId: "6", ParentId: "5"}).
+			Return(&messagesproto.MoveFolderResponse{Folder: &messagesproto.Folder{FolderId: "6", ParentId: "5"}}, nil)
 
-	moveRequest := map[string]interface{}{
-		"message_id": "123",
-		"folder_id":  "2",
-	}
+		req := createRequestWithToken("POST", "/messages/move-folder", bytes.NewBufferString(`{"folder_id":"6","parent_id":"5"}`))
+		w := httptest.NewRecorder()
 
-	t.Run("Success", func(t *testing.T) {
-		mockMessage.On("MoveToFolder", mock.Anything, mock.AnythingOfType("*messagesproto.MoveToFolderRequest")).
-			Return(&messagesproto.MoveToFolderResponse{}, nil)
+		server.moveFolderHandler(w, req)
 
-		var body bytes.Buffer
-		json.NewEncoder(&body).Encode(moveRequest)
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
 
-		req := createRequestWithToken("POST", "/messages/move-to-folder", &body)
+	t.Run("MoveFolderInvalidParent", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("MoveFolder", mock.Anything, mock.AnythingOfType("*messagesproto.MoveFolderRequest")).
+			Return(nil, status.Error(codes.InvalidArgument, "invalid parent folder"))
+
+		req := createRequestWithToken("POST", "/messages/move-folder", bytes.NewBufferString(`{"folder_id":"5","parent_id":"6"}`))
 		w := httptest.NewRecorder()
 
-		server.moveToFolderHandler(w, req)
+		server.moveFolderHandler(w, req)
 
-		resp := w.Result()
-		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
 
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	t.Run("GetFolderTree", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("GetFolderTree", mock.Anything, mock.AnythingOfType("*messagesproto.GetFolderTreeRequest")).
+			Return(&messagesproto.GetFolderTreeResponse{Folders: []*messagesproto.FolderNode{
+				{Folder: &messagesproto.Folder{FolderId: "5", FolderName: "Clients"}},
+			}}, nil)
+
+		req := createRequestWithToken("GET", "/messages/get-folder-tree", nil)
+		w := httptest.NewRecorder()
+
+		server.getFolderTreeHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		assert.Contains(t, w.Body.String(), "Clients")
 		mockMessage.AssertExpectations(t)
 	})
 }
 
-func TestServer_UploadAvatarHandler(t *testing.T) {
-	server, _, mockProfile, _ := setupTestServer()
+func TestServer_LabelHandlers(t *testing.T) {
+	t.Run("CreateLabel", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("CreateLabel", mock.Anything, &messagesproto.CreateLabelRequest{Name: "Work", Color: "#ff0000"}).
+			Return(&messagesproto.CreateLabelResponse{Label: &messagesproto.Label{LabelId: "3", Name: "Work", Color: "#ff0000"}}, nil)
 
-	t.Run("Success", func(t *testing.T) {
-		mockProfile.On("UploadAvatar", mock.Anything, mock.AnythingOfType("*profileproto.UploadAvatarRequest")).
-			Return(&profileproto.UploadAvatarResponse{
-				AvatarPath: "/avatars/new-avatar.jpg",
-			}, nil)
+		req := createRequestWithToken("POST", "/messages/create-label", bytes.NewBufferString(`{"name":"Work","color":"#ff0000"}`))
+		w := httptest.NewRecorder()
 
-		var body bytes.Buffer
-		writer := multipart.NewWriter(&body)
-		part, _ := writer.CreateFormFile("avatar", "test.jpg")
-		part.Write([]byte("fake image data"))
-		writer.Close()
+		server.createLabelHandler(w, req)
 
-		req := createRequestWithToken("POST", "/user/upload/avatar", &body)
-		req.Header.Set("Content-Type", writer.FormDataContentType())
-		w := httptest.NewRecorder()
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
 
-		server.uploadAvatarHandler(w, req)
+	t.Run("CreateLabelExists", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("CreateLabel", mock.Anything, mock.AnythingOfType("*messagesproto.CreateLabelRequest")).
+			Return(nil, status.Error(codes.AlreadyExists, "label already exists"))
 
-		resp := w.Result()
-		defer resp.Body.Close()
+		req := createRequestWithToken("POST", "/messages/create-label", bytes.NewBufferString(`{"name":"Work"}`))
+		w := httptest.NewRecorder()
 
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
-		mockProfile.AssertExpectations(t)
+		server.createLabelHandler(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Result().StatusCode)
 	})
 
-	t.Run("NoFileProvided", func(t *testing.T) {
-		req := createRequestWithToken("POST", "/user/upload/avatar", strings.NewReader(""))
-		req.Header.Set("Content-Type", "multipart/form-data")
-		w := httptest.NewRecorder()
+	t.Run("GetLabels", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("GetLabels", mock.Anything, mock.AnythingOfType("*messagesproto.GetLabelsRequest")).
+			Return(&messagesproto.GetLabelsResponse{}, nil)
 
-		server.uploadAvatarHandler(w, req)
+		req := createRequestWithToken("GET", "/messages/get-labels", nil)
+		w := httptest.NewRecorder()
 
-		resp := w.Result()
-		defer resp.Body.Close()
+		server.getLabelsHandler(w, req)
 
-		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		mockMessage.AssertExpectations(t)
 	})
-}
 
-func TestNormalizeAvatarURL(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    string
-		expected string
-		hasError bool
-	}{
-		{
-			name:     "ValidURL",
-			input:    "http://example.com/avatar.jpg",
-			expected: "http://example.com/avatar.jpg",
-			hasError: false,
-		},
-		{
-			name:     "LocalhostToMinio",
-			input:    "http://localhost:9000/avatar.jpg",
-			expected: "http://minio:9000/avatar.jpg",
-			hasError: false,
-		},
-		{
-			name:     "127.0.0.1ToMinio",
-			input:    "http://127.0.0.1:9000/avatar.jpg",
-			expected: "http://minio:9000/avatar.jpg",
-			hasError: false,
-		},
-		{
-			name:     "InvalidURL",
-			input:    "://invalid",
-			expected: "",
-			hasError: true,
-		},
-	}
+	t.Run("ApplyLabel", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("ApplyLabel", mock.Anything, mock.MatchedBy(func(req *messagesproto.ApplyLabelRequest) bool {
+			return req.LabelId == "3" && len(req.MessageIds) == 2
+		})).Return(&messagesproto.ApplyLabelResponse{AppliedCount: 2}, nil)
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result, err := normalizeAvatarURL(tt.input)
+		req := createRequestWithToken("POST", "/messages/apply-label", bytes.NewBufferString(`{"label_id":"3","message_ids":["1","2"]}`))
+		w := httptest.NewRecorder()
 
-			if tt.hasError {
-				assert.Error(t, err)
-			} else {
-				assert.NoError(t, err)
-				assert.Equal(t, tt.expected, result)
-			}
-		})
-	}
-}
+		server.applyLabelHandler(w, req)
 
-func TestResolveFolderID(t *testing.T) {
-	folders := []*messagesproto.Folder{
-		{FolderId: "1", FolderName: "Inbox", FolderType: "inbox"},
-		{FolderId: "2", FolderName: "Sent", FolderType: "sent"},
-		{FolderId: "3", FolderName: "Custom", FolderType: "custom"},
-	}
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
 
-	tests := []struct {
-		name     string
-		target   string
-		expected string
-	}{
-		{
-			name:     "ByFolderID",
-			target:   "1",
-			expected: "1",
-		},
-		{
-			name:     "ByFolderName",
-			target:   "Inbox",
-			expected: "1",
-		},
-		{
-			name:     "ByFolderType",
-			target:   "inbox",
-			expected: "1",
-		},
-		{
-			name:     "NotFound",
-			target:   "nonexistent",
-			expected: "",
-		},
-		{
-			name:     "EmptyTarget",
-			target:   "",
-			expected: "",
-		},
-	}
+	t.Run("RemoveLabelNotFound", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("RemoveLabel", mock.Anything, mock.AnythingOfType("*messagesproto.RemoveLabelRequest")).
+			Return(nil, status.Error(codes.NotFound, "label not found"))
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := resolveFolderID(folders, tt.target)
-			assert.Equal(t, tt.expected, result)
-		})
-	}
+		req := createRequestWithToken("POST", "/messages/remove-label", bytes.NewBufferString(`{"label_id":"3","message_ids":["1"]}`))
+		w := httptest.NewRecorder()
+
+		server.removeLabelHandler(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
 }
 
-func TestMapProfile(t *testing.T) {
-	profile := &profileproto.Profile{
-		Username:   "testuser",
-		CreatedAt:  "2023-01-01",
-		Name:       "Test",
-		Surname:    "User",
-		Patronymic: "Middle",
-		Gender:     "male",
-		Birthday:   "1990-01-01",
-		AvatarPath: "/avatars/test.jpg",
-	}
+func TestServer_ContactHandlers(t *testing.T) {
+	t.Run("CreateContact", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("CreateContact", mock.Anything, mock.MatchedBy(func(req *messagesproto.CreateContactRequest) bool {
+			return req.Contact != nil && req.Contact.Name == "Bob" && len(req.Contact.Emails) == 1
+		})).Return(&messagesproto.CreateContactResponse{Contact: &messagesproto.Contact{ContactId: "10", Name: "Bob"}}, nil)
 
-	result := mapProfile(profile)
+		req := createRequestWithToken("POST", "/messages/contacts", bytes.NewBufferString(`{"contact":{"name":"Bob","emails":["bob@a4mail.ru"]}}`))
+		w := httptest.NewRecorder()
 
-	assert.Equal(t, "testuser", result.Username)
-	assert.Equal(t, "Test", result.Name)
-	assert.Equal(t, "User", result.Surname)
-	assert.Equal(t, "Middle", result.Patronymic)
-	assert.Equal(t, "male", result.Gender)
-	assert.Equal(t, "1990-01-01", result.DateOfBirth)
-	assert.Equal(t, "/avatars/test.jpg", result.AvatarPath)
-	assert.Equal(t, "user", result.Role)
-}
+		server.createContactHandler(w, req)
 
-func TestMapProfile_Nil(t *testing.T) {
-	result := mapProfile(nil)
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
 
-	assert.Equal(t, "", result.Username)
-	assert.Equal(t, "", result.Name)
-	assert.Equal(t, "", result.Surname)
-}
+	t.Run("CreateContactExists", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("CreateContact", mock.Anything, mock.AnythingOfType("*messagesproto.CreateContactRequest")).
+			Return(nil, status.Error(codes.AlreadyExists, "contact with this email already exists"))
 
-func TestServer_RefreshHandler(t *testing.T) {
-	server, mockAuth, _, _ := setupTestServer()
+		req := createRequestWithToken("POST", "/messages/contacts", bytes.NewBufferString(`{"contact":{"emails":["bob@a4mail.ru"]}}`))
+		w := httptest.NewRecorder()
 
-	t.Run("Success", func(t *testing.T) {
-		mockAuth.On("Refresh", mock.Anything, mock.AnythingOfType("*authproto.RefreshRequest")).
-			Return(&authproto.RefreshResponse{
-				AccessToken: "new-access-token",
-			}, nil)
+		server.createContactHandler(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Result().StatusCode)
+	})
 
-		req := httptest.NewRequest("POST", "/auth/refresh", nil)
-		req.AddCookie(&http.Cookie{
-			Name:  "refresh_token",
-			Value: "old-refresh-token",
-		})
+	t.Run("GetContactsByGroup", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("GetContacts", mock.Anything, &messagesproto.GetContactsRequest{GroupId: "3"}).
+			Return(&messagesproto.GetContactsResponse{}, nil)
+
+		req := createRequestWithToken("GET", "/messages/contacts?group_id=3", nil)
 		w := httptest.NewRecorder()
 
-		server.refreshHandler(w, req)
+		server.getContactsHandler(w, req)
 
-		resp := w.Result()
-		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
 
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
-		mockAuth.AssertExpectations(t)
+	t.Run("SuggestContacts", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("SuggestContacts", mock.Anything, &messagesproto.SuggestContactsRequest{Prefix: "bo", Limit: 5}).
+			Return(&messagesproto.SuggestContactsResponse{}, nil)
+
+		req := createRequestWithToken("GET", "/messages/contacts/suggest?prefix=bo&limit=5", nil)
+		w := httptest.NewRecorder()
+
+		server.suggestContactsHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		mockMessage.AssertExpectations(t)
 	})
 
-	t.Run("NoRefreshToken", func(t *testing.T) {
-		req := httptest.NewRequest("POST", "/auth/refresh", nil)
+	t.Run("SuggestContactsInvalidLimit", func(t *testing.T) {
+		server, _, _, _ := setupTestServer()
+
+		req := createRequestWithToken("GET", "/messages/contacts/suggest?prefix=bo&limit=abc", nil)
 		w := httptest.NewRecorder()
 
-		server.refreshHandler(w, req)
+		server.suggestContactsHandler(w, req)
 
-		resp := w.Result()
-		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
 
-		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	t.Run("DeleteContactGroupNotFound", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("DeleteContactGroup", mock.Anything, mock.AnythingOfType("*messagesproto.DeleteContactGroupRequest")).
+			Return(nil, status.Error(codes.NotFound, "contact group not found"))
+
+		req := createRequestWithToken("DELETE", "/messages/contact-groups", bytes.NewBufferString(`{"group_id":"3"}`))
+		w := httptest.NewRecorder()
+
+		server.deleteContactGroupHandler(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
 	})
 }
 
-func TestServer_LogoutHandler(t *testing.T) {
-	server, mockAuth, _, _ := setupTestServer()
+func TestServer_SenderListHandlers(t *testing.T) {
+	t.Run("GetSenderLists", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("GetSenderLists", mock.Anything, mock.AnythingOfType("*messagesproto.GetSenderListsRequest")).
+			Return(&messagesproto.GetSenderListsResponse{BlockedAction: "spam"}, nil)
 
-	t.Run("Success", func(t *testing.T) {
-		mockAuth.On("Logout", mock.Anything, mock.AnythingOfType("*authproto.LogoutRequest")).
-			Return(&authproto.LogoutResponse{}, nil)
+		req := createRequestWithToken("GET", "/messages/sender-lists", nil)
+		w := httptest.NewRecorder()
 
-		req := httptest.NewRequest("POST", "/auth/logout", nil)
+		server.getSenderListsHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
+
+	t.Run("AddSenderRuleInvalid", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("AddSenderRule", mock.Anything, &messagesproto.AddSenderRuleRequest{Kind: "block", Pattern: "localhost"}).
+			Return(nil, status.Error(codes.InvalidArgument, "invalid sender rule"))
+
+		req := createRequestWithToken("POST", "/messages/sender-lists", bytes.NewBufferString(`{"kind":"block","pattern":"localhost"}`))
 		w := httptest.NewRecorder()
 
-		server.logoutHandler(w, req)
+		server.addSenderRuleHandler(w, req)
 
-		resp := w.Result()
-		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
 
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	t.Run("BlockSender", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("BlockSender", mock.Anything, &messagesproto.BlockSenderRequest{MessageId: "5", WholeDomain: true}).
+			Return(&messagesproto.BlockSenderResponse{Rule: &messagesproto.SenderRule{RuleId: "2", Kind: "block", Pattern: "spam.com"}}, nil)
 
-		cookies := resp.Cookies()
-		var accessCookie, refreshCookie *http.Cookie
-		for _, cookie := range cookies {
-			if cookie.Name == "access_token" {
-				accessCookie = cookie
-			} else if cookie.Name == "refresh_token" {
-				refreshCookie = cookie
-			}
-		}
-		assert.NotNil(t, accessCookie)
-		assert.NotNil(t, refreshCookie)
-		assert.Equal(t, -1, accessCookie.MaxAge)
-		assert.Equal(t, -1, refreshCookie.MaxAge)
+		req := createRequestWithToken("POST", "/messages/block-sender", bytes.NewBufferString(`{"message_id":"5","whole_domain":true}`))
+		w := httptest.NewRecorder()
 
-		mockAuth.AssertExpectations(t)
+		server.blockSenderHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		mockMessage.AssertExpectations(t)
 	})
-}
 
-func TestWriteGrpcAwareError(t *testing.T) {
-	tests := []struct {
-		name           string
-		err            error
-		defaultMessage string
-		expectedStatus int
-	}{
-		{
-			name:           "Unauthenticated",
-			err:            status.Error(codes.Unauthenticated, "invalid token"),
-			defaultMessage: "Authentication failed",
-			expectedStatus: http.StatusUnauthorized,
-		},
-		{
-			name:           "InvalidArgument",
-			err:            status.Error(codes.InvalidArgument, "invalid input"),
-			defaultMessage: "Invalid request",
-			expectedStatus: http.StatusBadRequest,
-		},
-		{
-			name:           "NotFound",
-			err:            status.Error(codes.NotFound, "not found"),
-			defaultMessage: "Resource not found",
-			expectedStatus: http.StatusNotFound,
-		},
-		{
-			name:           "PermissionDenied",
-			err:            status.Error(codes.PermissionDenied, "access denied"),
-			defaultMessage: "Access denied",
-			expectedStatus: http.StatusForbidden,
-		},
-		{
-			name:           "AlreadyExists",
-			err:            status.Error(codes.AlreadyExists, "already exists"),
-			defaultMessage: "Conflict",
-			expectedStatus: http.StatusConflict,
-		},
-		{
-			name:           "GenericError",
-			err:            errors.New("generic error"),
-			defaultMessage: "Internal error",
-			expectedStatus: http.StatusInternalServerError,
-		},
-	}
+	t.Run("BlockSenderInvalidBody", func(t *testing.T) {
+		server, _, _, _ := setupTestServer()
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			w := httptest.NewRecorder()
-			writeGrpcAwareError(w, tt.err, tt.defaultMessage)
+		req := createRequestWithToken("POST", "/messages/block-sender", bytes.NewBufferString(`{`))
+		w := httptest.NewRecorder()
 
-			resp := w.Result()
-			defer resp.Body.Close()
+		server.blockSenderHandler(w, req)
 
-			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
-		})
-	}
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
 }
 
-func TestServer_ReplyHandler(t *testing.T) {
-	server, _, _, mockMessage := setupTestServer()
+func TestServer_AliasHandlers(t *testing.T) {
+	t.Run("GetAliases", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("GetAliases", mock.Anything, mock.AnythingOfType("*messagesproto.GetAliasesRequest")).
+			Return(&messagesproto.GetAliasesResponse{Aliases: []*messagesproto.Alias{{AliasId: "3", Address: "sales@flintmail.ru"}}}, nil)
 
-	replyRequest := map[string]interface{}{
-		"root_message_id": "123",
-		"topic":           "Re: Test",
-		"text":            "Reply text",
-		"thread_root":     "thread123",
-		"receivers": []map[string]interface{}{
-			{"email": "reply@example.com"},
-		},
-		"files": []map[string]interface{}{},
-	}
+		req := createRequestWithToken("GET", "/messages/aliases", nil)
+		w := httptest.NewRecorder()
 
-	t.Run("Success", func(t *testing.T) {
-		mockMessage.On("Reply", mock.Anything, mock.AnythingOfType("*messagesproto.ReplyRequest")).
-			Return(&messagesproto.ReplyResponse{
-				MessageId: "456",
-			}, nil)
+		server.getAliasesHandler(w, req)
 
-		var body bytes.Buffer
-		json.NewEncoder(&body).Encode(replyRequest)
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
 
-		req := createRequestWithToken("POST", "/messages/reply", &body)
-		w := httptest.NewRecorder()
+	t.Run("AddAliasTaken", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("AddAlias", mock.Anything, &messagesproto.AddAliasRequest{Username: "admin"}).
+			Return(nil, status.Error(codes.AlreadyExists, "alias is already taken"))
 
-		server.replyHandler(w, req)
+		req := createRequestWithToken("POST", "/messages/aliases", bytes.NewBufferString(`{"username":"admin"}`))
+		w := httptest.NewRecorder()
 
-		resp := w.Result()
-		defer resp.Body.Close()
+		server.addAliasHandler(w, req)
 
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, http.StatusConflict, w.Result().StatusCode)
 		mockMessage.AssertExpectations(t)
 	})
 
-	t.Run("InvalidRequestBody", func(t *testing.T) {
-		req := createRequestWithToken("POST", "/messages/reply", strings.NewReader("invalid json"))
-		w := httptest.NewRecorder()
+	t.Run("DeleteAliasNotFound", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("DeleteAlias", mock.Anything, &messagesproto.DeleteAliasRequest{AliasId: "3"}).
+			Return(nil, status.Error(codes.NotFound, "alias not found"))
 
-		server.replyHandler(w, req)
+		req := createRequestWithToken("DELETE", "/messages/aliases", bytes.NewBufferString(`{"alias_id":"3"}`))
+		w := httptest.NewRecorder()
 
-		resp := w.Result()
-		defer resp.Body.Close()
+		server.deleteAliasHandler(w, req)
 
-		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+		mockMessage.AssertExpectations(t)
 	})
 }
 
-func TestServer_SettingsHandler(t *testing.T) {
-	server, _, mockProfile, _ := setupTestServer()
-
+func TestServer_SetVacationHandler(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
-		mockProfile.On("Settings", mock.Anything, mock.AnythingOfType("*profileproto.SettingsRequest")).
-			Return(&profileproto.SettingsResponse{
-				Settings: &profileproto.Settings{
-					Theme: "dark",
-				},
-			}, nil)
-
-		req := createRequestWithToken("GET", "/user/settings", nil)
+		server, _, mockProfile, _ := setupTestServer()
+		mockProfile.On("SetVacation", mock.Anything, mock.MatchedBy(func(req *profileproto.SetVacationRequest) bool {
+			return req.Vacation != nil && req.Vacation.Enabled && req.Vacation.End == "2025-08-01T00:00:00Z" && req.Vacation.ContactsOnly
+		})).Return(&profileproto.SetVacationResponse{Settings: &profileproto.Settings{
+			Vacation: &profileproto.Vacation{Enabled: true, Body: "Away"},
+		}}, nil)
+
+		body := `{"vacation":{"enabled":true,"end":"2025-08-01T00:00:00Z","body":"Away","contacts_only":true}}`
+		req := createRequestWithToken("PUT", "/user/vacation", bytes.NewBufferString(body))
 		w := httptest.NewRecorder()
 
-		server.settingsHandler(w, req)
-
-		resp := w.Result()
-		defer resp.Body.Close()
+		server.setVacationHandler(w, req)
 
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		assert.Contains(t, w.Body.String(), "Away")
 		mockProfile.AssertExpectations(t)
 	})
-}
 
-func TestServer_SendDraftHandler(t *testing.T) {
-	server, _, _, mockMessage := setupTestServer()
+	t.Run("Invalid", func(t *testing.T) {
+		server, _, mockProfile, _ := setupTestServer()
+		mockProfile.On("SetVacation", mock.Anything, mock.AnythingOfType("*profileproto.SetVacationRequest")).
+			Return(nil, status.Error(codes.InvalidArgument, "invalid vacation settings"))
 
-	sendDraftRequest := map[string]interface{}{
-		"draft_id": "123",
-	}
+		req := createRequestWithToken("PUT", "/user/vacation", bytes.NewBufferString(`{"vacation":{"enabled":true}}`))
+		w := httptest.NewRecorder()
 
-	t.Run("Success", func(t *testing.T) {
-		mockMessage.On("SendDraft", mock.Anything, mock.AnythingOfType("*messagesproto.SendDraftRequest")).
-			Return(&messagesproto.SendDraftResponse{
-				Success:   true,
-				MessageId: "456",
-			}, nil)
+		server.setVacationHandler(w, req)
 
-		var body bytes.Buffer
-		json.NewEncoder(&body).Encode(sendDraftRequest)
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
 
-		req := createRequestWithToken("POST", "/messages/send-draft", &body)
+	t.Run("Unauthorized", func(t *testing.T) {
+		server, _, _, _ := setupTestServer()
+
+		req := httptest.NewRequest("PUT", "/user/vacation", bytes.NewBufferString(`{}`))
 		w := httptest.NewRecorder()
 
-		server.sendDraftHandler(w, req)
+		server.setVacationHandler(w, req)
 
-		resp := w.Result()
-		defer resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
+	})
+}
 
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+func TestServer_EventsHandler(t *testing.T) {
+	t.Run("StreamsEvents", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		stream := &fakeEventStream{
+			header: metadata.MD{},
+			events: []*messagesproto.MailEvent{
+				{EventId: "11", Type: "new_message", MessageId: "5", Topic: "Hi"},
+				{EventId: "12", Type: "unread_count", Unread: "3"},
+			},
+			err: io.EOF,
+		}
+		mockMessage.On("Subscribe", mock.Anything, &messagesproto.SubscribeRequest{LastEventId: "10"}).Return(stream, nil)
+
+		req := createRequestWithToken("GET", "/messages/events", nil)
+		req.Header.Set("Last-Event-ID", "10")
+		w := httptest.NewRecorder()
+
+		server.eventsHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+		body := w.Body.String()
+		assert.Contains(t, body, "id: 11\nevent: new_message\ndata: {")
+		assert.Contains(t, body, `"topic":"Hi"`)
+		assert.Contains(t, body, "id: 12\nevent: unread_count\n")
+		assert.True(t, w.Flushed)
 		mockMessage.AssertExpectations(t)
 	})
-}
 
-func TestServer_DeleteFolderHandler(t *testing.T) {
-	server, _, _, mockMessage := setupTestServer()
+	t.Run("LastEventIDFromQuery", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("Subscribe", mock.Anything, &messagesproto.SubscribeRequest{LastEventId: "42"}).
+			Return(&fakeEventStream{header: metadata.MD{}, err: io.EOF}, nil)
 
-	t.Run("Success", func(t *testing.T) {
-		mockMessage.On("DeleteFolder", mock.Anything, mock.AnythingOfType("*messagesproto.DeleteFolderRequest")).
-			Return(&messagesproto.DeleteFolderResponse{}, nil)
+		req := createRequestWithToken("GET", "/messages/events?last_event_id=42", nil)
+		w := httptest.NewRecorder()
 
-		req := createRequestWithToken("DELETE", "/messages/delete-folder?folder_id=1", nil)
+		server.eventsHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
+
+	t.Run("SubscribeRejected", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("Subscribe", mock.Anything, mock.AnythingOfType("*messagesproto.SubscribeRequest")).
+			Return(&fakeEventStream{err: status.Error(codes.InvalidArgument, "invalid last event id")}, nil)
+
+		req := createRequestWithToken("GET", "/messages/events?last_event_id=abc", nil)
 		w := httptest.NewRecorder()
 
-		server.deleteFolderHandler(w, req)
+		server.eventsHandler(w, req)
 
-		resp := w.Result()
-		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+		assert.NotEqual(t, "text/event-stream", w.Header().Get("Content-Type"))
+	})
 
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
-		mockMessage.AssertExpectations(t)
+	t.Run("Unauthorized", func(t *testing.T) {
+		server, _, _, _ := setupTestServer()
+
+		req := httptest.NewRequest("GET", "/messages/events", nil)
+		w := httptest.NewRecorder()
+
+		server.eventsHandler(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Result().StatusCode)
 	})
 }
 
-func TestServer_DeleteMessageFromFolderHandler(t *testing.T) {
-	server, _, _, mockMessage := setupTestServer()
+func TestServer_InboxHandlerListOptions(t *testing.T) {
+	folders := &messagesproto.GetFoldersResponse{Folders: []*messagesproto.Folder{{FolderId: "1", FolderName: "Входящие", FolderType: "inbox"}}}
 
-	deleteRequest := map[string]interface{}{
-		"message_id": "123",
-		"folder_id":  "1",
-	}
+	t.Run("PassesCursor", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("GetFolders", mock.Anything, mock.AnythingOfType("*messagesproto.GetFoldersRequest")).Return(folders, nil)
+		mockMessage.On("GetFolder", mock.Anything, mock.MatchedBy(func(req *messagesproto.GetFolderRequest) bool {
+			return req.FolderId == "1" && req.Cursor == "1.abc.def"
+		})).Return(&messagesproto.GetFolderResponse{Pagination: &messagesproto.PaginationInfo{HasNext: "false", PrevCursor: "1.prev.sig"}}, nil)
 
-	t.Run("Success", func(t *testing.T) {
-		mockMessage.On("DeleteMessageFromFolder", mock.Anything, mock.AnythingOfType("*messagesproto.DeleteMessageFromFolderRequest")).
-			Return(&messagesproto.DeleteMessageFromFolderResponse{}, nil)
+		req := createRequestWithToken("GET", "/messages/inbox?cursor=1.abc.def", nil)
+		w := httptest.NewRecorder()
 
-		var body bytes.Buffer
-		json.NewEncoder(&body).Encode(deleteRequest)
+		server.inboxHandler(w, req)
 
-		req := createRequestWithToken("DELETE", "/messages/delete-message-from-folder", &body)
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		assert.Contains(t, w.Body.String(), "1.prev.sig")
+		mockMessage.AssertExpectations(t)
+	})
+
+	t.Run("PassesSortAndFilters", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("GetFolders", mock.Anything, mock.AnythingOfType("*messagesproto.GetFoldersRequest")).Return(folders, nil)
+		mockMessage.On("GetFolder", mock.Anything, mock.MatchedBy(func(req *messagesproto.GetFolderRequest) bool {
+			return req.Sort == "sender" && req.UnreadOnly == "true" && req.HasAttachments == "true" &&
+				req.Sender == "boss@a4mail.ru" && req.After == "2025-10-01T00:00:00Z"
+		})).Return(&messagesproto.GetFolderResponse{Pagination: &messagesproto.PaginationInfo{HasNext: "false"}}, nil)
+
+		req := createRequestWithToken("GET", "/messages/inbox?sort=sender&unread_only=true&has_attachments=true&sender=boss@a4mail.ru&after=2025-10-01T00:00:00Z", nil)
 		w := httptest.NewRecorder()
 
-		server.deleteMessageFromFolderHandler(w, req)
+		server.inboxHandler(w, req)
 
-		resp := w.Result()
-		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+		mockMessage.AssertExpectations(t)
+	})
 
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	t.Run("TamperedCursor", func(t *testing.T) {
+		server, _, _, mockMessage := setupTestServer()
+		mockMessage.On("GetFolders", mock.Anything, mock.AnythingOfType("*messagesproto.GetFoldersRequest")).Return(folders, nil)
+		mockMessage.On("GetFolder", mock.Anything, mock.AnythingOfType("*messagesproto.GetFolderRequest")).
+			Return(nil, status.Error(codes.InvalidArgument, "invalid cursor"))
+
+		req := createRequestWithToken("GET", "/messages/inbox?cursor=1.abc.forged", nil)
+		w := httptest.NewRecorder()
+
+		server.inboxHandler(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
 		mockMessage.AssertExpectations(t)
 	})
 }
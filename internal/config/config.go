@@ -3,15 +3,22 @@ package config
 import (
 	e "2025_2_a4code/internal/lib/wrapper"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	AppConfig   *AppConfig
-	DBConfig    *DBConfig
-	MinioConfig *MinioConfig
+	AppConfig        *AppConfig
+	DBConfig         *DBConfig
+	MinioConfig      *MinioConfig
+	SMTPConfig       *SMTPConfig
+	IMAPConfig       *IMAPConfig
+	POP3Config       *POP3Config
+	SubmissionConfig *SubmissionConfig
+	OutboundConfig   *OutboundConfig
+	DKIMConfig       *DKIMConfig
 }
 
 type AppConfig struct {
@@ -25,6 +32,10 @@ type AppConfig struct {
 	MessagesMetricsPort string `yaml:"messages_metrics_port"`
 	ProfileMetricsPort  string `yaml:"profile_metrics_port"`
 	Secret              string `yaml:"secret"`
+	// Оценка спам-фильтра, начиная с которой письмо попадает в спам
+	SpamThreshold float64 `yaml:"spam_threshold"`
+	// Адрес сервиса авторизации для серверов IMAP, POP3 и отправки почты, пустой - Host
+	AuthHost string `yaml:"auth_host"`
 }
 
 type DBConfig struct {
@@ -48,6 +59,109 @@ type MinioConfig struct {
 	PublicUseSSL   bool   `yaml:"public_use_ssl"`
 }
 
+// SMTPConfig - настройки приема внешней почты по SMTP
+type SMTPConfig struct {
+	Host string `yaml:"host"`
+	// Пустой порт отключает прием почты
+	Port string `yaml:"port"`
+	// Имя сервера в приветствии и ответе на EHLO
+	Hostname string `yaml:"hostname"`
+	// Домен, для которого принимается почта
+	Domain  string `yaml:"domain"`
+	TLSCert string `yaml:"tls_cert"`
+	TLSKey  string `yaml:"tls_key"`
+	// Максимальный размер письма в байтах
+	MaxMessageSize int64 `yaml:"max_message_size"`
+	MaxRecipients  int   `yaml:"max_recipients"`
+	// Проверка отправителя входящих писем по SPF и DMARC
+	CheckSPF   bool `yaml:"check_spf"`
+	CheckDMARC bool `yaml:"check_dmarc"`
+}
+
+// IMAPConfig - настройки доступа к почте из почтовых клиентов по IMAP
+type IMAPConfig struct {
+	Host string `yaml:"host"`
+	// Пустой порт отключает IMAP
+	Port string `yaml:"port"`
+	// Порт соединений, зашифрованных с самого начала (IMAPS), работает только с сертификатом
+	TLSPort  string `yaml:"tls_port"`
+	Hostname string `yaml:"hostname"`
+	TLSCert  string `yaml:"tls_cert"`
+	TLSKey   string `yaml:"tls_key"`
+	// Вход без TLS, пароль передается открытым текстом. Только для локального запуска
+	AllowInsecureAuth bool `yaml:"allow_insecure_auth"`
+	// Вход по паролю от аккаунта, а не только по паролям приложений
+	AllowAccountPassword bool `yaml:"allow_account_password"`
+}
+
+// POP3Config - настройки доступа к папке "Входящие" из старых почтовых клиентов по POP3
+type POP3Config struct {
+	Host string `yaml:"host"`
+	// Пустой порт отключает POP3
+	Port string `yaml:"port"`
+	// Порт соединений, зашифрованных с самого начала (POP3S), работает только с сертификатом
+	TLSPort  string `yaml:"tls_port"`
+	Hostname string `yaml:"hostname"`
+	TLSCert  string `yaml:"tls_cert"`
+	TLSKey   string `yaml:"tls_key"`
+	// Вход по USER/PASS без TLS, пароль передается открытым текстом. Только для локального запуска
+	AllowInsecureAuth bool `yaml:"allow_insecure_auth"`
+	// Вход по паролю от аккаунта, а не только по паролям приложений
+	AllowAccountPassword bool `yaml:"allow_account_password"`
+}
+
+// SubmissionConfig - настройки отправки почты из почтовых клиентов по SMTP AUTH (RFC 6409)
+type SubmissionConfig struct {
+	Host string `yaml:"host"`
+	// Пустой порт отключает сервер отправки
+	Port string `yaml:"port"`
+	// Порт соединений, зашифрованных с самого начала (RFC 8314), работает только с сертификатом
+	TLSPort  string `yaml:"tls_port"`
+	Hostname string `yaml:"hostname"`
+	TLSCert  string `yaml:"tls_cert"`
+	TLSKey   string `yaml:"tls_key"`
+	// Вход без TLS, пароль передается открытым текстом. Только для локального запуска
+	AllowInsecureAuth bool `yaml:"allow_insecure_auth"`
+	// Вход по паролю от аккаунта, а не только по паролям приложений
+	AllowAccountPassword bool `yaml:"allow_account_password"`
+	// Максимальный размер письма в байтах
+	MaxMessageSize int64 `yaml:"max_message_size"`
+	MaxRecipients  int   `yaml:"max_recipients"`
+}
+
+// OutboundConfig - настройки отправки почты на внешние домены.
+// Адрес отправителя и имя сервера берутся из SMTPConfig
+type OutboundConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Smarthost - "host:port" сервера для всей исходящей почты, пустой - доставка напрямую на MX
+	Smarthost     string `yaml:"smarthost"`
+	Username      string `yaml:"username"`
+	Password      string `yaml:"password"`
+	TLSSkipVerify bool   `yaml:"tls_skip_verify"`
+	// Интервалы в формате time.ParseDuration, нулевые значения заменяются значениями по умолчанию
+	PollInterval time.Duration `yaml:"poll_interval"`
+	RetryBase    time.Duration `yaml:"retry_base"`
+	RetryMax     time.Duration `yaml:"retry_max"`
+	MaxAge       time.Duration `yaml:"max_age"`
+	DelayWarning time.Duration `yaml:"delay_warning"`
+}
+
+// DKIMConfig - ключи подписи исходящей почты и проверка подписей входящей
+type DKIMConfig struct {
+	Verify bool            `yaml:"verify"`
+	Keys   []DKIMKeyConfig `yaml:"keys"`
+}
+
+// DKIMKeyConfig - ключ подписи домена. Для смены ключа новый селектор добавляется
+// с active_from в будущем, старый остается в списке, пока не истечет его DNS-запись
+type DKIMKeyConfig struct {
+	Domain   string `yaml:"domain"`
+	Selector string `yaml:"selector"`
+	// Путь к закрытому ключу RSA или Ed25519 в формате PEM
+	PrivateKey string    `yaml:"private_key"`
+	ActiveFrom time.Time `yaml:"active_from"`
+}
+
 func GetConfig() (Config, error) {
 	err := godotenv.Load(".env")
 	if err != nil {
@@ -65,9 +179,15 @@ func GetConfig() (Config, error) {
 	}
 
 	var yamlStruct struct {
-		App   AppConfig   `yaml:"app"`
-		DB    DBConfig    `yaml:"db"`
-		Minio MinioConfig `yaml:"minio"`
+		App        AppConfig        `yaml:"app"`
+		DB         DBConfig         `yaml:"db"`
+		Minio      MinioConfig      `yaml:"minio"`
+		SMTP       SMTPConfig       `yaml:"smtp"`
+		IMAP       IMAPConfig       `yaml:"imap"`
+		POP3       POP3Config       `yaml:"pop3"`
+		Submission SubmissionConfig `yaml:"submission"`
+		Outbound   OutboundConfig   `yaml:"outbound"`
+		DKIM       DKIMConfig       `yaml:"dkim"`
 	}
 
 	if err := yaml.Unmarshal(data, &yamlStruct); err != nil {
@@ -75,8 +195,14 @@ func GetConfig() (Config, error) {
 	}
 
 	return Config{
-		AppConfig:   &yamlStruct.App,
-		DBConfig:    &yamlStruct.DB,
-		MinioConfig: &yamlStruct.Minio,
+		AppConfig:        &yamlStruct.App,
+		DBConfig:         &yamlStruct.DB,
+		MinioConfig:      &yamlStruct.Minio,
+		SMTPConfig:       &yamlStruct.SMTP,
+		IMAPConfig:       &yamlStruct.IMAP,
+		POP3Config:       &yamlStruct.POP3,
+		SubmissionConfig: &yamlStruct.Submission,
+		OutboundConfig:   &yamlStruct.Outbound,
+		DKIMConfig:       &yamlStruct.DKIM,
 	}, nil
 }
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// MaxAliases - максимальное число дополнительных адресов у одного профиля
+const MaxAliases = 10
+
+// Alias - дополнительный адрес профиля на домене его основного адреса. Почта на него
+// доставляется во входящие профиля, а почтовые клиенты могут указывать его в поле From
+type Alias struct {
+	ID        int64     `json:"id"`
+	Address   string    `json:"address"`
+	CreatedAt time.Time `json:"created_at"`
+}
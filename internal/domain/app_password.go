@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// MaxAppPasswords - максимальное число паролей приложений у одного профиля
+	MaxAppPasswords = 20
+	// MaxAppPasswordNameLength - максимальная длина названия пароля приложения
+	MaxAppPasswordNameLength = 100
+)
+
+var ErrAppPasswordNotFound = errors.New("app password not found")
+var ErrInvalidAppPassword = errors.New("invalid app password")
+var ErrTooManyAppPasswords = errors.New("too many app passwords")
+
+// AppPassword - пароль приложения для почтовых клиентов (IMAP, POP3, SMTP AUTH).
+// Сам пароль не хранится, его показывают один раз при создании
+type AppPassword struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	// Нулевое время и пустой адрес - паролем еще не пользовались
+	LastUsedAt time.Time `json:"last_used_at"`
+	LastUsedIP string    `json:"last_used_ip"`
+}
+
+// AppPasswordHash - хеш пароля приложения для проверки при входе
+type AppPasswordHash struct {
+	ID           int64
+	ProfileID    int64
+	PasswordHash string
+}
@@ -0,0 +1,85 @@
+package domain
+
+// DKIMStatus - результат проверки DKIM-подписи в терминах Authentication-Results (RFC 8601)
+type DKIMStatus string
+
+const (
+	// DKIMNone - письмо не подписано
+	DKIMNone DKIMStatus = "none"
+	DKIMPass DKIMStatus = "pass"
+	// DKIMFail - подпись не сошлась или истекла
+	DKIMFail DKIMStatus = "fail"
+	// DKIMTempError - ключ не удалось получить из-за временной ошибки DNS
+	DKIMTempError DKIMStatus = "temperror"
+	// DKIMPermError - подпись или ключ некорректны
+	DKIMPermError DKIMStatus = "permerror"
+)
+
+// DKIMResult - итог проверки подписей входящего письма. Пустой Status - письмо
+// отправлено внутри сервиса и не проверялось
+type DKIMResult struct {
+	Status DKIMStatus `json:"status"`
+	// Domain - домен из тега d= подписи, по которой получен результат
+	Domain string `json:"domain"`
+}
+
+// SPFStatus - результат проверки SPF (RFC 7208, 2.6)
+type SPFStatus string
+
+const (
+	// SPFNone - домен не публикует SPF-запись
+	SPFNone SPFStatus = "none"
+	// SPFNeutral - домен не утверждает ничего об адресе отправителя
+	SPFNeutral SPFStatus = "neutral"
+	SPFPass    SPFStatus = "pass"
+	// SPFFail - домен запрещает отправку с этого адреса
+	SPFFail SPFStatus = "fail"
+	// SPFSoftFail - адрес скорее всего не разрешен, но домен не требует отклонять письма
+	SPFSoftFail  SPFStatus = "softfail"
+	SPFTempError SPFStatus = "temperror"
+	SPFPermError SPFStatus = "permerror"
+)
+
+// SPFResult - итог проверки SPF входящего письма
+type SPFResult struct {
+	Status SPFStatus `json:"status"`
+	// Domain - проверенный домен: из MAIL FROM или, для пустого MAIL FROM, из HELO
+	Domain string `json:"domain"`
+}
+
+// DMARCStatus - результат проверки DMARC (RFC 7489)
+type DMARCStatus string
+
+const (
+	// DMARCNone - домен из поля From не публикует DMARC-запись
+	DMARCNone DMARCStatus = "none"
+	DMARCPass DMARCStatus = "pass"
+	// DMARCFail - ни DKIM, ни SPF не подтвердили домен из поля From
+	DMARCFail      DMARCStatus = "fail"
+	DMARCTempError DMARCStatus = "temperror"
+	DMARCPermError DMARCStatus = "permerror"
+)
+
+// DMARCPolicy - как домен просит поступать с письмами, не прошедшими проверку
+type DMARCPolicy string
+
+const (
+	DMARCPolicyNone       DMARCPolicy = "none"
+	DMARCPolicyQuarantine DMARCPolicy = "quarantine"
+	DMARCPolicyReject     DMARCPolicy = "reject"
+)
+
+// DMARCResult - итог проверки DMARC входящего письма
+type DMARCResult struct {
+	Status DMARCStatus `json:"status"`
+	// Policy - политика домена для этого письма, пустая - записи нет
+	Policy DMARCPolicy `json:"policy"`
+	// Aligned - домен из поля From подтвержден подписью DKIM или SPF, даже если
+	// домен не публикует DMARC-запись
+	Aligned bool `json:"aligned"`
+}
+
+// Quarantined сообщает, что домен просит не доставлять письмо во входящие
+func (r DMARCResult) Quarantined() bool {
+	return r.Status == DMARCFail && (r.Policy == DMARCPolicyQuarantine || r.Policy == DMARCPolicyReject)
+}
@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+// MaxBatchSize - максимальное число писем, обрабатываемых одной пакетной операцией
+const MaxBatchSize = 500
+
+// BatchAction - действие пакетной операции над письмами
+type BatchAction string
+
+const (
+	BatchMove             BatchAction = "move"
+	BatchMarkAsSpam       BatchAction = "mark_as_spam"
+	BatchMarkAsNotSpam    BatchAction = "mark_as_not_spam"
+	BatchDeleteFromFolder BatchAction = "delete_from_folder"
+	BatchMarkAsRead       BatchAction = "mark_as_read"
+)
+
+// MessageFilter - условия отбора писем папки, нулевые значения не ограничивают выдачу
+type MessageFilter struct {
+	UnreadOnly      bool
+	StarredOnly     bool
+	WithAttachments bool
+	// Адрес отправителя, без учета регистра
+	Sender string
+	// Границы даты отправки: After включительно, Before не включительно
+	After  time.Time
+	Before time.Time
+}
+
+// BatchSelector выбирает письма либо списком идентификаторов, либо папкой с фильтром
+type BatchSelector struct {
+	MessageIDs []int64
+	FolderID   int64
+	Filter     MessageFilter
+}
+
+type BatchOperation struct {
+	Action   BatchAction
+	Selector BatchSelector
+	// Папка назначения для перемещения
+	TargetFolderID int64
+}
+
+// BatchItemResult - результат операции над одним письмом, пустой Error означает успех
+type BatchItemResult struct {
+	MessageID int64
+	Error     string
+}
+
+type BatchResult struct {
+	Items []BatchItemResult
+	// Под селектор папки попало больше писем, чем обработано за один раз
+	HasMore bool
+}
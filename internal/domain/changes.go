@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MailObjectType - тип данных почтового ящика, изменения которого ведутся в журнале
+type MailObjectType string
+
+const (
+	MailObjectMailbox MailObjectType = "Mailbox"
+	MailObjectEmail   MailObjectType = "Email"
+	// MailObjectThread - цепочки писем. Отдельного журнала у них нет: изменения цепочек
+	// считаются по записям о письмах, поэтому состояние цепочек совпадает с состоянием писем
+	MailObjectThread MailObjectType = "Thread"
+)
+
+// MailChangeType - что произошло с объектом
+type MailChangeType string
+
+const (
+	MailChangeCreated   MailChangeType = "created"
+	MailChangeUpdated   MailChangeType = "updated"
+	MailChangeDestroyed MailChangeType = "destroyed"
+)
+
+// MailChange - запись журнала изменений
+type MailChange struct {
+	ID         int64
+	ObjectID   int64
+	ChangeType MailChangeType
+	// ThreadKey - цепочка, в которой письмо появилось или из которой пропало
+	ThreadKey string
+}
+
+// MailState - текущие состояния данных почтового ящика (номера последних записей журнала)
+type MailState struct {
+	Mailbox int64
+	Email   int64
+}
+
+// MailChanges - объекты, измененные после состояния OldState. Если изменений больше, чем
+// запрошено, HasMoreChanges выставлен, а NewState - промежуточное состояние
+type MailChanges struct {
+	OldState       int64
+	NewState       int64
+	HasMoreChanges bool
+	Created        []string
+	Updated        []string
+	Destroyed      []string
+}
+
+// ThreadKey - идентификатор цепочки письма. Письмо без цепочки считается отдельной
+// цепочкой из одного письма
+func ThreadKey(threadID, messageID int64) string {
+	if threadID != 0 {
+		return strconv.FormatInt(threadID, 10)
+	}
+	return "m" + strconv.FormatInt(messageID, 10)
+}
+
+// ParseThreadKey разбирает идентификатор цепочки: возвращает цепочку или, для письма
+// без цепочки, само письмо
+func ParseThreadKey(key string) (threadID, messageID int64, ok bool) {
+	if rest, found := strings.CutPrefix(key, "m"); found {
+		messageID, err := strconv.ParseInt(rest, 10, 64)
+		return 0, messageID, err == nil && messageID > 0
+	}
+	threadID, err := strconv.ParseInt(key, 10, 64)
+	return threadID, 0, err == nil && threadID > 0
+}
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+const (
+	// MaxContactNameLength - максимальная длина имени контакта в символах
+	MaxContactNameLength = 100
+	// MaxContactPhoneLength - максимальная длина телефона контакта
+	MaxContactPhoneLength = 20
+	// MaxContactNotesLength - максимальная длина заметок о контакте в символах
+	MaxContactNotesLength = 2000
+	// MaxContactAvatarPathLength - максимальная длина пути к аватару контакта
+	MaxContactAvatarPathLength = 200
+	// MaxContactEmails - максимальное число адресов у одного контакта
+	MaxContactEmails = 10
+	// MaxContactGroupNameLength - максимальная длина названия группы контактов в символах
+	MaxContactGroupNameLength = 50
+	// MaxContactSuggestions - максимальное число подсказок автодополнения
+	MaxContactSuggestions = 20
+)
+
+type Contact struct {
+	ID         int64     `json:"id"`
+	ProfileID  int64     `json:"profile_id"`
+	Name       string    `json:"name"`
+	Emails     []string  `json:"emails"`
+	Phone      string    `json:"phone"`
+	Notes      string    `json:"notes"`
+	AvatarPath string    `json:"avatar_path"`
+	GroupIDs   []int64   `json:"group_ids"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ContactGroup struct {
+	ID           int64     `json:"id"`
+	ProfileID    int64     `json:"profile_id"`
+	Name         string    `json:"name"`
+	ContactCount int       `json:"contact_count"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ContactSuggestion - подсказка автодополнения адреса получателя
+type ContactSuggestion struct {
+	ContactID int64  `json:"contact_id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+}
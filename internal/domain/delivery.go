@@ -0,0 +1,82 @@
+package domain
+
+import "time"
+
+// Delivery - письмо, доставленное во входящие получателя, которое обрабатывают фильтры доставки
+type Delivery struct {
+	MessageID     int64
+	ProfileID     int64
+	BaseProfileID int64
+	From          string
+	To            string
+	Topic         string
+	Text          string
+	Datetime      time.Time
+	Size          int64
+	// Заголовки письма в каноническом виде (textproto.CanonicalMIMEHeaderKey)
+	Headers       map[string][]string
+	AutoSubmitted bool
+	// Сколько раз письмо уже было автоматически переслано до этого получателя
+	ForwardHops int
+	// Отправитель в списке разрешенных: письмо не проверяется спам-фильтром
+	SenderAllowed bool
+	// Результаты проверки отправителя внешнего письма, пустые - письмо отправлено внутри сервиса
+	SPF   SPFResult
+	DMARC DMARCResult
+}
+
+// DeliveryAction - решение фильтра о том, куда и как поместить письмо
+type DeliveryAction struct {
+	// Имена или типы папок, в которые нужно поместить письмо
+	Folders []string
+	// Оставить копию во входящих, если письмо помещено в другие папки
+	Keep    bool
+	Discard bool
+	// Флаги IMAP: \Seen делает письмо прочитанным, \Flagged - помеченным
+	Flags   []string
+	Replies []AutoReply
+	// Адреса, на которые письмо нужно автоматически переслать
+	Redirects []string
+	// Не передавать письмо следующим фильтрам
+	Stop bool
+}
+
+// AutoReply - автоматический ответ отправителю
+type AutoReply struct {
+	To    string
+	Topic string
+	Text  string
+	// Отвечать, только если письмо в итоге осталось во входящих и не попало в спам
+	InboxOnly bool
+}
+
+// Merge объединяет решения фильтров: более поздний фильтр, указавший папки или удаление,
+// переопределяет размещение письма, флаги и автоответы накапливаются
+func (a *DeliveryAction) Merge(other *DeliveryAction) {
+	if other == nil {
+		return
+	}
+
+	if other.Discard || len(other.Folders) > 0 {
+		a.Folders = other.Folders
+		a.Keep = other.Keep
+		a.Discard = other.Discard
+	}
+
+	for _, flag := range other.Flags {
+		if !containsFlag(a.Flags, flag) {
+			a.Flags = append(a.Flags, flag)
+		}
+	}
+	a.Replies = append(a.Replies, other.Replies...)
+	a.Redirects = append(a.Redirects, other.Redirects...)
+}
+
+func containsFlag(flags []string, flag string) bool {
+	for _, f := range flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
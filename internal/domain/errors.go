@@ -4,4 +4,34 @@ import "errors"
 
 var ErrFolderExists = errors.New("folder already exists")
 var ErrFolderNotFound = errors.New("folder not found")
+var ErrFolderAmbiguous = errors.New("folder name matches several folders")
 var ErrFolderSystem = errors.New("cannot delete system folder")
+var ErrMessageNotInTrash = errors.New("message is not in trash")
+var ErrMessageNotFound = errors.New("message not found")
+var ErrBatchTooLarge = errors.New("batch is too large")
+var ErrInvalidBatch = errors.New("invalid batch operation")
+var ErrNoFlags = errors.New("no flags to update")
+var ErrLabelNotFound = errors.New("label not found")
+var ErrLabelExists = errors.New("label already exists")
+var ErrInvalidLabel = errors.New("invalid label")
+var ErrInvalidFolderParent = errors.New("invalid parent folder")
+var ErrInvalidForwarding = errors.New("invalid forwarding rule")
+var ErrContactNotFound = errors.New("contact not found")
+var ErrContactExists = errors.New("contact with this email already exists")
+var ErrInvalidContact = errors.New("invalid contact")
+var ErrContactGroupNotFound = errors.New("contact group not found")
+var ErrContactGroupExists = errors.New("contact group already exists")
+var ErrInvalidSenderRule = errors.New("invalid sender rule")
+var ErrSenderRuleNotFound = errors.New("sender rule not found")
+var ErrTooManySenderRules = errors.New("too many sender rules")
+var ErrInvalidSender = errors.New("invalid sender address")
+var ErrRecipientNotFound = errors.New("recipient not found")
+var ErrDeliveryRejected = errors.New("delivery rejected by remote server")
+var ErrFileNotFound = errors.New("file not found")
+var ErrInvalidPOP3Settings = errors.New("invalid pop3 settings")
+var ErrInvalidMailState = errors.New("invalid mail state")
+var ErrSenderNotAllowed = errors.New("sender address does not belong to profile")
+var ErrInvalidAlias = errors.New("invalid alias")
+var ErrAliasNotFound = errors.New("alias not found")
+var ErrAliasTaken = errors.New("alias is already taken")
+var ErrTooManyAliases = errors.New("too many aliases")
@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// EventType - тип события, отправляемого клиенту в реальном времени
+type EventType string
+
+const (
+	EventNewMessage    EventType = "new_message"
+	EventMessageMoved  EventType = "message_moved"
+	EventMessageCopied EventType = "message_copied"
+	EventFlagsChanged  EventType = "flags_changed"
+	EventFolderRenamed EventType = "folder_renamed"
+	EventUnreadCount   EventType = "unread_count"
+	// EventResync - пропущенные события недоступны, клиенту нужно заново загрузить данные
+	EventResync EventType = "resync"
+)
+
+// MailEvent - изменение почтового ящика пользователя
+type MailEvent struct {
+	// Идентификаторы растут монотонно, по ним клиент продолжает поток после переподключения
+	ID         int64       `json:"id"`
+	Type       EventType   `json:"type"`
+	ProfileID  int64       `json:"profile_id"`
+	MessageID  int64       `json:"message_id,omitempty"`
+	FolderID   int64       `json:"folder_id,omitempty"`
+	FolderName string      `json:"folder_name,omitempty"`
+	From       string      `json:"from,omitempty"`
+	Topic      string      `json:"topic,omitempty"`
+	Flags      FlagsUpdate `json:"flags"`
+	Unread     int         `json:"unread"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
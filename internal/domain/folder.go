@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type FolderType string
 
@@ -11,12 +14,95 @@ const (
 	FolderTrash  FolderType = "trash"
 	FolderSpam   FolderType = "spam"
 	FolderCustom FolderType = "custom"
+	// Виртуальная папка с помеченными письмами, в таблице folder не хранится
+	FolderStarred FolderType = "starred"
 )
 
+// StarredFolderID - идентификатор виртуальной папки помеченных писем в API
+const StarredFolderID = "starred"
+
+// MaxFolderDepth - максимальная вложенность пользовательских папок, корневая папка имеет глубину 1
+const MaxFolderDepth = 8
+
+// FolderDelimiter - разделитель уровней в полном имени папки
+const FolderDelimiter = "/"
+
+// InboxPath - полное имя входящих, под которым их знают почтовые клиенты и sieve
+const InboxPath = "INBOX"
+
 type Folder struct {
 	ID        int64
 	ProfileID int64
-	Name      string
-	Type      FolderType
-	CreatedAt time.Time `json:"created_at"`
+	// Родительская папка, 0 - корневая папка
+	ParentID int64
+	Name     string
+	Type     FolderType
+	// Счетчики писем, которые поддерживают триггеры базы данных
+	MessageTotal  int
+	MessageUnread int
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// FolderNode - папка вместе с вложенными папками
+type FolderNode struct {
+	Folder
+	Children []FolderNode
+}
+
+// FolderPaths возвращает полные имена папок через FolderDelimiter по идентификаторам папок
+func FolderPaths(folders []Folder) map[int64]string {
+	byID := make(map[int64]Folder, len(folders))
+	for _, folder := range folders {
+		byID[folder.ID] = folder
+	}
+
+	var fullName func(folder Folder, depth int) string
+	fullName = func(folder Folder, depth int) string {
+		name := folder.Name
+		if folder.Type == FolderInbox {
+			name = InboxPath
+		}
+		if parent, ok := byID[folder.ParentID]; ok && folder.ParentID != 0 && depth < MaxFolderDepth {
+			name = fullName(parent, depth+1) + FolderDelimiter + name
+		}
+		return name
+	}
+
+	paths := make(map[int64]string, len(folders))
+	for _, folder := range folders {
+		paths[folder.ID] = fullName(folder, 0)
+	}
+	return paths
+}
+
+// FindFolder ищет папку без учета регистра по полному имени, затем по типу системной папки
+// и по имени без родителей. Если имени без родителей соответствуют несколько вложенных папок,
+// возвращается ErrFolderAmbiguous
+func FindFolder(folders []Folder, name string) (Folder, error) {
+	paths := FolderPaths(folders)
+	for _, folder := range folders {
+		if strings.EqualFold(paths[folder.ID], name) {
+			return folder, nil
+		}
+	}
+	for _, folder := range folders {
+		if folder.Type != FolderCustom && strings.EqualFold(string(folder.Type), name) {
+			return folder, nil
+		}
+	}
+
+	var found []Folder
+	for _, folder := range folders {
+		if strings.EqualFold(folder.Name, name) {
+			found = append(found, folder)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return Folder{}, ErrFolderNotFound
+	case 1:
+		return found[0], nil
+	default:
+		return Folder{}, ErrFolderAmbiguous
+	}
 }
@@ -0,0 +1,39 @@
+package domain
+
+import "strings"
+
+const (
+	// MaxForwardingAddresses - максимальное число адресов автопересылки
+	MaxForwardingAddresses = 10
+	// MaxForwardHops - максимальное число последовательных автопересылок одного письма
+	MaxForwardHops = 5
+)
+
+// ForwardingRule - правило автопересылки входящих писем профиля
+type ForwardingRule struct {
+	Enabled   bool     `json:"enabled"`
+	Addresses []string `json:"addresses"`
+	// Оставить копию письма во входящих
+	KeepCopy bool `json:"keep_copy"`
+	// Условия отбора без учета регистра, пустое условие подходит любому письму
+	FromContains    string `json:"from_contains"`
+	SubjectContains string `json:"subject_contains"`
+}
+
+// Matches сообщает, нужно ли пересылать письмо по этому правилу
+func (r ForwardingRule) Matches(delivery Delivery) bool {
+	if !r.Enabled || len(r.Addresses) == 0 {
+		return false
+	}
+	if r.FromContains != "" && !containsFold(delivery.From, r.FromContains) {
+		return false
+	}
+	if r.SubjectContains != "" && !containsFold(delivery.Topic, r.SubjectContains) {
+		return false
+	}
+	return true
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
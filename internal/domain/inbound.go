@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// InboundMessage - письмо, принятое по SMTP от внешнего сервера
+type InboundMessage struct {
+	// Адрес из MAIL FROM, пустой для уведомлений о доставке
+	EnvelopeFrom string
+	// Адрес и имя из заголовка From
+	From     string
+	FromName string
+	Topic    string
+	Text     string
+	Datetime time.Time
+	// Заголовки письма в каноническом виде (textproto.CanonicalMIMEHeaderKey)
+	Headers     map[string][]string
+	Attachments []Attachment
+	// Результат проверки DKIM-подписи, пустой - письмо не проверялось
+	DKIM  DKIMResult
+	SPF   SPFResult
+	DMARC DMARCResult
+	// AuthenticationResults - сводка проверок в формате значения
+	// заголовка Authentication-Results (RFC 8601)
+	AuthenticationResults string
+}
+
+// Attachment - вложение письма до загрузки в хранилище
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Sender возвращает адрес, от имени которого письмо сохраняется у получателя
+func (m InboundMessage) Sender() string {
+	if m.From != "" {
+		return m.From
+	}
+	return m.EnvelopeFrom
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// MaxLabelNameLength - максимальная длина названия метки в символах
+const MaxLabelNameLength = 50
+
+// DefaultLabelColor - цвет метки, если пользователь его не выбрал
+const DefaultLabelColor = "#9e9e9e"
+
+type Label struct {
+	ID        int64     `json:"id"`
+	ProfileID int64     `json:"profile_id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	CreatedAt time.Time `json:"created_at"`
+}
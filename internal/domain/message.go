@@ -8,20 +8,69 @@ type Message struct {
 	Snippet  string    `json:"snippet"`
 	Datetime time.Time `json:"datetime"`
 	IsRead   bool      `json:"is_read"`
+	// Размер текста и вложений в байтах
+	Size int64 `json:"size"`
+	MessageFlags
+	Labels []Label `json:"labels"`
 	Sender
 }
 
+// MessageFlags - пользовательские флаги письма, кроме прочитанности
+type MessageFlags struct {
+	IsStarred   bool `json:"is_starred"`
+	IsImportant bool `json:"is_important"`
+	IsAnswered  bool `json:"is_answered"`
+	IsForwarded bool `json:"is_forwarded"`
+}
+
+// FlagsUpdate - изменение флагов письма, nil означает, что флаг не меняется
+type FlagsUpdate struct {
+	Read      *bool
+	Starred   *bool
+	Important *bool
+	Answered  *bool
+	Forwarded *bool
+}
+
+// Empty сообщает, что изменение не затрагивает ни одного флага
+func (u FlagsUpdate) Empty() bool {
+	return u.Read == nil && u.Starred == nil && u.Important == nil && u.Answered == nil && u.Forwarded == nil
+}
+
 type FullMessage struct {
 	ID         string    `json:"id"`
 	Topic      string    `json:"topic"`
 	Text       string    `json:"text"`
 	Datetime   time.Time `json:"datetime"`
 	ThreadRoot string    `json:"thread_root"`
+	// Оценка спам-фильтра (0 - не спам, 1 - спам) и повлиявшие на нее токены
+	SpamScore   float64  `json:"spam_score"`
+	SpamReasons []string `json:"spam_reasons"`
+	// Результат проверки DKIM-подписи письма от внешнего отправителя
+	DKIM  DKIMResult  `json:"dkim"`
+	SPF   SPFResult   `json:"spf"`
+	DMARC DMARCResult `json:"dmarc"`
+	// AuthenticationResults - сводка проверок отправителя в формате Authentication-Results
+	AuthenticationResults string `json:"authentication_results"`
+	// Размер текста и вложений в байтах
+	Size   int64 `json:"size"`
+	IsRead bool  `json:"is_read"`
+	MessageFlags
+	// Все папки пользователя, в которых лежит письмо
+	FolderIDs []int64 `json:"folder_ids"`
+	// Адреса получателей, кроме отправителя
+	Receivers []string `json:"receivers"`
 	Folder
 	Sender
 	Files
 }
 
+// UnverifiedSender сообщает, что адрес в поле From внешнего письма
+// не подтвержден ни DKIM, ни SPF и мог быть подделан
+func (m FullMessage) UnverifiedSender() bool {
+	return m.DMARC.Status != "" && !m.DMARC.Aligned
+}
+
 type Messages struct {
 	MessageTotal  int         `json:"message_total"`
 	MessageUnread int         `json:"message_unread"`
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"strconv"
+	"time"
+)
+
+// MessageSort - порядок писем в списке папки
+type MessageSort string
+
+const (
+	SortDateDesc MessageSort = "date_desc"
+	SortDateAsc  MessageSort = "date_asc"
+	// По адресу отправителя, от A до Z
+	SortSender MessageSort = "sender"
+	// По теме, от A до Z
+	SortTopic MessageSort = "topic"
+	// По размеру, от больших писем к маленьким
+	SortSize MessageSort = "size"
+)
+
+// ParseMessageSort проверяет порядок сортировки из запроса, пустая строка - по убыванию даты
+func ParseMessageSort(value string) (MessageSort, bool) {
+	switch sort := MessageSort(value); sort {
+	case "":
+		return SortDateDesc, true
+	case SortDateDesc, SortDateAsc, SortSender, SortTopic, SortSize:
+		return sort, true
+	}
+	return "", false
+}
+
+// MessageKey - значения ключей сортировки граничного письма страницы
+type MessageKey struct {
+	MessageID int64
+	Datetime  time.Time
+	Sender    string
+	Topic     string
+	Size      int64
+}
+
+// MessagePage - запрос страницы списка писем с keyset-пагинацией
+type MessagePage struct {
+	Sort   MessageSort
+	Filter MessageFilter
+	// After - граничное письмо соседней страницы, nil - первая страница
+	After *MessageKey
+	// Backward - страница перед письмом After. Письма все равно идут в порядке сортировки
+	Backward bool
+	Limit    int
+}
+
+// Key возвращает ключи сортировки письма, по которым продолжается список
+func (m Message) Key() MessageKey {
+	messageID, _ := strconv.ParseInt(m.ID, 10, 64)
+	return MessageKey{
+		MessageID: messageID,
+		Datetime:  m.Datetime,
+		Sender:    m.Sender.Email,
+		Topic:     m.Topic,
+		Size:      m.Size,
+	}
+}
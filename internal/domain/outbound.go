@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// OutboundStatus - состояние доставки письма на внешний адрес
+type OutboundStatus string
+
+const (
+	OutboundQueued OutboundStatus = "queued"
+	// OutboundSending - письмо забрал обработчик очереди. Если он упал, письмо
+	// вернется в очередь после окончания аренды
+	OutboundSending  OutboundStatus = "sending"
+	OutboundDeferred OutboundStatus = "deferred"
+	OutboundSent     OutboundStatus = "sent"
+	OutboundBounced  OutboundStatus = "bounced"
+)
+
+// OutboundMessage - письмо из очереди исходящей почты вместе с содержимым для отправки
+type OutboundMessage struct {
+	ID              int64
+	MessageID       int64
+	SenderProfileID int64
+	From            string
+	FromName        string
+	Recipient       string
+	// Тема и текст хранятся экранированными, как и у остальных писем
+	Topic    string
+	Text     string
+	Datetime time.Time
+	Files    []File
+	// Attempts - номер текущей попытки доставки, начиная с 1
+	Attempts      int
+	DelayNotified bool
+	CreatedAt     time.Time
+	// Сколько раз письмо было автоматически переслано, включая эту отправку
+	ForwardHops int
+}
@@ -0,0 +1,28 @@
+package domain
+
+// POP3DeleteAction - что делает удаление письма по POP3
+type POP3DeleteAction string
+
+const (
+	// POP3DeleteToTrash переносит письмо в корзину
+	POP3DeleteToTrash POP3DeleteAction = "trash"
+	// POP3DeleteHide только скрывает письмо от POP3, в остальных клиентах оно остается во входящих
+	POP3DeleteHide POP3DeleteAction = "hide"
+)
+
+// POP3Settings - настройки доступа по POP3
+type POP3Settings struct {
+	DeleteAction POP3DeleteAction `json:"delete_action"`
+	// APOPEnabled - задан ли секрет для входа командой APOP
+	APOPEnabled bool `json:"apop_enabled"`
+}
+
+// POP3SettingsUpdate - изменение настроек POP3. Пустой APOPSecret при включенном APOP
+// оставляет прежний секрет.
+// Секрет APOP - отдельный общий секрет с почтовым клиентом, а не пароль входа: сервер хранит его
+// открытым, чтобы считать MD5-дайджест (RFC 1939, 7), поэтому наружу он никогда не возвращается
+type POP3SettingsUpdate struct {
+	DeleteAction POP3DeleteAction
+	APOPEnabled  bool
+	APOPSecret   string
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// MaxSenderRules - максимальное суммарное число записей в списках отправителей профиля
+const MaxSenderRules = 1000
+
+// SenderListKind - список, в котором находится отправитель
+type SenderListKind string
+
+const (
+	SenderBlocked SenderListKind = "block"
+	SenderAllowed SenderListKind = "allow"
+)
+
+// BlockedSenderAction - что делать с письмами заблокированных отправителей
+type BlockedSenderAction string
+
+const (
+	BlockedToSpam  BlockedSenderAction = "spam"
+	BlockedDiscard BlockedSenderAction = "discard"
+)
+
+// SenderRule - запись списка отправителей: точный адрес или домен (вместе с поддоменами)
+type SenderRule struct {
+	ID        int64          `json:"id"`
+	Kind      SenderListKind `json:"kind"`
+	Pattern   string         `json:"pattern"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// IsDomain сообщает, задает ли запись домен, а не адрес
+func (r SenderRule) IsDomain() bool {
+	return !strings.Contains(r.Pattern, "@")
+}
+
+type SenderLists struct {
+	Blocked       []SenderRule        `json:"blocked"`
+	Allowed       []SenderRule        `json:"allowed"`
+	BlockedAction BlockedSenderAction `json:"blocked_action"`
+}
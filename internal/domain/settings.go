@@ -7,16 +7,24 @@ type Settings struct {
 	Language              string   `json:"language"`
 	Theme                 string   `json:"theme"`
 	Signatures            []string `json:"signatures"`
+	// Срок хранения писем в корзине и спаме в днях, 0 - не удалять автоматически
+	TrashRetentionDays int              `json:"trash_retention_days"`
+	Vacation           VacationSettings `json:"vacation"`
 }
 
 type Signatures []string
 
+// DefaultTrashRetentionDays - срок хранения писем в корзине по умолчанию
+const DefaultTrashRetentionDays = 30
+
 func SetDefaultSettings(profileID int64) Settings {
 	return Settings{
 		ProfileID:             profileID,
 		Language:              "ru",
 		Theme:                 "light",
 		NotificationTolerance: "normal",
+		TrashRetentionDays:    DefaultTrashRetentionDays,
+		Vacation:              VacationSettings{Days: DefaultVacationDays},
 		//Signature:             "",
 	}
 }
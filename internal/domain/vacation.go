@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// DefaultVacationDays - интервал между автоответами одному отправителю по умолчанию
+	DefaultVacationDays = 7
+	// MaxVacationDays - максимальный интервал между автоответами одному отправителю
+	MaxVacationDays = 30
+)
+
+var ErrInvalidVacation = errors.New("invalid vacation settings")
+
+// VacationSettings - настройки автоответа "вне офиса"
+type VacationSettings struct {
+	Enabled bool `json:"enabled"`
+	// Границы периода: Start включительно, End не включительно, нулевое время - без ограничения
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Subject string    `json:"subject"`
+	Body    string    `json:"body"`
+	// Отвечать только отправителям из адресной книги
+	ContactsOnly bool `json:"contacts_only"`
+	// Не чаще одного автоответа одному отправителю за указанное число дней
+	Days int `json:"days"`
+}
+
+// Active сообщает, действует ли автоответ в момент now
+func (v VacationSettings) Active(now time.Time) bool {
+	if !v.Enabled {
+		return false
+	}
+	if !v.Start.IsZero() && now.Before(v.Start) {
+		return false
+	}
+	if !v.End.IsZero() && !now.Before(v.End) {
+		return false
+	}
+	return true
+}
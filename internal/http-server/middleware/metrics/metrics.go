@@ -22,6 +22,12 @@ func (srw *statusResponseWriter) WriteHeader(code int) {
 	srw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap дает http.ResponseController доступ к исходному ResponseWriter,
+// чтобы потоковые ответы могли сбрасывать буфер и менять таймауты записи
+func (srw *statusResponseWriter) Unwrap() http.ResponseWriter {
+	return srw.ResponseWriter
+}
+
 func Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
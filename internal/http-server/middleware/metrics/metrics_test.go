@@ -73,6 +73,16 @@ func TestStatusResponseWriter_Write(t *testing.T) {
 	assert.Equal(t, http.StatusOK, srw.statusCode)
 }
 
+func TestStatusResponseWriter_Flush(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	sw := NewStatusResponseWriter(recorder)
+
+	err := http.NewResponseController(sw).Flush()
+
+	assert.NoError(t, err)
+	assert.True(t, recorder.Flushed)
+}
+
 func TestMiddleware(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -0,0 +1,192 @@
+// Package bayes реализует наивный байесовский классификатор спама по токенам
+// (оценка вероятности токена по Робинсону, комбинирование наиболее значимых токенов).
+package bayes
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	minTokenLen = 2
+	maxTokenLen = 40
+	// Число наиболее значимых токенов, участвующих в оценке
+	interestingTokens = 15
+	// Параметры сглаживания Робинсона: сила априорного знания и априорная вероятность
+	robinsonS = 1.0
+	robinsonX = 0.5
+	// Токены, вероятность которых близка к нейтральной, не учитываются
+	minDeviation = 0.1
+)
+
+// TokenCounts - сколько раз токен встречался в спаме и не-спаме
+type TokenCounts struct {
+	Spam int64
+	Ham  int64
+}
+
+// Stats - статистика обучения: число писем каждого класса и счетчики токенов
+type Stats struct {
+	SpamMessages int64
+	HamMessages  int64
+	Tokens       map[string]TokenCounts
+}
+
+// Reason - токен, повлиявший на оценку
+type Reason struct {
+	Token       string
+	Probability float64
+}
+
+func (r Reason) String() string {
+	return fmt.Sprintf("%q: %.2f", r.Token, r.Probability)
+}
+
+// Result - результат классификации
+type Result struct {
+	// Вероятность того, что письмо - спам, от 0 до 1
+	Score   float64
+	Reasons []Reason
+}
+
+// Trained сообщает, достаточно ли статистики для классификации
+func (s Stats) Trained() bool {
+	return s.SpamMessages > 0 && s.HamMessages > 0
+}
+
+// Tokenize разбивает текст письма на уникальные токены в нижнем регистре.
+// Токены заголовков помечаются префиксом, чтобы отличаться от токенов текста.
+func Tokenize(topic, text, senderDomain string) []string {
+	seen := make(map[string]struct{})
+	var tokens []string
+	add := func(token string) {
+		if _, ok := seen[token]; ok {
+			return
+		}
+		seen[token] = struct{}{}
+		tokens = append(tokens, token)
+	}
+
+	for _, word := range words(topic) {
+		add("subject:" + word)
+	}
+	for _, word := range words(text) {
+		add(word)
+	}
+	if senderDomain != "" {
+		add("from:" + strings.ToLower(senderDomain))
+	}
+
+	return tokens
+}
+
+func words(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '$' && r != '!' && r != '\'' && r != '-'
+	})
+
+	result := make([]string, 0, len(fields))
+	for _, field := range fields {
+		field = strings.Trim(field, "-'")
+		length := len([]rune(field))
+		if length < minTokenLen || length > maxTokenLen {
+			continue
+		}
+		if isNumber(field) {
+			continue
+		}
+		result = append(result, field)
+	}
+	return result
+}
+
+func isNumber(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Classify оценивает вероятность спама по токенам письма
+func Classify(tokens []string, stats Stats) Result {
+	if !stats.Trained() {
+		return Result{Score: robinsonX}
+	}
+
+	var reasons []Reason
+	for _, token := range tokens {
+		counts, ok := stats.Tokens[token]
+		if !ok {
+			continue
+		}
+		p := tokenProbability(counts, stats)
+		if math.Abs(p-0.5) < minDeviation {
+			continue
+		}
+		reasons = append(reasons, Reason{Token: token, Probability: p})
+	}
+
+	sort.SliceStable(reasons, func(i, j int) bool {
+		di := math.Abs(reasons[i].Probability - 0.5)
+		dj := math.Abs(reasons[j].Probability - 0.5)
+		if di != dj {
+			return di > dj
+		}
+		return reasons[i].Token < reasons[j].Token
+	})
+	if len(reasons) > interestingTokens {
+		reasons = reasons[:interestingTokens]
+	}
+	if len(reasons) == 0 {
+		return Result{Score: robinsonX}
+	}
+
+	// Комбинирование вероятностей в логарифмах, чтобы избежать потери точности
+	var logSum float64
+	for _, reason := range reasons {
+		logSum += math.Log(1-reason.Probability) - math.Log(reason.Probability)
+	}
+	score := 1 / (1 + math.Exp(logSum))
+
+	return Result{Score: score, Reasons: reasons}
+}
+
+// tokenProbability - вероятность спама для токена со сглаживанием Робинсона
+func tokenProbability(counts TokenCounts, stats Stats) float64 {
+	spamFreq := math.Min(1, float64(counts.Spam)/float64(stats.SpamMessages))
+	hamFreq := math.Min(1, float64(counts.Ham)/float64(stats.HamMessages))
+	if spamFreq+hamFreq == 0 {
+		return robinsonX
+	}
+
+	p := spamFreq / (spamFreq + hamFreq)
+	n := float64(counts.Spam + counts.Ham)
+	p = (robinsonS*robinsonX + n*p) / (robinsonS + n)
+
+	// Крайние значения ограничиваются, чтобы один токен не определял результат
+	return math.Max(0.01, math.Min(0.99, p))
+}
+
+// Merge объединяет статистику: счетчики other учитываются с весом weight
+func (s Stats) Merge(other Stats, weight int64) Stats {
+	result := Stats{
+		SpamMessages: s.SpamMessages + other.SpamMessages*weight,
+		HamMessages:  s.HamMessages + other.HamMessages*weight,
+		Tokens:       make(map[string]TokenCounts, len(s.Tokens)+len(other.Tokens)),
+	}
+	for token, counts := range s.Tokens {
+		result.Tokens[token] = counts
+	}
+	for token, counts := range other.Tokens {
+		current := result.Tokens[token]
+		current.Spam += counts.Spam * weight
+		current.Ham += counts.Ham * weight
+		result.Tokens[token] = current
+	}
+	return result
+}
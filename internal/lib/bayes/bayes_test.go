@@ -0,0 +1,90 @@
+package bayes
+
+import (
+	"reflect"
+	"testing"
+)
+
+func trainedStats() Stats {
+	return Stats{
+		SpamMessages: 10,
+		HamMessages:  10,
+		Tokens: map[string]TokenCounts{
+			"viagra":          {Spam: 9, Ham: 0},
+			"subject:выигрыш": {Spam: 8, Ham: 0},
+			"бесплатно":       {Spam: 7, Ham: 1},
+			"отчет":           {Spam: 0, Ham: 8},
+			"subject:встреча": {Spam: 0, Ham: 6},
+			"привет":          {Spam: 5, Ham: 5},
+		},
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("Большой ВЫИГРЫШ", "Получите выигрыш бесплатно! 100 раз, а-а", "Spam.com")
+	want := []string{
+		"subject:большой", "subject:выигрыш",
+		"получите", "выигрыш", "бесплатно!", "раз", "а-а",
+		"from:spam.com",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize() = %v, want %v", got, want)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	stats := trainedStats()
+
+	tests := []struct {
+		name     string
+		tokens   []string
+		wantSpam bool
+		wantHam  bool
+	}{
+		{name: "Spam tokens", tokens: []string{"viagra", "subject:выигрыш", "бесплатно", "привет"}, wantSpam: true},
+		{name: "Ham tokens", tokens: []string{"отчет", "subject:встреча", "привет"}, wantHam: true},
+		{name: "Unknown tokens are neutral", tokens: []string{"неизвестно"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Classify(tt.tokens, stats)
+			if tt.wantSpam && got.Score < 0.9 {
+				t.Errorf("Classify() score = %v, want spam", got.Score)
+			}
+			if tt.wantHam && got.Score > 0.1 {
+				t.Errorf("Classify() score = %v, want ham", got.Score)
+			}
+			if !tt.wantSpam && !tt.wantHam && got.Score != 0.5 {
+				t.Errorf("Classify() score = %v, want neutral", got.Score)
+			}
+			for _, reason := range got.Reasons {
+				if reason.Token == "привет" {
+					t.Error("neutral token must not be a reason")
+				}
+			}
+		})
+	}
+}
+
+func TestClassify_Untrained(t *testing.T) {
+	got := Classify([]string{"viagra"}, Stats{SpamMessages: 3})
+	if got.Score != 0.5 || len(got.Reasons) != 0 {
+		t.Errorf("Classify() = %+v, want neutral result without training", got)
+	}
+}
+
+func TestStats_Merge(t *testing.T) {
+	global := Stats{SpamMessages: 1, HamMessages: 2, Tokens: map[string]TokenCounts{"a": {Spam: 1}}}
+	user := Stats{SpamMessages: 1, HamMessages: 1, Tokens: map[string]TokenCounts{"a": {Ham: 1}, "b": {Spam: 1}}}
+
+	got := global.Merge(user, 3)
+	want := Stats{
+		SpamMessages: 4,
+		HamMessages:  5,
+		Tokens:       map[string]TokenCounts{"a": {Spam: 1, Ham: 3}, "b": {Spam: 3}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,61 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCodec_RoundTrip(t *testing.T) {
+	codec := NewCodec([]byte("secret"), time.Hour)
+	datetime := time.Date(2025, 3, 1, 12, 0, 0, 123456000, time.UTC)
+
+	value := codec.Encode(Cursor{ProfileID: 1, Scope: "folder:5:0", Sort: "topic", MessageID: 42, Datetime: datetime, Key: "Отчет", Backward: true})
+	got, err := codec.Decode(value)
+
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if got.ProfileID != 1 || got.Scope != "folder:5:0" || got.MessageID != 42 || !got.Backward || got.Sort != "topic" || got.Key != "Отчет" {
+		t.Errorf("Decode() = %+v", got)
+	}
+	if !got.Datetime.Equal(datetime) {
+		t.Errorf("Decode() datetime = %v, want %v", got.Datetime, datetime)
+	}
+	if strings.ContainsAny(value, "+/=") {
+		t.Errorf("cursor %q is not URL-safe", value)
+	}
+}
+
+func TestCodec_Decode_Rejects(t *testing.T) {
+	codec := NewCodec([]byte("secret"), time.Hour)
+	value := codec.Encode(Cursor{ProfileID: 1, Scope: "starred", MessageID: 42})
+	parts := strings.Split(value, ".")
+
+	tampered := base64.RawURLEncoding.EncodeToString([]byte(`{"p":2,"s":"starred","id":42,"iat":` + "9999999999" + `}`))
+
+	expired := NewCodec([]byte("secret"), time.Hour)
+	expired.now = func() time.Time { return time.Now().Add(-2 * time.Hour) }
+
+	tests := []struct {
+		name  string
+		value string
+		want  error
+	}{
+		{name: "Empty", value: "", want: ErrInvalidCursor},
+		{name: "Garbage", value: "not-a-cursor", want: ErrInvalidCursor},
+		{name: "TamperedPayload", value: parts[0] + "." + tampered + "." + parts[2], want: ErrInvalidCursor},
+		{name: "UnknownVersion", value: "2." + parts[1] + "." + parts[2], want: ErrInvalidCursor},
+		{name: "OtherSecret", value: NewCodec([]byte("other"), time.Hour).Encode(Cursor{ProfileID: 1}), want: ErrInvalidCursor},
+		{name: "Expired", value: expired.Encode(Cursor{ProfileID: 1}), want: ErrExpiredCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := codec.Decode(tt.value); err != tt.want {
+				t.Errorf("Decode() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// Package dkim подписывает и проверяет письма по DKIM (RFC 6376)
+// с алгоритмами rsa-sha256 и ed25519-sha256 (RFC 8463)
+package dkim
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	AlgorithmRSA     = "rsa-sha256"
+	AlgorithmEd25519 = "ed25519-sha256"
+
+	signatureHeader = "DKIM-Signature"
+
+	canonicalSimple  = "simple"
+	canonicalRelaxed = "relaxed"
+)
+
+var (
+	ErrNoFrom         = errors.New("dkim: message has no From header")
+	ErrMalformedTags  = errors.New("dkim: malformed tag list")
+	ErrUnsupportedKey = errors.New("dkim: unsupported key type")
+)
+
+// header - поле заголовка в исходном виде, вместе с переносами строк и завершающим CRLF
+type header struct {
+	name string
+	raw  string
+}
+
+func (h header) value() string {
+	_, value, _ := strings.Cut(h.raw, ":")
+	return value
+}
+
+// splitMessage делит письмо на поля заголовка и тело. Переводы строк
+// приводятся к CRLF, потому что подпись считается по письму в виде для SMTP
+func splitMessage(msg []byte) ([]header, []byte) {
+	msg = bytes.ReplaceAll(bytes.ReplaceAll(msg, []byte("\r\n"), []byte("\n")), []byte("\n"), []byte("\r\n"))
+
+	var headers []header
+	for len(msg) > 0 {
+		if bytes.HasPrefix(msg, []byte("\r\n")) {
+			return headers, msg[2:]
+		}
+
+		end := bytes.Index(msg, []byte("\r\n"))
+		if end < 0 {
+			end = len(msg)
+		} else {
+			end += 2
+		}
+
+		line := string(msg[:end])
+		if (line[0] == ' ' || line[0] == '\t') && len(headers) > 0 {
+			headers[len(headers)-1].raw += line
+		} else {
+			name, _, _ := strings.Cut(line, ":")
+			headers = append(headers, header{name: strings.TrimSpace(name), raw: line})
+		}
+		msg = msg[end:]
+	}
+	return headers, nil
+}
+
+// selectHeaders выбирает поля для подписи в порядке списка h=. Одноименные поля
+// берутся снизу вверх, отсутствующее поле не дает ничего (RFC 6376, 5.4.2)
+func selectHeaders(headers []header, names []string) []header {
+	used := make(map[int]bool)
+	selected := make([]header, 0, len(names))
+	for _, name := range names {
+		for i := len(headers) - 1; i >= 0; i-- {
+			if !used[i] && strings.EqualFold(headers[i].name, name) {
+				used[i] = true
+				selected = append(selected, headers[i])
+				break
+			}
+		}
+	}
+	return selected
+}
+
+func canonicalHeader(h header, method string) string {
+	if method == canonicalSimple {
+		return h.raw
+	}
+	return strings.ToLower(h.name) + ":" + strings.Trim(compressSpace(unfold(h.value())), " ") + "\r\n"
+}
+
+func canonicalBody(body []byte, method string) []byte {
+	lines := strings.Split(string(body), "\r\n")
+	if method == canonicalRelaxed {
+		for i, line := range lines {
+			lines[i] = strings.TrimRight(compressSpace(line), " ")
+		}
+	}
+
+	// Пустые строки в конце тела не подписываются
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		if method == canonicalSimple {
+			return []byte("\r\n")
+		}
+		return nil
+	}
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
+
+func unfold(s string) string {
+	return strings.NewReplacer("\r\n", "", "\n", "").Replace(s)
+}
+
+// compressSpace заменяет последовательности пробелов и табуляций одним пробелом
+func compressSpace(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		if r == ' ' || r == '\t' {
+			if !space {
+				b.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		space = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// parseTags разбирает список тегов вида "a=1; b=2" (RFC 6376, 3.2)
+func parseTags(s string) (map[string]string, error) {
+	tags := make(map[string]string)
+	for _, part := range strings.Split(unfold(s), ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%w: %q", ErrMalformedTags, part)
+		}
+		if _, dup := tags[name]; dup {
+			return nil, fmt.Errorf("%w: duplicate tag %q", ErrMalformedTags, name)
+		}
+		tags[name] = strings.TrimSpace(value)
+	}
+	return tags, nil
+}
+
+// removeSpace убирает пробелы из значений в base64, которые могут быть перенесены
+func removeSpace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\t' || r == '\r' || r == '\n' {
+			return -1
+		}
+		return r
+	}, s)
+}
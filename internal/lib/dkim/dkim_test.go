@@ -0,0 +1,302 @@
+package dkim
+
+import (
+	"2025_2_a4code/internal/domain"
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+const message = "From: Алексей <alexey@flintmail.ru>\r\n" +
+	"To: someone@example.com\r\n" +
+	"Reply-To: alexey@flintmail.ru\r\n" +
+	"In-Reply-To: <99@example.com>\r\n" +
+	"References: <98@example.com> <99@example.com>\r\n" +
+	"Mime-Version: 1.0\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Subject: Hello\r\n" +
+	"Date: Mon, 03 Nov 2025 12:00:00 +0000\r\n" +
+	"Message-Id: <100@flintmail.ru>\r\n" +
+	"X-Mailer: test\r\n" +
+	"\r\n" +
+	"Hello,  world!\r\n" +
+	"\r\n"
+
+var now = time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC)
+
+// dns - TXT-записи для проверки подписей без обращения к сети
+type dns map[string]string
+
+func (d dns) lookupTXT(ctx context.Context, name string) ([]string, error) {
+	switch record, ok := d[name]; {
+	case name == "down._domainkey.flintmail.ru":
+		return nil, &net.DNSError{Err: "server misbehaving", Name: name, IsTemporary: true}
+	case !ok:
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	default:
+		return []string{record}, nil
+	}
+}
+
+func newKeys(t *testing.T) (Key, Key, dns) {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsaSigner := Key{Domain: "flintmail.ru", Selector: "rsa", Signer: rsaKey}
+	edSigner := Key{Domain: "flintmail.ru", Selector: "ed", Signer: edKey}
+
+	records := dns{"revoked._domainkey.flintmail.ru": "v=DKIM1; k=rsa; p="}
+	for _, key := range []Key{rsaSigner, edSigner} {
+		record, err := key.DNSRecord()
+		if err != nil {
+			t.Fatal(err)
+		}
+		records[key.Selector+"._domainkey."+key.Domain] = record
+	}
+	return rsaSigner, edSigner, records
+}
+
+func newVerifier(records dns) *Verifier {
+	verifier := NewVerifier(records.lookupTXT)
+	verifier.now = func() time.Time { return now }
+	return verifier
+}
+
+func TestCanonicalization(t *testing.T) {
+	// Пример из RFC 6376, 3.4.6
+	headers, body := splitMessage([]byte("A: X\r\nB : Y\t\r\n\tZ  \r\n\r\n C \r\nD \t E\r\n\r\n\r\n"))
+
+	var relaxed, simple strings.Builder
+	for _, h := range headers {
+		relaxed.WriteString(canonicalHeader(h, canonicalRelaxed))
+		simple.WriteString(canonicalHeader(h, canonicalSimple))
+	}
+	if got := relaxed.String(); got != "a:X\r\nb:Y Z\r\n" {
+		t.Errorf("relaxed headers = %q", got)
+	}
+	if got := simple.String(); got != "A: X\r\nB : Y\t\r\n\tZ  \r\n" {
+		t.Errorf("simple headers = %q", got)
+	}
+	if got := string(canonicalBody(body, canonicalRelaxed)); got != " C\r\nD E\r\n" {
+		t.Errorf("relaxed body = %q", got)
+	}
+	if got := string(canonicalBody(body, canonicalSimple)); got != " C \r\nD \t E\r\n" {
+		t.Errorf("simple body = %q", got)
+	}
+	if got := string(canonicalBody(nil, canonicalSimple)); got != "\r\n" {
+		t.Errorf("simple empty body = %q", got)
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	rsaKey, edKey, records := newKeys(t)
+	verifier := newVerifier(records)
+
+	for _, key := range []Key{rsaKey, edKey} {
+		t.Run(key.Selector, func(t *testing.T) {
+			signed, err := Sign([]byte(message), key, now)
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+			for i, line := range strings.Split(string(signed), "\r\n") {
+				if len(line) > 78 {
+					t.Errorf("line %d is %d bytes long", i, len(line))
+				}
+			}
+
+			results := verifier.Verify(context.Background(), signed)
+			if len(results) != 1 || results[0].Status != domain.DKIMPass {
+				t.Fatalf("Verify() = %+v, want pass", results)
+			}
+			if results[0].Domain != "flintmail.ru" || results[0].Selector != key.Selector {
+				t.Errorf("Verify() = %+v", results[0])
+			}
+
+			// Переносы строк и пробелы, которые меняют почтовые серверы по пути
+			relayed := strings.ReplaceAll(string(signed), "\r\n", "\n")
+			relayed = strings.Replace(relayed, "Subject: Hello", "Subject:   Hello ", 1)
+			relayed = strings.Replace(relayed, "To: someone@example.com", "To:\n someone@example.com", 1)
+			relayed += "\n\n"
+			if results := verifier.Verify(context.Background(), []byte(relayed)); results[0].Status != domain.DKIMPass {
+				t.Errorf("Verify(relayed) = %+v, want pass", results)
+			}
+		})
+	}
+}
+
+func TestVerify_RFC8463Example(t *testing.T) {
+	// Пример из RFC 8463, приложение A: подпись другой реализации
+	msg := "DKIM-Signature: v=1; a=ed25519-sha256; c=relaxed/relaxed;\r\n" +
+		" d=football.example.com; i=@football.example.com;\r\n" +
+		" q=dns/txt; s=brisbane; t=1528637909; h=from : to :\r\n" +
+		" subject : date : message-id : from : subject : date;\r\n" +
+		" bh=2jUSOH9NhtVGCQWNr9BrIAPreKQjO6Sn7XIkfJVOzv8=;\r\n" +
+		" b=/gCrinpcQOoIfuHNQIbq4pgh9kyIK3AQUdt9OdqQehSwhEIug4D11Bus\r\n" +
+		" Fa3bT3FY5OsU7ZbnKELq+eXdp1Q1Dw==\r\n" +
+		"From: Joe SixPack <joe@football.example.com>\r\n" +
+		"To: Suzie Q <suzie@shopping.example.net>\r\n" +
+		"Subject: Is dinner ready?\r\n" +
+		"Date: Fri, 11 Jul 2003 21:00:37 -0700 (PDT)\r\n" +
+		"Message-ID: <20030712040037.46341.5F8J@football.example.com>\r\n" +
+		"\r\n" +
+		"Hi.\r\n" +
+		"\r\n" +
+		"We lost the game.  Are you hungry yet?\r\n" +
+		"\r\n" +
+		"Joe.\r\n"
+	verifier := newVerifier(dns{
+		"brisbane._domainkey.football.example.com": "v=DKIM1; k=ed25519; p=11qYAYKxCrfVS/7TyWQHOg7hcvPapiMlrwIaaPcHURo=",
+	})
+
+	results := verifier.Verify(context.Background(), []byte(msg))
+
+	if len(results) != 1 || results[0].Status != domain.DKIMPass {
+		t.Errorf("Verify() = %+v, want pass", results)
+	}
+}
+
+func TestVerify_Failures(t *testing.T) {
+	rsaKey, _, records := newKeys(t)
+	verifier := newVerifier(records)
+
+	sign := func(key Key) string {
+		signed, err := Sign([]byte(message), key, now)
+		if err != nil {
+			t.Fatalf("Sign() error = %v", err)
+		}
+		return string(signed)
+	}
+	signed := sign(rsaKey)
+
+	tests := []struct {
+		name string
+		msg  string
+		want domain.DKIMStatus
+	}{
+		{name: "Unsigned", msg: message, want: domain.DKIMNone},
+		{name: "BodyChanged", msg: strings.Replace(signed, "world", "World", 1), want: domain.DKIMFail},
+		{name: "SubjectChanged", msg: strings.Replace(signed, "Subject: Hello", "Subject: Hi", 1), want: domain.DKIMFail},
+		{name: "FromAdded", msg: "From: admin@flintmail.ru\r\n" + signed, want: domain.DKIMFail},
+		{name: "UnsignedHeaderChanged", msg: strings.Replace(signed, "X-Mailer: test", "X-Mailer: other", 1), want: domain.DKIMPass},
+		{name: "NoKey", msg: sign(Key{Domain: "flintmail.ru", Selector: "missing", Signer: rsaKey.Signer}), want: domain.DKIMPermError},
+		{name: "RevokedKey", msg: sign(Key{Domain: "flintmail.ru", Selector: "revoked", Signer: rsaKey.Signer}), want: domain.DKIMPermError},
+		{name: "DNSFailure", msg: sign(Key{Domain: "flintmail.ru", Selector: "down", Signer: rsaKey.Signer}), want: domain.DKIMTempError},
+		{name: "WrongKey", msg: sign(Key{Domain: "flintmail.ru", Selector: "ed", Signer: rsaKey.Signer}), want: domain.DKIMPermError},
+		{name: "Expired", msg: strings.Replace(signed, "v=1;", "v=1; x=1;", 1), want: domain.DKIMFail},
+		{name: "Malformed", msg: "DKIM-Signature: v=1; a\r\n" + message, want: domain.DKIMPermError},
+		{name: "UnsupportedAlgorithm", msg: strings.Replace(signed, "a=rsa-sha256", "a=rsa-sha1", 1), want: domain.DKIMPermError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := verifier.Verify(context.Background(), []byte(tt.msg))
+			if len(results) == 0 || results[0].Status != tt.want {
+				t.Errorf("Verify() = %+v, want %s", results, tt.want)
+			}
+		})
+	}
+}
+
+func TestSign_NoFrom(t *testing.T) {
+	rsaKey, _, _ := newKeys(t)
+
+	if _, err := Sign([]byte("Subject: Hello\r\n\r\nbody"), rsaKey, now); err != ErrNoFrom {
+		t.Errorf("Sign() error = %v, want ErrNoFrom", err)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	results := []Result{
+		{Status: domain.DKIMFail, Domain: "flintmail.ru"},
+		{Status: domain.DKIMPass, Domain: "mailer.example.com"},
+		{Status: domain.DKIMPass, Domain: "flintmail.ru"},
+	}
+
+	if got := Summarize(results, "flintmail.ru"); got != (domain.DKIMResult{Status: domain.DKIMPass, Domain: "flintmail.ru"}) {
+		t.Errorf("Summarize() = %+v", got)
+	}
+	if got := Summarize(results[:2], "flintmail.ru"); got != (domain.DKIMResult{Status: domain.DKIMPass, Domain: "mailer.example.com"}) {
+		t.Errorf("Summarize() = %+v", got)
+	}
+	if got := Summarize([]Result{{Status: domain.DKIMNone}}, "flintmail.ru"); got.Status != domain.DKIMNone {
+		t.Errorf("Summarize() = %+v", got)
+	}
+	if got := FromDomain([]byte(message)); got != "flintmail.ru" {
+		t.Errorf("FromDomain() = %q", got)
+	}
+}
+
+func TestKeyring(t *testing.T) {
+	rsaKey, edKey, records := newKeys(t)
+	edKey.ActiveFrom = now.Add(24 * time.Hour)
+	keyring := NewKeyring(rsaKey, edKey)
+	keyring.now = func() time.Time { return now }
+
+	signed, err := keyring.Sign("FlintMail.ru", []byte(message))
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if results := newVerifier(records).Verify(context.Background(), signed); results[0].Selector != "rsa" {
+		t.Errorf("signed with %q before rotation, want rsa", results[0].Selector)
+	}
+
+	keyring.now = func() time.Time { return now.Add(25 * time.Hour) }
+	if key, _ := keyring.ActiveKey("flintmail.ru"); key.Selector != "ed" {
+		t.Errorf("ActiveKey() = %q after rotation, want ed", key.Selector)
+	}
+
+	unsigned, err := keyring.Sign("example.com", []byte(message))
+	if err != nil || !bytes.Equal(unsigned, []byte(message)) {
+		t.Errorf("Sign() for a domain without keys = %q, %v", unsigned, err)
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	rsaKey, edKey, _ := newKeys(t)
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(edKey.Signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs1 := x509.MarshalPKCS1PrivateKey(rsaKey.Signer.(*rsa.PrivateKey))
+
+	tests := map[string]struct {
+		block *pem.Block
+		want  crypto.PublicKey
+	}{
+		"Ed25519PKCS8": {block: &pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}, want: edKey.Signer.Public()},
+		"RSAPKCS1":     {block: &pem.Block{Type: "RSA PRIVATE KEY", Bytes: pkcs1}, want: rsaKey.Signer.Public()},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			signer, err := ParsePrivateKey(pem.EncodeToMemory(tt.block))
+			if err != nil {
+				t.Fatalf("ParsePrivateKey() error = %v", err)
+			}
+			if !signer.Public().(interface{ Equal(crypto.PublicKey) bool }).Equal(tt.want) {
+				t.Errorf("ParsePrivateKey() returned a different key")
+			}
+		})
+	}
+
+	if _, err := ParsePrivateKey([]byte("not a key")); err == nil {
+		t.Error("ParsePrivateKey() accepted garbage")
+	}
+}
@@ -0,0 +1,225 @@
+package dkim
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// minRSABits - минимальная длина ключа RSA (RFC 8301, 3.2)
+const minRSABits = 1024
+
+// signatureLineLength - длина строки при переносе значений h= и b=
+const signatureLineLength = 72
+
+// signedHeaders - поля, которые подписываются, если они есть в письме
+var signedHeaders = []string{
+	"From", "Reply-To", "Subject", "Date", "To", "Cc", "Message-Id", "In-Reply-To", "References",
+	"Mime-Version", "Content-Type", "Content-Transfer-Encoding", "Auto-Submitted",
+}
+
+// Key - ключ подписи домена
+type Key struct {
+	Domain   string
+	Selector string
+	// Signer - *rsa.PrivateKey или ed25519.PrivateKey
+	Signer crypto.Signer
+	// ActiveFrom - с какого момента ключ используется для подписи. Новый ключ
+	// заводится заранее, чтобы его DNS-запись успела разойтись
+	ActiveFrom time.Time
+}
+
+func (k Key) algorithm() (string, error) {
+	switch key := k.Signer.(type) {
+	case *rsa.PrivateKey:
+		if key.N.BitLen() < minRSABits {
+			return "", fmt.Errorf("%w: RSA key shorter than %d bits", ErrUnsupportedKey, minRSABits)
+		}
+		return AlgorithmRSA, nil
+	case ed25519.PrivateKey:
+		return AlgorithmEd25519, nil
+	}
+	return "", ErrUnsupportedKey
+}
+
+// DNSRecord возвращает значение TXT-записи <selector>._domainkey.<domain> для ключа
+func (k Key) DNSRecord() (string, error) {
+	algorithm, err := k.algorithm()
+	if err != nil {
+		return "", err
+	}
+
+	if algorithm == AlgorithmEd25519 {
+		public := k.Signer.Public().(ed25519.PublicKey)
+		return "v=DKIM1; k=ed25519; p=" + base64.StdEncoding.EncodeToString(public), nil
+	}
+	public, err := x509.MarshalPKIXPublicKey(k.Signer.Public())
+	if err != nil {
+		return "", err
+	}
+	return "v=DKIM1; k=rsa; p=" + base64.StdEncoding.EncodeToString(public), nil
+}
+
+// ParsePrivateKey читает ключ RSA (PKCS #1 или PKCS #8) или Ed25519 (PKCS #8) в формате PEM
+func ParsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("dkim: no PEM block found")
+	}
+
+	if block.Type == "RSA PRIVATE KEY" {
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return key, nil
+	case ed25519.PrivateKey:
+		return key, nil
+	}
+	return nil, ErrUnsupportedKey
+}
+
+// Sign подписывает письмо ключом key с каноникализацией relaxed/relaxed
+// и возвращает его с полем DKIM-Signature в начале
+func Sign(msg []byte, key Key, now time.Time) ([]byte, error) {
+	algorithm, err := key.algorithm()
+	if err != nil {
+		return nil, err
+	}
+
+	headers, body := splitMessage(msg)
+
+	var names []string
+	for _, name := range signedHeaders {
+		for _, h := range headers {
+			if strings.EqualFold(h.name, name) {
+				names = append(names, strings.ToLower(name))
+			}
+		}
+	}
+	if !containsName(names, "from") {
+		return nil, ErrNoFrom
+	}
+	// Лишний from в h= не дает добавить к подписанному письму второе поле From
+	names = append(names, "from")
+
+	bodyHash := sha256.Sum256(canonicalBody(body, canonicalRelaxed))
+	value := fmt.Sprintf(" v=1; a=%s; c=relaxed/relaxed;\r\n\td=%s; s=%s; t=%d;\r\n\th=%s;\r\n\tbh=%s;\r\n\tb=",
+		algorithm, key.Domain, key.Selector, now.Unix(),
+		foldNames(names), base64.StdEncoding.EncodeToString(bodyHash[:]))
+
+	var data strings.Builder
+	for _, h := range selectHeaders(headers, names) {
+		data.WriteString(canonicalHeader(h, canonicalRelaxed))
+	}
+	signature := header{name: signatureHeader, raw: signatureHeader + ":" + value}
+	data.WriteString(strings.TrimSuffix(canonicalHeader(signature, canonicalRelaxed), "\r\n"))
+
+	hash := sha256.Sum256([]byte(data.String()))
+	var opts crypto.SignerOpts = crypto.SHA256
+	if algorithm == AlgorithmEd25519 {
+		// Ed25519 подписывает хеш SHA-256 как сообщение (RFC 8463, 3)
+		opts = crypto.Hash(0)
+	}
+	sig, err := key.Signer.Sign(rand.Reader, hash[:], opts)
+	if err != nil {
+		return nil, fmt.Errorf("dkim: sign: %w", err)
+	}
+
+	var out strings.Builder
+	out.WriteString(signature.raw)
+	encoded := base64.StdEncoding.EncodeToString(sig)
+	for len(encoded) > signatureLineLength {
+		out.WriteString(encoded[:signatureLineLength] + "\r\n\t ")
+		encoded = encoded[signatureLineLength:]
+	}
+	out.WriteString(encoded + "\r\n")
+	for _, h := range headers {
+		out.WriteString(h.raw)
+	}
+	out.WriteString("\r\n")
+
+	return append([]byte(out.String()), body...), nil
+}
+
+// Keyring хранит ключи подписи по доменам. Для каждого домена используется
+// самый новый из уже действующих ключей, что позволяет менять селекторы без простоя
+type Keyring struct {
+	keys map[string][]Key
+	now  func() time.Time
+}
+
+func NewKeyring(keys ...Key) *Keyring {
+	keyring := &Keyring{keys: make(map[string][]Key), now: time.Now}
+	for _, key := range keys {
+		domainName := strings.ToLower(key.Domain)
+		keyring.keys[domainName] = append(keyring.keys[domainName], key)
+	}
+	for _, domainKeys := range keyring.keys {
+		sort.SliceStable(domainKeys, func(i, j int) bool { return domainKeys[i].ActiveFrom.After(domainKeys[j].ActiveFrom) })
+	}
+	return keyring
+}
+
+// ActiveKey возвращает ключ, которым сейчас подписываются письма домена
+func (k *Keyring) ActiveKey(domainName string) (Key, bool) {
+	now := k.now()
+	for _, key := range k.keys[strings.ToLower(domainName)] {
+		if !key.ActiveFrom.After(now) {
+			return key, true
+		}
+	}
+	return Key{}, false
+}
+
+// Sign подписывает письмо действующим ключом домена. Письма доменов без ключа
+// возвращаются без изменений
+func (k *Keyring) Sign(domainName string, msg []byte) ([]byte, error) {
+	key, ok := k.ActiveKey(domainName)
+	if !ok {
+		return msg, nil
+	}
+	return Sign(msg, key, k.now())
+}
+
+// foldNames записывает список h= в несколько строк, чтобы поле оставалось читаемым
+func foldNames(names []string) string {
+	var b strings.Builder
+	lineLength := len("\th=")
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(':')
+			lineLength++
+			if lineLength+len(name) > signatureLineLength {
+				b.WriteString("\r\n\t ")
+				lineLength = 2
+			}
+		}
+		b.WriteString(name)
+		lineLength += len(name)
+	}
+	return b.String()
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
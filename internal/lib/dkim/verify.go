@@ -0,0 +1,320 @@
+package dkim
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSignatures - сколько подписей одного письма проверяется, остальные игнорируются
+const maxSignatures = 5
+
+// Result - результат проверки одной подписи
+type Result struct {
+	Status   domain.DKIMStatus
+	Domain   string
+	Selector string
+	// Reason объясняет результат, отличный от pass
+	Reason string
+}
+
+// Verifier проверяет DKIM-подписи входящих писем
+type Verifier struct {
+	lookupTXT func(ctx context.Context, name string) ([]string, error)
+	now       func() time.Time
+}
+
+// NewVerifier создает проверку подписей. lookupTXT = nil - ключи берутся из DNS
+func NewVerifier(lookupTXT func(ctx context.Context, name string) ([]string, error)) *Verifier {
+	if lookupTXT == nil {
+		lookupTXT = net.DefaultResolver.LookupTXT
+	}
+	return &Verifier{lookupTXT: lookupTXT, now: time.Now}
+}
+
+// Verify проверяет все подписи письма. Для неподписанного письма возвращается
+// один результат со статусом none
+func (v *Verifier) Verify(ctx context.Context, msg []byte) []Result {
+	headers, body := splitMessage(msg)
+
+	var results []Result
+	for _, h := range headers {
+		if !strings.EqualFold(h.name, signatureHeader) {
+			continue
+		}
+		if len(results) == maxSignatures {
+			break
+		}
+		results = append(results, v.verifySignature(ctx, headers, body, h))
+	}
+
+	if len(results) == 0 {
+		return []Result{{Status: domain.DKIMNone}}
+	}
+	return results
+}
+
+// Summarize сводит результаты проверки к одному. Лучше всего успешная подпись
+// домена из поля From, затем любая успешная, затем временная ошибка
+func Summarize(results []Result, fromDomain string) domain.DKIMResult {
+	rank := func(r Result) int {
+		switch r.Status {
+		case domain.DKIMPass:
+			if strings.EqualFold(r.Domain, fromDomain) {
+				return 5
+			}
+			return 4
+		case domain.DKIMTempError:
+			return 3
+		case domain.DKIMFail:
+			return 2
+		case domain.DKIMPermError:
+			return 1
+		}
+		return 0
+	}
+
+	best := Result{Status: domain.DKIMNone}
+	for _, r := range results {
+		if rank(r) > rank(best) {
+			best = r
+		}
+	}
+	return domain.DKIMResult{Status: best.Status, Domain: best.Domain}
+}
+
+// FromDomain возвращает домен адреса из поля From письма
+func FromDomain(msg []byte) string {
+	headers, _ := splitMessage(msg)
+	for _, h := range headers {
+		if !strings.EqualFold(h.name, "From") {
+			continue
+		}
+		address, err := mail.ParseAddress(strings.TrimSpace(unfold(h.value())))
+		if err != nil {
+			return ""
+		}
+		_, domainName, _ := strings.Cut(address.Address, "@")
+		return strings.ToLower(domainName)
+	}
+	return ""
+}
+
+func (v *Verifier) verifySignature(ctx context.Context, headers []header, body []byte, sigHeader header) Result {
+	tags, err := parseTags(sigHeader.value())
+	if err != nil {
+		return Result{Status: domain.DKIMPermError, Reason: err.Error()}
+	}
+
+	result := Result{Domain: strings.ToLower(tags["d"]), Selector: tags["s"]}
+	permError := func(reason string) Result {
+		result.Status = domain.DKIMPermError
+		result.Reason = reason
+		return result
+	}
+	fail := func(reason string) Result {
+		result.Status = domain.DKIMFail
+		result.Reason = reason
+		return result
+	}
+
+	for _, name := range []string{"v", "a", "b", "bh", "d", "h", "s"} {
+		if tags[name] == "" {
+			return permError("missing tag " + name)
+		}
+	}
+	if tags["v"] != "1" {
+		return permError("unsupported version")
+	}
+	algorithm := strings.ToLower(tags["a"])
+	if algorithm != AlgorithmRSA && algorithm != AlgorithmEd25519 {
+		return permError("unsupported algorithm " + algorithm)
+	}
+
+	headerCanon, bodyCanon := canonicalSimple, canonicalSimple
+	if c := strings.ToLower(tags["c"]); c != "" {
+		var ok bool
+		headerCanon, bodyCanon, ok = strings.Cut(c, "/")
+		if !ok {
+			bodyCanon = canonicalSimple
+		}
+		if !validCanonicalization(headerCanon) || !validCanonicalization(bodyCanon) {
+			return permError("unsupported canonicalization " + c)
+		}
+	}
+
+	names := strings.Split(tags["h"], ":")
+	for i := range names {
+		names[i] = strings.TrimSpace(names[i])
+	}
+	if !containsName(names, "from") {
+		return permError("From header is not signed")
+	}
+
+	if identity := tags["i"]; identity != "" {
+		_, identityDomain, _ := strings.Cut(identity, "@")
+		identityDomain = strings.ToLower(identityDomain)
+		if identityDomain != result.Domain && !strings.HasSuffix(identityDomain, "."+result.Domain) {
+			return permError("identity is not in the signing domain")
+		}
+	}
+	if expires := tags["x"]; expires != "" {
+		timestamp, err := strconv.ParseInt(expires, 10, 64)
+		if err != nil {
+			return permError("malformed expiration")
+		}
+		if v.now().Unix() > timestamp {
+			return fail("signature expired")
+		}
+	}
+
+	canonical := canonicalBody(body, bodyCanon)
+	if length := tags["l"]; length != "" {
+		limit, err := strconv.Atoi(length)
+		if err != nil || limit < 0 || limit > len(canonical) {
+			return permError("invalid body length")
+		}
+		canonical = canonical[:limit]
+	}
+	bodyHash := sha256.Sum256(canonical)
+	if base64.StdEncoding.EncodeToString(bodyHash[:]) != removeSpace(tags["bh"]) {
+		return fail("body hash did not verify")
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(removeSpace(tags["b"]))
+	if err != nil {
+		return permError("malformed signature")
+	}
+
+	publicKey, status, err := v.publicKey(ctx, result.Domain, result.Selector, algorithm)
+	if err != nil {
+		result.Status = status
+		result.Reason = err.Error()
+		return result
+	}
+
+	var data strings.Builder
+	for _, h := range selectHeaders(headers, names) {
+		data.WriteString(canonicalHeader(h, headerCanon))
+	}
+	unsigned := header{name: sigHeader.name, raw: stripSignature(sigHeader.raw)}
+	data.WriteString(strings.TrimSuffix(canonicalHeader(unsigned, headerCanon), "\r\n"))
+	hash := sha256.Sum256([]byte(data.String()))
+
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		if rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], signature) != nil {
+			return fail("signature did not verify")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, hash[:], signature) {
+			return fail("signature did not verify")
+		}
+	}
+
+	result.Status = domain.DKIMPass
+	return result
+}
+
+// publicKey получает ключ из TXT-записи <selector>._domainkey.<domain>
+func (v *Verifier) publicKey(ctx context.Context, domainName, selector, algorithm string) (crypto.PublicKey, domain.DKIMStatus, error) {
+	name := selector + "._domainkey." + domainName
+	records, err := v.lookupTXT(ctx, name)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return nil, domain.DKIMPermError, fmt.Errorf("no key for signature at %s", name)
+		}
+		return nil, domain.DKIMTempError, fmt.Errorf("key lookup failed: %w", err)
+	}
+
+	for _, record := range records {
+		tags, err := parseTags(record)
+		if err != nil || (tags["v"] != "" && tags["v"] != "DKIM1") {
+			continue
+		}
+
+		keyType := strings.ToLower(tags["k"])
+		if keyType == "" {
+			keyType = "rsa"
+		}
+		if !strings.HasPrefix(algorithm, keyType+"-") {
+			return nil, domain.DKIMPermError, fmt.Errorf("key type %s does not match algorithm %s", keyType, algorithm)
+		}
+		if hashes := tags["h"]; hashes != "" && !containsName(strings.Split(hashes, ":"), "sha256") {
+			return nil, domain.DKIMPermError, errors.New("key does not allow sha256")
+		}
+
+		data, err := base64.StdEncoding.DecodeString(removeSpace(tags["p"]))
+		if err != nil {
+			return nil, domain.DKIMPermError, errors.New("malformed public key")
+		}
+		if len(data) == 0 {
+			return nil, domain.DKIMPermError, errors.New("key revoked")
+		}
+
+		if keyType == "ed25519" {
+			if len(data) != ed25519.PublicKeySize {
+				return nil, domain.DKIMPermError, errors.New("malformed public key")
+			}
+			return ed25519.PublicKey(data), "", nil
+		}
+
+		var key *rsa.PublicKey
+		if parsed, err := x509.ParsePKIXPublicKey(data); err == nil {
+			key, _ = parsed.(*rsa.PublicKey)
+		} else {
+			key, _ = x509.ParsePKCS1PublicKey(data)
+		}
+		if key == nil {
+			return nil, domain.DKIMPermError, errors.New("malformed public key")
+		}
+		if key.N.BitLen() < minRSABits {
+			return nil, domain.DKIMPermError, errors.New("public key is too short")
+		}
+		return key, "", nil
+	}
+
+	return nil, domain.DKIMPermError, fmt.Errorf("no valid key record at %s", name)
+}
+
+// stripSignature убирает значение тега b= из поля DKIM-Signature, сохраняя остальное как есть
+func stripSignature(raw string) string {
+	name, value, _ := strings.Cut(raw, ":")
+
+	var b strings.Builder
+	b.WriteString(name + ":")
+	for i, tag := range strings.Split(value, ";") {
+		if i > 0 {
+			b.WriteByte(';')
+		}
+		tagName, _, ok := strings.Cut(tag, "=")
+		if ok && strings.TrimSpace(tagName) == "b" {
+			eq := strings.Index(tag, "=")
+			b.WriteString(tag[:eq+1])
+			// Завершающий CRLF поля сохраняется
+			if strings.HasSuffix(tag, "\r\n") {
+				b.WriteString("\r\n")
+			}
+			continue
+		}
+		b.WriteString(tag)
+	}
+	return b.String()
+}
+
+func validCanonicalization(method string) bool {
+	return method == canonicalSimple || method == canonicalRelaxed
+}
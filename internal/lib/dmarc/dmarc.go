@@ -0,0 +1,244 @@
+// Package dmarc проверяет, что домен из поля From подтвержден DKIM или SPF,
+// и находит политику, которую домен публикует для писем без подтверждения (RFC 7489)
+package dmarc
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+var (
+	ErrNotDMARC      = errors.New("dmarc: not a DMARC record")
+	ErrInvalidPolicy = errors.New("dmarc: invalid policy")
+)
+
+// Resolver - запросы DNS, нужные для проверки. Ему удовлетворяет *net.Resolver
+type Resolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// Identifiers - подтвержденные домены письма
+type Identifiers struct {
+	// FromDomain - домен адреса из поля From
+	FromDomain string
+	// DKIMDomains - домены из тега d= подписей, прошедших проверку
+	DKIMDomains []string
+	// SPFDomain - домен, прошедший SPF: из MAIL FROM или, для пустого MAIL FROM, из HELO.
+	// Пустой - SPF не пройден
+	SPFDomain string
+}
+
+// Record - DMARC-запись домена
+type Record struct {
+	Policy          domain.DMARCPolicy
+	SubdomainPolicy domain.DMARCPolicy
+	// Строгое выравнивание требует совпадения доменов, а не только организационных доменов
+	StrictDKIM bool
+	StrictSPF  bool
+	// Percent - к какой доле писем без подтверждения применяется политика
+	Percent int
+}
+
+// Result - результат проверки
+type Result struct {
+	domain.DMARCResult
+	// PolicyDomain - домен, в котором найдена запись
+	PolicyDomain string
+	Reason       string
+}
+
+// Checker проверяет письма по DMARC-записям доменов отправителей
+type Checker struct {
+	resolver Resolver
+	// sample возвращает случайное число от 0 до n-1 для выборки по pct
+	sample func(n int) int
+}
+
+// NewChecker создает проверку DMARC. resolver = nil - записи берутся из DNS
+func NewChecker(resolver Resolver) *Checker {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	return &Checker{resolver: resolver, sample: rand.IntN}
+}
+
+// Check проверяет выравнивание доменов письма и находит политику домена из поля From
+func (c *Checker) Check(ctx context.Context, ids Identifiers) Result {
+	fromDomain := strings.ToLower(strings.TrimSuffix(ids.FromDomain, "."))
+	if fromDomain == "" {
+		return Result{DMARCResult: domain.DMARCResult{Status: domain.DMARCPermError}, Reason: "no From domain"}
+	}
+
+	record, policyDomain, err := c.lookup(ctx, fromDomain)
+	if err != nil {
+		// Выравнивание проверяется и без записи: по нему письмо помечается как подтвержденное
+		result := Result{Reason: err.Error()}
+		result.Status = domain.DMARCTempError
+		result.Aligned = aligned(fromDomain, ids, Record{})
+		return result
+	}
+	if record == nil {
+		result := Result{Reason: "no DMARC record for " + fromDomain}
+		result.Status = domain.DMARCNone
+		result.Aligned = aligned(fromDomain, ids, Record{})
+		return result
+	}
+
+	result := Result{PolicyDomain: policyDomain}
+	result.Aligned = aligned(fromDomain, ids, *record)
+	result.Policy = record.Policy
+	if policyDomain != fromDomain && record.SubdomainPolicy != "" {
+		result.Policy = record.SubdomainPolicy
+	}
+
+	if result.Aligned {
+		result.Status = domain.DMARCPass
+		return result
+	}
+	result.Status = domain.DMARCFail
+	result.Reason = "From domain is not aligned with DKIM or SPF"
+
+	// Письмо вне выборки pct получает политику на ступень мягче (RFC 7489, 6.6.4)
+	if record.Percent < 100 && c.sample(100) >= record.Percent {
+		switch result.Policy {
+		case domain.DMARCPolicyReject:
+			result.Policy = domain.DMARCPolicyQuarantine
+		case domain.DMARCPolicyQuarantine:
+			result.Policy = domain.DMARCPolicyNone
+		}
+	}
+	return result
+}
+
+// lookup ищет запись в _dmarc.<домен From>, затем в _dmarc.<организационный домен> (RFC 7489, 6.6.3)
+func (c *Checker) lookup(ctx context.Context, fromDomain string) (*Record, string, error) {
+	record, err := c.record(ctx, fromDomain)
+	if record != nil || err != nil {
+		return record, fromDomain, err
+	}
+
+	orgDomain := OrganizationalDomain(fromDomain)
+	if orgDomain == fromDomain {
+		return nil, "", nil
+	}
+	record, err = c.record(ctx, orgDomain)
+	return record, orgDomain, err
+}
+
+func (c *Checker) record(ctx context.Context, domainName string) (*Record, error) {
+	name := "_dmarc." + domainName
+	records, err := c.resolver.LookupTXT(ctx, name)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("DNS lookup for %s failed", name)
+	}
+
+	var found []Record
+	for _, txt := range records {
+		record, err := ParseRecord(txt)
+		if err == nil {
+			found = append(found, record)
+		}
+	}
+	// Несколько записей - то же, что ни одной
+	if len(found) != 1 {
+		return nil, nil
+	}
+	return &found[0], nil
+}
+
+// ParseRecord разбирает DMARC-запись вида "v=DMARC1; p=reject; ..."
+func ParseRecord(s string) (Record, error) {
+	record := Record{Percent: 100}
+	var hasReport bool
+
+	for i, part := range strings.Split(s, ";") {
+		name, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		name, value = strings.ToLower(strings.TrimSpace(name)), strings.TrimSpace(value)
+		if i == 0 {
+			if name != "v" || value != "DMARC1" {
+				return Record{}, ErrNotDMARC
+			}
+			continue
+		}
+
+		switch name {
+		case "p":
+			record.Policy = parsePolicy(value)
+		case "sp":
+			record.SubdomainPolicy = parsePolicy(value)
+		case "adkim":
+			record.StrictDKIM = strings.EqualFold(value, "s")
+		case "aspf":
+			record.StrictSPF = strings.EqualFold(value, "s")
+		case "pct":
+			if percent, err := strconv.Atoi(value); err == nil && percent >= 0 && percent <= 100 {
+				record.Percent = percent
+			}
+		case "rua":
+			hasReport = value != ""
+		}
+	}
+
+	// Запись без корректной политики, но с адресом для отчетов, считается записью
+	// с p=none (RFC 7489, 6.6.3)
+	if record.Policy == "" {
+		if !hasReport {
+			return Record{}, ErrInvalidPolicy
+		}
+		record.Policy = domain.DMARCPolicyNone
+	}
+	return record, nil
+}
+
+func parsePolicy(value string) domain.DMARCPolicy {
+	switch policy := domain.DMARCPolicy(strings.ToLower(value)); policy {
+	case domain.DMARCPolicyNone, domain.DMARCPolicyQuarantine, domain.DMARCPolicyReject:
+		return policy
+	}
+	return ""
+}
+
+// OrganizationalDomain возвращает домен, зарегистрированный владельцем имени,
+// по списку публичных суффиксов: для mail.example.co.uk это example.co.uk
+func OrganizationalDomain(name string) string {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	orgDomain, err := publicsuffix.EffectiveTLDPlusOne(name)
+	if err != nil {
+		return name
+	}
+	return orgDomain
+}
+
+// aligned проверяет, что хотя бы один подтвержденный домен совпадает с доменом
+// из поля From с точностью до организационного домена или полностью при строгом выравнивании
+func aligned(fromDomain string, ids Identifiers, record Record) bool {
+	match := func(identifier string, strict bool) bool {
+		identifier = strings.ToLower(strings.TrimSuffix(identifier, "."))
+		if identifier == "" {
+			return false
+		}
+		if strict {
+			return identifier == fromDomain
+		}
+		return OrganizationalDomain(identifier) == OrganizationalDomain(fromDomain)
+	}
+
+	for _, dkimDomain := range ids.DKIMDomains {
+		if match(dkimDomain, record.StrictDKIM) {
+			return true
+		}
+	}
+	return match(ids.SPFDomain, record.StrictSPF)
+}
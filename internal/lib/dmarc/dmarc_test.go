@@ -0,0 +1,176 @@
+package dmarc
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/lib/dnstest"
+	"context"
+	"testing"
+)
+
+func newChecker(sample int) *Checker {
+	checker := NewChecker(&dnstest.Zone{
+		TXT: map[string][]string{
+			"_dmarc.example.com":     {"v=DMARC1; p=reject; sp=quarantine; rua=mailto:dmarc@example.com"},
+			"_dmarc.strict.com":      {"v=DMARC1; p=quarantine; adkim=s; aspf=s"},
+			"_dmarc.sample.com":      {"v=DMARC1; p=reject; pct=20"},
+			"_dmarc.monitor.com":     {"v=DMARC1; p=none"},
+			"_dmarc.example.co.uk":   {"v=DMARC1; p=quarantine"},
+			"_dmarc.double.com":      {"v=DMARC1; p=reject", "v=DMARC1; p=none"},
+			"_dmarc.reportonly.com":  {"v=DMARC1; p=bogus; rua=mailto:r@reportonly.com"},
+			"_dmarc.own.example.com": {"v=DMARC1; p=none"},
+			"_dmarc.spf-record.com":  {"v=spf1 -all"},
+		},
+		Broken: map[string]bool{"_dmarc.down.com": true},
+	})
+	checker.sample = func(n int) int { return sample }
+	return checker
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		ids    Identifiers
+		sample int
+		want   domain.DMARCResult
+	}{
+		{
+			name: "DKIMAligned",
+			ids:  Identifiers{FromDomain: "example.com", DKIMDomains: []string{"other.net", "example.com"}},
+			want: domain.DMARCResult{Status: domain.DMARCPass, Policy: domain.DMARCPolicyReject, Aligned: true},
+		},
+		{
+			name: "DKIMRelaxedAlignment",
+			ids:  Identifiers{FromDomain: "example.com", DKIMDomains: []string{"mail.example.com"}},
+			want: domain.DMARCResult{Status: domain.DMARCPass, Policy: domain.DMARCPolicyReject, Aligned: true},
+		},
+		{
+			name: "SPFAligned",
+			ids:  Identifiers{FromDomain: "example.com", SPFDomain: "bounce.example.com"},
+			want: domain.DMARCResult{Status: domain.DMARCPass, Policy: domain.DMARCPolicyReject, Aligned: true},
+		},
+		{
+			name: "NotAligned",
+			ids:  Identifiers{FromDomain: "example.com", DKIMDomains: []string{"example.net"}, SPFDomain: "example.org"},
+			want: domain.DMARCResult{Status: domain.DMARCFail, Policy: domain.DMARCPolicyReject},
+		},
+		{
+			name: "SubdomainPolicy",
+			ids:  Identifiers{FromDomain: "news.example.com"},
+			want: domain.DMARCResult{Status: domain.DMARCFail, Policy: domain.DMARCPolicyQuarantine},
+		},
+		{
+			name: "SubdomainOwnRecord",
+			ids:  Identifiers{FromDomain: "own.example.com"},
+			want: domain.DMARCResult{Status: domain.DMARCFail, Policy: domain.DMARCPolicyNone},
+		},
+		{
+			name: "StrictDKIM",
+			ids:  Identifiers{FromDomain: "strict.com", DKIMDomains: []string{"mail.strict.com"}, SPFDomain: "strict.com"},
+			want: domain.DMARCResult{Status: domain.DMARCPass, Policy: domain.DMARCPolicyQuarantine, Aligned: true},
+		},
+		{
+			name: "StrictNotAligned",
+			ids:  Identifiers{FromDomain: "strict.com", DKIMDomains: []string{"mail.strict.com"}, SPFDomain: "bounce.strict.com"},
+			want: domain.DMARCResult{Status: domain.DMARCFail, Policy: domain.DMARCPolicyQuarantine},
+		},
+		{
+			name:   "InSample",
+			ids:    Identifiers{FromDomain: "sample.com"},
+			sample: 19,
+			want:   domain.DMARCResult{Status: domain.DMARCFail, Policy: domain.DMARCPolicyReject},
+		},
+		{
+			name:   "OutOfSample",
+			ids:    Identifiers{FromDomain: "sample.com"},
+			sample: 20,
+			want:   domain.DMARCResult{Status: domain.DMARCFail, Policy: domain.DMARCPolicyQuarantine},
+		},
+		{
+			name: "PublicSuffix",
+			ids:  Identifiers{FromDomain: "shop.example.co.uk", DKIMDomains: []string{"co.uk"}},
+			want: domain.DMARCResult{Status: domain.DMARCFail, Policy: domain.DMARCPolicyQuarantine},
+		},
+		{
+			name: "Monitor",
+			ids:  Identifiers{FromDomain: "monitor.com"},
+			want: domain.DMARCResult{Status: domain.DMARCFail, Policy: domain.DMARCPolicyNone},
+		},
+		{
+			name: "NoRecordAligned",
+			ids:  Identifiers{FromDomain: "norecord.com", DKIMDomains: []string{"norecord.com"}},
+			want: domain.DMARCResult{Status: domain.DMARCNone, Aligned: true},
+		},
+		{
+			name: "NoRecord",
+			ids:  Identifiers{FromDomain: "norecord.com", SPFDomain: "other.com"},
+			want: domain.DMARCResult{Status: domain.DMARCNone},
+		},
+		{
+			name: "MultipleRecords",
+			ids:  Identifiers{FromDomain: "double.com"},
+			want: domain.DMARCResult{Status: domain.DMARCNone},
+		},
+		{
+			name: "NotDMARCRecord",
+			ids:  Identifiers{FromDomain: "spf-record.com"},
+			want: domain.DMARCResult{Status: domain.DMARCNone},
+		},
+		{
+			name: "InvalidPolicyWithReports",
+			ids:  Identifiers{FromDomain: "reportonly.com"},
+			want: domain.DMARCResult{Status: domain.DMARCFail, Policy: domain.DMARCPolicyNone},
+		},
+		{
+			name: "DNSFailure",
+			ids:  Identifiers{FromDomain: "down.com", DKIMDomains: []string{"down.com"}},
+			want: domain.DMARCResult{Status: domain.DMARCTempError, Aligned: true},
+		},
+		{
+			name: "NoFrom",
+			ids:  Identifiers{},
+			want: domain.DMARCResult{Status: domain.DMARCPermError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newChecker(tt.sample).Check(context.Background(), tt.ids)
+			if result.DMARCResult != tt.want {
+				t.Errorf("Check() = %+v, want %+v", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRecord(t *testing.T) {
+	record, err := ParseRecord("v=DMARC1;p=Quarantine; SP=reject ;adkim=s; pct=50")
+	if err != nil {
+		t.Fatalf("ParseRecord() error = %v", err)
+	}
+	want := Record{Policy: domain.DMARCPolicyQuarantine, SubdomainPolicy: domain.DMARCPolicyReject, StrictDKIM: true, Percent: 50}
+	if record != want {
+		t.Errorf("ParseRecord() = %+v, want %+v", record, want)
+	}
+
+	for _, s := range []string{"", "p=reject; v=DMARC1", "v=DMARC2; p=reject", "v=DMARC1", "v=DMARC1; p=block"} {
+		if _, err := ParseRecord(s); err == nil {
+			t.Errorf("ParseRecord(%q) error = nil", s)
+		}
+	}
+}
+
+func TestOrganizationalDomain(t *testing.T) {
+	tests := map[string]string{
+		"example.com":       "example.com",
+		"Mail.Example.COM.": "example.com",
+		"a.b.example.co.uk": "example.co.uk",
+		"flintmail.ru":      "flintmail.ru",
+		"mx.flintmail.ru":   "flintmail.ru",
+		"com":               "com",
+	}
+	for name, want := range tests {
+		if got := OrganizationalDomain(name); got != want {
+			t.Errorf("OrganizationalDomain(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Package dnstest - зона DNS в памяти для тестов проверок SPF, DKIM и DMARC
+package dnstest
+
+import (
+	"context"
+	"net"
+	"strings"
+)
+
+// Zone отвечает на запросы из своих записей. Имена записей задаются в нижнем
+// регистре без завершающей точки, имя без записей нужного типа не существует
+type Zone struct {
+	TXT map[string][]string
+	A   map[string][]string
+	MX  map[string][]string
+	PTR map[string][]string
+	// Broken - имена, запрос которых завершается временной ошибкой
+	Broken map[string]bool
+	// Queries - сколько запросов было сделано
+	Queries int
+}
+
+func (z *Zone) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	return z.lookup(z.TXT, name)
+}
+
+func (z *Zone) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	records, err := z.lookup(z.A, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var ips []net.IP
+	for _, record := range records {
+		ip := net.ParseIP(record)
+		isIPv4 := ip.To4() != nil
+		if network == "ip" || (network == "ip4" && isIPv4) || (network == "ip6" && !isIPv4) {
+			ips = append(ips, ip)
+		}
+	}
+	if len(ips) == 0 {
+		return nil, notFound(host)
+	}
+	return ips, nil
+}
+
+func (z *Zone) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	records, err := z.lookup(z.MX, name)
+	if err != nil {
+		return nil, err
+	}
+	mx := make([]*net.MX, 0, len(records))
+	for i, host := range records {
+		mx = append(mx, &net.MX{Host: host, Pref: uint16(10 * (i + 1))})
+	}
+	return mx, nil
+}
+
+func (z *Zone) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	return z.lookup(z.PTR, addr)
+}
+
+func (z *Zone) lookup(records map[string][]string, name string) ([]string, error) {
+	z.Queries++
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	if z.Broken[name] {
+		return nil, &net.DNSError{Err: "server misbehaving", Name: name, IsTemporary: true}
+	}
+	if values, ok := records[name]; ok {
+		return values, nil
+	}
+	return nil, notFound(name)
+}
+
+func notFound(name string) error {
+	return &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
@@ -0,0 +1,189 @@
+package events
+
+import (
+	"2025_2_a4code/internal/domain"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultHistorySize - сколько последних событий профиля хранится для продолжения потока
+	DefaultHistorySize = 256
+	// DefaultHistoryTTL - сколько хранится история профиля без подписчиков после его последнего события
+	DefaultHistoryTTL = 30 * time.Minute
+	// subscriberBuffer - размер очереди подписчика. Подписчик, не успевающий читать, отключается
+	subscriberBuffer = 64
+)
+
+// Hub раздает события почтовых ящиков подписчикам внутри процесса и хранит
+// последние события каждого профиля, чтобы клиент мог продолжить поток после переподключения
+type Hub struct {
+	mu          sync.Mutex
+	startID     int64
+	lastID      int64
+	historySize int
+	historyTTL  time.Duration
+	history     map[int64]*profileHistory
+	// Идентификатор последнего события из удаленных историй профилей
+	prunedID    int64
+	lastPrune   time.Time
+	subscribers map[int64]map[*Subscription]struct{}
+	now         func() time.Time
+}
+
+type profileHistory struct {
+	events []domain.MailEvent
+	// Идентификатор последнего вытесненного из истории события
+	evictedID int64
+	updatedAt time.Time
+}
+
+// Subscription - подписка на события профиля. Канал Events закрывается при отписке
+// или если подписчик не успевает читать события
+type Subscription struct {
+	Events <-chan domain.MailEvent
+
+	hub       *Hub
+	profileID int64
+	ch        chan domain.MailEvent
+	once      sync.Once
+}
+
+func NewHub(historySize int) *Hub {
+	if historySize <= 0 {
+		historySize = DefaultHistorySize
+	}
+	// Начинаем с текущего времени в микросекундах, чтобы идентификаторы
+	// не повторялись после перезапуска сервиса
+	startID := time.Now().UnixMicro()
+	return &Hub{
+		startID:     startID,
+		lastID:      startID,
+		historySize: historySize,
+		historyTTL:  DefaultHistoryTTL,
+		history:     make(map[int64]*profileHistory),
+		subscribers: make(map[int64]map[*Subscription]struct{}),
+		now:         time.Now,
+	}
+}
+
+// Publish присваивает событию идентификатор и рассылает его подписчикам профиля
+func (h *Hub) Publish(event domain.MailEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastID++
+	event.ID = h.lastID
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = h.now()
+	}
+
+	h.pruneLocked()
+
+	history := h.history[event.ProfileID]
+	if history == nil {
+		// Прежняя история профиля могла быть удалена, более ранние события продолжить нельзя
+		history = &profileHistory{evictedID: h.prunedID}
+		h.history[event.ProfileID] = history
+	}
+	history.updatedAt = h.now()
+	history.events = append(history.events, event)
+	if extra := len(history.events) - h.historySize; extra > 0 {
+		history.evictedID = history.events[extra-1].ID
+		history.events = append([]domain.MailEvent(nil), history.events[extra:]...)
+	}
+
+	for sub := range h.subscribers[event.ProfileID] {
+		select {
+		case sub.ch <- event:
+		default:
+			h.removeLocked(sub)
+		}
+	}
+}
+
+// Subscribe подписывает на события профиля. Если lastEventID не нулевой, сначала отдаются
+// события после него, а если они уже вытеснены из истории - событие EventResync
+func (h *Hub) Subscribe(profileID, lastEventID int64) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var backlog []domain.MailEvent
+	if lastEventID > 0 {
+		backlog = h.replayLocked(profileID, lastEventID)
+	}
+
+	ch := make(chan domain.MailEvent, subscriberBuffer+len(backlog))
+	for _, event := range backlog {
+		ch <- event
+	}
+
+	sub := &Subscription{Events: ch, hub: h, profileID: profileID, ch: ch}
+	if h.subscribers[profileID] == nil {
+		h.subscribers[profileID] = make(map[*Subscription]struct{})
+	}
+	h.subscribers[profileID][sub] = struct{}{}
+	return sub
+}
+
+// Close отписывает подписчика, повторный вызов безопасен
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.removeLocked(s)
+}
+
+func (h *Hub) replayLocked(profileID, lastEventID int64) []domain.MailEvent {
+	history := h.history[profileID]
+	// Историю нельзя продолжить, если событие выдано до перезапуска сервиса
+	// или более новые события уже вытеснены
+	evictedID := h.prunedID
+	if history != nil {
+		evictedID = history.evictedID
+	}
+	if lastEventID < h.startID || lastEventID > h.lastID || evictedID > lastEventID {
+		return []domain.MailEvent{{ID: h.lastID, Type: domain.EventResync, ProfileID: profileID, CreatedAt: h.now()}}
+	}
+	if history == nil {
+		return nil
+	}
+
+	for i, event := range history.events {
+		if event.ID > lastEventID {
+			backlog := make([]domain.MailEvent, len(history.events)-i)
+			copy(backlog, history.events[i:])
+			return backlog
+		}
+	}
+	return nil
+}
+
+// pruneLocked не чаще раза в historyTTL удаляет истории профилей без подписчиков,
+// в которых не было событий дольше historyTTL
+func (h *Hub) pruneLocked() {
+	now := h.now()
+	if now.Sub(h.lastPrune) < h.historyTTL {
+		return
+	}
+	h.lastPrune = now
+
+	for profileID, history := range h.history {
+		if len(h.subscribers[profileID]) > 0 || now.Sub(history.updatedAt) < h.historyTTL {
+			continue
+		}
+		if last := history.events[len(history.events)-1].ID; last > h.prunedID {
+			h.prunedID = last
+		}
+		delete(h.history, profileID)
+	}
+}
+
+func (h *Hub) removeLocked(sub *Subscription) {
+	sub.once.Do(func() {
+		delete(h.subscribers[sub.profileID], sub)
+		if len(h.subscribers[sub.profileID]) == 0 {
+			delete(h.subscribers, sub.profileID)
+		}
+		close(sub.ch)
+	})
+}
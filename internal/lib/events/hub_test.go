@@ -0,0 +1,138 @@
+package events
+
+import (
+	"2025_2_a4code/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receive(t *testing.T, sub *Subscription) domain.MailEvent {
+	t.Helper()
+	select {
+	case event, ok := <-sub.Events:
+		require.True(t, ok, "subscription closed")
+		return event
+	default:
+		t.Fatal("no event")
+	}
+	return domain.MailEvent{}
+}
+
+func TestHub_PublishSubscribe(t *testing.T) {
+	hub := NewHub(0)
+	sub := hub.Subscribe(1, 0)
+	other := hub.Subscribe(2, 0)
+	defer sub.Close()
+	defer other.Close()
+
+	hub.Publish(domain.MailEvent{Type: domain.EventNewMessage, ProfileID: 1, MessageID: 10})
+	hub.Publish(domain.MailEvent{Type: domain.EventUnreadCount, ProfileID: 1, Unread: 3})
+
+	first := receive(t, sub)
+	second := receive(t, sub)
+	assert.Equal(t, int64(10), first.MessageID)
+	assert.Equal(t, domain.EventUnreadCount, second.Type)
+	assert.Greater(t, second.ID, first.ID)
+	assert.False(t, first.CreatedAt.IsZero())
+	assert.Empty(t, other.Events)
+}
+
+func TestHub_Resume(t *testing.T) {
+	hub := NewHub(0)
+	hub.Publish(domain.MailEvent{Type: domain.EventNewMessage, ProfileID: 1, MessageID: 10})
+	hub.Publish(domain.MailEvent{Type: domain.EventNewMessage, ProfileID: 2, MessageID: 11})
+	hub.Publish(domain.MailEvent{Type: domain.EventNewMessage, ProfileID: 1, MessageID: 12})
+
+	first := hub.Subscribe(1, 0)
+	assert.Empty(t, first.Events)
+	first.Close()
+
+	sub := hub.Subscribe(1, hub.startID+1)
+	defer sub.Close()
+	event := receive(t, sub)
+	assert.Equal(t, int64(12), event.MessageID)
+	assert.Empty(t, sub.Events)
+
+	upToDate := hub.Subscribe(1, hub.lastID)
+	defer upToDate.Close()
+	assert.Empty(t, upToDate.Events)
+}
+
+func TestHub_Resync(t *testing.T) {
+	tests := []struct {
+		name        string
+		lastEventID func(hub *Hub) int64
+	}{
+		{name: "BeforeRestart", lastEventID: func(hub *Hub) int64 { return hub.startID - 5 }},
+		{name: "FromFuture", lastEventID: func(hub *Hub) int64 { return hub.lastID + 5 }},
+		{name: "Evicted", lastEventID: func(hub *Hub) int64 { return hub.startID }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := NewHub(2)
+			for i := 0; i < 3; i++ {
+				hub.Publish(domain.MailEvent{Type: domain.EventNewMessage, ProfileID: 1})
+			}
+
+			sub := hub.Subscribe(1, tt.lastEventID(hub))
+			defer sub.Close()
+
+			event := receive(t, sub)
+			assert.Equal(t, domain.EventResync, event.Type)
+			assert.Empty(t, sub.Events)
+		})
+	}
+}
+
+func TestHub_HistoryExpires(t *testing.T) {
+	hub := NewHub(0)
+	now := time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC)
+	hub.now = func() time.Time { return now }
+
+	hub.Publish(domain.MailEvent{Type: domain.EventNewMessage, ProfileID: 1, MessageID: 10})
+	hub.Publish(domain.MailEvent{Type: domain.EventNewMessage, ProfileID: 2, MessageID: 11})
+	online := hub.Subscribe(2, 0)
+	defer online.Close()
+
+	now = now.Add(DefaultHistoryTTL)
+	hub.Publish(domain.MailEvent{Type: domain.EventNewMessage, ProfileID: 3, MessageID: 12})
+
+	assert.NotContains(t, hub.history, int64(1))
+	assert.Contains(t, hub.history, int64(2))
+	assert.Contains(t, hub.history, int64(3))
+
+	// Клиент, не получивший удаленное событие, должен перечитать ящик
+	sub := hub.Subscribe(1, hub.startID)
+	defer sub.Close()
+	assert.Equal(t, domain.EventResync, receive(t, sub).Type)
+
+	// Новые события профиля после удаления истории продолжают поток как обычно
+	hub.Publish(domain.MailEvent{Type: domain.EventNewMessage, ProfileID: 1, MessageID: 13})
+	<-sub.Events
+	resumed := hub.Subscribe(1, hub.lastID-1)
+	defer resumed.Close()
+	assert.Equal(t, int64(13), receive(t, resumed).MessageID)
+}
+
+func TestHub_SlowSubscriberDropped(t *testing.T) {
+	hub := NewHub(0)
+	sub := hub.Subscribe(1, 0)
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		hub.Publish(domain.MailEvent{Type: domain.EventNewMessage, ProfileID: 1})
+	}
+
+	received := 0
+	for range sub.Events {
+		received++
+	}
+	assert.Equal(t, subscriberBuffer, received)
+	assert.NotContains(t, hub.subscribers, int64(1))
+
+	// Повторная отписка не должна паниковать
+	sub.Close()
+}
@@ -0,0 +1,168 @@
+package mailbuild
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"slices"
+	"strings"
+	"time"
+
+	"2025_2_a4code/internal/domain"
+)
+
+// base64LineLength - длина строки base64 в теле письма (RFC 2045, 6.8)
+const base64LineLength = 76
+
+// Message - письмо для отправки на внешний сервер
+type Message struct {
+	From      mail.Address
+	To        string
+	Subject   string
+	Text      string
+	Date      time.Time
+	MessageID string
+	// Headers - дополнительные заголовки, например Auto-Submitted
+	Headers     map[string]string
+	Attachments []domain.Attachment
+	// Boundary - разделитель частей письма с вложениями, пустой - случайный.
+	// Постоянный разделитель нужен, чтобы письмо при каждой сборке было одним и тем же
+	Boundary string
+}
+
+// Build формирует письмо в формате RFC 5322. Текст передается в quoted-printable,
+// вложения - в base64, поэтому письмо состоит только из 7-битных строк
+func Build(msg Message) ([]byte, error) {
+	var buf bytes.Buffer
+
+	header := textproto.MIMEHeader{}
+	header.Set("From", msg.From.String())
+	header.Set("To", msg.To)
+	header.Set("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header.Set("Date", msg.Date.Format(time.RFC1123Z))
+	if msg.MessageID != "" {
+		header.Set("Message-Id", msg.MessageID)
+	}
+	header.Set("Mime-Version", "1.0")
+	for key, value := range msg.Headers {
+		header.Set(key, mime.QEncoding.Encode("utf-8", value))
+	}
+
+	if len(msg.Attachments) == 0 {
+		header.Set("Content-Type", "text/plain; charset=utf-8")
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+		writeHeader(&buf, header)
+		if err := writeQuotedPrintable(&buf, msg.Text); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if msg.Boundary != "" {
+		if err := writer.SetBoundary(msg.Boundary); err != nil {
+			return nil, err
+		}
+	}
+	header.Set("Content-Type", mime.FormatMediaType("multipart/mixed", map[string]string{"boundary": writer.Boundary()}))
+	writeHeader(&buf, header)
+
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := writeQuotedPrintable(part, msg.Text); err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range msg.Attachments {
+		contentType := attachment.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(contentType, map[string]string{"name": attachment.Name})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64(part, attachment.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	buf.Write(body.Bytes())
+
+	return buf.Bytes(), nil
+}
+
+// ContentType определяет тип вложения по расширению имени файла
+func ContentType(name string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		if contentType := mime.TypeByExtension(strings.ToLower(name[i:])); contentType != "" {
+			return contentType
+		}
+	}
+	return "application/octet-stream"
+}
+
+func writeHeader(w io.Writer, header textproto.MIMEHeader) {
+	// Порядок заголовков фиксирован, чтобы письмо было воспроизводимым
+	order := []string{"From", "To", "Subject", "Date", "Message-Id", "Mime-Version"}
+	written := map[string]bool{}
+	for _, key := range order {
+		if value := header.Get(key); value != "" {
+			fmt.Fprintf(w, "%s: %s\r\n", key, value)
+		}
+		written[key] = true
+	}
+
+	var rest []string
+	for key := range header {
+		if !written[key] {
+			rest = append(rest, key)
+		}
+	}
+	slices.Sort(rest)
+	for _, key := range rest {
+		fmt.Fprintf(w, "%s: %s\r\n", key, header.Get(key))
+	}
+	io.WriteString(w, "\r\n")
+}
+
+func writeQuotedPrintable(w io.Writer, text string) error {
+	qp := quotedprintable.NewWriter(w)
+	// Строки текста переводятся в CRLF, как требует RFC 5322
+	text = strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\n", "\r\n")
+	if _, err := io.WriteString(qp, text); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+func writeBase64(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > base64LineLength {
+		if _, err := io.WriteString(w, encoded[:base64LineLength]+"\r\n"); err != nil {
+			return err
+		}
+		encoded = encoded[base64LineLength:]
+	}
+	_, err := io.WriteString(w, encoded+"\r\n")
+	return err
+}
@@ -0,0 +1,135 @@
+package mailbuild
+
+import (
+	"bytes"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/lib/mailparse"
+)
+
+func TestBuild_RoundTrip(t *testing.T) {
+	data := bytes.Repeat([]byte{0, 1, 2, 255}, 50)
+	msg := Message{
+		From:      mail.Address{Name: "Алексей", Address: "alexey@flintmail.ru"},
+		To:        "someone@gmail.com",
+		Subject:   "Отчет за ноябрь",
+		Text:      "Привет!\nСтрока с = и очень длинным текстом " + strings.Repeat("слово ", 30),
+		Date:      time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC),
+		MessageID: "<100@flintmail.ru>",
+		Attachments: []domain.Attachment{
+			{Name: "отчет.pdf", ContentType: "application/pdf", Data: data},
+		},
+	}
+
+	raw, err := Build(msg)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	for i, line := range strings.Split(string(raw), "\r\n") {
+		if len(line) > 998 {
+			t.Errorf("line %d is %d bytes long", i, len(line))
+		}
+		for _, c := range []byte(line) {
+			if c > 127 {
+				t.Fatalf("line %d contains 8-bit data: %q", i, line)
+			}
+		}
+	}
+
+	parsed, err := mailparse.Parse(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if parsed.From != "alexey@flintmail.ru" || parsed.FromName != "Алексей" {
+		t.Errorf("From = %q %q", parsed.FromName, parsed.From)
+	}
+	if parsed.Topic != msg.Subject {
+		t.Errorf("Topic = %q, want %q", parsed.Topic, msg.Subject)
+	}
+	if parsed.Text != strings.TrimSpace(msg.Text) {
+		t.Errorf("Text = %q, want %q", parsed.Text, msg.Text)
+	}
+	if !parsed.Datetime.Equal(msg.Date) {
+		t.Errorf("Datetime = %v, want %v", parsed.Datetime, msg.Date)
+	}
+	if got := parsed.Headers["Message-Id"]; len(got) != 1 || got[0] != "<100@flintmail.ru>" {
+		t.Errorf("Message-Id = %v", got)
+	}
+	if len(parsed.Attachments) != 1 {
+		t.Fatalf("Attachments = %d, want 1", len(parsed.Attachments))
+	}
+	attachment := parsed.Attachments[0]
+	if attachment.Name != "отчет.pdf" || attachment.ContentType != "application/pdf" || !bytes.Equal(attachment.Data, data) {
+		t.Errorf("Attachment = %q %q %d bytes", attachment.Name, attachment.ContentType, len(attachment.Data))
+	}
+}
+
+func TestBuild_PlainText(t *testing.T) {
+	raw, err := Build(Message{
+		From:    mail.Address{Address: "mailer-daemon@flintmail.ru"},
+		To:      "alexey@flintmail.ru",
+		Subject: "Test",
+		Text:    "body",
+		Date:    time.Now(),
+		Headers: map[string]string{"Auto-Submitted": "auto-replied"},
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	text := string(raw)
+	if !strings.HasPrefix(text, "From: <mailer-daemon@flintmail.ru>\r\nTo: alexey@flintmail.ru\r\n") {
+		t.Errorf("unexpected header order:\n%s", text)
+	}
+	if !strings.Contains(text, "Auto-Submitted: auto-replied\r\n") || !strings.Contains(text, "Content-Type: text/plain; charset=utf-8\r\n") {
+		t.Errorf("missing headers:\n%s", text)
+	}
+	if !strings.HasSuffix(text, "\r\n\r\nbody") {
+		t.Errorf("unexpected body:\n%s", text)
+	}
+}
+
+func TestBuild_Boundary(t *testing.T) {
+	msg := Message{
+		From:        mail.Address{Address: "alexey@flintmail.ru"},
+		Subject:     "Test",
+		Text:        "body",
+		Date:        time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC),
+		Attachments: []domain.Attachment{{Name: "a.txt", ContentType: "text/plain", Data: []byte("a")}},
+		Boundary:    "=_flintmail_100",
+	}
+
+	first, err := Build(msg)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	second, err := Build(msg)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if !bytes.Equal(first, second) {
+		t.Errorf("Build() is not reproducible:\n%s\n%s", first, second)
+	}
+	if !strings.Contains(string(first), "\r\n--=_flintmail_100--\r\n") {
+		t.Errorf("boundary is not used:\n%s", first)
+	}
+}
+
+func TestContentType(t *testing.T) {
+	tests := map[string]string{
+		"report.PDF":  "application/pdf",
+		"archive":     "application/octet-stream",
+		"file.nonext": "application/octet-stream",
+	}
+	for name, want := range tests {
+		if got := ContentType(name); got != want {
+			t.Errorf("ContentType(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package mailparse
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"2025_2_a4code/internal/domain"
+
+	"golang.org/x/net/html"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// maxDepth - максимальная вложенность multipart-частей
+const maxDepth = 10
+
+var ErrMalformedMessage = errors.New("malformed message")
+
+var decoder = &mime.WordDecoder{CharsetReader: charsetReader}
+
+// Parse разбирает письмо в формате RFC 5322 с MIME-частями: тему и отправителя,
+// текст письма (text/plain, а если его нет - текст из text/html) и вложения
+func Parse(r io.Reader) (domain.InboundMessage, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return domain.InboundMessage{}, fmt.Errorf("%w: %v", ErrMalformedMessage, err)
+	}
+
+	result := domain.InboundMessage{
+		Headers: msg.Header,
+		Topic:   decodeHeader(msg.Header.Get("Subject")),
+	}
+
+	parser := mail.AddressParser{WordDecoder: decoder}
+	if from, err := parser.Parse(msg.Header.Get("From")); err == nil {
+		result.From = strings.ToLower(from.Address)
+		result.FromName = from.Name
+	}
+
+	result.Datetime, err = msg.Header.Date()
+	if err != nil {
+		result.Datetime = time.Now()
+	}
+
+	var body parts
+	header := textproto.MIMEHeader(msg.Header)
+	if err := body.walk(header, msg.Body, 0); err != nil {
+		return domain.InboundMessage{}, fmt.Errorf("%w: %v", ErrMalformedMessage, err)
+	}
+
+	result.Text = body.text
+	if result.Text == "" && body.html != "" {
+		result.Text = htmlToText(body.html)
+	}
+	result.Text = strings.TrimSpace(strings.ReplaceAll(result.Text, "\r\n", "\n"))
+	result.Attachments = body.attachments
+
+	return result, nil
+}
+
+// parts - текстовые части и вложения, собранные при обходе письма
+type parts struct {
+	text        string
+	html        string
+	attachments []domain.Attachment
+}
+
+func (p *parts) walk(header textproto.MIMEHeader, body io.Reader, depth int) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		if depth >= maxDepth {
+			return errors.New("too deeply nested multipart")
+		}
+		boundary := params["boundary"]
+		if boundary == "" {
+			return errors.New("multipart without boundary")
+		}
+
+		reader := multipart.NewReader(body, boundary)
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := p.walk(part.Header, part, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := io.ReadAll(transferDecoder(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return err
+	}
+
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	name := dispositionParams["filename"]
+	if name == "" {
+		name = params["name"]
+	}
+	name = decodeHeader(name)
+
+	isText := mediaType == "text/plain" || mediaType == "text/html"
+	if isText && disposition != "attachment" && name == "" {
+		text, err := decodeCharset(params["charset"], data)
+		if err != nil {
+			// Неизвестная кодировка - оставляем текст как есть
+			text = string(data)
+		}
+		if mediaType == "text/plain" && p.text == "" {
+			p.text = text
+		} else if mediaType == "text/html" && p.html == "" {
+			p.html = text
+		}
+		return nil
+	}
+
+	if name == "" {
+		name = defaultName(mediaType)
+	}
+	p.attachments = append(p.attachments, domain.Attachment{
+		Name:        name,
+		ContentType: mediaType,
+		Data:        data,
+	})
+	return nil
+}
+
+func transferDecoder(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		// Переносы строк внутри base64 пропускаются декодером
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	}
+	return body
+}
+
+func decodeCharset(charset string, data []byte) (string, error) {
+	switch strings.ToLower(charset) {
+	case "", "utf-8", "utf8", "us-ascii":
+		return string(data), nil
+	}
+
+	reader, err := charsetReader(charset, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
+
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported charset %q", charset)
+	}
+	return enc.NewDecoder().Reader(input), nil
+}
+
+// decodeHeader декодирует encoded-word из RFC 2047, при ошибке возвращает значение как есть
+func decodeHeader(value string) string {
+	decoded, err := decoder.DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+func defaultName(mediaType string) string {
+	if mediaType == "message/rfc822" {
+		return "message.eml"
+	}
+	if extensions, err := mime.ExtensionsByType(mediaType); err == nil && len(extensions) > 0 {
+		return "attachment" + extensions[0]
+	}
+	return "attachment.bin"
+}
+
+// blockTags - теги, после которых в тексте начинается новая строка
+var blockTags = map[string]bool{
+	"p": true, "div": true, "br": true, "li": true, "tr": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"blockquote": true, "table": true,
+}
+
+// htmlToText оставляет от HTML-письма только видимый текст
+func htmlToText(source string) string {
+	var (
+		builder strings.Builder
+		skip    int
+	)
+
+	tokenizer := html.NewTokenizer(strings.NewReader(source))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return builder.String()
+		case html.TextToken:
+			if skip == 0 {
+				builder.WriteString(strings.Join(strings.Fields(string(tokenizer.Text())), " "))
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, _ := tokenizer.TagName()
+			switch tag := string(name); {
+			case tag == "script" || tag == "style" || tag == "head":
+				skip++
+			case blockTags[tag]:
+				builder.WriteString("\n")
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch tag := string(name); {
+			case tag == "script" || tag == "style" || tag == "head":
+				if skip > 0 {
+					skip--
+				}
+			case blockTags[tag]:
+				builder.WriteString("\n")
+			}
+		}
+	}
+}
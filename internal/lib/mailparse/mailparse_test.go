@@ -0,0 +1,103 @@
+package mailparse
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse_Multipart(t *testing.T) {
+	raw := strings.Join([]string{
+		"From: =?UTF-8?B?0JjQstCw0L0=?= <Ivan@Example.com>",
+		"To: alexey@flintmail.ru",
+		"Subject: =?KOI8-R?B?8NLJ18XU?=",
+		"Date: Mon, 03 Nov 2025 10:00:00 +0300",
+		"MIME-Version: 1.0",
+		`Content-Type: multipart/mixed; boundary="outer"`,
+		"",
+		"--outer",
+		`Content-Type: multipart/alternative; boundary="inner"`,
+		"",
+		"--inner",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Transfer-Encoding: quoted-printable",
+		"",
+		"=D0=9F=D1=80=D0=B8=D0=B2=D0=B5=D1=82, =",
+		"=D0=BC=D0=B8=D1=80",
+		"--inner",
+		"Content-Type: text/html; charset=utf-8",
+		"",
+		"<p>Привет, мир</p>",
+		"--inner--",
+		"--outer",
+		`Content-Type: application/pdf; name="report.pdf"`,
+		`Content-Disposition: attachment; filename="report.pdf"`,
+		"Content-Transfer-Encoding: base64",
+		"",
+		"JVBERi0x",
+		"LjQ=",
+		"--outer--",
+		"",
+	}, "\r\n")
+
+	msg, err := Parse(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if msg.From != "ivan@example.com" || msg.FromName != "Иван" {
+		t.Errorf("From = %q %q", msg.FromName, msg.From)
+	}
+	if msg.Topic != "Привет" {
+		t.Errorf("Topic = %q, want %q", msg.Topic, "Привет")
+	}
+	if msg.Text != "Привет, мир" {
+		t.Errorf("Text = %q, want %q", msg.Text, "Привет, мир")
+	}
+	if msg.Datetime.UTC().Hour() != 7 {
+		t.Errorf("Datetime = %v", msg.Datetime)
+	}
+	if len(msg.Attachments) != 1 {
+		t.Fatalf("Attachments = %d, want 1", len(msg.Attachments))
+	}
+	attachment := msg.Attachments[0]
+	if attachment.Name != "report.pdf" || attachment.ContentType != "application/pdf" || string(attachment.Data) != "%PDF-1.4" {
+		t.Errorf("Attachment = %q %q %q", attachment.Name, attachment.ContentType, attachment.Data)
+	}
+}
+
+func TestParse_HTMLOnly(t *testing.T) {
+	raw := "From: news@example.com\r\n" +
+		"Subject: News\r\n" +
+		"Content-Type: text/html; charset=windows-1251\r\n" +
+		"\r\n" +
+		"<html><head><style>p{}</style></head><body><p>\xcf\xf0\xe8\xe2\xe5\xf2</p><script>x()</script></body></html>"
+
+	msg, err := Parse(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if msg.Text != "Привет" {
+		t.Errorf("Text = %q, want %q", msg.Text, "Привет")
+	}
+	if len(msg.Attachments) != 0 {
+		t.Errorf("Attachments = %d, want 0", len(msg.Attachments))
+	}
+}
+
+func TestParse_Malformed(t *testing.T) {
+	tests := map[string]string{
+		"NoHeaders":       "just text without headers",
+		"NoBoundary":      "Content-Type: multipart/mixed\r\n\r\nbody",
+		"BrokenMultipart": "Content-Type: multipart/mixed; boundary=b\r\n\r\n--b\r\nno end",
+	}
+
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(raw))
+			if !errors.Is(err, ErrMalformedMessage) {
+				t.Errorf("Parse() error = %v, want ErrMalformedMessage", err)
+			}
+		})
+	}
+}
@@ -83,6 +83,14 @@ var (
 		[]string{"status"},
 	)
 
+	AuthAppPasswordAttempts = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "auth_app_password_attempts_total",
+			Help: "Total number of mail client logins with app passwords",
+		},
+		[]string{"protocol", "status"},
+	)
+
 	// === Метрики токенов ===
 	TokenGenerations = promauto.NewCounterVec(
 		prometheus.CounterOpts{
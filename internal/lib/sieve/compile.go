@@ -0,0 +1,633 @@
+package sieve
+
+import (
+	"strings"
+)
+
+// Поддерживаемые расширения (require)
+var supportedExtensions = map[string]struct{}{
+	"fileinto":   {},
+	"envelope":   {},
+	"vacation":   {},
+	"imap4flags": {},
+	"copy":       {},
+}
+
+type (
+	ifCommand struct {
+		branches []ifBranch
+	}
+	ifBranch struct {
+		cond test // nil для else
+		body []command
+	}
+	stopCommand    struct{}
+	discardCommand struct{}
+	keepCommand    struct {
+		flags    []string
+		hasFlags bool
+	}
+	fileIntoCommand struct {
+		mailbox  string
+		flags    []string
+		hasFlags bool
+		copy     bool
+	}
+	vacationCommand struct {
+		days      int64
+		subject   string
+		from      string
+		addresses []string
+		handle    string
+		mime      bool
+		reason    string
+	}
+	flagCommand struct {
+		op    string // setflag, addflag, removeflag
+		flags []string
+	}
+)
+
+type command interface{}
+
+type (
+	headerTest struct {
+		cmp     comparator
+		match   matchType
+		headers []string
+		keys    []string
+	}
+	addressTest struct {
+		cmp     comparator
+		match   matchType
+		part    addressPart
+		headers []string
+		keys    []string
+	}
+	envelopeTest struct {
+		cmp   comparator
+		match matchType
+		part  addressPart
+		parts []string
+		keys  []string
+	}
+	existsTest struct {
+		headers []string
+	}
+	sizeTest struct {
+		over  bool
+		limit int64
+	}
+	allOfTest struct {
+		tests []test
+	}
+	anyOfTest struct {
+		tests []test
+	}
+	notTest struct {
+		test test
+	}
+	constTest struct {
+		value bool
+	}
+	hasFlagTest struct {
+		cmp   comparator
+		match matchType
+		keys  []string
+	}
+)
+
+type test interface{}
+
+type compiler struct {
+	extensions map[string]struct{}
+}
+
+func compile(raw []rawCommand) ([]command, map[string]struct{}, error) {
+	c := &compiler{extensions: make(map[string]struct{})}
+
+	// require допускается только в начале скрипта
+	start := 0
+	for ; start < len(raw) && raw[start].name == "require"; start++ {
+		if err := c.require(raw[start]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	commands, err := c.commands(raw[start:])
+	if err != nil {
+		return nil, nil, err
+	}
+	return commands, c.extensions, nil
+}
+
+func (c *compiler) require(cmd rawCommand) error {
+	if cmd.hasBlock || len(cmd.tests) > 0 || len(cmd.args) != 1 || cmd.args[0].kind != argStringList {
+		return errorf(cmd.line, "require expects a string list")
+	}
+	for _, ext := range cmd.args[0].strings {
+		ext = strings.ToLower(ext)
+		if _, ok := supportedExtensions[ext]; !ok {
+			return errorf(cmd.line, "unsupported extension %q", ext)
+		}
+		c.extensions[ext] = struct{}{}
+	}
+	return nil
+}
+
+func (c *compiler) requires(line int, ext, what string) error {
+	if _, ok := c.extensions[ext]; !ok {
+		return errorf(line, "%s requires %q extension (missing require)", what, ext)
+	}
+	return nil
+}
+
+func (c *compiler) commands(raw []rawCommand) ([]command, error) {
+	var commands []command
+	for i := 0; i < len(raw); i++ {
+		cmd := raw[i]
+		switch cmd.name {
+		case "if":
+			compiled, consumed, err := c.ifChain(raw[i:])
+			if err != nil {
+				return nil, err
+			}
+			commands = append(commands, compiled)
+			i += consumed - 1
+		case "elsif", "else":
+			return nil, errorf(cmd.line, "%q without preceding \"if\"", cmd.name)
+		case "require":
+			return nil, errorf(cmd.line, "require is only allowed at the beginning of the script")
+		default:
+			compiled, err := c.action(cmd)
+			if err != nil {
+				return nil, err
+			}
+			commands = append(commands, compiled)
+		}
+	}
+	return commands, nil
+}
+
+func (c *compiler) ifChain(raw []rawCommand) (command, int, error) {
+	var result ifCommand
+	consumed := 0
+	for consumed < len(raw) {
+		cmd := raw[consumed]
+		if consumed > 0 && cmd.name != "elsif" && cmd.name != "else" {
+			break
+		}
+		if consumed > 0 && len(result.branches) > 0 && result.branches[len(result.branches)-1].cond == nil {
+			return nil, 0, errorf(cmd.line, "%q after \"else\"", cmd.name)
+		}
+		if !cmd.hasBlock {
+			return nil, 0, errorf(cmd.line, "%q requires a block", cmd.name)
+		}
+		if len(cmd.args) > 0 {
+			return nil, 0, errorf(cmd.line, "unexpected arguments for %q", cmd.name)
+		}
+
+		var branch ifBranch
+		if cmd.name == "else" {
+			if len(cmd.tests) != 0 {
+				return nil, 0, errorf(cmd.line, "\"else\" does not take a test")
+			}
+		} else {
+			if len(cmd.tests) != 1 {
+				return nil, 0, errorf(cmd.line, "%q requires exactly one test", cmd.name)
+			}
+			cond, err := c.test(cmd.tests[0])
+			if err != nil {
+				return nil, 0, err
+			}
+			branch.cond = cond
+		}
+
+		body, err := c.commands(cmd.block)
+		if err != nil {
+			return nil, 0, err
+		}
+		branch.body = body
+		result.branches = append(result.branches, branch)
+		consumed++
+	}
+	return result, consumed, nil
+}
+
+func (c *compiler) action(cmd rawCommand) (command, error) {
+	if cmd.hasBlock {
+		return nil, errorf(cmd.line, "%q does not take a block", cmd.name)
+	}
+	if len(cmd.tests) > 0 {
+		return nil, errorf(cmd.line, "%q does not take a test", cmd.name)
+	}
+
+	switch cmd.name {
+	case "stop":
+		if len(cmd.args) > 0 {
+			return nil, errorf(cmd.line, "stop does not take arguments")
+		}
+		return stopCommand{}, nil
+
+	case "discard":
+		if len(cmd.args) > 0 {
+			return nil, errorf(cmd.line, "discard does not take arguments")
+		}
+		return discardCommand{}, nil
+
+	case "keep":
+		args, err := c.splitArgs(cmd.line, cmd.name, cmd.args, map[string]tagSpec{
+			"flags": {value: argStringList, ext: "imap4flags"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(args.positional) != 0 {
+			return nil, errorf(cmd.line, "keep does not take positional arguments")
+		}
+		flagsArg, hasFlags := args.tags["flags"]
+		return keepCommand{flags: normalizeFlags(flagsArg.strings), hasFlags: hasFlags}, nil
+
+	case "fileinto":
+		if err := c.requires(cmd.line, "fileinto", "fileinto"); err != nil {
+			return nil, err
+		}
+		args, err := c.splitArgs(cmd.line, cmd.name, cmd.args, map[string]tagSpec{
+			"flags": {value: argStringList, ext: "imap4flags"},
+			"copy":  {ext: "copy"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		mailbox, err := singleString(cmd.line, "fileinto", args.positional)
+		if err != nil {
+			return nil, err
+		}
+		flagsArg, hasFlags := args.tags["flags"]
+		_, copyTag := args.tags["copy"]
+		return fileIntoCommand{
+			mailbox:  mailbox,
+			flags:    normalizeFlags(flagsArg.strings),
+			hasFlags: hasFlags,
+			copy:     copyTag,
+		}, nil
+
+	case "vacation":
+		if err := c.requires(cmd.line, "vacation", "vacation"); err != nil {
+			return nil, err
+		}
+		args, err := c.splitArgs(cmd.line, cmd.name, cmd.args, map[string]tagSpec{
+			"days":      {value: argNumber},
+			"subject":   {value: argStringList, single: true},
+			"from":      {value: argStringList, single: true},
+			"addresses": {value: argStringList},
+			"handle":    {value: argStringList, single: true},
+			"mime":      {},
+		})
+		if err != nil {
+			return nil, err
+		}
+		reason, err := singleString(cmd.line, "vacation", args.positional)
+		if err != nil {
+			return nil, err
+		}
+		vacation := vacationCommand{days: defaultVacationDays, reason: reason}
+		if days, ok := args.tags["days"]; ok {
+			vacation.days = days.number
+			if vacation.days < 1 {
+				vacation.days = 1
+			}
+		}
+		if v, ok := args.tags["subject"]; ok {
+			vacation.subject = v.strings[0]
+		}
+		if v, ok := args.tags["from"]; ok {
+			vacation.from = v.strings[0]
+		}
+		if v, ok := args.tags["addresses"]; ok {
+			vacation.addresses = v.strings
+		}
+		if v, ok := args.tags["handle"]; ok {
+			vacation.handle = v.strings[0]
+		}
+		_, vacation.mime = args.tags["mime"]
+		return vacation, nil
+
+	case "setflag", "addflag", "removeflag":
+		if err := c.requires(cmd.line, "imap4flags", cmd.name); err != nil {
+			return nil, err
+		}
+		switch len(cmd.args) {
+		case 1:
+		case 2:
+			return nil, errorf(cmd.line, "%s with a variable name requires \"variables\" extension, which is not supported", cmd.name)
+		default:
+			return nil, errorf(cmd.line, "%s expects a list of flags", cmd.name)
+		}
+		if cmd.args[0].kind != argStringList {
+			return nil, errorf(cmd.line, "%s expects a list of flags", cmd.name)
+		}
+		return flagCommand{op: cmd.name, flags: normalizeFlags(cmd.args[0].strings)}, nil
+
+	case "redirect":
+		return nil, errorf(cmd.line, "redirect is not supported")
+	}
+
+	return nil, errorf(cmd.line, "unknown command %q", cmd.name)
+}
+
+func (c *compiler) test(raw rawTest) (test, error) {
+	switch raw.name {
+	case "true", "false":
+		if len(raw.args) > 0 || len(raw.tests) > 0 {
+			return nil, errorf(raw.line, "%q does not take arguments", raw.name)
+		}
+		return constTest{value: raw.name == "true"}, nil
+
+	case "not":
+		if len(raw.args) > 0 || len(raw.tests) != 1 {
+			return nil, errorf(raw.line, "not expects exactly one test")
+		}
+		inner, err := c.test(raw.tests[0])
+		if err != nil {
+			return nil, err
+		}
+		return notTest{test: inner}, nil
+
+	case "allof", "anyof":
+		if len(raw.args) > 0 || len(raw.tests) == 0 {
+			return nil, errorf(raw.line, "%s expects a test list", raw.name)
+		}
+		tests := make([]test, 0, len(raw.tests))
+		for _, t := range raw.tests {
+			compiled, err := c.test(t)
+			if err != nil {
+				return nil, err
+			}
+			tests = append(tests, compiled)
+		}
+		if raw.name == "allof" {
+			return allOfTest{tests: tests}, nil
+		}
+		return anyOfTest{tests: tests}, nil
+	}
+
+	if len(raw.tests) > 0 {
+		return nil, errorf(raw.line, "%q does not take nested tests", raw.name)
+	}
+
+	switch raw.name {
+	case "header":
+		args, err := c.splitArgs(raw.line, raw.name, raw.args, matchTagSpecs(false))
+		if err != nil {
+			return nil, err
+		}
+		lists, err := stringLists(raw.line, raw.name, args.positional, 2)
+		if err != nil {
+			return nil, err
+		}
+		cmp, match, err := comparatorAndMatch(raw.line, args)
+		if err != nil {
+			return nil, err
+		}
+		return headerTest{cmp: cmp, match: match, headers: lists[0], keys: lists[1]}, nil
+
+	case "address":
+		args, err := c.splitArgs(raw.line, raw.name, raw.args, matchTagSpecs(true))
+		if err != nil {
+			return nil, err
+		}
+		lists, err := stringLists(raw.line, raw.name, args.positional, 2)
+		if err != nil {
+			return nil, err
+		}
+		cmp, match, err := comparatorAndMatch(raw.line, args)
+		if err != nil {
+			return nil, err
+		}
+		return addressTest{cmp: cmp, match: match, part: addressPartOf(args), headers: lists[0], keys: lists[1]}, nil
+
+	case "envelope":
+		if err := c.requires(raw.line, "envelope", "envelope"); err != nil {
+			return nil, err
+		}
+		args, err := c.splitArgs(raw.line, raw.name, raw.args, matchTagSpecs(true))
+		if err != nil {
+			return nil, err
+		}
+		lists, err := stringLists(raw.line, raw.name, args.positional, 2)
+		if err != nil {
+			return nil, err
+		}
+		for i, part := range lists[0] {
+			part = strings.ToLower(part)
+			if part != "from" && part != "to" {
+				return nil, errorf(raw.line, "unsupported envelope part %q", part)
+			}
+			lists[0][i] = part
+		}
+		cmp, match, err := comparatorAndMatch(raw.line, args)
+		if err != nil {
+			return nil, err
+		}
+		return envelopeTest{cmp: cmp, match: match, part: addressPartOf(args), parts: lists[0], keys: lists[1]}, nil
+
+	case "exists":
+		lists, err := stringLists(raw.line, raw.name, raw.args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return existsTest{headers: lists[0]}, nil
+
+	case "size":
+		args, err := c.splitArgs(raw.line, raw.name, raw.args, map[string]tagSpec{
+			"over":  {group: "relation"},
+			"under": {group: "relation"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		_, over := args.tags["over"]
+		_, under := args.tags["under"]
+		if !over && !under {
+			return nil, errorf(raw.line, "size requires :over or :under")
+		}
+		if len(args.positional) != 1 || args.positional[0].kind != argNumber {
+			return nil, errorf(raw.line, "size expects a number")
+		}
+		return sizeTest{over: over, limit: args.positional[0].number}, nil
+
+	case "hasflag":
+		if err := c.requires(raw.line, "imap4flags", "hasflag"); err != nil {
+			return nil, err
+		}
+		args, err := c.splitArgs(raw.line, raw.name, raw.args, matchTagSpecs(false))
+		if err != nil {
+			return nil, err
+		}
+		if len(args.positional) == 2 {
+			return nil, errorf(raw.line, "hasflag with a variable list requires \"variables\" extension, which is not supported")
+		}
+		lists, err := stringLists(raw.line, raw.name, args.positional, 1)
+		if err != nil {
+			return nil, err
+		}
+		cmp, match, err := comparatorAndMatch(raw.line, args)
+		if err != nil {
+			return nil, err
+		}
+		return hasFlagTest{cmp: cmp, match: match, keys: normalizeFlags(lists[0])}, nil
+	}
+
+	return nil, errorf(raw.line, "unknown test %q", raw.name)
+}
+
+type tagSpec struct {
+	value  argKind // тип значения тега; argTag - тег без значения
+	single bool    // значение должно быть одной строкой
+	group  string  // взаимоисключающие теги
+	ext    string  // расширение, необходимое для использования тега
+}
+
+type splitResult struct {
+	tags       map[string]argument
+	positional []argument
+}
+
+func (c *compiler) splitArgs(line int, name string, args []argument, specs map[string]tagSpec) (splitResult, error) {
+	result := splitResult{tags: make(map[string]argument)}
+	groups := make(map[string]string)
+
+	i := 0
+	for ; i < len(args); i++ {
+		arg := args[i]
+		if arg.kind != argTag {
+			break
+		}
+		spec, ok := specs[arg.tag]
+		if !ok {
+			return splitResult{}, errorf(arg.line, "unknown tag :%s for %q", arg.tag, name)
+		}
+		if spec.ext != "" {
+			if err := c.requires(arg.line, spec.ext, ":"+arg.tag); err != nil {
+				return splitResult{}, err
+			}
+		}
+		if _, dup := result.tags[arg.tag]; dup {
+			return splitResult{}, errorf(arg.line, "duplicate tag :%s", arg.tag)
+		}
+		if spec.group != "" {
+			if other, ok := groups[spec.group]; ok {
+				return splitResult{}, errorf(arg.line, "tags :%s and :%s are mutually exclusive", other, arg.tag)
+			}
+			groups[spec.group] = arg.tag
+		}
+
+		value := arg
+		if spec.value != argTag {
+			if i+1 >= len(args) || args[i+1].kind != spec.value {
+				return splitResult{}, errorf(arg.line, "tag :%s requires a value", arg.tag)
+			}
+			i++
+			value = args[i]
+			if spec.single && (value.list || len(value.strings) != 1) {
+				return splitResult{}, errorf(arg.line, "tag :%s requires a single string", arg.tag)
+			}
+		}
+		result.tags[arg.tag] = value
+	}
+
+	for _, arg := range args[i:] {
+		if arg.kind == argTag {
+			return splitResult{}, errorf(arg.line, "tag :%s must precede positional arguments", arg.tag)
+		}
+	}
+	result.positional = args[i:]
+	return result, nil
+}
+
+func matchTagSpecs(withAddressPart bool) map[string]tagSpec {
+	specs := map[string]tagSpec{
+		"comparator": {value: argStringList, single: true},
+		"is":         {group: "match"},
+		"contains":   {group: "match"},
+		"matches":    {group: "match"},
+	}
+	if withAddressPart {
+		specs["all"] = tagSpec{group: "address-part"}
+		specs["localpart"] = tagSpec{group: "address-part"}
+		specs["domain"] = tagSpec{group: "address-part"}
+	}
+	return specs
+}
+
+func comparatorAndMatch(line int, args splitResult) (comparator, matchType, error) {
+	cmp := cmpASCIICasemap
+	if v, ok := args.tags["comparator"]; ok {
+		switch strings.ToLower(v.strings[0]) {
+		case "i;ascii-casemap":
+			cmp = cmpASCIICasemap
+		case "i;octet":
+			cmp = cmpOctet
+		default:
+			return 0, 0, errorf(line, "unsupported comparator %q", v.strings[0])
+		}
+	}
+
+	match := matchIs
+	if _, ok := args.tags["contains"]; ok {
+		match = matchContains
+	}
+	if _, ok := args.tags["matches"]; ok {
+		match = matchMatches
+	}
+	return cmp, match, nil
+}
+
+func addressPartOf(args splitResult) addressPart {
+	if _, ok := args.tags["localpart"]; ok {
+		return partLocal
+	}
+	if _, ok := args.tags["domain"]; ok {
+		return partDomain
+	}
+	return partAll
+}
+
+func stringLists(line int, name string, args []argument, count int) ([][]string, error) {
+	if len(args) != count {
+		return nil, errorf(line, "%s expects %d string list argument(s), got %d", name, count, len(args))
+	}
+	lists := make([][]string, 0, count)
+	for _, arg := range args {
+		if arg.kind != argStringList {
+			return nil, errorf(arg.line, "%s expects string list arguments", name)
+		}
+		lists = append(lists, arg.strings)
+	}
+	return lists, nil
+}
+
+func singleString(line int, name string, args []argument) (string, error) {
+	if len(args) != 1 || args[0].kind != argStringList || args[0].list || len(args[0].strings) != 1 {
+		return "", errorf(line, "%s expects a single string argument", name)
+	}
+	return args[0].strings[0], nil
+}
+
+// normalizeFlags разбивает строки с флагами по пробелам и убирает дубликаты
+func normalizeFlags(values []string) []string {
+	var flags []string
+	seen := make(map[string]struct{})
+	for _, value := range values {
+		for _, flag := range strings.Fields(value) {
+			key := strings.ToLower(flag)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			flags = append(flags, flag)
+		}
+	}
+	return flags
+}
@@ -0,0 +1,311 @@
+package sieve
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdentifier
+	tokTag
+	tokNumber
+	tokString
+	tokLBracket
+	tokRBracket
+	tokLParen
+	tokRParen
+	tokLBrace
+	tokRBrace
+	tokComma
+	tokSemicolon
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	number int64
+	line   int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of script"
+	case tokString:
+		return strconv.Quote(t.text)
+	case tokNumber:
+		return strconv.FormatInt(t.number, 10)
+	default:
+		return "\"" + t.text + "\""
+	}
+}
+
+// Error - ошибка разбора или проверки скрипта с номером строки
+type Error struct {
+	Line int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+func errorf(line int, format string, args ...interface{}) *Error {
+	return &Error{Line: line, Msg: fmt.Sprintf(format, args...)}
+}
+
+type lexer struct {
+	src  string
+	pos  int
+	line int
+}
+
+// tokenize разбивает скрипт на токены согласно RFC 5228, раздел 8.1
+func tokenize(src string) ([]token, error) {
+	l := &lexer{src: src, line: 1}
+	var tokens []token
+	for {
+		tok, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, tok)
+		if tok.kind == tokEOF {
+			return tokens, nil
+		}
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	if err := l.skipSpaceAndComments(); err != nil {
+		return token{}, err
+	}
+
+	if l.pos >= len(l.src) {
+		return token{kind: tokEOF, line: l.line}, nil
+	}
+
+	c := l.src[l.pos]
+	line := l.line
+
+	switch c {
+	case '[':
+		l.pos++
+		return token{kind: tokLBracket, text: "[", line: line}, nil
+	case ']':
+		l.pos++
+		return token{kind: tokRBracket, text: "]", line: line}, nil
+	case '(':
+		l.pos++
+		return token{kind: tokLParen, text: "(", line: line}, nil
+	case ')':
+		l.pos++
+		return token{kind: tokRParen, text: ")", line: line}, nil
+	case '{':
+		l.pos++
+		return token{kind: tokLBrace, text: "{", line: line}, nil
+	case '}':
+		l.pos++
+		return token{kind: tokRBrace, text: "}", line: line}, nil
+	case ',':
+		l.pos++
+		return token{kind: tokComma, text: ",", line: line}, nil
+	case ';':
+		l.pos++
+		return token{kind: tokSemicolon, text: ";", line: line}, nil
+	case '"':
+		return l.quotedString()
+	case ':':
+		l.pos++
+		name := l.identifier()
+		if name == "" {
+			return token{}, errorf(line, "expected tag name after ':'")
+		}
+		return token{kind: tokTag, text: strings.ToLower(name), line: line}, nil
+	}
+
+	if isDigit(c) {
+		return l.number()
+	}
+
+	if isAlpha(c) || c == '_' {
+		name := l.identifier()
+		if strings.EqualFold(name, "text") && l.pos < len(l.src) && l.src[l.pos] == ':' {
+			l.pos++
+			return l.multiLineString(line)
+		}
+		return token{kind: tokIdentifier, text: strings.ToLower(name), line: line}, nil
+	}
+
+	return token{}, errorf(line, "unexpected character %q", c)
+}
+
+func (l *lexer) skipSpaceAndComments() error {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '\n':
+			l.line++
+			l.pos++
+		case c == ' ' || c == '\t' || c == '\r':
+			l.pos++
+		case c == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		case c == '/' && l.pos+1 < len(l.src) && l.src[l.pos+1] == '*':
+			start := l.line
+			end := strings.Index(l.src[l.pos+2:], "*/")
+			if end < 0 {
+				return errorf(start, "unterminated comment")
+			}
+			comment := l.src[l.pos : l.pos+2+end+2]
+			l.line += strings.Count(comment, "\n")
+			l.pos += len(comment)
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (l *lexer) identifier() string {
+	start := l.pos
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if !isAlpha(c) && !isDigit(c) && c != '_' {
+			break
+		}
+		if l.pos == start && isDigit(c) {
+			break
+		}
+		l.pos++
+	}
+	return l.src[start:l.pos]
+}
+
+func (l *lexer) number() (token, error) {
+	line := l.line
+	start := l.pos
+	for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+		l.pos++
+	}
+
+	value, err := strconv.ParseInt(l.src[start:l.pos], 10, 64)
+	if err != nil {
+		return token{}, errorf(line, "invalid number %q", l.src[start:l.pos])
+	}
+
+	if l.pos < len(l.src) {
+		var multiplier int64
+		switch l.src[l.pos] {
+		case 'K', 'k':
+			multiplier = 1 << 10
+		case 'M', 'm':
+			multiplier = 1 << 20
+		case 'G', 'g':
+			multiplier = 1 << 30
+		}
+		if multiplier != 0 {
+			l.pos++
+			if value > (1<<62)/multiplier {
+				return token{}, errorf(line, "number is too large")
+			}
+			value *= multiplier
+		}
+	}
+
+	return token{kind: tokNumber, number: value, text: l.src[start:l.pos], line: line}, nil
+}
+
+func (l *lexer) quotedString() (token, error) {
+	line := l.line
+	l.pos++ // открывающая кавычка
+
+	var b strings.Builder
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch c {
+		case '"':
+			l.pos++
+			return token{kind: tokString, text: b.String(), line: line}, nil
+		case '\\':
+			if l.pos+1 >= len(l.src) {
+				return token{}, errorf(line, "unterminated string")
+			}
+			next := l.src[l.pos+1]
+			if next == '\n' {
+				l.line++
+			}
+			b.WriteByte(next)
+			l.pos += 2
+		case '\n':
+			l.line++
+			b.WriteByte(c)
+			l.pos++
+		default:
+			b.WriteByte(c)
+			l.pos++
+		}
+	}
+
+	return token{}, errorf(line, "unterminated string")
+}
+
+func (l *lexer) multiLineString(line int) (token, error) {
+	// после "text:" допускаются пробелы и комментарий до конца строки
+	for l.pos < len(l.src) && (l.src[l.pos] == ' ' || l.src[l.pos] == '\t') {
+		l.pos++
+	}
+	if l.pos < len(l.src) && l.src[l.pos] == '#' {
+		for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+			l.pos++
+		}
+	}
+	if l.pos < len(l.src) && l.src[l.pos] == '\r' {
+		l.pos++
+	}
+	if l.pos >= len(l.src) || l.src[l.pos] != '\n' {
+		return token{}, errorf(line, "expected line break after \"text:\"")
+	}
+	l.pos++
+	l.line++
+
+	var b strings.Builder
+	for l.pos < len(l.src) {
+		end := strings.IndexByte(l.src[l.pos:], '\n')
+		var rawLine string
+		if end < 0 {
+			rawLine = l.src[l.pos:]
+			l.pos = len(l.src)
+		} else {
+			rawLine = l.src[l.pos : l.pos+end]
+			l.pos += end + 1
+			l.line++
+		}
+		current := strings.TrimSuffix(rawLine, "\r")
+
+		if current == "." {
+			return token{kind: tokString, text: b.String(), line: line}, nil
+		}
+		if strings.HasPrefix(current, "..") {
+			current = current[1:]
+		}
+		b.WriteString(current)
+		b.WriteString("\r\n")
+	}
+
+	return token{}, errorf(line, "unterminated multi-line string")
+}
+
+func isAlpha(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,169 @@
+package sieve
+
+import (
+	"mime"
+	"net/mail"
+	"strings"
+)
+
+type comparator int
+
+const (
+	cmpASCIICasemap comparator = iota
+	cmpOctet
+)
+
+type matchType int
+
+const (
+	matchIs matchType = iota
+	matchContains
+	matchMatches
+)
+
+type addressPart int
+
+const (
+	partAll addressPart = iota
+	partLocal
+	partDomain
+)
+
+// matchAny проверяет, совпадает ли хотя бы одно значение хотя бы с одним ключом
+func matchAny(cmp comparator, match matchType, values, keys []string) bool {
+	for _, value := range values {
+		for _, key := range keys {
+			if matchValue(cmp, match, value, key) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchValue(cmp comparator, match matchType, value, key string) bool {
+	if cmp == cmpASCIICasemap {
+		value = asciiLower(value)
+		key = asciiLower(key)
+	}
+
+	switch match {
+	case matchContains:
+		return strings.Contains(value, key)
+	case matchMatches:
+		return glob(key, value)
+	default:
+		return value == key
+	}
+}
+
+// glob сопоставляет строку с шаблоном :matches, где "*" - любая последовательность,
+// "?" - один символ, а "\" экранирует следующий символ
+func glob(pattern, value string) bool {
+	p := []rune(pattern)
+	v := []rune(value)
+
+	pi, vi := 0, 0
+	starP, starV := -1, 0
+	for vi < len(v) {
+		if pi < len(p) {
+			switch p[pi] {
+			case '*':
+				starP = pi
+				starV = vi
+				pi++
+				continue
+			case '?':
+				pi++
+				vi++
+				continue
+			case '\\':
+				if pi+1 < len(p) && p[pi+1] == v[vi] {
+					pi += 2
+					vi++
+					continue
+				}
+			default:
+				if p[pi] == v[vi] {
+					pi++
+					vi++
+					continue
+				}
+			}
+		}
+		if starP < 0 {
+			return false
+		}
+		pi = starP + 1
+		starV++
+		vi = starV
+	}
+
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
+
+// asciiLower приводит к нижнему регистру только ASCII-символы (i;ascii-casemap)
+func asciiLower(s string) string {
+	b := []byte(s)
+	changed := false
+	for i, c := range b {
+		if c >= 'A' && c <= 'Z' {
+			b[i] = c + ('a' - 'A')
+			changed = true
+		}
+	}
+	if !changed {
+		return s
+	}
+	return string(b)
+}
+
+var wordDecoder = &mime.WordDecoder{}
+
+// decodeHeader декодирует encoded-word (RFC 2047) в значении заголовка
+func decodeHeader(value string) string {
+	decoded, err := wordDecoder.DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+// parseAddresses извлекает адреса из значения заголовка
+func parseAddresses(value string) []string {
+	list, err := mail.ParseAddressList(value)
+	if err != nil {
+		// некорректный заголовок сравнивается целиком
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return nil
+		}
+		return []string{strings.Trim(value, "<>")}
+	}
+
+	addresses := make([]string, 0, len(list))
+	for _, addr := range list {
+		addresses = append(addresses, addr.Address)
+	}
+	return addresses
+}
+
+func addressPartValue(address string, part addressPart) string {
+	switch part {
+	case partLocal:
+		if at := strings.LastIndexByte(address, '@'); at >= 0 {
+			return address[:at]
+		}
+		return address
+	case partDomain:
+		if at := strings.LastIndexByte(address, '@'); at >= 0 {
+			return address[at+1:]
+		}
+		return ""
+	default:
+		return address
+	}
+}
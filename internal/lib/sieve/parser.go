@@ -0,0 +1,232 @@
+package sieve
+
+type argKind int
+
+const (
+	argTag argKind = iota
+	argNumber
+	argStringList
+)
+
+type argument struct {
+	kind    argKind
+	tag     string
+	number  int64
+	strings []string
+	list    bool // значение было задано в квадратных скобках
+	line    int
+}
+
+type rawTest struct {
+	name  string
+	args  []argument
+	tests []rawTest
+	line  int
+}
+
+type rawCommand struct {
+	name     string
+	args     []argument
+	tests    []rawTest
+	block    []rawCommand
+	hasBlock bool
+	line     int
+}
+
+const maxNestingDepth = 32
+
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+}
+
+func parse(src string) ([]rawCommand, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	commands, err := p.commands()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, errorf(tok.line, "unexpected %s", tok)
+	}
+	return commands, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) advance() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	tok := p.advance()
+	if tok.kind != kind {
+		return tok, errorf(tok.line, "expected %s, got %s", what, tok)
+	}
+	return tok, nil
+}
+
+func (p *parser) commands() ([]rawCommand, error) {
+	var commands []rawCommand
+	for {
+		tok := p.peek()
+		if tok.kind == tokEOF || tok.kind == tokRBrace {
+			return commands, nil
+		}
+		cmd, err := p.command()
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, cmd)
+	}
+}
+
+func (p *parser) command() (rawCommand, error) {
+	name, err := p.expect(tokIdentifier, "command")
+	if err != nil {
+		return rawCommand{}, err
+	}
+
+	cmd := rawCommand{name: name.text, line: name.line}
+	cmd.args, cmd.tests, err = p.arguments()
+	if err != nil {
+		return rawCommand{}, err
+	}
+
+	tok := p.advance()
+	switch tok.kind {
+	case tokSemicolon:
+		return cmd, nil
+	case tokLBrace:
+		p.depth++
+		if p.depth > maxNestingDepth {
+			return rawCommand{}, errorf(tok.line, "blocks are nested too deeply")
+		}
+		cmd.block, err = p.commands()
+		if err != nil {
+			return rawCommand{}, err
+		}
+		if _, err := p.expect(tokRBrace, "\"}\""); err != nil {
+			return rawCommand{}, err
+		}
+		p.depth--
+		cmd.hasBlock = true
+		return cmd, nil
+	default:
+		return rawCommand{}, errorf(tok.line, "expected \";\" or block after %q, got %s", cmd.name, tok)
+	}
+}
+
+// arguments = *argument [ test / test-list ]
+func (p *parser) arguments() ([]argument, []rawTest, error) {
+	var args []argument
+	for {
+		tok := p.peek()
+		switch tok.kind {
+		case tokTag:
+			p.advance()
+			args = append(args, argument{kind: argTag, tag: tok.text, line: tok.line})
+		case tokNumber:
+			p.advance()
+			args = append(args, argument{kind: argNumber, number: tok.number, line: tok.line})
+		case tokString:
+			p.advance()
+			args = append(args, argument{kind: argStringList, strings: []string{tok.text}, line: tok.line})
+		case tokLBracket:
+			list, err := p.stringList()
+			if err != nil {
+				return nil, nil, err
+			}
+			args = append(args, list)
+		case tokIdentifier:
+			test, err := p.test()
+			if err != nil {
+				return nil, nil, err
+			}
+			return args, []rawTest{test}, nil
+		case tokLParen:
+			tests, err := p.testList()
+			if err != nil {
+				return nil, nil, err
+			}
+			return args, tests, nil
+		default:
+			return args, nil, nil
+		}
+	}
+}
+
+func (p *parser) stringList() (argument, error) {
+	open := p.advance()
+	arg := argument{kind: argStringList, list: true, line: open.line}
+	for {
+		tok, err := p.expect(tokString, "string")
+		if err != nil {
+			return argument{}, err
+		}
+		arg.strings = append(arg.strings, tok.text)
+
+		tok = p.advance()
+		switch tok.kind {
+		case tokComma:
+			continue
+		case tokRBracket:
+			return arg, nil
+		default:
+			return argument{}, errorf(tok.line, "expected \",\" or \"]\" in string list, got %s", tok)
+		}
+	}
+}
+
+func (p *parser) test() (rawTest, error) {
+	name := p.advance()
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxNestingDepth {
+		return rawTest{}, errorf(name.line, "tests are nested too deeply")
+	}
+
+	args, tests, err := p.arguments()
+	if err != nil {
+		return rawTest{}, err
+	}
+	return rawTest{name: name.text, args: args, tests: tests, line: name.line}, nil
+}
+
+func (p *parser) testList() ([]rawTest, error) {
+	p.advance() // "("
+	var tests []rawTest
+	for {
+		tok := p.peek()
+		if tok.kind != tokIdentifier {
+			return nil, errorf(tok.line, "expected test, got %s", tok)
+		}
+		test, err := p.test()
+		if err != nil {
+			return nil, err
+		}
+		tests = append(tests, test)
+
+		tok = p.advance()
+		switch tok.kind {
+		case tokComma:
+			continue
+		case tokRParen:
+			return tests, nil
+		default:
+			return nil, errorf(tok.line, "expected \",\" or \")\" in test list, got %s", tok)
+		}
+	}
+}
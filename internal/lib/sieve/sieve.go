@@ -0,0 +1,360 @@
+// Package sieve реализует разбор и выполнение скриптов фильтрации почты Sieve (RFC 5228)
+// с расширениями fileinto, envelope, vacation (RFC 5230), imap4flags (RFC 5232) и copy (RFC 3894).
+package sieve
+
+import (
+	"net/textproto"
+	"strings"
+)
+
+const (
+	// MaxScriptSize - максимальный размер скрипта в байтах
+	MaxScriptSize = 64 * 1024
+
+	defaultVacationDays = 7
+	inboxMailbox        = "INBOX"
+)
+
+// Script - скомпилированный скрипт
+type Script struct {
+	commands   []command
+	extensions map[string]struct{}
+}
+
+// Message - письмо, к которому применяется скрипт
+type Message struct {
+	Header       textproto.MIMEHeader
+	EnvelopeFrom string
+	EnvelopeTo   string
+	Size         int64
+}
+
+// FileInto - помещение письма в папку
+type FileInto struct {
+	Mailbox string
+	Flags   []string
+}
+
+// Vacation - автоответ, который нужно отправить
+type Vacation struct {
+	To      string
+	Days    int
+	Subject string
+	From    string
+	Handle  string
+	Reason  string
+	Mime    bool
+}
+
+// Result - результат выполнения скрипта
+type Result struct {
+	Keep      bool
+	KeepFlags []string
+	FileInto  []FileInto
+	Vacation  *Vacation
+}
+
+// Parse разбирает и проверяет скрипт
+func Parse(src string) (*Script, error) {
+	if len(src) > MaxScriptSize {
+		return nil, errorf(1, "script is too large (max %d bytes)", MaxScriptSize)
+	}
+
+	raw, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	commands, extensions, err := compile(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Script{commands: commands, extensions: extensions}, nil
+}
+
+// Mailboxes возвращает имена всех папок, используемых в fileinto
+func (s *Script) Mailboxes() []string {
+	var mailboxes []string
+	seen := make(map[string]struct{})
+	var walk func(commands []command)
+	walk = func(commands []command) {
+		for _, cmd := range commands {
+			switch cmd := cmd.(type) {
+			case fileIntoCommand:
+				if _, ok := seen[cmd.mailbox]; !ok {
+					seen[cmd.mailbox] = struct{}{}
+					mailboxes = append(mailboxes, cmd.mailbox)
+				}
+			case ifCommand:
+				for _, branch := range cmd.branches {
+					walk(branch.body)
+				}
+			}
+		}
+	}
+	walk(s.commands)
+	return mailboxes
+}
+
+// Execute выполняет скрипт над письмом
+func (s *Script) Execute(msg Message) Result {
+	if msg.Header == nil {
+		msg.Header = textproto.MIMEHeader{}
+	}
+
+	e := &executor{msg: msg, implicitKeep: true}
+	e.run(s.commands)
+
+	result := Result{FileInto: e.fileInto, KeepFlags: e.keepFlags}
+	result.Keep = e.explicitKeep || e.implicitKeep
+	if e.implicitKeep && !e.explicitKeep {
+		result.KeepFlags = e.flags
+	}
+	if e.vacation != nil && shouldRespond(msg, e.vacation.addresses) {
+		result.Vacation = &Vacation{
+			To:      responseAddress(msg),
+			Days:    int(e.vacation.days),
+			Subject: e.vacation.subject,
+			From:    e.vacation.from,
+			Handle:  e.vacation.handle,
+			Reason:  e.vacation.reason,
+			Mime:    e.vacation.mime,
+		}
+		if result.Vacation.Handle == "" {
+			result.Vacation.Handle = e.vacation.subject + "\x00" + e.vacation.reason
+		}
+	}
+	return result
+}
+
+type executor struct {
+	msg          Message
+	stopped      bool
+	implicitKeep bool
+	explicitKeep bool
+	keepFlags    []string
+	flags        []string
+	fileInto     []FileInto
+	vacation     *vacationCommand
+}
+
+func (e *executor) run(commands []command) {
+	for _, cmd := range commands {
+		if e.stopped {
+			return
+		}
+
+		switch cmd := cmd.(type) {
+		case ifCommand:
+			for _, branch := range cmd.branches {
+				if branch.cond == nil || e.test(branch.cond) {
+					e.run(branch.body)
+					break
+				}
+			}
+		case stopCommand:
+			e.stopped = true
+		case discardCommand:
+			e.implicitKeep = false
+		case keepCommand:
+			e.explicitKeep = true
+			e.keepFlags = e.currentFlags(cmd.flags, cmd.hasFlags)
+		case fileIntoCommand:
+			if !cmd.copy {
+				e.implicitKeep = false
+			}
+			flags := e.currentFlags(cmd.flags, cmd.hasFlags)
+			if strings.EqualFold(cmd.mailbox, inboxMailbox) {
+				e.explicitKeep = true
+				e.keepFlags = flags
+				continue
+			}
+			e.addFileInto(cmd.mailbox, flags)
+		case vacationCommand:
+			// по RFC 5230 учитывается только одно действие vacation
+			if e.vacation == nil {
+				v := cmd
+				e.vacation = &v
+			}
+		case flagCommand:
+			e.applyFlags(cmd)
+		}
+	}
+}
+
+func (e *executor) addFileInto(mailbox string, flags []string) {
+	for i := range e.fileInto {
+		if e.fileInto[i].Mailbox == mailbox {
+			e.fileInto[i].Flags = normalizeFlags(append(e.fileInto[i].Flags, flags...))
+			return
+		}
+	}
+	e.fileInto = append(e.fileInto, FileInto{Mailbox: mailbox, Flags: flags})
+}
+
+func (e *executor) currentFlags(flags []string, explicit bool) []string {
+	if explicit {
+		return flags
+	}
+	return append([]string(nil), e.flags...)
+}
+
+func (e *executor) applyFlags(cmd flagCommand) {
+	switch cmd.op {
+	case "setflag":
+		e.flags = append([]string(nil), cmd.flags...)
+	case "addflag":
+		e.flags = normalizeFlags(append(e.flags, cmd.flags...))
+	case "removeflag":
+		var kept []string
+		for _, flag := range e.flags {
+			removed := false
+			for _, r := range cmd.flags {
+				if strings.EqualFold(flag, r) {
+					removed = true
+					break
+				}
+			}
+			if !removed {
+				kept = append(kept, flag)
+			}
+		}
+		e.flags = kept
+	}
+}
+
+func (e *executor) test(t test) bool {
+	switch t := t.(type) {
+	case constTest:
+		return t.value
+	case notTest:
+		return !e.test(t.test)
+	case allOfTest:
+		for _, inner := range t.tests {
+			if !e.test(inner) {
+				return false
+			}
+		}
+		return true
+	case anyOfTest:
+		for _, inner := range t.tests {
+			if e.test(inner) {
+				return true
+			}
+		}
+		return false
+	case headerTest:
+		var values []string
+		for _, name := range t.headers {
+			for _, value := range e.msg.Header.Values(name) {
+				values = append(values, decodeHeader(value))
+			}
+		}
+		return matchAny(t.cmp, t.match, values, t.keys)
+	case addressTest:
+		var values []string
+		for _, name := range t.headers {
+			for _, value := range e.msg.Header.Values(name) {
+				for _, addr := range parseAddresses(decodeHeader(value)) {
+					values = append(values, addressPartValue(addr, t.part))
+				}
+			}
+		}
+		return matchAny(t.cmp, t.match, values, t.keys)
+	case envelopeTest:
+		var values []string
+		for _, part := range t.parts {
+			address := e.msg.EnvelopeTo
+			if part == "from" {
+				address = e.msg.EnvelopeFrom
+			}
+			values = append(values, addressPartValue(address, t.part))
+		}
+		return matchAny(t.cmp, t.match, values, t.keys)
+	case existsTest:
+		for _, name := range t.headers {
+			if len(e.msg.Header.Values(name)) == 0 {
+				return false
+			}
+		}
+		return true
+	case sizeTest:
+		if t.over {
+			return e.msg.Size > t.limit
+		}
+		return e.msg.Size < t.limit
+	case hasFlagTest:
+		return matchAny(t.cmp, t.match, e.flags, t.keys)
+	}
+	return false
+}
+
+// shouldRespond проверяет условия отправки автоответа из RFC 5230, раздел 4.5
+func shouldRespond(msg Message, addresses []string) bool {
+	sender := strings.ToLower(responseAddress(msg))
+	if sender == "" || IsAutomated(msg.Header, sender) {
+		return false
+	}
+
+	own := map[string]struct{}{strings.ToLower(msg.EnvelopeTo): {}}
+	for _, addr := range addresses {
+		own[strings.ToLower(addr)] = struct{}{}
+	}
+	if _, ok := own[sender]; ok {
+		return false
+	}
+
+	for _, name := range []string{"To", "Cc", "Bcc", "Resent-To", "Resent-Cc"} {
+		for _, value := range msg.Header.Values(name) {
+			for _, addr := range parseAddresses(decodeHeader(value)) {
+				if _, ok := own[strings.ToLower(addr)]; ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func responseAddress(msg Message) string {
+	if msg.EnvelopeFrom != "" {
+		return msg.EnvelopeFrom
+	}
+	if addrs := parseAddresses(msg.Header.Get("From")); len(addrs) > 0 {
+		return addrs[0]
+	}
+	return ""
+}
+
+// IsAutomated сообщает, отправлено ли письмо автоматически (рассылка, робот, автоответ),
+// на такие письма автоответы не отправляются
+func IsAutomated(header textproto.MIMEHeader, sender string) bool {
+	if v := strings.ToLower(strings.TrimSpace(header.Get("Auto-Submitted"))); v != "" && v != "no" {
+		return true
+	}
+	switch strings.ToLower(strings.TrimSpace(header.Get("Precedence"))) {
+	case "bulk", "list", "junk":
+		return true
+	}
+	for name := range header {
+		if strings.HasPrefix(name, "List-") {
+			return true
+		}
+	}
+	if header.Get("X-Auto-Response-Suppress") != "" {
+		return true
+	}
+
+	local := strings.ToLower(addressPartValue(sender, partLocal))
+	switch {
+	case local == "mailer-daemon", local == "postmaster", local == "listserv", local == "majordomo":
+		return true
+	case strings.HasPrefix(local, "owner-"), strings.HasSuffix(local, "-request"):
+		return true
+	case strings.Contains(strings.ReplaceAll(strings.ReplaceAll(local, "-", ""), "_", ""), "noreply"),
+		strings.Contains(local, "donotreply"):
+		return true
+	}
+	return false
+}
@@ -0,0 +1,242 @@
+package sieve
+
+import (
+	"net/textproto"
+	"reflect"
+	"testing"
+)
+
+func testMessage() Message {
+	header := textproto.MIMEHeader{}
+	header.Set("From", "Boss <boss@example.com>")
+	header.Set("To", "me@a4mail.ru")
+	header.Set("Subject", "=?UTF-8?B?0J7RgtGH0LXRgiDQt9CwINC80LDQuQ==?=")
+	header.Set("X-Spam-Flag", "NO")
+	return Message{
+		Header:       header,
+		EnvelopeFrom: "boss@example.com",
+		EnvelopeTo:   "me@a4mail.ru",
+		Size:         2048,
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+	}{
+		{name: "Missing require for fileinto", script: `fileinto "Work";`},
+		{name: "Unknown command", script: `frobnicate;`},
+		{name: "Unknown extension", script: `require "variables";`},
+		{name: "Require after command", script: `keep; require "fileinto";`},
+		{name: "Missing semicolon", script: `keep`},
+		{name: "Else without if", script: `else { keep; }`},
+		{name: "Unterminated string", script: `require "fileinto`},
+		{name: "Mutually exclusive match types", script: `if header :is :contains "Subject" "x" { stop; }`},
+		{name: "Size without relation", script: `if size 100 { discard; }`},
+		{name: "Redirect is not supported", script: `redirect "a@b.c";`},
+		{name: "Unterminated comment", script: `/* keep;`},
+		{name: "Variables form of setflag", script: `require "imap4flags"; setflag "var" "\\Seen";`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.script); err == nil {
+				t.Fatalf("Parse(%q) expected error, got nil", tt.script)
+			}
+		})
+	}
+}
+
+func TestParse_ErrorLine(t *testing.T) {
+	_, err := Parse("require \"fileinto\";\n\nfileinto;\n")
+	serr, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("expected *Error, got %T (%v)", err, err)
+	}
+	if serr.Line != 3 {
+		t.Errorf("expected error on line 3, got %d", serr.Line)
+	}
+}
+
+func TestScript_Execute(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   Result
+	}{
+		{
+			name:   "Empty script keeps the message",
+			script: ``,
+			want:   Result{Keep: true},
+		},
+		{
+			name: "Header contains decoded subject",
+			script: `require "fileinto";
+if header :contains "subject" "май" { fileinto "Reports"; }`,
+			want: Result{FileInto: []FileInto{{Mailbox: "Reports"}}},
+		},
+		{
+			name: "Address domain with elsif chain",
+			script: `require ["fileinto"];
+if address :is :domain "from" "other.com" { fileinto "Other"; }
+elsif address :domain "from" "EXAMPLE.COM" { fileinto "Work"; }
+else { discard; }`,
+			want: Result{FileInto: []FileInto{{Mailbox: "Work"}}},
+		},
+		{
+			name: "Matches with wildcard and copy keeps the message",
+			script: `require ["fileinto", "copy", "envelope"];
+if envelope :matches "from" "*@example.???" { fileinto :copy "Copies"; }`,
+			want: Result{Keep: true, FileInto: []FileInto{{Mailbox: "Copies"}}},
+		},
+		{
+			name:   "Discard cancels implicit keep",
+			script: `if size :over 1K { discard; }`,
+			want:   Result{},
+		},
+		{
+			name:   "Stop ends execution",
+			script: `stop; discard;`,
+			want:   Result{Keep: true},
+		},
+		{
+			name:   "Octet comparator is case sensitive",
+			script: `if header :comparator "i;octet" :is "X-Spam-Flag" "no" { discard; }`,
+			want:   Result{Keep: true},
+		},
+		{
+			name:   "Anyof, allof and not",
+			script: `if allof (exists ["From", "To"], not exists "List-Id", anyof (false, true)) { discard; }`,
+			want:   Result{},
+		},
+		{
+			name: "Imap4flags apply to keep and fileinto",
+			script: `require ["imap4flags", "fileinto"];
+addflag ["\\Seen", "\\Flagged"];
+removeflag "\\Flagged";
+if hasflag "\\seen" { fileinto :flags "$Work" "Work"; keep; }`,
+			want: Result{
+				Keep:      true,
+				KeepFlags: []string{"\\Seen"},
+				FileInto:  []FileInto{{Mailbox: "Work", Flags: []string{"$Work"}}},
+			},
+		},
+		{
+			name: "Fileinto INBOX is a keep",
+			script: `require "fileinto";
+fileinto "INBOX";`,
+			want: Result{Keep: true},
+		},
+		{
+			name: "Comments are ignored",
+			script: `require "fileinto"; # comment
+/* block
+   comment */
+if header :contains "subject" ["отпуск", /* inline */ "май"]
+{ fileinto "Reports"; }`,
+			want: Result{FileInto: []FileInto{{Mailbox: "Reports"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script, err := Parse(tt.script)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got := script.Execute(testMessage())
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Execute() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScript_Vacation(t *testing.T) {
+	const script = `require "vacation";
+vacation :days 3 :subject "Out of office" text:
+I am on vacation.
+..
+.
+;`
+
+	s, err := Parse(script)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	t.Run("Responds to a personal message", func(t *testing.T) {
+		got := s.Execute(testMessage())
+		if got.Vacation == nil {
+			t.Fatal("expected vacation response")
+		}
+		if got.Vacation.To != "boss@example.com" || got.Vacation.Days != 3 || got.Vacation.Subject != "Out of office" {
+			t.Errorf("unexpected vacation: %+v", got.Vacation)
+		}
+		if got.Vacation.Reason != "I am on vacation.\r\n.\r\n" {
+			t.Errorf("unexpected multi-line reason: %q", got.Vacation.Reason)
+		}
+		if !got.Keep {
+			t.Error("vacation must not cancel implicit keep")
+		}
+	})
+
+	t.Run("Skips mailing lists", func(t *testing.T) {
+		msg := testMessage()
+		msg.Header.Set("List-Id", "<news.example.com>")
+		if got := s.Execute(msg); got.Vacation != nil {
+			t.Errorf("expected no vacation for mailing list, got %+v", got.Vacation)
+		}
+	})
+
+	t.Run("Skips no-reply senders", func(t *testing.T) {
+		msg := testMessage()
+		msg.EnvelopeFrom = "no-reply@example.com"
+		if got := s.Execute(msg); got.Vacation != nil {
+			t.Errorf("expected no vacation for no-reply sender, got %+v", got.Vacation)
+		}
+	})
+
+	t.Run("Skips messages not addressed to the user", func(t *testing.T) {
+		msg := testMessage()
+		msg.Header.Set("To", "team@example.com")
+		if got := s.Execute(msg); got.Vacation != nil {
+			t.Errorf("expected no vacation for indirect recipient, got %+v", got.Vacation)
+		}
+	})
+}
+
+func TestScript_Mailboxes(t *testing.T) {
+	s, err := Parse(`require "fileinto";
+if true { fileinto "A"; } else { fileinto "B"; fileinto "A"; }`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got, want := s.Mailboxes(), []string{"A", "B"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Mailboxes() = %v, want %v", got, want)
+	}
+}
+
+func TestGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{"*", "", true},
+		{"a*c", "abbbc", true},
+		{"a?c", "abc", true},
+		{"a?c", "ac", false},
+		{"*@example.com", "boss@example.com", true},
+		{"\\*", "*", true},
+		{"\\*", "a", false},
+		{"ф*", "файл", true},
+	}
+
+	for _, tt := range tests {
+		if got := glob(tt.pattern, tt.value); got != tt.want {
+			t.Errorf("glob(%q, %q) = %v, want %v", tt.pattern, tt.value, got, tt.want)
+		}
+	}
+}
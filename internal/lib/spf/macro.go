@@ -0,0 +1,145 @@
+package spf
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// macroDelimiters - допустимые разделители в макросе (RFC 7208, 7.1)
+const macroDelimiters = ".-+,/_="
+
+// expand раскрывает макросы в доменном имени механизма или модификатора
+func (ev *evaluation) expand(spec, domainName string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(spec); i++ {
+		if spec[i] != '%' {
+			b.WriteByte(spec[i])
+			continue
+		}
+		if i+1 == len(spec) {
+			return "", permError("invalid macro in %q", spec)
+		}
+
+		i++
+		switch spec[i] {
+		case '%':
+			b.WriteByte('%')
+		case '_':
+			b.WriteByte(' ')
+		case '-':
+			b.WriteString("%20")
+		case '{':
+			end := strings.IndexByte(spec[i:], '}')
+			if end < 0 {
+				return "", permError("invalid macro in %q", spec)
+			}
+			value, err := ev.macro(spec[i+1:i+end], domainName)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(value)
+			i += end
+		default:
+			return "", permError("invalid macro in %q", spec)
+		}
+	}
+
+	name := strings.TrimSuffix(b.String(), ".")
+	// Слишком длинное имя укорачивается на метки слева (RFC 7208, 7.3)
+	for len(name) > maxDomainLength {
+		_, name, _ = strings.Cut(name, ".")
+	}
+	return name, nil
+}
+
+// macro раскрывает один макрос вида "{ir}" или "{d2}" без фигурных скобок
+func (ev *evaluation) macro(expr, domainName string) (string, error) {
+	if expr == "" {
+		return "", permError("empty macro")
+	}
+
+	var value string
+	letter := expr[0]
+	switch letter | 0x20 {
+	case 's':
+		value = ev.localPart + "@" + ev.senderDomain
+	case 'l':
+		value = ev.localPart
+	case 'o':
+		value = ev.senderDomain
+	case 'd':
+		value = domainName
+	case 'i':
+		value = ev.ipName()
+	case 'p':
+		// Проверенное имя узла требует лишних запросов и не рекомендуется (RFC 7208, 7.3)
+		value = "unknown"
+	case 'v':
+		value = "ip6"
+		if ev.ip.To4() != nil {
+			value = "in-addr"
+		}
+	case 'h':
+		value = ev.helo
+	default:
+		return "", permError("unknown macro letter %q", letter)
+	}
+
+	rest := expr[1:]
+	digits := 0
+	for digits < len(rest) && rest[digits] >= '0' && rest[digits] <= '9' {
+		digits++
+	}
+	keep := 0
+	if digits > 0 {
+		var err error
+		if keep, err = strconv.Atoi(rest[:digits]); err != nil || keep == 0 {
+			return "", permError("invalid macro transformer in %q", expr)
+		}
+	}
+	rest = rest[digits:]
+
+	reverse := false
+	if rest != "" && rest[0]|0x20 == 'r' {
+		reverse, rest = true, rest[1:]
+	}
+	delimiters := rest
+	if delimiters == "" {
+		delimiters = "."
+	}
+	if strings.Trim(delimiters, macroDelimiters) != "" {
+		return "", permError("invalid macro delimiter in %q", expr)
+	}
+
+	parts := strings.FieldsFunc(value, func(r rune) bool { return strings.ContainsRune(delimiters, r) })
+	if reverse {
+		for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+			parts[i], parts[j] = parts[j], parts[i]
+		}
+	}
+	if keep > 0 && keep < len(parts) {
+		parts = parts[len(parts)-keep:]
+	}
+	value = strings.Join(parts, ".")
+
+	// Заглавная буква макроса требует URL-кодирования значения
+	if letter >= 'A' && letter <= 'Z' {
+		value = strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+	}
+	return value, nil
+}
+
+// ipName записывает адрес клиента для макроса i: IPv4 через точки,
+// IPv6 - по полубайтам через точки (RFC 7208, 7.3)
+func (ev *evaluation) ipName() string {
+	if ipv4 := ev.ip.To4(); ipv4 != nil {
+		return ipv4.String()
+	}
+	nibbles := make([]string, 0, 2*len(ev.ip))
+	for _, b := range ev.ip.To16() {
+		nibbles = append(nibbles, fmt.Sprintf("%x", b>>4), fmt.Sprintf("%x", b&0x0f))
+	}
+	return strings.Join(nibbles, ".")
+}
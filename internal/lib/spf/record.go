@@ -0,0 +1,173 @@
+package spf
+
+import (
+	"2025_2_a4code/internal/domain"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// mechanism - механизм SPF-записи вместе с квалификатором
+type mechanism struct {
+	qualifier domain.SPFStatus
+	name      string
+	// value - доменное имя с макросами после двоеточия, пустое - текущий домен
+	value string
+	// Длины префиксов для a и mx
+	cidr4, cidr6 int
+	// network - сеть для ip4 и ip6
+	network *net.IPNet
+	raw     string
+}
+
+// dualCIDR отделяет от доменного имени префиксы вида "/24//64"
+var dualCIDR = regexp.MustCompile(`^(.*?)(?:/(\d+))?(?://(\d+))?$`)
+
+// parseRecord разбирает запись "v=spf1 ..." на механизмы и модификатор redirect
+func parseRecord(record string) ([]mechanism, string, error) {
+	var mechanisms []mechanism
+	var redirect string
+	var hasRedirect, hasExp bool
+
+	for _, term := range strings.Fields(record)[1:] {
+		if name, value, ok := modifier(term); ok {
+			switch name {
+			case "redirect":
+				if hasRedirect || value == "" {
+					return nil, "", permError("invalid redirect modifier")
+				}
+				redirect, hasRedirect = value, true
+			case "exp":
+				// Пояснение к отказу не используется, но проверяется на повтор
+				if hasExp {
+					return nil, "", permError("duplicate exp modifier")
+				}
+				hasExp = true
+			}
+			continue
+		}
+
+		m, err := parseMechanism(term)
+		if err != nil {
+			return nil, "", err
+		}
+		mechanisms = append(mechanisms, m)
+	}
+	return mechanisms, redirect, nil
+}
+
+// modifier распознает модификатор "name=value". Неизвестные модификаторы пропускаются
+func modifier(term string) (string, string, bool) {
+	i := strings.IndexAny(term, "=:/")
+	if i <= 0 || term[i] != '=' {
+		return "", "", false
+	}
+	name := term[:i]
+	for j, r := range name {
+		isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		if !isLetter && (j == 0 || !(r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.')) {
+			return "", "", false
+		}
+	}
+	return strings.ToLower(name), term[i+1:], true
+}
+
+func parseMechanism(term string) (mechanism, error) {
+	m := mechanism{qualifier: domain.SPFPass, cidr4: 32, cidr6: 128, raw: term}
+	switch term[0] {
+	case '+':
+		term = term[1:]
+	case '-':
+		m.qualifier, term = domain.SPFFail, term[1:]
+	case '~':
+		m.qualifier, term = domain.SPFSoftFail, term[1:]
+	case '?':
+		m.qualifier, term = domain.SPFNeutral, term[1:]
+	}
+
+	name, rest := term, ""
+	if i := strings.IndexAny(term, ":/"); i >= 0 {
+		name, rest = term[:i], term[i:]
+	}
+	m.name = strings.ToLower(name)
+
+	switch m.name {
+	case "all":
+		if rest != "" {
+			return m, permError("invalid mechanism %s", m.raw)
+		}
+	case "include", "exists":
+		if !strings.HasPrefix(rest, ":") || len(rest) == 1 {
+			return m, permError("%s requires a domain", m.raw)
+		}
+		m.value = rest[1:]
+	case "ptr":
+		if rest != "" {
+			if !strings.HasPrefix(rest, ":") || len(rest) == 1 {
+				return m, permError("invalid mechanism %s", m.raw)
+			}
+			m.value = rest[1:]
+		}
+	case "a", "mx":
+		parts := dualCIDR.FindStringSubmatch(rest)
+		spec := parts[1]
+		if spec != "" {
+			if !strings.HasPrefix(spec, ":") || len(spec) == 1 {
+				return m, permError("invalid mechanism %s", m.raw)
+			}
+			m.value = spec[1:]
+		}
+		var err error
+		if m.cidr4, err = prefixLength(parts[2], 32); err != nil {
+			return m, permError("invalid prefix in %s", m.raw)
+		}
+		if m.cidr6, err = prefixLength(parts[3], 128); err != nil {
+			return m, permError("invalid prefix in %s", m.raw)
+		}
+	case "ip4", "ip6":
+		network, err := parseNetwork(strings.TrimPrefix(rest, ":"), m.name == "ip4")
+		if err != nil || !strings.HasPrefix(rest, ":") {
+			return m, permError("invalid network in %s", m.raw)
+		}
+		m.network = network
+	default:
+		return m, permError("unknown mechanism %s", m.raw)
+	}
+	return m, nil
+}
+
+// prefixLength разбирает длину префикса, пустая строка - весь адрес
+func prefixLength(s string, bits int) (int, error) {
+	if s == "" {
+		return bits, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || n > bits {
+		return 0, strconv.ErrRange
+	}
+	return n, nil
+}
+
+// parseNetwork разбирает адрес или сеть механизма ip4 или ip6
+func parseNetwork(s string, ipv4 bool) (*net.IPNet, error) {
+	address, length, _ := strings.Cut(s, "/")
+	ip := net.ParseIP(address)
+	if ip == nil || (ip.To4() != nil) != ipv4 || strings.Contains(address, ":") == ipv4 {
+		return nil, strconv.ErrSyntax
+	}
+
+	bits := 128
+	if ipv4 {
+		ip, bits = ip.To4(), 32
+	}
+	if strings.Contains(s, "/") && length == "" {
+		return nil, strconv.ErrSyntax
+	}
+	ones, err := prefixLength(length, bits)
+	if err != nil {
+		return nil, err
+	}
+	mask := net.CIDRMask(ones, bits)
+	return &net.IPNet{IP: ip.Mask(mask), Mask: mask}, nil
+}
@@ -0,0 +1,373 @@
+// Package spf проверяет, разрешено ли узлу отправлять почту от имени домена (RFC 7208)
+package spf
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+const (
+	// maxLookups - ограничение числа механизмов и модификаторов, которым нужны запросы DNS (RFC 7208, 4.6.4)
+	maxLookups = 10
+	// maxVoidLookups - ограничение числа запросов, на которые нет ни одной записи
+	maxVoidLookups = 2
+	// maxNames - сколько имен из ответа MX или PTR проверяется
+	maxNames = 10
+	// maxDomainLength - ограничение длины доменного имени
+	maxDomainLength = 253
+)
+
+// Resolver - запросы DNS, нужные для проверки. Ему удовлетворяет *net.Resolver,
+// в тестах подставляется зона в памяти
+type Resolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+	LookupIP(ctx context.Context, network, host string) ([]net.IP, error)
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupAddr(ctx context.Context, addr string) ([]string, error)
+}
+
+// Result - результат проверки
+type Result struct {
+	Status domain.SPFStatus
+	// Domain - проверенный домен
+	Domain string
+	// Reason объясняет результат
+	Reason string
+}
+
+// Checker проверяет SPF-записи доменов отправителей
+type Checker struct {
+	resolver Resolver
+}
+
+// NewChecker создает проверку SPF. resolver = nil - записи берутся из DNS
+func NewChecker(resolver Resolver) *Checker {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	return &Checker{resolver: resolver}
+}
+
+// Check проверяет, может ли узел с адресом ip отправлять письма с адресом sender
+// из MAIL FROM. Для пустого MAIL FROM проверяется имя из HELO (RFC 7208, 2.4)
+func (c *Checker) Check(ctx context.Context, ip net.IP, helo, sender string) Result {
+	localPart, domainName := "postmaster", helo
+	if at := strings.LastIndexByte(sender, '@'); at >= 0 {
+		domainName = sender[at+1:]
+		if at > 0 {
+			localPart = sender[:at]
+		}
+	}
+	domainName = strings.ToLower(strings.TrimSuffix(domainName, "."))
+
+	result := Result{Domain: domainName}
+	if !validDomain(domainName) {
+		result.Status = domain.SPFNone
+		result.Reason = "no valid domain to check"
+		return result
+	}
+	if ipv4 := ip.To4(); ipv4 != nil {
+		ip = ipv4
+	}
+
+	ev := &evaluation{resolver: c.resolver, ip: ip, localPart: localPart, senderDomain: domainName, helo: helo}
+	result.Status, result.Reason = ev.checkHost(ctx, domainName)
+	return result
+}
+
+// failure прерывает проверку с результатом temperror или permerror
+type failure struct {
+	status domain.SPFStatus
+	reason string
+}
+
+func (f *failure) Error() string {
+	return f.reason
+}
+
+func permError(format string, args ...any) error {
+	return &failure{status: domain.SPFPermError, reason: fmt.Sprintf(format, args...)}
+}
+
+func tempError(format string, args ...any) error {
+	return &failure{status: domain.SPFTempError, reason: fmt.Sprintf(format, args...)}
+}
+
+// evaluation - состояние одной проверки, общее для всех вложенных include и redirect
+type evaluation struct {
+	resolver     Resolver
+	ip           net.IP
+	localPart    string
+	senderDomain string
+	helo         string
+
+	lookups int
+	voids   int
+}
+
+// checkHost - функция check_host() из RFC 7208, 4
+func (ev *evaluation) checkHost(ctx context.Context, domainName string) (domain.SPFStatus, string) {
+	status, reason, err := ev.evaluate(ctx, domainName)
+	var f *failure
+	if errors.As(err, &f) {
+		return f.status, f.reason
+	}
+	return status, reason
+}
+
+func (ev *evaluation) evaluate(ctx context.Context, domainName string) (domain.SPFStatus, string, error) {
+	record, err := ev.record(ctx, domainName)
+	if err != nil {
+		return "", "", err
+	}
+	if record == "" {
+		return domain.SPFNone, "no SPF record for " + domainName, nil
+	}
+
+	// Синтаксическая ошибка в любом месте записи - сразу permerror (RFC 7208, 4.6)
+	mechanisms, redirect, err := parseRecord(record)
+	if err != nil {
+		return "", "", err
+	}
+
+	for _, m := range mechanisms {
+		matched, err := ev.match(ctx, m, domainName)
+		if err != nil {
+			return "", "", err
+		}
+		if matched {
+			return m.qualifier, fmt.Sprintf("%s matched %s", m.raw, domainName), nil
+		}
+	}
+
+	if redirect == "" {
+		return domain.SPFNeutral, "no mechanism matched " + domainName, nil
+	}
+	if err := ev.countLookup(); err != nil {
+		return "", "", err
+	}
+	target, err := ev.expand(redirect, domainName)
+	if err != nil {
+		return "", "", err
+	}
+	status, reason := ev.checkHost(ctx, target)
+	if status == domain.SPFNone {
+		return "", "", permError("redirect target %s has no SPF record", target)
+	}
+	return status, reason, nil
+}
+
+// record находит SPF-запись домена. Пустая строка - записи нет
+func (ev *evaluation) record(ctx context.Context, domainName string) (string, error) {
+	records, err := ev.resolver.LookupTXT(ctx, domainName)
+	if isNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", tempError("DNS lookup for %s failed", domainName)
+	}
+
+	var found []string
+	for _, record := range records {
+		version, _, _ := strings.Cut(record, " ")
+		if strings.EqualFold(version, "v=spf1") {
+			found = append(found, record)
+		}
+	}
+	if len(found) > 1 {
+		return "", permError("multiple SPF records for %s", domainName)
+	}
+	if len(found) == 0 {
+		return "", nil
+	}
+	return found[0], nil
+}
+
+func (ev *evaluation) match(ctx context.Context, m mechanism, domainName string) (bool, error) {
+	switch m.name {
+	case "all":
+		return true, nil
+	case "ip4":
+		return ev.ip.To4() != nil && m.network.Contains(ev.ip), nil
+	case "ip6":
+		return ev.ip.To4() == nil && m.network.Contains(ev.ip), nil
+	}
+
+	if err := ev.countLookup(); err != nil {
+		return false, err
+	}
+	target := domainName
+	if m.value != "" {
+		var err error
+		if target, err = ev.expand(m.value, domainName); err != nil {
+			return false, err
+		}
+	}
+
+	switch m.name {
+	case "include":
+		status, reason := ev.checkHost(ctx, target)
+		switch status {
+		case domain.SPFPass:
+			return true, nil
+		case domain.SPFFail, domain.SPFSoftFail, domain.SPFNeutral:
+			return false, nil
+		case domain.SPFTempError:
+			return false, tempError("%s", reason)
+		}
+		return false, permError("include:%s: %s", target, reason)
+	case "a":
+		ips, err := ev.lookupIP(ctx, ev.network(), target, true)
+		if err != nil {
+			return false, err
+		}
+		return ev.contains(ips, m), nil
+	case "mx":
+		return ev.matchMX(ctx, target, m)
+	case "ptr":
+		return ev.matchPTR(ctx, target)
+	case "exists":
+		// exists всегда запрашивает запись A, независимо от адреса клиента
+		ips, err := ev.lookupIP(ctx, "ip4", target, true)
+		return len(ips) > 0, err
+	}
+	return false, permError("unknown mechanism %s", m.raw)
+}
+
+func (ev *evaluation) matchMX(ctx context.Context, target string, m mechanism) (bool, error) {
+	records, err := ev.resolver.LookupMX(ctx, target)
+	if isNotFound(err) {
+		return false, ev.countVoid()
+	}
+	if err != nil {
+		return false, tempError("DNS lookup for %s failed", target)
+	}
+	if len(records) > maxNames {
+		return false, permError("too many MX records for %s", target)
+	}
+
+	for _, mx := range records {
+		ips, err := ev.lookupIP(ctx, ev.network(), strings.TrimSuffix(mx.Host, "."), false)
+		if err != nil {
+			return false, err
+		}
+		if ev.contains(ips, m) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// matchPTR проверяет, что обратная запись адреса, подтвержденная прямой,
+// указывает в домен target. Механизм устарел (RFC 7208, 5.5), но еще встречается
+func (ev *evaluation) matchPTR(ctx context.Context, target string) (bool, error) {
+	names, err := ev.resolver.LookupAddr(ctx, ev.ip.String())
+	if err != nil {
+		// Ошибка обратного запроса означает, что механизм не подошел
+		return false, nil
+	}
+	if len(names) > maxNames {
+		names = names[:maxNames]
+	}
+
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSuffix(name, "."))
+		if name != target && !strings.HasSuffix(name, "."+target) {
+			continue
+		}
+		ips, err := ev.lookupIP(ctx, ev.network(), name, false)
+		if err != nil {
+			continue
+		}
+		for _, ip := range ips {
+			if ip.Equal(ev.ip) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// lookupIP получает адреса имени. void - учитывать ли пустой ответ в ограничении maxVoidLookups
+func (ev *evaluation) lookupIP(ctx context.Context, network, name string, void bool) ([]net.IP, error) {
+	ips, err := ev.resolver.LookupIP(ctx, network, name)
+	if isNotFound(err) {
+		if void {
+			return nil, ev.countVoid()
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, tempError("DNS lookup for %s failed", name)
+	}
+	return ips, nil
+}
+
+// network - семейство адресов клиента в терминах net.Resolver.LookupIP
+func (ev *evaluation) network() string {
+	if ev.ip.To4() != nil {
+		return "ip4"
+	}
+	return "ip6"
+}
+
+// contains сравнивает адрес клиента с адресами ips с учетом префиксов механизма
+func (ev *evaluation) contains(ips []net.IP, m mechanism) bool {
+	mask := net.CIDRMask(m.cidr6, 128)
+	if ev.ip.To4() != nil {
+		mask = net.CIDRMask(m.cidr4, 32)
+	}
+	client := ev.ip.Mask(mask)
+
+	for _, ip := range ips {
+		if ipv4 := ip.To4(); ipv4 != nil {
+			ip = ipv4
+		}
+		if len(ip) == len(ev.ip) && ip.Mask(mask).Equal(client) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ev *evaluation) countLookup() error {
+	ev.lookups++
+	if ev.lookups > maxLookups {
+		return permError("too many DNS lookups")
+	}
+	return nil
+}
+
+func (ev *evaluation) countVoid() error {
+	ev.voids++
+	if ev.voids > maxVoidLookups {
+		return permError("too many void DNS lookups")
+	}
+	return nil
+}
+
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+// validDomain проверяет, что имя состоит хотя бы из двух меток допустимой длины (RFC 7208, 4.3)
+func validDomain(name string) bool {
+	if len(name) > maxDomainLength {
+		return false
+	}
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if len(label) == 0 || len(label) > 63 {
+			return false
+		}
+	}
+	return true
+}
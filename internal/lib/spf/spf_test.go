@@ -0,0 +1,218 @@
+package spf
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/lib/dnstest"
+	"context"
+	"net"
+	"strings"
+	"testing"
+)
+
+func newZone() *dnstest.Zone {
+	return &dnstest.Zone{
+		TXT: map[string][]string{
+			"example.com":            {"google-site-verification=abc", "v=spf1 ip4:192.0.2.0/24 a:mail.example.com mx include:_spf.partner.net -all"},
+			"_spf.partner.net":       {"v=spf1 ip6:2001:db8::/32 ~all"},
+			"soft.example.com":       {"v=spf1 ~all"},
+			"neutral.example.com":    {"v=spf1 ip4:203.0.113.1"},
+			"redirect.example.com":   {"v=spf1 redirect=example.com"},
+			"dangling.example.com":   {"v=spf1 redirect=nospf.example.com"},
+			"double.example.com":     {"v=spf1 -all", "v=spf1 +all"},
+			"typo.example.com":       {"v=spf1 ip4:192.0.2.1 foo:bar -all"},
+			"broken-inc.example.com": {"v=spf1 include:down.example.com -all"},
+			"loop.example.com":       {"v=spf1 include:loop.example.com -all"},
+			"voids.example.com":      {"v=spf1 a:n1.example.com a:n2.example.com a:n3.example.com -all"},
+			"exists.example.com":     {"v=spf1 exists:%{ir}.%{l1r-}.allow.example.com -all"},
+			"cidr.example.com":       {"v=spf1 a:mail.example.com/24//64 -all"},
+			"ptr.example.com":        {"v=spf1 ptr -all"},
+			"helo.example.com":       {"v=spf1 a -all"},
+		},
+		A: map[string][]string{
+			"mail.example.com":                {"198.51.100.10", "2001:db8:1::10"},
+			"mx.example.com":                  {"198.51.100.20"},
+			"3.2.0.192.bob.allow.example.com": {"127.0.0.2"},
+			"relay.ptr.example.com":           {"198.51.100.30"},
+			"helo.example.com":                {"198.51.100.40"},
+		},
+		MX: map[string][]string{
+			"example.com": {"mx.example.com."},
+		},
+		PTR: map[string][]string{
+			"198.51.100.30": {"relay.ptr.example.com."},
+		},
+		Broken: map[string]bool{"down.example.com": true, "tempfail.example.com": true},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		ip     string
+		helo   string
+		sender string
+		want   domain.SPFStatus
+	}{
+		{name: "IP4Network", ip: "192.0.2.55", sender: "user@example.com", want: domain.SPFPass},
+		{name: "A", ip: "198.51.100.10", sender: "user@example.com", want: domain.SPFPass},
+		{name: "AIPv6", ip: "2001:db8:1::10", sender: "user@example.com", want: domain.SPFPass},
+		{name: "MX", ip: "198.51.100.20", sender: "user@example.com", want: domain.SPFPass},
+		{name: "Include", ip: "2001:db8:ffff::1", sender: "user@example.com", want: domain.SPFPass},
+		{name: "Fail", ip: "203.0.113.5", sender: "user@Example.COM", want: domain.SPFFail},
+		{name: "SoftFail", ip: "203.0.113.5", sender: "user@soft.example.com", want: domain.SPFSoftFail},
+		{name: "NeutralByDefault", ip: "203.0.113.5", sender: "user@neutral.example.com", want: domain.SPFNeutral},
+		{name: "NoRecord", ip: "203.0.113.5", sender: "user@nospf.example.com", want: domain.SPFNone},
+		{name: "Redirect", ip: "192.0.2.1", sender: "user@redirect.example.com", want: domain.SPFPass},
+		{name: "RedirectWithoutRecord", ip: "192.0.2.1", sender: "user@dangling.example.com", want: domain.SPFPermError},
+		{name: "MultipleRecords", ip: "192.0.2.1", sender: "user@double.example.com", want: domain.SPFPermError},
+		{name: "SyntaxError", ip: "192.0.2.1", sender: "user@typo.example.com", want: domain.SPFPermError},
+		{name: "DNSFailure", ip: "192.0.2.1", sender: "user@tempfail.example.com", want: domain.SPFTempError},
+		{name: "IncludeDNSFailure", ip: "192.0.2.1", sender: "user@broken-inc.example.com", want: domain.SPFTempError},
+		{name: "IncludeLoop", ip: "192.0.2.1", sender: "user@loop.example.com", want: domain.SPFPermError},
+		{name: "VoidLookups", ip: "192.0.2.1", sender: "user@voids.example.com", want: domain.SPFPermError},
+		{name: "ExistsMacro", ip: "192.0.2.3", sender: "bob@exists.example.com", want: domain.SPFPass},
+		{name: "ExistsMacroNoMatch", ip: "192.0.2.4", sender: "bob@exists.example.com", want: domain.SPFFail},
+		{name: "CIDR", ip: "198.51.100.99", sender: "user@cidr.example.com", want: domain.SPFPass},
+		{name: "CIDRIPv6", ip: "2001:db8:1::ffff", sender: "user@cidr.example.com", want: domain.SPFPass},
+		{name: "PTR", ip: "198.51.100.30", sender: "user@ptr.example.com", want: domain.SPFPass},
+		{name: "PTRNoMatch", ip: "198.51.100.31", sender: "user@ptr.example.com", want: domain.SPFFail},
+		{name: "NullSenderUsesHelo", ip: "198.51.100.40", helo: "helo.example.com", sender: "", want: domain.SPFPass},
+		{name: "InvalidDomain", ip: "192.0.2.1", helo: "localhost", sender: "", want: domain.SPFNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(newZone())
+			result := checker.Check(context.Background(), net.ParseIP(tt.ip), tt.helo, tt.sender)
+			if result.Status != tt.want {
+				t.Errorf("Check() = %+v, want %s", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck_Domain(t *testing.T) {
+	checker := NewChecker(newZone())
+
+	result := checker.Check(context.Background(), net.ParseIP("192.0.2.1"), "mx.other.org", "User@Example.COM.")
+	if result.Domain != "example.com" {
+		t.Errorf("Domain = %q, want example.com", result.Domain)
+	}
+	result = checker.Check(context.Background(), net.ParseIP("192.0.2.1"), "mx.other.org", "")
+	if result.Domain != "mx.other.org" {
+		t.Errorf("Domain = %q, want mx.other.org", result.Domain)
+	}
+}
+
+func TestCheck_LookupLimit(t *testing.T) {
+	zone := newZone()
+	var chain []string
+	for i := 0; i < 12; i++ {
+		name := "n" + strings.Repeat("x", i) + ".example.com"
+		chain = append(chain, name)
+	}
+	for i, name := range chain {
+		next := "-all"
+		if i+1 < len(chain) {
+			next = "include:" + chain[i+1] + " -all"
+		}
+		zone.TXT[name] = []string{"v=spf1 " + next}
+	}
+
+	result := NewChecker(zone).Check(context.Background(), net.ParseIP("192.0.2.1"), "", "user@"+chain[0])
+	if result.Status != domain.SPFPermError {
+		t.Errorf("Check() = %+v, want permerror", result)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	// Примеры из RFC 7208, 7.4
+	ev := &evaluation{
+		ip:           net.ParseIP("192.0.2.3").To4(),
+		localPart:    "strong-bad",
+		senderDomain: "email.example.com",
+		helo:         "mx.example.org",
+	}
+	ev6 := *ev
+	ev6.ip = net.ParseIP("2001:db8::cb01")
+
+	tests := []struct {
+		ev   *evaluation
+		spec string
+		want string
+	}{
+		{ev, "%{s}", "strong-bad@email.example.com"},
+		{ev, "%{o}", "email.example.com"},
+		{ev, "%{d}", "email.example.com"},
+		{ev, "%{d4}", "email.example.com"},
+		{ev, "%{d3}", "email.example.com"},
+		{ev, "%{d2}", "example.com"},
+		{ev, "%{d1}", "com"},
+		{ev, "%{dr}", "com.example.email"},
+		{ev, "%{d2r}", "example.email"},
+		{ev, "%{l}", "strong-bad"},
+		{ev, "%{l-}", "strong.bad"},
+		{ev, "%{lr}", "strong-bad"},
+		{ev, "%{lr-}", "bad.strong"},
+		{ev, "%{l1r-}", "strong"},
+		{ev, "%{ir}.%{v}._spf.%{d2}", "3.2.0.192.in-addr._spf.example.com"},
+		{ev, "%{lr-}.lp._spf.%{d2}", "bad.strong.lp._spf.example.com"},
+		{ev, "%{lr-}.lp.%{ir}.%{v}._spf.%{d2}", "bad.strong.lp.3.2.0.192.in-addr._spf.example.com"},
+		{ev, "%{ir}.%{v}.%{l1r-}.lp._spf.%{d2}", "3.2.0.192.in-addr.strong.lp._spf.example.com"},
+		{ev, "%{d2}.trusted-domains.example.net", "example.com.trusted-domains.example.net"},
+		{ev, "%{h}%%%_%-", "mx.example.org% %20"},
+		{&ev6, "%{ir}.%{v}._spf.%{d2}", "1.0.b.c.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6._spf.example.com"},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.ev.expand(tt.spec, "email.example.com")
+		if err != nil {
+			t.Errorf("expand(%q) error = %v", tt.spec, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("expand(%q) = %q, want %q", tt.spec, got, tt.want)
+		}
+	}
+
+	for _, spec := range []string{"%", "%{", "%{x}", "%{d0}", "%{d!}", "%a"} {
+		if _, err := ev.expand(spec, "email.example.com"); err == nil {
+			t.Errorf("expand(%q) error = nil, want permerror", spec)
+		}
+	}
+}
+
+func TestParseRecord(t *testing.T) {
+	valid := []string{
+		"v=spf1",
+		"v=spf1 +all",
+		"v=spf1 a mx/24 a:example.com//64 mx:example.com/24//64 ptr ptr:example.com -all",
+		"v=spf1 ip4:192.0.2.1 ip4:192.0.2.0/24 ip6:2001:db8::1 ip6:2001:db8::/32 ?all",
+		"v=spf1 include:example.com exists:%{i}.example.com ~all",
+		"v=spf1 redirect=example.com exp=explain.example.com unknown-modifier=x",
+	}
+	for _, record := range valid {
+		if _, _, err := parseRecord(record); err != nil {
+			t.Errorf("parseRecord(%q) error = %v", record, err)
+		}
+	}
+
+	invalid := []string{
+		"v=spf1 all:example.com",
+		"v=spf1 include",
+		"v=spf1 include:",
+		"v=spf1 ip4:2001:db8::1",
+		"v=spf1 ip6:192.0.2.1",
+		"v=spf1 ip4:192.0.2.0/33",
+		"v=spf1 a/33",
+		"v=spf1 mx//129",
+		"v=spf1 redirect=a.example.com redirect=b.example.com",
+		"v=spf1 exp=a.example.com exp=b.example.com",
+		"v=spf1 foo",
+	}
+	for _, record := range invalid {
+		if _, _, err := parseRecord(record); err == nil {
+			t.Errorf("parseRecord(%q) error = nil, want permerror", record)
+		}
+	}
+}
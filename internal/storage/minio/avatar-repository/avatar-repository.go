@@ -1,6 +1,7 @@
 package avatar_repository
 
 import (
+	"2025_2_a4code/internal/domain"
 	"context"
 	"io"
 	"net/url"
@@ -32,6 +33,25 @@ func (repo *AvatarRepository) UploadFile(ctx context.Context, objectName string,
 	return err
 }
 
+// GetFile открывает объект хранилища на чтение. Отсутствующий объект - domain.ErrFileNotFound
+func (repo *AvatarRepository) GetFile(ctx context.Context, objectName string) (io.ReadCloser, error) {
+	object, err := repo.Client.GetObject(ctx, repo.BucketName, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// GetObject не обращается к хранилищу, ошибки видны только при первом запросе
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, domain.ErrFileNotFound
+		}
+		return nil, err
+	}
+
+	return object, nil
+}
+
 func (repo *AvatarRepository) GetAvatarPresignedURL(ctx context.Context, objectName string, duration time.Duration) (*url.URL, error) {
 	presignedURL, err := repo.Client.PresignedGetObject(ctx, repo.BucketName, objectName, duration, nil)
 	if err != nil {
@@ -62,6 +82,12 @@ func (repo *AvatarRepository) GetAvatarPresignedURL(ctx context.Context, objectN
 
 	return presignedURL, nil
 }
+
+// DeleteFile удаляет объект хранилища. Удаление отсутствующего объекта не считается ошибкой
+func (repo *AvatarRepository) DeleteFile(ctx context.Context, objectName string) error {
+	return repo.Client.RemoveObject(ctx, repo.BucketName, objectName, minio.RemoveObjectOptions{})
+}
+
 func (repo *AvatarRepository) DeleteAvatar(ctx context.Context, objectName string) error {
 	return repo.Client.RemoveObject(ctx, repo.BucketName, objectName, minio.RemoveObjectOptions{})
 }
@@ -0,0 +1,154 @@
+package alias_repository
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/jackc/pgconn"
+)
+
+// uniqueViolation - код ошибки PostgreSQL при нарушении уникального индекса
+const uniqueViolation = "23505"
+
+type AliasRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *AliasRepository {
+	return &AliasRepository{db: db}
+}
+
+func (repo *AliasRepository) GetAliases(ctx context.Context, profileID int64) ([]domain.Alias, error) {
+	const op = "storage.postgresql.alias.GetAliases"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        SELECT pa.id, pa.username || '@' || pa.domain, pa.created_at
+        FROM profile_alias pa
+        JOIN profile p ON p.id = pa.profile_id
+        WHERE p.base_profile_id = $1
+        ORDER BY pa.id`
+
+	log.Debug("Querying aliases...")
+	rows, err := repo.db.QueryContext(ctx, query, profileID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	aliases := make([]domain.Alias, 0)
+	for rows.Next() {
+		var alias domain.Alias
+		if err := rows.Scan(&alias.ID, &alias.Address, &alias.CreatedAt); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		aliases = append(aliases, alias)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return aliases, nil
+}
+
+// SaveAlias добавляет профилю адрес username на домене его основного адреса.
+// Адрес, занятый другим пользователем или дополнительным адресом, - domain.ErrAliasTaken
+func (repo *AliasRepository) SaveAlias(ctx context.Context, profileID int64, username string) (domain.Alias, error) {
+	const op = "storage.postgresql.alias.SaveAlias"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.Alias{}, e.Wrap(op+": failed to begin transaction: ", err)
+	}
+	defer tx.Rollback()
+
+	// Строка профиля блокируется, чтобы параллельные запросы не превысили лимит адресов
+	var id int64
+	var domainName string
+	var count int
+	log.Debug("Locking profile...")
+	err = tx.QueryRowContext(ctx, `
+        SELECT p.id, bp.domain,
+            (SELECT COUNT(*) FROM profile_alias pa WHERE pa.profile_id = p.id)
+        FROM profile p
+        JOIN base_profile bp ON bp.id = p.base_profile_id
+        WHERE p.base_profile_id = $1
+        FOR UPDATE OF p`, profileID).Scan(&id, &domainName, &count)
+	if err != nil {
+		return domain.Alias{}, e.Wrap(op, err)
+	}
+	if count >= domain.MaxAliases {
+		return domain.Alias{}, domain.ErrTooManyAliases
+	}
+
+	// Адрес не должен совпадать ни с основным адресом пользователя, ни с чужим или своим
+	// дополнительным адресом
+	var taken bool
+	err = tx.QueryRowContext(ctx, `
+        SELECT EXISTS (
+            SELECT 1 FROM base_profile
+            WHERE LOWER(username) = LOWER($1) AND LOWER(domain) = LOWER($2)
+        ) OR EXISTS (
+            SELECT 1 FROM profile_alias
+            WHERE LOWER(username) = LOWER($1) AND LOWER(domain) = LOWER($2)
+        )`, username, domainName).Scan(&taken)
+	if err != nil {
+		return domain.Alias{}, e.Wrap(op, err)
+	}
+	if taken {
+		return domain.Alias{}, domain.ErrAliasTaken
+	}
+
+	alias := domain.Alias{Address: username + "@" + domainName}
+	log.Debug("Saving alias...")
+	err = tx.QueryRowContext(ctx, `
+        INSERT INTO profile_alias (profile_id, username, domain)
+        VALUES ($1, $2, $3)
+        RETURNING id, created_at`, id, username, domainName).Scan(&alias.ID, &alias.CreatedAt)
+	if err != nil {
+		// Тот же адрес мог одновременно занять другой пользователь
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return domain.Alias{}, domain.ErrAliasTaken
+		}
+		return domain.Alias{}, e.Wrap(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.Alias{}, e.Wrap(op+": failed to commit transaction: ", err)
+	}
+
+	return alias, nil
+}
+
+func (repo *AliasRepository) DeleteAlias(ctx context.Context, profileID, aliasID int64) error {
+	const op = "storage.postgresql.alias.DeleteAlias"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        DELETE FROM profile_alias pa
+        USING profile p
+        WHERE pa.id = $1 AND p.id = pa.profile_id AND p.base_profile_id = $2`
+
+	log.Debug("Deleting alias...")
+	result, err := repo.db.ExecContext(ctx, query, aliasID, profileID)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+	if affected == 0 {
+		return domain.ErrAliasNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package alias_repository
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTest(t *testing.T) (context.Context, *AliasRepository, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return context.Background(), New(db), mock
+}
+
+func quote(query string) string {
+	return regexp.QuoteMeta(query)
+}
+
+func TestAliasRepository_GetAliases(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	createdAt := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(quote(`WHERE p.base_profile_id = $1`)).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "address", "created_at"}).
+			AddRow(3, "sales@flintmail.ru", createdAt))
+
+	aliases, err := repo.GetAliases(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Alias{{ID: 3, Address: "sales@flintmail.ru", CreatedAt: createdAt}}, aliases)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAliasRepository_SaveAlias(t *testing.T) {
+	createdAt := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(quote(`FOR UPDATE OF p`)).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "domain", "count"}).AddRow(7, "flintmail.ru", 2))
+		mock.ExpectQuery(quote(`FROM base_profile`)).
+			WithArgs("sales", "flintmail.ru").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectQuery(quote(`INSERT INTO profile_alias`)).
+			WithArgs(int64(7), "sales", "flintmail.ru").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, createdAt))
+		mock.ExpectCommit()
+
+		alias, err := repo.SaveAlias(ctx, 1, "sales")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Alias{ID: 3, Address: "sales@flintmail.ru", CreatedAt: createdAt}, alias)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("TooMany", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(quote(`FOR UPDATE OF p`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "domain", "count"}).AddRow(7, "flintmail.ru", domain.MaxAliases))
+		mock.ExpectRollback()
+
+		_, err := repo.SaveAlias(ctx, 1, "sales")
+
+		assert.ErrorIs(t, err, domain.ErrTooManyAliases)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("TakenByUser", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(quote(`FOR UPDATE OF p`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "domain", "count"}).AddRow(7, "flintmail.ru", 0))
+		mock.ExpectQuery(quote(`FROM base_profile`)).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectRollback()
+
+		_, err := repo.SaveAlias(ctx, 1, "admin")
+
+		assert.ErrorIs(t, err, domain.ErrAliasTaken)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("TakenConcurrently", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(quote(`FOR UPDATE OF p`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "domain", "count"}).AddRow(7, "flintmail.ru", 0))
+		mock.ExpectQuery(quote(`FROM profile_alias`)).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectQuery(quote(`INSERT INTO profile_alias`)).
+			WillReturnError(&pgconn.PgError{Code: uniqueViolation})
+		mock.ExpectRollback()
+
+		_, err := repo.SaveAlias(ctx, 1, "sales")
+
+		assert.ErrorIs(t, err, domain.ErrAliasTaken)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAliasRepository_DeleteAlias(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectExec(quote(`DELETE FROM profile_alias pa`)).
+			WithArgs(int64(3), int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.DeleteAlias(ctx, 1, 3))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectExec(quote(`DELETE FROM profile_alias pa`)).
+			WithArgs(int64(3), int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIs(t, repo.DeleteAlias(ctx, 1, 3), domain.ErrAliasNotFound)
+	})
+}
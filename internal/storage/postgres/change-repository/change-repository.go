@@ -0,0 +1,129 @@
+package change_repository
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"database/sql"
+	"log/slog"
+)
+
+type ChangeRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *ChangeRepository {
+	return &ChangeRepository{db: db}
+}
+
+// State возвращает номера последних записей журнала о папках и письмах профиля
+func (repo *ChangeRepository) State(ctx context.Context, profileID int64) (domain.MailState, error) {
+	const op = "storage.postgresql.change.State"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        SELECT
+            COALESCE((SELECT MAX(id) FROM mail_change WHERE profile_id = $1 AND object_type = 'Mailbox'), 0),
+            COALESCE((SELECT MAX(id) FROM mail_change WHERE profile_id = $1 AND object_type = 'Email'), 0)`
+
+	var state domain.MailState
+	log.Debug("Querying mail state...")
+	if err := repo.db.QueryRowContext(ctx, query, profileID).Scan(&state.Mailbox, &state.Email); err != nil {
+		return domain.MailState{}, e.Wrap(op, err)
+	}
+
+	return state, nil
+}
+
+// FindChanges возвращает не больше limit записей журнала после записи since в порядке записи
+func (repo *ChangeRepository) FindChanges(ctx context.Context, profileID int64, objectType domain.MailObjectType, since int64, limit int) ([]domain.MailChange, error) {
+	const op = "storage.postgresql.change.FindChanges"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        SELECT id, object_id, change_type, COALESCE(thread_key, '')
+        FROM mail_change
+        WHERE profile_id = $1 AND object_type = $2 AND id > $3
+        ORDER BY id
+        LIMIT $4`
+
+	log.Debug("Querying mail changes...")
+	rows, err := repo.db.QueryContext(ctx, query, profileID, string(objectType), since, limit)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	var changes []domain.MailChange
+	for rows.Next() {
+		var change domain.MailChange
+		var changeType string
+		if err := rows.Scan(&change.ID, &change.ObjectID, &changeType, &change.ThreadKey); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		change.ChangeType = domain.MailChangeType(changeType)
+		changes = append(changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return changes, nil
+}
+
+// FindThreadMessages возвращает письма цепочек, которые лежат в папках пользователя,
+// старые письма первыми. Цепочек без таких писем в ответе нет
+func (repo *ChangeRepository) FindThreadMessages(ctx context.Context, profileID int64, keys []string) (map[string][]int64, error) {
+	const op = "storage.postgresql.change.FindThreadMessages"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	var threadIDs, messageIDs []int64
+	for _, key := range keys {
+		threadID, messageID, ok := domain.ParseThreadKey(key)
+		if !ok {
+			continue
+		}
+		if threadID != 0 {
+			threadIDs = append(threadIDs, threadID)
+		} else {
+			messageIDs = append(messageIDs, messageID)
+		}
+	}
+	threads := make(map[string][]int64)
+	if len(threadIDs) == 0 && len(messageIDs) == 0 {
+		return threads, nil
+	}
+
+	const query = `
+        SELECT COALESCE(m.thread_id, 0), m.id
+        FROM message m
+        WHERE (m.thread_id = ANY($2::int[]) OR (m.thread_id IS NULL AND m.id = ANY($3::int[])))
+        AND EXISTS (
+            SELECT 1 FROM folder_profile_message fpm
+            JOIN folder f ON f.id = fpm.folder_id
+            WHERE fpm.message_id = m.id AND f.profile_id = $1
+        )
+        ORDER BY m.date_of_dispatch, m.id`
+
+	log.Debug("Querying thread messages...")
+	rows, err := repo.db.QueryContext(ctx, query, profileID, threadIDs, messageIDs)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var threadID, messageID int64
+		if err := rows.Scan(&threadID, &messageID); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		key := domain.ThreadKey(threadID, messageID)
+		threads[key] = append(threads[key], messageID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return threads, nil
+}
@@ -0,0 +1,107 @@
+package change_repository
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// arrayConverter пропускает срезы идентификаторов, которые pgx передает как int[]
+type arrayConverter struct{}
+
+func (arrayConverter) ConvertValue(v interface{}) (driver.Value, error) {
+	if ids, ok := v.([]int64); ok {
+		return ids, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(v)
+}
+
+func setupTest(t *testing.T) (context.Context, *ChangeRepository, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(arrayConverter{}))
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return context.Background(), New(db), mock
+}
+
+func quote(query string) string {
+	return regexp.QuoteMeta(query)
+}
+
+func TestChangeRepository_State(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`FROM mail_change`)).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"mailbox", "email"}).AddRow(int64(12), int64(40)))
+
+		state, err := repo.State(ctx, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.MailState{Mailbox: 12, Email: 40}, state)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("DBError", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`FROM mail_change`)).WillReturnError(errors.New("db down"))
+
+		_, err := repo.State(ctx, 1)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestChangeRepository_FindChanges(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	mock.ExpectQuery(quote(`WHERE profile_id = $1 AND object_type = $2 AND id > $3`)).
+		WithArgs(int64(1), "Email", int64(10), 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "object_id", "change_type", "thread_key"}).
+			AddRow(int64(11), int64(5), "created", "m5").
+			AddRow(int64(14), int64(5), "updated", ""))
+
+	changes, err := repo.FindChanges(ctx, 1, domain.MailObjectEmail, 10, 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.MailChange{
+		{ID: 11, ObjectID: 5, ChangeType: domain.MailChangeCreated, ThreadKey: "m5"},
+		{ID: 14, ObjectID: 5, ChangeType: domain.MailChangeUpdated},
+	}, changes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestChangeRepository_FindThreadMessages(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`FROM message m`)).
+			WithArgs(int64(1), []int64{7}, []int64{9}).
+			WillReturnRows(sqlmock.NewRows([]string{"thread_id", "id"}).
+				AddRow(int64(7), int64(3)).
+				AddRow(int64(0), int64(9)).
+				AddRow(int64(7), int64(4)))
+
+		threads, err := repo.FindThreadMessages(ctx, 1, []string{"7", "m9", "bad"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]int64{"7": {3, 4}, "m9": {9}}, threads)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NoValidKeys", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+
+		threads, err := repo.FindThreadMessages(ctx, 1, []string{"x", "m"})
+
+		assert.NoError(t, err)
+		assert.Empty(t, threads)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,474 @@
+package contact_repository
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+type ContactRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *ContactRepository {
+	return &ContactRepository{db: db}
+}
+
+// queryer - общий интерфейс *sql.DB и *sql.Tx для чтения контактов
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// contactColumns - поля контакта вместе с его адресами и группами в виде JSON-массивов
+const contactColumns = `
+            c.id, c.name, c.phone, c.notes, c.avatar_path, c.created_at,
+            COALESCE((
+                SELECT json_agg(ce.email ORDER BY ce.id)
+                FROM contact_email ce
+                WHERE ce.contact_id = c.id
+            ), '[]'),
+            COALESCE((
+                SELECT json_agg(gm.group_id ORDER BY gm.group_id)
+                FROM contact_group_member gm
+                WHERE gm.contact_id = c.id
+            ), '[]')`
+
+func (repo *ContactRepository) CreateContact(ctx context.Context, profileID int64, contact domain.Contact) (domain.Contact, error) {
+	const op = "storage.postgresql.contact.CreateContact"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.Contact{}, e.Wrap(op+": failed to begin transaction: ", err)
+	}
+	defer tx.Rollback()
+
+	log.Debug("Creating contact...")
+	var contactID int64
+	err = tx.QueryRowContext(ctx, `
+        INSERT INTO contact (profile_id, name, phone, notes, avatar_path)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id`,
+		profileID, contact.Name, contact.Phone, contact.Notes, contact.AvatarPath).Scan(&contactID)
+	if err != nil {
+		return domain.Contact{}, e.Wrap(op, err)
+	}
+
+	if err := addEmails(ctx, tx, profileID, contactID, contact.Emails); err != nil {
+		return domain.Contact{}, err
+	}
+	if err := setGroups(ctx, tx, profileID, contactID, contact.GroupIDs); err != nil {
+		return domain.Contact{}, err
+	}
+
+	created, err := findContact(ctx, tx, profileID, contactID)
+	if err != nil {
+		return domain.Contact{}, e.Wrap(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.Contact{}, e.Wrap(op+": failed to commit transaction: ", err)
+	}
+
+	return created, nil
+}
+
+// GetContacts возвращает контакты пользователя, при groupID != 0 - только участников группы
+func (repo *ContactRepository) GetContacts(ctx context.Context, profileID, groupID int64) ([]domain.Contact, error) {
+	const op = "storage.postgresql.contact.GetContacts"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	query := `
+        SELECT ` + contactColumns + `
+        FROM contact c
+        WHERE c.profile_id = $1
+            AND ($2 = 0 OR EXISTS (
+                SELECT 1 FROM contact_group_member gm
+                WHERE gm.contact_id = c.id AND gm.group_id = $2
+            ))
+        ORDER BY LOWER(NULLIF(c.name, '')) NULLS LAST, c.id`
+
+	log.Debug("Querying contacts...")
+	rows, err := repo.db.QueryContext(ctx, query, profileID, groupID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	contacts, err := scanContacts(rows, profileID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return contacts, nil
+}
+
+// UpdateContact перезаписывает контакт. Статистика использования сохраняется
+// для адресов, которые остались у контакта
+func (repo *ContactRepository) UpdateContact(ctx context.Context, profileID int64, contact domain.Contact) (domain.Contact, error) {
+	const op = "storage.postgresql.contact.UpdateContact"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.Contact{}, e.Wrap(op+": failed to begin transaction: ", err)
+	}
+	defer tx.Rollback()
+
+	log.Debug("Updating contact...")
+	result, err := tx.ExecContext(ctx, `
+        UPDATE contact
+        SET name = $1, phone = $2, notes = $3, avatar_path = $4
+        WHERE id = $5 AND profile_id = $6`,
+		contact.Name, contact.Phone, contact.Notes, contact.AvatarPath, contact.ID, profileID)
+	if err != nil {
+		return domain.Contact{}, e.Wrap(op, err)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return domain.Contact{}, e.Wrap(op, err)
+	}
+	if updated == 0 {
+		return domain.Contact{}, domain.ErrContactNotFound
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        DELETE FROM contact_email
+        WHERE contact_id = $1 AND email <> ALL($2::text[])`,
+		contact.ID, contact.Emails)
+	if err != nil {
+		return domain.Contact{}, e.Wrap(op, err)
+	}
+	if err := addEmails(ctx, tx, profileID, contact.ID, contact.Emails); err != nil {
+		return domain.Contact{}, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        DELETE FROM contact_group_member
+        WHERE contact_id = $1`,
+		contact.ID)
+	if err != nil {
+		return domain.Contact{}, e.Wrap(op, err)
+	}
+	if err := setGroups(ctx, tx, profileID, contact.ID, contact.GroupIDs); err != nil {
+		return domain.Contact{}, err
+	}
+
+	saved, err := findContact(ctx, tx, profileID, contact.ID)
+	if err != nil {
+		return domain.Contact{}, e.Wrap(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.Contact{}, e.Wrap(op+": failed to commit transaction: ", err)
+	}
+
+	return saved, nil
+}
+
+// DeleteContact удаляет контакт, его адреса и членство в группах удаляются каскадно
+func (repo *ContactRepository) DeleteContact(ctx context.Context, profileID, contactID int64) error {
+	const op = "storage.postgresql.contact.DeleteContact"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	log.Debug("Deleting contact...")
+	result, err := repo.db.ExecContext(ctx, `
+        DELETE FROM contact
+        WHERE id = $1 AND profile_id = $2`,
+		contactID, profileID)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+	if rows == 0 {
+		return domain.ErrContactNotFound
+	}
+
+	return nil
+}
+
+// SuggestContacts ищет адреса по началу адреса или любого слова имени контакта.
+// Чаще и недавно использованные адреса идут первыми: счетчик использований
+// затухает с характерным временем 30 дней
+func (repo *ContactRepository) SuggestContacts(ctx context.Context, profileID int64, prefix string, now time.Time, limit int) ([]domain.ContactSuggestion, error) {
+	const op = "storage.postgresql.contact.SuggestContacts"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        SELECT c.id, c.name, ce.email
+        FROM contact_email ce
+        JOIN contact c ON ce.contact_id = c.id
+        WHERE ce.profile_id = $1
+            AND (ce.email LIKE $2 || '%' ESCAPE '\'
+                OR LOWER(c.name) LIKE $2 || '%' ESCAPE '\'
+                OR LOWER(c.name) LIKE '% ' || $2 || '%' ESCAPE '\')
+        ORDER BY
+            (ce.use_count + 1) * EXP(-GREATEST(EXTRACT(EPOCH FROM ($3 - COALESCE(ce.last_used_at, ce.created_at))), 0) / 2592000) DESC,
+            ce.email
+        LIMIT $4`
+
+	log.Debug("Querying contact suggestions...")
+	rows, err := repo.db.QueryContext(ctx, query, profileID, escapeLike(strings.ToLower(prefix)), now, limit)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	suggestions := make([]domain.ContactSuggestion, 0)
+	for rows.Next() {
+		var suggestion domain.ContactSuggestion
+		if err := rows.Scan(&suggestion.ContactID, &suggestion.Name, &suggestion.Email); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return suggestions, nil
+}
+
+func (repo *ContactRepository) CreateGroup(ctx context.Context, profileID int64, name string) (domain.ContactGroup, error) {
+	const op = "storage.postgresql.contact.CreateGroup"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	exists, err := repo.groupExists(ctx, profileID, name, 0)
+	if err != nil {
+		return domain.ContactGroup{}, e.Wrap(op, err)
+	}
+	if exists {
+		return domain.ContactGroup{}, domain.ErrContactGroupExists
+	}
+
+	group := domain.ContactGroup{ProfileID: profileID}
+	log.Debug("Creating contact group...")
+	err = repo.db.QueryRowContext(ctx, `
+        INSERT INTO contact_group (profile_id, name)
+        VALUES ($1, $2)
+        RETURNING id, name, created_at`,
+		profileID, name).Scan(&group.ID, &group.Name, &group.CreatedAt)
+	if err != nil {
+		return domain.ContactGroup{}, e.Wrap(op, err)
+	}
+
+	return group, nil
+}
+
+func (repo *ContactRepository) GetGroups(ctx context.Context, profileID int64) ([]domain.ContactGroup, error) {
+	const op = "storage.postgresql.contact.GetGroups"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        SELECT g.id, g.name, g.created_at, COUNT(gm.contact_id)
+        FROM contact_group g
+        LEFT JOIN contact_group_member gm ON gm.group_id = g.id
+        WHERE g.profile_id = $1
+        GROUP BY g.id
+        ORDER BY LOWER(g.name), g.id`
+
+	log.Debug("Querying contact groups...")
+	rows, err := repo.db.QueryContext(ctx, query, profileID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	groups := make([]domain.ContactGroup, 0)
+	for rows.Next() {
+		group := domain.ContactGroup{ProfileID: profileID}
+		if err := rows.Scan(&group.ID, &group.Name, &group.CreatedAt, &group.ContactCount); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		groups = append(groups, group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return groups, nil
+}
+
+func (repo *ContactRepository) RenameGroup(ctx context.Context, profileID, groupID int64, name string) (domain.ContactGroup, error) {
+	const op = "storage.postgresql.contact.RenameGroup"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	exists, err := repo.groupExists(ctx, profileID, name, groupID)
+	if err != nil {
+		return domain.ContactGroup{}, e.Wrap(op, err)
+	}
+	if exists {
+		return domain.ContactGroup{}, domain.ErrContactGroupExists
+	}
+
+	group := domain.ContactGroup{ProfileID: profileID}
+	log.Debug("Renaming contact group...")
+	err = repo.db.QueryRowContext(ctx, `
+        UPDATE contact_group
+        SET name = $1
+        WHERE id = $2 AND profile_id = $3
+        RETURNING id, name, created_at,
+            (SELECT COUNT(*) FROM contact_group_member WHERE group_id = $2)`,
+		name, groupID, profileID).Scan(&group.ID, &group.Name, &group.CreatedAt, &group.ContactCount)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ContactGroup{}, domain.ErrContactGroupNotFound
+		}
+		return domain.ContactGroup{}, e.Wrap(op, err)
+	}
+
+	return group, nil
+}
+
+// DeleteGroup удаляет группу, сами контакты остаются
+func (repo *ContactRepository) DeleteGroup(ctx context.Context, profileID, groupID int64) error {
+	const op = "storage.postgresql.contact.DeleteGroup"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	log.Debug("Deleting contact group...")
+	result, err := repo.db.ExecContext(ctx, `
+        DELETE FROM contact_group
+        WHERE id = $1 AND profile_id = $2`,
+		groupID, profileID)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+	if rows == 0 {
+		return domain.ErrContactGroupNotFound
+	}
+
+	return nil
+}
+
+// addEmails добавляет контакту адреса, которых у него еще нет. Если адрес уже
+// принадлежит другому контакту пользователя, возвращает ErrContactExists
+func addEmails(ctx context.Context, tx *sql.Tx, profileID, contactID int64, emails []string) error {
+	_, err := tx.ExecContext(ctx, `
+        INSERT INTO contact_email (contact_id, profile_id, email)
+        SELECT $1, $2, t.email
+        FROM UNNEST($3::text[]) WITH ORDINALITY AS t(email, n)
+        ORDER BY t.n
+        ON CONFLICT (profile_id, email) DO NOTHING`,
+		contactID, profileID, emails)
+	if err != nil {
+		return e.Wrap("failed to add contact emails", err)
+	}
+
+	var owned int
+	err = tx.QueryRowContext(ctx, `
+        SELECT COUNT(*) FROM contact_email
+        WHERE contact_id = $1`,
+		contactID).Scan(&owned)
+	if err != nil {
+		return e.Wrap("failed to count contact emails", err)
+	}
+	if owned != len(emails) {
+		return domain.ErrContactExists
+	}
+	return nil
+}
+
+// setGroups добавляет контакт в группы пользователя
+func setGroups(ctx context.Context, tx *sql.Tx, profileID, contactID int64, groupIDs []int64) error {
+	if len(groupIDs) == 0 {
+		return nil
+	}
+
+	result, err := tx.ExecContext(ctx, `
+        INSERT INTO contact_group_member (group_id, contact_id)
+        SELECT g.id, $1
+        FROM contact_group g
+        WHERE g.profile_id = $2 AND g.id = ANY($3)`,
+		contactID, profileID, groupIDs)
+	if err != nil {
+		return e.Wrap("failed to set contact groups", err)
+	}
+	added, err := result.RowsAffected()
+	if err != nil {
+		return e.Wrap("failed to set contact groups", err)
+	}
+	if added != int64(len(groupIDs)) {
+		return domain.ErrContactGroupNotFound
+	}
+	return nil
+}
+
+func findContact(ctx context.Context, q queryer, profileID, contactID int64) (domain.Contact, error) {
+	rows, err := q.QueryContext(ctx, `
+        SELECT `+contactColumns+`
+        FROM contact c
+        WHERE c.id = $1 AND c.profile_id = $2`,
+		contactID, profileID)
+	if err != nil {
+		return domain.Contact{}, err
+	}
+	defer rows.Close()
+
+	contacts, err := scanContacts(rows, profileID)
+	if err != nil {
+		return domain.Contact{}, err
+	}
+	if len(contacts) == 0 {
+		return domain.Contact{}, domain.ErrContactNotFound
+	}
+	return contacts[0], nil
+}
+
+func scanContacts(rows *sql.Rows, profileID int64) ([]domain.Contact, error) {
+	contacts := make([]domain.Contact, 0)
+	for rows.Next() {
+		contact := domain.Contact{ProfileID: profileID}
+		var emails, groups []byte
+		err := rows.Scan(
+			&contact.ID, &contact.Name, &contact.Phone, &contact.Notes, &contact.AvatarPath, &contact.CreatedAt,
+			&emails, &groups,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(emails, &contact.Emails); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(groups, &contact.GroupIDs); err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, contact)
+	}
+	return contacts, rows.Err()
+}
+
+func (repo *ContactRepository) groupExists(ctx context.Context, profileID int64, name string, excludeID int64) (bool, error) {
+	var exists int
+	err := repo.db.QueryRowContext(ctx, `
+        SELECT 1 FROM contact_group
+        WHERE profile_id = $1 AND LOWER(name) = LOWER($2) AND id <> $3`,
+		profileID, name, excludeID).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// escapeLike экранирует спецсимволы шаблона LIKE
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
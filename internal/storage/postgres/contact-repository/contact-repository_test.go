@@ -0,0 +1,293 @@
+package contact_repository
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// arrayConverter пропускает срезы адресов и идентификаторов, которые pgx передает как массивы
+type arrayConverter struct{}
+
+func (arrayConverter) ConvertValue(v interface{}) (driver.Value, error) {
+	switch v.(type) {
+	case []int64, []string:
+		return v, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(v)
+}
+
+func setupTest(t *testing.T) (context.Context, *ContactRepository, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(arrayConverter{}))
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return context.Background(), New(db), mock
+}
+
+func quote(query string) string {
+	return regexp.QuoteMeta(query)
+}
+
+var contactRowColumns = []string{"id", "name", "phone", "notes", "avatar_path", "created_at", "emails", "groups"}
+
+func contactRow(id int64, name, emails, groups string, createdAt time.Time) []driver.Value {
+	return []driver.Value{id, name, "", "", "", createdAt, []byte(emails), []byte(groups)}
+}
+
+func TestContactRepository_CreateContact(t *testing.T) {
+	createdAt := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	contact := domain.Contact{Name: "Bob", Emails: []string{"bob@a4mail.ru", "bob@work.ru"}, GroupIDs: []int64{3}}
+
+	t.Run("Success", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(quote(`INSERT INTO contact (profile_id, name, phone, notes, avatar_path)`)).
+			WithArgs(int64(1), "Bob", "", "", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+		mock.ExpectExec(quote(`INSERT INTO contact_email`)).
+			WithArgs(int64(10), int64(1), contact.Emails).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectQuery(quote(`SELECT COUNT(*) FROM contact_email`)).
+			WithArgs(int64(10)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectExec(quote(`INSERT INTO contact_group_member`)).
+			WithArgs(int64(10), int64(1), contact.GroupIDs).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(quote(`WHERE c.id = $1 AND c.profile_id = $2`)).
+			WithArgs(int64(10), int64(1)).
+			WillReturnRows(sqlmock.NewRows(contactRowColumns).
+				AddRow(contactRow(10, "Bob", `["bob@a4mail.ru","bob@work.ru"]`, `[3]`, createdAt)...))
+		mock.ExpectCommit()
+
+		created, err := repo.CreateContact(ctx, 1, contact)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Contact{
+			ID: 10, ProfileID: 1, Name: "Bob",
+			Emails: []string{"bob@a4mail.ru", "bob@work.ru"}, GroupIDs: []int64{3},
+			CreatedAt: createdAt,
+		}, created)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("EmailTaken", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(quote(`INSERT INTO contact`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+		mock.ExpectExec(quote(`INSERT INTO contact_email`)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(quote(`SELECT COUNT(*) FROM contact_email`)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectRollback()
+
+		_, err := repo.CreateContact(ctx, 1, contact)
+
+		assert.ErrorIs(t, err, domain.ErrContactExists)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ForeignGroup", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(quote(`INSERT INTO contact`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+		mock.ExpectExec(quote(`INSERT INTO contact_email`)).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectQuery(quote(`SELECT COUNT(*) FROM contact_email`)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectExec(quote(`INSERT INTO contact_group_member`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		_, err := repo.CreateContact(ctx, 1, contact)
+
+		assert.ErrorIs(t, err, domain.ErrContactGroupNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestContactRepository_GetContacts(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	createdAt := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(quote(`FROM contact c`)).
+		WithArgs(int64(1), int64(3)).
+		WillReturnRows(sqlmock.NewRows(contactRowColumns).
+			AddRow(contactRow(10, "Bob", `["bob@a4mail.ru"]`, `[3]`, createdAt)...).
+			AddRow(contactRow(11, "", `["x@a4mail.ru"]`, `[]`, createdAt)...))
+
+	contacts, err := repo.GetContacts(ctx, 1, 3)
+
+	assert.NoError(t, err)
+	assert.Len(t, contacts, 2)
+	assert.Equal(t, []string{"bob@a4mail.ru"}, contacts[0].Emails)
+	assert.Empty(t, contacts[1].GroupIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestContactRepository_UpdateContact(t *testing.T) {
+	contact := domain.Contact{ID: 10, Name: "Robert", Emails: []string{"bob@a4mail.ru"}}
+
+	t.Run("Success", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(quote(`UPDATE contact`)).
+			WithArgs("Robert", "", "", "", int64(10), int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(quote(`DELETE FROM contact_email`)).
+			WithArgs(int64(10), contact.Emails).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(quote(`INSERT INTO contact_email`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(quote(`SELECT COUNT(*) FROM contact_email`)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectExec(quote(`DELETE FROM contact_group_member`)).
+			WithArgs(int64(10)).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectQuery(quote(`WHERE c.id = $1 AND c.profile_id = $2`)).
+			WillReturnRows(sqlmock.NewRows(contactRowColumns).
+				AddRow(contactRow(10, "Robert", `["bob@a4mail.ru"]`, `[]`, time.Now())...))
+		mock.ExpectCommit()
+
+		saved, err := repo.UpdateContact(ctx, 1, contact)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Robert", saved.Name)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectBegin()
+		mock.ExpectExec(quote(`UPDATE contact`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		_, err := repo.UpdateContact(ctx, 1, contact)
+
+		assert.ErrorIs(t, err, domain.ErrContactNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestContactRepository_DeleteContact(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	mock.ExpectExec(quote(`DELETE FROM contact`)).
+		WithArgs(int64(10), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.DeleteContact(ctx, 1, 10)
+
+	assert.ErrorIs(t, err, domain.ErrContactNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestContactRepository_SuggestContacts(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		now := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(quote(`ORDER BY`)).
+			WithArgs(int64(1), `bo\_b`, now, 5).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).
+				AddRow(10, "Bob", "bo_b@a4mail.ru"))
+
+		suggestions, err := repo.SuggestContacts(ctx, 1, "Bo_b", now, 5)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.ContactSuggestion{{ContactID: 10, Name: "Bob", Email: "bo_b@a4mail.ru"}}, suggestions)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("DatabaseError", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`FROM contact_email ce`)).
+			WillReturnError(errors.New("db error"))
+
+		_, err := repo.SuggestContacts(ctx, 1, "bob", time.Now(), 5)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestContactRepository_Groups(t *testing.T) {
+	createdAt := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("CreateGroup", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`SELECT 1 FROM contact_group`)).
+			WithArgs(int64(1), "Family", int64(0)).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}))
+		mock.ExpectQuery(quote(`INSERT INTO contact_group (profile_id, name)`)).
+			WithArgs(int64(1), "Family").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at"}).AddRow(3, "Family", createdAt))
+
+		group, err := repo.CreateGroup(ctx, 1, "Family")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ContactGroup{ID: 3, ProfileID: 1, Name: "Family", CreatedAt: createdAt}, group)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("CreateGroupExists", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`SELECT 1 FROM contact_group`)).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(1))
+
+		_, err := repo.CreateGroup(ctx, 1, "family")
+
+		assert.ErrorIs(t, err, domain.ErrContactGroupExists)
+	})
+
+	t.Run("GetGroups", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`COUNT(gm.contact_id)`)).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at", "count"}).
+				AddRow(3, "Family", createdAt, 2))
+
+		groups, err := repo.GetGroups(ctx, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.ContactGroup{{ID: 3, ProfileID: 1, Name: "Family", ContactCount: 2, CreatedAt: createdAt}}, groups)
+	})
+
+	t.Run("RenameGroupNotFound", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`SELECT 1 FROM contact_group`)).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}))
+		mock.ExpectQuery(quote(`UPDATE contact_group`)).
+			WithArgs("Friends", int64(3), int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at", "count"}))
+
+		_, err := repo.RenameGroup(ctx, 1, 3, "Friends")
+
+		assert.ErrorIs(t, err, domain.ErrContactGroupNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("DeleteGroup", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectExec(quote(`DELETE FROM contact_group`)).
+			WithArgs(int64(3), int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.DeleteGroup(ctx, 1, 3)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `50\% off\_\\`, escapeLike(`50% off_\`))
+}
@@ -0,0 +1,73 @@
+package forwarding_repository
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+)
+
+type ForwardingRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *ForwardingRepository {
+	return &ForwardingRepository{db: db}
+}
+
+// GetRule возвращает правило автопересылки профиля, выключенное правило - если оно не задано
+func (repo *ForwardingRepository) GetRule(ctx context.Context, profileID int64) (domain.ForwardingRule, error) {
+	const op = "storage.postgresql.forwarding.GetRule"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        SELECT enabled, addresses, keep_copy, from_contains, subject_contains
+        FROM forwarding_rule
+        WHERE profile_id = $1`
+
+	var rule domain.ForwardingRule
+	var addresses string
+	log.Debug("Querying forwarding rule...")
+	err := repo.db.QueryRowContext(ctx, query, profileID).Scan(
+		&rule.Enabled, &addresses, &rule.KeepCopy, &rule.FromContains, &rule.SubjectContains)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ForwardingRule{KeepCopy: true}, nil
+		}
+		return domain.ForwardingRule{}, e.Wrap(op, err)
+	}
+
+	if addresses != "" {
+		rule.Addresses = strings.Split(addresses, "\n")
+	}
+
+	return rule, nil
+}
+
+func (repo *ForwardingRepository) SaveRule(ctx context.Context, profileID int64, rule domain.ForwardingRule) error {
+	const op = "storage.postgresql.forwarding.SaveRule"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        INSERT INTO forwarding_rule (profile_id, enabled, addresses, keep_copy, from_contains, subject_contains)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (profile_id)
+        DO UPDATE SET enabled = EXCLUDED.enabled,
+                      addresses = EXCLUDED.addresses,
+                      keep_copy = EXCLUDED.keep_copy,
+                      from_contains = EXCLUDED.from_contains,
+                      subject_contains = EXCLUDED.subject_contains`
+
+	log.Debug("Saving forwarding rule...")
+	_, err := repo.db.ExecContext(ctx, query, profileID, rule.Enabled, strings.Join(rule.Addresses, "\n"),
+		rule.KeepCopy, rule.FromContains, rule.SubjectContains)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package forwarding_repository
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTest(t *testing.T) (context.Context, *ForwardingRepository, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return context.Background(), New(db), mock
+}
+
+func quote(query string) string {
+	return regexp.QuoteMeta(query)
+}
+
+func TestForwardingRepository_GetRule(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`SELECT enabled, addresses, keep_copy, from_contains, subject_contains`)).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"enabled", "addresses", "keep_copy", "from_contains", "subject_contains"}).
+				AddRow(true, "a@a4mail.ru\nb@a4mail.ru", false, "boss", ""))
+
+		rule, err := repo.GetRule(ctx, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ForwardingRule{
+			Enabled:      true,
+			Addresses:    []string{"a@a4mail.ru", "b@a4mail.ru"},
+			FromContains: "boss",
+		}, rule)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotConfigured", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`SELECT enabled`)).
+			WithArgs(int64(1)).
+			WillReturnError(sql.ErrNoRows)
+
+		rule, err := repo.GetRule(ctx, 1)
+
+		assert.NoError(t, err)
+		assert.False(t, rule.Enabled)
+		assert.True(t, rule.KeepCopy)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("DBError", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`SELECT enabled`)).
+			WithArgs(int64(1)).
+			WillReturnError(errors.New("db down"))
+
+		_, err := repo.GetRule(ctx, 1)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestForwardingRepository_SaveRule(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	mock.ExpectExec(quote(`INSERT INTO forwarding_rule`)).
+		WithArgs(int64(1), true, "a@a4mail.ru\nb@a4mail.ru", true, "", "invoice").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.SaveRule(ctx, 1, domain.ForwardingRule{
+		Enabled:         true,
+		Addresses:       []string{"a@a4mail.ru", "b@a4mail.ru"},
+		KeepCopy:        true,
+		SubjectContains: "invoice",
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,242 @@
+package label_repository
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+)
+
+type LabelRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *LabelRepository {
+	return &LabelRepository{db: db}
+}
+
+func (repo *LabelRepository) CreateLabel(ctx context.Context, profileID int64, name, color string) (domain.Label, error) {
+	const op = "storage.postgresql.label.CreateLabel"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	exists, err := repo.labelExists(ctx, profileID, name, 0)
+	if err != nil {
+		return domain.Label{}, e.Wrap(op, err)
+	}
+	if exists {
+		return domain.Label{}, domain.ErrLabelExists
+	}
+
+	const query = `
+        INSERT INTO label (profile_id, name, color)
+        VALUES ($1, $2, $3)
+        RETURNING id, name, color, created_at`
+
+	label := domain.Label{ProfileID: profileID}
+	log.Debug("Creating label...")
+	err = repo.db.QueryRowContext(ctx, query, profileID, name, color).Scan(
+		&label.ID, &label.Name, &label.Color, &label.CreatedAt)
+	if err != nil {
+		return domain.Label{}, e.Wrap(op, err)
+	}
+
+	return label, nil
+}
+
+func (repo *LabelRepository) GetLabels(ctx context.Context, profileID int64) ([]domain.Label, error) {
+	const op = "storage.postgresql.label.GetLabels"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        SELECT id, name, color, created_at
+        FROM label
+        WHERE profile_id = $1
+        ORDER BY LOWER(name), id`
+
+	log.Debug("Querying labels...")
+	rows, err := repo.db.QueryContext(ctx, query, profileID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	labels := make([]domain.Label, 0)
+	for rows.Next() {
+		label := domain.Label{ProfileID: profileID}
+		if err := rows.Scan(&label.ID, &label.Name, &label.Color, &label.CreatedAt); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		labels = append(labels, label)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return labels, nil
+}
+
+func (repo *LabelRepository) UpdateLabel(ctx context.Context, profileID, labelID int64, name, color string) (domain.Label, error) {
+	const op = "storage.postgresql.label.UpdateLabel"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	exists, err := repo.labelExists(ctx, profileID, name, labelID)
+	if err != nil {
+		return domain.Label{}, e.Wrap(op, err)
+	}
+	if exists {
+		return domain.Label{}, domain.ErrLabelExists
+	}
+
+	const query = `
+        UPDATE label
+        SET name = $1, color = $2
+        WHERE id = $3 AND profile_id = $4
+        RETURNING id, name, color, created_at`
+
+	label := domain.Label{ProfileID: profileID}
+	log.Debug("Updating label...")
+	err = repo.db.QueryRowContext(ctx, query, name, color, labelID, profileID).Scan(
+		&label.ID, &label.Name, &label.Color, &label.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Label{}, domain.ErrLabelNotFound
+		}
+		return domain.Label{}, e.Wrap(op, err)
+	}
+
+	return label, nil
+}
+
+// DeleteLabel удаляет метку, связи с письмами удаляются каскадно
+func (repo *LabelRepository) DeleteLabel(ctx context.Context, profileID, labelID int64) error {
+	const op = "storage.postgresql.label.DeleteLabel"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	log.Debug("Deleting label...")
+	result, err := repo.db.ExecContext(ctx, `
+        DELETE FROM label
+        WHERE id = $1 AND profile_id = $2`,
+		labelID, profileID)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+	if rows == 0 {
+		return domain.ErrLabelNotFound
+	}
+
+	return nil
+}
+
+// ApplyLabel вешает метку на письма пользователя и возвращает число новых связей.
+// Письма, которых нет в папках пользователя, пропускаются
+func (repo *LabelRepository) ApplyLabel(ctx context.Context, profileID, labelID int64, messageIDs []int64) (int64, error) {
+	const op = "storage.postgresql.label.ApplyLabel"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, e.Wrap(op+": failed to begin transaction: ", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkLabelOwner(ctx, tx, profileID, labelID); err != nil {
+		return 0, err
+	}
+
+	const query = `
+        INSERT INTO message_label (label_id, message_id)
+        SELECT DISTINCT $1::integer, fpm.message_id
+        FROM folder_profile_message fpm
+        JOIN folder f ON fpm.folder_id = f.id
+        WHERE f.profile_id = $2 AND fpm.message_id = ANY($3)
+        ON CONFLICT (label_id, message_id) DO NOTHING`
+
+	log.Debug("Applying label...")
+	result, err := tx.ExecContext(ctx, query, labelID, profileID, messageIDs)
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+	applied, err := result.RowsAffected()
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, e.Wrap(op+": failed to commit transaction: ", err)
+	}
+
+	return applied, nil
+}
+
+// RemoveLabel снимает метку с писем и возвращает число удаленных связей
+func (repo *LabelRepository) RemoveLabel(ctx context.Context, profileID, labelID int64, messageIDs []int64) (int64, error) {
+	const op = "storage.postgresql.label.RemoveLabel"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, e.Wrap(op+": failed to begin transaction: ", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkLabelOwner(ctx, tx, profileID, labelID); err != nil {
+		return 0, err
+	}
+
+	log.Debug("Removing label...")
+	result, err := tx.ExecContext(ctx, `
+        DELETE FROM message_label
+        WHERE label_id = $1 AND message_id = ANY($2)`,
+		labelID, messageIDs)
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, e.Wrap(op+": failed to commit transaction: ", err)
+	}
+
+	return removed, nil
+}
+
+func checkLabelOwner(ctx context.Context, tx *sql.Tx, profileID, labelID int64) error {
+	var exists int
+	err := tx.QueryRowContext(ctx, `
+        SELECT 1 FROM label
+        WHERE id = $1 AND profile_id = $2`,
+		labelID, profileID).Scan(&exists)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrLabelNotFound
+		}
+		return e.Wrap("failed to check label", err)
+	}
+	return nil
+}
+
+func (repo *LabelRepository) labelExists(ctx context.Context, profileID int64, name string, excludeID int64) (bool, error) {
+	var exists int
+	err := repo.db.QueryRowContext(ctx, `
+        SELECT 1 FROM label
+        WHERE profile_id = $1 AND LOWER(name) = LOWER($2) AND id <> $3`,
+		profileID, name, excludeID).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,168 @@
+package label_repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"2025_2_a4code/internal/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// arrayConverter пропускает срезы идентификаторов, которые pgx передает как массивы
+type arrayConverter struct{}
+
+func (arrayConverter) ConvertValue(v interface{}) (driver.Value, error) {
+	if ids, ok := v.([]int64); ok {
+		return ids, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(v)
+}
+
+func setupTest(t *testing.T) (context.Context, *LabelRepository, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(arrayConverter{}))
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return context.Background(), New(db), mock
+}
+
+func TestLabelRepository_CreateLabel(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	createdAt := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT 1 FROM label`).
+			WithArgs(int64(1), "Work", int64(0)).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(`INSERT INTO label`).
+			WithArgs(int64(1), "Work", "#ff0000").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "color", "created_at"}).
+				AddRow(int64(3), "Work", "#ff0000", createdAt))
+
+		label, err := repo.CreateLabel(ctx, 1, "Work", "#ff0000")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Label{ID: 3, ProfileID: 1, Name: "Work", Color: "#ff0000", CreatedAt: createdAt}, label)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("AlreadyExists", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT 1 FROM label`).
+			WithArgs(int64(1), "work", int64(0)).
+			WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+
+		_, err := repo.CreateLabel(ctx, 1, "work", "#ff0000")
+
+		assert.ErrorIs(t, err, domain.ErrLabelExists)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestLabelRepository_GetLabels(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	createdAt := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`FROM label`).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "color", "created_at"}).
+			AddRow(int64(3), "Work", "#ff0000", createdAt).
+			AddRow(int64(4), "Travel", "#00ff00", createdAt))
+
+	labels, err := repo.GetLabels(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, labels, 2)
+	assert.Equal(t, "Travel", labels[1].Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLabelRepository_UpdateLabel_NotFound(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+
+	mock.ExpectQuery(`SELECT 1 FROM label`).
+		WithArgs(int64(1), "Work", int64(3)).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`UPDATE label`).
+		WithArgs("Work", "#ff0000", int64(3), int64(1)).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := repo.UpdateLabel(ctx, 1, 3, "Work", "#ff0000")
+
+	assert.ErrorIs(t, err, domain.ErrLabelNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLabelRepository_DeleteLabel(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+
+	mock.ExpectExec(`DELETE FROM label`).
+		WithArgs(int64(3), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.DeleteLabel(ctx, 1, 3)
+
+	assert.ErrorIs(t, err, domain.ErrLabelNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLabelRepository_ApplyLabel(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	messageIDs := []int64{10, 11, 12}
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT 1 FROM label`).
+			WithArgs(int64(3), int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+		mock.ExpectExec(`INSERT INTO message_label`).
+			WithArgs(int64(3), int64(1), messageIDs).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		applied, err := repo.ApplyLabel(ctx, 1, 3, messageIDs)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), applied)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ForeignLabel", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT 1 FROM label`).
+			WithArgs(int64(3), int64(2)).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		_, err := repo.ApplyLabel(ctx, 2, 3, messageIDs)
+
+		assert.ErrorIs(t, err, domain.ErrLabelNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestLabelRepository_RemoveLabel(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	messageIDs := []int64{10}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT 1 FROM label`).
+		WithArgs(int64(3), int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+	mock.ExpectExec(`DELETE FROM message_label`).
+		WithArgs(int64(3), messageIDs).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	removed, err := repo.RemoveLabel(ctx, 1, 3, messageIDs)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), removed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -6,8 +6,13 @@ import (
 	e "2025_2_a4code/internal/lib/wrapper"
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"mime"
+	"net/mail"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -41,12 +46,12 @@ func (repo *MessageRepository) FindByMessageID(ctx context.Context, messageID in
 	const query = `
         SELECT
             m.id, m.topic, m.text, m.date_of_dispatch,
-            bp.id, bp.username, bp.domain,
+            COALESCE(bp.id, 0), ` + senderAddressColumns + `,
             p.name, p.surname, p.image_path,
             pm.read_status
         FROM
             message m
-        JOIN
+        LEFT JOIN
             base_profile bp ON m.sender_base_profile_id = bp.id
         LEFT JOIN
             profile p ON bp.id = p.base_profile_id
@@ -105,13 +110,20 @@ func (repo *MessageRepository) FindFullByMessageID(ctx context.Context, messageI
 	const query = `
         SELECT
             m.id, m.topic, m.text, m.date_of_dispatch,
-            bp.id, bp.username, bp.domain,
+            COALESCE(bp.id, 0), ` + senderAddressColumns + `,
             p.name, p.surname, p.image_path,
             t.id, t.root_message_id,
-            f.id, f.profile_id, f.folder_name, f.folder_type
+            f.id, f.profile_id, f.folder_name, f.folder_type,
+            pm.spam_score, pm.spam_reasons,
+            m.dkim_result, m.dkim_domain,
+            m.spf_result, m.spf_domain, m.dmarc_result, m.dmarc_policy, m.dmarc_aligned,
+            m.authentication_results,
+            m.size, COALESCE(pm.read_status, FALSE),
+            COALESCE(pm.starred, FALSE), COALESCE(pm.important, FALSE),
+            COALESCE(pm.answered, FALSE), COALESCE(pm.forwarded, FALSE)
         FROM
             message m
-        JOIN
+        LEFT JOIN
             base_profile bp ON m.sender_base_profile_id = bp.id
         LEFT JOIN
             profile p ON bp.id = p.base_profile_id
@@ -121,6 +133,8 @@ func (repo *MessageRepository) FindFullByMessageID(ctx context.Context, messageI
             folder_profile_message fpm ON m.id = fpm.message_id
         LEFT JOIN
             folder f ON fpm.folder_id = f.id AND f.profile_id = $2
+        LEFT JOIN
+            profile_message pm ON pm.message_id = m.id AND pm.profile_id = $2
         WHERE
             m.id = $1
         LIMIT 1`
@@ -133,6 +147,11 @@ func (repo *MessageRepository) FindFullByMessageID(ctx context.Context, messageI
 	var threadID, threadRootID sql.NullInt64
 	var folderID, folderProfileID sql.NullInt64
 	var folderName, folderType sql.NullString
+	var spamScore sql.NullFloat64
+	var spamReasons sql.NullString
+	var dkimResult, dkimDomain sql.NullString
+	var spfResult, spfDomain, dmarcResult, dmarcPolicy, authenticationResults sql.NullString
+	var dmarcAligned sql.NullBool
 
 	log.Debug("Scanning full message data...")
 	err = repo.db.QueryRowContext(ctx, query, messageID, profileID).Scan(
@@ -141,6 +160,13 @@ func (repo *MessageRepository) FindFullByMessageID(ctx context.Context, messageI
 		&senderName, &senderSurname, &senderAvatar,
 		&threadID, &threadRootID,
 		&folderID, &folderProfileID, &folderName, &folderType,
+		&spamScore, &spamReasons,
+		&dkimResult, &dkimDomain,
+		&spfResult, &spfDomain, &dmarcResult, &dmarcPolicy, &dmarcAligned,
+		&authenticationResults,
+		&msg.Size, &msg.IsRead,
+		&msg.IsStarred, &msg.IsImportant,
+		&msg.IsAnswered, &msg.IsForwarded,
 	)
 	if err != nil {
 		return domain.FullMessage{}, e.Wrap(op, err)
@@ -148,6 +174,29 @@ func (repo *MessageRepository) FindFullByMessageID(ctx context.Context, messageI
 
 	msg.ID = strconv.FormatInt(messageIdInt, 10)
 
+	// Оценка спам-фильтра есть только у писем, прошедших классификацию
+	if spamScore.Valid {
+		msg.SpamScore = spamScore.Float64
+	}
+	if spamReasons.Valid && spamReasons.String != "" {
+		msg.SpamReasons = strings.Split(spamReasons.String, "\n")
+	}
+	// DKIM проверяется только у писем от внешних серверов
+	if dkimResult.Valid {
+		msg.DKIM = domain.DKIMResult{Status: domain.DKIMStatus(dkimResult.String), Domain: dkimDomain.String}
+	}
+	if spfResult.Valid {
+		msg.SPF = domain.SPFResult{Status: domain.SPFStatus(spfResult.String), Domain: spfDomain.String}
+	}
+	if dmarcResult.Valid {
+		msg.DMARC = domain.DMARCResult{
+			Status:  domain.DMARCStatus(dmarcResult.String),
+			Policy:  domain.DMARCPolicy(dmarcPolicy.String),
+			Aligned: dmarcAligned.Bool,
+		}
+	}
+	msg.AuthenticationResults = authenticationResults.String
+
 	msg.Sender = domain.Sender{
 		Id:    senderId,
 		Email: fmt.Sprintf("%s@%s", senderUsername, senderDomain),
@@ -193,6 +242,39 @@ func (repo *MessageRepository) FindFullByMessageID(ctx context.Context, messageI
 	}
 
 	msg.Files = files
+	if err := rows.Err(); err != nil {
+		return domain.FullMessage{}, e.Wrap(op, err)
+	}
+
+	// Письмо может лежать в нескольких папках пользователя
+	log.Debug("Getting message folders...")
+	folderRows, err := repo.db.QueryContext(ctx, `
+        SELECT fpm.folder_id
+        FROM folder_profile_message fpm
+        JOIN folder f ON f.id = fpm.folder_id
+        WHERE fpm.message_id = $1 AND f.profile_id = $2
+        ORDER BY fpm.folder_id`, messageID, profileID)
+	if err != nil {
+		return domain.FullMessage{}, e.Wrap(op, err)
+	}
+	defer folderRows.Close()
+
+	for folderRows.Next() {
+		var id int64
+		if err := folderRows.Scan(&id); err != nil {
+			return domain.FullMessage{}, e.Wrap(op, err)
+		}
+		msg.FolderIDs = append(msg.FolderIDs, id)
+	}
+	if err := folderRows.Err(); err != nil {
+		return domain.FullMessage{}, e.Wrap(op, err)
+	}
+
+	msg.Receivers, err = repo.FindRecipients(ctx, messageID)
+	if err != nil {
+		return domain.FullMessage{}, e.Wrap(op, err)
+	}
+
 	if err := tx.Commit(); err != nil {
 		return domain.FullMessage{}, e.Wrap(op+": failed to commit transaction: ", err)
 	}
@@ -295,6 +377,104 @@ func (repo *MessageRepository) SaveFile(ctx context.Context, messageID int64, fi
 	return fileID, nil
 }
 
+// CopyFiles привязывает к письму toMessageID вложения письма fromMessageID без повторной загрузки
+func (repo *MessageRepository) CopyFiles(ctx context.Context, fromMessageID, toMessageID int64) (int64, error) {
+	const op = "storage.postgresql.message.CopyFiles"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        INSERT INTO file (file_type, size, storage_path, message_id)
+        SELECT file_type, size, storage_path, $2
+        FROM file
+        WHERE message_id = $1
+        ORDER BY id`
+
+	log.Debug("Copying files...")
+	result, err := repo.db.ExecContext(ctx, query, fromMessageID, toMessageID)
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	copied, err := result.RowsAffected()
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	return copied, nil
+}
+
+// CollectContact запоминает адрес, на который пользователь отправил письмо: при первой
+// отправке создает контакт с именем из профиля получателя, иначе обновляет статистику адреса
+func (repo *MessageRepository) CollectContact(ctx context.Context, senderBaseProfileID int64, email string, usedAt time.Time) error {
+	const op = "storage.postgresql.message.CollectContact"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return e.Wrap(op+": failed to begin transaction: ", err)
+	}
+	defer tx.Rollback()
+
+	var profileID int64
+	err = tx.QueryRowContext(ctx, `
+        SELECT id FROM profile
+        WHERE base_profile_id = $1`,
+		senderBaseProfileID).Scan(&profileID)
+	if err != nil {
+		return e.Wrap(op+": failed to get profile: ", err)
+	}
+
+	log.Debug("Updating contact usage...")
+	result, err := tx.ExecContext(ctx, `
+        UPDATE contact_email
+        SET use_count = use_count + 1, last_used_at = $3
+        WHERE profile_id = $1 AND email = LOWER($2)`,
+		profileID, email, usedAt)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if updated == 0 {
+		log.Debug("Creating contact...")
+		var contactID int64
+		err = tx.QueryRowContext(ctx, `
+            INSERT INTO contact (profile_id, name)
+            SELECT $1, LEFT(TRIM(CONCAT_WS(' ', rp.name, rp.surname)), 100)
+            FROM (SELECT 1) AS one
+            LEFT JOIN base_profile rbp ON LOWER(rbp.username || '@' || rbp.domain) = LOWER($2)
+            LEFT JOIN profile rp ON rp.base_profile_id = rbp.id
+            LIMIT 1
+            RETURNING id`,
+			profileID, email).Scan(&contactID)
+		if err != nil {
+			return e.Wrap(op, err)
+		}
+
+		result, err = tx.ExecContext(ctx, `
+            INSERT INTO contact_email (contact_id, profile_id, email, use_count, last_used_at)
+            VALUES ($1, $2, LOWER($3), 1, $4)
+            ON CONFLICT (profile_id, email) DO NOTHING`,
+			contactID, profileID, email, usedAt)
+		if err != nil {
+			return e.Wrap(op, err)
+		}
+		if added, err := result.RowsAffected(); err != nil || added == 0 {
+			// адрес успели добавить параллельно, пустой контакт не нужен
+			return nil
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return e.Wrap(op+": failed to commit transaction: ", err)
+	}
+
+	return nil
+}
+
 func (repo *MessageRepository) SaveThread(ctx context.Context, messageID int64) (threadID int64, err error) {
 	const op = "storage.postgresql.message.SaveThread"
 	log := logger.GetLogger(ctx).With(slog.String("op", op))
@@ -449,6 +629,16 @@ func (repo *MessageRepository) MarkMessageAsSpam(ctx context.Context, messageID
 	}
 	defer tx.Rollback()
 
+	if err := markMessageAsSpamTx(ctx, tx, log, messageID, profileID); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	log.Debug("Committing transaction...")
+	return tx.Commit()
+}
+
+func markMessageAsSpamTx(ctx context.Context, tx *sql.Tx, log *slog.Logger, messageID, profileID int64) error {
+	var err error
 	var spamFolderID int64
 	log.Debug("Getting spam folder ID...")
 	err = tx.QueryRowContext(ctx, `
@@ -456,7 +646,7 @@ func (repo *MessageRepository) MarkMessageAsSpam(ctx context.Context, messageID
         WHERE profile_id = $1 AND folder_type = 'spam'`,
 		profileID).Scan(&spamFolderID)
 	if err != nil {
-		return e.Wrap(op+": failed to get spam folder: ", err)
+		return e.Wrap("failed to get spam folder", err)
 	}
 
 	const moveQuery = `
@@ -467,7 +657,7 @@ func (repo *MessageRepository) MarkMessageAsSpam(ctx context.Context, messageID
 	log.Debug("Moving message to spam folder...")
 	_, err = tx.ExecContext(ctx, moveQuery, messageID, spamFolderID)
 	if err != nil {
-		return e.Wrap(op+": failed to move message to spam: ", err)
+		return e.Wrap("failed to move message to spam", err)
 	}
 
 	const deleteQuery = `
@@ -481,13 +671,79 @@ func (repo *MessageRepository) MarkMessageAsSpam(ctx context.Context, messageID
 	log.Debug("Removing message from other folders...")
 	_, err = tx.ExecContext(ctx, deleteQuery, messageID, profileID)
 	if err != nil {
-		return e.Wrap(op+": failed to remove from other folders: ", err)
+		return e.Wrap("failed to remove from other folders", err)
+	}
+
+	return nil
+}
+
+// MarkMessageAsNotSpam - возвращает письмо из спама во входящие
+func (repo *MessageRepository) MarkMessageAsNotSpam(ctx context.Context, messageID int64, profileID int64) error {
+	const op = "storage.postgresql.message.MarkMessageAsNotSpam"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return e.Wrap(op+": failed to begin transaction: ", err)
+	}
+	defer tx.Rollback()
+
+	if err := markMessageAsNotSpamTx(ctx, tx, log, messageID, profileID); err != nil {
+		return e.Wrap(op, err)
 	}
 
 	log.Debug("Committing transaction...")
 	return tx.Commit()
 }
 
+func markMessageAsNotSpamTx(ctx context.Context, tx *sql.Tx, log *slog.Logger, messageID, profileID int64) error {
+	var err error
+	var inboxFolderID int64
+	log.Debug("Getting inbox folder ID...")
+	err = tx.QueryRowContext(ctx, `
+        SELECT id FROM folder 
+        WHERE profile_id = $1 AND folder_type = 'inbox'`,
+		profileID).Scan(&inboxFolderID)
+	if err != nil {
+		return e.Wrap("failed to get inbox folder", err)
+	}
+
+	const deleteQuery = `
+        DELETE FROM folder_profile_message 
+        WHERE message_id = $1 
+        AND folder_id IN (
+            SELECT id FROM folder 
+            WHERE profile_id = $2 AND folder_type = 'spam'
+        )`
+
+	log.Debug("Removing message from spam folder...")
+	result, err := tx.ExecContext(ctx, deleteQuery, messageID, profileID)
+	if err != nil {
+		return e.Wrap("failed to remove from spam folder", err)
+	}
+	// Во входящие попадает только письмо из спама этого пользователя
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return e.Wrap("failed to remove from spam folder", err)
+	}
+	if affected == 0 {
+		return domain.ErrMessageNotFound
+	}
+
+	const moveQuery = `
+        INSERT INTO folder_profile_message (message_id, folder_id)
+        SELECT $1, $2
+        ON CONFLICT (message_id, folder_id) DO NOTHING`
+
+	log.Debug("Moving message to inbox folder...")
+	_, err = tx.ExecContext(ctx, moveQuery, messageID, inboxFolderID)
+	if err != nil {
+		return e.Wrap("failed to move message to inbox", err)
+	}
+
+	return nil
+}
+
 func (repo *MessageRepository) SaveDraft(ctx context.Context, profileID int64, draftID, receiverEmail, topic, text string) (int64, error) {
 	const op = "storage.postgresql.message.SaveDraft"
 	log := logger.GetLogger(ctx).With(slog.String("op", op))
@@ -770,6 +1026,30 @@ func (repo *MessageRepository) MoveToFolder(ctx context.Context, profileID, mess
 	}
 	defer tx.Rollback()
 
+	if err := moveToFolderTx(ctx, tx, log, profileID, messageID, folderID); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	log.Debug("Committing transaction...")
+	return tx.Commit()
+}
+
+func moveToFolderTx(ctx context.Context, tx *sql.Tx, log *slog.Logger, profileID, messageID, folderID int64) error {
+	var err error
+	// Запоминаем исходную папку, чтобы письмо можно было вернуть из корзины
+	var originalFolderID sql.NullInt64
+	log.Debug("Getting original folder...")
+	err = tx.QueryRowContext(ctx, `
+        SELECT fpm.folder_id
+        FROM folder_profile_message fpm
+        JOIN folder f ON fpm.folder_id = f.id
+        WHERE fpm.message_id = $1 AND f.profile_id = $2 AND f.folder_type != 'trash'
+        ORDER BY fpm.created_at DESC
+        LIMIT 1`, messageID, profileID).Scan(&originalFolderID)
+	if err != nil && err != sql.ErrNoRows {
+		return e.Wrap("failed to get original folder", err)
+	}
+
 	log.Debug("Removing from all user folders...")
 	_, err = tx.ExecContext(ctx, `
         DELETE FROM folder_profile_message 
@@ -777,19 +1057,18 @@ func (repo *MessageRepository) MoveToFolder(ctx context.Context, profileID, mess
             SELECT id FROM folder WHERE profile_id = $2
         )`, messageID, profileID)
 	if err != nil {
-		return e.Wrap(op+": failed to remove from folders: ", err)
+		return e.Wrap("failed to remove from folders", err)
 	}
 
 	log.Debug("Adding to target folder...")
 	_, err = tx.ExecContext(ctx, `
-        INSERT INTO folder_profile_message (message_id, folder_id)
-        VALUES ($1, $2)`, messageID, folderID)
+        INSERT INTO folder_profile_message (message_id, folder_id, original_folder_id)
+        VALUES ($1, $2, $3)`, messageID, folderID, originalFolderID)
 	if err != nil {
-		return e.Wrap(op+": failed to add to folder: ", err)
+		return e.Wrap("failed to add to folder", err)
 	}
 
-	log.Debug("Committing transaction...")
-	return tx.Commit()
+	return nil
 }
 
 func (repo *MessageRepository) GetFolderByType(ctx context.Context, profileID int64, folderType string) (int64, error) {
@@ -841,7 +1120,7 @@ func (repo *MessageRepository) CreateFolder(ctx context.Context, profileID int64
 	const op = "storage.postgresql.message.CreateFolder"
 	log := logger.GetLogger(ctx).With(slog.String("op", op))
 
-	exists, err := repo.folderExists(ctx, profileID, folderName, 0)
+	exists, err := folderExists(ctx, repo.db, profileID, 0, folderName, 0)
 	if err != nil {
 		return nil, e.Wrap(op, err)
 	}
@@ -871,7 +1150,7 @@ func (repo *MessageRepository) GetUserFolders(ctx context.Context, profileID int
 	log := logger.GetLogger(ctx).With(slog.String("op", op))
 
 	const query = `
-        SELECT id, folder_name, folder_type
+        SELECT id, folder_name, folder_type, COALESCE(parent_id, 0), message_total, message_unread
         FROM folder
         WHERE profile_id = $1
         ORDER BY 
@@ -895,7 +1174,7 @@ func (repo *MessageRepository) GetUserFolders(ctx context.Context, profileID int
 	log.Debug("Scanning folders...")
 	for rows.Next() {
 		var folder domain.Folder
-		err := rows.Scan(&folder.ID, &folder.Name, &folder.Type)
+		err := rows.Scan(&folder.ID, &folder.Name, &folder.Type, &folder.ParentID, &folder.MessageTotal, &folder.MessageUnread)
 		if err != nil {
 			return nil, e.Wrap(op, err)
 		}
@@ -914,7 +1193,20 @@ func (repo *MessageRepository) RenameFolder(ctx context.Context, profileID, fold
 	const op = "storage.postgresql.message.RenameFolder"
 	log := logger.GetLogger(ctx).With(slog.String("op", op))
 
-	exists, err := repo.folderExists(ctx, profileID, newName, folderID)
+	var parentID int64
+	log.Debug("Querying folder parent...")
+	err := repo.db.QueryRowContext(ctx, `
+        SELECT COALESCE(parent_id, 0) FROM folder
+        WHERE id = $1 AND profile_id = $2`,
+		folderID, profileID).Scan(&parentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrFolderNotFound
+		}
+		return nil, e.Wrap(op, err)
+	}
+
+	exists, err := folderExists(ctx, repo.db, profileID, parentID, newName, folderID)
 	if err != nil {
 		return nil, e.Wrap(op, err)
 	}
@@ -937,6 +1229,7 @@ func (repo *MessageRepository) RenameFolder(ctx context.Context, profileID, fold
 	}
 
 	folder.ProfileID = profileID
+	folder.ParentID = parentID
 	return &folder, nil
 }
 
@@ -977,26 +1270,37 @@ func (repo *MessageRepository) DeleteFolder(ctx context.Context, profileID, fold
 		return e.Wrap(op+": failed to get trash folder: ", err)
 	}
 
-	log.Debug("Moving messages from deleted folder to trash...")
-	_, err = tx.ExecContext(ctx, `
+	// Исходная папка удаляется, поэтому такие письма восстанавливаются из корзины во входящие.
+	// Письма вложенных папок тоже уходят в корзину
+	log.Debug("Moving messages from deleted folder tree to trash...")
+	_, err = tx.ExecContext(ctx, `WITH RECURSIVE subtree AS (
+            SELECT id FROM folder WHERE id = $2
+            UNION ALL
+            SELECT f.id FROM folder f JOIN subtree s ON f.parent_id = s.id
+        )
         INSERT INTO folder_profile_message (message_id, folder_id)
-        SELECT fpm.message_id, $1
+        SELECT DISTINCT fpm.message_id, $1::integer
         FROM folder_profile_message fpm
-        WHERE fpm.folder_id = $2
+        WHERE fpm.folder_id IN (SELECT id FROM subtree)
         ON CONFLICT DO NOTHING`,
 		trashFolderID, folderID)
 	if err != nil {
 		return e.Wrap(op+": failed to move messages to trash: ", err)
 	}
 
-	log.Debug("Removing folder messages...")
-	_, err = tx.ExecContext(ctx, `
+	log.Debug("Removing folder tree messages...")
+	_, err = tx.ExecContext(ctx, `WITH RECURSIVE subtree AS (
+            SELECT id FROM folder WHERE id = $1
+            UNION ALL
+            SELECT f.id FROM folder f JOIN subtree s ON f.parent_id = s.id
+        )
         DELETE FROM folder_profile_message 
-        WHERE folder_id = $1`, folderID)
+        WHERE folder_id IN (SELECT id FROM subtree)`, folderID)
 	if err != nil {
 		return e.Wrap(op+": failed to remove folder messages: ", err)
 	}
 
+	// Вложенные папки удаляются каскадно по parent_id
 	log.Debug("Deleting folder...")
 	_, err = tx.ExecContext(ctx, `DELETE FROM folder WHERE id = $1`, folderID)
 	if err != nil {
@@ -1007,6 +1311,160 @@ func (repo *MessageRepository) DeleteFolder(ctx context.Context, profileID, fold
 	return tx.Commit()
 }
 
+// folderInfo возвращает тип, имя и глубину папки (1 - папка верхнего уровня)
+func folderInfo(ctx context.Context, q queryRower, profileID, folderID int64) (folderType, name string, depth int, err error) {
+	const query = `
+        WITH RECURSIVE ancestors AS (
+            SELECT id, parent_id FROM folder WHERE id = $1 AND profile_id = $2
+            UNION ALL
+            SELECT f.id, f.parent_id FROM folder f JOIN ancestors a ON f.id = a.parent_id
+        )
+        SELECT f.folder_type, f.folder_name, (SELECT COUNT(*) FROM ancestors)
+        FROM folder f
+        WHERE f.id = $1 AND f.profile_id = $2`
+
+	err = q.QueryRowContext(ctx, query, folderID, profileID).Scan(&folderType, &name, &depth)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = domain.ErrFolderNotFound
+	}
+	return folderType, name, depth, err
+}
+
+// CreateSubfolder создает пользовательскую папку внутри parentID, нулевой parentID означает верхний уровень
+func (repo *MessageRepository) CreateSubfolder(ctx context.Context, profileID, parentID int64, folderName string) (*domain.Folder, error) {
+	const op = "storage.postgresql.message.CreateSubfolder"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	if parentID == 0 {
+		return repo.CreateFolder(ctx, profileID, folderName)
+	}
+
+	log.Debug("Checking parent folder...")
+	parentType, _, parentDepth, err := folderInfo(ctx, repo.db, profileID, parentID)
+	if err != nil {
+		if errors.Is(err, domain.ErrFolderNotFound) {
+			return nil, err
+		}
+		return nil, e.Wrap(op, err)
+	}
+	if parentType != "custom" || parentDepth+1 > domain.MaxFolderDepth {
+		return nil, domain.ErrInvalidFolderParent
+	}
+
+	exists, err := folderExists(ctx, repo.db, profileID, parentID, folderName, 0)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	if exists {
+		return nil, domain.ErrFolderExists
+	}
+
+	const query = `
+        INSERT INTO folder (profile_id, folder_name, folder_type, parent_id)
+        VALUES ($1, $2, 'custom', $3)
+        RETURNING id, folder_name, folder_type`
+
+	var folder domain.Folder
+	log.Debug("Creating subfolder...")
+	err = repo.db.QueryRowContext(ctx, query, profileID, folderName, parentID).Scan(
+		&folder.ID, &folder.Name, &folder.Type)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	folder.ProfileID = profileID
+	folder.ParentID = parentID
+	return &folder, nil
+}
+
+// MoveFolder переносит папку вместе с вложенными в newParentID, нулевой newParentID - на верхний уровень
+func (repo *MessageRepository) MoveFolder(ctx context.Context, profileID, folderID, newParentID int64) (*domain.Folder, error) {
+	const op = "storage.postgresql.message.MoveFolder"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, e.Wrap(op+": failed to begin transaction: ", err)
+	}
+	defer tx.Rollback()
+
+	log.Debug("Checking folder...")
+	folderType, folderName, _, err := folderInfo(ctx, tx, profileID, folderID)
+	if err != nil {
+		if errors.Is(err, domain.ErrFolderNotFound) {
+			return nil, err
+		}
+		return nil, e.Wrap(op, err)
+	}
+	if folderType != "custom" {
+		return nil, domain.ErrFolderSystem
+	}
+
+	if newParentID != 0 {
+		log.Debug("Checking new parent folder...")
+		parentType, _, parentDepth, err := folderInfo(ctx, tx, profileID, newParentID)
+		if err != nil {
+			if errors.Is(err, domain.ErrFolderNotFound) {
+				return nil, err
+			}
+			return nil, e.Wrap(op, err)
+		}
+		if parentType != "custom" {
+			return nil, domain.ErrInvalidFolderParent
+		}
+
+		// Новый родитель не должен лежать внутри переносимой папки, а итоговая глубина - превышать лимит
+		var height int
+		var cycle bool
+		log.Debug("Checking folder subtree...")
+		err = tx.QueryRowContext(ctx, `
+            WITH RECURSIVE subtree AS (
+                SELECT id, 1 AS depth FROM folder WHERE id = $1
+                UNION ALL
+                SELECT f.id, s.depth + 1 FROM folder f JOIN subtree s ON f.parent_id = s.id
+            )
+            SELECT MAX(depth), BOOL_OR(id = $2) FROM subtree`,
+			folderID, newParentID).Scan(&height, &cycle)
+		if err != nil {
+			return nil, e.Wrap(op+": failed to check subtree: ", err)
+		}
+		if cycle || parentDepth+height > domain.MaxFolderDepth {
+			return nil, domain.ErrInvalidFolderParent
+		}
+	}
+
+	exists, err := folderExists(ctx, tx, profileID, newParentID, folderName, folderID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	if exists {
+		return nil, domain.ErrFolderExists
+	}
+
+	const query = `
+        UPDATE folder
+        SET parent_id = NULLIF($1::integer, 0), updated_at = $2
+        WHERE id = $3 AND profile_id = $4
+        RETURNING id, folder_name, folder_type`
+
+	var folder domain.Folder
+	log.Debug("Moving folder...")
+	err = tx.QueryRowContext(ctx, query, newParentID, time.Now(), folderID, profileID).Scan(
+		&folder.ID, &folder.Name, &folder.Type)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	log.Debug("Committing transaction...")
+	if err = tx.Commit(); err != nil {
+		return nil, e.Wrap(op+": failed to commit transaction: ", err)
+	}
+
+	folder.ProfileID = profileID
+	folder.ParentID = newParentID
+	return &folder, nil
+}
+
 func (repo *MessageRepository) DeleteMessageFromFolder(ctx context.Context, profileID, messageID, folderID int64) error {
 	const op = "storage.postgresql.message.DeleteMessageFromFolder"
 	log := logger.GetLogger(ctx).With(slog.String("op", op))
@@ -1035,17 +1493,24 @@ func (repo *MessageRepository) DeleteMessageFromFolder(ctx context.Context, prof
 	return nil
 }
 
-func (repo *MessageRepository) folderExists(ctx context.Context, profileID int64, name string, excludeID int64) (bool, error) {
+// queryRower - общий интерфейс *sql.DB и *sql.Tx для запросов одной строки
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// folderExists проверяет, есть ли среди соседних папок (с тем же родителем) папка с таким именем
+func folderExists(ctx context.Context, q queryRower, profileID, parentID int64, name string, excludeID int64) (bool, error) {
 	const op = "storage.postgresql.message.folderExists"
 	const query = `
         SELECT 1 FROM folder
         WHERE profile_id = $1
           AND LOWER(folder_name) = LOWER($2)
           AND ($3 = 0 OR id <> $3)
+          AND COALESCE(parent_id, 0) = $4
         LIMIT 1`
 
 	var stub int
-	err := repo.db.QueryRowContext(ctx, query, profileID, name, excludeID).Scan(&stub)
+	err := q.QueryRowContext(ctx, query, profileID, name, excludeID, parentID).Scan(&stub)
 	if err == sql.ErrNoRows {
 		return false, nil
 	}
@@ -1055,69 +1520,196 @@ func (repo *MessageRepository) folderExists(ctx context.Context, profileID int64
 	return true, nil
 }
 
+// GetFolderMessagesWithKeysetPagination отдает страницу писем папки, labelID != 0 оставляет только письма с этой меткой
 func (repo *MessageRepository) GetFolderMessagesWithKeysetPagination(
 	ctx context.Context,
-	profileID, folderID, lastMessageID int64,
-	lastDatetime time.Time,
-	limit int,
+	profileID, folderID, labelID int64,
+	page domain.MessagePage,
 ) ([]domain.Message, error) {
 	const op = "storage.postgresql.message.GetFolderMessagesWithKeysetPagination"
 	log := logger.GetLogger(ctx).With(slog.String("op", op))
 
-	const query = `
-        SELECT
-            m.id, m.topic, m.text, m.date_of_dispatch,
-            pm.read_status,
-            bp.id, bp.username, bp.domain,
-            sender_profile.name, sender_profile.surname, sender_profile.image_path
-        FROM
-            message m
-        JOIN
-            profile_message pm ON m.id = pm.message_id
-        JOIN
-            folder_profile_message fpm ON m.id = fpm.message_id
+	query, args := buildMessageListQuery(folderMessagesQuery, []any{profileID, folderID, labelID}, page)
+
+	log.Debug("Querying folder messages with pagination...")
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	log.Debug("Scanning messages...")
+	messages, err := scanMessageList(rows)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if page.Backward {
+		slices.Reverse(messages)
+	}
+	return messages, nil
+}
+
+// messageSortKey - выражение, по которому сортируется список, и направление сортировки.
+// При равных значениях порядок задает id письма в том же направлении
+type messageSortKey struct {
+	expr  string
+	desc  bool
+	value func(key domain.MessageKey) any
+}
+
+var messageSortKeys = map[domain.MessageSort]messageSortKey{
+	domain.SortDateDesc: {expr: "m.date_of_dispatch", desc: true, value: func(key domain.MessageKey) any { return key.Datetime }},
+	domain.SortDateAsc:  {expr: "m.date_of_dispatch", value: func(key domain.MessageKey) any { return key.Datetime }},
+	domain.SortSender:   {expr: "(" + senderAddressExpr + ")", value: func(key domain.MessageKey) any { return key.Sender }},
+	domain.SortTopic:    {expr: "COALESCE(m.topic, '')", value: func(key domain.MessageKey) any { return key.Topic }},
+	domain.SortSize:     {expr: "m.size", desc: true, value: func(key domain.MessageKey) any { return key.Size }},
+}
+
+// buildMessageListQuery дополняет запрос списка писем фильтрами, keyset-условием, сортировкой и лимитом.
+// Новые параметры нумеруются после параметров args базового запроса
+func buildMessageListQuery(base string, args []any, page domain.MessagePage) (string, []any) {
+	arg := func(value any) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	var query strings.Builder
+	query.WriteString(base)
+
+	filter := page.Filter
+	if filter.UnreadOnly {
+		query.WriteString(`
+            AND NOT pm.read_status`)
+	}
+	if filter.StarredOnly {
+		query.WriteString(`
+            AND pm.starred`)
+	}
+	if filter.WithAttachments {
+		query.WriteString(`
+            AND EXISTS (SELECT 1 FROM file fl WHERE fl.message_id = m.id)`)
+	}
+	if sender := strings.TrimSpace(filter.Sender); sender != "" {
+		query.WriteString(`
+            AND LOWER(` + senderAddressExpr + `) = LOWER(` + arg(sender) + `)`)
+	}
+	if !filter.After.IsZero() {
+		query.WriteString(`
+            AND m.date_of_dispatch >= ` + arg(filter.After))
+	}
+	if !filter.Before.IsZero() {
+		query.WriteString(`
+            AND m.date_of_dispatch < ` + arg(filter.Before))
+	}
+
+	key, ok := messageSortKeys[page.Sort]
+	if !ok {
+		key = messageSortKeys[domain.SortDateDesc]
+	}
+	// Предыдущая страница читается в обратном порядке от граничного письма и переворачивается после чтения
+	desc := key.desc != page.Backward
+
+	if page.After != nil {
+		cmp := ">"
+		if desc {
+			cmp = "<"
+		}
+		fmt.Fprintf(&query, `
+            AND (%s, m.id) %s (%s, %s)`, key.expr, cmp, arg(key.value(*page.After)), arg(page.After.MessageID))
+	}
+
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+	fmt.Fprintf(&query, `
+        ORDER BY
+            %s %s, m.id %s
+        LIMIT %s`, key.expr, direction, direction, arg(page.Limit))
+
+	return query.String(), args
+}
+
+// senderAddressColumns - имя и домен отправителя письма m. Адрес из письма (внешний отправитель
+// или дополнительный адрес пользователя) важнее основного адреса bp
+const senderAddressColumns = `COALESCE(m.sender_username, bp.username), COALESCE(m.sender_domain, bp.domain)`
+
+// senderAddressExpr - адрес отправителя письма m целиком
+const senderAddressExpr = `COALESCE(m.sender_username, bp.username) || '@' || COALESCE(m.sender_domain, bp.domain)`
+
+// folderMessagesQuery - письма папки $2 пользователя $1, при $3 != 0 - только с меткой $3.
+// Запросы дописывают к нему условие по ключу страницы, сортировку и LIMIT
+const folderMessagesQuery = `
+        SELECT
+            m.id, m.topic, m.text, m.date_of_dispatch, m.size,
+            pm.read_status, pm.starred, pm.important, pm.answered, pm.forwarded,
+            ` + messageLabelsColumn + `,
+            COALESCE(bp.id, 0), ` + senderAddressColumns + `,
+            sender_profile.name, sender_profile.surname, sender_profile.image_path,
+            ` + senderContactNameColumn + `
+        FROM
+            message m
+        JOIN
+            folder_profile_message fpm ON m.id = fpm.message_id
         JOIN
             folder f ON fpm.folder_id = f.id
         JOIN
+            profile_message pm ON m.id = pm.message_id AND pm.profile_id = f.profile_id
+        LEFT JOIN
             base_profile bp ON m.sender_base_profile_id = bp.id
         LEFT JOIN
             profile sender_profile ON bp.id = sender_profile.base_profile_id
         WHERE
             f.profile_id = $1 AND f.id = $2
-            AND (($3 = 0 AND $4 = 0) OR (m.date_of_dispatch, m.id) < (to_timestamp($4), $3))
-        ORDER BY
-            m.date_of_dispatch DESC, m.id DESC
-        LIMIT $5`
-
-	lastUnix := int64(0)
-	if !lastDatetime.IsZero() {
-		lastUnix = lastDatetime.Unix()
-	}
-
-	log.Debug("Querying folder messages with pagination...")
-	rows, err := repo.db.QueryContext(ctx, query, profileID, folderID, lastMessageID, lastUnix, limit)
-	if err != nil {
-		return nil, e.Wrap(op, err)
-	}
-	defer rows.Close()
-
+            AND ($3 = 0 OR EXISTS (
+                SELECT 1 FROM message_label ml
+                JOIN label l ON ml.label_id = l.id
+                WHERE ml.message_id = m.id AND l.id = $3 AND l.profile_id = $1
+            ))`
+
+// messageLabelsColumn - метки письма, которые повесил владелец списка ($1), в виде JSON-массива
+const messageLabelsColumn = `COALESCE((
+                SELECT json_agg(json_build_object('id', l.id, 'name', l.name, 'color', l.color) ORDER BY LOWER(l.name), l.id)
+                FROM message_label ml
+                JOIN label l ON ml.label_id = l.id
+                WHERE ml.message_id = m.id AND l.profile_id = $1
+            ), '[]')`
+
+// senderContactNameColumn - имя, под которым отправитель записан в контактах владельца списка ($1)
+const senderContactNameColumn = `(
+                SELECT c.name
+                FROM contact_email ce
+                JOIN contact c ON ce.contact_id = c.id
+                WHERE ce.profile_id = $1 AND ce.email = LOWER(` + senderAddressExpr + `)
+                    AND c.name <> ''
+            )`
+
+// scanMessageList читает письма списка папки: письмо, флаги и метки пользователя, отправитель.
+// Имя отправителя из контактов пользователя имеет приоритет над именем из его профиля
+func scanMessageList(rows *sql.Rows) ([]domain.Message, error) {
 	var messages []domain.Message
-	log.Debug("Scanning messages...")
 	for rows.Next() {
 		var message domain.Message
 		var messageIdInt int64
 		var senderId int64
 		var senderUsername, senderDomain string
-		var senderName, senderSurname, senderAvatar sql.NullString
+		var senderName, senderSurname, senderAvatar, contactName sql.NullString
 		var text string
+		var labels []byte
 
 		err := rows.Scan(
-			&messageIdInt, &message.Topic, &text, &message.Datetime, &message.IsRead,
+			&messageIdInt, &message.Topic, &text, &message.Datetime, &message.Size, &message.IsRead,
+			&message.IsStarred, &message.IsImportant, &message.IsAnswered, &message.IsForwarded,
+			&labels,
 			&senderId, &senderUsername, &senderDomain,
-			&senderName, &senderSurname, &senderAvatar,
+			&senderName, &senderSurname, &senderAvatar, &contactName,
 		)
 		if err != nil {
-			return nil, e.Wrap(op, err)
+			return nil, err
+		}
+		if err := json.Unmarshal(labels, &message.Labels); err != nil {
+			return nil, err
 		}
 		message.ID = strconv.FormatInt(messageIdInt, 10)
 		message.Snippet = buildSnippet(text, 40)
@@ -1128,31 +1720,56 @@ func (repo *MessageRepository) GetFolderMessagesWithKeysetPagination(
 				senderName.String, senderSurname.String)),
 			Avatar: senderAvatar.String,
 		}
+		if contactName.Valid {
+			message.Sender.Username = contactName.String
+		}
 		messages = append(messages, message)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, e.Wrap(op, err)
-	}
-
-	return messages, nil
+	return messages, rows.Err()
 }
 
-func (repo *MessageRepository) GetFolderMessagesInfo(ctx context.Context, profileID, folderID int64) (domain.Messages, error) {
+func (repo *MessageRepository) GetFolderMessagesInfo(ctx context.Context, profileID, folderID, labelID int64) (domain.Messages, error) {
 	const op = "storage.postgresql.message.GetFolderMessagesInfo"
 	log := logger.GetLogger(ctx).With(slog.String("op", op))
 
+	// Без фильтра по метке используются счетчики папки, чтобы не пересчитывать все письма
+	if labelID == 0 {
+		const countersQuery = `
+        SELECT message_total, message_unread
+        FROM folder
+        WHERE id = $1 AND profile_id = $2`
+
+		var messagesInfo domain.Messages
+		log.Debug("Getting folder counters...")
+		err := repo.db.QueryRowContext(ctx, countersQuery, folderID, profileID).Scan(
+			&messagesInfo.MessageTotal, &messagesInfo.MessageUnread)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.Messages{}, nil
+			}
+			return domain.Messages{}, e.Wrap(op, err)
+		}
+
+		return messagesInfo, nil
+	}
+
 	const query = `
         SELECT 
             COUNT(*) as total_count,
             COUNT(CASE WHEN pm.read_status = false THEN 1 END) as unread_count
         FROM folder_profile_message fpm
         JOIN profile_message pm ON fpm.message_id = pm.message_id AND pm.profile_id = $1
-        WHERE fpm.folder_id = $2`
+        WHERE fpm.folder_id = $2
+        AND ($3 = 0 OR EXISTS (
+            SELECT 1 FROM message_label ml
+            JOIN label l ON ml.label_id = l.id
+            WHERE ml.message_id = fpm.message_id AND l.id = $3 AND l.profile_id = $1
+        ))`
 
 	var messagesInfo domain.Messages
 	log.Debug("Getting folder messages info...")
-	err := repo.db.QueryRowContext(ctx, query, profileID, folderID).Scan(
+	err := repo.db.QueryRowContext(ctx, query, profileID, folderID, labelID).Scan(
 		&messagesInfo.MessageTotal, &messagesInfo.MessageUnread)
 	if err != nil {
 		return domain.Messages{}, e.Wrap(op, err)
@@ -1392,3 +2009,1067 @@ func (repo *MessageRepository) IsUsersMessage(ctx context.Context, messageID int
 
 	return isUserMessage, nil
 }
+
+func (repo *MessageRepository) GetDelivery(ctx context.Context, messageID int64, receiverEmail string) (domain.Delivery, error) {
+	const op = "storage.postgresql.message.GetDelivery"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	parts := strings.SplitN(receiverEmail, "@", 2)
+	if len(parts) != 2 {
+		return domain.Delivery{}, e.Wrap(op, fmt.Errorf("invalid receiver email %q", receiverEmail))
+	}
+
+	const query = `
+        SELECT
+            m.id, m.topic, m.text, m.date_of_dispatch,
+            COALESCE(m.sender_username, sbp.username), COALESCE(m.sender_domain, sbp.domain),
+            sp.name, sp.surname,
+            p.id, p.base_profile_id
+        FROM
+            message m
+        LEFT JOIN
+            base_profile sbp ON m.sender_base_profile_id = sbp.id
+        LEFT JOIN
+            profile sp ON sbp.id = sp.base_profile_id
+        JOIN
+            base_profile rbp ON rbp.username = $2 AND rbp.domain = $3
+        JOIN
+            profile p ON p.base_profile_id = rbp.id
+        WHERE
+            m.id = $1`
+
+	var delivery domain.Delivery
+	var senderUsername, senderDomain string
+	var senderName, senderSurname sql.NullString
+
+	log.Debug("Querying delivery...")
+	err := repo.db.QueryRowContext(ctx, query, messageID, parts[0], parts[1]).Scan(
+		&delivery.MessageID, &delivery.Topic, &delivery.Text, &delivery.Datetime,
+		&senderUsername, &senderDomain,
+		&senderName, &senderSurname,
+		&delivery.ProfileID, &delivery.BaseProfileID,
+	)
+	if err != nil {
+		return domain.Delivery{}, e.Wrap(op, err)
+	}
+
+	delivery.From = fmt.Sprintf("%s@%s", senderUsername, senderDomain)
+	delivery.To = receiverEmail
+	delivery.Size = int64(len(delivery.Topic) + len(delivery.Text))
+
+	from := delivery.From
+	if name := strings.TrimSpace(senderName.String + " " + senderSurname.String); name != "" {
+		from = (&mail.Address{Name: name, Address: delivery.From}).String()
+	}
+	delivery.Headers = map[string][]string{
+		"From":       {from},
+		"To":         {receiverEmail},
+		"Subject":    {mime.QEncoding.Encode("utf-8", delivery.Topic)},
+		"Date":       {delivery.Datetime.Format(time.RFC1123Z)},
+		"Message-Id": {fmt.Sprintf("<%d@%s>", delivery.MessageID, senderDomain)},
+	}
+
+	return delivery, nil
+}
+
+func (repo *MessageRepository) AddMessageToFolder(ctx context.Context, messageID, folderID int64) error {
+	const op = "storage.postgresql.message.AddMessageToFolder"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        INSERT INTO folder_profile_message (message_id, folder_id)
+        VALUES ($1, $2)
+        ON CONFLICT (message_id, folder_id) DO NOTHING`
+
+	log.Debug("Adding message to folder...")
+	_, err := repo.db.ExecContext(ctx, query, messageID, folderID)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// defaultTrashRetentionDays - срок хранения писем в корзине и спаме для профилей без настроек
+const defaultTrashRetentionDays = 30
+
+// RestoreFromTrash - возвращает письмо из корзины в исходную папку, а если ее нет - во входящие
+func (repo *MessageRepository) RestoreFromTrash(ctx context.Context, profileID, messageID int64) (int64, error) {
+	const op = "storage.postgresql.message.RestoreFromTrash"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, e.Wrap(op+": failed to begin transaction: ", err)
+	}
+	defer tx.Rollback()
+
+	var trashFolderID int64
+	var originalFolderID sql.NullInt64
+	log.Debug("Getting message from trash...")
+	err = tx.QueryRowContext(ctx, `
+        SELECT fpm.folder_id, fpm.original_folder_id
+        FROM folder_profile_message fpm
+        JOIN folder f ON fpm.folder_id = f.id
+        WHERE fpm.message_id = $1 AND f.profile_id = $2 AND f.folder_type = 'trash'`,
+		messageID, profileID).Scan(&trashFolderID, &originalFolderID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, domain.ErrMessageNotInTrash
+		}
+		return 0, e.Wrap(op+": failed to get message from trash: ", err)
+	}
+
+	targetFolderID := originalFolderID.Int64
+	if !originalFolderID.Valid {
+		log.Debug("Original folder is missing, restoring to inbox...")
+		err = tx.QueryRowContext(ctx, `
+            SELECT id FROM folder 
+            WHERE profile_id = $1 AND folder_type = 'inbox'`,
+			profileID).Scan(&targetFolderID)
+		if err != nil {
+			return 0, e.Wrap(op+": failed to get inbox folder: ", err)
+		}
+	}
+
+	log.Debug("Restoring message to folder...")
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO folder_profile_message (message_id, folder_id)
+        VALUES ($1, $2)
+        ON CONFLICT (message_id, folder_id) DO NOTHING`,
+		messageID, targetFolderID)
+	if err != nil {
+		return 0, e.Wrap(op+": failed to restore message: ", err)
+	}
+
+	log.Debug("Removing message from trash...")
+	_, err = tx.ExecContext(ctx, `
+        DELETE FROM folder_profile_message 
+        WHERE message_id = $1 AND folder_id = $2`,
+		messageID, trashFolderID)
+	if err != nil {
+		return 0, e.Wrap(op+": failed to remove from trash: ", err)
+	}
+
+	log.Debug("Committing transaction...")
+	if err := tx.Commit(); err != nil {
+		return 0, e.Wrap(op+": failed to commit transaction: ", err)
+	}
+
+	return targetFolderID, nil
+}
+
+// EmptyTrash - удаляет все письма из корзины пользователя и возвращает их количество.
+// Письма, которые больше не лежат ни в одной папке, удаляются окончательно.
+func (repo *MessageRepository) EmptyTrash(ctx context.Context, profileID int64) (int64, error) {
+	const op = "storage.postgresql.message.EmptyTrash"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, e.Wrap(op+": failed to begin transaction: ", err)
+	}
+	defer tx.Rollback()
+
+	var trashFolderID int64
+	log.Debug("Fetching trash folder id...")
+	err = tx.QueryRowContext(ctx, `
+        SELECT id FROM folder 
+        WHERE profile_id = $1 AND folder_type = 'trash'`,
+		profileID).Scan(&trashFolderID)
+	if err != nil {
+		return 0, e.Wrap(op+": failed to get trash folder: ", err)
+	}
+
+	// Сначала удаляем письма, которые есть только в корзине: связи удалятся каскадно
+	log.Debug("Deleting messages referenced only by trash...")
+	result, err := tx.ExecContext(ctx, `
+        DELETE FROM message m
+        WHERE m.id IN (SELECT message_id FROM folder_profile_message WHERE folder_id = $1)
+        AND NOT EXISTS (
+            SELECT 1 FROM folder_profile_message fpm
+            WHERE fpm.message_id = m.id AND fpm.folder_id != $1
+        )`, trashFolderID)
+	if err != nil {
+		return 0, e.Wrap(op+": failed to delete messages: ", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	log.Debug("Removing remaining messages from trash...")
+	result, err = tx.ExecContext(ctx, `
+        DELETE FROM folder_profile_message 
+        WHERE folder_id = $1`, trashFolderID)
+	if err != nil {
+		return 0, e.Wrap(op+": failed to remove messages from trash: ", err)
+	}
+	unlinked, err := result.RowsAffected()
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	log.Debug("Committing transaction...")
+	if err := tx.Commit(); err != nil {
+		return 0, e.Wrap(op+": failed to commit transaction: ", err)
+	}
+
+	return deleted + unlinked, nil
+}
+
+// DeletePermanently - удаляет письмо из всех папок пользователя.
+// Само письмо удаляется, если оно не лежит в папках других пользователей.
+func (repo *MessageRepository) DeletePermanently(ctx context.Context, profileID, messageID int64) error {
+	const op = "storage.postgresql.message.DeletePermanently"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return e.Wrap(op+": failed to begin transaction: ", err)
+	}
+	defer tx.Rollback()
+
+	log.Debug("Deleting message without other owners...")
+	result, err := tx.ExecContext(ctx, `
+        DELETE FROM message m
+        WHERE m.id = $1
+        AND EXISTS (
+            SELECT 1 FROM folder_profile_message fpm
+            JOIN folder f ON fpm.folder_id = f.id
+            WHERE fpm.message_id = m.id AND f.profile_id = $2
+        )
+        AND NOT EXISTS (
+            SELECT 1 FROM folder_profile_message fpm
+            JOIN folder f ON fpm.folder_id = f.id
+            WHERE fpm.message_id = m.id AND f.profile_id != $2
+        )`, messageID, profileID)
+	if err != nil {
+		return e.Wrap(op+": failed to delete message: ", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	if deleted == 0 {
+		log.Debug("Removing message from user folders...")
+		result, err = tx.ExecContext(ctx, `
+            DELETE FROM folder_profile_message 
+            WHERE message_id = $1 AND folder_id IN (
+                SELECT id FROM folder WHERE profile_id = $2
+            )`, messageID, profileID)
+		if err != nil {
+			return e.Wrap(op+": failed to remove from folders: ", err)
+		}
+		unlinked, err := result.RowsAffected()
+		if err != nil {
+			return e.Wrap(op, err)
+		}
+		if unlinked == 0 {
+			return domain.ErrMessageNotFound
+		}
+	}
+
+	log.Debug("Committing transaction...")
+	return tx.Commit()
+}
+
+// PurgeExpiredMessages - убирает из корзины и спама письма, срок хранения которых истек
+// по настройкам пользователя. Возвращает количество удаленных связей.
+func (repo *MessageRepository) PurgeExpiredMessages(ctx context.Context, now time.Time) (int64, error) {
+	const op = "storage.postgresql.message.PurgeExpiredMessages"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        DELETE FROM folder_profile_message fpm
+        USING folder f
+        LEFT JOIN settings s ON s.profile_id = f.profile_id
+        WHERE fpm.folder_id = f.id
+        AND f.folder_type IN ('trash', 'spam')
+        AND COALESCE(s.trash_retention_days, $2) > 0
+        AND fpm.created_at < $1 - COALESCE(s.trash_retention_days, $2) * INTERVAL '1 day'`
+
+	log.Debug("Purging expired messages...")
+	result, err := repo.db.ExecContext(ctx, query, now, defaultTrashRetentionDays)
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	return purged, nil
+}
+
+// DeleteOrphanMessages - удаляет письма, созданные раньше olderThan, которые не лежат ни в одной папке
+// и не ждут отправки во внешней очереди. Возвращает число удаленных писем и пути вложений в хранилище,
+// на которые больше не ссылается ни одно письмо
+func (repo *MessageRepository) DeleteOrphanMessages(ctx context.Context, olderThan time.Time) (int64, []string, error) {
+	const op = "storage.postgresql.message.DeleteOrphanMessages"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, nil, e.Wrap(op+": failed to begin transaction: ", err)
+	}
+	defer tx.Rollback()
+
+	log.Debug("Selecting orphan messages...")
+	rows, err := tx.QueryContext(ctx, `
+        SELECT m.id
+        FROM message m
+        WHERE m.created_at < $1
+        AND NOT EXISTS (
+            SELECT 1 FROM folder_profile_message fpm WHERE fpm.message_id = m.id
+        )
+        AND NOT EXISTS (
+            SELECT 1 FROM outbound_message o
+            WHERE o.message_id = m.id AND o.status IN ('queued', 'deferred', 'sending')
+        )
+        FOR UPDATE SKIP LOCKED`, olderThan)
+	if err != nil {
+		return 0, nil, e.Wrap(op, err)
+	}
+	var messageIDs []int64
+	for rows.Next() {
+		var messageID int64
+		if err := rows.Scan(&messageID); err != nil {
+			rows.Close()
+			return 0, nil, e.Wrap(op, err)
+		}
+		messageIDs = append(messageIDs, messageID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, nil, e.Wrap(op, err)
+	}
+	if len(messageIDs) == 0 {
+		return 0, nil, nil
+	}
+
+	// Пересланные письма ссылаются на те же объекты хранилища, поэтому удалять можно
+	// только объекты, на которые не осталось ссылок
+	log.Debug("Deleting orphan messages...")
+	rows, err = tx.QueryContext(ctx, `
+        WITH deleted_files AS (
+            DELETE FROM file WHERE message_id = ANY($1::int[])
+            RETURNING storage_path
+        ), deleted_messages AS (
+            DELETE FROM message WHERE id = ANY($1::int[])
+            RETURNING id
+        )
+        SELECT DISTINCT df.storage_path
+        FROM deleted_files df
+        WHERE NOT EXISTS (
+            SELECT 1 FROM file f
+            WHERE f.storage_path = df.storage_path AND f.message_id <> ALL($1::int[])
+        )`, messageIDs)
+	if err != nil {
+		return 0, nil, e.Wrap(op, err)
+	}
+	var storagePaths []string
+	for rows.Next() {
+		var storagePath string
+		if err := rows.Scan(&storagePath); err != nil {
+			rows.Close()
+			return 0, nil, e.Wrap(op, err)
+		}
+		storagePaths = append(storagePaths, storagePath)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, nil, e.Wrap(op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, nil, e.Wrap(op+": failed to commit transaction: ", err)
+	}
+
+	return int64(len(messageIDs)), storagePaths, nil
+}
+
+// ApplyBatch - применяет операцию к набору писем в одной транзакции.
+// Ошибка отдельного письма откатывается до точки сохранения и не прерывает обработку остальных.
+func (repo *MessageRepository) ApplyBatch(ctx context.Context, profileID int64, batch domain.BatchOperation) (domain.BatchResult, error) {
+	const op = "storage.postgresql.message.ApplyBatch"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.BatchResult{}, e.Wrap(op+": failed to begin transaction: ", err)
+	}
+	defer tx.Rollback()
+
+	var folderID int64
+	switch batch.Action {
+	case domain.BatchMove:
+		folderID = batch.TargetFolderID
+	case domain.BatchDeleteFromFolder:
+		folderID = batch.Selector.FolderID
+	}
+	if folderID != 0 {
+		var exists int
+		log.Debug("Checking batch folder...")
+		err = tx.QueryRowContext(ctx, `
+            SELECT 1 FROM folder 
+            WHERE id = $1 AND profile_id = $2`,
+			folderID, profileID).Scan(&exists)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return domain.BatchResult{}, domain.ErrFolderNotFound
+			}
+			return domain.BatchResult{}, e.Wrap(op+": failed to check folder: ", err)
+		}
+	}
+
+	var result domain.BatchResult
+	messageIDs := batch.Selector.MessageIDs
+	checkOwnership := len(messageIDs) > 0
+	if !checkOwnership {
+		messageIDs, err = selectBatchMessages(ctx, tx, log, profileID, batch.Selector, domain.MaxBatchSize+1)
+		if err != nil {
+			return domain.BatchResult{}, e.Wrap(op, err)
+		}
+		if len(messageIDs) > domain.MaxBatchSize {
+			messageIDs = messageIDs[:domain.MaxBatchSize]
+			result.HasMore = true
+		}
+	}
+
+	result.Items = make([]domain.BatchItemResult, 0, len(messageIDs))
+	for _, messageID := range messageIDs {
+		if _, err := tx.ExecContext(ctx, `SAVEPOINT batch_item`); err != nil {
+			return domain.BatchResult{}, e.Wrap(op+": failed to create savepoint: ", err)
+		}
+
+		itemErr := applyBatchItem(ctx, tx, log, profileID, messageID, batch, checkOwnership)
+		item := domain.BatchItemResult{MessageID: messageID}
+		if itemErr != nil {
+			if _, err := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT batch_item`); err != nil {
+				return domain.BatchResult{}, e.Wrap(op+": failed to rollback savepoint: ", err)
+			}
+			if errors.Is(itemErr, domain.ErrMessageNotFound) {
+				item.Error = itemErr.Error()
+			} else {
+				log.Warn("batch item failed: "+itemErr.Error(), slog.Int64("message_id", messageID))
+				item.Error = "operation failed"
+			}
+		} else if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT batch_item`); err != nil {
+			return domain.BatchResult{}, e.Wrap(op+": failed to release savepoint: ", err)
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	log.Debug("Committing transaction...")
+	if err := tx.Commit(); err != nil {
+		return domain.BatchResult{}, e.Wrap(op+": failed to commit transaction: ", err)
+	}
+
+	return result, nil
+}
+
+// selectBatchMessages - выбирает письма папки пользователя по фильтру, новые первыми
+func selectBatchMessages(ctx context.Context, tx *sql.Tx, log *slog.Logger, profileID int64, selector domain.BatchSelector, limit int) ([]int64, error) {
+	const query = `
+        SELECT fpm.message_id
+        FROM folder_profile_message fpm
+        JOIN folder f ON fpm.folder_id = f.id
+        JOIN message m ON fpm.message_id = m.id
+        LEFT JOIN base_profile bp ON m.sender_base_profile_id = bp.id
+        LEFT JOIN profile_message pm ON pm.message_id = m.id AND pm.profile_id = f.profile_id
+        WHERE f.id = $1 AND f.profile_id = $2
+        AND ($3 = FALSE OR COALESCE(pm.read_status, FALSE) = FALSE)
+        AND ($4 = FALSE OR COALESCE(pm.starred, FALSE))
+        AND ($5 = FALSE OR EXISTS (SELECT 1 FROM file fl WHERE fl.message_id = m.id))
+        AND ($6 = '' OR LOWER(` + senderAddressExpr + `) = LOWER($6))
+        AND ($7::timestamptz IS NULL OR m.date_of_dispatch >= $7)
+        AND ($8::timestamptz IS NULL OR m.date_of_dispatch < $8)
+        ORDER BY m.date_of_dispatch DESC, m.id DESC
+        LIMIT $9`
+
+	filter := selector.Filter
+	after := sql.NullTime{Time: filter.After, Valid: !filter.After.IsZero()}
+	before := sql.NullTime{Time: filter.Before, Valid: !filter.Before.IsZero()}
+
+	log.Debug("Selecting batch messages...")
+	rows, err := tx.QueryContext(ctx, query,
+		selector.FolderID, profileID, filter.UnreadOnly, filter.StarredOnly, filter.WithAttachments,
+		strings.TrimSpace(filter.Sender), after, before, limit)
+	if err != nil {
+		return nil, e.Wrap("failed to select messages", err)
+	}
+	defer rows.Close()
+
+	var messageIDs []int64
+	for rows.Next() {
+		var messageID int64
+		if err := rows.Scan(&messageID); err != nil {
+			return nil, e.Wrap("failed to scan message id", err)
+		}
+		messageIDs = append(messageIDs, messageID)
+	}
+
+	return messageIDs, rows.Err()
+}
+
+func applyBatchItem(ctx context.Context, tx *sql.Tx, log *slog.Logger, profileID, messageID int64, batch domain.BatchOperation, checkOwnership bool) error {
+	if checkOwnership {
+		var owned bool
+		err := tx.QueryRowContext(ctx, `
+            SELECT EXISTS (
+                SELECT 1 FROM folder_profile_message fpm
+                JOIN folder f ON fpm.folder_id = f.id
+                WHERE fpm.message_id = $1 AND f.profile_id = $2
+            )`, messageID, profileID).Scan(&owned)
+		if err != nil {
+			return e.Wrap("failed to check message owner", err)
+		}
+		if !owned {
+			return domain.ErrMessageNotFound
+		}
+	}
+
+	switch batch.Action {
+	case domain.BatchMove:
+		return moveToFolderTx(ctx, tx, log, profileID, messageID, batch.TargetFolderID)
+	case domain.BatchMarkAsSpam:
+		return markMessageAsSpamTx(ctx, tx, log, messageID, profileID)
+	case domain.BatchMarkAsNotSpam:
+		return markMessageAsNotSpamTx(ctx, tx, log, messageID, profileID)
+	case domain.BatchDeleteFromFolder:
+		result, err := tx.ExecContext(ctx, `
+            DELETE FROM folder_profile_message 
+            WHERE message_id = $1 AND folder_id = $2`,
+			messageID, batch.Selector.FolderID)
+		if err != nil {
+			return e.Wrap("failed to delete from folder", err)
+		}
+		if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+			return domain.ErrMessageNotFound
+		}
+		return nil
+	case domain.BatchMarkAsRead:
+		_, err := tx.ExecContext(ctx, `
+            INSERT INTO profile_message (profile_id, message_id, read_status)
+            VALUES ($1, $2, TRUE)
+            ON CONFLICT (profile_id, message_id)
+            DO UPDATE SET read_status = TRUE`,
+			profileID, messageID)
+		if err != nil {
+			return e.Wrap("failed to mark as read", err)
+		}
+		return nil
+	}
+
+	return domain.ErrInvalidBatch
+}
+
+// SetFlags меняет флаги письма пользователя. Незаданные флаги сохраняют текущее значение
+func (repo *MessageRepository) SetFlags(ctx context.Context, profileID, messageID int64, update domain.FlagsUpdate) error {
+	const op = "storage.postgresql.message.SetFlags"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        INSERT INTO profile_message (profile_id, message_id, read_status, starred, important, answered, forwarded)
+        SELECT $1, $2,
+            COALESCE($3::boolean, FALSE), COALESCE($4::boolean, FALSE), COALESCE($5::boolean, FALSE),
+            COALESCE($6::boolean, FALSE), COALESCE($7::boolean, FALSE)
+        WHERE EXISTS (
+            SELECT 1 FROM folder_profile_message fpm
+            JOIN folder f ON fpm.folder_id = f.id
+            WHERE fpm.message_id = $2 AND f.profile_id = $1
+        )
+        ON CONFLICT (profile_id, message_id)
+        DO UPDATE SET
+            read_status = COALESCE($3::boolean, profile_message.read_status),
+            starred = COALESCE($4::boolean, profile_message.starred),
+            important = COALESCE($5::boolean, profile_message.important),
+            answered = COALESCE($6::boolean, profile_message.answered),
+            forwarded = COALESCE($7::boolean, profile_message.forwarded)`
+
+	log.Debug("Setting message flags...")
+	result, err := repo.db.ExecContext(ctx, query, profileID, messageID,
+		nullBool(update.Read), nullBool(update.Starred), nullBool(update.Important),
+		nullBool(update.Answered), nullBool(update.Forwarded))
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+	if rows == 0 {
+		return domain.ErrMessageNotFound
+	}
+
+	return nil
+}
+
+func nullBool(value *bool) sql.NullBool {
+	if value == nil {
+		return sql.NullBool{}
+	}
+	return sql.NullBool{Bool: *value, Valid: true}
+}
+
+// GetStarredMessagesWithKeysetPagination отдает помеченные письма пользователя из всех папок, кроме корзины и спама
+func (repo *MessageRepository) GetStarredMessagesWithKeysetPagination(
+	ctx context.Context,
+	profileID int64,
+	page domain.MessagePage,
+) ([]domain.Message, error) {
+	const op = "storage.postgresql.message.GetStarredMessagesWithKeysetPagination"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	query, args := buildMessageListQuery(starredMessagesQuery, []any{profileID}, page)
+
+	log.Debug("Querying starred messages with pagination...")
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	messages, err := scanMessageList(rows)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	if page.Backward {
+		slices.Reverse(messages)
+	}
+	return messages, nil
+}
+
+// starredMessagesQuery - помеченные письма пользователя $1 вне корзины и спама
+const starredMessagesQuery = `
+        SELECT
+            m.id, m.topic, m.text, m.date_of_dispatch, m.size,
+            pm.read_status, pm.starred, pm.important, pm.answered, pm.forwarded,
+            ` + messageLabelsColumn + `,
+            COALESCE(bp.id, 0), ` + senderAddressColumns + `,
+            sender_profile.name, sender_profile.surname, sender_profile.image_path,
+            ` + senderContactNameColumn + `
+        FROM
+            profile_message pm
+        JOIN
+            message m ON m.id = pm.message_id
+        LEFT JOIN
+            base_profile bp ON m.sender_base_profile_id = bp.id
+        LEFT JOIN
+            profile sender_profile ON bp.id = sender_profile.base_profile_id
+        WHERE
+            pm.profile_id = $1 AND pm.starred
+            AND EXISTS (
+                SELECT 1 FROM folder_profile_message fpm
+                JOIN folder f ON fpm.folder_id = f.id
+                WHERE fpm.message_id = m.id AND f.profile_id = $1
+                AND f.folder_type NOT IN ('trash', 'spam')
+            )`
+
+func (repo *MessageRepository) GetStarredMessagesInfo(ctx context.Context, profileID int64) (domain.Messages, error) {
+	const op = "storage.postgresql.message.GetStarredMessagesInfo"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        SELECT
+            COUNT(*) as total_count,
+            COUNT(CASE WHEN pm.read_status = false THEN 1 END) as unread_count
+        FROM profile_message pm
+        WHERE pm.profile_id = $1 AND pm.starred
+        AND EXISTS (
+            SELECT 1 FROM folder_profile_message fpm
+            JOIN folder f ON fpm.folder_id = f.id
+            WHERE fpm.message_id = pm.message_id AND f.profile_id = $1
+            AND f.folder_type NOT IN ('trash', 'spam')
+        )`
+
+	var messagesInfo domain.Messages
+	log.Debug("Getting starred messages info...")
+	err := repo.db.QueryRowContext(ctx, query, profileID).Scan(
+		&messagesInfo.MessageTotal, &messagesInfo.MessageUnread)
+	if err != nil {
+		return domain.Messages{}, e.Wrap(op, err)
+	}
+
+	return messagesInfo, nil
+}
+
+// ResolveRecipient ищет локального пользователя по адресу без учета регистра
+// и возвращает адрес в том виде, в котором он хранится в base_profile
+func (repo *MessageRepository) ResolveRecipient(ctx context.Context, email string) (string, error) {
+	const op = "storage.postgresql.message.ResolveRecipient"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	parts := strings.SplitN(email, "@", 2)
+	if len(parts) != 2 {
+		return "", domain.ErrRecipientNotFound
+	}
+
+	// Письмо на дополнительный адрес доставляется на основной адрес профиля
+	const query = `
+        SELECT bp.username, bp.domain
+        FROM base_profile bp
+        JOIN profile p ON p.base_profile_id = bp.id
+        WHERE LOWER(bp.username) = LOWER($1) AND LOWER(bp.domain) = LOWER($2)
+        UNION ALL
+        SELECT bp.username, bp.domain
+        FROM profile_alias pa
+        JOIN profile p ON p.id = pa.profile_id
+        JOIN base_profile bp ON bp.id = p.base_profile_id
+        WHERE LOWER(pa.username) = LOWER($1) AND LOWER(pa.domain) = LOWER($2)
+        LIMIT 1`
+
+	var username, domainName string
+	log.Debug("Resolving recipient...")
+	err := repo.db.QueryRowContext(ctx, query, parts[0], parts[1]).Scan(&username, &domainName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", domain.ErrRecipientNotFound
+	}
+	if err != nil {
+		return "", e.Wrap(op, err)
+	}
+
+	return username + "@" + domainName, nil
+}
+
+// FindRecipients возвращает адреса получателей письма: локальных пользователей, кроме отправителя,
+// и внешних адресатов из очереди исходящей почты
+func (repo *MessageRepository) FindRecipients(ctx context.Context, messageID int64) ([]string, error) {
+	const op = "storage.postgresql.message.FindRecipients"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        SELECT bp.username || '@' || bp.domain
+        FROM profile_message pm
+        JOIN profile p ON p.id = pm.profile_id
+        JOIN base_profile bp ON bp.id = p.base_profile_id
+        JOIN message m ON m.id = pm.message_id
+        WHERE pm.message_id = $1 AND p.base_profile_id IS DISTINCT FROM m.sender_base_profile_id
+        UNION
+        SELECT om.recipient
+        FROM outbound_message om
+        WHERE om.message_id = $1
+        ORDER BY 1`
+
+	log.Debug("Querying message recipients...")
+	rows, err := repo.db.QueryContext(ctx, query, messageID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	var recipients []string
+	for rows.Next() {
+		var recipient string
+		if err := rows.Scan(&recipient); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		recipients = append(recipients, recipient)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return recipients, nil
+}
+
+// SaveInboundMessage сохраняет письмо внешнего отправителя во входящие получателя.
+// Отправитель заводится в base_profile без профиля, вложения уже загружены в хранилище
+func (repo *MessageRepository) SaveInboundMessage(ctx context.Context, receiverEmail string, msg domain.InboundMessage, files []domain.File) (messageID int64, err error) {
+	const op = "storage.postgresql.message.SaveInboundMessage"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	sender := strings.SplitN(msg.Sender(), "@", 2)
+	receiver := strings.SplitN(receiverEmail, "@", 2)
+	if len(sender) != 2 || len(receiver) != 2 {
+		return 0, e.Wrap(op, fmt.Errorf("invalid address: sender %q, receiver %q", msg.Sender(), receiverEmail))
+	}
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, e.Wrap(op+": failed to begin transaction: ", err)
+	}
+	defer tx.Rollback()
+
+	// Внешний отправитель хранится адресом в самом письме: base_profile есть только
+	// у пользователей сервиса, их идентификаторы совпадают с идентификаторами profile
+	const insertMessage = `
+        INSERT INTO message (
+            topic, text, date_of_dispatch, sender_username, sender_domain, dkim_result, dkim_domain,
+            spf_result, spf_domain, dmarc_result, dmarc_policy, dmarc_aligned, authentication_results
+        )
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+        RETURNING id`
+
+	log.Debug("Inserting message...")
+	// Письмо без темы хранится с topic = NULL: пустая тема не проходит CHECK
+	topic := sql.NullString{String: msg.Topic, Valid: msg.Topic != ""}
+	dkimResult := sql.NullString{String: string(msg.DKIM.Status), Valid: msg.DKIM.Status != ""}
+	dkimDomain := sql.NullString{String: msg.DKIM.Domain, Valid: msg.DKIM.Domain != ""}
+	spfResult := sql.NullString{String: string(msg.SPF.Status), Valid: msg.SPF.Status != ""}
+	spfDomain := sql.NullString{String: msg.SPF.Domain, Valid: msg.SPF.Domain != ""}
+	dmarcResult := sql.NullString{String: string(msg.DMARC.Status), Valid: msg.DMARC.Status != ""}
+	dmarcPolicy := sql.NullString{String: string(msg.DMARC.Policy), Valid: msg.DMARC.Policy != ""}
+	dmarcAligned := sql.NullBool{Bool: msg.DMARC.Aligned, Valid: msg.DMARC.Status != ""}
+	authenticationResults := sql.NullString{String: msg.AuthenticationResults, Valid: msg.AuthenticationResults != ""}
+	err = tx.QueryRowContext(ctx, insertMessage, topic, msg.Text, msg.Datetime, sender[0], sender[1],
+		dkimResult, dkimDomain, spfResult, spfDomain, dmarcResult, dmarcPolicy, dmarcAligned, authenticationResults,
+	).Scan(&messageID)
+	if err != nil {
+		return 0, e.Wrap(op+": failed to insert message: ", err)
+	}
+
+	var receiverProfileID int64
+	log.Debug("Getting receiver profile ID...")
+	err = tx.QueryRowContext(ctx, `
+        SELECT p.id
+        FROM profile p
+        JOIN base_profile bp ON p.base_profile_id = bp.id
+        WHERE bp.username = $1 AND bp.domain = $2`,
+		receiver[0], receiver[1]).Scan(&receiverProfileID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, domain.ErrRecipientNotFound
+	}
+	if err != nil {
+		return 0, e.Wrap(op+": failed to get receiver id: ", err)
+	}
+
+	const insertToInbox = `
+        INSERT INTO folder_profile_message (message_id, folder_id)
+        SELECT $1, f.id
+        FROM folder f
+        WHERE f.profile_id = $2 AND f.folder_type = 'inbox'`
+
+	log.Debug("Adding to receiver's inbox...")
+	_, err = tx.ExecContext(ctx, insertToInbox, messageID, receiverProfileID)
+	if err != nil {
+		return 0, e.Wrap(op+": failed to insert to inbox: ", err)
+	}
+
+	const insertProfileMessage = `
+        INSERT INTO profile_message (profile_id, message_id, read_status)
+        VALUES ($1, $2, false)`
+
+	log.Debug("Creating profile message bond...")
+	_, err = tx.ExecContext(ctx, insertProfileMessage, receiverProfileID, messageID)
+	if err != nil {
+		return 0, e.Wrap(op+": failed to insert profile message: ", err)
+	}
+
+	const insertFile = `
+        INSERT INTO file (file_type, size, storage_path, message_id)
+        VALUES ($1, $2, $3, $4)`
+
+	for _, file := range files {
+		log.Debug("Inserting file...")
+		_, err = tx.ExecContext(ctx, insertFile, file.FileType, file.Size, file.StoragePath, messageID)
+		if err != nil {
+			return 0, e.Wrap(op+": failed to insert file: ", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, e.Wrap(op+": failed to commit transaction: ", err)
+	}
+
+	return messageID, nil
+}
+
+// SaveOutboundMessage сохраняет письмо на внешний адрес в отправленные и ставит его
+// в очередь исходящей почты. threadRoot = 0 - письмо начинает новый тред.
+// Письмо уйдет не раньше notBefore, forwardHops > 0 - письмо автоматически пересылается
+func (repo *MessageRepository) SaveOutboundMessage(
+	ctx context.Context,
+	receiverEmail string,
+	senderBaseProfileID int64,
+	threadRoot int64,
+	topic, text string,
+	notBefore time.Time,
+	forwardHops int,
+) (messageID int64, err error) {
+	const op = "storage.postgresql.message.SaveOutboundMessage"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, e.Wrap(op+": failed to begin transaction: ", err)
+	}
+	defer tx.Rollback()
+
+	var senderProfileID int64
+	log.Debug("Getting sender profile ID...")
+	err = tx.QueryRowContext(ctx, `
+        SELECT id FROM profile WHERE base_profile_id = $1`,
+		senderBaseProfileID).Scan(&senderProfileID)
+	if err != nil {
+		return 0, e.Wrap(op+": failed to get sender profile id: ", err)
+	}
+
+	const insertMessage = `
+        INSERT INTO message (topic, text, date_of_dispatch, sender_base_profile_id, thread_id)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id`
+
+	thread := sql.NullInt64{Int64: threadRoot, Valid: threadRoot != 0}
+	log.Debug("Inserting message...")
+	err = tx.QueryRowContext(ctx, insertMessage, topic, text, time.Now(), senderBaseProfileID, thread).Scan(&messageID)
+	if err != nil {
+		return 0, e.Wrap(op+": failed to insert message: ", err)
+	}
+
+	const insertToSent = `
+        INSERT INTO folder_profile_message (message_id, folder_id)
+        SELECT $1, f.id
+        FROM folder f
+        WHERE f.profile_id = $2 AND f.folder_type = 'sent'`
+
+	log.Debug("Adding to sender's sent folder...")
+	_, err = tx.ExecContext(ctx, insertToSent, messageID, senderProfileID)
+	if err != nil {
+		return 0, e.Wrap(op+": failed to insert to sent: ", err)
+	}
+
+	const insertProfileMessage = `
+        INSERT INTO profile_message (profile_id, message_id, read_status)
+        VALUES ($1, $2, false)
+        ON CONFLICT (profile_id, message_id) DO NOTHING`
+
+	log.Debug("Creating profile message bond for sender...")
+	_, err = tx.ExecContext(ctx, insertProfileMessage, senderProfileID, messageID)
+	if err != nil {
+		return 0, e.Wrap(op+": failed to insert profile message: ", err)
+	}
+
+	const insertOutbound = `
+        INSERT INTO outbound_message (message_id, sender_profile_id, recipient, next_attempt_at, forward_hops)
+        VALUES ($1, $2, $3, $4, $5)`
+
+	log.Debug("Queueing outbound message...")
+	_, err = tx.ExecContext(ctx, insertOutbound, messageID, senderProfileID, receiverEmail, notBefore, forwardHops)
+	if err != nil {
+		return 0, e.Wrap(op+": failed to queue message: ", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, e.Wrap(op+": failed to commit transaction: ", err)
+	}
+
+	return messageID, nil
+}
+
+// FindSenderAddresses возвращает адреса, от имени которых профиль может отправлять
+// письма: основной адрес и дополнительные
+func (repo *MessageRepository) FindSenderAddresses(ctx context.Context, profileID int64) ([]string, error) {
+	const op = "storage.postgresql.message.FindSenderAddresses"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        SELECT bp.username || '@' || bp.domain
+        FROM profile p
+        JOIN base_profile bp ON bp.id = p.base_profile_id
+        WHERE p.base_profile_id = $1
+        UNION ALL
+        SELECT pa.username || '@' || pa.domain
+        FROM profile_alias pa
+        JOIN profile p ON p.id = pa.profile_id
+        WHERE p.base_profile_id = $1`
+
+	log.Debug("Querying sender addresses...")
+	rows, err := repo.db.QueryContext(ctx, query, profileID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	var addresses []string
+	for rows.Next() {
+		var address string
+		if err := rows.Scan(&address); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		addresses = append(addresses, address)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return addresses, nil
+}
+
+// SetSenderAddress запоминает в письме дополнительный адрес, с которого оно отправлено.
+// Для основного адреса пользователя ничего не меняется: он берется из base_profile
+func (repo *MessageRepository) SetSenderAddress(ctx context.Context, messageID int64, address string) error {
+	const op = "storage.postgresql.message.SetSenderAddress"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	username, domainName, ok := strings.Cut(address, "@")
+	if !ok {
+		return e.Wrap(op, domain.ErrInvalidSender)
+	}
+
+	const query = `
+        UPDATE message m
+        SET sender_username = $2, sender_domain = $3
+        FROM base_profile bp
+        WHERE m.id = $1 AND bp.id = m.sender_base_profile_id
+          AND NOT (LOWER(bp.username) = LOWER($2) AND LOWER(bp.domain) = LOWER($3))`
+
+	log.Debug("Updating message sender address...")
+	if _, err := repo.db.ExecContext(ctx, query, messageID, username, domainName); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// SaveSubmission запоминает Message-Id письма, отправленного из почтового клиента.
+// Повторная отправка с тем же Message-Id указывает на новую копию
+func (repo *MessageRepository) SaveSubmission(ctx context.Context, profileID int64, messageIDHeader string, messageID int64) error {
+	const op = "storage.postgresql.message.SaveSubmission"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        INSERT INTO submitted_message (profile_id, message_id_header, message_id)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (profile_id, message_id_header)
+        DO UPDATE SET message_id = EXCLUDED.message_id, submitted_at = CURRENT_TIMESTAMP`
+
+	log.Debug("Saving submission...")
+	_, err := repo.db.ExecContext(ctx, query, profileID, messageIDHeader, messageID)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// FindSubmission возвращает копию в "Отправленных" письма, отправленного с этим Message-Id
+// не раньше since. Письма нет - domain.ErrMessageNotFound
+func (repo *MessageRepository) FindSubmission(ctx context.Context, profileID int64, messageIDHeader string, since time.Time) (int64, error) {
+	const op = "storage.postgresql.message.FindSubmission"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        SELECT sm.message_id
+        FROM submitted_message sm
+        WHERE sm.profile_id = $1 AND sm.message_id_header = $2 AND sm.submitted_at >= $3`
+
+	var messageID int64
+	log.Debug("Querying submission...")
+	err := repo.db.QueryRowContext(ctx, query, profileID, messageIDHeader, since).Scan(&messageID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, domain.ErrMessageNotFound
+	}
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	return messageID, nil
+}
@@ -4,6 +4,7 @@ import (
 	"2025_2_a4code/internal/domain"
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -15,10 +16,20 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+// arrayConverter пропускает срезы идентификаторов, которые pgx передает как int[]
+type arrayConverter struct{}
+
+func (arrayConverter) ConvertValue(v interface{}) (driver.Value, error) {
+	if ids, ok := v.([]int64); ok {
+		return ids, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(v)
+}
+
 func setupTest(t *testing.T) (context.Context, *MessageRepository, sqlmock.Sqlmock) {
 	t.Helper()
 
-	db, mock, err := sqlmock.New()
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(arrayConverter{}))
 	assert.NoError(t, err)
 	t.Cleanup(func() { db.Close() })
 
@@ -41,12 +52,12 @@ func TestMessageRepository_FindByMessageID(t *testing.T) {
 	const query = `
         SELECT
             m.id, m.topic, m.text, m.date_of_dispatch,
-            bp.id, bp.username, bp.domain,
+            COALESCE(bp.id, 0), COALESCE(m.sender_username, bp.username), COALESCE(m.sender_domain, bp.domain),
             p.name, p.surname, p.image_path,
             pm.read_status
         FROM
             message m
-        JOIN
+        LEFT JOIN
             base_profile bp ON m.sender_base_profile_id = bp.id
         LEFT JOIN
             profile p ON bp.id = p.base_profile_id
@@ -91,13 +102,20 @@ func TestMessageRepository_FindFullByMessageID(t *testing.T) {
 	const messageQuery = `
         SELECT
             m.id, m.topic, m.text, m.date_of_dispatch,
-            bp.id, bp.username, bp.domain,
+            COALESCE(bp.id, 0), COALESCE(m.sender_username, bp.username), COALESCE(m.sender_domain, bp.domain),
             p.name, p.surname, p.image_path,
             t.id, t.root_message_id,
-            f.id, f.profile_id, f.folder_name, f.folder_type
+            f.id, f.profile_id, f.folder_name, f.folder_type,
+            pm.spam_score, pm.spam_reasons,
+            m.dkim_result, m.dkim_domain,
+            m.spf_result, m.spf_domain, m.dmarc_result, m.dmarc_policy, m.dmarc_aligned,
+            m.authentication_results,
+            m.size, COALESCE(pm.read_status, FALSE),
+            COALESCE(pm.starred, FALSE), COALESCE(pm.important, FALSE),
+            COALESCE(pm.answered, FALSE), COALESCE(pm.forwarded, FALSE)
         FROM
             message m
-        JOIN
+        LEFT JOIN
             base_profile bp ON m.sender_base_profile_id = bp.id
         LEFT JOIN
             profile p ON bp.id = p.base_profile_id
@@ -107,6 +125,8 @@ func TestMessageRepository_FindFullByMessageID(t *testing.T) {
             folder_profile_message fpm ON m.id = fpm.message_id
         LEFT JOIN
             folder f ON fpm.folder_id = f.id AND f.profile_id = $2
+        LEFT JOIN
+            profile_message pm ON pm.message_id = m.id AND pm.profile_id = $2
         WHERE
             m.id = $1
         LIMIT 1`
@@ -119,6 +139,13 @@ func TestMessageRepository_FindFullByMessageID(t *testing.T) {
 		"p.name", "p.surname", "p.image_path",
 		"t.id", "t.root_message_id",
 		"f.id", "f.profile_id", "f.folder_name", "f.folder_type",
+		"pm.spam_score", "pm.spam_reasons",
+		"m.dkim_result", "m.dkim_domain",
+		"m.spf_result", "m.spf_domain", "m.dmarc_result", "m.dmarc_policy", "m.dmarc_aligned",
+		"m.authentication_results",
+		"m.size", "pm.read_status",
+		"pm.starred", "pm.important",
+		"pm.answered", "pm.forwarded",
 	}).AddRow(
 		mockMessageID, "Full Topic", "Full text", mockTime,
 		int64(2), "sender", "example.com",
@@ -126,6 +153,14 @@ func TestMessageRepository_FindFullByMessageID(t *testing.T) {
 		sql.NullInt64{Int64: 5, Valid: true}, sql.NullInt64{Int64: 1, Valid: true}, // thread
 		sql.NullInt64{Int64: 100, Valid: true}, sql.NullInt64{Int64: mockProfileID, Valid: true}, // folder
 		sql.NullString{String: "Inbox", Valid: true}, sql.NullString{String: "inbox", Valid: true},
+		sql.NullFloat64{Float64: 0.12, Valid: true}, sql.NullString{String: "\"meeting\": 0.01\n\"report\": 0.02", Valid: true}, // spam
+		sql.NullString{String: "pass", Valid: true}, sql.NullString{String: "example.com", Valid: true}, // dkim
+		sql.NullString{String: "softfail", Valid: true}, sql.NullString{String: "bounce.example.com", Valid: true}, // spf
+		sql.NullString{String: "pass", Valid: true}, sql.NullString{String: "reject", Valid: true}, sql.NullBool{Bool: true, Valid: true}, // dmarc
+		sql.NullString{String: "mx.flintmail.ru; dkim=pass header.d=example.com", Valid: true},
+		int64(3072), true,
+		true, false,
+		false, false,
 	)
 
 	mock.ExpectQuery(quote(messageQuery)).
@@ -145,6 +180,14 @@ func TestMessageRepository_FindFullByMessageID(t *testing.T) {
 		WithArgs(mockMessageID).
 		WillReturnRows(filesRows)
 
+	mock.ExpectQuery(quote(`SELECT fpm.folder_id`)).
+		WithArgs(mockMessageID, mockProfileID).
+		WillReturnRows(sqlmock.NewRows([]string{"folder_id"}).AddRow(int64(100)).AddRow(int64(104)))
+
+	mock.ExpectQuery(quote(`SELECT bp.username || '@' || bp.domain`)).
+		WithArgs(mockMessageID).
+		WillReturnRows(sqlmock.NewRows([]string{"email"}).AddRow("receiver@example.com"))
+
 	mock.ExpectCommit()
 
 	msg, err := repo.FindFullByMessageID(ctx, mockMessageID, mockProfileID)
@@ -155,9 +198,21 @@ func TestMessageRepository_FindFullByMessageID(t *testing.T) {
 	assert.Equal(t, "Sender User", msg.Sender.Username)
 	assert.Equal(t, "5", msg.ThreadRoot)
 	assert.Equal(t, "Inbox", msg.Folder.Name)
+	assert.Equal(t, 0.12, msg.SpamScore)
+	assert.Equal(t, []string{`"meeting": 0.01`, `"report": 0.02`}, msg.SpamReasons)
+	assert.Equal(t, domain.DKIMResult{Status: domain.DKIMPass, Domain: "example.com"}, msg.DKIM)
+	assert.Equal(t, domain.SPFResult{Status: domain.SPFSoftFail, Domain: "bounce.example.com"}, msg.SPF)
+	assert.Equal(t, domain.DMARCResult{Status: domain.DMARCPass, Policy: domain.DMARCPolicyReject, Aligned: true}, msg.DMARC)
+	assert.Equal(t, "mx.flintmail.ru; dkim=pass header.d=example.com", msg.AuthenticationResults)
+	assert.False(t, msg.UnverifiedSender())
 	assert.Len(t, msg.Files, 2)
 	assert.Equal(t, "image/png", msg.Files[0].FileType)
 	assert.Equal(t, "path/to/file2.pdf", msg.Files[1].StoragePath)
+	assert.Equal(t, int64(3072), msg.Size)
+	assert.True(t, msg.IsRead)
+	assert.Equal(t, domain.MessageFlags{IsStarred: true}, msg.MessageFlags)
+	assert.Equal(t, []int64{100, 104}, msg.FolderIDs)
+	assert.Equal(t, []string{"receiver@example.com"}, msg.Receivers)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -259,6 +314,20 @@ func TestMessageRepository_SaveFile(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestMessageRepository_CopyFiles(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+
+	mock.ExpectExec(`INSERT INTO file \(file_type, size, storage_path, message_id\) SELECT file_type, size, storage_path, \$2 FROM file WHERE message_id = \$1`).
+		WithArgs(int64(10), int64(20)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	copied, err := repo.CopyFiles(ctx, 10, 20)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), copied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestMessageRepository_SaveThread(t *testing.T) {
 	ctx, repo, mock := setupTest(t)
 	expectedThreadID := int64(1)
@@ -383,6 +452,64 @@ func TestMessageRepository_MarkMessageAsSpam(t *testing.T) {
 	})
 }
 
+func TestMessageRepository_MarkMessageAsNotSpam(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	messageID := int64(123)
+	profileID := int64(1)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+
+		mock.ExpectQuery(`SELECT id FROM folder WHERE profile_id = \$1 AND folder_type = 'inbox'`).
+			WithArgs(profileID).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)))
+
+		mock.ExpectExec(`DELETE FROM folder_profile_message`).
+			WithArgs(messageID, profileID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		mock.ExpectExec(`INSERT INTO folder_profile_message`).
+			WithArgs(messageID, int64(1)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectCommit()
+
+		err := repo.MarkMessageAsNotSpam(ctx, messageID, profileID)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotInSpam", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT id FROM folder WHERE profile_id = \$1 AND folder_type = 'inbox'`).
+			WithArgs(profileID).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)))
+		mock.ExpectExec(`DELETE FROM folder_profile_message`).
+			WithArgs(messageID, profileID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.MarkMessageAsNotSpam(ctx, messageID, profileID)
+
+		assert.ErrorIs(t, err, domain.ErrMessageNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ErrorOnGetInboxFolder", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT id FROM folder WHERE profile_id = \$1 AND folder_type = 'inbox'`).
+			WithArgs(profileID).
+			WillReturnError(fmt.Errorf("db error"))
+		mock.ExpectRollback()
+
+		err := repo.MarkMessageAsNotSpam(ctx, messageID, profileID)
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestMessageRepository_SaveDraft(t *testing.T) {
 	ctx, repo, mock := setupTest(t)
 	profileID := int64(1)
@@ -585,16 +712,36 @@ func TestMessageRepository_GetDraft(t *testing.T) {
 				"p.name", "p.surname", "p.image_path",
 				"t.id", "t.root_message_id",
 				"f.id", "f.profile_id", "f.folder_name", "f.folder_type",
+				"pm.spam_score", "pm.spam_reasons",
+				"m.dkim_result", "m.dkim_domain",
+				"m.spf_result", "m.spf_domain", "m.dmarc_result", "m.dmarc_policy", "m.dmarc_aligned",
+				"m.authentication_results",
+				"m.size", "pm.read_status",
+				"pm.starred", "pm.important",
+				"pm.answered", "pm.forwarded",
 			}).AddRow(
 				draftID, "Topic", "Text", time.Now(),
 				int64(2), "user", "domain.com",
 				sql.NullString{}, sql.NullString{}, sql.NullString{},
 				sql.NullInt64{}, sql.NullInt64{},
 				sql.NullInt64{}, sql.NullInt64{}, sql.NullString{}, sql.NullString{},
+				sql.NullFloat64{}, sql.NullString{},
+				sql.NullString{}, sql.NullString{},
+				sql.NullString{}, sql.NullString{}, sql.NullString{}, sql.NullString{}, sql.NullBool{},
+				sql.NullString{},
+				int64(0), true,
+				false, false,
+				false, false,
 			))
 		mock.ExpectQuery(`SELECT id, file_type, size, storage_path, message_id FROM file`).
 			WithArgs(draftID).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "file_type", "size", "storage_path", "message_id"}))
+		mock.ExpectQuery(`SELECT fpm.folder_id`).
+			WithArgs(draftID, profileID).
+			WillReturnRows(sqlmock.NewRows([]string{"folder_id"}).AddRow(int64(40)))
+		mock.ExpectQuery(`FROM profile_message pm`).
+			WithArgs(draftID).
+			WillReturnRows(sqlmock.NewRows([]string{"email"}))
 		mock.ExpectCommit()
 
 		draft, err := repo.GetDraft(ctx, draftID, profileID)
@@ -614,12 +761,39 @@ func TestMessageRepository_MoveToFolder(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mock.ExpectBegin()
 
+		mock.ExpectQuery(`SELECT fpm.folder_id FROM folder_profile_message fpm`).
+			WithArgs(messageID, profileID).
+			WillReturnRows(sqlmock.NewRows([]string{"folder_id"}).AddRow(int64(1)))
+
 		mock.ExpectExec(`DELETE FROM folder_profile_message`).
 			WithArgs(messageID, profileID).
 			WillReturnResult(sqlmock.NewResult(0, 2))
 
 		mock.ExpectExec(`INSERT INTO folder_profile_message`).
-			WithArgs(messageID, folderID).
+			WithArgs(messageID, folderID, sql.NullInt64{Int64: 1, Valid: true}).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		mock.ExpectCommit()
+
+		err := repo.MoveToFolder(ctx, profileID, messageID, folderID)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NoOriginalFolder", func(t *testing.T) {
+		mock.ExpectBegin()
+
+		mock.ExpectQuery(`SELECT fpm.folder_id FROM folder_profile_message fpm`).
+			WithArgs(messageID, profileID).
+			WillReturnError(sql.ErrNoRows)
+
+		mock.ExpectExec(`DELETE FROM folder_profile_message`).
+			WithArgs(messageID, profileID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		mock.ExpectExec(`INSERT INTO folder_profile_message`).
+			WithArgs(messageID, folderID, sql.NullInt64{}).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		mock.ExpectCommit()
@@ -709,7 +883,7 @@ func TestMessageRepository_CreateFolder(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		mock.ExpectQuery(`SELECT 1 FROM folder`).
-			WithArgs(profileID, folderName, int64(0)).
+			WithArgs(profileID, folderName, int64(0), int64(0)).
 			WillReturnError(sql.ErrNoRows)
 
 		mock.ExpectQuery(`INSERT INTO folder`).
@@ -728,7 +902,7 @@ func TestMessageRepository_CreateFolder(t *testing.T) {
 
 	t.Run("FolderAlreadyExists", func(t *testing.T) {
 		mock.ExpectQuery(`SELECT 1 FROM folder`).
-			WithArgs(profileID, folderName, int64(0)).
+			WithArgs(profileID, folderName, int64(0), int64(0)).
 			WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
 
 		folder, err := repo.CreateFolder(ctx, profileID, folderName)
@@ -744,21 +918,25 @@ func TestMessageRepository_GetUserFolders(t *testing.T) {
 	profileID := int64(1)
 
 	t.Run("Success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "folder_name", "folder_type"}).
-			AddRow(int64(1), "Inbox", "inbox").
-			AddRow(int64(2), "Sent", "sent").
-			AddRow(int64(3), "Custom Folder", "custom")
+		rows := sqlmock.NewRows([]string{"id", "folder_name", "folder_type", "parent_id", "message_total", "message_unread"}).
+			AddRow(int64(1), "Inbox", "inbox", int64(0), 12, 3).
+			AddRow(int64(2), "Sent", "sent", int64(0), 5, 0).
+			AddRow(int64(3), "Custom Folder", "custom", int64(0), 0, 0).
+			AddRow(int64(4), "Nested Folder", "custom", int64(3), 2, 1)
 
-		mock.ExpectQuery(`SELECT id, folder_name, folder_type FROM folder`).
+		mock.ExpectQuery(`SELECT id, folder_name, folder_type, COALESCE\(parent_id, 0\), message_total, message_unread FROM folder`).
 			WithArgs(profileID).
 			WillReturnRows(rows)
 
 		folders, err := repo.GetUserFolders(ctx, profileID)
 
 		assert.NoError(t, err)
-		assert.Len(t, folders, 3)
+		assert.Len(t, folders, 4)
 		assert.Equal(t, "Inbox", folders[0].Name)
 		assert.Equal(t, "Custom Folder", folders[2].Name)
+		assert.Equal(t, int64(3), folders[3].ParentID)
+		assert.Equal(t, 12, folders[0].MessageTotal)
+		assert.Equal(t, 3, folders[0].MessageUnread)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
@@ -770,8 +948,12 @@ func TestMessageRepository_RenameFolder(t *testing.T) {
 	newName := "Renamed Folder"
 
 	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT COALESCE\(parent_id, 0\) FROM folder`).
+			WithArgs(folderID, profileID).
+			WillReturnRows(sqlmock.NewRows([]string{"parent_id"}).AddRow(int64(3)))
+
 		mock.ExpectQuery(`SELECT 1 FROM folder`).
-			WithArgs(profileID, newName, folderID).
+			WithArgs(profileID, newName, folderID, int64(3)).
 			WillReturnError(sql.ErrNoRows)
 
 		mock.ExpectQuery(`UPDATE folder`).
@@ -784,6 +966,178 @@ func TestMessageRepository_RenameFolder(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotNil(t, folder)
 		assert.Equal(t, newName, folder.Name)
+		assert.Equal(t, int64(3), folder.ParentID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("FolderNotFound", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT COALESCE\(parent_id, 0\) FROM folder`).
+			WithArgs(folderID, profileID).
+			WillReturnError(sql.ErrNoRows)
+
+		folder, err := repo.RenameFolder(ctx, profileID, folderID, newName)
+
+		assert.ErrorIs(t, err, domain.ErrFolderNotFound)
+		assert.Nil(t, folder)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMessageRepository_CreateSubfolder(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	profileID := int64(1)
+	parentID := int64(3)
+	folderName := "Invoices"
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectQuery(`WITH RECURSIVE ancestors`).
+			WithArgs(parentID, profileID).
+			WillReturnRows(sqlmock.NewRows([]string{"folder_type", "folder_name", "depth"}).
+				AddRow("custom", "Acme", 2))
+
+		mock.ExpectQuery(`SELECT 1 FROM folder`).
+			WithArgs(profileID, folderName, int64(0), parentID).
+			WillReturnError(sql.ErrNoRows)
+
+		mock.ExpectQuery(`INSERT INTO folder \(profile_id, folder_name, folder_type, parent_id\)`).
+			WithArgs(profileID, folderName, parentID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "folder_name", "folder_type"}).
+				AddRow(int64(7), folderName, "custom"))
+
+		folder, err := repo.CreateSubfolder(ctx, profileID, parentID, folderName)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), folder.ID)
+		assert.Equal(t, parentID, folder.ParentID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("SystemParent", func(t *testing.T) {
+		mock.ExpectQuery(`WITH RECURSIVE ancestors`).
+			WithArgs(parentID, profileID).
+			WillReturnRows(sqlmock.NewRows([]string{"folder_type", "folder_name", "depth"}).
+				AddRow("inbox", "Inbox", 1))
+
+		folder, err := repo.CreateSubfolder(ctx, profileID, parentID, folderName)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidFolderParent)
+		assert.Nil(t, folder)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("TooDeep", func(t *testing.T) {
+		mock.ExpectQuery(`WITH RECURSIVE ancestors`).
+			WithArgs(parentID, profileID).
+			WillReturnRows(sqlmock.NewRows([]string{"folder_type", "folder_name", "depth"}).
+				AddRow("custom", "Deep", domain.MaxFolderDepth))
+
+		folder, err := repo.CreateSubfolder(ctx, profileID, parentID, folderName)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidFolderParent)
+		assert.Nil(t, folder)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ParentNotFound", func(t *testing.T) {
+		mock.ExpectQuery(`WITH RECURSIVE ancestors`).
+			WithArgs(parentID, profileID).
+			WillReturnError(sql.ErrNoRows)
+
+		folder, err := repo.CreateSubfolder(ctx, profileID, parentID, folderName)
+
+		assert.ErrorIs(t, err, domain.ErrFolderNotFound)
+		assert.Nil(t, folder)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMessageRepository_MoveFolder(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	profileID := int64(1)
+	folderID := int64(5)
+	parentID := int64(3)
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`WITH RECURSIVE ancestors`).
+			WithArgs(folderID, profileID).
+			WillReturnRows(sqlmock.NewRows([]string{"folder_type", "folder_name", "depth"}).
+				AddRow("custom", "Acme", 1))
+		mock.ExpectQuery(`WITH RECURSIVE ancestors`).
+			WithArgs(parentID, profileID).
+			WillReturnRows(sqlmock.NewRows([]string{"folder_type", "folder_name", "depth"}).
+				AddRow("custom", "Clients", 1))
+		mock.ExpectQuery(`WITH RECURSIVE subtree`).
+			WithArgs(folderID, parentID).
+			WillReturnRows(sqlmock.NewRows([]string{"max", "bool_or"}).AddRow(2, false))
+		mock.ExpectQuery(`SELECT 1 FROM folder`).
+			WithArgs(profileID, "Acme", folderID, parentID).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(`UPDATE folder SET parent_id = NULLIF`).
+			WithArgs(parentID, sqlmock.AnyArg(), folderID, profileID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "folder_name", "folder_type"}).
+				AddRow(folderID, "Acme", "custom"))
+		mock.ExpectCommit()
+
+		folder, err := repo.MoveFolder(ctx, profileID, folderID, parentID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, parentID, folder.ParentID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`WITH RECURSIVE ancestors`).
+			WithArgs(folderID, profileID).
+			WillReturnRows(sqlmock.NewRows([]string{"folder_type", "folder_name", "depth"}).
+				AddRow("custom", "Acme", 1))
+		mock.ExpectQuery(`WITH RECURSIVE ancestors`).
+			WithArgs(parentID, profileID).
+			WillReturnRows(sqlmock.NewRows([]string{"folder_type", "folder_name", "depth"}).
+				AddRow("custom", "Invoices", 2))
+		mock.ExpectQuery(`WITH RECURSIVE subtree`).
+			WithArgs(folderID, parentID).
+			WillReturnRows(sqlmock.NewRows([]string{"max", "bool_or"}).AddRow(2, true))
+		mock.ExpectRollback()
+
+		folder, err := repo.MoveFolder(ctx, profileID, folderID, parentID)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidFolderParent)
+		assert.Nil(t, folder)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ToRootNameTaken", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`WITH RECURSIVE ancestors`).
+			WithArgs(folderID, profileID).
+			WillReturnRows(sqlmock.NewRows([]string{"folder_type", "folder_name", "depth"}).
+				AddRow("custom", "Acme", 2))
+		mock.ExpectQuery(`SELECT 1 FROM folder`).
+			WithArgs(profileID, "Acme", folderID, int64(0)).
+			WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+		mock.ExpectRollback()
+
+		folder, err := repo.MoveFolder(ctx, profileID, folderID, 0)
+
+		assert.ErrorIs(t, err, domain.ErrFolderExists)
+		assert.Nil(t, folder)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("SystemFolder", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`WITH RECURSIVE ancestors`).
+			WithArgs(folderID, profileID).
+			WillReturnRows(sqlmock.NewRows([]string{"folder_type", "folder_name", "depth"}).
+				AddRow("inbox", "Inbox", 1))
+		mock.ExpectRollback()
+
+		folder, err := repo.MoveFolder(ctx, profileID, folderID, parentID)
+
+		assert.ErrorIs(t, err, domain.ErrFolderSystem)
+		assert.Nil(t, folder)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
@@ -877,34 +1231,77 @@ func TestMessageRepository_GetFolderMessagesWithKeysetPagination(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		rows := sqlmock.NewRows([]string{
-			"m.id", "m.topic", "m.text", "m.date_of_dispatch", "pm.read_status",
+			"m.id", "m.topic", "m.text", "m.date_of_dispatch", "m.size", "pm.read_status",
+			"pm.starred", "pm.important", "pm.answered", "pm.forwarded", "labels",
 			"bp.id", "bp.username", "bp.domain",
-			"p.name", "p.surname", "p.image_path",
+			"p.name", "p.surname", "p.image_path", "contact_name",
 		}).
 			AddRow(
-				int64(101), "Topic 1", "Message text 1", time.Now(), false,
+				int64(101), "Topic 1", "Message text 1", time.Now(), int64(14), false,
+				true, false, false, false, []byte(`[{"id": 3, "name": "Work", "color": "#ff0000"}]`),
 				int64(2), "user1", "domain.com",
 				sql.NullString{String: "John", Valid: true}, sql.NullString{String: "Doe", Valid: true}, sql.NullString{String: "avatar1.jpg", Valid: true},
+				sql.NullString{},
 			).
 			AddRow(
-				int64(102), "Topic 2", "Message text 2", time.Now().Add(-30*time.Minute), true,
+				int64(102), "Topic 2", "Message text 2", time.Now().Add(-30*time.Minute), int64(14), true,
+				false, false, true, false, []byte(`[]`),
 				int64(3), "user2", "domain.com",
 				sql.NullString{String: "Jane", Valid: true}, sql.NullString{String: "Smith", Valid: true}, sql.NullString{String: "avatar2.jpg", Valid: true},
+				sql.NullString{String: "Janie from work", Valid: true},
 			)
 
-		mock.ExpectQuery(`SELECT`).
-			WithArgs(profileID, folderID, lastMessageID, lastDatetime.Unix(), limit).
+		mock.ExpectQuery(quote(`AND (m.date_of_dispatch, m.id) < ($4, $5)`)).
+			WithArgs(profileID, folderID, int64(0), lastDatetime, lastMessageID, limit).
 			WillReturnRows(rows)
 
-		messages, err := repo.GetFolderMessagesWithKeysetPagination(ctx, profileID, folderID, lastMessageID, lastDatetime, limit)
+		messages, err := repo.GetFolderMessagesWithKeysetPagination(ctx, profileID, folderID, 0, domain.MessagePage{
+			After: &domain.MessageKey{MessageID: lastMessageID, Datetime: lastDatetime},
+			Limit: limit,
+		})
 
 		assert.NoError(t, err)
 		assert.Len(t, messages, 2)
+		assert.Equal(t, []domain.Label{{ID: 3, Name: "Work", Color: "#ff0000"}}, messages[0].Labels)
+		assert.Empty(t, messages[1].Labels)
 		assert.Equal(t, "101", messages[0].ID)
 		assert.Equal(t, "John Doe", messages[0].Sender.Username)
+		assert.Equal(t, "Janie from work", messages[1].Sender.Username)
 		assert.Equal(t, "Message text 1...", messages[0].Snippet)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
+
+	t.Run("MessageWithTwoRecipients", func(t *testing.T) {
+		// Письмо 101 получили два пользователя: флаги берутся только из строки владельца папки,
+		// поэтому письмо попадает в выдачу один раз и фильтр по звезде учитывает только его отметку
+		rows := sqlmock.NewRows([]string{
+			"m.id", "m.topic", "m.text", "m.date_of_dispatch", "m.size", "pm.read_status",
+			"pm.starred", "pm.important", "pm.answered", "pm.forwarded", "labels",
+			"bp.id", "bp.username", "bp.domain",
+			"p.name", "p.surname", "p.image_path", "contact_name",
+		}).
+			AddRow(
+				int64(101), "Topic 1", "Message text 1", time.Now(), int64(14), true,
+				true, false, false, false, []byte(`[]`),
+				int64(2), "user1", "domain.com",
+				sql.NullString{}, sql.NullString{}, sql.NullString{}, sql.NullString{},
+			)
+
+		mock.ExpectQuery(quote(`profile_message pm ON m.id = pm.message_id AND pm.profile_id = f.profile_id`)+`.*`+quote(`AND pm.starred`)).
+			WithArgs(profileID, folderID, int64(0), limit).
+			WillReturnRows(rows)
+
+		messages, err := repo.GetFolderMessagesWithKeysetPagination(ctx, profileID, folderID, 0, domain.MessagePage{
+			Filter: domain.MessageFilter{StarredOnly: true},
+			Limit:  limit,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, messages, 1)
+		assert.True(t, messages[0].IsStarred)
+		assert.True(t, messages[0].IsRead)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 }
 
 func TestMessageRepository_GetFolderMessagesInfo(t *testing.T) {
@@ -913,18 +1310,43 @@ func TestMessageRepository_GetFolderMessagesInfo(t *testing.T) {
 	folderID := int64(5)
 
 	t.Run("Success", func(t *testing.T) {
-		mock.ExpectQuery(`SELECT`).
-			WithArgs(profileID, folderID).
-			WillReturnRows(sqlmock.NewRows([]string{"total_count", "unread_count"}).
+		mock.ExpectQuery(`SELECT message_total, message_unread FROM folder`).
+			WithArgs(folderID, profileID).
+			WillReturnRows(sqlmock.NewRows([]string{"message_total", "message_unread"}).
 				AddRow(100, 25))
 
-		info, err := repo.GetFolderMessagesInfo(ctx, profileID, folderID)
+		info, err := repo.GetFolderMessagesInfo(ctx, profileID, folderID, 0)
 
 		assert.NoError(t, err)
 		assert.Equal(t, 100, info.MessageTotal)
 		assert.Equal(t, 25, info.MessageUnread)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
+
+	t.Run("ForeignFolder", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT message_total, message_unread FROM folder`).
+			WithArgs(folderID, profileID).
+			WillReturnError(sql.ErrNoRows)
+
+		info, err := repo.GetFolderMessagesInfo(ctx, profileID, folderID, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Messages{}, info)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("FilteredByLabel", func(t *testing.T) {
+		mock.ExpectQuery(`FROM message_label ml`).
+			WithArgs(profileID, folderID, int64(3)).
+			WillReturnRows(sqlmock.NewRows([]string{"total_count", "unread_count"}).
+				AddRow(4, 1))
+
+		info, err := repo.GetFolderMessagesInfo(ctx, profileID, folderID, 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, info.MessageTotal)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 }
 
 func TestMessageRepository_SaveMessageWithFolderDistribution(t *testing.T) {
@@ -1115,6 +1537,52 @@ func TestMessageRepository_FindThreadsByProfileID(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestMessageRepository_GetDelivery(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	mockTime := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT\s+m.id, m.topic, m.text, m.date_of_dispatch`).
+		WithArgs(int64(5), "receiver", "a4mail.ru").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "topic", "text", "date_of_dispatch",
+			"username", "domain", "name", "surname",
+			"profile_id", "base_profile_id",
+		}).AddRow(5, "Отчет", "Текст", mockTime, "boss", "example.com", "Иван", "Петров", 7, 8))
+
+	delivery, err := repo.GetDelivery(ctx, 5, "receiver@a4mail.ru")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), delivery.MessageID)
+	assert.Equal(t, int64(7), delivery.ProfileID)
+	assert.Equal(t, int64(8), delivery.BaseProfileID)
+	assert.Equal(t, "boss@example.com", delivery.From)
+	assert.Equal(t, "receiver@a4mail.ru", delivery.To)
+	assert.Contains(t, delivery.Headers["From"][0], "<boss@example.com>")
+	assert.Equal(t, []string{"receiver@a4mail.ru"}, delivery.Headers["To"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMessageRepository_GetDelivery_InvalidEmail(t *testing.T) {
+	ctx, repo, _ := setupTest(t)
+
+	_, err := repo.GetDelivery(ctx, 5, "receiver")
+
+	assert.Error(t, err)
+}
+
+func TestMessageRepository_AddMessageToFolder(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+
+	mock.ExpectExec(quote(`INSERT INTO folder_profile_message (message_id, folder_id)`)).
+		WithArgs(int64(5), int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.AddMessageToFolder(ctx, 5, 10)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestBuildSnippet(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -1167,3 +1635,750 @@ func TestBuildSnippet(t *testing.T) {
 		})
 	}
 }
+
+func TestMessageRepository_RestoreFromTrash(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	profileID := int64(1)
+	messageID := int64(123)
+
+	t.Run("RestoresToOriginalFolder", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT fpm.folder_id, fpm.original_folder_id`).
+			WithArgs(messageID, profileID).
+			WillReturnRows(sqlmock.NewRows([]string{"folder_id", "original_folder_id"}).AddRow(int64(5), int64(10)))
+		mock.ExpectExec(`INSERT INTO folder_profile_message`).
+			WithArgs(messageID, int64(10)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`DELETE FROM folder_profile_message`).
+			WithArgs(messageID, int64(5)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		folderID, err := repo.RestoreFromTrash(ctx, profileID, messageID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(10), folderID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("FallsBackToInbox", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT fpm.folder_id, fpm.original_folder_id`).
+			WithArgs(messageID, profileID).
+			WillReturnRows(sqlmock.NewRows([]string{"folder_id", "original_folder_id"}).AddRow(int64(5), nil))
+		mock.ExpectQuery(`SELECT id FROM folder WHERE profile_id = \$1 AND folder_type = 'inbox'`).
+			WithArgs(profileID).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)))
+		mock.ExpectExec(`INSERT INTO folder_profile_message`).
+			WithArgs(messageID, int64(1)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`DELETE FROM folder_profile_message`).
+			WithArgs(messageID, int64(5)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		folderID, err := repo.RestoreFromTrash(ctx, profileID, messageID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), folderID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotInTrash", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT fpm.folder_id, fpm.original_folder_id`).
+			WithArgs(messageID, profileID).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		_, err := repo.RestoreFromTrash(ctx, profileID, messageID)
+
+		assert.ErrorIs(t, err, domain.ErrMessageNotInTrash)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMessageRepository_EmptyTrash(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	profileID := int64(1)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id FROM folder WHERE profile_id = \$1 AND folder_type = 'trash'`).
+		WithArgs(profileID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(5)))
+	mock.ExpectExec(`DELETE FROM message m`).
+		WithArgs(int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`DELETE FROM folder_profile_message WHERE folder_id = \$1`).
+		WithArgs(int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	count, err := repo.EmptyTrash(ctx, profileID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMessageRepository_DeletePermanently(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	profileID := int64(1)
+	messageID := int64(123)
+
+	t.Run("DeletesMessage", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM message m`).
+			WithArgs(messageID, profileID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.DeletePermanently(ctx, profileID, messageID)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("UnlinksSharedMessage", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM message m`).
+			WithArgs(messageID, profileID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DELETE FROM folder_profile_message`).
+			WithArgs(messageID, profileID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.DeletePermanently(ctx, profileID, messageID)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM message m`).
+			WithArgs(messageID, profileID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DELETE FROM folder_profile_message`).
+			WithArgs(messageID, profileID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.DeletePermanently(ctx, profileID, messageID)
+
+		assert.ErrorIs(t, err, domain.ErrMessageNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMessageRepository_PurgeExpiredMessages(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	now := time.Now()
+
+	mock.ExpectExec(`DELETE FROM folder_profile_message fpm USING folder f`).
+		WithArgs(now, defaultTrashRetentionDays).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	purged, err := repo.PurgeExpiredMessages(ctx, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMessageRepository_DeleteOrphanMessages(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	olderThan := time.Now().Add(-time.Hour)
+
+	t.Run("DeletesMessagesAndReturnsUnreferencedFiles", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT m.id FROM message m WHERE m.created_at < \$1 .* AND NOT EXISTS \( SELECT 1 FROM outbound_message o WHERE o.message_id = m.id AND o.status IN \('queued', 'deferred', 'sending'\) \)`).
+			WithArgs(olderThan).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(10)).AddRow(int64(11)))
+		mock.ExpectQuery(`DELETE FROM file WHERE message_id = ANY\(\$1::int\[\]\)`).
+			WithArgs([]int64{10, 11}).
+			WillReturnRows(sqlmock.NewRows([]string{"storage_path"}).AddRow("inbound/10/report.pdf"))
+		mock.ExpectCommit()
+
+		deleted, storagePaths, err := repo.DeleteOrphanMessages(ctx, olderThan)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+		assert.Equal(t, []string{"inbound/10/report.pdf"}, storagePaths)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NothingToDelete", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT m.id FROM message m`).
+			WithArgs(olderThan).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		deleted, storagePaths, err := repo.DeleteOrphanMessages(ctx, olderThan)
+
+		assert.NoError(t, err)
+		assert.Zero(t, deleted)
+		assert.Empty(t, storagePaths)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMessageRepository_ApplyBatch(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	profileID := int64(1)
+
+	t.Run("ExplicitIDsPartialFailure", func(t *testing.T) {
+		batch := domain.BatchOperation{
+			Action:   domain.BatchMarkAsRead,
+			Selector: domain.BatchSelector{MessageIDs: []int64{10, 20}},
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`SAVEPOINT batch_item`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`SELECT EXISTS`).
+			WithArgs(int64(10), profileID).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectExec(`INSERT INTO profile_message`).
+			WithArgs(profileID, int64(10)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`RELEASE SAVEPOINT batch_item`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`SAVEPOINT batch_item`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`SELECT EXISTS`).
+			WithArgs(int64(20), profileID).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectExec(`ROLLBACK TO SAVEPOINT batch_item`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		result, err := repo.ApplyBatch(ctx, profileID, batch)
+
+		assert.NoError(t, err)
+		assert.False(t, result.HasMore)
+		assert.Equal(t, []domain.BatchItemResult{
+			{MessageID: 10},
+			{MessageID: 20, Error: domain.ErrMessageNotFound.Error()},
+		}, result.Items)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("FolderSelector", func(t *testing.T) {
+		batch := domain.BatchOperation{
+			Action:   domain.BatchDeleteFromFolder,
+			Selector: domain.BatchSelector{FolderID: 5, Filter: domain.MessageFilter{UnreadOnly: true}},
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT 1 FROM folder`).
+			WithArgs(int64(5), profileID).
+			WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+		mock.ExpectQuery(`SELECT fpm.message_id`).
+			WithArgs(int64(5), profileID, true, false, false, "", sql.NullTime{}, sql.NullTime{}, domain.MaxBatchSize+1).
+			WillReturnRows(sqlmock.NewRows([]string{"message_id"}).AddRow(int64(30)))
+		mock.ExpectExec(`SAVEPOINT batch_item`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DELETE FROM folder_profile_message`).
+			WithArgs(int64(30), int64(5)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`RELEASE SAVEPOINT batch_item`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		result, err := repo.ApplyBatch(ctx, profileID, batch)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.BatchItemResult{{MessageID: 30}}, result.Items)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("FolderSelectorStarredWithAttachments", func(t *testing.T) {
+		batch := domain.BatchOperation{
+			Action: domain.BatchMarkAsRead,
+			Selector: domain.BatchSelector{FolderID: 5, Filter: domain.MessageFilter{
+				StarredOnly:     true,
+				WithAttachments: true,
+			}},
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(quote(`AND ($4 = FALSE OR COALESCE(pm.starred, FALSE)) AND ($5 = FALSE OR EXISTS`)).
+			WithArgs(int64(5), profileID, false, true, true, "", sql.NullTime{}, sql.NullTime{}, domain.MaxBatchSize+1).
+			WillReturnRows(sqlmock.NewRows([]string{"message_id"}))
+		mock.ExpectCommit()
+
+		result, err := repo.ApplyBatch(ctx, profileID, batch)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Items)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("FolderNotFound", func(t *testing.T) {
+		batch := domain.BatchOperation{
+			Action:         domain.BatchMove,
+			Selector:       domain.BatchSelector{MessageIDs: []int64{10}},
+			TargetFolderID: 7,
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT 1 FROM folder`).
+			WithArgs(int64(7), profileID).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		_, err := repo.ApplyBatch(ctx, profileID, batch)
+
+		assert.ErrorIs(t, err, domain.ErrFolderNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMessageRepository_SetFlags(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	profileID := int64(1)
+	messageID := int64(123)
+	unread, starred := false, true
+
+	t.Run("Success", func(t *testing.T) {
+		mock.ExpectExec(`INSERT INTO profile_message`).
+			WithArgs(profileID, messageID,
+				sql.NullBool{Bool: false, Valid: true}, sql.NullBool{Bool: true, Valid: true},
+				sql.NullBool{}, sql.NullBool{}, sql.NullBool{}).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.SetFlags(ctx, profileID, messageID, domain.FlagsUpdate{Read: &unread, Starred: &starred})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotUsersMessage", func(t *testing.T) {
+		mock.ExpectExec(`INSERT INTO profile_message`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.SetFlags(ctx, profileID, messageID, domain.FlagsUpdate{Starred: &starred})
+
+		assert.ErrorIs(t, err, domain.ErrMessageNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMessageRepository_GetStarredMessagesWithKeysetPagination(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	profileID := int64(1)
+	dispatched := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{
+		"m.id", "m.topic", "m.text", "m.date_of_dispatch", "m.size", "pm.read_status",
+		"pm.starred", "pm.important", "pm.answered", "pm.forwarded", "labels",
+		"bp.id", "bp.username", "bp.domain",
+		"p.name", "p.surname", "p.image_path", "contact_name",
+	}).AddRow(
+		int64(101), "Topic", "Text", dispatched, int64(4), false,
+		true, true, false, false, []byte(`[]`),
+		int64(2), "user1", "domain.com",
+		sql.NullString{String: "John", Valid: true}, sql.NullString{}, sql.NullString{},
+		sql.NullString{},
+	)
+
+	mock.ExpectQuery(`FROM profile_message pm`).
+		WithArgs(profileID, 20).
+		WillReturnRows(rows)
+
+	messages, err := repo.GetStarredMessagesWithKeysetPagination(ctx, profileID, domain.MessagePage{Limit: 20})
+
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+	assert.True(t, messages[0].IsStarred)
+	assert.True(t, messages[0].IsImportant)
+	assert.Equal(t, "user1@domain.com", messages[0].Sender.Email)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMessageRepository_GetFolderMessagesSortedAndFiltered(t *testing.T) {
+	columns := []string{
+		"m.id", "m.topic", "m.text", "m.date_of_dispatch", "m.size", "pm.read_status",
+		"pm.starred", "pm.important", "pm.answered", "pm.forwarded", "labels",
+		"bp.id", "bp.username", "bp.domain",
+		"p.name", "p.surname", "p.image_path", "contact_name",
+	}
+	first := time.Date(2025, 11, 1, 12, 0, 0, 500000000, time.UTC)
+	pageRows := func(ids ...int64) *sqlmock.Rows {
+		rows := sqlmock.NewRows(columns)
+		for i, id := range ids {
+			rows.AddRow(
+				id, "Topic", "Text", first.Add(time.Duration(i+1)*time.Minute), int64(100*(i+1)), false,
+				false, false, false, false, []byte(`[]`),
+				int64(2), "user1", "domain.com",
+				sql.NullString{}, sql.NullString{}, sql.NullString{},
+				sql.NullString{},
+			)
+		}
+		return rows
+	}
+
+	t.Run("BackwardByDate", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`AND (m.date_of_dispatch, m.id) > ($4, $5)
+        ORDER BY
+            m.date_of_dispatch ASC, m.id ASC`)).
+			WithArgs(int64(1), int64(5), int64(0), first, int64(10), 2).
+			WillReturnRows(pageRows(11, 12))
+
+		messages, err := repo.GetFolderMessagesWithKeysetPagination(ctx, 1, 5, 0, domain.MessagePage{
+			After:    &domain.MessageKey{MessageID: 10, Datetime: first},
+			Backward: true,
+			Limit:    2,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, messages, 2)
+		// Страница возвращается в порядке списка: сначала более новые письма
+		assert.Equal(t, "12", messages[0].ID)
+		assert.Equal(t, "11", messages[1].ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("BySizeWithFilters", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		after := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(quote(`AND NOT pm.read_status
+            AND EXISTS (SELECT 1 FROM file fl WHERE fl.message_id = m.id)
+            AND LOWER(COALESCE(m.sender_username, bp.username) || '@' || COALESCE(m.sender_domain, bp.domain)) = LOWER($4)
+            AND m.date_of_dispatch >= $5
+            AND (m.size, m.id) < ($6, $7)
+        ORDER BY
+            m.size DESC, m.id DESC
+        LIMIT $8`)).
+			WithArgs(int64(1), int64(5), int64(0), "user1@domain.com", after, int64(500), int64(10), 2).
+			WillReturnRows(pageRows(9))
+
+		messages, err := repo.GetFolderMessagesWithKeysetPagination(ctx, 1, 5, 0, domain.MessagePage{
+			Sort: domain.SortSize,
+			Filter: domain.MessageFilter{
+				UnreadOnly:      true,
+				WithAttachments: true,
+				Sender:          " user1@domain.com ",
+				After:           after,
+			},
+			After: &domain.MessageKey{MessageID: 10, Size: 500},
+			Limit: 2,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, int64(100), messages[0].Size)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("StarredBySender", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`AND ((COALESCE(m.sender_username, bp.username) || '@' || COALESCE(m.sender_domain, bp.domain)), m.id) > ($2, $3)
+        ORDER BY
+            (COALESCE(m.sender_username, bp.username) || '@' || COALESCE(m.sender_domain, bp.domain)) ASC, m.id ASC`)).
+			WithArgs(int64(1), "a@domain.com", int64(10), 2).
+			WillReturnRows(pageRows(12))
+
+		messages, err := repo.GetStarredMessagesWithKeysetPagination(ctx, 1, domain.MessagePage{
+			Sort:  domain.SortSender,
+			After: &domain.MessageKey{MessageID: 10, Sender: "a@domain.com"},
+			Limit: 2,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, messages, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMessageRepository_GetStarredMessagesInfo(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	profileID := int64(1)
+
+	mock.ExpectQuery(`WHERE pm.profile_id = \$1 AND pm.starred`).
+		WithArgs(profileID).
+		WillReturnRows(sqlmock.NewRows([]string{"total_count", "unread_count"}).AddRow(7, 2))
+
+	info, err := repo.GetStarredMessagesInfo(ctx, profileID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, info.MessageTotal)
+	assert.Equal(t, 2, info.MessageUnread)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMessageRepository_CollectContact(t *testing.T) {
+	usedAt := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("KnownAddress", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT id FROM profile`).
+			WithArgs(int64(7)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec(`UPDATE contact_email`).
+			WithArgs(int64(1), "Bob@a4mail.ru", usedAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.CollectContact(ctx, 7, "Bob@a4mail.ru", usedAt)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NewAddress", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT id FROM profile`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec(`UPDATE contact_email`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`INSERT INTO contact \(profile_id, name\)`).
+			WithArgs(int64(1), "bob@a4mail.ru").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+		mock.ExpectExec(`INSERT INTO contact_email`).
+			WithArgs(int64(10), int64(1), "bob@a4mail.ru", usedAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.CollectContact(ctx, 7, "bob@a4mail.ru", usedAt)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ConcurrentInsert", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT id FROM profile`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec(`UPDATE contact_email`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`INSERT INTO contact`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
+		mock.ExpectExec(`INSERT INTO contact_email`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.CollectContact(ctx, 7, "bob@a4mail.ru", usedAt)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMessageRepository_ResolveRecipient(t *testing.T) {
+	t.Run("Found", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(`SELECT bp.username, bp.domain`).
+			WithArgs("Alexey", "FLINTMAIL.ru").
+			WillReturnRows(sqlmock.NewRows([]string{"username", "domain"}).AddRow("alexey", "flintmail.ru"))
+
+		email, err := repo.ResolveRecipient(ctx, "Alexey@FLINTMAIL.ru")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "alexey@flintmail.ru", email)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(`SELECT bp.username, bp.domain`).
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := repo.ResolveRecipient(ctx, "nobody@flintmail.ru")
+
+		assert.ErrorIs(t, err, domain.ErrRecipientNotFound)
+	})
+}
+
+func TestMessageRepository_FindRecipients(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	mock.ExpectQuery(`SELECT bp.username \|\| '@' \|\| bp.domain FROM profile_message pm .* UNION SELECT om.recipient FROM outbound_message om`).
+		WithArgs(int64(42)).
+		WillReturnRows(sqlmock.NewRows([]string{"recipient"}).AddRow("ivan@example.com").AddRow("maria@flintmail.ru"))
+
+	recipients, err := repo.FindRecipients(ctx, 42)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ivan@example.com", "maria@flintmail.ru"}, recipients)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMessageRepository_FindSenderAddresses(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	mock.ExpectQuery(`SELECT bp.username \|\| '@' \|\| bp.domain FROM profile p .* WHERE p.base_profile_id = \$1 UNION ALL SELECT pa.username \|\| '@' \|\| pa.domain FROM profile_alias pa JOIN profile p ON p.id = pa.profile_id WHERE p.base_profile_id = \$1`).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"address"}).AddRow("alexey@flintmail.ru").AddRow("support@flintmail.ru"))
+
+	addresses, err := repo.FindSenderAddresses(ctx, 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"alexey@flintmail.ru", "support@flintmail.ru"}, addresses)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMessageRepository_SetSenderAddress(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	mock.ExpectExec(`UPDATE message m SET sender_username = \$2, sender_domain = \$3 FROM base_profile bp WHERE m.id = \$1 AND bp.id = m.sender_base_profile_id`).
+		WithArgs(int64(42), "support", "flintmail.ru").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.SetSenderAddress(ctx, 42, "support@flintmail.ru")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMessageRepository_SaveSubmission(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	mock.ExpectExec(`INSERT INTO submitted_message .* ON CONFLICT \(profile_id, message_id_header\)`).
+		WithArgs(int64(7), "<abc@client>", int64(42)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.SaveSubmission(ctx, 7, "<abc@client>", 42)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMessageRepository_FindSubmission(t *testing.T) {
+	since := time.Date(2025, 11, 3, 7, 0, 0, 0, time.UTC)
+
+	t.Run("Found", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(`SELECT sm.message_id FROM submitted_message sm`).
+			WithArgs(int64(7), "<abc@client>", since).
+			WillReturnRows(sqlmock.NewRows([]string{"message_id"}).AddRow(42))
+
+		messageID, err := repo.FindSubmission(ctx, 7, "<abc@client>", since)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), messageID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(`SELECT sm.message_id FROM submitted_message sm`).
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := repo.FindSubmission(ctx, 7, "<abc@client>", since)
+
+		assert.ErrorIs(t, err, domain.ErrMessageNotFound)
+	})
+}
+
+func TestMessageRepository_SaveInboundMessage(t *testing.T) {
+	datetime := time.Date(2025, 11, 3, 7, 0, 0, 0, time.UTC)
+	msg := domain.InboundMessage{
+		EnvelopeFrom: "bounce@example.com",
+		From:         "ivan@example.com",
+		Topic:        "Привет",
+		Text:         "Текст",
+		Datetime:     datetime,
+		DKIM:         domain.DKIMResult{Status: domain.DKIMPass, Domain: "example.com"},
+		SPF:          domain.SPFResult{Status: domain.SPFPass, Domain: "example.com"},
+		DMARC:        domain.DMARCResult{Status: domain.DMARCNone, Aligned: true},
+		AuthenticationResults: "mx.flintmail.ru; dkim=pass header.d=example.com header.s=mail; " +
+			"spf=pass smtp.mailfrom=bounce@example.com; dmarc=none header.from=example.com",
+	}
+	files := []domain.File{{FileType: "document", Size: 8, StoragePath: "mail/abc/report.pdf"}}
+
+	t.Run("Success", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO message .* sender_username, sender_domain`).
+			WithArgs("Привет", "Текст", datetime, "ivan", "example.com", "pass", "example.com",
+				"pass", "example.com", "none", nil, true, msg.AuthenticationResults).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(100))
+		mock.ExpectQuery(`SELECT p.id`).
+			WithArgs("alexey", "flintmail.ru").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec(`INSERT INTO folder_profile_message`).
+			WithArgs(int64(100), int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO profile_message`).
+			WithArgs(int64(1), int64(100)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO file`).
+			WithArgs("document", int64(8), "mail/abc/report.pdf", int64(100)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		messageID, err := repo.SaveInboundMessage(ctx, "alexey@flintmail.ru", msg, files)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(100), messageID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ReceiverRemoved", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO message`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(100))
+		mock.ExpectQuery(`SELECT p.id`).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		_, err := repo.SaveInboundMessage(ctx, "alexey@flintmail.ru", msg, nil)
+
+		assert.ErrorIs(t, err, domain.ErrRecipientNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMessageRepository_SaveOutboundMessage(t *testing.T) {
+	notBefore := time.Date(2025, 11, 3, 12, 0, 30, 0, time.UTC)
+
+	// Отправитель передается идентификатором base_profile (7), папки и очередь
+	// используют идентификатор profile (1)
+	t.Run("NewThread", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT id FROM profile WHERE base_profile_id = \$1`).
+			WithArgs(int64(7)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectQuery(`INSERT INTO message`).
+			WithArgs("Hi", "Text", sqlmock.AnyArg(), int64(7), nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(100))
+		mock.ExpectExec(`INSERT INTO folder_profile_message`).
+			WithArgs(int64(100), int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO profile_message`).
+			WithArgs(int64(1), int64(100)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO outbound_message`).
+			WithArgs(int64(100), int64(1), "someone@gmail.com", notBefore, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		messageID, err := repo.SaveOutboundMessage(ctx, "someone@gmail.com", 7, 0, "Hi", "Text", notBefore, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(100), messageID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("QueueFailure", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT id FROM profile`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectQuery(`INSERT INTO message`).
+			WithArgs("Re: Hi", "Text", sqlmock.AnyArg(), int64(7), int64(55)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(100))
+		mock.ExpectExec(`INSERT INTO folder_profile_message`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO profile_message`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO outbound_message`).
+			WillReturnError(fmt.Errorf("db error"))
+		mock.ExpectRollback()
+
+		_, err := repo.SaveOutboundMessage(ctx, "someone@gmail.com", 7, 55, "Re: Hi", "Text", notBefore, 0)
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
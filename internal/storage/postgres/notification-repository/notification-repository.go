@@ -0,0 +1,39 @@
+package notification_repository
+
+import (
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// GetNotificationTolerance возвращает минимальный интервал между уведомлениями о новых письмах.
+// Настройка хранится в секундах, без настроек уведомления не ограничиваются
+func (repo *NotificationRepository) GetNotificationTolerance(ctx context.Context, profileID int64) (time.Duration, error) {
+	const op = "storage.postgresql.notification.GetNotificationTolerance"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `SELECT notification_tolerance FROM settings WHERE profile_id = $1`
+
+	var seconds int64
+	log.Debug("Querying notification tolerance...")
+	if err := repo.db.QueryRowContext(ctx, query, profileID).Scan(&seconds); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, e.Wrap(op, err)
+	}
+
+	return time.Duration(seconds) * time.Second, nil
+}
@@ -0,0 +1,65 @@
+package notification_repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationRepository_GetNotificationTolerance(t *testing.T) {
+	query := regexp.QuoteMeta(`SELECT notification_tolerance FROM settings WHERE profile_id = $1`)
+
+	tests := []struct {
+		name      string
+		mockSetup func(mock sqlmock.Sqlmock)
+		want      time.Duration
+		wantErr   bool
+	}{
+		{
+			name: "Configured",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).WithArgs(int64(1)).
+					WillReturnRows(sqlmock.NewRows([]string{"notification_tolerance"}).AddRow(30))
+			},
+			want: 30 * time.Second,
+		},
+		{
+			name: "NoSettings",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).WithArgs(int64(1)).WillReturnError(sql.ErrNoRows)
+			},
+		},
+		{
+			name: "DBError",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(query).WithArgs(int64(1)).WillReturnError(errors.New("db down"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+			tt.mockSetup(mock)
+
+			got, err := New(db).GetNotificationTolerance(context.Background(), 1)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
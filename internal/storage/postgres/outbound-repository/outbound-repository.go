@@ -0,0 +1,180 @@
+package outbound_repository
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"database/sql"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxErrorLength - ограничение длины last_error в таблице outbound_message
+const maxErrorLength = 1000
+
+type OutboundRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *OutboundRepository {
+	return &OutboundRepository{db: db}
+}
+
+// ClaimDue забирает до limit писем, которым пора уходить, и арендует их на время lease.
+// Письма, которые уже обрабатывает другой экземпляр сервиса, пропускаются
+func (repo *OutboundRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboundMessage, error) {
+	const op = "storage.postgresql.outbound.ClaimDue"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const claim = `
+        WITH due AS (
+            SELECT id
+            FROM outbound_message
+            WHERE status IN ('queued', 'sending', 'deferred') AND next_attempt_at <= NOW()
+            ORDER BY next_attempt_at
+            LIMIT $1
+            FOR UPDATE SKIP LOCKED
+        )
+        UPDATE outbound_message o
+        SET status = 'sending',
+            attempts = o.attempts + 1,
+            next_attempt_at = NOW() + $2 * INTERVAL '1 second'
+        FROM due
+        WHERE o.id = due.id
+        RETURNING o.id, o.message_id, o.sender_profile_id, o.recipient, o.attempts, o.delay_notified, o.created_at,
+            o.forward_hops`
+
+	log.Debug("Claiming due outbound messages...")
+	rows, err := repo.db.QueryContext(ctx, claim, limit, int64(lease/time.Second))
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	var messages []domain.OutboundMessage
+	for rows.Next() {
+		var msg domain.OutboundMessage
+		err := rows.Scan(&msg.ID, &msg.MessageID, &msg.SenderProfileID, &msg.Recipient,
+			&msg.Attempts, &msg.DelayNotified, &msg.CreatedAt, &msg.ForwardHops)
+		if err != nil {
+			rows.Close()
+			return nil, e.Wrap(op, err)
+		}
+		messages = append(messages, msg)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	for i := range messages {
+		if err := repo.loadContent(ctx, &messages[i]); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+	}
+
+	return messages, nil
+}
+
+// loadContent дополняет письмо из очереди отправителем, текстом и вложениями
+func (repo *OutboundRepository) loadContent(ctx context.Context, msg *domain.OutboundMessage) error {
+	const query = `
+        SELECT m.topic, m.text, m.date_of_dispatch,
+            COALESCE(m.sender_username, bp.username), COALESCE(m.sender_domain, bp.domain), p.name, p.surname
+        FROM message m
+        LEFT JOIN base_profile bp ON m.sender_base_profile_id = bp.id
+        LEFT JOIN profile p ON p.base_profile_id = bp.id
+        WHERE m.id = $1`
+
+	var topic, text, name, surname sql.NullString
+	var username, domainName string
+	err := repo.db.QueryRowContext(ctx, query, msg.MessageID).Scan(
+		&topic, &text, &msg.Datetime, &username, &domainName, &name, &surname)
+	if err != nil {
+		return err
+	}
+
+	msg.Topic = topic.String
+	msg.Text = text.String
+	msg.From = username + "@" + domainName
+	msg.FromName = strings.TrimSpace(name.String + " " + surname.String)
+
+	rows, err := repo.db.QueryContext(ctx, `
+        SELECT id, file_type, size, storage_path
+        FROM file
+        WHERE message_id = $1
+        ORDER BY id`, msg.MessageID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		file := domain.File{MessageID: msg.MessageID}
+		if err := rows.Scan(&file.ID, &file.FileType, &file.Size, &file.StoragePath); err != nil {
+			return err
+		}
+		msg.Files = append(msg.Files, file)
+	}
+
+	return rows.Err()
+}
+
+func (repo *OutboundRepository) MarkSent(ctx context.Context, id int64) error {
+	const op = "storage.postgresql.outbound.MarkSent"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	log.Debug("Marking outbound message as sent...")
+	_, err := repo.db.ExecContext(ctx, `
+        UPDATE outbound_message
+        SET status = 'sent', last_error = NULL
+        WHERE id = $1`, id)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// MarkDeferred откладывает следующую попытку доставки до nextAttempt
+func (repo *OutboundRepository) MarkDeferred(ctx context.Context, id int64, nextAttempt time.Time, reason string, delayNotified bool) error {
+	const op = "storage.postgresql.outbound.MarkDeferred"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	log.Debug("Deferring outbound message...")
+	_, err := repo.db.ExecContext(ctx, `
+        UPDATE outbound_message
+        SET status = 'deferred', next_attempt_at = $2, last_error = $3, delay_notified = $4
+        WHERE id = $1`, id, nextAttempt, truncateError(reason), delayNotified)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func (repo *OutboundRepository) MarkBounced(ctx context.Context, id int64, reason string) error {
+	const op = "storage.postgresql.outbound.MarkBounced"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	log.Debug("Marking outbound message as bounced...")
+	_, err := repo.db.ExecContext(ctx, `
+        UPDATE outbound_message
+        SET status = 'bounced', last_error = $2
+        WHERE id = $1`, id, truncateError(reason))
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+func truncateError(reason string) string {
+	reason = strings.ToValidUTF8(reason, "")
+	if utf8.RuneCountInString(reason) > maxErrorLength {
+		reason = string([]rune(reason)[:maxErrorLength])
+	}
+	return reason
+}
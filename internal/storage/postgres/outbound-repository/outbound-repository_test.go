@@ -0,0 +1,124 @@
+package outbound_repository
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTest(t *testing.T) (context.Context, *OutboundRepository, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return context.Background(), New(db), mock
+}
+
+func quote(query string) string {
+	return regexp.QuoteMeta(query)
+}
+
+func TestOutboundRepository_ClaimDue(t *testing.T) {
+	created := time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`FOR UPDATE SKIP LOCKED`)).
+			WithArgs(10, int64(300)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "message_id", "sender_profile_id", "recipient", "attempts", "delay_notified", "created_at", "forward_hops"}).
+				AddRow(1, 100, 5, "someone@gmail.com", 2, false, created, 1))
+		mock.ExpectQuery(quote(`SELECT m.topic, m.text, m.date_of_dispatch`)).
+			WithArgs(int64(100)).
+			WillReturnRows(sqlmock.NewRows([]string{"topic", "text", "date_of_dispatch", "username", "domain", "name", "surname"}).
+				AddRow("Hi", "Text", created, "alexey", "flintmail.ru", "Алексей", nil))
+		mock.ExpectQuery(quote(`SELECT id, file_type, size, storage_path`)).
+			WithArgs(int64(100)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "file_type", "size", "storage_path"}).
+				AddRow(3, "document", 8, "files/report.pdf"))
+
+		messages, err := repo.ClaimDue(ctx, 10, 5*time.Minute)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.OutboundMessage{{
+			ID:              1,
+			MessageID:       100,
+			SenderProfileID: 5,
+			From:            "alexey@flintmail.ru",
+			FromName:        "Алексей",
+			Recipient:       "someone@gmail.com",
+			Topic:           "Hi",
+			Text:            "Text",
+			Datetime:        created,
+			Files:           []domain.File{{ID: 3, FileType: "document", Size: 8, StoragePath: "files/report.pdf", MessageID: 100}},
+			Attempts:        2,
+			CreatedAt:       created,
+			ForwardHops:     1,
+		}}, messages)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`FOR UPDATE SKIP LOCKED`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "message_id", "sender_profile_id", "recipient", "attempts", "delay_notified", "created_at", "forward_hops"}))
+
+		messages, err := repo.ClaimDue(ctx, 10, time.Minute)
+
+		assert.NoError(t, err)
+		assert.Empty(t, messages)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`FOR UPDATE SKIP LOCKED`)).
+			WillReturnError(errors.New("db error"))
+
+		_, err := repo.ClaimDue(ctx, 10, time.Minute)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestOutboundRepository_MarkStatus(t *testing.T) {
+	next := time.Date(2025, 11, 3, 12, 30, 0, 0, time.UTC)
+
+	t.Run("Sent", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectExec(quote(`SET status = 'sent'`)).
+			WithArgs(int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.MarkSent(ctx, 1))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Deferred", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectExec(quote(`SET status = 'deferred'`)).
+			WithArgs(int64(1), next, "421 try later", true).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.MarkDeferred(ctx, 1, next, "421 try later", true))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("BouncedLongReason", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectExec(quote(`SET status = 'bounced'`)).
+			WithArgs(int64(1), strings.Repeat("x", maxErrorLength)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.MarkBounced(ctx, 1, strings.Repeat("x", 2*maxErrorLength)))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,155 @@
+package pop3_repository
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	commonE "2025_2_a4code/internal/lib/errors"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+)
+
+// POP3Repository принимает идентификатор базового профиля, как он приходит из токена
+// и из FindAPOPSecret. Настройки и скрытые письма хранятся под идентификатором профиля
+type POP3Repository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *POP3Repository {
+	return &POP3Repository{db: db}
+}
+
+// GetSettings возвращает настройки POP3 профиля, настройки по умолчанию - если их нет
+func (repo *POP3Repository) GetSettings(ctx context.Context, profileID int64) (domain.POP3Settings, error) {
+	const op = "storage.postgresql.pop3.GetSettings"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        SELECT s.pop3_delete_action, s.pop3_apop_secret IS NOT NULL
+        FROM settings s
+        JOIN profile p ON p.id = s.profile_id
+        WHERE p.base_profile_id = $1`
+
+	var action string
+	var settings domain.POP3Settings
+	log.Debug("Querying pop3 settings...")
+	if err := repo.db.QueryRowContext(ctx, query, profileID).Scan(&action, &settings.APOPEnabled); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.POP3Settings{DeleteAction: domain.POP3DeleteToTrash}, nil
+		}
+		return domain.POP3Settings{}, e.Wrap(op, err)
+	}
+
+	settings.DeleteAction = domain.POP3DeleteAction(action)
+	return settings, nil
+}
+
+// SaveSettings сохраняет настройки POP3. Выключенный APOP стирает секрет, пустой
+// секрет при включенном APOP оставляет прежний
+func (repo *POP3Repository) SaveSettings(ctx context.Context, profileID int64, update domain.POP3SettingsUpdate) error {
+	const op = "storage.postgresql.pop3.SaveSettings"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        INSERT INTO settings (profile_id, pop3_delete_action, pop3_apop_secret)
+        SELECT p.id, $2, $3
+        FROM profile p
+        WHERE p.base_profile_id = $1
+        ON CONFLICT (profile_id) DO UPDATE SET
+            pop3_delete_action = EXCLUDED.pop3_delete_action,
+            pop3_apop_secret = CASE WHEN $4 THEN EXCLUDED.pop3_apop_secret ELSE settings.pop3_apop_secret END`
+
+	var secret sql.NullString
+	if update.APOPEnabled && update.APOPSecret != "" {
+		secret = sql.NullString{String: update.APOPSecret, Valid: true}
+	}
+	replaceSecret := !update.APOPEnabled || update.APOPSecret != ""
+
+	log.Debug("Saving pop3 settings...")
+	if _, err := repo.db.ExecContext(ctx, query, profileID, string(update.DeleteAction), secret, replaceSecret); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// FindAPOPSecret возвращает профиль пользователя и его секрет APOP, пустой - если APOP выключен
+func (repo *POP3Repository) FindAPOPSecret(ctx context.Context, username, emailDomain string) (int64, string, error) {
+	const op = "storage.postgresql.pop3.FindAPOPSecret"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        SELECT bp.id, COALESCE(s.pop3_apop_secret, '')
+        FROM base_profile bp
+        JOIN profile p ON p.base_profile_id = bp.id
+        LEFT JOIN settings s ON s.profile_id = p.id
+        WHERE bp.username = $1 AND bp.domain = $2`
+
+	var profileID int64
+	var secret string
+	log.Debug("Querying apop secret...")
+	if err := repo.db.QueryRowContext(ctx, query, username, emailDomain).Scan(&profileID, &secret); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, "", e.Wrap(op, commonE.ErrNotFound)
+		}
+		return 0, "", e.Wrap(op, err)
+	}
+
+	return profileID, secret, nil
+}
+
+// FindHidden возвращает письма, скрытые от POP3
+func (repo *POP3Repository) FindHidden(ctx context.Context, profileID int64) ([]int64, error) {
+	const op = "storage.postgresql.pop3.FindHidden"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        SELECT h.message_id
+        FROM pop3_hidden_message h
+        JOIN profile p ON p.id = h.profile_id
+        WHERE p.base_profile_id = $1`
+
+	log.Debug("Querying hidden messages...")
+	rows, err := repo.db.QueryContext(ctx, query, profileID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return ids, nil
+}
+
+// Hide скрывает письма пользователя от POP3. Чужие письма пропускаются
+func (repo *POP3Repository) Hide(ctx context.Context, profileID int64, messageIDs []int64) error {
+	const op = "storage.postgresql.pop3.Hide"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        INSERT INTO pop3_hidden_message (profile_id, message_id)
+        SELECT pm.profile_id, pm.message_id
+        FROM profile_message pm
+        JOIN profile p ON p.id = pm.profile_id AND p.base_profile_id = $1
+        WHERE pm.message_id = ANY($2::int[])
+        ON CONFLICT (profile_id, message_id) DO NOTHING`
+
+	log.Debug("Hiding messages from pop3...")
+	if _, err := repo.db.ExecContext(ctx, query, profileID, messageIDs); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
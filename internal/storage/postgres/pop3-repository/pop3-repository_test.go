@@ -0,0 +1,155 @@
+package pop3_repository
+
+import (
+	"2025_2_a4code/internal/domain"
+	commonE "2025_2_a4code/internal/lib/errors"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// arrayConverter пропускает срезы идентификаторов, которые pgx передает как int[]
+type arrayConverter struct{}
+
+func (arrayConverter) ConvertValue(v interface{}) (driver.Value, error) {
+	if ids, ok := v.([]int64); ok {
+		return ids, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(v)
+}
+
+func setupTest(t *testing.T) (context.Context, *POP3Repository, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(arrayConverter{}))
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return context.Background(), New(db), mock
+}
+
+func quote(query string) string {
+	return regexp.QuoteMeta(query)
+}
+
+func TestPOP3Repository_GetSettings(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`JOIN profile p ON p.id = s.profile_id WHERE p.base_profile_id = $1`)).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"pop3_delete_action", "apop_enabled"}).AddRow("hide", true))
+
+		settings, err := repo.GetSettings(ctx, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.POP3Settings{DeleteAction: domain.POP3DeleteHide, APOPEnabled: true}, settings)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotConfigured", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`FROM settings`)).
+			WillReturnRows(sqlmock.NewRows([]string{"pop3_delete_action", "apop_enabled"}))
+
+		settings, err := repo.GetSettings(ctx, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.POP3Settings{DeleteAction: domain.POP3DeleteToTrash}, settings)
+	})
+}
+
+func TestPOP3Repository_SaveSettings(t *testing.T) {
+	tests := []struct {
+		name          string
+		update        domain.POP3SettingsUpdate
+		secret        any
+		replaceSecret bool
+	}{
+		{
+			name:          "NewSecret",
+			update:        domain.POP3SettingsUpdate{DeleteAction: domain.POP3DeleteHide, APOPEnabled: true, APOPSecret: "tanstaaf"},
+			secret:        "tanstaaf",
+			replaceSecret: true,
+		},
+		{
+			name:          "KeepSecret",
+			update:        domain.POP3SettingsUpdate{DeleteAction: domain.POP3DeleteToTrash, APOPEnabled: true},
+			secret:        nil,
+			replaceSecret: false,
+		},
+		{
+			name:          "DisableAPOP",
+			update:        domain.POP3SettingsUpdate{DeleteAction: domain.POP3DeleteToTrash, APOPSecret: "ignored"},
+			secret:        nil,
+			replaceSecret: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, repo, mock := setupTest(t)
+			mock.ExpectExec(quote(`INSERT INTO settings (profile_id, pop3_delete_action, pop3_apop_secret) SELECT p.id, $2, $3 FROM profile p WHERE p.base_profile_id = $1`)).
+				WithArgs(int64(1), string(tt.update.DeleteAction), tt.secret, tt.replaceSecret).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			assert.NoError(t, repo.SaveSettings(ctx, 1, tt.update))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPOP3Repository_FindAPOPSecret(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`FROM base_profile bp`)).
+			WithArgs("alexey", "flintmail.ru").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "secret"}).AddRow(7, "tanstaaf"))
+
+		profileID, secret, err := repo.FindAPOPSecret(ctx, "alexey", "flintmail.ru")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), profileID)
+		assert.Equal(t, "tanstaaf", secret)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`FROM base_profile bp`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "secret"}))
+
+		_, _, err := repo.FindAPOPSecret(ctx, "maria", "flintmail.ru")
+
+		assert.ErrorIs(t, err, commonE.ErrNotFound)
+	})
+}
+
+func TestPOP3Repository_Hidden(t *testing.T) {
+	t.Run("FindHidden", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`FROM pop3_hidden_message h JOIN profile p ON p.id = h.profile_id WHERE p.base_profile_id = $1`)).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"message_id"}).AddRow(3).AddRow(5))
+
+		ids, err := repo.FindHidden(ctx, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{3, 5}, ids)
+	})
+
+	t.Run("Hide", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectExec(quote(`JOIN profile p ON p.id = pm.profile_id AND p.base_profile_id = $1`)).
+			WithArgs(int64(1), []int64{3, 5}).
+			WillReturnError(errors.New("db error"))
+
+		err := repo.Hide(ctx, 1, []int64{3, 5})
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
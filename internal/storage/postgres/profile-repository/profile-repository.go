@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 )
 
 type ProfileRepository struct {
@@ -135,6 +136,7 @@ func (repo *ProfileRepository) FindSenderByID(ctx context.Context, id int64) (*d
 	return &sender, nil
 }
 
+// UserExists проверяет, занято ли имя основным или дополнительным адресом
 func (repo *ProfileRepository) UserExists(ctx context.Context, username string) (bool, error) {
 	const op = "storage.postgres.profile-repository.UserExists"
 	log := logger.GetLogger(ctx).With(slog.String("op", op))
@@ -144,6 +146,10 @@ func (repo *ProfileRepository) UserExists(ctx context.Context, username string)
 			SELECT 1
 			FROM base_profile 
 			WHERE username = $1 AND domain = 'flintmail.ru'
+		) OR EXISTS (
+			SELECT 1
+			FROM profile_alias
+			WHERE LOWER(username) = LOWER($1) AND LOWER(domain) = 'flintmail.ru'
 		)`
 
 	stmt, err := repo.db.PrepareContext(ctx, query)
@@ -395,6 +401,9 @@ func (repo *ProfileRepository) FindSettingsByProfileId(ctx context.Context, prof
 	const query = `
         SELECT 
             s.id, s.profile_id, s.notification_tolerance, s.language, s.theme, s.signature,
+            s.trash_retention_days,
+            s.vacation_enabled, s.vacation_start, s.vacation_end, s.vacation_subject,
+            s.vacation_body, s.vacation_contacts_only, s.vacation_days,
             p.id as actual_profile_id
         FROM 
             base_profile bp
@@ -417,11 +426,19 @@ func (repo *ProfileRepository) FindSettingsByProfileId(ctx context.Context, prof
 	var settingsProfileID sql.NullInt64
 	var notificationTolerance, language, theme sql.NullString
 	var signatureNullable sql.NullString
+	var trashRetentionDays sql.NullInt64
+	var vacationEnabled, vacationContactsOnly sql.NullBool
+	var vacationStart, vacationEnd sql.NullTime
+	var vacationSubject, vacationBody sql.NullString
+	var vacationDays sql.NullInt64
 
 	log.Debug("Executing FindSettingsByProfileId query...")
 	err = stmt.QueryRowContext(ctx, profileID).Scan(
 		&settingsID, &settingsProfileID, &notificationTolerance,
 		&language, &theme, &signatureNullable,
+		&trashRetentionDays,
+		&vacationEnabled, &vacationStart, &vacationEnd, &vacationSubject,
+		&vacationBody, &vacationContactsOnly, &vacationDays,
 		&actualProfileID,
 	)
 
@@ -444,6 +461,16 @@ func (repo *ProfileRepository) FindSettingsByProfileId(ctx context.Context, prof
 	settings.NotificationTolerance = notificationTolerance.String
 	settings.Language = language.String
 	settings.Theme = theme.String
+	settings.TrashRetentionDays = int(trashRetentionDays.Int64)
+	settings.Vacation = domain.VacationSettings{
+		Enabled:      vacationEnabled.Bool,
+		Start:        vacationStart.Time,
+		End:          vacationEnd.Time,
+		Subject:      vacationSubject.String,
+		Body:         vacationBody.String,
+		ContactsOnly: vacationContactsOnly.Bool,
+		Days:         int(vacationDays.Int64),
+	}
 
 	if signatureNullable.Valid && signatureNullable.String != "" {
 		settings.Signatures = []string{signatureNullable.String}
@@ -496,6 +523,55 @@ func (repo *ProfileRepository) UpdateProfileInfo(ctx context.Context, profileID
 	return nil
 }
 
+// UpdateVacation сохраняет настройки автоответа, создавая строку настроек при ее отсутствии
+func (repo *ProfileRepository) UpdateVacation(ctx context.Context, profileID int64, vacation domain.VacationSettings) error {
+	const op = "storage.postgres.profile-repository.UpdateVacation"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+		INSERT INTO settings (
+			profile_id, vacation_enabled, vacation_start, vacation_end,
+			vacation_subject, vacation_body, vacation_contacts_only, vacation_days
+		)
+		SELECT p.id, $2, $3, $4, $5, $6, $7, $8
+		FROM profile p
+		WHERE p.base_profile_id = $1
+		ON CONFLICT (profile_id) DO UPDATE SET
+			vacation_enabled = EXCLUDED.vacation_enabled,
+			vacation_start = EXCLUDED.vacation_start,
+			vacation_end = EXCLUDED.vacation_end,
+			vacation_subject = EXCLUDED.vacation_subject,
+			vacation_body = EXCLUDED.vacation_body,
+			vacation_contacts_only = EXCLUDED.vacation_contacts_only,
+			vacation_days = EXCLUDED.vacation_days
+	`
+
+	stmt, err := repo.db.PrepareContext(ctx, query)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+	defer stmt.Close()
+
+	log.Debug("Executing UpdateVacation query...")
+	res, err := stmt.ExecContext(ctx, profileID,
+		vacation.Enabled, toNullTime(vacation.Start), toNullTime(vacation.End),
+		vacation.Subject, vacation.Body, vacation.ContactsOnly, vacation.Days,
+	)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+	if affected == 0 {
+		return e.Wrap(op, commonE.ErrNotFound)
+	}
+
+	return nil
+}
+
 func (repo *ProfileRepository) InsertProfileAvatar(ctx context.Context, profileID int64, avatarURL string) error {
 	const op = "storage.postgres.profile-repository.InsertProfileAvatar"
 	log := logger.GetLogger(ctx).With(slog.String("op", op))
@@ -520,6 +596,152 @@ func (repo *ProfileRepository) InsertProfileAvatar(ctx context.Context, profileI
 	return nil
 }
 
+// CreateAppPassword сохраняет хеш нового пароля приложения, если у профиля их меньше domain.MaxAppPasswords
+func (repo *ProfileRepository) CreateAppPassword(ctx context.Context, profileID int64, name, passwordHash string) (domain.AppPassword, error) {
+	const op = "storage.postgres.profile-repository.CreateAppPassword"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+		INSERT INTO app_password (profile_id, name, password_hash)
+		SELECT p.id, $2, $3
+		FROM profile p
+		WHERE p.base_profile_id = $1
+		  AND (SELECT COUNT(*) FROM app_password ap WHERE ap.profile_id = p.id) < $4
+		RETURNING id, name, created_at`
+
+	var appPassword domain.AppPassword
+	log.Debug("Executing CreateAppPassword query...")
+	err := repo.db.QueryRowContext(ctx, query, profileID, name, passwordHash, domain.MaxAppPasswords).Scan(
+		&appPassword.ID, &appPassword.Name, &appPassword.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.AppPassword{}, domain.ErrTooManyAppPasswords
+		}
+		return domain.AppPassword{}, e.Wrap(op, err)
+	}
+
+	return appPassword, nil
+}
+
+func (repo *ProfileRepository) FindAppPasswords(ctx context.Context, profileID int64) ([]domain.AppPassword, error) {
+	const op = "storage.postgres.profile-repository.FindAppPasswords"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+		SELECT ap.id, ap.name, ap.created_at, ap.last_used_at, ap.last_used_ip
+		FROM app_password ap
+		JOIN profile p ON p.id = ap.profile_id
+		WHERE p.base_profile_id = $1
+		ORDER BY ap.created_at DESC, ap.id DESC`
+
+	log.Debug("Executing FindAppPasswords query...")
+	rows, err := repo.db.QueryContext(ctx, query, profileID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	appPasswords := make([]domain.AppPassword, 0)
+	for rows.Next() {
+		var appPassword domain.AppPassword
+		var lastUsedAt sql.NullTime
+		var lastUsedIP sql.NullString
+		if err := rows.Scan(&appPassword.ID, &appPassword.Name, &appPassword.CreatedAt, &lastUsedAt, &lastUsedIP); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		if lastUsedAt.Valid {
+			appPassword.LastUsedAt = lastUsedAt.Time
+		}
+		if lastUsedIP.Valid {
+			appPassword.LastUsedIP = lastUsedIP.String
+		}
+		appPasswords = append(appPasswords, appPassword)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return appPasswords, nil
+}
+
+func (repo *ProfileRepository) DeleteAppPassword(ctx context.Context, profileID, appPasswordID int64) error {
+	const op = "storage.postgres.profile-repository.DeleteAppPassword"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+		DELETE FROM app_password ap
+		USING profile p
+		WHERE ap.id = $1 AND ap.profile_id = p.id AND p.base_profile_id = $2`
+
+	log.Debug("Executing DeleteAppPassword query...")
+	res, err := repo.db.ExecContext(ctx, query, appPasswordID, profileID)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+	if affected == 0 {
+		return domain.ErrAppPasswordNotFound
+	}
+
+	return nil
+}
+
+// FindAppPasswordHashes возвращает хеши всех паролей приложений пользователя с адресом username@emailDomain
+func (repo *ProfileRepository) FindAppPasswordHashes(ctx context.Context, username string, emailDomain string) ([]domain.AppPasswordHash, error) {
+	const op = "storage.postgres.profile-repository.FindAppPasswordHashes"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+		SELECT ap.id, bp.id, ap.password_hash
+		FROM app_password ap
+		JOIN profile p ON p.id = ap.profile_id
+		JOIN base_profile bp ON bp.id = p.base_profile_id
+		WHERE bp.username = $1 AND bp.domain = $2`
+
+	log.Debug("Executing FindAppPasswordHashes query...")
+	rows, err := repo.db.QueryContext(ctx, query, username, emailDomain)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	var hashes []domain.AppPasswordHash
+	for rows.Next() {
+		var hash domain.AppPasswordHash
+		if err := rows.Scan(&hash.ID, &hash.ProfileID, &hash.PasswordHash); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		hashes = append(hashes, hash)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return hashes, nil
+}
+
+// UpdateAppPasswordUsage запоминает время и адрес последнего входа с паролем приложения
+func (repo *ProfileRepository) UpdateAppPasswordUsage(ctx context.Context, appPasswordID int64, usedAt time.Time, ip string) error {
+	const op = "storage.postgres.profile-repository.UpdateAppPasswordUsage"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+		UPDATE app_password
+		SET last_used_at = $2, last_used_ip = $3
+		WHERE id = $1`
+
+	log.Debug("Executing UpdateAppPasswordUsage query...")
+	if _, err := repo.db.ExecContext(ctx, query, appPasswordID, usedAt, toNullString(ip)); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
 func toNullString(value string) sql.NullString {
 	value = strings.TrimSpace(value)
 	if value == "" {
@@ -527,3 +749,10 @@ func toNullString(value string) sql.NullString {
 	}
 	return sql.NullString{String: value, Valid: true}
 }
+
+func toNullTime(value time.Time) sql.NullTime {
+	if value.IsZero() {
+		return sql.NullTime{Valid: false}
+	}
+	return sql.NullTime{Time: value, Valid: true}
+}
@@ -210,9 +210,15 @@ func TestFindSettingsByProfileId_Default(t *testing.T) {
 
 	rows := sqlmock.NewRows([]string{
 		"id", "profile_id", "notification_tolerance", "language", "theme", "signature",
+		"trash_retention_days",
+		"vacation_enabled", "vacation_start", "vacation_end", "vacation_subject",
+		"vacation_body", "vacation_contacts_only", "vacation_days",
 		"actual_profile_id",
 	}).AddRow(
 		sql.NullInt64{}, sql.NullInt64{}, sql.NullString{}, sql.NullString{}, sql.NullString{}, sql.NullString{},
+		sql.NullInt64{},
+		sql.NullBool{}, sql.NullTime{}, sql.NullTime{}, sql.NullString{},
+		sql.NullString{}, sql.NullBool{}, sql.NullInt64{},
 		actualProfileID,
 	)
 
@@ -228,9 +234,86 @@ func TestFindSettingsByProfileId_Default(t *testing.T) {
 	assert.Equal(t, "ru", settings.Language)
 	assert.Equal(t, "light", settings.Theme)
 	assert.Empty(t, settings.Signatures)
+	assert.Equal(t, domain.DefaultTrashRetentionDays, settings.TrashRetentionDays)
+	assert.False(t, settings.Vacation.Enabled)
+	assert.Equal(t, domain.DefaultVacationDays, settings.Vacation.Days)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestFindSettingsByProfileId_Vacation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := New(db)
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{
+		"id", "profile_id", "notification_tolerance", "language", "theme", "signature",
+		"trash_retention_days",
+		"vacation_enabled", "vacation_start", "vacation_end", "vacation_subject",
+		"vacation_body", "vacation_contacts_only", "vacation_days",
+		"actual_profile_id",
+	}).AddRow(
+		1, 5, "0", "ru", "light", nil,
+		30,
+		true, start, nil, "В отпуске",
+		"Вернусь в августе", true, 3,
+		5,
+	)
+
+	mock.ExpectPrepare("s.vacation_enabled").ExpectQuery().
+		WithArgs(int64(10)).
+		WillReturnRows(rows)
+
+	settings, err := repo.FindSettingsByProfileId(testCtx, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.VacationSettings{
+		Enabled:      true,
+		Start:        start,
+		Subject:      "В отпуске",
+		Body:         "Вернусь в августе",
+		ContactsOnly: true,
+		Days:         3,
+	}, settings.Vacation)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateVacation(t *testing.T) {
+	end := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	vacation := domain.VacationSettings{Enabled: true, End: end, Subject: "OOO", Body: "Away", Days: 7}
+
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectPrepare("INSERT INTO settings").ExpectExec().
+			WithArgs(int64(10), true, sql.NullTime{}, sql.NullTime{Time: end, Valid: true}, "OOO", "Away", false, 7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err = New(db).UpdateVacation(testCtx, 10, vacation)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ProfileNotFound", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectPrepare("INSERT INTO settings").ExpectExec().
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err = New(db).UpdateVacation(testCtx, 10, vacation)
+
+		assert.True(t, errors.Is(err, commonE.ErrNotFound))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestInsertProfileAvatar_Success(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -250,3 +333,124 @@ func TestInsertProfileAvatar_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestCreateAppPassword(t *testing.T) {
+	createdAt := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery("INSERT INTO app_password").
+			WithArgs(int64(10), "Thunderbird", "hash", domain.MaxAppPasswords).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at"}).AddRow(3, "Thunderbird", createdAt))
+
+		appPassword, err := New(db).CreateAppPassword(testCtx, 10, "Thunderbird", "hash")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.AppPassword{ID: 3, Name: "Thunderbird", CreatedAt: createdAt}, appPassword)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("LimitReached", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery("INSERT INTO app_password").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at"}))
+
+		_, err = New(db).CreateAppPassword(testCtx, 10, "Thunderbird", "hash")
+
+		assert.True(t, errors.Is(err, domain.ErrTooManyAppPasswords))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestFindAppPasswords(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	createdAt := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	usedAt := time.Date(2025, 9, 2, 8, 30, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT ap.id, ap.name").
+		WithArgs(int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at", "last_used_at", "last_used_ip"}).
+			AddRow(4, "Phone", createdAt, usedAt, "203.0.113.7").
+			AddRow(3, "Laptop", createdAt, nil, nil))
+
+	appPasswords, err := New(db).FindAppPasswords(testCtx, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.AppPassword{
+		{ID: 4, Name: "Phone", CreatedAt: createdAt, LastUsedAt: usedAt, LastUsedIP: "203.0.113.7"},
+		{ID: 3, Name: "Laptop", CreatedAt: createdAt},
+	}, appPasswords)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteAppPassword(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec("DELETE FROM app_password").
+			WithArgs(int64(3), int64(10)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, New(db).DeleteAppPassword(testCtx, 10, 3))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec("DELETE FROM app_password").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err = New(db).DeleteAppPassword(testCtx, 10, 3)
+
+		assert.True(t, errors.Is(err, domain.ErrAppPasswordNotFound))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestFindAppPasswordHashes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT ap.id, bp.id, ap.password_hash").
+		WithArgs("user", "flintmail.ru").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "profile_id", "password_hash"}).
+			AddRow(3, 10, "hash1").
+			AddRow(4, 10, "hash2"))
+
+	hashes, err := New(db).FindAppPasswordHashes(testCtx, "user", "flintmail.ru")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.AppPasswordHash{
+		{ID: 3, ProfileID: 10, PasswordHash: "hash1"},
+		{ID: 4, ProfileID: 10, PasswordHash: "hash2"},
+	}, hashes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateAppPasswordUsage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	usedAt := time.Date(2025, 9, 2, 8, 30, 0, 0, time.UTC)
+	mock.ExpectExec("UPDATE app_password").
+		WithArgs(int64(3), usedAt, sql.NullString{String: "203.0.113.7", Valid: true}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, New(db).UpdateAppPasswordUsage(testCtx, 3, usedAt, "203.0.113.7"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
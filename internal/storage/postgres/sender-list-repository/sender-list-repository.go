@@ -0,0 +1,174 @@
+package sender_list_repository
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+)
+
+type SenderListRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *SenderListRepository {
+	return &SenderListRepository{db: db}
+}
+
+// MatchSender ищет запись списков, подходящую отправителю. Точный адрес важнее домена,
+// более длинный домен - важнее родительского. Пустой kind - отправителя нет в списках
+func (repo *SenderListRepository) MatchSender(ctx context.Context, profileID int64, address string) (domain.SenderListKind, domain.BlockedSenderAction, error) {
+	const op = "storage.postgresql.senderlist.MatchSender"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	address = strings.ToLower(address)
+	senderDomain := address[strings.LastIndex(address, "@")+1:]
+
+	const query = `
+        SELECT r.kind, COALESCE(s.blocked_sender_action, 'spam')
+        FROM sender_rule r
+        LEFT JOIN settings s ON s.profile_id = r.profile_id
+        WHERE r.profile_id = $1
+          AND (r.pattern = $2
+               OR r.pattern = $3
+               OR RIGHT($3, LENGTH(r.pattern) + 1) = '.' || r.pattern)
+        ORDER BY (r.pattern = $2) DESC, LENGTH(r.pattern) DESC
+        LIMIT 1`
+
+	var kind, action string
+	log.Debug("Matching sender...")
+	err := repo.db.QueryRowContext(ctx, query, profileID, address, senderDomain).Scan(&kind, &action)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", "", nil
+		}
+		return "", "", e.Wrap(op, err)
+	}
+
+	return domain.SenderListKind(kind), domain.BlockedSenderAction(action), nil
+}
+
+// GetRules возвращает записи обоих списков профиля
+func (repo *SenderListRepository) GetRules(ctx context.Context, profileID int64) ([]domain.SenderRule, error) {
+	const op = "storage.postgresql.senderlist.GetRules"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        SELECT id, kind, pattern, created_at
+        FROM sender_rule
+        WHERE profile_id = $1
+        ORDER BY pattern`
+
+	log.Debug("Querying sender rules...")
+	rows, err := repo.db.QueryContext(ctx, query, profileID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	rules := make([]domain.SenderRule, 0)
+	for rows.Next() {
+		var rule domain.SenderRule
+		if err := rows.Scan(&rule.ID, &rule.Kind, &rule.Pattern, &rule.CreatedAt); err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	return rules, nil
+}
+
+// SaveRule добавляет шаблон в список. Если шаблон уже есть в другом списке, он переносится
+func (repo *SenderListRepository) SaveRule(ctx context.Context, profileID int64, kind domain.SenderListKind, pattern string) (domain.SenderRule, error) {
+	const op = "storage.postgresql.senderlist.SaveRule"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        INSERT INTO sender_rule (profile_id, kind, pattern)
+        SELECT $1, $2, $3
+        WHERE (SELECT COUNT(*) FROM sender_rule WHERE profile_id = $1) < $4
+           OR EXISTS (SELECT 1 FROM sender_rule WHERE profile_id = $1 AND pattern = $3)
+        ON CONFLICT (profile_id, pattern)
+        DO UPDATE SET kind = EXCLUDED.kind
+        RETURNING id, kind, pattern, created_at`
+
+	var rule domain.SenderRule
+	log.Debug("Saving sender rule...")
+	err := repo.db.QueryRowContext(ctx, query, profileID, string(kind), pattern, domain.MaxSenderRules).Scan(
+		&rule.ID, &rule.Kind, &rule.Pattern, &rule.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.SenderRule{}, domain.ErrTooManySenderRules
+		}
+		return domain.SenderRule{}, e.Wrap(op, err)
+	}
+
+	return rule, nil
+}
+
+func (repo *SenderListRepository) DeleteRule(ctx context.Context, profileID, ruleID int64) error {
+	const op = "storage.postgresql.senderlist.DeleteRule"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `DELETE FROM sender_rule WHERE id = $1 AND profile_id = $2`
+
+	log.Debug("Deleting sender rule...")
+	result, err := repo.db.ExecContext(ctx, query, ruleID, profileID)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+	if affected == 0 {
+		return domain.ErrSenderRuleNotFound
+	}
+
+	return nil
+}
+
+// GetBlockedAction возвращает настройку обработки писем заблокированных отправителей
+func (repo *SenderListRepository) GetBlockedAction(ctx context.Context, profileID int64) (domain.BlockedSenderAction, error) {
+	const op = "storage.postgresql.senderlist.GetBlockedAction"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `SELECT blocked_sender_action FROM settings WHERE profile_id = $1`
+
+	var action string
+	log.Debug("Querying blocked sender action...")
+	if err := repo.db.QueryRowContext(ctx, query, profileID).Scan(&action); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.BlockedToSpam, nil
+		}
+		return "", e.Wrap(op, err)
+	}
+
+	return domain.BlockedSenderAction(action), nil
+}
+
+func (repo *SenderListRepository) SetBlockedAction(ctx context.Context, profileID int64, action domain.BlockedSenderAction) error {
+	const op = "storage.postgresql.senderlist.SetBlockedAction"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        INSERT INTO settings (profile_id, blocked_sender_action)
+        VALUES ($1, $2)
+        ON CONFLICT (profile_id)
+        DO UPDATE SET blocked_sender_action = EXCLUDED.blocked_sender_action`
+
+	log.Debug("Saving blocked sender action...")
+	if _, err := repo.db.ExecContext(ctx, query, profileID, string(action)); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package sender_list_repository
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTest(t *testing.T) (context.Context, *SenderListRepository, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return context.Background(), New(db), mock
+}
+
+func quote(query string) string {
+	return regexp.QuoteMeta(query)
+}
+
+func TestSenderListRepository_MatchSender(t *testing.T) {
+	t.Run("Blocked", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`FROM sender_rule r`)).
+			WithArgs(int64(1), "spammer@mail.example.com", "mail.example.com").
+			WillReturnRows(sqlmock.NewRows([]string{"kind", "action"}).AddRow("block", "discard"))
+
+		kind, action, err := repo.MatchSender(ctx, 1, "Spammer@Mail.Example.com")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.SenderBlocked, kind)
+		assert.Equal(t, domain.BlockedDiscard, action)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotListed", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`FROM sender_rule r`)).
+			WillReturnError(sql.ErrNoRows)
+
+		kind, _, err := repo.MatchSender(ctx, 1, "friend@a4mail.ru")
+
+		assert.NoError(t, err)
+		assert.Empty(t, kind)
+	})
+
+	t.Run("DBError", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`FROM sender_rule r`)).
+			WillReturnError(errors.New("db down"))
+
+		_, _, err := repo.MatchSender(ctx, 1, "friend@a4mail.ru")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestSenderListRepository_GetRules(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	createdAt := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(quote(`SELECT id, kind, pattern, created_at`)).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "kind", "pattern", "created_at"}).
+			AddRow(1, "allow", "boss@a4mail.ru", createdAt).
+			AddRow(2, "block", "spam.com", createdAt))
+
+	rules, err := repo.GetRules(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.SenderRule{
+		{ID: 1, Kind: domain.SenderAllowed, Pattern: "boss@a4mail.ru", CreatedAt: createdAt},
+		{ID: 2, Kind: domain.SenderBlocked, Pattern: "spam.com", CreatedAt: createdAt},
+	}, rules)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSenderListRepository_SaveRule(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`INSERT INTO sender_rule`)).
+			WithArgs(int64(1), "block", "spam.com", domain.MaxSenderRules).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "kind", "pattern", "created_at"}).
+				AddRow(3, "block", "spam.com", time.Now()))
+
+		rule, err := repo.SaveRule(ctx, 1, domain.SenderBlocked, "spam.com")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), rule.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("LimitReached", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`INSERT INTO sender_rule`)).
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := repo.SaveRule(ctx, 1, domain.SenderBlocked, "spam.com")
+
+		assert.ErrorIs(t, err, domain.ErrTooManySenderRules)
+	})
+}
+
+func TestSenderListRepository_DeleteRule(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectExec(quote(`DELETE FROM sender_rule`)).
+			WithArgs(int64(3), int64(1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.DeleteRule(ctx, 1, 3))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectExec(quote(`DELETE FROM sender_rule`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIs(t, repo.DeleteRule(ctx, 1, 3), domain.ErrSenderRuleNotFound)
+	})
+}
+
+func TestSenderListRepository_BlockedAction(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`SELECT blocked_sender_action FROM settings`)).
+			WithArgs(int64(1)).
+			WillReturnError(sql.ErrNoRows)
+
+		action, err := repo.GetBlockedAction(ctx, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.BlockedToSpam, action)
+	})
+
+	t.Run("Set", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectExec(quote(`INSERT INTO settings (profile_id, blocked_sender_action)`)).
+			WithArgs(int64(1), "discard").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.SetBlockedAction(ctx, 1, domain.BlockedDiscard))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
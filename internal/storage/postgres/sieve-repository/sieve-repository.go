@@ -0,0 +1,104 @@
+package sieve_repository
+
+import (
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+type SieveRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *SieveRepository {
+	return &SieveRepository{db: db}
+}
+
+// GetScript возвращает sieve-скрипт профиля, пустую строку - если скрипт не задан
+func (repo *SieveRepository) GetScript(ctx context.Context, profileID int64) (string, error) {
+	const op = "storage.postgresql.sieve.GetScript"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        SELECT script
+        FROM sieve_script
+        WHERE profile_id = $1`
+
+	var script string
+	log.Debug("Querying sieve script...")
+	err := repo.db.QueryRowContext(ctx, query, profileID).Scan(&script)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", e.Wrap(op, err)
+	}
+
+	return script, nil
+}
+
+func (repo *SieveRepository) SaveScript(ctx context.Context, profileID int64, script string) error {
+	const op = "storage.postgresql.sieve.SaveScript"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        INSERT INTO sieve_script (profile_id, script)
+        VALUES ($1, $2)
+        ON CONFLICT (profile_id)
+        DO UPDATE SET script = EXCLUDED.script`
+
+	log.Debug("Saving sieve script...")
+	_, err := repo.db.ExecContext(ctx, query, profileID, script)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
+
+// LastVacationResponse возвращает время последнего автоответа отправителю,
+// нулевое время - если автоответ не отправлялся
+func (repo *SieveRepository) LastVacationResponse(ctx context.Context, profileID int64, sender, handle string) (time.Time, error) {
+	const op = "storage.postgresql.sieve.LastVacationResponse"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        SELECT last_sent_at
+        FROM vacation_response
+        WHERE profile_id = $1 AND sender = LOWER($2) AND handle = $3`
+
+	var lastSentAt time.Time
+	log.Debug("Querying last vacation response...")
+	err := repo.db.QueryRowContext(ctx, query, profileID, sender, handle).Scan(&lastSentAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, e.Wrap(op, err)
+	}
+
+	return lastSentAt, nil
+}
+
+func (repo *SieveRepository) SaveVacationResponse(ctx context.Context, profileID int64, sender, handle string, sentAt time.Time) error {
+	const op = "storage.postgresql.sieve.SaveVacationResponse"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        INSERT INTO vacation_response (profile_id, sender, handle, last_sent_at)
+        VALUES ($1, LOWER($2), $3, $4)
+        ON CONFLICT (profile_id, sender, handle)
+        DO UPDATE SET last_sent_at = EXCLUDED.last_sent_at`
+
+	log.Debug("Saving vacation response...")
+	_, err := repo.db.ExecContext(ctx, query, profileID, sender, handle, sentAt)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
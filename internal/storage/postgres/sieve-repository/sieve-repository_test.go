@@ -0,0 +1,106 @@
+package sieve_repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTest(t *testing.T) (context.Context, *SieveRepository, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return context.Background(), New(db), mock
+}
+
+func quote(query string) string {
+	return regexp.QuoteMeta(query)
+}
+
+func TestSieveRepository_GetScript(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+
+	mock.ExpectQuery(quote(`SELECT script`)).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"script"}).AddRow("keep;"))
+
+	script, err := repo.GetScript(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "keep;", script)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSieveRepository_GetScript_NotFound(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+
+	mock.ExpectQuery(quote(`SELECT script`)).
+		WithArgs(int64(1)).
+		WillReturnError(sql.ErrNoRows)
+
+	script, err := repo.GetScript(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Empty(t, script)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSieveRepository_SaveScript(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+
+	mock.ExpectExec(quote(`INSERT INTO sieve_script (profile_id, script)`)).
+		WithArgs(int64(1), "discard;").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err := repo.SaveScript(ctx, 1, "discard;")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSieveRepository_SaveScript_Error(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+
+	mock.ExpectExec(quote(`INSERT INTO sieve_script (profile_id, script)`)).
+		WillReturnError(errors.New("db error"))
+
+	err := repo.SaveScript(ctx, 1, "discard;")
+
+	assert.Error(t, err)
+}
+
+func TestSieveRepository_VacationResponse(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	sentAt := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(quote(`SELECT last_sent_at`)).
+		WithArgs(int64(1), "boss@example.com", "").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectExec(quote(`INSERT INTO vacation_response (profile_id, sender, handle, last_sent_at)`)).
+		WithArgs(int64(1), "boss@example.com", "", sentAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(quote(`SELECT last_sent_at`)).
+		WithArgs(int64(1), "boss@example.com", "").
+		WillReturnRows(sqlmock.NewRows([]string{"last_sent_at"}).AddRow(sentAt))
+
+	last, err := repo.LastVacationResponse(ctx, 1, "boss@example.com", "")
+	assert.NoError(t, err)
+	assert.True(t, last.IsZero())
+
+	err = repo.SaveVacationResponse(ctx, 1, "boss@example.com", "", sentAt)
+	assert.NoError(t, err)
+
+	last, err = repo.LastVacationResponse(ctx, 1, "boss@example.com", "")
+	assert.NoError(t, err)
+	assert.Equal(t, sentAt, last)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,250 @@
+package spam_repository
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	"2025_2_a4code/internal/lib/bayes"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+)
+
+// globalProfileID - идентификатор глобальной статистики спам-фильтра
+const globalProfileID = 0
+
+const (
+	labelSpam = "spam"
+	labelHam  = "ham"
+)
+
+type SpamRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *SpamRepository {
+	return &SpamRepository{db: db}
+}
+
+// GetStats возвращает статистику пользователя и глобальную статистику по указанным токенам
+func (repo *SpamRepository) GetStats(ctx context.Context, profileID int64, tokens []string) (user bayes.Stats, global bayes.Stats, err error) {
+	const op = "storage.postgresql.spam.GetStats"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	user.Tokens = make(map[string]bayes.TokenCounts)
+	global.Tokens = make(map[string]bayes.TokenCounts)
+	pick := func(id int64) *bayes.Stats {
+		if id == globalProfileID {
+			return &global
+		}
+		return &user
+	}
+
+	const statsQuery = `
+        SELECT profile_id, spam_messages, ham_messages
+        FROM spam_stats
+        WHERE profile_id IN ($1, $2)`
+
+	log.Debug("Querying spam stats...")
+	rows, err := repo.db.QueryContext(ctx, statsQuery, globalProfileID, profileID)
+	if err != nil {
+		return bayes.Stats{}, bayes.Stats{}, e.Wrap(op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var stats bayes.Stats
+		if err := rows.Scan(&id, &stats.SpamMessages, &stats.HamMessages); err != nil {
+			return bayes.Stats{}, bayes.Stats{}, e.Wrap(op, err)
+		}
+		target := pick(id)
+		target.SpamMessages, target.HamMessages = stats.SpamMessages, stats.HamMessages
+	}
+	if err := rows.Err(); err != nil {
+		return bayes.Stats{}, bayes.Stats{}, e.Wrap(op, err)
+	}
+
+	if len(tokens) == 0 {
+		return user, global, nil
+	}
+
+	const tokensQuery = `
+        SELECT profile_id, token, spam_count, ham_count
+        FROM spam_token
+        WHERE profile_id IN ($1, $2) AND token = ANY($3)`
+
+	log.Debug("Querying spam tokens...")
+	tokenRows, err := repo.db.QueryContext(ctx, tokensQuery, globalProfileID, profileID, tokens)
+	if err != nil {
+		return bayes.Stats{}, bayes.Stats{}, e.Wrap(op, err)
+	}
+	defer tokenRows.Close()
+
+	for tokenRows.Next() {
+		var id int64
+		var token string
+		var counts bayes.TokenCounts
+		if err := tokenRows.Scan(&id, &token, &counts.Spam, &counts.Ham); err != nil {
+			return bayes.Stats{}, bayes.Stats{}, e.Wrap(op, err)
+		}
+		pick(id).Tokens[token] = counts
+	}
+	if err := tokenRows.Err(); err != nil {
+		return bayes.Stats{}, bayes.Stats{}, e.Wrap(op, err)
+	}
+
+	return user, global, nil
+}
+
+// GetProfileID возвращает идентификатор профиля, под которым хранятся письма и спам-статистика
+// пользователя, по идентификатору базового профиля из токена
+func (repo *SpamRepository) GetProfileID(ctx context.Context, baseProfileID int64) (int64, error) {
+	const op = "storage.postgresql.spam.GetProfileID"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	var profileID int64
+	log.Debug("Resolving profile id...")
+	err := repo.db.QueryRowContext(ctx, `
+        SELECT id FROM profile WHERE base_profile_id = $1`,
+		baseProfileID).Scan(&profileID)
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	return profileID, nil
+}
+
+// GetMessageContent возвращает тему, текст и домен отправителя письма пользователя для обучения
+func (repo *SpamRepository) GetMessageContent(ctx context.Context, profileID, messageID int64) (topic, text, senderDomain string, err error) {
+	const op = "storage.postgresql.spam.GetMessageContent"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        SELECT m.topic, m.text, COALESCE(m.sender_domain, bp.domain)
+        FROM message m
+        JOIN profile_message pm ON pm.message_id = m.id AND pm.profile_id = $1
+        LEFT JOIN base_profile bp ON m.sender_base_profile_id = bp.id
+        WHERE m.id = $2`
+
+	log.Debug("Querying message content...")
+	err = repo.db.QueryRowContext(ctx, query, profileID, messageID).Scan(&topic, &text, &senderDomain)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", "", "", e.Wrap(op, domain.ErrMessageNotFound)
+	}
+	if err != nil {
+		return "", "", "", e.Wrap(op, err)
+	}
+
+	return topic, text, senderDomain, nil
+}
+
+// Train обучает фильтр пользователя и глобальный фильтр на письме.
+// Повторная отметка тем же классом игнорируется, смена класса отменяет прошлое обучение.
+func (repo *SpamRepository) Train(ctx context.Context, profileID, messageID int64, tokens []string, spam bool) error {
+	const op = "storage.postgresql.spam.Train"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return e.Wrap(op+": failed to begin transaction: ", err)
+	}
+	defer tx.Rollback()
+
+	var previous sql.NullString
+	log.Debug("Getting previous spam label...")
+	err = tx.QueryRowContext(ctx, `
+        SELECT spam_label FROM profile_message
+        WHERE profile_id = $1 AND message_id = $2
+        FOR UPDATE`,
+		profileID, messageID).Scan(&previous)
+	if errors.Is(err, sql.ErrNoRows) {
+		return e.Wrap(op, domain.ErrMessageNotFound)
+	}
+	if err != nil {
+		return e.Wrap(op+": failed to get spam label: ", err)
+	}
+
+	label := labelHam
+	if spam {
+		label = labelSpam
+	}
+	if previous.String == label {
+		log.Debug("Message is already trained with the same label")
+		return nil
+	}
+
+	var spamDelta, hamDelta int64
+	if spam {
+		spamDelta = 1
+	} else {
+		hamDelta = 1
+	}
+	switch previous.String {
+	case labelSpam:
+		spamDelta--
+	case labelHam:
+		hamDelta--
+	}
+
+	const updateStats = `
+        INSERT INTO spam_stats (profile_id, spam_messages, ham_messages)
+        VALUES ($1, GREATEST($2, 0), GREATEST($3, 0))
+        ON CONFLICT (profile_id) DO UPDATE SET
+            spam_messages = GREATEST(spam_stats.spam_messages + $2, 0),
+            ham_messages = GREATEST(spam_stats.ham_messages + $3, 0)`
+
+	const updateTokens = `
+        INSERT INTO spam_token (profile_id, token, spam_count, ham_count)
+        SELECT $1, t, GREATEST($3, 0), GREATEST($4, 0)
+        FROM unnest($2::text[]) AS t
+        ON CONFLICT (profile_id, token) DO UPDATE SET
+            spam_count = GREATEST(spam_token.spam_count + $3, 0),
+            ham_count = GREATEST(spam_token.ham_count + $4, 0)`
+
+	for _, id := range []int64{profileID, globalProfileID} {
+		log.Debug("Updating spam stats...", slog.Int64("stats_profile_id", id))
+		if _, err := tx.ExecContext(ctx, updateStats, id, spamDelta, hamDelta); err != nil {
+			return e.Wrap(op+": failed to update spam stats: ", err)
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, updateTokens, id, tokens, spamDelta, hamDelta); err != nil {
+			return e.Wrap(op+": failed to update spam tokens: ", err)
+		}
+	}
+
+	log.Debug("Saving spam label...")
+	_, err = tx.ExecContext(ctx, `
+        UPDATE profile_message SET spam_label = $3
+        WHERE profile_id = $1 AND message_id = $2`,
+		profileID, messageID, label)
+	if err != nil {
+		return e.Wrap(op+": failed to save spam label: ", err)
+	}
+
+	log.Debug("Committing transaction...")
+	return tx.Commit()
+}
+
+// SaveScore сохраняет оценку спам-фильтра для письма пользователя
+func (repo *SpamRepository) SaveScore(ctx context.Context, profileID, messageID int64, score float64, reasons []string) error {
+	const op = "storage.postgresql.spam.SaveScore"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        UPDATE profile_message
+        SET spam_score = $3, spam_reasons = $4
+        WHERE profile_id = $1 AND message_id = $2`
+
+	log.Debug("Saving spam score...")
+	_, err := repo.db.ExecContext(ctx, query, profileID, messageID, score, strings.Join(reasons, "\n"))
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,204 @@
+package spam_repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/lib/bayes"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// arrayConverter пропускает срезы строк, которые pgx передает как text[]
+type arrayConverter struct{}
+
+func (arrayConverter) ConvertValue(v interface{}) (driver.Value, error) {
+	if tokens, ok := v.([]string); ok {
+		return tokens, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(v)
+}
+
+func setupTest(t *testing.T) (context.Context, *SpamRepository, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(arrayConverter{}))
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return context.Background(), New(db), mock
+}
+
+func quote(query string) string {
+	return regexp.QuoteMeta(query)
+}
+
+func TestSpamRepository_GetStats(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	tokens := []string{"viagra", "subject:free"}
+
+	mock.ExpectQuery(quote(`FROM spam_stats`)).
+		WithArgs(globalProfileID, int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"profile_id", "spam_messages", "ham_messages"}).
+			AddRow(0, 100, 200).
+			AddRow(7, 3, 5))
+	mock.ExpectQuery(quote(`FROM spam_token`)).
+		WithArgs(globalProfileID, int64(7), tokens).
+		WillReturnRows(sqlmock.NewRows([]string{"profile_id", "token", "spam_count", "ham_count"}).
+			AddRow(0, "viagra", 50, 1).
+			AddRow(7, "subject:free", 2, 0))
+
+	user, global, err := repo.GetStats(ctx, 7, tokens)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), user.SpamMessages)
+	assert.Equal(t, int64(5), user.HamMessages)
+	assert.Equal(t, bayes.TokenCounts{Spam: 2}, user.Tokens["subject:free"])
+	assert.Equal(t, int64(100), global.SpamMessages)
+	assert.Equal(t, bayes.TokenCounts{Spam: 50, Ham: 1}, global.Tokens["viagra"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSpamRepository_GetStats_Error(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+
+	mock.ExpectQuery(quote(`FROM spam_stats`)).
+		WillReturnError(errors.New("db error"))
+
+	_, _, err := repo.GetStats(ctx, 7, []string{"a"})
+
+	assert.Error(t, err)
+}
+
+func TestSpamRepository_Train(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	tokens := []string{"viagra"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(quote(`SELECT spam_label FROM profile_message`)).
+		WithArgs(int64(7), int64(42)).
+		WillReturnRows(sqlmock.NewRows([]string{"spam_label"}).AddRow("ham"))
+	for _, id := range []int64{7, globalProfileID} {
+		mock.ExpectExec(quote(`INSERT INTO spam_stats`)).
+			WithArgs(id, int64(1), int64(-1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(quote(`INSERT INTO spam_token`)).
+			WithArgs(id, tokens, int64(1), int64(-1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectExec(quote(`UPDATE profile_message SET spam_label`)).
+		WithArgs(int64(7), int64(42), "spam").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.Train(ctx, 7, 42, tokens, true)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSpamRepository_Train_SameLabel(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(quote(`SELECT spam_label FROM profile_message`)).
+		WithArgs(int64(7), int64(42)).
+		WillReturnRows(sqlmock.NewRows([]string{"spam_label"}).AddRow("spam"))
+	mock.ExpectRollback()
+
+	err := repo.Train(ctx, 7, 42, []string{"viagra"}, true)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSpamRepository_Train_NotFound(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(quote(`SELECT spam_label FROM profile_message`)).
+		WithArgs(int64(7), int64(42)).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	err := repo.Train(ctx, 7, 42, []string{"viagra"}, true)
+
+	assert.ErrorIs(t, err, domain.ErrMessageNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSpamRepository_Train_Error(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(quote(`SELECT spam_label FROM profile_message`)).
+		WillReturnRows(sqlmock.NewRows([]string{"spam_label"}).AddRow(nil))
+	mock.ExpectExec(quote(`INSERT INTO spam_stats`)).
+		WillReturnError(errors.New("db error"))
+	mock.ExpectRollback()
+
+	err := repo.Train(ctx, 7, 42, nil, false)
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSpamRepository_SaveScore(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+
+	mock.ExpectExec(quote(`UPDATE profile_message`)).
+		WithArgs(int64(7), int64(42), 0.97, "viagra (0.99)\nfree (0.95)").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.SaveScore(ctx, 7, 42, 0.97, []string{"viagra (0.99)", "free (0.95)"})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSpamRepository_GetProfileID(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+
+	mock.ExpectQuery(quote(`SELECT id FROM profile WHERE base_profile_id = $1`)).
+		WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(7)))
+
+	profileID, err := repo.GetProfileID(ctx, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), profileID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSpamRepository_GetMessageContent(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+
+	mock.ExpectQuery(quote(`SELECT m.topic, m.text, COALESCE(m.sender_domain, bp.domain)`)).
+		WithArgs(int64(7), int64(42)).
+		WillReturnRows(sqlmock.NewRows([]string{"topic", "text", "domain"}).AddRow("Hi", "Body", "example.com"))
+
+	topic, text, senderDomain, err := repo.GetMessageContent(ctx, 7, 42)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Hi", topic)
+	assert.Equal(t, "Body", text)
+	assert.Equal(t, "example.com", senderDomain)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSpamRepository_GetMessageContent_NotFound(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+
+	mock.ExpectQuery(quote(`SELECT m.topic, m.text, COALESCE(m.sender_domain, bp.domain)`)).
+		WillReturnError(sql.ErrNoRows)
+
+	_, _, _, err := repo.GetMessageContent(ctx, 7, 42)
+
+	assert.ErrorIs(t, err, domain.ErrMessageNotFound)
+}
@@ -0,0 +1,117 @@
+package vacation_repository
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// responseHandle отделяет автоответы из настроек от sieve-автоответов в таблице vacation_response
+const responseHandle = "settings"
+
+type VacationRepository struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *VacationRepository {
+	return &VacationRepository{db: db}
+}
+
+// GetVacation возвращает настройки автоответа профиля, выключенный автоответ - если настроек нет
+func (repo *VacationRepository) GetVacation(ctx context.Context, profileID int64) (domain.VacationSettings, error) {
+	const op = "storage.postgresql.vacation.GetVacation"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        SELECT vacation_enabled, vacation_start, vacation_end, vacation_subject,
+               vacation_body, vacation_contacts_only, vacation_days
+        FROM settings
+        WHERE profile_id = $1`
+
+	var vacation domain.VacationSettings
+	var start, end sql.NullTime
+	log.Debug("Querying vacation settings...")
+	err := repo.db.QueryRowContext(ctx, query, profileID).Scan(
+		&vacation.Enabled, &start, &end, &vacation.Subject,
+		&vacation.Body, &vacation.ContactsOnly, &vacation.Days,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.VacationSettings{Days: domain.DefaultVacationDays}, nil
+		}
+		return domain.VacationSettings{}, e.Wrap(op, err)
+	}
+
+	vacation.Start = start.Time
+	vacation.End = end.Time
+	return vacation, nil
+}
+
+// IsContact сообщает, сохранен ли адрес address в адресной книге пользователя
+func (repo *VacationRepository) IsContact(ctx context.Context, baseProfileID int64, address string) (bool, error) {
+	const op = "storage.postgresql.vacation.IsContact"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        SELECT EXISTS (
+            SELECT 1
+            FROM contact_email ce
+            JOIN profile p ON p.id = ce.profile_id
+            WHERE p.base_profile_id = $1 AND ce.email = LOWER($2)
+        )`
+
+	var exists bool
+	log.Debug("Checking known contact...")
+	if err := repo.db.QueryRowContext(ctx, query, baseProfileID, address).Scan(&exists); err != nil {
+		return false, e.Wrap(op, err)
+	}
+
+	return exists, nil
+}
+
+// LastResponse возвращает время последнего автоответа отправителю,
+// нулевое время - если автоответ не отправлялся
+func (repo *VacationRepository) LastResponse(ctx context.Context, profileID int64, sender string) (time.Time, error) {
+	const op = "storage.postgresql.vacation.LastResponse"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        SELECT last_sent_at
+        FROM vacation_response
+        WHERE profile_id = $1 AND sender = LOWER($2) AND handle = $3`
+
+	var lastSentAt time.Time
+	log.Debug("Querying last vacation response...")
+	err := repo.db.QueryRowContext(ctx, query, profileID, sender, responseHandle).Scan(&lastSentAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, e.Wrap(op, err)
+	}
+
+	return lastSentAt, nil
+}
+
+func (repo *VacationRepository) SaveResponse(ctx context.Context, profileID int64, sender string, sentAt time.Time) error {
+	const op = "storage.postgresql.vacation.SaveResponse"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	const query = `
+        INSERT INTO vacation_response (profile_id, sender, handle, last_sent_at)
+        VALUES ($1, LOWER($2), $3, $4)
+        ON CONFLICT (profile_id, sender, handle)
+        DO UPDATE SET last_sent_at = EXCLUDED.last_sent_at`
+
+	log.Debug("Saving vacation response...")
+	if _, err := repo.db.ExecContext(ctx, query, profileID, sender, responseHandle, sentAt); err != nil {
+		return e.Wrap(op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package vacation_repository
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTest(t *testing.T) (context.Context, *VacationRepository, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return context.Background(), New(db), mock
+}
+
+func quote(query string) string {
+	return regexp.QuoteMeta(query)
+}
+
+func TestVacationRepository_GetVacation(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		end := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(quote(`FROM settings`)).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{
+				"vacation_enabled", "vacation_start", "vacation_end", "vacation_subject",
+				"vacation_body", "vacation_contacts_only", "vacation_days",
+			}).AddRow(true, nil, end, "OOO", "Away", true, 3))
+
+		vacation, err := repo.GetVacation(ctx, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.VacationSettings{
+			Enabled: true, End: end, Subject: "OOO", Body: "Away", ContactsOnly: true, Days: 3,
+		}, vacation)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("NotConfigured", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`FROM settings`)).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows([]string{"vacation_enabled"}))
+
+		vacation, err := repo.GetVacation(ctx, 1)
+
+		assert.NoError(t, err)
+		assert.False(t, vacation.Enabled)
+		assert.Equal(t, domain.DefaultVacationDays, vacation.Days)
+	})
+}
+
+func TestVacationRepository_IsContact(t *testing.T) {
+	ctx, repo, mock := setupTest(t)
+	mock.ExpectQuery(`SELECT EXISTS \( SELECT 1 FROM contact_email ce JOIN profile p ON p.id = ce.profile_id WHERE p.base_profile_id = \$1 AND ce.email = LOWER\(\$2\) \)`).
+		WithArgs(int64(7), "friend@a4mail.ru").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	known, err := repo.IsContact(ctx, 7, "friend@a4mail.ru")
+
+	assert.NoError(t, err)
+	assert.True(t, known)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestVacationRepository_Responses(t *testing.T) {
+	sentAt := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("LastResponse", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`FROM vacation_response`)).
+			WithArgs(int64(1), "Friend@a4mail.ru", responseHandle).
+			WillReturnRows(sqlmock.NewRows([]string{"last_sent_at"}).AddRow(sentAt))
+
+		last, err := repo.LastResponse(ctx, 1, "Friend@a4mail.ru")
+
+		assert.NoError(t, err)
+		assert.Equal(t, sentAt, last)
+	})
+
+	t.Run("LastResponseNever", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectQuery(quote(`FROM vacation_response`)).
+			WillReturnRows(sqlmock.NewRows([]string{"last_sent_at"}))
+
+		last, err := repo.LastResponse(ctx, 1, "friend@a4mail.ru")
+
+		assert.NoError(t, err)
+		assert.True(t, last.IsZero())
+	})
+
+	t.Run("SaveResponse", func(t *testing.T) {
+		ctx, repo, mock := setupTest(t)
+		mock.ExpectExec(quote(`INSERT INTO vacation_response`)).
+			WithArgs(int64(1), "friend@a4mail.ru", responseHandle, sentAt).
+			WillReturnError(errors.New("db error"))
+
+		err := repo.SaveResponse(ctx, 1, "friend@a4mail.ru", sentAt)
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,44 @@
+package alias
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// usernamePattern - имя адреса до @: латиница, цифры, точка, дефис и подчеркивание,
+// точка и дефис не в начале и не в конце
+var usernamePattern = regexp.MustCompile(`^[a-z0-9_]([a-z0-9._-]{0,48}[a-z0-9_])?$`)
+
+type AliasRepository interface {
+	GetAliases(ctx context.Context, profileID int64) ([]domain.Alias, error)
+	SaveAlias(ctx context.Context, profileID int64, username string) (domain.Alias, error)
+	DeleteAlias(ctx context.Context, profileID, aliasID int64) error
+}
+
+type AliasUcase struct {
+	repo AliasRepository
+}
+
+func New(repo AliasRepository) *AliasUcase {
+	return &AliasUcase{repo: repo}
+}
+
+func (uc *AliasUcase) GetAliases(ctx context.Context, profileID int64) ([]domain.Alias, error) {
+	return uc.repo.GetAliases(ctx, profileID)
+}
+
+// AddAlias добавляет профилю дополнительный адрес на домене основного адреса
+func (uc *AliasUcase) AddAlias(ctx context.Context, profileID int64, username string) (domain.Alias, error) {
+	username = strings.ToLower(strings.TrimSpace(username))
+	if !usernamePattern.MatchString(username) || strings.Contains(username, "..") {
+		return domain.Alias{}, fmt.Errorf("%w: invalid username %q", domain.ErrInvalidAlias, username)
+	}
+	return uc.repo.SaveAlias(ctx, profileID, username)
+}
+
+func (uc *AliasUcase) DeleteAlias(ctx context.Context, profileID, aliasID int64) error {
+	return uc.repo.DeleteAlias(ctx, profileID, aliasID)
+}
@@ -0,0 +1,57 @@
+package alias
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"testing"
+)
+
+type mockAliasRepository struct {
+	savedUsername string
+}
+
+func (m *mockAliasRepository) GetAliases(ctx context.Context, profileID int64) ([]domain.Alias, error) {
+	return nil, nil
+}
+
+func (m *mockAliasRepository) SaveAlias(ctx context.Context, profileID int64, username string) (domain.Alias, error) {
+	m.savedUsername = username
+	return domain.Alias{ID: 1, Address: username + "@flintmail.ru"}, nil
+}
+
+func (m *mockAliasRepository) DeleteAlias(ctx context.Context, profileID, aliasID int64) error {
+	return nil
+}
+
+func TestAliasUcase_AddAlias(t *testing.T) {
+	tests := []struct {
+		name         string
+		username     string
+		wantUsername string
+		wantErr      error
+	}{
+		{name: "Simple", username: " Sales ", wantUsername: "sales"},
+		{name: "With dot", username: "ivan.petrov", wantUsername: "ivan.petrov"},
+		{name: "Empty", username: "  ", wantErr: domain.ErrInvalidAlias},
+		{name: "Full address", username: "sales@flintmail.ru", wantErr: domain.ErrInvalidAlias},
+		{name: "Leading dot", username: ".sales", wantErr: domain.ErrInvalidAlias},
+		{name: "Double dot", username: "ivan..petrov", wantErr: domain.ErrInvalidAlias},
+		{name: "Too long", username: "abcdefghijabcdefghijabcdefghijabcdefghijabcdefghijk", wantErr: domain.ErrInvalidAlias},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockAliasRepository{}
+
+			_, err := New(repo).AddAlias(context.Background(), 1, tt.username)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AddAlias() error = %v, want %v", err, tt.wantErr)
+			}
+			if repo.savedUsername != tt.wantUsername {
+				t.Errorf("AddAlias() saved %q, want %q", repo.savedUsername, tt.wantUsername)
+			}
+		})
+	}
+}
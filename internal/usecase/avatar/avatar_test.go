@@ -74,6 +74,24 @@ func (m *MockProfileRepository) InsertProfileAvatar(ctx context.Context, profile
 func (m *MockProfileRepository) UpdateProfileInfo(ctx context.Context, profileID int64, info domain.ProfileUpdate) error {
 	return nil
 }
+func (m *MockProfileRepository) UpdateVacation(ctx context.Context, profileID int64, vacation domain.VacationSettings) error {
+	return nil
+}
+func (m *MockProfileRepository) CreateAppPassword(ctx context.Context, profileID int64, name, passwordHash string) (domain.AppPassword, error) {
+	return domain.AppPassword{}, nil
+}
+func (m *MockProfileRepository) FindAppPasswords(ctx context.Context, profileID int64) ([]domain.AppPassword, error) {
+	return nil, nil
+}
+func (m *MockProfileRepository) DeleteAppPassword(ctx context.Context, profileID, appPasswordID int64) error {
+	return nil
+}
+func (m *MockProfileRepository) FindAppPasswordHashes(ctx context.Context, username string, domain string) ([]domain.AppPasswordHash, error) {
+	return nil, nil
+}
+func (m *MockProfileRepository) UpdateAppPasswordUsage(ctx context.Context, appPasswordID int64, usedAt time.Time, ip string) error {
+	return nil
+}
 
 type dummyReader struct{}
 
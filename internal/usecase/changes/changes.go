@@ -0,0 +1,176 @@
+package changes
+
+import (
+	"2025_2_a4code/internal/domain"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"fmt"
+	"strconv"
+)
+
+const (
+	// DefaultMaxChanges - сколько изменений отдается за один запрос, если клиент не ограничил сам
+	DefaultMaxChanges = 256
+	// MaxChanges - больше изменений за один запрос не отдается
+	MaxChanges = 1024
+	// changesPage - по сколько записей журнала читается за раз
+	changesPage = 1000
+)
+
+type ChangeRepository interface {
+	State(ctx context.Context, profileID int64) (domain.MailState, error)
+	FindChanges(ctx context.Context, profileID int64, objectType domain.MailObjectType, since int64, limit int) ([]domain.MailChange, error)
+	FindThreadMessages(ctx context.Context, profileID int64, keys []string) (map[string][]int64, error)
+}
+
+type ChangesUcase struct {
+	repo ChangeRepository
+}
+
+func New(repo ChangeRepository) *ChangesUcase {
+	return &ChangesUcase{repo: repo}
+}
+
+func (uc *ChangesUcase) State(ctx context.Context, profileID int64) (domain.MailState, error) {
+	return uc.repo.State(ctx, profileID)
+}
+
+// Threads возвращает письма цепочек, старые письма первыми
+func (uc *ChangesUcase) Threads(ctx context.Context, profileID int64, keys []string) (map[string][]int64, error) {
+	return uc.repo.FindThreadMessages(ctx, profileID, keys)
+}
+
+// objectChange - первое и последнее изменение объекта после исходного состояния
+type objectChange struct {
+	first, last domain.MailChangeType
+}
+
+// Changes возвращает объекты, измененные после состояния since. Несколько записей об одном
+// объекте сводятся в одно изменение: созданный и удаленный после since объект не попадает
+// в ответ, созданный и измененный считается созданным
+func (uc *ChangesUcase) Changes(ctx context.Context, profileID int64, objectType domain.MailObjectType, since int64, maxChanges int) (domain.MailChanges, error) {
+	const op = "usecase.changes.Changes"
+
+	if objectType != domain.MailObjectMailbox && objectType != domain.MailObjectEmail && objectType != domain.MailObjectThread {
+		return domain.MailChanges{}, fmt.Errorf("unknown object type %q", objectType)
+	}
+	if maxChanges <= 0 {
+		maxChanges = DefaultMaxChanges
+	}
+	maxChanges = min(maxChanges, MaxChanges)
+
+	state, err := uc.repo.State(ctx, profileID)
+	if err != nil {
+		return domain.MailChanges{}, e.Wrap(op, err)
+	}
+	current := state.Email
+	if objectType == domain.MailObjectMailbox {
+		current = state.Mailbox
+	}
+	// Состояние из будущего выдано другим типам данных или до пересоздания базы
+	if since < 0 || since > current {
+		return domain.MailChanges{}, domain.ErrInvalidMailState
+	}
+
+	logType := objectType
+	if objectType == domain.MailObjectThread {
+		logType = domain.MailObjectEmail
+	}
+
+	result := domain.MailChanges{OldState: since, NewState: since}
+	// Письма и цепочки в порядке первого изменения
+	var order []string
+	objects := make(map[string]*objectChange)
+	created := make(map[int64]bool)
+
+read:
+	for {
+		page, err := uc.repo.FindChanges(ctx, profileID, logType, result.NewState, changesPage)
+		if err != nil {
+			return domain.MailChanges{}, e.Wrap(op, err)
+		}
+
+		for _, change := range page {
+			id := strconv.FormatInt(change.ObjectID, 10)
+			if objectType == domain.MailObjectThread {
+				id = change.ThreadKey
+			}
+			if id != "" {
+				object, seen := objects[id]
+				if !seen {
+					if len(order) == maxChanges {
+						result.HasMoreChanges = true
+						break read
+					}
+					object = &objectChange{first: change.ChangeType}
+					objects[id] = object
+					order = append(order, id)
+				}
+				object.last = change.ChangeType
+			}
+			if change.ChangeType == domain.MailChangeCreated {
+				created[change.ObjectID] = true
+			}
+			result.NewState = change.ID
+		}
+
+		if len(page) < changesPage {
+			break
+		}
+	}
+
+	if objectType == domain.MailObjectThread {
+		if err := uc.resolveThreads(ctx, profileID, order, objects, created); err != nil {
+			return domain.MailChanges{}, e.Wrap(op, err)
+		}
+	}
+
+	for _, id := range order {
+		object := objects[id]
+		switch {
+		case object.first == domain.MailChangeCreated && object.last == domain.MailChangeDestroyed:
+		case object.first == domain.MailChangeCreated:
+			result.Created = append(result.Created, id)
+		case object.last == domain.MailChangeDestroyed:
+			result.Destroyed = append(result.Destroyed, id)
+		default:
+			result.Updated = append(result.Updated, id)
+		}
+	}
+
+	return result, nil
+}
+
+// resolveThreads определяет, что стало с цепочками, в которых появлялись или пропадали письма:
+// цепочка без писем удалена, цепочка только из новых писем создана, остальные изменены.
+// Цепочка, созданная и удаленная после исходного состояния, в ответ не попадает
+func (uc *ChangesUcase) resolveThreads(ctx context.Context, profileID int64, keys []string, objects map[string]*objectChange, created map[int64]bool) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	threads, err := uc.repo.FindThreadMessages(ctx, profileID, keys)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		object := objects[key]
+		messages := threads[key]
+		if len(messages) == 0 {
+			object.last = domain.MailChangeDestroyed
+			continue
+		}
+
+		isNew := true
+		for _, id := range messages {
+			isNew = isNew && created[id]
+		}
+		object.last = domain.MailChangeUpdated
+		if isNew {
+			object.first = domain.MailChangeCreated
+		} else {
+			object.first = domain.MailChangeUpdated
+		}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package changes
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockChangeRepository struct {
+	state   domain.MailState
+	changes []domain.MailChange
+	threads map[string][]int64
+
+	threadKeys []string
+}
+
+func (m *mockChangeRepository) State(ctx context.Context, profileID int64) (domain.MailState, error) {
+	return m.state, nil
+}
+
+func (m *mockChangeRepository) FindChanges(ctx context.Context, profileID int64, objectType domain.MailObjectType, since int64, limit int) ([]domain.MailChange, error) {
+	var page []domain.MailChange
+	for _, change := range m.changes {
+		if change.ID > since && len(page) < limit {
+			page = append(page, change)
+		}
+	}
+	return page, nil
+}
+
+func (m *mockChangeRepository) FindThreadMessages(ctx context.Context, profileID int64, keys []string) (map[string][]int64, error) {
+	m.threadKeys = keys
+	return m.threads, nil
+}
+
+func TestChangesUcase_Changes(t *testing.T) {
+	emailChanges := []domain.MailChange{
+		{ID: 11, ObjectID: 1, ChangeType: domain.MailChangeCreated, ThreadKey: "m1"},
+		{ID: 12, ObjectID: 1, ChangeType: domain.MailChangeUpdated},
+		{ID: 13, ObjectID: 2, ChangeType: domain.MailChangeCreated, ThreadKey: "m2"},
+		{ID: 14, ObjectID: 2, ChangeType: domain.MailChangeDestroyed, ThreadKey: "m2"},
+		{ID: 15, ObjectID: 3, ChangeType: domain.MailChangeUpdated},
+		{ID: 16, ObjectID: 4, ChangeType: domain.MailChangeDestroyed, ThreadKey: "7"},
+		// Перенос письма удалением и вставкой связи с папкой
+		{ID: 17, ObjectID: 5, ChangeType: domain.MailChangeDestroyed, ThreadKey: "7"},
+		{ID: 18, ObjectID: 5, ChangeType: domain.MailChangeCreated, ThreadKey: "7"},
+	}
+
+	t.Run("Collapse", func(t *testing.T) {
+		repo := &mockChangeRepository{state: domain.MailState{Email: 18}, changes: emailChanges}
+		uc := New(repo)
+
+		changes, err := uc.Changes(context.Background(), 1, domain.MailObjectEmail, 10, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.MailChanges{
+			OldState:  10,
+			NewState:  18,
+			Created:   []string{"1"},
+			Updated:   []string{"3", "5"},
+			Destroyed: []string{"4"},
+		}, changes)
+	})
+
+	t.Run("MaxChanges", func(t *testing.T) {
+		repo := &mockChangeRepository{state: domain.MailState{Email: 18}, changes: emailChanges}
+		uc := New(repo)
+
+		changes, err := uc.Changes(context.Background(), 1, domain.MailObjectEmail, 10, 2)
+
+		assert.NoError(t, err)
+		assert.True(t, changes.HasMoreChanges)
+		assert.Equal(t, int64(14), changes.NewState)
+		assert.Equal(t, []string{"1"}, changes.Created)
+		assert.Empty(t, changes.Updated)
+
+		changes, err = uc.Changes(context.Background(), 1, domain.MailObjectEmail, changes.NewState, 2)
+
+		assert.NoError(t, err)
+		assert.True(t, changes.HasMoreChanges)
+		assert.Equal(t, int64(16), changes.NewState)
+		assert.Equal(t, []string{"3"}, changes.Updated)
+		assert.Equal(t, []string{"4"}, changes.Destroyed)
+	})
+
+	t.Run("NoChanges", func(t *testing.T) {
+		repo := &mockChangeRepository{state: domain.MailState{Email: 18}, changes: emailChanges}
+		uc := New(repo)
+
+		changes, err := uc.Changes(context.Background(), 1, domain.MailObjectEmail, 18, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.MailChanges{OldState: 18, NewState: 18}, changes)
+	})
+
+	t.Run("StateFromFuture", func(t *testing.T) {
+		repo := &mockChangeRepository{state: domain.MailState{Mailbox: 5, Email: 18}}
+		uc := New(repo)
+
+		_, err := uc.Changes(context.Background(), 1, domain.MailObjectMailbox, 18, 0)
+
+		assert.True(t, errors.Is(err, domain.ErrInvalidMailState))
+	})
+
+	t.Run("UnknownType", func(t *testing.T) {
+		uc := New(&mockChangeRepository{})
+
+		_, err := uc.Changes(context.Background(), 1, "Identity", 0, 0)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Threads", func(t *testing.T) {
+		repo := &mockChangeRepository{
+			state: domain.MailState{Email: 23},
+			changes: append(emailChanges,
+				// Письму 1 назначена цепочка 9, в которой больше нет писем
+				domain.MailChange{ID: 19, ObjectID: 1, ChangeType: domain.MailChangeUpdated, ThreadKey: "m1"},
+				domain.MailChange{ID: 20, ObjectID: 1, ChangeType: domain.MailChangeUpdated, ThreadKey: "9"},
+				domain.MailChange{ID: 21, ObjectID: 6, ChangeType: domain.MailChangeCreated, ThreadKey: "8"},
+				domain.MailChange{ID: 22, ObjectID: 6, ChangeType: domain.MailChangeUpdated},
+				domain.MailChange{ID: 23, ObjectID: 8, ChangeType: domain.MailChangeDestroyed, ThreadKey: "3"},
+			),
+			threads: map[string][]int64{
+				"7": {3, 5},
+				"9": {1},
+				"8": {6},
+			},
+		}
+		uc := New(repo)
+
+		changes, err := uc.Changes(context.Background(), 1, domain.MailObjectThread, 10, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"m1", "m2", "7", "9", "8", "3"}, repo.threadKeys)
+		// Цепочки m1 и m2 созданы и опустели после исходного состояния
+		assert.Equal(t, domain.MailChanges{
+			OldState:  10,
+			NewState:  23,
+			Created:   []string{"9", "8"},
+			Updated:   []string{"7"},
+			Destroyed: []string{"3"},
+		}, changes)
+	})
+}
@@ -0,0 +1,165 @@
+package contact
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type ContactRepository interface {
+	CreateContact(ctx context.Context, profileID int64, contact domain.Contact) (domain.Contact, error)
+	GetContacts(ctx context.Context, profileID, groupID int64) ([]domain.Contact, error)
+	UpdateContact(ctx context.Context, profileID int64, contact domain.Contact) (domain.Contact, error)
+	DeleteContact(ctx context.Context, profileID, contactID int64) error
+	SuggestContacts(ctx context.Context, profileID int64, prefix string, now time.Time, limit int) ([]domain.ContactSuggestion, error)
+	CreateGroup(ctx context.Context, profileID int64, name string) (domain.ContactGroup, error)
+	GetGroups(ctx context.Context, profileID int64) ([]domain.ContactGroup, error)
+	RenameGroup(ctx context.Context, profileID, groupID int64, name string) (domain.ContactGroup, error)
+	DeleteGroup(ctx context.Context, profileID, groupID int64) error
+}
+
+type ContactUcase struct {
+	repo ContactRepository
+	now  func() time.Time
+}
+
+func New(repo ContactRepository) *ContactUcase {
+	return &ContactUcase{repo: repo, now: time.Now}
+}
+
+func (uc *ContactUcase) CreateContact(ctx context.Context, profileID int64, contact domain.Contact) (domain.Contact, error) {
+	contact, err := normalizeContact(contact)
+	if err != nil {
+		return domain.Contact{}, err
+	}
+	return uc.repo.CreateContact(ctx, profileID, contact)
+}
+
+// GetContacts возвращает контакты пользователя, groupID = 0 - все контакты
+func (uc *ContactUcase) GetContacts(ctx context.Context, profileID, groupID int64) ([]domain.Contact, error) {
+	return uc.repo.GetContacts(ctx, profileID, groupID)
+}
+
+func (uc *ContactUcase) UpdateContact(ctx context.Context, profileID int64, contact domain.Contact) (domain.Contact, error) {
+	contact, err := normalizeContact(contact)
+	if err != nil {
+		return domain.Contact{}, err
+	}
+	return uc.repo.UpdateContact(ctx, profileID, contact)
+}
+
+func (uc *ContactUcase) DeleteContact(ctx context.Context, profileID, contactID int64) error {
+	return uc.repo.DeleteContact(ctx, profileID, contactID)
+}
+
+// SuggestContacts подбирает адреса для автодополнения по началу адреса или имени,
+// чаще и недавно используемые адреса идут первыми
+func (uc *ContactUcase) SuggestContacts(ctx context.Context, profileID int64, prefix string, limit int) ([]domain.ContactSuggestion, error) {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		return []domain.ContactSuggestion{}, nil
+	}
+	if limit <= 0 || limit > domain.MaxContactSuggestions {
+		limit = domain.MaxContactSuggestions
+	}
+	return uc.repo.SuggestContacts(ctx, profileID, prefix, uc.now(), limit)
+}
+
+func (uc *ContactUcase) CreateGroup(ctx context.Context, profileID int64, name string) (domain.ContactGroup, error) {
+	name, err := normalizeGroupName(name)
+	if err != nil {
+		return domain.ContactGroup{}, err
+	}
+	return uc.repo.CreateGroup(ctx, profileID, name)
+}
+
+func (uc *ContactUcase) GetGroups(ctx context.Context, profileID int64) ([]domain.ContactGroup, error) {
+	return uc.repo.GetGroups(ctx, profileID)
+}
+
+func (uc *ContactUcase) RenameGroup(ctx context.Context, profileID, groupID int64, name string) (domain.ContactGroup, error) {
+	name, err := normalizeGroupName(name)
+	if err != nil {
+		return domain.ContactGroup{}, err
+	}
+	return uc.repo.RenameGroup(ctx, profileID, groupID, name)
+}
+
+func (uc *ContactUcase) DeleteGroup(ctx context.Context, profileID, groupID int64) error {
+	return uc.repo.DeleteGroup(ctx, profileID, groupID)
+}
+
+// normalizeContact обрезает пробелы, приводит адреса к нижнему регистру
+// и убирает повторы адресов и групп
+func normalizeContact(contact domain.Contact) (domain.Contact, error) {
+	contact.Name = strings.TrimSpace(contact.Name)
+	contact.Phone = strings.TrimSpace(contact.Phone)
+	contact.Notes = strings.TrimSpace(contact.Notes)
+	contact.AvatarPath = strings.TrimSpace(contact.AvatarPath)
+
+	if utf8.RuneCountInString(contact.Name) > domain.MaxContactNameLength {
+		return domain.Contact{}, fmt.Errorf("%w: name is longer than %d characters", domain.ErrInvalidContact, domain.MaxContactNameLength)
+	}
+	if len(contact.Phone) > domain.MaxContactPhoneLength {
+		return domain.Contact{}, fmt.Errorf("%w: phone is longer than %d characters", domain.ErrInvalidContact, domain.MaxContactPhoneLength)
+	}
+	if utf8.RuneCountInString(contact.Notes) > domain.MaxContactNotesLength {
+		return domain.Contact{}, fmt.Errorf("%w: notes are longer than %d characters", domain.ErrInvalidContact, domain.MaxContactNotesLength)
+	}
+	if len(contact.AvatarPath) > domain.MaxContactAvatarPathLength {
+		return domain.Contact{}, fmt.Errorf("%w: avatar path is too long", domain.ErrInvalidContact)
+	}
+
+	emails := make([]string, 0, len(contact.Emails))
+	seen := make(map[string]struct{}, len(contact.Emails))
+	for _, email := range contact.Emails {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if email == "" {
+			continue
+		}
+		addr, err := mail.ParseAddress(email)
+		if err != nil || addr.Address != email {
+			return domain.Contact{}, fmt.Errorf("%w: invalid email %q", domain.ErrInvalidContact, email)
+		}
+		if _, ok := seen[email]; ok {
+			continue
+		}
+		seen[email] = struct{}{}
+		emails = append(emails, email)
+	}
+	if len(emails) > domain.MaxContactEmails {
+		return domain.Contact{}, fmt.Errorf("%w: at most %d emails allowed", domain.ErrInvalidContact, domain.MaxContactEmails)
+	}
+	if contact.Name == "" && len(emails) == 0 {
+		return domain.Contact{}, fmt.Errorf("%w: name or email is required", domain.ErrInvalidContact)
+	}
+	contact.Emails = emails
+
+	groupIDs := make([]int64, 0, len(contact.GroupIDs))
+	seenGroups := make(map[int64]struct{}, len(contact.GroupIDs))
+	for _, id := range contact.GroupIDs {
+		if id <= 0 {
+			return domain.Contact{}, fmt.Errorf("%w: invalid group id %d", domain.ErrInvalidContact, id)
+		}
+		if _, ok := seenGroups[id]; ok {
+			continue
+		}
+		seenGroups[id] = struct{}{}
+		groupIDs = append(groupIDs, id)
+	}
+	contact.GroupIDs = groupIDs
+
+	return contact, nil
+}
+
+func normalizeGroupName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > domain.MaxContactGroupNameLength {
+		return "", fmt.Errorf("%w: group name must be from 1 to %d characters", domain.ErrInvalidContact, domain.MaxContactGroupNameLength)
+	}
+	return name, nil
+}
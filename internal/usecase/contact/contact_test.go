@@ -0,0 +1,166 @@
+package contact
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockContactRepository struct {
+	created     domain.Contact
+	groupName   string
+	prefix      string
+	limit       int
+	suggestedAt time.Time
+}
+
+func (m *mockContactRepository) CreateContact(ctx context.Context, profileID int64, contact domain.Contact) (domain.Contact, error) {
+	m.created = contact
+	contact.ID = 1
+	contact.ProfileID = profileID
+	return contact, nil
+}
+
+func (m *mockContactRepository) GetContacts(ctx context.Context, profileID, groupID int64) ([]domain.Contact, error) {
+	return nil, nil
+}
+
+func (m *mockContactRepository) UpdateContact(ctx context.Context, profileID int64, contact domain.Contact) (domain.Contact, error) {
+	m.created = contact
+	return contact, nil
+}
+
+func (m *mockContactRepository) DeleteContact(ctx context.Context, profileID, contactID int64) error {
+	return nil
+}
+
+func (m *mockContactRepository) SuggestContacts(ctx context.Context, profileID int64, prefix string, now time.Time, limit int) ([]domain.ContactSuggestion, error) {
+	m.prefix, m.limit, m.suggestedAt = prefix, limit, now
+	return []domain.ContactSuggestion{{ContactID: 1, Email: prefix + "@a4mail.ru"}}, nil
+}
+
+func (m *mockContactRepository) CreateGroup(ctx context.Context, profileID int64, name string) (domain.ContactGroup, error) {
+	m.groupName = name
+	return domain.ContactGroup{ID: 1, ProfileID: profileID, Name: name}, nil
+}
+
+func (m *mockContactRepository) GetGroups(ctx context.Context, profileID int64) ([]domain.ContactGroup, error) {
+	return nil, nil
+}
+
+func (m *mockContactRepository) RenameGroup(ctx context.Context, profileID, groupID int64, name string) (domain.ContactGroup, error) {
+	m.groupName = name
+	return domain.ContactGroup{ID: groupID, Name: name}, nil
+}
+
+func (m *mockContactRepository) DeleteGroup(ctx context.Context, profileID, groupID int64) error {
+	return nil
+}
+
+func TestContactUcase_CreateContact(t *testing.T) {
+	tests := []struct {
+		name    string
+		contact domain.Contact
+		want    domain.Contact
+		wantErr error
+	}{
+		{
+			name: "Normalizes fields",
+			contact: domain.Contact{
+				Name:     "  Иван Петров ",
+				Emails:   []string{" Ivan@A4mail.ru", "ivan@a4mail.ru", "", "ivan@work.ru"},
+				Phone:    " +79990000000 ",
+				GroupIDs: []int64{2, 2, 3},
+			},
+			want: domain.Contact{
+				Name:     "Иван Петров",
+				Emails:   []string{"ivan@a4mail.ru", "ivan@work.ru"},
+				Phone:    "+79990000000",
+				GroupIDs: []int64{2, 3},
+			},
+		},
+		{
+			name:    "Email only",
+			contact: domain.Contact{Emails: []string{"bob@a4mail.ru"}},
+			want:    domain.Contact{Emails: []string{"bob@a4mail.ru"}, GroupIDs: []int64{}},
+		},
+		{name: "Empty contact", contact: domain.Contact{Name: "  "}, wantErr: domain.ErrInvalidContact},
+		{name: "Invalid email", contact: domain.Contact{Emails: []string{"Bob <bob@a4mail.ru>"}}, wantErr: domain.ErrInvalidContact},
+		{name: "Too long name", contact: domain.Contact{Name: strings.Repeat("я", domain.MaxContactNameLength+1)}, wantErr: domain.ErrInvalidContact},
+		{name: "Too long phone", contact: domain.Contact{Name: "Bob", Phone: strings.Repeat("1", domain.MaxContactPhoneLength+1)}, wantErr: domain.ErrInvalidContact},
+		{name: "Invalid group", contact: domain.Contact{Name: "Bob", GroupIDs: []int64{0}}, wantErr: domain.ErrInvalidContact},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockContactRepository{}
+
+			_, err := New(repo).CreateContact(context.Background(), 1, tt.contact)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateContact() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(repo.created, tt.want) {
+				t.Errorf("CreateContact() saved %+v, want %+v", repo.created, tt.want)
+			}
+		})
+	}
+
+	t.Run("Too many emails", func(t *testing.T) {
+		emails := make([]string, 0, domain.MaxContactEmails+1)
+		for i := 0; i <= domain.MaxContactEmails; i++ {
+			emails = append(emails, strings.Repeat("a", i+1)+"@a4mail.ru")
+		}
+
+		_, err := New(&mockContactRepository{}).CreateContact(context.Background(), 1, domain.Contact{Emails: emails})
+
+		if !errors.Is(err, domain.ErrInvalidContact) {
+			t.Errorf("CreateContact() error = %v, want %v", err, domain.ErrInvalidContact)
+		}
+	})
+}
+
+func TestContactUcase_SuggestContacts(t *testing.T) {
+	now := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Clamps limit", func(t *testing.T) {
+		repo := &mockContactRepository{}
+		uc := New(repo)
+		uc.now = func() time.Time { return now }
+
+		got, err := uc.SuggestContacts(context.Background(), 1, " iv ", 1000)
+
+		if err != nil {
+			t.Fatalf("SuggestContacts() error = %v", err)
+		}
+		if len(got) != 1 || repo.prefix != "iv" || repo.limit != domain.MaxContactSuggestions || !repo.suggestedAt.Equal(now) {
+			t.Errorf("SuggestContacts() called repo with %q %d %v", repo.prefix, repo.limit, repo.suggestedAt)
+		}
+	})
+
+	t.Run("Empty prefix", func(t *testing.T) {
+		repo := &mockContactRepository{}
+
+		got, err := New(repo).SuggestContacts(context.Background(), 1, "  ", 5)
+
+		if err != nil || len(got) != 0 || repo.prefix != "" {
+			t.Errorf("SuggestContacts() = %v, %v; repo prefix %q", got, err, repo.prefix)
+		}
+	})
+}
+
+func TestContactUcase_CreateGroup(t *testing.T) {
+	repo := &mockContactRepository{}
+	uc := New(repo)
+
+	if _, err := uc.CreateGroup(context.Background(), 1, "  Семья "); err != nil || repo.groupName != "Семья" {
+		t.Errorf("CreateGroup() = %v, saved %q", err, repo.groupName)
+	}
+	if _, err := uc.RenameGroup(context.Background(), 1, 2, strings.Repeat("a", domain.MaxContactGroupNameLength+1)); !errors.Is(err, domain.ErrInvalidContact) {
+		t.Errorf("RenameGroup() error = %v, want %v", err, domain.ErrInvalidContact)
+	}
+}
@@ -0,0 +1,99 @@
+package forwarding
+
+import (
+	"2025_2_a4code/internal/domain"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxFromConditionLength    = 320
+	maxSubjectConditionLength = 200
+)
+
+type ForwardingRepository interface {
+	GetRule(ctx context.Context, profileID int64) (domain.ForwardingRule, error)
+	SaveRule(ctx context.Context, profileID int64, rule domain.ForwardingRule) error
+}
+
+type ForwardingUcase struct {
+	repo ForwardingRepository
+}
+
+func New(repo ForwardingRepository) *ForwardingUcase {
+	return &ForwardingUcase{repo: repo}
+}
+
+func (uc *ForwardingUcase) GetRule(ctx context.Context, profileID int64) (domain.ForwardingRule, error) {
+	return uc.repo.GetRule(ctx, profileID)
+}
+
+// PutRule проверяет и сохраняет правило автопересылки, возвращает его в нормализованном виде
+func (uc *ForwardingUcase) PutRule(ctx context.Context, profileID int64, rule domain.ForwardingRule) (domain.ForwardingRule, error) {
+	rule, err := normalizeRule(rule)
+	if err != nil {
+		return domain.ForwardingRule{}, err
+	}
+	if err := uc.repo.SaveRule(ctx, profileID, rule); err != nil {
+		return domain.ForwardingRule{}, err
+	}
+	return rule, nil
+}
+
+// Filter пересылает подходящие под правило входящие письма. Письма, признанные спамом, не пересылаются
+func (uc *ForwardingUcase) Filter(ctx context.Context, delivery *domain.Delivery) (*domain.DeliveryAction, error) {
+	const op = "usecase.forwarding.Filter"
+
+	rule, err := uc.repo.GetRule(ctx, delivery.ProfileID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	if !rule.Matches(*delivery) || isSpam(delivery) {
+		return nil, nil
+	}
+
+	return &domain.DeliveryAction{Redirects: rule.Addresses, Discard: !rule.KeepCopy}, nil
+}
+
+func isSpam(delivery *domain.Delivery) bool {
+	flags := delivery.Headers["X-Spam-Flag"]
+	return len(flags) > 0 && strings.EqualFold(flags[0], "YES")
+}
+
+func normalizeRule(rule domain.ForwardingRule) (domain.ForwardingRule, error) {
+	seen := make(map[string]struct{}, len(rule.Addresses))
+	addresses := make([]string, 0, len(rule.Addresses))
+	for _, raw := range rule.Addresses {
+		address := strings.ToLower(strings.TrimSpace(raw))
+		parsed, err := mail.ParseAddress(address)
+		if err != nil || parsed.Address != address {
+			return domain.ForwardingRule{}, fmt.Errorf("%w: invalid address %q", domain.ErrInvalidForwarding, raw)
+		}
+		if _, ok := seen[address]; ok {
+			continue
+		}
+		seen[address] = struct{}{}
+		addresses = append(addresses, address)
+	}
+
+	if len(addresses) > domain.MaxForwardingAddresses {
+		return domain.ForwardingRule{}, fmt.Errorf("%w: too many addresses (max %d)", domain.ErrInvalidForwarding, domain.MaxForwardingAddresses)
+	}
+	if rule.Enabled && len(addresses) == 0 {
+		return domain.ForwardingRule{}, fmt.Errorf("%w: no addresses", domain.ErrInvalidForwarding)
+	}
+
+	rule.Addresses = addresses
+	rule.FromContains = strings.TrimSpace(rule.FromContains)
+	rule.SubjectContains = strings.TrimSpace(rule.SubjectContains)
+	if utf8.RuneCountInString(rule.FromContains) > maxFromConditionLength ||
+		utf8.RuneCountInString(rule.SubjectContains) > maxSubjectConditionLength {
+		return domain.ForwardingRule{}, fmt.Errorf("%w: condition too long", domain.ErrInvalidForwarding)
+	}
+
+	return rule, nil
+}
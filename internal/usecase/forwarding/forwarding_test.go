@@ -0,0 +1,159 @@
+package forwarding
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type mockForwardingRepository struct {
+	rule  domain.ForwardingRule
+	err   error
+	saved *domain.ForwardingRule
+}
+
+func (m *mockForwardingRepository) GetRule(ctx context.Context, profileID int64) (domain.ForwardingRule, error) {
+	return m.rule, m.err
+}
+
+func (m *mockForwardingRepository) SaveRule(ctx context.Context, profileID int64, rule domain.ForwardingRule) error {
+	m.saved = &rule
+	return nil
+}
+
+func TestForwardingUcase_PutRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    domain.ForwardingRule
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "Normalizes and deduplicates addresses",
+			rule: domain.ForwardingRule{Enabled: true, Addresses: []string{" Deputy@a4mail.ru", "deputy@a4mail.ru", "team@a4mail.ru"}},
+			want: []string{"deputy@a4mail.ru", "team@a4mail.ru"},
+		},
+		{
+			name:    "Enabled without addresses",
+			rule:    domain.ForwardingRule{Enabled: true},
+			wantErr: true,
+		},
+		{
+			name:    "Address with display name",
+			rule:    domain.ForwardingRule{Addresses: []string{"Deputy <deputy@a4mail.ru>"}},
+			wantErr: true,
+		},
+		{
+			name: "Duplicates do not count towards limit",
+			rule: domain.ForwardingRule{Addresses: strings.Split(strings.Repeat("x@a4mail.ru,", domain.MaxForwardingAddresses)+"y@a4mail.ru", ",")},
+			want: []string{"x@a4mail.ru", "y@a4mail.ru"},
+		},
+		{
+			name:    "Condition too long",
+			rule:    domain.ForwardingRule{SubjectContains: strings.Repeat("a", maxSubjectConditionLength+1)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockForwardingRepository{}
+			rule, err := New(repo).PutRule(context.Background(), 1, tt.rule)
+
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrInvalidForwarding) {
+					t.Errorf("PutRule() error = %v, want %v", err, domain.ErrInvalidForwarding)
+				}
+				if repo.saved != nil {
+					t.Error("invalid rule must not be saved")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PutRule() error = %v", err)
+			}
+			if !reflect.DeepEqual(rule.Addresses, tt.want) || !reflect.DeepEqual(repo.saved.Addresses, tt.want) {
+				t.Errorf("PutRule() addresses = %v, want %v", rule.Addresses, tt.want)
+			}
+		})
+	}
+
+	t.Run("Limit counts unique addresses", func(t *testing.T) {
+		addresses := make([]string, 0, domain.MaxForwardingAddresses+1)
+		for i := 0; i <= domain.MaxForwardingAddresses; i++ {
+			addresses = append(addresses, string(rune('a'+i))+"@a4mail.ru")
+		}
+
+		_, err := New(&mockForwardingRepository{}).PutRule(context.Background(), 1, domain.ForwardingRule{Addresses: addresses})
+		if !errors.Is(err, domain.ErrInvalidForwarding) {
+			t.Errorf("PutRule() error = %v, want %v", err, domain.ErrInvalidForwarding)
+		}
+	})
+}
+
+func TestForwardingUcase_Filter(t *testing.T) {
+	rule := domain.ForwardingRule{
+		Enabled:         true,
+		Addresses:       []string{"deputy@a4mail.ru"},
+		FromContains:    "@client.ru",
+		SubjectContains: "invoice",
+	}
+
+	tests := []struct {
+		name     string
+		rule     domain.ForwardingRule
+		delivery domain.Delivery
+		want     *domain.DeliveryAction
+	}{
+		{
+			name:     "Matching message is forwarded without local copy",
+			rule:     rule,
+			delivery: domain.Delivery{From: "cfo@Client.ru", Topic: "Invoice #12"},
+			want:     &domain.DeliveryAction{Redirects: []string{"deputy@a4mail.ru"}, Discard: true},
+		},
+		{
+			name:     "Sender does not match",
+			rule:     rule,
+			delivery: domain.Delivery{From: "friend@a4mail.ru", Topic: "Invoice #12"},
+		},
+		{
+			name:     "Disabled rule",
+			rule:     domain.ForwardingRule{Addresses: []string{"deputy@a4mail.ru"}},
+			delivery: domain.Delivery{From: "friend@a4mail.ru"},
+		},
+		{
+			name:     "Forward everything keeping copy",
+			rule:     domain.ForwardingRule{Enabled: true, KeepCopy: true, Addresses: []string{"deputy@a4mail.ru"}},
+			delivery: domain.Delivery{From: "friend@a4mail.ru"},
+			want:     &domain.DeliveryAction{Redirects: []string{"deputy@a4mail.ru"}},
+		},
+		{
+			name:     "Spam is not forwarded",
+			rule:     domain.ForwardingRule{Enabled: true, Addresses: []string{"deputy@a4mail.ru"}},
+			delivery: domain.Delivery{From: "bot@spam.ru", Headers: map[string][]string{"X-Spam-Flag": {"YES"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delivery := tt.delivery
+			action, err := New(&mockForwardingRepository{rule: tt.rule}).Filter(context.Background(), &delivery)
+			if err != nil {
+				t.Fatalf("Filter() error = %v", err)
+			}
+			if !reflect.DeepEqual(action, tt.want) {
+				t.Errorf("Filter() = %+v, want %+v", action, tt.want)
+			}
+		})
+	}
+
+	t.Run("Repository error", func(t *testing.T) {
+		_, err := New(&mockForwardingRepository{err: errors.New("db down")}).Filter(context.Background(), &domain.Delivery{})
+		if err == nil {
+			t.Error("Filter() expected error")
+		}
+	})
+}
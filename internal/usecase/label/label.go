@@ -0,0 +1,96 @@
+package label
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+type LabelRepository interface {
+	CreateLabel(ctx context.Context, profileID int64, name, color string) (domain.Label, error)
+	GetLabels(ctx context.Context, profileID int64) ([]domain.Label, error)
+	UpdateLabel(ctx context.Context, profileID, labelID int64, name, color string) (domain.Label, error)
+	DeleteLabel(ctx context.Context, profileID, labelID int64) error
+	ApplyLabel(ctx context.Context, profileID, labelID int64, messageIDs []int64) (int64, error)
+	RemoveLabel(ctx context.Context, profileID, labelID int64, messageIDs []int64) (int64, error)
+}
+
+type LabelUcase struct {
+	repo LabelRepository
+}
+
+func New(repo LabelRepository) *LabelUcase {
+	return &LabelUcase{repo: repo}
+}
+
+func (uc *LabelUcase) CreateLabel(ctx context.Context, profileID int64, name, color string) (domain.Label, error) {
+	name, color, err := normalizeLabel(name, color)
+	if err != nil {
+		return domain.Label{}, err
+	}
+	return uc.repo.CreateLabel(ctx, profileID, name, color)
+}
+
+func (uc *LabelUcase) GetLabels(ctx context.Context, profileID int64) ([]domain.Label, error) {
+	return uc.repo.GetLabels(ctx, profileID)
+}
+
+func (uc *LabelUcase) UpdateLabel(ctx context.Context, profileID, labelID int64, name, color string) (domain.Label, error) {
+	name, color, err := normalizeLabel(name, color)
+	if err != nil {
+		return domain.Label{}, err
+	}
+	return uc.repo.UpdateLabel(ctx, profileID, labelID, name, color)
+}
+
+func (uc *LabelUcase) DeleteLabel(ctx context.Context, profileID, labelID int64) error {
+	return uc.repo.DeleteLabel(ctx, profileID, labelID)
+}
+
+// ApplyLabel вешает метку на письма, число писем ограничено так же, как в пакетных операциях
+func (uc *LabelUcase) ApplyLabel(ctx context.Context, profileID, labelID int64, messageIDs []int64) (int64, error) {
+	if err := validateMessageIDs(messageIDs); err != nil {
+		return 0, err
+	}
+	return uc.repo.ApplyLabel(ctx, profileID, labelID, messageIDs)
+}
+
+func (uc *LabelUcase) RemoveLabel(ctx context.Context, profileID, labelID int64, messageIDs []int64) (int64, error) {
+	if err := validateMessageIDs(messageIDs); err != nil {
+		return 0, err
+	}
+	return uc.repo.RemoveLabel(ctx, profileID, labelID, messageIDs)
+}
+
+// normalizeLabel обрезает пробелы в названии и приводит цвет к виду #rrggbb
+func normalizeLabel(name, color string) (string, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > domain.MaxLabelNameLength {
+		return "", "", fmt.Errorf("%w: name must be from 1 to %d characters", domain.ErrInvalidLabel, domain.MaxLabelNameLength)
+	}
+
+	color = strings.ToLower(strings.TrimSpace(color))
+	if color == "" {
+		color = domain.DefaultLabelColor
+	}
+	if !colorPattern.MatchString(color) {
+		return "", "", fmt.Errorf("%w: color must look like #rrggbb", domain.ErrInvalidLabel)
+	}
+
+	return name, color, nil
+}
+
+func validateMessageIDs(messageIDs []int64) error {
+	if len(messageIDs) == 0 {
+		return fmt.Errorf("%w: no messages", domain.ErrInvalidLabel)
+	}
+	if len(messageIDs) > domain.MaxBatchSize {
+		return domain.ErrBatchTooLarge
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package label
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type mockLabelRepository struct {
+	createdName  string
+	createdColor string
+	applied      []int64
+}
+
+func (m *mockLabelRepository) CreateLabel(ctx context.Context, profileID int64, name, color string) (domain.Label, error) {
+	m.createdName, m.createdColor = name, color
+	return domain.Label{ID: 1, ProfileID: profileID, Name: name, Color: color}, nil
+}
+
+func (m *mockLabelRepository) GetLabels(ctx context.Context, profileID int64) ([]domain.Label, error) {
+	return nil, nil
+}
+
+func (m *mockLabelRepository) UpdateLabel(ctx context.Context, profileID, labelID int64, name, color string) (domain.Label, error) {
+	return domain.Label{ID: labelID, Name: name, Color: color}, nil
+}
+
+func (m *mockLabelRepository) DeleteLabel(ctx context.Context, profileID, labelID int64) error {
+	return nil
+}
+
+func (m *mockLabelRepository) ApplyLabel(ctx context.Context, profileID, labelID int64, messageIDs []int64) (int64, error) {
+	m.applied = messageIDs
+	return int64(len(messageIDs)), nil
+}
+
+func (m *mockLabelRepository) RemoveLabel(ctx context.Context, profileID, labelID int64, messageIDs []int64) (int64, error) {
+	return int64(len(messageIDs)), nil
+}
+
+func TestLabelUcase_CreateLabel(t *testing.T) {
+	tests := []struct {
+		name      string
+		label     string
+		color     string
+		wantName  string
+		wantColor string
+		wantErr   error
+	}{
+		{name: "Normalizes name and color", label: "  Work ", color: "#FF00AA", wantName: "Work", wantColor: "#ff00aa"},
+		{name: "Default color", label: "Travel", wantName: "Travel", wantColor: domain.DefaultLabelColor},
+		{name: "Empty name", label: "   ", wantErr: domain.ErrInvalidLabel},
+		{name: "Too long name", label: strings.Repeat("я", domain.MaxLabelNameLength+1), wantErr: domain.ErrInvalidLabel},
+		{name: "Invalid color", label: "Work", color: "red", wantErr: domain.ErrInvalidLabel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockLabelRepository{}
+
+			_, err := New(repo).CreateLabel(context.Background(), 1, tt.label, tt.color)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateLabel() error = %v, want %v", err, tt.wantErr)
+			}
+			if repo.createdName != tt.wantName || repo.createdColor != tt.wantColor {
+				t.Errorf("CreateLabel() saved %q %q, want %q %q", repo.createdName, repo.createdColor, tt.wantName, tt.wantColor)
+			}
+		})
+	}
+}
+
+func TestLabelUcase_ApplyLabel(t *testing.T) {
+	t.Run("Applies to messages", func(t *testing.T) {
+		repo := &mockLabelRepository{}
+
+		applied, err := New(repo).ApplyLabel(context.Background(), 1, 2, []int64{10, 11})
+
+		if err != nil || applied != 2 {
+			t.Fatalf("ApplyLabel() = %d, %v", applied, err)
+		}
+	})
+
+	t.Run("No messages", func(t *testing.T) {
+		_, err := New(&mockLabelRepository{}).ApplyLabel(context.Background(), 1, 2, nil)
+		if !errors.Is(err, domain.ErrInvalidLabel) {
+			t.Errorf("ApplyLabel() error = %v, want %v", err, domain.ErrInvalidLabel)
+		}
+	})
+
+	t.Run("Too many messages", func(t *testing.T) {
+		_, err := New(&mockLabelRepository{}).RemoveLabel(context.Background(), 1, 2, make([]int64, domain.MaxBatchSize+1))
+		if !errors.Is(err, domain.ErrBatchTooLarge) {
+			t.Errorf("RemoveLabel() error = %v, want %v", err, domain.ErrBatchTooLarge)
+		}
+	})
+}
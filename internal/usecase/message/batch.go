@@ -0,0 +1,53 @@
+package message
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+)
+
+// ApplyBatch проверяет пакетную операцию и применяет ее к письмам пользователя
+func (uc *MessageUcase) ApplyBatch(ctx context.Context, profileID int64, batch domain.BatchOperation) (domain.BatchResult, error) {
+	if err := validateBatch(batch); err != nil {
+		return domain.BatchResult{}, err
+	}
+
+	result, err := uc.repo.ApplyBatch(ctx, profileID, batch)
+	if err != nil {
+		return domain.BatchResult{}, err
+	}
+
+	// Отдельные события по каждому письму не отправляются: клиенту достаточно
+	// нового числа непрочитанных, чтобы перезагрузить открытую папку
+	uc.publishUnreadCount(ctx, profileID)
+	return result, nil
+}
+
+func validateBatch(batch domain.BatchOperation) error {
+	switch batch.Action {
+	case domain.BatchMove:
+		if batch.TargetFolderID <= 0 {
+			return domain.ErrInvalidBatch
+		}
+	case domain.BatchDeleteFromFolder:
+		if batch.Selector.FolderID <= 0 {
+			return domain.ErrInvalidBatch
+		}
+	case domain.BatchMarkAsSpam, domain.BatchMarkAsNotSpam, domain.BatchMarkAsRead:
+	default:
+		return domain.ErrInvalidBatch
+	}
+
+	selector := batch.Selector
+	if len(selector.MessageIDs) > domain.MaxBatchSize {
+		return domain.ErrBatchTooLarge
+	}
+	if len(selector.MessageIDs) == 0 && selector.FolderID <= 0 {
+		return domain.ErrInvalidBatch
+	}
+	filter := selector.Filter
+	if !filter.After.IsZero() && !filter.Before.IsZero() && !filter.After.Before(filter.Before) {
+		return domain.ErrInvalidBatch
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package message
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMessageUcase_ApplyBatch(t *testing.T) {
+	now := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		batch   domain.BatchOperation
+		wantErr error
+		called  bool
+	}{
+		{
+			name: "Mark as read by IDs",
+			batch: domain.BatchOperation{
+				Action:   domain.BatchMarkAsRead,
+				Selector: domain.BatchSelector{MessageIDs: []int64{1, 2}},
+			},
+			called: true,
+		},
+		{
+			name: "Move by folder selector",
+			batch: domain.BatchOperation{
+				Action:         domain.BatchMove,
+				Selector:       domain.BatchSelector{FolderID: 3, Filter: domain.MessageFilter{Sender: "a@b.ru"}},
+				TargetFolderID: 4,
+			},
+			called: true,
+		},
+		{
+			name: "Unknown action",
+			batch: domain.BatchOperation{
+				Action:   "archive",
+				Selector: domain.BatchSelector{MessageIDs: []int64{1}},
+			},
+			wantErr: domain.ErrInvalidBatch,
+		},
+		{
+			name: "Move without target",
+			batch: domain.BatchOperation{
+				Action:   domain.BatchMove,
+				Selector: domain.BatchSelector{MessageIDs: []int64{1}},
+			},
+			wantErr: domain.ErrInvalidBatch,
+		},
+		{
+			name: "Delete from folder without folder",
+			batch: domain.BatchOperation{
+				Action:   domain.BatchDeleteFromFolder,
+				Selector: domain.BatchSelector{MessageIDs: []int64{1}},
+			},
+			wantErr: domain.ErrInvalidBatch,
+		},
+		{
+			name:    "Empty selector",
+			batch:   domain.BatchOperation{Action: domain.BatchMarkAsSpam},
+			wantErr: domain.ErrInvalidBatch,
+		},
+		{
+			name: "Too many IDs",
+			batch: domain.BatchOperation{
+				Action:   domain.BatchMarkAsRead,
+				Selector: domain.BatchSelector{MessageIDs: make([]int64, domain.MaxBatchSize+1)},
+			},
+			wantErr: domain.ErrBatchTooLarge,
+		},
+		{
+			name: "Inverted date range",
+			batch: domain.BatchOperation{
+				Action: domain.BatchMarkAsRead,
+				Selector: domain.BatchSelector{
+					FolderID: 3,
+					Filter:   domain.MessageFilter{After: now, Before: now.Add(-time.Hour)},
+				},
+			},
+			wantErr: domain.ErrInvalidBatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			repo := &MockMessageRepository{
+				ApplyBatchFn: func(ctx context.Context, profileID int64, batch domain.BatchOperation) (domain.BatchResult, error) {
+					called = true
+					return domain.BatchResult{Items: []domain.BatchItemResult{{MessageID: 1}}}, nil
+				},
+			}
+
+			_, err := New(repo).ApplyBatch(context.Background(), 1, tt.batch)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ApplyBatch() error = %v, want %v", err, tt.wantErr)
+			}
+			if called != tt.called {
+				t.Errorf("repository called = %v, want %v", called, tt.called)
+			}
+		})
+	}
+}
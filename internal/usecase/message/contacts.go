@@ -0,0 +1,23 @@
+package message
+
+import (
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// collectContact добавляет получателя письма в адресную книгу отправителя.
+// Автоответы и автопересылки не считаются: их адресатов пользователь не выбирал.
+// Ошибка не прерывает отправку письма
+func (uc *MessageUcase) collectContact(ctx context.Context, senderProfileID int64, receiverEmail string) {
+	const op = "usecase.message.collectContact"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	if isAutoSubmitted(ctx) || len(forwardChain(ctx)) > 0 {
+		return
+	}
+	if err := uc.repo.CollectContact(ctx, senderProfileID, receiverEmail, time.Now()); err != nil {
+		log.Warn("failed to collect contact: " + err.Error())
+	}
+}
@@ -0,0 +1,50 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMessageUcase_CollectContact(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		collectErr error
+		want       []string
+	}{
+		{name: "Collects receiver", ctx: context.Background(), want: []string{"bob@a4mail.ru"}},
+		{name: "Skips auto replies", ctx: WithAutoSubmitted(context.Background())},
+		{name: "Skips automatic forwarding", ctx: withForwardHop(context.Background(), "me@a4mail.ru")},
+		{
+			name:       "Collection error does not fail sending",
+			ctx:        context.Background(),
+			collectErr: errors.New("db error"),
+			want:       []string{"bob@a4mail.ru"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var collected []string
+			uc := New(&MockMessageRepository{
+				SaveMessageWithFolderDistributionFn: func(ctx context.Context, receiverProfileEmail string, senderBaseProfileID int64, topic, text string) (int64, error) {
+					return 1, nil
+				},
+				CollectContactFn: func(ctx context.Context, senderBaseProfileID int64, email string, usedAt time.Time) error {
+					collected = append(collected, email)
+					return tt.collectErr
+				},
+			})
+
+			if _, err := uc.SendMessage(tt.ctx, "bob@a4mail.ru", 7, "Hi", "Text"); err != nil {
+				t.Fatalf("SendMessage() error = %v", err)
+			}
+			if !reflect.DeepEqual(collected, tt.want) {
+				t.Errorf("collected = %v, want %v", collected, tt.want)
+			}
+		})
+	}
+}
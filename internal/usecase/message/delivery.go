@@ -0,0 +1,271 @@
+package message
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// DeliveryFilter - обработчик входящего письма (sieve, спам-фильтр и т.п.)
+type DeliveryFilter interface {
+	Filter(ctx context.Context, delivery *domain.Delivery) (*domain.DeliveryAction, error)
+}
+
+type autoSubmittedKey struct{}
+
+// WithAutoSubmitted помечает письма, отправленные в рамках контекста, как автоматические,
+// чтобы на них не срабатывали автоответы
+func WithAutoSubmitted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, autoSubmittedKey{}, true)
+}
+
+func isAutoSubmitted(ctx context.Context) bool {
+	v, _ := ctx.Value(autoSubmittedKey{}).(bool)
+	return v
+}
+
+// forwardedCountHeader - заголовок с числом автопересылок, который получают пересланные письма
+const forwardedCountHeader = "X-Forwarded-Count"
+
+type forwardChainKey struct{}
+
+// withForwardHop добавляет получателя, пересылающего письмо, в цепочку автопересылки контекста
+func withForwardHop(ctx context.Context, forwarder string) context.Context {
+	chain := forwardChain(ctx)
+	next := make([]string, len(chain), len(chain)+1)
+	copy(next, chain)
+	next = append(next, strings.ToLower(forwarder))
+	return context.WithValue(ctx, forwardChainKey{}, next)
+}
+
+// forwardChain возвращает адреса получателей, которые уже автоматически переслали письмо
+func forwardChain(ctx context.Context) []string {
+	chain, _ := ctx.Value(forwardChainKey{}).([]string)
+	return chain
+}
+
+// forwardHops - сколько раз письмо уже было автоматически переслано: пересылки внутри
+// сервиса и пересылки до него, о которых сообщает заголовок X-Forwarded-Count внешнего письма
+func forwardHops(ctx context.Context) int {
+	hops := len(forwardChain(ctx))
+	if inbound, ok := inboundMessage(ctx); ok {
+		hops += parseForwardedCount(inbound.Headers)
+	}
+	return hops
+}
+
+// parseForwardedCount читает заголовок X-Forwarded-Count, некорректное значение считается нулем
+func parseForwardedCount(headers map[string][]string) int {
+	values := headers[forwardedCountHeader]
+	if len(values) == 0 {
+		return 0
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(values[0]))
+	if err != nil || count < 0 {
+		return 0
+	}
+	return count
+}
+
+// deliver прогоняет письмо получателя через фильтры доставки и применяет их решение.
+// Ошибки фильтров не прерывают отправку: письмо остается во входящих.
+func (uc *MessageUcase) deliver(ctx context.Context, messageID int64, receiverEmail string) {
+	const op = "usecase.message.deliver"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	if len(uc.filters) == 0 && uc.events == nil {
+		return
+	}
+
+	delivery, err := uc.repo.GetDelivery(ctx, messageID, receiverEmail)
+	if err != nil {
+		log.Error("failed to load delivery: " + err.Error())
+		return
+	}
+	if isAutoSubmitted(ctx) {
+		delivery.AutoSubmitted = true
+		delivery.Headers["Auto-Submitted"] = []string{"auto-replied"}
+	}
+	// Фильтры внешнего письма видят его настоящие заголовки и результаты проверки отправителя
+	if inbound, ok := inboundMessage(ctx); ok {
+		for key, values := range inbound.Headers {
+			delivery.Headers[key] = values
+		}
+		delivery.SPF = inbound.SPF
+		delivery.DMARC = inbound.DMARC
+	}
+	if hops := forwardHops(ctx); hops > 0 {
+		delivery.ForwardHops = hops
+		delivery.Headers[forwardedCountHeader] = []string{strconv.Itoa(hops)}
+	}
+
+	var action domain.DeliveryAction
+	for _, filter := range uc.filters {
+		result, err := filter.Filter(ctx, &delivery)
+		if err != nil {
+			log.Warn("delivery filter failed: " + err.Error())
+			continue
+		}
+		action.Merge(result)
+		if result != nil && result.Stop {
+			break
+		}
+	}
+
+	if uc.applyDeliveryAction(ctx, delivery, action) {
+		uc.publishNewMessage(ctx, delivery)
+	}
+}
+
+// applyDeliveryAction размещает письмо по решению фильтров и сообщает, осталось ли оно во входящих
+func (uc *MessageUcase) applyDeliveryAction(ctx context.Context, delivery domain.Delivery, action domain.DeliveryAction) bool {
+	const op = "usecase.message.applyDeliveryAction"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	if len(action.Redirects) > 0 {
+		if forwarded := uc.redirect(ctx, delivery, action.Redirects); forwarded == 0 && action.Discard {
+			// Письмо не удалось переслать, поэтому оно остается во входящих
+			action.Discard = false
+		}
+	}
+
+	removeFromInbox := action.Discard
+	spam := false
+	if len(action.Folders) > 0 && !action.Discard {
+		folders, err := uc.repo.GetUserFolders(ctx, delivery.ProfileID)
+		if err != nil {
+			log.Error("failed to get user folders: " + err.Error())
+			return true
+		}
+
+		filed := 0
+		for _, name := range action.Folders {
+			folder, err := domain.FindFolder(folders, name)
+			if err != nil {
+				// по RFC 5228 при ошибке fileinto письмо остается во входящих
+				log.Warn("delivery folder not resolved", slog.String("folder", name), slog.String("error", err.Error()))
+				continue
+			}
+			if folder.Type == domain.FolderInbox {
+				filed++
+				continue
+			}
+			if folder.Type == domain.FolderSpam {
+				spam = true
+			}
+			if err := uc.repo.AddMessageToFolder(ctx, delivery.MessageID, folder.ID); err != nil {
+				log.Error("failed to add message to folder: " + err.Error())
+				continue
+			}
+			filed++
+		}
+		removeFromInbox = filed == len(action.Folders) && !action.Keep && !inboxRequested(folders, action.Folders)
+	}
+
+	if removeFromInbox {
+		inboxID, err := uc.repo.GetFolderByType(ctx, delivery.ProfileID, string(domain.FolderInbox))
+		if err != nil {
+			log.Error("failed to get inbox: " + err.Error())
+		} else if err := uc.repo.DeleteMessageFromFolder(ctx, delivery.ProfileID, delivery.MessageID, inboxID); err != nil {
+			log.Error("failed to remove message from inbox: " + err.Error())
+		}
+	}
+
+	for _, flag := range action.Flags {
+		switch {
+		case strings.EqualFold(flag, `\Seen`):
+			if err := uc.repo.MarkMessageAsRead(ctx, delivery.MessageID, delivery.BaseProfileID); err != nil {
+				log.Error("failed to mark message as read: " + err.Error())
+			}
+		case strings.EqualFold(flag, `\Flagged`):
+			starred := true
+			if err := uc.repo.SetFlags(ctx, delivery.ProfileID, delivery.MessageID, domain.FlagsUpdate{Starred: &starred}); err != nil {
+				log.Error("failed to star message: " + err.Error())
+			}
+		}
+	}
+
+	if delivery.AutoSubmitted {
+		return !removeFromInbox
+	}
+	replyCtx := WithAutoSubmitted(ctx)
+	for _, reply := range action.Replies {
+		if reply.InboxOnly && (removeFromInbox || spam) {
+			continue
+		}
+		if _, err := uc.SendMessage(replyCtx, reply.To, delivery.BaseProfileID, reply.Topic, reply.Text); err != nil {
+			log.Warn("failed to send auto reply: " + err.Error())
+		}
+	}
+	return !removeFromInbox
+}
+
+// redirect автоматически пересылает письмо на указанные адреса от имени получателя и возвращает
+// число успешных пересылок. Адреса, уже пересылавшие это письмо, и превышение числа
+// пересылок считаются петлей и пропускаются. Пересланные копии не попадают в "Отправленные"
+// получателя: он не отправлял их сам. Копия на внешний адрес после этого не лежит ни в одной папке,
+// но очистка писем без папок не трогает ее, пока она ждет отправки в очереди исходящей почты
+func (uc *MessageUcase) redirect(ctx context.Context, delivery domain.Delivery, addresses []string) int {
+	const op = "usecase.message.redirect"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	chain := forwardChain(ctx)
+	if delivery.ForwardHops >= domain.MaxForwardHops {
+		log.Warn("forwarding hop limit reached", slog.Int64("message_id", delivery.MessageID))
+		return 0
+	}
+
+	forwardCtx := withForwardHop(ctx, delivery.To)
+	topic, text := buildForward(domain.FullMessage{
+		Topic:    delivery.Topic,
+		Text:     delivery.Text,
+		Datetime: delivery.Datetime,
+		Sender:   domain.Sender{Email: delivery.From},
+	}, "")
+
+	forwarded := 0
+	for _, to := range addresses {
+		if strings.EqualFold(to, delivery.To) || containsAddress(chain, to) {
+			log.Warn("forwarding loop detected", slog.String("to", to))
+			continue
+		}
+
+		messageID, err := uc.SendMessage(forwardCtx, to, delivery.BaseProfileID, topic, text)
+		if err != nil {
+			log.Warn("failed to forward message: " + err.Error())
+			continue
+		}
+		if _, err := uc.repo.CopyFiles(ctx, delivery.MessageID, messageID); err != nil {
+			log.Warn("failed to copy forwarded files: " + err.Error())
+		}
+		if sentID, err := uc.repo.GetFolderByType(ctx, delivery.ProfileID, string(domain.FolderSent)); err != nil {
+			log.Warn("failed to get sent folder: " + err.Error())
+		} else if err := uc.repo.DeleteMessageFromFolder(ctx, delivery.ProfileID, messageID, sentID); err != nil {
+			log.Warn("failed to remove forwarded message from sent: " + err.Error())
+		}
+		forwarded++
+	}
+
+	return forwarded
+}
+
+func containsAddress(addresses []string, address string) bool {
+	for _, a := range addresses {
+		if strings.EqualFold(a, address) {
+			return true
+		}
+	}
+	return false
+}
+
+func inboxRequested(folders []domain.Folder, names []string) bool {
+	for _, name := range names {
+		if folder, err := domain.FindFolder(folders, name); err == nil && folder.Type == domain.FolderInbox {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,441 @@
+package message
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type stubFilter struct {
+	action *domain.DeliveryAction
+	err    error
+}
+
+func (f stubFilter) Filter(ctx context.Context, delivery *domain.Delivery) (*domain.DeliveryAction, error) {
+	return f.action, f.err
+}
+
+func TestMessageUcase_Deliver(t *testing.T) {
+	folders := []domain.Folder{
+		{ID: 1, Name: "Входящие", Type: domain.FolderInbox},
+		{ID: 4, Name: "Спам", Type: domain.FolderSpam},
+		{ID: 10, Name: "Work", Type: domain.FolderCustom},
+		{ID: 11, ParentID: 10, Name: "Reports", Type: domain.FolderCustom},
+		{ID: 12, Name: "Home", Type: domain.FolderCustom},
+		{ID: 13, ParentID: 12, Name: "Reports", Type: domain.FolderCustom},
+	}
+
+	tests := []struct {
+		name          string
+		filters       []DeliveryFilter
+		wantAdded     []int64
+		wantRemoved   bool
+		wantRead      bool
+		wantStarred   bool
+		wantReplyTo   []string
+		wantDelivered bool
+	}{
+		{
+			name:          "No decision keeps message in inbox",
+			filters:       []DeliveryFilter{stubFilter{}},
+			wantDelivered: true,
+		},
+		{
+			name:          "Fileinto custom folder moves message",
+			filters:       []DeliveryFilter{stubFilter{action: &domain.DeliveryAction{Folders: []string{"work"}}}},
+			wantAdded:     []int64{10},
+			wantRemoved:   true,
+			wantDelivered: true,
+		},
+		{
+			name: "Later filter overrides folders, keep leaves inbox copy",
+			filters: []DeliveryFilter{
+				stubFilter{action: &domain.DeliveryAction{Folders: []string{"spam"}}},
+				stubFilter{action: &domain.DeliveryAction{Folders: []string{"Work"}, Keep: true}},
+			},
+			wantAdded:     []int64{10},
+			wantDelivered: true,
+		},
+		{
+			name:          "Fileinto nested folder by full name",
+			filters:       []DeliveryFilter{stubFilter{action: &domain.DeliveryAction{Folders: []string{"home/reports"}}}},
+			wantAdded:     []int64{13},
+			wantRemoved:   true,
+			wantDelivered: true,
+		},
+		{
+			name:          "Ambiguous folder name keeps message in inbox",
+			filters:       []DeliveryFilter{stubFilter{action: &domain.DeliveryAction{Folders: []string{"Reports"}}}},
+			wantDelivered: true,
+		},
+		{
+			name:          "Unknown folder keeps message in inbox",
+			filters:       []DeliveryFilter{stubFilter{action: &domain.DeliveryAction{Folders: []string{"Missing"}}}},
+			wantDelivered: true,
+		},
+		{
+			name:          "Discard removes message from inbox",
+			filters:       []DeliveryFilter{stubFilter{action: &domain.DeliveryAction{Discard: true}}},
+			wantRemoved:   true,
+			wantDelivered: true,
+		},
+		{
+			name: "Stop skips later filters",
+			filters: []DeliveryFilter{
+				stubFilter{action: &domain.DeliveryAction{Folders: []string{"spam"}, Stop: true}},
+				stubFilter{action: &domain.DeliveryAction{
+					Folders: []string{"Work"},
+					Replies: []domain.AutoReply{{To: "sender@a4mail.ru", Topic: "Auto", Text: "Away"}},
+				}},
+			},
+			wantAdded:     []int64{4},
+			wantRemoved:   true,
+			wantDelivered: true,
+		},
+		{
+			name: "Failed filter is skipped, flags and replies applied",
+			filters: []DeliveryFilter{
+				stubFilter{err: errors.New("boom")},
+				stubFilter{action: &domain.DeliveryAction{
+					Flags:   []string{`\Seen`},
+					Replies: []domain.AutoReply{{To: "sender@a4mail.ru", Topic: "Auto", Text: "Away"}},
+				}},
+			},
+			wantRead:      true,
+			wantReplyTo:   []string{"sender@a4mail.ru"},
+			wantDelivered: true,
+		},
+		{
+			name: "Inbox-only reply skipped for spam",
+			filters: []DeliveryFilter{
+				stubFilter{action: &domain.DeliveryAction{Folders: []string{"spam"}}},
+				stubFilter{action: &domain.DeliveryAction{Replies: []domain.AutoReply{{To: "sender@a4mail.ru", Topic: "Auto", Text: "Away", InboxOnly: true}}}},
+			},
+			wantAdded:     []int64{4},
+			wantRemoved:   true,
+			wantDelivered: true,
+		},
+		{
+			name: "Inbox-only reply skipped when spam copy keeps inbox",
+			filters: []DeliveryFilter{
+				stubFilter{action: &domain.DeliveryAction{
+					Folders: []string{"spam"},
+					Keep:    true,
+					Replies: []domain.AutoReply{{To: "sender@a4mail.ru", Topic: "Auto", Text: "Away", InboxOnly: true}},
+				}},
+			},
+			wantAdded:     []int64{4},
+			wantDelivered: true,
+		},
+		{
+			name: "Inbox-only reply skipped for filed message",
+			filters: []DeliveryFilter{
+				stubFilter{action: &domain.DeliveryAction{
+					Folders: []string{"Work"},
+					Replies: []domain.AutoReply{{To: "sender@a4mail.ru", Topic: "Auto", Text: "Away", InboxOnly: true}},
+				}},
+			},
+			wantAdded:     []int64{10},
+			wantRemoved:   true,
+			wantDelivered: true,
+		},
+		{
+			name: "Inbox-only reply sent for inbox message",
+			filters: []DeliveryFilter{
+				stubFilter{action: &domain.DeliveryAction{Replies: []domain.AutoReply{{To: "sender@a4mail.ru", Topic: "Auto", Text: "Away", InboxOnly: true}}}},
+			},
+			wantReplyTo:   []string{"sender@a4mail.ru"},
+			wantDelivered: true,
+		},
+		{
+			name:          "Flagged stars message",
+			filters:       []DeliveryFilter{stubFilter{action: &domain.DeliveryAction{Flags: []string{`\flagged`}}}},
+			wantStarred:   true,
+			wantDelivered: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var added []int64
+			var removed, read, starred bool
+			var replyTo []string
+			delivered := false
+
+			repo := &MockMessageRepository{
+				SaveMessageWithFolderDistributionFn: func(ctx context.Context, receiverProfileEmail string, senderBaseProfileID int64, topic, text string) (int64, error) {
+					if isAutoSubmitted(ctx) {
+						replyTo = append(replyTo, receiverProfileEmail)
+						return 2, nil
+					}
+					return 1, nil
+				},
+				GetDeliveryFn: func(ctx context.Context, messageID int64, receiverEmail string) (domain.Delivery, error) {
+					if messageID == 1 {
+						delivered = true
+					}
+					return domain.Delivery{MessageID: messageID, ProfileID: 7, BaseProfileID: 7, Headers: map[string][]string{}}, nil
+				},
+				GetUserFoldersFn: func(ctx context.Context, profileID int64) ([]domain.Folder, error) {
+					return folders, nil
+				},
+				AddMessageToFolderFn: func(ctx context.Context, messageID, folderID int64) error {
+					added = append(added, folderID)
+					return nil
+				},
+				GetFolderByTypeFn: func(ctx context.Context, profileID int64, folderType string) (int64, error) {
+					return 1, nil
+				},
+				DeleteMessageFromFolderFn: func(ctx context.Context, profileID, messageID, folderID int64) error {
+					removed = folderID == 1 && messageID == 1
+					return nil
+				},
+				MarkMessageAsReadFn: func(ctx context.Context, messageID int64, profileID int64) error {
+					read = true
+					return nil
+				},
+				SetFlagsFn: func(ctx context.Context, profileID, messageID int64, update domain.FlagsUpdate) error {
+					starred = profileID == 7 && messageID == 1 && update.Starred != nil && *update.Starred
+					return nil
+				},
+			}
+
+			uc := New(repo, tt.filters...)
+			if _, err := uc.SendMessage(context.Background(), "receiver@a4mail.ru", 3, "Topic", "Text"); err != nil {
+				t.Fatalf("SendMessage() error = %v", err)
+			}
+
+			if delivered != tt.wantDelivered {
+				t.Errorf("delivered = %v, want %v", delivered, tt.wantDelivered)
+			}
+			if !reflect.DeepEqual(added, tt.wantAdded) {
+				t.Errorf("added to folders = %v, want %v", added, tt.wantAdded)
+			}
+			if removed != tt.wantRemoved {
+				t.Errorf("removed from inbox = %v, want %v", removed, tt.wantRemoved)
+			}
+			if read != tt.wantRead {
+				t.Errorf("marked as read = %v, want %v", read, tt.wantRead)
+			}
+			if starred != tt.wantStarred {
+				t.Errorf("starred = %v, want %v", starred, tt.wantStarred)
+			}
+			if !reflect.DeepEqual(replyTo, tt.wantReplyTo) {
+				t.Errorf("auto replies = %v, want %v", replyTo, tt.wantReplyTo)
+			}
+		})
+	}
+}
+
+// redirectFilter пересылает письма получателя по таблице адресов
+type redirectFilter struct {
+	rules   map[string][]string
+	discard bool
+}
+
+func (f redirectFilter) Filter(ctx context.Context, delivery *domain.Delivery) (*domain.DeliveryAction, error) {
+	to, ok := f.rules[delivery.To]
+	if !ok {
+		return nil, nil
+	}
+	return &domain.DeliveryAction{Redirects: to, Discard: f.discard}, nil
+}
+
+func TestMessageUcase_DeliverRedirect(t *testing.T) {
+	chain := map[string][]string{}
+	for i := 0; i < 10; i++ {
+		chain[string(rune('a'+i))+"@a4mail.ru"] = []string{string(rune('a'+i+1)) + "@a4mail.ru"}
+	}
+
+	tests := []struct {
+		name        string
+		filter      redirectFilter
+		wantSent    []string
+		wantRemoved []int64
+	}{
+		{
+			name:     "Forwards to another address",
+			filter:   redirectFilter{rules: map[string][]string{"a@a4mail.ru": {"b@a4mail.ru"}}},
+			wantSent: []string{"a@a4mail.ru", "b@a4mail.ru"},
+		},
+		{
+			name: "Loop between two users stops",
+			filter: redirectFilter{rules: map[string][]string{
+				"a@a4mail.ru": {"b@a4mail.ru"},
+				"b@a4mail.ru": {"a@a4mail.ru"},
+			}},
+			wantSent: []string{"a@a4mail.ru", "b@a4mail.ru"},
+		},
+		{
+			name: "Without local copy only forwarded messages leave inbox",
+			filter: redirectFilter{rules: map[string][]string{
+				"a@a4mail.ru": {"b@a4mail.ru"},
+				"b@a4mail.ru": {"a@a4mail.ru"},
+			}, discard: true},
+			wantSent:    []string{"a@a4mail.ru", "b@a4mail.ru"},
+			wantRemoved: []int64{1},
+		},
+		{
+			name:     "Hop limit stops long chains",
+			filter:   redirectFilter{rules: chain},
+			wantSent: []string{"a@a4mail.ru", "b@a4mail.ru", "c@a4mail.ru", "d@a4mail.ru", "e@a4mail.ru", "f@a4mail.ru"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []string
+			var removed, removedFromSent []int64
+			var copiedFrom []int64
+
+			repo := &MockMessageRepository{
+				SaveMessageWithFolderDistributionFn: func(ctx context.Context, receiverProfileEmail string, senderBaseProfileID int64, topic, text string) (int64, error) {
+					sent = append(sent, receiverProfileEmail)
+					return int64(len(sent)), nil
+				},
+				GetDeliveryFn: func(ctx context.Context, messageID int64, receiverEmail string) (domain.Delivery, error) {
+					return domain.Delivery{MessageID: messageID, ProfileID: messageID, To: receiverEmail, Headers: map[string][]string{}}, nil
+				},
+				GetFolderByTypeFn: func(ctx context.Context, profileID int64, folderType string) (int64, error) {
+					if folderType == string(domain.FolderSent) {
+						return 2, nil
+					}
+					return 1, nil
+				},
+				DeleteMessageFromFolderFn: func(ctx context.Context, profileID, messageID, folderID int64) error {
+					if folderID == 2 {
+						removedFromSent = append(removedFromSent, messageID)
+					} else {
+						removed = append(removed, messageID)
+					}
+					return nil
+				},
+				CopyFilesFn: func(ctx context.Context, fromMessageID, toMessageID int64) (int64, error) {
+					copiedFrom = append(copiedFrom, fromMessageID)
+					return 0, nil
+				},
+			}
+
+			uc := New(repo, tt.filter)
+			if _, err := uc.SendMessage(context.Background(), "a@a4mail.ru", 3, "Topic", "Text"); err != nil {
+				t.Fatalf("SendMessage() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(sent, tt.wantSent) {
+				t.Errorf("sent = %v, want %v", sent, tt.wantSent)
+			}
+			if !reflect.DeepEqual(removed, tt.wantRemoved) {
+				t.Errorf("removed from inbox = %v, want %v", removed, tt.wantRemoved)
+			}
+			if len(copiedFrom) != len(sent)-1 {
+				t.Errorf("copied files %d times, want %d", len(copiedFrom), len(sent)-1)
+			}
+			// Автоматически пересланные копии не остаются в "Отправленных" пересылающего
+			if len(removedFromSent) != len(sent)-1 {
+				t.Errorf("removed %d copies from sent, want %d", len(removedFromSent), len(sent)-1)
+			}
+		})
+	}
+}
+
+func TestMessageUcase_DeliverRedirectExternal(t *testing.T) {
+	var queued []string
+	var removedFromSent []int64
+	repo := &MockMessageRepository{
+		ResolveRecipientFn: func(ctx context.Context, email string) (string, error) {
+			if email == "someone@gmail.com" {
+				return "", domain.ErrRecipientNotFound
+			}
+			return email, nil
+		},
+		SaveMessageWithFolderDistributionFn: func(ctx context.Context, receiverProfileEmail string, senderBaseProfileID int64, topic, text string) (int64, error) {
+			return 1, nil
+		},
+		GetDeliveryFn: func(ctx context.Context, messageID int64, receiverEmail string) (domain.Delivery, error) {
+			return domain.Delivery{MessageID: messageID, ProfileID: 7, BaseProfileID: 3, To: receiverEmail, Headers: map[string][]string{}}, nil
+		},
+		SaveOutboundMessageFn: func(ctx context.Context, receiverEmail string, senderBaseProfileID, threadRoot int64, topic, text string, at time.Time, forwardHops int) (int64, error) {
+			queued = append(queued, receiverEmail)
+			return 2, nil
+		},
+		GetFolderByTypeFn: func(ctx context.Context, profileID int64, folderType string) (int64, error) {
+			return 5, nil
+		},
+		DeleteMessageFromFolderFn: func(ctx context.Context, profileID, messageID, folderID int64) error {
+			removedFromSent = append(removedFromSent, messageID)
+			return nil
+		},
+	}
+	uc := New(repo, redirectFilter{rules: map[string][]string{"a@flintmail.ru": {"someone@gmail.com"}}})
+	uc.EnableOutbound("flintmail.ru")
+
+	if _, err := uc.SendMessage(context.Background(), "a@flintmail.ru", 3, "Topic", "Text"); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+
+	// Копия уходит через очередь исходящей почты, которая и держит ее до отправки
+	if !reflect.DeepEqual(queued, []string{"someone@gmail.com"}) {
+		t.Errorf("queued = %v, want [someone@gmail.com]", queued)
+	}
+	if !reflect.DeepEqual(removedFromSent, []int64{2}) {
+		t.Errorf("removed from sent = %v, want [2]", removedFromSent)
+	}
+}
+
+func TestMessageUcase_ReceiveMessageForwardHops(t *testing.T) {
+	tests := []struct {
+		name      string
+		count     string
+		wantHops  []int
+		wantFound string
+	}{
+		{name: "Continues external count", count: "3", wantHops: []int{4}, wantFound: "3"},
+		{name: "Stops at hop limit", count: "5", wantFound: "5"},
+		{name: "Ignores malformed count", count: "many", wantHops: []int{1}, wantFound: "many"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hops []int
+			var seen []string
+			repo := &MockMessageRepository{
+				ResolveRecipientFn: func(ctx context.Context, email string) (string, error) {
+					return "", domain.ErrRecipientNotFound
+				},
+				SaveInboundMessageFn: func(ctx context.Context, receiverEmail string, msg domain.InboundMessage, files []domain.File) (int64, error) {
+					return 1, nil
+				},
+				GetDeliveryFn: func(ctx context.Context, messageID int64, receiverEmail string) (domain.Delivery, error) {
+					return domain.Delivery{MessageID: messageID, ProfileID: 3, To: receiverEmail, Headers: map[string][]string{}}, nil
+				},
+				SaveOutboundMessageFn: func(ctx context.Context, receiverEmail string, senderProfileID, threadRoot int64, topic, text string, at time.Time, forwardHops int) (int64, error) {
+					hops = append(hops, forwardHops)
+					return 2, nil
+				},
+			}
+			uc := New(repo,
+				headerFilter{header: "X-Forwarded-Count", seen: &seen},
+				redirectFilter{rules: map[string][]string{"a@flintmail.ru": {"someone@gmail.com"}}})
+			uc.EnableOutbound("flintmail.ru")
+
+			msg := domain.InboundMessage{
+				From:    "ivan@example.com",
+				Topic:   "Topic",
+				Headers: map[string][]string{"X-Forwarded-Count": {tt.count}},
+			}
+			if err := uc.ReceiveMessage(context.Background(), msg, []string{"a@flintmail.ru"}); err != nil {
+				t.Fatalf("ReceiveMessage() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(hops, tt.wantHops) {
+				t.Errorf("outbound forward hops = %v, want %v", hops, tt.wantHops)
+			}
+			if len(seen) != 1 || seen[0] != tt.wantFound {
+				t.Errorf("filters saw X-Forwarded-Count %v, want %q", seen, tt.wantFound)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package message
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	"context"
+	"log/slog"
+)
+
+// EventPublisher рассылает изменения почтового ящика подписанным клиентам
+type EventPublisher interface {
+	Publish(event domain.MailEvent)
+}
+
+// SetEventPublisher включает отправку событий об изменениях почтовых ящиков
+func (uc *MessageUcase) SetEventPublisher(events EventPublisher) {
+	uc.events = events
+}
+
+func (uc *MessageUcase) publish(event domain.MailEvent) {
+	if uc.events != nil {
+		uc.events.Publish(event)
+	}
+}
+
+// publishNewMessage сообщает получателю о новом письме во входящих и о новом числе непрочитанных
+func (uc *MessageUcase) publishNewMessage(ctx context.Context, delivery domain.Delivery) {
+	if uc.events == nil {
+		return
+	}
+
+	uc.publish(domain.MailEvent{
+		Type:      domain.EventNewMessage,
+		ProfileID: delivery.ProfileID,
+		MessageID: delivery.MessageID,
+		From:      delivery.From,
+		Topic:     delivery.Topic,
+	})
+	uc.publishUnreadCount(ctx, delivery.ProfileID)
+}
+
+// publishUnreadCount пересчитывает непрочитанные письма во входящих пользователя.
+// Ошибка не влияет на результат операции, вызвавшей пересчет
+func (uc *MessageUcase) publishUnreadCount(ctx context.Context, profileID int64) {
+	const op = "usecase.message.publishUnreadCount"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	if uc.events == nil {
+		return
+	}
+
+	inboxID, err := uc.repo.GetFolderByType(ctx, profileID, string(domain.FolderInbox))
+	if err != nil {
+		log.Warn("failed to get inbox: " + err.Error())
+		return
+	}
+	info, err := uc.repo.GetFolderMessagesInfo(ctx, profileID, inboxID, 0)
+	if err != nil {
+		log.Warn("failed to count unread messages: " + err.Error())
+		return
+	}
+
+	uc.publish(domain.MailEvent{
+		Type:      domain.EventUnreadCount,
+		ProfileID: profileID,
+		FolderID:  inboxID,
+		Unread:    info.MessageUnread,
+	})
+}
@@ -0,0 +1,140 @@
+package message
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"reflect"
+	"testing"
+)
+
+type recordingPublisher struct {
+	events []domain.MailEvent
+}
+
+func (p *recordingPublisher) Publish(event domain.MailEvent) {
+	p.events = append(p.events, event)
+}
+
+func (p *recordingPublisher) types() []domain.EventType {
+	var types []domain.EventType
+	for _, event := range p.events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func newEventsRepo() *MockMessageRepository {
+	return &MockMessageRepository{
+		SaveMessageWithFolderDistributionFn: func(ctx context.Context, receiverProfileEmail string, senderBaseProfileID int64, topic, text string) (int64, error) {
+			return 1, nil
+		},
+		GetDeliveryFn: func(ctx context.Context, messageID int64, receiverEmail string) (domain.Delivery, error) {
+			return domain.Delivery{MessageID: messageID, ProfileID: 7, BaseProfileID: 7, From: "sender@a4mail.ru", Topic: "Topic", Headers: map[string][]string{}}, nil
+		},
+		GetUserFoldersFn: func(ctx context.Context, profileID int64) ([]domain.Folder, error) {
+			return []domain.Folder{{ID: 1, Type: domain.FolderInbox}, {ID: 4, Name: "Спам", Type: domain.FolderSpam}}, nil
+		},
+		AddMessageToFolderFn: func(ctx context.Context, messageID, folderID int64) error {
+			return nil
+		},
+		DeleteMessageFromFolderFn: func(ctx context.Context, profileID, messageID, folderID int64) error {
+			return nil
+		},
+		GetFolderByTypeFn: func(ctx context.Context, profileID int64, folderType string) (int64, error) {
+			return 1, nil
+		},
+		GetFolderMessagesInfoFn: func(ctx context.Context, profileID, folderID, labelID int64) (domain.Messages, error) {
+			return domain.Messages{MessageTotal: 10, MessageUnread: 3}, nil
+		},
+		MoveToFolderFn: func(ctx context.Context, profileID, messageID, folderID int64) error {
+			return nil
+		},
+		SetFlagsFn: func(ctx context.Context, profileID, messageID int64, update domain.FlagsUpdate) error {
+			return nil
+		},
+		RenameFolderFn: func(ctx context.Context, profileID, folderID int64, newName string) (*domain.Folder, error) {
+			return &domain.Folder{ID: folderID, Name: newName}, nil
+		},
+	}
+}
+
+func TestMessageUcase_Events(t *testing.T) {
+	read := true
+	starred := true
+
+	tests := []struct {
+		name    string
+		filters []DeliveryFilter
+		run     func(uc *MessageUcase) error
+		want    []domain.EventType
+	}{
+		{
+			name: "New message without filters",
+			run: func(uc *MessageUcase) error {
+				_, err := uc.SendMessage(context.Background(), "receiver@a4mail.ru", 3, "Topic", "Text")
+				return err
+			},
+			want: []domain.EventType{domain.EventNewMessage, domain.EventUnreadCount},
+		},
+		{
+			name:    "Message filed to spam is not announced",
+			filters: []DeliveryFilter{stubFilter{action: &domain.DeliveryAction{Folders: []string{"spam"}}}},
+			run: func(uc *MessageUcase) error {
+				_, err := uc.SendMessage(context.Background(), "receiver@a4mail.ru", 3, "Topic", "Text")
+				return err
+			},
+		},
+		{
+			name: "Move",
+			run: func(uc *MessageUcase) error {
+				return uc.MoveToFolder(context.Background(), 7, 1, 4)
+			},
+			want: []domain.EventType{domain.EventMessageMoved, domain.EventUnreadCount},
+		},
+		{
+			name: "Read flag changes unread count",
+			run: func(uc *MessageUcase) error {
+				return uc.SetFlags(context.Background(), 7, 1, domain.FlagsUpdate{Read: &read})
+			},
+			want: []domain.EventType{domain.EventFlagsChanged, domain.EventUnreadCount},
+		},
+		{
+			name: "Star does not change unread count",
+			run: func(uc *MessageUcase) error {
+				return uc.SetFlags(context.Background(), 7, 1, domain.FlagsUpdate{Starred: &starred})
+			},
+			want: []domain.EventType{domain.EventFlagsChanged},
+		},
+		{
+			name: "Rename folder",
+			run: func(uc *MessageUcase) error {
+				_, err := uc.RenameFolder(context.Background(), 7, 10, "Work")
+				return err
+			},
+			want: []domain.EventType{domain.EventFolderRenamed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := &recordingPublisher{}
+			uc := New(newEventsRepo(), tt.filters...)
+			uc.SetEventPublisher(publisher)
+
+			if err := tt.run(uc); err != nil {
+				t.Fatalf("unexpected error = %v", err)
+			}
+			if got := publisher.types(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("published %v, want %v", got, tt.want)
+			}
+			for _, event := range publisher.events {
+				if event.ProfileID != 7 {
+					t.Errorf("event %s published to profile %d, want 7", event.Type, event.ProfileID)
+				}
+				if event.Type == domain.EventUnreadCount && event.Unread != 3 {
+					t.Errorf("unread = %d, want 3", event.Unread)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package message
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/lib/mailbuild"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/mail"
+	"path"
+	"strings"
+)
+
+var errNoAttachmentStorage = errors.New("attachment storage is not configured")
+
+// ExportMessage собирает письмо пользователя в формате RFC 5322 для почтовых клиентов.
+// Разделитель частей зависит только от письма, поэтому при каждом вызове получается
+// одно и то же письмо: клиенты могут читать его по частям
+func (uc *MessageUcase) ExportMessage(ctx context.Context, profileID, messageID int64) ([]byte, error) {
+	const op = "usecase.message.ExportMessage"
+
+	owned, err := uc.repo.IsUsersMessage(ctx, messageID, profileID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	if !owned {
+		return nil, domain.ErrMessageNotFound
+	}
+
+	msg, err := uc.repo.FindFullByMessageID(ctx, messageID, profileID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	recipients, err := uc.repo.FindRecipients(ctx, messageID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	attachments := make([]domain.Attachment, 0, len(msg.Files))
+	for _, file := range msg.Files {
+		attachment, err := uc.loadAttachment(ctx, file)
+		if err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	headers := map[string]string{}
+	if msg.AuthenticationResults != "" {
+		headers["Authentication-Results"] = msg.AuthenticationResults
+	}
+
+	// Тема и текст хранятся экранированными для веб-интерфейса
+	_, senderDomain, _ := strings.Cut(msg.Sender.Email, "@")
+	data, err := mailbuild.Build(mailbuild.Message{
+		From:        mail.Address{Name: msg.Sender.Username, Address: msg.Sender.Email},
+		To:          strings.Join(recipients, ", "),
+		Subject:     html.UnescapeString(msg.Topic),
+		Text:        html.UnescapeString(msg.Text),
+		Date:        msg.Datetime,
+		MessageID:   fmt.Sprintf("<%d@%s>", messageID, senderDomain),
+		Headers:     headers,
+		Attachments: attachments,
+		Boundary:    fmt.Sprintf("=_flintmail_%d", messageID),
+	})
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	return data, nil
+}
+
+func (uc *MessageUcase) loadAttachment(ctx context.Context, file domain.File) (domain.Attachment, error) {
+	if uc.storage == nil {
+		return domain.Attachment{}, errNoAttachmentStorage
+	}
+
+	object, err := uc.storage.GetFile(ctx, file.StoragePath)
+	if err != nil {
+		return domain.Attachment{}, fmt.Errorf("attachment %s: %w", file.StoragePath, err)
+	}
+	defer object.Close()
+
+	data, err := io.ReadAll(object)
+	if err != nil {
+		return domain.Attachment{}, fmt.Errorf("attachment %s: %w", file.StoragePath, err)
+	}
+
+	name := path.Base(file.StoragePath)
+	return domain.Attachment{Name: name, ContentType: mailbuild.ContentType(name), Data: data}, nil
+}
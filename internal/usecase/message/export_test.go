@@ -0,0 +1,88 @@
+package message
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/lib/mailparse"
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newExportRepo(owned bool) *MockMessageRepository {
+	return &MockMessageRepository{
+		IsUsersMessageFn: func(ctx context.Context, messageID int64, profileID int64) (bool, error) {
+			return owned, nil
+		},
+		FindFullByMessageIDFn: func(ctx context.Context, messageID int64, profileID int64) (domain.FullMessage, error) {
+			return domain.FullMessage{
+				ID:                    "42",
+				Topic:                 "Отчет &amp; планы",
+				Text:                  "Привет!\n&lt;b&gt;текст&lt;/b&gt;",
+				Datetime:              time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC),
+				AuthenticationResults: "mx.flintmail.ru; dkim=pass header.d=example.com",
+				Sender:                domain.Sender{Email: "ivan@example.com", Username: "Иван Петров"},
+				Files:                 domain.Files{{StoragePath: "inbound/42/report.pdf"}},
+			}, nil
+		},
+		FindRecipientsFn: func(ctx context.Context, messageID int64) ([]string, error) {
+			return []string{"alexey@flintmail.ru", "maria@flintmail.ru"}, nil
+		},
+	}
+}
+
+func TestMessageUcase_ExportMessage(t *testing.T) {
+	uc := New(newExportRepo(true))
+	uc.SetAttachmentStorage(&memoryStorage{objects: map[string]string{"inbound/42/report.pdf": "%PDF-1.4"}})
+
+	raw, err := uc.ExportMessage(context.Background(), 1, 42)
+	if err != nil {
+		t.Fatalf("ExportMessage() error = %v", err)
+	}
+
+	msg, err := mailparse.Parse(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if msg.From != "ivan@example.com" || msg.FromName != "Иван Петров" {
+		t.Errorf("From = %q <%s>", msg.FromName, msg.From)
+	}
+	if msg.Topic != "Отчет & планы" || msg.Text != "Привет!\n<b>текст</b>" {
+		t.Errorf("Topic = %q, Text = %q", msg.Topic, msg.Text)
+	}
+	if got := msg.Headers["To"]; len(got) != 1 || got[0] != "alexey@flintmail.ru, maria@flintmail.ru" {
+		t.Errorf("To = %v", got)
+	}
+	if got := msg.Headers["Message-Id"]; len(got) != 1 || got[0] != "<42@example.com>" {
+		t.Errorf("Message-Id = %v", got)
+	}
+	if got := msg.Headers["Authentication-Results"]; len(got) != 1 {
+		t.Errorf("Authentication-Results = %v", got)
+	}
+	if len(msg.Attachments) != 1 || msg.Attachments[0].Name != "report.pdf" || string(msg.Attachments[0].Data) != "%PDF-1.4" {
+		t.Errorf("Attachments = %+v", msg.Attachments)
+	}
+
+	again, err := uc.ExportMessage(context.Background(), 1, 42)
+	if err != nil || !bytes.Equal(raw, again) {
+		t.Errorf("ExportMessage() is not reproducible, error = %v", err)
+	}
+}
+
+func TestMessageUcase_ExportMessage_Errors(t *testing.T) {
+	t.Run("ForeignMessage", func(t *testing.T) {
+		uc := New(newExportRepo(false))
+		if _, err := uc.ExportMessage(context.Background(), 1, 42); !errors.Is(err, domain.ErrMessageNotFound) {
+			t.Errorf("ExportMessage() error = %v, want %v", err, domain.ErrMessageNotFound)
+		}
+	})
+
+	t.Run("MissingAttachment", func(t *testing.T) {
+		uc := New(newExportRepo(true))
+		uc.SetAttachmentStorage(&memoryStorage{objects: map[string]string{}})
+		if _, err := uc.ExportMessage(context.Background(), 1, 42); !errors.Is(err, domain.ErrFileNotFound) {
+			t.Errorf("ExportMessage() error = %v, want %v", err, domain.ErrFileNotFound)
+		}
+	})
+}
@@ -0,0 +1,30 @@
+package message
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+)
+
+// SetFlags меняет флаги письма пользователя, в том числе позволяет снова сделать письмо непрочитанным
+func (uc *MessageUcase) SetFlags(ctx context.Context, profileID, messageID int64, update domain.FlagsUpdate) error {
+	if update.Empty() {
+		return domain.ErrNoFlags
+	}
+	if err := uc.repo.SetFlags(ctx, profileID, messageID, update); err != nil {
+		return err
+	}
+
+	uc.publish(domain.MailEvent{Type: domain.EventFlagsChanged, ProfileID: profileID, MessageID: messageID, Flags: update})
+	if update.Read != nil {
+		uc.publishUnreadCount(ctx, profileID)
+	}
+	return nil
+}
+
+func (uc *MessageUcase) GetStarredMessagesWithKeysetPagination(ctx context.Context, profileID int64, page domain.MessagePage) ([]domain.Message, error) {
+	return uc.repo.GetStarredMessagesWithKeysetPagination(ctx, profileID, page)
+}
+
+func (uc *MessageUcase) GetStarredMessagesInfo(ctx context.Context, profileID int64) (domain.Messages, error) {
+	return uc.repo.GetStarredMessagesInfo(ctx, profileID)
+}
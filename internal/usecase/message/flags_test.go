@@ -0,0 +1,43 @@
+package message
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMessageUcase_SetFlags(t *testing.T) {
+	t.Run("Passes update to repository", func(t *testing.T) {
+		unread := false
+		var got domain.FlagsUpdate
+		repo := &MockMessageRepository{
+			SetFlagsFn: func(ctx context.Context, profileID, messageID int64, update domain.FlagsUpdate) error {
+				got = update
+				return nil
+			},
+		}
+
+		err := New(repo).SetFlags(context.Background(), 1, 2, domain.FlagsUpdate{Read: &unread})
+		if err != nil {
+			t.Fatalf("SetFlags() error = %v", err)
+		}
+		if got.Read == nil || *got.Read || got.Starred != nil {
+			t.Errorf("SetFlags() passed %+v", got)
+		}
+	})
+
+	t.Run("Empty update", func(t *testing.T) {
+		repo := &MockMessageRepository{
+			SetFlagsFn: func(ctx context.Context, profileID, messageID int64, update domain.FlagsUpdate) error {
+				t.Error("repository must not be called")
+				return nil
+			},
+		}
+
+		err := New(repo).SetFlags(context.Background(), 1, 2, domain.FlagsUpdate{})
+		if !errors.Is(err, domain.ErrNoFlags) {
+			t.Errorf("SetFlags() error = %v, want %v", err, domain.ErrNoFlags)
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package message
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+)
+
+func (uc *MessageUcase) CreateSubfolder(ctx context.Context, profileID, parentID int64, folderName string) (*domain.Folder, error) {
+	return uc.repo.CreateSubfolder(ctx, profileID, parentID, folderName)
+}
+
+// MoveFolder переносит папку под newParentID, перенос папки в саму себя отклоняется без обращения к базе
+func (uc *MessageUcase) MoveFolder(ctx context.Context, profileID, folderID, newParentID int64) (*domain.Folder, error) {
+	if folderID == newParentID {
+		return nil, domain.ErrInvalidFolderParent
+	}
+	return uc.repo.MoveFolder(ctx, profileID, folderID, newParentID)
+}
+
+// GetFolderTree возвращает папки пользователя в виде дерева с сохранением порядка GetUserFolders
+func (uc *MessageUcase) GetFolderTree(ctx context.Context, profileID int64) ([]domain.FolderNode, error) {
+	folders, err := uc.repo.GetUserFolders(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+	return buildFolderTree(folders), nil
+}
+
+func buildFolderTree(folders []domain.Folder) []domain.FolderNode {
+	known := make(map[int64]bool, len(folders))
+	children := make(map[int64][]domain.Folder, len(folders))
+	for _, folder := range folders {
+		known[folder.ID] = true
+	}
+	for _, folder := range folders {
+		parentID := folder.ParentID
+		// Папка с недоступным родителем показывается на верхнем уровне
+		if !known[parentID] {
+			parentID = 0
+		}
+		children[parentID] = append(children[parentID], folder)
+	}
+
+	var build func(parentID int64, depth int) []domain.FolderNode
+	build = func(parentID int64, depth int) []domain.FolderNode {
+		if depth > domain.MaxFolderDepth {
+			return nil
+		}
+		nodes := make([]domain.FolderNode, 0, len(children[parentID]))
+		for _, folder := range children[parentID] {
+			nodes = append(nodes, domain.FolderNode{
+				Folder:   folder,
+				Children: build(folder.ID, depth+1),
+			})
+		}
+		return nodes
+	}
+
+	return build(0, 1)
+}
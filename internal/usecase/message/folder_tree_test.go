@@ -0,0 +1,88 @@
+package message
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMessageUcase_GetFolderTree(t *testing.T) {
+	t.Run("Builds nested tree", func(t *testing.T) {
+		repo := &MockMessageRepository{
+			GetUserFoldersFn: func(ctx context.Context, profileID int64) ([]domain.Folder, error) {
+				return []domain.Folder{
+					{ID: 1, Name: "Inbox", Type: domain.FolderInbox},
+					{ID: 5, Name: "Clients", Type: domain.FolderCustom},
+					{ID: 6, Name: "Acme", Type: domain.FolderCustom, ParentID: 5},
+					{ID: 7, Name: "Invoices", Type: domain.FolderCustom, ParentID: 6},
+					{ID: 8, Name: "Orphan", Type: domain.FolderCustom, ParentID: 42},
+				}, nil
+			},
+		}
+
+		tree, err := New(repo).GetFolderTree(context.Background(), 1)
+		if err != nil {
+			t.Fatalf("GetFolderTree() error = %v", err)
+		}
+		if len(tree) != 3 {
+			t.Fatalf("GetFolderTree() roots = %d, want 3", len(tree))
+		}
+		if tree[1].Name != "Clients" || len(tree[1].Children) != 1 {
+			t.Fatalf("GetFolderTree() Clients node = %+v", tree[1])
+		}
+		acme := tree[1].Children[0]
+		if acme.Name != "Acme" || len(acme.Children) != 1 || acme.Children[0].Name != "Invoices" {
+			t.Errorf("GetFolderTree() Acme node = %+v", acme)
+		}
+		if tree[2].Name != "Orphan" {
+			t.Errorf("GetFolderTree() last root = %q, want Orphan", tree[2].Name)
+		}
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		repoErr := errors.New("db down")
+		repo := &MockMessageRepository{
+			GetUserFoldersFn: func(ctx context.Context, profileID int64) ([]domain.Folder, error) {
+				return nil, repoErr
+			},
+		}
+
+		_, err := New(repo).GetFolderTree(context.Background(), 1)
+		if !errors.Is(err, repoErr) {
+			t.Errorf("GetFolderTree() error = %v, want %v", err, repoErr)
+		}
+	})
+}
+
+func TestMessageUcase_MoveFolder(t *testing.T) {
+	t.Run("Into itself", func(t *testing.T) {
+		repo := &MockMessageRepository{
+			MoveFolderFn: func(ctx context.Context, profileID, folderID, newParentID int64) (*domain.Folder, error) {
+				t.Error("repository must not be called")
+				return nil, nil
+			},
+		}
+
+		_, err := New(repo).MoveFolder(context.Background(), 1, 5, 5)
+		if !errors.Is(err, domain.ErrInvalidFolderParent) {
+			t.Errorf("MoveFolder() error = %v, want %v", err, domain.ErrInvalidFolderParent)
+		}
+	})
+
+	t.Run("Passes to repository", func(t *testing.T) {
+		repo := &MockMessageRepository{
+			MoveFolderFn: func(ctx context.Context, profileID, folderID, newParentID int64) (*domain.Folder, error) {
+				return &domain.Folder{ID: folderID, ParentID: newParentID}, nil
+			},
+		}
+
+		folder, err := New(repo).MoveFolder(context.Background(), 1, 5, 0)
+		if err != nil {
+			t.Fatalf("MoveFolder() error = %v", err)
+		}
+		if folder.ID != 5 || folder.ParentID != 0 {
+			t.Errorf("MoveFolder() = %+v", folder)
+		}
+	})
+}
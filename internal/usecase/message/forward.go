@@ -0,0 +1,90 @@
+package message
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+)
+
+const (
+	forwardTopicPrefix = "Fwd: "
+	// Ограничение длины темы в таблице message
+	maxForwardTopicLen = 200
+)
+
+// ForwardMessage пересылает письмо sourceID каждому получателю: тема и текст оригинала цитируются
+// в блоке пересылки, вложения привязываются к новым письмам без повторной загрузки.
+// comment ожидается уже экранированным, как и текст при обычной отправке.
+// Возвращает идентификатор последнего созданного письма
+func (uc *MessageUcase) ForwardMessage(ctx context.Context, profileID, sourceID int64, receivers []string, comment string) (int64, error) {
+	original, err := uc.repo.FindFullByMessageID(ctx, sourceID, profileID)
+	if err != nil {
+		return 0, err
+	}
+
+	topic, text := buildForward(original, comment)
+
+	var messageID, threadID int64
+	for _, receiver := range receivers {
+		msgID, err := uc.SendMessage(ctx, receiver, profileID, topic, text)
+		if err != nil {
+			return 0, err
+		}
+
+		// Пересылка начинает новый тред с корнем в первой копии, остальные копии входят в него же
+		if threadID == 0 {
+			if threadID, err = uc.repo.SaveThread(ctx, msgID); err != nil {
+				return 0, err
+			}
+		}
+		if err := uc.repo.SaveThreadIdToMessage(ctx, msgID, threadID); err != nil {
+			return 0, err
+		}
+
+		if _, err := uc.repo.CopyFiles(ctx, sourceID, msgID); err != nil {
+			return 0, err
+		}
+
+		messageID = msgID
+	}
+
+	forwarded := true
+	if err := uc.repo.SetFlags(ctx, profileID, sourceID, domain.FlagsUpdate{Forwarded: &forwarded}); err != nil {
+		return 0, err
+	}
+
+	return messageID, nil
+}
+
+// buildForward формирует тему и текст пересылаемого письма. Тема и текст оригинала хранятся
+// экранированными, а адрес и имя отправителя берутся из профиля и экранируются здесь
+func buildForward(original domain.FullMessage, comment string) (string, string) {
+	topic := original.Topic
+	if !strings.HasPrefix(strings.ToLower(topic), strings.ToLower(forwardTopicPrefix)) {
+		topic = forwardTopicPrefix + topic
+	}
+	if runes := []rune(topic); len(runes) > maxForwardTopicLen {
+		topic = string(runes[:maxForwardTopicLen])
+	}
+
+	from := html.EscapeString(original.Sender.Email)
+	if original.Sender.Username != "" {
+		from = fmt.Sprintf("%s &lt;%s&gt;", html.EscapeString(original.Sender.Username), from)
+	}
+
+	var b strings.Builder
+	if comment != "" {
+		b.WriteString(comment)
+		b.WriteString("\n\n")
+	}
+	b.WriteString("---------- Forwarded message ---------\n")
+	fmt.Fprintf(&b, "From: %s\n", from)
+	fmt.Fprintf(&b, "Date: %s\n", original.Datetime.Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "Subject: %s\n\n", original.Topic)
+	b.WriteString(original.Text)
+
+	return topic, b.String()
+}
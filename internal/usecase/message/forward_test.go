@@ -0,0 +1,121 @@
+package message
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMessageUcase_ForwardMessage(t *testing.T) {
+	original := domain.FullMessage{
+		ID:       "10",
+		Topic:    "Quarterly report",
+		Text:     "Numbers are attached",
+		Datetime: time.Date(2025, 11, 3, 9, 30, 0, 0, time.UTC),
+		Sender:   domain.Sender{Email: "boss@a4code.ru", Username: "Big <Boss>"},
+	}
+
+	t.Run("Sends to every receiver and marks source", func(t *testing.T) {
+		var sentTopics, sentTexts []string
+		var copied, threaded [][2]int64
+		var threads int
+		var flagged *domain.FlagsUpdate
+		nextID := int64(100)
+
+		repo := &MockMessageRepository{
+			FindFullByMessageIDFn: func(ctx context.Context, messageID int64, profileID int64) (domain.FullMessage, error) {
+				return original, nil
+			},
+			SaveMessageWithFolderDistributionFn: func(ctx context.Context, receiverProfileEmail string, senderBaseProfileID int64, topic, text string) (int64, error) {
+				sentTopics = append(sentTopics, topic)
+				sentTexts = append(sentTexts, text)
+				nextID++
+				return nextID, nil
+			},
+			SaveThreadFn: func(ctx context.Context, messageID int64) (int64, error) {
+				threads++
+				return 7, nil
+			},
+			SaveThreadIdToMessageFn: func(ctx context.Context, messageID, threadID int64) error {
+				threaded = append(threaded, [2]int64{messageID, threadID})
+				return nil
+			},
+			CopyFilesFn: func(ctx context.Context, fromMessageID, toMessageID int64) (int64, error) {
+				copied = append(copied, [2]int64{fromMessageID, toMessageID})
+				return 1, nil
+			},
+			SetFlagsFn: func(ctx context.Context, profileID, messageID int64, update domain.FlagsUpdate) error {
+				flagged = &update
+				return nil
+			},
+		}
+
+		msgID, err := New(repo).ForwardMessage(context.Background(), 1, 10, []string{"a@a4code.ru", "b@a4code.ru"}, "FYI")
+		if err != nil {
+			t.Fatalf("ForwardMessage() error = %v", err)
+		}
+		if msgID != 102 {
+			t.Errorf("ForwardMessage() = %d, want 102", msgID)
+		}
+		if len(sentTopics) != 2 || sentTopics[0] != "Fwd: Quarterly report" {
+			t.Errorf("ForwardMessage() topics = %v", sentTopics)
+		}
+		if !strings.HasPrefix(sentTexts[0], "FYI\n\n---------- Forwarded message") ||
+			!strings.Contains(sentTexts[0], "From: Big &lt;Boss&gt; &lt;boss@a4code.ru&gt;") ||
+			!strings.HasSuffix(sentTexts[0], "Numbers are attached") {
+			t.Errorf("ForwardMessage() text = %q", sentTexts[0])
+		}
+		if threads != 1 {
+			t.Errorf("ForwardMessage() created %d threads, want 1", threads)
+		}
+		if len(threaded) != 2 || threaded[0] != [2]int64{101, 7} || threaded[1] != [2]int64{102, 7} {
+			t.Errorf("ForwardMessage() linked copies to threads %v", threaded)
+		}
+		if len(copied) != 2 || copied[0] != [2]int64{10, 101} || copied[1] != [2]int64{10, 102} {
+			t.Errorf("ForwardMessage() copied files %v", copied)
+		}
+		if flagged == nil || flagged.Forwarded == nil || !*flagged.Forwarded || flagged.Read != nil {
+			t.Errorf("ForwardMessage() flags = %+v", flagged)
+		}
+	})
+
+	t.Run("Source lookup error", func(t *testing.T) {
+		lookupErr := errors.New("not found")
+		repo := &MockMessageRepository{
+			FindFullByMessageIDFn: func(ctx context.Context, messageID int64, profileID int64) (domain.FullMessage, error) {
+				return domain.FullMessage{}, lookupErr
+			},
+			SaveMessageWithFolderDistributionFn: func(ctx context.Context, receiverProfileEmail string, senderBaseProfileID int64, topic, text string) (int64, error) {
+				t.Error("message must not be sent")
+				return 0, nil
+			},
+		}
+
+		_, err := New(repo).ForwardMessage(context.Background(), 1, 10, []string{"a@a4code.ru"}, "")
+		if !errors.Is(err, lookupErr) {
+			t.Errorf("ForwardMessage() error = %v, want %v", err, lookupErr)
+		}
+	})
+}
+
+func TestBuildForward(t *testing.T) {
+	t.Run("Keeps existing prefix", func(t *testing.T) {
+		topic, text := buildForward(domain.FullMessage{Topic: "FWD: hello", Sender: domain.Sender{Email: "x@a4code.ru"}}, "")
+		if topic != "FWD: hello" {
+			t.Errorf("buildForward() topic = %q", topic)
+		}
+		if !strings.HasPrefix(text, "---------- Forwarded message") || !strings.Contains(text, "From: x@a4code.ru\n") {
+			t.Errorf("buildForward() text = %q", text)
+		}
+	})
+
+	t.Run("Truncates long topic", func(t *testing.T) {
+		topic, _ := buildForward(domain.FullMessage{Topic: strings.Repeat("я", maxForwardTopicLen)}, "")
+		if n := len([]rune(topic)); n != maxForwardTopicLen {
+			t.Errorf("buildForward() topic length = %d, want %d", n, maxForwardTopicLen)
+		}
+	})
+}
@@ -0,0 +1,209 @@
+package message
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	"2025_2_a4code/internal/lib/rand"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"bytes"
+	"context"
+	"errors"
+	"html"
+	"io"
+	"log/slog"
+	"net/mail"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// maxTopicLength - ограничение длины темы в таблице message
+	maxTopicLength = 200
+	// maxImageSize - картинки крупнее хранятся как документы, чтобы пройти ограничения таблицы file
+	maxImageSize = 10 << 20
+	// maxFileNameLength оставляет место под префикс в storage_path длиной до 200 символов
+	maxFileNameLength = 100
+)
+
+// AttachmentStorage - хранилище вложений писем
+type AttachmentStorage interface {
+	UploadFile(ctx context.Context, objectName string, data io.Reader, size int64, contentType string) error
+	GetFile(ctx context.Context, objectName string) (io.ReadCloser, error)
+	DeleteFile(ctx context.Context, objectName string) error
+}
+
+// SetAttachmentStorage включает сохранение вложений внешних писем и их выгрузку почтовым клиентам
+func (uc *MessageUcase) SetAttachmentStorage(storage AttachmentStorage) {
+	uc.storage = storage
+}
+
+type inboundMessageKey struct{}
+
+// withInboundMessage передает фильтрам доставки заголовки письма, принятого по SMTP,
+// и результаты проверки его отправителя
+func withInboundMessage(ctx context.Context, msg domain.InboundMessage) context.Context {
+	return context.WithValue(ctx, inboundMessageKey{}, msg)
+}
+
+func inboundMessage(ctx context.Context) (domain.InboundMessage, bool) {
+	msg, ok := ctx.Value(inboundMessageKey{}).(domain.InboundMessage)
+	return msg, ok
+}
+
+// ResolveRecipient проверяет, что адрес принадлежит локальному пользователю,
+// и возвращает его в том виде, в котором он хранится
+func (uc *MessageUcase) ResolveRecipient(ctx context.Context, email string) (string, error) {
+	return uc.repo.ResolveRecipient(ctx, email)
+}
+
+// ReceiveMessage сохраняет письмо внешнего отправителя во входящие каждого получателя
+// и прогоняет его через фильтры доставки. Вложения загружаются в хранилище один раз
+// и разделяются между копиями письма. Ошибка возвращается, только если письмо
+// не удалось доставить ни одному получателю
+func (uc *MessageUcase) ReceiveMessage(ctx context.Context, msg domain.InboundMessage, recipients []string) error {
+	const op = "usecase.message.ReceiveMessage"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	if !validSender(msg.Sender()) {
+		return domain.ErrInvalidSender
+	}
+	msg = normalizeInbound(msg, time.Now())
+
+	files, err := uc.uploadAttachments(ctx, msg.Attachments)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	ctx = withInboundMessage(ctx, msg)
+
+	delivered := 0
+	var lastErr error
+	for _, recipient := range recipients {
+		messageID, err := uc.repo.SaveInboundMessage(ctx, recipient, msg, files)
+		if err != nil {
+			log.Error("failed to save inbound message for " + recipient + ": " + err.Error())
+			lastErr = err
+			continue
+		}
+		delivered++
+		uc.deliver(ctx, messageID, recipient)
+	}
+
+	if delivered == 0 && lastErr != nil {
+		return e.Wrap(op, lastErr)
+	}
+	return nil
+}
+
+func (uc *MessageUcase) uploadAttachments(ctx context.Context, attachments []domain.Attachment) ([]domain.File, error) {
+	if len(attachments) == 0 {
+		return nil, nil
+	}
+	if uc.storage == nil {
+		return nil, errors.New("attachment storage is not configured")
+	}
+
+	dir, err := rand.GenerateRandID()
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]domain.File, 0, len(attachments))
+	for i, attachment := range attachments {
+		name := attachmentObjectName(attachment.Name, i)
+		size := int64(len(attachment.Data))
+		storagePath := "mail/" + dir + "/" + name
+
+		err := uc.storage.UploadFile(ctx, storagePath, bytes.NewReader(attachment.Data), size, attachment.ContentType)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, domain.File{
+			Name:        attachment.Name,
+			FileType:    attachmentFileType(attachment.ContentType, size),
+			Size:        size,
+			StoragePath: storagePath,
+		})
+	}
+
+	return files, nil
+}
+
+// validSender проверяет, что адрес отправителя можно сохранить в base_profile
+func validSender(sender string) bool {
+	address, err := mail.ParseAddress(sender)
+	if err != nil {
+		return false
+	}
+	username, domainName, ok := strings.Cut(address.Address, "@")
+	return ok &&
+		len(username) >= 1 && len(username) <= 50 &&
+		len(domainName) >= 1 && len(domainName) <= 50
+}
+
+// normalizeInbound приводит письмо к ограничениям таблицы message. Тема и текст
+// экранируются так же, как у писем, отправленных из интерфейса
+func normalizeInbound(msg domain.InboundMessage, now time.Time) domain.InboundMessage {
+	topic := []rune(strings.TrimSpace(strings.ToValidUTF8(msg.Topic, "")))
+	if len(topic) > maxTopicLength {
+		topic = topic[:maxTopicLength]
+	}
+	msg.Topic = html.EscapeString(string(topic))
+	// Экранирование удлиняет тему, поэтому обрезаем исходный текст, а не сущности
+	for utf8.RuneCountInString(msg.Topic) > maxTopicLength {
+		topic = topic[:len(topic)-1]
+		msg.Topic = html.EscapeString(string(topic))
+	}
+	msg.Text = html.EscapeString(strings.ToValidUTF8(msg.Text, ""))
+
+	// Дата из заголовка Date не должна выводить письмо в начало списка навсегда
+	if msg.Datetime.IsZero() || msg.Datetime.After(now) {
+		msg.Datetime = now
+	}
+	return msg
+}
+
+// attachmentObjectName оставляет в имени вложения только символы, допустимые в storage_path
+func attachmentObjectName(name string, index int) string {
+	ext := strings.ToLower(path.Ext(name))
+	base := strings.TrimSuffix(name, path.Ext(name))
+
+	sanitize := func(value string) string {
+		return strings.Map(func(r rune) rune {
+			switch {
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+				return r
+			}
+			return '_'
+		}, value)
+	}
+
+	ext = sanitize(strings.TrimPrefix(ext, "."))
+	if ext == "" || len(ext) > 10 {
+		ext = "bin"
+	}
+	base = strings.Trim(sanitize(base), "_")
+	if base == "" {
+		base = "attachment"
+	}
+	if len(base) > maxFileNameLength {
+		base = base[:maxFileNameLength]
+	}
+
+	// Индекс не дает вложениям с одинаковыми именами перезаписать друг друга
+	return strconv.Itoa(index) + "_" + base + "." + ext
+}
+
+func attachmentFileType(contentType string, size int64) string {
+	switch {
+	case strings.HasPrefix(contentType, "image/") && size <= maxImageSize:
+		return "image"
+	case strings.HasPrefix(contentType, "video/"):
+		return "video"
+	}
+	return "document"
+}
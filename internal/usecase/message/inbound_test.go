@@ -0,0 +1,192 @@
+package message
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+type memoryStorage struct {
+	objects map[string]string
+	err     error
+}
+
+func (s *memoryStorage) UploadFile(ctx context.Context, objectName string, data io.Reader, size int64, contentType string) error {
+	if s.err != nil {
+		return s.err
+	}
+	body, _ := io.ReadAll(data)
+	s.objects[objectName] = string(body)
+	return nil
+}
+
+func (s *memoryStorage) GetFile(ctx context.Context, objectName string) (io.ReadCloser, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	body, ok := s.objects[objectName]
+	if !ok {
+		return nil, domain.ErrFileNotFound
+	}
+	return io.NopCloser(strings.NewReader(body)), nil
+}
+
+func (s *memoryStorage) DeleteFile(ctx context.Context, objectName string) error {
+	if s.err != nil {
+		return s.err
+	}
+	delete(s.objects, objectName)
+	return nil
+}
+
+// headerFilter запоминает заголовок письма, который видят фильтры доставки
+type headerFilter struct {
+	header string
+	seen   *[]string
+}
+
+func (f headerFilter) Filter(ctx context.Context, delivery *domain.Delivery) (*domain.DeliveryAction, error) {
+	*f.seen = append(*f.seen, strings.Join(delivery.Headers[f.header], ","))
+	return nil, nil
+}
+
+// authFilter запоминает результаты проверки отправителя, которые видят фильтры доставки
+type authFilter struct {
+	seen *[]domain.Delivery
+}
+
+func (f authFilter) Filter(ctx context.Context, delivery *domain.Delivery) (*domain.DeliveryAction, error) {
+	*f.seen = append(*f.seen, domain.Delivery{SPF: delivery.SPF, DMARC: delivery.DMARC})
+	return nil, nil
+}
+
+func TestMessageUcase_ReceiveMessage(t *testing.T) {
+	msg := domain.InboundMessage{
+		EnvelopeFrom: "bounce@example.com",
+		From:         "ivan@example.com",
+		Topic:        "Отчет",
+		Text:         "Текст",
+		Datetime:     time.Date(2025, 11, 3, 7, 0, 0, 0, time.UTC),
+		Headers:      map[string][]string{"X-Mailer": {"Thunderbird"}},
+		SPF:          domain.SPFResult{Status: domain.SPFPass, Domain: "example.com"},
+		DMARC:        domain.DMARCResult{Status: domain.DMARCPass, Policy: domain.DMARCPolicyNone, Aligned: true},
+		Attachments: []domain.Attachment{
+			{Name: "отчет 2025.pdf", ContentType: "application/pdf", Data: []byte("%PDF")},
+			{Name: "photo.JPG", ContentType: "image/jpeg", Data: []byte("jpeg")},
+		},
+	}
+
+	storage := &memoryStorage{objects: map[string]string{}}
+	var saved []string
+	var savedFiles [][]domain.File
+	var seen []string
+	var seenAuth []domain.Delivery
+	repo := &MockMessageRepository{
+		SaveInboundMessageFn: func(ctx context.Context, receiverEmail string, got domain.InboundMessage, files []domain.File) (int64, error) {
+			if receiverEmail == "gone@flintmail.ru" {
+				return 0, domain.ErrRecipientNotFound
+			}
+			saved = append(saved, receiverEmail)
+			savedFiles = append(savedFiles, files)
+			return int64(len(saved)), nil
+		},
+		GetDeliveryFn: func(ctx context.Context, messageID int64, receiverEmail string) (domain.Delivery, error) {
+			return domain.Delivery{MessageID: messageID, To: receiverEmail, Headers: map[string][]string{}}, nil
+		},
+	}
+	uc := New(repo, headerFilter{header: "X-Mailer", seen: &seen}, authFilter{seen: &seenAuth})
+	uc.SetAttachmentStorage(storage)
+
+	err := uc.ReceiveMessage(context.Background(), msg, []string{"alexey@flintmail.ru", "gone@flintmail.ru", "maria@flintmail.ru"})
+
+	if err != nil {
+		t.Fatalf("ReceiveMessage() error = %v", err)
+	}
+	if strings.Join(saved, ",") != "alexey@flintmail.ru,maria@flintmail.ru" {
+		t.Errorf("saved for %v", saved)
+	}
+	if len(storage.objects) != 2 {
+		t.Fatalf("uploaded %d objects, want 2 shared between recipients", len(storage.objects))
+	}
+	files := savedFiles[0]
+	if files[0].StoragePath != savedFiles[1][0].StoragePath {
+		t.Errorf("recipients got different copies of attachment: %q and %q", files[0].StoragePath, savedFiles[1][0].StoragePath)
+	}
+	if !strings.HasSuffix(files[0].StoragePath, "/0_2025.pdf") || files[0].FileType != "document" || files[0].Size != 4 {
+		t.Errorf("file[0] = %+v", files[0])
+	}
+	if !strings.HasSuffix(files[1].StoragePath, "/1_photo.jpg") || files[1].FileType != "image" {
+		t.Errorf("file[1] = %+v", files[1])
+	}
+	if strings.Join(seen, ",") != "Thunderbird,Thunderbird" {
+		t.Errorf("filters saw X-Mailer %v", seen)
+	}
+	if len(seenAuth) != 2 || seenAuth[0].SPF != msg.SPF || seenAuth[0].DMARC != msg.DMARC {
+		t.Errorf("filters saw authentication %+v", seenAuth)
+	}
+}
+
+func TestMessageUcase_ReceiveMessageErrors(t *testing.T) {
+	t.Run("Invalid sender", func(t *testing.T) {
+		uc := New(&MockMessageRepository{})
+
+		err := uc.ReceiveMessage(context.Background(), domain.InboundMessage{From: "not an address"}, []string{"alexey@flintmail.ru"})
+
+		if !errors.Is(err, domain.ErrInvalidSender) {
+			t.Errorf("ReceiveMessage() error = %v, want ErrInvalidSender", err)
+		}
+	})
+
+	t.Run("Upload failure", func(t *testing.T) {
+		repo := &MockMessageRepository{
+			SaveInboundMessageFn: func(ctx context.Context, receiverEmail string, msg domain.InboundMessage, files []domain.File) (int64, error) {
+				t.Error("message saved without attachments")
+				return 0, nil
+			},
+		}
+		uc := New(repo)
+		uc.SetAttachmentStorage(&memoryStorage{err: errors.New("minio is down")})
+		msg := domain.InboundMessage{From: "ivan@example.com", Attachments: []domain.Attachment{{Name: "a.txt"}}}
+
+		if err := uc.ReceiveMessage(context.Background(), msg, []string{"alexey@flintmail.ru"}); err == nil {
+			t.Error("ReceiveMessage() error = nil")
+		}
+	})
+
+	t.Run("No recipient saved", func(t *testing.T) {
+		repo := &MockMessageRepository{
+			SaveInboundMessageFn: func(ctx context.Context, receiverEmail string, msg domain.InboundMessage, files []domain.File) (int64, error) {
+				return 0, errors.New("db error")
+			},
+		}
+		uc := New(repo)
+
+		if err := uc.ReceiveMessage(context.Background(), domain.InboundMessage{From: "ivan@example.com"}, []string{"alexey@flintmail.ru"}); err == nil {
+			t.Error("ReceiveMessage() error = nil")
+		}
+	})
+}
+
+func TestNormalizeInbound(t *testing.T) {
+	now := time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC)
+
+	got := normalizeInbound(domain.InboundMessage{
+		Topic:    "  " + strings.Repeat("я", 195) + "<b>&" + " ",
+		Text:     "<script>ok\xff",
+		Datetime: now.Add(time.Hour),
+	}, now)
+
+	if want := strings.Repeat("я", 195) + "&lt;b"; got.Topic != want {
+		t.Errorf("topic = %q, want %q", got.Topic, want)
+	}
+	if got.Text != "&lt;script&gt;ok" {
+		t.Errorf("text = %q, want %q", got.Text, "&lt;script&gt;ok")
+	}
+	if !got.Datetime.Equal(now) {
+		t.Errorf("datetime = %v, want %v", got.Datetime, now)
+	}
+}
@@ -3,6 +3,7 @@ package message
 import (
 	"2025_2_a4code/internal/domain"
 	"context"
+	"slices"
 	"time"
 )
 
@@ -12,6 +13,7 @@ type MessageRepository interface {
 	FindFullByMessageID(ctx context.Context, messageID int64, profileID int64) (domain.FullMessage, error)
 	SaveMessage(ctx context.Context, receiverProfileEmail string, senderBaseProfileID int64, topic, text string) (int64, error)
 	SaveFile(ctx context.Context, messageID int64, fileName, fileType, storagePath string, size int64) (fileID int64, err error)
+	CopyFiles(ctx context.Context, fromMessageID, toMessageID int64) (int64, error)
 
 	// методы для тредов
 	SaveThread(ctx context.Context, messageID int64) (threadID int64, err error)
@@ -21,6 +23,7 @@ type MessageRepository interface {
 	// методы для работы с сообщениями
 	MarkMessageAsRead(ctx context.Context, messageID int64, profileID int64) error
 	MarkMessageAsSpam(ctx context.Context, messageID int64, profileID int64) error
+	MarkMessageAsNotSpam(ctx context.Context, messageID int64, profileID int64) error
 	IsUsersMessage(ctx context.Context, messageID int64, profileID int64) (bool, error)
 
 	// методы для черновиков
@@ -39,20 +42,65 @@ type MessageRepository interface {
 	RenameFolder(ctx context.Context, profileID, folderID int64, newName string) (*domain.Folder, error)
 	DeleteFolder(ctx context.Context, profileID, folderID int64) error
 	DeleteMessageFromFolder(ctx context.Context, profileID, messageID, folderID int64) error
-	GetFolderMessagesWithKeysetPagination(ctx context.Context, profileID, folderID, lastMessageID int64, lastDatetime time.Time, limit int) ([]domain.Message, error)
-	GetFolderMessagesInfo(ctx context.Context, profileID, folderID int64) (domain.Messages, error)
+	GetFolderMessagesWithKeysetPagination(ctx context.Context, profileID, folderID, labelID int64, page domain.MessagePage) ([]domain.Message, error)
+	GetFolderMessagesInfo(ctx context.Context, profileID, folderID, labelID int64) (domain.Messages, error)
+
+	// методы для флагов
+	SetFlags(ctx context.Context, profileID, messageID int64, update domain.FlagsUpdate) error
+	GetStarredMessagesWithKeysetPagination(ctx context.Context, profileID int64, page domain.MessagePage) ([]domain.Message, error)
+	GetStarredMessagesInfo(ctx context.Context, profileID int64) (domain.Messages, error)
 
 	// методы для отправки сообщений с автоматическим распределением по папкам
 	SaveMessageWithFolderDistribution(ctx context.Context, receiverProfileEmail string, senderBaseProfileID int64, topic, text string) (int64, error)
 	ReplyToMessageWithFolderDistribution(ctx context.Context, receiverEmail string, senderProfileID int64, threadRoot int64, topic, text string) (int64, error)
+
+	// методы для фильтров доставки
+	GetDelivery(ctx context.Context, messageID int64, receiverEmail string) (domain.Delivery, error)
+	AddMessageToFolder(ctx context.Context, messageID, folderID int64) error
+
+	// методы для корзины
+	RestoreFromTrash(ctx context.Context, profileID, messageID int64) (int64, error)
+	EmptyTrash(ctx context.Context, profileID int64) (int64, error)
+	DeletePermanently(ctx context.Context, profileID, messageID int64) error
+	PurgeExpiredMessages(ctx context.Context, now time.Time) (int64, error)
+	DeleteOrphanMessages(ctx context.Context, olderThan time.Time) (int64, []string, error)
+
+	// пакетные операции
+	ApplyBatch(ctx context.Context, profileID int64, batch domain.BatchOperation) (domain.BatchResult, error)
+
+	// методы для вложенных папок
+	CreateSubfolder(ctx context.Context, profileID, parentID int64, folderName string) (*domain.Folder, error)
+	MoveFolder(ctx context.Context, profileID, folderID, newParentID int64) (*domain.Folder, error)
+
+	// методы для адресной книги
+	CollectContact(ctx context.Context, senderBaseProfileID int64, email string, usedAt time.Time) error
+
+	// методы для приема внешней почты
+	ResolveRecipient(ctx context.Context, email string) (string, error)
+	FindRecipients(ctx context.Context, messageID int64) ([]string, error)
+	SaveInboundMessage(ctx context.Context, receiverEmail string, msg domain.InboundMessage, files []domain.File) (int64, error)
+
+	// методы для отправки внешней почты
+	SaveOutboundMessage(ctx context.Context, receiverEmail string, senderBaseProfileID, threadRoot int64, topic, text string, notBefore time.Time, forwardHops int) (int64, error)
+
+	// методы для отправки из почтовых клиентов
+	FindSenderAddresses(ctx context.Context, profileID int64) ([]string, error)
+	SetSenderAddress(ctx context.Context, messageID int64, address string) error
+	SaveSubmission(ctx context.Context, profileID int64, messageIDHeader string, messageID int64) error
+	FindSubmission(ctx context.Context, profileID int64, messageIDHeader string, since time.Time) (int64, error)
 }
 
 type MessageUcase struct {
-	repo MessageRepository
+	repo    MessageRepository
+	filters []DeliveryFilter
+	events  EventPublisher
+	storage AttachmentStorage
+	// localDomain - домен сервиса, задается EnableOutbound
+	localDomain string
 }
 
-func New(repo MessageRepository) *MessageUcase {
-	return &MessageUcase{repo: repo}
+func New(repo MessageRepository, filters ...DeliveryFilter) *MessageUcase {
+	return &MessageUcase{repo: repo, filters: filters}
 }
 
 // базовые методы для сообщений
@@ -87,11 +135,32 @@ func (uc *MessageUcase) FindThreadsByProfileID(ctx context.Context, profileID in
 
 // методы для работы с сообщениями
 func (uc *MessageUcase) MarkMessageAsRead(ctx context.Context, messageID int64, profileID int64) error {
-	return uc.repo.MarkMessageAsRead(ctx, messageID, profileID)
+	if err := uc.repo.MarkMessageAsRead(ctx, messageID, profileID); err != nil {
+		return err
+	}
+
+	read := true
+	uc.publish(domain.MailEvent{Type: domain.EventFlagsChanged, ProfileID: profileID, MessageID: messageID, Flags: domain.FlagsUpdate{Read: &read}})
+	uc.publishUnreadCount(ctx, profileID)
+	return nil
 }
 
 func (uc *MessageUcase) MarkMessageAsSpam(ctx context.Context, messageID int64, profileID int64) error {
-	return uc.repo.MarkMessageAsSpam(ctx, messageID, profileID)
+	if err := uc.repo.MarkMessageAsSpam(ctx, messageID, profileID); err != nil {
+		return err
+	}
+
+	uc.publishUnreadCount(ctx, profileID)
+	return nil
+}
+
+func (uc *MessageUcase) MarkMessageAsNotSpam(ctx context.Context, messageID int64, profileID int64) error {
+	if err := uc.repo.MarkMessageAsNotSpam(ctx, messageID, profileID); err != nil {
+		return err
+	}
+
+	uc.publishUnreadCount(ctx, profileID)
+	return nil
 }
 
 // методы для черновиков
@@ -117,7 +186,40 @@ func (uc *MessageUcase) GetDraft(ctx context.Context, draftID, profileID int64)
 
 // методы для папок
 func (uc *MessageUcase) MoveToFolder(ctx context.Context, profileID, messageID, folderID int64) error {
-	return uc.repo.MoveToFolder(ctx, profileID, messageID, folderID)
+	if err := uc.repo.MoveToFolder(ctx, profileID, messageID, folderID); err != nil {
+		return err
+	}
+
+	uc.publish(domain.MailEvent{Type: domain.EventMessageMoved, ProfileID: profileID, MessageID: messageID, FolderID: folderID})
+	uc.publishUnreadCount(ctx, profileID)
+	return nil
+}
+
+// CopyToFolder добавляет письмо пользователя еще в одну его папку, из прежних папок письмо не убирается
+func (uc *MessageUcase) CopyToFolder(ctx context.Context, profileID, messageID, folderID int64) error {
+	owned, err := uc.repo.IsUsersMessage(ctx, messageID, profileID)
+	if err != nil {
+		return err
+	}
+	if !owned {
+		return domain.ErrMessageNotFound
+	}
+
+	folders, err := uc.repo.GetUserFolders(ctx, profileID)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(folders, func(folder domain.Folder) bool { return folder.ID == folderID }) {
+		return domain.ErrFolderNotFound
+	}
+
+	if err := uc.repo.AddMessageToFolder(ctx, messageID, folderID); err != nil {
+		return err
+	}
+
+	uc.publish(domain.MailEvent{Type: domain.EventMessageCopied, ProfileID: profileID, MessageID: messageID, FolderID: folderID})
+	uc.publishUnreadCount(ctx, profileID)
+	return nil
 }
 
 func (uc *MessageUcase) GetFolderByType(ctx context.Context, profileID int64, folderType string) (int64, error) {
@@ -137,7 +239,13 @@ func (uc *MessageUcase) GetUserFolders(ctx context.Context, profileID int64) ([]
 }
 
 func (uc *MessageUcase) RenameFolder(ctx context.Context, profileID, folderID int64, newName string) (*domain.Folder, error) {
-	return uc.repo.RenameFolder(ctx, profileID, folderID, newName)
+	folder, err := uc.repo.RenameFolder(ctx, profileID, folderID, newName)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.publish(domain.MailEvent{Type: domain.EventFolderRenamed, ProfileID: profileID, FolderID: folderID, FolderName: folder.Name})
+	return folder, nil
 }
 
 func (uc *MessageUcase) DeleteFolder(ctx context.Context, profileID, folderID int64) error {
@@ -148,20 +256,62 @@ func (uc *MessageUcase) DeleteMessageFromFolder(ctx context.Context, profileID,
 	return uc.repo.DeleteMessageFromFolder(ctx, profileID, messageID, folderID)
 }
 
-func (uc *MessageUcase) GetFolderMessagesWithKeysetPagination(ctx context.Context, profileID, folderID, lastMessageID int64, lastDatetime time.Time, limit int) ([]domain.Message, error) {
-	return uc.repo.GetFolderMessagesWithKeysetPagination(ctx, profileID, folderID, lastMessageID, lastDatetime, limit)
+func (uc *MessageUcase) GetFolderMessagesWithKeysetPagination(ctx context.Context, profileID, folderID, labelID int64, page domain.MessagePage) ([]domain.Message, error) {
+	return uc.repo.GetFolderMessagesWithKeysetPagination(ctx, profileID, folderID, labelID, page)
 }
 
-func (uc *MessageUcase) GetFolderMessagesInfo(ctx context.Context, profileID, folderID int64) (domain.Messages, error) {
-	return uc.repo.GetFolderMessagesInfo(ctx, profileID, folderID)
+func (uc *MessageUcase) GetFolderMessagesInfo(ctx context.Context, profileID, folderID, labelID int64) (domain.Messages, error) {
+	return uc.repo.GetFolderMessagesInfo(ctx, profileID, folderID, labelID)
 }
 
 func (uc *MessageUcase) SendMessage(ctx context.Context, receiverEmail string, senderProfileID int64, topic, text string) (int64, error) {
-	return uc.repo.SaveMessageWithFolderDistribution(ctx, receiverEmail, senderProfileID, topic, text)
+	return uc.send(ctx, receiverEmail, senderProfileID, "", topic, text)
+}
+
+// send отправляет письмо от имени профиля. Непустой senderAddress - дополнительный адрес
+// профиля, с которого уходит письмо; он сохраняется до доставки, чтобы его видели
+// фильтры получателя и очередь исходящей почты
+func (uc *MessageUcase) send(ctx context.Context, receiverEmail string, senderProfileID int64, senderAddress, topic, text string) (int64, error) {
+	external, err := uc.isExternal(ctx, receiverEmail)
+	if err != nil {
+		return 0, err
+	}
+	if external {
+		return uc.sendExternal(ctx, receiverEmail, senderProfileID, 0, senderAddress, topic, text)
+	}
+
+	messageID, err := uc.repo.SaveMessageWithFolderDistribution(ctx, receiverEmail, senderProfileID, topic, text)
+	if err != nil {
+		return 0, err
+	}
+	if senderAddress != "" {
+		if err := uc.repo.SetSenderAddress(ctx, messageID, senderAddress); err != nil {
+			return 0, err
+		}
+	}
+
+	uc.collectContact(ctx, senderProfileID, receiverEmail)
+	uc.deliver(ctx, messageID, receiverEmail)
+	return messageID, nil
 }
 
 func (uc *MessageUcase) ReplyToMessage(ctx context.Context, receiverEmail string, senderProfileID int64, threadRoot int64, topic, text string) (int64, error) {
-	return uc.repo.ReplyToMessageWithFolderDistribution(ctx, receiverEmail, senderProfileID, threadRoot, topic, text)
+	external, err := uc.isExternal(ctx, receiverEmail)
+	if err != nil {
+		return 0, err
+	}
+	if external {
+		return uc.sendExternal(ctx, receiverEmail, senderProfileID, threadRoot, "", topic, text)
+	}
+
+	messageID, err := uc.repo.ReplyToMessageWithFolderDistribution(ctx, receiverEmail, senderProfileID, threadRoot, topic, text)
+	if err != nil {
+		return 0, err
+	}
+
+	uc.collectContact(ctx, senderProfileID, receiverEmail)
+	uc.deliver(ctx, messageID, receiverEmail)
+	return messageID, nil
 }
 
 func (uc *MessageUcase) IsUsersMessage(ctx context.Context, messageID int64, profileID int64) (bool, error) {
@@ -26,6 +26,7 @@ type MockMessageRepository struct {
 	FindSentMessagesByProfileIDWithKeysetPaginationFn func(ctx context.Context, profileID int64, lastMessageID int64, lastDatetime time.Time, limit int) ([]domain.Message, error)
 	GetSentMessagesStatsFn                            func(ctx context.Context, profileID int64) (int, int, error)
 	MarkMessageAsSpamFn                               func(ctx context.Context, messageID int64, profileID int64) error
+	MarkMessageAsNotSpamFn                            func(ctx context.Context, messageID int64, profileID int64) error
 	IsUsersMessageFn                                  func(ctx context.Context, messageID int64, profileID int64) (bool, error)
 	SaveDraftFn                                       func(ctx context.Context, profileID int64, draftID, receiverEmail, topic, text string) (int64, error)
 	IsDraftBelongsToUserFn                            func(ctx context.Context, draftID, profileID int64) (bool, error)
@@ -40,10 +41,33 @@ type MockMessageRepository struct {
 	RenameFolderFn                                    func(ctx context.Context, profileID, folderID int64, newName string) (*domain.Folder, error)
 	DeleteFolderFn                                    func(ctx context.Context, profileID, folderID int64) error
 	DeleteMessageFromFolderFn                         func(ctx context.Context, profileID, messageID, folderID int64) error
-	GetFolderMessagesWithKeysetPaginationFn           func(ctx context.Context, profileID, folderID, lastMessageID int64, lastDatetime time.Time, limit int) ([]domain.Message, error)
-	GetFolderMessagesInfoFn                           func(ctx context.Context, profileID, folderID int64) (domain.Messages, error)
+	GetFolderMessagesWithKeysetPaginationFn           func(ctx context.Context, profileID, folderID, labelID int64, page domain.MessagePage) ([]domain.Message, error)
+	GetFolderMessagesInfoFn                           func(ctx context.Context, profileID, folderID, labelID int64) (domain.Messages, error)
 	SaveMessageWithFolderDistributionFn               func(ctx context.Context, receiverProfileEmail string, senderBaseProfileID int64, topic, text string) (int64, error)
 	ReplyToMessageWithFolderDistributionFn            func(ctx context.Context, receiverEmail string, senderProfileID int64, threadRoot int64, topic, text string) (int64, error)
+	GetDeliveryFn                                     func(ctx context.Context, messageID int64, receiverEmail string) (domain.Delivery, error)
+	AddMessageToFolderFn                              func(ctx context.Context, messageID, folderID int64) error
+	RestoreFromTrashFn                                func(ctx context.Context, profileID, messageID int64) (int64, error)
+	EmptyTrashFn                                      func(ctx context.Context, profileID int64) (int64, error)
+	DeletePermanentlyFn                               func(ctx context.Context, profileID, messageID int64) error
+	PurgeExpiredMessagesFn                            func(ctx context.Context, now time.Time) (int64, error)
+	DeleteOrphanMessagesFn                            func(ctx context.Context, olderThan time.Time) (int64, []string, error)
+	ApplyBatchFn                                      func(ctx context.Context, profileID int64, batch domain.BatchOperation) (domain.BatchResult, error)
+	SetFlagsFn                                        func(ctx context.Context, profileID, messageID int64, update domain.FlagsUpdate) error
+	GetStarredMessagesWithKeysetPaginationFn          func(ctx context.Context, profileID int64, page domain.MessagePage) ([]domain.Message, error)
+	GetStarredMessagesInfoFn                          func(ctx context.Context, profileID int64) (domain.Messages, error)
+	CreateSubfolderFn                                 func(ctx context.Context, profileID, parentID int64, folderName string) (*domain.Folder, error)
+	MoveFolderFn                                      func(ctx context.Context, profileID, folderID, newParentID int64) (*domain.Folder, error)
+	CopyFilesFn                                       func(ctx context.Context, fromMessageID, toMessageID int64) (int64, error)
+	CollectContactFn                                  func(ctx context.Context, senderBaseProfileID int64, email string, usedAt time.Time) error
+	ResolveRecipientFn                                func(ctx context.Context, email string) (string, error)
+	FindRecipientsFn                                  func(ctx context.Context, messageID int64) ([]string, error)
+	SaveInboundMessageFn                              func(ctx context.Context, receiverEmail string, msg domain.InboundMessage, files []domain.File) (int64, error)
+	SaveOutboundMessageFn                             func(ctx context.Context, receiverEmail string, senderProfileID, threadRoot int64, topic, text string, notBefore time.Time, forwardHops int) (int64, error)
+	FindSenderAddressesFn                             func(ctx context.Context, profileID int64) ([]string, error)
+	SetSenderAddressFn                                func(ctx context.Context, messageID int64, address string) error
+	SaveSubmissionFn                                  func(ctx context.Context, profileID int64, messageIDHeader string, messageID int64) error
+	FindSubmissionFn                                  func(ctx context.Context, profileID int64, messageIDHeader string, since time.Time) (int64, error)
 }
 
 func (m *MockMessageRepository) FindByMessageID(ctx context.Context, messageID int64) (*domain.Message, error) {
@@ -131,6 +155,13 @@ func (m *MockMessageRepository) MarkMessageAsSpam(ctx context.Context, messageID
 	return nil
 }
 
+func (m *MockMessageRepository) MarkMessageAsNotSpam(ctx context.Context, messageID int64, profileID int64) error {
+	if m.MarkMessageAsNotSpamFn != nil {
+		return m.MarkMessageAsNotSpamFn(ctx, messageID, profileID)
+	}
+	return nil
+}
+
 func (m *MockMessageRepository) IsUsersMessage(ctx context.Context, messageID int64, profileID int64) (bool, error) {
 	if m.IsUsersMessageFn != nil {
 		return m.IsUsersMessageFn(ctx, messageID, profileID)
@@ -229,16 +260,16 @@ func (m *MockMessageRepository) DeleteMessageFromFolder(ctx context.Context, pro
 	return nil
 }
 
-func (m *MockMessageRepository) GetFolderMessagesWithKeysetPagination(ctx context.Context, profileID, folderID, lastMessageID int64, lastDatetime time.Time, limit int) ([]domain.Message, error) {
+func (m *MockMessageRepository) GetFolderMessagesWithKeysetPagination(ctx context.Context, profileID, folderID, labelID int64, page domain.MessagePage) ([]domain.Message, error) {
 	if m.GetFolderMessagesWithKeysetPaginationFn != nil {
-		return m.GetFolderMessagesWithKeysetPaginationFn(ctx, profileID, folderID, lastMessageID, lastDatetime, limit)
+		return m.GetFolderMessagesWithKeysetPaginationFn(ctx, profileID, folderID, labelID, page)
 	}
 	return nil, nil
 }
 
-func (m *MockMessageRepository) GetFolderMessagesInfo(ctx context.Context, profileID, folderID int64) (domain.Messages, error) {
+func (m *MockMessageRepository) GetFolderMessagesInfo(ctx context.Context, profileID, folderID, labelID int64) (domain.Messages, error) {
 	if m.GetFolderMessagesInfoFn != nil {
-		return m.GetFolderMessagesInfoFn(ctx, profileID, folderID)
+		return m.GetFolderMessagesInfoFn(ctx, profileID, folderID, labelID)
 	}
 	return domain.Messages{}, nil
 }
@@ -257,6 +288,167 @@ func (m *MockMessageRepository) ReplyToMessageWithFolderDistribution(ctx context
 	return 0, nil
 }
 
+func (m *MockMessageRepository) GetDelivery(ctx context.Context, messageID int64, receiverEmail string) (domain.Delivery, error) {
+	if m.GetDeliveryFn != nil {
+		return m.GetDeliveryFn(ctx, messageID, receiverEmail)
+	}
+	return domain.Delivery{}, nil
+}
+
+func (m *MockMessageRepository) AddMessageToFolder(ctx context.Context, messageID, folderID int64) error {
+	if m.AddMessageToFolderFn != nil {
+		return m.AddMessageToFolderFn(ctx, messageID, folderID)
+	}
+	return nil
+}
+
+func (m *MockMessageRepository) RestoreFromTrash(ctx context.Context, profileID, messageID int64) (int64, error) {
+	if m.RestoreFromTrashFn != nil {
+		return m.RestoreFromTrashFn(ctx, profileID, messageID)
+	}
+	return 0, nil
+}
+
+func (m *MockMessageRepository) EmptyTrash(ctx context.Context, profileID int64) (int64, error) {
+	if m.EmptyTrashFn != nil {
+		return m.EmptyTrashFn(ctx, profileID)
+	}
+	return 0, nil
+}
+
+func (m *MockMessageRepository) DeletePermanently(ctx context.Context, profileID, messageID int64) error {
+	if m.DeletePermanentlyFn != nil {
+		return m.DeletePermanentlyFn(ctx, profileID, messageID)
+	}
+	return nil
+}
+
+func (m *MockMessageRepository) PurgeExpiredMessages(ctx context.Context, now time.Time) (int64, error) {
+	if m.PurgeExpiredMessagesFn != nil {
+		return m.PurgeExpiredMessagesFn(ctx, now)
+	}
+	return 0, nil
+}
+
+func (m *MockMessageRepository) DeleteOrphanMessages(ctx context.Context, olderThan time.Time) (int64, []string, error) {
+	if m.DeleteOrphanMessagesFn != nil {
+		return m.DeleteOrphanMessagesFn(ctx, olderThan)
+	}
+	return 0, nil, nil
+}
+
+func (m *MockMessageRepository) ApplyBatch(ctx context.Context, profileID int64, batch domain.BatchOperation) (domain.BatchResult, error) {
+	if m.ApplyBatchFn != nil {
+		return m.ApplyBatchFn(ctx, profileID, batch)
+	}
+	return domain.BatchResult{}, nil
+}
+
+func (m *MockMessageRepository) CreateSubfolder(ctx context.Context, profileID, parentID int64, folderName string) (*domain.Folder, error) {
+	if m.CreateSubfolderFn != nil {
+		return m.CreateSubfolderFn(ctx, profileID, parentID, folderName)
+	}
+	return nil, nil
+}
+
+func (m *MockMessageRepository) MoveFolder(ctx context.Context, profileID, folderID, newParentID int64) (*domain.Folder, error) {
+	if m.MoveFolderFn != nil {
+		return m.MoveFolderFn(ctx, profileID, folderID, newParentID)
+	}
+	return nil, nil
+}
+
+func (m *MockMessageRepository) CopyFiles(ctx context.Context, fromMessageID, toMessageID int64) (int64, error) {
+	if m.CopyFilesFn != nil {
+		return m.CopyFilesFn(ctx, fromMessageID, toMessageID)
+	}
+	return 0, nil
+}
+
+func (m *MockMessageRepository) CollectContact(ctx context.Context, senderBaseProfileID int64, email string, usedAt time.Time) error {
+	if m.CollectContactFn != nil {
+		return m.CollectContactFn(ctx, senderBaseProfileID, email, usedAt)
+	}
+	return nil
+}
+
+func (m *MockMessageRepository) ResolveRecipient(ctx context.Context, email string) (string, error) {
+	if m.ResolveRecipientFn != nil {
+		return m.ResolveRecipientFn(ctx, email)
+	}
+	return email, nil
+}
+
+func (m *MockMessageRepository) FindRecipients(ctx context.Context, messageID int64) ([]string, error) {
+	if m.FindRecipientsFn != nil {
+		return m.FindRecipientsFn(ctx, messageID)
+	}
+	return nil, nil
+}
+
+func (m *MockMessageRepository) SaveInboundMessage(ctx context.Context, receiverEmail string, msg domain.InboundMessage, files []domain.File) (int64, error) {
+	if m.SaveInboundMessageFn != nil {
+		return m.SaveInboundMessageFn(ctx, receiverEmail, msg, files)
+	}
+	return 0, nil
+}
+
+func (m *MockMessageRepository) SaveOutboundMessage(ctx context.Context, receiverEmail string, senderProfileID, threadRoot int64, topic, text string, notBefore time.Time, forwardHops int) (int64, error) {
+	if m.SaveOutboundMessageFn != nil {
+		return m.SaveOutboundMessageFn(ctx, receiverEmail, senderProfileID, threadRoot, topic, text, notBefore, forwardHops)
+	}
+	return 0, nil
+}
+
+func (m *MockMessageRepository) FindSenderAddresses(ctx context.Context, profileID int64) ([]string, error) {
+	if m.FindSenderAddressesFn != nil {
+		return m.FindSenderAddressesFn(ctx, profileID)
+	}
+	return nil, nil
+}
+
+func (m *MockMessageRepository) SetSenderAddress(ctx context.Context, messageID int64, address string) error {
+	if m.SetSenderAddressFn != nil {
+		return m.SetSenderAddressFn(ctx, messageID, address)
+	}
+	return nil
+}
+
+func (m *MockMessageRepository) SaveSubmission(ctx context.Context, profileID int64, messageIDHeader string, messageID int64) error {
+	if m.SaveSubmissionFn != nil {
+		return m.SaveSubmissionFn(ctx, profileID, messageIDHeader, messageID)
+	}
+	return nil
+}
+
+func (m *MockMessageRepository) FindSubmission(ctx context.Context, profileID int64, messageIDHeader string, since time.Time) (int64, error) {
+	if m.FindSubmissionFn != nil {
+		return m.FindSubmissionFn(ctx, profileID, messageIDHeader, since)
+	}
+	return 0, domain.ErrMessageNotFound
+}
+
+func (m *MockMessageRepository) SetFlags(ctx context.Context, profileID, messageID int64, update domain.FlagsUpdate) error {
+	if m.SetFlagsFn != nil {
+		return m.SetFlagsFn(ctx, profileID, messageID, update)
+	}
+	return nil
+}
+
+func (m *MockMessageRepository) GetStarredMessagesWithKeysetPagination(ctx context.Context, profileID int64, page domain.MessagePage) ([]domain.Message, error) {
+	if m.GetStarredMessagesWithKeysetPaginationFn != nil {
+		return m.GetStarredMessagesWithKeysetPaginationFn(ctx, profileID, page)
+	}
+	return nil, nil
+}
+
+func (m *MockMessageRepository) GetStarredMessagesInfo(ctx context.Context, profileID int64) (domain.Messages, error) {
+	if m.GetStarredMessagesInfoFn != nil {
+		return m.GetStarredMessagesInfoFn(ctx, profileID)
+	}
+	return domain.Messages{}, nil
+}
+
 func TestNew(t *testing.T) {
 	mockRepo := &MockMessageRepository{}
 	uc := New(mockRepo)
@@ -743,6 +935,59 @@ func TestMessageUcase_MarkMessageAsSpam(t *testing.T) {
 	}
 }
 
+func TestMessageUcase_MarkMessageAsNotSpam(t *testing.T) {
+	type fields struct {
+		repo MessageRepository
+	}
+	type args struct {
+		ctx       context.Context
+		messageID int64
+		profileID int64
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Success",
+			fields: fields{
+				repo: &MockMessageRepository{
+					MarkMessageAsNotSpamFn: func(ctx context.Context, messageID int64, profileID int64) error {
+						return nil
+					},
+				},
+			},
+			args:    args{ctx: context.Background(), messageID: 1, profileID: 1},
+			wantErr: false,
+		},
+		{
+			name: "Failure",
+			fields: fields{
+				repo: &MockMessageRepository{
+					MarkMessageAsNotSpamFn: func(ctx context.Context, messageID int64, profileID int64) error {
+						return mockError
+					},
+				},
+			},
+			args:    args{ctx: context.Background(), messageID: 1, profileID: 1},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &MessageUcase{
+				repo: tt.fields.repo,
+			}
+			err := uc.MarkMessageAsNotSpam(tt.args.ctx, tt.args.messageID, tt.args.profileID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MarkMessageAsNotSpam() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestMessageUcase_SaveDraft(t *testing.T) {
 	type fields struct {
 		repo MessageRepository
@@ -1104,6 +1349,49 @@ func TestMessageUcase_MoveToFolder(t *testing.T) {
 	}
 }
 
+func TestMessageUcase_CopyToFolder(t *testing.T) {
+	newRepo := func(owned bool, added *[]int64) *MockMessageRepository {
+		return &MockMessageRepository{
+			IsUsersMessageFn: func(ctx context.Context, messageID int64, profileID int64) (bool, error) {
+				return owned, nil
+			},
+			GetUserFoldersFn: func(ctx context.Context, profileID int64) ([]domain.Folder, error) {
+				return []domain.Folder{{ID: 1, Type: domain.FolderInbox}, {ID: 7, Type: domain.FolderCustom}}, nil
+			},
+			AddMessageToFolderFn: func(ctx context.Context, messageID, folderID int64) error {
+				*added = append(*added, folderID)
+				return nil
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		owned     bool
+		folderID  int64
+		wantErr   error
+		wantAdded []int64
+	}{
+		{name: "Success", owned: true, folderID: 7, wantAdded: []int64{7}},
+		{name: "ForeignMessage", owned: false, folderID: 7, wantErr: domain.ErrMessageNotFound},
+		{name: "ForeignFolder", owned: true, folderID: 8, wantErr: domain.ErrFolderNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var added []int64
+			uc := New(newRepo(tt.owned, &added))
+
+			err := uc.CopyToFolder(context.Background(), 1, 10, tt.folderID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CopyToFolder() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(added, tt.wantAdded) {
+				t.Errorf("AddMessageToFolder() calls = %v, want %v", added, tt.wantAdded)
+			}
+		})
+	}
+}
+
 func TestMessageUcase_GetFolderByType(t *testing.T) {
 	type fields struct {
 		repo MessageRepository
@@ -1543,12 +1831,11 @@ func TestMessageUcase_GetFolderMessagesWithKeysetPagination(t *testing.T) {
 		repo MessageRepository
 	}
 	type args struct {
-		ctx           context.Context
-		profileID     int64
-		folderID      int64
-		lastMessageID int64
-		lastDatetime  time.Time
-		limit         int
+		ctx       context.Context
+		profileID int64
+		folderID  int64
+		labelID   int64
+		page      domain.MessagePage
 	}
 	tests := []struct {
 		name    string
@@ -1561,12 +1848,12 @@ func TestMessageUcase_GetFolderMessagesWithKeysetPagination(t *testing.T) {
 			name: "Success",
 			fields: fields{
 				repo: &MockMessageRepository{
-					GetFolderMessagesWithKeysetPaginationFn: func(ctx context.Context, profileID, folderID, lastMessageID int64, lastDatetime time.Time, limit int) ([]domain.Message, error) {
+					GetFolderMessagesWithKeysetPaginationFn: func(ctx context.Context, profileID, folderID, labelID int64, page domain.MessagePage) ([]domain.Message, error) {
 						return expectedMessages, nil
 					},
 				},
 			},
-			args:    args{ctx: context.Background(), profileID: 1, folderID: 1, lastMessageID: 0, lastDatetime: time.Time{}, limit: 10},
+			args:    args{ctx: context.Background(), profileID: 1, folderID: 1, page: domain.MessagePage{Limit: 10}},
 			want:    expectedMessages,
 			wantErr: false,
 		},
@@ -1574,12 +1861,12 @@ func TestMessageUcase_GetFolderMessagesWithKeysetPagination(t *testing.T) {
 			name: "Failure",
 			fields: fields{
 				repo: &MockMessageRepository{
-					GetFolderMessagesWithKeysetPaginationFn: func(ctx context.Context, profileID, folderID, lastMessageID int64, lastDatetime time.Time, limit int) ([]domain.Message, error) {
+					GetFolderMessagesWithKeysetPaginationFn: func(ctx context.Context, profileID, folderID, labelID int64, page domain.MessagePage) ([]domain.Message, error) {
 						return nil, mockError
 					},
 				},
 			},
-			args:    args{ctx: context.Background(), profileID: 1, folderID: 1, lastMessageID: 0, lastDatetime: time.Time{}, limit: 10},
+			args:    args{ctx: context.Background(), profileID: 1, folderID: 1, page: domain.MessagePage{Limit: 10}},
 			want:    nil,
 			wantErr: true,
 		},
@@ -1589,7 +1876,7 @@ func TestMessageUcase_GetFolderMessagesWithKeysetPagination(t *testing.T) {
 			uc := &MessageUcase{
 				repo: tt.fields.repo,
 			}
-			got, err := uc.GetFolderMessagesWithKeysetPagination(tt.args.ctx, tt.args.profileID, tt.args.folderID, tt.args.lastMessageID, tt.args.lastDatetime, tt.args.limit)
+			got, err := uc.GetFolderMessagesWithKeysetPagination(tt.args.ctx, tt.args.profileID, tt.args.folderID, tt.args.labelID, tt.args.page)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetFolderMessagesWithKeysetPagination() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -1615,6 +1902,7 @@ func TestMessageUcase_GetFolderMessagesInfo(t *testing.T) {
 		ctx       context.Context
 		profileID int64
 		folderID  int64
+		labelID   int64
 	}
 	tests := []struct {
 		name    string
@@ -1627,7 +1915,7 @@ func TestMessageUcase_GetFolderMessagesInfo(t *testing.T) {
 			name: "Success",
 			fields: fields{
 				repo: &MockMessageRepository{
-					GetFolderMessagesInfoFn: func(ctx context.Context, profileID, folderID int64) (domain.Messages, error) {
+					GetFolderMessagesInfoFn: func(ctx context.Context, profileID, folderID, labelID int64) (domain.Messages, error) {
 						return expectedMessagesInfo, nil
 					},
 				},
@@ -1640,7 +1928,7 @@ func TestMessageUcase_GetFolderMessagesInfo(t *testing.T) {
 			name: "Failure",
 			fields: fields{
 				repo: &MockMessageRepository{
-					GetFolderMessagesInfoFn: func(ctx context.Context, profileID, folderID int64) (domain.Messages, error) {
+					GetFolderMessagesInfoFn: func(ctx context.Context, profileID, folderID, labelID int64) (domain.Messages, error) {
 						return domain.Messages{}, mockError
 					},
 				},
@@ -1655,7 +1943,7 @@ func TestMessageUcase_GetFolderMessagesInfo(t *testing.T) {
 			uc := &MessageUcase{
 				repo: tt.fields.repo,
 			}
-			got, err := uc.GetFolderMessagesInfo(tt.args.ctx, tt.args.profileID, tt.args.folderID)
+			got, err := uc.GetFolderMessagesInfo(tt.args.ctx, tt.args.profileID, tt.args.folderID, tt.args.labelID)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetFolderMessagesInfo() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -0,0 +1,58 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"2025_2_a4code/internal/domain"
+)
+
+// outboundHold - задержка перед первой попыткой отправки на внешний адрес.
+// Вложения сохраняются уже после создания письма, и к отправке они должны быть в базе
+const outboundHold = 30 * time.Second
+
+// EnableOutbound включает отправку писем на адреса вне localDomain через очередь исходящей почты.
+// Без этого письма на неизвестные адреса завершаются ошибкой, как и раньше
+func (uc *MessageUcase) EnableOutbound(localDomain string) {
+	uc.localDomain = localDomain
+}
+
+// isExternal сообщает, что письмо на receiverEmail нужно отправить на внешний сервер.
+// Адреса локальных пользователей на любых доменах доставляются напрямую
+func (uc *MessageUcase) isExternal(ctx context.Context, receiverEmail string) (bool, error) {
+	if uc.localDomain == "" {
+		return false, nil
+	}
+	_, receiverDomain, ok := strings.Cut(receiverEmail, "@")
+	if !ok || strings.EqualFold(receiverDomain, uc.localDomain) {
+		return false, nil
+	}
+
+	_, err := uc.repo.ResolveRecipient(ctx, receiverEmail)
+	switch {
+	case err == nil:
+		return false, nil
+	case errors.Is(err, domain.ErrRecipientNotFound):
+		return true, nil
+	default:
+		return false, err
+	}
+}
+
+// sendExternal сохраняет письмо в отправленные и ставит его в очередь исходящей почты
+func (uc *MessageUcase) sendExternal(ctx context.Context, receiverEmail string, senderBaseProfileID, threadRoot int64, senderAddress, topic, text string) (int64, error) {
+	messageID, err := uc.repo.SaveOutboundMessage(ctx, receiverEmail, senderBaseProfileID, threadRoot, topic, text, time.Now().Add(outboundHold), forwardHops(ctx))
+	if err != nil {
+		return 0, err
+	}
+	if senderAddress != "" {
+		if err := uc.repo.SetSenderAddress(ctx, messageID, senderAddress); err != nil {
+			return 0, err
+		}
+	}
+
+	uc.collectContact(ctx, senderBaseProfileID, receiverEmail)
+	return messageID, nil
+}
@@ -0,0 +1,96 @@
+package message
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMessageUcase_SendMessage_Outbound(t *testing.T) {
+	tests := []struct {
+		name         string
+		localDomain  string
+		receiver     string
+		resolveErr   error
+		wantOutbound bool
+		wantErr      bool
+	}{
+		{name: "ExternalAddress", localDomain: "flintmail.ru", receiver: "someone@gmail.com", resolveErr: domain.ErrRecipientNotFound, wantOutbound: true},
+		{name: "LocalUserOnOtherDomain", localDomain: "flintmail.ru", receiver: "ivan@a4mail.ru"},
+		{name: "LocalDomain", localDomain: "flintmail.ru", receiver: "maria@flintmail.ru", resolveErr: domain.ErrRecipientNotFound},
+		{name: "OutboundDisabled", receiver: "someone@gmail.com", resolveErr: domain.ErrRecipientNotFound},
+		{name: "ResolveFailed", localDomain: "flintmail.ru", receiver: "someone@gmail.com", resolveErr: errors.New("db is down"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var local, outbound int
+			var notBefore time.Time
+			repo := &MockMessageRepository{
+				ResolveRecipientFn: func(ctx context.Context, email string) (string, error) {
+					return email, tt.resolveErr
+				},
+				SaveMessageWithFolderDistributionFn: func(ctx context.Context, receiverProfileEmail string, senderBaseProfileID int64, topic, text string) (int64, error) {
+					local++
+					return 1, nil
+				},
+				SaveOutboundMessageFn: func(ctx context.Context, receiverEmail string, senderProfileID, threadRoot int64, topic, text string, at time.Time, forwardHops int) (int64, error) {
+					outbound++
+					notBefore = at
+					return 2, nil
+				},
+			}
+			uc := New(repo)
+			uc.EnableOutbound(tt.localDomain)
+
+			_, err := uc.SendMessage(context.Background(), tt.receiver, 1, "topic", "text")
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SendMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.wantOutbound {
+				if outbound != 1 || local != 0 {
+					t.Errorf("outbound = %d, local = %d, want outbound only", outbound, local)
+				}
+				if !notBefore.After(time.Now()) {
+					t.Errorf("notBefore = %v, want a hold in the future", notBefore)
+				}
+			} else if outbound != 0 || local != 1 {
+				t.Errorf("outbound = %d, local = %d, want local only", outbound, local)
+			}
+		})
+	}
+}
+
+func TestMessageUcase_ReplyToMessage_Outbound(t *testing.T) {
+	var gotThread int64
+	repo := &MockMessageRepository{
+		ResolveRecipientFn: func(ctx context.Context, email string) (string, error) {
+			return "", domain.ErrRecipientNotFound
+		},
+		SaveOutboundMessageFn: func(ctx context.Context, receiverEmail string, senderProfileID, threadRoot int64, topic, text string, at time.Time, forwardHops int) (int64, error) {
+			gotThread = threadRoot
+			return 2, nil
+		},
+		ReplyToMessageWithFolderDistributionFn: func(ctx context.Context, receiverEmail string, senderProfileID int64, threadRoot int64, topic, text string) (int64, error) {
+			t.Fatal("reply to an external address must not be delivered locally")
+			return 0, nil
+		},
+	}
+	uc := New(repo)
+	uc.EnableOutbound("flintmail.ru")
+
+	messageID, err := uc.ReplyToMessage(context.Background(), "someone@gmail.com", 1, 42, "Re: topic", "text")
+
+	if err != nil || messageID != 2 {
+		t.Fatalf("ReplyToMessage() = %d, %v", messageID, err)
+	}
+	if gotThread != 42 {
+		t.Errorf("threadRoot = %d, want 42", gotThread)
+	}
+}
@@ -0,0 +1,98 @@
+package message
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// submissionWindow - сколько после отправки клиент может положить копию письма
+// в "Отправленные" через IMAP APPEND, не создавая второй копии
+const submissionWindow = 24 * time.Hour
+
+// SubmitMessage отправляет письмо, принятое от почтового клиента по SMTP (RFC 6409), тем же путем,
+// что и письма из веб-интерфейса: каждому получателю отдельное письмо с копией в "Отправленных",
+// тред создается по первому из них. Адрес в поле From должен принадлежать профилю,
+// письмо уходит именно с него, в том числе с дополнительного адреса. Возвращает копию первого письма в "Отправленных"
+func (uc *MessageUcase) SubmitMessage(ctx context.Context, profileID int64, msg domain.InboundMessage, recipients []string) (int64, error) {
+	const op = "usecase.message.SubmitMessage"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	addresses, err := uc.repo.FindSenderAddresses(ctx, profileID)
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+	senderAddress := findAddress(addresses, msg.From)
+	if senderAddress == "" {
+		return 0, domain.ErrSenderNotAllowed
+	}
+
+	msg = normalizeInbound(msg, time.Now())
+	files, err := uc.uploadAttachments(ctx, msg.Attachments)
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	var firstID int64
+	for _, recipient := range recipients {
+		messageID, err := uc.send(ctx, recipient, profileID, senderAddress, msg.Topic, msg.Text)
+		if err != nil {
+			return 0, e.Wrap(op, err)
+		}
+
+		if firstID == 0 {
+			threadID, err := uc.repo.SaveThread(ctx, messageID)
+			if err != nil {
+				return 0, e.Wrap(op, err)
+			}
+			if err := uc.repo.SaveThreadIdToMessage(ctx, messageID, threadID); err != nil {
+				return 0, e.Wrap(op, err)
+			}
+			firstID = messageID
+		}
+
+		for _, file := range files {
+			if _, err := uc.repo.SaveFile(ctx, messageID, file.Name, file.FileType, file.StoragePath, file.Size); err != nil {
+				return 0, e.Wrap(op, err)
+			}
+		}
+	}
+
+	// Письмо уже отправлено, поэтому ошибка только записывается в журнал:
+	// в худшем случае APPEND клиента сохранит вторую копию
+	if header := messageIDHeader(msg); header != "" && firstID != 0 {
+		if err := uc.repo.SaveSubmission(ctx, profileID, header, firstID); err != nil {
+			log.Error("failed to save submission: " + err.Error())
+		}
+	}
+
+	return firstID, nil
+}
+
+// FindSubmittedMessage возвращает копию в "Отправленных" недавно отправленного письма
+// с этим Message-Id. Письма нет - domain.ErrMessageNotFound
+func (uc *MessageUcase) FindSubmittedMessage(ctx context.Context, profileID int64, messageIDHeader string) (int64, error) {
+	return uc.repo.FindSubmission(ctx, profileID, strings.TrimSpace(messageIDHeader), time.Now().Add(-submissionWindow))
+}
+
+// findAddress возвращает адрес из списка, совпадающий с address без учета регистра
+func findAddress(addresses []string, address string) string {
+	for _, candidate := range addresses {
+		if strings.EqualFold(candidate, address) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+func messageIDHeader(msg domain.InboundMessage) string {
+	values := msg.Headers["Message-Id"]
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(values[0])
+}
@@ -0,0 +1,146 @@
+package message
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMessageUcase_SubmitMessage(t *testing.T) {
+	msg := domain.InboundMessage{
+		EnvelopeFrom: "alexey@flintmail.ru",
+		From:         "Support@flintmail.ru",
+		Topic:        "Отчет <2025>",
+		Text:         "Текст",
+		Headers:      map[string][]string{"Message-Id": {" <abc@thunderbird> "}},
+		Attachments:  []domain.Attachment{{Name: "report.pdf", ContentType: "application/pdf", Data: []byte("%PDF")}},
+	}
+
+	storage := &memoryStorage{objects: map[string]string{}}
+	var sent []string
+	var threads []int64
+	var files []int64
+	var submission string
+	var submissionID int64
+	senders := map[int64]string{}
+	repo := &MockMessageRepository{
+		FindSenderAddressesFn: func(ctx context.Context, profileID int64) ([]string, error) {
+			return []string{"alexey@flintmail.ru", "support@flintmail.ru"}, nil
+		},
+		SaveMessageWithFolderDistributionFn: func(ctx context.Context, receiverProfileEmail string, senderBaseProfileID int64, topic, text string) (int64, error) {
+			if topic != "Отчет &lt;2025&gt;" {
+				t.Errorf("topic = %q, want escaped", topic)
+			}
+			sent = append(sent, receiverProfileEmail)
+			return int64(10 + len(sent)), nil
+		},
+		SetSenderAddressFn: func(ctx context.Context, messageID int64, address string) error {
+			senders[messageID] = address
+			return nil
+		},
+		SaveThreadFn: func(ctx context.Context, messageID int64) (int64, error) {
+			threads = append(threads, messageID)
+			return 5, nil
+		},
+		SaveFileFn: func(ctx context.Context, messageID int64, fileName, fileType, storagePath string, size int64) (int64, error) {
+			files = append(files, messageID)
+			return 1, nil
+		},
+		SaveSubmissionFn: func(ctx context.Context, profileID int64, messageIDHeader string, messageID int64) error {
+			submission, submissionID = messageIDHeader, messageID
+			return nil
+		},
+	}
+	uc := New(repo)
+	uc.SetAttachmentStorage(storage)
+
+	messageID, err := uc.SubmitMessage(context.Background(), 7, msg, []string{"ivan@flintmail.ru", "maria@flintmail.ru"})
+
+	if err != nil {
+		t.Fatalf("SubmitMessage() error = %v", err)
+	}
+	if messageID != 11 {
+		t.Errorf("messageID = %d, want first sent copy", messageID)
+	}
+	if strings.Join(sent, ",") != "ivan@flintmail.ru,maria@flintmail.ru" {
+		t.Errorf("sent to %v", sent)
+	}
+	if senders[11] != "support@flintmail.ru" || senders[12] != "support@flintmail.ru" {
+		t.Errorf("sender addresses = %v, want both messages sent from the alias", senders)
+	}
+	if len(threads) != 1 || threads[0] != 11 {
+		t.Errorf("threads created for %v, want only the first message", threads)
+	}
+	if len(storage.objects) != 1 || len(files) != 2 {
+		t.Errorf("uploaded %d objects, saved %d files, want one upload shared by both messages", len(storage.objects), len(files))
+	}
+	if submission != "<abc@thunderbird>" || submissionID != 11 {
+		t.Errorf("submission = %q -> %d", submission, submissionID)
+	}
+}
+
+func TestMessageUcase_SubmitMessageErrors(t *testing.T) {
+	t.Run("ForeignSender", func(t *testing.T) {
+		repo := &MockMessageRepository{
+			FindSenderAddressesFn: func(ctx context.Context, profileID int64) ([]string, error) {
+				return []string{"alexey@flintmail.ru"}, nil
+			},
+			SaveMessageWithFolderDistributionFn: func(ctx context.Context, receiverProfileEmail string, senderBaseProfileID int64, topic, text string) (int64, error) {
+				t.Error("message from foreign address was sent")
+				return 0, nil
+			},
+		}
+
+		_, err := New(repo).SubmitMessage(context.Background(), 7, domain.InboundMessage{From: "ceo@flintmail.ru"}, []string{"ivan@flintmail.ru"})
+
+		if !errors.Is(err, domain.ErrSenderNotAllowed) {
+			t.Errorf("error = %v, want ErrSenderNotAllowed", err)
+		}
+	})
+
+	t.Run("SubmissionNotSaved", func(t *testing.T) {
+		repo := &MockMessageRepository{
+			FindSenderAddressesFn: func(ctx context.Context, profileID int64) ([]string, error) {
+				return []string{"alexey@flintmail.ru"}, nil
+			},
+			SaveMessageWithFolderDistributionFn: func(ctx context.Context, receiverProfileEmail string, senderBaseProfileID int64, topic, text string) (int64, error) {
+				return 11, nil
+			},
+			SaveSubmissionFn: func(ctx context.Context, profileID int64, messageIDHeader string, messageID int64) error {
+				return mockError
+			},
+		}
+		msg := domain.InboundMessage{From: "alexey@flintmail.ru", Headers: map[string][]string{"Message-Id": {"<abc@client>"}}}
+
+		messageID, err := New(repo).SubmitMessage(context.Background(), 7, msg, []string{"ivan@flintmail.ru"})
+
+		if err != nil || messageID != 11 {
+			t.Errorf("SubmitMessage() = %d, %v, want sent message despite submission error", messageID, err)
+		}
+	})
+}
+
+func TestMessageUcase_FindSubmittedMessage(t *testing.T) {
+	var since time.Time
+	repo := &MockMessageRepository{
+		FindSubmissionFn: func(ctx context.Context, profileID int64, messageIDHeader string, at time.Time) (int64, error) {
+			if messageIDHeader != "<abc@client>" {
+				return 0, domain.ErrMessageNotFound
+			}
+			since = at
+			return 11, nil
+		},
+	}
+
+	messageID, err := New(repo).FindSubmittedMessage(context.Background(), 7, " <abc@client>")
+
+	if err != nil || messageID != 11 {
+		t.Fatalf("FindSubmittedMessage() = %d, %v", messageID, err)
+	}
+	if window := time.Since(since); window < submissionWindow-time.Minute || window > submissionWindow+time.Minute {
+		t.Errorf("looked up submissions since %v ago, want %v", window, submissionWindow)
+	}
+}
@@ -0,0 +1,57 @@
+package message
+
+import (
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// orphanGracePeriod - письма без папок удаляются не сразу, чтобы не задеть письма,
+// которые еще распределяются по папкам
+const orphanGracePeriod = time.Hour
+
+// RestoreFromTrash возвращает письмо из корзины и отдает идентификатор папки, в которую оно вернулось
+func (uc *MessageUcase) RestoreFromTrash(ctx context.Context, profileID, messageID int64) (int64, error) {
+	return uc.repo.RestoreFromTrash(ctx, profileID, messageID)
+}
+
+func (uc *MessageUcase) EmptyTrash(ctx context.Context, profileID int64) (int64, error) {
+	return uc.repo.EmptyTrash(ctx, profileID)
+}
+
+func (uc *MessageUcase) DeletePermanently(ctx context.Context, profileID, messageID int64) error {
+	return uc.repo.DeletePermanently(ctx, profileID, messageID)
+}
+
+// PurgeExpiredMessages очищает корзину и спам по сроку хранения пользователей
+// и удаляет письма, которые больше не лежат ни в одной папке, вместе с их вложениями в хранилище
+func (uc *MessageUcase) PurgeExpiredMessages(ctx context.Context, now time.Time) error {
+	const op = "usecase.message.PurgeExpiredMessages"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	purged, err := uc.repo.PurgeExpiredMessages(ctx, now)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	deleted, storagePaths, err := uc.repo.DeleteOrphanMessages(ctx, now.Add(-orphanGracePeriod))
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	// Строки файлов уже удалены, поэтому ошибка хранилища не прерывает очистку:
+	// оставшийся объект только занимает место
+	if uc.storage != nil {
+		for _, storagePath := range storagePaths {
+			if err := uc.storage.DeleteFile(ctx, storagePath); err != nil {
+				log.Warn("failed to delete attachment object",
+					slog.String("path", storagePath), slog.String("error", err.Error()))
+			}
+		}
+	}
+
+	log.Info("trash purged", slog.Int64("purged", purged), slog.Int64("deleted", deleted))
+	return nil
+}
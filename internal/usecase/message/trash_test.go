@@ -0,0 +1,97 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMessageUcase_PurgeExpiredMessages(t *testing.T) {
+	now := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Purges and deletes orphans after grace period", func(t *testing.T) {
+		var orphansBefore time.Time
+		repo := &MockMessageRepository{
+			PurgeExpiredMessagesFn: func(ctx context.Context, gotNow time.Time) (int64, error) {
+				if !gotNow.Equal(now) {
+					t.Errorf("PurgeExpiredMessages() now = %v, want %v", gotNow, now)
+				}
+				return 3, nil
+			},
+			DeleteOrphanMessagesFn: func(ctx context.Context, olderThan time.Time) (int64, []string, error) {
+				orphansBefore = olderThan
+				return 2, []string{"inbound/7/report.pdf"}, nil
+			},
+		}
+		storage := &memoryStorage{objects: map[string]string{
+			"inbound/7/report.pdf": "%PDF-1.4",
+			"inbound/8/photo.png":  "PNG",
+		}}
+		uc := New(repo)
+		uc.SetAttachmentStorage(storage)
+
+		if err := uc.PurgeExpiredMessages(context.Background(), now); err != nil {
+			t.Fatalf("PurgeExpiredMessages() error = %v", err)
+		}
+		if want := now.Add(-orphanGracePeriod); !orphansBefore.Equal(want) {
+			t.Errorf("orphans older than %v, want %v", orphansBefore, want)
+		}
+		if _, ok := storage.objects["inbound/7/report.pdf"]; ok {
+			t.Error("attachment of deleted message must be removed from storage")
+		}
+		if _, ok := storage.objects["inbound/8/photo.png"]; !ok {
+			t.Error("unrelated attachment must stay in storage")
+		}
+	})
+
+	t.Run("Storage error does not fail purge", func(t *testing.T) {
+		repo := &MockMessageRepository{
+			DeleteOrphanMessagesFn: func(ctx context.Context, olderThan time.Time) (int64, []string, error) {
+				return 1, []string{"inbound/7/report.pdf"}, nil
+			},
+		}
+		uc := New(repo)
+		uc.SetAttachmentStorage(&memoryStorage{err: errors.New("minio is down")})
+
+		if err := uc.PurgeExpiredMessages(context.Background(), now); err != nil {
+			t.Fatalf("PurgeExpiredMessages() error = %v", err)
+		}
+	})
+
+	t.Run("Purge error stops cleanup", func(t *testing.T) {
+		orphansDeleted := false
+		repo := &MockMessageRepository{
+			PurgeExpiredMessagesFn: func(ctx context.Context, now time.Time) (int64, error) {
+				return 0, errors.New("db error")
+			},
+			DeleteOrphanMessagesFn: func(ctx context.Context, olderThan time.Time) (int64, []string, error) {
+				orphansDeleted = true
+				return 0, nil, nil
+			},
+		}
+
+		if err := New(repo).PurgeExpiredMessages(context.Background(), now); err == nil {
+			t.Fatal("expected error")
+		}
+		if orphansDeleted {
+			t.Error("orphans must not be deleted after purge error")
+		}
+	})
+}
+
+func TestMessageUcase_RestoreFromTrash(t *testing.T) {
+	repo := &MockMessageRepository{
+		RestoreFromTrashFn: func(ctx context.Context, profileID, messageID int64) (int64, error) {
+			return 10, nil
+		},
+	}
+
+	folderID, err := New(repo).RestoreFromTrash(context.Background(), 1, 123)
+	if err != nil {
+		t.Fatalf("RestoreFromTrash() error = %v", err)
+	}
+	if folderID != 10 {
+		t.Errorf("RestoreFromTrash() = %d, want 10", folderID)
+	}
+}
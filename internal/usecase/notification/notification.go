@@ -0,0 +1,73 @@
+package notification
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/lib/events"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"time"
+)
+
+type NotificationRepository interface {
+	GetNotificationTolerance(ctx context.Context, profileID int64) (time.Duration, error)
+}
+
+// EventSource - источник событий почтовых ящиков
+type EventSource interface {
+	Subscribe(profileID, lastEventID int64) *events.Subscription
+}
+
+type NotificationUcase struct {
+	repo   NotificationRepository
+	source EventSource
+}
+
+func New(repo NotificationRepository, source EventSource) *NotificationUcase {
+	return &NotificationUcase{repo: repo, source: source}
+}
+
+// Subscribe возвращает поток событий профиля, начиная после lastEventID. Уведомления о новых
+// письмах, пришедшие раньше notification_tolerance после предыдущего, пропускаются: число
+// непрочитанных приходит всегда, и клиент обновляет папку по нему. Поток закрывается
+// при отмене ctx или если клиент не успевает читать события
+func (uc *NotificationUcase) Subscribe(ctx context.Context, profileID, lastEventID int64) (<-chan domain.MailEvent, error) {
+	const op = "usecase.notification.Subscribe"
+
+	tolerance, err := uc.repo.GetNotificationTolerance(ctx, profileID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	sub := uc.source.Subscribe(profileID, lastEventID)
+	out := make(chan domain.MailEvent)
+	go func() {
+		defer close(out)
+		defer sub.Close()
+
+		var lastNotified time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-sub.Events:
+				if !ok {
+					return
+				}
+				if event.Type == domain.EventNewMessage && tolerance > 0 {
+					if !lastNotified.IsZero() && event.CreatedAt.Sub(lastNotified) < tolerance {
+						continue
+					}
+					lastNotified = event.CreatedAt
+				}
+
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
@@ -0,0 +1,87 @@
+package notification
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/lib/events"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type mockRepo struct {
+	tolerance time.Duration
+	err       error
+}
+
+func (r mockRepo) GetNotificationTolerance(ctx context.Context, profileID int64) (time.Duration, error) {
+	return r.tolerance, r.err
+}
+
+func collect(t *testing.T, ch <-chan domain.MailEvent, n int) []domain.MailEvent {
+	t.Helper()
+	var got []domain.MailEvent
+	for len(got) < n {
+		select {
+		case event := <-ch:
+			got = append(got, event)
+		case <-time.After(time.Second):
+			t.Fatalf("received %d events, want %d", len(got), n)
+		}
+	}
+	return got
+}
+
+func TestNotificationUcase_Subscribe(t *testing.T) {
+	t.Run("Tolerance throttles new messages", func(t *testing.T) {
+		hub := events.NewHub(0)
+		uc := New(mockRepo{tolerance: 30 * time.Second}, hub)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		ch, err := uc.Subscribe(ctx, 1, 0)
+		if err != nil {
+			t.Fatalf("Subscribe() error = %v", err)
+		}
+
+		start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		hub.Publish(domain.MailEvent{Type: domain.EventNewMessage, ProfileID: 1, MessageID: 1, CreatedAt: start})
+		hub.Publish(domain.MailEvent{Type: domain.EventNewMessage, ProfileID: 1, MessageID: 2, CreatedAt: start.Add(10 * time.Second)})
+		hub.Publish(domain.MailEvent{Type: domain.EventUnreadCount, ProfileID: 1, Unread: 2, CreatedAt: start.Add(10 * time.Second)})
+		hub.Publish(domain.MailEvent{Type: domain.EventNewMessage, ProfileID: 1, MessageID: 3, CreatedAt: start.Add(40 * time.Second)})
+
+		got := collect(t, ch, 3)
+		if got[0].MessageID != 1 || got[1].Type != domain.EventUnreadCount || got[2].MessageID != 3 {
+			t.Errorf("Subscribe() events = %+v", got)
+		}
+	})
+
+	t.Run("Cancel closes stream", func(t *testing.T) {
+		hub := events.NewHub(0)
+		uc := New(mockRepo{}, hub)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		ch, err := uc.Subscribe(ctx, 1, 0)
+		if err != nil {
+			t.Fatalf("Subscribe() error = %v", err)
+		}
+		cancel()
+
+		select {
+		case _, ok := <-ch:
+			if ok {
+				t.Error("expected closed stream")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("stream was not closed")
+		}
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		uc := New(mockRepo{err: errors.New("db down")}, events.NewHub(0))
+
+		if _, err := uc.Subscribe(context.Background(), 1, 0); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
@@ -0,0 +1,300 @@
+package outbound
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	"2025_2_a4code/internal/lib/mailbuild"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"net/mail"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultBatchSize    = 20
+	DefaultLease        = 10 * time.Minute
+	DefaultRetryBase    = time.Minute
+	DefaultRetryMax     = 2 * time.Hour
+	DefaultMaxAge       = 72 * time.Hour
+	DefaultDelayWarning = 4 * time.Hour
+	DefaultPollInterval = 30 * time.Second
+)
+
+type OutboundRepository interface {
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboundMessage, error)
+	MarkSent(ctx context.Context, id int64) error
+	MarkDeferred(ctx context.Context, id int64, nextAttempt time.Time, reason string, delayNotified bool) error
+	MarkBounced(ctx context.Context, id int64, reason string) error
+}
+
+// FileStorage - хранилище вложений
+type FileStorage interface {
+	GetFile(ctx context.Context, objectName string) (io.ReadCloser, error)
+}
+
+// Transport передает готовое письмо внешнему серверу. Постоянный отказ -
+// ошибка domain.ErrDeliveryRejected, остальные ошибки считаются временными
+type Transport interface {
+	Send(ctx context.Context, from, to string, data []byte) error
+}
+
+// Signer подписывает готовое письмо ключом домена отправителя (DKIM)
+type Signer interface {
+	Sign(domainName string, msg []byte) ([]byte, error)
+}
+
+// Reporter кладет уведомления о доставке во входящие отправителя
+type Reporter interface {
+	ReceiveMessage(ctx context.Context, msg domain.InboundMessage, recipients []string) error
+}
+
+type Config struct {
+	// Domain - почтовый домен сервиса, от его имени приходят уведомления о доставке
+	Domain    string
+	BatchSize int
+	// Lease - сколько письмо числится за обработчиком. Письма упавшего обработчика
+	// возвращаются в очередь по истечении аренды
+	Lease time.Duration
+	// Интервал между попытками растет от RetryBase вдвое с каждой попыткой, но не больше RetryMax
+	RetryBase time.Duration
+	RetryMax  time.Duration
+	// MaxAge - сколько пытаться доставить письмо, прежде чем вернуть его отправителю
+	MaxAge time.Duration
+	// DelayWarning - через сколько сообщить отправителю, что доставка задерживается
+	DelayWarning time.Duration
+	PollInterval time.Duration
+}
+
+type OutboundUcase struct {
+	repo      OutboundRepository
+	storage   FileStorage
+	transport Transport
+	reporter  Reporter
+	signer    Signer
+	cfg       Config
+	now       func() time.Time
+}
+
+func New(repo OutboundRepository, storage FileStorage, transport Transport, reporter Reporter, cfg Config) *OutboundUcase {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = DefaultLease
+	}
+	if cfg.RetryBase <= 0 {
+		cfg.RetryBase = DefaultRetryBase
+	}
+	if cfg.RetryMax <= 0 {
+		cfg.RetryMax = DefaultRetryMax
+	}
+	if cfg.MaxAge <= 0 {
+		cfg.MaxAge = DefaultMaxAge
+	}
+	if cfg.DelayWarning <= 0 {
+		cfg.DelayWarning = DefaultDelayWarning
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+
+	return &OutboundUcase{
+		repo:      repo,
+		storage:   storage,
+		transport: transport,
+		reporter:  reporter,
+		cfg:       cfg,
+		now:       time.Now,
+	}
+}
+
+// SetSigner включает подпись исходящих писем
+func (uc *OutboundUcase) SetSigner(signer Signer) {
+	uc.signer = signer
+}
+
+// Run обрабатывает очередь, пока не отменен ctx. Полная пачка писем означает,
+// что в очереди могут быть еще письма, поэтому следующая забирается сразу
+func (uc *OutboundUcase) Run(ctx context.Context) {
+	const op = "usecase.outbound.Run"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		processed, err := uc.ProcessQueue(ctx)
+		if err != nil {
+			log.Error("failed to process outbound queue: " + err.Error())
+		}
+
+		if err == nil && processed == uc.cfg.BatchSize {
+			timer.Reset(0)
+		} else {
+			timer.Reset(uc.cfg.PollInterval)
+		}
+	}
+}
+
+// ProcessQueue делает одну попытку доставки для писем, которым пора уходить,
+// и возвращает их количество
+func (uc *OutboundUcase) ProcessQueue(ctx context.Context) (int, error) {
+	const op = "usecase.outbound.ProcessQueue"
+
+	messages, err := uc.repo.ClaimDue(ctx, uc.cfg.BatchSize, uc.cfg.Lease)
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	for _, msg := range messages {
+		if err := uc.process(ctx, msg); err != nil {
+			// Письмо останется в аренде и вернется в очередь, когда она истечет
+			logger.GetLogger(ctx).Error(op+": failed to update outbound message status: "+err.Error(),
+				slog.Int64("outbound_id", msg.ID))
+		}
+	}
+
+	return len(messages), nil
+}
+
+func (uc *OutboundUcase) process(ctx context.Context, msg domain.OutboundMessage) error {
+	const op = "usecase.outbound.process"
+	log := logger.GetLogger(ctx).With(slog.String("op", op), slog.Int64("outbound_id", msg.ID))
+
+	data, err := uc.render(ctx, msg)
+	if err == nil {
+		err = uc.transport.Send(ctx, msg.From, msg.Recipient, data)
+	}
+
+	now := uc.now()
+	age := now.Sub(msg.CreatedAt)
+	switch {
+	case err == nil:
+		log.Info("outbound message sent", slog.Int("attempt", msg.Attempts))
+		return uc.repo.MarkSent(ctx, msg.ID)
+
+	case errors.Is(err, domain.ErrDeliveryRejected), errors.Is(err, domain.ErrFileNotFound):
+		log.Warn("outbound message rejected: " + err.Error())
+		return uc.bounce(ctx, msg, err.Error())
+
+	case age >= uc.cfg.MaxAge:
+		log.Warn("outbound message expired: " + err.Error())
+		return uc.bounce(ctx, msg, fmt.Sprintf("delivery time expired after %d attempts, last error: %s", msg.Attempts, err))
+	}
+
+	log.Info("outbound message deferred: "+err.Error(), slog.Int("attempt", msg.Attempts))
+	delayNotified := msg.DelayNotified
+	if !delayNotified && age >= uc.cfg.DelayWarning {
+		uc.report(ctx, msg, "Delayed Mail (still being retried)", fmt.Sprintf(
+			"Your message to %s has not been delivered yet. The mail system will keep trying until %s.\n\nReason: %s",
+			msg.Recipient, msg.CreatedAt.Add(uc.cfg.MaxAge).Format(time.RFC1123Z), err))
+		delayNotified = true
+	}
+
+	return uc.repo.MarkDeferred(ctx, msg.ID, now.Add(uc.backoff(msg.Attempts)), err.Error(), delayNotified)
+}
+
+func (uc *OutboundUcase) bounce(ctx context.Context, msg domain.OutboundMessage, reason string) error {
+	if err := uc.repo.MarkBounced(ctx, msg.ID, reason); err != nil {
+		return err
+	}
+
+	uc.report(ctx, msg, "Undelivered Mail Returned to Sender", fmt.Sprintf(
+		"Your message to %s could not be delivered.\n\nReason: %s", msg.Recipient, reason))
+	return nil
+}
+
+// report сообщает отправителю о судьбе письма. Ошибка доставки уведомления не влияет на очередь
+func (uc *OutboundUcase) report(ctx context.Context, msg domain.OutboundMessage, topic, text string) {
+	const op = "usecase.outbound.report"
+
+	body := fmt.Sprintf("This is the mail system at %s.\n\n%s\n\n----- Original message -----\nTo: %s\nSubject: %s\nDate: %s\n",
+		uc.cfg.Domain, text, msg.Recipient, html.UnescapeString(msg.Topic), msg.Datetime.Format(time.RFC1123Z))
+
+	notice := domain.InboundMessage{
+		From:     "mailer-daemon@" + uc.cfg.Domain,
+		FromName: "Mail Delivery System",
+		Topic:    topic,
+		Text:     body,
+		Datetime: uc.now(),
+		// Автоответы и автопересылка не срабатывают на уведомления о доставке
+		Headers: map[string][]string{"Auto-Submitted": {"auto-replied"}},
+	}
+	if err := uc.reporter.ReceiveMessage(ctx, notice, []string{msg.From}); err != nil {
+		logger.GetLogger(ctx).Error(op+": failed to report delivery status: "+err.Error(), slog.Int64("outbound_id", msg.ID))
+	}
+}
+
+// backoff - пауза перед попыткой attempt+1
+func (uc *OutboundUcase) backoff(attempt int) time.Duration {
+	delay := uc.cfg.RetryBase
+	for i := 1; i < attempt && delay < uc.cfg.RetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, uc.cfg.RetryMax)
+}
+
+// render собирает письмо для отправки. Тема и текст хранятся экранированными
+// для веб-интерфейса, наружу они уходят в исходном виде
+func (uc *OutboundUcase) render(ctx context.Context, msg domain.OutboundMessage) ([]byte, error) {
+	attachments := make([]domain.Attachment, 0, len(msg.Files))
+	for _, file := range msg.Files {
+		attachment, err := uc.loadAttachment(ctx, file)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	// Сервер получателя продолжит счет пересылок и остановит петлю
+	var headers map[string]string
+	if msg.ForwardHops > 0 {
+		headers = map[string]string{"X-Forwarded-Count": strconv.Itoa(msg.ForwardHops)}
+	}
+
+	_, senderDomain, _ := strings.Cut(msg.From, "@")
+	data, err := mailbuild.Build(mailbuild.Message{
+		From:        mail.Address{Name: msg.FromName, Address: msg.From},
+		To:          msg.Recipient,
+		Subject:     html.UnescapeString(msg.Topic),
+		Text:        html.UnescapeString(msg.Text),
+		Date:        msg.Datetime,
+		MessageID:   fmt.Sprintf("<%d@%s>", msg.MessageID, senderDomain),
+		Headers:     headers,
+		Attachments: attachments,
+	})
+	if err != nil || uc.signer == nil {
+		return data, err
+	}
+	return uc.signer.Sign(senderDomain, data)
+}
+
+func (uc *OutboundUcase) loadAttachment(ctx context.Context, file domain.File) (domain.Attachment, error) {
+	object, err := uc.storage.GetFile(ctx, file.StoragePath)
+	if err != nil {
+		return domain.Attachment{}, fmt.Errorf("attachment %s: %w", file.StoragePath, err)
+	}
+	defer object.Close()
+
+	data, err := io.ReadAll(object)
+	if err != nil {
+		return domain.Attachment{}, fmt.Errorf("attachment %s: %w", file.StoragePath, err)
+	}
+
+	name := path.Base(file.StoragePath)
+	return domain.Attachment{Name: name, ContentType: mailbuild.ContentType(name), Data: data}, nil
+}
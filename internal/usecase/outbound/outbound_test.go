@@ -0,0 +1,313 @@
+package outbound
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/lib/mailparse"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockRepository struct {
+	due      []domain.OutboundMessage
+	sent     []int64
+	bounced  map[int64]string
+	deferred map[int64]deferral
+}
+
+type deferral struct {
+	next          time.Time
+	reason        string
+	delayNotified bool
+}
+
+func newMockRepository(due ...domain.OutboundMessage) *mockRepository {
+	return &mockRepository{due: due, bounced: map[int64]string{}, deferred: map[int64]deferral{}}
+}
+
+func (r *mockRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboundMessage, error) {
+	due := r.due[:min(limit, len(r.due))]
+	r.due = r.due[len(due):]
+	return due, nil
+}
+
+func (r *mockRepository) MarkSent(ctx context.Context, id int64) error {
+	r.sent = append(r.sent, id)
+	return nil
+}
+
+func (r *mockRepository) MarkDeferred(ctx context.Context, id int64, nextAttempt time.Time, reason string, delayNotified bool) error {
+	r.deferred[id] = deferral{next: nextAttempt, reason: reason, delayNotified: delayNotified}
+	return nil
+}
+
+func (r *mockRepository) MarkBounced(ctx context.Context, id int64, reason string) error {
+	r.bounced[id] = reason
+	return nil
+}
+
+type memoryStorage map[string][]byte
+
+func (s memoryStorage) GetFile(ctx context.Context, objectName string) (io.ReadCloser, error) {
+	data, ok := s[objectName]
+	if !ok {
+		return nil, domain.ErrFileNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+type mockTransport struct {
+	err  error
+	sent map[string][]byte
+}
+
+func (t *mockTransport) Send(ctx context.Context, from, to string, data []byte) error {
+	if t.err != nil {
+		return t.err
+	}
+	t.sent[from+" -> "+to] = data
+	return nil
+}
+
+type mockReporter struct {
+	notices []domain.InboundMessage
+	to      []string
+}
+
+func (r *mockReporter) ReceiveMessage(ctx context.Context, msg domain.InboundMessage, recipients []string) error {
+	r.notices = append(r.notices, msg)
+	r.to = append(r.to, recipients...)
+	return nil
+}
+
+// prefixSigner добавляет к письму заголовок с доменом подписи
+type prefixSigner struct{}
+
+func (prefixSigner) Sign(domainName string, msg []byte) ([]byte, error) {
+	return append([]byte("X-Signed-By: "+domainName+"\r\n"), msg...), nil
+}
+
+var now = time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC)
+
+func queued(attempts int, age time.Duration) domain.OutboundMessage {
+	return domain.OutboundMessage{
+		ID:        7,
+		MessageID: 100,
+		From:      "alexey@flintmail.ru",
+		FromName:  "Алексей",
+		Recipient: "someone@gmail.com",
+		Topic:     "Отчет &lt;ноябрь&gt;",
+		Text:      "Цены &amp; сроки",
+		Datetime:  now.Add(-age),
+		Files:     []domain.File{{Name: "report", StoragePath: "mail/abc/0_report.pdf"}},
+		Attempts:  attempts,
+		CreatedAt: now.Add(-age),
+	}
+}
+
+func newUcase(repo *mockRepository, transport *mockTransport, reporter *mockReporter) *OutboundUcase {
+	storage := memoryStorage{"mail/abc/0_report.pdf": []byte("%PDF-1.4")}
+	uc := New(repo, storage, transport, reporter, Config{Domain: "flintmail.ru"})
+	uc.now = func() time.Time { return now }
+	return uc
+}
+
+func TestOutboundUcase_Sent(t *testing.T) {
+	repo := newMockRepository(queued(1, time.Minute))
+	transport := &mockTransport{sent: map[string][]byte{}}
+	reporter := &mockReporter{}
+
+	processed, err := newUcase(repo, transport, reporter).ProcessQueue(context.Background())
+
+	if err != nil || processed != 1 {
+		t.Fatalf("ProcessQueue() = %d, %v", processed, err)
+	}
+	if len(repo.sent) != 1 || repo.sent[0] != 7 {
+		t.Errorf("sent = %v, want [7]", repo.sent)
+	}
+	if len(reporter.notices) != 0 {
+		t.Errorf("unexpected notices: %v", reporter.notices)
+	}
+
+	raw, ok := transport.sent["alexey@flintmail.ru -> someone@gmail.com"]
+	if !ok {
+		t.Fatalf("message was not sent: %v", transport.sent)
+	}
+	parsed, err := mailparse.Parse(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if parsed.Topic != "Отчет <ноябрь>" || parsed.Text != "Цены & сроки" {
+		t.Errorf("message = %q / %q, want unescaped topic and text", parsed.Topic, parsed.Text)
+	}
+	if got := parsed.Headers["Message-Id"]; len(got) != 1 || got[0] != "<100@flintmail.ru>" {
+		t.Errorf("Message-Id = %v", got)
+	}
+	if len(parsed.Attachments) != 1 || parsed.Attachments[0].Name != "0_report.pdf" || string(parsed.Attachments[0].Data) != "%PDF-1.4" {
+		t.Errorf("attachments = %+v", parsed.Attachments)
+	}
+}
+
+func TestOutboundUcase_ForwardedCount(t *testing.T) {
+	msg := queued(1, time.Minute)
+	msg.ForwardHops = 2
+	repo := newMockRepository(msg)
+	transport := &mockTransport{sent: map[string][]byte{}}
+
+	if _, err := newUcase(repo, transport, &mockReporter{}).ProcessQueue(context.Background()); err != nil {
+		t.Fatalf("ProcessQueue() error = %v", err)
+	}
+
+	parsed, err := mailparse.Parse(bytes.NewReader(transport.sent["alexey@flintmail.ru -> someone@gmail.com"]))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := parsed.Headers["X-Forwarded-Count"]; len(got) != 1 || got[0] != "2" {
+		t.Errorf("X-Forwarded-Count = %v, want [2]", got)
+	}
+}
+
+func TestOutboundUcase_Signed(t *testing.T) {
+	repo := newMockRepository(queued(1, time.Minute))
+	transport := &mockTransport{sent: map[string][]byte{}}
+	uc := newUcase(repo, transport, &mockReporter{})
+	uc.SetSigner(prefixSigner{})
+
+	if _, err := uc.ProcessQueue(context.Background()); err != nil {
+		t.Fatalf("ProcessQueue() error = %v", err)
+	}
+
+	raw := transport.sent["alexey@flintmail.ru -> someone@gmail.com"]
+	if !bytes.HasPrefix(raw, []byte("X-Signed-By: flintmail.ru\r\nFrom: ")) {
+		t.Errorf("message is not signed before sending:\n%s", raw)
+	}
+}
+
+func TestOutboundUcase_Failures(t *testing.T) {
+	tests := []struct {
+		name         string
+		msg          domain.OutboundMessage
+		err          error
+		wantBounce   bool
+		wantDelay    time.Duration
+		wantNotices  []string
+		wantNotified bool
+	}{
+		{
+			name:        "Rejected",
+			msg:         queued(1, time.Minute),
+			err:         fmt.Errorf("%w: 550 no such user", domain.ErrDeliveryRejected),
+			wantBounce:  true,
+			wantNotices: []string{"Undelivered Mail Returned to Sender"},
+		},
+		{
+			name:      "FirstRetry",
+			msg:       queued(1, time.Minute),
+			err:       errors.New("connection refused"),
+			wantDelay: DefaultRetryBase,
+		},
+		{
+			name:      "ExponentialBackoff",
+			msg:       queued(4, time.Hour),
+			err:       errors.New("451 try again later"),
+			wantDelay: 8 * DefaultRetryBase,
+		},
+		{
+			name:      "BackoffLimit",
+			msg:       queued(20, time.Hour),
+			err:       errors.New("connection refused"),
+			wantDelay: DefaultRetryMax,
+		},
+		{
+			name:         "DelayWarning",
+			msg:          queued(6, DefaultDelayWarning),
+			err:          errors.New("connection refused"),
+			wantDelay:    32 * DefaultRetryBase,
+			wantNotices:  []string{"Delayed Mail (still being retried)"},
+			wantNotified: true,
+		},
+		{
+			name: "DelayAlreadyReported",
+			msg: func() domain.OutboundMessage {
+				msg := queued(7, 5*time.Hour)
+				msg.DelayNotified = true
+				return msg
+			}(),
+			err:          errors.New("connection refused"),
+			wantDelay:    64 * DefaultRetryBase,
+			wantNotified: true,
+		},
+		{
+			name:        "Expired",
+			msg:         queued(30, DefaultMaxAge),
+			err:         errors.New("connection refused"),
+			wantBounce:  true,
+			wantNotices: []string{"Undelivered Mail Returned to Sender"},
+		},
+		{
+			name: "MissingAttachment",
+			msg: func() domain.OutboundMessage {
+				msg := queued(1, time.Minute)
+				msg.Files[0].StoragePath = "mail/abc/missing.pdf"
+				return msg
+			}(),
+			wantBounce:  true,
+			wantNotices: []string{"Undelivered Mail Returned to Sender"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockRepository(tt.msg)
+			transport := &mockTransport{err: tt.err, sent: map[string][]byte{}}
+			reporter := &mockReporter{}
+
+			if _, err := newUcase(repo, transport, reporter).ProcessQueue(context.Background()); err != nil {
+				t.Fatalf("ProcessQueue() error = %v", err)
+			}
+
+			if len(repo.sent) != 0 {
+				t.Errorf("sent = %v, want none", repo.sent)
+			}
+			if tt.wantBounce {
+				if _, ok := repo.bounced[tt.msg.ID]; !ok {
+					t.Errorf("message was not bounced, deferred = %v", repo.deferred)
+				}
+			} else {
+				got, ok := repo.deferred[tt.msg.ID]
+				if !ok {
+					t.Fatalf("message was not deferred, bounced = %v", repo.bounced)
+				}
+				if delay := got.next.Sub(now); delay != tt.wantDelay {
+					t.Errorf("delay = %v, want %v", delay, tt.wantDelay)
+				}
+				if got.delayNotified != tt.wantNotified {
+					t.Errorf("delayNotified = %v, want %v", got.delayNotified, tt.wantNotified)
+				}
+			}
+
+			if len(reporter.notices) != len(tt.wantNotices) {
+				t.Fatalf("notices = %d, want %d", len(reporter.notices), len(tt.wantNotices))
+			}
+			for i, notice := range reporter.notices {
+				if notice.Topic != tt.wantNotices[i] {
+					t.Errorf("notice topic = %q, want %q", notice.Topic, tt.wantNotices[i])
+				}
+				if notice.From != "mailer-daemon@flintmail.ru" || reporter.to[i] != "alexey@flintmail.ru" {
+					t.Errorf("notice %s -> %s", notice.From, reporter.to[i])
+				}
+				if len(notice.Headers["Auto-Submitted"]) == 0 {
+					t.Errorf("notice is not marked as auto-submitted")
+				}
+				if !strings.Contains(notice.Text, "someone@gmail.com") || !strings.Contains(notice.Text, "Отчет <ноябрь>") {
+					t.Errorf("notice text = %q", notice.Text)
+				}
+			}
+		})
+	}
+}
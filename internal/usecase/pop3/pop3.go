@@ -0,0 +1,133 @@
+package pop3
+
+import (
+	"2025_2_a4code/internal/domain"
+	commonE "2025_2_a4code/internal/lib/errors"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"crypto/md5"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	minAPOPSecretLength = 8
+	maxAPOPSecretLength = 128
+)
+
+// ErrAuthFailed - неизвестный пользователь, выключенный APOP или неверный дайджест
+var ErrAuthFailed = errors.New("apop authentication failed")
+
+type POP3Repository interface {
+	GetSettings(ctx context.Context, profileID int64) (domain.POP3Settings, error)
+	SaveSettings(ctx context.Context, profileID int64, update domain.POP3SettingsUpdate) error
+	FindAPOPSecret(ctx context.Context, username, emailDomain string) (int64, string, error)
+	FindHidden(ctx context.Context, profileID int64) ([]int64, error)
+	Hide(ctx context.Context, profileID int64, messageIDs []int64) error
+}
+
+type POP3Ucase struct {
+	repo POP3Repository
+}
+
+func New(repo POP3Repository) *POP3Ucase {
+	return &POP3Ucase{repo: repo}
+}
+
+func (uc *POP3Ucase) GetSettings(ctx context.Context, profileID int64) (domain.POP3Settings, error) {
+	return uc.repo.GetSettings(ctx, profileID)
+}
+
+// SetSettings проверяет и сохраняет настройки POP3, возвращает обновленные настройки
+func (uc *POP3Ucase) SetSettings(ctx context.Context, profileID int64, update domain.POP3SettingsUpdate) (domain.POP3Settings, error) {
+	const op = "usecase.pop3.SetSettings"
+
+	if update.DeleteAction == "" {
+		update.DeleteAction = domain.POP3DeleteToTrash
+	}
+	if update.DeleteAction != domain.POP3DeleteToTrash && update.DeleteAction != domain.POP3DeleteHide {
+		return domain.POP3Settings{}, fmt.Errorf("%w: unknown delete action %q", domain.ErrInvalidPOP3Settings, update.DeleteAction)
+	}
+
+	if update.APOPSecret != "" {
+		length := utf8.RuneCountInString(update.APOPSecret)
+		if length < minAPOPSecretLength || length > maxAPOPSecretLength {
+			return domain.POP3Settings{}, fmt.Errorf("%w: apop secret must be %d to %d characters long",
+				domain.ErrInvalidPOP3Settings, minAPOPSecretLength, maxAPOPSecretLength)
+		}
+	}
+	if update.APOPEnabled && update.APOPSecret == "" {
+		current, err := uc.repo.GetSettings(ctx, profileID)
+		if err != nil {
+			return domain.POP3Settings{}, e.Wrap(op, err)
+		}
+		if !current.APOPEnabled {
+			return domain.POP3Settings{}, fmt.Errorf("%w: apop secret is required", domain.ErrInvalidPOP3Settings)
+		}
+	}
+
+	if err := uc.repo.SaveSettings(ctx, profileID, update); err != nil {
+		return domain.POP3Settings{}, e.Wrap(op, err)
+	}
+
+	return domain.POP3Settings{DeleteAction: update.DeleteAction, APOPEnabled: update.APOPEnabled}, nil
+}
+
+// AuthenticateAPOP проверяет дайджест команды APOP: MD5 от метки времени из приветствия
+// сервера и секрета пользователя (RFC 1939, 7). Логин - имя пользователя или его полный адрес
+func (uc *POP3Ucase) AuthenticateAPOP(ctx context.Context, login, timestamp, digest string) (int64, error) {
+	const op = "usecase.pop3.AuthenticateAPOP"
+
+	username, emailDomain, ok := strings.Cut(login, "@")
+	if !ok {
+		emailDomain = "flintmail.ru"
+	}
+	if !strings.EqualFold(emailDomain, "flintmail.ru") {
+		return 0, e.Wrap(op, ErrAuthFailed)
+	}
+
+	profileID, secret, err := uc.repo.FindAPOPSecret(ctx, username, "flintmail.ru")
+	if errors.Is(err, commonE.ErrNotFound) {
+		return 0, e.Wrap(op, ErrAuthFailed)
+	}
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+	if secret == "" {
+		return 0, e.Wrap(op, ErrAuthFailed)
+	}
+
+	sum := md5.Sum([]byte(timestamp + secret))
+	expected := hex.EncodeToString(sum[:])
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(strings.ToLower(digest))) != 1 {
+		return 0, e.Wrap(op, ErrAuthFailed)
+	}
+
+	return profileID, nil
+}
+
+// HiddenMessages возвращает письма, удаленные по POP3 без переноса в корзину
+func (uc *POP3Ucase) HiddenMessages(ctx context.Context, profileID int64) (map[int64]bool, error) {
+	ids, err := uc.repo.FindHidden(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	hidden := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		hidden[id] = true
+	}
+	return hidden, nil
+}
+
+// HideMessages скрывает письма от POP3, не удаляя их из папок
+func (uc *POP3Ucase) HideMessages(ctx context.Context, profileID int64, messageIDs []int64) error {
+	if len(messageIDs) == 0 {
+		return nil
+	}
+	return uc.repo.Hide(ctx, profileID, messageIDs)
+}
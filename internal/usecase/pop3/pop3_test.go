@@ -0,0 +1,167 @@
+package pop3
+
+import (
+	"2025_2_a4code/internal/domain"
+	commonE "2025_2_a4code/internal/lib/errors"
+	"context"
+	"errors"
+	"testing"
+)
+
+type mockPOP3Repository struct {
+	settings domain.POP3Settings
+	secret   string
+	hidden   []int64
+
+	saved      *domain.POP3SettingsUpdate
+	hiddenSave []int64
+}
+
+func (m *mockPOP3Repository) GetSettings(ctx context.Context, profileID int64) (domain.POP3Settings, error) {
+	return m.settings, nil
+}
+
+func (m *mockPOP3Repository) SaveSettings(ctx context.Context, profileID int64, update domain.POP3SettingsUpdate) error {
+	m.saved = &update
+	return nil
+}
+
+func (m *mockPOP3Repository) FindAPOPSecret(ctx context.Context, username, emailDomain string) (int64, string, error) {
+	if username != "alexey" || emailDomain != "flintmail.ru" {
+		return 0, "", commonE.ErrNotFound
+	}
+	return 7, m.secret, nil
+}
+
+func (m *mockPOP3Repository) FindHidden(ctx context.Context, profileID int64) ([]int64, error) {
+	return m.hidden, nil
+}
+
+func (m *mockPOP3Repository) Hide(ctx context.Context, profileID int64, messageIDs []int64) error {
+	m.hiddenSave = messageIDs
+	return nil
+}
+
+func TestPOP3Ucase_SetSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		current domain.POP3Settings
+		update  domain.POP3SettingsUpdate
+		want    domain.POP3Settings
+		wantErr bool
+	}{
+		{
+			name:   "Default action",
+			update: domain.POP3SettingsUpdate{},
+			want:   domain.POP3Settings{DeleteAction: domain.POP3DeleteToTrash},
+		},
+		{
+			name:   "Hide with new secret",
+			update: domain.POP3SettingsUpdate{DeleteAction: domain.POP3DeleteHide, APOPEnabled: true, APOPSecret: "tanstaaf-secret"},
+			want:   domain.POP3Settings{DeleteAction: domain.POP3DeleteHide, APOPEnabled: true},
+		},
+		{
+			name:    "Keep existing secret",
+			current: domain.POP3Settings{APOPEnabled: true},
+			update:  domain.POP3SettingsUpdate{DeleteAction: domain.POP3DeleteToTrash, APOPEnabled: true},
+			want:    domain.POP3Settings{DeleteAction: domain.POP3DeleteToTrash, APOPEnabled: true},
+		},
+		{
+			name:    "Enable without secret",
+			update:  domain.POP3SettingsUpdate{APOPEnabled: true},
+			wantErr: true,
+		},
+		{
+			name:    "Short secret",
+			update:  domain.POP3SettingsUpdate{APOPEnabled: true, APOPSecret: "short"},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown action",
+			update:  domain.POP3SettingsUpdate{DeleteAction: "purge"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockPOP3Repository{settings: tt.current}
+			got, err := New(repo).SetSettings(context.Background(), 1, tt.update)
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrInvalidPOP3Settings) {
+					t.Fatalf("SetSettings() error = %v, want %v", err, domain.ErrInvalidPOP3Settings)
+				}
+				if repo.saved != nil {
+					t.Error("SetSettings() saved invalid settings")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetSettings() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("SetSettings() got = %+v, want %+v", got, tt.want)
+			}
+			if repo.saved == nil || repo.saved.DeleteAction != tt.want.DeleteAction {
+				t.Errorf("SetSettings() saved = %+v", repo.saved)
+			}
+		})
+	}
+}
+
+func TestPOP3Ucase_AuthenticateAPOP(t *testing.T) {
+	// Пример из RFC 1939, 7
+	const timestamp = "<1896.697170952@dbc.mtview.ca.us>"
+	const digest = "c4c9334bac560ecc979e58001b3e22fb"
+
+	tests := []struct {
+		name    string
+		secret  string
+		login   string
+		digest  string
+		wantErr bool
+	}{
+		{name: "Username", secret: "tanstaaf", login: "alexey", digest: digest},
+		{name: "Full address, upper case digest", secret: "tanstaaf", login: "alexey@flintmail.ru", digest: "C4C9334BAC560ECC979E58001B3E22FB"},
+		{name: "Wrong digest", secret: "tanstaaf", login: "alexey", digest: "00000000000000000000000000000000", wantErr: true},
+		{name: "APOP disabled", secret: "", login: "alexey", digest: digest, wantErr: true},
+		{name: "Unknown user", secret: "tanstaaf", login: "maria", digest: digest, wantErr: true},
+		{name: "Foreign domain", secret: "tanstaaf", login: "alexey@example.com", digest: digest, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := New(&mockPOP3Repository{secret: tt.secret})
+			profileID, err := uc.AuthenticateAPOP(context.Background(), tt.login, timestamp, tt.digest)
+			if tt.wantErr {
+				if !errors.Is(err, ErrAuthFailed) {
+					t.Errorf("AuthenticateAPOP() error = %v, want %v", err, ErrAuthFailed)
+				}
+				return
+			}
+			if err != nil || profileID != 7 {
+				t.Errorf("AuthenticateAPOP() = %d, %v, want 7", profileID, err)
+			}
+		})
+	}
+}
+
+func TestPOP3Ucase_HiddenMessages(t *testing.T) {
+	repo := &mockPOP3Repository{hidden: []int64{3, 5}}
+	uc := New(repo)
+
+	hidden, err := uc.HiddenMessages(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("HiddenMessages() error = %v", err)
+	}
+	if len(hidden) != 2 || !hidden[3] || !hidden[5] {
+		t.Errorf("HiddenMessages() = %v", hidden)
+	}
+
+	if err := uc.HideMessages(context.Background(), 1, nil); err != nil || repo.hiddenSave != nil {
+		t.Errorf("HideMessages() with no messages must not call repository")
+	}
+	if err := uc.HideMessages(context.Background(), 1, []int64{8}); err != nil || len(repo.hiddenSave) != 1 {
+		t.Errorf("HideMessages() error = %v, saved %v", err, repo.hiddenSave)
+	}
+}
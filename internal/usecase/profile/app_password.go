@@ -0,0 +1,122 @@
+package profile
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	appPasswordAlphabet = "abcdefghijklmnopqrstuvwxyz"
+	appPasswordLength   = 16
+	// appPasswordGroup - пароль показывается группами по 4 буквы, пробелы при входе не учитываются
+	appPasswordGroup = 4
+)
+
+// CreateAppPassword создает пароль приложения и возвращает его вместе с сохраненной записью.
+// Пароль хранится только в виде хеша, поэтому показать его можно лишь один раз
+func (uc *ProfileUcase) CreateAppPassword(ctx context.Context, profileID int64, name string) (domain.AppPassword, string, error) {
+	const op = "usecase.profile.CreateAppPassword"
+
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > domain.MaxAppPasswordNameLength {
+		return domain.AppPassword{}, "", fmt.Errorf("%w: name must be between 1 and %d characters", domain.ErrInvalidAppPassword, domain.MaxAppPasswordNameLength)
+	}
+
+	password, err := generateAppPassword()
+	if err != nil {
+		return domain.AppPassword{}, "", e.Wrap(op, err)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return domain.AppPassword{}, "", e.Wrap(op, ErrPasswordHashFailed)
+	}
+
+	appPassword, err := uc.repo.CreateAppPassword(ctx, profileID, name, string(hash))
+	if err != nil {
+		return domain.AppPassword{}, "", e.Wrap(op, err)
+	}
+
+	return appPassword, formatAppPassword(password), nil
+}
+
+func (uc *ProfileUcase) ListAppPasswords(ctx context.Context, profileID int64) ([]domain.AppPassword, error) {
+	return uc.repo.FindAppPasswords(ctx, profileID)
+}
+
+func (uc *ProfileUcase) RevokeAppPassword(ctx context.Context, profileID, appPasswordID int64) error {
+	return uc.repo.DeleteAppPassword(ctx, profileID, appPasswordID)
+}
+
+// AuthenticateAppPassword проверяет пароль почтового клиента (IMAP, POP3, отправка по SMTP AUTH).
+// Подходят только пароли приложений, пароль от аккаунта здесь не принимается. Логин - имя
+// пользователя или его полный адрес. При успехе запоминает время и адрес входа
+func (uc *ProfileUcase) AuthenticateAppPassword(ctx context.Context, login, password, remoteIP string) (int64, error) {
+	const op = "usecase.profile.AuthenticateAppPassword"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	username, emailDomain, ok := strings.Cut(login, "@")
+	if ok && !strings.EqualFold(emailDomain, "flintmail.ru") {
+		return 0, e.Wrap(op, ErrUserNotFound)
+	}
+
+	hashes, err := uc.repo.FindAppPasswordHashes(ctx, username, "flintmail.ru")
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+
+	password = normalizeAppPassword(password)
+	for _, hash := range hashes {
+		if !uc.checkPassword(password, hash.PasswordHash) {
+			continue
+		}
+
+		// Неудачная запись времени входа не мешает клиенту получить почту
+		if err := uc.repo.UpdateAppPasswordUsage(ctx, hash.ID, time.Now(), remoteIP); err != nil {
+			log.Warn("failed to record app password usage: " + err.Error())
+		}
+		return hash.ProfileID, nil
+	}
+
+	return 0, e.Wrap(op, ErrWrongPassword)
+}
+
+func generateAppPassword() (string, error) {
+	var b strings.Builder
+	alphabetSize := big.NewInt(int64(len(appPasswordAlphabet)))
+	for i := 0; i < appPasswordLength; i++ {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(appPasswordAlphabet[n.Int64()])
+	}
+	return b.String(), nil
+}
+
+// formatAppPassword разбивает пароль на группы: "abcd efgh ijkl mnop"
+func formatAppPassword(password string) string {
+	groups := make([]string, 0, len(password)/appPasswordGroup+1)
+	for len(password) > appPasswordGroup {
+		groups = append(groups, password[:appPasswordGroup])
+		password = password[appPasswordGroup:]
+	}
+	groups = append(groups, password)
+	return strings.Join(groups, " ")
+}
+
+// normalizeAppPassword убирает пробелы, с которыми пароль показывается пользователю, и приводит к нижнему регистру
+func normalizeAppPassword(password string) string {
+	return strings.ToLower(strings.Join(strings.Fields(password), ""))
+}
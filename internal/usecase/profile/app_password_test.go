@@ -0,0 +1,161 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"2025_2_a4code/internal/domain"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestProfileUcase_CreateAppPassword(t *testing.T) {
+	var savedName, savedHash string
+	uc := New(&MockProfileRepository{
+		CreateAppPasswordFn: func(ctx context.Context, profileID int64, name, passwordHash string) (domain.AppPassword, error) {
+			savedName, savedHash = name, passwordHash
+			return domain.AppPassword{ID: 3, Name: name}, nil
+		},
+	})
+
+	appPassword, password, err := uc.CreateAppPassword(context.Background(), 10, "  Thunderbird ")
+	if err != nil {
+		t.Fatalf("CreateAppPassword() error = %v", err)
+	}
+	if appPassword.ID != 3 || savedName != "Thunderbird" {
+		t.Errorf("CreateAppPassword() saved %q, returned %+v", savedName, appPassword)
+	}
+	if !regexp.MustCompile(`^[a-z]{4} [a-z]{4} [a-z]{4} [a-z]{4}$`).MatchString(password) {
+		t.Errorf("CreateAppPassword() password = %q, want four groups of letters", password)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(savedHash), []byte(strings.ReplaceAll(password, " ", ""))) != nil {
+		t.Error("CreateAppPassword() must save the hash of the password without spaces")
+	}
+
+	_, other, err := uc.CreateAppPassword(context.Background(), 10, "Phone")
+	if err != nil || other == password {
+		t.Errorf("CreateAppPassword() must generate a new password each time, got %q, %v", other, err)
+	}
+}
+
+func TestProfileUcase_CreateAppPassword_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		appName   string
+		createErr error
+		wantErr   error
+	}{
+		{name: "Empty name", appName: "   ", wantErr: domain.ErrInvalidAppPassword},
+		{name: "Name too long", appName: strings.Repeat("я", domain.MaxAppPasswordNameLength+1), wantErr: domain.ErrInvalidAppPassword},
+		{name: "Limit reached", appName: "Phone", createErr: domain.ErrTooManyAppPasswords, wantErr: domain.ErrTooManyAppPasswords},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := false
+			uc := New(&MockProfileRepository{
+				CreateAppPasswordFn: func(ctx context.Context, profileID int64, name, passwordHash string) (domain.AppPassword, error) {
+					created = true
+					return domain.AppPassword{}, tt.createErr
+				},
+			})
+
+			_, password, err := uc.CreateAppPassword(context.Background(), 10, tt.appName)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreateAppPassword() error = %v, want %v", err, tt.wantErr)
+			}
+			if password != "" {
+				t.Errorf("CreateAppPassword() must not return a password on error, got %q", password)
+			}
+			if tt.createErr == nil && created {
+				t.Error("invalid app password must not be saved")
+			}
+		})
+	}
+}
+
+func TestProfileUcase_AuthenticateAppPassword(t *testing.T) {
+	const appPassword = "abcdefghijklmnop"
+	hashes := []domain.AppPasswordHash{
+		{ID: 3, ProfileID: 42, PasswordHash: generateHash("qrstuvwxyzabcdef")},
+		{ID: 4, ProfileID: 42, PasswordHash: generateHash(appPassword)},
+	}
+
+	var lookedUp []string
+	var usedID int64
+	var usedIP string
+	uc := New(&MockProfileRepository{
+		FindAppPasswordHashesFn: func(ctx context.Context, username string, domainName string) ([]domain.AppPasswordHash, error) {
+			lookedUp = append(lookedUp, username)
+			if username != "testuser" {
+				return nil, nil
+			}
+			return hashes, nil
+		},
+		UpdateAppPasswordUsageFn: func(ctx context.Context, appPasswordID int64, usedAt time.Time, ip string) error {
+			usedID, usedIP = appPasswordID, ip
+			return nil
+		},
+		FindByUsernameAndDomainFn: func(ctx context.Context, username string, domainName string) (*domain.Profile, error) {
+			t.Error("account password must not be checked")
+			return nil, nil
+		},
+	})
+
+	tests := []struct {
+		name     string
+		login    string
+		password string
+		want     int64
+		wantErr  error
+	}{
+		{name: "Username", login: "testuser", password: appPassword, want: 42},
+		{name: "Address", login: "testuser@FlintMail.ru", password: appPassword, want: 42},
+		{name: "Grouped", login: "testuser", password: "ABCD efgh ijkl mnop", want: 42},
+		{name: "WrongPassword", login: "testuser", password: "testpassword123", wantErr: ErrWrongPassword},
+		{name: "ForeignDomain", login: "testuser@gmail.com", password: appPassword, wantErr: ErrUserNotFound},
+		{name: "UnknownUser", login: "unknown@flintmail.ru", password: appPassword, wantErr: ErrWrongPassword},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usedID, usedIP = 0, ""
+
+			got, err := uc.AuthenticateAppPassword(context.Background(), tt.login, tt.password, "203.0.113.7")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("AuthenticateAppPassword() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("AuthenticateAppPassword() = %v, want %v", got, tt.want)
+			}
+			if tt.wantErr == nil && (usedID != 4 || usedIP != "203.0.113.7") {
+				t.Errorf("usage recorded for %d from %q, want 4 from 203.0.113.7", usedID, usedIP)
+			}
+			if tt.wantErr != nil && usedID != 0 {
+				t.Errorf("usage must not be recorded on failure, got %d", usedID)
+			}
+		})
+	}
+
+	if len(lookedUp) != 5 {
+		t.Errorf("foreign domain must not be looked up, lookups = %v", lookedUp)
+	}
+}
+
+func TestProfileUcase_AuthenticateAppPassword_UsageError(t *testing.T) {
+	uc := New(&MockProfileRepository{
+		FindAppPasswordHashesFn: func(ctx context.Context, username string, domainName string) ([]domain.AppPasswordHash, error) {
+			return []domain.AppPasswordHash{{ID: 3, ProfileID: 42, PasswordHash: generateHash("abcdefghijklmnop")}}, nil
+		},
+		UpdateAppPasswordUsageFn: func(ctx context.Context, appPasswordID int64, usedAt time.Time, ip string) error {
+			return errors.New("db down")
+		},
+	})
+
+	got, err := uc.AuthenticateAppPassword(context.Background(), "testuser", "abcdefghijklmnop", "203.0.113.7")
+	if err != nil || got != 42 {
+		t.Errorf("AuthenticateAppPassword() = %v, %v, want 42, nil", got, err)
+	}
+}
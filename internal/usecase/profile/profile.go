@@ -52,6 +52,12 @@ type ProfileRepository interface {
 	FindSettingsByProfileId(ctx context.Context, profileID int64) (domain.Settings, error)
 	InsertProfileAvatar(ctx context.Context, profileID int64, avatarURL string) error
 	UpdateProfileInfo(ctx context.Context, profileID int64, info domain.ProfileUpdate) error
+	UpdateVacation(ctx context.Context, profileID int64, vacation domain.VacationSettings) error
+	CreateAppPassword(ctx context.Context, profileID int64, name, passwordHash string) (domain.AppPassword, error)
+	FindAppPasswords(ctx context.Context, profileID int64) ([]domain.AppPassword, error)
+	DeleteAppPassword(ctx context.Context, profileID, appPasswordID int64) error
+	FindAppPasswordHashes(ctx context.Context, username string, domain string) ([]domain.AppPasswordHash, error)
+	UpdateAppPasswordUsage(ctx context.Context, appPasswordID int64, usedAt time.Time, ip string) error
 }
 
 type ProfileUcase struct {
@@ -125,6 +131,23 @@ func (uc *ProfileUcase) Login(ctx context.Context, req LoginRequest) (int64, err
 	return profile.ID, nil
 }
 
+// Authenticate проверяет пароль от аккаунта для почтового клиента (IMAP, POP3, отправка по SMTP AUTH).
+// Логин - имя пользователя или его полный адрес
+func (uc *ProfileUcase) Authenticate(ctx context.Context, login, password string) (int64, error) {
+	const op = "usecase.profile.Authenticate"
+
+	username, emailDomain, ok := strings.Cut(login, "@")
+	if ok && !strings.EqualFold(emailDomain, "flintmail.ru") {
+		return 0, e.Wrap(op, ErrUserNotFound)
+	}
+
+	profileID, err := uc.Login(ctx, LoginRequest{Username: username, Password: password})
+	if err != nil {
+		return 0, e.Wrap(op, err)
+	}
+	return profileID, nil
+}
+
 func (uc *ProfileUcase) checkPassword(password, hash string) bool {
 	if hash == "" {
 		return false
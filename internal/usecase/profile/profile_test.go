@@ -24,6 +24,54 @@ type MockProfileRepository struct {
 	FindSettingsByProfileIdFn func(ctx context.Context, profileID int64) (domain.Settings, error)
 	InsertProfileAvatarFn     func(ctx context.Context, profileID int64, avatarURL string) error
 	UpdateProfileInfoFn       func(ctx context.Context, profileID int64, info domain.ProfileUpdate) error
+	UpdateVacationFn          func(ctx context.Context, profileID int64, vacation domain.VacationSettings) error
+	CreateAppPasswordFn       func(ctx context.Context, profileID int64, name, passwordHash string) (domain.AppPassword, error)
+	FindAppPasswordsFn        func(ctx context.Context, profileID int64) ([]domain.AppPassword, error)
+	DeleteAppPasswordFn       func(ctx context.Context, profileID, appPasswordID int64) error
+	FindAppPasswordHashesFn   func(ctx context.Context, username string, domain string) ([]domain.AppPasswordHash, error)
+	UpdateAppPasswordUsageFn  func(ctx context.Context, appPasswordID int64, usedAt time.Time, ip string) error
+}
+
+func (m *MockProfileRepository) CreateAppPassword(ctx context.Context, profileID int64, name, passwordHash string) (domain.AppPassword, error) {
+	if m.CreateAppPasswordFn != nil {
+		return m.CreateAppPasswordFn(ctx, profileID, name, passwordHash)
+	}
+	return domain.AppPassword{}, nil
+}
+
+func (m *MockProfileRepository) FindAppPasswords(ctx context.Context, profileID int64) ([]domain.AppPassword, error) {
+	if m.FindAppPasswordsFn != nil {
+		return m.FindAppPasswordsFn(ctx, profileID)
+	}
+	return nil, nil
+}
+
+func (m *MockProfileRepository) DeleteAppPassword(ctx context.Context, profileID, appPasswordID int64) error {
+	if m.DeleteAppPasswordFn != nil {
+		return m.DeleteAppPasswordFn(ctx, profileID, appPasswordID)
+	}
+	return nil
+}
+
+func (m *MockProfileRepository) FindAppPasswordHashes(ctx context.Context, username string, domain string) ([]domain.AppPasswordHash, error) {
+	if m.FindAppPasswordHashesFn != nil {
+		return m.FindAppPasswordHashesFn(ctx, username, domain)
+	}
+	return nil, nil
+}
+
+func (m *MockProfileRepository) UpdateAppPasswordUsage(ctx context.Context, appPasswordID int64, usedAt time.Time, ip string) error {
+	if m.UpdateAppPasswordUsageFn != nil {
+		return m.UpdateAppPasswordUsageFn(ctx, appPasswordID, usedAt, ip)
+	}
+	return nil
+}
+
+func (m *MockProfileRepository) UpdateVacation(ctx context.Context, profileID int64, vacation domain.VacationSettings) error {
+	if m.UpdateVacationFn != nil {
+		return m.UpdateVacationFn(ctx, profileID, vacation)
+	}
+	return nil
 }
 
 func (m *MockProfileRepository) FindByID(ctx context.Context, id int64) (*domain.Profile, error) {
@@ -463,6 +511,52 @@ func TestProfileUcase_Login(t *testing.T) {
 	}
 }
 
+func TestProfileUcase_Authenticate(t *testing.T) {
+	const testPassword = "testpassword123"
+	hashedPassword := generateHash(testPassword)
+
+	var lookedUp []string
+	uc := New(&MockProfileRepository{
+		FindByUsernameAndDomainFn: func(ctx context.Context, username string, domainName string) (*domain.Profile, error) {
+			lookedUp = append(lookedUp, username)
+			if username != "testuser" {
+				return nil, commone.ErrNotFound
+			}
+			return &domain.Profile{ID: 42, PasswordHash: hashedPassword}, nil
+		},
+	})
+
+	tests := []struct {
+		name    string
+		login   string
+		want    int64
+		wantErr error
+	}{
+		{name: "Username", login: "testuser", want: 42},
+		{name: "Address", login: "testuser@FlintMail.ru", want: 42},
+		{name: "ForeignDomain", login: "testuser@gmail.com", wantErr: ErrUserNotFound},
+		{name: "UnknownUser", login: "unknown@flintmail.ru", wantErr: ErrUserNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := uc.Authenticate(context.Background(), tt.login, testPassword)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Authenticate() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Authenticate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := uc.Authenticate(context.Background(), "testuser", "wrong"); !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("Authenticate() error = %v, want %v", err, ErrWrongPassword)
+	}
+	if len(lookedUp) != 4 {
+		t.Errorf("foreign domain must not be looked up, lookups = %v", lookedUp)
+	}
+}
+
 func TestProfileUcase_checkPassword(t *testing.T) {
 	const testPassword = "testpassword123"
 	const wrongPassword = "wrongpassword"
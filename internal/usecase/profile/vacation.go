@@ -0,0 +1,59 @@
+package profile
+
+import (
+	"2025_2_a4code/internal/domain"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxVacationSubjectLength = 200
+	maxVacationBodyLength    = 5000
+)
+
+// SetVacation проверяет и сохраняет настройки автоответа, возвращает обновленные настройки профиля
+func (uc *ProfileUcase) SetVacation(ctx context.Context, profileID int64, vacation domain.VacationSettings) (domain.Settings, error) {
+	const op = "usecase.profile.SetVacation"
+
+	vacation, err := normalizeVacation(vacation)
+	if err != nil {
+		return domain.Settings{}, err
+	}
+
+	if err := uc.repo.UpdateVacation(ctx, profileID, vacation); err != nil {
+		return domain.Settings{}, e.Wrap(op, err)
+	}
+
+	return uc.repo.FindSettingsByProfileId(ctx, profileID)
+}
+
+func normalizeVacation(vacation domain.VacationSettings) (domain.VacationSettings, error) {
+	vacation.Subject = strings.TrimSpace(vacation.Subject)
+	vacation.Body = strings.TrimSpace(vacation.Body)
+
+	if utf8.RuneCountInString(vacation.Subject) > maxVacationSubjectLength {
+		return domain.VacationSettings{}, fmt.Errorf("%w: subject too long", domain.ErrInvalidVacation)
+	}
+	if utf8.RuneCountInString(vacation.Body) > maxVacationBodyLength {
+		return domain.VacationSettings{}, fmt.Errorf("%w: body too long", domain.ErrInvalidVacation)
+	}
+	if vacation.Enabled && vacation.Body == "" {
+		return domain.VacationSettings{}, fmt.Errorf("%w: empty body", domain.ErrInvalidVacation)
+	}
+
+	if vacation.Days == 0 {
+		vacation.Days = domain.DefaultVacationDays
+	}
+	if vacation.Days < 1 || vacation.Days > domain.MaxVacationDays {
+		return domain.VacationSettings{}, fmt.Errorf("%w: days must be between 1 and %d", domain.ErrInvalidVacation, domain.MaxVacationDays)
+	}
+
+	if !vacation.Start.IsZero() && !vacation.End.IsZero() && !vacation.Start.Before(vacation.End) {
+		return domain.VacationSettings{}, fmt.Errorf("%w: start must be before end", domain.ErrInvalidVacation)
+	}
+
+	return vacation, nil
+}
@@ -0,0 +1,100 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"2025_2_a4code/internal/domain"
+)
+
+func TestProfileUcase_SetVacation(t *testing.T) {
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC)
+	repoErr := errors.New("repository error")
+
+	tests := []struct {
+		name      string
+		vacation  domain.VacationSettings
+		updateErr error
+		wantSaved domain.VacationSettings
+		wantErr   error
+	}{
+		{
+			name:      "Success with defaults",
+			vacation:  domain.VacationSettings{Enabled: true, Start: start, End: end, Subject: "  OOO ", Body: " Away "},
+			wantSaved: domain.VacationSettings{Enabled: true, Start: start, End: end, Subject: "OOO", Body: "Away", Days: domain.DefaultVacationDays},
+		},
+		{
+			name:      "Disabled without body",
+			vacation:  domain.VacationSettings{Days: 3},
+			wantSaved: domain.VacationSettings{Days: 3},
+		},
+		{
+			name:     "Enabled without body",
+			vacation: domain.VacationSettings{Enabled: true},
+			wantErr:  domain.ErrInvalidVacation,
+		},
+		{
+			name:     "Days out of range",
+			vacation: domain.VacationSettings{Body: "Away", Days: domain.MaxVacationDays + 1},
+			wantErr:  domain.ErrInvalidVacation,
+		},
+		{
+			name:     "Start after end",
+			vacation: domain.VacationSettings{Body: "Away", Start: end, End: start},
+			wantErr:  domain.ErrInvalidVacation,
+		},
+		{
+			name:     "Subject too long",
+			vacation: domain.VacationSettings{Body: "Away", Subject: strings.Repeat("я", maxVacationSubjectLength+1)},
+			wantErr:  domain.ErrInvalidVacation,
+		},
+		{
+			name:      "Repository error",
+			vacation:  domain.VacationSettings{Body: "Away"},
+			updateErr: repoErr,
+			wantErr:   repoErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved domain.VacationSettings
+			updated := false
+			uc := &ProfileUcase{repo: &MockProfileRepository{
+				UpdateVacationFn: func(ctx context.Context, profileID int64, vacation domain.VacationSettings) error {
+					updated = true
+					saved = vacation
+					return tt.updateErr
+				},
+				FindSettingsByProfileIdFn: func(ctx context.Context, profileID int64) (domain.Settings, error) {
+					return domain.Settings{ProfileID: profileID, Vacation: saved}, nil
+				},
+			}}
+
+			got, err := uc.SetVacation(context.Background(), 1, tt.vacation)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("SetVacation() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if errors.Is(tt.wantErr, domain.ErrInvalidVacation) && updated {
+					t.Error("SetVacation() saved invalid settings")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetVacation() unexpected error = %v", err)
+			}
+			if saved != tt.wantSaved {
+				t.Errorf("SetVacation() saved = %+v, want %+v", saved, tt.wantSaved)
+			}
+			if got.Vacation != tt.wantSaved {
+				t.Errorf("SetVacation() got = %+v, want %+v", got.Vacation, tt.wantSaved)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package senderlist
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"regexp"
+	"strings"
+)
+
+var domainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z0-9-]{2,63}$`)
+
+type SenderListRepository interface {
+	MatchSender(ctx context.Context, profileID int64, address string) (domain.SenderListKind, domain.BlockedSenderAction, error)
+	GetRules(ctx context.Context, profileID int64) ([]domain.SenderRule, error)
+	SaveRule(ctx context.Context, profileID int64, kind domain.SenderListKind, pattern string) (domain.SenderRule, error)
+	DeleteRule(ctx context.Context, profileID, ruleID int64) error
+	GetBlockedAction(ctx context.Context, profileID int64) (domain.BlockedSenderAction, error)
+	SetBlockedAction(ctx context.Context, profileID int64, action domain.BlockedSenderAction) error
+}
+
+type SenderListUcase struct {
+	repo SenderListRepository
+}
+
+func New(repo SenderListRepository) *SenderListUcase {
+	return &SenderListUcase{repo: repo}
+}
+
+// Filter помещает письма заблокированных отправителей в спам или удаляет их, не передавая
+// следующим фильтрам. Письма разрешенных отправителей не проверяются спам-фильтром.
+func (uc *SenderListUcase) Filter(ctx context.Context, delivery *domain.Delivery) (*domain.DeliveryAction, error) {
+	const op = "usecase.senderlist.Filter"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	if delivery.From == "" {
+		return nil, nil
+	}
+
+	kind, action, err := uc.repo.MatchSender(ctx, delivery.ProfileID, delivery.From)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	switch kind {
+	case domain.SenderAllowed:
+		delivery.SenderAllowed = true
+	case domain.SenderBlocked:
+		log.Debug("sender is blocked", slog.Int64("message_id", delivery.MessageID), slog.String("action", string(action)))
+		if action == domain.BlockedDiscard {
+			return &domain.DeliveryAction{Discard: true, Stop: true}, nil
+		}
+		return &domain.DeliveryAction{Folders: []string{string(domain.FolderSpam)}, Stop: true}, nil
+	}
+
+	return nil, nil
+}
+
+func (uc *SenderListUcase) GetSenderLists(ctx context.Context, profileID int64) (domain.SenderLists, error) {
+	const op = "usecase.senderlist.GetSenderLists"
+
+	rules, err := uc.repo.GetRules(ctx, profileID)
+	if err != nil {
+		return domain.SenderLists{}, e.Wrap(op, err)
+	}
+	action, err := uc.repo.GetBlockedAction(ctx, profileID)
+	if err != nil {
+		return domain.SenderLists{}, e.Wrap(op, err)
+	}
+
+	lists := domain.SenderLists{
+		Blocked:       make([]domain.SenderRule, 0),
+		Allowed:       make([]domain.SenderRule, 0),
+		BlockedAction: action,
+	}
+	for _, rule := range rules {
+		if rule.Kind == domain.SenderBlocked {
+			lists.Blocked = append(lists.Blocked, rule)
+		} else {
+			lists.Allowed = append(lists.Allowed, rule)
+		}
+	}
+	return lists, nil
+}
+
+// AddRule добавляет адрес или домен в список блокировки или разрешенных
+func (uc *SenderListUcase) AddRule(ctx context.Context, profileID int64, kind domain.SenderListKind, pattern string) (domain.SenderRule, error) {
+	if kind != domain.SenderBlocked && kind != domain.SenderAllowed {
+		return domain.SenderRule{}, fmt.Errorf("%w: unknown list %q", domain.ErrInvalidSenderRule, kind)
+	}
+	pattern, err := normalizePattern(pattern)
+	if err != nil {
+		return domain.SenderRule{}, err
+	}
+	return uc.repo.SaveRule(ctx, profileID, kind, pattern)
+}
+
+func (uc *SenderListUcase) DeleteRule(ctx context.Context, profileID, ruleID int64) error {
+	return uc.repo.DeleteRule(ctx, profileID, ruleID)
+}
+
+func (uc *SenderListUcase) SetBlockedAction(ctx context.Context, profileID int64, action domain.BlockedSenderAction) error {
+	if action != domain.BlockedToSpam && action != domain.BlockedDiscard {
+		return fmt.Errorf("%w: unknown action %q", domain.ErrInvalidSenderRule, action)
+	}
+	return uc.repo.SetBlockedAction(ctx, profileID, action)
+}
+
+// BlockSender блокирует отправителя письма: его адрес или весь домен
+func (uc *SenderListUcase) BlockSender(ctx context.Context, profileID int64, address string, wholeDomain bool) (domain.SenderRule, error) {
+	pattern := address
+	if wholeDomain {
+		i := strings.LastIndex(address, "@")
+		if i < 0 {
+			return domain.SenderRule{}, fmt.Errorf("%w: invalid sender address", domain.ErrInvalidSenderRule)
+		}
+		pattern = address[i+1:]
+	}
+	return uc.AddRule(ctx, profileID, domain.SenderBlocked, pattern)
+}
+
+// normalizePattern приводит шаблон к нижнему регистру. Шаблон без @ или начинающийся с @ - домен
+func normalizePattern(pattern string) (string, error) {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	pattern = strings.TrimPrefix(pattern, "@")
+
+	if strings.Contains(pattern, "@") {
+		addr, err := mail.ParseAddress(pattern)
+		if err != nil || addr.Address != pattern {
+			return "", fmt.Errorf("%w: invalid address %q", domain.ErrInvalidSenderRule, pattern)
+		}
+		return pattern, nil
+	}
+
+	if len(pattern) > 253 || !domainPattern.MatchString(pattern) {
+		return "", fmt.Errorf("%w: invalid domain %q", domain.ErrInvalidSenderRule, pattern)
+	}
+	return pattern, nil
+}
@@ -0,0 +1,186 @@
+package senderlist
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type mockSenderListRepository struct {
+	kind   domain.SenderListKind
+	action domain.BlockedSenderAction
+	err    error
+	rules  []domain.SenderRule
+
+	savedKind    domain.SenderListKind
+	savedPattern string
+}
+
+func (m *mockSenderListRepository) MatchSender(ctx context.Context, profileID int64, address string) (domain.SenderListKind, domain.BlockedSenderAction, error) {
+	return m.kind, m.action, m.err
+}
+
+func (m *mockSenderListRepository) GetRules(ctx context.Context, profileID int64) ([]domain.SenderRule, error) {
+	return m.rules, m.err
+}
+
+func (m *mockSenderListRepository) SaveRule(ctx context.Context, profileID int64, kind domain.SenderListKind, pattern string) (domain.SenderRule, error) {
+	m.savedKind, m.savedPattern = kind, pattern
+	return domain.SenderRule{ID: 1, Kind: kind, Pattern: pattern}, nil
+}
+
+func (m *mockSenderListRepository) DeleteRule(ctx context.Context, profileID, ruleID int64) error {
+	return nil
+}
+
+func (m *mockSenderListRepository) GetBlockedAction(ctx context.Context, profileID int64) (domain.BlockedSenderAction, error) {
+	return m.action, nil
+}
+
+func (m *mockSenderListRepository) SetBlockedAction(ctx context.Context, profileID int64, action domain.BlockedSenderAction) error {
+	m.action = action
+	return nil
+}
+
+func TestSenderListUcase_Filter(t *testing.T) {
+	tests := []struct {
+		name        string
+		repo        *mockSenderListRepository
+		from        string
+		want        *domain.DeliveryAction
+		wantAllowed bool
+		wantErr     bool
+	}{
+		{
+			name: "Blocked to spam",
+			repo: &mockSenderListRepository{kind: domain.SenderBlocked, action: domain.BlockedToSpam},
+			from: "troll@spam.com",
+			want: &domain.DeliveryAction{Folders: []string{string(domain.FolderSpam)}, Stop: true},
+		},
+		{
+			name: "Blocked and discarded",
+			repo: &mockSenderListRepository{kind: domain.SenderBlocked, action: domain.BlockedDiscard},
+			from: "troll@spam.com",
+			want: &domain.DeliveryAction{Discard: true, Stop: true},
+		},
+		{
+			name:        "Allowed",
+			repo:        &mockSenderListRepository{kind: domain.SenderAllowed},
+			from:        "boss@a4mail.ru",
+			wantAllowed: true,
+		},
+		{
+			name: "Not listed",
+			repo: &mockSenderListRepository{},
+			from: "friend@a4mail.ru",
+		},
+		{
+			name: "Empty sender",
+			repo: &mockSenderListRepository{kind: domain.SenderBlocked},
+		},
+		{
+			name:    "Repository error",
+			repo:    &mockSenderListRepository{err: errors.New("db down")},
+			from:    "friend@a4mail.ru",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delivery := &domain.Delivery{ProfileID: 1, From: tt.from}
+
+			got, err := New(tt.repo).Filter(context.Background(), delivery)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Filter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Filter() = %+v, want %+v", got, tt.want)
+			}
+			if delivery.SenderAllowed != tt.wantAllowed {
+				t.Errorf("Filter() SenderAllowed = %v, want %v", delivery.SenderAllowed, tt.wantAllowed)
+			}
+		})
+	}
+}
+
+func TestSenderListUcase_AddRule(t *testing.T) {
+	tests := []struct {
+		name        string
+		kind        domain.SenderListKind
+		pattern     string
+		wantPattern string
+		wantErr     error
+	}{
+		{name: "Address", kind: domain.SenderBlocked, pattern: " Troll@Spam.com ", wantPattern: "troll@spam.com"},
+		{name: "Domain", kind: domain.SenderAllowed, pattern: "A4mail.ru", wantPattern: "a4mail.ru"},
+		{name: "Domain with at", kind: domain.SenderBlocked, pattern: "@spam.com", wantPattern: "spam.com"},
+		{name: "Invalid address", kind: domain.SenderBlocked, pattern: "Troll <troll@spam.com>", wantErr: domain.ErrInvalidSenderRule},
+		{name: "Invalid domain", kind: domain.SenderBlocked, pattern: "localhost", wantErr: domain.ErrInvalidSenderRule},
+		{name: "Unknown list", kind: "mute", pattern: "spam.com", wantErr: domain.ErrInvalidSenderRule},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockSenderListRepository{}
+
+			_, err := New(repo).AddRule(context.Background(), 1, tt.kind, tt.pattern)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AddRule() error = %v, want %v", err, tt.wantErr)
+			}
+			if repo.savedPattern != tt.wantPattern {
+				t.Errorf("AddRule() saved %q, want %q", repo.savedPattern, tt.wantPattern)
+			}
+		})
+	}
+}
+
+func TestSenderListUcase_BlockSender(t *testing.T) {
+	repo := &mockSenderListRepository{}
+	uc := New(repo)
+
+	if _, err := uc.BlockSender(context.Background(), 1, "troll@spam.com", true); err != nil {
+		t.Fatalf("BlockSender() error = %v", err)
+	}
+	if repo.savedKind != domain.SenderBlocked || repo.savedPattern != "spam.com" {
+		t.Errorf("BlockSender() saved %q %q", repo.savedKind, repo.savedPattern)
+	}
+
+	if _, err := uc.BlockSender(context.Background(), 1, "troll@spam.com", false); err != nil || repo.savedPattern != "troll@spam.com" {
+		t.Errorf("BlockSender() = %v, saved %q", err, repo.savedPattern)
+	}
+}
+
+func TestSenderListUcase_GetSenderLists(t *testing.T) {
+	repo := &mockSenderListRepository{
+		action: domain.BlockedDiscard,
+		rules: []domain.SenderRule{
+			{ID: 1, Kind: domain.SenderAllowed, Pattern: "a4mail.ru"},
+			{ID: 2, Kind: domain.SenderBlocked, Pattern: "spam.com"},
+		},
+	}
+
+	lists, err := New(repo).GetSenderLists(context.Background(), 1)
+
+	if err != nil {
+		t.Fatalf("GetSenderLists() error = %v", err)
+	}
+	if len(lists.Allowed) != 1 || len(lists.Blocked) != 1 || lists.BlockedAction != domain.BlockedDiscard {
+		t.Errorf("GetSenderLists() = %+v", lists)
+	}
+}
+
+func TestSenderListUcase_SetBlockedAction(t *testing.T) {
+	uc := New(&mockSenderListRepository{})
+
+	if err := uc.SetBlockedAction(context.Background(), 1, domain.BlockedDiscard); err != nil {
+		t.Errorf("SetBlockedAction() error = %v", err)
+	}
+	if err := uc.SetBlockedAction(context.Background(), 1, "bounce"); !errors.Is(err, domain.ErrInvalidSenderRule) {
+		t.Errorf("SetBlockedAction() error = %v, want %v", err, domain.ErrInvalidSenderRule)
+	}
+}
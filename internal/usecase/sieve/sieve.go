@@ -0,0 +1,159 @@
+package sieve
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	"2025_2_a4code/internal/lib/sieve"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+var ErrInvalidScript = errors.New("invalid sieve script")
+
+type SieveRepository interface {
+	GetScript(ctx context.Context, profileID int64) (string, error)
+	SaveScript(ctx context.Context, profileID int64, script string) error
+	LastVacationResponse(ctx context.Context, profileID int64, sender, handle string) (time.Time, error)
+	SaveVacationResponse(ctx context.Context, profileID int64, sender, handle string, sentAt time.Time) error
+}
+
+type SieveUcase struct {
+	repo SieveRepository
+	now  func() time.Time
+}
+
+func New(repo SieveRepository) *SieveUcase {
+	return &SieveUcase{repo: repo, now: time.Now}
+}
+
+// PutScript проверяет и сохраняет скрипт, пустой скрипт отключает фильтрацию.
+// Скрипт с папкой fileinto, имени которой соответствуют несколько папок пользователя, отклоняется
+func (uc *SieveUcase) PutScript(ctx context.Context, profileID int64, script string, folders []domain.Folder) error {
+	if strings.TrimSpace(script) != "" {
+		parsed, err := sieve.Parse(script)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidScript, err.Error())
+		}
+		if err := checkAmbiguousMailboxes(parsed, folders); err != nil {
+			return err
+		}
+	}
+	return uc.repo.SaveScript(ctx, profileID, script)
+}
+
+func (uc *SieveUcase) GetScript(ctx context.Context, profileID int64) (string, error) {
+	return uc.repo.GetScript(ctx, profileID)
+}
+
+// CheckScript проверяет скрипт и возвращает предупреждения о папках fileinto,
+// которых нет среди папок пользователя
+func (uc *SieveUcase) CheckScript(ctx context.Context, script string, folders []domain.Folder) ([]string, error) {
+	parsed, err := sieve.Parse(script)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidScript, err.Error())
+	}
+	if err := checkAmbiguousMailboxes(parsed, folders); err != nil {
+		return nil, err
+	}
+
+	var warnings []string
+	for _, mailbox := range parsed.Mailboxes() {
+		if _, err := domain.FindFolder(folders, mailbox); err != nil {
+			warnings = append(warnings, fmt.Sprintf("folder %q does not exist, messages will be kept in inbox", mailbox))
+		}
+	}
+	return warnings, nil
+}
+
+// checkAmbiguousMailboxes отклоняет папки fileinto, которые нельзя однозначно найти по имени:
+// вложенные папки с одинаковым именем нужно указывать полным именем через "/"
+func checkAmbiguousMailboxes(parsed *sieve.Script, folders []domain.Folder) error {
+	for _, mailbox := range parsed.Mailboxes() {
+		if _, err := domain.FindFolder(folders, mailbox); errors.Is(err, domain.ErrFolderAmbiguous) {
+			return fmt.Errorf("%w: folder %q matches several folders, use its full name", ErrInvalidScript, mailbox)
+		}
+	}
+	return nil
+}
+
+// Filter применяет sieve-скрипт получателя к доставленному письму
+func (uc *SieveUcase) Filter(ctx context.Context, delivery *domain.Delivery) (*domain.DeliveryAction, error) {
+	const op = "usecase.sieve.Filter"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	script, err := uc.repo.GetScript(ctx, delivery.ProfileID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	if strings.TrimSpace(script) == "" {
+		return nil, nil
+	}
+
+	parsed, err := sieve.Parse(script)
+	if err != nil {
+		// сохраненный скрипт уже проверен, но при ошибке письмо просто остается во входящих
+		return nil, e.Wrap(op, err)
+	}
+
+	result := parsed.Execute(sieve.Message{
+		Header:       textproto.MIMEHeader(delivery.Headers),
+		EnvelopeFrom: delivery.From,
+		EnvelopeTo:   delivery.To,
+		Size:         delivery.Size,
+	})
+
+	action := &domain.DeliveryAction{Keep: result.Keep, Flags: result.KeepFlags}
+	for _, fileInto := range result.FileInto {
+		action.Folders = append(action.Folders, fileInto.Mailbox)
+		action.Flags = append(action.Flags, fileInto.Flags...)
+	}
+	if !result.Keep && len(result.FileInto) == 0 {
+		action.Discard = true
+	}
+
+	if result.Vacation != nil {
+		reply, err := uc.vacationReply(ctx, delivery, result.Vacation)
+		if err != nil {
+			log.Warn("failed to process vacation: " + err.Error())
+		} else if reply != nil {
+			action.Replies = append(action.Replies, *reply)
+		}
+	}
+
+	return action, nil
+}
+
+func (uc *SieveUcase) vacationReply(ctx context.Context, delivery *domain.Delivery, vacation *sieve.Vacation) (*domain.AutoReply, error) {
+	handle := vacationHandle(vacation.Handle)
+	last, err := uc.repo.LastVacationResponse(ctx, delivery.ProfileID, vacation.To, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	now := uc.now()
+	if !last.IsZero() && now.Sub(last) < time.Duration(vacation.Days)*24*time.Hour {
+		return nil, nil
+	}
+	if err := uc.repo.SaveVacationResponse(ctx, delivery.ProfileID, vacation.To, handle, now); err != nil {
+		return nil, err
+	}
+
+	topic := vacation.Subject
+	if topic == "" {
+		topic = "Auto: " + delivery.Topic
+	}
+	return &domain.AutoReply{To: vacation.To, Topic: topic, Text: vacation.Reason}, nil
+}
+
+func vacationHandle(handle string) string {
+	sum := sha256.Sum256([]byte(handle))
+	return hex.EncodeToString(sum[:16])
+}
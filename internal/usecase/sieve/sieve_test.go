@@ -0,0 +1,231 @@
+package sieve
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type MockSieveRepository struct {
+	GetScriptFn            func(ctx context.Context, profileID int64) (string, error)
+	SaveScriptFn           func(ctx context.Context, profileID int64, script string) error
+	LastVacationResponseFn func(ctx context.Context, profileID int64, sender, handle string) (time.Time, error)
+	SaveVacationResponseFn func(ctx context.Context, profileID int64, sender, handle string, sentAt time.Time) error
+}
+
+func (m *MockSieveRepository) GetScript(ctx context.Context, profileID int64) (string, error) {
+	if m.GetScriptFn != nil {
+		return m.GetScriptFn(ctx, profileID)
+	}
+	return "", nil
+}
+
+func (m *MockSieveRepository) SaveScript(ctx context.Context, profileID int64, script string) error {
+	if m.SaveScriptFn != nil {
+		return m.SaveScriptFn(ctx, profileID, script)
+	}
+	return nil
+}
+
+func (m *MockSieveRepository) LastVacationResponse(ctx context.Context, profileID int64, sender, handle string) (time.Time, error) {
+	if m.LastVacationResponseFn != nil {
+		return m.LastVacationResponseFn(ctx, profileID, sender, handle)
+	}
+	return time.Time{}, nil
+}
+
+func (m *MockSieveRepository) SaveVacationResponse(ctx context.Context, profileID int64, sender, handle string, sentAt time.Time) error {
+	if m.SaveVacationResponseFn != nil {
+		return m.SaveVacationResponseFn(ctx, profileID, sender, handle, sentAt)
+	}
+	return nil
+}
+
+func testDelivery() *domain.Delivery {
+	return &domain.Delivery{
+		MessageID: 1,
+		ProfileID: 7,
+		From:      "boss@example.com",
+		To:        "me@a4mail.ru",
+		Topic:     "Отчет",
+		Headers: map[string][]string{
+			"From":    {"boss@example.com"},
+			"To":      {"me@a4mail.ru"},
+			"Subject": {"Отчет"},
+		},
+	}
+}
+
+func TestSieveUcase_PutScript(t *testing.T) {
+	tests := []struct {
+		name      string
+		script    string
+		saveErr   error
+		wantSaved bool
+		wantErr   error
+	}{
+		{name: "Valid script is saved", script: `require "fileinto"; fileinto "Work";`, wantSaved: true},
+		{name: "Empty script disables filtering", script: "", wantSaved: true},
+		{name: "Invalid script is rejected", script: `fileinto "Work";`, wantErr: ErrInvalidScript},
+		{name: "Repository error", script: `keep;`, saveErr: errors.New("db error"), wantSaved: true},
+		{name: "Full folder name is saved", script: `require "fileinto"; fileinto "Work/Reports";`, wantSaved: true},
+		{name: "Ambiguous folder name is rejected", script: `require "fileinto"; fileinto "Reports";`, wantErr: ErrInvalidScript},
+	}
+	folders := []domain.Folder{
+		{ID: 1, Name: "Входящие", Type: domain.FolderInbox},
+		{ID: 2, Name: "Work", Type: domain.FolderCustom},
+		{ID: 3, ParentID: 2, Name: "Reports", Type: domain.FolderCustom},
+		{ID: 4, Name: "Home", Type: domain.FolderCustom},
+		{ID: 5, ParentID: 4, Name: "Reports", Type: domain.FolderCustom},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := false
+			uc := New(&MockSieveRepository{
+				SaveScriptFn: func(ctx context.Context, profileID int64, script string) error {
+					saved = true
+					return tt.saveErr
+				},
+			})
+
+			err := uc.PutScript(context.Background(), 7, tt.script, folders)
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("PutScript() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.saveErr != nil && err == nil {
+				t.Error("PutScript() expected repository error")
+			}
+			if saved != tt.wantSaved {
+				t.Errorf("saved = %v, want %v", saved, tt.wantSaved)
+			}
+		})
+	}
+}
+
+func TestSieveUcase_CheckScript(t *testing.T) {
+	uc := New(&MockSieveRepository{})
+	folders := []domain.Folder{
+		{ID: 1, Name: "Входящие", Type: domain.FolderInbox},
+		{ID: 2, Name: "Спам", Type: domain.FolderSpam},
+		{ID: 3, Name: "Work", Type: domain.FolderCustom},
+		{ID: 4, ParentID: 3, Name: "Reports", Type: domain.FolderCustom},
+		{ID: 5, Name: "Home", Type: domain.FolderCustom},
+		{ID: 6, ParentID: 5, Name: "Reports", Type: domain.FolderCustom},
+	}
+
+	warnings, err := uc.CheckScript(context.Background(), `require "fileinto";
+fileinto "work"; fileinto "spam"; fileinto "INBOX"; fileinto "work/reports"; fileinto "Missing";`, folders)
+	if err != nil {
+		t.Fatalf("CheckScript() error = %v", err)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("CheckScript() warnings = %v, want one warning about Missing", warnings)
+	}
+
+	if _, err := uc.CheckScript(context.Background(), `require "fileinto"; fileinto "Reports";`, folders); !errors.Is(err, ErrInvalidScript) {
+		t.Errorf("CheckScript() error = %v, want ErrInvalidScript for ambiguous folder", err)
+	}
+
+	if _, err := uc.CheckScript(context.Background(), `if`, folders); !errors.Is(err, ErrInvalidScript) {
+		t.Errorf("CheckScript() error = %v, want ErrInvalidScript", err)
+	}
+}
+
+func TestSieveUcase_Filter(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   *domain.DeliveryAction
+	}{
+		{
+			name:   "No script",
+			script: "",
+			want:   nil,
+		},
+		{
+			name:   "Fileinto",
+			script: `require ["fileinto", "imap4flags"]; if address :domain "from" "example.com" { fileinto :flags "\\Seen" "Work"; }`,
+			want:   &domain.DeliveryAction{Folders: []string{"Work"}, Flags: []string{`\Seen`}},
+		},
+		{
+			name:   "Discard",
+			script: `discard;`,
+			want:   &domain.DeliveryAction{Discard: true},
+		},
+		{
+			name:   "Implicit keep",
+			script: `if header :is "subject" "other" { discard; }`,
+			want:   &domain.DeliveryAction{Keep: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := New(&MockSieveRepository{
+				GetScriptFn: func(ctx context.Context, profileID int64) (string, error) {
+					return tt.script, nil
+				},
+			})
+
+			got, err := uc.Filter(context.Background(), testDelivery())
+			if err != nil {
+				t.Fatalf("Filter() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Filter() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSieveUcase_Filter_Vacation(t *testing.T) {
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	const script = `require "vacation"; vacation :days 2 "Я в отпуске";`
+
+	tests := []struct {
+		name      string
+		lastSent  time.Time
+		wantReply bool
+	}{
+		{name: "First message gets a reply", wantReply: true},
+		{name: "Reply within days is suppressed", lastSent: now.Add(-24 * time.Hour), wantReply: false},
+		{name: "Reply after days is sent again", lastSent: now.Add(-72 * time.Hour), wantReply: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := false
+			uc := New(&MockSieveRepository{
+				GetScriptFn: func(ctx context.Context, profileID int64) (string, error) {
+					return script, nil
+				},
+				LastVacationResponseFn: func(ctx context.Context, profileID int64, sender, handle string) (time.Time, error) {
+					return tt.lastSent, nil
+				},
+				SaveVacationResponseFn: func(ctx context.Context, profileID int64, sender, handle string, sentAt time.Time) error {
+					saved = true
+					return nil
+				},
+			})
+			uc.now = func() time.Time { return now }
+
+			got, err := uc.Filter(context.Background(), testDelivery())
+			if err != nil {
+				t.Fatalf("Filter() error = %v", err)
+			}
+			if (len(got.Replies) == 1) != tt.wantReply || saved != tt.wantReply {
+				t.Fatalf("replies = %+v, saved = %v, want reply %v", got.Replies, saved, tt.wantReply)
+			}
+			if tt.wantReply {
+				want := domain.AutoReply{To: "boss@example.com", Topic: "Auto: Отчет", Text: "Я в отпуске"}
+				if got.Replies[0] != want {
+					t.Errorf("reply = %+v, want %+v", got.Replies[0], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package spam
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	"2025_2_a4code/internal/lib/bayes"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+const (
+	// DefaultThreshold - оценка, начиная с которой письмо считается спамом
+	DefaultThreshold = 0.9
+	// Статистика пользователя весит больше глобальной, чтобы учитывать его личные отметки
+	userWeight = 3
+)
+
+type SpamRepository interface {
+	GetStats(ctx context.Context, profileID int64, tokens []string) (user bayes.Stats, global bayes.Stats, err error)
+	GetProfileID(ctx context.Context, baseProfileID int64) (int64, error)
+	GetMessageContent(ctx context.Context, profileID, messageID int64) (topic, text, senderDomain string, err error)
+	Train(ctx context.Context, profileID, messageID int64, tokens []string, spam bool) error
+	SaveScore(ctx context.Context, profileID, messageID int64, score float64, reasons []string) error
+}
+
+type SpamUcase struct {
+	repo      SpamRepository
+	threshold float64
+}
+
+func New(repo SpamRepository, threshold float64) *SpamUcase {
+	if threshold <= 0 || threshold > 1 {
+		threshold = DefaultThreshold
+	}
+	return &SpamUcase{repo: repo, threshold: threshold}
+}
+
+// Filter оценивает входящее письмо и перемещает его в спам, если оценка выше порога
+// или отправитель не прошел проверку, которой требует его домен.
+// Оценка добавляется в заголовки X-Spam-*, чтобы ее могли использовать sieve-скрипты.
+func (uc *SpamUcase) Filter(ctx context.Context, delivery *domain.Delivery) (*domain.DeliveryAction, error) {
+	const op = "usecase.spam.Filter"
+	log := logger.GetLogger(ctx).With(slog.String("op", op))
+
+	// Проверку отправителя не отменяет список разрешенных: адрес в From мог быть подделан
+	if reasons := authenticationReasons(delivery); len(reasons) > 0 {
+		log.Debug("sender authentication failed", slog.String("reasons", strings.Join(reasons, ", ")))
+		if err := uc.repo.SaveScore(ctx, delivery.ProfileID, delivery.MessageID, 1, reasons); err != nil {
+			log.Warn("failed to save spam score: " + err.Error())
+		}
+		setSpamHeaders(delivery, 1, true)
+		return &domain.DeliveryAction{Folders: []string{string(domain.FolderSpam)}}, nil
+	}
+
+	if delivery.SenderAllowed {
+		log.Debug("sender is allowlisted, skipping classification")
+		return nil, nil
+	}
+
+	tokens := bayes.Tokenize(delivery.Topic, delivery.Text, senderDomain(delivery.From))
+	user, global, err := uc.repo.GetStats(ctx, delivery.ProfileID, tokens)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	stats := global.Merge(user, userWeight)
+	if !stats.Trained() {
+		log.Debug("spam filter is not trained yet")
+		return nil, nil
+	}
+
+	result := bayes.Classify(tokens, stats)
+	reasons := make([]string, 0, len(result.Reasons))
+	for _, reason := range result.Reasons {
+		reasons = append(reasons, reason.String())
+	}
+	if err := uc.repo.SaveScore(ctx, delivery.ProfileID, delivery.MessageID, result.Score, reasons); err != nil {
+		log.Warn("failed to save spam score: " + err.Error())
+	}
+
+	isSpam := result.Score >= uc.threshold
+	setSpamHeaders(delivery, result.Score, isSpam)
+
+	if !isSpam {
+		return nil, nil
+	}
+	return &domain.DeliveryAction{Folders: []string{string(domain.FolderSpam)}}, nil
+}
+
+// authenticationReasons возвращает причины считать письмо спамом по результатам
+// проверки отправителя: домен из From просит не доставлять неподтвержденные письма
+// (DMARC) или домен из MAIL FROM запрещает отправку с этого адреса (SPF)
+func authenticationReasons(delivery *domain.Delivery) []string {
+	var reasons []string
+	if delivery.DMARC.Quarantined() {
+		reasons = append(reasons, fmt.Sprintf("dmarc=%s (p=%s)", delivery.DMARC.Status, delivery.DMARC.Policy))
+	}
+	if delivery.SPF.Status == domain.SPFFail && !delivery.DMARC.Aligned {
+		reasons = append(reasons, fmt.Sprintf("spf=%s smtp.mailfrom=%s", delivery.SPF.Status, delivery.SPF.Domain))
+	}
+	return reasons
+}
+
+// setSpamHeaders добавляет оценку в заголовки X-Spam-*
+func setSpamHeaders(delivery *domain.Delivery, score float64, isSpam bool) {
+	if delivery.Headers == nil {
+		return
+	}
+	delivery.Headers["X-Spam-Score"] = []string{fmt.Sprintf("%.4f", score)}
+	flag := "NO"
+	if isSpam {
+		flag = "YES"
+	}
+	delivery.Headers["X-Spam-Flag"] = []string{flag}
+}
+
+// Train обучает фильтр пользователя и глобальный фильтр на письме, отмеченном как спам или не спам.
+// Пользователь приходит с идентификатором базового профиля из токена, а статистика хранится
+// под идентификатором профиля, как и в Filter
+func (uc *SpamUcase) Train(ctx context.Context, baseProfileID, messageID int64, spam bool) error {
+	const op = "usecase.spam.Train"
+
+	profileID, err := uc.repo.GetProfileID(ctx, baseProfileID)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	topic, text, domainName, err := uc.repo.GetMessageContent(ctx, profileID, messageID)
+	if err != nil {
+		return e.Wrap(op, err)
+	}
+
+	tokens := bayes.Tokenize(topic, text, domainName)
+	if err := uc.repo.Train(ctx, profileID, messageID, tokens, spam); err != nil {
+		return e.Wrap(op, err)
+	}
+	return nil
+}
+
+func senderDomain(address string) string {
+	if i := strings.LastIndex(address, "@"); i >= 0 {
+		return address[i+1:]
+	}
+	return ""
+}
@@ -0,0 +1,311 @@
+package spam
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/lib/bayes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type MockSpamRepository struct {
+	GetStatsFn          func(ctx context.Context, profileID int64, tokens []string) (bayes.Stats, bayes.Stats, error)
+	GetProfileIDFn      func(ctx context.Context, baseProfileID int64) (int64, error)
+	GetMessageContentFn func(ctx context.Context, profileID, messageID int64) (string, string, string, error)
+	TrainFn             func(ctx context.Context, profileID, messageID int64, tokens []string, spam bool) error
+	SaveScoreFn         func(ctx context.Context, profileID, messageID int64, score float64, reasons []string) error
+}
+
+func (m *MockSpamRepository) GetStats(ctx context.Context, profileID int64, tokens []string) (bayes.Stats, bayes.Stats, error) {
+	if m.GetStatsFn != nil {
+		return m.GetStatsFn(ctx, profileID, tokens)
+	}
+	return bayes.Stats{}, bayes.Stats{}, nil
+}
+
+func (m *MockSpamRepository) GetProfileID(ctx context.Context, baseProfileID int64) (int64, error) {
+	if m.GetProfileIDFn != nil {
+		return m.GetProfileIDFn(ctx, baseProfileID)
+	}
+	return baseProfileID, nil
+}
+
+func (m *MockSpamRepository) GetMessageContent(ctx context.Context, profileID, messageID int64) (string, string, string, error) {
+	if m.GetMessageContentFn != nil {
+		return m.GetMessageContentFn(ctx, profileID, messageID)
+	}
+	return "", "", "", nil
+}
+
+func (m *MockSpamRepository) Train(ctx context.Context, profileID, messageID int64, tokens []string, spam bool) error {
+	if m.TrainFn != nil {
+		return m.TrainFn(ctx, profileID, messageID, tokens, spam)
+	}
+	return nil
+}
+
+func (m *MockSpamRepository) SaveScore(ctx context.Context, profileID, messageID int64, score float64, reasons []string) error {
+	if m.SaveScoreFn != nil {
+		return m.SaveScoreFn(ctx, profileID, messageID, score, reasons)
+	}
+	return nil
+}
+
+func trainedStats() bayes.Stats {
+	return bayes.Stats{
+		SpamMessages: 50,
+		HamMessages:  50,
+		Tokens: map[string]bayes.TokenCounts{
+			"viagra":        {Spam: 45, Ham: 0},
+			"casino":        {Spam: 40, Ham: 1},
+			"from:spam.biz": {Spam: 30, Ham: 0},
+			"meeting":       {Spam: 1, Ham: 40},
+			"report":        {Spam: 0, Ham: 35},
+		},
+	}
+}
+
+func TestSpamUcase_Filter(t *testing.T) {
+	tests := []struct {
+		name       string
+		delivery   domain.Delivery
+		stats      bayes.Stats
+		wantAction *domain.DeliveryAction
+		wantFlag   string
+		wantScore  bool
+	}{
+		{
+			name:       "Spam is moved to spam folder",
+			delivery:   domain.Delivery{From: "bot@spam.biz", Topic: "Viagra casino", Text: "viagra casino"},
+			stats:      trainedStats(),
+			wantAction: &domain.DeliveryAction{Folders: []string{"spam"}},
+			wantFlag:   "YES",
+			wantScore:  true,
+		},
+		{
+			name:      "Ham stays in inbox",
+			delivery:  domain.Delivery{From: "boss@a4mail.ru", Topic: "Meeting", Text: "meeting report"},
+			stats:     trainedStats(),
+			wantFlag:  "NO",
+			wantScore: true,
+		},
+		{
+			name:     "Untrained filter makes no decision",
+			delivery: domain.Delivery{From: "bot@spam.biz", Topic: "Viagra", Text: "viagra"},
+			stats:    bayes.Stats{SpamMessages: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scored := false
+			repo := &MockSpamRepository{
+				GetStatsFn: func(ctx context.Context, profileID int64, tokens []string) (bayes.Stats, bayes.Stats, error) {
+					return bayes.Stats{}, tt.stats, nil
+				},
+				SaveScoreFn: func(ctx context.Context, profileID, messageID int64, score float64, reasons []string) error {
+					scored = true
+					if len(reasons) == 0 {
+						t.Error("expected spam reasons")
+					}
+					return nil
+				},
+			}
+
+			delivery := tt.delivery
+			delivery.Headers = map[string][]string{}
+			action, err := New(repo, 0).Filter(context.Background(), &delivery)
+			if err != nil {
+				t.Fatalf("Filter() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(action, tt.wantAction) {
+				t.Errorf("Filter() = %+v, want %+v", action, tt.wantAction)
+			}
+			if scored != tt.wantScore {
+				t.Errorf("score saved = %v, want %v", scored, tt.wantScore)
+			}
+			if got := delivery.Headers["X-Spam-Flag"]; tt.wantFlag != "" && (len(got) != 1 || got[0] != tt.wantFlag) {
+				t.Errorf("X-Spam-Flag = %v, want %s", got, tt.wantFlag)
+			}
+		})
+	}
+}
+
+func TestSpamUcase_Filter_SenderAllowed(t *testing.T) {
+	repo := &MockSpamRepository{
+		GetStatsFn: func(ctx context.Context, profileID int64, tokens []string) (bayes.Stats, bayes.Stats, error) {
+			t.Error("allowlisted sender must not be classified")
+			return bayes.Stats{}, trainedStats(), nil
+		},
+	}
+
+	delivery := &domain.Delivery{From: "bot@spam.biz", Topic: "Viagra casino", Text: "viagra casino", SenderAllowed: true}
+	action, err := New(repo, 0).Filter(context.Background(), delivery)
+
+	if err != nil || action != nil {
+		t.Errorf("Filter() = %+v, %v; want no decision", action, err)
+	}
+}
+
+func TestSpamUcase_Filter_Authentication(t *testing.T) {
+	tests := []struct {
+		name        string
+		delivery    domain.Delivery
+		wantSpam    bool
+		wantReasons []string
+	}{
+		{
+			name: "DMARC reject policy",
+			delivery: domain.Delivery{
+				DMARC: domain.DMARCResult{Status: domain.DMARCFail, Policy: domain.DMARCPolicyReject},
+			},
+			wantSpam:    true,
+			wantReasons: []string{"dmarc=fail (p=reject)"},
+		},
+		{
+			name: "Allowlist does not override DMARC",
+			delivery: domain.Delivery{
+				SenderAllowed: true,
+				DMARC:         domain.DMARCResult{Status: domain.DMARCFail, Policy: domain.DMARCPolicyQuarantine},
+			},
+			wantSpam:    true,
+			wantReasons: []string{"dmarc=fail (p=quarantine)"},
+		},
+		{
+			name: "SPF fail",
+			delivery: domain.Delivery{
+				SPF:   domain.SPFResult{Status: domain.SPFFail, Domain: "bank.com"},
+				DMARC: domain.DMARCResult{Status: domain.DMARCNone},
+			},
+			wantSpam:    true,
+			wantReasons: []string{"spf=fail smtp.mailfrom=bank.com"},
+		},
+		{
+			name: "SPF fail on forwarded mail with valid signature",
+			delivery: domain.Delivery{
+				SPF:   domain.SPFResult{Status: domain.SPFFail, Domain: "bank.com"},
+				DMARC: domain.DMARCResult{Status: domain.DMARCPass, Policy: domain.DMARCPolicyReject, Aligned: true},
+			},
+		},
+		{
+			name: "DMARC monitoring policy",
+			delivery: domain.Delivery{
+				DMARC: domain.DMARCResult{Status: domain.DMARCFail, Policy: domain.DMARCPolicyNone},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotReasons []string
+			repo := &MockSpamRepository{
+				SaveScoreFn: func(ctx context.Context, profileID, messageID int64, score float64, reasons []string) error {
+					gotReasons = reasons
+					return nil
+				},
+			}
+
+			delivery := tt.delivery
+			delivery.Headers = map[string][]string{}
+			action, err := New(repo, 0).Filter(context.Background(), &delivery)
+			if err != nil {
+				t.Fatalf("Filter() error = %v", err)
+			}
+
+			if gotSpam := action != nil && reflect.DeepEqual(action.Folders, []string{"spam"}); gotSpam != tt.wantSpam {
+				t.Errorf("Filter() = %+v, want spam = %v", action, tt.wantSpam)
+			}
+			if !reflect.DeepEqual(gotReasons, tt.wantReasons) {
+				t.Errorf("reasons = %v, want %v", gotReasons, tt.wantReasons)
+			}
+			if tt.wantSpam && !reflect.DeepEqual(delivery.Headers["X-Spam-Flag"], []string{"YES"}) {
+				t.Errorf("X-Spam-Flag = %v, want YES", delivery.Headers["X-Spam-Flag"])
+			}
+		})
+	}
+}
+
+func TestSpamUcase_Filter_RepoError(t *testing.T) {
+	repo := &MockSpamRepository{
+		GetStatsFn: func(ctx context.Context, profileID int64, tokens []string) (bayes.Stats, bayes.Stats, error) {
+			return bayes.Stats{}, bayes.Stats{}, errors.New("db error")
+		},
+	}
+
+	if _, err := New(repo, 0).Filter(context.Background(), &domain.Delivery{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestSpamUcase_Train(t *testing.T) {
+	var gotSpam bool
+	var gotTokens []string
+	repo := &MockSpamRepository{
+		GetMessageContentFn: func(ctx context.Context, profileID, messageID int64) (string, string, string, error) {
+			return "Cheap viagra", "buy now", "spam.biz", nil
+		},
+		TrainFn: func(ctx context.Context, profileID, messageID int64, tokens []string, spam bool) error {
+			gotSpam, gotTokens = spam, tokens
+			return nil
+		},
+	}
+
+	if err := New(repo, 0).Train(context.Background(), 7, 42, true); err != nil {
+		t.Fatalf("Train() error = %v", err)
+	}
+	if !gotSpam {
+		t.Error("expected spam training")
+	}
+	if len(gotTokens) == 0 {
+		t.Error("expected tokens")
+	}
+}
+
+func TestSpamUcase_Train_ProfileID(t *testing.T) {
+	// Идентификатор базового профиля из токена не совпадает с идентификатором профиля,
+	// под которым письма доставляются и оцениваются фильтром
+	const baseProfileID, profileID = 3, 7
+
+	stats := map[int64]bayes.Stats{}
+	repo := &MockSpamRepository{
+		GetProfileIDFn: func(ctx context.Context, id int64) (int64, error) {
+			if id != baseProfileID {
+				t.Errorf("GetProfileID() base profile id = %d, want %d", id, baseProfileID)
+			}
+			return profileID, nil
+		},
+		GetMessageContentFn: func(ctx context.Context, id, messageID int64) (string, string, string, error) {
+			if id != profileID {
+				t.Errorf("GetMessageContent() profile id = %d, want %d", id, profileID)
+			}
+			return "Cheap viagra casino", "", "spam.biz", nil
+		},
+		TrainFn: func(ctx context.Context, id, messageID int64, tokens []string, spam bool) error {
+			trained := bayes.Stats{SpamMessages: 20, HamMessages: 20, Tokens: map[string]bayes.TokenCounts{}}
+			for _, token := range tokens {
+				trained.Tokens[token] = bayes.TokenCounts{Spam: 20}
+			}
+			stats[id] = trained
+			return nil
+		},
+		GetStatsFn: func(ctx context.Context, id int64, tokens []string) (bayes.Stats, bayes.Stats, error) {
+			return stats[id], bayes.Stats{}, nil
+		},
+	}
+	uc := New(repo, 0)
+
+	if err := uc.Train(context.Background(), baseProfileID, 42, true); err != nil {
+		t.Fatalf("Train() error = %v", err)
+	}
+
+	delivery := &domain.Delivery{ProfileID: profileID, MessageID: 43, Topic: "Cheap viagra casino", From: "a@spam.biz", Headers: map[string][]string{}}
+	action, err := uc.Filter(context.Background(), delivery)
+	if err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if action == nil || !reflect.DeepEqual(action.Folders, []string{string(domain.FolderSpam)}) {
+		t.Errorf("Filter() action = %+v, want message moved to spam by user statistics", action)
+	}
+}
@@ -0,0 +1,100 @@
+package vacation
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/lib/sieve"
+	e "2025_2_a4code/internal/lib/wrapper"
+	"context"
+	"html"
+	"net/textproto"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxTopicLength - ограничение длины темы письма в базе
+const maxTopicLength = 200
+
+type VacationRepository interface {
+	GetVacation(ctx context.Context, profileID int64) (domain.VacationSettings, error)
+	IsContact(ctx context.Context, baseProfileID int64, address string) (bool, error)
+	LastResponse(ctx context.Context, profileID int64, sender string) (time.Time, error)
+	SaveResponse(ctx context.Context, profileID int64, sender string, sentAt time.Time) error
+}
+
+type VacationUcase struct {
+	repo VacationRepository
+	now  func() time.Time
+}
+
+func New(repo VacationRepository) *VacationUcase {
+	return &VacationUcase{repo: repo, now: time.Now}
+}
+
+// Filter отвечает отправителю автоответом "вне офиса", если он включен у получателя.
+// Рассылкам, роботам, автоответам и спаму не отвечает, одному отправителю отвечает
+// не чаще одного раза за заданное в настройках число дней. Куда попадет письмо, решают
+// и следующие фильтры, поэтому ответ помечен InboxOnly: он не уйдет, если письмо
+// убрано из входящих или помещено в спам
+func (uc *VacationUcase) Filter(ctx context.Context, delivery *domain.Delivery) (*domain.DeliveryAction, error) {
+	const op = "usecase.vacation.Filter"
+
+	vacation, err := uc.repo.GetVacation(ctx, delivery.ProfileID)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	now := uc.now()
+	if !vacation.Active(now) || !shouldReply(delivery) {
+		return nil, nil
+	}
+
+	if vacation.ContactsOnly {
+		known, err := uc.repo.IsContact(ctx, delivery.BaseProfileID, delivery.From)
+		if err != nil {
+			return nil, e.Wrap(op, err)
+		}
+		if !known {
+			return nil, nil
+		}
+	}
+
+	last, err := uc.repo.LastResponse(ctx, delivery.ProfileID, delivery.From)
+	if err != nil {
+		return nil, e.Wrap(op, err)
+	}
+	if !last.IsZero() && now.Sub(last) < time.Duration(vacation.Days)*24*time.Hour {
+		return nil, nil
+	}
+	if err := uc.repo.SaveResponse(ctx, delivery.ProfileID, delivery.From, now); err != nil {
+		return nil, e.Wrap(op, err)
+	}
+
+	topic := html.EscapeString(vacation.Subject)
+	if topic == "" {
+		topic = truncate("Auto: "+delivery.Topic, maxTopicLength)
+	}
+	return &domain.DeliveryAction{Replies: []domain.AutoReply{{
+		To:        delivery.From,
+		Topic:     topic,
+		Text:      html.EscapeString(vacation.Body),
+		InboxOnly: true,
+	}}}, nil
+}
+
+func shouldReply(delivery *domain.Delivery) bool {
+	if delivery.AutoSubmitted || delivery.From == "" || strings.EqualFold(delivery.From, delivery.To) {
+		return false
+	}
+	if flags := delivery.Headers["X-Spam-Flag"]; len(flags) > 0 && strings.EqualFold(flags[0], "YES") {
+		return false
+	}
+	return !sieve.IsAutomated(textproto.MIMEHeader(delivery.Headers), delivery.From)
+}
+
+func truncate(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit])
+}
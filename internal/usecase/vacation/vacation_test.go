@@ -0,0 +1,174 @@
+package vacation
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockVacationRepository struct {
+	vacation domain.VacationSettings
+	known    bool
+	last     time.Time
+	saved    []string
+}
+
+func (m *mockVacationRepository) GetVacation(ctx context.Context, profileID int64) (domain.VacationSettings, error) {
+	return m.vacation, nil
+}
+
+func (m *mockVacationRepository) IsContact(ctx context.Context, baseProfileID int64, address string) (bool, error) {
+	return m.known, nil
+}
+
+func (m *mockVacationRepository) LastResponse(ctx context.Context, profileID int64, sender string) (time.Time, error) {
+	return m.last, nil
+}
+
+func (m *mockVacationRepository) SaveResponse(ctx context.Context, profileID int64, sender string, sentAt time.Time) error {
+	m.saved = append(m.saved, sender)
+	m.last = sentAt
+	return nil
+}
+
+func TestVacationUcase_Filter(t *testing.T) {
+	now := time.Date(2025, 7, 10, 12, 0, 0, 0, time.UTC)
+	active := domain.VacationSettings{
+		Enabled: true,
+		Start:   now.Add(-24 * time.Hour),
+		End:     now.Add(24 * time.Hour),
+		Body:    "Away <until> Monday",
+		Days:    7,
+	}
+
+	newDelivery := func() *domain.Delivery {
+		return &domain.Delivery{
+			ProfileID: 1,
+			From:      "friend@a4mail.ru",
+			To:        "me@a4mail.ru",
+			Topic:     "Lunch?",
+			Headers:   map[string][]string{"From": {"friend@a4mail.ru"}},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		vacation  domain.VacationSettings
+		known     bool
+		last      time.Time
+		delivery  func(d *domain.Delivery)
+		wantReply bool
+	}{
+		{name: "Replies when active", vacation: active, wantReply: true},
+		{name: "Disabled", vacation: domain.VacationSettings{Body: "Away", Days: 7}},
+		{name: "Before start", vacation: func() domain.VacationSettings { v := active; v.Start = now.Add(time.Hour); return v }()},
+		{name: "After end", vacation: func() domain.VacationSettings { v := active; v.End = now; return v }()},
+		{name: "Replied recently", vacation: active, last: now.Add(-6 * 24 * time.Hour)},
+		{name: "Replied long ago", vacation: active, last: now.Add(-8 * 24 * time.Hour), wantReply: true},
+		{
+			name:     "Mailing list",
+			vacation: active,
+			delivery: func(d *domain.Delivery) { d.Headers["List-Id"] = []string{"<news.a4mail.ru>"} },
+		},
+		{
+			name:     "No-reply sender",
+			vacation: active,
+			delivery: func(d *domain.Delivery) { d.From = "no-reply@shop.ru" },
+		},
+		{
+			name:     "Auto submitted",
+			vacation: active,
+			delivery: func(d *domain.Delivery) { d.AutoSubmitted = true },
+		},
+		{
+			name:     "Spam",
+			vacation: active,
+			delivery: func(d *domain.Delivery) { d.Headers["X-Spam-Flag"] = []string{"YES"} },
+		},
+		{
+			name:     "Own message",
+			vacation: active,
+			delivery: func(d *domain.Delivery) { d.From = "Me@a4mail.ru" },
+		},
+		{
+			name:     "Contacts only, unknown sender",
+			vacation: func() domain.VacationSettings { v := active; v.ContactsOnly = true; return v }(),
+		},
+		{
+			name:      "Contacts only, known sender",
+			vacation:  func() domain.VacationSettings { v := active; v.ContactsOnly = true; return v }(),
+			known:     true,
+			wantReply: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockVacationRepository{vacation: tt.vacation, known: tt.known, last: tt.last}
+			uc := &VacationUcase{repo: repo, now: func() time.Time { return now }}
+			delivery := newDelivery()
+			if tt.delivery != nil {
+				tt.delivery(delivery)
+			}
+
+			action, err := uc.Filter(context.Background(), delivery)
+			if err != nil {
+				t.Fatalf("Filter() error = %v", err)
+			}
+
+			if !tt.wantReply {
+				if action != nil {
+					t.Errorf("Filter() = %+v, want no reply", action)
+				}
+				if len(repo.saved) != 0 {
+					t.Errorf("Filter() saved response state without replying")
+				}
+				return
+			}
+			if action == nil || len(action.Replies) != 1 {
+				t.Fatalf("Filter() = %+v, want one reply", action)
+			}
+			reply := action.Replies[0]
+			if reply.To != "friend@a4mail.ru" || reply.Topic != "Auto: Lunch?" || reply.Text != "Away &lt;until&gt; Monday" || !reply.InboxOnly {
+				t.Errorf("Filter() reply = %+v", reply)
+			}
+			if action.Discard || len(action.Folders) > 0 {
+				t.Errorf("Filter() must not change placement: %+v", action)
+			}
+		})
+	}
+}
+
+func TestVacationUcase_FilterOncePerPeriod(t *testing.T) {
+	now := time.Date(2025, 7, 10, 12, 0, 0, 0, time.UTC)
+	repo := &mockVacationRepository{vacation: domain.VacationSettings{Enabled: true, Subject: "OOO", Body: "Away", Days: 3}}
+	uc := &VacationUcase{repo: repo, now: func() time.Time { return now }}
+	delivery := &domain.Delivery{From: "friend@a4mail.ru", To: "me@a4mail.ru", Headers: map[string][]string{}}
+
+	first, _ := uc.Filter(context.Background(), delivery)
+	second, _ := uc.Filter(context.Background(), delivery)
+	now = now.Add(3 * 24 * time.Hour)
+	third, _ := uc.Filter(context.Background(), delivery)
+
+	if first == nil || first.Replies[0].Topic != "OOO" {
+		t.Errorf("first Filter() = %+v, want reply with custom subject", first)
+	}
+	if second != nil {
+		t.Errorf("second Filter() = %+v, want no reply", second)
+	}
+	if third == nil {
+		t.Error("Filter() after the period must reply again")
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	long := strings.Repeat("я", maxTopicLength+10)
+	if got := truncate(long, maxTopicLength); len([]rune(got)) != maxTopicLength {
+		t.Errorf("truncate() length = %d, want %d", len([]rune(got)), maxTopicLength)
+	}
+	if got := truncate("short", maxTopicLength); got != "short" {
+		t.Errorf("truncate() = %q", got)
+	}
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"2025_2_a4code/auth-service/pkg/authproto"
+	profileUcase "2025_2_a4code/internal/usecase/profile"
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// appPasswordAuth проверяет пароли приложений почтовых клиентов через сервис авторизации
+type appPasswordAuth struct {
+	client   authproto.AuthServiceClient
+	protocol string
+}
+
+func newAppPasswordAuth(client authproto.AuthServiceClient, protocol string) *appPasswordAuth {
+	return &appPasswordAuth{client: client, protocol: protocol}
+}
+
+// Authenticate возвращает profileUcase.ErrWrongPassword на неверные учетные данные, чтобы серверы
+// отличали их от недоступности сервиса авторизации
+func (a *appPasswordAuth) Authenticate(ctx context.Context, login, password, remoteIP string) (int64, error) {
+	resp, err := a.client.AuthenticateAppPassword(ctx, &authproto.AuthenticateAppPasswordRequest{
+		Login:    login,
+		Password: password,
+		RemoteIp: remoteIP,
+		Protocol: a.protocol,
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.Unauthenticated, codes.InvalidArgument:
+			return 0, profileUcase.ErrWrongPassword
+		}
+		return 0, err
+	}
+	return resp.ProfileId, nil
+}
+
+// accountAuth проверяет пароль от аккаунта
+type accountAuth interface {
+	Authenticate(ctx context.Context, login, password string) (int64, error)
+}
+
+// mailClientAuth пускает почтовые клиенты по паролю приложения, а если это разрешено
+// настройками протокола - и по паролю от аккаунта
+type mailClientAuth struct {
+	appPasswords *appPasswordAuth
+	// nil - вход по паролю от аккаунта выключен
+	accounts accountAuth
+}
+
+func newMailClientAuth(client authproto.AuthServiceClient, protocol string, allowAccountPassword bool, accounts accountAuth) *mailClientAuth {
+	auth := &mailClientAuth{appPasswords: newAppPasswordAuth(client, protocol)}
+	if allowAccountPassword {
+		auth.accounts = accounts
+	}
+	return auth
+}
+
+// Authenticate сначала проверяет пароли приложений. Пароль от аккаунта проверяется только
+// после отказа сервиса авторизации, а не при его недоступности
+func (a *mailClientAuth) Authenticate(ctx context.Context, login, password, remoteIP string) (int64, error) {
+	profileID, err := a.appPasswords.Authenticate(ctx, login, password, remoteIP)
+	if err == nil || a.accounts == nil || !errors.Is(err, profileUcase.ErrWrongPassword) {
+		return profileID, err
+	}
+	return a.accounts.Authenticate(ctx, login, password)
+}
@@ -0,0 +1,93 @@
+package app
+
+import (
+	"2025_2_a4code/auth-service/pkg/authproto"
+	profileUcase "2025_2_a4code/internal/usecase/profile"
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// stubAuthClient отвечает на проверку пароля приложения заранее заданным результатом
+type stubAuthClient struct {
+	authproto.AuthServiceClient
+	profileID int64
+	err       error
+}
+
+func (c stubAuthClient) AuthenticateAppPassword(ctx context.Context, in *authproto.AuthenticateAppPasswordRequest, opts ...grpc.CallOption) (*authproto.AuthenticateAppPasswordResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &authproto.AuthenticateAppPasswordResponse{ProfileId: c.profileID}, nil
+}
+
+// stubAccountAuth запоминает, проверялся ли пароль от аккаунта
+type stubAccountAuth struct {
+	profileID int64
+	err       error
+	called    *bool
+}
+
+func (a stubAccountAuth) Authenticate(ctx context.Context, login, password string) (int64, error) {
+	*a.called = true
+	return a.profileID, a.err
+}
+
+func TestMailClientAuth_Authenticate(t *testing.T) {
+	rejected := status.Error(codes.Unauthenticated, "wrong password")
+
+	tests := []struct {
+		name           string
+		client         stubAuthClient
+		allowAccount   bool
+		account        stubAccountAuth
+		want           int64
+		wantErr        error
+		wantAccountHit bool
+	}{
+		{name: "App password", client: stubAuthClient{profileID: 42}, allowAccount: true, want: 42},
+		{
+			name: "Account password", client: stubAuthClient{err: rejected}, allowAccount: true,
+			account: stubAccountAuth{profileID: 42}, want: 42, wantAccountHit: true,
+		},
+		{
+			name: "Account password disabled", client: stubAuthClient{err: rejected},
+			account: stubAccountAuth{profileID: 42}, wantErr: profileUcase.ErrWrongPassword,
+		},
+		{
+			name: "Wrong account password", client: stubAuthClient{err: rejected}, allowAccount: true,
+			account: stubAccountAuth{err: profileUcase.ErrWrongPassword}, wantErr: profileUcase.ErrWrongPassword, wantAccountHit: true,
+		},
+		{
+			name: "Auth service unavailable", client: stubAuthClient{err: status.Error(codes.Unavailable, "down")}, allowAccount: true,
+			account: stubAccountAuth{profileID: 42},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			tt.account.called = &called
+			auth := newMailClientAuth(tt.client, "imap", tt.allowAccount, tt.account)
+
+			got, err := auth.Authenticate(context.Background(), "alexey", "secret", "192.0.2.1")
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Authenticate() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && tt.want == 0 && err == nil {
+				t.Error("Authenticate() must fail when the auth service is unavailable")
+			}
+			if got != tt.want {
+				t.Errorf("Authenticate() = %d, want %d", got, tt.want)
+			}
+			if called != tt.wantAccountHit {
+				t.Errorf("account password checked = %v, want %v", called, tt.wantAccountHit)
+			}
+		})
+	}
+}
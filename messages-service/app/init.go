@@ -1,11 +1,22 @@
 package app
 
 import (
+	"2025_2_a4code/auth-service/pkg/authproto"
 	"2025_2_a4code/internal/config"
 	"2025_2_a4code/internal/http-server/middleware/logger"
+	"2025_2_a4code/internal/lib/dkim"
+	"2025_2_a4code/internal/lib/dmarc"
+	"2025_2_a4code/internal/lib/events"
 	in "2025_2_a4code/internal/lib/init"
 	"2025_2_a4code/internal/lib/metrics"
+	"2025_2_a4code/internal/lib/spf"
 	messagesservice "2025_2_a4code/messages-service/grpc-service"
+	imapserver "2025_2_a4code/messages-service/imap-server"
+	pop3server "2025_2_a4code/messages-service/pop3-server"
+	smtpclient "2025_2_a4code/messages-service/smtp-client"
+	smtpserver "2025_2_a4code/messages-service/smtp-server"
+	submissionserver "2025_2_a4code/messages-service/submission-server"
+	"crypto/tls"
 	"database/sql"
 	"net"
 	"net/http"
@@ -14,10 +25,35 @@ import (
 	// uploadfile "2025_2_a4code/internal/http-server/handlers/user/upload/upload-file"
 
 	avatarrepository "2025_2_a4code/internal/storage/minio/avatar-repository"
+	aliasrepository "2025_2_a4code/internal/storage/postgres/alias-repository"
+	changerepository "2025_2_a4code/internal/storage/postgres/change-repository"
+	contactrepository "2025_2_a4code/internal/storage/postgres/contact-repository"
+	forwardingrepository "2025_2_a4code/internal/storage/postgres/forwarding-repository"
+	labelrepository "2025_2_a4code/internal/storage/postgres/label-repository"
 	messagerepository "2025_2_a4code/internal/storage/postgres/message-repository"
+	notificationrepository "2025_2_a4code/internal/storage/postgres/notification-repository"
+	outboundrepository "2025_2_a4code/internal/storage/postgres/outbound-repository"
+	pop3repository "2025_2_a4code/internal/storage/postgres/pop3-repository"
 	profilerepository "2025_2_a4code/internal/storage/postgres/profile-repository"
+	senderlistrepository "2025_2_a4code/internal/storage/postgres/sender-list-repository"
+	sieverepository "2025_2_a4code/internal/storage/postgres/sieve-repository"
+	spamrepository "2025_2_a4code/internal/storage/postgres/spam-repository"
+	vacationrepository "2025_2_a4code/internal/storage/postgres/vacation-repository"
+	aliasUcase "2025_2_a4code/internal/usecase/alias"
 	avatarUcase "2025_2_a4code/internal/usecase/avatar"
+	changesUcase "2025_2_a4code/internal/usecase/changes"
+	contactUcase "2025_2_a4code/internal/usecase/contact"
+	forwardingUcase "2025_2_a4code/internal/usecase/forwarding"
+	labelUcase "2025_2_a4code/internal/usecase/label"
 	messageUcase "2025_2_a4code/internal/usecase/message"
+	notificationUcase "2025_2_a4code/internal/usecase/notification"
+	outboundUcase "2025_2_a4code/internal/usecase/outbound"
+	pop3Ucase "2025_2_a4code/internal/usecase/pop3"
+	profileUcase "2025_2_a4code/internal/usecase/profile"
+	senderlistUcase "2025_2_a4code/internal/usecase/senderlist"
+	sieveUcase "2025_2_a4code/internal/usecase/sieve"
+	spamUcase "2025_2_a4code/internal/usecase/spam"
+	vacationUcase "2025_2_a4code/internal/usecase/vacation"
 	pb "2025_2_a4code/messages-service/pkg/messagesproto"
 	"context"
 	"fmt"
@@ -29,6 +65,7 @@ import (
 	_ "github.com/jackc/pgx/v4/stdlib"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
@@ -82,11 +119,90 @@ func MessagesInit() {
 	// Создание репозиториев
 	messageRepository := messagerepository.New(connection)
 	profileRepository := profilerepository.New(connection)
+	sieveRepository := sieverepository.New(connection)
+	spamRepository := spamrepository.New(connection)
+	labelRepository := labelrepository.New(connection)
+	forwardingRepository := forwardingrepository.New(connection)
+	vacationRepository := vacationrepository.New(connection)
+	contactRepository := contactrepository.New(connection)
+	senderListRepository := senderlistrepository.New(connection)
+	aliasRepository := aliasrepository.New(connection)
+	notificationRepository := notificationrepository.New(connection)
+	pop3Repository := pop3repository.New(connection)
+	changeRepository := changerepository.New(connection)
 	avatarRepository := avatarrepository.New(client, cfg.MinioConfig.BucketName, cfg.MinioConfig.PublicEndpoint, cfg.MinioConfig.PublicUseSSL)
 
 	// Создание юзкейсов
-	messageUCase := messageUcase.New(messageRepository)
+	sieveUCase := sieveUcase.New(sieveRepository)
+	spamUCase := spamUcase.New(spamRepository, cfg.AppConfig.SpamThreshold)
+	labelUCase := labelUcase.New(labelRepository)
+	forwardingUCase := forwardingUcase.New(forwardingRepository)
+	vacationUCase := vacationUcase.New(vacationRepository)
+	contactUCase := contactUcase.New(contactRepository)
+	senderListUCase := senderlistUcase.New(senderListRepository)
+	aliasUCase := aliasUcase.New(aliasRepository)
+	pop3UCase := pop3Ucase.New(pop3Repository)
+	changesUCase := changesUcase.New(changeRepository)
+	// списки отправителей проверяются до спам-фильтра: заблокированные письма дальше не обрабатываются,
+	// а разрешенные не оцениваются. Спам-фильтр идет раньше sieve-скриптов и автоответа, чтобы они
+	// видели заголовки X-Spam-*, автопересылка - последней, чтобы не пересылать спам
+	messageUCase := messageUcase.New(messageRepository, senderListUCase, spamUCase, sieveUCase, vacationUCase, forwardingUCase)
 	avatarUCase := avatarUcase.New(avatarRepository, profileRepository)
+	profileUCase := profileUcase.New(profileRepository)
+
+	// События хранятся в памяти процесса: продолжить поток после переподключения
+	// можно только в пределах одного экземпляра сервиса
+	eventHub := events.NewHub(events.DefaultHistorySize)
+	messageUCase.SetEventPublisher(eventHub)
+	notificationUCase := notificationUcase.New(notificationRepository, eventHub)
+
+	// Вложения внешних писем хранятся в том же бакете, что и аватары
+	messageUCase.SetAttachmentStorage(avatarRepository)
+
+	go purgeTrash(messageUCase, log)
+
+	// SMTP-сервер работает в этом же процессе, чтобы события о новых письмах
+	// попадали в eventHub, на который подписаны клиенты
+	var dkimVerifier *dkim.Verifier
+	if cfg.DKIMConfig.Verify {
+		dkimVerifier = dkim.NewVerifier(nil)
+	}
+	if cfg.SMTPConfig.Port != "" {
+		go startSMTPServer(cfg.SMTPConfig, dkimVerifier, messageUCase, log)
+	}
+
+	// Почтовые клиенты входят по паролям приложений, их проверяет сервис авторизации.
+	// Вход по паролю от аккаунта включается в настройках каждого протокола
+	authHost := cfg.AppConfig.AuthHost
+	if authHost == "" {
+		authHost = cfg.AppConfig.Host
+	}
+	authConn, err := grpc.NewClient(authHost+":"+cfg.AppConfig.AuthPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Error("failed to create auth client: " + err.Error())
+		os.Exit(1)
+	}
+	defer authConn.Close()
+	authClient := authproto.NewAuthServiceClient(authConn)
+
+	// IMAP-сервер получает события из того же eventHub, чтобы IDLE сразу сообщал о новых письмах
+	if cfg.IMAPConfig.Port != "" {
+		go startIMAPServer(cfg.IMAPConfig, messageUCase, newMailClientAuth(authClient, "imap", cfg.IMAPConfig.AllowAccountPassword, profileUCase), eventHub, log)
+	}
+	if cfg.POP3Config.Port != "" {
+		go startPOP3Server(cfg.POP3Config, messageUCase, pop3UCase, newMailClientAuth(authClient, "pop3", cfg.POP3Config.AllowAccountPassword, profileUCase), log)
+	}
+	// Письма из почтовых клиентов отправляются тем же путем, что и из веб-интерфейса
+	if cfg.SubmissionConfig.Port != "" {
+		go startSubmissionServer(cfg.SubmissionConfig, messageUCase, newMailClientAuth(authClient, "submission", cfg.SubmissionConfig.AllowAccountPassword, profileUCase), log)
+	}
+
+	// Письма на внешние домены уходят через очередь исходящей почты. Уведомления
+	// о недоставке messageUCase кладет во входящие отправителя
+	if cfg.OutboundConfig.Enabled {
+		messageUCase.EnableOutbound(cfg.SMTPConfig.Domain)
+		go startOutbound(cfg, connection, avatarRepository, loadDKIMKeys(cfg.DKIMConfig, log), messageUCase, log)
+	}
 
 	slog.Info("Messages microservice: server has started working...")
 
@@ -97,7 +213,20 @@ func MessagesInit() {
 		),
 	)
 
-	messagesService := messagesservice.New(messageUCase, avatarUCase, SECRET)
+	messagesService := messagesservice.New(messagesservice.Deps{
+		MessageUCase:    messageUCase,
+		AvatarUCase:     avatarUCase,
+		SieveUCase:      sieveUCase,
+		SpamUCase:       spamUCase,
+		LabelUCase:      labelUCase,
+		ForwardUCase:    forwardingUCase,
+		ContactUCase:    contactUCase,
+		SenderListUCase: senderListUCase,
+		AliasUCase:      aliasUCase,
+		NotifyUCase:     notificationUCase,
+		POP3UCase:       pop3UCase,
+		ChangesUCase:    changesUCase,
+	}, SECRET)
 	pb.RegisterMessagesServiceServer(grpcServer, messagesService)
 
 	lis, err := net.Listen("tcp", cfg.AppConfig.Host+":"+cfg.AppConfig.MessagesPort)
@@ -115,6 +244,207 @@ func MessagesInit() {
 
 }
 
+func startSMTPServer(cfg *config.SMTPConfig, dkimVerifier *dkim.Verifier, messageUCase *messageUcase.MessageUcase, log *slog.Logger) {
+	smtpConfig := smtpserver.Config{
+		Hostname:       cfg.Hostname,
+		Domain:         cfg.Domain,
+		MaxMessageSize: cfg.MaxMessageSize,
+		MaxRecipients:  cfg.MaxRecipients,
+		DKIM:           dkimVerifier,
+	}
+	if cfg.CheckSPF {
+		smtpConfig.SPF = spf.NewChecker(nil)
+	}
+	if cfg.CheckDMARC {
+		smtpConfig.DMARC = dmarc.NewChecker(nil)
+	}
+
+	if cfg.TLSCert != "" && cfg.TLSKey != "" {
+		certificate, err := tls.LoadX509KeyPair(cfg.TLSCert, cfg.TLSKey)
+		if err != nil {
+			log.Error("failed to load SMTP TLS certificate, STARTTLS is disabled: " + err.Error())
+		} else {
+			smtpConfig.TLSConfig = &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12}
+		}
+	}
+
+	addr := cfg.Host + ":" + cfg.Port
+	log.Info("Starting SMTP server on " + addr)
+	server := smtpserver.New(messageUCase, smtpConfig, log.With(slog.String("service", "smtp")))
+	if err := server.ListenAndServe(addr); err != nil {
+		log.Error("SMTP server failed: " + err.Error())
+	}
+}
+
+func startIMAPServer(cfg *config.IMAPConfig, messageUCase *messageUcase.MessageUcase, auth imapserver.Authenticator, eventHub *events.Hub, log *slog.Logger) {
+	imapConfig := imapserver.Config{
+		Hostname:          cfg.Hostname,
+		AllowInsecureAuth: cfg.AllowInsecureAuth,
+	}
+	if cfg.TLSCert != "" && cfg.TLSKey != "" {
+		certificate, err := tls.LoadX509KeyPair(cfg.TLSCert, cfg.TLSKey)
+		if err != nil {
+			log.Error("failed to load IMAP TLS certificate, STARTTLS is disabled: " + err.Error())
+		} else {
+			imapConfig.TLSConfig = &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12}
+		}
+	}
+	if imapConfig.TLSConfig == nil && !cfg.AllowInsecureAuth {
+		log.Error("IMAP has no TLS certificate and insecure auth is disabled, clients cannot log in")
+	}
+
+	log = log.With(slog.String("service", "imap"))
+	server := imapserver.New(messageUCase, auth, imapConfig, log)
+	server.SetEventSource(eventHub)
+
+	if cfg.TLSPort != "" && imapConfig.TLSConfig != nil {
+		go func() {
+			addr := cfg.Host + ":" + cfg.TLSPort
+			log.Info("Starting IMAPS server on " + addr)
+			if err := server.ListenAndServeTLS(addr); err != nil {
+				log.Error("IMAPS server failed: " + err.Error())
+			}
+		}()
+	}
+
+	addr := cfg.Host + ":" + cfg.Port
+	log.Info("Starting IMAP server on " + addr)
+	if err := server.ListenAndServe(addr); err != nil {
+		log.Error("IMAP server failed: " + err.Error())
+	}
+}
+
+func startPOP3Server(cfg *config.POP3Config, messageUCase *messageUcase.MessageUcase, pop3UCase *pop3Ucase.POP3Ucase, auth pop3server.Authenticator, log *slog.Logger) {
+	pop3Config := pop3server.Config{
+		Hostname:          cfg.Hostname,
+		AllowInsecureAuth: cfg.AllowInsecureAuth,
+	}
+	if cfg.TLSCert != "" && cfg.TLSKey != "" {
+		certificate, err := tls.LoadX509KeyPair(cfg.TLSCert, cfg.TLSKey)
+		if err != nil {
+			log.Error("failed to load POP3 TLS certificate, STLS is disabled: " + err.Error())
+		} else {
+			pop3Config.TLSConfig = &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12}
+		}
+	}
+	if pop3Config.TLSConfig == nil && !cfg.AllowInsecureAuth {
+		log.Error("POP3 has no TLS certificate and insecure auth is disabled, clients cannot log in")
+	}
+
+	log = log.With(slog.String("service", "pop3"))
+	server := pop3server.New(messageUCase, pop3UCase, auth, pop3Config, log)
+
+	if cfg.TLSPort != "" && pop3Config.TLSConfig != nil {
+		go func() {
+			addr := cfg.Host + ":" + cfg.TLSPort
+			log.Info("Starting POP3S server on " + addr)
+			if err := server.ListenAndServeTLS(addr); err != nil {
+				log.Error("POP3S server failed: " + err.Error())
+			}
+		}()
+	}
+
+	addr := cfg.Host + ":" + cfg.Port
+	log.Info("Starting POP3 server on " + addr)
+	if err := server.ListenAndServe(addr); err != nil {
+		log.Error("POP3 server failed: " + err.Error())
+	}
+}
+
+func startSubmissionServer(cfg *config.SubmissionConfig, messageUCase *messageUcase.MessageUcase, auth submissionserver.Authenticator, log *slog.Logger) {
+	submissionConfig := submissionserver.Config{
+		Hostname:          cfg.Hostname,
+		AllowInsecureAuth: cfg.AllowInsecureAuth,
+		MaxMessageSize:    cfg.MaxMessageSize,
+		MaxRecipients:     cfg.MaxRecipients,
+	}
+	if cfg.TLSCert != "" && cfg.TLSKey != "" {
+		certificate, err := tls.LoadX509KeyPair(cfg.TLSCert, cfg.TLSKey)
+		if err != nil {
+			log.Error("failed to load submission TLS certificate, STARTTLS is disabled: " + err.Error())
+		} else {
+			submissionConfig.TLSConfig = &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12}
+		}
+	}
+	if submissionConfig.TLSConfig == nil && !cfg.AllowInsecureAuth {
+		log.Error("submission has no TLS certificate and insecure auth is disabled, clients cannot log in")
+	}
+
+	log = log.With(slog.String("service", "submission"))
+	server := submissionserver.New(messageUCase, auth, submissionConfig, log)
+
+	if cfg.TLSPort != "" && submissionConfig.TLSConfig != nil {
+		go func() {
+			addr := cfg.Host + ":" + cfg.TLSPort
+			log.Info("Starting submission server with implicit TLS on " + addr)
+			if err := server.ListenAndServeTLS(addr); err != nil {
+				log.Error("submission TLS server failed: " + err.Error())
+			}
+		}()
+	}
+
+	addr := cfg.Host + ":" + cfg.Port
+	log.Info("Starting submission server on " + addr)
+	if err := server.ListenAndServe(addr); err != nil {
+		log.Error("submission server failed: " + err.Error())
+	}
+}
+
+func startOutbound(cfg config.Config, connection *sql.DB, storage outboundUcase.FileStorage, keyring *dkim.Keyring, messageUCase *messageUcase.MessageUcase, log *slog.Logger) {
+	client := smtpclient.New(smtpclient.Config{
+		Hostname:      cfg.SMTPConfig.Hostname,
+		Smarthost:     cfg.OutboundConfig.Smarthost,
+		Username:      cfg.OutboundConfig.Username,
+		Password:      cfg.OutboundConfig.Password,
+		TLSSkipVerify: cfg.OutboundConfig.TLSSkipVerify,
+	})
+
+	uc := outboundUcase.New(outboundrepository.New(connection), storage, client, messageUCase, outboundUcase.Config{
+		Domain:       cfg.SMTPConfig.Domain,
+		RetryBase:    cfg.OutboundConfig.RetryBase,
+		RetryMax:     cfg.OutboundConfig.RetryMax,
+		MaxAge:       cfg.OutboundConfig.MaxAge,
+		DelayWarning: cfg.OutboundConfig.DelayWarning,
+		PollInterval: cfg.OutboundConfig.PollInterval,
+	})
+
+	uc.SetSigner(keyring)
+
+	log.Info("Starting outbound mail queue")
+	uc.Run(context.Background())
+}
+
+// loadDKIMKeys читает ключи подписи. Ключ, который не удалось прочитать, пропускается:
+// письма домена без ключа уходят неподписанными
+func loadDKIMKeys(cfg *config.DKIMConfig, log *slog.Logger) *dkim.Keyring {
+	keys := make([]dkim.Key, 0, len(cfg.Keys))
+	for _, keyConfig := range cfg.Keys {
+		keyLog := log.With(slog.String("domain", keyConfig.Domain), slog.String("selector", keyConfig.Selector))
+
+		data, err := os.ReadFile(keyConfig.PrivateKey)
+		if err != nil {
+			keyLog.Error("failed to read DKIM key: " + err.Error())
+			continue
+		}
+		signer, err := dkim.ParsePrivateKey(data)
+		if err != nil {
+			keyLog.Error("failed to parse DKIM key: " + err.Error())
+			continue
+		}
+
+		key := dkim.Key{Domain: keyConfig.Domain, Selector: keyConfig.Selector, Signer: signer, ActiveFrom: keyConfig.ActiveFrom}
+		record, err := key.DNSRecord()
+		if err != nil {
+			keyLog.Error("unsupported DKIM key: " + err.Error())
+			continue
+		}
+		// Запись нужно опубликовать в DNS до того, как ключ начнет использоваться
+		keyLog.Info("DKIM key loaded", slog.String("dns_name", keyConfig.Selector+"._domainkey."+keyConfig.Domain), slog.String("dns_record", record))
+		keys = append(keys, key)
+	}
+	return dkim.NewKeyring(keys...)
+}
+
 func newMinioConnection(endpoint, accessKey, secretKey string, useSSL bool) (*minio.Client, error) {
 	minioClient, err := minio.New(endpoint, &minio.Options{
 		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
@@ -181,6 +511,20 @@ func monitorDBConnections(connection *sql.DB) {
 	}
 }
 
+// trashPurgeInterval - как часто очищаются корзина и спам с истекшим сроком хранения
+const trashPurgeInterval = time.Hour
+
+func purgeTrash(messageUCase *messageUcase.MessageUcase, log *slog.Logger) {
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := messageUCase.PurgeExpiredMessages(context.Background(), time.Now()); err != nil {
+			log.Error("failed to purge trash: " + err.Error())
+		}
+	}
+}
+
 func metricsInterceptor(serviceName string) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		start := time.Now()
@@ -0,0 +1,123 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	"2025_2_a4code/internal/lib/metrics"
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type AliasUsecase interface {
+	GetAliases(ctx context.Context, profileID int64) ([]domain.Alias, error)
+	AddAlias(ctx context.Context, profileID int64, username string) (domain.Alias, error)
+	DeleteAlias(ctx context.Context, profileID, aliasID int64) error
+}
+
+func (s *Server) GetAliases(ctx context.Context, req *pb.GetAliasesRequest) (*pb.GetAliasesResponse, error) {
+	const op = "messagesservice.GetAliases"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/get-aliases")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	aliases, err := s.aliasUCase.GetAliases(ctx, profileID)
+	if err != nil {
+		log.Error(op + ": failed to get aliases: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_aliases", "error").Inc()
+		return nil, status.Error(codes.Internal, "could not get aliases")
+	}
+
+	result := make([]*pb.Alias, 0, len(aliases))
+	for _, alias := range aliases {
+		result = append(result, domainAliasToProto(alias))
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_aliases", "ok").Inc()
+	return &pb.GetAliasesResponse{Aliases: result}, nil
+}
+
+func (s *Server) AddAlias(ctx context.Context, req *pb.AddAliasRequest) (*pb.AddAliasResponse, error) {
+	const op = "messagesservice.AddAlias"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/add-alias")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	alias, err := s.aliasUCase.AddAlias(ctx, profileID, req.Username)
+	if err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "add_alias", "error").Inc()
+		if st, ok := aliasStatus(err); ok {
+			return nil, st
+		}
+		log.Error(op + ": failed to add alias: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not add alias")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "add_alias", "ok").Inc()
+	return &pb.AddAliasResponse{Alias: domainAliasToProto(alias)}, nil
+}
+
+func (s *Server) DeleteAlias(ctx context.Context, req *pb.DeleteAliasRequest) (*pb.DeleteAliasResponse, error) {
+	const op = "messagesservice.DeleteAlias"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/delete-alias")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	aliasID, err := strconv.ParseInt(req.AliasId, 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid alias id")
+	}
+
+	if err := s.aliasUCase.DeleteAlias(ctx, profileID, aliasID); err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "delete_alias", "error").Inc()
+		if st, ok := aliasStatus(err); ok {
+			return nil, st
+		}
+		log.Error(op + ": failed to delete alias: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not delete alias")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "delete_alias", "ok").Inc()
+	return &pb.DeleteAliasResponse{}, nil
+}
+
+// aliasStatus переводит ожидаемые ошибки дополнительных адресов в gRPC-статус
+func aliasStatus(err error) (error, bool) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidAlias):
+		return status.Error(codes.InvalidArgument, err.Error()), true
+	case errors.Is(err, domain.ErrAliasNotFound):
+		return status.Error(codes.NotFound, "alias not found"), true
+	case errors.Is(err, domain.ErrAliasTaken):
+		return status.Error(codes.AlreadyExists, "alias is already taken"), true
+	case errors.Is(err, domain.ErrTooManyAliases):
+		return status.Error(codes.InvalidArgument, "too many aliases"), true
+	}
+	return nil, false
+}
+
+func domainAliasToProto(alias domain.Alias) *pb.Alias {
+	return &pb.Alias{
+		AliasId:   strconv.FormatInt(alias.ID, 10),
+		Address:   alias.Address,
+		CreatedAt: alias.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,154 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type MockAliasUsecase struct {
+	mock.Mock
+}
+
+func (m *MockAliasUsecase) GetAliases(ctx context.Context, profileID int64) ([]domain.Alias, error) {
+	args := m.Called(ctx, profileID)
+	return args.Get(0).([]domain.Alias), args.Error(1)
+}
+
+func (m *MockAliasUsecase) AddAlias(ctx context.Context, profileID int64, username string) (domain.Alias, error) {
+	args := m.Called(ctx, profileID, username)
+	return args.Get(0).(domain.Alias), args.Error(1)
+}
+
+func (m *MockAliasUsecase) DeleteAlias(ctx context.Context, profileID, aliasID int64) error {
+	args := m.Called(ctx, profileID, aliasID)
+	return args.Error(0)
+}
+
+func setupAliasTestServer() (*Server, *MockAliasUsecase) {
+	mockAliasUsecase := &MockAliasUsecase{}
+	jwtSecret := []byte("test-secret-key-very-long-for-testing")
+	server := New(Deps{AliasUCase: mockAliasUsecase}, jwtSecret)
+	return server, mockAliasUsecase
+}
+
+func TestServer_GetAliases(t *testing.T) {
+	server, mockAlias := setupAliasTestServer()
+	mockAlias.On("GetAliases", mock.Anything, int64(1)).
+		Return([]domain.Alias{{ID: 3, Address: "sales@flintmail.ru"}}, nil)
+
+	resp, err := server.GetAliases(createTestContextWithToken(1, server.JWTSecret), &pb.GetAliasesRequest{})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Aliases, 1)
+	assert.Equal(t, "3", resp.Aliases[0].AliasId)
+	assert.Equal(t, "sales@flintmail.ru", resp.Aliases[0].Address)
+	mockAlias.AssertExpectations(t)
+}
+
+func TestServer_AddAlias(t *testing.T) {
+	tests := []struct {
+		name         string
+		ctx          func(server *Server) context.Context
+		mockSetup    func(mockAlias *MockAliasUsecase)
+		expectedCode codes.Code
+	}{
+		{
+			name: "Success",
+			ctx:  func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			mockSetup: func(mockAlias *MockAliasUsecase) {
+				mockAlias.On("AddAlias", mock.Anything, int64(1), "sales").
+					Return(domain.Alias{ID: 3, Address: "sales@flintmail.ru"}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Unauthorized",
+			ctx:          func(server *Server) context.Context { return createTestContextWithoutAuth() },
+			mockSetup:    func(mockAlias *MockAliasUsecase) {},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name: "InvalidUsername",
+			ctx:  func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			mockSetup: func(mockAlias *MockAliasUsecase) {
+				mockAlias.On("AddAlias", mock.Anything, int64(1), "sales").
+					Return(domain.Alias{}, fmt.Errorf("%w: invalid username", domain.ErrInvalidAlias))
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Taken",
+			ctx:  func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			mockSetup: func(mockAlias *MockAliasUsecase) {
+				mockAlias.On("AddAlias", mock.Anything, int64(1), "sales").
+					Return(domain.Alias{}, domain.ErrAliasTaken)
+			},
+			expectedCode: codes.AlreadyExists,
+		},
+		{
+			name: "LimitReached",
+			ctx:  func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			mockSetup: func(mockAlias *MockAliasUsecase) {
+				mockAlias.On("AddAlias", mock.Anything, int64(1), "sales").
+					Return(domain.Alias{}, domain.ErrTooManyAliases)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "InternalError",
+			ctx:  func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			mockSetup: func(mockAlias *MockAliasUsecase) {
+				mockAlias.On("AddAlias", mock.Anything, int64(1), "sales").
+					Return(domain.Alias{}, errors.New("db down"))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, mockAlias := setupAliasTestServer()
+			tt.mockSetup(mockAlias)
+
+			resp, err := server.AddAlias(tt.ctx(server), &pb.AddAliasRequest{Username: "sales"})
+
+			if tt.expectedCode == codes.OK {
+				assert.NoError(t, err)
+				assert.Equal(t, "3", resp.Alias.AliasId)
+			} else {
+				assert.Equal(t, tt.expectedCode, status.Code(err))
+			}
+			mockAlias.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_DeleteAlias(t *testing.T) {
+	t.Run("NotFound", func(t *testing.T) {
+		server, mockAlias := setupAliasTestServer()
+		mockAlias.On("DeleteAlias", mock.Anything, int64(1), int64(3)).Return(domain.ErrAliasNotFound)
+
+		_, err := server.DeleteAlias(createTestContextWithToken(1, server.JWTSecret), &pb.DeleteAliasRequest{AliasId: "3"})
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		mockAlias.AssertExpectations(t)
+	})
+
+	t.Run("InvalidID", func(t *testing.T) {
+		server, _ := setupAliasTestServer()
+
+		_, err := server.DeleteAlias(createTestContextWithToken(1, server.JWTSecret), &pb.DeleteAliasRequest{AliasId: "abc"})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
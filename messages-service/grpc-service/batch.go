@@ -0,0 +1,118 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	"2025_2_a4code/internal/lib/metrics"
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) Batch(ctx context.Context, req *pb.BatchRequest) (*pb.BatchResponse, error) {
+	const op = "messagesservice.Batch"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/batch")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	batch, err := parseBatchRequest(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	result, err := s.messageUCase.ApplyBatch(ctx, profileID, batch)
+	if err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "batch", "error").Inc()
+		switch {
+		case errors.Is(err, domain.ErrBatchTooLarge):
+			return nil, status.Error(codes.InvalidArgument, "too many messages in batch")
+		case errors.Is(err, domain.ErrInvalidBatch):
+			return nil, status.Error(codes.InvalidArgument, "invalid batch")
+		case errors.Is(err, domain.ErrFolderNotFound):
+			return nil, status.Error(codes.NotFound, "folder not found")
+		}
+		log.Error(op + ": failed to apply batch: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not apply batch")
+	}
+
+	resp := &pb.BatchResponse{
+		Results: make([]*pb.BatchItemResult, 0, len(result.Items)),
+		HasMore: result.HasMore,
+	}
+	for _, item := range result.Items {
+		success := item.Error == ""
+		resp.Results = append(resp.Results, &pb.BatchItemResult{
+			MessageId: strconv.FormatInt(item.MessageID, 10),
+			Success:   success,
+			Error:     item.Error,
+		})
+
+		// Ошибка обучения не отменяет результат операции
+		if success && (batch.Action == domain.BatchMarkAsSpam || batch.Action == domain.BatchMarkAsNotSpam) {
+			spam := batch.Action == domain.BatchMarkAsSpam
+			if err := s.spamUCase.Train(ctx, profileID, item.MessageID, spam); err != nil {
+				log.Warn("failed to train spam filter: " + err.Error())
+			}
+		}
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "batch", "ok").Inc()
+	return resp, nil
+}
+
+func parseBatchRequest(req *pb.BatchRequest) (domain.BatchOperation, error) {
+	batch := domain.BatchOperation{
+		Action: domain.BatchAction(strings.TrimSpace(req.Action)),
+	}
+
+	batch.Selector.MessageIDs = make([]int64, 0, len(req.MessageIds))
+	for _, rawID := range req.MessageIds {
+		id, err := strconv.ParseInt(rawID, 10, 64)
+		if err != nil {
+			return domain.BatchOperation{}, errors.New("invalid message id")
+		}
+		batch.Selector.MessageIDs = append(batch.Selector.MessageIDs, id)
+	}
+
+	var err error
+	if req.FolderId != "" {
+		if batch.Selector.FolderID, err = strconv.ParseInt(req.FolderId, 10, 64); err != nil {
+			return domain.BatchOperation{}, errors.New("invalid folder id")
+		}
+	}
+	if req.TargetFolderId != "" {
+		if batch.TargetFolderID, err = strconv.ParseInt(req.TargetFolderId, 10, 64); err != nil {
+			return domain.BatchOperation{}, errors.New("invalid target folder id")
+		}
+	}
+
+	if filter := req.Filter; filter != nil {
+		batch.Selector.Filter.UnreadOnly = filter.UnreadOnly
+		batch.Selector.Filter.StarredOnly = filter.StarredOnly
+		batch.Selector.Filter.WithAttachments = filter.HasAttachments
+		batch.Selector.Filter.Sender = filter.Sender
+		if filter.After != "" {
+			if batch.Selector.Filter.After, err = time.Parse(time.RFC3339, filter.After); err != nil {
+				return domain.BatchOperation{}, errors.New("invalid after date")
+			}
+		}
+		if filter.Before != "" {
+			if batch.Selector.Filter.Before, err = time.Parse(time.RFC3339, filter.Before); err != nil {
+				return domain.BatchOperation{}, errors.New("invalid before date")
+			}
+		}
+	}
+
+	return batch, nil
+}
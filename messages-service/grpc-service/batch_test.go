@@ -0,0 +1,150 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"testing"
+	"time"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServer_Batch(t *testing.T) {
+	after := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		ctx          func(server *Server) context.Context
+		request      *pb.BatchRequest
+		mockSetup    func(mockMessage *MockMessageUsecase)
+		expectedCode codes.Code
+		expected     *pb.BatchResponse
+	}{
+		{
+			name: "SuccessWithPartialFailure",
+			ctx:  func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.BatchRequest{
+				Action:         "move",
+				MessageIds:     []string{"10", "20"},
+				TargetFolderId: "5",
+			},
+			mockSetup: func(mockMessage *MockMessageUsecase) {
+				batch := domain.BatchOperation{
+					Action:         domain.BatchMove,
+					Selector:       domain.BatchSelector{MessageIDs: []int64{10, 20}},
+					TargetFolderID: 5,
+				}
+				mockMessage.On("ApplyBatch", mock.Anything, int64(1), batch).Return(domain.BatchResult{
+					Items: []domain.BatchItemResult{{MessageID: 10}, {MessageID: 20, Error: "message not found"}},
+				}, nil)
+			},
+			expectedCode: codes.OK,
+			expected: &pb.BatchResponse{Results: []*pb.BatchItemResult{
+				{MessageId: "10", Success: true},
+				{MessageId: "20", Error: "message not found"},
+			}},
+		},
+		{
+			name: "FolderSelectorWithFilter",
+			ctx:  func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.BatchRequest{
+				Action:   "mark_as_read",
+				FolderId: "3",
+				Filter:   &pb.BatchFilter{UnreadOnly: true, StarredOnly: true, HasAttachments: true, After: after.Format(time.RFC3339)},
+			},
+			mockSetup: func(mockMessage *MockMessageUsecase) {
+				batch := domain.BatchOperation{
+					Action: domain.BatchMarkAsRead,
+					Selector: domain.BatchSelector{
+						MessageIDs: []int64{},
+						FolderID:   3,
+						Filter:     domain.MessageFilter{UnreadOnly: true, StarredOnly: true, WithAttachments: true, After: after},
+					},
+				}
+				mockMessage.On("ApplyBatch", mock.Anything, int64(1), batch).Return(domain.BatchResult{HasMore: true}, nil)
+			},
+			expectedCode: codes.OK,
+			expected:     &pb.BatchResponse{Results: []*pb.BatchItemResult{}, HasMore: true},
+		},
+		{
+			name:         "Unauthorized",
+			ctx:          func(server *Server) context.Context { return createTestContextWithoutAuth() },
+			request:      &pb.BatchRequest{Action: "mark_as_read", MessageIds: []string{"1"}},
+			mockSetup:    func(mockMessage *MockMessageUsecase) {},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "InvalidMessageID",
+			ctx:          func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request:      &pb.BatchRequest{Action: "mark_as_read", MessageIds: []string{"abc"}},
+			mockSetup:    func(mockMessage *MockMessageUsecase) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "TooLarge",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.BatchRequest{Action: "mark_as_read", MessageIds: []string{"1"}},
+			mockSetup: func(mockMessage *MockMessageUsecase) {
+				mockMessage.On("ApplyBatch", mock.Anything, int64(1), mock.Anything).Return(domain.BatchResult{}, domain.ErrBatchTooLarge)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "FolderNotFound",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.BatchRequest{Action: "move", MessageIds: []string{"1"}, TargetFolderId: "9"},
+			mockSetup: func(mockMessage *MockMessageUsecase) {
+				mockMessage.On("ApplyBatch", mock.Anything, int64(1), mock.Anything).Return(domain.BatchResult{}, domain.ErrFolderNotFound)
+			},
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, mockMessage, _ := setupTestServer()
+			tt.mockSetup(mockMessage)
+
+			resp, err := server.Batch(tt.ctx(server), tt.request)
+
+			if tt.expectedCode == codes.OK {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.HasMore, resp.HasMore)
+				assert.Equal(t, len(tt.expected.Results), len(resp.Results))
+				for i, result := range tt.expected.Results {
+					assert.Equal(t, result.MessageId, resp.Results[i].MessageId)
+					assert.Equal(t, result.Success, resp.Results[i].Success)
+					assert.Equal(t, result.Error, resp.Results[i].Error)
+				}
+			} else {
+				st, ok := status.FromError(err)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedCode, st.Code())
+			}
+			mockMessage.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_Batch_TrainsFilterForSucceededItems(t *testing.T) {
+	server, mockMessage, mockSpam := setupSpamTestServer()
+
+	mockMessage.On("ApplyBatch", mock.Anything, int64(1), mock.Anything).Return(domain.BatchResult{
+		Items: []domain.BatchItemResult{{MessageID: 10}, {MessageID: 20, Error: "message not found"}},
+	}, nil)
+	mockSpam.On("Train", mock.Anything, int64(1), int64(10), true).Return(nil).Once()
+
+	_, err := server.Batch(createTestContextWithToken(1, server.JWTSecret), &pb.BatchRequest{
+		Action:     "mark_as_spam",
+		MessageIds: []string{"10", "20"},
+	})
+
+	assert.NoError(t, err)
+	mockMessage.AssertExpectations(t)
+	mockSpam.AssertExpectations(t)
+}
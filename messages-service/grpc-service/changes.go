@@ -0,0 +1,128 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	"2025_2_a4code/internal/lib/metrics"
+	"context"
+	"errors"
+	"strconv"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type ChangesUsecase interface {
+	State(ctx context.Context, profileID int64) (domain.MailState, error)
+	Changes(ctx context.Context, profileID int64, objectType domain.MailObjectType, since int64, maxChanges int) (domain.MailChanges, error)
+	Threads(ctx context.Context, profileID int64, keys []string) (map[string][]int64, error)
+}
+
+// GetMailState возвращает текущие состояния данных почтового ящика для клиентов синхронизации
+func (s *Server) GetMailState(ctx context.Context, req *pb.GetMailStateRequest) (*pb.GetMailStateResponse, error) {
+	const op = "messagesservice.GetMailState"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/get-mail-state")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	state, err := s.changesUCase.State(ctx, profileID)
+	if err != nil {
+		log.Error(op + ": failed to get mail state: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_mail_state", "error").Inc()
+		return nil, status.Error(codes.Internal, "could not get mail state")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_mail_state", "ok").Inc()
+	return &pb.GetMailStateResponse{
+		MailboxState: strconv.FormatInt(state.Mailbox, 10),
+		EmailState:   strconv.FormatInt(state.Email, 10),
+		ThreadState:  strconv.FormatInt(state.Email, 10),
+	}, nil
+}
+
+// GetMailChanges возвращает папки, письма или цепочки, измененные после состояния клиента
+func (s *Server) GetMailChanges(ctx context.Context, req *pb.GetMailChangesRequest) (*pb.GetMailChangesResponse, error) {
+	const op = "messagesservice.GetMailChanges"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/get-mail-changes")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	objectType := domain.MailObjectType(req.Type)
+	if objectType != domain.MailObjectMailbox && objectType != domain.MailObjectEmail && objectType != domain.MailObjectThread {
+		return nil, status.Error(codes.InvalidArgument, "unknown object type")
+	}
+	since, err := strconv.ParseInt(req.SinceState, 10, 64)
+	if err != nil || since < 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid state")
+	}
+	if req.MaxChanges < 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid max changes")
+	}
+
+	changes, err := s.changesUCase.Changes(ctx, profileID, objectType, since, int(req.MaxChanges))
+	if err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_mail_changes", "error").Inc()
+		// Клиент должен заново загрузить данные целиком
+		if errors.Is(err, domain.ErrInvalidMailState) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		log.Error(op + ": failed to get mail changes: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not get mail changes")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_mail_changes", "ok").Inc()
+	return &pb.GetMailChangesResponse{
+		OldState:       strconv.FormatInt(changes.OldState, 10),
+		NewState:       strconv.FormatInt(changes.NewState, 10),
+		HasMoreChanges: changes.HasMoreChanges,
+		Created:        changes.Created,
+		Updated:        changes.Updated,
+		Destroyed:      changes.Destroyed,
+	}, nil
+}
+
+// GetThreads возвращает письма цепочек, которые видит пользователь
+func (s *Server) GetThreads(ctx context.Context, req *pb.GetThreadsRequest) (*pb.GetThreadsResponse, error) {
+	const op = "messagesservice.GetThreads"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/get-threads")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	threads, err := s.changesUCase.Threads(ctx, profileID, req.ThreadIds)
+	if err != nil {
+		log.Error(op + ": failed to get threads: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_threads", "error").Inc()
+		return nil, status.Error(codes.Internal, "could not get threads")
+	}
+
+	resp := &pb.GetThreadsResponse{}
+	for _, key := range req.ThreadIds {
+		messages, ok := threads[key]
+		if !ok {
+			resp.NotFound = append(resp.NotFound, key)
+			continue
+		}
+		thread := &pb.MailThread{ThreadId: key, MessageIds: make([]string, len(messages))}
+		for i, id := range messages {
+			thread.MessageIds[i] = strconv.FormatInt(id, 10)
+		}
+		resp.Threads = append(resp.Threads, thread)
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_threads", "ok").Inc()
+	return resp, nil
+}
@@ -0,0 +1,140 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"testing"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type MockChangesUsecase struct {
+	mock.Mock
+}
+
+func (m *MockChangesUsecase) State(ctx context.Context, profileID int64) (domain.MailState, error) {
+	args := m.Called(ctx, profileID)
+	return args.Get(0).(domain.MailState), args.Error(1)
+}
+
+func (m *MockChangesUsecase) Changes(ctx context.Context, profileID int64, objectType domain.MailObjectType, since int64, maxChanges int) (domain.MailChanges, error) {
+	args := m.Called(ctx, profileID, objectType, since, maxChanges)
+	return args.Get(0).(domain.MailChanges), args.Error(1)
+}
+
+func (m *MockChangesUsecase) Threads(ctx context.Context, profileID int64, keys []string) (map[string][]int64, error) {
+	args := m.Called(ctx, profileID, keys)
+	return args.Get(0).(map[string][]int64), args.Error(1)
+}
+
+func setupChangesTestServer() (*Server, *MockChangesUsecase) {
+	mockChangesUsecase := &MockChangesUsecase{}
+	jwtSecret := []byte("test-secret-key-very-long-for-testing")
+	server := New(Deps{
+		ChangesUCase: mockChangesUsecase,
+	}, jwtSecret)
+	return server, mockChangesUsecase
+}
+
+func TestServer_GetMailState(t *testing.T) {
+	server, mockChanges := setupChangesTestServer()
+	mockChanges.On("State", mock.Anything, int64(1)).Return(domain.MailState{Mailbox: 4, Email: 17}, nil)
+
+	resp, err := server.GetMailState(createTestContextWithToken(1, server.JWTSecret), &pb.GetMailStateRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "4", resp.MailboxState)
+	assert.Equal(t, "17", resp.EmailState)
+	assert.Equal(t, "17", resp.ThreadState)
+	mockChanges.AssertExpectations(t)
+}
+
+func TestServer_GetMailChanges(t *testing.T) {
+	tests := []struct {
+		name         string
+		request      *pb.GetMailChangesRequest
+		mockSetup    func(mockChanges *MockChangesUsecase)
+		expectedCode codes.Code
+	}{
+		{
+			name:    "Success",
+			request: &pb.GetMailChangesRequest{Type: "Email", SinceState: "10", MaxChanges: 50},
+			mockSetup: func(mockChanges *MockChangesUsecase) {
+				mockChanges.On("Changes", mock.Anything, int64(1), domain.MailObjectEmail, int64(10), 50).
+					Return(domain.MailChanges{OldState: 10, NewState: 15, Created: []string{"3"}, Destroyed: []string{"2"}}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "UnknownType",
+			request:      &pb.GetMailChangesRequest{Type: "Identity", SinceState: "10"},
+			mockSetup:    func(mockChanges *MockChangesUsecase) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "InvalidState",
+			request:      &pb.GetMailChangesRequest{Type: "Email", SinceState: "abc"},
+			mockSetup:    func(mockChanges *MockChangesUsecase) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "StateFromFuture",
+			request: &pb.GetMailChangesRequest{Type: "Mailbox", SinceState: "99"},
+			mockSetup: func(mockChanges *MockChangesUsecase) {
+				mockChanges.On("Changes", mock.Anything, int64(1), domain.MailObjectMailbox, int64(99), 0).
+					Return(domain.MailChanges{}, domain.ErrInvalidMailState)
+			},
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name:    "UsecaseError",
+			request: &pb.GetMailChangesRequest{Type: "Thread", SinceState: "0"},
+			mockSetup: func(mockChanges *MockChangesUsecase) {
+				mockChanges.On("Changes", mock.Anything, int64(1), domain.MailObjectThread, int64(0), 0).
+					Return(domain.MailChanges{}, errors.New("db down"))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, mockChanges := setupChangesTestServer()
+			tt.mockSetup(mockChanges)
+
+			resp, err := server.GetMailChanges(createTestContextWithToken(1, server.JWTSecret), tt.request)
+
+			if tt.expectedCode != codes.OK {
+				assert.Equal(t, tt.expectedCode, status.Code(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "10", resp.OldState)
+			assert.Equal(t, "15", resp.NewState)
+			assert.Equal(t, []string{"3"}, resp.Created)
+			assert.Equal(t, []string{"2"}, resp.Destroyed)
+			mockChanges.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_GetThreads(t *testing.T) {
+	server, mockChanges := setupChangesTestServer()
+	mockChanges.On("Threads", mock.Anything, int64(1), []string{"7", "m9", "8"}).
+		Return(map[string][]int64{"7": {3, 4}, "m9": {9}}, nil)
+
+	resp, err := server.GetThreads(createTestContextWithToken(1, server.JWTSecret), &pb.GetThreadsRequest{ThreadIds: []string{"7", "m9", "8"}})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Threads, 2)
+	assert.Equal(t, "7", resp.Threads[0].ThreadId)
+	assert.Equal(t, []string{"3", "4"}, resp.Threads[0].MessageIds)
+	assert.Equal(t, []string{"9"}, resp.Threads[1].MessageIds)
+	assert.Equal(t, []string{"8"}, resp.NotFound)
+}
@@ -0,0 +1,368 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	"2025_2_a4code/internal/lib/metrics"
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type ContactUsecase interface {
+	CreateContact(ctx context.Context, profileID int64, contact domain.Contact) (domain.Contact, error)
+	GetContacts(ctx context.Context, profileID, groupID int64) ([]domain.Contact, error)
+	UpdateContact(ctx context.Context, profileID int64, contact domain.Contact) (domain.Contact, error)
+	DeleteContact(ctx context.Context, profileID, contactID int64) error
+	SuggestContacts(ctx context.Context, profileID int64, prefix string, limit int) ([]domain.ContactSuggestion, error)
+	CreateGroup(ctx context.Context, profileID int64, name string) (domain.ContactGroup, error)
+	GetGroups(ctx context.Context, profileID int64) ([]domain.ContactGroup, error)
+	RenameGroup(ctx context.Context, profileID, groupID int64, name string) (domain.ContactGroup, error)
+	DeleteGroup(ctx context.Context, profileID, groupID int64) error
+}
+
+func (s *Server) CreateContact(ctx context.Context, req *pb.CreateContactRequest) (*pb.CreateContactResponse, error) {
+	const op = "messagesservice.CreateContact"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/create-contact")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	contact, err := protoContactToDomain(req.Contact)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	created, err := s.contactUCase.CreateContact(ctx, profileID, contact)
+	if err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "create_contact", "error").Inc()
+		if st, ok := contactStatus(err); ok {
+			return nil, st
+		}
+		log.Error(op + ": failed to create contact: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not create contact")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "create_contact", "ok").Inc()
+	return &pb.CreateContactResponse{Contact: domainContactToProto(created)}, nil
+}
+
+func (s *Server) GetContacts(ctx context.Context, req *pb.GetContactsRequest) (*pb.GetContactsResponse, error) {
+	const op = "messagesservice.GetContacts"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/get-contacts")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	var groupID int64
+	if req.GroupId != "" {
+		groupID, err = strconv.ParseInt(req.GroupId, 10, 64)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid group id")
+		}
+	}
+
+	contacts, err := s.contactUCase.GetContacts(ctx, profileID, groupID)
+	if err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_contacts", "error").Inc()
+		if st, ok := contactStatus(err); ok {
+			return nil, st
+		}
+		log.Error(op + ": failed to get contacts: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not get contacts")
+	}
+
+	result := make([]*pb.Contact, 0, len(contacts))
+	for _, contact := range contacts {
+		result = append(result, domainContactToProto(contact))
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_contacts", "ok").Inc()
+	return &pb.GetContactsResponse{Contacts: result}, nil
+}
+
+func (s *Server) UpdateContact(ctx context.Context, req *pb.UpdateContactRequest) (*pb.UpdateContactResponse, error) {
+	const op = "messagesservice.UpdateContact"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/update-contact")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	contact, err := protoContactToDomain(req.Contact)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if contact.ID == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid contact id")
+	}
+
+	updated, err := s.contactUCase.UpdateContact(ctx, profileID, contact)
+	if err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "update_contact", "error").Inc()
+		if st, ok := contactStatus(err); ok {
+			return nil, st
+		}
+		log.Error(op + ": failed to update contact: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not update contact")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "update_contact", "ok").Inc()
+	return &pb.UpdateContactResponse{Contact: domainContactToProto(updated)}, nil
+}
+
+func (s *Server) DeleteContact(ctx context.Context, req *pb.DeleteContactRequest) (*pb.DeleteContactResponse, error) {
+	const op = "messagesservice.DeleteContact"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/delete-contact")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	contactID, err := strconv.ParseInt(req.ContactId, 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid contact id")
+	}
+
+	if err := s.contactUCase.DeleteContact(ctx, profileID, contactID); err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "delete_contact", "error").Inc()
+		if st, ok := contactStatus(err); ok {
+			return nil, st
+		}
+		log.Error(op + ": failed to delete contact: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not delete contact")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "delete_contact", "ok").Inc()
+	return &pb.DeleteContactResponse{}, nil
+}
+
+func (s *Server) SuggestContacts(ctx context.Context, req *pb.SuggestContactsRequest) (*pb.SuggestContactsResponse, error) {
+	const op = "messagesservice.SuggestContacts"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/suggest-contacts")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	suggestions, err := s.contactUCase.SuggestContacts(ctx, profileID, req.Prefix, int(req.Limit))
+	if err != nil {
+		log.Error(op + ": failed to suggest contacts: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "suggest_contacts", "error").Inc()
+		return nil, status.Error(codes.Internal, "could not suggest contacts")
+	}
+
+	result := make([]*pb.ContactSuggestion, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		result = append(result, &pb.ContactSuggestion{
+			ContactId: strconv.FormatInt(suggestion.ContactID, 10),
+			Name:      suggestion.Name,
+			Email:     suggestion.Email,
+		})
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "suggest_contacts", "ok").Inc()
+	return &pb.SuggestContactsResponse{Suggestions: result}, nil
+}
+
+func (s *Server) CreateContactGroup(ctx context.Context, req *pb.CreateContactGroupRequest) (*pb.CreateContactGroupResponse, error) {
+	const op = "messagesservice.CreateContactGroup"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/create-contact-group")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	group, err := s.contactUCase.CreateGroup(ctx, profileID, req.Name)
+	if err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "create_contact_group", "error").Inc()
+		if st, ok := contactStatus(err); ok {
+			return nil, st
+		}
+		log.Error(op + ": failed to create contact group: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not create contact group")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "create_contact_group", "ok").Inc()
+	return &pb.CreateContactGroupResponse{Group: domainContactGroupToProto(group)}, nil
+}
+
+func (s *Server) GetContactGroups(ctx context.Context, req *pb.GetContactGroupsRequest) (*pb.GetContactGroupsResponse, error) {
+	const op = "messagesservice.GetContactGroups"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/get-contact-groups")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	groups, err := s.contactUCase.GetGroups(ctx, profileID)
+	if err != nil {
+		log.Error(op + ": failed to get contact groups: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_contact_groups", "error").Inc()
+		return nil, status.Error(codes.Internal, "could not get contact groups")
+	}
+
+	result := make([]*pb.ContactGroup, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, domainContactGroupToProto(group))
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_contact_groups", "ok").Inc()
+	return &pb.GetContactGroupsResponse{Groups: result}, nil
+}
+
+func (s *Server) RenameContactGroup(ctx context.Context, req *pb.RenameContactGroupRequest) (*pb.RenameContactGroupResponse, error) {
+	const op = "messagesservice.RenameContactGroup"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/rename-contact-group")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	groupID, err := strconv.ParseInt(req.GroupId, 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid group id")
+	}
+
+	group, err := s.contactUCase.RenameGroup(ctx, profileID, groupID, req.Name)
+	if err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "rename_contact_group", "error").Inc()
+		if st, ok := contactStatus(err); ok {
+			return nil, st
+		}
+		log.Error(op + ": failed to rename contact group: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not rename contact group")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "rename_contact_group", "ok").Inc()
+	return &pb.RenameContactGroupResponse{Group: domainContactGroupToProto(group)}, nil
+}
+
+func (s *Server) DeleteContactGroup(ctx context.Context, req *pb.DeleteContactGroupRequest) (*pb.DeleteContactGroupResponse, error) {
+	const op = "messagesservice.DeleteContactGroup"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/delete-contact-group")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	groupID, err := strconv.ParseInt(req.GroupId, 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid group id")
+	}
+
+	if err := s.contactUCase.DeleteGroup(ctx, profileID, groupID); err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "delete_contact_group", "error").Inc()
+		if st, ok := contactStatus(err); ok {
+			return nil, st
+		}
+		log.Error(op + ": failed to delete contact group: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not delete contact group")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "delete_contact_group", "ok").Inc()
+	return &pb.DeleteContactGroupResponse{}, nil
+}
+
+// contactStatus переводит ожидаемые ошибки адресной книги в gRPC-статус
+func contactStatus(err error) (error, bool) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidContact):
+		return status.Error(codes.InvalidArgument, err.Error()), true
+	case errors.Is(err, domain.ErrContactNotFound):
+		return status.Error(codes.NotFound, "contact not found"), true
+	case errors.Is(err, domain.ErrContactGroupNotFound):
+		return status.Error(codes.NotFound, "contact group not found"), true
+	case errors.Is(err, domain.ErrContactExists):
+		return status.Error(codes.AlreadyExists, "contact with this email already exists"), true
+	case errors.Is(err, domain.ErrContactGroupExists):
+		return status.Error(codes.AlreadyExists, "contact group already exists"), true
+	}
+	return nil, false
+}
+
+func protoContactToDomain(contact *pb.Contact) (domain.Contact, error) {
+	if contact == nil {
+		return domain.Contact{}, errors.New("contact is required")
+	}
+
+	var contactID int64
+	if contact.ContactId != "" {
+		id, err := strconv.ParseInt(contact.ContactId, 10, 64)
+		if err != nil {
+			return domain.Contact{}, errors.New("invalid contact id")
+		}
+		contactID = id
+	}
+
+	groupIDs := make([]int64, 0, len(contact.GroupIds))
+	for _, rawID := range contact.GroupIds {
+		id, err := strconv.ParseInt(rawID, 10, 64)
+		if err != nil {
+			return domain.Contact{}, errors.New("invalid group id")
+		}
+		groupIDs = append(groupIDs, id)
+	}
+
+	return domain.Contact{
+		ID:         contactID,
+		Name:       contact.Name,
+		Emails:     contact.Emails,
+		Phone:      contact.Phone,
+		Notes:      contact.Notes,
+		AvatarPath: contact.Avatar,
+		GroupIDs:   groupIDs,
+	}, nil
+}
+
+func domainContactToProto(contact domain.Contact) *pb.Contact {
+	groupIDs := make([]string, 0, len(contact.GroupIDs))
+	for _, id := range contact.GroupIDs {
+		groupIDs = append(groupIDs, strconv.FormatInt(id, 10))
+	}
+
+	return &pb.Contact{
+		ContactId: strconv.FormatInt(contact.ID, 10),
+		Name:      contact.Name,
+		Emails:    contact.Emails,
+		Phone:     contact.Phone,
+		Notes:     contact.Notes,
+		Avatar:    contact.AvatarPath,
+		GroupIds:  groupIDs,
+		CreatedAt: contact.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func domainContactGroupToProto(group domain.ContactGroup) *pb.ContactGroup {
+	return &pb.ContactGroup{
+		GroupId:      strconv.FormatInt(group.ID, 10),
+		Name:         group.Name,
+		ContactCount: int64(group.ContactCount),
+	}
+}
@@ -0,0 +1,294 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type MockContactUsecase struct {
+	mock.Mock
+}
+
+func (m *MockContactUsecase) CreateContact(ctx context.Context, profileID int64, contact domain.Contact) (domain.Contact, error) {
+	args := m.Called(ctx, profileID, contact)
+	return args.Get(0).(domain.Contact), args.Error(1)
+}
+
+func (m *MockContactUsecase) GetContacts(ctx context.Context, profileID, groupID int64) ([]domain.Contact, error) {
+	args := m.Called(ctx, profileID, groupID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Contact), args.Error(1)
+}
+
+func (m *MockContactUsecase) UpdateContact(ctx context.Context, profileID int64, contact domain.Contact) (domain.Contact, error) {
+	args := m.Called(ctx, profileID, contact)
+	return args.Get(0).(domain.Contact), args.Error(1)
+}
+
+func (m *MockContactUsecase) DeleteContact(ctx context.Context, profileID, contactID int64) error {
+	args := m.Called(ctx, profileID, contactID)
+	return args.Error(0)
+}
+
+func (m *MockContactUsecase) SuggestContacts(ctx context.Context, profileID int64, prefix string, limit int) ([]domain.ContactSuggestion, error) {
+	args := m.Called(ctx, profileID, prefix, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ContactSuggestion), args.Error(1)
+}
+
+func (m *MockContactUsecase) CreateGroup(ctx context.Context, profileID int64, name string) (domain.ContactGroup, error) {
+	args := m.Called(ctx, profileID, name)
+	return args.Get(0).(domain.ContactGroup), args.Error(1)
+}
+
+func (m *MockContactUsecase) GetGroups(ctx context.Context, profileID int64) ([]domain.ContactGroup, error) {
+	args := m.Called(ctx, profileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ContactGroup), args.Error(1)
+}
+
+func (m *MockContactUsecase) RenameGroup(ctx context.Context, profileID, groupID int64, name string) (domain.ContactGroup, error) {
+	args := m.Called(ctx, profileID, groupID, name)
+	return args.Get(0).(domain.ContactGroup), args.Error(1)
+}
+
+func (m *MockContactUsecase) DeleteGroup(ctx context.Context, profileID, groupID int64) error {
+	args := m.Called(ctx, profileID, groupID)
+	return args.Error(0)
+}
+
+func setupContactTestServer() (*Server, *MockContactUsecase) {
+	mockContactUsecase := &MockContactUsecase{}
+	jwtSecret := []byte("test-secret-key-very-long-for-testing")
+	server := New(Deps{
+		ContactUCase: mockContactUsecase,
+	}, jwtSecret)
+	return server, mockContactUsecase
+}
+
+func TestServer_CreateContact(t *testing.T) {
+	contact := domain.Contact{Name: "Bob", Emails: []string{"bob@a4mail.ru"}, GroupIDs: []int64{3}}
+
+	tests := []struct {
+		name         string
+		ctx          func(server *Server) context.Context
+		request      *pb.CreateContactRequest
+		mockSetup    func(mockContact *MockContactUsecase)
+		expectedCode codes.Code
+	}{
+		{
+			name:    "Success",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.CreateContactRequest{Contact: &pb.Contact{Name: "Bob", Emails: []string{"bob@a4mail.ru"}, GroupIds: []string{"3"}}},
+			mockSetup: func(mockContact *MockContactUsecase) {
+				created := contact
+				created.ID = 10
+				created.CreatedAt = time.Now()
+				mockContact.On("CreateContact", mock.Anything, int64(1), contact).Return(created, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Unauthorized",
+			ctx:          func(server *Server) context.Context { return createTestContextWithoutAuth() },
+			request:      &pb.CreateContactRequest{Contact: &pb.Contact{Name: "Bob"}},
+			mockSetup:    func(mockContact *MockContactUsecase) {},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "MissingContact",
+			ctx:          func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request:      &pb.CreateContactRequest{},
+			mockSetup:    func(mockContact *MockContactUsecase) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "InvalidGroupID",
+			ctx:          func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request:      &pb.CreateContactRequest{Contact: &pb.Contact{Name: "Bob", GroupIds: []string{"abc"}}},
+			mockSetup:    func(mockContact *MockContactUsecase) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "Duplicate",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.CreateContactRequest{Contact: &pb.Contact{Name: "Bob", Emails: []string{"bob@a4mail.ru"}}},
+			mockSetup: func(mockContact *MockContactUsecase) {
+				mockContact.On("CreateContact", mock.Anything, int64(1), mock.Anything).Return(domain.Contact{}, domain.ErrContactExists)
+			},
+			expectedCode: codes.AlreadyExists,
+		},
+		{
+			name:    "InvalidContact",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.CreateContactRequest{Contact: &pb.Contact{}},
+			mockSetup: func(mockContact *MockContactUsecase) {
+				mockContact.On("CreateContact", mock.Anything, int64(1), mock.Anything).
+					Return(domain.Contact{}, fmt.Errorf("%w: name or email is required", domain.ErrInvalidContact))
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "InternalError",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.CreateContactRequest{Contact: &pb.Contact{Name: "Bob"}},
+			mockSetup: func(mockContact *MockContactUsecase) {
+				mockContact.On("CreateContact", mock.Anything, int64(1), mock.Anything).Return(domain.Contact{}, errors.New("db down"))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, mockContact := setupContactTestServer()
+			tt.mockSetup(mockContact)
+
+			resp, err := server.CreateContact(tt.ctx(server), tt.request)
+
+			if tt.expectedCode == codes.OK {
+				assert.NoError(t, err)
+				assert.Equal(t, "10", resp.Contact.ContactId)
+				assert.Equal(t, []string{"3"}, resp.Contact.GroupIds)
+			} else {
+				st, ok := status.FromError(err)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedCode, st.Code())
+			}
+			mockContact.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_GetContacts(t *testing.T) {
+	t.Run("FilterByGroup", func(t *testing.T) {
+		server, mockContact := setupContactTestServer()
+		mockContact.On("GetContacts", mock.Anything, int64(1), int64(3)).
+			Return([]domain.Contact{{ID: 10, Name: "Bob", Emails: []string{"bob@a4mail.ru"}}}, nil)
+
+		resp, err := server.GetContacts(createTestContextWithToken(1, server.JWTSecret), &pb.GetContactsRequest{GroupId: "3"})
+
+		assert.NoError(t, err)
+		assert.Len(t, resp.Contacts, 1)
+		assert.Equal(t, "Bob", resp.Contacts[0].Name)
+		mockContact.AssertExpectations(t)
+	})
+
+	t.Run("InvalidGroupID", func(t *testing.T) {
+		server, _ := setupContactTestServer()
+
+		_, err := server.GetContacts(createTestContextWithToken(1, server.JWTSecret), &pb.GetContactsRequest{GroupId: "x"})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("GroupNotFound", func(t *testing.T) {
+		server, mockContact := setupContactTestServer()
+		mockContact.On("GetContacts", mock.Anything, int64(1), int64(3)).Return(nil, domain.ErrContactGroupNotFound)
+
+		_, err := server.GetContacts(createTestContextWithToken(1, server.JWTSecret), &pb.GetContactsRequest{GroupId: "3"})
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestServer_UpdateContact(t *testing.T) {
+	t.Run("MissingID", func(t *testing.T) {
+		server, _ := setupContactTestServer()
+
+		_, err := server.UpdateContact(createTestContextWithToken(1, server.JWTSecret), &pb.UpdateContactRequest{Contact: &pb.Contact{Name: "Bob"}})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		server, mockContact := setupContactTestServer()
+		mockContact.On("UpdateContact", mock.Anything, int64(1), domain.Contact{ID: 10, Name: "Bob", GroupIDs: []int64{}}).
+			Return(domain.Contact{}, domain.ErrContactNotFound)
+
+		_, err := server.UpdateContact(createTestContextWithToken(1, server.JWTSecret), &pb.UpdateContactRequest{Contact: &pb.Contact{ContactId: "10", Name: "Bob"}})
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		mockContact.AssertExpectations(t)
+	})
+}
+
+func TestServer_SuggestContacts(t *testing.T) {
+	server, mockContact := setupContactTestServer()
+	mockContact.On("SuggestContacts", mock.Anything, int64(1), "bo", 5).
+		Return([]domain.ContactSuggestion{{ContactID: 10, Name: "Bob", Email: "bob@a4mail.ru"}}, nil)
+
+	resp, err := server.SuggestContacts(createTestContextWithToken(1, server.JWTSecret), &pb.SuggestContactsRequest{Prefix: "bo", Limit: 5})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*pb.ContactSuggestion{{ContactId: "10", Name: "Bob", Email: "bob@a4mail.ru"}}, resp.Suggestions)
+	mockContact.AssertExpectations(t)
+}
+
+func TestServer_ContactGroups(t *testing.T) {
+	t.Run("Create", func(t *testing.T) {
+		server, mockContact := setupContactTestServer()
+		mockContact.On("CreateGroup", mock.Anything, int64(1), "Family").
+			Return(domain.ContactGroup{ID: 3, Name: "Family"}, nil)
+
+		resp, err := server.CreateContactGroup(createTestContextWithToken(1, server.JWTSecret), &pb.CreateContactGroupRequest{Name: "Family"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "3", resp.Group.GroupId)
+	})
+
+	t.Run("CreateDuplicate", func(t *testing.T) {
+		server, mockContact := setupContactTestServer()
+		mockContact.On("CreateGroup", mock.Anything, int64(1), "Family").
+			Return(domain.ContactGroup{}, domain.ErrContactGroupExists)
+
+		_, err := server.CreateContactGroup(createTestContextWithToken(1, server.JWTSecret), &pb.CreateContactGroupRequest{Name: "Family"})
+
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+
+	t.Run("List", func(t *testing.T) {
+		server, mockContact := setupContactTestServer()
+		mockContact.On("GetGroups", mock.Anything, int64(1)).
+			Return([]domain.ContactGroup{{ID: 3, Name: "Family", ContactCount: 2}}, nil)
+
+		resp, err := server.GetContactGroups(createTestContextWithToken(1, server.JWTSecret), &pb.GetContactGroupsRequest{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), resp.Groups[0].ContactCount)
+	})
+
+	t.Run("RenameInvalidID", func(t *testing.T) {
+		server, _ := setupContactTestServer()
+
+		_, err := server.RenameContactGroup(createTestContextWithToken(1, server.JWTSecret), &pb.RenameContactGroupRequest{GroupId: "x", Name: "Work"})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("DeleteNotFound", func(t *testing.T) {
+		server, mockContact := setupContactTestServer()
+		mockContact.On("DeleteGroup", mock.Anything, int64(1), int64(3)).Return(domain.ErrContactGroupNotFound)
+
+		_, err := server.DeleteContactGroup(createTestContextWithToken(1, server.JWTSecret), &pb.DeleteContactGroupRequest{GroupId: "3"})
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
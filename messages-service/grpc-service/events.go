@@ -0,0 +1,101 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	"2025_2_a4code/internal/lib/metrics"
+	"context"
+	"strconv"
+	"time"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type NotificationUsecase interface {
+	Subscribe(ctx context.Context, profileID, lastEventID int64) (<-chan domain.MailEvent, error)
+}
+
+// Subscribe отправляет события почтового ящика пользователя, пока клиент не отключится
+func (s *Server) Subscribe(req *pb.SubscribeRequest, stream grpc.ServerStreamingServer[pb.MailEvent]) error {
+	const op = "messagesservice.Subscribe"
+	ctx := stream.Context()
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/events")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	var lastEventID int64
+	if req.LastEventId != "" {
+		lastEventID, err = strconv.ParseInt(req.LastEventId, 10, 64)
+		if err != nil || lastEventID < 0 {
+			return status.Error(codes.InvalidArgument, "invalid last event id")
+		}
+	}
+
+	events, err := s.notifyUCase.Subscribe(ctx, profileID, lastEventID)
+	if err != nil {
+		log.Error(op + ": failed to subscribe: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "subscribe", "error").Inc()
+		return status.Error(codes.Internal, "could not subscribe to events")
+	}
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "subscribe", "ok").Inc()
+
+	// Заголовки отправляются сразу, чтобы клиент узнал об успешной подписке до первого события
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	for event := range events {
+		if err := stream.Send(domainMailEventToProto(event)); err != nil {
+			return err
+		}
+	}
+
+	// Поток событий закрывается и тогда, когда клиент не успевает их читать: такой клиент
+	// должен переподключиться с последним полученным идентификатором
+	if ctx.Err() == nil {
+		return status.Error(codes.Unavailable, "event stream closed")
+	}
+	return nil
+}
+
+func domainMailEventToProto(event domain.MailEvent) *pb.MailEvent {
+	result := &pb.MailEvent{
+		EventId:    strconv.FormatInt(event.ID, 10),
+		Type:       string(event.Type),
+		FolderName: event.FolderName,
+		From:       event.From,
+		Topic:      event.Topic,
+		Read:       formatFlag(event.Flags.Read),
+		Starred:    formatFlag(event.Flags.Starred),
+		Important:  formatFlag(event.Flags.Important),
+		Answered:   formatFlag(event.Flags.Answered),
+		Forwarded:  formatFlag(event.Flags.Forwarded),
+		CreatedAt:  event.CreatedAt.Format(time.RFC3339),
+	}
+	if event.MessageID != 0 {
+		result.MessageId = strconv.FormatInt(event.MessageID, 10)
+	}
+	if event.FolderID != 0 {
+		result.FolderId = strconv.FormatInt(event.FolderID, 10)
+	}
+	if event.Type == domain.EventUnreadCount {
+		result.Unread = strconv.Itoa(event.Unread)
+	}
+	return result
+}
+
+func formatFlag(value *bool) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatBool(*value)
+}
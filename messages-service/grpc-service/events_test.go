@@ -0,0 +1,119 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type MockNotificationUsecase struct {
+	mock.Mock
+}
+
+func (m *MockNotificationUsecase) Subscribe(ctx context.Context, profileID, lastEventID int64) (<-chan domain.MailEvent, error) {
+	args := m.Called(ctx, profileID, lastEventID)
+	events, _ := args.Get(0).(chan domain.MailEvent)
+	return events, args.Error(1)
+}
+
+type fakeEventStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*pb.MailEvent
+}
+
+func (s *fakeEventStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeEventStream) SendHeader(metadata.MD) error {
+	return nil
+}
+
+func (s *fakeEventStream) Send(event *pb.MailEvent) error {
+	s.sent = append(s.sent, event)
+	return nil
+}
+
+func setupEventsTestServer() (*Server, *MockNotificationUsecase) {
+	mockNotification := &MockNotificationUsecase{}
+	jwtSecret := []byte("test-secret-key-very-long-for-testing")
+	server := New(Deps{
+		NotifyUCase: mockNotification,
+	}, jwtSecret)
+	return server, mockNotification
+}
+
+func TestServer_Subscribe(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		server, mockNotification := setupEventsTestServer()
+		ctx, cancel := context.WithCancel(createTestContextWithToken(1, server.JWTSecret))
+		read := false
+		events := make(chan domain.MailEvent, 2)
+		events <- domain.MailEvent{ID: 11, Type: domain.EventFlagsChanged, MessageID: 5, Flags: domain.FlagsUpdate{Read: &read}, CreatedAt: time.Now()}
+		events <- domain.MailEvent{ID: 12, Type: domain.EventUnreadCount, FolderID: 1, Unread: 0, CreatedAt: time.Now()}
+		close(events)
+		cancel()
+		mockNotification.On("Subscribe", mock.Anything, int64(1), int64(10)).Return(events, nil)
+
+		stream := &fakeEventStream{ctx: ctx}
+		err := server.Subscribe(&pb.SubscribeRequest{LastEventId: "10"}, stream)
+
+		assert.NoError(t, err)
+		assert.Len(t, stream.sent, 2)
+		assert.Equal(t, "11", stream.sent[0].EventId)
+		assert.Equal(t, "5", stream.sent[0].MessageId)
+		assert.Equal(t, "false", stream.sent[0].Read)
+		assert.Empty(t, stream.sent[0].Starred)
+		assert.Equal(t, "unread_count", stream.sent[1].Type)
+		assert.Equal(t, "0", stream.sent[1].Unread)
+		mockNotification.AssertExpectations(t)
+	})
+
+	t.Run("SlowClientDisconnected", func(t *testing.T) {
+		server, mockNotification := setupEventsTestServer()
+		events := make(chan domain.MailEvent)
+		close(events)
+		mockNotification.On("Subscribe", mock.Anything, int64(1), int64(0)).Return(events, nil)
+
+		err := server.Subscribe(&pb.SubscribeRequest{}, &fakeEventStream{ctx: createTestContextWithToken(1, server.JWTSecret)})
+
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		server, _ := setupEventsTestServer()
+
+		err := server.Subscribe(&pb.SubscribeRequest{}, &fakeEventStream{ctx: createTestContextWithoutAuth()})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("InvalidLastEventID", func(t *testing.T) {
+		server, _ := setupEventsTestServer()
+
+		err := server.Subscribe(&pb.SubscribeRequest{LastEventId: "abc"}, &fakeEventStream{ctx: createTestContextWithToken(1, server.JWTSecret)})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("InternalError", func(t *testing.T) {
+		server, mockNotification := setupEventsTestServer()
+		mockNotification.On("Subscribe", mock.Anything, int64(1), int64(0)).Return(nil, errors.New("db down"))
+
+		err := server.Subscribe(&pb.SubscribeRequest{}, &fakeEventStream{ctx: createTestContextWithToken(1, server.JWTSecret)})
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
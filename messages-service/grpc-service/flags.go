@@ -0,0 +1,68 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	"2025_2_a4code/internal/lib/metrics"
+	"context"
+	"errors"
+	"strconv"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) SetFlags(ctx context.Context, req *pb.SetFlagsRequest) (*pb.SetFlagsResponse, error) {
+	const op = "messagesservice.SetFlags"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/set-flags")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	messageID, err := strconv.ParseInt(req.MessageId, 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid message id")
+	}
+
+	var update domain.FlagsUpdate
+	for _, flag := range []struct {
+		raw    string
+		target **bool
+	}{
+		{req.Read, &update.Read},
+		{req.Starred, &update.Starred},
+		{req.Important, &update.Important},
+		{req.Answered, &update.Answered},
+		{req.Forwarded, &update.Forwarded},
+	} {
+		if flag.raw == "" {
+			continue
+		}
+		value, err := strconv.ParseBool(flag.raw)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid flag value")
+		}
+		*flag.target = &value
+	}
+
+	err = s.messageUCase.SetFlags(ctx, profileID, messageID, update)
+	if err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "set_flags", "error").Inc()
+		switch {
+		case errors.Is(err, domain.ErrNoFlags):
+			return nil, status.Error(codes.InvalidArgument, "no flags to update")
+		case errors.Is(err, domain.ErrMessageNotFound):
+			return nil, status.Error(codes.NotFound, "message not found")
+		}
+		log.Error(op + ": failed to set flags: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not set flags")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "set_flags", "ok").Inc()
+	return &pb.SetFlagsResponse{}, nil
+}
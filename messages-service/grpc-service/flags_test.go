@@ -0,0 +1,128 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"testing"
+	"time"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServer_SetFlags(t *testing.T) {
+	unread, starred := false, true
+
+	tests := []struct {
+		name         string
+		ctx          func(server *Server) context.Context
+		request      *pb.SetFlagsRequest
+		mockSetup    func(mockMessage *MockMessageUsecase)
+		expectedCode codes.Code
+	}{
+		{
+			name:    "MarkUnreadAndStar",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.SetFlagsRequest{MessageId: "123", Read: "false", Starred: "true"},
+			mockSetup: func(mockMessage *MockMessageUsecase) {
+				update := domain.FlagsUpdate{Read: &unread, Starred: &starred}
+				mockMessage.On("SetFlags", mock.Anything, int64(1), int64(123), update).Return(nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Unauthorized",
+			ctx:          func(server *Server) context.Context { return createTestContextWithoutAuth() },
+			request:      &pb.SetFlagsRequest{MessageId: "123", Starred: "true"},
+			mockSetup:    func(mockMessage *MockMessageUsecase) {},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "InvalidFlagValue",
+			ctx:          func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request:      &pb.SetFlagsRequest{MessageId: "123", Starred: "yes please"},
+			mockSetup:    func(mockMessage *MockMessageUsecase) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "NoFlags",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.SetFlagsRequest{MessageId: "123"},
+			mockSetup: func(mockMessage *MockMessageUsecase) {
+				mockMessage.On("SetFlags", mock.Anything, int64(1), int64(123), domain.FlagsUpdate{}).Return(domain.ErrNoFlags)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "NotFound",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.SetFlagsRequest{MessageId: "123", Starred: "true"},
+			mockSetup: func(mockMessage *MockMessageUsecase) {
+				mockMessage.On("SetFlags", mock.Anything, int64(1), int64(123), mock.Anything).Return(domain.ErrMessageNotFound)
+			},
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, mockMessage, _ := setupTestServer()
+			tt.mockSetup(mockMessage)
+
+			_, err := server.SetFlags(tt.ctx(server), tt.request)
+
+			if tt.expectedCode == codes.OK {
+				assert.NoError(t, err)
+			} else {
+				st, ok := status.FromError(err)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedCode, st.Code())
+			}
+			mockMessage.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_GetFolder_Starred(t *testing.T) {
+	server, mockMessage, _ := setupTestServer()
+	dispatched := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	messages := []domain.Message{{
+		ID:           "101",
+		Topic:        "Topic",
+		Datetime:     dispatched,
+		MessageFlags: domain.MessageFlags{IsStarred: true, IsAnswered: true},
+	}}
+	mockMessage.On("GetStarredMessagesWithKeysetPagination", mock.Anything, int64(1), domain.MessagePage{Sort: domain.SortDateDesc, Limit: 20}).Return(messages, nil)
+	mockMessage.On("GetStarredMessagesInfo", mock.Anything, int64(1)).Return(domain.Messages{MessageTotal: 1}, nil)
+
+	resp, err := server.GetFolder(createTestContextWithToken(1, server.JWTSecret), &pb.GetFolderRequest{FolderId: domain.StarredFolderID})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Messages, 1)
+	assert.Equal(t, "true", resp.Messages[0].IsStarred)
+	assert.Equal(t, "true", resp.Messages[0].IsAnswered)
+	assert.Equal(t, "false", resp.Messages[0].IsForwarded)
+	assert.Equal(t, "1", resp.MessageTotal)
+	mockMessage.AssertExpectations(t)
+}
+
+func TestServer_GetFolders_IncludesStarred(t *testing.T) {
+	server, mockMessage, _ := setupTestServer()
+	mockMessage.On("GetUserFolders", mock.Anything, int64(1)).
+		Return([]domain.Folder{{ID: 1, Name: "Inbox", Type: domain.FolderInbox, MessageTotal: 12, MessageUnread: 3}}, nil)
+
+	resp, err := server.GetFolders(createTestContextWithToken(1, server.JWTSecret), &pb.GetFoldersRequest{})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Folders, 2)
+	assert.Equal(t, "12", resp.Folders[0].MessageTotal)
+	assert.Equal(t, "3", resp.Folders[0].MessageUnread)
+	assert.Empty(t, resp.Folders[1].MessageTotal)
+	assert.Equal(t, domain.StarredFolderID, resp.Folders[1].FolderId)
+	assert.Equal(t, string(domain.FolderStarred), resp.Folders[1].FolderType)
+}
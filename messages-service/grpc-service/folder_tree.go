@@ -0,0 +1,127 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	"2025_2_a4code/internal/lib/metrics"
+	"context"
+	"errors"
+	"strconv"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) MoveFolder(ctx context.Context, req *pb.MoveFolderRequest) (*pb.MoveFolderResponse, error) {
+	const op = "messagesservice.MoveFolder"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/move-folder")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	folderID, err := strconv.ParseInt(req.FolderId, 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid folder id")
+	}
+
+	parentID, err := parseParentID(req.ParentId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid parent id")
+	}
+
+	folder, err := s.messageUCase.MoveFolder(ctx, profileID, folderID, parentID)
+	if err != nil {
+		if st, ok := folderTreeStatus(err); ok {
+			return nil, st
+		}
+		if errors.Is(err, domain.ErrFolderSystem) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		log.Error(op + ": failed to move folder: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "move_folder", "error").Inc()
+		return nil, status.Error(codes.Internal, "could not move folder")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "move_folder", "ok").Inc()
+	return &pb.MoveFolderResponse{Folder: domainFolderToProto(*folder)}, nil
+}
+
+func (s *Server) GetFolderTree(ctx context.Context, req *pb.GetFolderTreeRequest) (*pb.GetFolderTreeResponse, error) {
+	const op = "messagesservice.GetFolderTree"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/get-folder-tree")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	tree, err := s.messageUCase.GetFolderTree(ctx, profileID)
+	if err != nil {
+		log.Error(op + ": failed to get folder tree: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_folder_tree", "error").Inc()
+		return nil, status.Error(codes.Internal, "could not get folder tree")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_folder_tree", "ok").Inc()
+	return &pb.GetFolderTreeResponse{Folders: domainFolderNodesToProto(tree)}, nil
+}
+
+// folderTreeStatus переводит ошибки создания и переноса папок в gRPC-статус
+func folderTreeStatus(err error) (error, bool) {
+	switch {
+	case errors.Is(err, domain.ErrFolderExists):
+		return status.Error(codes.AlreadyExists, err.Error()), true
+	case errors.Is(err, domain.ErrFolderNotFound):
+		return status.Error(codes.NotFound, err.Error()), true
+	case errors.Is(err, domain.ErrInvalidFolderParent):
+		return status.Error(codes.InvalidArgument, err.Error()), true
+	}
+	return nil, false
+}
+
+// parseParentID разбирает идентификатор родительской папки, пустая строка означает верхний уровень
+func parseParentID(parentID string) (int64, error) {
+	if parentID == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(parentID, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errors.New("invalid parent id")
+	}
+	return id, nil
+}
+
+func formatParentID(parentID int64) string {
+	if parentID == 0 {
+		return ""
+	}
+	return strconv.FormatInt(parentID, 10)
+}
+
+func domainFolderToProto(folder domain.Folder) *pb.Folder {
+	return &pb.Folder{
+		FolderId:      strconv.FormatInt(folder.ID, 10),
+		FolderName:    folder.Name,
+		FolderType:    string(folder.Type),
+		ParentId:      formatParentID(folder.ParentID),
+		MessageTotal:  strconv.Itoa(folder.MessageTotal),
+		MessageUnread: strconv.Itoa(folder.MessageUnread),
+	}
+}
+
+func domainFolderNodesToProto(nodes []domain.FolderNode) []*pb.FolderNode {
+	result := make([]*pb.FolderNode, 0, len(nodes))
+	for _, node := range nodes {
+		result = append(result, &pb.FolderNode{
+			Folder:   domainFolderToProto(node.Folder),
+			Children: domainFolderNodesToProto(node.Children),
+		})
+	}
+	return result
+}
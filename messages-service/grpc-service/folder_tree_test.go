@@ -0,0 +1,192 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"testing"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServer_CreateFolder_Subfolder(t *testing.T) {
+	tests := []struct {
+		name         string
+		request      *pb.CreateFolderRequest
+		mockSetup    func(mockMessage *MockMessageUsecase)
+		expectedCode codes.Code
+	}{
+		{
+			name:    "Success",
+			request: &pb.CreateFolderRequest{FolderName: "Invoices", ParentId: "6"},
+			mockSetup: func(mockMessage *MockMessageUsecase) {
+				mockMessage.On("CreateSubfolder", mock.Anything, int64(1), int64(6), "Invoices").
+					Return(&domain.Folder{ID: 7, Name: "Invoices", Type: domain.FolderCustom, ParentID: 6}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "SlashInName",
+			request:      &pb.CreateFolderRequest{FolderName: "Acme/Invoices", ParentId: "6"},
+			mockSetup:    func(mockMessage *MockMessageUsecase) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "InvalidParentID",
+			request:      &pb.CreateFolderRequest{FolderName: "Invoices", ParentId: "abc"},
+			mockSetup:    func(mockMessage *MockMessageUsecase) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "ParentNotFound",
+			request: &pb.CreateFolderRequest{FolderName: "Invoices", ParentId: "6"},
+			mockSetup: func(mockMessage *MockMessageUsecase) {
+				mockMessage.On("CreateSubfolder", mock.Anything, int64(1), int64(6), "Invoices").
+					Return(nil, domain.ErrFolderNotFound)
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name:    "TooDeep",
+			request: &pb.CreateFolderRequest{FolderName: "Invoices", ParentId: "6"},
+			mockSetup: func(mockMessage *MockMessageUsecase) {
+				mockMessage.On("CreateSubfolder", mock.Anything, int64(1), int64(6), "Invoices").
+					Return(nil, domain.ErrInvalidFolderParent)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, mockMessage, _ := setupTestServer()
+			tt.mockSetup(mockMessage)
+
+			resp, err := server.CreateFolder(createTestContextWithToken(1, server.JWTSecret), tt.request)
+
+			if tt.expectedCode == codes.OK {
+				assert.NoError(t, err)
+				assert.Equal(t, "6", resp.ParentId)
+			} else {
+				st, ok := status.FromError(err)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedCode, st.Code())
+			}
+			mockMessage.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_MoveFolder(t *testing.T) {
+	tests := []struct {
+		name         string
+		ctx          func(server *Server) context.Context
+		request      *pb.MoveFolderRequest
+		mockSetup    func(mockMessage *MockMessageUsecase)
+		expectedCode codes.Code
+	}{
+		{
+			name:    "Success",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.MoveFolderRequest{FolderId: "6", ParentId: "5"},
+			mockSetup: func(mockMessage *MockMessageUsecase) {
+				mockMessage.On("MoveFolder", mock.Anything, int64(1), int64(6), int64(5)).
+					Return(&domain.Folder{ID: 6, Name: "Acme", Type: domain.FolderCustom, ParentID: 5}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:    "ToTopLevel",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.MoveFolderRequest{FolderId: "6"},
+			mockSetup: func(mockMessage *MockMessageUsecase) {
+				mockMessage.On("MoveFolder", mock.Anything, int64(1), int64(6), int64(0)).
+					Return(&domain.Folder{ID: 6, Name: "Acme", Type: domain.FolderCustom}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Unauthorized",
+			ctx:          func(server *Server) context.Context { return createTestContextWithoutAuth() },
+			request:      &pb.MoveFolderRequest{FolderId: "6", ParentId: "5"},
+			mockSetup:    func(mockMessage *MockMessageUsecase) {},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:    "Cycle",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.MoveFolderRequest{FolderId: "5", ParentId: "7"},
+			mockSetup: func(mockMessage *MockMessageUsecase) {
+				mockMessage.On("MoveFolder", mock.Anything, int64(1), int64(5), int64(7)).
+					Return(nil, domain.ErrInvalidFolderParent)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "SystemFolder",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.MoveFolderRequest{FolderId: "1", ParentId: "5"},
+			mockSetup: func(mockMessage *MockMessageUsecase) {
+				mockMessage.On("MoveFolder", mock.Anything, int64(1), int64(1), int64(5)).
+					Return(nil, domain.ErrFolderSystem)
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:    "NameTaken",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.MoveFolderRequest{FolderId: "6", ParentId: "5"},
+			mockSetup: func(mockMessage *MockMessageUsecase) {
+				mockMessage.On("MoveFolder", mock.Anything, int64(1), int64(6), int64(5)).
+					Return(nil, domain.ErrFolderExists)
+			},
+			expectedCode: codes.AlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, mockMessage, _ := setupTestServer()
+			tt.mockSetup(mockMessage)
+
+			_, err := server.MoveFolder(tt.ctx(server), tt.request)
+
+			if tt.expectedCode == codes.OK {
+				assert.NoError(t, err)
+			} else {
+				st, ok := status.FromError(err)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedCode, st.Code())
+			}
+			mockMessage.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_GetFolderTree(t *testing.T) {
+	server, mockMessage, _ := setupTestServer()
+	tree := []domain.FolderNode{
+		{Folder: domain.Folder{ID: 1, Name: "Inbox", Type: domain.FolderInbox}},
+		{
+			Folder: domain.Folder{ID: 5, Name: "Clients", Type: domain.FolderCustom},
+			Children: []domain.FolderNode{
+				{Folder: domain.Folder{ID: 6, Name: "Acme", Type: domain.FolderCustom, ParentID: 5}},
+			},
+		},
+	}
+	mockMessage.On("GetFolderTree", mock.Anything, int64(1)).Return(tree, nil)
+
+	resp, err := server.GetFolderTree(createTestContextWithToken(1, server.JWTSecret), &pb.GetFolderTreeRequest{})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Folders, 2)
+	assert.Equal(t, "", resp.Folders[0].Folder.ParentId)
+	assert.Len(t, resp.Folders[1].Children, 1)
+	assert.Equal(t, "Acme", resp.Folders[1].Children[0].Folder.FolderName)
+	assert.Equal(t, "5", resp.Folders[1].Children[0].Folder.ParentId)
+	mockMessage.AssertExpectations(t)
+}
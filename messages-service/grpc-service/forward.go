@@ -0,0 +1,107 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	"2025_2_a4code/internal/lib/metrics"
+	"2025_2_a4code/internal/lib/validation"
+	"context"
+	"fmt"
+	"html"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) Forward(ctx context.Context, req *pb.ForwardRequest) (*pb.ForwardResponse, error) {
+	const op = "messagesservice.Forward"
+
+	timer := prometheus.NewTimer(metrics.MessagesOperationsDuration.WithLabelValues("messages", "forward"))
+	defer timer.ObserveDuration()
+
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/forward")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "forward", "error").Inc()
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	messageID, err := strconv.ParseInt(req.MessageId, 10, 64)
+	if err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "forward", "error").Inc()
+		return nil, status.Error(codes.InvalidArgument, "invalid message id")
+	}
+
+	receivers, err := validateForwardRequest(req)
+	if err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "forward", "error").Inc()
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ok, err := s.messageUCase.IsUsersMessage(ctx, messageID, profileID)
+	if err != nil {
+		log.Error(op + ": failed to check if it is users message: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "forward", "error").Inc()
+		return nil, status.Error(codes.Internal, "could not forward message")
+	}
+	if !ok {
+		log.Debug(op + ": unpermitted access to message")
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "forward", "error").Inc()
+		return nil, status.Error(codes.PermissionDenied, "access denied")
+	}
+
+	forwardedID, err := s.messageUCase.ForwardMessage(ctx, profileID, messageID, receivers, html.EscapeString(req.Text))
+	if err != nil {
+		log.Error(op + ": failed to forward message: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "forward", "error").Inc()
+		return nil, status.Error(codes.Internal, "could not forward message")
+	}
+
+	metrics.MessagesSentTotal.WithLabelValues("forward").Add(float64(len(receivers)))
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "forward", "ok").Inc()
+
+	return &pb.ForwardResponse{
+		MessageId: strconv.FormatInt(forwardedID, 10),
+	}, nil
+}
+
+// validateForwardRequest проверяет комментарий и получателей и возвращает их адреса
+func validateForwardRequest(req *pb.ForwardRequest) ([]string, error) {
+	if len(req.Receivers) == 0 {
+		return nil, fmt.Errorf("empty request body")
+	}
+	if len(req.Text) > maxTextLen {
+		return nil, fmt.Errorf("text too long")
+	}
+
+	receivers := make([]string, 0, len(req.Receivers))
+	seen := make(map[string]struct{})
+	for _, r := range req.Receivers {
+		email := strings.TrimSpace(r.Email)
+		if email == "" {
+			return nil, fmt.Errorf("empty receiver email")
+		}
+		if _, err := mail.ParseAddress(email); err != nil {
+			return nil, fmt.Errorf("invalid receiver email: %s", email)
+		}
+		lower := strings.ToLower(email)
+		if _, ok := seen[lower]; ok {
+			return nil, fmt.Errorf("duplicate receiver: %s", email)
+		}
+		seen[lower] = struct{}{}
+
+		if validation.HasDangerousCharacters(email) {
+			return nil, fmt.Errorf("receiver email contains forbidden characters: %s", email)
+		}
+		receivers = append(receivers, email)
+	}
+
+	return receivers, nil
+}
@@ -0,0 +1,98 @@
+package messages_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServer_Forward(t *testing.T) {
+	receivers := []*pb.Receiver{{Email: "a@a4code.ru"}, {Email: " b@a4code.ru "}}
+
+	tests := []struct {
+		name         string
+		ctx          func(server *Server) context.Context
+		request      *pb.ForwardRequest
+		mockSetup    func(mockMessage *MockMessageUsecase)
+		expectedCode codes.Code
+	}{
+		{
+			name:    "Success",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.ForwardRequest{MessageId: "10", Receivers: receivers, Text: "see <below>"},
+			mockSetup: func(mockMessage *MockMessageUsecase) {
+				mockMessage.On("IsUsersMessage", mock.Anything, int64(10), int64(1)).Return(true, nil)
+				mockMessage.On("ForwardMessage", mock.Anything, int64(1), int64(10),
+					[]string{"a@a4code.ru", "b@a4code.ru"}, "see &lt;below&gt;").Return(int64(42), nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Unauthorized",
+			ctx:          func(server *Server) context.Context { return createTestContextWithoutAuth() },
+			request:      &pb.ForwardRequest{MessageId: "10", Receivers: receivers},
+			mockSetup:    func(mockMessage *MockMessageUsecase) {},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "NoReceivers",
+			ctx:          func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request:      &pb.ForwardRequest{MessageId: "10"},
+			mockSetup:    func(mockMessage *MockMessageUsecase) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "DuplicateReceiver",
+			ctx:          func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request:      &pb.ForwardRequest{MessageId: "10", Receivers: []*pb.Receiver{{Email: "a@a4code.ru"}, {Email: "A@a4code.ru"}}},
+			mockSetup:    func(mockMessage *MockMessageUsecase) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "ForeignMessage",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.ForwardRequest{MessageId: "10", Receivers: receivers},
+			mockSetup: func(mockMessage *MockMessageUsecase) {
+				mockMessage.On("IsUsersMessage", mock.Anything, int64(10), int64(1)).Return(false, nil)
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:    "ForwardFailed",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.ForwardRequest{MessageId: "10", Receivers: receivers},
+			mockSetup: func(mockMessage *MockMessageUsecase) {
+				mockMessage.On("IsUsersMessage", mock.Anything, int64(10), int64(1)).Return(true, nil)
+				mockMessage.On("ForwardMessage", mock.Anything, int64(1), int64(10), mock.Anything, "").
+					Return(int64(0), errors.New("db down"))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, mockMessage, _ := setupTestServer()
+			tt.mockSetup(mockMessage)
+
+			resp, err := server.Forward(tt.ctx(server), tt.request)
+
+			if tt.expectedCode == codes.OK {
+				assert.NoError(t, err)
+				assert.Equal(t, "42", resp.MessageId)
+			} else {
+				st, ok := status.FromError(err)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedCode, st.Code())
+			}
+			mockMessage.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	"2025_2_a4code/internal/lib/metrics"
+	"context"
+	"errors"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type ForwardingUsecase interface {
+	GetRule(ctx context.Context, profileID int64) (domain.ForwardingRule, error)
+	PutRule(ctx context.Context, profileID int64, rule domain.ForwardingRule) (domain.ForwardingRule, error)
+}
+
+func (s *Server) GetForwarding(ctx context.Context, req *pb.GetForwardingRequest) (*pb.GetForwardingResponse, error) {
+	const op = "messagesservice.GetForwarding"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/get-forwarding")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	rule, err := s.forwardUCase.GetRule(ctx, profileID)
+	if err != nil {
+		log.Error(op + ": failed to get forwarding rule: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_forwarding", "error").Inc()
+		return nil, status.Error(codes.Internal, "could not get forwarding rule")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_forwarding", "ok").Inc()
+	return &pb.GetForwardingResponse{Rule: domainForwardingToProto(rule)}, nil
+}
+
+func (s *Server) PutForwarding(ctx context.Context, req *pb.PutForwardingRequest) (*pb.PutForwardingResponse, error) {
+	const op = "messagesservice.PutForwarding"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/put-forwarding")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	if req.Rule == nil {
+		return nil, status.Error(codes.InvalidArgument, "rule is required")
+	}
+
+	rule, err := s.forwardUCase.PutRule(ctx, profileID, domain.ForwardingRule{
+		Enabled:         req.Rule.Enabled,
+		Addresses:       req.Rule.Addresses,
+		KeepCopy:        req.Rule.KeepCopy,
+		FromContains:    req.Rule.FromContains,
+		SubjectContains: req.Rule.SubjectContains,
+	})
+	if err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "put_forwarding", "error").Inc()
+		if errors.Is(err, domain.ErrInvalidForwarding) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		log.Error(op + ": failed to save forwarding rule: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not save forwarding rule")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "put_forwarding", "ok").Inc()
+	return &pb.PutForwardingResponse{Rule: domainForwardingToProto(rule)}, nil
+}
+
+func domainForwardingToProto(rule domain.ForwardingRule) *pb.ForwardingRule {
+	return &pb.ForwardingRule{
+		Enabled:         rule.Enabled,
+		Addresses:       rule.Addresses,
+		KeepCopy:        rule.KeepCopy,
+		FromContains:    rule.FromContains,
+		SubjectContains: rule.SubjectContains,
+	}
+}
@@ -0,0 +1,129 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type MockForwardingUsecase struct {
+	mock.Mock
+}
+
+func (m *MockForwardingUsecase) GetRule(ctx context.Context, profileID int64) (domain.ForwardingRule, error) {
+	args := m.Called(ctx, profileID)
+	return args.Get(0).(domain.ForwardingRule), args.Error(1)
+}
+
+func (m *MockForwardingUsecase) PutRule(ctx context.Context, profileID int64, rule domain.ForwardingRule) (domain.ForwardingRule, error) {
+	args := m.Called(ctx, profileID, rule)
+	return args.Get(0).(domain.ForwardingRule), args.Error(1)
+}
+
+func setupForwardingTestServer() (*Server, *MockForwardingUsecase) {
+	mockForwardingUsecase := &MockForwardingUsecase{}
+	jwtSecret := []byte("test-secret-key-very-long-for-testing")
+	server := New(Deps{
+		ForwardUCase: mockForwardingUsecase,
+	}, jwtSecret)
+	return server, mockForwardingUsecase
+}
+
+func TestServer_GetForwarding(t *testing.T) {
+	server, mockForwarding := setupForwardingTestServer()
+	mockForwarding.On("GetRule", mock.Anything, int64(1)).
+		Return(domain.ForwardingRule{Enabled: true, Addresses: []string{"deputy@a4mail.ru"}, KeepCopy: true}, nil)
+
+	resp, err := server.GetForwarding(createTestContextWithToken(1, server.JWTSecret), &pb.GetForwardingRequest{})
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Rule.Enabled)
+	assert.True(t, resp.Rule.KeepCopy)
+	assert.Equal(t, []string{"deputy@a4mail.ru"}, resp.Rule.Addresses)
+	mockForwarding.AssertExpectations(t)
+}
+
+func TestServer_PutForwarding(t *testing.T) {
+	rule := domain.ForwardingRule{Enabled: true, Addresses: []string{"deputy@a4mail.ru"}, SubjectContains: "invoice"}
+
+	tests := []struct {
+		name         string
+		ctx          func(server *Server) context.Context
+		request      *pb.PutForwardingRequest
+		mockSetup    func(mockForwarding *MockForwardingUsecase)
+		expectedCode codes.Code
+	}{
+		{
+			name: "Success",
+			ctx:  func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.PutForwardingRequest{Rule: &pb.ForwardingRule{
+				Enabled: true, Addresses: []string{"deputy@a4mail.ru"}, SubjectContains: "invoice",
+			}},
+			mockSetup: func(mockForwarding *MockForwardingUsecase) {
+				mockForwarding.On("PutRule", mock.Anything, int64(1), rule).Return(rule, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Unauthorized",
+			ctx:          func(server *Server) context.Context { return createTestContextWithoutAuth() },
+			request:      &pb.PutForwardingRequest{Rule: &pb.ForwardingRule{}},
+			mockSetup:    func(mockForwarding *MockForwardingUsecase) {},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "MissingRule",
+			ctx:          func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request:      &pb.PutForwardingRequest{},
+			mockSetup:    func(mockForwarding *MockForwardingUsecase) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "InvalidRule",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.PutForwardingRequest{Rule: &pb.ForwardingRule{Enabled: true}},
+			mockSetup: func(mockForwarding *MockForwardingUsecase) {
+				mockForwarding.On("PutRule", mock.Anything, int64(1), mock.Anything).
+					Return(domain.ForwardingRule{}, fmt.Errorf("%w: no addresses", domain.ErrInvalidForwarding))
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "InternalError",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.PutForwardingRequest{Rule: &pb.ForwardingRule{}},
+			mockSetup: func(mockForwarding *MockForwardingUsecase) {
+				mockForwarding.On("PutRule", mock.Anything, int64(1), mock.Anything).
+					Return(domain.ForwardingRule{}, errors.New("db down"))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, mockForwarding := setupForwardingTestServer()
+			tt.mockSetup(mockForwarding)
+
+			_, err := server.PutForwarding(tt.ctx(server), tt.request)
+
+			if tt.expectedCode == codes.OK {
+				assert.NoError(t, err)
+			} else {
+				st, ok := status.FromError(err)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedCode, st.Code())
+			}
+			mockForwarding.AssertExpectations(t)
+		})
+	}
+}
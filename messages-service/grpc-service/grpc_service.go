@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"2025_2_a4code/internal/http-server/middleware/logger"
+	"2025_2_a4code/internal/lib/cursor"
 	"2025_2_a4code/internal/lib/session"
 	"2025_2_a4code/internal/lib/validation"
 
@@ -29,9 +30,20 @@ import (
 
 type Server struct {
 	pb.UnimplementedMessagesServiceServer
-	messageUCase MessageUsecase
-	avatarUCase  AvatarUsecase
-	JWTSecret    []byte
+	messageUCase    MessageUsecase
+	avatarUCase     AvatarUsecase
+	sieveUCase      SieveUsecase
+	spamUCase       SpamUsecase
+	labelUCase      LabelUsecase
+	forwardUCase    ForwardingUsecase
+	contactUCase    ContactUsecase
+	senderListUCase SenderListUsecase
+	aliasUCase      AliasUsecase
+	notifyUCase     NotificationUsecase
+	pop3UCase       POP3Usecase
+	changesUCase    ChangesUsecase
+	cursors         *cursor.Codec
+	JWTSecret       []byte
 }
 
 type MessageUsecase interface {
@@ -49,6 +61,7 @@ type MessageUsecase interface {
 	// методы для работы с сообщениями
 	MarkMessageAsRead(ctx context.Context, messageID int64, profileID int64) error
 	MarkMessageAsSpam(ctx context.Context, messageID int64, profileID int64) error
+	MarkMessageAsNotSpam(ctx context.Context, messageID int64, profileID int64) error
 	IsUsersMessage(ctx context.Context, messageID int64, profileID int64) (bool, error)
 
 	// методы для черновиков
@@ -67,18 +80,40 @@ type MessageUsecase interface {
 	RenameFolder(ctx context.Context, profileID, folderID int64, newName string) (*domain.Folder, error)
 	DeleteFolder(ctx context.Context, profileID, folderID int64) error
 	DeleteMessageFromFolder(ctx context.Context, profileID, messageID, folderID int64) error
-	GetFolderMessagesWithKeysetPagination(ctx context.Context, profileID, folderID, lastMessageID int64, lastDatetime time.Time, limit int) ([]domain.Message, error)
-	GetFolderMessagesInfo(ctx context.Context, profileID, folderID int64) (domain.Messages, error)
+	GetFolderMessagesWithKeysetPagination(ctx context.Context, profileID, folderID, labelID int64, page domain.MessagePage) ([]domain.Message, error)
+	GetFolderMessagesInfo(ctx context.Context, profileID, folderID, labelID int64) (domain.Messages, error)
 
 	// методы для отправки сообщений с автоматическим распределением по папкам
 	SendMessage(ctx context.Context, receiverEmail string, senderProfileID int64, topic, text string) (int64, error)
+	ForwardMessage(ctx context.Context, profileID, sourceID int64, receivers []string, comment string) (int64, error)
 	ReplyToMessage(ctx context.Context, receiverEmail string, senderProfileID int64, threadRoot int64, topic, text string) (int64, error)
+
+	// методы для корзины
+	RestoreFromTrash(ctx context.Context, profileID, messageID int64) (int64, error)
+	EmptyTrash(ctx context.Context, profileID int64) (int64, error)
+	DeletePermanently(ctx context.Context, profileID, messageID int64) error
+
+	ApplyBatch(ctx context.Context, profileID int64, batch domain.BatchOperation) (domain.BatchResult, error)
+
+	// методы для флагов
+	SetFlags(ctx context.Context, profileID, messageID int64, update domain.FlagsUpdate) error
+	GetStarredMessagesWithKeysetPagination(ctx context.Context, profileID int64, page domain.MessagePage) ([]domain.Message, error)
+	GetStarredMessagesInfo(ctx context.Context, profileID int64) (domain.Messages, error)
+
+	// методы для вложенных папок
+	CreateSubfolder(ctx context.Context, profileID, parentID int64, folderName string) (*domain.Folder, error)
+	MoveFolder(ctx context.Context, profileID, folderID, newParentID int64) (*domain.Folder, error)
+	GetFolderTree(ctx context.Context, profileID int64) ([]domain.FolderNode, error)
 }
 
 type AvatarUsecase interface {
 	GetAvatarPresignedURL(ctx context.Context, objectName string, duration time.Duration) (*url.URL, error)
 }
 
+type SpamUsecase interface {
+	Train(ctx context.Context, profileID, messageID int64, spam bool) error
+}
+
 const (
 	maxTopicLen       = 255
 	maxTextLen        = 10000
@@ -93,11 +128,38 @@ var allowedFileTypes = map[string]struct{}{
 	"text/plain":      {},
 }
 
-func New(messageUCase MessageUsecase, avatarUCase AvatarUsecase, secret []byte) *Server {
+// Deps - сценарии, на которые сервер опирается при обработке запросов
+type Deps struct {
+	MessageUCase    MessageUsecase
+	AvatarUCase     AvatarUsecase
+	SieveUCase      SieveUsecase
+	SpamUCase       SpamUsecase
+	LabelUCase      LabelUsecase
+	ForwardUCase    ForwardingUsecase
+	ContactUCase    ContactUsecase
+	SenderListUCase SenderListUsecase
+	AliasUCase      AliasUsecase
+	NotifyUCase     NotificationUsecase
+	POP3UCase       POP3Usecase
+	ChangesUCase    ChangesUsecase
+}
+
+func New(deps Deps, secret []byte) *Server {
 	return &Server{
-		messageUCase: messageUCase,
-		avatarUCase:  avatarUCase,
-		JWTSecret:    secret,
+		messageUCase:    deps.MessageUCase,
+		avatarUCase:     deps.AvatarUCase,
+		sieveUCase:      deps.SieveUCase,
+		spamUCase:       deps.SpamUCase,
+		labelUCase:      deps.LabelUCase,
+		forwardUCase:    deps.ForwardUCase,
+		contactUCase:    deps.ContactUCase,
+		senderListUCase: deps.SenderListUCase,
+		aliasUCase:      deps.AliasUCase,
+		notifyUCase:     deps.NotifyUCase,
+		pop3UCase:       deps.POP3UCase,
+		changesUCase:    deps.ChangesUCase,
+		cursors:         cursor.NewCodec(secret, cursor.DefaultTTL),
+		JWTSecret:       secret,
 	}
 }
 
@@ -156,10 +218,14 @@ func (s *Server) MessagePage(ctx context.Context, req *pb.MessagePageRequest) (*
 		return nil, status.Error(codes.Internal, "could not get message")
 	}
 
-	shouldMarkAsRead, err := s.messageUCase.ShouldMarkAsRead(ctx, messageID, profileID)
-	if err != nil {
-		log.Warn("failed to check if should mark as read: " + err.Error())
-		shouldMarkAsRead = false
+	// Клиенты синхронизации читают письма, не меняя флаг прочтения
+	shouldMarkAsRead := false
+	if !req.Peek {
+		shouldMarkAsRead, err = s.messageUCase.ShouldMarkAsRead(ctx, messageID, profileID)
+		if err != nil {
+			log.Warn("failed to check if should mark as read: " + err.Error())
+			shouldMarkAsRead = false
+		}
 	}
 
 	if shouldMarkAsRead {
@@ -182,20 +248,52 @@ func (s *Server) MessagePage(ctx context.Context, req *pb.MessagePageRequest) (*
 		}
 	}
 
+	folderIDs := make([]string, len(fullMessage.FolderIDs))
+	for i, id := range fullMessage.FolderIDs {
+		folderIDs[i] = strconv.FormatInt(id, 10)
+	}
+
 	metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_message", "ok").Inc()
 
 	return &pb.MessagePageResponse{
 		Message: &pb.FullMessage{
-			Topic:    fullMessage.Topic,
-			Text:     fullMessage.Text,
-			Datetime: fullMessage.Datetime.Format(time.RFC3339),
-			ThreadId: fullMessage.ThreadRoot,
-			Sender:   s.domainSenderToProto(&fullMessage.Sender),
-			Files:    pbFiles,
+			Topic:                 fullMessage.Topic,
+			Text:                  fullMessage.Text,
+			Datetime:              fullMessage.Datetime.Format(time.RFC3339),
+			ThreadId:              fullMessage.ThreadRoot,
+			Sender:                s.domainSenderToProto(&fullMessage.Sender),
+			Files:                 pbFiles,
+			SpamScore:             formatSpamScore(fullMessage),
+			SpamReasons:           fullMessage.SpamReasons,
+			DkimResult:            string(fullMessage.DKIM.Status),
+			DkimDomain:            fullMessage.DKIM.Domain,
+			SpfResult:             string(fullMessage.SPF.Status),
+			SpfDomain:             fullMessage.SPF.Domain,
+			DmarcResult:           string(fullMessage.DMARC.Status),
+			DmarcPolicy:           string(fullMessage.DMARC.Policy),
+			AuthenticationResults: fullMessage.AuthenticationResults,
+			UnverifiedSender:      fullMessage.UnverifiedSender(),
+			MessageId:             req.MessageId,
+			Receivers:             fullMessage.Receivers,
+			FolderIds:             folderIDs,
+			IsRead:                strconv.FormatBool(fullMessage.IsRead || shouldMarkAsRead),
+			IsStarred:             strconv.FormatBool(fullMessage.IsStarred),
+			IsImportant:           strconv.FormatBool(fullMessage.IsImportant),
+			IsAnswered:            strconv.FormatBool(fullMessage.IsAnswered),
+			IsForwarded:           strconv.FormatBool(fullMessage.IsForwarded),
+			Size:                  strconv.FormatInt(fullMessage.Size, 10),
 		},
 	}, nil
 }
 
+// formatSpamScore возвращает пустую строку для писем, которые не оценивал спам-фильтр
+func formatSpamScore(msg domain.FullMessage) string {
+	if msg.SpamScore == 0 && len(msg.SpamReasons) == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(msg.SpamScore, 'f', 4, 64)
+}
+
 func (s *Server) Reply(ctx context.Context, req *pb.ReplyRequest) (*pb.ReplyResponse, error) {
 	const op = "messagesservice.Reply"
 
@@ -571,16 +669,78 @@ func (s *Server) MarkAsSpam(ctx context.Context, req *pb.MarkAsSpamRequest) (*pb
 		return nil, status.Error(codes.InvalidArgument, "invalid message id")
 	}
 
+	ok, err := s.messageUCase.IsUsersMessage(ctx, messageID, profileID)
+	if err != nil {
+		log.Error(op + ": failed to check if it is users message: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "mark_spam", "error").Inc()
+		return nil, status.Error(codes.Internal, "could not mark message as spam")
+	}
+	if !ok {
+		log.Debug(op + ": unpermitted access to message")
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "mark_spam", "error").Inc()
+		return nil, status.Error(codes.PermissionDenied, "access denied")
+	}
+
 	if err := s.messageUCase.MarkMessageAsSpam(ctx, messageID, profileID); err != nil {
 		log.Warn("failed to mark message as spam: " + err.Error())
 		metrics.MessagesOperationsTotal.WithLabelValues("messages", "mark_spam", "error").Inc()
 		return nil, status.Error(codes.Internal, "could not mark message as spam")
 	}
 
+	// Ошибка обучения не отменяет перемещение письма
+	if err := s.spamUCase.Train(ctx, profileID, messageID, true); err != nil {
+		log.Warn("failed to train spam filter: " + err.Error())
+	}
+
 	metrics.MessagesOperationsTotal.WithLabelValues("messages", "mark_spam", "ok").Inc()
 	return &pb.MarkAsSpamResponse{}, nil
 }
 
+func (s *Server) MarkAsNotSpam(ctx context.Context, req *pb.MarkAsNotSpamRequest) (*pb.MarkAsNotSpamResponse, error) {
+	const op = "messagesservice.MarkAsNotSpam"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/mark-as-not-spam")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	messageID, err := strconv.ParseInt(req.MessageId, 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid message id")
+	}
+
+	ok, err := s.messageUCase.IsUsersMessage(ctx, messageID, profileID)
+	if err != nil {
+		log.Error(op + ": failed to check if it is users message: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "mark_not_spam", "error").Inc()
+		return nil, status.Error(codes.Internal, "could not mark message as not spam")
+	}
+	if !ok {
+		log.Debug(op + ": unpermitted access to message")
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "mark_not_spam", "error").Inc()
+		return nil, status.Error(codes.PermissionDenied, "access denied")
+	}
+
+	// Письмо возвращается во входящие, только если оно лежит в спаме пользователя
+	if err := s.messageUCase.MarkMessageAsNotSpam(ctx, messageID, profileID); err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "mark_not_spam", "error").Inc()
+		if errors.Is(err, domain.ErrMessageNotFound) {
+			return nil, status.Error(codes.NotFound, "message is not in spam")
+		}
+		log.Warn("failed to mark message as not spam: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not mark message as not spam")
+	}
+
+	if err := s.spamUCase.Train(ctx, profileID, messageID, false); err != nil {
+		log.Warn("failed to train spam filter: " + err.Error())
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "mark_not_spam", "ok").Inc()
+	return &pb.MarkAsNotSpamResponse{}, nil
+}
+
 func (s *Server) MoveToFolder(ctx context.Context, req *pb.MoveToFolderRequest) (*pb.MoveToFolderResponse, error) {
 	const op = "messagesservice.MoveToFolder"
 	log := logger.GetLogger(ctx)
@@ -625,10 +785,20 @@ func (s *Server) CreateFolder(ctx context.Context, req *pb.CreateFolderRequest)
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	folder, err := s.messageUCase.CreateFolder(ctx, profileID, req.FolderName)
+	parentID, err := parseParentID(req.ParentId)
 	if err != nil {
-		if errors.Is(err, domain.ErrFolderExists) {
-			return nil, status.Error(codes.AlreadyExists, err.Error())
+		return nil, status.Error(codes.InvalidArgument, "invalid parent id")
+	}
+
+	var folder *domain.Folder
+	if parentID == 0 {
+		folder, err = s.messageUCase.CreateFolder(ctx, profileID, req.FolderName)
+	} else {
+		folder, err = s.messageUCase.CreateSubfolder(ctx, profileID, parentID, req.FolderName)
+	}
+	if err != nil {
+		if st, ok := folderTreeStatus(err); ok {
+			return nil, st
 		}
 		log.Error(op + ": failed to create folder: " + err.Error())
 		metrics.MessagesOperationsTotal.WithLabelValues("messages", "create_folder", "error").Inc()
@@ -640,6 +810,7 @@ func (s *Server) CreateFolder(ctx context.Context, req *pb.CreateFolderRequest)
 		FolderId:   strconv.FormatInt(folder.ID, 10),
 		FolderName: folder.Name,
 		FolderType: string(folder.Type),
+		ParentId:   formatParentID(folder.ParentID),
 	}, nil
 }
 
@@ -660,6 +831,11 @@ func (s *Server) validateFolderName(folderName string) error {
 		return fmt.Errorf("folder name contains forbidden characters")
 	}
 
+	// Косая черта зарезервирована как разделитель пути вложенных папок
+	if strings.Contains(folderName, "/") {
+		return fmt.Errorf("folder name contains forbidden characters")
+	}
+
 	systemFolders := map[string]struct{}{
 		"inbox":  {},
 		"sent":   {},
@@ -691,41 +867,90 @@ func (s *Server) GetFolder(ctx context.Context, req *pb.GetFolderRequest) (*pb.G
 		return nil, status.Error(codes.Unauthenticated, "unauthorized")
 	}
 
-	folderID, err := strconv.ParseInt(req.FolderId, 10, 64)
-	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid folder id")
-	}
-
-	var lastMessageID int64
-	var lastDatetime time.Time
-	limit := 20
-
-	if req.LastMessageId != "" {
-		if id, err := strconv.ParseInt(req.LastMessageId, 10, 64); err == nil {
-			lastMessageID = id
+	// Виртуальная папка не хранится в таблице folder и собирается по флагу письма
+	starred := req.FolderId == domain.StarredFolderID
+	var folderID int64
+	if !starred {
+		folderID, err = strconv.ParseInt(req.FolderId, 10, 64)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid folder id")
 		}
 	}
 
-	if req.LastDatetime != "" {
-		if dt, err := time.Parse(time.RFC3339, req.LastDatetime); err == nil {
-			lastDatetime = dt
+	var labelID int64
+	if req.LabelId != "" {
+		labelID, err = strconv.ParseInt(req.LabelId, 10, 64)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid label id")
 		}
 	}
 
+	page := domain.MessagePage{Limit: 20}
 	if req.Limit != "" {
 		if l, err := strconv.Atoi(req.Limit); err == nil && l > 0 && l <= 100 {
-			limit = l
+			page.Limit = l
+		}
+	}
+
+	if page.Filter, err = folderMessageFilter(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var ok bool
+	if page.Sort, ok = domain.ParseMessageSort(req.Sort); !ok {
+		return nil, status.Error(codes.InvalidArgument, "invalid sort")
+	}
+
+	scope := folderCursorScope(starred, folderID, labelID)
+	if req.Cursor != "" {
+		cur, err := s.cursors.Decode(req.Cursor)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		// Курсор другого пользователя или другой папки считается подделанным
+		if cur.ProfileID != profileID || cur.Scope != scope {
+			return nil, status.Error(codes.InvalidArgument, cursor.ErrInvalidCursor.Error())
+		}
+		// Список продолжается в той сортировке, для которой выдан курсор
+		after, sort, err := cursorMessageKey(cur)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		page.Sort = sort
+		page.After = &after
+		page.Backward = cur.Backward
+	} else if req.LastMessageId != "" {
+		// Старые параметры пагинации несут только дату письма
+		if page.Sort != domain.SortDateDesc && page.Sort != domain.SortDateAsc {
+			return nil, status.Error(codes.InvalidArgument, "last_message_id requires date sort, use cursor")
+		}
+		if id, err := strconv.ParseInt(req.LastMessageId, 10, 64); err == nil && id != 0 {
+			after := domain.MessageKey{MessageID: id}
+			if dt, err := time.Parse(time.RFC3339, req.LastDatetime); err == nil {
+				after.Datetime = dt
+			}
+			page.After = &after
 		}
 	}
 
-	messages, err := s.messageUCase.GetFolderMessagesWithKeysetPagination(ctx, profileID, folderID, lastMessageID, lastDatetime, limit)
+	var messages []domain.Message
+	if starred {
+		messages, err = s.messageUCase.GetStarredMessagesWithKeysetPagination(ctx, profileID, page)
+	} else {
+		messages, err = s.messageUCase.GetFolderMessagesWithKeysetPagination(ctx, profileID, folderID, labelID, page)
+	}
 	if err != nil {
 		metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_folder_messages", "error").Inc()
 		log.Error(op + ": failed to get folder messages: " + err.Error())
 		return nil, status.Error(codes.Internal, "could not get folder messages")
 	}
 
-	messagesInfo, err := s.messageUCase.GetFolderMessagesInfo(ctx, profileID, folderID)
+	var messagesInfo domain.Messages
+	if starred {
+		messagesInfo, err = s.messageUCase.GetStarredMessagesInfo(ctx, profileID)
+	} else {
+		messagesInfo, err = s.messageUCase.GetFolderMessagesInfo(ctx, profileID, folderID, labelID)
+	}
 	if err != nil {
 		metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_folder_messages", "error").Inc()
 		log.Error(op + ": failed to get folder messages info: " + err.Error())
@@ -743,31 +968,141 @@ func (s *Server) GetFolder(ctx context.Context, req *pb.GetFolderRequest) (*pb.G
 		}
 
 		pbMessages = append(pbMessages, &pb.Message{
-			Id:       m.ID,
-			Sender:   s.domainSenderToProto(&m.Sender),
-			Topic:    m.Topic,
-			Snippet:  m.Snippet,
-			Datetime: m.Datetime.Format(time.RFC3339),
-			IsRead:   strconv.FormatBool(m.IsRead),
+			Id:          m.ID,
+			Sender:      s.domainSenderToProto(&m.Sender),
+			Topic:       m.Topic,
+			Snippet:     m.Snippet,
+			Datetime:    m.Datetime.Format(time.RFC3339),
+			IsRead:      strconv.FormatBool(m.IsRead),
+			IsStarred:   strconv.FormatBool(m.IsStarred),
+			IsImportant: strconv.FormatBool(m.IsImportant),
+			IsAnswered:  strconv.FormatBool(m.IsAnswered),
+			IsForwarded: strconv.FormatBool(m.IsForwarded),
+			Labels:      domainLabelsToProto(m.Labels),
+			Size:        strconv.FormatInt(m.Size, 10),
 		})
 
 		nextLastMessageID = messageID
 		nextLastDatetime = m.Datetime
 	}
 
+	// Страница, полученная по обратному курсору, всегда имеет продолжение - ту страницу,
+	// с которой клиент пришел. Предыдущая страница есть, если клиент уже листал список
+	hasNext := len(messages) == page.Limit || (page.Backward && len(messages) > 0)
+	hasPrev := len(messages) > 0 && ((req.Cursor != "" && !page.Backward) || (page.Backward && len(messages) == page.Limit))
+
+	pagination := &pb.PaginationInfo{
+		HasNext:           strconv.FormatBool(hasNext),
+		NextLastMessageId: strconv.FormatInt(nextLastMessageID, 10),
+		NextLastDatetime:  nextLastDatetime.Format(time.RFC3339),
+	}
+	if hasNext {
+		pagination.NextCursor = s.folderCursor(profileID, scope, page.Sort, messages[len(messages)-1], false)
+	}
+	if hasPrev {
+		pagination.PrevCursor = s.folderCursor(profileID, scope, page.Sort, messages[0], true)
+	}
+
 	metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_folder_messages", "ok").Inc()
 	return &pb.GetFolderResponse{
 		MessageTotal:  strconv.Itoa(messagesInfo.MessageTotal),
 		MessageUnread: strconv.Itoa(messagesInfo.MessageUnread),
 		Messages:      pbMessages,
-		Pagination: &pb.PaginationInfo{
-			HasNext:           strconv.FormatBool(len(messages) == limit),
-			NextLastMessageId: strconv.FormatInt(nextLastMessageID, 10),
-			NextLastDatetime:  nextLastDatetime.Format(time.RFC3339),
-		},
+		Pagination:    pagination,
 	}, nil
 }
 
+// folderCursorScope определяет список писем, к которому привязан курсор
+func folderCursorScope(starred bool, folderID, labelID int64) string {
+	if starred {
+		return domain.StarredFolderID
+	}
+	return fmt.Sprintf("folder:%d:%d", folderID, labelID)
+}
+
+// folderCursor выдает курсор на страницу после (или перед, если backward) письма m
+func (s *Server) folderCursor(profileID int64, scope string, sort domain.MessageSort, m domain.Message, backward bool) string {
+	key := m.Key()
+	cur := cursor.Cursor{
+		ProfileID: profileID,
+		Scope:     scope,
+		Sort:      string(sort),
+		MessageID: key.MessageID,
+		Datetime:  key.Datetime,
+		Backward:  backward,
+	}
+	switch sort {
+	case domain.SortSender:
+		cur.Key = key.Sender
+	case domain.SortTopic:
+		cur.Key = key.Topic
+	case domain.SortSize:
+		cur.Key = strconv.FormatInt(key.Size, 10)
+	}
+	return s.cursors.Encode(cur)
+}
+
+// cursorMessageKey восстанавливает из курсора сортировку и граничное письмо страницы
+func cursorMessageKey(cur cursor.Cursor) (domain.MessageKey, domain.MessageSort, error) {
+	sort, ok := domain.ParseMessageSort(cur.Sort)
+	if !ok || cur.Sort == "" {
+		return domain.MessageKey{}, "", cursor.ErrInvalidCursor
+	}
+
+	key := domain.MessageKey{MessageID: cur.MessageID, Datetime: cur.Datetime}
+	switch sort {
+	case domain.SortSender:
+		key.Sender = cur.Key
+	case domain.SortTopic:
+		key.Topic = cur.Key
+	case domain.SortSize:
+		size, err := strconv.ParseInt(cur.Key, 10, 64)
+		if err != nil {
+			return domain.MessageKey{}, "", cursor.ErrInvalidCursor
+		}
+		key.Size = size
+	}
+	return key, sort, nil
+}
+
+// folderMessageFilter разбирает фильтры списка писем из запроса
+func folderMessageFilter(req *pb.GetFolderRequest) (domain.MessageFilter, error) {
+	var filter domain.MessageFilter
+	flags := []struct {
+		value  string
+		target *bool
+	}{
+		{req.UnreadOnly, &filter.UnreadOnly},
+		{req.StarredOnly, &filter.StarredOnly},
+		{req.HasAttachments, &filter.WithAttachments},
+	}
+	for _, flag := range flags {
+		if flag.value == "" {
+			continue
+		}
+		value, err := strconv.ParseBool(flag.value)
+		if err != nil {
+			return domain.MessageFilter{}, errors.New("invalid filter flag")
+		}
+		*flag.target = value
+	}
+
+	filter.Sender = strings.TrimSpace(req.Sender)
+
+	var err error
+	if req.After != "" {
+		if filter.After, err = time.Parse(time.RFC3339, req.After); err != nil {
+			return domain.MessageFilter{}, errors.New("invalid after date")
+		}
+	}
+	if req.Before != "" {
+		if filter.Before, err = time.Parse(time.RFC3339, req.Before); err != nil {
+			return domain.MessageFilter{}, errors.New("invalid before date")
+		}
+	}
+	return filter, nil
+}
+
 func (s *Server) GetFolders(ctx context.Context, req *pb.GetFoldersRequest) (*pb.GetFoldersResponse, error) {
 	const op = "messagesservice.GetFolders"
 
@@ -792,12 +1127,13 @@ func (s *Server) GetFolders(ctx context.Context, req *pb.GetFoldersRequest) (*pb
 
 	pbFolders := make([]*pb.Folder, 0, len(folders))
 	for _, folder := range folders {
-		pbFolders = append(pbFolders, &pb.Folder{
-			FolderId:   strconv.FormatInt(folder.ID, 10),
-			FolderName: folder.Name,
-			FolderType: string(folder.Type),
-		})
+		pbFolders = append(pbFolders, domainFolderToProto(folder))
 	}
+	pbFolders = append(pbFolders, &pb.Folder{
+		FolderId:   domain.StarredFolderID,
+		FolderName: "Starred",
+		FolderType: string(domain.FolderStarred),
+	})
 
 	metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_folders", "ok").Inc()
 	return &pb.GetFoldersResponse{
@@ -829,6 +1165,9 @@ func (s *Server) RenameFolder(ctx context.Context, req *pb.RenameFolderRequest)
 		if errors.Is(err, domain.ErrFolderExists) {
 			return nil, status.Error(codes.AlreadyExists, err.Error())
 		}
+		if errors.Is(err, domain.ErrFolderNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
 		log.Error(op + ": failed to rename folder: " + err.Error())
 		return nil, status.Error(codes.Internal, "could not rename folder")
 	}
@@ -70,6 +70,49 @@ func (m *MockMessageUsecase) MarkMessageAsSpam(ctx context.Context, messageID in
 	return args.Error(0)
 }
 
+func (m *MockMessageUsecase) MarkMessageAsNotSpam(ctx context.Context, messageID int64, profileID int64) error {
+	args := m.Called(ctx, messageID, profileID)
+	return args.Error(0)
+}
+
+func (m *MockMessageUsecase) RestoreFromTrash(ctx context.Context, profileID, messageID int64) (int64, error) {
+	args := m.Called(ctx, profileID, messageID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockMessageUsecase) EmptyTrash(ctx context.Context, profileID int64) (int64, error) {
+	args := m.Called(ctx, profileID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockMessageUsecase) DeletePermanently(ctx context.Context, profileID, messageID int64) error {
+	args := m.Called(ctx, profileID, messageID)
+	return args.Error(0)
+}
+
+func (m *MockMessageUsecase) SetFlags(ctx context.Context, profileID, messageID int64, update domain.FlagsUpdate) error {
+	args := m.Called(ctx, profileID, messageID, update)
+	return args.Error(0)
+}
+
+func (m *MockMessageUsecase) GetStarredMessagesWithKeysetPagination(ctx context.Context, profileID int64, page domain.MessagePage) ([]domain.Message, error) {
+	args := m.Called(ctx, profileID, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Message), args.Error(1)
+}
+
+func (m *MockMessageUsecase) GetStarredMessagesInfo(ctx context.Context, profileID int64) (domain.Messages, error) {
+	args := m.Called(ctx, profileID)
+	return args.Get(0).(domain.Messages), args.Error(1)
+}
+
+func (m *MockMessageUsecase) ApplyBatch(ctx context.Context, profileID int64, batch domain.BatchOperation) (domain.BatchResult, error) {
+	args := m.Called(ctx, profileID, batch)
+	return args.Get(0).(domain.BatchResult), args.Error(1)
+}
+
 func (m *MockMessageUsecase) IsUsersMessage(ctx context.Context, messageID int64, profileID int64) (bool, error) {
 	args := m.Called(ctx, messageID, profileID)
 	return args.Bool(0), args.Error(1)
@@ -123,6 +166,35 @@ func (m *MockMessageUsecase) CreateFolder(ctx context.Context, profileID int64,
 	return args.Get(0).(*domain.Folder), args.Error(1)
 }
 
+func (m *MockMessageUsecase) CreateSubfolder(ctx context.Context, profileID, parentID int64, folderName string) (*domain.Folder, error) {
+	args := m.Called(ctx, profileID, parentID, folderName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Folder), args.Error(1)
+}
+
+func (m *MockMessageUsecase) MoveFolder(ctx context.Context, profileID, folderID, newParentID int64) (*domain.Folder, error) {
+	args := m.Called(ctx, profileID, folderID, newParentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Folder), args.Error(1)
+}
+
+func (m *MockMessageUsecase) GetFolderTree(ctx context.Context, profileID int64) ([]domain.FolderNode, error) {
+	args := m.Called(ctx, profileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.FolderNode), args.Error(1)
+}
+
+func (m *MockMessageUsecase) ForwardMessage(ctx context.Context, profileID, sourceID int64, receivers []string, comment string) (int64, error) {
+	args := m.Called(ctx, profileID, sourceID, receivers, comment)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockMessageUsecase) GetUserFolders(ctx context.Context, profileID int64) ([]domain.Folder, error) {
 	args := m.Called(ctx, profileID)
 	return args.Get(0).([]domain.Folder), args.Error(1)
@@ -146,13 +218,13 @@ func (m *MockMessageUsecase) DeleteMessageFromFolder(ctx context.Context, profil
 	return args.Error(0)
 }
 
-func (m *MockMessageUsecase) GetFolderMessagesWithKeysetPagination(ctx context.Context, profileID, folderID, lastMessageID int64, lastDatetime time.Time, limit int) ([]domain.Message, error) {
-	args := m.Called(ctx, profileID, folderID, lastMessageID, lastDatetime, limit)
+func (m *MockMessageUsecase) GetFolderMessagesWithKeysetPagination(ctx context.Context, profileID, folderID, labelID int64, page domain.MessagePage) ([]domain.Message, error) {
+	args := m.Called(ctx, profileID, folderID, labelID, page)
 	return args.Get(0).([]domain.Message), args.Error(1)
 }
 
-func (m *MockMessageUsecase) GetFolderMessagesInfo(ctx context.Context, profileID, folderID int64) (domain.Messages, error) {
-	args := m.Called(ctx, profileID, folderID)
+func (m *MockMessageUsecase) GetFolderMessagesInfo(ctx context.Context, profileID, folderID, labelID int64) (domain.Messages, error) {
+	args := m.Called(ctx, profileID, folderID, labelID)
 	return args.Get(0).(domain.Messages), args.Error(1)
 }
 
@@ -189,7 +261,13 @@ func setupTestServer() (*Server, *MockMessageUsecase, *MockAvatarUsecase) {
 	mockMessageUsecase := &MockMessageUsecase{}
 	mockAvatarUsecase := &MockAvatarUsecase{}
 	jwtSecret := []byte("test-secret-key-very-long-for-testing")
-	server := New(mockMessageUsecase, mockAvatarUsecase, jwtSecret)
+	mockSpamUsecase := &MockSpamUsecase{}
+	mockSpamUsecase.On("Train", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	server := New(Deps{
+		MessageUCase: mockMessageUsecase,
+		AvatarUCase:  mockAvatarUsecase,
+		SpamUCase:    mockSpamUsecase,
+	}, jwtSecret)
 	return server, mockMessageUsecase, mockAvatarUsecase
 }
 
@@ -469,10 +547,23 @@ func TestServer_MarkAsSpam(t *testing.T) {
 				MessageId: "123",
 			},
 			mockSetup: func() {
+				mockMessage.On("IsUsersMessage", mock.Anything, int64(123), int64(1)).Return(true, nil)
 				mockMessage.On("MarkMessageAsSpam", mock.Anything, int64(123), int64(1)).Return(nil)
 			},
 			expectedError: false,
 		},
+		{
+			name: "ForeignMessage",
+			ctx:  createTestContextWithToken(1, server.JWTSecret),
+			request: &pb.MarkAsSpamRequest{
+				MessageId: "456",
+			},
+			mockSetup: func() {
+				mockMessage.On("IsUsersMessage", mock.Anything, int64(456), int64(1)).Return(false, nil)
+			},
+			expectedError: true,
+			expectedCode:  codes.PermissionDenied,
+		},
 		{
 			name: "Unauthorized",
 			ctx:  createTestContextWithoutAuth(),
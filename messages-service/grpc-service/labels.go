@@ -0,0 +1,233 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	"2025_2_a4code/internal/lib/metrics"
+	"context"
+	"errors"
+	"strconv"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type LabelUsecase interface {
+	CreateLabel(ctx context.Context, profileID int64, name, color string) (domain.Label, error)
+	GetLabels(ctx context.Context, profileID int64) ([]domain.Label, error)
+	UpdateLabel(ctx context.Context, profileID, labelID int64, name, color string) (domain.Label, error)
+	DeleteLabel(ctx context.Context, profileID, labelID int64) error
+	ApplyLabel(ctx context.Context, profileID, labelID int64, messageIDs []int64) (int64, error)
+	RemoveLabel(ctx context.Context, profileID, labelID int64, messageIDs []int64) (int64, error)
+}
+
+func (s *Server) CreateLabel(ctx context.Context, req *pb.CreateLabelRequest) (*pb.CreateLabelResponse, error) {
+	const op = "messagesservice.CreateLabel"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/create-label")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	label, err := s.labelUCase.CreateLabel(ctx, profileID, req.Name, req.Color)
+	if err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "create_label", "error").Inc()
+		if st, ok := labelStatus(err); ok {
+			return nil, st
+		}
+		log.Error(op + ": failed to create label: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not create label")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "create_label", "ok").Inc()
+	return &pb.CreateLabelResponse{Label: domainLabelToProto(label)}, nil
+}
+
+func (s *Server) GetLabels(ctx context.Context, req *pb.GetLabelsRequest) (*pb.GetLabelsResponse, error) {
+	const op = "messagesservice.GetLabels"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/get-labels")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	labels, err := s.labelUCase.GetLabels(ctx, profileID)
+	if err != nil {
+		log.Error(op + ": failed to get labels: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_labels", "error").Inc()
+		return nil, status.Error(codes.Internal, "could not get labels")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_labels", "ok").Inc()
+	return &pb.GetLabelsResponse{Labels: domainLabelsToProto(labels)}, nil
+}
+
+func (s *Server) UpdateLabel(ctx context.Context, req *pb.UpdateLabelRequest) (*pb.UpdateLabelResponse, error) {
+	const op = "messagesservice.UpdateLabel"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/update-label")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	labelID, err := strconv.ParseInt(req.LabelId, 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid label id")
+	}
+
+	label, err := s.labelUCase.UpdateLabel(ctx, profileID, labelID, req.Name, req.Color)
+	if err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "update_label", "error").Inc()
+		if st, ok := labelStatus(err); ok {
+			return nil, st
+		}
+		log.Error(op + ": failed to update label: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not update label")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "update_label", "ok").Inc()
+	return &pb.UpdateLabelResponse{Label: domainLabelToProto(label)}, nil
+}
+
+func (s *Server) DeleteLabel(ctx context.Context, req *pb.DeleteLabelRequest) (*pb.DeleteLabelResponse, error) {
+	const op = "messagesservice.DeleteLabel"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/delete-label")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	labelID, err := strconv.ParseInt(req.LabelId, 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid label id")
+	}
+
+	if err := s.labelUCase.DeleteLabel(ctx, profileID, labelID); err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "delete_label", "error").Inc()
+		if st, ok := labelStatus(err); ok {
+			return nil, st
+		}
+		log.Error(op + ": failed to delete label: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not delete label")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "delete_label", "ok").Inc()
+	return &pb.DeleteLabelResponse{}, nil
+}
+
+func (s *Server) ApplyLabel(ctx context.Context, req *pb.ApplyLabelRequest) (*pb.ApplyLabelResponse, error) {
+	const op = "messagesservice.ApplyLabel"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/apply-label")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	labelID, messageIDs, err := parseLabelMessages(req.LabelId, req.MessageIds)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	applied, err := s.labelUCase.ApplyLabel(ctx, profileID, labelID, messageIDs)
+	if err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "apply_label", "error").Inc()
+		if st, ok := labelStatus(err); ok {
+			return nil, st
+		}
+		log.Error(op + ": failed to apply label: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not apply label")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "apply_label", "ok").Inc()
+	return &pb.ApplyLabelResponse{AppliedCount: applied}, nil
+}
+
+func (s *Server) RemoveLabel(ctx context.Context, req *pb.RemoveLabelRequest) (*pb.RemoveLabelResponse, error) {
+	const op = "messagesservice.RemoveLabel"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/remove-label")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	labelID, messageIDs, err := parseLabelMessages(req.LabelId, req.MessageIds)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	removed, err := s.labelUCase.RemoveLabel(ctx, profileID, labelID, messageIDs)
+	if err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "remove_label", "error").Inc()
+		if st, ok := labelStatus(err); ok {
+			return nil, st
+		}
+		log.Error(op + ": failed to remove label: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not remove label")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "remove_label", "ok").Inc()
+	return &pb.RemoveLabelResponse{RemovedCount: removed}, nil
+}
+
+// labelStatus переводит ожидаемые ошибки меток в gRPC-статус
+func labelStatus(err error) (error, bool) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidLabel):
+		return status.Error(codes.InvalidArgument, err.Error()), true
+	case errors.Is(err, domain.ErrBatchTooLarge):
+		return status.Error(codes.InvalidArgument, "too many messages"), true
+	case errors.Is(err, domain.ErrLabelNotFound):
+		return status.Error(codes.NotFound, "label not found"), true
+	case errors.Is(err, domain.ErrLabelExists):
+		return status.Error(codes.AlreadyExists, "label already exists"), true
+	}
+	return nil, false
+}
+
+func parseLabelMessages(rawLabelID string, rawMessageIDs []string) (int64, []int64, error) {
+	labelID, err := strconv.ParseInt(rawLabelID, 10, 64)
+	if err != nil {
+		return 0, nil, errors.New("invalid label id")
+	}
+
+	messageIDs := make([]int64, 0, len(rawMessageIDs))
+	for _, rawID := range rawMessageIDs {
+		id, err := strconv.ParseInt(rawID, 10, 64)
+		if err != nil {
+			return 0, nil, errors.New("invalid message id")
+		}
+		messageIDs = append(messageIDs, id)
+	}
+
+	return labelID, messageIDs, nil
+}
+
+func domainLabelToProto(label domain.Label) *pb.Label {
+	return &pb.Label{
+		LabelId: strconv.FormatInt(label.ID, 10),
+		Name:    label.Name,
+		Color:   label.Color,
+	}
+}
+
+func domainLabelsToProto(labels []domain.Label) []*pb.Label {
+	result := make([]*pb.Label, 0, len(labels))
+	for _, label := range labels {
+		result = append(result, domainLabelToProto(label))
+	}
+	return result
+}
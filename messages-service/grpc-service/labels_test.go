@@ -0,0 +1,184 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"testing"
+	"time"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type MockLabelUsecase struct {
+	mock.Mock
+}
+
+func (m *MockLabelUsecase) CreateLabel(ctx context.Context, profileID int64, name, color string) (domain.Label, error) {
+	args := m.Called(ctx, profileID, name, color)
+	return args.Get(0).(domain.Label), args.Error(1)
+}
+
+func (m *MockLabelUsecase) GetLabels(ctx context.Context, profileID int64) ([]domain.Label, error) {
+	args := m.Called(ctx, profileID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Label), args.Error(1)
+}
+
+func (m *MockLabelUsecase) UpdateLabel(ctx context.Context, profileID, labelID int64, name, color string) (domain.Label, error) {
+	args := m.Called(ctx, profileID, labelID, name, color)
+	return args.Get(0).(domain.Label), args.Error(1)
+}
+
+func (m *MockLabelUsecase) DeleteLabel(ctx context.Context, profileID, labelID int64) error {
+	args := m.Called(ctx, profileID, labelID)
+	return args.Error(0)
+}
+
+func (m *MockLabelUsecase) ApplyLabel(ctx context.Context, profileID, labelID int64, messageIDs []int64) (int64, error) {
+	args := m.Called(ctx, profileID, labelID, messageIDs)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockLabelUsecase) RemoveLabel(ctx context.Context, profileID, labelID int64, messageIDs []int64) (int64, error) {
+	args := m.Called(ctx, profileID, labelID, messageIDs)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func setupLabelTestServer() (*Server, *MockMessageUsecase, *MockLabelUsecase) {
+	mockMessageUsecase := &MockMessageUsecase{}
+	mockLabelUsecase := &MockLabelUsecase{}
+	jwtSecret := []byte("test-secret-key-very-long-for-testing")
+	server := New(Deps{
+		MessageUCase: mockMessageUsecase,
+		LabelUCase:   mockLabelUsecase,
+	}, jwtSecret)
+	return server, mockMessageUsecase, mockLabelUsecase
+}
+
+func TestServer_CreateLabel(t *testing.T) {
+	tests := []struct {
+		name         string
+		ctx          func(server *Server) context.Context
+		mockSetup    func(mockLabel *MockLabelUsecase)
+		expectedCode codes.Code
+	}{
+		{
+			name: "Success",
+			ctx:  func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			mockSetup: func(mockLabel *MockLabelUsecase) {
+				mockLabel.On("CreateLabel", mock.Anything, int64(1), "Work", "#ff0000").
+					Return(domain.Label{ID: 3, Name: "Work", Color: "#ff0000"}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Unauthorized",
+			ctx:          func(server *Server) context.Context { return createTestContextWithoutAuth() },
+			mockSetup:    func(mockLabel *MockLabelUsecase) {},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name: "Invalid",
+			ctx:  func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			mockSetup: func(mockLabel *MockLabelUsecase) {
+				mockLabel.On("CreateLabel", mock.Anything, int64(1), "Work", "#ff0000").
+					Return(domain.Label{}, domain.ErrInvalidLabel)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "AlreadyExists",
+			ctx:  func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			mockSetup: func(mockLabel *MockLabelUsecase) {
+				mockLabel.On("CreateLabel", mock.Anything, int64(1), "Work", "#ff0000").
+					Return(domain.Label{}, domain.ErrLabelExists)
+			},
+			expectedCode: codes.AlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _, mockLabel := setupLabelTestServer()
+			tt.mockSetup(mockLabel)
+
+			resp, err := server.CreateLabel(tt.ctx(server), &pb.CreateLabelRequest{Name: "Work", Color: "#ff0000"})
+
+			if tt.expectedCode == codes.OK {
+				assert.NoError(t, err)
+				assert.Equal(t, "3", resp.Label.LabelId)
+			} else {
+				st, ok := status.FromError(err)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedCode, st.Code())
+			}
+			mockLabel.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_DeleteLabel_NotFound(t *testing.T) {
+	server, _, mockLabel := setupLabelTestServer()
+	mockLabel.On("DeleteLabel", mock.Anything, int64(1), int64(3)).Return(domain.ErrLabelNotFound)
+
+	_, err := server.DeleteLabel(createTestContextWithToken(1, server.JWTSecret), &pb.DeleteLabelRequest{LabelId: "3"})
+
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.NotFound, st.Code())
+	mockLabel.AssertExpectations(t)
+}
+
+func TestServer_ApplyLabel(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		server, _, mockLabel := setupLabelTestServer()
+		mockLabel.On("ApplyLabel", mock.Anything, int64(1), int64(3), []int64{10, 11}).Return(int64(2), nil)
+
+		resp, err := server.ApplyLabel(createTestContextWithToken(1, server.JWTSecret), &pb.ApplyLabelRequest{
+			LabelId:    "3",
+			MessageIds: []string{"10", "11"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), resp.AppliedCount)
+		mockLabel.AssertExpectations(t)
+	})
+
+	t.Run("InvalidMessageID", func(t *testing.T) {
+		server, _, _ := setupLabelTestServer()
+
+		_, err := server.ApplyLabel(createTestContextWithToken(1, server.JWTSecret), &pb.ApplyLabelRequest{
+			LabelId:    "3",
+			MessageIds: []string{"abc"},
+		})
+
+		st, _ := status.FromError(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+	})
+}
+
+func TestServer_GetFolder_FilteredByLabel(t *testing.T) {
+	server, mockMessage, _ := setupLabelTestServer()
+	messages := []domain.Message{{
+		ID:       "101",
+		Datetime: time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC),
+		Labels:   []domain.Label{{ID: 3, Name: "Work", Color: "#ff0000"}},
+	}}
+	mockMessage.On("GetFolderMessagesWithKeysetPagination", mock.Anything, int64(1), int64(5), int64(3), domain.MessagePage{Sort: domain.SortDateDesc, Limit: 20}).Return(messages, nil)
+	mockMessage.On("GetFolderMessagesInfo", mock.Anything, int64(1), int64(5), int64(3)).Return(domain.Messages{MessageTotal: 1}, nil)
+
+	resp, err := server.GetFolder(createTestContextWithToken(1, server.JWTSecret), &pb.GetFolderRequest{FolderId: "5", LabelId: "3"})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Messages, 1)
+	assert.Len(t, resp.Messages[0].Labels, 1)
+	assert.Equal(t, "Work", resp.Messages[0].Labels[0].Name)
+	mockMessage.AssertExpectations(t)
+}
@@ -0,0 +1,146 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/lib/cursor"
+	"testing"
+	"time"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func folderPage(ids ...string) []domain.Message {
+	base := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+	messages := make([]domain.Message, 0, len(ids))
+	for i, id := range ids {
+		messages = append(messages, domain.Message{ID: id, Datetime: base.Add(-time.Duration(i) * time.Minute)})
+	}
+	return messages
+}
+
+func TestServer_GetFolder_Cursor(t *testing.T) {
+	server, mockMessage, _ := setupLabelTestServer()
+	ctx := createTestContextWithToken(1, server.JWTSecret)
+	page := folderPage("30", "29")
+	older := folderPage("28")
+	mockMessage.On("GetFolderMessagesInfo", mock.Anything, int64(1), int64(5), int64(0)).Return(domain.Messages{MessageTotal: 3}, nil)
+
+	mockMessage.On("GetFolderMessagesWithKeysetPagination", mock.Anything, int64(1), int64(5), int64(0),
+		domain.MessagePage{Sort: domain.SortDateDesc, Limit: 2}).Return(page, nil).Once()
+	first, err := server.GetFolder(ctx, &pb.GetFolderRequest{FolderId: "5", Limit: "2"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, first.Pagination.NextCursor)
+	assert.Empty(t, first.Pagination.PrevCursor)
+
+	mockMessage.On("GetFolderMessagesWithKeysetPagination", mock.Anything, int64(1), int64(5), int64(0), mock.MatchedBy(func(p domain.MessagePage) bool {
+		return !p.Backward && p.After != nil && p.After.MessageID == 29 && p.After.Datetime.Equal(page[1].Datetime)
+	})).Return(older, nil).Once()
+	second, err := server.GetFolder(ctx, &pb.GetFolderRequest{FolderId: "5", Limit: "2", Cursor: first.Pagination.NextCursor})
+	require.NoError(t, err)
+	assert.Equal(t, "false", second.Pagination.HasNext)
+	assert.Empty(t, second.Pagination.NextCursor)
+	require.NotEmpty(t, second.Pagination.PrevCursor)
+
+	// Возврат назад выдает письма новее первого письма текущей страницы
+	mockMessage.On("GetFolderMessagesWithKeysetPagination", mock.Anything, int64(1), int64(5), int64(0), mock.MatchedBy(func(p domain.MessagePage) bool {
+		return p.Backward && p.After != nil && p.After.MessageID == 28
+	})).Return(page, nil).Once()
+	back, err := server.GetFolder(ctx, &pb.GetFolderRequest{FolderId: "5", Limit: "2", Cursor: second.Pagination.PrevCursor})
+	require.NoError(t, err)
+	assert.Len(t, back.Messages, 2)
+	assert.Equal(t, "true", back.Pagination.HasNext)
+	assert.NotEmpty(t, back.Pagination.NextCursor)
+	assert.NotEmpty(t, back.Pagination.PrevCursor)
+
+	mockMessage.AssertExpectations(t)
+}
+
+func TestServer_GetFolder_SortAndFilters(t *testing.T) {
+	server, mockMessage, _ := setupLabelTestServer()
+	ctx := createTestContextWithToken(1, server.JWTSecret)
+	after := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	page := folderPage("30", "29")
+	page[1].Size = 2048
+	mockMessage.On("GetFolderMessagesInfo", mock.Anything, int64(1), int64(5), int64(0)).Return(domain.Messages{MessageTotal: 3}, nil)
+
+	mockMessage.On("GetFolderMessagesWithKeysetPagination", mock.Anything, int64(1), int64(5), int64(0), domain.MessagePage{
+		Sort:   domain.SortSize,
+		Filter: domain.MessageFilter{UnreadOnly: true, WithAttachments: true, Sender: "boss@a4mail.ru", After: after},
+		Limit:  2,
+	}).Return(page, nil).Once()
+	first, err := server.GetFolder(ctx, &pb.GetFolderRequest{
+		FolderId:       "5",
+		Limit:          "2",
+		Sort:           "size",
+		UnreadOnly:     "true",
+		HasAttachments: "true",
+		Sender:         "boss@a4mail.ru",
+		After:          after.Format(time.RFC3339),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "2048", first.Messages[1].Size)
+
+	// Курсор сохраняет сортировку, даже если клиент прислал другую
+	mockMessage.On("GetFolderMessagesWithKeysetPagination", mock.Anything, int64(1), int64(5), int64(0), mock.MatchedBy(func(p domain.MessagePage) bool {
+		return p.Sort == domain.SortSize && p.After != nil && p.After.Size == 2048 && p.After.MessageID == 29
+	})).Return([]domain.Message{}, nil).Once()
+	_, err = server.GetFolder(ctx, &pb.GetFolderRequest{FolderId: "5", Limit: "2", Sort: "topic", Cursor: first.Pagination.NextCursor})
+	require.NoError(t, err)
+
+	mockMessage.AssertExpectations(t)
+}
+
+func TestServer_GetFolder_InvalidListOptions(t *testing.T) {
+	server, _, _ := setupLabelTestServer()
+
+	tests := []struct {
+		name string
+		req  *pb.GetFolderRequest
+	}{
+		{name: "UnknownSort", req: &pb.GetFolderRequest{FolderId: "5", Sort: "random"}},
+		{name: "BadFlag", req: &pb.GetFolderRequest{FolderId: "5", UnreadOnly: "maybe"}},
+		{name: "BadDate", req: &pb.GetFolderRequest{FolderId: "5", Before: "yesterday"}},
+		{name: "LegacyPaginationWithSort", req: &pb.GetFolderRequest{FolderId: "5", Sort: "sender", LastMessageId: "10"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := server.GetFolder(createTestContextWithToken(1, server.JWTSecret), tt.req)
+
+			st, _ := status.FromError(err)
+			assert.Equal(t, codes.InvalidArgument, st.Code())
+		})
+	}
+}
+
+func TestServer_GetFolder_CursorRejected(t *testing.T) {
+	server, mockMessage, _ := setupLabelTestServer()
+	codec := cursor.NewCodec(server.JWTSecret, time.Hour)
+	foreign := cursor.NewCodec([]byte("another-secret"), time.Hour)
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "Garbage", cursor: "not-a-cursor"},
+		{name: "WrongSignature", cursor: foreign.Encode(cursor.Cursor{ProfileID: 1, Scope: "folder:5:0", MessageID: 10})},
+		{name: "AnotherProfile", cursor: codec.Encode(cursor.Cursor{ProfileID: 2, Scope: "folder:5:0", MessageID: 10})},
+		{name: "AnotherFolder", cursor: codec.Encode(cursor.Cursor{ProfileID: 1, Scope: "folder:6:0", MessageID: 10})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := server.GetFolder(createTestContextWithToken(1, server.JWTSecret), &pb.GetFolderRequest{FolderId: "5", Cursor: tt.cursor})
+
+			st, _ := status.FromError(err)
+			assert.Equal(t, codes.InvalidArgument, st.Code())
+		})
+	}
+	mockMessage.AssertNotCalled(t, "GetFolderMessagesWithKeysetPagination")
+}
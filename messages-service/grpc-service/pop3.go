@@ -0,0 +1,79 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	"2025_2_a4code/internal/lib/metrics"
+	"context"
+	"errors"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type POP3Usecase interface {
+	GetSettings(ctx context.Context, profileID int64) (domain.POP3Settings, error)
+	SetSettings(ctx context.Context, profileID int64, update domain.POP3SettingsUpdate) (domain.POP3Settings, error)
+}
+
+func (s *Server) GetPop3Settings(ctx context.Context, req *pb.GetPop3SettingsRequest) (*pb.GetPop3SettingsResponse, error) {
+	const op = "messagesservice.GetPop3Settings"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/get-pop3-settings")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	settings, err := s.pop3UCase.GetSettings(ctx, profileID)
+	if err != nil {
+		log.Error(op + ": failed to get pop3 settings: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_pop3_settings", "error").Inc()
+		return nil, status.Error(codes.Internal, "could not get pop3 settings")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_pop3_settings", "ok").Inc()
+	return &pb.GetPop3SettingsResponse{Settings: domainPOP3SettingsToProto(settings)}, nil
+}
+
+func (s *Server) PutPop3Settings(ctx context.Context, req *pb.PutPop3SettingsRequest) (*pb.PutPop3SettingsResponse, error) {
+	const op = "messagesservice.PutPop3Settings"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/put-pop3-settings")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	if req.Settings == nil {
+		return nil, status.Error(codes.InvalidArgument, "settings are required")
+	}
+
+	settings, err := s.pop3UCase.SetSettings(ctx, profileID, domain.POP3SettingsUpdate{
+		DeleteAction: domain.POP3DeleteAction(req.Settings.DeleteAction),
+		APOPEnabled:  req.Settings.ApopEnabled,
+		APOPSecret:   req.Settings.ApopSecret,
+	})
+	if err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "put_pop3_settings", "error").Inc()
+		if errors.Is(err, domain.ErrInvalidPOP3Settings) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		log.Error(op + ": failed to save pop3 settings: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not save pop3 settings")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "put_pop3_settings", "ok").Inc()
+	return &pb.PutPop3SettingsResponse{Settings: domainPOP3SettingsToProto(settings)}, nil
+}
+
+func domainPOP3SettingsToProto(settings domain.POP3Settings) *pb.Pop3Settings {
+	return &pb.Pop3Settings{
+		DeleteAction: string(settings.DeleteAction),
+		ApopEnabled:  settings.APOPEnabled,
+	}
+}
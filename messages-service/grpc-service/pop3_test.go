@@ -0,0 +1,131 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type MockPOP3Usecase struct {
+	mock.Mock
+}
+
+func (m *MockPOP3Usecase) GetSettings(ctx context.Context, profileID int64) (domain.POP3Settings, error) {
+	args := m.Called(ctx, profileID)
+	return args.Get(0).(domain.POP3Settings), args.Error(1)
+}
+
+func (m *MockPOP3Usecase) SetSettings(ctx context.Context, profileID int64, update domain.POP3SettingsUpdate) (domain.POP3Settings, error) {
+	args := m.Called(ctx, profileID, update)
+	return args.Get(0).(domain.POP3Settings), args.Error(1)
+}
+
+func setupPOP3TestServer() (*Server, *MockPOP3Usecase) {
+	mockPOP3Usecase := &MockPOP3Usecase{}
+	jwtSecret := []byte("test-secret-key-very-long-for-testing")
+	server := New(Deps{
+		POP3UCase: mockPOP3Usecase,
+	}, jwtSecret)
+	return server, mockPOP3Usecase
+}
+
+func TestServer_GetPop3Settings(t *testing.T) {
+	server, mockPOP3 := setupPOP3TestServer()
+	mockPOP3.On("GetSettings", mock.Anything, int64(1)).
+		Return(domain.POP3Settings{DeleteAction: domain.POP3DeleteHide, APOPEnabled: true}, nil)
+
+	resp, err := server.GetPop3Settings(createTestContextWithToken(1, server.JWTSecret), &pb.GetPop3SettingsRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "hide", resp.Settings.DeleteAction)
+	assert.True(t, resp.Settings.ApopEnabled)
+	assert.Empty(t, resp.Settings.ApopSecret)
+	mockPOP3.AssertExpectations(t)
+}
+
+func TestServer_PutPop3Settings(t *testing.T) {
+	update := domain.POP3SettingsUpdate{DeleteAction: domain.POP3DeleteHide, APOPEnabled: true, APOPSecret: "tanstaaf"}
+
+	tests := []struct {
+		name         string
+		ctx          func(server *Server) context.Context
+		request      *pb.PutPop3SettingsRequest
+		mockSetup    func(mockPOP3 *MockPOP3Usecase)
+		expectedCode codes.Code
+	}{
+		{
+			name: "Success",
+			ctx:  func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.PutPop3SettingsRequest{Settings: &pb.Pop3Settings{
+				DeleteAction: "hide", ApopEnabled: true, ApopSecret: "tanstaaf",
+			}},
+			mockSetup: func(mockPOP3 *MockPOP3Usecase) {
+				mockPOP3.On("SetSettings", mock.Anything, int64(1), update).
+					Return(domain.POP3Settings{DeleteAction: domain.POP3DeleteHide, APOPEnabled: true}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Unauthorized",
+			ctx:          func(server *Server) context.Context { return createTestContextWithoutAuth() },
+			request:      &pb.PutPop3SettingsRequest{Settings: &pb.Pop3Settings{}},
+			mockSetup:    func(mockPOP3 *MockPOP3Usecase) {},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "MissingSettings",
+			ctx:          func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request:      &pb.PutPop3SettingsRequest{},
+			mockSetup:    func(mockPOP3 *MockPOP3Usecase) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "InvalidSettings",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.PutPop3SettingsRequest{Settings: &pb.Pop3Settings{DeleteAction: "purge"}},
+			mockSetup: func(mockPOP3 *MockPOP3Usecase) {
+				mockPOP3.On("SetSettings", mock.Anything, int64(1), mock.Anything).
+					Return(domain.POP3Settings{}, fmt.Errorf("%w: unknown delete action", domain.ErrInvalidPOP3Settings))
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "InternalError",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.PutPop3SettingsRequest{Settings: &pb.Pop3Settings{}},
+			mockSetup: func(mockPOP3 *MockPOP3Usecase) {
+				mockPOP3.On("SetSettings", mock.Anything, int64(1), mock.Anything).
+					Return(domain.POP3Settings{}, errors.New("db down"))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, mockPOP3 := setupPOP3TestServer()
+			tt.mockSetup(mockPOP3)
+
+			resp, err := server.PutPop3Settings(tt.ctx(server), tt.request)
+
+			if tt.expectedCode == codes.OK {
+				assert.NoError(t, err)
+				assert.Empty(t, resp.Settings.ApopSecret)
+			} else {
+				st, ok := status.FromError(err)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedCode, st.Code())
+			}
+			mockPOP3.AssertExpectations(t)
+		})
+	}
+}
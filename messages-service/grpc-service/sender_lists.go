@@ -0,0 +1,202 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	"2025_2_a4code/internal/lib/metrics"
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type SenderListUsecase interface {
+	GetSenderLists(ctx context.Context, profileID int64) (domain.SenderLists, error)
+	AddRule(ctx context.Context, profileID int64, kind domain.SenderListKind, pattern string) (domain.SenderRule, error)
+	DeleteRule(ctx context.Context, profileID, ruleID int64) error
+	SetBlockedAction(ctx context.Context, profileID int64, action domain.BlockedSenderAction) error
+	BlockSender(ctx context.Context, profileID int64, address string, wholeDomain bool) (domain.SenderRule, error)
+}
+
+func (s *Server) GetSenderLists(ctx context.Context, req *pb.GetSenderListsRequest) (*pb.GetSenderListsResponse, error) {
+	const op = "messagesservice.GetSenderLists"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/get-sender-lists")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	lists, err := s.senderListUCase.GetSenderLists(ctx, profileID)
+	if err != nil {
+		log.Error(op + ": failed to get sender lists: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_sender_lists", "error").Inc()
+		return nil, status.Error(codes.Internal, "could not get sender lists")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_sender_lists", "ok").Inc()
+	return &pb.GetSenderListsResponse{
+		Blocked:       domainSenderRulesToProto(lists.Blocked),
+		Allowed:       domainSenderRulesToProto(lists.Allowed),
+		BlockedAction: string(lists.BlockedAction),
+	}, nil
+}
+
+func (s *Server) AddSenderRule(ctx context.Context, req *pb.AddSenderRuleRequest) (*pb.AddSenderRuleResponse, error) {
+	const op = "messagesservice.AddSenderRule"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/add-sender-rule")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	rule, err := s.senderListUCase.AddRule(ctx, profileID, domain.SenderListKind(req.Kind), req.Pattern)
+	if err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "add_sender_rule", "error").Inc()
+		if st, ok := senderListStatus(err); ok {
+			return nil, st
+		}
+		log.Error(op + ": failed to add sender rule: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not add sender rule")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "add_sender_rule", "ok").Inc()
+	return &pb.AddSenderRuleResponse{Rule: domainSenderRuleToProto(rule)}, nil
+}
+
+func (s *Server) DeleteSenderRule(ctx context.Context, req *pb.DeleteSenderRuleRequest) (*pb.DeleteSenderRuleResponse, error) {
+	const op = "messagesservice.DeleteSenderRule"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/delete-sender-rule")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	ruleID, err := strconv.ParseInt(req.RuleId, 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid rule id")
+	}
+
+	if err := s.senderListUCase.DeleteRule(ctx, profileID, ruleID); err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "delete_sender_rule", "error").Inc()
+		if st, ok := senderListStatus(err); ok {
+			return nil, st
+		}
+		log.Error(op + ": failed to delete sender rule: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not delete sender rule")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "delete_sender_rule", "ok").Inc()
+	return &pb.DeleteSenderRuleResponse{}, nil
+}
+
+func (s *Server) SetBlockedSenderAction(ctx context.Context, req *pb.SetBlockedSenderActionRequest) (*pb.SetBlockedSenderActionResponse, error) {
+	const op = "messagesservice.SetBlockedSenderAction"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/set-blocked-sender-action")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	if err := s.senderListUCase.SetBlockedAction(ctx, profileID, domain.BlockedSenderAction(req.Action)); err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "set_blocked_sender_action", "error").Inc()
+		if st, ok := senderListStatus(err); ok {
+			return nil, st
+		}
+		log.Error(op + ": failed to set blocked sender action: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not set blocked sender action")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "set_blocked_sender_action", "ok").Inc()
+	return &pb.SetBlockedSenderActionResponse{}, nil
+}
+
+// BlockSender блокирует отправителя письма, открытого пользователем
+func (s *Server) BlockSender(ctx context.Context, req *pb.BlockSenderRequest) (*pb.BlockSenderResponse, error) {
+	const op = "messagesservice.BlockSender"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/block-sender")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	messageID, err := strconv.ParseInt(req.MessageId, 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid message id")
+	}
+
+	ok, err := s.messageUCase.IsUsersMessage(ctx, messageID, profileID)
+	if err != nil {
+		log.Error(op + ": failed to check if it is users message: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "block_sender", "error").Inc()
+		return nil, status.Error(codes.Internal, "could not block sender")
+	}
+	if !ok {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "block_sender", "error").Inc()
+		return nil, status.Error(codes.PermissionDenied, "access denied")
+	}
+
+	message, err := s.messageUCase.FindFullByMessageID(ctx, messageID, profileID)
+	if err != nil {
+		log.Error(op + ": failed to get message: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "block_sender", "error").Inc()
+		return nil, status.Error(codes.Internal, "could not block sender")
+	}
+
+	rule, err := s.senderListUCase.BlockSender(ctx, profileID, message.Sender.Email, req.WholeDomain)
+	if err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "block_sender", "error").Inc()
+		if st, ok := senderListStatus(err); ok {
+			return nil, st
+		}
+		log.Error(op + ": failed to block sender: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not block sender")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "block_sender", "ok").Inc()
+	return &pb.BlockSenderResponse{Rule: domainSenderRuleToProto(rule)}, nil
+}
+
+// senderListStatus переводит ожидаемые ошибки списков отправителей в gRPC-статус
+func senderListStatus(err error) (error, bool) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidSenderRule):
+		return status.Error(codes.InvalidArgument, err.Error()), true
+	case errors.Is(err, domain.ErrSenderRuleNotFound):
+		return status.Error(codes.NotFound, "sender rule not found"), true
+	case errors.Is(err, domain.ErrTooManySenderRules):
+		return status.Error(codes.InvalidArgument, "too many sender rules"), true
+	}
+	return nil, false
+}
+
+func domainSenderRuleToProto(rule domain.SenderRule) *pb.SenderRule {
+	return &pb.SenderRule{
+		RuleId:    strconv.FormatInt(rule.ID, 10),
+		Kind:      string(rule.Kind),
+		Pattern:   rule.Pattern,
+		CreatedAt: rule.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func domainSenderRulesToProto(rules []domain.SenderRule) []*pb.SenderRule {
+	result := make([]*pb.SenderRule, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, domainSenderRuleToProto(rule))
+	}
+	return result
+}
@@ -0,0 +1,207 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type MockSenderListUsecase struct {
+	mock.Mock
+}
+
+func (m *MockSenderListUsecase) GetSenderLists(ctx context.Context, profileID int64) (domain.SenderLists, error) {
+	args := m.Called(ctx, profileID)
+	return args.Get(0).(domain.SenderLists), args.Error(1)
+}
+
+func (m *MockSenderListUsecase) AddRule(ctx context.Context, profileID int64, kind domain.SenderListKind, pattern string) (domain.SenderRule, error) {
+	args := m.Called(ctx, profileID, kind, pattern)
+	return args.Get(0).(domain.SenderRule), args.Error(1)
+}
+
+func (m *MockSenderListUsecase) DeleteRule(ctx context.Context, profileID, ruleID int64) error {
+	args := m.Called(ctx, profileID, ruleID)
+	return args.Error(0)
+}
+
+func (m *MockSenderListUsecase) SetBlockedAction(ctx context.Context, profileID int64, action domain.BlockedSenderAction) error {
+	args := m.Called(ctx, profileID, action)
+	return args.Error(0)
+}
+
+func (m *MockSenderListUsecase) BlockSender(ctx context.Context, profileID int64, address string, wholeDomain bool) (domain.SenderRule, error) {
+	args := m.Called(ctx, profileID, address, wholeDomain)
+	return args.Get(0).(domain.SenderRule), args.Error(1)
+}
+
+func setupSenderListTestServer() (*Server, *MockMessageUsecase, *MockSenderListUsecase) {
+	mockMessageUsecase := &MockMessageUsecase{}
+	mockSenderListUsecase := &MockSenderListUsecase{}
+	jwtSecret := []byte("test-secret-key-very-long-for-testing")
+	server := New(Deps{
+		MessageUCase:    mockMessageUsecase,
+		SenderListUCase: mockSenderListUsecase,
+	}, jwtSecret)
+	return server, mockMessageUsecase, mockSenderListUsecase
+}
+
+func TestServer_GetSenderLists(t *testing.T) {
+	server, _, mockSenderList := setupSenderListTestServer()
+	mockSenderList.On("GetSenderLists", mock.Anything, int64(1)).Return(domain.SenderLists{
+		Blocked:       []domain.SenderRule{{ID: 2, Kind: domain.SenderBlocked, Pattern: "spam.com"}},
+		Allowed:       []domain.SenderRule{},
+		BlockedAction: domain.BlockedDiscard,
+	}, nil)
+
+	resp, err := server.GetSenderLists(createTestContextWithToken(1, server.JWTSecret), &pb.GetSenderListsRequest{})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Blocked, 1)
+	assert.Equal(t, "spam.com", resp.Blocked[0].Pattern)
+	assert.Empty(t, resp.Allowed)
+	assert.Equal(t, "discard", resp.BlockedAction)
+	mockSenderList.AssertExpectations(t)
+}
+
+func TestServer_AddSenderRule(t *testing.T) {
+	tests := []struct {
+		name         string
+		ctx          func(server *Server) context.Context
+		mockSetup    func(mockSenderList *MockSenderListUsecase)
+		expectedCode codes.Code
+	}{
+		{
+			name: "Success",
+			ctx:  func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			mockSetup: func(mockSenderList *MockSenderListUsecase) {
+				mockSenderList.On("AddRule", mock.Anything, int64(1), domain.SenderBlocked, "spam.com").
+					Return(domain.SenderRule{ID: 2, Kind: domain.SenderBlocked, Pattern: "spam.com"}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Unauthorized",
+			ctx:          func(server *Server) context.Context { return createTestContextWithoutAuth() },
+			mockSetup:    func(mockSenderList *MockSenderListUsecase) {},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name: "InvalidPattern",
+			ctx:  func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			mockSetup: func(mockSenderList *MockSenderListUsecase) {
+				mockSenderList.On("AddRule", mock.Anything, int64(1), domain.SenderBlocked, "spam.com").
+					Return(domain.SenderRule{}, fmt.Errorf("%w: invalid domain", domain.ErrInvalidSenderRule))
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "LimitReached",
+			ctx:  func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			mockSetup: func(mockSenderList *MockSenderListUsecase) {
+				mockSenderList.On("AddRule", mock.Anything, int64(1), domain.SenderBlocked, "spam.com").
+					Return(domain.SenderRule{}, domain.ErrTooManySenderRules)
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "InternalError",
+			ctx:  func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			mockSetup: func(mockSenderList *MockSenderListUsecase) {
+				mockSenderList.On("AddRule", mock.Anything, int64(1), domain.SenderBlocked, "spam.com").
+					Return(domain.SenderRule{}, errors.New("db down"))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _, mockSenderList := setupSenderListTestServer()
+			tt.mockSetup(mockSenderList)
+
+			resp, err := server.AddSenderRule(tt.ctx(server), &pb.AddSenderRuleRequest{Kind: "block", Pattern: "spam.com"})
+
+			if tt.expectedCode == codes.OK {
+				assert.NoError(t, err)
+				assert.Equal(t, "2", resp.Rule.RuleId)
+			} else {
+				assert.Equal(t, tt.expectedCode, status.Code(err))
+			}
+			mockSenderList.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_DeleteSenderRule(t *testing.T) {
+	t.Run("InvalidID", func(t *testing.T) {
+		server, _, _ := setupSenderListTestServer()
+
+		_, err := server.DeleteSenderRule(createTestContextWithToken(1, server.JWTSecret), &pb.DeleteSenderRuleRequest{RuleId: "x"})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		server, _, mockSenderList := setupSenderListTestServer()
+		mockSenderList.On("DeleteRule", mock.Anything, int64(1), int64(2)).Return(domain.ErrSenderRuleNotFound)
+
+		_, err := server.DeleteSenderRule(createTestContextWithToken(1, server.JWTSecret), &pb.DeleteSenderRuleRequest{RuleId: "2"})
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestServer_SetBlockedSenderAction(t *testing.T) {
+	server, _, mockSenderList := setupSenderListTestServer()
+	mockSenderList.On("SetBlockedAction", mock.Anything, int64(1), domain.BlockedDiscard).Return(nil)
+
+	_, err := server.SetBlockedSenderAction(createTestContextWithToken(1, server.JWTSecret), &pb.SetBlockedSenderActionRequest{Action: "discard"})
+
+	assert.NoError(t, err)
+	mockSenderList.AssertExpectations(t)
+}
+
+func TestServer_BlockSender(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		server, mockMessage, mockSenderList := setupSenderListTestServer()
+		mockMessage.On("IsUsersMessage", mock.Anything, int64(5), int64(1)).Return(true, nil)
+		mockMessage.On("FindFullByMessageID", mock.Anything, int64(5), int64(1)).
+			Return(domain.FullMessage{Sender: domain.Sender{Email: "troll@spam.com"}}, nil)
+		mockSenderList.On("BlockSender", mock.Anything, int64(1), "troll@spam.com", true).
+			Return(domain.SenderRule{ID: 2, Kind: domain.SenderBlocked, Pattern: "spam.com"}, nil)
+
+		resp, err := server.BlockSender(createTestContextWithToken(1, server.JWTSecret), &pb.BlockSenderRequest{MessageId: "5", WholeDomain: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "spam.com", resp.Rule.Pattern)
+		mockMessage.AssertExpectations(t)
+		mockSenderList.AssertExpectations(t)
+	})
+
+	t.Run("AccessDenied", func(t *testing.T) {
+		server, mockMessage, _ := setupSenderListTestServer()
+		mockMessage.On("IsUsersMessage", mock.Anything, int64(5), int64(1)).Return(false, nil)
+
+		_, err := server.BlockSender(createTestContextWithToken(1, server.JWTSecret), &pb.BlockSenderRequest{MessageId: "5"})
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("InvalidMessageID", func(t *testing.T) {
+		server, _, _ := setupSenderListTestServer()
+
+		_, err := server.BlockSender(createTestContextWithToken(1, server.JWTSecret), &pb.BlockSenderRequest{MessageId: "abc"})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
@@ -0,0 +1,109 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	"2025_2_a4code/internal/lib/metrics"
+	sieveUcase "2025_2_a4code/internal/usecase/sieve"
+	"context"
+	"errors"
+	"strings"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type SieveUsecase interface {
+	PutScript(ctx context.Context, profileID int64, script string, folders []domain.Folder) error
+	GetScript(ctx context.Context, profileID int64) (string, error)
+	CheckScript(ctx context.Context, script string, folders []domain.Folder) ([]string, error)
+}
+
+func (s *Server) PutSieveScript(ctx context.Context, req *pb.PutSieveScriptRequest) (*pb.PutSieveScriptResponse, error) {
+	const op = "messagesservice.PutSieveScript"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/put-sieve-script")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	folders, err := s.messageUCase.GetUserFolders(ctx, profileID)
+	if err != nil {
+		log.Error(op + ": failed to get user folders: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "put_sieve_script", "error").Inc()
+		return nil, status.Error(codes.Internal, "could not save sieve script")
+	}
+
+	if err := s.sieveUCase.PutScript(ctx, profileID, req.Script, folders); err != nil {
+		if errors.Is(err, sieveUcase.ErrInvalidScript) {
+			metrics.MessagesOperationsTotal.WithLabelValues("messages", "put_sieve_script", "error").Inc()
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		log.Error(op + ": failed to save sieve script: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "put_sieve_script", "error").Inc()
+		return nil, status.Error(codes.Internal, "could not save sieve script")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "put_sieve_script", "ok").Inc()
+	return &pb.PutSieveScriptResponse{}, nil
+}
+
+func (s *Server) GetSieveScript(ctx context.Context, req *pb.GetSieveScriptRequest) (*pb.GetSieveScriptResponse, error) {
+	const op = "messagesservice.GetSieveScript"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/get-sieve-script")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	script, err := s.sieveUCase.GetScript(ctx, profileID)
+	if err != nil {
+		log.Error(op + ": failed to get sieve script: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_sieve_script", "error").Inc()
+		return nil, status.Error(codes.Internal, "could not get sieve script")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "get_sieve_script", "ok").Inc()
+	return &pb.GetSieveScriptResponse{Script: script}, nil
+}
+
+func (s *Server) CheckSieveScript(ctx context.Context, req *pb.CheckSieveScriptRequest) (*pb.CheckSieveScriptResponse, error) {
+	const op = "messagesservice.CheckSieveScript"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/check-sieve-script")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	folders, err := s.messageUCase.GetUserFolders(ctx, profileID)
+	if err != nil {
+		log.Error(op + ": failed to get user folders: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "check_sieve_script", "error").Inc()
+		return nil, status.Error(codes.Internal, "could not check sieve script")
+	}
+
+	warnings, err := s.sieveUCase.CheckScript(ctx, req.Script, folders)
+	if err != nil {
+		if errors.Is(err, sieveUcase.ErrInvalidScript) {
+			metrics.MessagesOperationsTotal.WithLabelValues("messages", "check_sieve_script", "ok").Inc()
+			return &pb.CheckSieveScriptResponse{
+				Valid: false,
+				Error: strings.TrimPrefix(err.Error(), sieveUcase.ErrInvalidScript.Error()+": "),
+			}, nil
+		}
+		log.Error(op + ": failed to check sieve script: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "check_sieve_script", "error").Inc()
+		return nil, status.Error(codes.Internal, "could not check sieve script")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "check_sieve_script", "ok").Inc()
+	return &pb.CheckSieveScriptResponse{Valid: true, Warnings: warnings}, nil
+}
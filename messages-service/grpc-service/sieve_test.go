@@ -0,0 +1,166 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	sieveUcase "2025_2_a4code/internal/usecase/sieve"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type MockSieveUsecase struct {
+	mock.Mock
+}
+
+func (m *MockSieveUsecase) PutScript(ctx context.Context, profileID int64, script string, folders []domain.Folder) error {
+	args := m.Called(ctx, profileID, script, folders)
+	return args.Error(0)
+}
+
+func (m *MockSieveUsecase) GetScript(ctx context.Context, profileID int64) (string, error) {
+	args := m.Called(ctx, profileID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockSieveUsecase) CheckScript(ctx context.Context, script string, folders []domain.Folder) ([]string, error) {
+	args := m.Called(ctx, script, folders)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func setupSieveTestServer() (*Server, *MockMessageUsecase, *MockSieveUsecase) {
+	mockMessageUsecase := &MockMessageUsecase{}
+	mockSieveUsecase := &MockSieveUsecase{}
+	jwtSecret := []byte("test-secret-key-very-long-for-testing")
+	server := New(Deps{
+		MessageUCase: mockMessageUsecase,
+		SieveUCase:   mockSieveUsecase,
+	}, jwtSecret)
+	return server, mockMessageUsecase, mockSieveUsecase
+}
+
+func TestServer_PutSieveScript(t *testing.T) {
+	folders := []domain.Folder{{ID: 1, Name: "Входящие", Type: domain.FolderInbox}}
+
+	tests := []struct {
+		name         string
+		ctx          func(server *Server) context.Context
+		mockSetup    func(mockMessage *MockMessageUsecase, mockSieve *MockSieveUsecase)
+		expectedCode codes.Code
+	}{
+		{
+			name: "Success",
+			ctx:  func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			mockSetup: func(mockMessage *MockMessageUsecase, mockSieve *MockSieveUsecase) {
+				mockMessage.On("GetUserFolders", mock.Anything, int64(1)).Return(folders, nil)
+				mockSieve.On("PutScript", mock.Anything, int64(1), "keep;", folders).Return(nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Unauthorized",
+			ctx:          func(server *Server) context.Context { return createTestContextWithoutAuth() },
+			mockSetup:    func(mockMessage *MockMessageUsecase, mockSieve *MockSieveUsecase) {},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name: "InvalidScript",
+			ctx:  func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			mockSetup: func(mockMessage *MockMessageUsecase, mockSieve *MockSieveUsecase) {
+				mockMessage.On("GetUserFolders", mock.Anything, int64(1)).Return(folders, nil)
+				mockSieve.On("PutScript", mock.Anything, int64(1), "keep;", folders).
+					Return(fmt.Errorf("%w: line 1: unknown command", sieveUcase.ErrInvalidScript))
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "FoldersError",
+			ctx:  func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			mockSetup: func(mockMessage *MockMessageUsecase, mockSieve *MockSieveUsecase) {
+				mockMessage.On("GetUserFolders", mock.Anything, int64(1)).Return([]domain.Folder(nil), errors.New("db error"))
+			},
+			expectedCode: codes.Internal,
+		},
+		{
+			name: "InternalError",
+			ctx:  func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			mockSetup: func(mockMessage *MockMessageUsecase, mockSieve *MockSieveUsecase) {
+				mockMessage.On("GetUserFolders", mock.Anything, int64(1)).Return(folders, nil)
+				mockSieve.On("PutScript", mock.Anything, int64(1), "keep;", folders).Return(errors.New("database error"))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, mockMessage, mockSieve := setupSieveTestServer()
+			tt.mockSetup(mockMessage, mockSieve)
+
+			_, err := server.PutSieveScript(tt.ctx(server), &pb.PutSieveScriptRequest{Script: "keep;"})
+
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			mockMessage.AssertExpectations(t)
+			mockSieve.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_GetSieveScript(t *testing.T) {
+	server, _, mockSieve := setupSieveTestServer()
+	mockSieve.On("GetScript", mock.Anything, int64(1)).Return("discard;", nil)
+
+	resp, err := server.GetSieveScript(createTestContextWithToken(1, server.JWTSecret), &pb.GetSieveScriptRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "discard;", resp.Script)
+	mockSieve.AssertExpectations(t)
+}
+
+func TestServer_CheckSieveScript(t *testing.T) {
+	folders := []domain.Folder{{ID: 1, Name: "Входящие", Type: domain.FolderInbox}}
+
+	t.Run("ValidWithWarnings", func(t *testing.T) {
+		server, mockMessage, mockSieve := setupSieveTestServer()
+		mockMessage.On("GetUserFolders", mock.Anything, int64(1)).Return(folders, nil)
+		mockSieve.On("CheckScript", mock.Anything, "script", folders).Return([]string{"folder \"Work\" does not exist"}, nil)
+
+		resp, err := server.CheckSieveScript(createTestContextWithToken(1, server.JWTSecret), &pb.CheckSieveScriptRequest{Script: "script"})
+
+		assert.NoError(t, err)
+		assert.True(t, resp.Valid)
+		assert.Len(t, resp.Warnings, 1)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		server, mockMessage, mockSieve := setupSieveTestServer()
+		mockMessage.On("GetUserFolders", mock.Anything, int64(1)).Return(folders, nil)
+		mockSieve.On("CheckScript", mock.Anything, "script", folders).
+			Return(nil, fmt.Errorf("%w: line 2: unknown command", sieveUcase.ErrInvalidScript))
+
+		resp, err := server.CheckSieveScript(createTestContextWithToken(1, server.JWTSecret), &pb.CheckSieveScriptRequest{Script: "script"})
+
+		assert.NoError(t, err)
+		assert.False(t, resp.Valid)
+		assert.Equal(t, "line 2: unknown command", resp.Error)
+	})
+
+	t.Run("FoldersError", func(t *testing.T) {
+		server, mockMessage, _ := setupSieveTestServer()
+		mockMessage.On("GetUserFolders", mock.Anything, int64(1)).Return([]domain.Folder(nil), errors.New("db error"))
+
+		_, err := server.CheckSieveScript(createTestContextWithToken(1, server.JWTSecret), &pb.CheckSieveScriptRequest{Script: "script"})
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
@@ -0,0 +1,185 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type MockSpamUsecase struct {
+	mock.Mock
+}
+
+func (m *MockSpamUsecase) Train(ctx context.Context, profileID, messageID int64, spam bool) error {
+	args := m.Called(ctx, profileID, messageID, spam)
+	return args.Error(0)
+}
+
+func setupSpamTestServer() (*Server, *MockMessageUsecase, *MockSpamUsecase) {
+	mockMessageUsecase := &MockMessageUsecase{}
+	mockSpamUsecase := &MockSpamUsecase{}
+	jwtSecret := []byte("test-secret-key-very-long-for-testing")
+	server := New(Deps{
+		MessageUCase: mockMessageUsecase,
+		SpamUCase:    mockSpamUsecase,
+	}, jwtSecret)
+	return server, mockMessageUsecase, mockSpamUsecase
+}
+
+func TestServer_MarkAsSpam_TrainsFilter(t *testing.T) {
+	server, mockMessage, mockSpam := setupSpamTestServer()
+
+	mockMessage.On("IsUsersMessage", mock.Anything, int64(123), int64(1)).Return(true, nil)
+	mockMessage.On("MarkMessageAsSpam", mock.Anything, int64(123), int64(1)).Return(nil)
+	mockSpam.On("Train", mock.Anything, int64(1), int64(123), true).Return(errors.New("db error"))
+
+	resp, err := server.MarkAsSpam(createTestContextWithToken(1, server.JWTSecret), &pb.MarkAsSpamRequest{MessageId: "123"})
+
+	assert.NoError(t, err, "training error must not fail the request")
+	assert.NotNil(t, resp)
+	mockMessage.AssertExpectations(t)
+	mockSpam.AssertExpectations(t)
+}
+
+func TestServer_MarkAsNotSpam(t *testing.T) {
+	tests := []struct {
+		name         string
+		ctx          func(server *Server) context.Context
+		request      *pb.MarkAsNotSpamRequest
+		mockSetup    func(mockMessage *MockMessageUsecase, mockSpam *MockSpamUsecase)
+		expectedCode codes.Code
+	}{
+		{
+			name:    "Success",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.MarkAsNotSpamRequest{MessageId: "123"},
+			mockSetup: func(mockMessage *MockMessageUsecase, mockSpam *MockSpamUsecase) {
+				mockMessage.On("IsUsersMessage", mock.Anything, int64(123), int64(1)).Return(true, nil)
+				mockMessage.On("MarkMessageAsNotSpam", mock.Anything, int64(123), int64(1)).Return(nil)
+				mockSpam.On("Train", mock.Anything, int64(1), int64(123), false).Return(nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:    "ForeignMessage",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.MarkAsNotSpamRequest{MessageId: "123"},
+			mockSetup: func(mockMessage *MockMessageUsecase, mockSpam *MockSpamUsecase) {
+				mockMessage.On("IsUsersMessage", mock.Anything, int64(123), int64(1)).Return(false, nil)
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:    "NotInSpam",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.MarkAsNotSpamRequest{MessageId: "123"},
+			mockSetup: func(mockMessage *MockMessageUsecase, mockSpam *MockSpamUsecase) {
+				mockMessage.On("IsUsersMessage", mock.Anything, int64(123), int64(1)).Return(true, nil)
+				mockMessage.On("MarkMessageAsNotSpam", mock.Anything, int64(123), int64(1)).Return(domain.ErrMessageNotFound)
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name:         "Unauthorized",
+			ctx:          func(server *Server) context.Context { return createTestContextWithoutAuth() },
+			request:      &pb.MarkAsNotSpamRequest{MessageId: "123"},
+			mockSetup:    func(mockMessage *MockMessageUsecase, mockSpam *MockSpamUsecase) {},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "InvalidMessageID",
+			ctx:          func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request:      &pb.MarkAsNotSpamRequest{MessageId: "invalid"},
+			mockSetup:    func(mockMessage *MockMessageUsecase, mockSpam *MockSpamUsecase) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "MoveError",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.MarkAsNotSpamRequest{MessageId: "123"},
+			mockSetup: func(mockMessage *MockMessageUsecase, mockSpam *MockSpamUsecase) {
+				mockMessage.On("IsUsersMessage", mock.Anything, int64(123), int64(1)).Return(true, nil)
+				mockMessage.On("MarkMessageAsNotSpam", mock.Anything, int64(123), int64(1)).Return(errors.New("database error"))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, mockMessage, mockSpam := setupSpamTestServer()
+			tt.mockSetup(mockMessage, mockSpam)
+
+			resp, err := server.MarkAsNotSpam(tt.ctx(server), tt.request)
+
+			if tt.expectedCode == codes.OK {
+				assert.NoError(t, err)
+				assert.NotNil(t, resp)
+			} else {
+				st, ok := status.FromError(err)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedCode, st.Code())
+			}
+			mockMessage.AssertExpectations(t)
+			mockSpam.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_MessagePage_SpamScore(t *testing.T) {
+	server, mockMessage, _ := setupSpamTestServer()
+
+	mockMessage.On("IsUsersMessage", mock.Anything, int64(123), int64(1)).Return(true, nil)
+	mockMessage.On("FindFullByMessageID", mock.Anything, int64(123), int64(1)).Return(domain.FullMessage{
+		ID:          "123",
+		Topic:       "Cheap pills",
+		Datetime:    time.Now(),
+		SpamScore:   0.97,
+		SpamReasons: []string{`"pills": 0.99`},
+	}, nil)
+	mockMessage.On("ShouldMarkAsRead", mock.Anything, mock.Anything, mock.Anything).Return(false, nil).Maybe()
+	mockMessage.On("MarkMessageAsRead", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	resp, err := server.MessagePage(createTestContextWithToken(1, server.JWTSecret), &pb.MessagePageRequest{MessageId: "123"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "0.9700", resp.Message.SpamScore)
+	assert.Equal(t, []string{`"pills": 0.99`}, resp.Message.SpamReasons)
+}
+
+func TestServer_MessagePage_Authentication(t *testing.T) {
+	server, mockMessage, _ := setupSpamTestServer()
+
+	mockMessage.On("IsUsersMessage", mock.Anything, int64(123), int64(1)).Return(true, nil)
+	mockMessage.On("FindFullByMessageID", mock.Anything, int64(123), int64(1)).Return(domain.FullMessage{
+		ID:       "123",
+		Topic:    "Ваш счет заблокирован",
+		Datetime: time.Now(),
+		DKIM:     domain.DKIMResult{Status: domain.DKIMNone},
+		SPF:      domain.SPFResult{Status: domain.SPFFail, Domain: "bank.com"},
+		DMARC:    domain.DMARCResult{Status: domain.DMARCFail, Policy: domain.DMARCPolicyReject},
+		AuthenticationResults: "mx.flintmail.ru; dkim=none; spf=fail smtp.mailfrom=support@bank.com; " +
+			"dmarc=fail (p=reject) header.from=bank.com",
+	}, nil)
+	mockMessage.On("ShouldMarkAsRead", mock.Anything, mock.Anything, mock.Anything).Return(false, nil).Maybe()
+	mockMessage.On("MarkMessageAsRead", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	resp, err := server.MessagePage(createTestContextWithToken(1, server.JWTSecret), &pb.MessagePageRequest{MessageId: "123"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "fail", resp.Message.SpfResult)
+	assert.Equal(t, "bank.com", resp.Message.SpfDomain)
+	assert.Equal(t, "fail", resp.Message.DmarcResult)
+	assert.Equal(t, "reject", resp.Message.DmarcPolicy)
+	assert.Contains(t, resp.Message.AuthenticationResults, "dmarc=fail (p=reject)")
+	assert.True(t, resp.Message.UnverifiedSender)
+}
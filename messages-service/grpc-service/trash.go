@@ -0,0 +1,93 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	"2025_2_a4code/internal/lib/metrics"
+	"context"
+	"errors"
+	"strconv"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) RestoreFromTrash(ctx context.Context, req *pb.RestoreFromTrashRequest) (*pb.RestoreFromTrashResponse, error) {
+	const op = "messagesservice.RestoreFromTrash"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/restore-from-trash")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	messageID, err := strconv.ParseInt(req.MessageId, 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid message id")
+	}
+
+	folderID, err := s.messageUCase.RestoreFromTrash(ctx, profileID, messageID)
+	if err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "restore_from_trash", "error").Inc()
+		if errors.Is(err, domain.ErrMessageNotInTrash) {
+			return nil, status.Error(codes.NotFound, "message is not in trash")
+		}
+		log.Error(op + ": failed to restore message: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not restore message")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "restore_from_trash", "ok").Inc()
+	return &pb.RestoreFromTrashResponse{FolderId: strconv.FormatInt(folderID, 10)}, nil
+}
+
+func (s *Server) EmptyTrash(ctx context.Context, req *pb.EmptyTrashRequest) (*pb.EmptyTrashResponse, error) {
+	const op = "messagesservice.EmptyTrash"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/empty-trash")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	deleted, err := s.messageUCase.EmptyTrash(ctx, profileID)
+	if err != nil {
+		log.Error(op + ": failed to empty trash: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "empty_trash", "error").Inc()
+		return nil, status.Error(codes.Internal, "could not empty trash")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "empty_trash", "ok").Inc()
+	return &pb.EmptyTrashResponse{DeletedCount: deleted}, nil
+}
+
+func (s *Server) DeletePermanently(ctx context.Context, req *pb.DeletePermanentlyRequest) (*pb.DeletePermanentlyResponse, error) {
+	const op = "messagesservice.DeletePermanently"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle messages/delete-permanently")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	messageID, err := strconv.ParseInt(req.MessageId, 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid message id")
+	}
+
+	if err := s.messageUCase.DeletePermanently(ctx, profileID, messageID); err != nil {
+		metrics.MessagesOperationsTotal.WithLabelValues("messages", "delete_permanently", "error").Inc()
+		if errors.Is(err, domain.ErrMessageNotFound) {
+			return nil, status.Error(codes.NotFound, "message not found")
+		}
+		log.Error(op + ": failed to delete message: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not delete message")
+	}
+
+	metrics.MessagesOperationsTotal.WithLabelValues("messages", "delete_permanently", "ok").Inc()
+	return &pb.DeletePermanentlyResponse{}, nil
+}
@@ -0,0 +1,124 @@
+package messages_service
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"testing"
+
+	pb "2025_2_a4code/messages-service/pkg/messagesproto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServer_RestoreFromTrash(t *testing.T) {
+	tests := []struct {
+		name         string
+		ctx          func(server *Server) context.Context
+		request      *pb.RestoreFromTrashRequest
+		mockSetup    func(mockMessage *MockMessageUsecase)
+		expectedCode codes.Code
+		expectedID   string
+	}{
+		{
+			name:    "Success",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.RestoreFromTrashRequest{MessageId: "123"},
+			mockSetup: func(mockMessage *MockMessageUsecase) {
+				mockMessage.On("RestoreFromTrash", mock.Anything, int64(1), int64(123)).Return(int64(10), nil)
+			},
+			expectedCode: codes.OK,
+			expectedID:   "10",
+		},
+		{
+			name:         "Unauthorized",
+			ctx:          func(server *Server) context.Context { return createTestContextWithoutAuth() },
+			request:      &pb.RestoreFromTrashRequest{MessageId: "123"},
+			mockSetup:    func(mockMessage *MockMessageUsecase) {},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "InvalidMessageID",
+			ctx:          func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request:      &pb.RestoreFromTrashRequest{MessageId: "abc"},
+			mockSetup:    func(mockMessage *MockMessageUsecase) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "NotInTrash",
+			ctx:     func(server *Server) context.Context { return createTestContextWithToken(1, server.JWTSecret) },
+			request: &pb.RestoreFromTrashRequest{MessageId: "123"},
+			mockSetup: func(mockMessage *MockMessageUsecase) {
+				mockMessage.On("RestoreFromTrash", mock.Anything, int64(1), int64(123)).Return(int64(0), domain.ErrMessageNotInTrash)
+			},
+			expectedCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, mockMessage, _ := setupTestServer()
+			tt.mockSetup(mockMessage)
+
+			resp, err := server.RestoreFromTrash(tt.ctx(server), tt.request)
+
+			if tt.expectedCode == codes.OK {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedID, resp.FolderId)
+			} else {
+				st, ok := status.FromError(err)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedCode, st.Code())
+			}
+			mockMessage.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_EmptyTrash(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		server, mockMessage, _ := setupTestServer()
+		mockMessage.On("EmptyTrash", mock.Anything, int64(1)).Return(int64(7), nil)
+
+		resp, err := server.EmptyTrash(createTestContextWithToken(1, server.JWTSecret), &pb.EmptyTrashRequest{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), resp.DeletedCount)
+		mockMessage.AssertExpectations(t)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		server, mockMessage, _ := setupTestServer()
+		mockMessage.On("EmptyTrash", mock.Anything, int64(1)).Return(int64(0), errors.New("db error"))
+
+		_, err := server.EmptyTrash(createTestContextWithToken(1, server.JWTSecret), &pb.EmptyTrashRequest{})
+
+		st, _ := status.FromError(err)
+		assert.Equal(t, codes.Internal, st.Code())
+	})
+}
+
+func TestServer_DeletePermanently(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		server, mockMessage, _ := setupTestServer()
+		mockMessage.On("DeletePermanently", mock.Anything, int64(1), int64(123)).Return(nil)
+
+		_, err := server.DeletePermanently(createTestContextWithToken(1, server.JWTSecret), &pb.DeletePermanentlyRequest{MessageId: "123"})
+
+		assert.NoError(t, err)
+		mockMessage.AssertExpectations(t)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		server, mockMessage, _ := setupTestServer()
+		mockMessage.On("DeletePermanently", mock.Anything, int64(1), int64(123)).Return(domain.ErrMessageNotFound)
+
+		_, err := server.DeletePermanently(createTestContextWithToken(1, server.JWTSecret), &pb.DeletePermanentlyRequest{MessageId: "123"})
+
+		st, _ := status.FromError(err)
+		assert.Equal(t, codes.NotFound, st.Code())
+	})
+}
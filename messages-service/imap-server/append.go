@@ -0,0 +1,97 @@
+package imap_server
+
+import (
+	"2025_2_a4code/internal/domain"
+	"bytes"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+// handleAppend принимает копию письма, которую клиент кладет в "Отправленные" после
+// отправки через сервер отправки. Копия там уже есть, поэтому письмо не сохраняется второй раз,
+// а флаги из команды переносятся на существующую копию. Другие письма APPEND не принимает
+func (s *session) handleAppend(cmd *command) string {
+	if len(cmd.args) < 2 || len(cmd.args) > 4 {
+		return s.reply(cmd.tag, "BAD", "Syntax: APPEND mailbox [flags] [date-time] message")
+	}
+	name, ok := mailboxName(cmd.args[0])
+	if !ok {
+		return s.reply(cmd.tag, "BAD", "Invalid mailbox name")
+	}
+
+	literal := cmd.args[len(cmd.args)-1]
+	if literal.kind != stringValue {
+		return s.reply(cmd.tag, "BAD", "Message literal expected")
+	}
+	var flags []value
+	for _, arg := range cmd.args[1 : len(cmd.args)-1] {
+		switch {
+		case arg.kind == listValue && flags == nil:
+			flags = arg.list
+		case arg.kind == stringValue:
+			if _, err := parseDateTime(arg.text); err != nil {
+				return s.reply(cmd.tag, "BAD", "Invalid date-time")
+			}
+		default:
+			return s.reply(cmd.tag, "BAD", "Syntax: APPEND mailbox [flags] [date-time] message")
+		}
+	}
+	update := domain.FlagsUpdate{}
+	for _, flag := range flags {
+		if flag.kind != atomValue {
+			return s.reply(cmd.tag, "BAD", "Invalid flag")
+		}
+		set := true
+		switch strings.ToLower(flag.text) {
+		case `\seen`:
+			update.Read = &set
+		case `\flagged`:
+			update.Starred = &set
+		case `\answered`:
+			update.Answered = &set
+		case `$forwarded`:
+			update.Forwarded = &set
+		}
+	}
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	target, err := s.findMailbox(ctx, name)
+	if err != nil {
+		return s.fail(cmd, "failed to find mailbox", err)
+	}
+	if target == nil {
+		return s.reply(cmd.tag, "NO", "[TRYCREATE] Mailbox does not exist")
+	}
+	if target.folder.Type != domain.FolderSent {
+		return s.reply(cmd.tag, "NO", "[CANNOT] APPEND is only supported for sent messages")
+	}
+
+	header := ""
+	if msg, err := mail.ReadMessage(bytes.NewReader([]byte(literal.text))); err == nil {
+		header = msg.Header.Get("Message-Id")
+	}
+	if header == "" {
+		return s.reply(cmd.tag, "NO", "[CANNOT] APPEND is only supported for sent messages")
+	}
+
+	messageID, err := s.server.messageUCase.FindSubmittedMessage(ctx, s.profileID, header)
+	if errors.Is(err, domain.ErrMessageNotFound) {
+		return s.reply(cmd.tag, "NO", "[CANNOT] Message was not sent through this server")
+	}
+	if err != nil {
+		return s.fail(cmd, "failed to find submitted message", err)
+	}
+
+	if !update.Empty() {
+		err := s.server.messageUCase.SetFlags(ctx, s.profileID, messageID, update)
+		if err != nil && !errors.Is(err, domain.ErrMessageNotFound) {
+			return s.fail(cmd, "failed to set flags", err)
+		}
+	}
+
+	return s.reply(cmd.tag, "OK", fmt.Sprintf("[APPENDUID %d %d] APPEND completed", target.uidValidity(), messageID))
+}
@@ -0,0 +1,102 @@
+package imap_server
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// handleCopy выполняет COPY и MOVE (RFC 6851). UID письма не меняется при копировании,
+// поэтому в COPYUID (RFC 4315) исходные и новые UID совпадают, а в целевой папке UID
+// может оказаться меньше ее UIDNEXT (см. uidNext)
+func (s *session) handleCopy(cmd *command) string {
+	if len(cmd.args) != 2 || cmd.args[0].kind != atomValue {
+		return s.reply(cmd.tag, "BAD", "Syntax: "+cmd.name+" set mailbox")
+	}
+	set, err := parseSeqSet(cmd.args[0].text)
+	if err != nil {
+		return s.reply(cmd.tag, "BAD", "Invalid sequence set")
+	}
+	name, ok := mailboxName(cmd.args[1])
+	if !ok {
+		return s.reply(cmd.tag, "BAD", "Invalid mailbox name")
+	}
+
+	mb := s.mailbox
+	if !cmd.uid && !mb.validSeqSet(set) {
+		return s.reply(cmd.tag, "BAD", "Invalid message sequence number")
+	}
+	move := cmd.name == "MOVE"
+	if move && mb.readOnly {
+		return s.reply(cmd.tag, "NO", "[READ-ONLY] Mailbox is read-only")
+	}
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	target, err := s.findMailbox(ctx, name)
+	if err != nil {
+		return s.fail(cmd, "failed to find mailbox", err)
+	}
+	if target == nil {
+		return s.reply(cmd.tag, "NO", "[TRYCREATE] Mailbox does not exist")
+	}
+
+	indexes := mb.selectMessages(set, cmd.uid)
+	sameFolder := target.folder.ID == mb.folder.ID
+	var uids []uint32
+	moved := make(map[int]bool, len(indexes))
+	for _, i := range indexes {
+		m := mb.messages[i]
+		if !sameFolder {
+			err := s.copyMessage(ctx, int64(m.uid), mb.folder.ID, target.folder, move)
+			if errors.Is(err, domain.ErrMessageNotFound) {
+				continue
+			}
+			if err != nil {
+				return s.fail(cmd, "failed to copy message", err)
+			}
+		}
+		uids = append(uids, m.uid)
+		moved[i] = true
+	}
+
+	copyUID := ""
+	if len(uids) > 0 {
+		copyUID = fmt.Sprintf("[COPYUID %d %s %s] ", target.uidValidity(), formatSeqSet(uids), formatSeqSet(uids))
+	}
+	if !move {
+		return s.reply(cmd.tag, "OK", copyUID+"COPY completed")
+	}
+
+	if copyUID != "" {
+		s.untagged("OK %sMoved", copyUID)
+	}
+	if !sameFolder {
+		for i := len(mb.messages) - 1; i >= 0; i-- {
+			if moved[i] {
+				mb.remove(i)
+				s.untagged("%d EXPUNGE", i+1)
+			}
+		}
+	}
+	return s.reply(cmd.tag, "OK", "MOVE completed")
+}
+
+// copyMessage копирует письмо в папку target, при move убирает его из папки folderID.
+// Перенос в корзину выполняется как в веб-интерфейсе, чтобы письмо можно было восстановить
+func (s *session) copyMessage(ctx context.Context, messageID, folderID int64, target domain.Folder, move bool) error {
+	uc := s.server.messageUCase
+	if move && target.Type == domain.FolderTrash {
+		return uc.MoveToFolder(ctx, s.profileID, messageID, target.ID)
+	}
+
+	if err := uc.CopyToFolder(ctx, s.profileID, messageID, target.ID); err != nil {
+		return err
+	}
+	if move {
+		return uc.DeleteMessageFromFolder(ctx, s.profileID, messageID, folderID)
+	}
+	return nil
+}
@@ -0,0 +1,431 @@
+package imap_server
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// fetchItem - запрашиваемый элемент FETCH. Для BODY[...] name равно "BODY[]"
+type fetchItem struct {
+	name    string
+	section *section
+	peek    bool
+}
+
+// section - секция BODY[...] (RFC 3501, 6.4.5)
+type section struct {
+	// spec - текст секции, как его передал клиент, повторяется в ответе
+	spec      string
+	path      []int
+	specifier string
+	fields    []string
+	partial   bool
+	offset    int64
+	count     int64
+}
+
+// setsSeen - чтение без .PEEK отмечает письмо прочитанным
+func (item fetchItem) setsSeen() bool {
+	return (item.name == "BODY[]" && !item.peek) || item.name == "RFC822" || item.name == "RFC822.TEXT"
+}
+
+// needsContent - для элемента нужно собрать письмо целиком
+func (item fetchItem) needsContent() bool {
+	switch item.name {
+	case "UID", "FLAGS", "INTERNALDATE":
+		return false
+	}
+	return true
+}
+
+func parseFetchItems(args []value) ([]fetchItem, error) {
+	raw := args
+	if len(args) == 1 && args[0].kind == listValue {
+		raw = args[0].list
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("%w: no fetch items", errSyntax)
+	}
+
+	var items []fetchItem
+	for _, arg := range raw {
+		if arg.kind != atomValue {
+			return nil, fmt.Errorf("%w: invalid fetch item", errSyntax)
+		}
+		// Макросы раскрываются только без скобок, но клиенты присылают их и в списке
+		switch strings.ToUpper(arg.text) {
+		case "ALL":
+			items = append(items, fetchItem{name: "FLAGS"}, fetchItem{name: "INTERNALDATE"}, fetchItem{name: "RFC822.SIZE"}, fetchItem{name: "ENVELOPE"})
+			continue
+		case "FAST":
+			items = append(items, fetchItem{name: "FLAGS"}, fetchItem{name: "INTERNALDATE"}, fetchItem{name: "RFC822.SIZE"})
+			continue
+		case "FULL":
+			items = append(items, fetchItem{name: "FLAGS"}, fetchItem{name: "INTERNALDATE"}, fetchItem{name: "RFC822.SIZE"}, fetchItem{name: "ENVELOPE"}, fetchItem{name: "BODY"})
+			continue
+		}
+
+		item, err := parseFetchItem(arg.text)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func parseFetchItem(text string) (fetchItem, error) {
+	upper := strings.ToUpper(text)
+	open := strings.IndexByte(upper, '[')
+	if open < 0 {
+		switch upper {
+		case "FLAGS", "UID", "INTERNALDATE", "RFC822.SIZE", "ENVELOPE", "BODY", "BODYSTRUCTURE", "RFC822", "RFC822.HEADER", "RFC822.TEXT":
+			return fetchItem{name: upper}, nil
+		}
+		return fetchItem{}, fmt.Errorf("%w: unknown fetch item %s", errSyntax, text)
+	}
+
+	name := upper[:open]
+	closing := strings.LastIndexByte(text, ']')
+	if (name != "BODY" && name != "BODY.PEEK") || closing < open {
+		return fetchItem{}, fmt.Errorf("%w: unknown fetch item %s", errSyntax, text)
+	}
+
+	sec, err := parseSection(text[open+1 : closing])
+	if err != nil {
+		return fetchItem{}, err
+	}
+	if partial := text[closing+1:]; partial != "" {
+		offset, count, ok := strings.Cut(strings.TrimSuffix(strings.TrimPrefix(partial, "<"), ">"), ".")
+		sec.offset, err = strconv.ParseInt(offset, 10, 64)
+		if err == nil {
+			sec.count, err = strconv.ParseInt(count, 10, 64)
+		}
+		if !ok || err != nil || !strings.HasPrefix(partial, "<") || !strings.HasSuffix(partial, ">") || sec.offset < 0 || sec.count <= 0 {
+			return fetchItem{}, fmt.Errorf("%w: invalid partial %s", errSyntax, partial)
+		}
+		sec.partial = true
+	}
+	return fetchItem{name: "BODY[]", section: sec, peek: name == "BODY.PEEK"}, nil
+}
+
+func parseSection(spec string) (*section, error) {
+	sec := &section{spec: spec}
+	rest := spec
+	if open := strings.IndexByte(rest, '('); open >= 0 {
+		closing := strings.LastIndexByte(rest, ')')
+		if closing < open {
+			return nil, fmt.Errorf("%w: invalid section %s", errSyntax, spec)
+		}
+		for _, field := range strings.Fields(rest[open+1 : closing]) {
+			sec.fields = append(sec.fields, strings.Trim(field, `"`))
+		}
+		rest = strings.TrimSpace(rest[:open])
+	}
+
+	rest = strings.ToUpper(rest)
+	for rest != "" {
+		head, tail, _ := strings.Cut(rest, ".")
+		n, err := strconv.Atoi(head)
+		if err != nil {
+			break
+		}
+		if n < 1 {
+			return nil, fmt.Errorf("%w: invalid section %s", errSyntax, spec)
+		}
+		sec.path = append(sec.path, n)
+		rest = tail
+	}
+
+	switch rest {
+	case "", "HEADER", "TEXT":
+	case "MIME":
+		if len(sec.path) == 0 {
+			return nil, fmt.Errorf("%w: invalid section %s", errSyntax, spec)
+		}
+	case "HEADER.FIELDS", "HEADER.FIELDS.NOT":
+		if len(sec.fields) == 0 {
+			return nil, fmt.Errorf("%w: invalid section %s", errSyntax, spec)
+		}
+	default:
+		return nil, fmt.Errorf("%w: invalid section %s", errSyntax, spec)
+	}
+	sec.specifier = rest
+	return sec, nil
+}
+
+// data возвращает содержимое секции. Вложенных писем message/rfc822 сервер не собирает,
+// поэтому HEADER и TEXT вложенной части пустые
+func (sec *section) data(c *content) []byte {
+	p, ok := c.findPart(sec.path)
+	if !ok {
+		return nil
+	}
+
+	var data []byte
+	nested := len(sec.path) > 0
+	switch sec.specifier {
+	case "":
+		if nested {
+			data = p.body
+		} else {
+			data = c.raw
+		}
+	case "MIME":
+		data = p.headerRaw
+	case "HEADER":
+		if !nested {
+			data = p.headerRaw
+		}
+	case "TEXT":
+		if !nested {
+			data = p.body
+		}
+	case "HEADER.FIELDS", "HEADER.FIELDS.NOT":
+		if !nested {
+			data = headerFields(p.headerRaw, sec.fields, sec.specifier == "HEADER.FIELDS.NOT")
+		}
+	}
+
+	if sec.partial {
+		if sec.offset >= int64(len(data)) {
+			return nil
+		}
+		data = data[sec.offset:min(sec.offset+sec.count, int64(len(data)))]
+	}
+	return data
+}
+
+func (sec *section) responseName() string {
+	name := "BODY[" + sec.spec + "]"
+	if sec.partial {
+		name += "<" + strconv.FormatInt(sec.offset, 10) + ">"
+	}
+	return name
+}
+
+func literal(data []byte) string {
+	return fmt.Sprintf("{%d}\r\n%s", len(data), data)
+}
+
+func (s *session) handleFetch(cmd *command) string {
+	if len(cmd.args) < 2 || cmd.args[0].kind != atomValue {
+		return s.reply(cmd.tag, "BAD", "Syntax: FETCH set items")
+	}
+	set, err := parseSeqSet(cmd.args[0].text)
+	if err != nil {
+		return s.reply(cmd.tag, "BAD", "Invalid sequence set")
+	}
+	items, err := parseFetchItems(cmd.args[1:])
+	if err != nil {
+		return s.reply(cmd.tag, "BAD", err.Error())
+	}
+	mb := s.mailbox
+	if !cmd.uid && !mb.validSeqSet(set) {
+		return s.reply(cmd.tag, "BAD", "Invalid message sequence number")
+	}
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	expunged := false
+	for _, i := range mb.selectMessages(set, cmd.uid) {
+		response, err := s.fetchMessage(ctx, mb.messages[i], items, cmd.uid)
+		if errors.Is(err, domain.ErrMessageNotFound) {
+			expunged = true
+			continue
+		}
+		if err != nil {
+			return s.fail(cmd, "failed to fetch message", err)
+		}
+		s.untagged("%d FETCH (%s)", i+1, response)
+	}
+
+	if expunged {
+		return s.reply(cmd.tag, "NO", "[EXPUNGEISSUED] Some of the requested messages no longer exist")
+	}
+	return s.reply(cmd.tag, "OK", "FETCH completed")
+}
+
+func (s *session) fetchMessage(ctx context.Context, m *message, items []fetchItem, uid bool) (string, error) {
+	mb := s.mailbox
+
+	var c *content
+	setSeen := false
+	for _, item := range items {
+		if item.needsContent() && c == nil {
+			var err error
+			if c, err = s.content(ctx, m.uid); err != nil {
+				return "", err
+			}
+		}
+		setSeen = setSeen || item.setsSeen()
+	}
+
+	flagsChanged := false
+	if setSeen && !mb.readOnly && !m.seen {
+		read := true
+		if err := s.server.messageUCase.SetFlags(ctx, s.profileID, int64(m.uid), domain.FlagsUpdate{Read: &read}); err != nil {
+			return "", err
+		}
+		m.seen = true
+		flagsChanged = true
+	}
+
+	var fields []string
+	hasUID, hasFlags := false, false
+	for _, item := range items {
+		switch item.name {
+		case "UID":
+			hasUID = true
+			fields = append(fields, fmt.Sprintf("UID %d", m.uid))
+		case "FLAGS":
+			hasFlags = true
+			fields = append(fields, "FLAGS "+mb.flags(m))
+		case "INTERNALDATE":
+			fields = append(fields, `INTERNALDATE "`+m.date.Format("_2-Jan-2006 15:04:05 -0700")+`"`)
+		case "RFC822.SIZE":
+			fields = append(fields, fmt.Sprintf("RFC822.SIZE %d", len(c.raw)))
+		case "ENVELOPE":
+			fields = append(fields, "ENVELOPE "+envelope(c.root.header))
+		case "BODY":
+			fields = append(fields, "BODY "+bodyStructure(c.root, false))
+		case "BODYSTRUCTURE":
+			fields = append(fields, "BODYSTRUCTURE "+bodyStructure(c.root, true))
+		case "RFC822":
+			fields = append(fields, "RFC822 "+literal(c.raw))
+		case "RFC822.HEADER":
+			fields = append(fields, "RFC822.HEADER "+literal(c.root.headerRaw))
+		case "RFC822.TEXT":
+			fields = append(fields, "RFC822.TEXT "+literal(c.root.body))
+		case "BODY[]":
+			fields = append(fields, item.section.responseName()+" "+literal(item.section.data(c)))
+		}
+	}
+
+	// В ответ на UID FETCH всегда входит UID, а на чтение письма - его новые флаги (RFC 3501, 6.4.5, 6.4.8)
+	if uid && !hasUID {
+		fields = append([]string{fmt.Sprintf("UID %d", m.uid)}, fields...)
+	}
+	if flagsChanged && !hasFlags {
+		fields = append(fields, "FLAGS "+mb.flags(m))
+	}
+	return strings.Join(fields, " "), nil
+}
+
+// handleStore меняет флаги. \Seen, \Flagged, \Answered и $Forwarded сохраняются в письме,
+// \Deleted - только в сессии, остальные флаги не поддерживаются и пропускаются
+func (s *session) handleStore(cmd *command) string {
+	if len(cmd.args) < 3 || cmd.args[0].kind != atomValue || cmd.args[1].kind != atomValue {
+		return s.reply(cmd.tag, "BAD", "Syntax: STORE set item flags")
+	}
+	set, err := parseSeqSet(cmd.args[0].text)
+	if err != nil {
+		return s.reply(cmd.tag, "BAD", "Invalid sequence set")
+	}
+
+	item := strings.ToUpper(cmd.args[1].text)
+	silent := strings.HasSuffix(item, ".SILENT")
+	item = strings.TrimSuffix(item, ".SILENT")
+	var mode byte
+	if strings.HasPrefix(item, "+") || strings.HasPrefix(item, "-") {
+		mode, item = item[0], item[1:]
+	}
+	if item != "FLAGS" {
+		return s.reply(cmd.tag, "BAD", "Unknown store item")
+	}
+
+	values := cmd.args[2:]
+	if len(values) == 1 && values[0].kind == listValue {
+		values = values[0].list
+	}
+	requested := make(map[string]bool, len(values))
+	for _, flag := range values {
+		if flag.kind != atomValue {
+			return s.reply(cmd.tag, "BAD", "Invalid flag")
+		}
+		requested[strings.ToLower(flag.text)] = true
+	}
+
+	mb := s.mailbox
+	if !cmd.uid && !mb.validSeqSet(set) {
+		return s.reply(cmd.tag, "BAD", "Invalid message sequence number")
+	}
+	if mb.readOnly {
+		return s.reply(cmd.tag, "NO", "[READ-ONLY] Mailbox is read-only")
+	}
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	apply := func(current bool, flag string) bool {
+		switch mode {
+		case '+':
+			return current || requested[flag]
+		case '-':
+			return current && !requested[flag]
+		}
+		return requested[flag]
+	}
+
+	expunged := false
+	for _, i := range mb.selectMessages(set, cmd.uid) {
+		m := mb.messages[i]
+		next := &message{
+			uid:       m.uid,
+			date:      m.date,
+			seen:      apply(m.seen, `\seen`),
+			flagged:   apply(m.flagged, `\flagged`),
+			answered:  apply(m.answered, `\answered`),
+			forwarded: apply(m.forwarded, `$forwarded`),
+		}
+
+		update := domain.FlagsUpdate{}
+		if next.seen != m.seen {
+			update.Read = &next.seen
+		}
+		if next.flagged != m.flagged {
+			update.Starred = &next.flagged
+		}
+		if next.answered != m.answered {
+			update.Answered = &next.answered
+		}
+		if next.forwarded != m.forwarded {
+			update.Forwarded = &next.forwarded
+		}
+		if !update.Empty() {
+			err := s.server.messageUCase.SetFlags(ctx, s.profileID, int64(m.uid), update)
+			if errors.Is(err, domain.ErrMessageNotFound) {
+				expunged = true
+				continue
+			}
+			if err != nil {
+				return s.fail(cmd, "failed to set flags", err)
+			}
+			mb.messages[i] = next
+		}
+
+		if apply(mb.deleted[m.uid], `\deleted`) {
+			mb.deleted[m.uid] = true
+		} else {
+			delete(mb.deleted, m.uid)
+		}
+
+		if !silent {
+			if cmd.uid {
+				s.untagged("%d FETCH (UID %d FLAGS %s)", i+1, m.uid, mb.flags(mb.messages[i]))
+			} else {
+				s.untagged("%d FETCH (FLAGS %s)", i+1, mb.flags(mb.messages[i]))
+			}
+		}
+	}
+
+	if expunged {
+		return s.reply(cmd.tag, "NO", "[EXPUNGEISSUED] Some of the requested messages no longer exist")
+	}
+	return s.reply(cmd.tag, "OK", "STORE completed")
+}
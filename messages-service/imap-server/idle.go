@@ -0,0 +1,83 @@
+package imap_server
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"time"
+)
+
+var errIdleNotDone = errors.New("expected DONE")
+
+// handleIdle ждет изменений выбранного ящика и сразу сообщает о них клиенту (RFC 2177).
+// Изменения приходят событиями почтовых ящиков, а то, о чем события не сообщают, например
+// удаление письма из папки, находит периодическая проверка
+func (s *session) handleIdle(cmd *command) (string, bool) {
+	s.write("+ idling\r\n")
+	s.flush()
+
+	var events <-chan struct{}
+	if s.server.events != nil {
+		sub := s.server.events.Subscribe(s.profileID, 0)
+		defer sub.Close()
+
+		notify := make(chan struct{}, 1)
+		go func() {
+			for range sub.Events {
+				select {
+				case notify <- struct{}{}:
+				default:
+				}
+			}
+		}()
+		events = notify
+	}
+
+	ticker := time.NewTicker(s.server.cfg.IdlePoll)
+	defer ticker.Stop()
+
+	done := make(chan error, 1)
+	go func() {
+		line, err := s.readLine()
+		if err == nil && !strings.EqualFold(strings.TrimSpace(line), "DONE") {
+			err = errIdleNotDone
+		}
+		done <- err
+	}()
+
+	for {
+		select {
+		case err := <-done:
+			if errors.Is(err, errIdleNotDone) {
+				return s.reply(cmd.tag, "BAD", "Expected DONE"), false
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				s.untagged("BYE Autologout, idle for too long")
+				s.flush()
+			}
+			if err != nil {
+				return "BAD", true
+			}
+			return s.reply(cmd.tag, "OK", "IDLE terminated"), false
+		case <-events:
+			s.idleSync()
+		case <-ticker.C:
+			s.idleSync()
+		}
+	}
+}
+
+func (s *session) idleSync() {
+	if s.mailbox == nil {
+		return
+	}
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	if err := s.sync(ctx); err != nil {
+		s.log.Error("failed to sync mailbox: " + err.Error())
+	}
+	s.flush()
+}
@@ -0,0 +1,698 @@
+package imap_server
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// delimiter - разделитель уровней иерархии в именах почтовых ящиков
+	delimiter = domain.FolderDelimiter
+	// listPageSize - размер страницы при чтении списка писем папки
+	listPageSize = 500
+)
+
+// mailboxInfo - папка пользователя под именем почтового ящика
+type mailboxInfo struct {
+	folder domain.Folder
+	// name - полное имя через разделитель, без кодирования UTF-7
+	name        string
+	hasChildren bool
+}
+
+// uidValidity - идентификатор папки: папка, созданная заново под тем же именем,
+// получает новый идентификатор, и клиент сбрасывает сохраненные UID
+func (m mailboxInfo) uidValidity() uint32 {
+	return uint32(m.folder.ID)
+}
+
+// attributes - атрибуты ящика для LIST, в том числе назначение системных папок (RFC 6154)
+func (m mailboxInfo) attributes() string {
+	attrs := []string{`\HasNoChildren`}
+	if m.hasChildren {
+		attrs[0] = `\HasChildren`
+	}
+	switch m.folder.Type {
+	case domain.FolderSent:
+		attrs = append(attrs, `\Sent`)
+	case domain.FolderDrafts, "draft":
+		attrs = append(attrs, `\Drafts`)
+	case domain.FolderSpam:
+		attrs = append(attrs, `\Junk`)
+	case domain.FolderTrash:
+		attrs = append(attrs, `\Trash`)
+	}
+	return "(" + strings.Join(attrs, " ") + ")"
+}
+
+// mailbox - выбранный почтовый ящик. Номер письма в ящике - индекс в messages плюс один
+type mailbox struct {
+	mailboxInfo
+	readOnly bool
+	// messages отсортированы по UID
+	messages []*message
+	// deleted - письма с флагом \Deleted. Флаг не хранится и живет до конца сессии
+	deleted map[uint32]bool
+}
+
+// message - письмо выбранного ящика. UID - идентификатор письма
+type message struct {
+	uid       uint32
+	date      time.Time
+	seen      bool
+	flagged   bool
+	answered  bool
+	forwarded bool
+}
+
+func newMessage(m domain.Message) (*message, bool) {
+	uid, err := strconv.ParseUint(m.ID, 10, 32)
+	if err != nil || uid == 0 {
+		return nil, false
+	}
+	return &message{
+		uid:       uint32(uid),
+		date:      m.Datetime,
+		seen:      m.IsRead,
+		flagged:   m.IsStarred,
+		answered:  m.IsAnswered,
+		forwarded: m.IsForwarded,
+	}, true
+}
+
+func (m *message) sameFlags(other *message) bool {
+	return m.seen == other.seen && m.flagged == other.flagged && m.answered == other.answered && m.forwarded == other.forwarded
+}
+
+func (mb *mailbox) flags(m *message) string {
+	var flags []string
+	if m.seen {
+		flags = append(flags, `\Seen`)
+	}
+	if m.flagged {
+		flags = append(flags, `\Flagged`)
+	}
+	if m.answered {
+		flags = append(flags, `\Answered`)
+	}
+	if m.forwarded {
+		flags = append(flags, `$Forwarded`)
+	}
+	if mb.deleted[m.uid] {
+		flags = append(flags, `\Deleted`)
+	}
+	return "(" + strings.Join(flags, " ") + ")"
+}
+
+// uidNext - следующий UID ящика: наибольший UID плюс один.
+// Ограничение: UID - общий идентификатор письма, а не номер внутри папки, поэтому письмо,
+// попавшее в папку через COPY, MOVE или перенос в веб-интерфейсе, сохраняет свой UID и может
+// оказаться ниже ранее объявленного UIDNEXT, а UIDVALIDITY при этом не меняется. Клиент,
+// который запрашивает только UID от UIDNEXT и выше, такое письмо не увидит до полной
+// синхронизации папки. Исправление требует собственных UID в каждой папке
+func (mb *mailbox) uidNext() uint32 {
+	if len(mb.messages) == 0 {
+		return 1
+	}
+	return mb.messages[len(mb.messages)-1].uid + 1
+}
+
+func (mb *mailbox) remove(i int) {
+	delete(mb.deleted, mb.messages[i].uid)
+	mb.messages = append(mb.messages[:i], mb.messages[i+1:]...)
+}
+
+// selectMessages возвращает индексы писем из набора номеров или UID
+func (mb *mailbox) selectMessages(set seqSet, uid bool) []int {
+	if len(mb.messages) == 0 {
+		return nil
+	}
+	max := uint32(len(mb.messages))
+	if uid {
+		max = mb.messages[len(mb.messages)-1].uid
+	}
+
+	var indexes []int
+	for i, m := range mb.messages {
+		n := uint32(i + 1)
+		if uid {
+			n = m.uid
+		}
+		if set.contains(n, max) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// validSeqSet проверяет, что номера писем не выходят за пределы ящика (RFC 3501, 9: seq-number)
+func (mb *mailbox) validSeqSet(set seqSet) bool {
+	if len(mb.messages) == 0 {
+		return false
+	}
+	for _, r := range set {
+		if r.from > uint32(len(mb.messages)) || r.to > uint32(len(mb.messages)) {
+			return false
+		}
+	}
+	return true
+}
+
+// mailboxes возвращает папки пользователя с полными именами
+func (s *session) mailboxes(ctx context.Context) ([]mailboxInfo, error) {
+	folders, err := s.server.messageUCase.GetUserFolders(ctx, s.profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	hasChildren := make(map[int64]bool)
+	for _, folder := range folders {
+		hasChildren[folder.ParentID] = true
+	}
+
+	paths := domain.FolderPaths(folders)
+	result := make([]mailboxInfo, 0, len(folders))
+	for _, folder := range folders {
+		result = append(result, mailboxInfo{
+			folder:      folder,
+			name:        paths[folder.ID],
+			hasChildren: hasChildren[folder.ID],
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if inbox := result[i].folder.Type == domain.FolderInbox; inbox != (result[j].folder.Type == domain.FolderInbox) {
+			return inbox
+		}
+		return result[i].name < result[j].name
+	})
+	return result, nil
+}
+
+// findMailbox ищет ящик по имени из команды, nil - ящика нет
+func (s *session) findMailbox(ctx context.Context, name string) (*mailboxInfo, error) {
+	mailboxes, err := s.mailboxes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range mailboxes {
+		if sameMailboxName(mailboxes[i].name, name) {
+			return &mailboxes[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// sameMailboxName сравнивает имена без учета регистра: имена папок уникальны без учета регистра
+func sameMailboxName(a, b string) bool {
+	return strings.EqualFold(a, b)
+}
+
+// mailboxName декодирует имя ящика из аргумента команды. Некоторые клиенты
+// передают имена в UTF-8, такие имена используются как есть
+func mailboxName(arg value) (string, bool) {
+	raw, ok := arg.astring()
+	if !ok {
+		return "", false
+	}
+	name, err := decodeMailboxName(raw)
+	if err != nil {
+		name = raw
+	}
+	return strings.TrimSuffix(name, delimiter), true
+}
+
+func (s *session) handleSelect(cmd *command) string {
+	// Неудачный SELECT тоже закрывает выбранный ящик (RFC 3501, 6.3.1)
+	s.mailbox = nil
+	if len(cmd.args) != 1 {
+		return s.reply(cmd.tag, "BAD", "Syntax: "+cmd.name+" mailbox")
+	}
+	name, ok := mailboxName(cmd.args[0])
+	if !ok {
+		return s.reply(cmd.tag, "BAD", "Invalid mailbox name")
+	}
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	info, err := s.findMailbox(ctx, name)
+	if err != nil {
+		return s.fail(cmd, "failed to find mailbox", err)
+	}
+	if info == nil {
+		return s.reply(cmd.tag, "NO", "[NONEXISTENT] Mailbox does not exist")
+	}
+	messages, err := s.listMessages(ctx, info.folder.ID)
+	if err != nil {
+		return s.fail(cmd, "failed to list messages", err)
+	}
+
+	mb := &mailbox{
+		mailboxInfo: *info,
+		readOnly:    cmd.name == "EXAMINE",
+		messages:    messages,
+		deleted:     make(map[uint32]bool),
+	}
+
+	s.untagged(`FLAGS (\Answered \Flagged \Deleted \Seen $Forwarded)`)
+	if mb.readOnly {
+		s.untagged("OK [PERMANENTFLAGS ()] Read-only mailbox")
+	} else {
+		s.untagged(`OK [PERMANENTFLAGS (\Answered \Flagged \Seen $Forwarded)] Limited`)
+	}
+	s.untagged("%d EXISTS", len(mb.messages))
+	s.untagged("0 RECENT")
+	for i, m := range mb.messages {
+		if !m.seen {
+			s.untagged("OK [UNSEEN %d] First unseen", i+1)
+			break
+		}
+	}
+	s.untagged("OK [UIDVALIDITY %d] UIDs valid", mb.uidValidity())
+	s.untagged("OK [UIDNEXT %d] Predicted next UID", mb.uidNext())
+
+	s.mailbox = mb
+	if mb.readOnly {
+		return s.reply(cmd.tag, "OK", "[READ-ONLY] EXAMINE completed")
+	}
+	return s.reply(cmd.tag, "OK", "[READ-WRITE] SELECT completed")
+}
+
+// handleClose закрывает ящик. CLOSE удаляет письма с флагом \Deleted без ответов EXPUNGE, UNSELECT - нет (RFC 3691)
+func (s *session) handleClose(cmd *command) string {
+	if cmd.name == "CLOSE" && !s.mailbox.readOnly {
+		ctx, cancel := s.context()
+		defer cancel()
+
+		if err := s.expunge(ctx, nil, true); err != nil {
+			return s.fail(cmd, "failed to expunge messages", err)
+		}
+	}
+	s.mailbox = nil
+	return s.reply(cmd.tag, "OK", cmd.name+" completed")
+}
+
+func (s *session) handleList(cmd *command) string {
+	if len(cmd.args) != 2 {
+		return s.reply(cmd.tag, "BAD", "Syntax: "+cmd.name+" reference mailbox")
+	}
+	reference, ok := mailboxName(cmd.args[0])
+	pattern, ok2 := cmd.args[1].astring()
+	if !ok || !ok2 {
+		return s.reply(cmd.tag, "BAD", "Invalid mailbox name")
+	}
+	if pattern == "" {
+		// Пустой шаблон запрашивает разделитель иерархии
+		s.untagged(`%s (\Noselect) "%s" ""`, cmd.name, delimiter)
+		return s.reply(cmd.tag, "OK", cmd.name+" completed")
+	}
+	if decoded, err := decodeMailboxName(pattern); err == nil {
+		pattern = decoded
+	}
+	if reference != "" && !strings.HasPrefix(pattern, delimiter) {
+		pattern = reference + delimiter + pattern
+	}
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	mailboxes, err := s.mailboxes(ctx)
+	if err != nil {
+		return s.fail(cmd, "failed to list mailboxes", err)
+	}
+	for _, m := range mailboxes {
+		if matchPattern(strings.ToLower(pattern), strings.ToLower(m.name)) {
+			s.untagged(`%s %s "%s" %s`, cmd.name, m.attributes(), delimiter, quote(encodeMailboxName(m.name)))
+		}
+	}
+	return s.reply(cmd.tag, "OK", cmd.name+" completed")
+}
+
+// matchPattern сопоставляет имя с шаблоном LIST: "*" - любые символы, "%" - любые, кроме разделителя
+func matchPattern(pattern, name string) bool {
+	if pattern == "" {
+		return name == ""
+	}
+	switch pattern[0] {
+	case '*', '%':
+		for i := 0; i <= len(name); i++ {
+			if matchPattern(pattern[1:], name[i:]) {
+				return true
+			}
+			if i < len(name) && pattern[0] == '%' && name[i] == delimiter[0] {
+				return false
+			}
+		}
+		return false
+	}
+	if name == "" || pattern[0] != name[0] {
+		return false
+	}
+	return matchPattern(pattern[1:], name[1:])
+}
+
+func (s *session) handleStatus(cmd *command) string {
+	if len(cmd.args) != 2 || cmd.args[1].kind != listValue {
+		return s.reply(cmd.tag, "BAD", "Syntax: STATUS mailbox (items)")
+	}
+	name, ok := mailboxName(cmd.args[0])
+	if !ok {
+		return s.reply(cmd.tag, "BAD", "Invalid mailbox name")
+	}
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	info, err := s.findMailbox(ctx, name)
+	if err != nil {
+		return s.fail(cmd, "failed to find mailbox", err)
+	}
+	if info == nil {
+		return s.reply(cmd.tag, "NO", "[NONEXISTENT] Mailbox does not exist")
+	}
+
+	total, unseen := info.folder.MessageTotal, info.folder.MessageUnread
+	var uidNext uint32
+	for _, item := range cmd.args[1].list {
+		if item.isAtom("UIDNEXT") {
+			// Для UIDNEXT нужен наибольший идентификатор письма, поэтому список читается целиком
+			messages, err := s.listMessages(ctx, info.folder.ID)
+			if err != nil {
+				return s.fail(cmd, "failed to list messages", err)
+			}
+			mb := mailbox{messages: messages}
+			uidNext = mb.uidNext()
+			total, unseen = len(messages), 0
+			for _, m := range messages {
+				if !m.seen {
+					unseen++
+				}
+			}
+			break
+		}
+	}
+
+	var items []string
+	for _, item := range cmd.args[1].list {
+		switch {
+		case item.isAtom("MESSAGES"):
+			items = append(items, fmt.Sprintf("MESSAGES %d", total))
+		case item.isAtom("RECENT"):
+			items = append(items, "RECENT 0")
+		case item.isAtom("UIDNEXT"):
+			items = append(items, fmt.Sprintf("UIDNEXT %d", uidNext))
+		case item.isAtom("UIDVALIDITY"):
+			items = append(items, fmt.Sprintf("UIDVALIDITY %d", info.uidValidity()))
+		case item.isAtom("UNSEEN"):
+			items = append(items, fmt.Sprintf("UNSEEN %d", unseen))
+		default:
+			return s.reply(cmd.tag, "BAD", "Unknown status item")
+		}
+	}
+
+	s.untagged("STATUS %s (%s)", quote(encodeMailboxName(info.name)), strings.Join(items, " "))
+	return s.reply(cmd.tag, "OK", "STATUS completed")
+}
+
+// handleCreate создает папку, а также недостающие родительские папки (RFC 3501, 6.3.3)
+func (s *session) handleCreate(cmd *command) string {
+	if len(cmd.args) != 1 {
+		return s.reply(cmd.tag, "BAD", "Syntax: CREATE mailbox")
+	}
+	name, ok := mailboxName(cmd.args[0])
+	if !ok || name == "" {
+		return s.reply(cmd.tag, "BAD", "Invalid mailbox name")
+	}
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	existing, err := s.findMailbox(ctx, name)
+	if err != nil {
+		return s.fail(cmd, "failed to find mailbox", err)
+	}
+	if existing != nil || sameMailboxName(name, "INBOX") {
+		return s.reply(cmd.tag, "NO", "[ALREADYEXISTS] Mailbox already exists")
+	}
+
+	if _, err := s.createPath(ctx, name); err != nil {
+		return s.folderError(cmd, "failed to create mailbox", err)
+	}
+	return s.reply(cmd.tag, "OK", "CREATE completed")
+}
+
+// createPath возвращает папку с полным именем name, создавая недостающие уровни
+func (s *session) createPath(ctx context.Context, name string) (int64, error) {
+	var parentID int64
+	path := ""
+	for _, segment := range strings.Split(name, delimiter) {
+		if segment == "" {
+			return 0, domain.ErrInvalidFolderParent
+		}
+		if path != "" {
+			path += delimiter
+		}
+		path += segment
+
+		existing, err := s.findMailbox(ctx, path)
+		if err != nil {
+			return 0, err
+		}
+		if existing != nil {
+			parentID = existing.folder.ID
+			continue
+		}
+
+		var folder *domain.Folder
+		if parentID == 0 {
+			folder, err = s.server.messageUCase.CreateFolder(ctx, s.profileID, segment)
+		} else {
+			folder, err = s.server.messageUCase.CreateSubfolder(ctx, s.profileID, parentID, segment)
+		}
+		if err != nil {
+			return 0, err
+		}
+		parentID = folder.ID
+	}
+	return parentID, nil
+}
+
+func (s *session) handleDelete(cmd *command) string {
+	if len(cmd.args) != 1 {
+		return s.reply(cmd.tag, "BAD", "Syntax: DELETE mailbox")
+	}
+	name, ok := mailboxName(cmd.args[0])
+	if !ok {
+		return s.reply(cmd.tag, "BAD", "Invalid mailbox name")
+	}
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	info, err := s.findMailbox(ctx, name)
+	if err != nil {
+		return s.fail(cmd, "failed to find mailbox", err)
+	}
+	if info == nil {
+		return s.reply(cmd.tag, "NO", "[NONEXISTENT] Mailbox does not exist")
+	}
+	if err := s.server.messageUCase.DeleteFolder(ctx, s.profileID, info.folder.ID); err != nil {
+		return s.folderError(cmd, "failed to delete mailbox", err)
+	}
+	if s.mailbox != nil && s.mailbox.folder.ID == info.folder.ID {
+		s.mailbox = nil
+	}
+	return s.reply(cmd.tag, "OK", "DELETE completed")
+}
+
+// handleRename переименовывает папку, а если изменился путь - переносит ее в другую родительскую папку
+func (s *session) handleRename(cmd *command) string {
+	if len(cmd.args) != 2 {
+		return s.reply(cmd.tag, "BAD", "Syntax: RENAME mailbox newname")
+	}
+	oldName, ok := mailboxName(cmd.args[0])
+	newName, ok2 := mailboxName(cmd.args[1])
+	if !ok || !ok2 || newName == "" {
+		return s.reply(cmd.tag, "BAD", "Invalid mailbox name")
+	}
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	info, err := s.findMailbox(ctx, oldName)
+	if err != nil {
+		return s.fail(cmd, "failed to find mailbox", err)
+	}
+	if info == nil {
+		return s.reply(cmd.tag, "NO", "[NONEXISTENT] Mailbox does not exist")
+	}
+	if info.folder.Type != domain.FolderCustom {
+		return s.reply(cmd.tag, "NO", "[CANNOT] System mailboxes cannot be renamed")
+	}
+	if existing, err := s.findMailbox(ctx, newName); err != nil {
+		return s.fail(cmd, "failed to find mailbox", err)
+	} else if existing != nil && existing.folder.ID != info.folder.ID {
+		return s.reply(cmd.tag, "NO", "[ALREADYEXISTS] Mailbox already exists")
+	}
+
+	var parentID int64
+	leaf := newName
+	if i := strings.LastIndex(newName, delimiter); i >= 0 {
+		if parentID, err = s.createPath(ctx, newName[:i]); err != nil {
+			return s.folderError(cmd, "failed to create parent mailbox", err)
+		}
+		leaf = newName[i+1:]
+	}
+
+	if parentID != info.folder.ParentID {
+		if _, err := s.server.messageUCase.MoveFolder(ctx, s.profileID, info.folder.ID, parentID); err != nil {
+			return s.folderError(cmd, "failed to move mailbox", err)
+		}
+	}
+	if leaf != info.folder.Name {
+		if _, err := s.server.messageUCase.RenameFolder(ctx, s.profileID, info.folder.ID, leaf); err != nil {
+			return s.folderError(cmd, "failed to rename mailbox", err)
+		}
+	}
+	return s.reply(cmd.tag, "OK", "RENAME completed")
+}
+
+// folderError отвечает на ошибку операции с папкой
+func (s *session) folderError(cmd *command, message string, err error) string {
+	switch {
+	case errors.Is(err, domain.ErrFolderExists):
+		return s.reply(cmd.tag, "NO", "[ALREADYEXISTS] Mailbox already exists")
+	case errors.Is(err, domain.ErrFolderNotFound):
+		return s.reply(cmd.tag, "NO", "[NONEXISTENT] Mailbox does not exist")
+	case errors.Is(err, domain.ErrFolderSystem):
+		return s.reply(cmd.tag, "NO", "[CANNOT] System mailboxes cannot be changed")
+	case errors.Is(err, domain.ErrInvalidFolderParent):
+		return s.reply(cmd.tag, "NO", "[CANNOT] Invalid mailbox hierarchy")
+	}
+	return s.fail(cmd, message, err)
+}
+
+// listMessages читает все письма папки и сортирует их по UID
+func (s *session) listMessages(ctx context.Context, folderID int64) ([]*message, error) {
+	page := domain.MessagePage{Sort: domain.SortDateAsc, Limit: listPageSize}
+
+	var messages []*message
+	for {
+		batch, err := s.server.messageUCase.GetFolderMessagesWithKeysetPagination(ctx, s.profileID, folderID, 0, page)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range batch {
+			if msg, ok := newMessage(m); ok {
+				messages = append(messages, msg)
+			}
+		}
+		if len(batch) < listPageSize {
+			break
+		}
+		key := batch[len(batch)-1].Key()
+		page.After = &key
+	}
+
+	sort.Slice(messages, func(i, j int) bool { return messages[i].uid < messages[j].uid })
+	return messages, nil
+}
+
+// sync перечитывает выбранный ящик и сообщает клиенту об изменениях: удаленных письмах,
+// новых флагах и новых письмах. Письмо, попавшее в папку с UID меньше уже известных
+// (например, перенесенное из другой папки), появится только после повторного SELECT:
+// номера писем должны возрастать вместе с UID
+func (s *session) sync(ctx context.Context) error {
+	mb := s.mailbox
+	fresh, err := s.listMessages(ctx, mb.folder.ID)
+	if err != nil {
+		return err
+	}
+
+	byUID := make(map[uint32]*message, len(fresh))
+	for _, m := range fresh {
+		byUID[m.uid] = m
+	}
+
+	// Удаляем с конца, чтобы номера еще не обработанных писем не сдвигались
+	for i := len(mb.messages) - 1; i >= 0; i-- {
+		if _, ok := byUID[mb.messages[i].uid]; !ok {
+			mb.remove(i)
+			s.untagged("%d EXPUNGE", i+1)
+		}
+	}
+
+	for i, m := range mb.messages {
+		if current := byUID[m.uid]; !m.sameFlags(current) {
+			current.date = m.date
+			mb.messages[i] = current
+			s.untagged("%d FETCH (FLAGS %s)", i+1, mb.flags(current))
+		}
+	}
+
+	last := mb.uidNext() - 1
+	added := false
+	for _, m := range fresh {
+		if m.uid > last {
+			mb.messages = append(mb.messages, m)
+			added = true
+		}
+	}
+	if added {
+		s.untagged("%d EXISTS", len(mb.messages))
+	}
+	return nil
+}
+
+// expunge удаляет из выбранной папки письма с флагом \Deleted, uids ограничивает удаление
+// (UID EXPUNGE, RFC 4315). Письмо, оставшееся без папок, удаляется при очистке хранилища
+func (s *session) expunge(ctx context.Context, uids seqSet, silent bool) error {
+	mb := s.mailbox
+	max := mb.uidNext() - 1
+	for i := len(mb.messages) - 1; i >= 0; i-- {
+		m := mb.messages[i]
+		if !mb.deleted[m.uid] || (uids != nil && !uids.contains(m.uid, max)) {
+			continue
+		}
+		err := s.server.messageUCase.DeleteMessageFromFolder(ctx, s.profileID, int64(m.uid), mb.folder.ID)
+		if err != nil && !errors.Is(err, domain.ErrMessageNotFound) {
+			return err
+		}
+		mb.remove(i)
+		if !silent {
+			s.untagged("%d EXPUNGE", i+1)
+		}
+	}
+	return nil
+}
+
+func (s *session) handleExpunge(cmd *command) string {
+	var uids seqSet
+	if cmd.uid {
+		if len(cmd.args) != 1 {
+			return s.reply(cmd.tag, "BAD", "Syntax: UID EXPUNGE set")
+		}
+		set, err := parseSeqSet(cmd.args[0].text)
+		if err != nil {
+			return s.reply(cmd.tag, "BAD", "Invalid UID set")
+		}
+		uids = set
+	}
+	if s.mailbox.readOnly {
+		return s.reply(cmd.tag, "NO", "[READ-ONLY] Mailbox is read-only")
+	}
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	if err := s.expunge(ctx, uids, false); err != nil {
+		return s.fail(cmd, "failed to expunge messages", err)
+	}
+	return s.reply(cmd.tag, "OK", "EXPUNGE completed")
+}
@@ -0,0 +1,336 @@
+package imap_server
+
+import (
+	"2025_2_a4code/internal/lib/mailparse"
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+)
+
+// maxPartDepth - ограничение вложенности частей письма
+const maxPartDepth = 16
+
+var crlf = []byte("\r\n")
+
+// content - собранное письмо, разобранное на части для FETCH и SEARCH
+type content struct {
+	raw  []byte
+	root *part
+	// text - текст письма для SEARCH BODY и TEXT, заполняется при первом поиске
+	text *string
+}
+
+// part - часть письма. Для multipart тело разбито на вложенные части
+type part struct {
+	header textproto.MIMEHeader
+	// headerRaw - заголовок вместе с пустой строкой после него
+	headerRaw []byte
+	body      []byte
+	children  []*part
+}
+
+func parseContent(raw []byte) *content {
+	return &content{raw: raw, root: parsePart(raw, 0)}
+}
+
+func parsePart(raw []byte, depth int) *part {
+	p := &part{}
+	switch i := bytes.Index(raw, []byte("\r\n\r\n")); {
+	case bytes.HasPrefix(raw, crlf):
+		p.headerRaw, p.body = raw[:2], raw[2:]
+	case i >= 0:
+		p.headerRaw, p.body = raw[:i+4], raw[i+4:]
+	default:
+		p.headerRaw = raw
+	}
+
+	p.header, _ = textproto.NewReader(bufio.NewReader(bytes.NewReader(p.headerRaw))).ReadMIMEHeader()
+	if p.header == nil {
+		p.header = textproto.MIMEHeader{}
+	}
+
+	mediaType, params := p.contentType()
+	if strings.HasPrefix(mediaType, "multipart/") && params["boundary"] != "" && depth < maxPartDepth {
+		for _, body := range splitMultipart(p.body, params["boundary"]) {
+			p.children = append(p.children, parsePart(body, depth+1))
+		}
+	}
+	return p
+}
+
+// contentType возвращает тип части, по умолчанию text/plain (RFC 2045, 5.2)
+func (p *part) contentType() (string, map[string]string) {
+	mediaType, params, err := mime.ParseMediaType(p.header.Get("Content-Type"))
+	if err != nil || !strings.Contains(mediaType, "/") {
+		return "text/plain", map[string]string{"charset": "us-ascii"}
+	}
+	return mediaType, params
+}
+
+// splitMultipart делит тело на части по разделителю. Перевод строки перед
+// разделителем относится к разделителю (RFC 2046, 5.1.1)
+func splitMultipart(body []byte, boundary string) [][]byte {
+	delim := []byte("--" + boundary)
+
+	var parts [][]byte
+	start := -1
+	for offset := 0; offset < len(body); {
+		line := body[offset:]
+		next := len(body)
+		if end := bytes.Index(line, crlf); end >= 0 {
+			line = line[:end]
+			next = offset + end + 2
+		}
+
+		if rest, ok := bytes.CutPrefix(bytes.TrimRight(line, " \t"), delim); ok && (len(rest) == 0 || string(rest) == "--") {
+			if start >= 0 {
+				end := max(offset-2, start)
+				parts = append(parts, body[start:end])
+			}
+			if len(rest) > 0 {
+				return parts
+			}
+			start = next
+		}
+		offset = next
+	}
+
+	if start >= 0 && start <= len(body) {
+		parts = append(parts, body[start:])
+	}
+	return parts
+}
+
+// findPart возвращает часть по номеру секции: "1.2" - вторая часть первой части.
+// У письма из одной части единственная часть - само письмо
+func (c *content) findPart(path []int) (*part, bool) {
+	p := c.root
+	for _, n := range path {
+		switch {
+		case len(p.children) > 0:
+			if n < 1 || n > len(p.children) {
+				return nil, false
+			}
+			p = p.children[n-1]
+		case n != 1:
+			return nil, false
+		}
+	}
+	return p, true
+}
+
+// headerFields оставляет в заголовке только перечисленные поля или, если not, все кроме них
+func headerFields(headerRaw []byte, fields []string, not bool) []byte {
+	wanted := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		wanted[strings.ToLower(field)] = true
+	}
+
+	var b bytes.Buffer
+	include := false
+	for _, line := range bytes.SplitAfter(headerRaw, crlf) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if line[0] != ' ' && line[0] != '\t' {
+			name, _, _ := bytes.Cut(line, []byte(":"))
+			include = wanted[strings.ToLower(string(bytes.TrimSpace(name)))] != not
+		}
+		if include {
+			b.Write(line)
+		}
+	}
+	b.Write(crlf)
+	return b.Bytes()
+}
+
+// envelope - структура ENVELOPE (RFC 3501, 7.4.2)
+func envelope(h textproto.MIMEHeader) string {
+	from := addressList(h.Get("From"))
+	sender := addressList(h.Get("Sender"))
+	if sender == "NIL" {
+		sender = from
+	}
+	replyTo := addressList(h.Get("Reply-To"))
+	if replyTo == "NIL" {
+		replyTo = from
+	}
+
+	fields := []string{
+		nstring(h.Get("Date")),
+		nstring(h.Get("Subject")),
+		from,
+		sender,
+		replyTo,
+		addressList(h.Get("To")),
+		addressList(h.Get("Cc")),
+		addressList(h.Get("Bcc")),
+		nstring(h.Get("In-Reply-To")),
+		nstring(h.Get("Message-Id")),
+	}
+	return "(" + strings.Join(fields, " ") + ")"
+}
+
+// addressList записывает адреса как (name adl mailbox host). Имена в UTF-8 кодируются по RFC 2047
+func addressList(value string) string {
+	if value == "" {
+		return "NIL"
+	}
+	addresses, err := mail.ParseAddressList(value)
+	if err != nil || len(addresses) == 0 {
+		return "NIL"
+	}
+
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, address := range addresses {
+		mailbox, host := address.Address, ""
+		if i := strings.LastIndexByte(mailbox, '@'); i >= 0 {
+			mailbox, host = mailbox[:i], mailbox[i+1:]
+		}
+		fmt.Fprintf(&b, "(%s NIL %s %s)", nstring(mime.QEncoding.Encode("utf-8", address.Name)), nstring(mailbox), nstring(host))
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// bodyStructure - структура BODY или, с extended, BODYSTRUCTURE (RFC 3501, 7.4.2)
+func bodyStructure(p *part, extended bool) string {
+	mediaType, params := p.contentType()
+	mainType, subType, _ := strings.Cut(strings.ToUpper(mediaType), "/")
+
+	var b strings.Builder
+	b.WriteByte('(')
+	if len(p.children) > 0 {
+		for _, child := range p.children {
+			b.WriteString(bodyStructure(child, extended))
+		}
+		b.WriteString(" " + quote(subType))
+		if extended {
+			fmt.Fprintf(&b, " %s %s NIL NIL", paramList(params), disposition(p))
+		}
+		b.WriteByte(')')
+		return b.String()
+	}
+
+	encoding := strings.ToUpper(p.header.Get("Content-Transfer-Encoding"))
+	if encoding == "" {
+		encoding = "7BIT"
+	}
+	fmt.Fprintf(&b, "%s %s %s %s %s %s %d",
+		quote(mainType), quote(subType), paramList(params),
+		nstring(p.header.Get("Content-Id")), nstring(p.header.Get("Content-Description")),
+		quote(encoding), len(p.body))
+	if mainType == "TEXT" {
+		fmt.Fprintf(&b, " %d", lineCount(p.body))
+	}
+	if extended {
+		fmt.Fprintf(&b, " %s %s NIL NIL", nstring(p.header.Get("Content-Md5")), disposition(p))
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+func paramList(params map[string]string) string {
+	if len(params) == 0 {
+		return "NIL"
+	}
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	items := make([]string, 0, len(params)*2)
+	for _, key := range keys {
+		// Значения в UTF-8, например имена вложений, кодируются по RFC 2047
+		items = append(items, quote(strings.ToUpper(key)), quote(mime.QEncoding.Encode("utf-8", params[key])))
+	}
+	return "(" + strings.Join(items, " ") + ")"
+}
+
+func disposition(p *part) string {
+	value := p.header.Get("Content-Disposition")
+	if value == "" {
+		return "NIL"
+	}
+	kind, params, err := mime.ParseMediaType(value)
+	if err != nil {
+		return "NIL"
+	}
+	return fmt.Sprintf("(%s %s)", quote(strings.ToUpper(kind)), paramList(params))
+}
+
+func lineCount(body []byte) int {
+	lines := bytes.Count(body, []byte("\n"))
+	if len(body) > 0 && body[len(body)-1] != '\n' {
+		lines++
+	}
+	return lines
+}
+
+// searchText возвращает текст письма без кодирования для поиска
+func (c *content) searchText() string {
+	if c.text == nil {
+		text := ""
+		if msg, err := mailparse.Parse(bytes.NewReader(c.raw)); err == nil {
+			text = msg.Text
+		}
+		c.text = &text
+	}
+	return *c.text
+}
+
+// content возвращает собранное письмо из кэша сессии или собирает его заново
+func (s *session) content(ctx context.Context, uid uint32) (*content, error) {
+	if c := s.cache.get(uid); c != nil {
+		return c, nil
+	}
+	raw, err := s.server.messageUCase.ExportMessage(ctx, s.profileID, int64(uid))
+	if err != nil {
+		return nil, err
+	}
+	c := parseContent(raw)
+	s.cache.put(uid, c)
+	return c, nil
+}
+
+// messageCache хранит последние собранные письма сессии: клиенты читают письмо
+// несколькими командами, по частям, и сборка с вложениями не должна повторяться
+type messageCache struct {
+	limit   int
+	size    int
+	order   []uint32
+	entries map[uint32]*content
+}
+
+func newMessageCache(limit int) *messageCache {
+	return &messageCache{limit: limit, entries: make(map[uint32]*content)}
+}
+
+func (c *messageCache) get(uid uint32) *content {
+	return c.entries[uid]
+}
+
+func (c *messageCache) put(uid uint32, entry *content) {
+	if _, ok := c.entries[uid]; ok {
+		return
+	}
+	c.entries[uid] = entry
+	c.order = append(c.order, uid)
+	c.size += len(entry.raw)
+
+	// Вытесняем самые старые письма, последнее письмо остается даже если оно больше лимита
+	for c.size > c.limit && len(c.order) > 1 {
+		oldest := c.order[0]
+		c.order = c.order[1:]
+		c.size -= len(c.entries[oldest].raw)
+		delete(c.entries, oldest)
+	}
+}
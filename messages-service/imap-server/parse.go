@@ -0,0 +1,290 @@
+package imap_server
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errSyntax = errors.New("syntax error")
+
+type valueKind int
+
+const (
+	atomValue valueKind = iota
+	stringValue
+	listValue
+)
+
+// value - аргумент команды: атом, строка (в кавычках или литерал) или список в скобках
+type value struct {
+	kind valueKind
+	text string
+	list []value
+}
+
+func (v value) isAtom(name string) bool {
+	return v.kind == atomValue && strings.EqualFold(v.text, name)
+}
+
+// astring - атом или строка (RFC 3501, 9: astring)
+func (v value) astring() (string, bool) {
+	if v.kind == listValue {
+		return "", false
+	}
+	return v.text, true
+}
+
+// command - команда клиента: метка, имя и аргументы. Префикс UID записывается в поле uid
+type command struct {
+	tag  string
+	name string
+	uid  bool
+	args []value
+}
+
+// parser разбирает строку команды. Литерал {n} в конце строки дочитывается из соединения,
+// после него разбор продолжается со следующей строки
+type parser struct {
+	line    string
+	pos     int
+	literal func(size int64, sync bool) ([]byte, string, error)
+}
+
+func (p *parser) parseValues(end byte) ([]value, error) {
+	var values []value
+	for {
+		for p.pos < len(p.line) && p.line[p.pos] == ' ' {
+			p.pos++
+		}
+		if p.pos >= len(p.line) {
+			if end != 0 {
+				return nil, fmt.Errorf("%w: unterminated list", errSyntax)
+			}
+			return values, nil
+		}
+
+		switch c := p.line[p.pos]; c {
+		case ')':
+			if end != ')' {
+				return nil, fmt.Errorf("%w: unexpected )", errSyntax)
+			}
+			p.pos++
+			return values, nil
+		case '(':
+			p.pos++
+			list, err := p.parseValues(')')
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value{kind: listValue, list: list})
+		case '"':
+			text, err := p.parseQuoted()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value{kind: stringValue, text: text})
+		case '{':
+			text, err := p.parseLiteral()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value{kind: stringValue, text: text})
+		default:
+			values = append(values, value{kind: atomValue, text: p.parseAtom()})
+		}
+	}
+}
+
+func (p *parser) parseQuoted() (string, error) {
+	var b strings.Builder
+	for p.pos++; p.pos < len(p.line); p.pos++ {
+		switch c := p.line[p.pos]; c {
+		case '"':
+			p.pos++
+			return b.String(), nil
+		case '\\':
+			p.pos++
+			if p.pos >= len(p.line) {
+				return "", fmt.Errorf("%w: unterminated string", errSyntax)
+			}
+			b.WriteByte(p.line[p.pos])
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("%w: unterminated string", errSyntax)
+}
+
+// parseLiteral читает литерал {n} (RFC 3501, 4.3) или {n+} без ожидания продолжения (RFC 7888)
+func (p *parser) parseLiteral() (string, error) {
+	spec := p.line[p.pos:]
+	if !strings.HasSuffix(spec, "}") {
+		return "", fmt.Errorf("%w: literal must end the line", errSyntax)
+	}
+	spec = spec[1 : len(spec)-1]
+	sync := true
+	if strings.HasSuffix(spec, "+") {
+		sync = false
+		spec = spec[:len(spec)-1]
+	}
+	size, err := strconv.ParseInt(spec, 10, 64)
+	if err != nil || size < 0 {
+		return "", fmt.Errorf("%w: invalid literal size", errSyntax)
+	}
+
+	data, next, err := p.literal(size, sync)
+	if err != nil {
+		return "", err
+	}
+	p.line, p.pos = next, 0
+	return string(data), nil
+}
+
+// parseAtom читает атом. Квадратные скобки в атоме могут содержать пробелы
+// и круглые скобки: BODY[HEADER.FIELDS (From To)]<0.100>
+func (p *parser) parseAtom() string {
+	start := p.pos
+	depth := 0
+	for ; p.pos < len(p.line); p.pos++ {
+		switch p.line[p.pos] {
+		case '[':
+			depth++
+		case ']':
+			if depth > 0 {
+				depth--
+			}
+		case ' ', '(', ')':
+			if depth == 0 {
+				return p.line[start:p.pos]
+			}
+		}
+	}
+	return p.line[start:]
+}
+
+// seqRange - диапазон номеров или UID, 0 означает "*"
+type seqRange struct {
+	from, to uint32
+}
+
+// seqSet - набор номеров писем вида 1,3:5,7:* (RFC 3501, 9: sequence-set)
+type seqSet []seqRange
+
+func parseSeqSet(text string) (seqSet, error) {
+	if text == "" {
+		return nil, fmt.Errorf("%w: empty sequence set", errSyntax)
+	}
+
+	var set seqSet
+	for _, item := range strings.Split(text, ",") {
+		from, to, isRange := strings.Cut(item, ":")
+		first, err := parseSeqNumber(from)
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if isRange {
+			if last, err = parseSeqNumber(to); err != nil {
+				return nil, err
+			}
+		}
+		set = append(set, seqRange{from: first, to: last})
+	}
+	return set, nil
+}
+
+func parseSeqNumber(text string) (uint32, error) {
+	if text == "*" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(text, 10, 32)
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf("%w: invalid sequence number %q", errSyntax, text)
+	}
+	return uint32(n), nil
+}
+
+// contains проверяет номер, "*" заменяется на max - наибольший номер в папке
+func (set seqSet) contains(n, max uint32) bool {
+	for _, r := range set {
+		from, to := r.from, r.to
+		if from == 0 {
+			from = max
+		}
+		if to == 0 {
+			to = max
+		}
+		if from > to {
+			from, to = to, from
+		}
+		if n >= from && n <= to {
+			return true
+		}
+	}
+	return false
+}
+
+// formatSeqSet записывает отсортированные номера диапазонами для ответов COPYUID
+func formatSeqSet(numbers []uint32) string {
+	numbers = append([]uint32(nil), numbers...)
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+
+	var b strings.Builder
+	for i := 0; i < len(numbers); {
+		j := i
+		for j+1 < len(numbers) && numbers[j+1] <= numbers[j]+1 {
+			j++
+		}
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatUint(uint64(numbers[i]), 10))
+		if numbers[j] != numbers[i] {
+			b.WriteByte(':')
+			b.WriteString(strconv.FormatUint(uint64(numbers[j]), 10))
+		}
+		i = j + 1
+	}
+	return b.String()
+}
+
+// parseDate разбирает дату вида 2-Jan-2006 из SEARCH
+func parseDate(text string) (time.Time, error) {
+	date, err := time.Parse("2-Jan-2006", text)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid date %q", errSyntax, text)
+	}
+	return date, nil
+}
+
+// parseDateTime разбирает дату вида " 2-Jan-2006 15:04:05 -0700" из APPEND (RFC 3501, 9: date-time)
+func parseDateTime(text string) (time.Time, error) {
+	date, err := time.Parse("_2-Jan-2006 15:04:05 -0700", text)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid date-time %q", errSyntax, text)
+	}
+	return date, nil
+}
+
+// quote записывает строку для ответа: в кавычках или литералом, если в ней
+// есть переводы строк, кавычки или 8-битные символы
+func quote(text string) string {
+	for i := 0; i < len(text); i++ {
+		if c := text[i]; c == '\r' || c == '\n' || c == 0 || c > 127 {
+			return fmt.Sprintf("{%d}\r\n%s", len(text), text)
+		}
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(text) + `"`
+}
+
+// nstring - строка или NIL для пустого значения
+func nstring(text string) string {
+	if text == "" {
+		return "NIL"
+	}
+	return quote(text)
+}
@@ -0,0 +1,81 @@
+package imap_server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMailboxName_UTF7(t *testing.T) {
+	tests := map[string]string{
+		"INBOX":         "INBOX",
+		"Корзина":       "&BBoEPgRABDcEOAQ9BDA-",
+		"Tom & Jerry":   "Tom &- Jerry",
+		"Отчеты/2025 г": "&BB4EQgRHBDUEQgRL-/2025 &BDM-",
+		"日本語":           "&ZeVnLIqe-",
+	}
+	for name, encoded := range tests {
+		assert.Equal(t, encoded, encodeMailboxName(name))
+		decoded, err := decodeMailboxName(encoded)
+		require.NoError(t, err)
+		assert.Equal(t, name, decoded)
+	}
+
+	for _, invalid := range []string{"&BBo", "&BB-", "Ящик"} {
+		_, err := decodeMailboxName(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestParser_Values(t *testing.T) {
+	p := parser{line: `1:* (UID BODY.PEEK[HEADER.FIELDS (From To)]<0.100>) "a \"b\"" NIL`}
+	values, err := p.parseValues(0)
+	require.NoError(t, err)
+	require.Len(t, values, 4)
+
+	assert.Equal(t, value{kind: atomValue, text: "1:*"}, values[0])
+	assert.Equal(t, listValue, values[1].kind)
+	assert.Equal(t, []value{
+		{kind: atomValue, text: "UID"},
+		{kind: atomValue, text: "BODY.PEEK[HEADER.FIELDS (From To)]<0.100>"},
+	}, values[1].list)
+	assert.Equal(t, value{kind: stringValue, text: `a "b"`}, values[2])
+	assert.True(t, values[3].isAtom("nil"))
+
+	for _, invalid := range []string{`(UID`, `UID)`, `"open`, `{abc}`} {
+		p := parser{line: invalid}
+		_, err := p.parseValues(0)
+		assert.ErrorIs(t, err, errSyntax, invalid)
+	}
+}
+
+func TestSeqSet(t *testing.T) {
+	set, err := parseSeqSet("2,4:5,9:*")
+	require.NoError(t, err)
+
+	var got []uint32
+	for n := uint32(1); n <= 12; n++ {
+		if set.contains(n, 12) {
+			got = append(got, n)
+		}
+	}
+	assert.Equal(t, []uint32{2, 4, 5, 9, 10, 11, 12}, got)
+	// "*" - наибольший номер, даже если он меньше начала диапазона
+	assert.True(t, set.contains(3, 3))
+
+	for _, invalid := range []string{"", "0", "1:", "a", "1,,2"} {
+		_, err := parseSeqSet(invalid)
+		assert.ErrorIs(t, err, errSyntax, invalid)
+	}
+
+	assert.Equal(t, "1:3,5,7:8", formatSeqSet([]uint32{8, 1, 2, 3, 5, 7}))
+}
+
+func TestSplitMultipart(t *testing.T) {
+	body := "preamble\r\n--b\r\nContent-Type: text/plain\r\n\r\nfirst\r\n--b \r\n\r\nsecond\r\n--b--\r\nepilogue"
+	parts := splitMultipart([]byte(body), "b")
+	require.Len(t, parts, 2)
+	assert.Equal(t, "Content-Type: text/plain\r\n\r\nfirst", string(parts[0]))
+	assert.Equal(t, "\r\nsecond", string(parts[1]))
+}
@@ -0,0 +1,335 @@
+package imap_server
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// matcher проверяет письмо с номером seq на соответствие критерию SEARCH
+type matcher func(ctx context.Context, seq uint32, m *message) (bool, error)
+
+// searchParser разбирает критерии SEARCH (RFC 3501, 6.4.4)
+type searchParser struct {
+	s    *session
+	args []value
+	pos  int
+}
+
+func (s *session) handleSearch(cmd *command) string {
+	args := cmd.args
+	if len(args) >= 2 && args[0].isAtom("CHARSET") {
+		charset, _ := args[1].astring()
+		if !strings.EqualFold(charset, "UTF-8") && !strings.EqualFold(charset, "US-ASCII") {
+			return s.reply(cmd.tag, "NO", "[BADCHARSET (UTF-8 US-ASCII)] Unsupported charset")
+		}
+		args = args[2:]
+	}
+	if len(args) == 0 {
+		return s.reply(cmd.tag, "BAD", "Syntax: SEARCH criteria")
+	}
+
+	p := &searchParser{s: s, args: args}
+	match, err := p.parseAll()
+	if err != nil {
+		return s.reply(cmd.tag, "BAD", err.Error())
+	}
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	var found []string
+	for i, m := range s.mailbox.messages {
+		ok, err := match(ctx, uint32(i+1), m)
+		if errors.Is(err, domain.ErrMessageNotFound) {
+			continue
+		}
+		if err != nil {
+			return s.fail(cmd, "failed to search messages", err)
+		}
+		if !ok {
+			continue
+		}
+		if cmd.uid {
+			found = append(found, strconv.FormatUint(uint64(m.uid), 10))
+		} else {
+			found = append(found, strconv.Itoa(i+1))
+		}
+	}
+
+	if len(found) == 0 {
+		s.untagged("SEARCH")
+	} else {
+		s.untagged("SEARCH %s", strings.Join(found, " "))
+	}
+	return s.reply(cmd.tag, "OK", "SEARCH completed")
+}
+
+// parseAll разбирает все критерии, письмо должно подходить под каждый
+func (p *searchParser) parseAll() (matcher, error) {
+	var matchers []matcher
+	for p.pos < len(p.args) {
+		match, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, match)
+	}
+	return allOf(matchers), nil
+}
+
+func allOf(matchers []matcher) matcher {
+	return func(ctx context.Context, seq uint32, m *message) (bool, error) {
+		for _, match := range matchers {
+			ok, err := match(ctx, seq, m)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+}
+
+func flagMatcher(get func(m *message) bool, want bool) matcher {
+	return func(ctx context.Context, seq uint32, m *message) (bool, error) {
+		return get(m) == want, nil
+	}
+}
+
+func constMatcher(result bool) matcher {
+	return func(ctx context.Context, seq uint32, m *message) (bool, error) {
+		return result, nil
+	}
+}
+
+func (p *searchParser) next() (value, error) {
+	if p.pos >= len(p.args) {
+		return value{}, fmt.Errorf("%w: missing search argument", errSyntax)
+	}
+	arg := p.args[p.pos]
+	p.pos++
+	return arg, nil
+}
+
+func (p *searchParser) nextString() (string, error) {
+	arg, err := p.next()
+	if err != nil {
+		return "", err
+	}
+	text, ok := arg.astring()
+	if !ok {
+		return "", fmt.Errorf("%w: expected string", errSyntax)
+	}
+	return text, nil
+}
+
+func (p *searchParser) nextDate() (time.Time, error) {
+	text, err := p.nextString()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parseDate(text)
+}
+
+func (p *searchParser) nextNumber() (int, error) {
+	text, err := p.nextString()
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: invalid number %q", errSyntax, text)
+	}
+	return n, nil
+}
+
+func (p *searchParser) parseKey() (matcher, error) {
+	arg, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if arg.kind == listValue {
+		sub := &searchParser{s: p.s, args: arg.list}
+		return sub.parseAll()
+	}
+	if arg.kind != atomValue {
+		return nil, fmt.Errorf("%w: unexpected string", errSyntax)
+	}
+
+	mb := p.s.mailbox
+	key := strings.ToUpper(arg.text)
+	if c := key[0]; c == '*' || (c >= '0' && c <= '9') {
+		set, err := parseSeqSet(key)
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, seq uint32, m *message) (bool, error) {
+			return set.contains(seq, uint32(len(mb.messages))), nil
+		}, nil
+	}
+
+	switch key {
+	case "ALL", "OLD":
+		return constMatcher(true), nil
+	case "NEW", "RECENT", "DRAFT":
+		// Флаги \Recent и \Draft не поддерживаются
+		return constMatcher(false), nil
+	case "UNDRAFT":
+		return constMatcher(true), nil
+	case "SEEN", "UNSEEN":
+		return flagMatcher(func(m *message) bool { return m.seen }, key == "SEEN"), nil
+	case "FLAGGED", "UNFLAGGED":
+		return flagMatcher(func(m *message) bool { return m.flagged }, key == "FLAGGED"), nil
+	case "ANSWERED", "UNANSWERED":
+		return flagMatcher(func(m *message) bool { return m.answered }, key == "ANSWERED"), nil
+	case "DELETED", "UNDELETED":
+		return flagMatcher(func(m *message) bool { return mb.deleted[m.uid] }, key == "DELETED"), nil
+	case "KEYWORD", "UNKEYWORD":
+		keyword, err := p.nextString()
+		if err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(keyword, "$Forwarded") {
+			return constMatcher(key == "UNKEYWORD"), nil
+		}
+		return flagMatcher(func(m *message) bool { return m.forwarded }, key == "KEYWORD"), nil
+	case "NOT":
+		match, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, seq uint32, m *message) (bool, error) {
+			ok, err := match(ctx, seq, m)
+			return !ok, err
+		}, nil
+	case "OR":
+		left, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+		right, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, seq uint32, m *message) (bool, error) {
+			if ok, err := left(ctx, seq, m); err != nil || ok {
+				return ok, err
+			}
+			return right(ctx, seq, m)
+		}, nil
+	case "UID":
+		text, err := p.nextString()
+		if err != nil {
+			return nil, err
+		}
+		set, err := parseSeqSet(text)
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, seq uint32, m *message) (bool, error) {
+			return set.contains(m.uid, mb.uidNext()-1), nil
+		}, nil
+	case "BEFORE", "ON", "SINCE", "SENTBEFORE", "SENTON", "SENTSINCE":
+		// Дата отправки совпадает с датой письма в ящике: письмо собирается с датой из базы
+		date, err := p.nextDate()
+		if err != nil {
+			return nil, err
+		}
+		compare := strings.TrimPrefix(key, "SENT")
+		return func(ctx context.Context, seq uint32, m *message) (bool, error) {
+			year, month, day := m.date.Date()
+			messageDate := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+			switch compare {
+			case "BEFORE":
+				return messageDate.Before(date), nil
+			case "ON":
+				return messageDate.Equal(date), nil
+			}
+			return !messageDate.Before(date), nil
+		}, nil
+	case "LARGER", "SMALLER":
+		size, err := p.nextNumber()
+		if err != nil {
+			return nil, err
+		}
+		return p.contentMatcher(func(c *content) bool {
+			if key == "LARGER" {
+				return len(c.raw) > size
+			}
+			return len(c.raw) < size
+		}), nil
+	case "FROM", "TO", "CC", "BCC", "SUBJECT":
+		text, err := p.nextString()
+		if err != nil {
+			return nil, err
+		}
+		return p.headerMatcher(key, text), nil
+	case "HEADER":
+		field, err := p.nextString()
+		if err != nil {
+			return nil, err
+		}
+		text, err := p.nextString()
+		if err != nil {
+			return nil, err
+		}
+		return p.headerMatcher(field, text), nil
+	case "BODY", "TEXT":
+		text, err := p.nextString()
+		if err != nil {
+			return nil, err
+		}
+		return p.contentMatcher(func(c *content) bool {
+			if containsFold(c.searchText(), text) {
+				return true
+			}
+			return key == "TEXT" && containsFold(decodeHeader(string(c.root.headerRaw)), text)
+		}), nil
+	}
+	return nil, fmt.Errorf("%w: unknown search key %s", errSyntax, arg.text)
+}
+
+// contentMatcher проверяет собранное письмо
+func (p *searchParser) contentMatcher(check func(c *content) bool) matcher {
+	return func(ctx context.Context, seq uint32, m *message) (bool, error) {
+		c, err := p.s.content(ctx, m.uid)
+		if err != nil {
+			return false, err
+		}
+		return check(c), nil
+	}
+}
+
+// headerMatcher ищет подстроку в поле заголовка без учета регистра, пустая строка - поле есть в письме
+func (p *searchParser) headerMatcher(field, text string) matcher {
+	return p.contentMatcher(func(c *content) bool {
+		values, ok := c.root.header[textproto.CanonicalMIMEHeaderKey(field)]
+		if !ok {
+			return false
+		}
+		for _, v := range values {
+			if containsFold(decodeHeader(v), text) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func decodeHeader(value string) string {
+	decoded, err := new(mime.WordDecoder).DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+func containsFold(text, substr string) bool {
+	return strings.Contains(strings.ToLower(text), strings.ToLower(substr))
+}
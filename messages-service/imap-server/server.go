@@ -0,0 +1,218 @@
+package imap_server
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/lib/events"
+	"context"
+	"crypto/tls"
+	"errors"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTimeout - время бездействия до автоматического выхода (RFC 3501, 5.4: не меньше 30 минут)
+	DefaultTimeout = 30 * time.Minute
+	// DefaultIdlePoll - как часто во время IDLE перечитывается выбранная папка
+	DefaultIdlePoll = time.Minute
+	// DefaultCacheSize - сколько байт собранных писем хранит одна сессия
+	DefaultCacheSize = 32 << 20
+)
+
+var ErrServerClosed = errors.New("imap: server closed")
+
+// MessageUsecase - папки и письма пользователя
+type MessageUsecase interface {
+	GetUserFolders(ctx context.Context, profileID int64) ([]domain.Folder, error)
+	CreateFolder(ctx context.Context, profileID int64, folderName string) (*domain.Folder, error)
+	CreateSubfolder(ctx context.Context, profileID, parentID int64, folderName string) (*domain.Folder, error)
+	RenameFolder(ctx context.Context, profileID, folderID int64, newName string) (*domain.Folder, error)
+	MoveFolder(ctx context.Context, profileID, folderID, newParentID int64) (*domain.Folder, error)
+	DeleteFolder(ctx context.Context, profileID, folderID int64) error
+
+	GetFolderMessagesWithKeysetPagination(ctx context.Context, profileID, folderID, labelID int64, page domain.MessagePage) ([]domain.Message, error)
+	ExportMessage(ctx context.Context, profileID, messageID int64) ([]byte, error)
+	SetFlags(ctx context.Context, profileID, messageID int64, update domain.FlagsUpdate) error
+	CopyToFolder(ctx context.Context, profileID, messageID, folderID int64) error
+	MoveToFolder(ctx context.Context, profileID, messageID, folderID int64) error
+	DeleteMessageFromFolder(ctx context.Context, profileID, messageID, folderID int64) error
+	FindSubmittedMessage(ctx context.Context, profileID int64, messageIDHeader string) (int64, error)
+}
+
+// Authenticator проверяет логин и пароль почтового клиента и возвращает профиль пользователя.
+// remoteIP - адрес клиента, он показывается пользователю в списке паролей приложений
+type Authenticator interface {
+	Authenticate(ctx context.Context, login, password, remoteIP string) (int64, error)
+}
+
+// EventSource - события об изменениях почтовых ящиков, по которым IDLE сообщает клиенту о новых письмах
+type EventSource interface {
+	Subscribe(profileID, lastEventID int64) *events.Subscription
+}
+
+type Config struct {
+	// Hostname - имя сервера в приветствии
+	Hostname string
+	// TLSConfig включает STARTTLS и ListenAndServeTLS, nil - соединения только без шифрования
+	TLSConfig *tls.Config
+	// AllowInsecureAuth разрешает вход без TLS, пароль при этом передается открытым текстом
+	AllowInsecureAuth bool
+	Timeout           time.Duration
+	IdlePoll          time.Duration
+	CacheSize         int
+}
+
+// Server - IMAP4rev1-сервер (RFC 3501) для почтовых клиентов. Папки пользователя
+// отдаются как почтовые ящики, UID письма - его идентификатор
+type Server struct {
+	cfg          Config
+	messageUCase MessageUsecase
+	auth         Authenticator
+	events       EventSource
+	log          *slog.Logger
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+func New(messageUCase MessageUsecase, auth Authenticator, cfg Config, log *slog.Logger) *Server {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.IdlePoll <= 0 {
+		cfg.IdlePoll = DefaultIdlePoll
+	}
+	if cfg.CacheSize <= 0 {
+		cfg.CacheSize = DefaultCacheSize
+	}
+	if log == nil {
+		log = slog.Default()
+	}
+
+	return &Server{
+		cfg:          cfg,
+		messageUCase: messageUCase,
+		auth:         auth,
+		log:          log,
+		listeners:    make(map[net.Listener]struct{}),
+		conns:        make(map[net.Conn]struct{}),
+	}
+}
+
+// SetEventSource включает уведомления о новых письмах во время IDLE без ожидания
+// очередной проверки папки
+func (s *Server) SetEventSource(source EventSource) {
+	s.events = source
+}
+
+func (s *Server) ListenAndServe(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+// ListenAndServeTLS принимает соединения, зашифрованные с самого начала (порт 993)
+func (s *Server) ListenAndServeTLS(addr string) error {
+	if s.cfg.TLSConfig == nil {
+		return errors.New("imap: TLS is not configured")
+	}
+	listener, err := tls.Listen("tcp", addr, s.cfg.TLSConfig)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+// Serve принимает соединения, пока listener не будет закрыт. Каждое соединение
+// обслуживается в отдельной горутине
+func (s *Server) Serve(listener net.Listener) error {
+	if !s.track(listener, true) {
+		listener.Close()
+		return ErrServerClosed
+	}
+	defer s.track(listener, false)
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return ErrServerClosed
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				time.Sleep(100 * time.Millisecond)
+				continue
+			}
+			return err
+		}
+
+		if !s.trackConn(conn, true) {
+			conn.Close()
+			return ErrServerClosed
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer s.trackConn(conn, false)
+			newSession(s, conn).serve()
+		}()
+	}
+}
+
+// Close закрывает все listener'ы и соединения и ждет завершения сессий
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for listener := range s.listeners {
+		listener.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return nil
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+func (s *Server) track(listener net.Listener, add bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !add {
+		delete(s.listeners, listener)
+		return true
+	}
+	if s.closed {
+		return false
+	}
+	s.listeners[listener] = struct{}{}
+	return true
+}
+
+func (s *Server) trackConn(conn net.Conn, add bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !add {
+		delete(s.conns, conn)
+		conn.Close()
+		return true
+	}
+	if s.closed {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	return true
+}
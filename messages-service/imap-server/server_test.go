@@ -0,0 +1,605 @@
+package imap_server
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/lib/events"
+	"2025_2_a4code/internal/lib/mailbuild"
+	profileUcase "2025_2_a4code/internal/usecase/profile"
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/mail"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeUsecase struct {
+	mu       sync.Mutex
+	folders  []domain.Folder
+	inFolder map[int64][]int64
+	messages map[int64]*domain.Message
+	raw      map[int64][]byte
+	updates  map[int64]domain.FlagsUpdate
+	moved    map[int64]int64
+	nextID   int64
+	// submitted - письма, отправленные через сервер отправки, по Message-Id
+	submitted map[string]int64
+}
+
+func (f *fakeUsecase) GetUserFolders(ctx context.Context, profileID int64) ([]domain.Folder, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.folders), nil
+}
+
+func (f *fakeUsecase) CreateFolder(ctx context.Context, profileID int64, folderName string) (*domain.Folder, error) {
+	return f.CreateSubfolder(ctx, profileID, 0, folderName)
+}
+
+func (f *fakeUsecase) CreateSubfolder(ctx context.Context, profileID, parentID int64, folderName string) (*domain.Folder, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	folder := domain.Folder{ID: f.nextID, ParentID: parentID, Name: folderName, Type: domain.FolderCustom}
+	f.folders = append(f.folders, folder)
+	return &folder, nil
+}
+
+func (f *fakeUsecase) RenameFolder(ctx context.Context, profileID, folderID int64, newName string) (*domain.Folder, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := range f.folders {
+		if f.folders[i].ID == folderID {
+			f.folders[i].Name = newName
+			return &f.folders[i], nil
+		}
+	}
+	return nil, domain.ErrFolderNotFound
+}
+
+func (f *fakeUsecase) MoveFolder(ctx context.Context, profileID, folderID, newParentID int64) (*domain.Folder, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := range f.folders {
+		if f.folders[i].ID == folderID {
+			f.folders[i].ParentID = newParentID
+			return &f.folders[i], nil
+		}
+	}
+	return nil, domain.ErrFolderNotFound
+}
+
+func (f *fakeUsecase) DeleteFolder(ctx context.Context, profileID, folderID int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, folder := range f.folders {
+		if folder.ID == folderID {
+			if folder.Type != domain.FolderCustom {
+				return domain.ErrFolderSystem
+			}
+			f.folders = append(f.folders[:i], f.folders[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrFolderNotFound
+}
+
+func (f *fakeUsecase) GetFolderMessagesWithKeysetPagination(ctx context.Context, profileID, folderID, labelID int64, page domain.MessagePage) ([]domain.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if page.After != nil {
+		return nil, nil
+	}
+	var result []domain.Message
+	for _, id := range f.inFolder[folderID] {
+		result = append(result, *f.messages[id])
+	}
+	return result, nil
+}
+
+func (f *fakeUsecase) ExportMessage(ctx context.Context, profileID, messageID int64) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	raw, ok := f.raw[messageID]
+	if !ok {
+		return nil, domain.ErrMessageNotFound
+	}
+	return raw, nil
+}
+
+func (f *fakeUsecase) SetFlags(ctx context.Context, profileID, messageID int64, update domain.FlagsUpdate) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	msg, ok := f.messages[messageID]
+	if !ok {
+		return domain.ErrMessageNotFound
+	}
+	if update.Read != nil {
+		msg.IsRead = *update.Read
+	}
+	if update.Starred != nil {
+		msg.IsStarred = *update.Starred
+	}
+	if update.Answered != nil {
+		msg.IsAnswered = *update.Answered
+	}
+	if update.Forwarded != nil {
+		msg.IsForwarded = *update.Forwarded
+	}
+	f.updates[messageID] = update
+	return nil
+}
+
+func (f *fakeUsecase) CopyToFolder(ctx context.Context, profileID, messageID, folderID int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !slices.Contains(f.inFolder[folderID], messageID) {
+		f.inFolder[folderID] = append(f.inFolder[folderID], messageID)
+	}
+	return nil
+}
+
+func (f *fakeUsecase) MoveToFolder(ctx context.Context, profileID, messageID, folderID int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for id, ids := range f.inFolder {
+		f.inFolder[id] = slices.DeleteFunc(ids, func(id int64) bool { return id == messageID })
+	}
+	f.inFolder[folderID] = append(f.inFolder[folderID], messageID)
+	f.moved[messageID] = folderID
+	return nil
+}
+
+func (f *fakeUsecase) DeleteMessageFromFolder(ctx context.Context, profileID, messageID, folderID int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inFolder[folderID] = slices.DeleteFunc(f.inFolder[folderID], func(id int64) bool { return id == messageID })
+	return nil
+}
+
+func (f *fakeUsecase) FindSubmittedMessage(ctx context.Context, profileID int64, messageIDHeader string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	messageID, ok := f.submitted[messageIDHeader]
+	if !ok {
+		return 0, domain.ErrMessageNotFound
+	}
+	return messageID, nil
+}
+
+// addMessage кладет письмо в папку и собирает его так же, как ExportMessage
+func (f *fakeUsecase) addMessage(t *testing.T, folderID, id int64, msg mailbuild.Message) {
+	t.Helper()
+	raw, err := mailbuild.Build(msg)
+	require.NoError(t, err)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.messages[id] = &domain.Message{ID: strconv.FormatInt(id, 10), Topic: msg.Subject, Datetime: msg.Date}
+	f.raw[id] = raw
+	f.inFolder[folderID] = append(f.inFolder[folderID], id)
+}
+
+type fakeAuth map[string]string
+
+func (f fakeAuth) Authenticate(ctx context.Context, login, password, remoteIP string) (int64, error) {
+	if remoteIP != "127.0.0.1" {
+		return 0, fmt.Errorf("unexpected remote ip %s", remoteIP)
+	}
+	expected, ok := f[login]
+	if !ok {
+		return 0, profileUcase.ErrUserNotFound
+	}
+	if password != expected {
+		return 0, profileUcase.ErrWrongPassword
+	}
+	return 1, nil
+}
+
+func newUsecase(t *testing.T) *fakeUsecase {
+	uc := &fakeUsecase{
+		folders: []domain.Folder{
+			{ID: 1, Name: "Входящие", Type: domain.FolderInbox},
+			{ID: 2, Name: "Отправленные", Type: domain.FolderSent},
+			{ID: 3, Name: "Корзина", Type: domain.FolderTrash},
+			{ID: 4, Name: "Проекты", Type: domain.FolderCustom},
+			{ID: 5, ParentID: 4, Name: "Work", Type: domain.FolderCustom},
+		},
+		inFolder:  map[int64][]int64{},
+		messages:  map[int64]*domain.Message{},
+		raw:       map[int64][]byte{},
+		updates:   map[int64]domain.FlagsUpdate{},
+		moved:     map[int64]int64{},
+		nextID:    5,
+		submitted: map[string]int64{},
+	}
+
+	uc.addMessage(t, 1, 10, mailbuild.Message{
+		From:        mail.Address{Name: "Иван", Address: "ivan@example.com"},
+		To:          "alexey@flintmail.ru",
+		Subject:     "Отчет",
+		Text:        "Отчет во вложении",
+		Date:        time.Date(2025, 11, 3, 12, 0, 0, 0, time.UTC),
+		MessageID:   "<10@example.com>",
+		Attachments: []domain.Attachment{{Name: "report.pdf", ContentType: "application/pdf", Data: []byte("%PDF-1.4")}},
+		Boundary:    "=_flintmail_10",
+	})
+	uc.addMessage(t, 1, 7, mailbuild.Message{
+		From:      mail.Address{Address: "maria@flintmail.ru"},
+		To:        "alexey@flintmail.ru",
+		Subject:   "Hello",
+		Text:      "Hi there",
+		Date:      time.Date(2025, 11, 1, 9, 0, 0, 0, time.UTC),
+		MessageID: "<7@flintmail.ru>",
+	})
+	uc.messages[7].IsRead = true
+	return uc
+}
+
+func startServer(t *testing.T, uc *fakeUsecase, cfg Config, hub *events.Hub) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	cfg.Hostname = "imap.flintmail.ru"
+	server := New(uc, fakeAuth{"alexey": "secret"}, cfg, nil)
+	if hub != nil {
+		server.SetEventSource(hub)
+	}
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	return listener.Addr().String()
+}
+
+// client - IMAP-клиент для тестов, ответы читаются вместе с литералами
+type client struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+	tag    int
+}
+
+func dial(t *testing.T, addr string) (*client, string) {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	c := &client{t: t, conn: conn, reader: bufio.NewReader(conn)}
+	return c, c.readLine()
+}
+
+func (c *client) readLine() string {
+	c.t.Helper()
+	var b strings.Builder
+	for {
+		line, err := c.reader.ReadString('\n')
+		require.NoError(c.t, err)
+		b.WriteString(line)
+
+		trimmed := strings.TrimRight(line, "\r\n")
+		open := strings.LastIndexByte(trimmed, '{')
+		if !strings.HasSuffix(trimmed, "}") || open < 0 {
+			return strings.TrimRight(b.String(), "\r\n")
+		}
+		size, err := strconv.Atoi(trimmed[open+1 : len(trimmed)-1])
+		require.NoError(c.t, err)
+		data := make([]byte, size)
+		_, err = io.ReadFull(c.reader, data)
+		require.NoError(c.t, err)
+		b.Write(data)
+	}
+}
+
+func (c *client) send(line string) string {
+	c.t.Helper()
+	c.tag++
+	tag := fmt.Sprintf("a%d", c.tag)
+	_, err := fmt.Fprintf(c.conn, "%s %s\r\n", tag, line)
+	require.NoError(c.t, err)
+	return tag
+}
+
+// cmd отправляет команду и возвращает ответы без метки и завершающий ответ
+func (c *client) cmd(line string) ([]string, string) {
+	c.t.Helper()
+	tag := c.send(line)
+	return c.wait(tag)
+}
+
+func (c *client) wait(tag string) ([]string, string) {
+	c.t.Helper()
+	var untagged []string
+	for {
+		line := c.readLine()
+		if strings.HasPrefix(line, tag+" ") {
+			return untagged, strings.TrimPrefix(line, tag+" ")
+		}
+		untagged = append(untagged, line)
+	}
+}
+
+func (c *client) login() {
+	c.t.Helper()
+	_, status := c.cmd("LOGIN alexey secret")
+	require.True(c.t, strings.HasPrefix(status, "OK"), status)
+}
+
+func (c *client) selectInbox() []string {
+	c.t.Helper()
+	c.login()
+	lines, status := c.cmd("SELECT INBOX")
+	require.True(c.t, strings.HasPrefix(status, "OK [READ-WRITE]"), status)
+	return lines
+}
+
+func TestServer_Login(t *testing.T) {
+	t.Run("LoginDisabledWithoutTLS", func(t *testing.T) {
+		c, greeting := dial(t, startServer(t, newUsecase(t), Config{}, nil))
+		assert.Contains(t, greeting, "LOGINDISABLED")
+
+		_, status := c.cmd("LOGIN alexey secret")
+		assert.Equal(t, "NO [PRIVACYREQUIRED] Use STARTTLS first", status)
+	})
+
+	t.Run("WrongPassword", func(t *testing.T) {
+		c, greeting := dial(t, startServer(t, newUsecase(t), Config{AllowInsecureAuth: true}, nil))
+		assert.Contains(t, greeting, "AUTH=PLAIN")
+
+		_, status := c.cmd("LOGIN alexey wrong")
+		assert.Equal(t, "NO [AUTHENTICATIONFAILED] Invalid credentials", status)
+		_, status = c.cmd("SELECT INBOX")
+		assert.Equal(t, "BAD Log in first", status)
+	})
+
+	t.Run("AuthenticatePlain", func(t *testing.T) {
+		c, _ := dial(t, startServer(t, newUsecase(t), Config{AllowInsecureAuth: true}, nil))
+
+		tag := c.send("AUTHENTICATE PLAIN")
+		assert.Equal(t, "+ ", c.readLine())
+		fmt.Fprintf(c.conn, "%s\r\n", base64.StdEncoding.EncodeToString([]byte("\x00alexey\x00secret")))
+		_, status := c.wait(tag)
+		assert.True(t, strings.HasPrefix(status, "OK [CAPABILITY IMAP4rev1"), status)
+		assert.NotContains(t, status, "AUTH=PLAIN")
+	})
+
+	t.Run("LiteralPassword", func(t *testing.T) {
+		c, _ := dial(t, startServer(t, newUsecase(t), Config{AllowInsecureAuth: true}, nil))
+
+		tag := c.send("LOGIN alexey {6}")
+		assert.True(t, strings.HasPrefix(c.readLine(), "+ "))
+		fmt.Fprintf(c.conn, "secret\r\n")
+		_, status := c.wait(tag)
+		assert.True(t, strings.HasPrefix(status, "OK"), status)
+	})
+}
+
+func TestServer_List(t *testing.T) {
+	c, _ := dial(t, startServer(t, newUsecase(t), Config{AllowInsecureAuth: true}, nil))
+	c.login()
+
+	lines, status := c.cmd(`LIST "" "*"`)
+	assert.Equal(t, "OK LIST completed", status)
+	assert.Equal(t, []string{
+		`* LIST (\HasNoChildren) "/" "INBOX"`,
+		`* LIST (\HasNoChildren \Trash) "/" "&BBoEPgRABDcEOAQ9BDA-"`,
+		`* LIST (\HasNoChildren \Sent) "/" "&BB4EQgQ,BEAEMAQyBDsENQQ9BD0ESwQ1-"`,
+		`* LIST (\HasChildren) "/" "&BB8EQAQ+BDUEOgRCBEs-"`,
+		`* LIST (\HasNoChildren) "/" "&BB8EQAQ+BDUEOgRCBEs-/Work"`,
+	}, lines)
+
+	lines, _ = c.cmd(`LIST "" "%"`)
+	assert.Len(t, lines, 4)
+
+	lines, _ = c.cmd(`LIST "" ""`)
+	assert.Equal(t, []string{`* LIST (\Noselect) "/" ""`}, lines)
+
+	lines, status = c.cmd(`STATUS INBOX (MESSAGES UIDNEXT UIDVALIDITY UNSEEN)`)
+	assert.Equal(t, "OK STATUS completed", status)
+	assert.Equal(t, []string{`* STATUS "INBOX" (MESSAGES 2 UIDNEXT 11 UIDVALIDITY 1 UNSEEN 1)`}, lines)
+}
+
+func TestServer_SelectAndFetch(t *testing.T) {
+	uc := newUsecase(t)
+	c, _ := dial(t, startServer(t, uc, Config{AllowInsecureAuth: true}, nil))
+
+	lines := c.selectInbox()
+	assert.Contains(t, lines, "* 2 EXISTS")
+	assert.Contains(t, lines, "* OK [UNSEEN 2] First unseen")
+	assert.Contains(t, lines, "* OK [UIDVALIDITY 1] UIDs valid")
+	assert.Contains(t, lines, "* OK [UIDNEXT 11] Predicted next UID")
+
+	lines, status := c.cmd("FETCH 1:* (UID FLAGS)")
+	assert.Equal(t, "OK FETCH completed", status)
+	assert.Equal(t, []string{`* 1 FETCH (UID 7 FLAGS (\Seen))`, `* 2 FETCH (UID 10 FLAGS ())`}, lines)
+
+	lines, _ = c.cmd("UID FETCH 10 (BODY.PEEK[HEADER.FIELDS (Subject Message-ID)])")
+	require.Len(t, lines, 1)
+	assert.Equal(t, "* 2 FETCH (UID 10 BODY[HEADER.FIELDS (Subject Message-ID)] {85}\r\n"+
+		"Subject: =?utf-8?q?=D0=9E=D1=82=D1=87=D0=B5=D1=82?=\r\nMessage-Id: <10@example.com>\r\n\r\n)", lines[0])
+	assert.Empty(t, uc.updates)
+
+	lines, _ = c.cmd("UID FETCH 10 BODYSTRUCTURE")
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"MIXED" ("BOUNDARY" "=_flintmail_10")`)
+	assert.Contains(t, lines[0], `("APPLICATION" "PDF" ("NAME" "report.pdf") NIL NIL "BASE64" 14 NIL ("ATTACHMENT" ("FILENAME" "report.pdf")) NIL NIL)`)
+
+	lines, _ = c.cmd("UID FETCH 10 BODY.PEEK[2]")
+	require.Len(t, lines, 1)
+	assert.Equal(t, "* 2 FETCH (UID 10 BODY[2] {14}\r\n"+base64.StdEncoding.EncodeToString([]byte("%PDF-1.4"))+"\r\n)", lines[0])
+
+	lines, _ = c.cmd("FETCH 1 (ENVELOPE)")
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"Hello" ((NIL NIL "maria" "flintmail.ru"))`)
+	assert.Contains(t, lines[0], `"<7@flintmail.ru>"`)
+
+	lines, _ = c.cmd("FETCH 2 (BODY[]<0.6>)")
+	assert.Equal(t, []string{`* 2 FETCH (BODY[]<0> {6}` + "\r\nFrom:  FLAGS (\\Seen))"}, lines)
+	require.NotNil(t, uc.updates[10].Read)
+	assert.True(t, *uc.updates[10].Read)
+
+	_, status = c.cmd("FETCH 3 FLAGS")
+	assert.Equal(t, "BAD Invalid message sequence number", status)
+}
+
+func TestServer_StoreSearchExpunge(t *testing.T) {
+	uc := newUsecase(t)
+	c, _ := dial(t, startServer(t, uc, Config{AllowInsecureAuth: true}, nil))
+	c.selectInbox()
+
+	lines, status := c.cmd(`STORE 2 +FLAGS (\Flagged)`)
+	assert.Equal(t, "OK STORE completed", status)
+	assert.Equal(t, []string{`* 2 FETCH (FLAGS (\Flagged))`}, lines)
+	require.NotNil(t, uc.updates[10].Starred)
+	assert.True(t, *uc.updates[10].Starred)
+
+	lines, _ = c.cmd("SEARCH FLAGGED")
+	assert.Equal(t, []string{"* SEARCH 2"}, lines)
+	lines, _ = c.cmd("UID SEARCH CHARSET UTF-8 SUBJECT {6+}\r\nотч")
+	assert.Equal(t, []string{"* SEARCH 10"}, lines)
+	lines, _ = c.cmd(`SEARCH OR FROM maria BODY "вложении" NOT SEEN`)
+	assert.Equal(t, []string{"* SEARCH 2"}, lines)
+	lines, _ = c.cmd("SEARCH SINCE 2-Nov-2025")
+	assert.Equal(t, []string{"* SEARCH 2"}, lines)
+
+	lines, _ = c.cmd(`UID STORE 7 +FLAGS.SILENT (\Deleted)`)
+	assert.Empty(t, lines)
+	lines, status = c.cmd("EXPUNGE")
+	assert.Equal(t, "OK EXPUNGE completed", status)
+	assert.Equal(t, []string{"* 1 EXPUNGE"}, lines)
+	assert.Equal(t, []int64{10}, uc.inFolder[1])
+
+	lines, _ = c.cmd("FETCH 1 UID")
+	assert.Equal(t, []string{"* 1 FETCH (UID 10)"}, lines)
+}
+
+func TestServer_CopyMove(t *testing.T) {
+	uc := newUsecase(t)
+	c, _ := dial(t, startServer(t, uc, Config{AllowInsecureAuth: true}, nil))
+	c.selectInbox()
+
+	_, status := c.cmd("UID COPY 7:10 &BB8EQAQ+BDUEOgRCBEs-/Work")
+	assert.Equal(t, "OK [COPYUID 5 7,10 7,10] COPY completed", status)
+	assert.Equal(t, []int64{7, 10}, uc.inFolder[5])
+
+	_, status = c.cmd("COPY 1 Missing")
+	assert.Equal(t, "NO [TRYCREATE] Mailbox does not exist", status)
+
+	lines, status := c.cmd("MOVE 1 &BB8EQAQ+BDUEOgRCBEs-")
+	assert.Equal(t, "OK MOVE completed", status)
+	assert.Equal(t, []string{"* OK [COPYUID 4 7 7] Moved", "* 1 EXPUNGE"}, lines)
+	assert.Equal(t, []int64{10}, uc.inFolder[1])
+	assert.Equal(t, []int64{7}, uc.inFolder[4])
+
+	// Перенос в корзину запоминает папку, из которой письмо можно восстановить
+	lines, _ = c.cmd("UID MOVE 10 &BBoEPgRABDcEOAQ9BDA-")
+	assert.Equal(t, []string{"* OK [COPYUID 3 10 10] Moved", "* 1 EXPUNGE"}, lines)
+	assert.Equal(t, int64(3), uc.moved[10])
+}
+
+func TestServer_Append(t *testing.T) {
+	uc := newUsecase(t)
+	uc.addMessage(t, 2, 20, mailbuild.Message{
+		From:    mail.Address{Address: "alexey@flintmail.ru"},
+		To:      "ivan@example.com",
+		Subject: "Sent",
+		Text:    "sent from client",
+		Date:    time.Date(2025, 11, 4, 9, 0, 0, 0, time.UTC),
+	})
+	uc.submitted["<abc@thunderbird>"] = 20
+	c, _ := dial(t, startServer(t, uc, Config{AllowInsecureAuth: true}, nil))
+	c.login()
+
+	sent := "Message-ID: <abc@thunderbird>\r\nFrom: alexey@flintmail.ru\r\nSubject: Sent\r\n\r\nsent from client\r\n"
+	other := "Message-ID: <other@thunderbird>\r\nSubject: Draft\r\n\r\ntext\r\n"
+
+	// Копия отправленного письма не сохраняется второй раз, флаги переносятся на существующую
+	_, status := c.cmd(fmt.Sprintf(`APPEND "Отправленные" (\Seen) "04-Nov-2025 09:00:00 +0000" {%d+}`+"\r\n%s", len(sent), sent))
+	assert.Equal(t, "OK [APPENDUID 2 20] APPEND completed", status)
+	assert.Equal(t, []int64{20}, uc.inFolder[2])
+	assert.True(t, uc.messages[20].IsRead)
+
+	tag := c.send(fmt.Sprintf(`APPEND "Отправленные" {%d}`, len(other)))
+	assert.Equal(t, "+ Ready for literal data", c.readLine())
+	fmt.Fprintf(c.conn, "%s\r\n", other)
+	_, status = c.wait(tag)
+	assert.Equal(t, "NO [CANNOT] Message was not sent through this server", status)
+
+	_, status = c.cmd(fmt.Sprintf("APPEND INBOX {%d+}\r\n%s", len(sent), sent))
+	assert.Equal(t, "NO [CANNOT] APPEND is only supported for sent messages", status)
+	_, status = c.cmd(fmt.Sprintf("APPEND Missing {%d+}\r\n%s", len(sent), sent))
+	assert.Equal(t, "NO [TRYCREATE] Mailbox does not exist", status)
+
+	// Слишком большое письмо не запрашивается у клиента
+	_, status = c.cmd(fmt.Sprintf(`APPEND "Отправленные" {%d}`, maxAppendSize+1))
+	assert.Equal(t, "NO [TOOBIG] Literal too large", status)
+}
+
+func TestServer_Folders(t *testing.T) {
+	uc := newUsecase(t)
+	c, _ := dial(t, startServer(t, uc, Config{AllowInsecureAuth: true}, nil))
+	c.login()
+
+	_, status := c.cmd("CREATE Archive/2025")
+	assert.Equal(t, "OK CREATE completed", status)
+	_, status = c.cmd("CREATE archive")
+	assert.Equal(t, "NO [ALREADYEXISTS] Mailbox already exists", status)
+
+	_, status = c.cmd("RENAME Archive/2025 &BB8EQAQ+BDUEOgRCBEs-/Old")
+	assert.Equal(t, "OK RENAME completed", status)
+	lines, _ := c.cmd(`LIST "" "*Old"`)
+	assert.Equal(t, []string{`* LIST (\HasNoChildren) "/" "&BB8EQAQ+BDUEOgRCBEs-/Old"`}, lines)
+
+	_, status = c.cmd("DELETE INBOX")
+	assert.Equal(t, "NO [CANNOT] System mailboxes cannot be changed", status)
+	_, status = c.cmd("DELETE Archive")
+	assert.Equal(t, "OK DELETE completed", status)
+}
+
+func TestServer_Idle(t *testing.T) {
+	uc := newUsecase(t)
+	hub := events.NewHub(0)
+	c, _ := dial(t, startServer(t, uc, Config{AllowInsecureAuth: true, IdlePoll: time.Hour}, hub))
+	c.selectInbox()
+
+	tag := c.send("IDLE")
+	assert.Equal(t, "+ idling", c.readLine())
+
+	uc.addMessage(t, 1, 12, mailbuild.Message{From: mail.Address{Address: "ivan@example.com"}, Subject: "New", Text: "new", Date: time.Now()})
+	// Подписка оформляется после ответа "+", событие публикуется, пока сервер его не получит
+	received := make(chan string, 1)
+	go func() { received <- c.readLine() }()
+	for {
+		hub.Publish(domain.MailEvent{Type: domain.EventNewMessage, ProfileID: 1, MessageID: 12, FolderID: 1})
+		select {
+		case line := <-received:
+			assert.Equal(t, "* 3 EXISTS", line)
+			fmt.Fprintf(c.conn, "DONE\r\n")
+			_, status := c.wait(tag)
+			assert.Equal(t, "OK IDLE terminated", status)
+			return
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+func TestServer_Logout(t *testing.T) {
+	c, _ := dial(t, startServer(t, newUsecase(t), Config{}, nil))
+
+	lines, status := c.cmd("LOGOUT")
+	assert.Equal(t, []string{"* BYE imap.flintmail.ru logging out"}, lines)
+	assert.Equal(t, "OK LOGOUT completed", status)
+}
@@ -0,0 +1,503 @@
+package imap_server
+
+import (
+	profileUcase "2025_2_a4code/internal/usecase/profile"
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	// maxLineLength - ограничение длины строки команды. Клиенты передают длинные наборы UID,
+	// RFC 7162, 4 рекомендует принимать строки не короче 8192 байт
+	maxLineLength = 64 * 1024
+	// maxLiteralSize - ограничение литерала в командах, кроме APPEND
+	maxLiteralSize = 64 * 1024
+	// maxAppendSize - ограничение письма в APPEND, совпадает с ограничением сервера отправки
+	maxAppendSize = 25 << 20
+	// maxErrors - после стольких ошибочных команд подряд соединение закрывается
+	maxErrors = 10
+	// maxAuthFailures - после стольких неудачных попыток входа соединение закрывается
+	maxAuthFailures = 3
+	// commandTimeout - ограничение времени выполнения одной команды
+	commandTimeout = time.Minute
+)
+
+var (
+	errLineTooLong     = errors.New("line too long")
+	errLiteralTooLarge = errors.New("literal too large")
+)
+
+// session - состояние одного IMAP-соединения
+type session struct {
+	server *Server
+	conn   net.Conn
+	reader *bufio.Reader
+	writer *bufio.Writer
+	log    *slog.Logger
+
+	tls          bool
+	authFailures int
+	// profileID - профиль вошедшего пользователя, 0 - вход еще не выполнен
+	profileID int64
+	// mailbox - выбранный почтовый ящик, nil - ящик не выбран
+	mailbox *mailbox
+	cache   *messageCache
+}
+
+func newSession(server *Server, conn net.Conn) *session {
+	s := &session{
+		server: server,
+		log:    server.log.With(slog.String("remote", conn.RemoteAddr().String())),
+		cache:  newMessageCache(server.cfg.CacheSize),
+	}
+	_, s.tls = conn.(*tls.Conn)
+	s.setConn(conn)
+	return s
+}
+
+func (s *session) setConn(conn net.Conn) {
+	s.conn = conn
+	s.reader = bufio.NewReaderSize(conn, maxLineLength+2)
+	s.writer = bufio.NewWriter(conn)
+}
+
+// remoteIP возвращает адрес клиента без порта
+func (s *session) remoteIP() string {
+	host, _, err := net.SplitHostPort(s.conn.RemoteAddr().String())
+	if err != nil {
+		return s.conn.RemoteAddr().String()
+	}
+	return host
+}
+
+func (s *session) serve() {
+	s.untagged("OK [CAPABILITY %s] %s IMAP4rev1 ready", s.capabilities(), s.server.cfg.Hostname)
+	s.flush()
+
+	errorsInRow := 0
+	for {
+		cmd, err := s.readCommand()
+		if err != nil {
+			var netErr net.Error
+			switch {
+			case errors.As(err, &netErr) && netErr.Timeout():
+				s.untagged("BYE Autologout, idle for too long")
+				s.flush()
+				return
+			case errors.Is(err, errLineTooLong):
+				s.untagged("BAD Line too long")
+				s.flush()
+			case errors.Is(err, errLiteralTooLarge):
+				s.reply(cmd.tag, "NO", "[TOOBIG] Literal too large")
+			case errors.Is(err, errSyntax):
+				s.reply(cmd.tag, "BAD", "Syntax error: "+strings.TrimPrefix(err.Error(), errSyntax.Error()+": "))
+			default:
+				return
+			}
+
+			errorsInRow++
+			if errorsInRow >= maxErrors {
+				s.untagged("BYE Too many errors, closing connection")
+				s.flush()
+				return
+			}
+			continue
+		}
+
+		status, quit := s.handle(cmd)
+		if quit {
+			return
+		}
+
+		if status == "BAD" {
+			errorsInRow++
+			if errorsInRow >= maxErrors {
+				s.untagged("BYE Too many errors, closing connection")
+				s.flush()
+				return
+			}
+		} else {
+			errorsInRow = 0
+		}
+	}
+}
+
+// handle выполняет команду и возвращает статус ответа и признак завершения сессии
+func (s *session) handle(cmd *command) (string, bool) {
+	switch cmd.name {
+	case "CAPABILITY":
+		s.untagged("CAPABILITY %s", s.capabilities())
+		return s.reply(cmd.tag, "OK", "CAPABILITY completed"), false
+	case "NOOP", "CHECK":
+		if cmd.name == "CHECK" && s.mailbox == nil {
+			return s.reply(cmd.tag, "BAD", "No mailbox selected"), false
+		}
+		return s.handleNoop(cmd), false
+	case "LOGOUT":
+		s.untagged("BYE %s logging out", s.server.cfg.Hostname)
+		s.reply(cmd.tag, "OK", "LOGOUT completed")
+		s.flush()
+		return "OK", true
+	case "ID":
+		// Сведения о клиенте не используются (RFC 2971)
+		s.untagged("ID NIL")
+		return s.reply(cmd.tag, "OK", "ID completed"), false
+	case "STARTTLS", "LOGIN", "AUTHENTICATE":
+		if s.profileID != 0 {
+			return s.reply(cmd.tag, "BAD", "Already authenticated"), false
+		}
+		switch cmd.name {
+		case "STARTTLS":
+			return s.handleStartTLS(cmd)
+		case "LOGIN":
+			return s.handleLogin(cmd)
+		}
+		return s.handleAuthenticate(cmd)
+	}
+
+	if s.profileID == 0 {
+		return s.reply(cmd.tag, "BAD", "Log in first"), false
+	}
+	if cmd.uid {
+		switch cmd.name {
+		case "FETCH", "STORE", "COPY", "MOVE", "SEARCH", "EXPUNGE":
+		default:
+			return s.reply(cmd.tag, "BAD", "Unknown UID command"), false
+		}
+	}
+
+	switch cmd.name {
+	case "SELECT", "EXAMINE":
+		return s.handleSelect(cmd), false
+	case "LIST", "LSUB":
+		return s.handleList(cmd), false
+	case "STATUS":
+		return s.handleStatus(cmd), false
+	case "CREATE":
+		return s.handleCreate(cmd), false
+	case "DELETE":
+		return s.handleDelete(cmd), false
+	case "RENAME":
+		return s.handleRename(cmd), false
+	case "SUBSCRIBE", "UNSUBSCRIBE":
+		// Подписаны все папки, LSUB совпадает с LIST
+		return s.reply(cmd.tag, "OK", cmd.name+" completed"), false
+	case "NAMESPACE":
+		s.untagged(`NAMESPACE (("" "/")) NIL NIL`)
+		return s.reply(cmd.tag, "OK", "NAMESPACE completed"), false
+	case "APPEND":
+		return s.handleAppend(cmd), false
+	case "IDLE":
+		return s.handleIdle(cmd)
+	}
+
+	switch cmd.name {
+	case "CLOSE", "UNSELECT", "EXPUNGE", "SEARCH", "FETCH", "STORE", "COPY", "MOVE":
+	default:
+		return s.reply(cmd.tag, "BAD", "Unknown command"), false
+	}
+	if s.mailbox == nil {
+		return s.reply(cmd.tag, "BAD", "No mailbox selected"), false
+	}
+
+	switch cmd.name {
+	case "CLOSE", "UNSELECT":
+		return s.handleClose(cmd), false
+	case "EXPUNGE":
+		return s.handleExpunge(cmd), false
+	case "SEARCH":
+		return s.handleSearch(cmd), false
+	case "FETCH":
+		return s.handleFetch(cmd), false
+	case "STORE":
+		return s.handleStore(cmd), false
+	}
+	return s.handleCopy(cmd), false
+}
+
+func (s *session) capabilities() string {
+	caps := []string{"IMAP4rev1", "LITERAL+", "SASL-IR", "ID", "IDLE", "MOVE", "UIDPLUS", "UNSELECT", "NAMESPACE", "CHILDREN", "SPECIAL-USE"}
+	if s.profileID == 0 {
+		if s.server.cfg.TLSConfig != nil && !s.tls {
+			caps = append(caps, "STARTTLS")
+		}
+		if s.authAllowed() {
+			caps = append(caps, "AUTH=PLAIN")
+		} else {
+			caps = append(caps, "LOGINDISABLED")
+		}
+	}
+	return strings.Join(caps, " ")
+}
+
+// authAllowed запрещает передавать пароль открытым текстом без явного разрешения
+func (s *session) authAllowed() bool {
+	return s.tls || s.server.cfg.AllowInsecureAuth
+}
+
+func (s *session) handleNoop(cmd *command) string {
+	if s.mailbox != nil {
+		ctx, cancel := s.context()
+		defer cancel()
+		if err := s.sync(ctx); err != nil {
+			return s.fail(cmd, "failed to sync mailbox", err)
+		}
+	}
+	return s.reply(cmd.tag, "OK", cmd.name+" completed")
+}
+
+func (s *session) handleStartTLS(cmd *command) (string, bool) {
+	if s.tls {
+		return s.reply(cmd.tag, "BAD", "TLS already active"), false
+	}
+	if s.server.cfg.TLSConfig == nil {
+		return s.reply(cmd.tag, "NO", "TLS not available"), false
+	}
+
+	s.reply(cmd.tag, "OK", "Begin TLS negotiation now")
+	s.flush()
+	// Команды, отправленные до завершения рукопожатия, отбрасываются (RFC 3501, 6.2.1)
+	tlsConn := tls.Server(s.conn, s.server.cfg.TLSConfig)
+	s.conn.SetDeadline(time.Now().Add(s.server.cfg.Timeout))
+	if err := tlsConn.Handshake(); err != nil {
+		s.log.Warn("TLS handshake failed: " + err.Error())
+		return "NO", true
+	}
+
+	s.setConn(tlsConn)
+	s.tls = true
+	return "OK", false
+}
+
+func (s *session) handleLogin(cmd *command) (string, bool) {
+	if len(cmd.args) != 2 {
+		return s.reply(cmd.tag, "BAD", "Syntax: LOGIN username password"), false
+	}
+	login, ok := cmd.args[0].astring()
+	password, ok2 := cmd.args[1].astring()
+	if !ok || !ok2 {
+		return s.reply(cmd.tag, "BAD", "Syntax: LOGIN username password"), false
+	}
+	return s.login(cmd, login, password)
+}
+
+// handleAuthenticate поддерживает механизм PLAIN (RFC 4616), в том числе с начальным ответом (RFC 4959)
+func (s *session) handleAuthenticate(cmd *command) (string, bool) {
+	if len(cmd.args) == 0 || len(cmd.args) > 2 {
+		return s.reply(cmd.tag, "BAD", "Syntax: AUTHENTICATE mechanism"), false
+	}
+	if !cmd.args[0].isAtom("PLAIN") {
+		return s.reply(cmd.tag, "NO", "Unsupported authentication mechanism"), false
+	}
+	if !s.authAllowed() {
+		return s.reply(cmd.tag, "NO", "[PRIVACYREQUIRED] Use STARTTLS first"), false
+	}
+
+	var response string
+	if len(cmd.args) == 2 {
+		response, _ = cmd.args[1].astring()
+	} else {
+		s.write("+ \r\n")
+		s.flush()
+		line, err := s.readLine()
+		if err != nil {
+			return "BAD", true
+		}
+		response = line
+	}
+	if response == "*" {
+		return s.reply(cmd.tag, "BAD", "Authentication cancelled"), false
+	}
+	if response == "=" {
+		response = ""
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(response)
+	if err != nil {
+		return s.reply(cmd.tag, "BAD", "Invalid base64 data"), false
+	}
+	parts := strings.Split(string(decoded), "\x00")
+	if len(parts) != 3 || (parts[0] != "" && parts[0] != parts[1]) {
+		return s.reply(cmd.tag, "NO", "[AUTHENTICATIONFAILED] Invalid credentials"), false
+	}
+	return s.login(cmd, parts[1], parts[2])
+}
+
+func (s *session) login(cmd *command, login, password string) (string, bool) {
+	if !s.authAllowed() {
+		return s.reply(cmd.tag, "NO", "[PRIVACYREQUIRED] Use STARTTLS first"), false
+	}
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	profileID, err := s.server.auth.Authenticate(ctx, login, password, s.remoteIP())
+	if errors.Is(err, profileUcase.ErrUserNotFound) || errors.Is(err, profileUcase.ErrWrongPassword) {
+		s.authFailures++
+		s.log.Info("authentication failed", slog.String("login", login))
+		if s.authFailures >= maxAuthFailures {
+			s.untagged("BYE Too many authentication failures")
+			s.reply(cmd.tag, "NO", "[AUTHENTICATIONFAILED] Invalid credentials")
+			s.flush()
+			return "NO", true
+		}
+		return s.reply(cmd.tag, "NO", "[AUTHENTICATIONFAILED] Invalid credentials"), false
+	}
+	if err != nil {
+		s.log.Error("failed to authenticate: " + err.Error())
+		return s.reply(cmd.tag, "NO", "[UNAVAILABLE] Temporary failure, try again later"), false
+	}
+
+	s.profileID = profileID
+	s.log = s.log.With(slog.Int64("profile_id", profileID))
+	return s.reply(cmd.tag, "OK", fmt.Sprintf("[CAPABILITY %s] Logged in", s.capabilities())), false
+}
+
+// context - контекст выполнения одной команды
+func (s *session) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), commandTimeout)
+}
+
+// fail записывает ошибку хранилища в журнал и отвечает клиенту временной ошибкой
+func (s *session) fail(cmd *command, message string, err error) string {
+	s.log.Error(message + ": " + err.Error())
+	return s.reply(cmd.tag, "NO", "[UNAVAILABLE] Temporary failure, try again later")
+}
+
+// readCommand читает команду вместе с литералами
+func (s *session) readCommand() (*command, error) {
+	cmd := &command{}
+	line, err := s.readLine()
+	if err != nil {
+		return cmd, err
+	}
+
+	tag, rest, _ := strings.Cut(line, " ")
+	if !validTag(tag) {
+		return cmd, fmt.Errorf("%w: invalid tag", errSyntax)
+	}
+	cmd.tag = tag
+
+	name, rest, _ := strings.Cut(rest, " ")
+	cmd.name = strings.ToUpper(name)
+	if cmd.name == "UID" {
+		cmd.uid = true
+		name, rest, _ = strings.Cut(rest, " ")
+		cmd.name = strings.ToUpper(name)
+	}
+	if cmd.name == "" {
+		return cmd, fmt.Errorf("%w: missing command", errSyntax)
+	}
+
+	limit := int64(maxLiteralSize)
+	if cmd.name == "APPEND" {
+		limit = maxAppendSize
+	}
+	p := parser{line: rest, literal: func(size int64, sync bool) ([]byte, string, error) {
+		return s.readLiteral(size, limit, sync)
+	}}
+	cmd.args, err = p.parseValues(0)
+	return cmd, err
+}
+
+func validTag(tag string) bool {
+	if tag == "" {
+		return false
+	}
+	for i := 0; i < len(tag); i++ {
+		if c := tag[i]; c <= ' ' || c > '~' || strings.IndexByte(`(){%*"\+`, c) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// readLiteral читает литерал и следующую за ним строку команды. Слишком большой литерал
+// не запрашивается у клиента, а отправленный без ожидания (LITERAL+) пропускается
+func (s *session) readLiteral(size, limit int64, sync bool) ([]byte, string, error) {
+	if size > limit {
+		if !sync {
+			if _, err := io.CopyN(io.Discard, s.reader, size); err != nil {
+				return nil, "", err
+			}
+			if _, err := s.readLine(); err != nil && !errors.Is(err, errLineTooLong) {
+				return nil, "", err
+			}
+		}
+		return nil, "", errLiteralTooLarge
+	}
+
+	if sync {
+		s.write("+ Ready for literal data\r\n")
+		s.flush()
+	}
+	data := make([]byte, size)
+	s.conn.SetDeadline(time.Now().Add(s.server.cfg.Timeout))
+	if _, err := io.ReadFull(s.reader, data); err != nil {
+		return nil, "", err
+	}
+	line, err := s.readLine()
+	if err != nil {
+		return nil, "", err
+	}
+	return data, line, nil
+}
+
+// readLine читает строку без CRLF. Слишком длинная строка пропускается целиком
+func (s *session) readLine() (string, error) {
+	s.conn.SetDeadline(time.Now().Add(s.server.cfg.Timeout))
+
+	line, err := s.reader.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		for errors.Is(err, bufio.ErrBufferFull) {
+			_, err = s.reader.ReadSlice('\n')
+		}
+		if err != nil {
+			return "", err
+		}
+		return "", errLineTooLong
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(line), "\r\n"), nil
+}
+
+// untagged добавляет в буфер ответ без метки
+func (s *session) untagged(format string, args ...any) {
+	s.write("* " + fmt.Sprintf(format, args...) + "\r\n")
+}
+
+// reply завершает команду ответом с меткой и возвращает его статус. Ответы
+// на команды, отправленные подряд, копятся в буфере, пока клиент не дождется их
+func (s *session) reply(tag, status, text string) string {
+	if tag == "" {
+		tag = "*"
+	}
+	s.write(tag + " " + status + " " + text + "\r\n")
+	if s.reader.Buffered() == 0 {
+		s.flush()
+	}
+	return status
+}
+
+func (s *session) write(text string) {
+	if _, err := s.writer.WriteString(text); err != nil {
+		s.log.Debug("failed to write response: " + err.Error())
+	}
+}
+
+func (s *session) flush() {
+	if err := s.writer.Flush(); err != nil {
+		s.log.Debug("failed to write response: " + err.Error())
+	}
+}
@@ -0,0 +1,91 @@
+package imap_server
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Имена почтовых ящиков передаются в модифицированной кодировке UTF-7 (RFC 3501, 5.1.3):
+// печатные ASCII-символы как есть, "&" как "&-", остальное - base64 от UTF-16 между "&" и "-"
+
+var errInvalidUTF7 = errors.New("invalid modified UTF-7")
+
+var utf7Encoding = base64.NewEncoding("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+,").WithPadding(base64.NoPadding)
+
+func encodeMailboxName(name string) string {
+	var b strings.Builder
+	var pending []uint16
+
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		buf := make([]byte, 0, len(pending)*2)
+		for _, unit := range pending {
+			buf = append(buf, byte(unit>>8), byte(unit))
+		}
+		b.WriteByte('&')
+		b.WriteString(utf7Encoding.EncodeToString(buf))
+		b.WriteByte('-')
+		pending = pending[:0]
+	}
+
+	for _, r := range name {
+		if r >= 0x20 && r <= 0x7e {
+			flush()
+			if r == '&' {
+				b.WriteString("&-")
+			} else {
+				b.WriteRune(r)
+			}
+			continue
+		}
+		pending = utf16.AppendRune(pending, r)
+	}
+	flush()
+	return b.String()
+}
+
+func decodeMailboxName(name string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c != '&' {
+			if c < 0x20 || c > 0x7e {
+				return "", errInvalidUTF7
+			}
+			b.WriteByte(c)
+			continue
+		}
+
+		end := strings.IndexByte(name[i:], '-')
+		if end < 0 {
+			return "", errInvalidUTF7
+		}
+		encoded := name[i+1 : i+end]
+		i += end
+		if encoded == "" {
+			b.WriteByte('&')
+			continue
+		}
+
+		buf, err := utf7Encoding.DecodeString(encoded)
+		if err != nil || len(buf)%2 != 0 {
+			return "", errInvalidUTF7
+		}
+		units := make([]uint16, 0, len(buf)/2)
+		for j := 0; j < len(buf); j += 2 {
+			units = append(units, uint16(buf[j])<<8|uint16(buf[j+1]))
+		}
+		for _, r := range utf16.Decode(units) {
+			if r == utf8.RuneError {
+				return "", errInvalidUTF7
+			}
+			b.WriteRune(r)
+		}
+	}
+	return b.String(), nil
+}
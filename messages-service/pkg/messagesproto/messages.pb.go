@@ -22,13 +22,20 @@ const (
 )
 
 type Message struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Sender        *Sender                `protobuf:"bytes,2,opt,name=sender,proto3" json:"sender,omitempty"`
-	Topic         string                 `protobuf:"bytes,3,opt,name=topic,proto3" json:"topic,omitempty"`
-	Snippet       string                 `protobuf:"bytes,4,opt,name=snippet,proto3" json:"snippet,omitempty"`
-	Datetime      string                 `protobuf:"bytes,5,opt,name=datetime,proto3" json:"datetime,omitempty"`
-	IsRead        string                 `protobuf:"bytes,6,opt,name=is_read,json=isRead,proto3" json:"is_read,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Sender      *Sender                `protobuf:"bytes,2,opt,name=sender,proto3" json:"sender,omitempty"`
+	Topic       string                 `protobuf:"bytes,3,opt,name=topic,proto3" json:"topic,omitempty"`
+	Snippet     string                 `protobuf:"bytes,4,opt,name=snippet,proto3" json:"snippet,omitempty"`
+	Datetime    string                 `protobuf:"bytes,5,opt,name=datetime,proto3" json:"datetime,omitempty"`
+	IsRead      string                 `protobuf:"bytes,6,opt,name=is_read,json=isRead,proto3" json:"is_read,omitempty"`
+	IsStarred   string                 `protobuf:"bytes,7,opt,name=is_starred,json=isStarred,proto3" json:"is_starred,omitempty"`
+	IsImportant string                 `protobuf:"bytes,8,opt,name=is_important,json=isImportant,proto3" json:"is_important,omitempty"`
+	IsAnswered  string                 `protobuf:"bytes,9,opt,name=is_answered,json=isAnswered,proto3" json:"is_answered,omitempty"`
+	IsForwarded string                 `protobuf:"bytes,10,opt,name=is_forwarded,json=isForwarded,proto3" json:"is_forwarded,omitempty"`
+	Labels      []*Label               `protobuf:"bytes,11,rep,name=labels,proto3" json:"labels,omitempty"`
+	// Размер текста и вложений в байтах
+	Size          string `protobuf:"bytes,12,opt,name=size,proto3" json:"size,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -105,21 +112,153 @@ func (x *Message) GetIsRead() string {
 	return ""
 }
 
-type FullMessage struct {
+func (x *Message) GetIsStarred() string {
+	if x != nil {
+		return x.IsStarred
+	}
+	return ""
+}
+
+func (x *Message) GetIsImportant() string {
+	if x != nil {
+		return x.IsImportant
+	}
+	return ""
+}
+
+func (x *Message) GetIsAnswered() string {
+	if x != nil {
+		return x.IsAnswered
+	}
+	return ""
+}
+
+func (x *Message) GetIsForwarded() string {
+	if x != nil {
+		return x.IsForwarded
+	}
+	return ""
+}
+
+func (x *Message) GetLabels() []*Label {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Message) GetSize() string {
+	if x != nil {
+		return x.Size
+	}
+	return ""
+}
+
+type Label struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Topic         string                 `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
-	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
-	Datetime      string                 `protobuf:"bytes,3,opt,name=datetime,proto3" json:"datetime,omitempty"`
-	ThreadId      string                 `protobuf:"bytes,4,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
-	Sender        *Sender                `protobuf:"bytes,5,opt,name=sender,proto3" json:"sender,omitempty"`
-	Files         []*File                `protobuf:"bytes,6,rep,name=files,proto3" json:"files,omitempty"`
+	LabelId       string                 `protobuf:"bytes,1,opt,name=label_id,json=labelId,proto3" json:"label_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Color         string                 `protobuf:"bytes,3,opt,name=color,proto3" json:"color,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Label) Reset() {
+	*x = Label{}
+	mi := &file_messages_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Label) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Label) ProtoMessage() {}
+
+func (x *Label) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Label.ProtoReflect.Descriptor instead.
+func (*Label) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Label) GetLabelId() string {
+	if x != nil {
+		return x.LabelId
+	}
+	return ""
+}
+
+func (x *Label) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Label) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+type FullMessage struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Topic    string                 `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	Text     string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	Datetime string                 `protobuf:"bytes,3,opt,name=datetime,proto3" json:"datetime,omitempty"`
+	ThreadId string                 `protobuf:"bytes,4,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	Sender   *Sender                `protobuf:"bytes,5,opt,name=sender,proto3" json:"sender,omitempty"`
+	Files    []*File                `protobuf:"bytes,6,rep,name=files,proto3" json:"files,omitempty"`
+	// Оценка спам-фильтра и токены, повлиявшие на нее
+	SpamScore   string   `protobuf:"bytes,7,opt,name=spam_score,json=spamScore,proto3" json:"spam_score,omitempty"`
+	SpamReasons []string `protobuf:"bytes,8,rep,name=spam_reasons,json=spamReasons,proto3" json:"spam_reasons,omitempty"`
+	// Результат проверки DKIM-подписи (none, pass, fail, temperror, permerror)
+	// и домен подписи. Пустой у писем, отправленных внутри сервиса
+	DkimResult string `protobuf:"bytes,9,opt,name=dkim_result,json=dkimResult,proto3" json:"dkim_result,omitempty"`
+	DkimDomain string `protobuf:"bytes,10,opt,name=dkim_domain,json=dkimDomain,proto3" json:"dkim_domain,omitempty"`
+	// Результат проверки SPF (none, neutral, pass, fail, softfail, temperror, permerror)
+	// и проверенный домен
+	SpfResult string `protobuf:"bytes,11,opt,name=spf_result,json=spfResult,proto3" json:"spf_result,omitempty"`
+	SpfDomain string `protobuf:"bytes,12,opt,name=spf_domain,json=spfDomain,proto3" json:"spf_domain,omitempty"`
+	// Результат проверки DMARC (none, pass, fail, temperror, permerror) и политика домена
+	// из поля From (none, quarantine, reject), пустая - домен не публикует запись
+	DmarcResult string `protobuf:"bytes,13,opt,name=dmarc_result,json=dmarcResult,proto3" json:"dmarc_result,omitempty"`
+	DmarcPolicy string `protobuf:"bytes,14,opt,name=dmarc_policy,json=dmarcPolicy,proto3" json:"dmarc_policy,omitempty"`
+	// Сводка проверок в формате заголовка Authentication-Results
+	AuthenticationResults string `protobuf:"bytes,15,opt,name=authentication_results,json=authenticationResults,proto3" json:"authentication_results,omitempty"`
+	// Адрес в поле From не подтвержден ни DKIM, ни SPF - показывается предупреждение
+	UnverifiedSender bool     `protobuf:"varint,16,opt,name=unverified_sender,json=unverifiedSender,proto3" json:"unverified_sender,omitempty"`
+	MessageId        string   `protobuf:"bytes,17,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Receivers        []string `protobuf:"bytes,18,rep,name=receivers,proto3" json:"receivers,omitempty"`
+	// Папки пользователя, в которых лежит письмо
+	FolderIds   []string `protobuf:"bytes,19,rep,name=folder_ids,json=folderIds,proto3" json:"folder_ids,omitempty"`
+	IsRead      string   `protobuf:"bytes,20,opt,name=is_read,json=isRead,proto3" json:"is_read,omitempty"`
+	IsStarred   string   `protobuf:"bytes,21,opt,name=is_starred,json=isStarred,proto3" json:"is_starred,omitempty"`
+	IsImportant string   `protobuf:"bytes,22,opt,name=is_important,json=isImportant,proto3" json:"is_important,omitempty"`
+	IsAnswered  string   `protobuf:"bytes,23,opt,name=is_answered,json=isAnswered,proto3" json:"is_answered,omitempty"`
+	IsForwarded string   `protobuf:"bytes,24,opt,name=is_forwarded,json=isForwarded,proto3" json:"is_forwarded,omitempty"`
+	// Размер текста и вложений в байтах
+	Size          string `protobuf:"bytes,25,opt,name=size,proto3" json:"size,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *FullMessage) Reset() {
 	*x = FullMessage{}
-	mi := &file_messages_proto_msgTypes[1]
+	mi := &file_messages_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -131,7 +270,7 @@ func (x *FullMessage) String() string {
 func (*FullMessage) ProtoMessage() {}
 
 func (x *FullMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[1]
+	mi := &file_messages_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -144,7 +283,7 @@ func (x *FullMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FullMessage.ProtoReflect.Descriptor instead.
 func (*FullMessage) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{1}
+	return file_messages_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *FullMessage) GetTopic() string {
@@ -189,6 +328,139 @@ func (x *FullMessage) GetFiles() []*File {
 	return nil
 }
 
+func (x *FullMessage) GetSpamScore() string {
+	if x != nil {
+		return x.SpamScore
+	}
+	return ""
+}
+
+func (x *FullMessage) GetSpamReasons() []string {
+	if x != nil {
+		return x.SpamReasons
+	}
+	return nil
+}
+
+func (x *FullMessage) GetDkimResult() string {
+	if x != nil {
+		return x.DkimResult
+	}
+	return ""
+}
+
+func (x *FullMessage) GetDkimDomain() string {
+	if x != nil {
+		return x.DkimDomain
+	}
+	return ""
+}
+
+func (x *FullMessage) GetSpfResult() string {
+	if x != nil {
+		return x.SpfResult
+	}
+	return ""
+}
+
+func (x *FullMessage) GetSpfDomain() string {
+	if x != nil {
+		return x.SpfDomain
+	}
+	return ""
+}
+
+func (x *FullMessage) GetDmarcResult() string {
+	if x != nil {
+		return x.DmarcResult
+	}
+	return ""
+}
+
+func (x *FullMessage) GetDmarcPolicy() string {
+	if x != nil {
+		return x.DmarcPolicy
+	}
+	return ""
+}
+
+func (x *FullMessage) GetAuthenticationResults() string {
+	if x != nil {
+		return x.AuthenticationResults
+	}
+	return ""
+}
+
+func (x *FullMessage) GetUnverifiedSender() bool {
+	if x != nil {
+		return x.UnverifiedSender
+	}
+	return false
+}
+
+func (x *FullMessage) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *FullMessage) GetReceivers() []string {
+	if x != nil {
+		return x.Receivers
+	}
+	return nil
+}
+
+func (x *FullMessage) GetFolderIds() []string {
+	if x != nil {
+		return x.FolderIds
+	}
+	return nil
+}
+
+func (x *FullMessage) GetIsRead() string {
+	if x != nil {
+		return x.IsRead
+	}
+	return ""
+}
+
+func (x *FullMessage) GetIsStarred() string {
+	if x != nil {
+		return x.IsStarred
+	}
+	return ""
+}
+
+func (x *FullMessage) GetIsImportant() string {
+	if x != nil {
+		return x.IsImportant
+	}
+	return ""
+}
+
+func (x *FullMessage) GetIsAnswered() string {
+	if x != nil {
+		return x.IsAnswered
+	}
+	return ""
+}
+
+func (x *FullMessage) GetIsForwarded() string {
+	if x != nil {
+		return x.IsForwarded
+	}
+	return ""
+}
+
+func (x *FullMessage) GetSize() string {
+	if x != nil {
+		return x.Size
+	}
+	return ""
+}
+
 type Sender struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
@@ -200,7 +472,7 @@ type Sender struct {
 
 func (x *Sender) Reset() {
 	*x = Sender{}
-	mi := &file_messages_proto_msgTypes[2]
+	mi := &file_messages_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -212,7 +484,7 @@ func (x *Sender) String() string {
 func (*Sender) ProtoMessage() {}
 
 func (x *Sender) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[2]
+	mi := &file_messages_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -225,7 +497,7 @@ func (x *Sender) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Sender.ProtoReflect.Descriptor instead.
 func (*Sender) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{2}
+	return file_messages_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Sender) GetEmail() string {
@@ -258,7 +530,7 @@ type Receiver struct {
 
 func (x *Receiver) Reset() {
 	*x = Receiver{}
-	mi := &file_messages_proto_msgTypes[3]
+	mi := &file_messages_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -270,7 +542,7 @@ func (x *Receiver) String() string {
 func (*Receiver) ProtoMessage() {}
 
 func (x *Receiver) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[3]
+	mi := &file_messages_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -283,7 +555,7 @@ func (x *Receiver) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Receiver.ProtoReflect.Descriptor instead.
 func (*Receiver) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{3}
+	return file_messages_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *Receiver) GetEmail() string {
@@ -305,7 +577,7 @@ type File struct {
 
 func (x *File) Reset() {
 	*x = File{}
-	mi := &file_messages_proto_msgTypes[4]
+	mi := &file_messages_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -317,7 +589,7 @@ func (x *File) String() string {
 func (*File) ProtoMessage() {}
 
 func (x *File) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[4]
+	mi := &file_messages_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -330,7 +602,7 @@ func (x *File) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use File.ProtoReflect.Descriptor instead.
 func (*File) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{4}
+	return file_messages_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *File) GetName() string {
@@ -366,13 +638,16 @@ type PaginationInfo struct {
 	HasNext           string                 `protobuf:"bytes,1,opt,name=has_next,json=hasNext,proto3" json:"has_next,omitempty"`
 	NextLastMessageId string                 `protobuf:"bytes,2,opt,name=next_last_message_id,json=nextLastMessageId,proto3" json:"next_last_message_id,omitempty"`
 	NextLastDatetime  string                 `protobuf:"bytes,3,opt,name=next_last_datetime,json=nextLastDatetime,proto3" json:"next_last_datetime,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	// Непрозрачные курсоры следующей и предыдущей страниц, пустые, если страницы нет
+	NextCursor    string `protobuf:"bytes,4,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	PrevCursor    string `protobuf:"bytes,5,opt,name=prev_cursor,json=prevCursor,proto3" json:"prev_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PaginationInfo) Reset() {
 	*x = PaginationInfo{}
-	mi := &file_messages_proto_msgTypes[5]
+	mi := &file_messages_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -384,7 +659,7 @@ func (x *PaginationInfo) String() string {
 func (*PaginationInfo) ProtoMessage() {}
 
 func (x *PaginationInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[5]
+	mi := &file_messages_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -397,7 +672,7 @@ func (x *PaginationInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PaginationInfo.ProtoReflect.Descriptor instead.
 func (*PaginationInfo) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{5}
+	return file_messages_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *PaginationInfo) GetHasNext() string {
@@ -421,18 +696,37 @@ func (x *PaginationInfo) GetNextLastDatetime() string {
 	return ""
 }
 
+func (x *PaginationInfo) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *PaginationInfo) GetPrevCursor() string {
+	if x != nil {
+		return x.PrevCursor
+	}
+	return ""
+}
+
 type Folder struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	FolderId      string                 `protobuf:"bytes,1,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
-	FolderName    string                 `protobuf:"bytes,2,opt,name=folder_name,json=folderName,proto3" json:"folder_name,omitempty"`
-	FolderType    string                 `protobuf:"bytes,3,opt,name=folder_type,json=folderType,proto3" json:"folder_type,omitempty"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	FolderId   string                 `protobuf:"bytes,1,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	FolderName string                 `protobuf:"bytes,2,opt,name=folder_name,json=folderName,proto3" json:"folder_name,omitempty"`
+	FolderType string                 `protobuf:"bytes,3,opt,name=folder_type,json=folderType,proto3" json:"folder_type,omitempty"`
+	// Идентификатор родительской папки, пустой для папок верхнего уровня
+	ParentId string `protobuf:"bytes,4,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	// Число писем и непрочитанных писем, у виртуальной папки starred не заполняются
+	MessageTotal  string `protobuf:"bytes,5,opt,name=message_total,json=messageTotal,proto3" json:"message_total,omitempty"`
+	MessageUnread string `protobuf:"bytes,6,opt,name=message_unread,json=messageUnread,proto3" json:"message_unread,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Folder) Reset() {
 	*x = Folder{}
-	mi := &file_messages_proto_msgTypes[6]
+	mi := &file_messages_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -444,7 +738,7 @@ func (x *Folder) String() string {
 func (*Folder) ProtoMessage() {}
 
 func (x *Folder) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[6]
+	mi := &file_messages_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -457,7 +751,7 @@ func (x *Folder) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Folder.ProtoReflect.Descriptor instead.
 func (*Folder) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{6}
+	return file_messages_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *Folder) GetFolderId() string {
@@ -481,17 +775,90 @@ func (x *Folder) GetFolderType() string {
 	return ""
 }
 
-type MessagesInfo struct {
+func (x *Folder) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *Folder) GetMessageTotal() string {
+	if x != nil {
+		return x.MessageTotal
+	}
+	return ""
+}
+
+func (x *Folder) GetMessageUnread() string {
+	if x != nil {
+		return x.MessageUnread
+	}
+	return ""
+}
+
+type FolderNode struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	MessageTotal  string                 `protobuf:"bytes,1,opt,name=message_total,json=messageTotal,proto3" json:"message_total,omitempty"`
-	MessageUnread string                 `protobuf:"bytes,2,opt,name=message_unread,json=messageUnread,proto3" json:"message_unread,omitempty"`
+	Folder        *Folder                `protobuf:"bytes,1,opt,name=folder,proto3" json:"folder,omitempty"`
+	Children      []*FolderNode          `protobuf:"bytes,2,rep,name=children,proto3" json:"children,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MessagesInfo) Reset() {
-	*x = MessagesInfo{}
-	mi := &file_messages_proto_msgTypes[7]
+func (x *FolderNode) Reset() {
+	*x = FolderNode{}
+	mi := &file_messages_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FolderNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FolderNode) ProtoMessage() {}
+
+func (x *FolderNode) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FolderNode.ProtoReflect.Descriptor instead.
+func (*FolderNode) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *FolderNode) GetFolder() *Folder {
+	if x != nil {
+		return x.Folder
+	}
+	return nil
+}
+
+func (x *FolderNode) GetChildren() []*FolderNode {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+type MessagesInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageTotal  string                 `protobuf:"bytes,1,opt,name=message_total,json=messageTotal,proto3" json:"message_total,omitempty"`
+	MessageUnread string                 `protobuf:"bytes,2,opt,name=message_unread,json=messageUnread,proto3" json:"message_unread,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessagesInfo) Reset() {
+	*x = MessagesInfo{}
+	mi := &file_messages_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -503,7 +870,7 @@ func (x *MessagesInfo) String() string {
 func (*MessagesInfo) ProtoMessage() {}
 
 func (x *MessagesInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[7]
+	mi := &file_messages_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -516,7 +883,7 @@ func (x *MessagesInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MessagesInfo.ProtoReflect.Descriptor instead.
 func (*MessagesInfo) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{7}
+	return file_messages_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *MessagesInfo) GetMessageTotal() string {
@@ -545,7 +912,7 @@ type InboxRequest struct {
 
 func (x *InboxRequest) Reset() {
 	*x = InboxRequest{}
-	mi := &file_messages_proto_msgTypes[8]
+	mi := &file_messages_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -557,7 +924,7 @@ func (x *InboxRequest) String() string {
 func (*InboxRequest) ProtoMessage() {}
 
 func (x *InboxRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[8]
+	mi := &file_messages_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -570,7 +937,7 @@ func (x *InboxRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InboxRequest.ProtoReflect.Descriptor instead.
 func (*InboxRequest) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{8}
+	return file_messages_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *InboxRequest) GetLastMessageId() string {
@@ -606,7 +973,7 @@ type InboxResponse struct {
 
 func (x *InboxResponse) Reset() {
 	*x = InboxResponse{}
-	mi := &file_messages_proto_msgTypes[9]
+	mi := &file_messages_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -618,7 +985,7 @@ func (x *InboxResponse) String() string {
 func (*InboxResponse) ProtoMessage() {}
 
 func (x *InboxResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[9]
+	mi := &file_messages_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -631,7 +998,7 @@ func (x *InboxResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InboxResponse.ProtoReflect.Descriptor instead.
 func (*InboxResponse) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{9}
+	return file_messages_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *InboxResponse) GetMessageTotal() string {
@@ -663,15 +1030,17 @@ func (x *InboxResponse) GetPagination() *PaginationInfo {
 }
 
 type MessagePageRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	MessageId string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	// Не отмечать письмо прочитанным
+	Peek          bool `protobuf:"varint,2,opt,name=peek,proto3" json:"peek,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *MessagePageRequest) Reset() {
 	*x = MessagePageRequest{}
-	mi := &file_messages_proto_msgTypes[10]
+	mi := &file_messages_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -683,7 +1052,7 @@ func (x *MessagePageRequest) String() string {
 func (*MessagePageRequest) ProtoMessage() {}
 
 func (x *MessagePageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[10]
+	mi := &file_messages_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -696,7 +1065,7 @@ func (x *MessagePageRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MessagePageRequest.ProtoReflect.Descriptor instead.
 func (*MessagePageRequest) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{10}
+	return file_messages_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *MessagePageRequest) GetMessageId() string {
@@ -706,6 +1075,13 @@ func (x *MessagePageRequest) GetMessageId() string {
 	return ""
 }
 
+func (x *MessagePageRequest) GetPeek() bool {
+	if x != nil {
+		return x.Peek
+	}
+	return false
+}
+
 type MessagePageResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Message       *FullMessage           `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
@@ -715,7 +1091,7 @@ type MessagePageResponse struct {
 
 func (x *MessagePageResponse) Reset() {
 	*x = MessagePageResponse{}
-	mi := &file_messages_proto_msgTypes[11]
+	mi := &file_messages_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -727,7 +1103,7 @@ func (x *MessagePageResponse) String() string {
 func (*MessagePageResponse) ProtoMessage() {}
 
 func (x *MessagePageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[11]
+	mi := &file_messages_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -740,7 +1116,7 @@ func (x *MessagePageResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MessagePageResponse.ProtoReflect.Descriptor instead.
 func (*MessagePageResponse) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{11}
+	return file_messages_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *MessagePageResponse) GetMessage() *FullMessage {
@@ -764,7 +1140,7 @@ type ReplyRequest struct {
 
 func (x *ReplyRequest) Reset() {
 	*x = ReplyRequest{}
-	mi := &file_messages_proto_msgTypes[12]
+	mi := &file_messages_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -776,7 +1152,7 @@ func (x *ReplyRequest) String() string {
 func (*ReplyRequest) ProtoMessage() {}
 
 func (x *ReplyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[12]
+	mi := &file_messages_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -789,7 +1165,7 @@ func (x *ReplyRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReplyRequest.ProtoReflect.Descriptor instead.
 func (*ReplyRequest) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{12}
+	return file_messages_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *ReplyRequest) GetRootMessageId() string {
@@ -843,7 +1219,7 @@ type ReplyResponse struct {
 
 func (x *ReplyResponse) Reset() {
 	*x = ReplyResponse{}
-	mi := &file_messages_proto_msgTypes[13]
+	mi := &file_messages_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -855,7 +1231,7 @@ func (x *ReplyResponse) String() string {
 func (*ReplyResponse) ProtoMessage() {}
 
 func (x *ReplyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[13]
+	mi := &file_messages_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -868,7 +1244,7 @@ func (x *ReplyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReplyResponse.ProtoReflect.Descriptor instead.
 func (*ReplyResponse) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{13}
+	return file_messages_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *ReplyResponse) GetMessageId() string {
@@ -890,7 +1266,7 @@ type SendRequest struct {
 
 func (x *SendRequest) Reset() {
 	*x = SendRequest{}
-	mi := &file_messages_proto_msgTypes[14]
+	mi := &file_messages_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -902,7 +1278,7 @@ func (x *SendRequest) String() string {
 func (*SendRequest) ProtoMessage() {}
 
 func (x *SendRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[14]
+	mi := &file_messages_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -915,7 +1291,7 @@ func (x *SendRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SendRequest.ProtoReflect.Descriptor instead.
 func (*SendRequest) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{14}
+	return file_messages_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *SendRequest) GetTopic() string {
@@ -955,7 +1331,7 @@ type SendResponse struct {
 
 func (x *SendResponse) Reset() {
 	*x = SendResponse{}
-	mi := &file_messages_proto_msgTypes[15]
+	mi := &file_messages_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -967,7 +1343,7 @@ func (x *SendResponse) String() string {
 func (*SendResponse) ProtoMessage() {}
 
 func (x *SendResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[15]
+	mi := &file_messages_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -980,7 +1356,7 @@ func (x *SendResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SendResponse.ProtoReflect.Descriptor instead.
 func (*SendResponse) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{15}
+	return file_messages_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *SendResponse) GetMessageId() string {
@@ -990,6 +1366,111 @@ func (x *SendResponse) GetMessageId() string {
 	return ""
 }
 
+type ForwardRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	MessageId string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Receivers []*Receiver            `protobuf:"bytes,2,rep,name=receivers,proto3" json:"receivers,omitempty"`
+	// Необязательный комментарий перед пересылаемым письмом
+	Text          string `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForwardRequest) Reset() {
+	*x = ForwardRequest{}
+	mi := &file_messages_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForwardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardRequest) ProtoMessage() {}
+
+func (x *ForwardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardRequest.ProtoReflect.Descriptor instead.
+func (*ForwardRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ForwardRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *ForwardRequest) GetReceivers() []*Receiver {
+	if x != nil {
+		return x.Receivers
+	}
+	return nil
+}
+
+func (x *ForwardRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type ForwardResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForwardResponse) Reset() {
+	*x = ForwardResponse{}
+	mi := &file_messages_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForwardResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardResponse) ProtoMessage() {}
+
+func (x *ForwardResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardResponse.ProtoReflect.Descriptor instead.
+func (*ForwardResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ForwardResponse) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
 type SentRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	LastMessageId string                 `protobuf:"bytes,1,opt,name=last_message_id,json=lastMessageId,proto3" json:"last_message_id,omitempty"`
@@ -1001,7 +1482,7 @@ type SentRequest struct {
 
 func (x *SentRequest) Reset() {
 	*x = SentRequest{}
-	mi := &file_messages_proto_msgTypes[16]
+	mi := &file_messages_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1013,7 +1494,7 @@ func (x *SentRequest) String() string {
 func (*SentRequest) ProtoMessage() {}
 
 func (x *SentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[16]
+	mi := &file_messages_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1026,7 +1507,7 @@ func (x *SentRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SentRequest.ProtoReflect.Descriptor instead.
 func (*SentRequest) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{16}
+	return file_messages_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *SentRequest) GetLastMessageId() string {
@@ -1062,7 +1543,7 @@ type SentResponse struct {
 
 func (x *SentResponse) Reset() {
 	*x = SentResponse{}
-	mi := &file_messages_proto_msgTypes[17]
+	mi := &file_messages_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1074,7 +1555,7 @@ func (x *SentResponse) String() string {
 func (*SentResponse) ProtoMessage() {}
 
 func (x *SentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[17]
+	mi := &file_messages_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1087,7 +1568,7 @@ func (x *SentResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SentResponse.ProtoReflect.Descriptor instead.
 func (*SentResponse) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{17}
+	return file_messages_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *SentResponse) GetMessageTotal() string {
@@ -1128,7 +1609,7 @@ type MarkAsSpamRequest struct {
 
 func (x *MarkAsSpamRequest) Reset() {
 	*x = MarkAsSpamRequest{}
-	mi := &file_messages_proto_msgTypes[18]
+	mi := &file_messages_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1140,7 +1621,7 @@ func (x *MarkAsSpamRequest) String() string {
 func (*MarkAsSpamRequest) ProtoMessage() {}
 
 func (x *MarkAsSpamRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[18]
+	mi := &file_messages_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1153,7 +1634,7 @@ func (x *MarkAsSpamRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MarkAsSpamRequest.ProtoReflect.Descriptor instead.
 func (*MarkAsSpamRequest) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{18}
+	return file_messages_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *MarkAsSpamRequest) GetMessageId() string {
@@ -1171,7 +1652,7 @@ type MarkAsSpamResponse struct {
 
 func (x *MarkAsSpamResponse) Reset() {
 	*x = MarkAsSpamResponse{}
-	mi := &file_messages_proto_msgTypes[19]
+	mi := &file_messages_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1183,7 +1664,7 @@ func (x *MarkAsSpamResponse) String() string {
 func (*MarkAsSpamResponse) ProtoMessage() {}
 
 func (x *MarkAsSpamResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[19]
+	mi := &file_messages_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1196,32 +1677,31 @@ func (x *MarkAsSpamResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MarkAsSpamResponse.ProtoReflect.Descriptor instead.
 func (*MarkAsSpamResponse) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{19}
+	return file_messages_proto_rawDescGZIP(), []int{23}
 }
 
-type MoveToFolderRequest struct {
+type MarkAsNotSpamRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
-	FolderId      string                 `protobuf:"bytes,2,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MoveToFolderRequest) Reset() {
-	*x = MoveToFolderRequest{}
-	mi := &file_messages_proto_msgTypes[20]
+func (x *MarkAsNotSpamRequest) Reset() {
+	*x = MarkAsNotSpamRequest{}
+	mi := &file_messages_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MoveToFolderRequest) String() string {
+func (x *MarkAsNotSpamRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MoveToFolderRequest) ProtoMessage() {}
+func (*MarkAsNotSpamRequest) ProtoMessage() {}
 
-func (x *MoveToFolderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[20]
+func (x *MarkAsNotSpamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1232,46 +1712,39 @@ func (x *MoveToFolderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MoveToFolderRequest.ProtoReflect.Descriptor instead.
-func (*MoveToFolderRequest) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{20}
+// Deprecated: Use MarkAsNotSpamRequest.ProtoReflect.Descriptor instead.
+func (*MarkAsNotSpamRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *MoveToFolderRequest) GetMessageId() string {
+func (x *MarkAsNotSpamRequest) GetMessageId() string {
 	if x != nil {
 		return x.MessageId
 	}
 	return ""
 }
 
-func (x *MoveToFolderRequest) GetFolderId() string {
-	if x != nil {
-		return x.FolderId
-	}
-	return ""
-}
-
-type MoveToFolderResponse struct {
+type MarkAsNotSpamResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MoveToFolderResponse) Reset() {
-	*x = MoveToFolderResponse{}
-	mi := &file_messages_proto_msgTypes[21]
+func (x *MarkAsNotSpamResponse) Reset() {
+	*x = MarkAsNotSpamResponse{}
+	mi := &file_messages_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MoveToFolderResponse) String() string {
+func (x *MarkAsNotSpamResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MoveToFolderResponse) ProtoMessage() {}
+func (*MarkAsNotSpamResponse) ProtoMessage() {}
 
-func (x *MoveToFolderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[21]
+func (x *MarkAsNotSpamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1282,34 +1755,245 @@ func (x *MoveToFolderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MoveToFolderResponse.ProtoReflect.Descriptor instead.
-func (*MoveToFolderResponse) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{21}
+// Deprecated: Use MarkAsNotSpamResponse.ProtoReflect.Descriptor instead.
+func (*MarkAsNotSpamResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{25}
 }
 
-// Методы для папок
-type CreateFolderRequest struct {
+type MoveToFolderRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	FolderName    string                 `protobuf:"bytes,1,opt,name=folder_name,json=folderName,proto3" json:"folder_name,omitempty"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	FolderId      string                 `protobuf:"bytes,2,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateFolderRequest) Reset() {
-	*x = CreateFolderRequest{}
-	mi := &file_messages_proto_msgTypes[22]
+func (x *MoveToFolderRequest) Reset() {
+	*x = MoveToFolderRequest{}
+	mi := &file_messages_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateFolderRequest) String() string {
+func (x *MoveToFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveToFolderRequest) ProtoMessage() {}
+
+func (x *MoveToFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveToFolderRequest.ProtoReflect.Descriptor instead.
+func (*MoveToFolderRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *MoveToFolderRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *MoveToFolderRequest) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+type MoveToFolderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveToFolderResponse) Reset() {
+	*x = MoveToFolderResponse{}
+	mi := &file_messages_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveToFolderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveToFolderResponse) ProtoMessage() {}
+
+func (x *MoveToFolderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveToFolderResponse.ProtoReflect.Descriptor instead.
+func (*MoveToFolderResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{27}
+}
+
+// Флаги задаются строками "true"/"false", пустая строка оставляет флаг без изменений
+type SetFlagsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Read          string                 `protobuf:"bytes,2,opt,name=read,proto3" json:"read,omitempty"`
+	Starred       string                 `protobuf:"bytes,3,opt,name=starred,proto3" json:"starred,omitempty"`
+	Important     string                 `protobuf:"bytes,4,opt,name=important,proto3" json:"important,omitempty"`
+	Answered      string                 `protobuf:"bytes,5,opt,name=answered,proto3" json:"answered,omitempty"`
+	Forwarded     string                 `protobuf:"bytes,6,opt,name=forwarded,proto3" json:"forwarded,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetFlagsRequest) Reset() {
+	*x = SetFlagsRequest{}
+	mi := &file_messages_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetFlagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetFlagsRequest) ProtoMessage() {}
+
+func (x *SetFlagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetFlagsRequest.ProtoReflect.Descriptor instead.
+func (*SetFlagsRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *SetFlagsRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *SetFlagsRequest) GetRead() string {
+	if x != nil {
+		return x.Read
+	}
+	return ""
+}
+
+func (x *SetFlagsRequest) GetStarred() string {
+	if x != nil {
+		return x.Starred
+	}
+	return ""
+}
+
+func (x *SetFlagsRequest) GetImportant() string {
+	if x != nil {
+		return x.Important
+	}
+	return ""
+}
+
+func (x *SetFlagsRequest) GetAnswered() string {
+	if x != nil {
+		return x.Answered
+	}
+	return ""
+}
+
+func (x *SetFlagsRequest) GetForwarded() string {
+	if x != nil {
+		return x.Forwarded
+	}
+	return ""
+}
+
+type SetFlagsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetFlagsResponse) Reset() {
+	*x = SetFlagsResponse{}
+	mi := &file_messages_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetFlagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetFlagsResponse) ProtoMessage() {}
+
+func (x *SetFlagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetFlagsResponse.ProtoReflect.Descriptor instead.
+func (*SetFlagsResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{29}
+}
+
+// Методы для папок
+type CreateFolderRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	FolderName string                 `protobuf:"bytes,1,opt,name=folder_name,json=folderName,proto3" json:"folder_name,omitempty"`
+	// Если задан, папка создается внутри указанной
+	ParentId      string `protobuf:"bytes,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateFolderRequest) Reset() {
+	*x = CreateFolderRequest{}
+	mi := &file_messages_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateFolderRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
 func (*CreateFolderRequest) ProtoMessage() {}
 
 func (x *CreateFolderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[22]
+	mi := &file_messages_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1322,7 +2006,7 @@ func (x *CreateFolderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateFolderRequest.ProtoReflect.Descriptor instead.
 func (*CreateFolderRequest) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{22}
+	return file_messages_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *CreateFolderRequest) GetFolderName() string {
@@ -1332,30 +2016,4476 @@ func (x *CreateFolderRequest) GetFolderName() string {
 	return ""
 }
 
+func (x *CreateFolderRequest) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
 type CreateFolderResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	FolderId      string                 `protobuf:"bytes,1,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
 	FolderName    string                 `protobuf:"bytes,2,opt,name=folder_name,json=folderName,proto3" json:"folder_name,omitempty"`
 	FolderType    string                 `protobuf:"bytes,3,opt,name=folder_type,json=folderType,proto3" json:"folder_type,omitempty"`
+	ParentId      string                 `protobuf:"bytes,4,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateFolderResponse) Reset() {
+	*x = CreateFolderResponse{}
+	mi := &file_messages_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateFolderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFolderResponse) ProtoMessage() {}
+
+func (x *CreateFolderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFolderResponse.ProtoReflect.Descriptor instead.
+func (*CreateFolderResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *CreateFolderResponse) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+func (x *CreateFolderResponse) GetFolderName() string {
+	if x != nil {
+		return x.FolderName
+	}
+	return ""
+}
+
+func (x *CreateFolderResponse) GetFolderType() string {
+	if x != nil {
+		return x.FolderType
+	}
+	return ""
+}
+
+func (x *CreateFolderResponse) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+type GetFolderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FolderId      string                 `protobuf:"bytes,1,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	LastMessageId string                 `protobuf:"bytes,2,opt,name=last_message_id,json=lastMessageId,proto3" json:"last_message_id,omitempty"`
+	LastDatetime  string                 `protobuf:"bytes,3,opt,name=last_datetime,json=lastDatetime,proto3" json:"last_datetime,omitempty"`
+	Limit         string                 `protobuf:"bytes,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Если задан, в выдачу попадают только письма с этой меткой
+	LabelId string `protobuf:"bytes,5,opt,name=label_id,json=labelId,proto3" json:"label_id,omitempty"`
+	// Курсор из PaginationInfo. Если задан, last_message_id и last_datetime не используются
+	Cursor string `protobuf:"bytes,6,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// Сортировка: date_desc (по умолчанию), date_asc, sender, topic, size.
+	// При заданном курсоре используется сортировка, для которой он выдан
+	Sort string `protobuf:"bytes,7,opt,name=sort,proto3" json:"sort,omitempty"`
+	// Фильтры, "true" включает фильтр
+	UnreadOnly     string `protobuf:"bytes,8,opt,name=unread_only,json=unreadOnly,proto3" json:"unread_only,omitempty"`
+	StarredOnly    string `protobuf:"bytes,9,opt,name=starred_only,json=starredOnly,proto3" json:"starred_only,omitempty"`
+	HasAttachments string `protobuf:"bytes,10,opt,name=has_attachments,json=hasAttachments,proto3" json:"has_attachments,omitempty"`
+	// Адрес отправителя
+	Sender string `protobuf:"bytes,11,opt,name=sender,proto3" json:"sender,omitempty"`
+	// Границы даты отправки в формате RFC3339: after включительно, before не включительно
+	After         string `protobuf:"bytes,12,opt,name=after,proto3" json:"after,omitempty"`
+	Before        string `protobuf:"bytes,13,opt,name=before,proto3" json:"before,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFolderRequest) Reset() {
+	*x = GetFolderRequest{}
+	mi := &file_messages_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFolderRequest) ProtoMessage() {}
+
+func (x *GetFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFolderRequest.ProtoReflect.Descriptor instead.
+func (*GetFolderRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *GetFolderRequest) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+func (x *GetFolderRequest) GetLastMessageId() string {
+	if x != nil {
+		return x.LastMessageId
+	}
+	return ""
+}
+
+func (x *GetFolderRequest) GetLastDatetime() string {
+	if x != nil {
+		return x.LastDatetime
+	}
+	return ""
+}
+
+func (x *GetFolderRequest) GetLimit() string {
+	if x != nil {
+		return x.Limit
+	}
+	return ""
+}
+
+func (x *GetFolderRequest) GetLabelId() string {
+	if x != nil {
+		return x.LabelId
+	}
+	return ""
+}
+
+func (x *GetFolderRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *GetFolderRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *GetFolderRequest) GetUnreadOnly() string {
+	if x != nil {
+		return x.UnreadOnly
+	}
+	return ""
+}
+
+func (x *GetFolderRequest) GetStarredOnly() string {
+	if x != nil {
+		return x.StarredOnly
+	}
+	return ""
+}
+
+func (x *GetFolderRequest) GetHasAttachments() string {
+	if x != nil {
+		return x.HasAttachments
+	}
+	return ""
+}
+
+func (x *GetFolderRequest) GetSender() string {
+	if x != nil {
+		return x.Sender
+	}
+	return ""
+}
+
+func (x *GetFolderRequest) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
+func (x *GetFolderRequest) GetBefore() string {
+	if x != nil {
+		return x.Before
+	}
+	return ""
+}
+
+type GetFolderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageTotal  string                 `protobuf:"bytes,1,opt,name=message_total,json=messageTotal,proto3" json:"message_total,omitempty"`
+	MessageUnread string                 `protobuf:"bytes,2,opt,name=message_unread,json=messageUnread,proto3" json:"message_unread,omitempty"`
+	Messages      []*Message             `protobuf:"bytes,3,rep,name=messages,proto3" json:"messages,omitempty"`
+	Pagination    *PaginationInfo        `protobuf:"bytes,4,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFolderResponse) Reset() {
+	*x = GetFolderResponse{}
+	mi := &file_messages_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFolderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFolderResponse) ProtoMessage() {}
+
+func (x *GetFolderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFolderResponse.ProtoReflect.Descriptor instead.
+func (*GetFolderResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetFolderResponse) GetMessageTotal() string {
+	if x != nil {
+		return x.MessageTotal
+	}
+	return ""
+}
+
+func (x *GetFolderResponse) GetMessageUnread() string {
+	if x != nil {
+		return x.MessageUnread
+	}
+	return ""
+}
+
+func (x *GetFolderResponse) GetMessages() []*Message {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *GetFolderResponse) GetPagination() *PaginationInfo {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+type GetFoldersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFoldersRequest) Reset() {
+	*x = GetFoldersRequest{}
+	mi := &file_messages_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFoldersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFoldersRequest) ProtoMessage() {}
+
+func (x *GetFoldersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFoldersRequest.ProtoReflect.Descriptor instead.
+func (*GetFoldersRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{34}
+}
+
+type GetFoldersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Folders       []*Folder              `protobuf:"bytes,1,rep,name=folders,proto3" json:"folders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFoldersResponse) Reset() {
+	*x = GetFoldersResponse{}
+	mi := &file_messages_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFoldersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFoldersResponse) ProtoMessage() {}
+
+func (x *GetFoldersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFoldersResponse.ProtoReflect.Descriptor instead.
+func (*GetFoldersResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetFoldersResponse) GetFolders() []*Folder {
+	if x != nil {
+		return x.Folders
+	}
+	return nil
+}
+
+type RenameFolderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FolderId      string                 `protobuf:"bytes,1,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	NewFolderName string                 `protobuf:"bytes,2,opt,name=new_folder_name,json=newFolderName,proto3" json:"new_folder_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameFolderRequest) Reset() {
+	*x = RenameFolderRequest{}
+	mi := &file_messages_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameFolderRequest) ProtoMessage() {}
+
+func (x *RenameFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameFolderRequest.ProtoReflect.Descriptor instead.
+func (*RenameFolderRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *RenameFolderRequest) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+func (x *RenameFolderRequest) GetNewFolderName() string {
+	if x != nil {
+		return x.NewFolderName
+	}
+	return ""
+}
+
+type RenameFolderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FolderId      string                 `protobuf:"bytes,1,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	FolderName    string                 `protobuf:"bytes,2,opt,name=folder_name,json=folderName,proto3" json:"folder_name,omitempty"`
+	FolderType    string                 `protobuf:"bytes,3,opt,name=folder_type,json=folderType,proto3" json:"folder_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameFolderResponse) Reset() {
+	*x = RenameFolderResponse{}
+	mi := &file_messages_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameFolderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameFolderResponse) ProtoMessage() {}
+
+func (x *RenameFolderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameFolderResponse.ProtoReflect.Descriptor instead.
+func (*RenameFolderResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *RenameFolderResponse) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+func (x *RenameFolderResponse) GetFolderName() string {
+	if x != nil {
+		return x.FolderName
+	}
+	return ""
+}
+
+func (x *RenameFolderResponse) GetFolderType() string {
+	if x != nil {
+		return x.FolderType
+	}
+	return ""
+}
+
+type DeleteFolderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FolderId      string                 `protobuf:"bytes,1,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteFolderRequest) Reset() {
+	*x = DeleteFolderRequest{}
+	mi := &file_messages_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFolderRequest) ProtoMessage() {}
+
+func (x *DeleteFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFolderRequest.ProtoReflect.Descriptor instead.
+func (*DeleteFolderRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *DeleteFolderRequest) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+type DeleteFolderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteFolderResponse) Reset() {
+	*x = DeleteFolderResponse{}
+	mi := &file_messages_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFolderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFolderResponse) ProtoMessage() {}
+
+func (x *DeleteFolderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFolderResponse.ProtoReflect.Descriptor instead.
+func (*DeleteFolderResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{39}
+}
+
+type DeleteMessageFromFolderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	FolderId      string                 `protobuf:"bytes,2,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMessageFromFolderRequest) Reset() {
+	*x = DeleteMessageFromFolderRequest{}
+	mi := &file_messages_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMessageFromFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMessageFromFolderRequest) ProtoMessage() {}
+
+func (x *DeleteMessageFromFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMessageFromFolderRequest.ProtoReflect.Descriptor instead.
+func (*DeleteMessageFromFolderRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *DeleteMessageFromFolderRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *DeleteMessageFromFolderRequest) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+type DeleteMessageFromFolderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMessageFromFolderResponse) Reset() {
+	*x = DeleteMessageFromFolderResponse{}
+	mi := &file_messages_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMessageFromFolderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMessageFromFolderResponse) ProtoMessage() {}
+
+func (x *DeleteMessageFromFolderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMessageFromFolderResponse.ProtoReflect.Descriptor instead.
+func (*DeleteMessageFromFolderResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{41}
+}
+
+type MoveFolderRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	FolderId string                 `protobuf:"bytes,1,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	// Пустой parent_id переносит папку на верхний уровень
+	ParentId      string `protobuf:"bytes,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveFolderRequest) Reset() {
+	*x = MoveFolderRequest{}
+	mi := &file_messages_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveFolderRequest) ProtoMessage() {}
+
+func (x *MoveFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveFolderRequest.ProtoReflect.Descriptor instead.
+func (*MoveFolderRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *MoveFolderRequest) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+func (x *MoveFolderRequest) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+type MoveFolderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Folder        *Folder                `protobuf:"bytes,1,opt,name=folder,proto3" json:"folder,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveFolderResponse) Reset() {
+	*x = MoveFolderResponse{}
+	mi := &file_messages_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveFolderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveFolderResponse) ProtoMessage() {}
+
+func (x *MoveFolderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveFolderResponse.ProtoReflect.Descriptor instead.
+func (*MoveFolderResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *MoveFolderResponse) GetFolder() *Folder {
+	if x != nil {
+		return x.Folder
+	}
+	return nil
+}
+
+type GetFolderTreeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFolderTreeRequest) Reset() {
+	*x = GetFolderTreeRequest{}
+	mi := &file_messages_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFolderTreeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFolderTreeRequest) ProtoMessage() {}
+
+func (x *GetFolderTreeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFolderTreeRequest.ProtoReflect.Descriptor instead.
+func (*GetFolderTreeRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{44}
+}
+
+type GetFolderTreeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Folders       []*FolderNode          `protobuf:"bytes,1,rep,name=folders,proto3" json:"folders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFolderTreeResponse) Reset() {
+	*x = GetFolderTreeResponse{}
+	mi := &file_messages_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFolderTreeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFolderTreeResponse) ProtoMessage() {}
+
+func (x *GetFolderTreeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFolderTreeResponse.ProtoReflect.Descriptor instead.
+func (*GetFolderTreeResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *GetFolderTreeResponse) GetFolders() []*FolderNode {
+	if x != nil {
+		return x.Folders
+	}
+	return nil
+}
+
+// Методы для черновиков
+type SaveDraftRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DraftId       string                 `protobuf:"bytes,1,opt,name=draft_id,json=draftId,proto3" json:"draft_id,omitempty"`
+	ThreadId      string                 `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	Topic         string                 `protobuf:"bytes,3,opt,name=topic,proto3" json:"topic,omitempty"`
+	Text          string                 `protobuf:"bytes,4,opt,name=text,proto3" json:"text,omitempty"`
+	Receivers     []*Receiver            `protobuf:"bytes,5,rep,name=receivers,proto3" json:"receivers,omitempty"`
+	Files         []*File                `protobuf:"bytes,6,rep,name=files,proto3" json:"files,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SaveDraftRequest) Reset() {
+	*x = SaveDraftRequest{}
+	mi := &file_messages_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SaveDraftRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveDraftRequest) ProtoMessage() {}
+
+func (x *SaveDraftRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveDraftRequest.ProtoReflect.Descriptor instead.
+func (*SaveDraftRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *SaveDraftRequest) GetDraftId() string {
+	if x != nil {
+		return x.DraftId
+	}
+	return ""
+}
+
+func (x *SaveDraftRequest) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
+}
+
+func (x *SaveDraftRequest) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *SaveDraftRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *SaveDraftRequest) GetReceivers() []*Receiver {
+	if x != nil {
+		return x.Receivers
+	}
+	return nil
+}
+
+func (x *SaveDraftRequest) GetFiles() []*File {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+type SaveDraftResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DraftId       string                 `protobuf:"bytes,1,opt,name=draft_id,json=draftId,proto3" json:"draft_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SaveDraftResponse) Reset() {
+	*x = SaveDraftResponse{}
+	mi := &file_messages_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SaveDraftResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveDraftResponse) ProtoMessage() {}
+
+func (x *SaveDraftResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveDraftResponse.ProtoReflect.Descriptor instead.
+func (*SaveDraftResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *SaveDraftResponse) GetDraftId() string {
+	if x != nil {
+		return x.DraftId
+	}
+	return ""
+}
+
+type DeleteDraftRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DraftId       string                 `protobuf:"bytes,1,opt,name=draft_id,json=draftId,proto3" json:"draft_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDraftRequest) Reset() {
+	*x = DeleteDraftRequest{}
+	mi := &file_messages_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDraftRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDraftRequest) ProtoMessage() {}
+
+func (x *DeleteDraftRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDraftRequest.ProtoReflect.Descriptor instead.
+func (*DeleteDraftRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *DeleteDraftRequest) GetDraftId() string {
+	if x != nil {
+		return x.DraftId
+	}
+	return ""
+}
+
+type DeleteDraftResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDraftResponse) Reset() {
+	*x = DeleteDraftResponse{}
+	mi := &file_messages_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDraftResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDraftResponse) ProtoMessage() {}
+
+func (x *DeleteDraftResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDraftResponse.ProtoReflect.Descriptor instead.
+func (*DeleteDraftResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *DeleteDraftResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type SendDraftRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DraftId       string                 `protobuf:"bytes,1,opt,name=draft_id,json=draftId,proto3" json:"draft_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendDraftRequest) Reset() {
+	*x = SendDraftRequest{}
+	mi := &file_messages_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendDraftRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendDraftRequest) ProtoMessage() {}
+
+func (x *SendDraftRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendDraftRequest.ProtoReflect.Descriptor instead.
+func (*SendDraftRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *SendDraftRequest) GetDraftId() string {
+	if x != nil {
+		return x.DraftId
+	}
+	return ""
+}
+
+type SendDraftResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	MessageId     string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendDraftResponse) Reset() {
+	*x = SendDraftResponse{}
+	mi := &file_messages_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendDraftResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendDraftResponse) ProtoMessage() {}
+
+func (x *SendDraftResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendDraftResponse.ProtoReflect.Descriptor instead.
+func (*SendDraftResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *SendDraftResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SendDraftResponse) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+// Методы для sieve-фильтров
+type PutSieveScriptRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Script        string                 `protobuf:"bytes,1,opt,name=script,proto3" json:"script,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutSieveScriptRequest) Reset() {
+	*x = PutSieveScriptRequest{}
+	mi := &file_messages_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutSieveScriptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutSieveScriptRequest) ProtoMessage() {}
+
+func (x *PutSieveScriptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutSieveScriptRequest.ProtoReflect.Descriptor instead.
+func (*PutSieveScriptRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *PutSieveScriptRequest) GetScript() string {
+	if x != nil {
+		return x.Script
+	}
+	return ""
+}
+
+type PutSieveScriptResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutSieveScriptResponse) Reset() {
+	*x = PutSieveScriptResponse{}
+	mi := &file_messages_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutSieveScriptResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutSieveScriptResponse) ProtoMessage() {}
+
+func (x *PutSieveScriptResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutSieveScriptResponse.ProtoReflect.Descriptor instead.
+func (*PutSieveScriptResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{53}
+}
+
+type GetSieveScriptRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSieveScriptRequest) Reset() {
+	*x = GetSieveScriptRequest{}
+	mi := &file_messages_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSieveScriptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSieveScriptRequest) ProtoMessage() {}
+
+func (x *GetSieveScriptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSieveScriptRequest.ProtoReflect.Descriptor instead.
+func (*GetSieveScriptRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{54}
+}
+
+type GetSieveScriptResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Script        string                 `protobuf:"bytes,1,opt,name=script,proto3" json:"script,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSieveScriptResponse) Reset() {
+	*x = GetSieveScriptResponse{}
+	mi := &file_messages_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSieveScriptResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSieveScriptResponse) ProtoMessage() {}
+
+func (x *GetSieveScriptResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSieveScriptResponse.ProtoReflect.Descriptor instead.
+func (*GetSieveScriptResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *GetSieveScriptResponse) GetScript() string {
+	if x != nil {
+		return x.Script
+	}
+	return ""
+}
+
+type CheckSieveScriptRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Script        string                 `protobuf:"bytes,1,opt,name=script,proto3" json:"script,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckSieveScriptRequest) Reset() {
+	*x = CheckSieveScriptRequest{}
+	mi := &file_messages_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckSieveScriptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckSieveScriptRequest) ProtoMessage() {}
+
+func (x *CheckSieveScriptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckSieveScriptRequest.ProtoReflect.Descriptor instead.
+func (*CheckSieveScriptRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *CheckSieveScriptRequest) GetScript() string {
+	if x != nil {
+		return x.Script
+	}
+	return ""
+}
+
+type CheckSieveScriptResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Warnings      []string               `protobuf:"bytes,3,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckSieveScriptResponse) Reset() {
+	*x = CheckSieveScriptResponse{}
+	mi := &file_messages_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckSieveScriptResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckSieveScriptResponse) ProtoMessage() {}
+
+func (x *CheckSieveScriptResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckSieveScriptResponse.ProtoReflect.Descriptor instead.
+func (*CheckSieveScriptResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *CheckSieveScriptResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *CheckSieveScriptResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *CheckSieveScriptResponse) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+// Методы для корзины
+type RestoreFromTrashRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreFromTrashRequest) Reset() {
+	*x = RestoreFromTrashRequest{}
+	mi := &file_messages_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreFromTrashRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreFromTrashRequest) ProtoMessage() {}
+
+func (x *RestoreFromTrashRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreFromTrashRequest.ProtoReflect.Descriptor instead.
+func (*RestoreFromTrashRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *RestoreFromTrashRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+type RestoreFromTrashResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Папка, в которую вернулось письмо
+	FolderId      string `protobuf:"bytes,1,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreFromTrashResponse) Reset() {
+	*x = RestoreFromTrashResponse{}
+	mi := &file_messages_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreFromTrashResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreFromTrashResponse) ProtoMessage() {}
+
+func (x *RestoreFromTrashResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreFromTrashResponse.ProtoReflect.Descriptor instead.
+func (*RestoreFromTrashResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *RestoreFromTrashResponse) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+type EmptyTrashRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmptyTrashRequest) Reset() {
+	*x = EmptyTrashRequest{}
+	mi := &file_messages_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmptyTrashRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmptyTrashRequest) ProtoMessage() {}
+
+func (x *EmptyTrashRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmptyTrashRequest.ProtoReflect.Descriptor instead.
+func (*EmptyTrashRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{60}
+}
+
+type EmptyTrashResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeletedCount  int64                  `protobuf:"varint,1,opt,name=deleted_count,json=deletedCount,proto3" json:"deleted_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmptyTrashResponse) Reset() {
+	*x = EmptyTrashResponse{}
+	mi := &file_messages_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmptyTrashResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmptyTrashResponse) ProtoMessage() {}
+
+func (x *EmptyTrashResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmptyTrashResponse.ProtoReflect.Descriptor instead.
+func (*EmptyTrashResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *EmptyTrashResponse) GetDeletedCount() int64 {
+	if x != nil {
+		return x.DeletedCount
+	}
+	return 0
+}
+
+type DeletePermanentlyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePermanentlyRequest) Reset() {
+	*x = DeletePermanentlyRequest{}
+	mi := &file_messages_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePermanentlyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePermanentlyRequest) ProtoMessage() {}
+
+func (x *DeletePermanentlyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePermanentlyRequest.ProtoReflect.Descriptor instead.
+func (*DeletePermanentlyRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *DeletePermanentlyRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+type DeletePermanentlyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePermanentlyResponse) Reset() {
+	*x = DeletePermanentlyResponse{}
+	mi := &file_messages_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePermanentlyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePermanentlyResponse) ProtoMessage() {}
+
+func (x *DeletePermanentlyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePermanentlyResponse.ProtoReflect.Descriptor instead.
+func (*DeletePermanentlyResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{63}
+}
+
+// Пакетные операции
+type BatchFilter struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	UnreadOnly bool                   `protobuf:"varint,1,opt,name=unread_only,json=unreadOnly,proto3" json:"unread_only,omitempty"`
+	Sender     string                 `protobuf:"bytes,2,opt,name=sender,proto3" json:"sender,omitempty"`
+	// Границы даты отправки в формате RFC3339
+	After          string `protobuf:"bytes,3,opt,name=after,proto3" json:"after,omitempty"`
+	Before         string `protobuf:"bytes,4,opt,name=before,proto3" json:"before,omitempty"`
+	StarredOnly    bool   `protobuf:"varint,5,opt,name=starred_only,json=starredOnly,proto3" json:"starred_only,omitempty"`
+	HasAttachments bool   `protobuf:"varint,6,opt,name=has_attachments,json=hasAttachments,proto3" json:"has_attachments,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BatchFilter) Reset() {
+	*x = BatchFilter{}
+	mi := &file_messages_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchFilter) ProtoMessage() {}
+
+func (x *BatchFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchFilter.ProtoReflect.Descriptor instead.
+func (*BatchFilter) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *BatchFilter) GetUnreadOnly() bool {
+	if x != nil {
+		return x.UnreadOnly
+	}
+	return false
+}
+
+func (x *BatchFilter) GetSender() string {
+	if x != nil {
+		return x.Sender
+	}
+	return ""
+}
+
+func (x *BatchFilter) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
+func (x *BatchFilter) GetBefore() string {
+	if x != nil {
+		return x.Before
+	}
+	return ""
+}
+
+func (x *BatchFilter) GetStarredOnly() bool {
+	if x != nil {
+		return x.StarredOnly
+	}
+	return false
+}
+
+func (x *BatchFilter) GetHasAttachments() bool {
+	if x != nil {
+		return x.HasAttachments
+	}
+	return false
+}
+
+type BatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// move, mark_as_spam, mark_as_not_spam, delete_from_folder, mark_as_read
+	Action string `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	// Письма задаются списком идентификаторов либо папкой с фильтром
+	MessageIds     []string     `protobuf:"bytes,2,rep,name=message_ids,json=messageIds,proto3" json:"message_ids,omitempty"`
+	FolderId       string       `protobuf:"bytes,3,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	Filter         *BatchFilter `protobuf:"bytes,4,opt,name=filter,proto3" json:"filter,omitempty"`
+	TargetFolderId string       `protobuf:"bytes,5,opt,name=target_folder_id,json=targetFolderId,proto3" json:"target_folder_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BatchRequest) Reset() {
+	*x = BatchRequest{}
+	mi := &file_messages_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchRequest) ProtoMessage() {}
+
+func (x *BatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchRequest.ProtoReflect.Descriptor instead.
+func (*BatchRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *BatchRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *BatchRequest) GetMessageIds() []string {
+	if x != nil {
+		return x.MessageIds
+	}
+	return nil
+}
+
+func (x *BatchRequest) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+func (x *BatchRequest) GetFilter() *BatchFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *BatchRequest) GetTargetFolderId() string {
+	if x != nil {
+		return x.TargetFolderId
+	}
+	return ""
+}
+
+type BatchItemResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchItemResult) Reset() {
+	*x = BatchItemResult{}
+	mi := &file_messages_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchItemResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchItemResult) ProtoMessage() {}
+
+func (x *BatchItemResult) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchItemResult.ProtoReflect.Descriptor instead.
+func (*BatchItemResult) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *BatchItemResult) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *BatchItemResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *BatchItemResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type BatchResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Results []*BatchItemResult     `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	// Под фильтр попало больше писем, чем обработано за один запрос
+	HasMore       bool `protobuf:"varint,2,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchResponse) Reset() {
+	*x = BatchResponse{}
+	mi := &file_messages_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchResponse) ProtoMessage() {}
+
+func (x *BatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchResponse.ProtoReflect.Descriptor instead.
+func (*BatchResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *BatchResponse) GetResults() []*BatchItemResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *BatchResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+// Методы для меток
+type CreateLabelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Color         string                 `protobuf:"bytes,2,opt,name=color,proto3" json:"color,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateLabelRequest) Reset() {
+	*x = CreateLabelRequest{}
+	mi := &file_messages_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateLabelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateLabelRequest) ProtoMessage() {}
+
+func (x *CreateLabelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateLabelRequest.ProtoReflect.Descriptor instead.
+func (*CreateLabelRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *CreateLabelRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateLabelRequest) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+type CreateLabelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Label         *Label                 `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateLabelResponse) Reset() {
+	*x = CreateLabelResponse{}
+	mi := &file_messages_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateLabelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateLabelResponse) ProtoMessage() {}
+
+func (x *CreateLabelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateLabelResponse.ProtoReflect.Descriptor instead.
+func (*CreateLabelResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *CreateLabelResponse) GetLabel() *Label {
+	if x != nil {
+		return x.Label
+	}
+	return nil
+}
+
+type GetLabelsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLabelsRequest) Reset() {
+	*x = GetLabelsRequest{}
+	mi := &file_messages_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLabelsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLabelsRequest) ProtoMessage() {}
+
+func (x *GetLabelsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLabelsRequest.ProtoReflect.Descriptor instead.
+func (*GetLabelsRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{70}
+}
+
+type GetLabelsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Labels        []*Label               `protobuf:"bytes,1,rep,name=labels,proto3" json:"labels,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLabelsResponse) Reset() {
+	*x = GetLabelsResponse{}
+	mi := &file_messages_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLabelsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLabelsResponse) ProtoMessage() {}
+
+func (x *GetLabelsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLabelsResponse.ProtoReflect.Descriptor instead.
+func (*GetLabelsResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *GetLabelsResponse) GetLabels() []*Label {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type UpdateLabelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LabelId       string                 `protobuf:"bytes,1,opt,name=label_id,json=labelId,proto3" json:"label_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Color         string                 `protobuf:"bytes,3,opt,name=color,proto3" json:"color,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateLabelRequest) Reset() {
+	*x = UpdateLabelRequest{}
+	mi := &file_messages_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateLabelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateLabelRequest) ProtoMessage() {}
+
+func (x *UpdateLabelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateLabelRequest.ProtoReflect.Descriptor instead.
+func (*UpdateLabelRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *UpdateLabelRequest) GetLabelId() string {
+	if x != nil {
+		return x.LabelId
+	}
+	return ""
+}
+
+func (x *UpdateLabelRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateLabelRequest) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+type UpdateLabelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Label         *Label                 `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateLabelResponse) Reset() {
+	*x = UpdateLabelResponse{}
+	mi := &file_messages_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateLabelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateLabelResponse) ProtoMessage() {}
+
+func (x *UpdateLabelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateLabelResponse.ProtoReflect.Descriptor instead.
+func (*UpdateLabelResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *UpdateLabelResponse) GetLabel() *Label {
+	if x != nil {
+		return x.Label
+	}
+	return nil
+}
+
+type DeleteLabelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LabelId       string                 `protobuf:"bytes,1,opt,name=label_id,json=labelId,proto3" json:"label_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteLabelRequest) Reset() {
+	*x = DeleteLabelRequest{}
+	mi := &file_messages_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteLabelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteLabelRequest) ProtoMessage() {}
+
+func (x *DeleteLabelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteLabelRequest.ProtoReflect.Descriptor instead.
+func (*DeleteLabelRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *DeleteLabelRequest) GetLabelId() string {
+	if x != nil {
+		return x.LabelId
+	}
+	return ""
+}
+
+type DeleteLabelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteLabelResponse) Reset() {
+	*x = DeleteLabelResponse{}
+	mi := &file_messages_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteLabelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteLabelResponse) ProtoMessage() {}
+
+func (x *DeleteLabelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteLabelResponse.ProtoReflect.Descriptor instead.
+func (*DeleteLabelResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{75}
+}
+
+type ApplyLabelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LabelId       string                 `protobuf:"bytes,1,opt,name=label_id,json=labelId,proto3" json:"label_id,omitempty"`
+	MessageIds    []string               `protobuf:"bytes,2,rep,name=message_ids,json=messageIds,proto3" json:"message_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyLabelRequest) Reset() {
+	*x = ApplyLabelRequest{}
+	mi := &file_messages_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyLabelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyLabelRequest) ProtoMessage() {}
+
+func (x *ApplyLabelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyLabelRequest.ProtoReflect.Descriptor instead.
+func (*ApplyLabelRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *ApplyLabelRequest) GetLabelId() string {
+	if x != nil {
+		return x.LabelId
+	}
+	return ""
+}
+
+func (x *ApplyLabelRequest) GetMessageIds() []string {
+	if x != nil {
+		return x.MessageIds
+	}
+	return nil
+}
+
+type ApplyLabelResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Число писем, на которые метка была повешена впервые
+	AppliedCount  int64 `protobuf:"varint,1,opt,name=applied_count,json=appliedCount,proto3" json:"applied_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyLabelResponse) Reset() {
+	*x = ApplyLabelResponse{}
+	mi := &file_messages_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyLabelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyLabelResponse) ProtoMessage() {}
+
+func (x *ApplyLabelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyLabelResponse.ProtoReflect.Descriptor instead.
+func (*ApplyLabelResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *ApplyLabelResponse) GetAppliedCount() int64 {
+	if x != nil {
+		return x.AppliedCount
+	}
+	return 0
+}
+
+type RemoveLabelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LabelId       string                 `protobuf:"bytes,1,opt,name=label_id,json=labelId,proto3" json:"label_id,omitempty"`
+	MessageIds    []string               `protobuf:"bytes,2,rep,name=message_ids,json=messageIds,proto3" json:"message_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveLabelRequest) Reset() {
+	*x = RemoveLabelRequest{}
+	mi := &file_messages_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveLabelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveLabelRequest) ProtoMessage() {}
+
+func (x *RemoveLabelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveLabelRequest.ProtoReflect.Descriptor instead.
+func (*RemoveLabelRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *RemoveLabelRequest) GetLabelId() string {
+	if x != nil {
+		return x.LabelId
+	}
+	return ""
+}
+
+func (x *RemoveLabelRequest) GetMessageIds() []string {
+	if x != nil {
+		return x.MessageIds
+	}
+	return nil
+}
+
+type RemoveLabelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RemovedCount  int64                  `protobuf:"varint,1,opt,name=removed_count,json=removedCount,proto3" json:"removed_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveLabelResponse) Reset() {
+	*x = RemoveLabelResponse{}
+	mi := &file_messages_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveLabelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveLabelResponse) ProtoMessage() {}
+
+func (x *RemoveLabelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveLabelResponse.ProtoReflect.Descriptor instead.
+func (*RemoveLabelResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *RemoveLabelResponse) GetRemovedCount() int64 {
+	if x != nil {
+		return x.RemovedCount
+	}
+	return 0
+}
+
+// Методы для автопересылки
+type ForwardingRule struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Enabled   bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Addresses []string               `protobuf:"bytes,2,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	// Оставлять копию пересланного письма во входящих
+	KeepCopy bool `protobuf:"varint,3,opt,name=keep_copy,json=keepCopy,proto3" json:"keep_copy,omitempty"`
+	// Условия отбора, пустое условие подходит любому письму
+	FromContains    string `protobuf:"bytes,4,opt,name=from_contains,json=fromContains,proto3" json:"from_contains,omitempty"`
+	SubjectContains string `protobuf:"bytes,5,opt,name=subject_contains,json=subjectContains,proto3" json:"subject_contains,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ForwardingRule) Reset() {
+	*x = ForwardingRule{}
+	mi := &file_messages_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForwardingRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardingRule) ProtoMessage() {}
+
+func (x *ForwardingRule) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardingRule.ProtoReflect.Descriptor instead.
+func (*ForwardingRule) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *ForwardingRule) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ForwardingRule) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+func (x *ForwardingRule) GetKeepCopy() bool {
+	if x != nil {
+		return x.KeepCopy
+	}
+	return false
+}
+
+func (x *ForwardingRule) GetFromContains() string {
+	if x != nil {
+		return x.FromContains
+	}
+	return ""
+}
+
+func (x *ForwardingRule) GetSubjectContains() string {
+	if x != nil {
+		return x.SubjectContains
+	}
+	return ""
+}
+
+type GetForwardingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetForwardingRequest) Reset() {
+	*x = GetForwardingRequest{}
+	mi := &file_messages_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetForwardingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetForwardingRequest) ProtoMessage() {}
+
+func (x *GetForwardingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetForwardingRequest.ProtoReflect.Descriptor instead.
+func (*GetForwardingRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{81}
+}
+
+type GetForwardingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *ForwardingRule        `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetForwardingResponse) Reset() {
+	*x = GetForwardingResponse{}
+	mi := &file_messages_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetForwardingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetForwardingResponse) ProtoMessage() {}
+
+func (x *GetForwardingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetForwardingResponse.ProtoReflect.Descriptor instead.
+func (*GetForwardingResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *GetForwardingResponse) GetRule() *ForwardingRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+type PutForwardingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *ForwardingRule        `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutForwardingRequest) Reset() {
+	*x = PutForwardingRequest{}
+	mi := &file_messages_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutForwardingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutForwardingRequest) ProtoMessage() {}
+
+func (x *PutForwardingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutForwardingRequest.ProtoReflect.Descriptor instead.
+func (*PutForwardingRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *PutForwardingRequest) GetRule() *ForwardingRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+type PutForwardingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *ForwardingRule        `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutForwardingResponse) Reset() {
+	*x = PutForwardingResponse{}
+	mi := &file_messages_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutForwardingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutForwardingResponse) ProtoMessage() {}
+
+func (x *PutForwardingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutForwardingResponse.ProtoReflect.Descriptor instead.
+func (*PutForwardingResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *PutForwardingResponse) GetRule() *ForwardingRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+// Методы для адресной книги
+type Contact struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ContactId     string                 `protobuf:"bytes,1,opt,name=contact_id,json=contactId,proto3" json:"contact_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Emails        []string               `protobuf:"bytes,3,rep,name=emails,proto3" json:"emails,omitempty"`
+	Phone         string                 `protobuf:"bytes,4,opt,name=phone,proto3" json:"phone,omitempty"`
+	Notes         string                 `protobuf:"bytes,5,opt,name=notes,proto3" json:"notes,omitempty"`
+	Avatar        string                 `protobuf:"bytes,6,opt,name=avatar,proto3" json:"avatar,omitempty"`
+	GroupIds      []string               `protobuf:"bytes,7,rep,name=group_ids,json=groupIds,proto3" json:"group_ids,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Contact) Reset() {
+	*x = Contact{}
+	mi := &file_messages_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Contact) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Contact) ProtoMessage() {}
+
+func (x *Contact) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Contact.ProtoReflect.Descriptor instead.
+func (*Contact) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *Contact) GetContactId() string {
+	if x != nil {
+		return x.ContactId
+	}
+	return ""
+}
+
+func (x *Contact) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Contact) GetEmails() []string {
+	if x != nil {
+		return x.Emails
+	}
+	return nil
+}
+
+func (x *Contact) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *Contact) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *Contact) GetAvatar() string {
+	if x != nil {
+		return x.Avatar
+	}
+	return ""
+}
+
+func (x *Contact) GetGroupIds() []string {
+	if x != nil {
+		return x.GroupIds
+	}
+	return nil
+}
+
+func (x *Contact) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type ContactGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupId       string                 `protobuf:"bytes,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ContactCount  int64                  `protobuf:"varint,3,opt,name=contact_count,json=contactCount,proto3" json:"contact_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContactGroup) Reset() {
+	*x = ContactGroup{}
+	mi := &file_messages_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContactGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContactGroup) ProtoMessage() {}
+
+func (x *ContactGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContactGroup.ProtoReflect.Descriptor instead.
+func (*ContactGroup) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *ContactGroup) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *ContactGroup) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ContactGroup) GetContactCount() int64 {
+	if x != nil {
+		return x.ContactCount
+	}
+	return 0
+}
+
+type ContactSuggestion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ContactId     string                 `protobuf:"bytes,1,opt,name=contact_id,json=contactId,proto3" json:"contact_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContactSuggestion) Reset() {
+	*x = ContactSuggestion{}
+	mi := &file_messages_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContactSuggestion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContactSuggestion) ProtoMessage() {}
+
+func (x *ContactSuggestion) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[87]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContactSuggestion.ProtoReflect.Descriptor instead.
+func (*ContactSuggestion) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *ContactSuggestion) GetContactId() string {
+	if x != nil {
+		return x.ContactId
+	}
+	return ""
+}
+
+func (x *ContactSuggestion) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ContactSuggestion) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type CreateContactRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Contact       *Contact               `protobuf:"bytes,1,opt,name=contact,proto3" json:"contact,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateContactRequest) Reset() {
+	*x = CreateContactRequest{}
+	mi := &file_messages_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateContactRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateContactRequest) ProtoMessage() {}
+
+func (x *CreateContactRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[88]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateContactRequest.ProtoReflect.Descriptor instead.
+func (*CreateContactRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{88}
+}
+
+func (x *CreateContactRequest) GetContact() *Contact {
+	if x != nil {
+		return x.Contact
+	}
+	return nil
+}
+
+type CreateContactResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Contact       *Contact               `protobuf:"bytes,1,opt,name=contact,proto3" json:"contact,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateContactResponse) Reset() {
+	*x = CreateContactResponse{}
+	mi := &file_messages_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateContactResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateContactResponse) ProtoMessage() {}
+
+func (x *CreateContactResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[89]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateContactResponse.ProtoReflect.Descriptor instead.
+func (*CreateContactResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *CreateContactResponse) GetContact() *Contact {
+	if x != nil {
+		return x.Contact
+	}
+	return nil
+}
+
+type GetContactsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Пустой group_id - все контакты
+	GroupId       string `protobuf:"bytes,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetContactsRequest) Reset() {
+	*x = GetContactsRequest{}
+	mi := &file_messages_proto_msgTypes[90]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetContactsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetContactsRequest) ProtoMessage() {}
+
+func (x *GetContactsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[90]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetContactsRequest.ProtoReflect.Descriptor instead.
+func (*GetContactsRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *GetContactsRequest) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+type GetContactsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Contacts      []*Contact             `protobuf:"bytes,1,rep,name=contacts,proto3" json:"contacts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetContactsResponse) Reset() {
+	*x = GetContactsResponse{}
+	mi := &file_messages_proto_msgTypes[91]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetContactsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetContactsResponse) ProtoMessage() {}
+
+func (x *GetContactsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[91]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetContactsResponse.ProtoReflect.Descriptor instead.
+func (*GetContactsResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{91}
+}
+
+func (x *GetContactsResponse) GetContacts() []*Contact {
+	if x != nil {
+		return x.Contacts
+	}
+	return nil
+}
+
+type UpdateContactRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Contact       *Contact               `protobuf:"bytes,1,opt,name=contact,proto3" json:"contact,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateContactRequest) Reset() {
+	*x = UpdateContactRequest{}
+	mi := &file_messages_proto_msgTypes[92]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateContactRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateContactRequest) ProtoMessage() {}
+
+func (x *UpdateContactRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[92]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateContactRequest.ProtoReflect.Descriptor instead.
+func (*UpdateContactRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{92}
+}
+
+func (x *UpdateContactRequest) GetContact() *Contact {
+	if x != nil {
+		return x.Contact
+	}
+	return nil
+}
+
+type UpdateContactResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Contact       *Contact               `protobuf:"bytes,1,opt,name=contact,proto3" json:"contact,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateContactResponse) Reset() {
+	*x = UpdateContactResponse{}
+	mi := &file_messages_proto_msgTypes[93]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateContactResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateContactResponse) ProtoMessage() {}
+
+func (x *UpdateContactResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[93]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateContactResponse.ProtoReflect.Descriptor instead.
+func (*UpdateContactResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{93}
+}
+
+func (x *UpdateContactResponse) GetContact() *Contact {
+	if x != nil {
+		return x.Contact
+	}
+	return nil
+}
+
+type DeleteContactRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ContactId     string                 `protobuf:"bytes,1,opt,name=contact_id,json=contactId,proto3" json:"contact_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteContactRequest) Reset() {
+	*x = DeleteContactRequest{}
+	mi := &file_messages_proto_msgTypes[94]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteContactRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteContactRequest) ProtoMessage() {}
+
+func (x *DeleteContactRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[94]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteContactRequest.ProtoReflect.Descriptor instead.
+func (*DeleteContactRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{94}
+}
+
+func (x *DeleteContactRequest) GetContactId() string {
+	if x != nil {
+		return x.ContactId
+	}
+	return ""
+}
+
+type DeleteContactResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteContactResponse) Reset() {
+	*x = DeleteContactResponse{}
+	mi := &file_messages_proto_msgTypes[95]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteContactResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteContactResponse) ProtoMessage() {}
+
+func (x *DeleteContactResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[95]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteContactResponse.ProtoReflect.Descriptor instead.
+func (*DeleteContactResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{95}
+}
+
+type SuggestContactsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Начало адреса или имени
+	Prefix        string `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Limit         int32  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuggestContactsRequest) Reset() {
+	*x = SuggestContactsRequest{}
+	mi := &file_messages_proto_msgTypes[96]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestContactsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestContactsRequest) ProtoMessage() {}
+
+func (x *SuggestContactsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[96]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestContactsRequest.ProtoReflect.Descriptor instead.
+func (*SuggestContactsRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{96}
+}
+
+func (x *SuggestContactsRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *SuggestContactsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type SuggestContactsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Suggestions   []*ContactSuggestion   `protobuf:"bytes,1,rep,name=suggestions,proto3" json:"suggestions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SuggestContactsResponse) Reset() {
+	*x = SuggestContactsResponse{}
+	mi := &file_messages_proto_msgTypes[97]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestContactsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestContactsResponse) ProtoMessage() {}
+
+func (x *SuggestContactsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[97]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestContactsResponse.ProtoReflect.Descriptor instead.
+func (*SuggestContactsResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{97}
+}
+
+func (x *SuggestContactsResponse) GetSuggestions() []*ContactSuggestion {
+	if x != nil {
+		return x.Suggestions
+	}
+	return nil
+}
+
+type CreateContactGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateContactGroupRequest) Reset() {
+	*x = CreateContactGroupRequest{}
+	mi := &file_messages_proto_msgTypes[98]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateContactGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateContactGroupRequest) ProtoMessage() {}
+
+func (x *CreateContactGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[98]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateContactGroupRequest.ProtoReflect.Descriptor instead.
+func (*CreateContactGroupRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{98}
+}
+
+func (x *CreateContactGroupRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CreateContactGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Group         *ContactGroup          `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateContactGroupResponse) Reset() {
+	*x = CreateContactGroupResponse{}
+	mi := &file_messages_proto_msgTypes[99]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateContactGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateContactGroupResponse) ProtoMessage() {}
+
+func (x *CreateContactGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[99]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateContactGroupResponse.ProtoReflect.Descriptor instead.
+func (*CreateContactGroupResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{99}
+}
+
+func (x *CreateContactGroupResponse) GetGroup() *ContactGroup {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+type GetContactGroupsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetContactGroupsRequest) Reset() {
+	*x = GetContactGroupsRequest{}
+	mi := &file_messages_proto_msgTypes[100]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetContactGroupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetContactGroupsRequest) ProtoMessage() {}
+
+func (x *GetContactGroupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[100]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetContactGroupsRequest.ProtoReflect.Descriptor instead.
+func (*GetContactGroupsRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{100}
+}
+
+type GetContactGroupsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Groups        []*ContactGroup        `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetContactGroupsResponse) Reset() {
+	*x = GetContactGroupsResponse{}
+	mi := &file_messages_proto_msgTypes[101]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetContactGroupsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetContactGroupsResponse) ProtoMessage() {}
+
+func (x *GetContactGroupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[101]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetContactGroupsResponse.ProtoReflect.Descriptor instead.
+func (*GetContactGroupsResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{101}
+}
+
+func (x *GetContactGroupsResponse) GetGroups() []*ContactGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+type RenameContactGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupId       string                 `protobuf:"bytes,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameContactGroupRequest) Reset() {
+	*x = RenameContactGroupRequest{}
+	mi := &file_messages_proto_msgTypes[102]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameContactGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameContactGroupRequest) ProtoMessage() {}
+
+func (x *RenameContactGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[102]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameContactGroupRequest.ProtoReflect.Descriptor instead.
+func (*RenameContactGroupRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{102}
+}
+
+func (x *RenameContactGroupRequest) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *RenameContactGroupRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type RenameContactGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Group         *ContactGroup          `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameContactGroupResponse) Reset() {
+	*x = RenameContactGroupResponse{}
+	mi := &file_messages_proto_msgTypes[103]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameContactGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameContactGroupResponse) ProtoMessage() {}
+
+func (x *RenameContactGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[103]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameContactGroupResponse.ProtoReflect.Descriptor instead.
+func (*RenameContactGroupResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{103}
+}
+
+func (x *RenameContactGroupResponse) GetGroup() *ContactGroup {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+type DeleteContactGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupId       string                 `protobuf:"bytes,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteContactGroupRequest) Reset() {
+	*x = DeleteContactGroupRequest{}
+	mi := &file_messages_proto_msgTypes[104]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteContactGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteContactGroupRequest) ProtoMessage() {}
+
+func (x *DeleteContactGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[104]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteContactGroupRequest.ProtoReflect.Descriptor instead.
+func (*DeleteContactGroupRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{104}
+}
+
+func (x *DeleteContactGroupRequest) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+type DeleteContactGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteContactGroupResponse) Reset() {
+	*x = DeleteContactGroupResponse{}
+	mi := &file_messages_proto_msgTypes[105]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteContactGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteContactGroupResponse) ProtoMessage() {}
+
+func (x *DeleteContactGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[105]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteContactGroupResponse.ProtoReflect.Descriptor instead.
+func (*DeleteContactGroupResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{105}
+}
+
+// Методы для списков блокировки и разрешенных отправителей
+type SenderRule struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	RuleId string                 `protobuf:"bytes,1,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
+	// "block" или "allow"
+	Kind string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	// Точный адрес или домен
+	Pattern       string `protobuf:"bytes,3,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	CreatedAt     string `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SenderRule) Reset() {
+	*x = SenderRule{}
+	mi := &file_messages_proto_msgTypes[106]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SenderRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SenderRule) ProtoMessage() {}
+
+func (x *SenderRule) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[106]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SenderRule.ProtoReflect.Descriptor instead.
+func (*SenderRule) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{106}
+}
+
+func (x *SenderRule) GetRuleId() string {
+	if x != nil {
+		return x.RuleId
+	}
+	return ""
+}
+
+func (x *SenderRule) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *SenderRule) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+func (x *SenderRule) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type GetSenderListsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSenderListsRequest) Reset() {
+	*x = GetSenderListsRequest{}
+	mi := &file_messages_proto_msgTypes[107]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSenderListsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSenderListsRequest) ProtoMessage() {}
+
+func (x *GetSenderListsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[107]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSenderListsRequest.ProtoReflect.Descriptor instead.
+func (*GetSenderListsRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{107}
+}
+
+type GetSenderListsResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Blocked []*SenderRule          `protobuf:"bytes,1,rep,name=blocked,proto3" json:"blocked,omitempty"`
+	Allowed []*SenderRule          `protobuf:"bytes,2,rep,name=allowed,proto3" json:"allowed,omitempty"`
+	// "spam" или "discard"
+	BlockedAction string `protobuf:"bytes,3,opt,name=blocked_action,json=blockedAction,proto3" json:"blocked_action,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSenderListsResponse) Reset() {
+	*x = GetSenderListsResponse{}
+	mi := &file_messages_proto_msgTypes[108]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSenderListsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSenderListsResponse) ProtoMessage() {}
+
+func (x *GetSenderListsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[108]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSenderListsResponse.ProtoReflect.Descriptor instead.
+func (*GetSenderListsResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{108}
+}
+
+func (x *GetSenderListsResponse) GetBlocked() []*SenderRule {
+	if x != nil {
+		return x.Blocked
+	}
+	return nil
+}
+
+func (x *GetSenderListsResponse) GetAllowed() []*SenderRule {
+	if x != nil {
+		return x.Allowed
+	}
+	return nil
+}
+
+func (x *GetSenderListsResponse) GetBlockedAction() string {
+	if x != nil {
+		return x.BlockedAction
+	}
+	return ""
+}
+
+type AddSenderRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Pattern       string                 `protobuf:"bytes,2,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddSenderRuleRequest) Reset() {
+	*x = AddSenderRuleRequest{}
+	mi := &file_messages_proto_msgTypes[109]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddSenderRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddSenderRuleRequest) ProtoMessage() {}
+
+func (x *AddSenderRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[109]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddSenderRuleRequest.ProtoReflect.Descriptor instead.
+func (*AddSenderRuleRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{109}
+}
+
+func (x *AddSenderRuleRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *AddSenderRuleRequest) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+type AddSenderRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *SenderRule            `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddSenderRuleResponse) Reset() {
+	*x = AddSenderRuleResponse{}
+	mi := &file_messages_proto_msgTypes[110]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddSenderRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddSenderRuleResponse) ProtoMessage() {}
+
+func (x *AddSenderRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[110]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddSenderRuleResponse.ProtoReflect.Descriptor instead.
+func (*AddSenderRuleResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{110}
+}
+
+func (x *AddSenderRuleResponse) GetRule() *SenderRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+type DeleteSenderRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RuleId        string                 `protobuf:"bytes,1,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSenderRuleRequest) Reset() {
+	*x = DeleteSenderRuleRequest{}
+	mi := &file_messages_proto_msgTypes[111]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSenderRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSenderRuleRequest) ProtoMessage() {}
+
+func (x *DeleteSenderRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[111]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSenderRuleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSenderRuleRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{111}
+}
+
+func (x *DeleteSenderRuleRequest) GetRuleId() string {
+	if x != nil {
+		return x.RuleId
+	}
+	return ""
+}
+
+type DeleteSenderRuleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSenderRuleResponse) Reset() {
+	*x = DeleteSenderRuleResponse{}
+	mi := &file_messages_proto_msgTypes[112]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSenderRuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSenderRuleResponse) ProtoMessage() {}
+
+func (x *DeleteSenderRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[112]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSenderRuleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteSenderRuleResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{112}
+}
+
+type SetBlockedSenderActionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Action        string                 `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetBlockedSenderActionRequest) Reset() {
+	*x = SetBlockedSenderActionRequest{}
+	mi := &file_messages_proto_msgTypes[113]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetBlockedSenderActionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetBlockedSenderActionRequest) ProtoMessage() {}
+
+func (x *SetBlockedSenderActionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[113]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetBlockedSenderActionRequest.ProtoReflect.Descriptor instead.
+func (*SetBlockedSenderActionRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{113}
+}
+
+func (x *SetBlockedSenderActionRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+type SetBlockedSenderActionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetBlockedSenderActionResponse) Reset() {
+	*x = SetBlockedSenderActionResponse{}
+	mi := &file_messages_proto_msgTypes[114]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetBlockedSenderActionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetBlockedSenderActionResponse) ProtoMessage() {}
+
+func (x *SetBlockedSenderActionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[114]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetBlockedSenderActionResponse.ProtoReflect.Descriptor instead.
+func (*SetBlockedSenderActionResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{114}
+}
+
+// Блокировка отправителя открытого письма
+type BlockSenderRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	MessageId string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	// Заблокировать весь домен отправителя, а не только адрес
+	WholeDomain   bool `protobuf:"varint,2,opt,name=whole_domain,json=wholeDomain,proto3" json:"whole_domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockSenderRequest) Reset() {
+	*x = BlockSenderRequest{}
+	mi := &file_messages_proto_msgTypes[115]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockSenderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockSenderRequest) ProtoMessage() {}
+
+func (x *BlockSenderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[115]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockSenderRequest.ProtoReflect.Descriptor instead.
+func (*BlockSenderRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{115}
+}
+
+func (x *BlockSenderRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *BlockSenderRequest) GetWholeDomain() bool {
+	if x != nil {
+		return x.WholeDomain
+	}
+	return false
+}
+
+type BlockSenderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *SenderRule            `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockSenderResponse) Reset() {
+	*x = BlockSenderResponse{}
+	mi := &file_messages_proto_msgTypes[116]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockSenderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockSenderResponse) ProtoMessage() {}
+
+func (x *BlockSenderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[116]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockSenderResponse.ProtoReflect.Descriptor instead.
+func (*BlockSenderResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{116}
+}
+
+func (x *BlockSenderResponse) GetRule() *SenderRule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+// Дополнительные адреса профиля
+type Alias struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AliasId       string                 `protobuf:"bytes,1,opt,name=alias_id,json=aliasId,proto3" json:"alias_id,omitempty"`
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Alias) Reset() {
+	*x = Alias{}
+	mi := &file_messages_proto_msgTypes[117]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Alias) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Alias) ProtoMessage() {}
+
+func (x *Alias) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[117]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Alias.ProtoReflect.Descriptor instead.
+func (*Alias) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{117}
+}
+
+func (x *Alias) GetAliasId() string {
+	if x != nil {
+		return x.AliasId
+	}
+	return ""
+}
+
+func (x *Alias) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Alias) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type GetAliasesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAliasesRequest) Reset() {
+	*x = GetAliasesRequest{}
+	mi := &file_messages_proto_msgTypes[118]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAliasesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAliasesRequest) ProtoMessage() {}
+
+func (x *GetAliasesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[118]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAliasesRequest.ProtoReflect.Descriptor instead.
+func (*GetAliasesRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{118}
+}
+
+type GetAliasesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Aliases       []*Alias               `protobuf:"bytes,1,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAliasesResponse) Reset() {
+	*x = GetAliasesResponse{}
+	mi := &file_messages_proto_msgTypes[119]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAliasesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAliasesResponse) ProtoMessage() {}
+
+func (x *GetAliasesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[119]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAliasesResponse.ProtoReflect.Descriptor instead.
+func (*GetAliasesResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{119}
+}
+
+func (x *GetAliasesResponse) GetAliases() []*Alias {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+type AddAliasRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Имя адреса до @, домен совпадает с доменом основного адреса
+	Username      string `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddAliasRequest) Reset() {
+	*x = AddAliasRequest{}
+	mi := &file_messages_proto_msgTypes[120]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddAliasRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddAliasRequest) ProtoMessage() {}
+
+func (x *AddAliasRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[120]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddAliasRequest.ProtoReflect.Descriptor instead.
+func (*AddAliasRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{120}
+}
+
+func (x *AddAliasRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type AddAliasResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alias         *Alias                 `protobuf:"bytes,1,opt,name=alias,proto3" json:"alias,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateFolderResponse) Reset() {
-	*x = CreateFolderResponse{}
-	mi := &file_messages_proto_msgTypes[23]
+func (x *AddAliasResponse) Reset() {
+	*x = AddAliasResponse{}
+	mi := &file_messages_proto_msgTypes[121]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateFolderResponse) String() string {
+func (x *AddAliasResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateFolderResponse) ProtoMessage() {}
+func (*AddAliasResponse) ProtoMessage() {}
 
-func (x *CreateFolderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[23]
+func (x *AddAliasResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[121]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1366,57 +6496,40 @@ func (x *CreateFolderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateFolderResponse.ProtoReflect.Descriptor instead.
-func (*CreateFolderResponse) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{23}
-}
-
-func (x *CreateFolderResponse) GetFolderId() string {
-	if x != nil {
-		return x.FolderId
-	}
-	return ""
-}
-
-func (x *CreateFolderResponse) GetFolderName() string {
-	if x != nil {
-		return x.FolderName
-	}
-	return ""
+// Deprecated: Use AddAliasResponse.ProtoReflect.Descriptor instead.
+func (*AddAliasResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{121}
 }
 
-func (x *CreateFolderResponse) GetFolderType() string {
+func (x *AddAliasResponse) GetAlias() *Alias {
 	if x != nil {
-		return x.FolderType
+		return x.Alias
 	}
-	return ""
+	return nil
 }
 
-type GetFolderRequest struct {
+type DeleteAliasRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	FolderId      string                 `protobuf:"bytes,1,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
-	LastMessageId string                 `protobuf:"bytes,2,opt,name=last_message_id,json=lastMessageId,proto3" json:"last_message_id,omitempty"`
-	LastDatetime  string                 `protobuf:"bytes,3,opt,name=last_datetime,json=lastDatetime,proto3" json:"last_datetime,omitempty"`
-	Limit         string                 `protobuf:"bytes,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	AliasId       string                 `protobuf:"bytes,1,opt,name=alias_id,json=aliasId,proto3" json:"alias_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetFolderRequest) Reset() {
-	*x = GetFolderRequest{}
-	mi := &file_messages_proto_msgTypes[24]
+func (x *DeleteAliasRequest) Reset() {
+	*x = DeleteAliasRequest{}
+	mi := &file_messages_proto_msgTypes[122]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetFolderRequest) String() string {
+func (x *DeleteAliasRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetFolderRequest) ProtoMessage() {}
+func (*DeleteAliasRequest) ProtoMessage() {}
 
-func (x *GetFolderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[24]
+func (x *DeleteAliasRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[122]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1427,64 +6540,83 @@ func (x *GetFolderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetFolderRequest.ProtoReflect.Descriptor instead.
-func (*GetFolderRequest) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{24}
+// Deprecated: Use DeleteAliasRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAliasRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{122}
 }
 
-func (x *GetFolderRequest) GetFolderId() string {
+func (x *DeleteAliasRequest) GetAliasId() string {
 	if x != nil {
-		return x.FolderId
+		return x.AliasId
 	}
 	return ""
 }
 
-func (x *GetFolderRequest) GetLastMessageId() string {
-	if x != nil {
-		return x.LastMessageId
-	}
-	return ""
+type DeleteAliasResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetFolderRequest) GetLastDatetime() string {
-	if x != nil {
-		return x.LastDatetime
-	}
-	return ""
+func (x *DeleteAliasResponse) Reset() {
+	*x = DeleteAliasResponse{}
+	mi := &file_messages_proto_msgTypes[123]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetFolderRequest) GetLimit() string {
+func (x *DeleteAliasResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAliasResponse) ProtoMessage() {}
+
+func (x *DeleteAliasResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[123]
 	if x != nil {
-		return x.Limit
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-type GetFolderResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	MessageTotal  string                 `protobuf:"bytes,1,opt,name=message_total,json=messageTotal,proto3" json:"message_total,omitempty"`
-	MessageUnread string                 `protobuf:"bytes,2,opt,name=message_unread,json=messageUnread,proto3" json:"message_unread,omitempty"`
-	Messages      []*Message             `protobuf:"bytes,3,rep,name=messages,proto3" json:"messages,omitempty"`
-	Pagination    *PaginationInfo        `protobuf:"bytes,4,opt,name=pagination,proto3" json:"pagination,omitempty"`
+// Deprecated: Use DeleteAliasResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAliasResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{123}
+}
+
+// Настройки POP3
+type Pop3Settings struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// trash - удаленное по POP3 письмо переносится в корзину, hide - только скрывается от POP3
+	DeleteAction string `protobuf:"bytes,1,opt,name=delete_action,json=deleteAction,proto3" json:"delete_action,omitempty"`
+	ApopEnabled  bool   `protobuf:"varint,2,opt,name=apop_enabled,json=apopEnabled,proto3" json:"apop_enabled,omitempty"`
+	// Новый секрет для команды APOP, в ответах не возвращается. Пустой при включенном
+	// APOP оставляет прежний секрет. Это отдельный общий секрет с почтовым клиентом,
+	// а не пароль входа: сервер хранит его открытым
+	ApopSecret    string `protobuf:"bytes,3,opt,name=apop_secret,json=apopSecret,proto3" json:"apop_secret,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetFolderResponse) Reset() {
-	*x = GetFolderResponse{}
-	mi := &file_messages_proto_msgTypes[25]
+func (x *Pop3Settings) Reset() {
+	*x = Pop3Settings{}
+	mi := &file_messages_proto_msgTypes[124]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetFolderResponse) String() string {
+func (x *Pop3Settings) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetFolderResponse) ProtoMessage() {}
+func (*Pop3Settings) ProtoMessage() {}
 
-func (x *GetFolderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[25]
+func (x *Pop3Settings) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[124]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1495,60 +6627,53 @@ func (x *GetFolderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetFolderResponse.ProtoReflect.Descriptor instead.
-func (*GetFolderResponse) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{25}
-}
-
-func (x *GetFolderResponse) GetMessageTotal() string {
-	if x != nil {
-		return x.MessageTotal
-	}
-	return ""
+// Deprecated: Use Pop3Settings.ProtoReflect.Descriptor instead.
+func (*Pop3Settings) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{124}
 }
 
-func (x *GetFolderResponse) GetMessageUnread() string {
+func (x *Pop3Settings) GetDeleteAction() string {
 	if x != nil {
-		return x.MessageUnread
+		return x.DeleteAction
 	}
 	return ""
 }
 
-func (x *GetFolderResponse) GetMessages() []*Message {
+func (x *Pop3Settings) GetApopEnabled() bool {
 	if x != nil {
-		return x.Messages
+		return x.ApopEnabled
 	}
-	return nil
+	return false
 }
 
-func (x *GetFolderResponse) GetPagination() *PaginationInfo {
+func (x *Pop3Settings) GetApopSecret() string {
 	if x != nil {
-		return x.Pagination
+		return x.ApopSecret
 	}
-	return nil
+	return ""
 }
 
-type GetFoldersRequest struct {
+type GetPop3SettingsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetFoldersRequest) Reset() {
-	*x = GetFoldersRequest{}
-	mi := &file_messages_proto_msgTypes[26]
+func (x *GetPop3SettingsRequest) Reset() {
+	*x = GetPop3SettingsRequest{}
+	mi := &file_messages_proto_msgTypes[125]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetFoldersRequest) String() string {
+func (x *GetPop3SettingsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetFoldersRequest) ProtoMessage() {}
+func (*GetPop3SettingsRequest) ProtoMessage() {}
 
-func (x *GetFoldersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[26]
+func (x *GetPop3SettingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[125]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1559,33 +6684,33 @@ func (x *GetFoldersRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetFoldersRequest.ProtoReflect.Descriptor instead.
-func (*GetFoldersRequest) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{26}
+// Deprecated: Use GetPop3SettingsRequest.ProtoReflect.Descriptor instead.
+func (*GetPop3SettingsRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{125}
 }
 
-type GetFoldersResponse struct {
+type GetPop3SettingsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Folders       []*Folder              `protobuf:"bytes,1,rep,name=folders,proto3" json:"folders,omitempty"`
+	Settings      *Pop3Settings          `protobuf:"bytes,1,opt,name=settings,proto3" json:"settings,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetFoldersResponse) Reset() {
-	*x = GetFoldersResponse{}
-	mi := &file_messages_proto_msgTypes[27]
+func (x *GetPop3SettingsResponse) Reset() {
+	*x = GetPop3SettingsResponse{}
+	mi := &file_messages_proto_msgTypes[126]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetFoldersResponse) String() string {
+func (x *GetPop3SettingsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetFoldersResponse) ProtoMessage() {}
+func (*GetPop3SettingsResponse) ProtoMessage() {}
 
-func (x *GetFoldersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[27]
+func (x *GetPop3SettingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[126]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1596,41 +6721,40 @@ func (x *GetFoldersResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetFoldersResponse.ProtoReflect.Descriptor instead.
-func (*GetFoldersResponse) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{27}
+// Deprecated: Use GetPop3SettingsResponse.ProtoReflect.Descriptor instead.
+func (*GetPop3SettingsResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{126}
 }
 
-func (x *GetFoldersResponse) GetFolders() []*Folder {
+func (x *GetPop3SettingsResponse) GetSettings() *Pop3Settings {
 	if x != nil {
-		return x.Folders
+		return x.Settings
 	}
 	return nil
 }
 
-type RenameFolderRequest struct {
+type PutPop3SettingsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	FolderId      string                 `protobuf:"bytes,1,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
-	NewFolderName string                 `protobuf:"bytes,2,opt,name=new_folder_name,json=newFolderName,proto3" json:"new_folder_name,omitempty"`
+	Settings      *Pop3Settings          `protobuf:"bytes,1,opt,name=settings,proto3" json:"settings,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RenameFolderRequest) Reset() {
-	*x = RenameFolderRequest{}
-	mi := &file_messages_proto_msgTypes[28]
+func (x *PutPop3SettingsRequest) Reset() {
+	*x = PutPop3SettingsRequest{}
+	mi := &file_messages_proto_msgTypes[127]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RenameFolderRequest) String() string {
+func (x *PutPop3SettingsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RenameFolderRequest) ProtoMessage() {}
+func (*PutPop3SettingsRequest) ProtoMessage() {}
 
-func (x *RenameFolderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[28]
+func (x *PutPop3SettingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[127]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1641,49 +6765,40 @@ func (x *RenameFolderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RenameFolderRequest.ProtoReflect.Descriptor instead.
-func (*RenameFolderRequest) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{28}
-}
-
-func (x *RenameFolderRequest) GetFolderId() string {
-	if x != nil {
-		return x.FolderId
-	}
-	return ""
+// Deprecated: Use PutPop3SettingsRequest.ProtoReflect.Descriptor instead.
+func (*PutPop3SettingsRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{127}
 }
 
-func (x *RenameFolderRequest) GetNewFolderName() string {
+func (x *PutPop3SettingsRequest) GetSettings() *Pop3Settings {
 	if x != nil {
-		return x.NewFolderName
+		return x.Settings
 	}
-	return ""
+	return nil
 }
 
-type RenameFolderResponse struct {
+type PutPop3SettingsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	FolderId      string                 `protobuf:"bytes,1,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
-	FolderName    string                 `protobuf:"bytes,2,opt,name=folder_name,json=folderName,proto3" json:"folder_name,omitempty"`
-	FolderType    string                 `protobuf:"bytes,3,opt,name=folder_type,json=folderType,proto3" json:"folder_type,omitempty"`
+	Settings      *Pop3Settings          `protobuf:"bytes,1,opt,name=settings,proto3" json:"settings,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RenameFolderResponse) Reset() {
-	*x = RenameFolderResponse{}
-	mi := &file_messages_proto_msgTypes[29]
+func (x *PutPop3SettingsResponse) Reset() {
+	*x = PutPop3SettingsResponse{}
+	mi := &file_messages_proto_msgTypes[128]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RenameFolderResponse) String() string {
+func (x *PutPop3SettingsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RenameFolderResponse) ProtoMessage() {}
+func (*PutPop3SettingsResponse) ProtoMessage() {}
 
-func (x *RenameFolderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[29]
+func (x *PutPop3SettingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[128]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1694,54 +6809,42 @@ func (x *RenameFolderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RenameFolderResponse.ProtoReflect.Descriptor instead.
-func (*RenameFolderResponse) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{29}
-}
-
-func (x *RenameFolderResponse) GetFolderId() string {
-	if x != nil {
-		return x.FolderId
-	}
-	return ""
-}
-
-func (x *RenameFolderResponse) GetFolderName() string {
-	if x != nil {
-		return x.FolderName
-	}
-	return ""
+// Deprecated: Use PutPop3SettingsResponse.ProtoReflect.Descriptor instead.
+func (*PutPop3SettingsResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{128}
 }
 
-func (x *RenameFolderResponse) GetFolderType() string {
+func (x *PutPop3SettingsResponse) GetSettings() *Pop3Settings {
 	if x != nil {
-		return x.FolderType
+		return x.Settings
 	}
-	return ""
+	return nil
 }
 
-type DeleteFolderRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	FolderId      string                 `protobuf:"bytes,1,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+// Подписка на события почтового ящика
+type SubscribeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Идентификатор последнего полученного события, чтобы продолжить поток после переподключения
+	LastEventId   string `protobuf:"bytes,1,opt,name=last_event_id,json=lastEventId,proto3" json:"last_event_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteFolderRequest) Reset() {
-	*x = DeleteFolderRequest{}
-	mi := &file_messages_proto_msgTypes[30]
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	mi := &file_messages_proto_msgTypes[129]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteFolderRequest) String() string {
+func (x *SubscribeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteFolderRequest) ProtoMessage() {}
+func (*SubscribeRequest) ProtoMessage() {}
 
-func (x *DeleteFolderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[30]
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[129]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1752,39 +6855,55 @@ func (x *DeleteFolderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteFolderRequest.ProtoReflect.Descriptor instead.
-func (*DeleteFolderRequest) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{30}
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{129}
 }
 
-func (x *DeleteFolderRequest) GetFolderId() string {
+func (x *SubscribeRequest) GetLastEventId() string {
 	if x != nil {
-		return x.FolderId
+		return x.LastEventId
 	}
 	return ""
 }
 
-type DeleteFolderResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+type MailEvent struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	EventId string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// new_message, message_moved, message_copied, flags_changed, folder_renamed, unread_count или resync
+	Type       string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	MessageId  string `protobuf:"bytes,3,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	FolderId   string `protobuf:"bytes,4,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	FolderName string `protobuf:"bytes,5,opt,name=folder_name,json=folderName,proto3" json:"folder_name,omitempty"`
+	From       string `protobuf:"bytes,6,opt,name=from,proto3" json:"from,omitempty"`
+	Topic      string `protobuf:"bytes,7,opt,name=topic,proto3" json:"topic,omitempty"`
+	// Измененные флаги: "true", "false" или пустая строка, если флаг не менялся
+	Read          string `protobuf:"bytes,8,opt,name=read,proto3" json:"read,omitempty"`
+	Starred       string `protobuf:"bytes,9,opt,name=starred,proto3" json:"starred,omitempty"`
+	Important     string `protobuf:"bytes,10,opt,name=important,proto3" json:"important,omitempty"`
+	Answered      string `protobuf:"bytes,11,opt,name=answered,proto3" json:"answered,omitempty"`
+	Forwarded     string `protobuf:"bytes,12,opt,name=forwarded,proto3" json:"forwarded,omitempty"`
+	Unread        string `protobuf:"bytes,13,opt,name=unread,proto3" json:"unread,omitempty"`
+	CreatedAt     string `protobuf:"bytes,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteFolderResponse) Reset() {
-	*x = DeleteFolderResponse{}
-	mi := &file_messages_proto_msgTypes[31]
+func (x *MailEvent) Reset() {
+	*x = MailEvent{}
+	mi := &file_messages_proto_msgTypes[130]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteFolderResponse) String() string {
+func (x *MailEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteFolderResponse) ProtoMessage() {}
+func (*MailEvent) ProtoMessage() {}
 
-func (x *DeleteFolderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[31]
+func (x *MailEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[130]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1795,84 +6914,131 @@ func (x *DeleteFolderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteFolderResponse.ProtoReflect.Descriptor instead.
-func (*DeleteFolderResponse) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{31}
+// Deprecated: Use MailEvent.ProtoReflect.Descriptor instead.
+func (*MailEvent) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{130}
+}
+
+func (x *MailEvent) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *MailEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *MailEvent) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *MailEvent) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+func (x *MailEvent) GetFolderName() string {
+	if x != nil {
+		return x.FolderName
+	}
+	return ""
+}
+
+func (x *MailEvent) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *MailEvent) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
 }
 
-type DeleteMessageFromFolderRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
-	FolderId      string                 `protobuf:"bytes,2,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *MailEvent) GetRead() string {
+	if x != nil {
+		return x.Read
+	}
+	return ""
 }
 
-func (x *DeleteMessageFromFolderRequest) Reset() {
-	*x = DeleteMessageFromFolderRequest{}
-	mi := &file_messages_proto_msgTypes[32]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *MailEvent) GetStarred() string {
+	if x != nil {
+		return x.Starred
+	}
+	return ""
 }
 
-func (x *DeleteMessageFromFolderRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *MailEvent) GetImportant() string {
+	if x != nil {
+		return x.Important
+	}
+	return ""
 }
 
-func (*DeleteMessageFromFolderRequest) ProtoMessage() {}
-
-func (x *DeleteMessageFromFolderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[32]
+func (x *MailEvent) GetAnswered() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Answered
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use DeleteMessageFromFolderRequest.ProtoReflect.Descriptor instead.
-func (*DeleteMessageFromFolderRequest) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{32}
+func (x *MailEvent) GetForwarded() string {
+	if x != nil {
+		return x.Forwarded
+	}
+	return ""
 }
 
-func (x *DeleteMessageFromFolderRequest) GetMessageId() string {
+func (x *MailEvent) GetUnread() string {
 	if x != nil {
-		return x.MessageId
+		return x.Unread
 	}
 	return ""
 }
 
-func (x *DeleteMessageFromFolderRequest) GetFolderId() string {
+func (x *MailEvent) GetCreatedAt() string {
 	if x != nil {
-		return x.FolderId
+		return x.CreatedAt
 	}
 	return ""
 }
 
-type DeleteMessageFromFolderResponse struct {
+// Синхронизация клиентов JMAP
+type GetMailStateRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteMessageFromFolderResponse) Reset() {
-	*x = DeleteMessageFromFolderResponse{}
-	mi := &file_messages_proto_msgTypes[33]
+func (x *GetMailStateRequest) Reset() {
+	*x = GetMailStateRequest{}
+	mi := &file_messages_proto_msgTypes[131]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteMessageFromFolderResponse) String() string {
+func (x *GetMailStateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteMessageFromFolderResponse) ProtoMessage() {}
+func (*GetMailStateRequest) ProtoMessage() {}
 
-func (x *DeleteMessageFromFolderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[33]
+func (x *GetMailStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[131]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1883,39 +7049,36 @@ func (x *DeleteMessageFromFolderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteMessageFromFolderResponse.ProtoReflect.Descriptor instead.
-func (*DeleteMessageFromFolderResponse) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{33}
+// Deprecated: Use GetMailStateRequest.ProtoReflect.Descriptor instead.
+func (*GetMailStateRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{131}
 }
 
-// Методы для черновиков
-type SaveDraftRequest struct {
+// Текущие состояния папок, писем и цепочек
+type GetMailStateResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	DraftId       string                 `protobuf:"bytes,1,opt,name=draft_id,json=draftId,proto3" json:"draft_id,omitempty"`
-	ThreadId      string                 `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
-	Topic         string                 `protobuf:"bytes,3,opt,name=topic,proto3" json:"topic,omitempty"`
-	Text          string                 `protobuf:"bytes,4,opt,name=text,proto3" json:"text,omitempty"`
-	Receivers     []*Receiver            `protobuf:"bytes,5,rep,name=receivers,proto3" json:"receivers,omitempty"`
-	Files         []*File                `protobuf:"bytes,6,rep,name=files,proto3" json:"files,omitempty"`
+	MailboxState  string                 `protobuf:"bytes,1,opt,name=mailbox_state,json=mailboxState,proto3" json:"mailbox_state,omitempty"`
+	EmailState    string                 `protobuf:"bytes,2,opt,name=email_state,json=emailState,proto3" json:"email_state,omitempty"`
+	ThreadState   string                 `protobuf:"bytes,3,opt,name=thread_state,json=threadState,proto3" json:"thread_state,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SaveDraftRequest) Reset() {
-	*x = SaveDraftRequest{}
-	mi := &file_messages_proto_msgTypes[34]
+func (x *GetMailStateResponse) Reset() {
+	*x = GetMailStateResponse{}
+	mi := &file_messages_proto_msgTypes[132]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SaveDraftRequest) String() string {
+func (x *GetMailStateResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SaveDraftRequest) ProtoMessage() {}
+func (*GetMailStateResponse) ProtoMessage() {}
 
-func (x *SaveDraftRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[34]
+func (x *GetMailStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[132]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1926,75 +7089,58 @@ func (x *SaveDraftRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SaveDraftRequest.ProtoReflect.Descriptor instead.
-func (*SaveDraftRequest) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{34}
-}
-
-func (x *SaveDraftRequest) GetDraftId() string {
-	if x != nil {
-		return x.DraftId
-	}
-	return ""
+// Deprecated: Use GetMailStateResponse.ProtoReflect.Descriptor instead.
+func (*GetMailStateResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{132}
 }
 
-func (x *SaveDraftRequest) GetThreadId() string {
+func (x *GetMailStateResponse) GetMailboxState() string {
 	if x != nil {
-		return x.ThreadId
+		return x.MailboxState
 	}
 	return ""
 }
 
-func (x *SaveDraftRequest) GetTopic() string {
+func (x *GetMailStateResponse) GetEmailState() string {
 	if x != nil {
-		return x.Topic
+		return x.EmailState
 	}
 	return ""
 }
 
-func (x *SaveDraftRequest) GetText() string {
+func (x *GetMailStateResponse) GetThreadState() string {
 	if x != nil {
-		return x.Text
+		return x.ThreadState
 	}
 	return ""
 }
 
-func (x *SaveDraftRequest) GetReceivers() []*Receiver {
-	if x != nil {
-		return x.Receivers
-	}
-	return nil
-}
-
-func (x *SaveDraftRequest) GetFiles() []*File {
-	if x != nil {
-		return x.Files
-	}
-	return nil
-}
-
-type SaveDraftResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	DraftId       string                 `protobuf:"bytes,1,opt,name=draft_id,json=draftId,proto3" json:"draft_id,omitempty"`
+type GetMailChangesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Mailbox, Email или Thread
+	Type       string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	SinceState string `protobuf:"bytes,2,opt,name=since_state,json=sinceState,proto3" json:"since_state,omitempty"`
+	// Сколько объектов вернуть за раз, 0 - значение по умолчанию
+	MaxChanges    int32 `protobuf:"varint,3,opt,name=max_changes,json=maxChanges,proto3" json:"max_changes,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SaveDraftResponse) Reset() {
-	*x = SaveDraftResponse{}
-	mi := &file_messages_proto_msgTypes[35]
+func (x *GetMailChangesRequest) Reset() {
+	*x = GetMailChangesRequest{}
+	mi := &file_messages_proto_msgTypes[133]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SaveDraftResponse) String() string {
+func (x *GetMailChangesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SaveDraftResponse) ProtoMessage() {}
+func (*GetMailChangesRequest) ProtoMessage() {}
 
-func (x *SaveDraftResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[35]
+func (x *GetMailChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[133]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2005,40 +7151,59 @@ func (x *SaveDraftResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SaveDraftResponse.ProtoReflect.Descriptor instead.
-func (*SaveDraftResponse) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{35}
+// Deprecated: Use GetMailChangesRequest.ProtoReflect.Descriptor instead.
+func (*GetMailChangesRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{133}
 }
 
-func (x *SaveDraftResponse) GetDraftId() string {
+func (x *GetMailChangesRequest) GetType() string {
 	if x != nil {
-		return x.DraftId
+		return x.Type
 	}
 	return ""
 }
 
-type DeleteDraftRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	DraftId       string                 `protobuf:"bytes,1,opt,name=draft_id,json=draftId,proto3" json:"draft_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *GetMailChangesRequest) GetSinceState() string {
+	if x != nil {
+		return x.SinceState
+	}
+	return ""
 }
 
-func (x *DeleteDraftRequest) Reset() {
-	*x = DeleteDraftRequest{}
-	mi := &file_messages_proto_msgTypes[36]
+func (x *GetMailChangesRequest) GetMaxChanges() int32 {
+	if x != nil {
+		return x.MaxChanges
+	}
+	return 0
+}
+
+type GetMailChangesResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	OldState       string                 `protobuf:"bytes,1,opt,name=old_state,json=oldState,proto3" json:"old_state,omitempty"`
+	NewState       string                 `protobuf:"bytes,2,opt,name=new_state,json=newState,proto3" json:"new_state,omitempty"`
+	HasMoreChanges bool                   `protobuf:"varint,3,opt,name=has_more_changes,json=hasMoreChanges,proto3" json:"has_more_changes,omitempty"`
+	Created        []string               `protobuf:"bytes,4,rep,name=created,proto3" json:"created,omitempty"`
+	Updated        []string               `protobuf:"bytes,5,rep,name=updated,proto3" json:"updated,omitempty"`
+	Destroyed      []string               `protobuf:"bytes,6,rep,name=destroyed,proto3" json:"destroyed,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetMailChangesResponse) Reset() {
+	*x = GetMailChangesResponse{}
+	mi := &file_messages_proto_msgTypes[134]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteDraftRequest) String() string {
+func (x *GetMailChangesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteDraftRequest) ProtoMessage() {}
+func (*GetMailChangesResponse) ProtoMessage() {}
 
-func (x *DeleteDraftRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[36]
+func (x *GetMailChangesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[134]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2049,40 +7214,77 @@ func (x *DeleteDraftRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteDraftRequest.ProtoReflect.Descriptor instead.
-func (*DeleteDraftRequest) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{36}
+// Deprecated: Use GetMailChangesResponse.ProtoReflect.Descriptor instead.
+func (*GetMailChangesResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{134}
 }
 
-func (x *DeleteDraftRequest) GetDraftId() string {
+func (x *GetMailChangesResponse) GetOldState() string {
 	if x != nil {
-		return x.DraftId
+		return x.OldState
 	}
 	return ""
 }
 
-type DeleteDraftResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+func (x *GetMailChangesResponse) GetNewState() string {
+	if x != nil {
+		return x.NewState
+	}
+	return ""
+}
+
+func (x *GetMailChangesResponse) GetHasMoreChanges() bool {
+	if x != nil {
+		return x.HasMoreChanges
+	}
+	return false
+}
+
+func (x *GetMailChangesResponse) GetCreated() []string {
+	if x != nil {
+		return x.Created
+	}
+	return nil
+}
+
+func (x *GetMailChangesResponse) GetUpdated() []string {
+	if x != nil {
+		return x.Updated
+	}
+	return nil
+}
+
+func (x *GetMailChangesResponse) GetDestroyed() []string {
+	if x != nil {
+		return x.Destroyed
+	}
+	return nil
+}
+
+type MailThread struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ThreadId string                 `protobuf:"bytes,1,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	// Письма цепочки, старые первыми
+	MessageIds    []string `protobuf:"bytes,2,rep,name=message_ids,json=messageIds,proto3" json:"message_ids,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteDraftResponse) Reset() {
-	*x = DeleteDraftResponse{}
-	mi := &file_messages_proto_msgTypes[37]
+func (x *MailThread) Reset() {
+	*x = MailThread{}
+	mi := &file_messages_proto_msgTypes[135]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteDraftResponse) String() string {
+func (x *MailThread) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteDraftResponse) ProtoMessage() {}
+func (*MailThread) ProtoMessage() {}
 
-func (x *DeleteDraftResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[37]
+func (x *MailThread) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[135]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2093,40 +7295,47 @@ func (x *DeleteDraftResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteDraftResponse.ProtoReflect.Descriptor instead.
-func (*DeleteDraftResponse) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{37}
+// Deprecated: Use MailThread.ProtoReflect.Descriptor instead.
+func (*MailThread) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{135}
 }
 
-func (x *DeleteDraftResponse) GetSuccess() bool {
+func (x *MailThread) GetThreadId() string {
 	if x != nil {
-		return x.Success
+		return x.ThreadId
 	}
-	return false
+	return ""
 }
 
-type SendDraftRequest struct {
+func (x *MailThread) GetMessageIds() []string {
+	if x != nil {
+		return x.MessageIds
+	}
+	return nil
+}
+
+type GetThreadsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	DraftId       string                 `protobuf:"bytes,1,opt,name=draft_id,json=draftId,proto3" json:"draft_id,omitempty"`
+	ThreadIds     []string               `protobuf:"bytes,1,rep,name=thread_ids,json=threadIds,proto3" json:"thread_ids,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SendDraftRequest) Reset() {
-	*x = SendDraftRequest{}
-	mi := &file_messages_proto_msgTypes[38]
+func (x *GetThreadsRequest) Reset() {
+	*x = GetThreadsRequest{}
+	mi := &file_messages_proto_msgTypes[136]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SendDraftRequest) String() string {
+func (x *GetThreadsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendDraftRequest) ProtoMessage() {}
+func (*GetThreadsRequest) ProtoMessage() {}
 
-func (x *SendDraftRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[38]
+func (x *GetThreadsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[136]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2137,41 +7346,41 @@ func (x *SendDraftRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendDraftRequest.ProtoReflect.Descriptor instead.
-func (*SendDraftRequest) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{38}
+// Deprecated: Use GetThreadsRequest.ProtoReflect.Descriptor instead.
+func (*GetThreadsRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{136}
 }
 
-func (x *SendDraftRequest) GetDraftId() string {
+func (x *GetThreadsRequest) GetThreadIds() []string {
 	if x != nil {
-		return x.DraftId
+		return x.ThreadIds
 	}
-	return ""
+	return nil
 }
 
-type SendDraftResponse struct {
+type GetThreadsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	MessageId     string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Threads       []*MailThread          `protobuf:"bytes,1,rep,name=threads,proto3" json:"threads,omitempty"`
+	NotFound      []string               `protobuf:"bytes,2,rep,name=not_found,json=notFound,proto3" json:"not_found,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SendDraftResponse) Reset() {
-	*x = SendDraftResponse{}
-	mi := &file_messages_proto_msgTypes[39]
+func (x *GetThreadsResponse) Reset() {
+	*x = GetThreadsResponse{}
+	mi := &file_messages_proto_msgTypes[137]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SendDraftResponse) String() string {
+func (x *GetThreadsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendDraftResponse) ProtoMessage() {}
+func (*GetThreadsResponse) ProtoMessage() {}
 
-func (x *SendDraftResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[39]
+func (x *GetThreadsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[137]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2182,44 +7391,86 @@ func (x *SendDraftResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendDraftResponse.ProtoReflect.Descriptor instead.
-func (*SendDraftResponse) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{39}
+// Deprecated: Use GetThreadsResponse.ProtoReflect.Descriptor instead.
+func (*GetThreadsResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{137}
 }
 
-func (x *SendDraftResponse) GetSuccess() bool {
+func (x *GetThreadsResponse) GetThreads() []*MailThread {
 	if x != nil {
-		return x.Success
+		return x.Threads
 	}
-	return false
+	return nil
 }
 
-func (x *SendDraftResponse) GetMessageId() string {
+func (x *GetThreadsResponse) GetNotFound() []string {
 	if x != nil {
-		return x.MessageId
+		return x.NotFound
 	}
-	return ""
+	return nil
 }
 
 var File_messages_proto protoreflect.FileDescriptor
 
 const file_messages_proto_rawDesc = "" +
 	"\n" +
-	"\x0emessages.proto\x12\rmessagesproto\"\xad\x01\n" +
+	"\x0emessages.proto\x12\rmessagesproto\"\xf5\x02\n" +
 	"\aMessage\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12-\n" +
 	"\x06sender\x18\x02 \x01(\v2\x15.messagesproto.SenderR\x06sender\x12\x14\n" +
 	"\x05topic\x18\x03 \x01(\tR\x05topic\x12\x18\n" +
 	"\asnippet\x18\x04 \x01(\tR\asnippet\x12\x1a\n" +
 	"\bdatetime\x18\x05 \x01(\tR\bdatetime\x12\x17\n" +
-	"\ais_read\x18\x06 \x01(\tR\x06isRead\"\xca\x01\n" +
+	"\ais_read\x18\x06 \x01(\tR\x06isRead\x12\x1d\n" +
+	"\n" +
+	"is_starred\x18\a \x01(\tR\tisStarred\x12!\n" +
+	"\fis_important\x18\b \x01(\tR\visImportant\x12\x1f\n" +
+	"\vis_answered\x18\t \x01(\tR\n" +
+	"isAnswered\x12!\n" +
+	"\fis_forwarded\x18\n" +
+	" \x01(\tR\visForwarded\x12,\n" +
+	"\x06labels\x18\v \x03(\v2\x14.messagesproto.LabelR\x06labels\x12\x12\n" +
+	"\x04size\x18\f \x01(\tR\x04size\"L\n" +
+	"\x05Label\x12\x19\n" +
+	"\blabel_id\x18\x01 \x01(\tR\alabelId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05color\x18\x03 \x01(\tR\x05color\"\xc5\x06\n" +
 	"\vFullMessage\x12\x14\n" +
 	"\x05topic\x18\x01 \x01(\tR\x05topic\x12\x12\n" +
 	"\x04text\x18\x02 \x01(\tR\x04text\x12\x1a\n" +
 	"\bdatetime\x18\x03 \x01(\tR\bdatetime\x12\x1b\n" +
 	"\tthread_id\x18\x04 \x01(\tR\bthreadId\x12-\n" +
 	"\x06sender\x18\x05 \x01(\v2\x15.messagesproto.SenderR\x06sender\x12)\n" +
-	"\x05files\x18\x06 \x03(\v2\x13.messagesproto.FileR\x05files\"R\n" +
+	"\x05files\x18\x06 \x03(\v2\x13.messagesproto.FileR\x05files\x12\x1d\n" +
+	"\n" +
+	"spam_score\x18\a \x01(\tR\tspamScore\x12!\n" +
+	"\fspam_reasons\x18\b \x03(\tR\vspamReasons\x12\x1f\n" +
+	"\vdkim_result\x18\t \x01(\tR\n" +
+	"dkimResult\x12\x1f\n" +
+	"\vdkim_domain\x18\n" +
+	" \x01(\tR\n" +
+	"dkimDomain\x12\x1d\n" +
+	"\n" +
+	"spf_result\x18\v \x01(\tR\tspfResult\x12\x1d\n" +
+	"\n" +
+	"spf_domain\x18\f \x01(\tR\tspfDomain\x12!\n" +
+	"\fdmarc_result\x18\r \x01(\tR\vdmarcResult\x12!\n" +
+	"\fdmarc_policy\x18\x0e \x01(\tR\vdmarcPolicy\x125\n" +
+	"\x16authentication_results\x18\x0f \x01(\tR\x15authenticationResults\x12+\n" +
+	"\x11unverified_sender\x18\x10 \x01(\bR\x10unverifiedSender\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x11 \x01(\tR\tmessageId\x12\x1c\n" +
+	"\treceivers\x18\x12 \x03(\tR\treceivers\x12\x1d\n" +
+	"\n" +
+	"folder_ids\x18\x13 \x03(\tR\tfolderIds\x12\x17\n" +
+	"\ais_read\x18\x14 \x01(\tR\x06isRead\x12\x1d\n" +
+	"\n" +
+	"is_starred\x18\x15 \x01(\tR\tisStarred\x12!\n" +
+	"\fis_important\x18\x16 \x01(\tR\visImportant\x12\x1f\n" +
+	"\vis_answered\x18\x17 \x01(\tR\n" +
+	"isAnswered\x12!\n" +
+	"\fis_forwarded\x18\x18 \x01(\tR\visForwarded\x12\x12\n" +
+	"\x04size\x18\x19 \x01(\tR\x04size\"R\n" +
 	"\x06Sender\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
 	"\busername\x18\x02 \x01(\tR\busername\x12\x16\n" +
@@ -2230,17 +7481,28 @@ const file_messages_proto_rawDesc = "" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1b\n" +
 	"\tfile_type\x18\x02 \x01(\tR\bfileType\x12\x12\n" +
 	"\x04size\x18\x03 \x01(\tR\x04size\x12!\n" +
-	"\fstorage_path\x18\x04 \x01(\tR\vstoragePath\"\x8a\x01\n" +
+	"\fstorage_path\x18\x04 \x01(\tR\vstoragePath\"\xcc\x01\n" +
 	"\x0ePaginationInfo\x12\x19\n" +
 	"\bhas_next\x18\x01 \x01(\tR\ahasNext\x12/\n" +
 	"\x14next_last_message_id\x18\x02 \x01(\tR\x11nextLastMessageId\x12,\n" +
-	"\x12next_last_datetime\x18\x03 \x01(\tR\x10nextLastDatetime\"g\n" +
+	"\x12next_last_datetime\x18\x03 \x01(\tR\x10nextLastDatetime\x12\x1f\n" +
+	"\vnext_cursor\x18\x04 \x01(\tR\n" +
+	"nextCursor\x12\x1f\n" +
+	"\vprev_cursor\x18\x05 \x01(\tR\n" +
+	"prevCursor\"\xd0\x01\n" +
 	"\x06Folder\x12\x1b\n" +
 	"\tfolder_id\x18\x01 \x01(\tR\bfolderId\x12\x1f\n" +
 	"\vfolder_name\x18\x02 \x01(\tR\n" +
 	"folderName\x12\x1f\n" +
 	"\vfolder_type\x18\x03 \x01(\tR\n" +
-	"folderType\"Z\n" +
+	"folderType\x12\x1b\n" +
+	"\tparent_id\x18\x04 \x01(\tR\bparentId\x12#\n" +
+	"\rmessage_total\x18\x05 \x01(\tR\fmessageTotal\x12%\n" +
+	"\x0emessage_unread\x18\x06 \x01(\tR\rmessageUnread\"r\n" +
+	"\n" +
+	"FolderNode\x12-\n" +
+	"\x06folder\x18\x01 \x01(\v2\x15.messagesproto.FolderR\x06folder\x125\n" +
+	"\bchildren\x18\x02 \x03(\v2\x19.messagesproto.FolderNodeR\bchildren\"Z\n" +
 	"\fMessagesInfo\x12#\n" +
 	"\rmessage_total\x18\x01 \x01(\tR\fmessageTotal\x12%\n" +
 	"\x0emessage_unread\x18\x02 \x01(\tR\rmessageUnread\"q\n" +
@@ -2254,10 +7516,11 @@ const file_messages_proto_rawDesc = "" +
 	"\bmessages\x18\x03 \x03(\v2\x16.messagesproto.MessageR\bmessages\x12=\n" +
 	"\n" +
 	"pagination\x18\x04 \x01(\v2\x1d.messagesproto.PaginationInfoR\n" +
-	"pagination\"3\n" +
+	"pagination\"G\n" +
 	"\x12MessagePageRequest\x12\x1d\n" +
 	"\n" +
-	"message_id\x18\x01 \x01(\tR\tmessageId\"K\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x12\n" +
+	"\x04peek\x18\x02 \x01(\bR\x04peek\"K\n" +
 	"\x13MessagePageResponse\x124\n" +
 	"\amessage\x18\x01 \x01(\v2\x1a.messagesproto.FullMessageR\amessage\"\xe3\x01\n" +
 	"\fReplyRequest\x12&\n" +
@@ -2278,6 +7541,14 @@ const file_messages_proto_rawDesc = "" +
 	"\x05files\x18\x04 \x03(\v2\x13.messagesproto.FileR\x05files\"-\n" +
 	"\fSendResponse\x12\x1d\n" +
 	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\"z\n" +
+	"\x0eForwardRequest\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x125\n" +
+	"\treceivers\x18\x02 \x03(\v2\x17.messagesproto.ReceiverR\treceivers\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text\"0\n" +
+	"\x0fForwardResponse\x12\x1d\n" +
+	"\n" +
 	"message_id\x18\x01 \x01(\tR\tmessageId\"p\n" +
 	"\vSentRequest\x12&\n" +
 	"\x0flast_message_id\x18\x01 \x01(\tR\rlastMessageId\x12#\n" +
@@ -2293,26 +7564,52 @@ const file_messages_proto_rawDesc = "" +
 	"\x11MarkAsSpamRequest\x12\x1d\n" +
 	"\n" +
 	"message_id\x18\x01 \x01(\tR\tmessageId\"\x14\n" +
-	"\x12MarkAsSpamResponse\"Q\n" +
+	"\x12MarkAsSpamResponse\"5\n" +
+	"\x14MarkAsNotSpamRequest\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\"\x17\n" +
+	"\x15MarkAsNotSpamResponse\"Q\n" +
 	"\x13MoveToFolderRequest\x12\x1d\n" +
 	"\n" +
 	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x1b\n" +
 	"\tfolder_id\x18\x02 \x01(\tR\bfolderId\"\x16\n" +
-	"\x14MoveToFolderResponse\"6\n" +
+	"\x14MoveToFolderResponse\"\xb6\x01\n" +
+	"\x0fSetFlagsRequest\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x12\n" +
+	"\x04read\x18\x02 \x01(\tR\x04read\x12\x18\n" +
+	"\astarred\x18\x03 \x01(\tR\astarred\x12\x1c\n" +
+	"\timportant\x18\x04 \x01(\tR\timportant\x12\x1a\n" +
+	"\banswered\x18\x05 \x01(\tR\banswered\x12\x1c\n" +
+	"\tforwarded\x18\x06 \x01(\tR\tforwarded\"\x12\n" +
+	"\x10SetFlagsResponse\"S\n" +
 	"\x13CreateFolderRequest\x12\x1f\n" +
 	"\vfolder_name\x18\x01 \x01(\tR\n" +
-	"folderName\"u\n" +
+	"folderName\x12\x1b\n" +
+	"\tparent_id\x18\x02 \x01(\tR\bparentId\"\x92\x01\n" +
 	"\x14CreateFolderResponse\x12\x1b\n" +
 	"\tfolder_id\x18\x01 \x01(\tR\bfolderId\x12\x1f\n" +
 	"\vfolder_name\x18\x02 \x01(\tR\n" +
 	"folderName\x12\x1f\n" +
 	"\vfolder_type\x18\x03 \x01(\tR\n" +
-	"folderType\"\x92\x01\n" +
+	"folderType\x12\x1b\n" +
+	"\tparent_id\x18\x04 \x01(\tR\bparentId\"\x8c\x03\n" +
 	"\x10GetFolderRequest\x12\x1b\n" +
 	"\tfolder_id\x18\x01 \x01(\tR\bfolderId\x12&\n" +
 	"\x0flast_message_id\x18\x02 \x01(\tR\rlastMessageId\x12#\n" +
 	"\rlast_datetime\x18\x03 \x01(\tR\flastDatetime\x12\x14\n" +
-	"\x05limit\x18\x04 \x01(\tR\x05limit\"\xd2\x01\n" +
+	"\x05limit\x18\x04 \x01(\tR\x05limit\x12\x19\n" +
+	"\blabel_id\x18\x05 \x01(\tR\alabelId\x12\x16\n" +
+	"\x06cursor\x18\x06 \x01(\tR\x06cursor\x12\x12\n" +
+	"\x04sort\x18\a \x01(\tR\x04sort\x12\x1f\n" +
+	"\vunread_only\x18\b \x01(\tR\n" +
+	"unreadOnly\x12!\n" +
+	"\fstarred_only\x18\t \x01(\tR\vstarredOnly\x12'\n" +
+	"\x0fhas_attachments\x18\n" +
+	" \x01(\tR\x0ehasAttachments\x12\x16\n" +
+	"\x06sender\x18\v \x01(\tR\x06sender\x12\x14\n" +
+	"\x05after\x18\f \x01(\tR\x05after\x12\x16\n" +
+	"\x06before\x18\r \x01(\tR\x06before\"\xd2\x01\n" +
 	"\x11GetFolderResponse\x12#\n" +
 	"\rmessage_total\x18\x01 \x01(\tR\fmessageTotal\x12%\n" +
 	"\x0emessage_unread\x18\x02 \x01(\tR\rmessageUnread\x122\n" +
@@ -2339,7 +7636,15 @@ const file_messages_proto_rawDesc = "" +
 	"\n" +
 	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x1b\n" +
 	"\tfolder_id\x18\x02 \x01(\tR\bfolderId\"!\n" +
-	"\x1fDeleteMessageFromFolderResponse\"\xd6\x01\n" +
+	"\x1fDeleteMessageFromFolderResponse\"M\n" +
+	"\x11MoveFolderRequest\x12\x1b\n" +
+	"\tfolder_id\x18\x01 \x01(\tR\bfolderId\x12\x1b\n" +
+	"\tparent_id\x18\x02 \x01(\tR\bparentId\"C\n" +
+	"\x12MoveFolderResponse\x12-\n" +
+	"\x06folder\x18\x01 \x01(\v2\x15.messagesproto.FolderR\x06folder\"\x16\n" +
+	"\x14GetFolderTreeRequest\"L\n" +
+	"\x15GetFolderTreeResponse\x123\n" +
+	"\afolders\x18\x01 \x03(\v2\x19.messagesproto.FolderNodeR\afolders\"\xd6\x01\n" +
 	"\x10SaveDraftRequest\x12\x19\n" +
 	"\bdraft_id\x18\x01 \x01(\tR\adraftId\x12\x1b\n" +
 	"\tthread_id\x18\x02 \x01(\tR\bthreadId\x12\x14\n" +
@@ -2358,27 +7663,326 @@ const file_messages_proto_rawDesc = "" +
 	"\x11SendDraftResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x1d\n" +
 	"\n" +
-	"message_id\x18\x02 \x01(\tR\tmessageId2\xbb\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\"/\n" +
+	"\x15PutSieveScriptRequest\x12\x16\n" +
+	"\x06script\x18\x01 \x01(\tR\x06script\"\x18\n" +
+	"\x16PutSieveScriptResponse\"\x17\n" +
+	"\x15GetSieveScriptRequest\"0\n" +
+	"\x16GetSieveScriptResponse\x12\x16\n" +
+	"\x06script\x18\x01 \x01(\tR\x06script\"1\n" +
+	"\x17CheckSieveScriptRequest\x12\x16\n" +
+	"\x06script\x18\x01 \x01(\tR\x06script\"b\n" +
+	"\x18CheckSieveScriptResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12\x1a\n" +
+	"\bwarnings\x18\x03 \x03(\tR\bwarnings\"8\n" +
+	"\x17RestoreFromTrashRequest\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\"7\n" +
+	"\x18RestoreFromTrashResponse\x12\x1b\n" +
+	"\tfolder_id\x18\x01 \x01(\tR\bfolderId\"\x13\n" +
+	"\x11EmptyTrashRequest\"9\n" +
+	"\x12EmptyTrashResponse\x12#\n" +
+	"\rdeleted_count\x18\x01 \x01(\x03R\fdeletedCount\"9\n" +
+	"\x18DeletePermanentlyRequest\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\"\x1b\n" +
+	"\x19DeletePermanentlyResponse\"\xc0\x01\n" +
+	"\vBatchFilter\x12\x1f\n" +
+	"\vunread_only\x18\x01 \x01(\bR\n" +
+	"unreadOnly\x12\x16\n" +
+	"\x06sender\x18\x02 \x01(\tR\x06sender\x12\x14\n" +
+	"\x05after\x18\x03 \x01(\tR\x05after\x12\x16\n" +
+	"\x06before\x18\x04 \x01(\tR\x06before\x12!\n" +
+	"\fstarred_only\x18\x05 \x01(\bR\vstarredOnly\x12'\n" +
+	"\x0fhas_attachments\x18\x06 \x01(\bR\x0ehasAttachments\"\xc2\x01\n" +
+	"\fBatchRequest\x12\x16\n" +
+	"\x06action\x18\x01 \x01(\tR\x06action\x12\x1f\n" +
+	"\vmessage_ids\x18\x02 \x03(\tR\n" +
+	"messageIds\x12\x1b\n" +
+	"\tfolder_id\x18\x03 \x01(\tR\bfolderId\x122\n" +
+	"\x06filter\x18\x04 \x01(\v2\x1a.messagesproto.BatchFilterR\x06filter\x12(\n" +
+	"\x10target_folder_id\x18\x05 \x01(\tR\x0etargetFolderId\"`\n" +
+	"\x0fBatchItemResult\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"d\n" +
+	"\rBatchResponse\x128\n" +
+	"\aresults\x18\x01 \x03(\v2\x1e.messagesproto.BatchItemResultR\aresults\x12\x19\n" +
+	"\bhas_more\x18\x02 \x01(\bR\ahasMore\">\n" +
+	"\x12CreateLabelRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05color\x18\x02 \x01(\tR\x05color\"A\n" +
+	"\x13CreateLabelResponse\x12*\n" +
+	"\x05label\x18\x01 \x01(\v2\x14.messagesproto.LabelR\x05label\"\x12\n" +
+	"\x10GetLabelsRequest\"A\n" +
+	"\x11GetLabelsResponse\x12,\n" +
+	"\x06labels\x18\x01 \x03(\v2\x14.messagesproto.LabelR\x06labels\"Y\n" +
+	"\x12UpdateLabelRequest\x12\x19\n" +
+	"\blabel_id\x18\x01 \x01(\tR\alabelId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05color\x18\x03 \x01(\tR\x05color\"A\n" +
+	"\x13UpdateLabelResponse\x12*\n" +
+	"\x05label\x18\x01 \x01(\v2\x14.messagesproto.LabelR\x05label\"/\n" +
+	"\x12DeleteLabelRequest\x12\x19\n" +
+	"\blabel_id\x18\x01 \x01(\tR\alabelId\"\x15\n" +
+	"\x13DeleteLabelResponse\"O\n" +
+	"\x11ApplyLabelRequest\x12\x19\n" +
+	"\blabel_id\x18\x01 \x01(\tR\alabelId\x12\x1f\n" +
+	"\vmessage_ids\x18\x02 \x03(\tR\n" +
+	"messageIds\"9\n" +
+	"\x12ApplyLabelResponse\x12#\n" +
+	"\rapplied_count\x18\x01 \x01(\x03R\fappliedCount\"P\n" +
+	"\x12RemoveLabelRequest\x12\x19\n" +
+	"\blabel_id\x18\x01 \x01(\tR\alabelId\x12\x1f\n" +
+	"\vmessage_ids\x18\x02 \x03(\tR\n" +
+	"messageIds\":\n" +
+	"\x13RemoveLabelResponse\x12#\n" +
+	"\rremoved_count\x18\x01 \x01(\x03R\fremovedCount\"\xb5\x01\n" +
+	"\x0eForwardingRule\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x1c\n" +
+	"\taddresses\x18\x02 \x03(\tR\taddresses\x12\x1b\n" +
+	"\tkeep_copy\x18\x03 \x01(\bR\bkeepCopy\x12#\n" +
+	"\rfrom_contains\x18\x04 \x01(\tR\ffromContains\x12)\n" +
+	"\x10subject_contains\x18\x05 \x01(\tR\x0fsubjectContains\"\x16\n" +
+	"\x14GetForwardingRequest\"J\n" +
+	"\x15GetForwardingResponse\x121\n" +
+	"\x04rule\x18\x01 \x01(\v2\x1d.messagesproto.ForwardingRuleR\x04rule\"I\n" +
+	"\x14PutForwardingRequest\x121\n" +
+	"\x04rule\x18\x01 \x01(\v2\x1d.messagesproto.ForwardingRuleR\x04rule\"J\n" +
+	"\x15PutForwardingResponse\x121\n" +
+	"\x04rule\x18\x01 \x01(\v2\x1d.messagesproto.ForwardingRuleR\x04rule\"\xd4\x01\n" +
+	"\aContact\x12\x1d\n" +
 	"\n" +
+	"contact_id\x18\x01 \x01(\tR\tcontactId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06emails\x18\x03 \x03(\tR\x06emails\x12\x14\n" +
+	"\x05phone\x18\x04 \x01(\tR\x05phone\x12\x14\n" +
+	"\x05notes\x18\x05 \x01(\tR\x05notes\x12\x16\n" +
+	"\x06avatar\x18\x06 \x01(\tR\x06avatar\x12\x1b\n" +
+	"\tgroup_ids\x18\a \x03(\tR\bgroupIds\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\tR\tcreatedAt\"b\n" +
+	"\fContactGroup\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\tR\agroupId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12#\n" +
+	"\rcontact_count\x18\x03 \x01(\x03R\fcontactCount\"\\\n" +
+	"\x11ContactSuggestion\x12\x1d\n" +
+	"\n" +
+	"contact_id\x18\x01 \x01(\tR\tcontactId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\"H\n" +
+	"\x14CreateContactRequest\x120\n" +
+	"\acontact\x18\x01 \x01(\v2\x16.messagesproto.ContactR\acontact\"I\n" +
+	"\x15CreateContactResponse\x120\n" +
+	"\acontact\x18\x01 \x01(\v2\x16.messagesproto.ContactR\acontact\"/\n" +
+	"\x12GetContactsRequest\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\tR\agroupId\"I\n" +
+	"\x13GetContactsResponse\x122\n" +
+	"\bcontacts\x18\x01 \x03(\v2\x16.messagesproto.ContactR\bcontacts\"H\n" +
+	"\x14UpdateContactRequest\x120\n" +
+	"\acontact\x18\x01 \x01(\v2\x16.messagesproto.ContactR\acontact\"I\n" +
+	"\x15UpdateContactResponse\x120\n" +
+	"\acontact\x18\x01 \x01(\v2\x16.messagesproto.ContactR\acontact\"5\n" +
+	"\x14DeleteContactRequest\x12\x1d\n" +
+	"\n" +
+	"contact_id\x18\x01 \x01(\tR\tcontactId\"\x17\n" +
+	"\x15DeleteContactResponse\"F\n" +
+	"\x16SuggestContactsRequest\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"]\n" +
+	"\x17SuggestContactsResponse\x12B\n" +
+	"\vsuggestions\x18\x01 \x03(\v2 .messagesproto.ContactSuggestionR\vsuggestions\"/\n" +
+	"\x19CreateContactGroupRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"O\n" +
+	"\x1aCreateContactGroupResponse\x121\n" +
+	"\x05group\x18\x01 \x01(\v2\x1b.messagesproto.ContactGroupR\x05group\"\x19\n" +
+	"\x17GetContactGroupsRequest\"O\n" +
+	"\x18GetContactGroupsResponse\x123\n" +
+	"\x06groups\x18\x01 \x03(\v2\x1b.messagesproto.ContactGroupR\x06groups\"J\n" +
+	"\x19RenameContactGroupRequest\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\tR\agroupId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"O\n" +
+	"\x1aRenameContactGroupResponse\x121\n" +
+	"\x05group\x18\x01 \x01(\v2\x1b.messagesproto.ContactGroupR\x05group\"6\n" +
+	"\x19DeleteContactGroupRequest\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\tR\agroupId\"\x1c\n" +
+	"\x1aDeleteContactGroupResponse\"r\n" +
+	"\n" +
+	"SenderRule\x12\x17\n" +
+	"\arule_id\x18\x01 \x01(\tR\x06ruleId\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x18\n" +
+	"\apattern\x18\x03 \x01(\tR\apattern\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\"\x17\n" +
+	"\x15GetSenderListsRequest\"\xa9\x01\n" +
+	"\x16GetSenderListsResponse\x123\n" +
+	"\ablocked\x18\x01 \x03(\v2\x19.messagesproto.SenderRuleR\ablocked\x123\n" +
+	"\aallowed\x18\x02 \x03(\v2\x19.messagesproto.SenderRuleR\aallowed\x12%\n" +
+	"\x0eblocked_action\x18\x03 \x01(\tR\rblockedAction\"D\n" +
+	"\x14AddSenderRuleRequest\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x18\n" +
+	"\apattern\x18\x02 \x01(\tR\apattern\"F\n" +
+	"\x15AddSenderRuleResponse\x12-\n" +
+	"\x04rule\x18\x01 \x01(\v2\x19.messagesproto.SenderRuleR\x04rule\"2\n" +
+	"\x17DeleteSenderRuleRequest\x12\x17\n" +
+	"\arule_id\x18\x01 \x01(\tR\x06ruleId\"\x1a\n" +
+	"\x18DeleteSenderRuleResponse\"7\n" +
+	"\x1dSetBlockedSenderActionRequest\x12\x16\n" +
+	"\x06action\x18\x01 \x01(\tR\x06action\" \n" +
+	"\x1eSetBlockedSenderActionResponse\"V\n" +
+	"\x12BlockSenderRequest\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12!\n" +
+	"\fwhole_domain\x18\x02 \x01(\bR\vwholeDomain\"D\n" +
+	"\x13BlockSenderResponse\x12-\n" +
+	"\x04rule\x18\x01 \x01(\v2\x19.messagesproto.SenderRuleR\x04rule\"[\n" +
+	"\x05Alias\x12\x19\n" +
+	"\balias_id\x18\x01 \x01(\tR\aaliasId\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\tR\tcreatedAt\"\x13\n" +
+	"\x11GetAliasesRequest\"D\n" +
+	"\x12GetAliasesResponse\x12.\n" +
+	"\aaliases\x18\x01 \x03(\v2\x14.messagesproto.AliasR\aaliases\"-\n" +
+	"\x0fAddAliasRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\">\n" +
+	"\x10AddAliasResponse\x12*\n" +
+	"\x05alias\x18\x01 \x01(\v2\x14.messagesproto.AliasR\x05alias\"/\n" +
+	"\x12DeleteAliasRequest\x12\x19\n" +
+	"\balias_id\x18\x01 \x01(\tR\aaliasId\"\x15\n" +
+	"\x13DeleteAliasResponse\"w\n" +
+	"\fPop3Settings\x12#\n" +
+	"\rdelete_action\x18\x01 \x01(\tR\fdeleteAction\x12!\n" +
+	"\fapop_enabled\x18\x02 \x01(\bR\vapopEnabled\x12\x1f\n" +
+	"\vapop_secret\x18\x03 \x01(\tR\n" +
+	"apopSecret\"\x18\n" +
+	"\x16GetPop3SettingsRequest\"R\n" +
+	"\x17GetPop3SettingsResponse\x127\n" +
+	"\bsettings\x18\x01 \x01(\v2\x1b.messagesproto.Pop3SettingsR\bsettings\"Q\n" +
+	"\x16PutPop3SettingsRequest\x127\n" +
+	"\bsettings\x18\x01 \x01(\v2\x1b.messagesproto.Pop3SettingsR\bsettings\"R\n" +
+	"\x17PutPop3SettingsResponse\x127\n" +
+	"\bsettings\x18\x01 \x01(\v2\x1b.messagesproto.Pop3SettingsR\bsettings\"6\n" +
+	"\x10SubscribeRequest\x12\"\n" +
+	"\rlast_event_id\x18\x01 \x01(\tR\vlastEventId\"\xfe\x02\n" +
+	"\tMailEvent\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x03 \x01(\tR\tmessageId\x12\x1b\n" +
+	"\tfolder_id\x18\x04 \x01(\tR\bfolderId\x12\x1f\n" +
+	"\vfolder_name\x18\x05 \x01(\tR\n" +
+	"folderName\x12\x12\n" +
+	"\x04from\x18\x06 \x01(\tR\x04from\x12\x14\n" +
+	"\x05topic\x18\a \x01(\tR\x05topic\x12\x12\n" +
+	"\x04read\x18\b \x01(\tR\x04read\x12\x18\n" +
+	"\astarred\x18\t \x01(\tR\astarred\x12\x1c\n" +
+	"\timportant\x18\n" +
+	" \x01(\tR\timportant\x12\x1a\n" +
+	"\banswered\x18\v \x01(\tR\banswered\x12\x1c\n" +
+	"\tforwarded\x18\f \x01(\tR\tforwarded\x12\x16\n" +
+	"\x06unread\x18\r \x01(\tR\x06unread\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x0e \x01(\tR\tcreatedAt\"\x15\n" +
+	"\x13GetMailStateRequest\"\x7f\n" +
+	"\x14GetMailStateResponse\x12#\n" +
+	"\rmailbox_state\x18\x01 \x01(\tR\fmailboxState\x12\x1f\n" +
+	"\vemail_state\x18\x02 \x01(\tR\n" +
+	"emailState\x12!\n" +
+	"\fthread_state\x18\x03 \x01(\tR\vthreadState\"m\n" +
+	"\x15GetMailChangesRequest\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x1f\n" +
+	"\vsince_state\x18\x02 \x01(\tR\n" +
+	"sinceState\x12\x1f\n" +
+	"\vmax_changes\x18\x03 \x01(\x05R\n" +
+	"maxChanges\"\xce\x01\n" +
+	"\x16GetMailChangesResponse\x12\x1b\n" +
+	"\told_state\x18\x01 \x01(\tR\boldState\x12\x1b\n" +
+	"\tnew_state\x18\x02 \x01(\tR\bnewState\x12(\n" +
+	"\x10has_more_changes\x18\x03 \x01(\bR\x0ehasMoreChanges\x12\x18\n" +
+	"\acreated\x18\x04 \x03(\tR\acreated\x12\x18\n" +
+	"\aupdated\x18\x05 \x03(\tR\aupdated\x12\x1c\n" +
+	"\tdestroyed\x18\x06 \x03(\tR\tdestroyed\"J\n" +
+	"\n" +
+	"MailThread\x12\x1b\n" +
+	"\tthread_id\x18\x01 \x01(\tR\bthreadId\x12\x1f\n" +
+	"\vmessage_ids\x18\x02 \x03(\tR\n" +
+	"messageIds\"2\n" +
+	"\x11GetThreadsRequest\x12\x1d\n" +
+	"\n" +
+	"thread_ids\x18\x01 \x03(\tR\tthreadIds\"f\n" +
+	"\x12GetThreadsResponse\x123\n" +
+	"\athreads\x18\x01 \x03(\v2\x19.messagesproto.MailThreadR\athreads\x12\x1b\n" +
+	"\tnot_found\x18\x02 \x03(\tR\bnotFound2\x85)\n" +
 	"\x0fMessagesService\x12B\n" +
 	"\x05Inbox\x12\x1b.messagesproto.InboxRequest\x1a\x1c.messagesproto.InboxResponse\x12T\n" +
 	"\vMessagePage\x12!.messagesproto.MessagePageRequest\x1a\".messagesproto.MessagePageResponse\x12B\n" +
 	"\x05Reply\x12\x1b.messagesproto.ReplyRequest\x1a\x1c.messagesproto.ReplyResponse\x12?\n" +
-	"\x04Send\x12\x1a.messagesproto.SendRequest\x1a\x1b.messagesproto.SendResponse\x12?\n" +
+	"\x04Send\x12\x1a.messagesproto.SendRequest\x1a\x1b.messagesproto.SendResponse\x12H\n" +
+	"\aForward\x12\x1d.messagesproto.ForwardRequest\x1a\x1e.messagesproto.ForwardResponse\x12?\n" +
 	"\x04Sent\x12\x1a.messagesproto.SentRequest\x1a\x1b.messagesproto.SentResponse\x12Q\n" +
 	"\n" +
-	"MarkAsSpam\x12 .messagesproto.MarkAsSpamRequest\x1a!.messagesproto.MarkAsSpamResponse\x12W\n" +
-	"\fMoveToFolder\x12\".messagesproto.MoveToFolderRequest\x1a#.messagesproto.MoveToFolderResponse\x12W\n" +
+	"MarkAsSpam\x12 .messagesproto.MarkAsSpamRequest\x1a!.messagesproto.MarkAsSpamResponse\x12Z\n" +
+	"\rMarkAsNotSpam\x12#.messagesproto.MarkAsNotSpamRequest\x1a$.messagesproto.MarkAsNotSpamResponse\x12W\n" +
+	"\fMoveToFolder\x12\".messagesproto.MoveToFolderRequest\x1a#.messagesproto.MoveToFolderResponse\x12K\n" +
+	"\bSetFlags\x12\x1e.messagesproto.SetFlagsRequest\x1a\x1f.messagesproto.SetFlagsResponse\x12W\n" +
 	"\fCreateFolder\x12\".messagesproto.CreateFolderRequest\x1a#.messagesproto.CreateFolderResponse\x12N\n" +
 	"\tGetFolder\x12\x1f.messagesproto.GetFolderRequest\x1a .messagesproto.GetFolderResponse\x12Q\n" +
 	"\n" +
 	"GetFolders\x12 .messagesproto.GetFoldersRequest\x1a!.messagesproto.GetFoldersResponse\x12W\n" +
 	"\fRenameFolder\x12\".messagesproto.RenameFolderRequest\x1a#.messagesproto.RenameFolderResponse\x12W\n" +
 	"\fDeleteFolder\x12\".messagesproto.DeleteFolderRequest\x1a#.messagesproto.DeleteFolderResponse\x12x\n" +
-	"\x17DeleteMessageFromFolder\x12-.messagesproto.DeleteMessageFromFolderRequest\x1a..messagesproto.DeleteMessageFromFolderResponse\x12N\n" +
+	"\x17DeleteMessageFromFolder\x12-.messagesproto.DeleteMessageFromFolderRequest\x1a..messagesproto.DeleteMessageFromFolderResponse\x12Q\n" +
+	"\n" +
+	"MoveFolder\x12 .messagesproto.MoveFolderRequest\x1a!.messagesproto.MoveFolderResponse\x12Z\n" +
+	"\rGetFolderTree\x12#.messagesproto.GetFolderTreeRequest\x1a$.messagesproto.GetFolderTreeResponse\x12N\n" +
 	"\tSaveDraft\x12\x1f.messagesproto.SaveDraftRequest\x1a .messagesproto.SaveDraftResponse\x12T\n" +
 	"\vDeleteDraft\x12!.messagesproto.DeleteDraftRequest\x1a\".messagesproto.DeleteDraftResponse\x12N\n" +
-	"\tSendDraft\x12\x1f.messagesproto.SendDraftRequest\x1a .messagesproto.SendDraftResponseB\x11Z\x0f/;messagesprotob\x06proto3"
+	"\tSendDraft\x12\x1f.messagesproto.SendDraftRequest\x1a .messagesproto.SendDraftResponse\x12]\n" +
+	"\x0ePutSieveScript\x12$.messagesproto.PutSieveScriptRequest\x1a%.messagesproto.PutSieveScriptResponse\x12]\n" +
+	"\x0eGetSieveScript\x12$.messagesproto.GetSieveScriptRequest\x1a%.messagesproto.GetSieveScriptResponse\x12c\n" +
+	"\x10CheckSieveScript\x12&.messagesproto.CheckSieveScriptRequest\x1a'.messagesproto.CheckSieveScriptResponse\x12c\n" +
+	"\x10RestoreFromTrash\x12&.messagesproto.RestoreFromTrashRequest\x1a'.messagesproto.RestoreFromTrashResponse\x12Q\n" +
+	"\n" +
+	"EmptyTrash\x12 .messagesproto.EmptyTrashRequest\x1a!.messagesproto.EmptyTrashResponse\x12f\n" +
+	"\x11DeletePermanently\x12'.messagesproto.DeletePermanentlyRequest\x1a(.messagesproto.DeletePermanentlyResponse\x12B\n" +
+	"\x05Batch\x12\x1b.messagesproto.BatchRequest\x1a\x1c.messagesproto.BatchResponse\x12T\n" +
+	"\vCreateLabel\x12!.messagesproto.CreateLabelRequest\x1a\".messagesproto.CreateLabelResponse\x12N\n" +
+	"\tGetLabels\x12\x1f.messagesproto.GetLabelsRequest\x1a .messagesproto.GetLabelsResponse\x12T\n" +
+	"\vUpdateLabel\x12!.messagesproto.UpdateLabelRequest\x1a\".messagesproto.UpdateLabelResponse\x12T\n" +
+	"\vDeleteLabel\x12!.messagesproto.DeleteLabelRequest\x1a\".messagesproto.DeleteLabelResponse\x12Q\n" +
+	"\n" +
+	"ApplyLabel\x12 .messagesproto.ApplyLabelRequest\x1a!.messagesproto.ApplyLabelResponse\x12T\n" +
+	"\vRemoveLabel\x12!.messagesproto.RemoveLabelRequest\x1a\".messagesproto.RemoveLabelResponse\x12Z\n" +
+	"\rGetForwarding\x12#.messagesproto.GetForwardingRequest\x1a$.messagesproto.GetForwardingResponse\x12Z\n" +
+	"\rPutForwarding\x12#.messagesproto.PutForwardingRequest\x1a$.messagesproto.PutForwardingResponse\x12Z\n" +
+	"\rCreateContact\x12#.messagesproto.CreateContactRequest\x1a$.messagesproto.CreateContactResponse\x12T\n" +
+	"\vGetContacts\x12!.messagesproto.GetContactsRequest\x1a\".messagesproto.GetContactsResponse\x12Z\n" +
+	"\rUpdateContact\x12#.messagesproto.UpdateContactRequest\x1a$.messagesproto.UpdateContactResponse\x12Z\n" +
+	"\rDeleteContact\x12#.messagesproto.DeleteContactRequest\x1a$.messagesproto.DeleteContactResponse\x12`\n" +
+	"\x0fSuggestContacts\x12%.messagesproto.SuggestContactsRequest\x1a&.messagesproto.SuggestContactsResponse\x12i\n" +
+	"\x12CreateContactGroup\x12(.messagesproto.CreateContactGroupRequest\x1a).messagesproto.CreateContactGroupResponse\x12c\n" +
+	"\x10GetContactGroups\x12&.messagesproto.GetContactGroupsRequest\x1a'.messagesproto.GetContactGroupsResponse\x12i\n" +
+	"\x12RenameContactGroup\x12(.messagesproto.RenameContactGroupRequest\x1a).messagesproto.RenameContactGroupResponse\x12i\n" +
+	"\x12DeleteContactGroup\x12(.messagesproto.DeleteContactGroupRequest\x1a).messagesproto.DeleteContactGroupResponse\x12]\n" +
+	"\x0eGetSenderLists\x12$.messagesproto.GetSenderListsRequest\x1a%.messagesproto.GetSenderListsResponse\x12Z\n" +
+	"\rAddSenderRule\x12#.messagesproto.AddSenderRuleRequest\x1a$.messagesproto.AddSenderRuleResponse\x12c\n" +
+	"\x10DeleteSenderRule\x12&.messagesproto.DeleteSenderRuleRequest\x1a'.messagesproto.DeleteSenderRuleResponse\x12u\n" +
+	"\x16SetBlockedSenderAction\x12,.messagesproto.SetBlockedSenderActionRequest\x1a-.messagesproto.SetBlockedSenderActionResponse\x12T\n" +
+	"\vBlockSender\x12!.messagesproto.BlockSenderRequest\x1a\".messagesproto.BlockSenderResponse\x12Q\n" +
+	"\n" +
+	"GetAliases\x12 .messagesproto.GetAliasesRequest\x1a!.messagesproto.GetAliasesResponse\x12K\n" +
+	"\bAddAlias\x12\x1e.messagesproto.AddAliasRequest\x1a\x1f.messagesproto.AddAliasResponse\x12T\n" +
+	"\vDeleteAlias\x12!.messagesproto.DeleteAliasRequest\x1a\".messagesproto.DeleteAliasResponse\x12`\n" +
+	"\x0fGetPop3Settings\x12%.messagesproto.GetPop3SettingsRequest\x1a&.messagesproto.GetPop3SettingsResponse\x12`\n" +
+	"\x0fPutPop3Settings\x12%.messagesproto.PutPop3SettingsRequest\x1a&.messagesproto.PutPop3SettingsResponse\x12H\n" +
+	"\tSubscribe\x12\x1f.messagesproto.SubscribeRequest\x1a\x18.messagesproto.MailEvent0\x01\x12W\n" +
+	"\fGetMailState\x12\".messagesproto.GetMailStateRequest\x1a#.messagesproto.GetMailStateResponse\x12]\n" +
+	"\x0eGetMailChanges\x12$.messagesproto.GetMailChangesRequest\x1a%.messagesproto.GetMailChangesResponse\x12Q\n" +
+	"\n" +
+	"GetThreads\x12 .messagesproto.GetThreadsRequest\x1a!.messagesproto.GetThreadsResponseB\x11Z\x0f/;messagesprotob\x06proto3"
 
 var (
 	file_messages_proto_rawDescOnce sync.Once
@@ -2392,104 +7996,321 @@ func file_messages_proto_rawDescGZIP() []byte {
 	return file_messages_proto_rawDescData
 }
 
-var file_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 40)
+var file_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 138)
 var file_messages_proto_goTypes = []any{
 	(*Message)(nil),                         // 0: messagesproto.Message
-	(*FullMessage)(nil),                     // 1: messagesproto.FullMessage
-	(*Sender)(nil),                          // 2: messagesproto.Sender
-	(*Receiver)(nil),                        // 3: messagesproto.Receiver
-	(*File)(nil),                            // 4: messagesproto.File
-	(*PaginationInfo)(nil),                  // 5: messagesproto.PaginationInfo
-	(*Folder)(nil),                          // 6: messagesproto.Folder
-	(*MessagesInfo)(nil),                    // 7: messagesproto.MessagesInfo
-	(*InboxRequest)(nil),                    // 8: messagesproto.InboxRequest
-	(*InboxResponse)(nil),                   // 9: messagesproto.InboxResponse
-	(*MessagePageRequest)(nil),              // 10: messagesproto.MessagePageRequest
-	(*MessagePageResponse)(nil),             // 11: messagesproto.MessagePageResponse
-	(*ReplyRequest)(nil),                    // 12: messagesproto.ReplyRequest
-	(*ReplyResponse)(nil),                   // 13: messagesproto.ReplyResponse
-	(*SendRequest)(nil),                     // 14: messagesproto.SendRequest
-	(*SendResponse)(nil),                    // 15: messagesproto.SendResponse
-	(*SentRequest)(nil),                     // 16: messagesproto.SentRequest
-	(*SentResponse)(nil),                    // 17: messagesproto.SentResponse
-	(*MarkAsSpamRequest)(nil),               // 18: messagesproto.MarkAsSpamRequest
-	(*MarkAsSpamResponse)(nil),              // 19: messagesproto.MarkAsSpamResponse
-	(*MoveToFolderRequest)(nil),             // 20: messagesproto.MoveToFolderRequest
-	(*MoveToFolderResponse)(nil),            // 21: messagesproto.MoveToFolderResponse
-	(*CreateFolderRequest)(nil),             // 22: messagesproto.CreateFolderRequest
-	(*CreateFolderResponse)(nil),            // 23: messagesproto.CreateFolderResponse
-	(*GetFolderRequest)(nil),                // 24: messagesproto.GetFolderRequest
-	(*GetFolderResponse)(nil),               // 25: messagesproto.GetFolderResponse
-	(*GetFoldersRequest)(nil),               // 26: messagesproto.GetFoldersRequest
-	(*GetFoldersResponse)(nil),              // 27: messagesproto.GetFoldersResponse
-	(*RenameFolderRequest)(nil),             // 28: messagesproto.RenameFolderRequest
-	(*RenameFolderResponse)(nil),            // 29: messagesproto.RenameFolderResponse
-	(*DeleteFolderRequest)(nil),             // 30: messagesproto.DeleteFolderRequest
-	(*DeleteFolderResponse)(nil),            // 31: messagesproto.DeleteFolderResponse
-	(*DeleteMessageFromFolderRequest)(nil),  // 32: messagesproto.DeleteMessageFromFolderRequest
-	(*DeleteMessageFromFolderResponse)(nil), // 33: messagesproto.DeleteMessageFromFolderResponse
-	(*SaveDraftRequest)(nil),                // 34: messagesproto.SaveDraftRequest
-	(*SaveDraftResponse)(nil),               // 35: messagesproto.SaveDraftResponse
-	(*DeleteDraftRequest)(nil),              // 36: messagesproto.DeleteDraftRequest
-	(*DeleteDraftResponse)(nil),             // 37: messagesproto.DeleteDraftResponse
-	(*SendDraftRequest)(nil),                // 38: messagesproto.SendDraftRequest
-	(*SendDraftResponse)(nil),               // 39: messagesproto.SendDraftResponse
+	(*Label)(nil),                           // 1: messagesproto.Label
+	(*FullMessage)(nil),                     // 2: messagesproto.FullMessage
+	(*Sender)(nil),                          // 3: messagesproto.Sender
+	(*Receiver)(nil),                        // 4: messagesproto.Receiver
+	(*File)(nil),                            // 5: messagesproto.File
+	(*PaginationInfo)(nil),                  // 6: messagesproto.PaginationInfo
+	(*Folder)(nil),                          // 7: messagesproto.Folder
+	(*FolderNode)(nil),                      // 8: messagesproto.FolderNode
+	(*MessagesInfo)(nil),                    // 9: messagesproto.MessagesInfo
+	(*InboxRequest)(nil),                    // 10: messagesproto.InboxRequest
+	(*InboxResponse)(nil),                   // 11: messagesproto.InboxResponse
+	(*MessagePageRequest)(nil),              // 12: messagesproto.MessagePageRequest
+	(*MessagePageResponse)(nil),             // 13: messagesproto.MessagePageResponse
+	(*ReplyRequest)(nil),                    // 14: messagesproto.ReplyRequest
+	(*ReplyResponse)(nil),                   // 15: messagesproto.ReplyResponse
+	(*SendRequest)(nil),                     // 16: messagesproto.SendRequest
+	(*SendResponse)(nil),                    // 17: messagesproto.SendResponse
+	(*ForwardRequest)(nil),                  // 18: messagesproto.ForwardRequest
+	(*ForwardResponse)(nil),                 // 19: messagesproto.ForwardResponse
+	(*SentRequest)(nil),                     // 20: messagesproto.SentRequest
+	(*SentResponse)(nil),                    // 21: messagesproto.SentResponse
+	(*MarkAsSpamRequest)(nil),               // 22: messagesproto.MarkAsSpamRequest
+	(*MarkAsSpamResponse)(nil),              // 23: messagesproto.MarkAsSpamResponse
+	(*MarkAsNotSpamRequest)(nil),            // 24: messagesproto.MarkAsNotSpamRequest
+	(*MarkAsNotSpamResponse)(nil),           // 25: messagesproto.MarkAsNotSpamResponse
+	(*MoveToFolderRequest)(nil),             // 26: messagesproto.MoveToFolderRequest
+	(*MoveToFolderResponse)(nil),            // 27: messagesproto.MoveToFolderResponse
+	(*SetFlagsRequest)(nil),                 // 28: messagesproto.SetFlagsRequest
+	(*SetFlagsResponse)(nil),                // 29: messagesproto.SetFlagsResponse
+	(*CreateFolderRequest)(nil),             // 30: messagesproto.CreateFolderRequest
+	(*CreateFolderResponse)(nil),            // 31: messagesproto.CreateFolderResponse
+	(*GetFolderRequest)(nil),                // 32: messagesproto.GetFolderRequest
+	(*GetFolderResponse)(nil),               // 33: messagesproto.GetFolderResponse
+	(*GetFoldersRequest)(nil),               // 34: messagesproto.GetFoldersRequest
+	(*GetFoldersResponse)(nil),              // 35: messagesproto.GetFoldersResponse
+	(*RenameFolderRequest)(nil),             // 36: messagesproto.RenameFolderRequest
+	(*RenameFolderResponse)(nil),            // 37: messagesproto.RenameFolderResponse
+	(*DeleteFolderRequest)(nil),             // 38: messagesproto.DeleteFolderRequest
+	(*DeleteFolderResponse)(nil),            // 39: messagesproto.DeleteFolderResponse
+	(*DeleteMessageFromFolderRequest)(nil),  // 40: messagesproto.DeleteMessageFromFolderRequest
+	(*DeleteMessageFromFolderResponse)(nil), // 41: messagesproto.DeleteMessageFromFolderResponse
+	(*MoveFolderRequest)(nil),               // 42: messagesproto.MoveFolderRequest
+	(*MoveFolderResponse)(nil),              // 43: messagesproto.MoveFolderResponse
+	(*GetFolderTreeRequest)(nil),            // 44: messagesproto.GetFolderTreeRequest
+	(*GetFolderTreeResponse)(nil),           // 45: messagesproto.GetFolderTreeResponse
+	(*SaveDraftRequest)(nil),                // 46: messagesproto.SaveDraftRequest
+	(*SaveDraftResponse)(nil),               // 47: messagesproto.SaveDraftResponse
+	(*DeleteDraftRequest)(nil),              // 48: messagesproto.DeleteDraftRequest
+	(*DeleteDraftResponse)(nil),             // 49: messagesproto.DeleteDraftResponse
+	(*SendDraftRequest)(nil),                // 50: messagesproto.SendDraftRequest
+	(*SendDraftResponse)(nil),               // 51: messagesproto.SendDraftResponse
+	(*PutSieveScriptRequest)(nil),           // 52: messagesproto.PutSieveScriptRequest
+	(*PutSieveScriptResponse)(nil),          // 53: messagesproto.PutSieveScriptResponse
+	(*GetSieveScriptRequest)(nil),           // 54: messagesproto.GetSieveScriptRequest
+	(*GetSieveScriptResponse)(nil),          // 55: messagesproto.GetSieveScriptResponse
+	(*CheckSieveScriptRequest)(nil),         // 56: messagesproto.CheckSieveScriptRequest
+	(*CheckSieveScriptResponse)(nil),        // 57: messagesproto.CheckSieveScriptResponse
+	(*RestoreFromTrashRequest)(nil),         // 58: messagesproto.RestoreFromTrashRequest
+	(*RestoreFromTrashResponse)(nil),        // 59: messagesproto.RestoreFromTrashResponse
+	(*EmptyTrashRequest)(nil),               // 60: messagesproto.EmptyTrashRequest
+	(*EmptyTrashResponse)(nil),              // 61: messagesproto.EmptyTrashResponse
+	(*DeletePermanentlyRequest)(nil),        // 62: messagesproto.DeletePermanentlyRequest
+	(*DeletePermanentlyResponse)(nil),       // 63: messagesproto.DeletePermanentlyResponse
+	(*BatchFilter)(nil),                     // 64: messagesproto.BatchFilter
+	(*BatchRequest)(nil),                    // 65: messagesproto.BatchRequest
+	(*BatchItemResult)(nil),                 // 66: messagesproto.BatchItemResult
+	(*BatchResponse)(nil),                   // 67: messagesproto.BatchResponse
+	(*CreateLabelRequest)(nil),              // 68: messagesproto.CreateLabelRequest
+	(*CreateLabelResponse)(nil),             // 69: messagesproto.CreateLabelResponse
+	(*GetLabelsRequest)(nil),                // 70: messagesproto.GetLabelsRequest
+	(*GetLabelsResponse)(nil),               // 71: messagesproto.GetLabelsResponse
+	(*UpdateLabelRequest)(nil),              // 72: messagesproto.UpdateLabelRequest
+	(*UpdateLabelResponse)(nil),             // 73: messagesproto.UpdateLabelResponse
+	(*DeleteLabelRequest)(nil),              // 74: messagesproto.DeleteLabelRequest
+	(*DeleteLabelResponse)(nil),             // 75: messagesproto.DeleteLabelResponse
+	(*ApplyLabelRequest)(nil),               // 76: messagesproto.ApplyLabelRequest
+	(*ApplyLabelResponse)(nil),              // 77: messagesproto.ApplyLabelResponse
+	(*RemoveLabelRequest)(nil),              // 78: messagesproto.RemoveLabelRequest
+	(*RemoveLabelResponse)(nil),             // 79: messagesproto.RemoveLabelResponse
+	(*ForwardingRule)(nil),                  // 80: messagesproto.ForwardingRule
+	(*GetForwardingRequest)(nil),            // 81: messagesproto.GetForwardingRequest
+	(*GetForwardingResponse)(nil),           // 82: messagesproto.GetForwardingResponse
+	(*PutForwardingRequest)(nil),            // 83: messagesproto.PutForwardingRequest
+	(*PutForwardingResponse)(nil),           // 84: messagesproto.PutForwardingResponse
+	(*Contact)(nil),                         // 85: messagesproto.Contact
+	(*ContactGroup)(nil),                    // 86: messagesproto.ContactGroup
+	(*ContactSuggestion)(nil),               // 87: messagesproto.ContactSuggestion
+	(*CreateContactRequest)(nil),            // 88: messagesproto.CreateContactRequest
+	(*CreateContactResponse)(nil),           // 89: messagesproto.CreateContactResponse
+	(*GetContactsRequest)(nil),              // 90: messagesproto.GetContactsRequest
+	(*GetContactsResponse)(nil),             // 91: messagesproto.GetContactsResponse
+	(*UpdateContactRequest)(nil),            // 92: messagesproto.UpdateContactRequest
+	(*UpdateContactResponse)(nil),           // 93: messagesproto.UpdateContactResponse
+	(*DeleteContactRequest)(nil),            // 94: messagesproto.DeleteContactRequest
+	(*DeleteContactResponse)(nil),           // 95: messagesproto.DeleteContactResponse
+	(*SuggestContactsRequest)(nil),          // 96: messagesproto.SuggestContactsRequest
+	(*SuggestContactsResponse)(nil),         // 97: messagesproto.SuggestContactsResponse
+	(*CreateContactGroupRequest)(nil),       // 98: messagesproto.CreateContactGroupRequest
+	(*CreateContactGroupResponse)(nil),      // 99: messagesproto.CreateContactGroupResponse
+	(*GetContactGroupsRequest)(nil),         // 100: messagesproto.GetContactGroupsRequest
+	(*GetContactGroupsResponse)(nil),        // 101: messagesproto.GetContactGroupsResponse
+	(*RenameContactGroupRequest)(nil),       // 102: messagesproto.RenameContactGroupRequest
+	(*RenameContactGroupResponse)(nil),      // 103: messagesproto.RenameContactGroupResponse
+	(*DeleteContactGroupRequest)(nil),       // 104: messagesproto.DeleteContactGroupRequest
+	(*DeleteContactGroupResponse)(nil),      // 105: messagesproto.DeleteContactGroupResponse
+	(*SenderRule)(nil),                      // 106: messagesproto.SenderRule
+	(*GetSenderListsRequest)(nil),           // 107: messagesproto.GetSenderListsRequest
+	(*GetSenderListsResponse)(nil),          // 108: messagesproto.GetSenderListsResponse
+	(*AddSenderRuleRequest)(nil),            // 109: messagesproto.AddSenderRuleRequest
+	(*AddSenderRuleResponse)(nil),           // 110: messagesproto.AddSenderRuleResponse
+	(*DeleteSenderRuleRequest)(nil),         // 111: messagesproto.DeleteSenderRuleRequest
+	(*DeleteSenderRuleResponse)(nil),        // 112: messagesproto.DeleteSenderRuleResponse
+	(*SetBlockedSenderActionRequest)(nil),   // 113: messagesproto.SetBlockedSenderActionRequest
+	(*SetBlockedSenderActionResponse)(nil),  // 114: messagesproto.SetBlockedSenderActionResponse
+	(*BlockSenderRequest)(nil),              // 115: messagesproto.BlockSenderRequest
+	(*BlockSenderResponse)(nil),             // 116: messagesproto.BlockSenderResponse
+	(*Alias)(nil),                           // 117: messagesproto.Alias
+	(*GetAliasesRequest)(nil),               // 118: messagesproto.GetAliasesRequest
+	(*GetAliasesResponse)(nil),              // 119: messagesproto.GetAliasesResponse
+	(*AddAliasRequest)(nil),                 // 120: messagesproto.AddAliasRequest
+	(*AddAliasResponse)(nil),                // 121: messagesproto.AddAliasResponse
+	(*DeleteAliasRequest)(nil),              // 122: messagesproto.DeleteAliasRequest
+	(*DeleteAliasResponse)(nil),             // 123: messagesproto.DeleteAliasResponse
+	(*Pop3Settings)(nil),                    // 124: messagesproto.Pop3Settings
+	(*GetPop3SettingsRequest)(nil),          // 125: messagesproto.GetPop3SettingsRequest
+	(*GetPop3SettingsResponse)(nil),         // 126: messagesproto.GetPop3SettingsResponse
+	(*PutPop3SettingsRequest)(nil),          // 127: messagesproto.PutPop3SettingsRequest
+	(*PutPop3SettingsResponse)(nil),         // 128: messagesproto.PutPop3SettingsResponse
+	(*SubscribeRequest)(nil),                // 129: messagesproto.SubscribeRequest
+	(*MailEvent)(nil),                       // 130: messagesproto.MailEvent
+	(*GetMailStateRequest)(nil),             // 131: messagesproto.GetMailStateRequest
+	(*GetMailStateResponse)(nil),            // 132: messagesproto.GetMailStateResponse
+	(*GetMailChangesRequest)(nil),           // 133: messagesproto.GetMailChangesRequest
+	(*GetMailChangesResponse)(nil),          // 134: messagesproto.GetMailChangesResponse
+	(*MailThread)(nil),                      // 135: messagesproto.MailThread
+	(*GetThreadsRequest)(nil),               // 136: messagesproto.GetThreadsRequest
+	(*GetThreadsResponse)(nil),              // 137: messagesproto.GetThreadsResponse
 }
 var file_messages_proto_depIdxs = []int32{
-	2,  // 0: messagesproto.Message.sender:type_name -> messagesproto.Sender
-	2,  // 1: messagesproto.FullMessage.sender:type_name -> messagesproto.Sender
-	4,  // 2: messagesproto.FullMessage.files:type_name -> messagesproto.File
-	0,  // 3: messagesproto.InboxResponse.messages:type_name -> messagesproto.Message
-	5,  // 4: messagesproto.InboxResponse.pagination:type_name -> messagesproto.PaginationInfo
-	1,  // 5: messagesproto.MessagePageResponse.message:type_name -> messagesproto.FullMessage
-	3,  // 6: messagesproto.ReplyRequest.receivers:type_name -> messagesproto.Receiver
-	4,  // 7: messagesproto.ReplyRequest.files:type_name -> messagesproto.File
-	3,  // 8: messagesproto.SendRequest.receivers:type_name -> messagesproto.Receiver
-	4,  // 9: messagesproto.SendRequest.files:type_name -> messagesproto.File
-	0,  // 10: messagesproto.SentResponse.messages:type_name -> messagesproto.Message
-	5,  // 11: messagesproto.SentResponse.pagination:type_name -> messagesproto.PaginationInfo
-	0,  // 12: messagesproto.GetFolderResponse.messages:type_name -> messagesproto.Message
-	5,  // 13: messagesproto.GetFolderResponse.pagination:type_name -> messagesproto.PaginationInfo
-	6,  // 14: messagesproto.GetFoldersResponse.folders:type_name -> messagesproto.Folder
-	3,  // 15: messagesproto.SaveDraftRequest.receivers:type_name -> messagesproto.Receiver
-	4,  // 16: messagesproto.SaveDraftRequest.files:type_name -> messagesproto.File
-	8,  // 17: messagesproto.MessagesService.Inbox:input_type -> messagesproto.InboxRequest
-	10, // 18: messagesproto.MessagesService.MessagePage:input_type -> messagesproto.MessagePageRequest
-	12, // 19: messagesproto.MessagesService.Reply:input_type -> messagesproto.ReplyRequest
-	14, // 20: messagesproto.MessagesService.Send:input_type -> messagesproto.SendRequest
-	16, // 21: messagesproto.MessagesService.Sent:input_type -> messagesproto.SentRequest
-	18, // 22: messagesproto.MessagesService.MarkAsSpam:input_type -> messagesproto.MarkAsSpamRequest
-	20, // 23: messagesproto.MessagesService.MoveToFolder:input_type -> messagesproto.MoveToFolderRequest
-	22, // 24: messagesproto.MessagesService.CreateFolder:input_type -> messagesproto.CreateFolderRequest
-	24, // 25: messagesproto.MessagesService.GetFolder:input_type -> messagesproto.GetFolderRequest
-	26, // 26: messagesproto.MessagesService.GetFolders:input_type -> messagesproto.GetFoldersRequest
-	28, // 27: messagesproto.MessagesService.RenameFolder:input_type -> messagesproto.RenameFolderRequest
-	30, // 28: messagesproto.MessagesService.DeleteFolder:input_type -> messagesproto.DeleteFolderRequest
-	32, // 29: messagesproto.MessagesService.DeleteMessageFromFolder:input_type -> messagesproto.DeleteMessageFromFolderRequest
-	34, // 30: messagesproto.MessagesService.SaveDraft:input_type -> messagesproto.SaveDraftRequest
-	36, // 31: messagesproto.MessagesService.DeleteDraft:input_type -> messagesproto.DeleteDraftRequest
-	38, // 32: messagesproto.MessagesService.SendDraft:input_type -> messagesproto.SendDraftRequest
-	9,  // 33: messagesproto.MessagesService.Inbox:output_type -> messagesproto.InboxResponse
-	11, // 34: messagesproto.MessagesService.MessagePage:output_type -> messagesproto.MessagePageResponse
-	13, // 35: messagesproto.MessagesService.Reply:output_type -> messagesproto.ReplyResponse
-	15, // 36: messagesproto.MessagesService.Send:output_type -> messagesproto.SendResponse
-	17, // 37: messagesproto.MessagesService.Sent:output_type -> messagesproto.SentResponse
-	19, // 38: messagesproto.MessagesService.MarkAsSpam:output_type -> messagesproto.MarkAsSpamResponse
-	21, // 39: messagesproto.MessagesService.MoveToFolder:output_type -> messagesproto.MoveToFolderResponse
-	23, // 40: messagesproto.MessagesService.CreateFolder:output_type -> messagesproto.CreateFolderResponse
-	25, // 41: messagesproto.MessagesService.GetFolder:output_type -> messagesproto.GetFolderResponse
-	27, // 42: messagesproto.MessagesService.GetFolders:output_type -> messagesproto.GetFoldersResponse
-	29, // 43: messagesproto.MessagesService.RenameFolder:output_type -> messagesproto.RenameFolderResponse
-	31, // 44: messagesproto.MessagesService.DeleteFolder:output_type -> messagesproto.DeleteFolderResponse
-	33, // 45: messagesproto.MessagesService.DeleteMessageFromFolder:output_type -> messagesproto.DeleteMessageFromFolderResponse
-	35, // 46: messagesproto.MessagesService.SaveDraft:output_type -> messagesproto.SaveDraftResponse
-	37, // 47: messagesproto.MessagesService.DeleteDraft:output_type -> messagesproto.DeleteDraftResponse
-	39, // 48: messagesproto.MessagesService.SendDraft:output_type -> messagesproto.SendDraftResponse
-	33, // [33:49] is the sub-list for method output_type
-	17, // [17:33] is the sub-list for method input_type
-	17, // [17:17] is the sub-list for extension type_name
-	17, // [17:17] is the sub-list for extension extendee
-	0,  // [0:17] is the sub-list for field type_name
+	3,   // 0: messagesproto.Message.sender:type_name -> messagesproto.Sender
+	1,   // 1: messagesproto.Message.labels:type_name -> messagesproto.Label
+	3,   // 2: messagesproto.FullMessage.sender:type_name -> messagesproto.Sender
+	5,   // 3: messagesproto.FullMessage.files:type_name -> messagesproto.File
+	7,   // 4: messagesproto.FolderNode.folder:type_name -> messagesproto.Folder
+	8,   // 5: messagesproto.FolderNode.children:type_name -> messagesproto.FolderNode
+	0,   // 6: messagesproto.InboxResponse.messages:type_name -> messagesproto.Message
+	6,   // 7: messagesproto.InboxResponse.pagination:type_name -> messagesproto.PaginationInfo
+	2,   // 8: messagesproto.MessagePageResponse.message:type_name -> messagesproto.FullMessage
+	4,   // 9: messagesproto.ReplyRequest.receivers:type_name -> messagesproto.Receiver
+	5,   // 10: messagesproto.ReplyRequest.files:type_name -> messagesproto.File
+	4,   // 11: messagesproto.SendRequest.receivers:type_name -> messagesproto.Receiver
+	5,   // 12: messagesproto.SendRequest.files:type_name -> messagesproto.File
+	4,   // 13: messagesproto.ForwardRequest.receivers:type_name -> messagesproto.Receiver
+	0,   // 14: messagesproto.SentResponse.messages:type_name -> messagesproto.Message
+	6,   // 15: messagesproto.SentResponse.pagination:type_name -> messagesproto.PaginationInfo
+	0,   // 16: messagesproto.GetFolderResponse.messages:type_name -> messagesproto.Message
+	6,   // 17: messagesproto.GetFolderResponse.pagination:type_name -> messagesproto.PaginationInfo
+	7,   // 18: messagesproto.GetFoldersResponse.folders:type_name -> messagesproto.Folder
+	7,   // 19: messagesproto.MoveFolderResponse.folder:type_name -> messagesproto.Folder
+	8,   // 20: messagesproto.GetFolderTreeResponse.folders:type_name -> messagesproto.FolderNode
+	4,   // 21: messagesproto.SaveDraftRequest.receivers:type_name -> messagesproto.Receiver
+	5,   // 22: messagesproto.SaveDraftRequest.files:type_name -> messagesproto.File
+	64,  // 23: messagesproto.BatchRequest.filter:type_name -> messagesproto.BatchFilter
+	66,  // 24: messagesproto.BatchResponse.results:type_name -> messagesproto.BatchItemResult
+	1,   // 25: messagesproto.CreateLabelResponse.label:type_name -> messagesproto.Label
+	1,   // 26: messagesproto.GetLabelsResponse.labels:type_name -> messagesproto.Label
+	1,   // 27: messagesproto.UpdateLabelResponse.label:type_name -> messagesproto.Label
+	80,  // 28: messagesproto.GetForwardingResponse.rule:type_name -> messagesproto.ForwardingRule
+	80,  // 29: messagesproto.PutForwardingRequest.rule:type_name -> messagesproto.ForwardingRule
+	80,  // 30: messagesproto.PutForwardingResponse.rule:type_name -> messagesproto.ForwardingRule
+	85,  // 31: messagesproto.CreateContactRequest.contact:type_name -> messagesproto.Contact
+	85,  // 32: messagesproto.CreateContactResponse.contact:type_name -> messagesproto.Contact
+	85,  // 33: messagesproto.GetContactsResponse.contacts:type_name -> messagesproto.Contact
+	85,  // 34: messagesproto.UpdateContactRequest.contact:type_name -> messagesproto.Contact
+	85,  // 35: messagesproto.UpdateContactResponse.contact:type_name -> messagesproto.Contact
+	87,  // 36: messagesproto.SuggestContactsResponse.suggestions:type_name -> messagesproto.ContactSuggestion
+	86,  // 37: messagesproto.CreateContactGroupResponse.group:type_name -> messagesproto.ContactGroup
+	86,  // 38: messagesproto.GetContactGroupsResponse.groups:type_name -> messagesproto.ContactGroup
+	86,  // 39: messagesproto.RenameContactGroupResponse.group:type_name -> messagesproto.ContactGroup
+	106, // 40: messagesproto.GetSenderListsResponse.blocked:type_name -> messagesproto.SenderRule
+	106, // 41: messagesproto.GetSenderListsResponse.allowed:type_name -> messagesproto.SenderRule
+	106, // 42: messagesproto.AddSenderRuleResponse.rule:type_name -> messagesproto.SenderRule
+	106, // 43: messagesproto.BlockSenderResponse.rule:type_name -> messagesproto.SenderRule
+	117, // 44: messagesproto.GetAliasesResponse.aliases:type_name -> messagesproto.Alias
+	117, // 45: messagesproto.AddAliasResponse.alias:type_name -> messagesproto.Alias
+	124, // 46: messagesproto.GetPop3SettingsResponse.settings:type_name -> messagesproto.Pop3Settings
+	124, // 47: messagesproto.PutPop3SettingsRequest.settings:type_name -> messagesproto.Pop3Settings
+	124, // 48: messagesproto.PutPop3SettingsResponse.settings:type_name -> messagesproto.Pop3Settings
+	135, // 49: messagesproto.GetThreadsResponse.threads:type_name -> messagesproto.MailThread
+	10,  // 50: messagesproto.MessagesService.Inbox:input_type -> messagesproto.InboxRequest
+	12,  // 51: messagesproto.MessagesService.MessagePage:input_type -> messagesproto.MessagePageRequest
+	14,  // 52: messagesproto.MessagesService.Reply:input_type -> messagesproto.ReplyRequest
+	16,  // 53: messagesproto.MessagesService.Send:input_type -> messagesproto.SendRequest
+	18,  // 54: messagesproto.MessagesService.Forward:input_type -> messagesproto.ForwardRequest
+	20,  // 55: messagesproto.MessagesService.Sent:input_type -> messagesproto.SentRequest
+	22,  // 56: messagesproto.MessagesService.MarkAsSpam:input_type -> messagesproto.MarkAsSpamRequest
+	24,  // 57: messagesproto.MessagesService.MarkAsNotSpam:input_type -> messagesproto.MarkAsNotSpamRequest
+	26,  // 58: messagesproto.MessagesService.MoveToFolder:input_type -> messagesproto.MoveToFolderRequest
+	28,  // 59: messagesproto.MessagesService.SetFlags:input_type -> messagesproto.SetFlagsRequest
+	30,  // 60: messagesproto.MessagesService.CreateFolder:input_type -> messagesproto.CreateFolderRequest
+	32,  // 61: messagesproto.MessagesService.GetFolder:input_type -> messagesproto.GetFolderRequest
+	34,  // 62: messagesproto.MessagesService.GetFolders:input_type -> messagesproto.GetFoldersRequest
+	36,  // 63: messagesproto.MessagesService.RenameFolder:input_type -> messagesproto.RenameFolderRequest
+	38,  // 64: messagesproto.MessagesService.DeleteFolder:input_type -> messagesproto.DeleteFolderRequest
+	40,  // 65: messagesproto.MessagesService.DeleteMessageFromFolder:input_type -> messagesproto.DeleteMessageFromFolderRequest
+	42,  // 66: messagesproto.MessagesService.MoveFolder:input_type -> messagesproto.MoveFolderRequest
+	44,  // 67: messagesproto.MessagesService.GetFolderTree:input_type -> messagesproto.GetFolderTreeRequest
+	46,  // 68: messagesproto.MessagesService.SaveDraft:input_type -> messagesproto.SaveDraftRequest
+	48,  // 69: messagesproto.MessagesService.DeleteDraft:input_type -> messagesproto.DeleteDraftRequest
+	50,  // 70: messagesproto.MessagesService.SendDraft:input_type -> messagesproto.SendDraftRequest
+	52,  // 71: messagesproto.MessagesService.PutSieveScript:input_type -> messagesproto.PutSieveScriptRequest
+	54,  // 72: messagesproto.MessagesService.GetSieveScript:input_type -> messagesproto.GetSieveScriptRequest
+	56,  // 73: messagesproto.MessagesService.CheckSieveScript:input_type -> messagesproto.CheckSieveScriptRequest
+	58,  // 74: messagesproto.MessagesService.RestoreFromTrash:input_type -> messagesproto.RestoreFromTrashRequest
+	60,  // 75: messagesproto.MessagesService.EmptyTrash:input_type -> messagesproto.EmptyTrashRequest
+	62,  // 76: messagesproto.MessagesService.DeletePermanently:input_type -> messagesproto.DeletePermanentlyRequest
+	65,  // 77: messagesproto.MessagesService.Batch:input_type -> messagesproto.BatchRequest
+	68,  // 78: messagesproto.MessagesService.CreateLabel:input_type -> messagesproto.CreateLabelRequest
+	70,  // 79: messagesproto.MessagesService.GetLabels:input_type -> messagesproto.GetLabelsRequest
+	72,  // 80: messagesproto.MessagesService.UpdateLabel:input_type -> messagesproto.UpdateLabelRequest
+	74,  // 81: messagesproto.MessagesService.DeleteLabel:input_type -> messagesproto.DeleteLabelRequest
+	76,  // 82: messagesproto.MessagesService.ApplyLabel:input_type -> messagesproto.ApplyLabelRequest
+	78,  // 83: messagesproto.MessagesService.RemoveLabel:input_type -> messagesproto.RemoveLabelRequest
+	81,  // 84: messagesproto.MessagesService.GetForwarding:input_type -> messagesproto.GetForwardingRequest
+	83,  // 85: messagesproto.MessagesService.PutForwarding:input_type -> messagesproto.PutForwardingRequest
+	88,  // 86: messagesproto.MessagesService.CreateContact:input_type -> messagesproto.CreateContactRequest
+	90,  // 87: messagesproto.MessagesService.GetContacts:input_type -> messagesproto.GetContactsRequest
+	92,  // 88: messagesproto.MessagesService.UpdateContact:input_type -> messagesproto.UpdateContactRequest
+	94,  // 89: messagesproto.MessagesService.DeleteContact:input_type -> messagesproto.DeleteContactRequest
+	96,  // 90: messagesproto.MessagesService.SuggestContacts:input_type -> messagesproto.SuggestContactsRequest
+	98,  // 91: messagesproto.MessagesService.CreateContactGroup:input_type -> messagesproto.CreateContactGroupRequest
+	100, // 92: messagesproto.MessagesService.GetContactGroups:input_type -> messagesproto.GetContactGroupsRequest
+	102, // 93: messagesproto.MessagesService.RenameContactGroup:input_type -> messagesproto.RenameContactGroupRequest
+	104, // 94: messagesproto.MessagesService.DeleteContactGroup:input_type -> messagesproto.DeleteContactGroupRequest
+	107, // 95: messagesproto.MessagesService.GetSenderLists:input_type -> messagesproto.GetSenderListsRequest
+	109, // 96: messagesproto.MessagesService.AddSenderRule:input_type -> messagesproto.AddSenderRuleRequest
+	111, // 97: messagesproto.MessagesService.DeleteSenderRule:input_type -> messagesproto.DeleteSenderRuleRequest
+	113, // 98: messagesproto.MessagesService.SetBlockedSenderAction:input_type -> messagesproto.SetBlockedSenderActionRequest
+	115, // 99: messagesproto.MessagesService.BlockSender:input_type -> messagesproto.BlockSenderRequest
+	118, // 100: messagesproto.MessagesService.GetAliases:input_type -> messagesproto.GetAliasesRequest
+	120, // 101: messagesproto.MessagesService.AddAlias:input_type -> messagesproto.AddAliasRequest
+	122, // 102: messagesproto.MessagesService.DeleteAlias:input_type -> messagesproto.DeleteAliasRequest
+	125, // 103: messagesproto.MessagesService.GetPop3Settings:input_type -> messagesproto.GetPop3SettingsRequest
+	127, // 104: messagesproto.MessagesService.PutPop3Settings:input_type -> messagesproto.PutPop3SettingsRequest
+	129, // 105: messagesproto.MessagesService.Subscribe:input_type -> messagesproto.SubscribeRequest
+	131, // 106: messagesproto.MessagesService.GetMailState:input_type -> messagesproto.GetMailStateRequest
+	133, // 107: messagesproto.MessagesService.GetMailChanges:input_type -> messagesproto.GetMailChangesRequest
+	136, // 108: messagesproto.MessagesService.GetThreads:input_type -> messagesproto.GetThreadsRequest
+	11,  // 109: messagesproto.MessagesService.Inbox:output_type -> messagesproto.InboxResponse
+	13,  // 110: messagesproto.MessagesService.MessagePage:output_type -> messagesproto.MessagePageResponse
+	15,  // 111: messagesproto.MessagesService.Reply:output_type -> messagesproto.ReplyResponse
+	17,  // 112: messagesproto.MessagesService.Send:output_type -> messagesproto.SendResponse
+	19,  // 113: messagesproto.MessagesService.Forward:output_type -> messagesproto.ForwardResponse
+	21,  // 114: messagesproto.MessagesService.Sent:output_type -> messagesproto.SentResponse
+	23,  // 115: messagesproto.MessagesService.MarkAsSpam:output_type -> messagesproto.MarkAsSpamResponse
+	25,  // 116: messagesproto.MessagesService.MarkAsNotSpam:output_type -> messagesproto.MarkAsNotSpamResponse
+	27,  // 117: messagesproto.MessagesService.MoveToFolder:output_type -> messagesproto.MoveToFolderResponse
+	29,  // 118: messagesproto.MessagesService.SetFlags:output_type -> messagesproto.SetFlagsResponse
+	31,  // 119: messagesproto.MessagesService.CreateFolder:output_type -> messagesproto.CreateFolderResponse
+	33,  // 120: messagesproto.MessagesService.GetFolder:output_type -> messagesproto.GetFolderResponse
+	35,  // 121: messagesproto.MessagesService.GetFolders:output_type -> messagesproto.GetFoldersResponse
+	37,  // 122: messagesproto.MessagesService.RenameFolder:output_type -> messagesproto.RenameFolderResponse
+	39,  // 123: messagesproto.MessagesService.DeleteFolder:output_type -> messagesproto.DeleteFolderResponse
+	41,  // 124: messagesproto.MessagesService.DeleteMessageFromFolder:output_type -> messagesproto.DeleteMessageFromFolderResponse
+	43,  // 125: messagesproto.MessagesService.MoveFolder:output_type -> messagesproto.MoveFolderResponse
+	45,  // 126: messagesproto.MessagesService.GetFolderTree:output_type -> messagesproto.GetFolderTreeResponse
+	47,  // 127: messagesproto.MessagesService.SaveDraft:output_type -> messagesproto.SaveDraftResponse
+	49,  // 128: messagesproto.MessagesService.DeleteDraft:output_type -> messagesproto.DeleteDraftResponse
+	51,  // 129: messagesproto.MessagesService.SendDraft:output_type -> messagesproto.SendDraftResponse
+	53,  // 130: messagesproto.MessagesService.PutSieveScript:output_type -> messagesproto.PutSieveScriptResponse
+	55,  // 131: messagesproto.MessagesService.GetSieveScript:output_type -> messagesproto.GetSieveScriptResponse
+	57,  // 132: messagesproto.MessagesService.CheckSieveScript:output_type -> messagesproto.CheckSieveScriptResponse
+	59,  // 133: messagesproto.MessagesService.RestoreFromTrash:output_type -> messagesproto.RestoreFromTrashResponse
+	61,  // 134: messagesproto.MessagesService.EmptyTrash:output_type -> messagesproto.EmptyTrashResponse
+	63,  // 135: messagesproto.MessagesService.DeletePermanently:output_type -> messagesproto.DeletePermanentlyResponse
+	67,  // 136: messagesproto.MessagesService.Batch:output_type -> messagesproto.BatchResponse
+	69,  // 137: messagesproto.MessagesService.CreateLabel:output_type -> messagesproto.CreateLabelResponse
+	71,  // 138: messagesproto.MessagesService.GetLabels:output_type -> messagesproto.GetLabelsResponse
+	73,  // 139: messagesproto.MessagesService.UpdateLabel:output_type -> messagesproto.UpdateLabelResponse
+	75,  // 140: messagesproto.MessagesService.DeleteLabel:output_type -> messagesproto.DeleteLabelResponse
+	77,  // 141: messagesproto.MessagesService.ApplyLabel:output_type -> messagesproto.ApplyLabelResponse
+	79,  // 142: messagesproto.MessagesService.RemoveLabel:output_type -> messagesproto.RemoveLabelResponse
+	82,  // 143: messagesproto.MessagesService.GetForwarding:output_type -> messagesproto.GetForwardingResponse
+	84,  // 144: messagesproto.MessagesService.PutForwarding:output_type -> messagesproto.PutForwardingResponse
+	89,  // 145: messagesproto.MessagesService.CreateContact:output_type -> messagesproto.CreateContactResponse
+	91,  // 146: messagesproto.MessagesService.GetContacts:output_type -> messagesproto.GetContactsResponse
+	93,  // 147: messagesproto.MessagesService.UpdateContact:output_type -> messagesproto.UpdateContactResponse
+	95,  // 148: messagesproto.MessagesService.DeleteContact:output_type -> messagesproto.DeleteContactResponse
+	97,  // 149: messagesproto.MessagesService.SuggestContacts:output_type -> messagesproto.SuggestContactsResponse
+	99,  // 150: messagesproto.MessagesService.CreateContactGroup:output_type -> messagesproto.CreateContactGroupResponse
+	101, // 151: messagesproto.MessagesService.GetContactGroups:output_type -> messagesproto.GetContactGroupsResponse
+	103, // 152: messagesproto.MessagesService.RenameContactGroup:output_type -> messagesproto.RenameContactGroupResponse
+	105, // 153: messagesproto.MessagesService.DeleteContactGroup:output_type -> messagesproto.DeleteContactGroupResponse
+	108, // 154: messagesproto.MessagesService.GetSenderLists:output_type -> messagesproto.GetSenderListsResponse
+	110, // 155: messagesproto.MessagesService.AddSenderRule:output_type -> messagesproto.AddSenderRuleResponse
+	112, // 156: messagesproto.MessagesService.DeleteSenderRule:output_type -> messagesproto.DeleteSenderRuleResponse
+	114, // 157: messagesproto.MessagesService.SetBlockedSenderAction:output_type -> messagesproto.SetBlockedSenderActionResponse
+	116, // 158: messagesproto.MessagesService.BlockSender:output_type -> messagesproto.BlockSenderResponse
+	119, // 159: messagesproto.MessagesService.GetAliases:output_type -> messagesproto.GetAliasesResponse
+	121, // 160: messagesproto.MessagesService.AddAlias:output_type -> messagesproto.AddAliasResponse
+	123, // 161: messagesproto.MessagesService.DeleteAlias:output_type -> messagesproto.DeleteAliasResponse
+	126, // 162: messagesproto.MessagesService.GetPop3Settings:output_type -> messagesproto.GetPop3SettingsResponse
+	128, // 163: messagesproto.MessagesService.PutPop3Settings:output_type -> messagesproto.PutPop3SettingsResponse
+	130, // 164: messagesproto.MessagesService.Subscribe:output_type -> messagesproto.MailEvent
+	132, // 165: messagesproto.MessagesService.GetMailState:output_type -> messagesproto.GetMailStateResponse
+	134, // 166: messagesproto.MessagesService.GetMailChanges:output_type -> messagesproto.GetMailChangesResponse
+	137, // 167: messagesproto.MessagesService.GetThreads:output_type -> messagesproto.GetThreadsResponse
+	109, // [109:168] is the sub-list for method output_type
+	50,  // [50:109] is the sub-list for method input_type
+	50,  // [50:50] is the sub-list for extension type_name
+	50,  // [50:50] is the sub-list for extension extendee
+	0,   // [0:50] is the sub-list for field type_name
 }
 
 func init() { file_messages_proto_init() }
@@ -2503,7 +8324,7 @@ func file_messages_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_messages_proto_rawDesc), len(file_messages_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   40,
+			NumMessages:   138,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -23,18 +23,61 @@ const (
 	MessagesService_MessagePage_FullMethodName             = "/messagesproto.MessagesService/MessagePage"
 	MessagesService_Reply_FullMethodName                   = "/messagesproto.MessagesService/Reply"
 	MessagesService_Send_FullMethodName                    = "/messagesproto.MessagesService/Send"
+	MessagesService_Forward_FullMethodName                 = "/messagesproto.MessagesService/Forward"
 	MessagesService_Sent_FullMethodName                    = "/messagesproto.MessagesService/Sent"
 	MessagesService_MarkAsSpam_FullMethodName              = "/messagesproto.MessagesService/MarkAsSpam"
+	MessagesService_MarkAsNotSpam_FullMethodName           = "/messagesproto.MessagesService/MarkAsNotSpam"
 	MessagesService_MoveToFolder_FullMethodName            = "/messagesproto.MessagesService/MoveToFolder"
+	MessagesService_SetFlags_FullMethodName                = "/messagesproto.MessagesService/SetFlags"
 	MessagesService_CreateFolder_FullMethodName            = "/messagesproto.MessagesService/CreateFolder"
 	MessagesService_GetFolder_FullMethodName               = "/messagesproto.MessagesService/GetFolder"
 	MessagesService_GetFolders_FullMethodName              = "/messagesproto.MessagesService/GetFolders"
 	MessagesService_RenameFolder_FullMethodName            = "/messagesproto.MessagesService/RenameFolder"
 	MessagesService_DeleteFolder_FullMethodName            = "/messagesproto.MessagesService/DeleteFolder"
 	MessagesService_DeleteMessageFromFolder_FullMethodName = "/messagesproto.MessagesService/DeleteMessageFromFolder"
+	MessagesService_MoveFolder_FullMethodName              = "/messagesproto.MessagesService/MoveFolder"
+	MessagesService_GetFolderTree_FullMethodName           = "/messagesproto.MessagesService/GetFolderTree"
 	MessagesService_SaveDraft_FullMethodName               = "/messagesproto.MessagesService/SaveDraft"
 	MessagesService_DeleteDraft_FullMethodName             = "/messagesproto.MessagesService/DeleteDraft"
 	MessagesService_SendDraft_FullMethodName               = "/messagesproto.MessagesService/SendDraft"
+	MessagesService_PutSieveScript_FullMethodName          = "/messagesproto.MessagesService/PutSieveScript"
+	MessagesService_GetSieveScript_FullMethodName          = "/messagesproto.MessagesService/GetSieveScript"
+	MessagesService_CheckSieveScript_FullMethodName        = "/messagesproto.MessagesService/CheckSieveScript"
+	MessagesService_RestoreFromTrash_FullMethodName        = "/messagesproto.MessagesService/RestoreFromTrash"
+	MessagesService_EmptyTrash_FullMethodName              = "/messagesproto.MessagesService/EmptyTrash"
+	MessagesService_DeletePermanently_FullMethodName       = "/messagesproto.MessagesService/DeletePermanently"
+	MessagesService_Batch_FullMethodName                   = "/messagesproto.MessagesService/Batch"
+	MessagesService_CreateLabel_FullMethodName             = "/messagesproto.MessagesService/CreateLabel"
+	MessagesService_GetLabels_FullMethodName               = "/messagesproto.MessagesService/GetLabels"
+	MessagesService_UpdateLabel_FullMethodName             = "/messagesproto.MessagesService/UpdateLabel"
+	MessagesService_DeleteLabel_FullMethodName             = "/messagesproto.MessagesService/DeleteLabel"
+	MessagesService_ApplyLabel_FullMethodName              = "/messagesproto.MessagesService/ApplyLabel"
+	MessagesService_RemoveLabel_FullMethodName             = "/messagesproto.MessagesService/RemoveLabel"
+	MessagesService_GetForwarding_FullMethodName           = "/messagesproto.MessagesService/GetForwarding"
+	MessagesService_PutForwarding_FullMethodName           = "/messagesproto.MessagesService/PutForwarding"
+	MessagesService_CreateContact_FullMethodName           = "/messagesproto.MessagesService/CreateContact"
+	MessagesService_GetContacts_FullMethodName             = "/messagesproto.MessagesService/GetContacts"
+	MessagesService_UpdateContact_FullMethodName           = "/messagesproto.MessagesService/UpdateContact"
+	MessagesService_DeleteContact_FullMethodName           = "/messagesproto.MessagesService/DeleteContact"
+	MessagesService_SuggestContacts_FullMethodName         = "/messagesproto.MessagesService/SuggestContacts"
+	MessagesService_CreateContactGroup_FullMethodName      = "/messagesproto.MessagesService/CreateContactGroup"
+	MessagesService_GetContactGroups_FullMethodName        = "/messagesproto.MessagesService/GetContactGroups"
+	MessagesService_RenameContactGroup_FullMethodName      = "/messagesproto.MessagesService/RenameContactGroup"
+	MessagesService_DeleteContactGroup_FullMethodName      = "/messagesproto.MessagesService/DeleteContactGroup"
+	MessagesService_GetSenderLists_FullMethodName          = "/messagesproto.MessagesService/GetSenderLists"
+	MessagesService_AddSenderRule_FullMethodName           = "/messagesproto.MessagesService/AddSenderRule"
+	MessagesService_DeleteSenderRule_FullMethodName        = "/messagesproto.MessagesService/DeleteSenderRule"
+	MessagesService_SetBlockedSenderAction_FullMethodName  = "/messagesproto.MessagesService/SetBlockedSenderAction"
+	MessagesService_BlockSender_FullMethodName             = "/messagesproto.MessagesService/BlockSender"
+	MessagesService_GetAliases_FullMethodName              = "/messagesproto.MessagesService/GetAliases"
+	MessagesService_AddAlias_FullMethodName                = "/messagesproto.MessagesService/AddAlias"
+	MessagesService_DeleteAlias_FullMethodName             = "/messagesproto.MessagesService/DeleteAlias"
+	MessagesService_GetPop3Settings_FullMethodName         = "/messagesproto.MessagesService/GetPop3Settings"
+	MessagesService_PutPop3Settings_FullMethodName         = "/messagesproto.MessagesService/PutPop3Settings"
+	MessagesService_Subscribe_FullMethodName               = "/messagesproto.MessagesService/Subscribe"
+	MessagesService_GetMailState_FullMethodName            = "/messagesproto.MessagesService/GetMailState"
+	MessagesService_GetMailChanges_FullMethodName          = "/messagesproto.MessagesService/GetMailChanges"
+	MessagesService_GetThreads_FullMethodName              = "/messagesproto.MessagesService/GetThreads"
 )
 
 // MessagesServiceClient is the client API for MessagesService service.
@@ -46,10 +89,13 @@ type MessagesServiceClient interface {
 	MessagePage(ctx context.Context, in *MessagePageRequest, opts ...grpc.CallOption) (*MessagePageResponse, error)
 	Reply(ctx context.Context, in *ReplyRequest, opts ...grpc.CallOption) (*ReplyResponse, error)
 	Send(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error)
+	Forward(ctx context.Context, in *ForwardRequest, opts ...grpc.CallOption) (*ForwardResponse, error)
 	Sent(ctx context.Context, in *SentRequest, opts ...grpc.CallOption) (*SentResponse, error)
 	// Методы для работы с сообщениями
 	MarkAsSpam(ctx context.Context, in *MarkAsSpamRequest, opts ...grpc.CallOption) (*MarkAsSpamResponse, error)
+	MarkAsNotSpam(ctx context.Context, in *MarkAsNotSpamRequest, opts ...grpc.CallOption) (*MarkAsNotSpamResponse, error)
 	MoveToFolder(ctx context.Context, in *MoveToFolderRequest, opts ...grpc.CallOption) (*MoveToFolderResponse, error)
+	SetFlags(ctx context.Context, in *SetFlagsRequest, opts ...grpc.CallOption) (*SetFlagsResponse, error)
 	// Методы для папок
 	CreateFolder(ctx context.Context, in *CreateFolderRequest, opts ...grpc.CallOption) (*CreateFolderResponse, error)
 	GetFolder(ctx context.Context, in *GetFolderRequest, opts ...grpc.CallOption) (*GetFolderResponse, error)
@@ -57,10 +103,61 @@ type MessagesServiceClient interface {
 	RenameFolder(ctx context.Context, in *RenameFolderRequest, opts ...grpc.CallOption) (*RenameFolderResponse, error)
 	DeleteFolder(ctx context.Context, in *DeleteFolderRequest, opts ...grpc.CallOption) (*DeleteFolderResponse, error)
 	DeleteMessageFromFolder(ctx context.Context, in *DeleteMessageFromFolderRequest, opts ...grpc.CallOption) (*DeleteMessageFromFolderResponse, error)
+	MoveFolder(ctx context.Context, in *MoveFolderRequest, opts ...grpc.CallOption) (*MoveFolderResponse, error)
+	GetFolderTree(ctx context.Context, in *GetFolderTreeRequest, opts ...grpc.CallOption) (*GetFolderTreeResponse, error)
 	// Методы для черновиков
 	SaveDraft(ctx context.Context, in *SaveDraftRequest, opts ...grpc.CallOption) (*SaveDraftResponse, error)
 	DeleteDraft(ctx context.Context, in *DeleteDraftRequest, opts ...grpc.CallOption) (*DeleteDraftResponse, error)
 	SendDraft(ctx context.Context, in *SendDraftRequest, opts ...grpc.CallOption) (*SendDraftResponse, error)
+	// Методы для sieve-фильтров
+	PutSieveScript(ctx context.Context, in *PutSieveScriptRequest, opts ...grpc.CallOption) (*PutSieveScriptResponse, error)
+	GetSieveScript(ctx context.Context, in *GetSieveScriptRequest, opts ...grpc.CallOption) (*GetSieveScriptResponse, error)
+	CheckSieveScript(ctx context.Context, in *CheckSieveScriptRequest, opts ...grpc.CallOption) (*CheckSieveScriptResponse, error)
+	// Методы для корзины
+	RestoreFromTrash(ctx context.Context, in *RestoreFromTrashRequest, opts ...grpc.CallOption) (*RestoreFromTrashResponse, error)
+	EmptyTrash(ctx context.Context, in *EmptyTrashRequest, opts ...grpc.CallOption) (*EmptyTrashResponse, error)
+	DeletePermanently(ctx context.Context, in *DeletePermanentlyRequest, opts ...grpc.CallOption) (*DeletePermanentlyResponse, error)
+	// Пакетные операции
+	Batch(ctx context.Context, in *BatchRequest, opts ...grpc.CallOption) (*BatchResponse, error)
+	// Методы для меток
+	CreateLabel(ctx context.Context, in *CreateLabelRequest, opts ...grpc.CallOption) (*CreateLabelResponse, error)
+	GetLabels(ctx context.Context, in *GetLabelsRequest, opts ...grpc.CallOption) (*GetLabelsResponse, error)
+	UpdateLabel(ctx context.Context, in *UpdateLabelRequest, opts ...grpc.CallOption) (*UpdateLabelResponse, error)
+	DeleteLabel(ctx context.Context, in *DeleteLabelRequest, opts ...grpc.CallOption) (*DeleteLabelResponse, error)
+	ApplyLabel(ctx context.Context, in *ApplyLabelRequest, opts ...grpc.CallOption) (*ApplyLabelResponse, error)
+	RemoveLabel(ctx context.Context, in *RemoveLabelRequest, opts ...grpc.CallOption) (*RemoveLabelResponse, error)
+	// Методы для автопересылки
+	GetForwarding(ctx context.Context, in *GetForwardingRequest, opts ...grpc.CallOption) (*GetForwardingResponse, error)
+	PutForwarding(ctx context.Context, in *PutForwardingRequest, opts ...grpc.CallOption) (*PutForwardingResponse, error)
+	// Методы для адресной книги
+	CreateContact(ctx context.Context, in *CreateContactRequest, opts ...grpc.CallOption) (*CreateContactResponse, error)
+	GetContacts(ctx context.Context, in *GetContactsRequest, opts ...grpc.CallOption) (*GetContactsResponse, error)
+	UpdateContact(ctx context.Context, in *UpdateContactRequest, opts ...grpc.CallOption) (*UpdateContactResponse, error)
+	DeleteContact(ctx context.Context, in *DeleteContactRequest, opts ...grpc.CallOption) (*DeleteContactResponse, error)
+	SuggestContacts(ctx context.Context, in *SuggestContactsRequest, opts ...grpc.CallOption) (*SuggestContactsResponse, error)
+	CreateContactGroup(ctx context.Context, in *CreateContactGroupRequest, opts ...grpc.CallOption) (*CreateContactGroupResponse, error)
+	GetContactGroups(ctx context.Context, in *GetContactGroupsRequest, opts ...grpc.CallOption) (*GetContactGroupsResponse, error)
+	RenameContactGroup(ctx context.Context, in *RenameContactGroupRequest, opts ...grpc.CallOption) (*RenameContactGroupResponse, error)
+	DeleteContactGroup(ctx context.Context, in *DeleteContactGroupRequest, opts ...grpc.CallOption) (*DeleteContactGroupResponse, error)
+	// Методы для списков блокировки и разрешенных отправителей
+	GetSenderLists(ctx context.Context, in *GetSenderListsRequest, opts ...grpc.CallOption) (*GetSenderListsResponse, error)
+	AddSenderRule(ctx context.Context, in *AddSenderRuleRequest, opts ...grpc.CallOption) (*AddSenderRuleResponse, error)
+	DeleteSenderRule(ctx context.Context, in *DeleteSenderRuleRequest, opts ...grpc.CallOption) (*DeleteSenderRuleResponse, error)
+	SetBlockedSenderAction(ctx context.Context, in *SetBlockedSenderActionRequest, opts ...grpc.CallOption) (*SetBlockedSenderActionResponse, error)
+	BlockSender(ctx context.Context, in *BlockSenderRequest, opts ...grpc.CallOption) (*BlockSenderResponse, error)
+	// Дополнительные адреса профиля
+	GetAliases(ctx context.Context, in *GetAliasesRequest, opts ...grpc.CallOption) (*GetAliasesResponse, error)
+	AddAlias(ctx context.Context, in *AddAliasRequest, opts ...grpc.CallOption) (*AddAliasResponse, error)
+	DeleteAlias(ctx context.Context, in *DeleteAliasRequest, opts ...grpc.CallOption) (*DeleteAliasResponse, error)
+	// Настройки доступа по POP3
+	GetPop3Settings(ctx context.Context, in *GetPop3SettingsRequest, opts ...grpc.CallOption) (*GetPop3SettingsResponse, error)
+	PutPop3Settings(ctx context.Context, in *PutPop3SettingsRequest, opts ...grpc.CallOption) (*PutPop3SettingsResponse, error)
+	// События почтового ящика в реальном времени
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MailEvent], error)
+	// Синхронизация клиентов JMAP
+	GetMailState(ctx context.Context, in *GetMailStateRequest, opts ...grpc.CallOption) (*GetMailStateResponse, error)
+	GetMailChanges(ctx context.Context, in *GetMailChangesRequest, opts ...grpc.CallOption) (*GetMailChangesResponse, error)
+	GetThreads(ctx context.Context, in *GetThreadsRequest, opts ...grpc.CallOption) (*GetThreadsResponse, error)
 }
 
 type messagesServiceClient struct {
@@ -111,6 +208,16 @@ func (c *messagesServiceClient) Send(ctx context.Context, in *SendRequest, opts
 	return out, nil
 }
 
+func (c *messagesServiceClient) Forward(ctx context.Context, in *ForwardRequest, opts ...grpc.CallOption) (*ForwardResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ForwardResponse)
+	err := c.cc.Invoke(ctx, MessagesService_Forward_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *messagesServiceClient) Sent(ctx context.Context, in *SentRequest, opts ...grpc.CallOption) (*SentResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(SentResponse)
@@ -131,6 +238,16 @@ func (c *messagesServiceClient) MarkAsSpam(ctx context.Context, in *MarkAsSpamRe
 	return out, nil
 }
 
+func (c *messagesServiceClient) MarkAsNotSpam(ctx context.Context, in *MarkAsNotSpamRequest, opts ...grpc.CallOption) (*MarkAsNotSpamResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MarkAsNotSpamResponse)
+	err := c.cc.Invoke(ctx, MessagesService_MarkAsNotSpam_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *messagesServiceClient) MoveToFolder(ctx context.Context, in *MoveToFolderRequest, opts ...grpc.CallOption) (*MoveToFolderResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(MoveToFolderResponse)
@@ -141,6 +258,16 @@ func (c *messagesServiceClient) MoveToFolder(ctx context.Context, in *MoveToFold
 	return out, nil
 }
 
+func (c *messagesServiceClient) SetFlags(ctx context.Context, in *SetFlagsRequest, opts ...grpc.CallOption) (*SetFlagsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetFlagsResponse)
+	err := c.cc.Invoke(ctx, MessagesService_SetFlags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *messagesServiceClient) CreateFolder(ctx context.Context, in *CreateFolderRequest, opts ...grpc.CallOption) (*CreateFolderResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(CreateFolderResponse)
@@ -201,6 +328,26 @@ func (c *messagesServiceClient) DeleteMessageFromFolder(ctx context.Context, in
 	return out, nil
 }
 
+func (c *messagesServiceClient) MoveFolder(ctx context.Context, in *MoveFolderRequest, opts ...grpc.CallOption) (*MoveFolderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MoveFolderResponse)
+	err := c.cc.Invoke(ctx, MessagesService_MoveFolder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagesServiceClient) GetFolderTree(ctx context.Context, in *GetFolderTreeRequest, opts ...grpc.CallOption) (*GetFolderTreeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetFolderTreeResponse)
+	err := c.cc.Invoke(ctx, MessagesService_GetFolderTree_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *messagesServiceClient) SaveDraft(ctx context.Context, in *SaveDraftRequest, opts ...grpc.CallOption) (*SaveDraftResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(SaveDraftResponse)
@@ -231,393 +378,1732 @@ func (c *messagesServiceClient) SendDraft(ctx context.Context, in *SendDraftRequ
 	return out, nil
 }
 
-// MessagesServiceServer is the server API for MessagesService service.
-// All implementations must embed UnimplementedMessagesServiceServer
-// for forward compatibility.
-type MessagesServiceServer interface {
-	// Основные методы для сообщений
-	Inbox(context.Context, *InboxRequest) (*InboxResponse, error)
-	MessagePage(context.Context, *MessagePageRequest) (*MessagePageResponse, error)
-	Reply(context.Context, *ReplyRequest) (*ReplyResponse, error)
-	Send(context.Context, *SendRequest) (*SendResponse, error)
-	Sent(context.Context, *SentRequest) (*SentResponse, error)
-	// Методы для работы с сообщениями
-	MarkAsSpam(context.Context, *MarkAsSpamRequest) (*MarkAsSpamResponse, error)
-	MoveToFolder(context.Context, *MoveToFolderRequest) (*MoveToFolderResponse, error)
-	// Методы для папок
-	CreateFolder(context.Context, *CreateFolderRequest) (*CreateFolderResponse, error)
-	GetFolder(context.Context, *GetFolderRequest) (*GetFolderResponse, error)
-	GetFolders(context.Context, *GetFoldersRequest) (*GetFoldersResponse, error)
-	RenameFolder(context.Context, *RenameFolderRequest) (*RenameFolderResponse, error)
-	DeleteFolder(context.Context, *DeleteFolderRequest) (*DeleteFolderResponse, error)
-	DeleteMessageFromFolder(context.Context, *DeleteMessageFromFolderRequest) (*DeleteMessageFromFolderResponse, error)
-	// Методы для черновиков
-	SaveDraft(context.Context, *SaveDraftRequest) (*SaveDraftResponse, error)
-	DeleteDraft(context.Context, *DeleteDraftRequest) (*DeleteDraftResponse, error)
-	SendDraft(context.Context, *SendDraftRequest) (*SendDraftResponse, error)
-	mustEmbedUnimplementedMessagesServiceServer()
+func (c *messagesServiceClient) PutSieveScript(ctx context.Context, in *PutSieveScriptRequest, opts ...grpc.CallOption) (*PutSieveScriptResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PutSieveScriptResponse)
+	err := c.cc.Invoke(ctx, MessagesService_PutSieveScript_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-// UnimplementedMessagesServiceServer must be embedded to have
-// forward compatible implementations.
-//
-// NOTE: this should be embedded by value instead of pointer to avoid a nil
-// pointer dereference when methods are called.
-type UnimplementedMessagesServiceServer struct{}
+func (c *messagesServiceClient) GetSieveScript(ctx context.Context, in *GetSieveScriptRequest, opts ...grpc.CallOption) (*GetSieveScriptResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSieveScriptResponse)
+	err := c.cc.Invoke(ctx, MessagesService_GetSieveScript_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
 
-func (UnimplementedMessagesServiceServer) Inbox(context.Context, *InboxRequest) (*InboxResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Inbox not implemented")
+func (c *messagesServiceClient) CheckSieveScript(ctx context.Context, in *CheckSieveScriptRequest, opts ...grpc.CallOption) (*CheckSieveScriptResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckSieveScriptResponse)
+	err := c.cc.Invoke(ctx, MessagesService_CheckSieveScript_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMessagesServiceServer) MessagePage(context.Context, *MessagePageRequest) (*MessagePageResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method MessagePage not implemented")
+
+func (c *messagesServiceClient) RestoreFromTrash(ctx context.Context, in *RestoreFromTrashRequest, opts ...grpc.CallOption) (*RestoreFromTrashResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreFromTrashResponse)
+	err := c.cc.Invoke(ctx, MessagesService_RestoreFromTrash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMessagesServiceServer) Reply(context.Context, *ReplyRequest) (*ReplyResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Reply not implemented")
+
+func (c *messagesServiceClient) EmptyTrash(ctx context.Context, in *EmptyTrashRequest, opts ...grpc.CallOption) (*EmptyTrashResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EmptyTrashResponse)
+	err := c.cc.Invoke(ctx, MessagesService_EmptyTrash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMessagesServiceServer) Send(context.Context, *SendRequest) (*SendResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Send not implemented")
+
+func (c *messagesServiceClient) DeletePermanently(ctx context.Context, in *DeletePermanentlyRequest, opts ...grpc.CallOption) (*DeletePermanentlyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeletePermanentlyResponse)
+	err := c.cc.Invoke(ctx, MessagesService_DeletePermanently_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMessagesServiceServer) Sent(context.Context, *SentRequest) (*SentResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Sent not implemented")
+
+func (c *messagesServiceClient) Batch(ctx context.Context, in *BatchRequest, opts ...grpc.CallOption) (*BatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchResponse)
+	err := c.cc.Invoke(ctx, MessagesService_Batch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMessagesServiceServer) MarkAsSpam(context.Context, *MarkAsSpamRequest) (*MarkAsSpamResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method MarkAsSpam not implemented")
+
+func (c *messagesServiceClient) CreateLabel(ctx context.Context, in *CreateLabelRequest, opts ...grpc.CallOption) (*CreateLabelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateLabelResponse)
+	err := c.cc.Invoke(ctx, MessagesService_CreateLabel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMessagesServiceServer) MoveToFolder(context.Context, *MoveToFolderRequest) (*MoveToFolderResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method MoveToFolder not implemented")
+
+func (c *messagesServiceClient) GetLabels(ctx context.Context, in *GetLabelsRequest, opts ...grpc.CallOption) (*GetLabelsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLabelsResponse)
+	err := c.cc.Invoke(ctx, MessagesService_GetLabels_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMessagesServiceServer) CreateFolder(context.Context, *CreateFolderRequest) (*CreateFolderResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateFolder not implemented")
+
+func (c *messagesServiceClient) UpdateLabel(ctx context.Context, in *UpdateLabelRequest, opts ...grpc.CallOption) (*UpdateLabelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateLabelResponse)
+	err := c.cc.Invoke(ctx, MessagesService_UpdateLabel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMessagesServiceServer) GetFolder(context.Context, *GetFolderRequest) (*GetFolderResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetFolder not implemented")
+
+func (c *messagesServiceClient) DeleteLabel(ctx context.Context, in *DeleteLabelRequest, opts ...grpc.CallOption) (*DeleteLabelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteLabelResponse)
+	err := c.cc.Invoke(ctx, MessagesService_DeleteLabel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMessagesServiceServer) GetFolders(context.Context, *GetFoldersRequest) (*GetFoldersResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetFolders not implemented")
+
+func (c *messagesServiceClient) ApplyLabel(ctx context.Context, in *ApplyLabelRequest, opts ...grpc.CallOption) (*ApplyLabelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApplyLabelResponse)
+	err := c.cc.Invoke(ctx, MessagesService_ApplyLabel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMessagesServiceServer) RenameFolder(context.Context, *RenameFolderRequest) (*RenameFolderResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RenameFolder not implemented")
+
+func (c *messagesServiceClient) RemoveLabel(ctx context.Context, in *RemoveLabelRequest, opts ...grpc.CallOption) (*RemoveLabelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveLabelResponse)
+	err := c.cc.Invoke(ctx, MessagesService_RemoveLabel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMessagesServiceServer) DeleteFolder(context.Context, *DeleteFolderRequest) (*DeleteFolderResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteFolder not implemented")
+
+func (c *messagesServiceClient) GetForwarding(ctx context.Context, in *GetForwardingRequest, opts ...grpc.CallOption) (*GetForwardingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetForwardingResponse)
+	err := c.cc.Invoke(ctx, MessagesService_GetForwarding_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMessagesServiceServer) DeleteMessageFromFolder(context.Context, *DeleteMessageFromFolderRequest) (*DeleteMessageFromFolderResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteMessageFromFolder not implemented")
+
+func (c *messagesServiceClient) PutForwarding(ctx context.Context, in *PutForwardingRequest, opts ...grpc.CallOption) (*PutForwardingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PutForwardingResponse)
+	err := c.cc.Invoke(ctx, MessagesService_PutForwarding_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagesServiceClient) CreateContact(ctx context.Context, in *CreateContactRequest, opts ...grpc.CallOption) (*CreateContactResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateContactResponse)
+	err := c.cc.Invoke(ctx, MessagesService_CreateContact_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagesServiceClient) GetContacts(ctx context.Context, in *GetContactsRequest, opts ...grpc.CallOption) (*GetContactsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetContactsResponse)
+	err := c.cc.Invoke(ctx, MessagesService_GetContacts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagesServiceClient) UpdateContact(ctx context.Context, in *UpdateContactRequest, opts ...grpc.CallOption) (*UpdateContactResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateContactResponse)
+	err := c.cc.Invoke(ctx, MessagesService_UpdateContact_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagesServiceClient) DeleteContact(ctx context.Context, in *DeleteContactRequest, opts ...grpc.CallOption) (*DeleteContactResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteContactResponse)
+	err := c.cc.Invoke(ctx, MessagesService_DeleteContact_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagesServiceClient) SuggestContacts(ctx context.Context, in *SuggestContactsRequest, opts ...grpc.CallOption) (*SuggestContactsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SuggestContactsResponse)
+	err := c.cc.Invoke(ctx, MessagesService_SuggestContacts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagesServiceClient) CreateContactGroup(ctx context.Context, in *CreateContactGroupRequest, opts ...grpc.CallOption) (*CreateContactGroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateContactGroupResponse)
+	err := c.cc.Invoke(ctx, MessagesService_CreateContactGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagesServiceClient) GetContactGroups(ctx context.Context, in *GetContactGroupsRequest, opts ...grpc.CallOption) (*GetContactGroupsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetContactGroupsResponse)
+	err := c.cc.Invoke(ctx, MessagesService_GetContactGroups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagesServiceClient) RenameContactGroup(ctx context.Context, in *RenameContactGroupRequest, opts ...grpc.CallOption) (*RenameContactGroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RenameContactGroupResponse)
+	err := c.cc.Invoke(ctx, MessagesService_RenameContactGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagesServiceClient) DeleteContactGroup(ctx context.Context, in *DeleteContactGroupRequest, opts ...grpc.CallOption) (*DeleteContactGroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteContactGroupResponse)
+	err := c.cc.Invoke(ctx, MessagesService_DeleteContactGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagesServiceClient) GetSenderLists(ctx context.Context, in *GetSenderListsRequest, opts ...grpc.CallOption) (*GetSenderListsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSenderListsResponse)
+	err := c.cc.Invoke(ctx, MessagesService_GetSenderLists_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagesServiceClient) AddSenderRule(ctx context.Context, in *AddSenderRuleRequest, opts ...grpc.CallOption) (*AddSenderRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddSenderRuleResponse)
+	err := c.cc.Invoke(ctx, MessagesService_AddSenderRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagesServiceClient) DeleteSenderRule(ctx context.Context, in *DeleteSenderRuleRequest, opts ...grpc.CallOption) (*DeleteSenderRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteSenderRuleResponse)
+	err := c.cc.Invoke(ctx, MessagesService_DeleteSenderRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagesServiceClient) SetBlockedSenderAction(ctx context.Context, in *SetBlockedSenderActionRequest, opts ...grpc.CallOption) (*SetBlockedSenderActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetBlockedSenderActionResponse)
+	err := c.cc.Invoke(ctx, MessagesService_SetBlockedSenderAction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagesServiceClient) BlockSender(ctx context.Context, in *BlockSenderRequest, opts ...grpc.CallOption) (*BlockSenderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BlockSenderResponse)
+	err := c.cc.Invoke(ctx, MessagesService_BlockSender_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagesServiceClient) GetAliases(ctx context.Context, in *GetAliasesRequest, opts ...grpc.CallOption) (*GetAliasesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAliasesResponse)
+	err := c.cc.Invoke(ctx, MessagesService_GetAliases_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagesServiceClient) AddAlias(ctx context.Context, in *AddAliasRequest, opts ...grpc.CallOption) (*AddAliasResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddAliasResponse)
+	err := c.cc.Invoke(ctx, MessagesService_AddAlias_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagesServiceClient) DeleteAlias(ctx context.Context, in *DeleteAliasRequest, opts ...grpc.CallOption) (*DeleteAliasResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteAliasResponse)
+	err := c.cc.Invoke(ctx, MessagesService_DeleteAlias_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagesServiceClient) GetPop3Settings(ctx context.Context, in *GetPop3SettingsRequest, opts ...grpc.CallOption) (*GetPop3SettingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPop3SettingsResponse)
+	err := c.cc.Invoke(ctx, MessagesService_GetPop3Settings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagesServiceClient) PutPop3Settings(ctx context.Context, in *PutPop3SettingsRequest, opts ...grpc.CallOption) (*PutPop3SettingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PutPop3SettingsResponse)
+	err := c.cc.Invoke(ctx, MessagesService_PutPop3Settings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagesServiceClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MailEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MessagesService_ServiceDesc.Streams[0], MessagesService_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeRequest, MailEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MessagesService_SubscribeClient = grpc.ServerStreamingClient[MailEvent]
+
+func (c *messagesServiceClient) GetMailState(ctx context.Context, in *GetMailStateRequest, opts ...grpc.CallOption) (*GetMailStateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMailStateResponse)
+	err := c.cc.Invoke(ctx, MessagesService_GetMailState_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagesServiceClient) GetMailChanges(ctx context.Context, in *GetMailChangesRequest, opts ...grpc.CallOption) (*GetMailChangesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMailChangesResponse)
+	err := c.cc.Invoke(ctx, MessagesService_GetMailChanges_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *messagesServiceClient) GetThreads(ctx context.Context, in *GetThreadsRequest, opts ...grpc.CallOption) (*GetThreadsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetThreadsResponse)
+	err := c.cc.Invoke(ctx, MessagesService_GetThreads_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MessagesServiceServer is the server API for MessagesService service.
+// All implementations must embed UnimplementedMessagesServiceServer
+// for forward compatibility.
+type MessagesServiceServer interface {
+	// Основные методы для сообщений
+	Inbox(context.Context, *InboxRequest) (*InboxResponse, error)
+	MessagePage(context.Context, *MessagePageRequest) (*MessagePageResponse, error)
+	Reply(context.Context, *ReplyRequest) (*ReplyResponse, error)
+	Send(context.Context, *SendRequest) (*SendResponse, error)
+	Forward(context.Context, *ForwardRequest) (*ForwardResponse, error)
+	Sent(context.Context, *SentRequest) (*SentResponse, error)
+	// Методы для работы с сообщениями
+	MarkAsSpam(context.Context, *MarkAsSpamRequest) (*MarkAsSpamResponse, error)
+	MarkAsNotSpam(context.Context, *MarkAsNotSpamRequest) (*MarkAsNotSpamResponse, error)
+	MoveToFolder(context.Context, *MoveToFolderRequest) (*MoveToFolderResponse, error)
+	SetFlags(context.Context, *SetFlagsRequest) (*SetFlagsResponse, error)
+	// Методы для папок
+	CreateFolder(context.Context, *CreateFolderRequest) (*CreateFolderResponse, error)
+	GetFolder(context.Context, *GetFolderRequest) (*GetFolderResponse, error)
+	GetFolders(context.Context, *GetFoldersRequest) (*GetFoldersResponse, error)
+	RenameFolder(context.Context, *RenameFolderRequest) (*RenameFolderResponse, error)
+	DeleteFolder(context.Context, *DeleteFolderRequest) (*DeleteFolderResponse, error)
+	DeleteMessageFromFolder(context.Context, *DeleteMessageFromFolderRequest) (*DeleteMessageFromFolderResponse, error)
+	MoveFolder(context.Context, *MoveFolderRequest) (*MoveFolderResponse, error)
+	GetFolderTree(context.Context, *GetFolderTreeRequest) (*GetFolderTreeResponse, error)
+	// Методы для черновиков
+	SaveDraft(context.Context, *SaveDraftRequest) (*SaveDraftResponse, error)
+	DeleteDraft(context.Context, *DeleteDraftRequest) (*DeleteDraftResponse, error)
+	SendDraft(context.Context, *SendDraftRequest) (*SendDraftResponse, error)
+	// Методы для sieve-фильтров
+	PutSieveScript(context.Context, *PutSieveScriptRequest) (*PutSieveScriptResponse, error)
+	GetSieveScript(context.Context, *GetSieveScriptRequest) (*GetSieveScriptResponse, error)
+	CheckSieveScript(context.Context, *CheckSieveScriptRequest) (*CheckSieveScriptResponse, error)
+	// Методы для корзины
+	RestoreFromTrash(context.Context, *RestoreFromTrashRequest) (*RestoreFromTrashResponse, error)
+	EmptyTrash(context.Context, *EmptyTrashRequest) (*EmptyTrashResponse, error)
+	DeletePermanently(context.Context, *DeletePermanentlyRequest) (*DeletePermanentlyResponse, error)
+	// Пакетные операции
+	Batch(context.Context, *BatchRequest) (*BatchResponse, error)
+	// Методы для меток
+	CreateLabel(context.Context, *CreateLabelRequest) (*CreateLabelResponse, error)
+	GetLabels(context.Context, *GetLabelsRequest) (*GetLabelsResponse, error)
+	UpdateLabel(context.Context, *UpdateLabelRequest) (*UpdateLabelResponse, error)
+	DeleteLabel(context.Context, *DeleteLabelRequest) (*DeleteLabelResponse, error)
+	ApplyLabel(context.Context, *ApplyLabelRequest) (*ApplyLabelResponse, error)
+	RemoveLabel(context.Context, *RemoveLabelRequest) (*RemoveLabelResponse, error)
+	// Методы для автопересылки
+	GetForwarding(context.Context, *GetForwardingRequest) (*GetForwardingResponse, error)
+	PutForwarding(context.Context, *PutForwardingRequest) (*PutForwardingResponse, error)
+	// Методы для адресной книги
+	CreateContact(context.Context, *CreateContactRequest) (*CreateContactResponse, error)
+	GetContacts(context.Context, *GetContactsRequest) (*GetContactsResponse, error)
+	UpdateContact(context.Context, *UpdateContactRequest) (*UpdateContactResponse, error)
+	DeleteContact(context.Context, *DeleteContactRequest) (*DeleteContactResponse, error)
+	SuggestContacts(context.Context, *SuggestContactsRequest) (*SuggestContactsResponse, error)
+	CreateContactGroup(context.Context, *CreateContactGroupRequest) (*CreateContactGroupResponse, error)
+	GetContactGroups(context.Context, *GetContactGroupsRequest) (*GetContactGroupsResponse, error)
+	RenameContactGroup(context.Context, *RenameContactGroupRequest) (*RenameContactGroupResponse, error)
+	DeleteContactGroup(context.Context, *DeleteContactGroupRequest) (*DeleteContactGroupResponse, error)
+	// Методы для списков блокировки и разрешенных отправителей
+	GetSenderLists(context.Context, *GetSenderListsRequest) (*GetSenderListsResponse, error)
+	AddSenderRule(context.Context, *AddSenderRuleRequest) (*AddSenderRuleResponse, error)
+	DeleteSenderRule(context.Context, *DeleteSenderRuleRequest) (*DeleteSenderRuleResponse, error)
+	SetBlockedSenderAction(context.Context, *SetBlockedSenderActionRequest) (*SetBlockedSenderActionResponse, error)
+	BlockSender(context.Context, *BlockSenderRequest) (*BlockSenderResponse, error)
+	// Дополнительные адреса профиля
+	GetAliases(context.Context, *GetAliasesRequest) (*GetAliasesResponse, error)
+	AddAlias(context.Context, *AddAliasRequest) (*AddAliasResponse, error)
+	DeleteAlias(context.Context, *DeleteAliasRequest) (*DeleteAliasResponse, error)
+	// Настройки доступа по POP3
+	GetPop3Settings(context.Context, *GetPop3SettingsRequest) (*GetPop3SettingsResponse, error)
+	PutPop3Settings(context.Context, *PutPop3SettingsRequest) (*PutPop3SettingsResponse, error)
+	// События почтового ящика в реальном времени
+	Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[MailEvent]) error
+	// Синхронизация клиентов JMAP
+	GetMailState(context.Context, *GetMailStateRequest) (*GetMailStateResponse, error)
+	GetMailChanges(context.Context, *GetMailChangesRequest) (*GetMailChangesResponse, error)
+	GetThreads(context.Context, *GetThreadsRequest) (*GetThreadsResponse, error)
+	mustEmbedUnimplementedMessagesServiceServer()
+}
+
+// UnimplementedMessagesServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMessagesServiceServer struct{}
+
+func (UnimplementedMessagesServiceServer) Inbox(context.Context, *InboxRequest) (*InboxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Inbox not implemented")
+}
+func (UnimplementedMessagesServiceServer) MessagePage(context.Context, *MessagePageRequest) (*MessagePageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MessagePage not implemented")
+}
+func (UnimplementedMessagesServiceServer) Reply(context.Context, *ReplyRequest) (*ReplyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reply not implemented")
+}
+func (UnimplementedMessagesServiceServer) Send(context.Context, *SendRequest) (*SendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Send not implemented")
+}
+func (UnimplementedMessagesServiceServer) Forward(context.Context, *ForwardRequest) (*ForwardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Forward not implemented")
+}
+func (UnimplementedMessagesServiceServer) Sent(context.Context, *SentRequest) (*SentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Sent not implemented")
+}
+func (UnimplementedMessagesServiceServer) MarkAsSpam(context.Context, *MarkAsSpamRequest) (*MarkAsSpamResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MarkAsSpam not implemented")
+}
+func (UnimplementedMessagesServiceServer) MarkAsNotSpam(context.Context, *MarkAsNotSpamRequest) (*MarkAsNotSpamResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MarkAsNotSpam not implemented")
+}
+func (UnimplementedMessagesServiceServer) MoveToFolder(context.Context, *MoveToFolderRequest) (*MoveToFolderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveToFolder not implemented")
+}
+func (UnimplementedMessagesServiceServer) SetFlags(context.Context, *SetFlagsRequest) (*SetFlagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetFlags not implemented")
+}
+func (UnimplementedMessagesServiceServer) CreateFolder(context.Context, *CreateFolderRequest) (*CreateFolderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateFolder not implemented")
+}
+func (UnimplementedMessagesServiceServer) GetFolder(context.Context, *GetFolderRequest) (*GetFolderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFolder not implemented")
+}
+func (UnimplementedMessagesServiceServer) GetFolders(context.Context, *GetFoldersRequest) (*GetFoldersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFolders not implemented")
+}
+func (UnimplementedMessagesServiceServer) RenameFolder(context.Context, *RenameFolderRequest) (*RenameFolderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameFolder not implemented")
+}
+func (UnimplementedMessagesServiceServer) DeleteFolder(context.Context, *DeleteFolderRequest) (*DeleteFolderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFolder not implemented")
+}
+func (UnimplementedMessagesServiceServer) DeleteMessageFromFolder(context.Context, *DeleteMessageFromFolderRequest) (*DeleteMessageFromFolderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMessageFromFolder not implemented")
+}
+func (UnimplementedMessagesServiceServer) MoveFolder(context.Context, *MoveFolderRequest) (*MoveFolderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveFolder not implemented")
+}
+func (UnimplementedMessagesServiceServer) GetFolderTree(context.Context, *GetFolderTreeRequest) (*GetFolderTreeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFolderTree not implemented")
+}
+func (UnimplementedMessagesServiceServer) SaveDraft(context.Context, *SaveDraftRequest) (*SaveDraftResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveDraft not implemented")
+}
+func (UnimplementedMessagesServiceServer) DeleteDraft(context.Context, *DeleteDraftRequest) (*DeleteDraftResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDraft not implemented")
+}
+func (UnimplementedMessagesServiceServer) SendDraft(context.Context, *SendDraftRequest) (*SendDraftResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendDraft not implemented")
+}
+func (UnimplementedMessagesServiceServer) PutSieveScript(context.Context, *PutSieveScriptRequest) (*PutSieveScriptResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutSieveScript not implemented")
+}
+func (UnimplementedMessagesServiceServer) GetSieveScript(context.Context, *GetSieveScriptRequest) (*GetSieveScriptResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSieveScript not implemented")
+}
+func (UnimplementedMessagesServiceServer) CheckSieveScript(context.Context, *CheckSieveScriptRequest) (*CheckSieveScriptResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckSieveScript not implemented")
+}
+func (UnimplementedMessagesServiceServer) RestoreFromTrash(context.Context, *RestoreFromTrashRequest) (*RestoreFromTrashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreFromTrash not implemented")
+}
+func (UnimplementedMessagesServiceServer) EmptyTrash(context.Context, *EmptyTrashRequest) (*EmptyTrashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EmptyTrash not implemented")
+}
+func (UnimplementedMessagesServiceServer) DeletePermanently(context.Context, *DeletePermanentlyRequest) (*DeletePermanentlyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePermanently not implemented")
+}
+func (UnimplementedMessagesServiceServer) Batch(context.Context, *BatchRequest) (*BatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Batch not implemented")
+}
+func (UnimplementedMessagesServiceServer) CreateLabel(context.Context, *CreateLabelRequest) (*CreateLabelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateLabel not implemented")
+}
+func (UnimplementedMessagesServiceServer) GetLabels(context.Context, *GetLabelsRequest) (*GetLabelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLabels not implemented")
+}
+func (UnimplementedMessagesServiceServer) UpdateLabel(context.Context, *UpdateLabelRequest) (*UpdateLabelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateLabel not implemented")
+}
+func (UnimplementedMessagesServiceServer) DeleteLabel(context.Context, *DeleteLabelRequest) (*DeleteLabelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteLabel not implemented")
+}
+func (UnimplementedMessagesServiceServer) ApplyLabel(context.Context, *ApplyLabelRequest) (*ApplyLabelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyLabel not implemented")
+}
+func (UnimplementedMessagesServiceServer) RemoveLabel(context.Context, *RemoveLabelRequest) (*RemoveLabelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveLabel not implemented")
+}
+func (UnimplementedMessagesServiceServer) GetForwarding(context.Context, *GetForwardingRequest) (*GetForwardingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetForwarding not implemented")
+}
+func (UnimplementedMessagesServiceServer) PutForwarding(context.Context, *PutForwardingRequest) (*PutForwardingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutForwarding not implemented")
+}
+func (UnimplementedMessagesServiceServer) CreateContact(context.Context, *CreateContactRequest) (*CreateContactResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateContact not implemented")
+}
+func (UnimplementedMessagesServiceServer) GetContacts(context.Context, *GetContactsRequest) (*GetContactsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetContacts not implemented")
+}
+func (UnimplementedMessagesServiceServer) UpdateContact(context.Context, *UpdateContactRequest) (*UpdateContactResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateContact not implemented")
+}
+func (UnimplementedMessagesServiceServer) DeleteContact(context.Context, *DeleteContactRequest) (*DeleteContactResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteContact not implemented")
+}
+func (UnimplementedMessagesServiceServer) SuggestContacts(context.Context, *SuggestContactsRequest) (*SuggestContactsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuggestContacts not implemented")
+}
+func (UnimplementedMessagesServiceServer) CreateContactGroup(context.Context, *CreateContactGroupRequest) (*CreateContactGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateContactGroup not implemented")
+}
+func (UnimplementedMessagesServiceServer) GetContactGroups(context.Context, *GetContactGroupsRequest) (*GetContactGroupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetContactGroups not implemented")
+}
+func (UnimplementedMessagesServiceServer) RenameContactGroup(context.Context, *RenameContactGroupRequest) (*RenameContactGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameContactGroup not implemented")
+}
+func (UnimplementedMessagesServiceServer) DeleteContactGroup(context.Context, *DeleteContactGroupRequest) (*DeleteContactGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteContactGroup not implemented")
+}
+func (UnimplementedMessagesServiceServer) GetSenderLists(context.Context, *GetSenderListsRequest) (*GetSenderListsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSenderLists not implemented")
+}
+func (UnimplementedMessagesServiceServer) AddSenderRule(context.Context, *AddSenderRuleRequest) (*AddSenderRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddSenderRule not implemented")
+}
+func (UnimplementedMessagesServiceServer) DeleteSenderRule(context.Context, *DeleteSenderRuleRequest) (*DeleteSenderRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSenderRule not implemented")
+}
+func (UnimplementedMessagesServiceServer) SetBlockedSenderAction(context.Context, *SetBlockedSenderActionRequest) (*SetBlockedSenderActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetBlockedSenderAction not implemented")
+}
+func (UnimplementedMessagesServiceServer) BlockSender(context.Context, *BlockSenderRequest) (*BlockSenderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BlockSender not implemented")
+}
+func (UnimplementedMessagesServiceServer) GetAliases(context.Context, *GetAliasesRequest) (*GetAliasesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAliases not implemented")
+}
+func (UnimplementedMessagesServiceServer) AddAlias(context.Context, *AddAliasRequest) (*AddAliasResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddAlias not implemented")
+}
+func (UnimplementedMessagesServiceServer) DeleteAlias(context.Context, *DeleteAliasRequest) (*DeleteAliasResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAlias not implemented")
+}
+func (UnimplementedMessagesServiceServer) GetPop3Settings(context.Context, *GetPop3SettingsRequest) (*GetPop3SettingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPop3Settings not implemented")
+}
+func (UnimplementedMessagesServiceServer) PutPop3Settings(context.Context, *PutPop3SettingsRequest) (*PutPop3SettingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutPop3Settings not implemented")
+}
+func (UnimplementedMessagesServiceServer) Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[MailEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedMessagesServiceServer) GetMailState(context.Context, *GetMailStateRequest) (*GetMailStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMailState not implemented")
+}
+func (UnimplementedMessagesServiceServer) GetMailChanges(context.Context, *GetMailChangesRequest) (*GetMailChangesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMailChanges not implemented")
+}
+func (UnimplementedMessagesServiceServer) GetThreads(context.Context, *GetThreadsRequest) (*GetThreadsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetThreads not implemented")
+}
+func (UnimplementedMessagesServiceServer) mustEmbedUnimplementedMessagesServiceServer() {}
+func (UnimplementedMessagesServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeMessagesServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MessagesServiceServer will
+// result in compilation errors.
+type UnsafeMessagesServiceServer interface {
+	mustEmbedUnimplementedMessagesServiceServer()
+}
+
+func RegisterMessagesServiceServer(s grpc.ServiceRegistrar, srv MessagesServiceServer) {
+	// If the following call pancis, it indicates UnimplementedMessagesServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MessagesService_ServiceDesc, srv)
+}
+
+func _MessagesService_Inbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InboxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).Inbox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_Inbox_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).Inbox(ctx, req.(*InboxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_MessagePage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MessagePageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).MessagePage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_MessagePage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).MessagePage(ctx, req.(*MessagePageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_Reply_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).Reply(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_Reply_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).Reply(ctx, req.(*ReplyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_Send_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).Send(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_Send_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).Send(ctx, req.(*SendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_Forward_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForwardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).Forward(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_Forward_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).Forward(ctx, req.(*ForwardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_Sent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).Sent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_Sent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).Sent(ctx, req.(*SentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_MarkAsSpam_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MarkAsSpamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).MarkAsSpam(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_MarkAsSpam_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).MarkAsSpam(ctx, req.(*MarkAsSpamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_MarkAsNotSpam_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MarkAsNotSpamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).MarkAsNotSpam(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_MarkAsNotSpam_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).MarkAsNotSpam(ctx, req.(*MarkAsNotSpamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_MoveToFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveToFolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).MoveToFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_MoveToFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).MoveToFolder(ctx, req.(*MoveToFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_SetFlags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetFlagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).SetFlags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_SetFlags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).SetFlags(ctx, req.(*SetFlagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_CreateFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateFolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).CreateFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_CreateFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).CreateFolder(ctx, req.(*CreateFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_GetFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).GetFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_GetFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).GetFolder(ctx, req.(*GetFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_GetFolders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFoldersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).GetFolders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_GetFolders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).GetFolders(ctx, req.(*GetFoldersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_RenameFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameFolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).RenameFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_RenameFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).RenameFolder(ctx, req.(*RenameFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_DeleteFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).DeleteFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_DeleteFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).DeleteFolder(ctx, req.(*DeleteFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_DeleteMessageFromFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMessageFromFolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).DeleteMessageFromFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_DeleteMessageFromFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).DeleteMessageFromFolder(ctx, req.(*DeleteMessageFromFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_MoveFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveFolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).MoveFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_MoveFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).MoveFolder(ctx, req.(*MoveFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_GetFolderTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFolderTreeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).GetFolderTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_GetFolderTree_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).GetFolderTree(ctx, req.(*GetFolderTreeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_SaveDraft_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveDraftRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).SaveDraft(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_SaveDraft_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).SaveDraft(ctx, req.(*SaveDraftRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_DeleteDraft_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteDraftRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).DeleteDraft(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_DeleteDraft_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).DeleteDraft(ctx, req.(*DeleteDraftRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_SendDraft_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendDraftRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).SendDraft(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_SendDraft_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).SendDraft(ctx, req.(*SendDraftRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_PutSieveScript_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutSieveScriptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).PutSieveScript(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_PutSieveScript_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).PutSieveScript(ctx, req.(*PutSieveScriptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_GetSieveScript_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSieveScriptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).GetSieveScript(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_GetSieveScript_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).GetSieveScript(ctx, req.(*GetSieveScriptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_CheckSieveScript_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckSieveScriptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).CheckSieveScript(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_CheckSieveScript_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).CheckSieveScript(ctx, req.(*CheckSieveScriptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_RestoreFromTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreFromTrashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).RestoreFromTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_RestoreFromTrash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).RestoreFromTrash(ctx, req.(*RestoreFromTrashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_EmptyTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyTrashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).EmptyTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_EmptyTrash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).EmptyTrash(ctx, req.(*EmptyTrashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_DeletePermanently_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePermanentlyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).DeletePermanently(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_DeletePermanently_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).DeletePermanently(ctx, req.(*DeletePermanentlyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_Batch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).Batch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_Batch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).Batch(ctx, req.(*BatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_CreateLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateLabelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).CreateLabel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_CreateLabel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).CreateLabel(ctx, req.(*CreateLabelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_GetLabels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLabelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).GetLabels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_GetLabels_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).GetLabels(ctx, req.(*GetLabelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_UpdateLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateLabelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).UpdateLabel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_UpdateLabel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).UpdateLabel(ctx, req.(*UpdateLabelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_DeleteLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteLabelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).DeleteLabel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_DeleteLabel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).DeleteLabel(ctx, req.(*DeleteLabelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_ApplyLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyLabelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).ApplyLabel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_ApplyLabel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).ApplyLabel(ctx, req.(*ApplyLabelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_RemoveLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveLabelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).RemoveLabel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_RemoveLabel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).RemoveLabel(ctx, req.(*RemoveLabelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_GetForwarding_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetForwardingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).GetForwarding(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_GetForwarding_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).GetForwarding(ctx, req.(*GetForwardingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_PutForwarding_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutForwardingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).PutForwarding(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_PutForwarding_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).PutForwarding(ctx, req.(*PutForwardingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MessagesService_CreateContact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateContactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).CreateContact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_CreateContact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).CreateContact(ctx, req.(*CreateContactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedMessagesServiceServer) SaveDraft(context.Context, *SaveDraftRequest) (*SaveDraftResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SaveDraft not implemented")
+
+func _MessagesService_GetContacts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetContactsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).GetContacts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_GetContacts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).GetContacts(ctx, req.(*GetContactsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedMessagesServiceServer) DeleteDraft(context.Context, *DeleteDraftRequest) (*DeleteDraftResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteDraft not implemented")
+
+func _MessagesService_UpdateContact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateContactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).UpdateContact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_UpdateContact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).UpdateContact(ctx, req.(*UpdateContactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedMessagesServiceServer) SendDraft(context.Context, *SendDraftRequest) (*SendDraftResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SendDraft not implemented")
+
+func _MessagesService_DeleteContact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteContactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).DeleteContact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_DeleteContact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).DeleteContact(ctx, req.(*DeleteContactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedMessagesServiceServer) mustEmbedUnimplementedMessagesServiceServer() {}
-func (UnimplementedMessagesServiceServer) testEmbeddedByValue()                         {}
 
-// UnsafeMessagesServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to MessagesServiceServer will
-// result in compilation errors.
-type UnsafeMessagesServiceServer interface {
-	mustEmbedUnimplementedMessagesServiceServer()
+func _MessagesService_SuggestContacts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestContactsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).SuggestContacts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_SuggestContacts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).SuggestContacts(ctx, req.(*SuggestContactsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterMessagesServiceServer(s grpc.ServiceRegistrar, srv MessagesServiceServer) {
-	// If the following call pancis, it indicates UnimplementedMessagesServiceServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
+func _MessagesService_CreateContactGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateContactGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	s.RegisterService(&MessagesService_ServiceDesc, srv)
+	if interceptor == nil {
+		return srv.(MessagesServiceServer).CreateContactGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MessagesService_CreateContactGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MessagesServiceServer).CreateContactGroup(ctx, req.(*CreateContactGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _MessagesService_Inbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(InboxRequest)
+func _MessagesService_GetContactGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetContactGroupsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MessagesServiceServer).Inbox(ctx, in)
+		return srv.(MessagesServiceServer).GetContactGroups(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MessagesService_Inbox_FullMethodName,
+		FullMethod: MessagesService_GetContactGroups_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MessagesServiceServer).Inbox(ctx, req.(*InboxRequest))
+		return srv.(MessagesServiceServer).GetContactGroups(ctx, req.(*GetContactGroupsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MessagesService_MessagePage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(MessagePageRequest)
+func _MessagesService_RenameContactGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameContactGroupRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MessagesServiceServer).MessagePage(ctx, in)
+		return srv.(MessagesServiceServer).RenameContactGroup(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MessagesService_MessagePage_FullMethodName,
+		FullMethod: MessagesService_RenameContactGroup_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MessagesServiceServer).MessagePage(ctx, req.(*MessagePageRequest))
+		return srv.(MessagesServiceServer).RenameContactGroup(ctx, req.(*RenameContactGroupRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MessagesService_Reply_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ReplyRequest)
+func _MessagesService_DeleteContactGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteContactGroupRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MessagesServiceServer).Reply(ctx, in)
+		return srv.(MessagesServiceServer).DeleteContactGroup(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MessagesService_Reply_FullMethodName,
+		FullMethod: MessagesService_DeleteContactGroup_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MessagesServiceServer).Reply(ctx, req.(*ReplyRequest))
+		return srv.(MessagesServiceServer).DeleteContactGroup(ctx, req.(*DeleteContactGroupRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MessagesService_Send_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendRequest)
+func _MessagesService_GetSenderLists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSenderListsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MessagesServiceServer).Send(ctx, in)
+		return srv.(MessagesServiceServer).GetSenderLists(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MessagesService_Send_FullMethodName,
+		FullMethod: MessagesService_GetSenderLists_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MessagesServiceServer).Send(ctx, req.(*SendRequest))
+		return srv.(MessagesServiceServer).GetSenderLists(ctx, req.(*GetSenderListsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MessagesService_Sent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SentRequest)
+func _MessagesService_AddSenderRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddSenderRuleRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MessagesServiceServer).Sent(ctx, in)
+		return srv.(MessagesServiceServer).AddSenderRule(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MessagesService_Sent_FullMethodName,
+		FullMethod: MessagesService_AddSenderRule_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MessagesServiceServer).Sent(ctx, req.(*SentRequest))
+		return srv.(MessagesServiceServer).AddSenderRule(ctx, req.(*AddSenderRuleRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MessagesService_MarkAsSpam_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(MarkAsSpamRequest)
+func _MessagesService_DeleteSenderRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSenderRuleRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MessagesServiceServer).MarkAsSpam(ctx, in)
+		return srv.(MessagesServiceServer).DeleteSenderRule(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MessagesService_MarkAsSpam_FullMethodName,
+		FullMethod: MessagesService_DeleteSenderRule_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MessagesServiceServer).MarkAsSpam(ctx, req.(*MarkAsSpamRequest))
+		return srv.(MessagesServiceServer).DeleteSenderRule(ctx, req.(*DeleteSenderRuleRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MessagesService_MoveToFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(MoveToFolderRequest)
+func _MessagesService_SetBlockedSenderAction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetBlockedSenderActionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MessagesServiceServer).MoveToFolder(ctx, in)
+		return srv.(MessagesServiceServer).SetBlockedSenderAction(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MessagesService_MoveToFolder_FullMethodName,
+		FullMethod: MessagesService_SetBlockedSenderAction_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MessagesServiceServer).MoveToFolder(ctx, req.(*MoveToFolderRequest))
+		return srv.(MessagesServiceServer).SetBlockedSenderAction(ctx, req.(*SetBlockedSenderActionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MessagesService_CreateFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateFolderRequest)
+func _MessagesService_BlockSender_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockSenderRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MessagesServiceServer).CreateFolder(ctx, in)
+		return srv.(MessagesServiceServer).BlockSender(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MessagesService_CreateFolder_FullMethodName,
+		FullMethod: MessagesService_BlockSender_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MessagesServiceServer).CreateFolder(ctx, req.(*CreateFolderRequest))
+		return srv.(MessagesServiceServer).BlockSender(ctx, req.(*BlockSenderRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MessagesService_GetFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetFolderRequest)
+func _MessagesService_GetAliases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAliasesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MessagesServiceServer).GetFolder(ctx, in)
+		return srv.(MessagesServiceServer).GetAliases(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MessagesService_GetFolder_FullMethodName,
+		FullMethod: MessagesService_GetAliases_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MessagesServiceServer).GetFolder(ctx, req.(*GetFolderRequest))
+		return srv.(MessagesServiceServer).GetAliases(ctx, req.(*GetAliasesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MessagesService_GetFolders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetFoldersRequest)
+func _MessagesService_AddAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddAliasRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MessagesServiceServer).GetFolders(ctx, in)
+		return srv.(MessagesServiceServer).AddAlias(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MessagesService_GetFolders_FullMethodName,
+		FullMethod: MessagesService_AddAlias_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MessagesServiceServer).GetFolders(ctx, req.(*GetFoldersRequest))
+		return srv.(MessagesServiceServer).AddAlias(ctx, req.(*AddAliasRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MessagesService_RenameFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RenameFolderRequest)
+func _MessagesService_DeleteAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAliasRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MessagesServiceServer).RenameFolder(ctx, in)
+		return srv.(MessagesServiceServer).DeleteAlias(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MessagesService_RenameFolder_FullMethodName,
+		FullMethod: MessagesService_DeleteAlias_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MessagesServiceServer).RenameFolder(ctx, req.(*RenameFolderRequest))
+		return srv.(MessagesServiceServer).DeleteAlias(ctx, req.(*DeleteAliasRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MessagesService_DeleteFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteFolderRequest)
+func _MessagesService_GetPop3Settings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPop3SettingsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MessagesServiceServer).DeleteFolder(ctx, in)
+		return srv.(MessagesServiceServer).GetPop3Settings(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MessagesService_DeleteFolder_FullMethodName,
+		FullMethod: MessagesService_GetPop3Settings_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MessagesServiceServer).DeleteFolder(ctx, req.(*DeleteFolderRequest))
+		return srv.(MessagesServiceServer).GetPop3Settings(ctx, req.(*GetPop3SettingsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MessagesService_DeleteMessageFromFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteMessageFromFolderRequest)
+func _MessagesService_PutPop3Settings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutPop3SettingsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MessagesServiceServer).DeleteMessageFromFolder(ctx, in)
+		return srv.(MessagesServiceServer).PutPop3Settings(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MessagesService_DeleteMessageFromFolder_FullMethodName,
+		FullMethod: MessagesService_PutPop3Settings_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MessagesServiceServer).DeleteMessageFromFolder(ctx, req.(*DeleteMessageFromFolderRequest))
+		return srv.(MessagesServiceServer).PutPop3Settings(ctx, req.(*PutPop3SettingsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MessagesService_SaveDraft_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SaveDraftRequest)
+func _MessagesService_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MessagesServiceServer).Subscribe(m, &grpc.GenericServerStream[SubscribeRequest, MailEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MessagesService_SubscribeServer = grpc.ServerStreamingServer[MailEvent]
+
+func _MessagesService_GetMailState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMailStateRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MessagesServiceServer).SaveDraft(ctx, in)
+		return srv.(MessagesServiceServer).GetMailState(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MessagesService_SaveDraft_FullMethodName,
+		FullMethod: MessagesService_GetMailState_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MessagesServiceServer).SaveDraft(ctx, req.(*SaveDraftRequest))
+		return srv.(MessagesServiceServer).GetMailState(ctx, req.(*GetMailStateRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MessagesService_DeleteDraft_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteDraftRequest)
+func _MessagesService_GetMailChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMailChangesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MessagesServiceServer).DeleteDraft(ctx, in)
+		return srv.(MessagesServiceServer).GetMailChanges(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MessagesService_DeleteDraft_FullMethodName,
+		FullMethod: MessagesService_GetMailChanges_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MessagesServiceServer).DeleteDraft(ctx, req.(*DeleteDraftRequest))
+		return srv.(MessagesServiceServer).GetMailChanges(ctx, req.(*GetMailChangesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MessagesService_SendDraft_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendDraftRequest)
+func _MessagesService_GetThreads_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetThreadsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MessagesServiceServer).SendDraft(ctx, in)
+		return srv.(MessagesServiceServer).GetThreads(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MessagesService_SendDraft_FullMethodName,
+		FullMethod: MessagesService_GetThreads_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MessagesServiceServer).SendDraft(ctx, req.(*SendDraftRequest))
+		return srv.(MessagesServiceServer).GetThreads(ctx, req.(*GetThreadsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -645,6 +2131,10 @@ var MessagesService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Send",
 			Handler:    _MessagesService_Send_Handler,
 		},
+		{
+			MethodName: "Forward",
+			Handler:    _MessagesService_Forward_Handler,
+		},
 		{
 			MethodName: "Sent",
 			Handler:    _MessagesService_Sent_Handler,
@@ -653,10 +2143,18 @@ var MessagesService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "MarkAsSpam",
 			Handler:    _MessagesService_MarkAsSpam_Handler,
 		},
+		{
+			MethodName: "MarkAsNotSpam",
+			Handler:    _MessagesService_MarkAsNotSpam_Handler,
+		},
 		{
 			MethodName: "MoveToFolder",
 			Handler:    _MessagesService_MoveToFolder_Handler,
 		},
+		{
+			MethodName: "SetFlags",
+			Handler:    _MessagesService_SetFlags_Handler,
+		},
 		{
 			MethodName: "CreateFolder",
 			Handler:    _MessagesService_CreateFolder_Handler,
@@ -681,6 +2179,14 @@ var MessagesService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteMessageFromFolder",
 			Handler:    _MessagesService_DeleteMessageFromFolder_Handler,
 		},
+		{
+			MethodName: "MoveFolder",
+			Handler:    _MessagesService_MoveFolder_Handler,
+		},
+		{
+			MethodName: "GetFolderTree",
+			Handler:    _MessagesService_GetFolderTree_Handler,
+		},
 		{
 			MethodName: "SaveDraft",
 			Handler:    _MessagesService_SaveDraft_Handler,
@@ -693,7 +2199,161 @@ var MessagesService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendDraft",
 			Handler:    _MessagesService_SendDraft_Handler,
 		},
+		{
+			MethodName: "PutSieveScript",
+			Handler:    _MessagesService_PutSieveScript_Handler,
+		},
+		{
+			MethodName: "GetSieveScript",
+			Handler:    _MessagesService_GetSieveScript_Handler,
+		},
+		{
+			MethodName: "CheckSieveScript",
+			Handler:    _MessagesService_CheckSieveScript_Handler,
+		},
+		{
+			MethodName: "RestoreFromTrash",
+			Handler:    _MessagesService_RestoreFromTrash_Handler,
+		},
+		{
+			MethodName: "EmptyTrash",
+			Handler:    _MessagesService_EmptyTrash_Handler,
+		},
+		{
+			MethodName: "DeletePermanently",
+			Handler:    _MessagesService_DeletePermanently_Handler,
+		},
+		{
+			MethodName: "Batch",
+			Handler:    _MessagesService_Batch_Handler,
+		},
+		{
+			MethodName: "CreateLabel",
+			Handler:    _MessagesService_CreateLabel_Handler,
+		},
+		{
+			MethodName: "GetLabels",
+			Handler:    _MessagesService_GetLabels_Handler,
+		},
+		{
+			MethodName: "UpdateLabel",
+			Handler:    _MessagesService_UpdateLabel_Handler,
+		},
+		{
+			MethodName: "DeleteLabel",
+			Handler:    _MessagesService_DeleteLabel_Handler,
+		},
+		{
+			MethodName: "ApplyLabel",
+			Handler:    _MessagesService_ApplyLabel_Handler,
+		},
+		{
+			MethodName: "RemoveLabel",
+			Handler:    _MessagesService_RemoveLabel_Handler,
+		},
+		{
+			MethodName: "GetForwarding",
+			Handler:    _MessagesService_GetForwarding_Handler,
+		},
+		{
+			MethodName: "PutForwarding",
+			Handler:    _MessagesService_PutForwarding_Handler,
+		},
+		{
+			MethodName: "CreateContact",
+			Handler:    _MessagesService_CreateContact_Handler,
+		},
+		{
+			MethodName: "GetContacts",
+			Handler:    _MessagesService_GetContacts_Handler,
+		},
+		{
+			MethodName: "UpdateContact",
+			Handler:    _MessagesService_UpdateContact_Handler,
+		},
+		{
+			MethodName: "DeleteContact",
+			Handler:    _MessagesService_DeleteContact_Handler,
+		},
+		{
+			MethodName: "SuggestContacts",
+			Handler:    _MessagesService_SuggestContacts_Handler,
+		},
+		{
+			MethodName: "CreateContactGroup",
+			Handler:    _MessagesService_CreateContactGroup_Handler,
+		},
+		{
+			MethodName: "GetContactGroups",
+			Handler:    _MessagesService_GetContactGroups_Handler,
+		},
+		{
+			MethodName: "RenameContactGroup",
+			Handler:    _MessagesService_RenameContactGroup_Handler,
+		},
+		{
+			MethodName: "DeleteContactGroup",
+			Handler:    _MessagesService_DeleteContactGroup_Handler,
+		},
+		{
+			MethodName: "GetSenderLists",
+			Handler:    _MessagesService_GetSenderLists_Handler,
+		},
+		{
+			MethodName: "AddSenderRule",
+			Handler:    _MessagesService_AddSenderRule_Handler,
+		},
+		{
+			MethodName: "DeleteSenderRule",
+			Handler:    _MessagesService_DeleteSenderRule_Handler,
+		},
+		{
+			MethodName: "SetBlockedSenderAction",
+			Handler:    _MessagesService_SetBlockedSenderAction_Handler,
+		},
+		{
+			MethodName: "BlockSender",
+			Handler:    _MessagesService_BlockSender_Handler,
+		},
+		{
+			MethodName: "GetAliases",
+			Handler:    _MessagesService_GetAliases_Handler,
+		},
+		{
+			MethodName: "AddAlias",
+			Handler:    _MessagesService_AddAlias_Handler,
+		},
+		{
+			MethodName: "DeleteAlias",
+			Handler:    _MessagesService_DeleteAlias_Handler,
+		},
+		{
+			MethodName: "GetPop3Settings",
+			Handler:    _MessagesService_GetPop3Settings_Handler,
+		},
+		{
+			MethodName: "PutPop3Settings",
+			Handler:    _MessagesService_PutPop3Settings_Handler,
+		},
+		{
+			MethodName: "GetMailState",
+			Handler:    _MessagesService_GetMailState_Handler,
+		},
+		{
+			MethodName: "GetMailChanges",
+			Handler:    _MessagesService_GetMailChanges_Handler,
+		},
+		{
+			MethodName: "GetThreads",
+			Handler:    _MessagesService_GetThreads_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _MessagesService_Subscribe_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "messages.proto",
 }
@@ -0,0 +1,345 @@
+package pop3_server
+
+import (
+	"2025_2_a4code/internal/domain"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// listPageSize - по сколько писем читается папка при открытии ящика
+const listPageSize = 500
+
+var crlf = []byte("\r\n")
+
+// maildrop - ящик сессии: письма папки "Входящие" на момент входа. Номера писем
+// не меняются до конца сессии (RFC 1939, 5)
+type maildrop struct {
+	settings domain.POP3Settings
+	messages []*message
+}
+
+type message struct {
+	id int64
+	// size - размер собранного письма, -1 - письмо еще не собиралось
+	size    int
+	deleted bool
+}
+
+// openMaildrop читает письма папки "Входящие" без скрытых от POP3, старые письма первыми
+func (s *session) openMaildrop(ctx context.Context, profileID int64) (*maildrop, error) {
+	settings, err := s.server.pop3UCase.GetSettings(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+	hidden, err := s.server.pop3UCase.HiddenMessages(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+	inboxID, err := s.server.messageUCase.GetFolderByType(ctx, profileID, string(domain.FolderInbox))
+	if err != nil {
+		return nil, err
+	}
+
+	drop := &maildrop{settings: settings}
+	page := domain.MessagePage{Sort: domain.SortDateAsc, Limit: listPageSize}
+	for {
+		batch, err := s.server.messageUCase.GetFolderMessagesWithKeysetPagination(ctx, profileID, inboxID, 0, page)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range batch {
+			id, err := strconv.ParseInt(m.ID, 10, 64)
+			if err != nil || hidden[id] {
+				continue
+			}
+			drop.messages = append(drop.messages, &message{id: id, size: -1})
+		}
+		if len(batch) < listPageSize {
+			break
+		}
+		key := batch[len(batch)-1].Key()
+		page.After = &key
+	}
+
+	sort.Slice(drop.messages, func(i, j int) bool { return drop.messages[i].id < drop.messages[j].id })
+	return drop, nil
+}
+
+func (s *session) handleStat() string {
+	count, size, err := s.dropSize()
+	if err != nil {
+		return s.fail("failed to count messages size", err)
+	}
+	return s.ok(fmt.Sprintf("%d %d", count, size))
+}
+
+// handleList отвечает на LIST (номер и размер письма) и UIDL (номер и уникальный идентификатор)
+func (s *session) handleList(name string, args []string) string {
+	if len(args) > 1 {
+		return s.bad("Syntax: " + name + " [msg]")
+	}
+	info := func(m *message) (string, error) {
+		if name == "UIDL" {
+			return strconv.FormatInt(m.id, 10), nil
+		}
+		size, err := s.messageSize(m)
+		return strconv.Itoa(size), err
+	}
+
+	if len(args) == 1 {
+		n, m, errText := s.findMessage(args[0])
+		if m == nil {
+			return s.err(errText)
+		}
+		text, err := info(m)
+		if err != nil {
+			return s.fail("failed to render message", err)
+		}
+		return s.ok(fmt.Sprintf("%d %s", n, text))
+	}
+
+	var lines []string
+	for i, m := range s.drop.messages {
+		if m.deleted {
+			continue
+		}
+		text, err := info(m)
+		if err != nil {
+			return s.fail("failed to render message", err)
+		}
+		lines = append(lines, fmt.Sprintf("%d %s", i+1, text))
+	}
+
+	if name == "UIDL" {
+		s.begin("Unique-id listing follows")
+	} else {
+		count, size, err := s.dropSize()
+		if err != nil {
+			return s.fail("failed to count messages size", err)
+		}
+		s.begin(fmt.Sprintf("%d messages (%d octets)", count, size))
+	}
+	for _, line := range lines {
+		s.write(line + "\r\n")
+	}
+	return s.end()
+}
+
+func (s *session) handleRetr(args []string) string {
+	if len(args) != 1 {
+		return s.bad("Syntax: RETR msg")
+	}
+	_, m, errText := s.findMessage(args[0])
+	if m == nil {
+		return s.err(errText)
+	}
+
+	raw, err := s.render(m)
+	if errors.Is(err, domain.ErrMessageNotFound) {
+		return s.err("Message is no longer available")
+	}
+	if err != nil {
+		return s.fail("failed to render message", err)
+	}
+
+	s.begin(fmt.Sprintf("%d octets", len(raw)))
+	s.writeData(raw)
+	return s.end()
+}
+
+// handleTop отдает заголовок письма и первые lines строк тела
+func (s *session) handleTop(args []string) string {
+	if len(args) != 2 {
+		return s.bad("Syntax: TOP msg lines")
+	}
+	lines, err := strconv.Atoi(args[1])
+	if err != nil || lines < 0 {
+		return s.bad("Invalid number of lines")
+	}
+	_, m, errText := s.findMessage(args[0])
+	if m == nil {
+		return s.err(errText)
+	}
+
+	raw, err := s.render(m)
+	if errors.Is(err, domain.ErrMessageNotFound) {
+		return s.err("Message is no longer available")
+	}
+	if err != nil {
+		return s.fail("failed to render message", err)
+	}
+
+	s.begin("Top of message follows")
+	s.writeData(top(raw, lines))
+	return s.end()
+}
+
+func (s *session) handleDele(args []string) string {
+	if len(args) != 1 {
+		return s.bad("Syntax: DELE msg")
+	}
+	n, m, errText := s.findMessage(args[0])
+	if m == nil {
+		return s.err(errText)
+	}
+	m.deleted = true
+	return s.ok(fmt.Sprintf("Message %d deleted", n))
+}
+
+func (s *session) handleRset() string {
+	for _, m := range s.drop.messages {
+		m.deleted = false
+	}
+	count, size, err := s.dropSize()
+	if err != nil {
+		return s.fail("failed to count messages size", err)
+	}
+	return s.ok(fmt.Sprintf("Maildrop has %d messages (%d octets)", count, size))
+}
+
+// update удаляет отмеченные письма: переносит в корзину или скрывает от POP3, как задано
+// в настройках пользователя. Возвращает число оставшихся писем
+func (s *session) update(ctx context.Context) (int, error) {
+	var deleted []int64
+	for _, m := range s.drop.messages {
+		if m.deleted {
+			deleted = append(deleted, m.id)
+		}
+	}
+	left := len(s.drop.messages) - len(deleted)
+	if len(deleted) == 0 {
+		return left, nil
+	}
+
+	if s.drop.settings.DeleteAction == domain.POP3DeleteHide {
+		return left, s.server.pop3UCase.HideMessages(ctx, s.profileID, deleted)
+	}
+
+	trashID, err := s.server.messageUCase.GetFolderByType(ctx, s.profileID, string(domain.FolderTrash))
+	if err != nil {
+		return left, err
+	}
+	for _, id := range deleted {
+		// Письмо могли удалить в другом клиенте, пока сессия была открыта
+		err := s.server.messageUCase.MoveToFolder(ctx, s.profileID, id, trashID)
+		if err != nil && !errors.Is(err, domain.ErrMessageNotFound) {
+			return left, err
+		}
+	}
+	return left, nil
+}
+
+// findMessage возвращает письмо по номеру или текст ошибки, если письма нет или оно удалено
+func (s *session) findMessage(arg string) (int, *message, string) {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 1 || n > len(s.drop.messages) {
+		return 0, nil, "No such message"
+	}
+	m := s.drop.messages[n-1]
+	if m.deleted {
+		return 0, nil, fmt.Sprintf("Message %d already deleted", n)
+	}
+	return n, m, ""
+}
+
+// dropSize возвращает число и суммарный размер неудаленных писем
+func (s *session) dropSize() (int, int, error) {
+	count, total := 0, 0
+	for _, m := range s.drop.messages {
+		if m.deleted {
+			continue
+		}
+		size, err := s.messageSize(m)
+		if err != nil {
+			return 0, 0, err
+		}
+		count++
+		total += size
+	}
+	return count, total, nil
+}
+
+// messageSize возвращает размер письма, собирая его при первом обращении. Клиенты,
+// которые забирают только новые письма по UIDL, размеры остальных писем не запрашивают.
+// Письмо, удаленное в другом клиенте, имеет нулевой размер
+func (s *session) messageSize(m *message) (int, error) {
+	if m.size >= 0 {
+		return m.size, nil
+	}
+	_, err := s.render(m)
+	if errors.Is(err, domain.ErrMessageNotFound) {
+		m.size = 0
+		return 0, nil
+	}
+	return m.size, err
+}
+
+// render собирает письмо в формате RFC 5322 с CRLF в конце каждой строки
+func (s *session) render(m *message) ([]byte, error) {
+	ctx, cancel := s.context()
+	defer cancel()
+
+	raw, err := s.server.messageUCase.ExportMessage(ctx, s.profileID, m.id)
+	if err != nil {
+		return nil, err
+	}
+	raw = normalizeLines(raw)
+	m.size = len(raw)
+	return raw, nil
+}
+
+// normalizeLines заменяет одиночные LF на CRLF и завершает последнюю строку переводом строки
+func normalizeLines(raw []byte) []byte {
+	var b bytes.Buffer
+	b.Grow(len(raw) + 2)
+	for len(raw) > 0 {
+		line, rest, found := bytes.Cut(raw, []byte("\n"))
+		b.Write(bytes.TrimSuffix(line, []byte("\r")))
+		b.Write(crlf)
+		if !found {
+			break
+		}
+		raw = rest
+	}
+	return b.Bytes()
+}
+
+// top оставляет заголовок, пустую строку после него и первые lines строк тела
+func top(raw []byte, lines int) []byte {
+	headerEnd := bytes.Index(raw, []byte("\r\n\r\n"))
+	switch {
+	case bytes.HasPrefix(raw, crlf):
+		headerEnd = 2
+	case headerEnd < 0:
+		return raw
+	default:
+		headerEnd += 4
+	}
+
+	end := headerEnd
+	for i := 0; i < lines && end < len(raw); i++ {
+		next := bytes.Index(raw[end:], crlf)
+		if next < 0 {
+			end = len(raw)
+			break
+		}
+		end += next + 2
+	}
+	return raw[:end]
+}
+
+// writeData отправляет строки многострочного ответа, удваивая точку в начале строки (RFC 1939, 3)
+func (s *session) writeData(data []byte) {
+	for len(data) > 0 {
+		line, rest, _ := bytes.Cut(data, crlf)
+		if len(line) > 0 && line[0] == '.' {
+			s.write(".")
+		}
+		s.write(string(line) + "\r\n")
+		data = rest
+	}
+}
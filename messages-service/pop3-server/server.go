@@ -0,0 +1,211 @@
+package pop3_server
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"crypto/tls"
+	"errors"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout - время бездействия до автоматического выхода (RFC 1939, 3: не меньше 10 минут)
+const DefaultTimeout = 10 * time.Minute
+
+var ErrServerClosed = errors.New("pop3: server closed")
+
+// MessageUsecase - письма папки "Входящие" пользователя
+type MessageUsecase interface {
+	GetFolderByType(ctx context.Context, profileID int64, folderType string) (int64, error)
+	GetFolderMessagesWithKeysetPagination(ctx context.Context, profileID, folderID, labelID int64, page domain.MessagePage) ([]domain.Message, error)
+	ExportMessage(ctx context.Context, profileID, messageID int64) ([]byte, error)
+	MoveToFolder(ctx context.Context, profileID, messageID, folderID int64) error
+}
+
+// POP3Usecase - настройки POP3, вход по APOP и письма, скрытые от POP3
+type POP3Usecase interface {
+	AuthenticateAPOP(ctx context.Context, login, timestamp, digest string) (int64, error)
+	GetSettings(ctx context.Context, profileID int64) (domain.POP3Settings, error)
+	HiddenMessages(ctx context.Context, profileID int64) (map[int64]bool, error)
+	HideMessages(ctx context.Context, profileID int64, messageIDs []int64) error
+}
+
+// Authenticator проверяет логин и пароль почтового клиента и возвращает профиль пользователя.
+// remoteIP - адрес клиента, он показывается пользователю в списке паролей приложений
+type Authenticator interface {
+	Authenticate(ctx context.Context, login, password, remoteIP string) (int64, error)
+}
+
+type Config struct {
+	// Hostname - имя сервера в приветствии и метке времени APOP
+	Hostname string
+	// TLSConfig включает STLS и ListenAndServeTLS, nil - соединения только без шифрования
+	TLSConfig *tls.Config
+	// AllowInsecureAuth разрешает USER/PASS без TLS, пароль при этом передается открытым текстом
+	AllowInsecureAuth bool
+	Timeout           time.Duration
+}
+
+// Server - POP3-сервер (RFC 1939) для старых почтовых клиентов. Отдает письма папки
+// "Входящие", уникальный идентификатор письма - его идентификатор в базе
+type Server struct {
+	cfg          Config
+	messageUCase MessageUsecase
+	pop3UCase    POP3Usecase
+	auth         Authenticator
+	log          *slog.Logger
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	// locked - профили, ящик которых открыт в одной из сессий (RFC 1939, 8)
+	locked map[int64]struct{}
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func New(messageUCase MessageUsecase, pop3UCase POP3Usecase, auth Authenticator, cfg Config, log *slog.Logger) *Server {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if log == nil {
+		log = slog.Default()
+	}
+
+	return &Server{
+		cfg:          cfg,
+		messageUCase: messageUCase,
+		pop3UCase:    pop3UCase,
+		auth:         auth,
+		log:          log,
+		listeners:    make(map[net.Listener]struct{}),
+		conns:        make(map[net.Conn]struct{}),
+		locked:       make(map[int64]struct{}),
+	}
+}
+
+func (s *Server) ListenAndServe(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+// ListenAndServeTLS принимает соединения, зашифрованные с самого начала (порт 995)
+func (s *Server) ListenAndServeTLS(addr string) error {
+	if s.cfg.TLSConfig == nil {
+		return errors.New("pop3: TLS is not configured")
+	}
+	listener, err := tls.Listen("tcp", addr, s.cfg.TLSConfig)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+// Serve принимает соединения, пока listener не будет закрыт. Каждое соединение
+// обслуживается в отдельной горутине
+func (s *Server) Serve(listener net.Listener) error {
+	if !s.track(listener, true) {
+		listener.Close()
+		return ErrServerClosed
+	}
+	defer s.track(listener, false)
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return ErrServerClosed
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				time.Sleep(100 * time.Millisecond)
+				continue
+			}
+			return err
+		}
+
+		if !s.trackConn(conn, true) {
+			conn.Close()
+			return ErrServerClosed
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer s.trackConn(conn, false)
+			newSession(s, conn).serve()
+		}()
+	}
+}
+
+// Close закрывает все listener'ы и соединения и ждет завершения сессий
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for listener := range s.listeners {
+		listener.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return nil
+}
+
+// lock занимает ящик профиля, false - ящик уже открыт в другой сессии
+func (s *Server) lock(profileID int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.locked[profileID]; ok {
+		return false
+	}
+	s.locked[profileID] = struct{}{}
+	return true
+}
+
+func (s *Server) unlock(profileID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.locked, profileID)
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+func (s *Server) track(listener net.Listener, add bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !add {
+		delete(s.listeners, listener)
+		return true
+	}
+	if s.closed {
+		return false
+	}
+	s.listeners[listener] = struct{}{}
+	return true
+}
+
+func (s *Server) trackConn(conn net.Conn, add bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !add {
+		delete(s.conns, conn)
+		conn.Close()
+		return true
+	}
+	if s.closed {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	return true
+}
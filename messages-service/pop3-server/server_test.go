@@ -0,0 +1,403 @@
+package pop3_server
+
+import (
+	"2025_2_a4code/internal/domain"
+	pop3Ucase "2025_2_a4code/internal/usecase/pop3"
+	profileUcase "2025_2_a4code/internal/usecase/profile"
+	"bufio"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	inboxID = 1
+	trashID = 2
+)
+
+type fakeUsecase struct {
+	mu      sync.Mutex
+	inbox   []int64
+	raw     map[int64][]byte
+	moved   map[int64]int64
+	exports int
+}
+
+func (f *fakeUsecase) GetFolderByType(ctx context.Context, profileID int64, folderType string) (int64, error) {
+	switch domain.FolderType(folderType) {
+	case domain.FolderInbox:
+		return inboxID, nil
+	case domain.FolderTrash:
+		return trashID, nil
+	}
+	return 0, domain.ErrFolderNotFound
+}
+
+func (f *fakeUsecase) GetFolderMessagesWithKeysetPagination(ctx context.Context, profileID, folderID, labelID int64, page domain.MessagePage) ([]domain.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var messages []domain.Message
+	for _, id := range f.inbox {
+		messages = append(messages, domain.Message{ID: strconv.FormatInt(id, 10)})
+	}
+	return messages, nil
+}
+
+func (f *fakeUsecase) ExportMessage(ctx context.Context, profileID, messageID int64) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.exports++
+	raw, ok := f.raw[messageID]
+	if !ok {
+		return nil, domain.ErrMessageNotFound
+	}
+	return raw, nil
+}
+
+func (f *fakeUsecase) MoveToFolder(ctx context.Context, profileID, messageID, folderID int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.moved[messageID] = folderID
+	f.inbox = slices.DeleteFunc(f.inbox, func(id int64) bool { return id == messageID })
+	return nil
+}
+
+func (f *fakeUsecase) movedTo(messageID int64) (int64, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	folderID, ok := f.moved[messageID]
+	return folderID, ok
+}
+
+type fakePOP3 struct {
+	mu       sync.Mutex
+	settings domain.POP3Settings
+	secret   string
+	hidden   map[int64]bool
+}
+
+func (f *fakePOP3) AuthenticateAPOP(ctx context.Context, login, timestamp, digest string) (int64, error) {
+	sum := md5.Sum([]byte(timestamp + f.secret))
+	if login != "alexey" || f.secret == "" || hex.EncodeToString(sum[:]) != digest {
+		return 0, pop3Ucase.ErrAuthFailed
+	}
+	return 1, nil
+}
+
+func (f *fakePOP3) GetSettings(ctx context.Context, profileID int64) (domain.POP3Settings, error) {
+	return f.settings, nil
+}
+
+func (f *fakePOP3) HiddenMessages(ctx context.Context, profileID int64) (map[int64]bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return maps.Clone(f.hidden), nil
+}
+
+func (f *fakePOP3) HideMessages(ctx context.Context, profileID int64, messageIDs []int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, id := range messageIDs {
+		f.hidden[id] = true
+	}
+	return nil
+}
+
+func (f *fakePOP3) isHidden(messageID int64) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.hidden[messageID]
+}
+
+type fakeAuth map[string]string
+
+func (f fakeAuth) Authenticate(ctx context.Context, login, password, remoteIP string) (int64, error) {
+	if remoteIP != "127.0.0.1" {
+		return 0, fmt.Errorf("unexpected remote ip %s", remoteIP)
+	}
+	login = strings.TrimSuffix(login, "@flintmail.ru")
+	expected, ok := f[login]
+	if !ok {
+		return 0, profileUcase.ErrUserNotFound
+	}
+	if expected != password {
+		return 0, profileUcase.ErrWrongPassword
+	}
+	return 1, nil
+}
+
+const (
+	firstMessage  = "From: maria@flintmail.ru\r\nSubject: Hello\r\n\r\nFirst line\r\n.hidden dot\r\nThird line\r\n"
+	secondMessage = "From: ivan@example.com\nSubject: Report\n\nBody with bare LF"
+)
+
+func newUsecases() (*fakeUsecase, *fakePOP3) {
+	uc := &fakeUsecase{
+		inbox: []int64{5, 3, 9},
+		raw: map[int64][]byte{
+			3: []byte(firstMessage),
+			5: []byte(secondMessage),
+			9: []byte("Subject: Hidden\r\n\r\nHidden from POP3\r\n"),
+		},
+		moved: make(map[int64]int64),
+	}
+	pop3 := &fakePOP3{
+		settings: domain.POP3Settings{DeleteAction: domain.POP3DeleteToTrash},
+		secret:   "tanstaaf",
+		hidden:   map[int64]bool{9: true},
+	}
+	return uc, pop3
+}
+
+func startServer(t *testing.T, uc *fakeUsecase, pop3 *fakePOP3, cfg Config) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	cfg.Hostname = "pop.flintmail.ru"
+	server := New(uc, pop3, fakeAuth{"alexey": "secret"}, cfg, nil)
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	return listener.Addr().String()
+}
+
+type client struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func dial(t *testing.T, addr string) (*client, string) {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	c := &client{t: t, conn: conn, reader: bufio.NewReader(conn)}
+	return c, c.readLine()
+}
+
+func (c *client) readLine() string {
+	c.t.Helper()
+	line, err := c.reader.ReadString('\n')
+	require.NoError(c.t, err)
+	return strings.TrimRight(line, "\r\n")
+}
+
+func (c *client) cmd(line string) string {
+	c.t.Helper()
+	_, err := fmt.Fprintf(c.conn, "%s\r\n", line)
+	require.NoError(c.t, err)
+	return c.readLine()
+}
+
+// multi отправляет команду с многострочным ответом и возвращает строки без точки в конце
+func (c *client) multi(line string) (string, []string) {
+	c.t.Helper()
+	status := c.cmd(line)
+	require.True(c.t, strings.HasPrefix(status, "+OK"), status)
+
+	var lines []string
+	for {
+		line := c.readLine()
+		if line == "." {
+			return status, lines
+		}
+		lines = append(lines, line)
+	}
+}
+
+func (c *client) login() {
+	c.t.Helper()
+	require.True(c.t, strings.HasPrefix(c.cmd("USER alexey"), "+OK"))
+	status := c.cmd("PASS secret")
+	require.True(c.t, strings.HasPrefix(status, "+OK"), status)
+}
+
+func TestServer_Login(t *testing.T) {
+	uc, pop3 := newUsecases()
+	addr := startServer(t, uc, pop3, Config{AllowInsecureAuth: true})
+
+	c, greeting := dial(t, addr)
+	assert.Regexp(t, `^\+OK pop\.flintmail\.ru POP3 ready <[0-9a-f]+\.\d+@pop\.flintmail\.ru>$`, greeting)
+
+	_, caps := c.multi("CAPA")
+	assert.Equal(t, []string{"TOP", "UIDL", "RESP-CODES", "AUTH-RESP-CODE", "PIPELINING", "USER"}, caps)
+
+	assert.Equal(t, "-ERR Send USER first", c.cmd("PASS secret"))
+	assert.Equal(t, "-ERR Unknown command or log in first", c.cmd("STAT"))
+	c.cmd("USER alexey")
+	assert.Equal(t, "-ERR [AUTH] Invalid credentials", c.cmd("PASS wrong"))
+	c.login()
+	assert.Equal(t, "+OK", c.cmd("NOOP"))
+
+	// Ящик открыт только в одной сессии
+	other, _ := dial(t, addr)
+	other.cmd("USER alexey@flintmail.ru")
+	assert.Equal(t, "-ERR [IN-USE] Mailbox is already open in another session", other.cmd("PASS secret"))
+
+	assert.Equal(t, "+OK pop.flintmail.ru POP3 server signing off (2 messages left)", c.cmd("QUIT"))
+	require.Eventually(t, func() bool {
+		other.cmd("USER alexey")
+		return strings.HasPrefix(other.cmd("PASS secret"), "+OK")
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestServer_LoginRequiresTLS(t *testing.T) {
+	uc, pop3 := newUsecases()
+	addr := startServer(t, uc, pop3, Config{})
+
+	c, _ := dial(t, addr)
+	_, caps := c.multi("CAPA")
+	assert.NotContains(t, caps, "USER")
+	assert.Equal(t, "-ERR Use STLS first", c.cmd("USER alexey"))
+	assert.Equal(t, "-ERR TLS not available", c.cmd("STLS"))
+}
+
+func TestServer_APOP(t *testing.T) {
+	uc, pop3 := newUsecases()
+	addr := startServer(t, uc, pop3, Config{})
+
+	c, greeting := dial(t, addr)
+	timestamp := greeting[strings.LastIndexByte(greeting, '<'):]
+	sum := md5.Sum([]byte(timestamp + "tanstaaf"))
+
+	assert.Equal(t, "-ERR [AUTH] Invalid credentials", c.cmd("APOP alexey 00000000000000000000000000000000"))
+	assert.Equal(t, "-ERR Syntax: APOP name digest", c.cmd("APOP alexey"))
+	assert.Equal(t, "+OK Mailbox ready, 2 messages", c.cmd("APOP alexey "+hex.EncodeToString(sum[:])))
+}
+
+func TestServer_AuthFailuresCloseConnection(t *testing.T) {
+	uc, pop3 := newUsecases()
+	addr := startServer(t, uc, pop3, Config{AllowInsecureAuth: true})
+
+	c, _ := dial(t, addr)
+	for i := 0; i < maxAuthFailures; i++ {
+		c.cmd("USER alexey")
+		assert.Equal(t, "-ERR [AUTH] Invalid credentials", c.cmd("PASS wrong"))
+	}
+	_, err := c.reader.ReadString('\n')
+	assert.Error(t, err)
+}
+
+func TestServer_StatListRetr(t *testing.T) {
+	uc, pop3 := newUsecases()
+	addr := startServer(t, uc, pop3, Config{AllowInsecureAuth: true})
+
+	c, _ := dial(t, addr)
+	c.login()
+
+	// Письма упорядочены по идентификатору, скрытое от POP3 письмо не показывается
+	_, uids := c.multi("UIDL")
+	assert.Equal(t, []string{"1 3", "2 5"}, uids)
+	assert.Equal(t, 0, uc.exports, "UIDL must not render messages")
+	assert.Equal(t, "+OK 2 5", c.cmd("UIDL 2"))
+
+	// Одиночные LF заменяются на CRLF, последняя строка завершается переводом строки
+	firstSize, secondSize := len(firstMessage), len(secondMessage)+5
+	assert.Equal(t, fmt.Sprintf("+OK 2 %d", firstSize+secondSize), c.cmd("STAT"))
+	status, sizes := c.multi("LIST")
+	assert.Equal(t, fmt.Sprintf("+OK 2 messages (%d octets)", firstSize+secondSize), status)
+	assert.Equal(t, []string{fmt.Sprintf("1 %d", firstSize), fmt.Sprintf("2 %d", secondSize)}, sizes)
+	assert.Equal(t, "-ERR No such message", c.cmd("LIST 3"))
+
+	status, lines := c.multi("RETR 1")
+	assert.Equal(t, fmt.Sprintf("+OK %d octets", firstSize), status)
+	assert.Equal(t, []string{
+		"From: maria@flintmail.ru", "Subject: Hello", "", "First line", "..hidden dot", "Third line",
+	}, lines)
+
+	_, lines = c.multi("TOP 2 0")
+	assert.Equal(t, []string{"From: ivan@example.com", "Subject: Report", ""}, lines)
+	_, lines = c.multi("TOP 1 2")
+	assert.Equal(t, []string{"From: maria@flintmail.ru", "Subject: Hello", "", "First line", "..hidden dot"}, lines)
+	assert.Equal(t, "-ERR Invalid number of lines", c.cmd("TOP 1 -1"))
+}
+
+func TestServer_DeleteToTrash(t *testing.T) {
+	uc, pop3 := newUsecases()
+	addr := startServer(t, uc, pop3, Config{AllowInsecureAuth: true})
+
+	// Без QUIT отмеченные письма не удаляются
+	c, _ := dial(t, addr)
+	c.login()
+	assert.Equal(t, "+OK Message 1 deleted", c.cmd("DELE 1"))
+	c.conn.Close()
+
+	c, _ = dial(t, addr)
+	require.Eventually(t, func() bool {
+		c.cmd("USER alexey")
+		return strings.HasPrefix(c.cmd("PASS secret"), "+OK")
+	}, time.Second, 10*time.Millisecond)
+	_, ok := uc.movedTo(3)
+	assert.False(t, ok)
+
+	assert.Equal(t, "+OK Message 1 deleted", c.cmd("DELE 1"))
+	assert.Equal(t, "-ERR Message 1 already deleted", c.cmd("RETR 1"))
+	assert.Equal(t, fmt.Sprintf("+OK 1 %d", len(secondMessage)+5), c.cmd("STAT"))
+	_, uids := c.multi("UIDL")
+	assert.Equal(t, []string{"2 5"}, uids)
+
+	assert.True(t, strings.HasPrefix(c.cmd("RSET"), "+OK Maildrop has 2 messages"))
+	c.cmd("DELE 2")
+	assert.Equal(t, "+OK pop.flintmail.ru POP3 server signing off (1 messages left)", c.cmd("QUIT"))
+
+	folderID, ok := uc.movedTo(5)
+	assert.True(t, ok)
+	assert.Equal(t, int64(trashID), folderID)
+	_, ok = uc.movedTo(3)
+	assert.False(t, ok)
+	assert.False(t, pop3.isHidden(5))
+}
+
+func TestServer_DeleteHides(t *testing.T) {
+	uc, pop3 := newUsecases()
+	pop3.settings.DeleteAction = domain.POP3DeleteHide
+	addr := startServer(t, uc, pop3, Config{AllowInsecureAuth: true})
+
+	c, _ := dial(t, addr)
+	c.login()
+	c.cmd("DELE 1")
+	assert.True(t, strings.HasPrefix(c.cmd("QUIT"), "+OK"))
+
+	assert.True(t, pop3.isHidden(3))
+	_, ok := uc.movedTo(3)
+	assert.False(t, ok, "hidden message must stay in inbox")
+
+	c, _ = dial(t, addr)
+	require.Eventually(t, func() bool {
+		c.cmd("USER alexey")
+		return strings.HasPrefix(c.cmd("PASS secret"), "+OK")
+	}, time.Second, 10*time.Millisecond)
+	_, uids := c.multi("UIDL")
+	assert.Equal(t, []string{"1 5"}, uids)
+}
+
+func TestServer_Pipelining(t *testing.T) {
+	uc, pop3 := newUsecases()
+	addr := startServer(t, uc, pop3, Config{AllowInsecureAuth: true})
+
+	c, _ := dial(t, addr)
+	_, err := fmt.Fprint(c.conn, "USER alexey\r\nPASS secret\r\nUIDL 1\r\nNOOP\r\n")
+	require.NoError(t, err)
+
+	assert.Equal(t, "+OK Send your password", c.readLine())
+	assert.Equal(t, "+OK Mailbox ready, 2 messages", c.readLine())
+	assert.Equal(t, "+OK 1 3", c.readLine())
+	assert.Equal(t, "+OK", c.readLine())
+}
@@ -0,0 +1,406 @@
+package pop3_server
+
+import (
+	pop3Ucase "2025_2_a4code/internal/usecase/pop3"
+	profileUcase "2025_2_a4code/internal/usecase/profile"
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	// maxLineLength - ограничение длины строки команды (RFC 2449, 4: не больше 255 байт)
+	maxLineLength = 512
+	// maxErrors - после стольких неизвестных или ошибочных команд подряд соединение закрывается
+	maxErrors = 10
+	// maxAuthFailures - после стольких неудачных попыток входа соединение закрывается
+	maxAuthFailures = 3
+	// commandTimeout - ограничение времени выполнения одной команды
+	commandTimeout = time.Minute
+)
+
+// statusBad - ответ -ERR на неизвестную или неверно записанную команду
+const statusBad = "BAD"
+
+var errLineTooLong = errors.New("line too long")
+
+// session - состояние одного POP3-соединения
+type session struct {
+	server *Server
+	conn   net.Conn
+	reader *bufio.Reader
+	writer *bufio.Writer
+	log    *slog.Logger
+
+	tls bool
+	// timestamp - метка времени из приветствия, по ней клиент считает дайджест APOP
+	timestamp    string
+	user         string
+	authFailures int
+	// profileID - профиль вошедшего пользователя, 0 - состояние AUTHORIZATION
+	profileID int64
+	drop      *maildrop
+}
+
+func newSession(server *Server, conn net.Conn) *session {
+	s := &session{
+		server:    server,
+		log:       server.log.With(slog.String("remote", conn.RemoteAddr().String())),
+		timestamp: newTimestamp(server.cfg.Hostname),
+	}
+	_, s.tls = conn.(*tls.Conn)
+	s.setConn(conn)
+	return s
+}
+
+// newTimestamp возвращает уникальную для соединения метку в форме msg-id (RFC 1939, 7)
+func newTimestamp(hostname string) string {
+	random := make([]byte, 8)
+	rand.Read(random)
+	return fmt.Sprintf("<%s.%d@%s>", hex.EncodeToString(random), time.Now().Unix(), hostname)
+}
+
+func (s *session) setConn(conn net.Conn) {
+	s.conn = conn
+	s.reader = bufio.NewReaderSize(conn, maxLineLength+2)
+	s.writer = bufio.NewWriter(conn)
+}
+
+// remoteIP возвращает адрес клиента без порта
+func (s *session) remoteIP() string {
+	host, _, err := net.SplitHostPort(s.conn.RemoteAddr().String())
+	if err != nil {
+		return s.conn.RemoteAddr().String()
+	}
+	return host
+}
+
+func (s *session) serve() {
+	defer func() {
+		if s.profileID != 0 {
+			s.server.unlock(s.profileID)
+		}
+	}()
+
+	s.ok(fmt.Sprintf("%s POP3 ready %s", s.server.cfg.Hostname, s.timestamp))
+	s.flush()
+
+	errorsInRow := 0
+	for {
+		line, err := s.readLine()
+		status, quit := "", false
+		switch {
+		case errors.Is(err, errLineTooLong):
+			status = s.bad("Line too long")
+		case err != nil:
+			// Сессия, закрытая без QUIT, не удаляет письма (RFC 1939, 6)
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				s.log.Debug("autologout, idle for too long")
+			}
+			return
+		default:
+			status, quit = s.handle(line)
+		}
+		if quit {
+			return
+		}
+
+		if status != statusBad {
+			errorsInRow = 0
+			continue
+		}
+		errorsInRow++
+		if errorsInRow >= maxErrors {
+			s.err("Too many errors, closing connection")
+			s.flush()
+			return
+		}
+	}
+}
+
+// handle выполняет команду и возвращает статус ответа и признак завершения сессии
+func (s *session) handle(line string) (string, bool) {
+	name, arg, _ := strings.Cut(line, " ")
+	name = strings.ToUpper(name)
+	var args []string
+	if arg != "" {
+		args = strings.Split(arg, " ")
+	}
+
+	switch name {
+	case "CAPA":
+		return s.handleCapa(), false
+	case "QUIT":
+		return s.handleQuit()
+	}
+
+	if s.profileID == 0 {
+		switch name {
+		case "STLS":
+			return s.handleStartTLS()
+		case "USER":
+			return s.handleUser(arg), false
+		case "PASS":
+			return s.handlePass(arg)
+		case "APOP":
+			return s.handleAPOP(args)
+		}
+		return s.bad("Unknown command or log in first"), false
+	}
+
+	switch name {
+	case "STAT":
+		return s.handleStat(), false
+	case "LIST", "UIDL":
+		return s.handleList(name, args), false
+	case "RETR":
+		return s.handleRetr(args), false
+	case "TOP":
+		return s.handleTop(args), false
+	case "DELE":
+		return s.handleDele(args), false
+	case "RSET":
+		return s.handleRset(), false
+	case "NOOP":
+		return s.ok(""), false
+	}
+	return s.bad("Unknown command"), false
+}
+
+// handleCapa перечисляет возможности сервера (RFC 2449)
+func (s *session) handleCapa() string {
+	caps := []string{"TOP", "UIDL", "RESP-CODES", "AUTH-RESP-CODE", "PIPELINING"}
+	if s.profileID == 0 {
+		if s.authAllowed() {
+			caps = append(caps, "USER")
+		}
+		if s.server.cfg.TLSConfig != nil && !s.tls {
+			caps = append(caps, "STLS")
+		}
+	}
+
+	s.begin("Capability list follows")
+	for _, capability := range caps {
+		s.write(capability + "\r\n")
+	}
+	return s.end()
+}
+
+// authAllowed запрещает передавать пароль открытым текстом без явного разрешения.
+// APOP пароль не передает и разрешен всегда
+func (s *session) authAllowed() bool {
+	return s.tls || s.server.cfg.AllowInsecureAuth
+}
+
+func (s *session) handleStartTLS() (string, bool) {
+	if s.tls {
+		return s.err("TLS already active"), false
+	}
+	if s.server.cfg.TLSConfig == nil {
+		return s.err("TLS not available"), false
+	}
+
+	s.ok("Begin TLS negotiation now")
+	s.flush()
+	tlsConn := tls.Server(s.conn, s.server.cfg.TLSConfig)
+	s.conn.SetDeadline(time.Now().Add(s.server.cfg.Timeout))
+	if err := tlsConn.Handshake(); err != nil {
+		s.log.Warn("TLS handshake failed: " + err.Error())
+		return "-ERR", true
+	}
+
+	// Имя пользователя, переданное без шифрования, забывается (RFC 2595, 4)
+	s.setConn(tlsConn)
+	s.tls = true
+	s.user = ""
+	return "+OK", false
+}
+
+func (s *session) handleUser(name string) string {
+	if !s.authAllowed() {
+		return s.err("Use STLS first")
+	}
+	if name == "" {
+		return s.bad("Syntax: USER name")
+	}
+	s.user = name
+	return s.ok("Send your password")
+}
+
+func (s *session) handlePass(password string) (string, bool) {
+	if s.user == "" {
+		return s.bad("Send USER first"), false
+	}
+	login := s.user
+	s.user = ""
+	return s.login(login, func(ctx context.Context) (int64, error) {
+		return s.server.auth.Authenticate(ctx, login, password, s.remoteIP())
+	})
+}
+
+func (s *session) handleAPOP(args []string) (string, bool) {
+	if len(args) != 2 {
+		return s.bad("Syntax: APOP name digest"), false
+	}
+	return s.login(args[0], func(ctx context.Context) (int64, error) {
+		return s.server.pop3UCase.AuthenticateAPOP(ctx, args[0], s.timestamp, args[1])
+	})
+}
+
+// login проверяет учетные данные, занимает ящик пользователя и переходит в состояние TRANSACTION
+func (s *session) login(login string, authenticate func(ctx context.Context) (int64, error)) (string, bool) {
+	ctx, cancel := s.context()
+	defer cancel()
+
+	profileID, err := authenticate(ctx)
+	if errors.Is(err, profileUcase.ErrUserNotFound) || errors.Is(err, profileUcase.ErrWrongPassword) || errors.Is(err, pop3Ucase.ErrAuthFailed) {
+		s.authFailures++
+		s.log.Info("authentication failed", slog.String("login", login))
+		s.err("[AUTH] Invalid credentials")
+		if s.authFailures >= maxAuthFailures {
+			s.flush()
+			return "-ERR", true
+		}
+		return "-ERR", false
+	}
+	if err != nil {
+		s.log.Error("failed to authenticate: " + err.Error())
+		return s.err("[SYS/TEMP] Temporary failure, try again later"), false
+	}
+
+	if !s.server.lock(profileID) {
+		return s.err("[IN-USE] Mailbox is already open in another session"), false
+	}
+	drop, err := s.openMaildrop(ctx, profileID)
+	if err != nil {
+		s.server.unlock(profileID)
+		s.log.Error("failed to open maildrop: " + err.Error())
+		return s.err("[SYS/TEMP] Temporary failure, try again later"), false
+	}
+
+	s.profileID = profileID
+	s.drop = drop
+	s.log = s.log.With(slog.Int64("profile_id", profileID))
+	return s.ok(fmt.Sprintf("Mailbox ready, %d messages", len(drop.messages))), false
+}
+
+// handleQuit завершает сессию. В состоянии TRANSACTION перед выходом удаляются
+// отмеченные письма (состояние UPDATE, RFC 1939, 6)
+func (s *session) handleQuit() (string, bool) {
+	if s.profileID == 0 {
+		s.ok(s.server.cfg.Hostname + " POP3 server signing off")
+		s.flush()
+		return "+OK", true
+	}
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	left, err := s.update(ctx)
+	if err != nil {
+		s.log.Error("failed to remove deleted messages: " + err.Error())
+		s.err("[SYS/TEMP] Some deleted messages not removed")
+		s.flush()
+		return "-ERR", true
+	}
+
+	s.ok(fmt.Sprintf("%s POP3 server signing off (%d messages left)", s.server.cfg.Hostname, left))
+	s.flush()
+	return "+OK", true
+}
+
+// context - контекст выполнения одной команды
+func (s *session) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), commandTimeout)
+}
+
+// fail записывает ошибку хранилища в журнал и отвечает клиенту временной ошибкой
+func (s *session) fail(message string, err error) string {
+	s.log.Error(message + ": " + err.Error())
+	return s.err("[SYS/TEMP] Temporary failure, try again later")
+}
+
+// readLine читает строку без CRLF. Слишком длинная строка пропускается целиком
+func (s *session) readLine() (string, error) {
+	s.conn.SetDeadline(time.Now().Add(s.server.cfg.Timeout))
+
+	line, err := s.reader.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		for errors.Is(err, bufio.ErrBufferFull) {
+			_, err = s.reader.ReadSlice('\n')
+		}
+		if err != nil {
+			return "", err
+		}
+		return "", errLineTooLong
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(line), "\r\n"), nil
+}
+
+// ok добавляет в буфер положительный ответ и возвращает его статус
+func (s *session) ok(text string) string {
+	if text == "" {
+		s.write("+OK\r\n")
+	} else {
+		s.write("+OK " + text + "\r\n")
+	}
+	s.flushIdle()
+	return "+OK"
+}
+
+func (s *session) err(text string) string {
+	s.write("-ERR " + text + "\r\n")
+	s.flushIdle()
+	return "-ERR"
+}
+
+// bad отвечает на неизвестную или неверно записанную команду. Такие ответы
+// считаются подряд, чтобы закрыть соединение клиента, который не говорит по POP3
+func (s *session) bad(text string) string {
+	s.err(text)
+	return statusBad
+}
+
+// begin начинает многострочный ответ
+func (s *session) begin(text string) {
+	s.write("+OK " + text + "\r\n")
+}
+
+// end завершает многострочный ответ
+func (s *session) end() string {
+	s.write(".\r\n")
+	s.flushIdle()
+	return "+OK"
+}
+
+// flushIdle отправляет накопленные ответы, если клиент не прислал следующие команды
+// заранее (RFC 2449, 6.6)
+func (s *session) flushIdle() {
+	if s.reader.Buffered() == 0 {
+		s.flush()
+	}
+}
+
+func (s *session) write(text string) {
+	if _, err := s.writer.WriteString(text); err != nil {
+		s.log.Debug("failed to write response: " + err.Error())
+	}
+}
+
+func (s *session) flush() {
+	if err := s.writer.Flush(); err != nil {
+		s.log.Debug("failed to write response: " + err.Error())
+	}
+}
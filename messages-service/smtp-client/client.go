@@ -0,0 +1,186 @@
+package smtp_client
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultTimeout = 2 * time.Minute
+	// smtpPort - порт, на котором MX принимает почту от других серверов
+	smtpPort = "25"
+)
+
+type Config struct {
+	// Hostname - имя сервера в команде EHLO
+	Hostname string
+	// Smarthost - "host:port" сервера, через который уходит вся почта.
+	// Пустое значение - доставка напрямую на MX домена получателя
+	Smarthost string
+	// Username и Password для AUTH PLAIN на smarthost
+	Username string
+	Password string
+	// TLSSkipVerify отключает проверку сертификата smarthost. Сертификаты MX
+	// не проверяются: STARTTLS с ними используется оппортунистически (RFC 7435)
+	TLSSkipVerify bool
+	Timeout       time.Duration
+}
+
+// Client доставляет письма на внешние SMTP-серверы
+type Client struct {
+	cfg      Config
+	lookupMX func(ctx context.Context, domain string) ([]*net.MX, error)
+}
+
+func New(cfg Config) *Client {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.Hostname == "" {
+		cfg.Hostname = "localhost"
+	}
+	return &Client{cfg: cfg, lookupMX: net.DefaultResolver.LookupMX}
+}
+
+// Send передает письмо data одному получателю. Отказ сервера с кодом 5xx
+// и несуществующий домен возвращаются как domain.ErrDeliveryRejected,
+// остальные ошибки временные
+func (c *Client) Send(ctx context.Context, from, to string, data []byte) error {
+	if c.cfg.Smarthost != "" {
+		host, _, err := net.SplitHostPort(c.cfg.Smarthost)
+		if err != nil {
+			return fmt.Errorf("invalid smarthost %q: %w", c.cfg.Smarthost, err)
+		}
+		return c.deliver(ctx, c.cfg.Smarthost, host, true, from, to, data)
+	}
+
+	hosts, err := c.mxHosts(ctx, to)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, host := range hosts {
+		err := c.deliver(ctx, net.JoinHostPort(host, smtpPort), host, false, from, to, data)
+		if err == nil || errors.Is(err, domain.ErrDeliveryRejected) {
+			return err
+		}
+		// Временная ошибка - пробуем следующий MX
+		lastErr = err
+	}
+	return lastErr
+}
+
+// mxHosts возвращает MX домена получателя в порядке приоритета
+func (c *Client) mxHosts(ctx context.Context, to string) ([]string, error) {
+	_, domainName, ok := strings.Cut(to, "@")
+	if !ok || domainName == "" {
+		return nil, fmt.Errorf("%w: invalid recipient %q", domain.ErrDeliveryRejected, to)
+	}
+
+	records, err := c.lookupMX(ctx, domainName)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			// Без MX письмо доставляется на сам домен (RFC 5321, 5.1)
+			if _, err := net.DefaultResolver.LookupHost(ctx, domainName); err != nil {
+				return nil, fmt.Errorf("%w: domain %s not found", domain.ErrDeliveryRejected, domainName)
+			}
+			return []string{domainName}, nil
+		}
+		return nil, fmt.Errorf("mx lookup for %s: %w", domainName, err)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Pref < records[j].Pref })
+
+	hosts := make([]string, 0, len(records))
+	for _, record := range records {
+		host := strings.TrimSuffix(record.Host, ".")
+		if host == "" {
+			// Null MX: домен не принимает почту (RFC 7505)
+			return nil, fmt.Errorf("%w: domain %s does not accept mail", domain.ErrDeliveryRejected, domainName)
+		}
+		hosts = append(hosts, host)
+	}
+	if len(hosts) == 0 {
+		return []string{domainName}, nil
+	}
+	return hosts, nil
+}
+
+func (c *Client) deliver(ctx context.Context, addr, host string, smarthost bool, from, to string, data []byte) error {
+	dialer := net.Dialer{Timeout: c.cfg.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(c.cfg.Timeout))
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return classify(err)
+	}
+	defer client.Close()
+
+	if err := client.Hello(c.cfg.Hostname); err != nil {
+		return classify(err)
+	}
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		tlsConfig := &tls.Config{
+			ServerName:         host,
+			InsecureSkipVerify: !smarthost || c.cfg.TLSSkipVerify,
+			MinVersion:         tls.VersionTLS12,
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return classify(err)
+		}
+	}
+
+	if smarthost && c.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", c.cfg.Username, c.cfg.Password, host)); err != nil {
+			return classify(err)
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return classify(err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		return classify(err)
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return classify(err)
+	}
+	if _, err := writer.Write(data); err != nil {
+		return classify(err)
+	}
+	if err := writer.Close(); err != nil {
+		return classify(err)
+	}
+
+	// Письмо уже принято, ошибка QUIT на доставку не влияет
+	client.Quit()
+	return nil
+}
+
+// classify помечает постоянные отказы сервера
+func classify(err error) error {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) && protoErr.Code >= 500 {
+		return fmt.Errorf("%w: %d %s", domain.ErrDeliveryRejected, protoErr.Code, protoErr.Msg)
+	}
+	return err
+}
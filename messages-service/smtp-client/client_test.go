@@ -0,0 +1,147 @@
+package smtp_client
+
+import (
+	"2025_2_a4code/internal/domain"
+	smtpserver "2025_2_a4code/messages-service/smtp-server"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sink - локальный SMTP-сервер, который подменяет внешний
+type sink struct {
+	mu       sync.Mutex
+	messages []domain.InboundMessage
+	err      error
+}
+
+func (s *sink) ResolveRecipient(ctx context.Context, email string) (string, error) {
+	if strings.HasPrefix(email, "nobody@") {
+		return "", domain.ErrRecipientNotFound
+	}
+	return email, nil
+}
+
+func (s *sink) ReceiveMessage(ctx context.Context, msg domain.InboundMessage, recipients []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+func startSink(t *testing.T, s *sink) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := smtpserver.New(s, smtpserver.Config{Hostname: "mx.example.com", Domain: "example.com"}, nil)
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	return listener.Addr().String()
+}
+
+const message = "From: alexey@flintmail.ru\r\nTo: someone@example.com\r\nSubject: Hi\r\n\r\nHello\r\n"
+
+func TestClient_SendViaSmarthost(t *testing.T) {
+	s := &sink{}
+	client := New(Config{Hostname: "mx.flintmail.ru", Smarthost: startSink(t, s)})
+
+	err := client.Send(context.Background(), "alexey@flintmail.ru", "someone@example.com", []byte(message))
+
+	require.NoError(t, err)
+	require.Len(t, s.messages, 1)
+	assert.Equal(t, "alexey@flintmail.ru", s.messages[0].EnvelopeFrom)
+	assert.Equal(t, "Hello", s.messages[0].Text)
+	assert.Contains(t, s.messages[0].Headers["Received"][0], "from mx.flintmail.ru")
+}
+
+func TestClient_SendErrors(t *testing.T) {
+	t.Run("PermanentRejection", func(t *testing.T) {
+		client := New(Config{Smarthost: startSink(t, &sink{})})
+
+		err := client.Send(context.Background(), "alexey@flintmail.ru", "nobody@example.com", []byte(message))
+
+		assert.ErrorIs(t, err, domain.ErrDeliveryRejected)
+		assert.Contains(t, err.Error(), "550")
+	})
+
+	t.Run("TemporaryFailure", func(t *testing.T) {
+		client := New(Config{Smarthost: startSink(t, &sink{err: errors.New("db is down")})})
+
+		err := client.Send(context.Background(), "alexey@flintmail.ru", "someone@example.com", []byte(message))
+
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrDeliveryRejected)
+	})
+
+	t.Run("ConnectionRefused", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := listener.Addr().String()
+		listener.Close()
+		client := New(Config{Smarthost: addr})
+
+		err = client.Send(context.Background(), "alexey@flintmail.ru", "someone@example.com", []byte(message))
+
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrDeliveryRejected)
+	})
+}
+
+func TestClient_MXHosts(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []*net.MX
+		err     error
+		want    []string
+		wantErr error
+	}{
+		{
+			name:    "SortedByPreference",
+			records: []*net.MX{{Host: "backup.example.com.", Pref: 20}, {Host: "mx.example.com.", Pref: 10}},
+			want:    []string{"mx.example.com", "backup.example.com"},
+		},
+		{
+			name:    "NullMX",
+			records: []*net.MX{{Host: ".", Pref: 0}},
+			wantErr: domain.ErrDeliveryRejected,
+		},
+		{
+			name: "TemporaryDNSFailure",
+			err:  &net.DNSError{Err: "server misbehaving", IsTemporary: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := New(Config{})
+			client.lookupMX = func(ctx context.Context, domain string) ([]*net.MX, error) {
+				return tt.records, tt.err
+			}
+
+			hosts, err := client.mxHosts(context.Background(), "someone@example.com")
+
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.err != nil:
+				assert.Error(t, err)
+				assert.NotErrorIs(t, err, domain.ErrDeliveryRejected)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, hosts)
+			}
+		})
+	}
+}
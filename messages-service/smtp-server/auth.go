@@ -0,0 +1,104 @@
+package smtp_server
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/lib/dkim"
+	"2025_2_a4code/internal/lib/dmarc"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+)
+
+const authResultsHeader = "Authentication-Results"
+
+// authenticate проверяет отправителя письма по SPF, DKIM и DMARC и добавляет к нему
+// заголовок Authentication-Results (RFC 8601), по которому фильтры доставки видят результат
+func (s *session) authenticate(ctx context.Context, raw []byte, msg *domain.InboundMessage) {
+	cfg := s.server.cfg
+	if cfg.DKIM == nil && cfg.SPF == nil && cfg.DMARC == nil {
+		return
+	}
+
+	fromDomain := dkim.FromDomain(raw)
+	var methods []string
+
+	var dkimDomains []string
+	if cfg.DKIM != nil {
+		results := cfg.DKIM.Verify(ctx, raw)
+		msg.DKIM = dkim.Summarize(results, fromDomain)
+		for _, result := range results {
+			method := "dkim=" + string(result.Status)
+			if result.Reason != "" {
+				method += fmt.Sprintf(" reason=%q", result.Reason)
+			}
+			if result.Domain != "" {
+				method += " header.d=" + result.Domain + " header.s=" + result.Selector
+			}
+			methods = append(methods, method)
+
+			if result.Status == domain.DKIMPass {
+				dkimDomains = append(dkimDomains, result.Domain)
+			}
+		}
+	}
+
+	var spfDomain string
+	if cfg.SPF != nil {
+		result := cfg.SPF.Check(ctx, s.remoteIP(), s.helo, msg.EnvelopeFrom)
+		msg.SPF = domain.SPFResult{Status: result.Status, Domain: result.Domain}
+
+		method := "spf=" + string(result.Status)
+		if result.Status != domain.SPFPass {
+			method += fmt.Sprintf(" reason=%q", result.Reason)
+		}
+		if msg.EnvelopeFrom != "" {
+			method += " smtp.mailfrom=" + msg.EnvelopeFrom
+		} else {
+			method += " smtp.helo=" + s.helo
+		}
+		methods = append(methods, method)
+
+		if result.Status == domain.SPFPass {
+			spfDomain = result.Domain
+		}
+	}
+
+	if cfg.DMARC != nil {
+		result := cfg.DMARC.Check(ctx, dmarc.Identifiers{FromDomain: fromDomain, DKIMDomains: dkimDomains, SPFDomain: spfDomain})
+		msg.DMARC = result.DMARCResult
+
+		method := "dmarc=" + string(result.Status)
+		if result.Policy != "" {
+			method += " (p=" + string(result.Policy) + ")"
+		}
+		if fromDomain != "" {
+			method += " header.from=" + fromDomain
+		}
+		methods = append(methods, method)
+	}
+
+	msg.AuthenticationResults = cfg.Hostname + "; " + strings.Join(methods, "; ")
+
+	// Заголовки от имени этого сервера, пришедшие снаружи, поддельные (RFC 8601, 5)
+	values := []string{msg.AuthenticationResults}
+	for _, value := range msg.Headers[authResultsHeader] {
+		authServID, _, _ := strings.Cut(value, ";")
+		if !strings.EqualFold(strings.TrimSpace(authServID), cfg.Hostname) {
+			values = append(values, value)
+		}
+	}
+	if msg.Headers == nil {
+		msg.Headers = make(map[string][]string)
+	}
+	msg.Headers[authResultsHeader] = values
+}
+
+// remoteIP возвращает адрес клиента, с которого пришло письмо
+func (s *session) remoteIP() net.IP {
+	host, _, err := net.SplitHostPort(s.conn.RemoteAddr().String())
+	if err != nil {
+		return nil
+	}
+	return net.ParseIP(host)
+}
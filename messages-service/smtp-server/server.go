@@ -0,0 +1,182 @@
+package smtp_server
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/lib/dkim"
+	"2025_2_a4code/internal/lib/dmarc"
+	"2025_2_a4code/internal/lib/spf"
+	"context"
+	"crypto/tls"
+	"errors"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMaxMessageSize = 25 << 20
+	DefaultMaxRecipients  = 100
+	DefaultTimeout        = 5 * time.Minute
+)
+
+var ErrServerClosed = errors.New("smtp: server closed")
+
+// MessageUsecase - доставка принятых писем локальным пользователям
+type MessageUsecase interface {
+	ResolveRecipient(ctx context.Context, email string) (string, error)
+	ReceiveMessage(ctx context.Context, msg domain.InboundMessage, recipients []string) error
+}
+
+type Config struct {
+	// Hostname - имя сервера в приветствии, ответе на EHLO и заголовке Received
+	Hostname string
+	// Domain - домен, для которого сервер принимает почту. Письма на другие домены не пересылаются
+	Domain string
+	// TLSConfig включает STARTTLS, nil - команда недоступна
+	TLSConfig      *tls.Config
+	MaxMessageSize int64
+	MaxRecipients  int
+	// Timeout - сколько ждать очередную команду или данные письма от клиента
+	Timeout time.Duration
+	// DKIM проверяет подписи принятых писем, nil - проверка отключена
+	DKIM *dkim.Verifier
+	// SPF проверяет, может ли узел отправлять почту от имени домена из MAIL FROM, nil - проверка отключена
+	SPF *spf.Checker
+	// DMARC проверяет домен из поля From по результатам DKIM и SPF, nil - проверка отключена
+	DMARC *dmarc.Checker
+}
+
+// Server - SMTP-сервер (RFC 5321) для приема писем от внешних почтовых серверов
+type Server struct {
+	cfg          Config
+	messageUCase MessageUsecase
+	log          *slog.Logger
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+func New(messageUCase MessageUsecase, cfg Config, log *slog.Logger) *Server {
+	if cfg.MaxMessageSize <= 0 {
+		cfg.MaxMessageSize = DefaultMaxMessageSize
+	}
+	if cfg.MaxRecipients <= 0 {
+		cfg.MaxRecipients = DefaultMaxRecipients
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.Hostname == "" {
+		cfg.Hostname = cfg.Domain
+	}
+	if log == nil {
+		log = slog.Default()
+	}
+
+	return &Server{
+		cfg:          cfg,
+		messageUCase: messageUCase,
+		log:          log,
+		listeners:    make(map[net.Listener]struct{}),
+		conns:        make(map[net.Conn]struct{}),
+	}
+}
+
+func (s *Server) ListenAndServe(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+// Serve принимает соединения, пока listener не будет закрыт. Каждое соединение
+// обслуживается в отдельной горутине
+func (s *Server) Serve(listener net.Listener) error {
+	if !s.track(listener, true) {
+		listener.Close()
+		return ErrServerClosed
+	}
+	defer s.track(listener, false)
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return ErrServerClosed
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				time.Sleep(100 * time.Millisecond)
+				continue
+			}
+			return err
+		}
+
+		if !s.trackConn(conn, true) {
+			conn.Close()
+			return ErrServerClosed
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer s.trackConn(conn, false)
+			newSession(s, conn).serve()
+		}()
+	}
+}
+
+// Close закрывает все listener'ы и соединения и ждет завершения сессий
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for listener := range s.listeners {
+		listener.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return nil
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+func (s *Server) track(listener net.Listener, add bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !add {
+		delete(s.listeners, listener)
+		return true
+	}
+	if s.closed {
+		return false
+	}
+	s.listeners[listener] = struct{}{}
+	return true
+}
+
+func (s *Server) trackConn(conn net.Conn, add bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !add {
+		delete(s.conns, conn)
+		conn.Close()
+		return true
+	}
+	if s.closed {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	return true
+}
@@ -0,0 +1,368 @@
+package smtp_server
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/lib/dkim"
+	"2025_2_a4code/internal/lib/dmarc"
+	"2025_2_a4code/internal/lib/dnstest"
+	"2025_2_a4code/internal/lib/spf"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeUsecase struct {
+	mu         sync.Mutex
+	users      map[string]string
+	received   []domain.InboundMessage
+	recipients [][]string
+	err        error
+}
+
+func (f *fakeUsecase) ResolveRecipient(ctx context.Context, email string) (string, error) {
+	if user, ok := f.users[strings.ToLower(email)]; ok {
+		return user, nil
+	}
+	return "", domain.ErrRecipientNotFound
+}
+
+func (f *fakeUsecase) ReceiveMessage(ctx context.Context, msg domain.InboundMessage, recipients []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.received = append(f.received, msg)
+	f.recipients = append(f.recipients, recipients)
+	return nil
+}
+
+func startServer(t *testing.T, uc *fakeUsecase, cfg Config) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	cfg.Hostname = "mx.flintmail.ru"
+	cfg.Domain = "flintmail.ru"
+	server := New(uc, cfg, nil)
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	return listener.Addr().String()
+}
+
+func newUsecase() *fakeUsecase {
+	return &fakeUsecase{users: map[string]string{
+		"alexey@flintmail.ru": "Alexey@flintmail.ru",
+		"maria@flintmail.ru":  "maria@flintmail.ru",
+	}}
+}
+
+const testMessage = "From: Ivan <ivan@example.com>\r\n" +
+	"To: alexey@flintmail.ru\r\n" +
+	"Subject: Hello\r\n" +
+	"\r\n" +
+	".leading dot\r\n" +
+	"Body\r\n"
+
+func TestServer_ReceiveMessage(t *testing.T) {
+	uc := newUsecase()
+	addr := startServer(t, uc, Config{})
+
+	err := smtp.SendMail(addr, nil, "bounce@example.com", []string{"ALEXEY@flintmail.ru", "maria@flintmail.ru"}, []byte(testMessage))
+	require.NoError(t, err)
+
+	require.Len(t, uc.received, 1)
+	msg := uc.received[0]
+	assert.Equal(t, "bounce@example.com", msg.EnvelopeFrom)
+	assert.Equal(t, "ivan@example.com", msg.From)
+	assert.Equal(t, "Hello", msg.Topic)
+	assert.Equal(t, ".leading dot\nBody", msg.Text)
+	assert.Contains(t, msg.Headers["Received"][0], "by mx.flintmail.ru with ESMTP")
+	assert.Equal(t, []string{"Alexey@flintmail.ru", "maria@flintmail.ru"}, uc.recipients[0])
+}
+
+func TestServer_RejectsRecipients(t *testing.T) {
+	uc := newUsecase()
+	addr := startServer(t, uc, Config{MaxRecipients: 1})
+
+	client, err := smtp.Dial(addr)
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.Mail("ivan@example.com"))
+
+	tests := []struct {
+		name      string
+		recipient string
+		code      int
+	}{
+		{name: "Relay", recipient: "someone@gmail.com", code: 550},
+		{name: "UnknownUser", recipient: "nobody@flintmail.ru", code: 550},
+		{name: "Accepted", recipient: "alexey@flintmail.ru", code: 0},
+		{name: "TooManyRecipients", recipient: "maria@flintmail.ru", code: 452},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := client.Rcpt(tt.recipient)
+			if tt.code == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			var protoErr *textproto.Error
+			require.True(t, errors.As(err, &protoErr), "error = %v", err)
+			assert.Equal(t, tt.code, protoErr.Code)
+		})
+	}
+}
+
+func TestServer_Pipelining(t *testing.T) {
+	uc := newUsecase()
+	addr := startServer(t, uc, Config{MaxMessageSize: 1024})
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+
+	readCode := func() int {
+		code, _, err := text.ReadResponse(0)
+		require.NoError(t, err)
+		return code
+	}
+
+	assert.Equal(t, 220, readCode())
+	require.NoError(t, text.PrintfLine("EHLO client.example.com"))
+	_, ehlo, err := text.ReadResponse(250)
+	require.NoError(t, err)
+	assert.Contains(t, ehlo, "PIPELINING")
+	assert.Contains(t, ehlo, "SIZE 1024")
+	assert.Contains(t, ehlo, "8BITMIME")
+	assert.NotContains(t, ehlo, "STARTTLS")
+
+	// Все команды транзакции отправляются одним пакетом
+	_, err = conn.Write([]byte("MAIL FROM:<ivan@example.com> BODY=8BITMIME\r\n" +
+		"RCPT TO:<nobody@flintmail.ru>\r\n" +
+		"RCPT TO:<maria@flintmail.ru>\r\n" +
+		"DATA\r\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 250, readCode())
+	assert.Equal(t, 550, readCode())
+	assert.Equal(t, 250, readCode())
+	assert.Equal(t, 354, readCode())
+
+	require.NoError(t, text.PrintfLine("Subject: Pipelined\r\n\r\nПривет\r\n."))
+	assert.Equal(t, 250, readCode())
+
+	// Заявленный размер больше лимита
+	require.NoError(t, text.PrintfLine("MAIL FROM:<ivan@example.com> SIZE=4096"))
+	assert.Equal(t, 552, readCode())
+
+	// Фактический размер больше лимита: письмо дочитывается, сессия продолжается
+	require.NoError(t, text.PrintfLine("MAIL FROM:<ivan@example.com>"))
+	assert.Equal(t, 250, readCode())
+	require.NoError(t, text.PrintfLine("RCPT TO:<maria@flintmail.ru>"))
+	assert.Equal(t, 250, readCode())
+	require.NoError(t, text.PrintfLine("DATA"))
+	assert.Equal(t, 354, readCode())
+	require.NoError(t, text.PrintfLine("Subject: Big\r\n\r\n%s\r\n.", strings.Repeat("x", 2048)))
+	assert.Equal(t, 552, readCode())
+
+	require.NoError(t, text.PrintfLine("QUIT"))
+	assert.Equal(t, 221, readCode())
+
+	require.Len(t, uc.received, 1)
+	assert.Equal(t, "Привет", uc.received[0].Text)
+}
+
+func TestServer_CommandSequence(t *testing.T) {
+	uc := newUsecase()
+	uc.err = domain.ErrInvalidSender
+	addr := startServer(t, uc, Config{})
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	_, _, err = text.ReadResponse(220)
+	require.NoError(t, err)
+
+	steps := []struct {
+		command string
+		code    int
+	}{
+		{command: "MAIL FROM:<ivan@example.com>", code: 503},
+		{command: "HELO client.example.com", code: 250},
+		{command: "RCPT TO:<maria@flintmail.ru>", code: 503},
+		{command: "MAIL FROM:<>", code: 250},
+		{command: "MAIL FROM:<ivan@example.com>", code: 503},
+		{command: "DATA", code: 554},
+		{command: "RCPT TO:maria@flintmail.ru", code: 501},
+		{command: "RSET", code: 250},
+		{command: "MAIL FROM:<ivan@example.com> AUTH=<>", code: 555},
+		{command: "STARTTLS", code: 454},
+		{command: "VRFY maria", code: 252},
+		{command: "NOOP", code: 250},
+		{command: "HELP", code: 500},
+		{command: "MAIL FROM:<ivan@example.com>", code: 250},
+		{command: "RCPT TO:<maria@flintmail.ru>", code: 250},
+		{command: "DATA", code: 354},
+		{command: "From: spoof\r\nSubject: x\r\n\r\nbody\r\n.", code: 550},
+		{command: "QUIT", code: 221},
+	}
+
+	for _, step := range steps {
+		require.NoError(t, text.PrintfLine("%s", step.command))
+		code, msg, err := text.ReadResponse(0)
+		if err != nil && code == 0 {
+			t.Fatalf("%q: %v", step.command, err)
+		}
+		assert.Equal(t, step.code, code, "%q: %s", step.command, msg)
+	}
+}
+
+func TestServer_StartTLS(t *testing.T) {
+	uc := newUsecase()
+	addr := startServer(t, uc, Config{TLSConfig: &tls.Config{Certificates: []tls.Certificate{selfSignedCert(t)}}})
+
+	client, err := smtp.Dial(addr)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Hello("client.example.com"))
+	ok, _ := client.Extension("STARTTLS")
+	require.True(t, ok)
+	require.NoError(t, client.StartTLS(&tls.Config{InsecureSkipVerify: true}))
+
+	ok, _ = client.Extension("STARTTLS")
+	assert.False(t, ok, "STARTTLS advertised after TLS is active")
+
+	require.NoError(t, client.Mail("ivan@example.com"))
+	require.NoError(t, client.Rcpt("maria@flintmail.ru"))
+	writer, err := client.Data()
+	require.NoError(t, err)
+	_, err = writer.Write([]byte(testMessage))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	require.NoError(t, client.Quit())
+
+	require.Len(t, uc.received, 1)
+	assert.Contains(t, uc.received[0].Headers["Received"][0], "with ESMTPS")
+}
+
+func TestServer_LineTooLong(t *testing.T) {
+	addr := startServer(t, newUsecase(), Config{})
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	_, _, err = text.ReadResponse(220)
+	require.NoError(t, err)
+
+	_, err = conn.Write([]byte("NOOP " + strings.Repeat("x", 2000) + "\r\nNOOP\r\n"))
+	require.NoError(t, err)
+	code, _, _ := text.ReadResponse(0)
+	assert.Equal(t, 500, code)
+	_, _, err = text.ReadResponse(250)
+	assert.NoError(t, err)
+}
+
+func selfSignedCert(t *testing.T) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mx.flintmail.ru"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"mx.flintmail.ru"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestServer_VerifiesDKIM(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer := dkim.Key{Domain: "example.com", Selector: "mail", Signer: key}
+	record, err := signer.DNSRecord()
+	require.NoError(t, err)
+
+	signed, err := dkim.Sign([]byte(testMessage), signer, time.Now())
+	require.NoError(t, err)
+	// Поддельный результат от имени принимающего сервера и честный от промежуточного
+	message := "Authentication-Results: mx.flintmail.ru; dkim=pass header.d=flintmail.ru\r\n" +
+		"Authentication-Results: relay.example.com; dkim=pass header.d=example.com\r\n" +
+		string(signed)
+
+	uc := newUsecase()
+	addr := startServer(t, uc, Config{DKIM: dkim.NewVerifier(func(ctx context.Context, name string) ([]string, error) {
+		if name != "mail._domainkey.example.com" {
+			return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+		}
+		return []string{record}, nil
+	})})
+
+	require.NoError(t, smtp.SendMail(addr, nil, "bounce@example.com", []string{"alexey@flintmail.ru"}, []byte(message)))
+	require.NoError(t, smtp.SendMail(addr, nil, "bounce@example.com", []string{"alexey@flintmail.ru"}, []byte(strings.Replace(message, "Body", "Changed", 1))))
+
+	require.Len(t, uc.received, 2)
+	assert.Equal(t, domain.DKIMResult{Status: domain.DKIMPass, Domain: "example.com"}, uc.received[0].DKIM)
+	assert.Equal(t, []string{
+		"mx.flintmail.ru; dkim=pass header.d=example.com header.s=mail",
+		"relay.example.com; dkim=pass header.d=example.com",
+	}, uc.received[0].Headers["Authentication-Results"])
+
+	assert.Equal(t, domain.DKIMFail, uc.received[1].DKIM.Status)
+	assert.Contains(t, uc.received[1].Headers["Authentication-Results"][0], `dkim=fail reason="body hash did not verify"`)
+}
+
+func TestServer_ChecksSPFAndDMARC(t *testing.T) {
+	zone := &dnstest.Zone{TXT: map[string][]string{
+		"example.com":        {"v=spf1 ip4:127.0.0.1 -all"},
+		"_dmarc.example.com": {"v=DMARC1; p=reject"},
+		"spammer.net":        {"v=spf1 -all"},
+	}}
+
+	uc := newUsecase()
+	addr := startServer(t, uc, Config{SPF: spf.NewChecker(zone), DMARC: dmarc.NewChecker(zone)})
+
+	require.NoError(t, smtp.SendMail(addr, nil, "bounce@example.com", []string{"alexey@flintmail.ru"}, []byte(testMessage)))
+	require.NoError(t, smtp.SendMail(addr, nil, "bounce@spammer.net", []string{"alexey@flintmail.ru"}, []byte(testMessage)))
+
+	require.Len(t, uc.received, 2)
+	assert.Equal(t, domain.SPFResult{Status: domain.SPFPass, Domain: "example.com"}, uc.received[0].SPF)
+	assert.Equal(t, domain.DMARCResult{Status: domain.DMARCPass, Policy: domain.DMARCPolicyReject, Aligned: true}, uc.received[0].DMARC)
+	assert.Equal(t, "mx.flintmail.ru; spf=pass smtp.mailfrom=bounce@example.com; dmarc=pass (p=reject) header.from=example.com",
+		uc.received[0].AuthenticationResults)
+
+	// Домен из MAIL FROM не разрешает отправку с этого адреса, и адрес в From не подтвержден
+	assert.Equal(t, domain.SPFFail, uc.received[1].SPF.Status)
+	assert.Equal(t, domain.DMARCResult{Status: domain.DMARCFail, Policy: domain.DMARCPolicyReject}, uc.received[1].DMARC)
+	assert.Equal(t, []string{uc.received[1].AuthenticationResults}, uc.received[1].Headers["Authentication-Results"])
+	assert.Contains(t, uc.received[1].AuthenticationResults, "dmarc=fail (p=reject) header.from=example.com")
+}
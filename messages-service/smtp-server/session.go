@@ -0,0 +1,399 @@
+package smtp_server
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/lib/mailparse"
+	"2025_2_a4code/internal/lib/metrics"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxLineLength - ограничение длины строки команды с учетом расширений (RFC 5321, 4.5.3.1.4)
+	maxLineLength = 1000
+	// maxErrors - после стольких ошибочных команд подряд соединение закрывается
+	maxErrors = 10
+)
+
+var errLineTooLong = errors.New("line too long")
+
+// session - состояние одного SMTP-соединения
+type session struct {
+	server *Server
+	conn   net.Conn
+	reader *bufio.Reader
+	writer *bufio.Writer
+	log    *slog.Logger
+
+	helo string
+	tls  bool
+
+	// Транзакция: MAIL FROM, затем RCPT TO, затем DATA
+	mailFrom   *string
+	recipients []string
+}
+
+func newSession(server *Server, conn net.Conn) *session {
+	s := &session{
+		server: server,
+		log:    server.log.With(slog.String("remote", conn.RemoteAddr().String())),
+	}
+	s.setConn(conn)
+	return s
+}
+
+func (s *session) setConn(conn net.Conn) {
+	s.conn = conn
+	s.reader = bufio.NewReaderSize(conn, maxLineLength+2)
+	s.writer = bufio.NewWriter(conn)
+}
+
+func (s *session) serve() {
+	s.reply(220, s.server.cfg.Hostname+" ESMTP ready")
+
+	errorsInRow := 0
+	for {
+		line, err := s.readLine()
+		if errors.Is(err, errLineTooLong) {
+			s.reply(500, "5.5.2 Line too long")
+			continue
+		}
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				s.reply(421, "4.4.2 "+s.server.cfg.Hostname+" Timeout, closing connection")
+			}
+			return
+		}
+
+		verb, args, _ := strings.Cut(line, " ")
+		code, quit := s.handle(strings.ToUpper(verb), strings.TrimSpace(args))
+		if quit {
+			return
+		}
+
+		if code >= 500 {
+			errorsInRow++
+			if errorsInRow >= maxErrors {
+				s.reply(421, "4.7.0 Too many errors, closing connection")
+				return
+			}
+		} else {
+			errorsInRow = 0
+		}
+	}
+}
+
+// handle выполняет команду и возвращает код ответа и признак завершения сессии
+func (s *session) handle(verb, args string) (int, bool) {
+	switch verb {
+	case "EHLO", "HELO":
+		return s.handleHelo(verb, args), false
+	case "STARTTLS":
+		return s.handleStartTLS()
+	case "MAIL":
+		return s.handleMail(args), false
+	case "RCPT":
+		return s.handleRcpt(args), false
+	case "DATA":
+		return s.handleData()
+	case "RSET":
+		s.reset()
+		return s.reply(250, "2.0.0 OK"), false
+	case "NOOP":
+		return s.reply(250, "2.0.0 OK"), false
+	case "VRFY":
+		// Не раскрываем, какие адреса существуют
+		return s.reply(252, "2.5.0 Cannot VRFY user, but will accept message"), false
+	case "QUIT":
+		s.reply(221, "2.0.0 Bye")
+		return 221, true
+	}
+	return s.reply(500, "5.5.2 Command not recognized"), false
+}
+
+func (s *session) handleHelo(verb, args string) int {
+	if args == "" {
+		return s.reply(501, "5.5.4 Domain name required")
+	}
+	s.helo = args
+	s.reset()
+
+	if verb == "HELO" {
+		return s.reply(250, s.server.cfg.Hostname)
+	}
+
+	lines := []string{
+		s.server.cfg.Hostname + " greets " + args,
+		"SIZE " + strconv.FormatInt(s.server.cfg.MaxMessageSize, 10),
+		"8BITMIME",
+		"PIPELINING",
+		"ENHANCEDSTATUSCODES",
+	}
+	if s.server.cfg.TLSConfig != nil && !s.tls {
+		lines = append(lines, "STARTTLS")
+	}
+	return s.reply(250, lines...)
+}
+
+func (s *session) handleStartTLS() (int, bool) {
+	if s.tls {
+		return s.reply(503, "5.5.1 TLS already active"), false
+	}
+	if s.server.cfg.TLSConfig == nil {
+		return s.reply(454, "4.7.0 TLS not available"), false
+	}
+
+	s.reply(220, "2.0.0 Ready to start TLS")
+	// Команды, отправленные до завершения рукопожатия, отбрасываются (RFC 3207, 4.2)
+	tlsConn := tls.Server(s.conn, s.server.cfg.TLSConfig)
+	s.conn.SetDeadline(time.Now().Add(s.server.cfg.Timeout))
+	if err := tlsConn.Handshake(); err != nil {
+		s.log.Warn("TLS handshake failed: " + err.Error())
+		return 0, true
+	}
+
+	s.setConn(tlsConn)
+	s.tls = true
+	s.helo = ""
+	s.reset()
+	return 220, false
+}
+
+func (s *session) handleMail(args string) int {
+	if s.helo == "" {
+		return s.reply(503, "5.5.1 Send EHLO first")
+	}
+	if s.mailFrom != nil {
+		return s.reply(503, "5.5.1 Sender already specified")
+	}
+
+	address, params, ok := parsePath(args, "FROM:")
+	if !ok {
+		return s.reply(501, "5.5.4 Syntax: MAIL FROM:<address>")
+	}
+	if address != "" && !strings.Contains(address, "@") {
+		return s.reply(553, "5.1.7 Invalid sender address")
+	}
+
+	for _, param := range params {
+		key, value, _ := strings.Cut(param, "=")
+		switch strings.ToUpper(key) {
+		case "SIZE":
+			size, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || size < 0 {
+				return s.reply(501, "5.5.4 Invalid SIZE parameter")
+			}
+			if size > s.server.cfg.MaxMessageSize {
+				return s.reply(552, "5.3.4 Message size exceeds fixed maximum message size")
+			}
+		case "BODY":
+			if body := strings.ToUpper(value); body != "7BIT" && body != "8BITMIME" {
+				return s.reply(501, "5.5.4 Invalid BODY parameter")
+			}
+		default:
+			return s.reply(555, "5.5.4 Unsupported parameter "+key)
+		}
+	}
+
+	s.mailFrom = &address
+	return s.reply(250, "2.1.0 Sender OK")
+}
+
+func (s *session) handleRcpt(args string) int {
+	if s.mailFrom == nil {
+		return s.reply(503, "5.5.1 Send MAIL first")
+	}
+
+	address, _, ok := parsePath(args, "TO:")
+	if !ok || address == "" {
+		return s.reply(501, "5.5.4 Syntax: RCPT TO:<address>")
+	}
+
+	_, domainName, ok := strings.Cut(address, "@")
+	if !ok || !strings.EqualFold(domainName, s.server.cfg.Domain) {
+		return s.reply(550, "5.7.1 Relaying denied")
+	}
+	if len(s.recipients) >= s.server.cfg.MaxRecipients {
+		return s.reply(452, "4.5.3 Too many recipients")
+	}
+
+	recipient, err := s.server.messageUCase.ResolveRecipient(context.Background(), address)
+	if errors.Is(err, domain.ErrRecipientNotFound) {
+		return s.reply(550, "5.1.1 No such user")
+	}
+	if err != nil {
+		s.log.Error("failed to resolve recipient: " + err.Error())
+		return s.reply(451, "4.3.0 Temporary failure, try again later")
+	}
+
+	for _, existing := range s.recipients {
+		if existing == recipient {
+			return s.reply(250, "2.1.5 Recipient OK")
+		}
+	}
+	s.recipients = append(s.recipients, recipient)
+	return s.reply(250, "2.1.5 Recipient OK")
+}
+
+func (s *session) handleData() (int, bool) {
+	if s.mailFrom == nil {
+		return s.reply(503, "5.5.1 Send MAIL first"), false
+	}
+	if len(s.recipients) == 0 {
+		return s.reply(554, "5.5.1 No valid recipients"), false
+	}
+
+	s.reply(354, "Start mail input; end with <CRLF>.<CRLF>")
+
+	s.conn.SetDeadline(time.Now().Add(s.server.cfg.Timeout))
+	dot := textproto.NewReader(s.reader).DotReader()
+	raw, err := io.ReadAll(io.LimitReader(dot, s.server.cfg.MaxMessageSize+1))
+	if err != nil {
+		return 0, true
+	}
+	if int64(len(raw)) > s.server.cfg.MaxMessageSize {
+		// Дочитываем письмо до конца, чтобы сессию можно было продолжить
+		if _, err := io.Copy(io.Discard, dot); err != nil {
+			return 0, true
+		}
+		s.reset()
+		metrics.MessagesOperationsTotal.WithLabelValues("smtp", "receive", "error").Inc()
+		return s.reply(552, "5.3.4 Message size exceeds fixed maximum message size"), false
+	}
+
+	code := s.deliver(raw)
+	s.reset()
+	return code, false
+}
+
+// deliver разбирает принятое письмо и передает его получателям
+func (s *session) deliver(raw []byte) int {
+	const op = "smtp_server.deliver"
+	log := s.log.With(slog.String("op", op))
+
+	received := s.receivedHeader()
+	msg, err := mailparse.Parse(io.MultiReader(strings.NewReader(received), bytes.NewReader(raw)))
+	if err != nil {
+		log.Warn("failed to parse message: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("smtp", "receive", "error").Inc()
+		return s.reply(554, "5.6.0 Malformed message")
+	}
+	msg.EnvelopeFrom = *s.mailFrom
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.server.cfg.Timeout)
+	defer cancel()
+
+	s.authenticate(ctx, raw, &msg)
+
+	err = s.server.messageUCase.ReceiveMessage(ctx, msg, s.recipients)
+	if errors.Is(err, domain.ErrInvalidSender) {
+		metrics.MessagesOperationsTotal.WithLabelValues("smtp", "receive", "error").Inc()
+		return s.reply(550, "5.1.7 Invalid sender address")
+	}
+	if err != nil {
+		log.Error("failed to receive message: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("smtp", "receive", "error").Inc()
+		return s.reply(451, "4.3.0 Temporary failure, try again later")
+	}
+
+	log.Info("message received", slog.String("from", msg.Sender()), slog.Int("recipients", len(s.recipients)))
+	metrics.MessagesOperationsTotal.WithLabelValues("smtp", "receive", "success").Inc()
+	return s.reply(250, "2.0.0 Message accepted for delivery")
+}
+
+func (s *session) receivedHeader() string {
+	protocol := "ESMTP"
+	if s.tls {
+		protocol = "ESMTPS"
+	}
+	remote := s.conn.RemoteAddr().String()
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+	return fmt.Sprintf("Received: from %s ([%s])\r\n\tby %s with %s;\r\n\t%s\r\n",
+		s.helo, remote, s.server.cfg.Hostname, protocol, time.Now().Format(time.RFC1123Z))
+}
+
+func (s *session) reset() {
+	s.mailFrom = nil
+	s.recipients = nil
+}
+
+// readLine читает строку команды без CRLF. Слишком длинная строка пропускается целиком
+func (s *session) readLine() (string, error) {
+	s.conn.SetDeadline(time.Now().Add(s.server.cfg.Timeout))
+
+	line, err := s.reader.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		for errors.Is(err, bufio.ErrBufferFull) {
+			_, err = s.reader.ReadSlice('\n')
+		}
+		if err != nil {
+			return "", err
+		}
+		return "", errLineTooLong
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(line), "\r\n"), nil
+}
+
+// reply отправляет ответ, многострочный - если передано несколько строк.
+// При конвейерной передаче команд (PIPELINING) ответы копятся в буфере,
+// пока клиент не дождется их, прочитав все отправленные команды
+func (s *session) reply(code int, lines ...string) int {
+	for i, line := range lines {
+		separator := " "
+		if i < len(lines)-1 {
+			separator = "-"
+		}
+		fmt.Fprintf(s.writer, "%d%s%s\r\n", code, separator, line)
+	}
+
+	// 354, 220 и 221 клиент всегда ждет: перед данными письма, TLS-рукопожатием и закрытием соединения
+	if s.reader.Buffered() == 0 || code == 354 || code == 220 || code == 221 {
+		if err := s.writer.Flush(); err != nil {
+			s.log.Debug("failed to write reply: " + err.Error())
+		}
+	}
+	return code
+}
+
+// parsePath разбирает аргумент MAIL/RCPT вида "FROM:<address> PARAM=value"
+func parsePath(args, prefix string) (string, []string, bool) {
+	if len(args) < len(prefix) || !strings.EqualFold(args[:len(prefix)], prefix) {
+		return "", nil, false
+	}
+	rest := strings.TrimSpace(args[len(prefix):])
+	if !strings.HasPrefix(rest, "<") {
+		return "", nil, false
+	}
+	end := strings.IndexByte(rest, '>')
+	if end < 0 {
+		return "", nil, false
+	}
+
+	address := rest[1:end]
+	// Маршрут источника "@a,@b:user@c" устарел, используется только адрес
+	if i := strings.LastIndexByte(address, ':'); i >= 0 && strings.HasPrefix(address, "@") {
+		address = address[i+1:]
+	}
+	if strings.ContainsAny(address, " \t<>") {
+		return "", nil, false
+	}
+
+	return address, strings.Fields(rest[end+1:]), true
+}
@@ -0,0 +1,133 @@
+package submission_server
+
+import (
+	profileUcase "2025_2_a4code/internal/usecase/profile"
+	"context"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// authTimeout - ограничение времени проверки пароля
+const authTimeout = time.Minute
+
+// Приглашения механизма LOGIN: "Username:" и "Password:" в base64
+const (
+	loginUsernameChallenge = "VXNlcm5hbWU6"
+	loginPasswordChallenge = "UGFzc3dvcmQ6"
+)
+
+// authAllowed запрещает передавать пароль открытым текстом без явного разрешения
+func (s *session) authAllowed() bool {
+	return s.tls || s.server.cfg.AllowInsecureAuth
+}
+
+// handleAuth выполняет вход по SMTP AUTH (RFC 4954) механизмами PLAIN (RFC 4616) и LOGIN
+func (s *session) handleAuth(args string) (int, bool) {
+	if s.helo == "" {
+		return s.reply(503, "5.5.1 Send EHLO first"), false
+	}
+	if s.profileID != 0 {
+		return s.reply(503, "5.5.1 Already authenticated"), false
+	}
+	if s.mailFrom != nil {
+		return s.reply(503, "5.5.1 AUTH not permitted during a mail transaction"), false
+	}
+	if !s.authAllowed() {
+		return s.reply(538, "5.7.11 Encryption required for requested authentication mechanism"), false
+	}
+
+	mechanism, initial, _ := strings.Cut(args, " ")
+	initial = strings.TrimSpace(initial)
+
+	var login, password string
+	switch strings.ToUpper(mechanism) {
+	case "PLAIN":
+		response, code, quit := s.saslResponse(initial, "")
+		if code != 0 || quit {
+			return code, quit
+		}
+		parts := strings.Split(string(response), "\x00")
+		if len(parts) != 3 || (parts[0] != "" && parts[0] != parts[1]) {
+			return s.authFailed()
+		}
+		login, password = parts[1], parts[2]
+	case "LOGIN":
+		response, code, quit := s.saslResponse(initial, loginUsernameChallenge)
+		if code != 0 || quit {
+			return code, quit
+		}
+		login = string(response)
+
+		response, code, quit = s.saslResponse("", loginPasswordChallenge)
+		if code != 0 || quit {
+			return code, quit
+		}
+		password = string(response)
+	case "":
+		return s.reply(501, "5.5.4 Syntax: AUTH mechanism"), false
+	default:
+		return s.reply(504, "5.5.4 Unrecognized authentication type"), false
+	}
+
+	return s.login(login, password)
+}
+
+// saslResponse возвращает начальный ответ клиента или запрашивает ответ на challenge.
+// Ненулевой код означает, что ответ уже отправлен и вход прерван
+func (s *session) saslResponse(initial, challenge string) ([]byte, int, bool) {
+	response := initial
+	if response == "" {
+		s.reply(334, challenge)
+		line, err := s.readLine()
+		if err != nil {
+			return nil, 0, true
+		}
+		response = strings.TrimSpace(line)
+	}
+	if response == "*" {
+		return nil, s.reply(501, "5.0.0 Authentication cancelled"), false
+	}
+	// "=" - пустой начальный ответ (RFC 4954, 4)
+	if response == "=" {
+		return []byte{}, 0, false
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(response)
+	if err != nil {
+		return nil, s.reply(501, "5.5.2 Invalid base64 data"), false
+	}
+	return decoded, 0, false
+}
+
+func (s *session) login(login, password string) (int, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), authTimeout)
+	defer cancel()
+
+	profileID, err := s.server.auth.Authenticate(ctx, login, password, s.remoteIP())
+	if errors.Is(err, profileUcase.ErrUserNotFound) || errors.Is(err, profileUcase.ErrWrongPassword) {
+		s.log.Info("authentication failed", slog.String("login", login))
+		return s.authFailed()
+	}
+	if err != nil {
+		s.log.Error("failed to authenticate: " + err.Error())
+		return s.reply(454, "4.7.0 Temporary authentication failure"), false
+	}
+
+	s.profileID = profileID
+	s.log = s.log.With(slog.Int64("profile_id", profileID))
+	return s.reply(235, "2.7.0 Authentication successful"), false
+}
+
+// authFailed отвечает на неверные учетные данные и закрывает соединение после maxAuthFailures попыток
+func (s *session) authFailed() (int, bool) {
+	s.authFailures++
+	if s.authFailures >= maxAuthFailures {
+		s.reply(535, "5.7.8 Authentication credentials invalid")
+		s.reply(421, "4.7.0 Too many authentication failures, closing connection")
+		return 421, true
+	}
+	return s.reply(535, "5.7.8 Authentication credentials invalid"), false
+}
@@ -0,0 +1,190 @@
+package submission_server
+
+import (
+	"2025_2_a4code/internal/domain"
+	"context"
+	"crypto/tls"
+	"errors"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMaxMessageSize = 25 << 20
+	DefaultMaxRecipients  = 100
+	DefaultTimeout        = 5 * time.Minute
+)
+
+var ErrServerClosed = errors.New("submission: server closed")
+
+// MessageUsecase - отправка писем вошедшего пользователя
+type MessageUsecase interface {
+	SubmitMessage(ctx context.Context, profileID int64, msg domain.InboundMessage, recipients []string) (int64, error)
+}
+
+// Authenticator проверяет логин и пароль почтового клиента и возвращает профиль пользователя.
+// remoteIP - адрес клиента, он показывается пользователю в списке паролей приложений
+type Authenticator interface {
+	Authenticate(ctx context.Context, login, password, remoteIP string) (int64, error)
+}
+
+type Config struct {
+	// Hostname - имя сервера в приветствии и ответе на EHLO
+	Hostname string
+	// TLSConfig включает STARTTLS и ListenAndServeTLS, nil - соединения только без шифрования
+	TLSConfig *tls.Config
+	// AllowInsecureAuth разрешает вход без TLS, пароль при этом передается открытым текстом
+	AllowInsecureAuth bool
+	MaxMessageSize    int64
+	MaxRecipients     int
+	// Timeout - сколько ждать очередную команду или данные письма от клиента
+	Timeout time.Duration
+}
+
+// Server - сервер отправки почты (RFC 6409) для почтовых клиентов. Принимает письма
+// только после входа по SMTP AUTH и отправляет их так же, как веб-интерфейс
+type Server struct {
+	cfg          Config
+	messageUCase MessageUsecase
+	auth         Authenticator
+	log          *slog.Logger
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+func New(messageUCase MessageUsecase, auth Authenticator, cfg Config, log *slog.Logger) *Server {
+	if cfg.MaxMessageSize <= 0 {
+		cfg.MaxMessageSize = DefaultMaxMessageSize
+	}
+	if cfg.MaxRecipients <= 0 {
+		cfg.MaxRecipients = DefaultMaxRecipients
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if log == nil {
+		log = slog.Default()
+	}
+
+	return &Server{
+		cfg:          cfg,
+		messageUCase: messageUCase,
+		auth:         auth,
+		log:          log,
+		listeners:    make(map[net.Listener]struct{}),
+		conns:        make(map[net.Conn]struct{}),
+	}
+}
+
+func (s *Server) ListenAndServe(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+// ListenAndServeTLS принимает соединения, зашифрованные с самого начала (порт 465, RFC 8314)
+func (s *Server) ListenAndServeTLS(addr string) error {
+	if s.cfg.TLSConfig == nil {
+		return errors.New("submission: TLS is not configured")
+	}
+	listener, err := tls.Listen("tcp", addr, s.cfg.TLSConfig)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+// Serve принимает соединения, пока listener не будет закрыт. Каждое соединение
+// обслуживается в отдельной горутине
+func (s *Server) Serve(listener net.Listener) error {
+	if !s.track(listener, true) {
+		listener.Close()
+		return ErrServerClosed
+	}
+	defer s.track(listener, false)
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return ErrServerClosed
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				time.Sleep(100 * time.Millisecond)
+				continue
+			}
+			return err
+		}
+
+		if !s.trackConn(conn, true) {
+			conn.Close()
+			return ErrServerClosed
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer s.trackConn(conn, false)
+			newSession(s, conn).serve()
+		}()
+	}
+}
+
+// Close закрывает все listener'ы и соединения и ждет завершения сессий
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for listener := range s.listeners {
+		listener.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return nil
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+func (s *Server) track(listener net.Listener, add bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !add {
+		delete(s.listeners, listener)
+		return true
+	}
+	if s.closed {
+		return false
+	}
+	s.listeners[listener] = struct{}{}
+	return true
+}
+
+func (s *Server) trackConn(conn net.Conn, add bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !add {
+		delete(s.conns, conn)
+		conn.Close()
+		return true
+	}
+	if s.closed {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	return true
+}
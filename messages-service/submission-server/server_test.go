@@ -0,0 +1,277 @@
+package submission_server
+
+import (
+	"2025_2_a4code/internal/domain"
+	profileUcase "2025_2_a4code/internal/usecase/profile"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeUsecase struct {
+	mu         sync.Mutex
+	profiles   []int64
+	submitted  []domain.InboundMessage
+	recipients [][]string
+	err        error
+}
+
+func (f *fakeUsecase) SubmitMessage(ctx context.Context, profileID int64, msg domain.InboundMessage, recipients []string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return 0, f.err
+	}
+	f.profiles = append(f.profiles, profileID)
+	f.submitted = append(f.submitted, msg)
+	f.recipients = append(f.recipients, recipients)
+	return 11, nil
+}
+
+type fakeAuth struct{}
+
+func (fakeAuth) Authenticate(ctx context.Context, login, password, remoteIP string) (int64, error) {
+	if remoteIP != "127.0.0.1" {
+		return 0, fmt.Errorf("unexpected remote ip %s", remoteIP)
+	}
+	if login != "alexey" && login != "alexey@flintmail.ru" {
+		return 0, profileUcase.ErrUserNotFound
+	}
+	if password != "secret" {
+		return 0, profileUcase.ErrWrongPassword
+	}
+	return 7, nil
+}
+
+func startServer(t *testing.T, uc *fakeUsecase, cfg Config) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	cfg.Hostname = "smtp.flintmail.ru"
+	server := New(uc, fakeAuth{}, cfg, nil)
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	return listener.Addr().String()
+}
+
+// dial подключается к серверу и читает приветствие
+func dial(t *testing.T, addr string) *textproto.Conn {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	text := textproto.NewConn(conn)
+	t.Cleanup(func() { text.Close() })
+	_, _, err = text.ReadResponse(220)
+	require.NoError(t, err)
+	return text
+}
+
+type step struct {
+	command string
+	code    int
+}
+
+func runSteps(t *testing.T, text *textproto.Conn, steps []step) {
+	t.Helper()
+	for _, step := range steps {
+		require.NoError(t, text.PrintfLine("%s", step.command))
+		code, msg, err := text.ReadResponse(0)
+		if err != nil && code == 0 {
+			t.Fatalf("%q: %v", step.command, err)
+		}
+		assert.Equal(t, step.code, code, "%q: %s", step.command, msg)
+	}
+}
+
+func plainResponse(login, password string) string {
+	return base64.StdEncoding.EncodeToString([]byte("\x00" + login + "\x00" + password))
+}
+
+const testMessage = "From: Alexey <alexey@flintmail.ru>\r\n" +
+	"To: ivan@example.com\r\n" +
+	"Subject: Hello\r\n" +
+	"Message-ID: <abc@thunderbird>\r\n" +
+	"\r\n" +
+	".leading dot\r\n" +
+	"Body\r\n"
+
+func TestServer_SubmitMessage(t *testing.T) {
+	uc := &fakeUsecase{}
+	addr := startServer(t, uc, Config{AllowInsecureAuth: true})
+
+	auth := smtp.PlainAuth("", "alexey@flintmail.ru", "secret", "127.0.0.1")
+	// maria - скрытая копия: ее нет в заголовках, только в RCPT TO
+	err := smtp.SendMail(addr, auth, "alexey@flintmail.ru", []string{"ivan@example.com", "maria@flintmail.ru", "IVAN@example.com"}, []byte(testMessage))
+	require.NoError(t, err)
+
+	require.Len(t, uc.submitted, 1)
+	msg := uc.submitted[0]
+	assert.Equal(t, int64(7), uc.profiles[0])
+	assert.Equal(t, "alexey@flintmail.ru", msg.EnvelopeFrom)
+	assert.Equal(t, "alexey@flintmail.ru", msg.From)
+	assert.Equal(t, "Hello", msg.Topic)
+	assert.Equal(t, ".leading dot\nBody", msg.Text)
+	assert.Equal(t, []string{"<abc@thunderbird>"}, msg.Headers["Message-Id"])
+	assert.Equal(t, []string{"ivan@example.com", "maria@flintmail.ru"}, uc.recipients[0])
+}
+
+func TestServer_CommandSequence(t *testing.T) {
+	uc := &fakeUsecase{err: domain.ErrSenderNotAllowed}
+	addr := startServer(t, uc, Config{AllowInsecureAuth: true})
+	text := dial(t, addr)
+
+	runSteps(t, text, []step{
+		{command: "AUTH PLAIN " + plainResponse("alexey", "secret"), code: 503},
+		{command: "EHLO client.example.com", code: 250},
+		{command: "MAIL FROM:<alexey@flintmail.ru>", code: 530},
+		{command: "AUTH CRAM-MD5", code: 504},
+		{command: "AUTH PLAIN", code: 334},
+		{command: "*", code: 501},
+		{command: "AUTH PLAIN !!!", code: 501},
+		{command: "AUTH PLAIN " + plainResponse("alexey", "secret"), code: 235},
+		{command: "AUTH PLAIN " + plainResponse("alexey", "secret"), code: 503},
+		{command: "MAIL FROM:<alexey@flintmail.ru> AUTH=<> SIZE=100", code: 250},
+		{command: "RCPT TO:<not an address>", code: 501},
+		{command: "RCPT TO:<someone@gmail.com>", code: 250},
+		{command: "DATA", code: 354},
+		{command: "From: ceo@flintmail.ru\r\nSubject: x\r\n\r\nbody\r\n.", code: 550},
+		{command: "DATA", code: 503},
+		{command: "QUIT", code: 221},
+	})
+	assert.Empty(t, uc.submitted)
+}
+
+func TestServer_AuthLogin(t *testing.T) {
+	addr := startServer(t, &fakeUsecase{}, Config{AllowInsecureAuth: true})
+	text := dial(t, addr)
+	encode := func(value string) string { return base64.StdEncoding.EncodeToString([]byte(value)) }
+
+	require.NoError(t, text.PrintfLine("EHLO client.example.com"))
+	_, ehlo, err := text.ReadResponse(250)
+	require.NoError(t, err)
+	assert.Contains(t, ehlo, "AUTH PLAIN LOGIN")
+
+	require.NoError(t, text.PrintfLine("AUTH LOGIN"))
+	_, challenge, err := text.ReadResponse(334)
+	require.NoError(t, err)
+	assert.Equal(t, loginUsernameChallenge, challenge)
+	runSteps(t, text, []step{
+		{command: encode("alexey"), code: 334},
+		{command: encode("secret"), code: 235},
+	})
+
+	require.NoError(t, text.PrintfLine("EHLO client.example.com"))
+	_, ehlo, err = text.ReadResponse(250)
+	require.NoError(t, err)
+	assert.NotContains(t, ehlo, "AUTH", "AUTH advertised after login")
+}
+
+func TestServer_AuthFailures(t *testing.T) {
+	addr := startServer(t, &fakeUsecase{}, Config{AllowInsecureAuth: true})
+	text := dial(t, addr)
+
+	runSteps(t, text, []step{
+		{command: "EHLO client.example.com", code: 250},
+		{command: "AUTH PLAIN " + plainResponse("alexey", "wrong"), code: 535},
+		{command: "AUTH PLAIN " + plainResponse("nobody", "secret"), code: 535},
+		{command: "AUTH PLAIN " + plainResponse("alexey", "wrong"), code: 535},
+	})
+	code, _, _ := text.ReadResponse(0)
+	assert.Equal(t, 421, code)
+	_, err := text.ReadLine()
+	assert.Error(t, err, "connection is still open")
+}
+
+func TestServer_StartTLS(t *testing.T) {
+	uc := &fakeUsecase{}
+	addr := startServer(t, uc, Config{TLSConfig: &tls.Config{Certificates: []tls.Certificate{selfSignedCert(t)}}})
+
+	text := dial(t, addr)
+	runSteps(t, text, []step{
+		{command: "EHLO client.example.com", code: 250},
+		{command: "AUTH PLAIN " + plainResponse("alexey", "secret"), code: 538},
+	})
+
+	client, err := smtp.Dial(addr)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.Hello("client.example.com"))
+	ok, _ := client.Extension("AUTH")
+	assert.False(t, ok, "AUTH advertised without TLS")
+	require.NoError(t, client.StartTLS(&tls.Config{InsecureSkipVerify: true}))
+
+	ok, mechanisms := client.Extension("AUTH")
+	require.True(t, ok)
+	assert.Equal(t, "PLAIN LOGIN", mechanisms)
+	require.NoError(t, client.Auth(smtp.PlainAuth("", "alexey", "secret", "127.0.0.1")))
+
+	require.NoError(t, client.Mail("alexey@flintmail.ru"))
+	require.NoError(t, client.Rcpt("ivan@example.com"))
+	writer, err := client.Data()
+	require.NoError(t, err)
+	_, err = writer.Write([]byte(testMessage))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	require.NoError(t, client.Quit())
+
+	require.Len(t, uc.submitted, 1)
+}
+
+func TestServer_MessageTooLarge(t *testing.T) {
+	uc := &fakeUsecase{}
+	addr := startServer(t, uc, Config{AllowInsecureAuth: true, MaxMessageSize: 64})
+	text := dial(t, addr)
+
+	runSteps(t, text, []step{
+		{command: "EHLO client.example.com", code: 250},
+		{command: "AUTH PLAIN " + plainResponse("alexey", "secret"), code: 235},
+		{command: "MAIL FROM:<alexey@flintmail.ru> SIZE=100", code: 552},
+		{command: "MAIL FROM:<alexey@flintmail.ru>", code: 250},
+		{command: "RCPT TO:<ivan@example.com>", code: 250},
+		{command: "DATA", code: 354},
+		{command: strings.TrimSuffix(testMessage, "\r\n") + "\r\n.", code: 552},
+		{command: "NOOP", code: 250},
+	})
+	assert.Empty(t, uc.submitted)
+}
+
+func selfSignedCert(t *testing.T) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "smtp.flintmail.ru"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"smtp.flintmail.ru"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
@@ -0,0 +1,403 @@
+package submission_server
+
+import (
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/lib/mailparse"
+	"2025_2_a4code/internal/lib/metrics"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxLineLength - ограничение длины строки команды с учетом расширений (RFC 5321, 4.5.3.1.4)
+	maxLineLength = 1000
+	// maxErrors - после стольких ошибочных команд подряд соединение закрывается
+	maxErrors = 10
+	// maxAuthFailures - после стольких неудачных попыток входа соединение закрывается
+	maxAuthFailures = 3
+)
+
+var errLineTooLong = errors.New("line too long")
+
+// session - состояние одного соединения почтового клиента
+type session struct {
+	server *Server
+	conn   net.Conn
+	reader *bufio.Reader
+	writer *bufio.Writer
+	log    *slog.Logger
+
+	helo         string
+	tls          bool
+	authFailures int
+	// profileID - профиль вошедшего пользователя, 0 - вход еще не выполнен
+	profileID int64
+
+	// Транзакция: MAIL FROM, затем RCPT TO, затем DATA
+	mailFrom   *string
+	recipients []string
+}
+
+func newSession(server *Server, conn net.Conn) *session {
+	s := &session{
+		server: server,
+		log:    server.log.With(slog.String("remote", conn.RemoteAddr().String())),
+	}
+	_, s.tls = conn.(*tls.Conn)
+	s.setConn(conn)
+	return s
+}
+
+func (s *session) setConn(conn net.Conn) {
+	s.conn = conn
+	s.reader = bufio.NewReaderSize(conn, maxLineLength+2)
+	s.writer = bufio.NewWriter(conn)
+}
+
+// remoteIP возвращает адрес клиента без порта
+func (s *session) remoteIP() string {
+	host, _, err := net.SplitHostPort(s.conn.RemoteAddr().String())
+	if err != nil {
+		return s.conn.RemoteAddr().String()
+	}
+	return host
+}
+
+func (s *session) serve() {
+	s.reply(220, s.server.cfg.Hostname+" ESMTP submission ready")
+
+	errorsInRow := 0
+	for {
+		line, err := s.readLine()
+		if errors.Is(err, errLineTooLong) {
+			s.reply(500, "5.5.2 Line too long")
+			continue
+		}
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				s.reply(421, "4.4.2 "+s.server.cfg.Hostname+" Timeout, closing connection")
+			}
+			return
+		}
+
+		verb, args, _ := strings.Cut(line, " ")
+		code, quit := s.handle(strings.ToUpper(verb), strings.TrimSpace(args))
+		if quit {
+			return
+		}
+
+		if code >= 500 {
+			errorsInRow++
+			if errorsInRow >= maxErrors {
+				s.reply(421, "4.7.0 Too many errors, closing connection")
+				return
+			}
+		} else {
+			errorsInRow = 0
+		}
+	}
+}
+
+// handle выполняет команду и возвращает код ответа и признак завершения сессии
+func (s *session) handle(verb, args string) (int, bool) {
+	switch verb {
+	case "EHLO", "HELO":
+		return s.handleHelo(verb, args), false
+	case "STARTTLS":
+		return s.handleStartTLS()
+	case "AUTH":
+		return s.handleAuth(args)
+	case "MAIL":
+		return s.handleMail(args), false
+	case "RCPT":
+		return s.handleRcpt(args), false
+	case "DATA":
+		return s.handleData()
+	case "RSET":
+		s.reset()
+		return s.reply(250, "2.0.0 OK"), false
+	case "NOOP":
+		return s.reply(250, "2.0.0 OK"), false
+	case "VRFY":
+		return s.reply(252, "2.5.0 Cannot VRFY user, but will accept message"), false
+	case "QUIT":
+		s.reply(221, "2.0.0 Bye")
+		return 221, true
+	}
+	return s.reply(500, "5.5.2 Command not recognized"), false
+}
+
+func (s *session) handleHelo(verb, args string) int {
+	if args == "" {
+		return s.reply(501, "5.5.4 Domain name required")
+	}
+	s.helo = args
+	s.reset()
+
+	if verb == "HELO" {
+		return s.reply(250, s.server.cfg.Hostname)
+	}
+
+	lines := []string{
+		s.server.cfg.Hostname + " greets " + args,
+		"SIZE " + strconv.FormatInt(s.server.cfg.MaxMessageSize, 10),
+		"8BITMIME",
+		"PIPELINING",
+		"ENHANCEDSTATUSCODES",
+	}
+	if s.server.cfg.TLSConfig != nil && !s.tls {
+		lines = append(lines, "STARTTLS")
+	}
+	// Механизмы входа объявляются только там, где ими можно воспользоваться (RFC 4954, 4)
+	if s.profileID == 0 && s.authAllowed() {
+		lines = append(lines, "AUTH PLAIN LOGIN")
+	}
+	return s.reply(250, lines...)
+}
+
+func (s *session) handleStartTLS() (int, bool) {
+	if s.tls {
+		return s.reply(503, "5.5.1 TLS already active"), false
+	}
+	if s.server.cfg.TLSConfig == nil {
+		return s.reply(454, "4.7.0 TLS not available"), false
+	}
+
+	s.reply(220, "2.0.0 Ready to start TLS")
+	// Команды, отправленные до завершения рукопожатия, отбрасываются (RFC 3207, 4.2)
+	tlsConn := tls.Server(s.conn, s.server.cfg.TLSConfig)
+	s.conn.SetDeadline(time.Now().Add(s.server.cfg.Timeout))
+	if err := tlsConn.Handshake(); err != nil {
+		s.log.Warn("TLS handshake failed: " + err.Error())
+		return 0, true
+	}
+
+	s.setConn(tlsConn)
+	s.tls = true
+	s.helo = ""
+	s.reset()
+	return 220, false
+}
+
+func (s *session) handleMail(args string) int {
+	if s.helo == "" {
+		return s.reply(503, "5.5.1 Send EHLO first")
+	}
+	if s.profileID == 0 {
+		return s.reply(530, "5.7.0 Authentication required")
+	}
+	if s.mailFrom != nil {
+		return s.reply(503, "5.5.1 Sender already specified")
+	}
+
+	address, params, ok := parsePath(args, "FROM:")
+	if !ok {
+		return s.reply(501, "5.5.4 Syntax: MAIL FROM:<address>")
+	}
+	if address != "" && !strings.Contains(address, "@") {
+		return s.reply(553, "5.1.7 Invalid sender address")
+	}
+
+	for _, param := range params {
+		key, value, _ := strings.Cut(param, "=")
+		switch strings.ToUpper(key) {
+		case "SIZE":
+			size, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || size < 0 {
+				return s.reply(501, "5.5.4 Invalid SIZE parameter")
+			}
+			if size > s.server.cfg.MaxMessageSize {
+				return s.reply(552, "5.3.4 Message size exceeds fixed maximum message size")
+			}
+		case "BODY":
+			if body := strings.ToUpper(value); body != "7BIT" && body != "8BITMIME" {
+				return s.reply(501, "5.5.4 Invalid BODY parameter")
+			}
+		case "AUTH":
+			// Письмо отправляется от имени вошедшего пользователя, параметр не нужен (RFC 4954, 5)
+		default:
+			return s.reply(555, "5.5.4 Unsupported parameter "+key)
+		}
+	}
+
+	s.mailFrom = &address
+	return s.reply(250, "2.1.0 Sender OK")
+}
+
+// handleRcpt принимает любые адреса: вошедший пользователь может писать и на внешние домены
+func (s *session) handleRcpt(args string) int {
+	if s.mailFrom == nil {
+		return s.reply(503, "5.5.1 Send MAIL first")
+	}
+
+	address, _, ok := parsePath(args, "TO:")
+	if !ok || address == "" {
+		return s.reply(501, "5.5.4 Syntax: RCPT TO:<address>")
+	}
+	if _, err := mail.ParseAddress(address); err != nil || !strings.Contains(address, "@") {
+		return s.reply(553, "5.1.3 Invalid recipient address")
+	}
+	if len(s.recipients) >= s.server.cfg.MaxRecipients {
+		return s.reply(452, "4.5.3 Too many recipients")
+	}
+
+	for _, existing := range s.recipients {
+		if strings.EqualFold(existing, address) {
+			return s.reply(250, "2.1.5 Recipient OK")
+		}
+	}
+	s.recipients = append(s.recipients, address)
+	return s.reply(250, "2.1.5 Recipient OK")
+}
+
+func (s *session) handleData() (int, bool) {
+	if s.mailFrom == nil {
+		return s.reply(503, "5.5.1 Send MAIL first"), false
+	}
+	if len(s.recipients) == 0 {
+		return s.reply(554, "5.5.1 No valid recipients"), false
+	}
+
+	s.reply(354, "Start mail input; end with <CRLF>.<CRLF>")
+
+	s.conn.SetDeadline(time.Now().Add(s.server.cfg.Timeout))
+	dot := textproto.NewReader(s.reader).DotReader()
+	raw, err := io.ReadAll(io.LimitReader(dot, s.server.cfg.MaxMessageSize+1))
+	if err != nil {
+		return 0, true
+	}
+	if int64(len(raw)) > s.server.cfg.MaxMessageSize {
+		// Дочитываем письмо до конца, чтобы сессию можно было продолжить
+		if _, err := io.Copy(io.Discard, dot); err != nil {
+			return 0, true
+		}
+		s.reset()
+		metrics.MessagesOperationsTotal.WithLabelValues("submission", "send", "error").Inc()
+		return s.reply(552, "5.3.4 Message size exceeds fixed maximum message size"), false
+	}
+
+	code := s.submit(raw)
+	s.reset()
+	return code, false
+}
+
+// submit разбирает письмо клиента и отправляет его получателям из RCPT TO.
+// Получатели из заголовков не учитываются, так скрытые копии (Bcc) доходят до адресатов
+func (s *session) submit(raw []byte) int {
+	const op = "submission_server.submit"
+	log := s.log.With(slog.String("op", op))
+
+	msg, err := mailparse.Parse(bytes.NewReader(raw))
+	if err != nil {
+		log.Warn("failed to parse message: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("submission", "send", "error").Inc()
+		return s.reply(554, "5.6.0 Malformed message")
+	}
+	msg.EnvelopeFrom = *s.mailFrom
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.server.cfg.Timeout)
+	defer cancel()
+
+	_, err = s.server.messageUCase.SubmitMessage(ctx, s.profileID, msg, s.recipients)
+	if errors.Is(err, domain.ErrSenderNotAllowed) {
+		log.Info("sender address rejected", slog.String("from", msg.From))
+		metrics.MessagesOperationsTotal.WithLabelValues("submission", "send", "error").Inc()
+		return s.reply(550, "5.7.1 From address does not belong to the authenticated user")
+	}
+	if err != nil {
+		log.Error("failed to submit message: " + err.Error())
+		metrics.MessagesOperationsTotal.WithLabelValues("submission", "send", "error").Inc()
+		return s.reply(451, "4.3.0 Temporary failure, try again later")
+	}
+
+	log.Info("message submitted", slog.String("from", msg.From), slog.Int("recipients", len(s.recipients)))
+	metrics.MessagesSentTotal.WithLabelValues("submission").Add(float64(len(s.recipients)))
+	metrics.MessagesOperationsTotal.WithLabelValues("submission", "send", "success").Inc()
+	return s.reply(250, "2.0.0 Message accepted for delivery")
+}
+
+func (s *session) reset() {
+	s.mailFrom = nil
+	s.recipients = nil
+}
+
+// readLine читает строку команды без CRLF. Слишком длинная строка пропускается целиком
+func (s *session) readLine() (string, error) {
+	s.conn.SetDeadline(time.Now().Add(s.server.cfg.Timeout))
+
+	line, err := s.reader.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		for errors.Is(err, bufio.ErrBufferFull) {
+			_, err = s.reader.ReadSlice('\n')
+		}
+		if err != nil {
+			return "", err
+		}
+		return "", errLineTooLong
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(line), "\r\n"), nil
+}
+
+// reply отправляет ответ, многострочный - если передано несколько строк.
+// При конвейерной передаче команд (PIPELINING) ответы копятся в буфере,
+// пока клиент не дождется их, прочитав все отправленные команды
+func (s *session) reply(code int, lines ...string) int {
+	for i, line := range lines {
+		separator := " "
+		if i < len(lines)-1 {
+			separator = "-"
+		}
+		fmt.Fprintf(s.writer, "%d%s%s\r\n", code, separator, line)
+	}
+
+	// 354, 334 и 220 клиент всегда ждет: перед данными письма, ответом на запрос входа
+	// и TLS-рукопожатием. После 221 и 421 соединение закрывается
+	if s.reader.Buffered() == 0 || code == 354 || code == 334 || code == 220 || code == 221 || code == 421 {
+		if err := s.writer.Flush(); err != nil {
+			s.log.Debug("failed to write reply: " + err.Error())
+		}
+	}
+	return code
+}
+
+// parsePath разбирает аргумент MAIL/RCPT вида "FROM:<address> PARAM=value"
+func parsePath(args, prefix string) (string, []string, bool) {
+	if len(args) < len(prefix) || !strings.EqualFold(args[:len(prefix)], prefix) {
+		return "", nil, false
+	}
+	rest := strings.TrimSpace(args[len(prefix):])
+	if !strings.HasPrefix(rest, "<") {
+		return "", nil, false
+	}
+	end := strings.IndexByte(rest, '>')
+	if end < 0 {
+		return "", nil, false
+	}
+
+	address := rest[1:end]
+	// Маршрут источника "@a,@b:user@c" устарел, используется только адрес
+	if i := strings.LastIndexByte(address, ':'); i >= 0 && strings.HasPrefix(address, "@") {
+		address = address[i+1:]
+	}
+	if strings.ContainsAny(address, " \t<>") {
+		return "", nil, false
+	}
+
+	return address, strings.Fields(rest[end+1:]), true
+}
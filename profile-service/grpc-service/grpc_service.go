@@ -35,6 +35,7 @@ type ProfileUsecase interface {
 	UpdateProfileInfo(ctx context.Context, profileID int64, req profile.UpdateProfileRequest) error
 	FindSettingsByProfileId(ctx context.Context, profileID int64) (domain.Settings, error)
 	InsertProfileAvatar(ctx context.Context, profileID int64, avatarURL string) error
+	SetVacation(ctx context.Context, profileID int64, vacation domain.VacationSettings) (domain.Settings, error)
 }
 
 type AvatarUsecase interface {
@@ -222,6 +223,8 @@ func (s *Server) domainSettingsToProto(settings domain.Settings) *pb.Settings {
 		Language:              settings.Language,
 		Theme:                 settings.Theme,
 		Signatures:            settings.Signatures,
+		TrashRetentionDays:    strconv.Itoa(settings.TrashRetentionDays),
+		Vacation:              domainVacationToProto(settings.Vacation),
 	}
 }
 
@@ -48,6 +48,11 @@ func (m *MockProfileUsecase) InsertProfileAvatar(ctx context.Context, profileID
 	return args.Error(0)
 }
 
+func (m *MockProfileUsecase) SetVacation(ctx context.Context, profileID int64, vacation domain.VacationSettings) (domain.Settings, error) {
+	args := m.Called(ctx, profileID, vacation)
+	return args.Get(0).(domain.Settings), args.Error(1)
+}
+
 func (m *MockAvatarUsecase) GetAvatarPresignedURL(ctx context.Context, objectName string, duration time.Duration) (*url.URL, error) {
 	args := m.Called(ctx, objectName, duration)
 	if args.Get(0) == nil {
@@ -0,0 +1,97 @@
+package profile_service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"2025_2_a4code/internal/domain"
+	"2025_2_a4code/internal/http-server/middleware/logger"
+	commonE "2025_2_a4code/internal/lib/errors"
+	pb "2025_2_a4code/profile-service/pkg/profileproto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) SetVacation(ctx context.Context, req *pb.SetVacationRequest) (*pb.SetVacationResponse, error) {
+	const op = "profileservice.SetVacation"
+	log := logger.GetLogger(ctx)
+	log.Debug("handle user/vacation (PUT)")
+
+	profileID, err := s.getProfileID(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	if req.Vacation == nil {
+		return nil, status.Error(codes.InvalidArgument, "vacation is required")
+	}
+	vacation, err := protoVacationToDomain(req.Vacation)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	settings, err := s.profileUCase.SetVacation(ctx, profileID, vacation)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidVacation):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, commonE.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "profile not found")
+		}
+		log.Error(op + ": failed to set vacation: " + err.Error())
+		return nil, status.Error(codes.Internal, "could not set vacation")
+	}
+
+	return &pb.SetVacationResponse{
+		Settings: s.domainSettingsToProto(settings),
+	}, nil
+}
+
+func protoVacationToDomain(vacation *pb.Vacation) (domain.VacationSettings, error) {
+	start, err := parseVacationTime(vacation.Start)
+	if err != nil {
+		return domain.VacationSettings{}, errors.New("invalid start, expected RFC 3339")
+	}
+	end, err := parseVacationTime(vacation.End)
+	if err != nil {
+		return domain.VacationSettings{}, errors.New("invalid end, expected RFC 3339")
+	}
+
+	return domain.VacationSettings{
+		Enabled:      vacation.Enabled,
+		Start:        start,
+		End:          end,
+		Subject:      vacation.Subject,
+		Body:         vacation.Body,
+		ContactsOnly: vacation.ContactsOnly,
+		Days:         int(vacation.Days),
+	}, nil
+}
+
+func domainVacationToProto(vacation domain.VacationSettings) *pb.Vacation {
+	return &pb.Vacation{
+		Enabled:      vacation.Enabled,
+		Start:        formatVacationTime(vacation.Start),
+		End:          formatVacationTime(vacation.End),
+		Subject:      vacation.Subject,
+		Body:         vacation.Body,
+		ContactsOnly: vacation.ContactsOnly,
+		Days:         int32(vacation.Days),
+	}
+}
+
+func parseVacationTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func formatVacationTime(value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
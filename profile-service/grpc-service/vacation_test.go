@@ -0,0 +1,107 @@
+package profile_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"2025_2_a4code/internal/domain"
+	pb "2025_2_a4code/profile-service/pkg/profileproto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServer_SetVacation(t *testing.T) {
+	start := time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+	vacation := domain.VacationSettings{Enabled: true, Start: start, Subject: "OOO", Body: "Away", Days: 5}
+
+	tests := []struct {
+		name         string
+		ctx          func(secret []byte) context.Context
+		request      *pb.SetVacationRequest
+		mockSetup    func(m *MockProfileUsecase)
+		expectedCode codes.Code
+	}{
+		{
+			name: "Success",
+			ctx:  func(secret []byte) context.Context { return createTestContextWithToken(1, secret) },
+			request: &pb.SetVacationRequest{Vacation: &pb.Vacation{
+				Enabled: true, Start: "2025-07-01T12:00:00+03:00", Subject: "OOO", Body: "Away", Days: 5,
+			}},
+			mockSetup: func(m *MockProfileUsecase) {
+				m.On("SetVacation", mock.Anything, int64(1), mock.MatchedBy(func(v domain.VacationSettings) bool {
+					return v.Start.Equal(start) && v.End.IsZero() && v.Body == "Away" && v.Days == 5
+				})).Return(domain.Settings{Vacation: vacation}, nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Unauthorized",
+			ctx:          func([]byte) context.Context { return createTestContextWithoutAuth() },
+			request:      &pb.SetVacationRequest{Vacation: &pb.Vacation{}},
+			mockSetup:    func(m *MockProfileUsecase) {},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "MissingVacation",
+			ctx:          func(secret []byte) context.Context { return createTestContextWithToken(1, secret) },
+			request:      &pb.SetVacationRequest{},
+			mockSetup:    func(m *MockProfileUsecase) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "InvalidDate",
+			ctx:          func(secret []byte) context.Context { return createTestContextWithToken(1, secret) },
+			request:      &pb.SetVacationRequest{Vacation: &pb.Vacation{End: "01.08.2025"}},
+			mockSetup:    func(m *MockProfileUsecase) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "InvalidSettings",
+			ctx:     func(secret []byte) context.Context { return createTestContextWithToken(1, secret) },
+			request: &pb.SetVacationRequest{Vacation: &pb.Vacation{Enabled: true}},
+			mockSetup: func(m *MockProfileUsecase) {
+				m.On("SetVacation", mock.Anything, int64(1), mock.Anything).
+					Return(domain.Settings{}, fmt.Errorf("%w: empty body", domain.ErrInvalidVacation))
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "InternalError",
+			ctx:     func(secret []byte) context.Context { return createTestContextWithToken(1, secret) },
+			request: &pb.SetVacationRequest{Vacation: &pb.Vacation{Body: "Away"}},
+			mockSetup: func(m *MockProfileUsecase) {
+				m.On("SetVacation", mock.Anything, int64(1), mock.Anything).
+					Return(domain.Settings{}, errors.New("database error"))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, mockProfile, _ := setupTestServer()
+			tt.mockSetup(mockProfile)
+
+			resp, err := server.SetVacation(tt.ctx(server.JWTSecret), tt.request)
+
+			if tt.expectedCode != codes.OK {
+				grpcStatus, ok := status.FromError(err)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedCode, grpcStatus.Code())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "2025-07-01T09:00:00Z", resp.Settings.Vacation.Start)
+				assert.Empty(t, resp.Settings.Vacation.End)
+				assert.Equal(t, int32(5), resp.Settings.Vacation.Days)
+			}
+
+			mockProfile.AssertExpectations(t)
+		})
+	}
+}
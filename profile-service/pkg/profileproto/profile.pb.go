@@ -135,8 +135,11 @@ type Settings struct {
 	Language              string                 `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
 	Theme                 string                 `protobuf:"bytes,3,opt,name=theme,proto3" json:"theme,omitempty"`
 	Signatures            []string               `protobuf:"bytes,4,rep,name=signatures,proto3" json:"signatures,omitempty"`
-	unknownFields         protoimpl.UnknownFields
-	sizeCache             protoimpl.SizeCache
+	// Срок хранения писем в корзине и спаме в днях, 0 - не удалять автоматически
+	TrashRetentionDays string    `protobuf:"bytes,5,opt,name=trash_retention_days,json=trashRetentionDays,proto3" json:"trash_retention_days,omitempty"`
+	Vacation           *Vacation `protobuf:"bytes,6,opt,name=vacation,proto3" json:"vacation,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *Settings) Reset() {
@@ -197,6 +200,115 @@ func (x *Settings) GetSignatures() []string {
 	return nil
 }
 
+func (x *Settings) GetTrashRetentionDays() string {
+	if x != nil {
+		return x.TrashRetentionDays
+	}
+	return ""
+}
+
+func (x *Settings) GetVacation() *Vacation {
+	if x != nil {
+		return x.Vacation
+	}
+	return nil
+}
+
+// Автоответ "вне офиса". Даты в формате RFC 3339, пустая строка - без ограничения
+type Vacation struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Enabled bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Start   string                 `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	End     string                 `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	Subject string                 `protobuf:"bytes,4,opt,name=subject,proto3" json:"subject,omitempty"`
+	Body    string                 `protobuf:"bytes,5,opt,name=body,proto3" json:"body,omitempty"`
+	// Отвечать только отправителям из адресной книги
+	ContactsOnly bool `protobuf:"varint,6,opt,name=contacts_only,json=contactsOnly,proto3" json:"contacts_only,omitempty"`
+	// Не чаще одного автоответа одному отправителю за указанное число дней
+	Days          int32 `protobuf:"varint,7,opt,name=days,proto3" json:"days,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Vacation) Reset() {
+	*x = Vacation{}
+	mi := &file_profile_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Vacation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vacation) ProtoMessage() {}
+
+func (x *Vacation) ProtoReflect() protoreflect.Message {
+	mi := &file_profile_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vacation.ProtoReflect.Descriptor instead.
+func (*Vacation) Descriptor() ([]byte, []int) {
+	return file_profile_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Vacation) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *Vacation) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *Vacation) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+func (x *Vacation) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *Vacation) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *Vacation) GetContactsOnly() bool {
+	if x != nil {
+		return x.ContactsOnly
+	}
+	return false
+}
+
+func (x *Vacation) GetDays() int32 {
+	if x != nil {
+		return x.Days
+	}
+	return 0
+}
+
 type GetProfileRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -205,7 +317,7 @@ type GetProfileRequest struct {
 
 func (x *GetProfileRequest) Reset() {
 	*x = GetProfileRequest{}
-	mi := &file_profile_proto_msgTypes[2]
+	mi := &file_profile_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -217,7 +329,7 @@ func (x *GetProfileRequest) String() string {
 func (*GetProfileRequest) ProtoMessage() {}
 
 func (x *GetProfileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_profile_proto_msgTypes[2]
+	mi := &file_profile_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -230,7 +342,7 @@ func (x *GetProfileRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetProfileRequest.ProtoReflect.Descriptor instead.
 func (*GetProfileRequest) Descriptor() ([]byte, []int) {
-	return file_profile_proto_rawDescGZIP(), []int{2}
+	return file_profile_proto_rawDescGZIP(), []int{3}
 }
 
 type GetProfileResponse struct {
@@ -242,7 +354,7 @@ type GetProfileResponse struct {
 
 func (x *GetProfileResponse) Reset() {
 	*x = GetProfileResponse{}
-	mi := &file_profile_proto_msgTypes[3]
+	mi := &file_profile_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -254,7 +366,7 @@ func (x *GetProfileResponse) String() string {
 func (*GetProfileResponse) ProtoMessage() {}
 
 func (x *GetProfileResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_profile_proto_msgTypes[3]
+	mi := &file_profile_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -267,7 +379,7 @@ func (x *GetProfileResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetProfileResponse.ProtoReflect.Descriptor instead.
 func (*GetProfileResponse) Descriptor() ([]byte, []int) {
-	return file_profile_proto_rawDescGZIP(), []int{3}
+	return file_profile_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GetProfileResponse) GetProfile() *Profile {
@@ -290,7 +402,7 @@ type UpdateProfileRequest struct {
 
 func (x *UpdateProfileRequest) Reset() {
 	*x = UpdateProfileRequest{}
-	mi := &file_profile_proto_msgTypes[4]
+	mi := &file_profile_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -302,7 +414,7 @@ func (x *UpdateProfileRequest) String() string {
 func (*UpdateProfileRequest) ProtoMessage() {}
 
 func (x *UpdateProfileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_profile_proto_msgTypes[4]
+	mi := &file_profile_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -315,7 +427,7 @@ func (x *UpdateProfileRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateProfileRequest.ProtoReflect.Descriptor instead.
 func (*UpdateProfileRequest) Descriptor() ([]byte, []int) {
-	return file_profile_proto_rawDescGZIP(), []int{4}
+	return file_profile_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *UpdateProfileRequest) GetName() string {
@@ -362,7 +474,7 @@ type UpdateProfileResponse struct {
 
 func (x *UpdateProfileResponse) Reset() {
 	*x = UpdateProfileResponse{}
-	mi := &file_profile_proto_msgTypes[5]
+	mi := &file_profile_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -374,7 +486,7 @@ func (x *UpdateProfileResponse) String() string {
 func (*UpdateProfileResponse) ProtoMessage() {}
 
 func (x *UpdateProfileResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_profile_proto_msgTypes[5]
+	mi := &file_profile_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -387,7 +499,7 @@ func (x *UpdateProfileResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateProfileResponse.ProtoReflect.Descriptor instead.
 func (*UpdateProfileResponse) Descriptor() ([]byte, []int) {
-	return file_profile_proto_rawDescGZIP(), []int{5}
+	return file_profile_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *UpdateProfileResponse) GetProfile() *Profile {
@@ -405,7 +517,7 @@ type SettingsRequest struct {
 
 func (x *SettingsRequest) Reset() {
 	*x = SettingsRequest{}
-	mi := &file_profile_proto_msgTypes[6]
+	mi := &file_profile_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -417,7 +529,7 @@ func (x *SettingsRequest) String() string {
 func (*SettingsRequest) ProtoMessage() {}
 
 func (x *SettingsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_profile_proto_msgTypes[6]
+	mi := &file_profile_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -430,7 +542,7 @@ func (x *SettingsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SettingsRequest.ProtoReflect.Descriptor instead.
 func (*SettingsRequest) Descriptor() ([]byte, []int) {
-	return file_profile_proto_rawDescGZIP(), []int{6}
+	return file_profile_proto_rawDescGZIP(), []int{7}
 }
 
 type SettingsResponse struct {
@@ -442,7 +554,7 @@ type SettingsResponse struct {
 
 func (x *SettingsResponse) Reset() {
 	*x = SettingsResponse{}
-	mi := &file_profile_proto_msgTypes[7]
+	mi := &file_profile_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -454,7 +566,7 @@ func (x *SettingsResponse) String() string {
 func (*SettingsResponse) ProtoMessage() {}
 
 func (x *SettingsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_profile_proto_msgTypes[7]
+	mi := &file_profile_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -467,7 +579,7 @@ func (x *SettingsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SettingsResponse.ProtoReflect.Descriptor instead.
 func (*SettingsResponse) Descriptor() ([]byte, []int) {
-	return file_profile_proto_rawDescGZIP(), []int{7}
+	return file_profile_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *SettingsResponse) GetSettings() *Settings {
@@ -488,7 +600,7 @@ type UploadAvatarRequest struct {
 
 func (x *UploadAvatarRequest) Reset() {
 	*x = UploadAvatarRequest{}
-	mi := &file_profile_proto_msgTypes[8]
+	mi := &file_profile_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -500,7 +612,7 @@ func (x *UploadAvatarRequest) String() string {
 func (*UploadAvatarRequest) ProtoMessage() {}
 
 func (x *UploadAvatarRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_profile_proto_msgTypes[8]
+	mi := &file_profile_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -513,7 +625,7 @@ func (x *UploadAvatarRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UploadAvatarRequest.ProtoReflect.Descriptor instead.
 func (*UploadAvatarRequest) Descriptor() ([]byte, []int) {
-	return file_profile_proto_rawDescGZIP(), []int{8}
+	return file_profile_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *UploadAvatarRequest) GetAvatarData() []byte {
@@ -546,7 +658,7 @@ type UploadAvatarResponse struct {
 
 func (x *UploadAvatarResponse) Reset() {
 	*x = UploadAvatarResponse{}
-	mi := &file_profile_proto_msgTypes[9]
+	mi := &file_profile_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -558,7 +670,7 @@ func (x *UploadAvatarResponse) String() string {
 func (*UploadAvatarResponse) ProtoMessage() {}
 
 func (x *UploadAvatarResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_profile_proto_msgTypes[9]
+	mi := &file_profile_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -571,7 +683,7 @@ func (x *UploadAvatarResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UploadAvatarResponse.ProtoReflect.Descriptor instead.
 func (*UploadAvatarResponse) Descriptor() ([]byte, []int) {
-	return file_profile_proto_rawDescGZIP(), []int{9}
+	return file_profile_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *UploadAvatarResponse) GetAvatarPath() string {
@@ -581,6 +693,94 @@ func (x *UploadAvatarResponse) GetAvatarPath() string {
 	return ""
 }
 
+type SetVacationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vacation      *Vacation              `protobuf:"bytes,1,opt,name=vacation,proto3" json:"vacation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetVacationRequest) Reset() {
+	*x = SetVacationRequest{}
+	mi := &file_profile_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetVacationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetVacationRequest) ProtoMessage() {}
+
+func (x *SetVacationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_profile_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetVacationRequest.ProtoReflect.Descriptor instead.
+func (*SetVacationRequest) Descriptor() ([]byte, []int) {
+	return file_profile_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SetVacationRequest) GetVacation() *Vacation {
+	if x != nil {
+		return x.Vacation
+	}
+	return nil
+}
+
+type SetVacationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Settings      *Settings              `protobuf:"bytes,1,opt,name=settings,proto3" json:"settings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetVacationResponse) Reset() {
+	*x = SetVacationResponse{}
+	mi := &file_profile_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetVacationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetVacationResponse) ProtoMessage() {}
+
+func (x *SetVacationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_profile_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetVacationResponse.ProtoReflect.Descriptor instead.
+func (*SetVacationResponse) Descriptor() ([]byte, []int) {
+	return file_profile_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SetVacationResponse) GetSettings() *Settings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
 var File_profile_proto protoreflect.FileDescriptor
 
 const file_profile_proto_rawDesc = "" +
@@ -599,14 +799,24 @@ const file_profile_proto_rawDesc = "" +
 	"\x06gender\x18\a \x01(\tR\x06gender\x12\x1a\n" +
 	"\bbirthday\x18\b \x01(\tR\bbirthday\x12\x1f\n" +
 	"\vavatar_path\x18\t \x01(\tR\n" +
-	"avatarPath\"\x93\x01\n" +
+	"avatarPath\"\xf9\x01\n" +
 	"\bSettings\x125\n" +
 	"\x16notification_tolerance\x18\x01 \x01(\tR\x15notificationTolerance\x12\x1a\n" +
 	"\blanguage\x18\x02 \x01(\tR\blanguage\x12\x14\n" +
 	"\x05theme\x18\x03 \x01(\tR\x05theme\x12\x1e\n" +
 	"\n" +
 	"signatures\x18\x04 \x03(\tR\n" +
-	"signatures\"\x13\n" +
+	"signatures\x120\n" +
+	"\x14trash_retention_days\x18\x05 \x01(\tR\x12trashRetentionDays\x122\n" +
+	"\bvacation\x18\x06 \x01(\v2\x16.profileproto.VacationR\bvacation\"\xb3\x01\n" +
+	"\bVacation\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x14\n" +
+	"\x05start\x18\x02 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x03 \x01(\tR\x03end\x12\x18\n" +
+	"\asubject\x18\x04 \x01(\tR\asubject\x12\x12\n" +
+	"\x04body\x18\x05 \x01(\tR\x04body\x12#\n" +
+	"\rcontacts_only\x18\x06 \x01(\bR\fcontactsOnly\x12\x12\n" +
+	"\x04days\x18\a \x01(\x05R\x04days\"\x13\n" +
 	"\x11GetProfileRequest\"E\n" +
 	"\x12GetProfileResponse\x12/\n" +
 	"\aprofile\x18\x01 \x01(\v2\x15.profileproto.ProfileR\aprofile\"\x98\x01\n" +
@@ -630,13 +840,18 @@ const file_profile_proto_rawDesc = "" +
 	"\fcontent_type\x18\x03 \x01(\tR\vcontentType\"7\n" +
 	"\x14UploadAvatarResponse\x12\x1f\n" +
 	"\vavatar_path\x18\x01 \x01(\tR\n" +
-	"avatarPath2\xdd\x02\n" +
+	"avatarPath\"H\n" +
+	"\x12SetVacationRequest\x122\n" +
+	"\bvacation\x18\x01 \x01(\v2\x16.profileproto.VacationR\bvacation\"I\n" +
+	"\x13SetVacationResponse\x122\n" +
+	"\bsettings\x18\x01 \x01(\v2\x16.profileproto.SettingsR\bsettings2\xb1\x03\n" +
 	"\x0eProfileService\x12O\n" +
 	"\n" +
 	"GetProfile\x12\x1f.profileproto.GetProfileRequest\x1a .profileproto.GetProfileResponse\x12X\n" +
 	"\rUpdateProfile\x12\".profileproto.UpdateProfileRequest\x1a#.profileproto.UpdateProfileResponse\x12I\n" +
 	"\bSettings\x12\x1d.profileproto.SettingsRequest\x1a\x1e.profileproto.SettingsResponse\x12U\n" +
-	"\fUploadAvatar\x12!.profileproto.UploadAvatarRequest\x1a\".profileproto.UploadAvatarResponseB\x10Z\x0e/;profileprotob\x06proto3"
+	"\fUploadAvatar\x12!.profileproto.UploadAvatarRequest\x1a\".profileproto.UploadAvatarResponse\x12R\n" +
+	"\vSetVacation\x12 .profileproto.SetVacationRequest\x1a!.profileproto.SetVacationResponseB\x10Z\x0e/;profileprotob\x06proto3"
 
 var (
 	file_profile_proto_rawDescOnce sync.Once
@@ -650,36 +865,44 @@ func file_profile_proto_rawDescGZIP() []byte {
 	return file_profile_proto_rawDescData
 }
 
-var file_profile_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_profile_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_profile_proto_goTypes = []any{
 	(*Profile)(nil),               // 0: profileproto.Profile
 	(*Settings)(nil),              // 1: profileproto.Settings
-	(*GetProfileRequest)(nil),     // 2: profileproto.GetProfileRequest
-	(*GetProfileResponse)(nil),    // 3: profileproto.GetProfileResponse
-	(*UpdateProfileRequest)(nil),  // 4: profileproto.UpdateProfileRequest
-	(*UpdateProfileResponse)(nil), // 5: profileproto.UpdateProfileResponse
-	(*SettingsRequest)(nil),       // 6: profileproto.SettingsRequest
-	(*SettingsResponse)(nil),      // 7: profileproto.SettingsResponse
-	(*UploadAvatarRequest)(nil),   // 8: profileproto.UploadAvatarRequest
-	(*UploadAvatarResponse)(nil),  // 9: profileproto.UploadAvatarResponse
+	(*Vacation)(nil),              // 2: profileproto.Vacation
+	(*GetProfileRequest)(nil),     // 3: profileproto.GetProfileRequest
+	(*GetProfileResponse)(nil),    // 4: profileproto.GetProfileResponse
+	(*UpdateProfileRequest)(nil),  // 5: profileproto.UpdateProfileRequest
+	(*UpdateProfileResponse)(nil), // 6: profileproto.UpdateProfileResponse
+	(*SettingsRequest)(nil),       // 7: profileproto.SettingsRequest
+	(*SettingsResponse)(nil),      // 8: profileproto.SettingsResponse
+	(*UploadAvatarRequest)(nil),   // 9: profileproto.UploadAvatarRequest
+	(*UploadAvatarResponse)(nil),  // 10: profileproto.UploadAvatarResponse
+	(*SetVacationRequest)(nil),    // 11: profileproto.SetVacationRequest
+	(*SetVacationResponse)(nil),   // 12: profileproto.SetVacationResponse
 }
 var file_profile_proto_depIdxs = []int32{
-	0, // 0: profileproto.GetProfileResponse.profile:type_name -> profileproto.Profile
-	0, // 1: profileproto.UpdateProfileResponse.profile:type_name -> profileproto.Profile
-	1, // 2: profileproto.SettingsResponse.settings:type_name -> profileproto.Settings
-	2, // 3: profileproto.ProfileService.GetProfile:input_type -> profileproto.GetProfileRequest
-	4, // 4: profileproto.ProfileService.UpdateProfile:input_type -> profileproto.UpdateProfileRequest
-	6, // 5: profileproto.ProfileService.Settings:input_type -> profileproto.SettingsRequest
-	8, // 6: profileproto.ProfileService.UploadAvatar:input_type -> profileproto.UploadAvatarRequest
-	3, // 7: profileproto.ProfileService.GetProfile:output_type -> profileproto.GetProfileResponse
-	5, // 8: profileproto.ProfileService.UpdateProfile:output_type -> profileproto.UpdateProfileResponse
-	7, // 9: profileproto.ProfileService.Settings:output_type -> profileproto.SettingsResponse
-	9, // 10: profileproto.ProfileService.UploadAvatar:output_type -> profileproto.UploadAvatarResponse
-	7, // [7:11] is the sub-list for method output_type
-	3, // [3:7] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	2,  // 0: profileproto.Settings.vacation:type_name -> profileproto.Vacation
+	0,  // 1: profileproto.GetProfileResponse.profile:type_name -> profileproto.Profile
+	0,  // 2: profileproto.UpdateProfileResponse.profile:type_name -> profileproto.Profile
+	1,  // 3: profileproto.SettingsResponse.settings:type_name -> profileproto.Settings
+	2,  // 4: profileproto.SetVacationRequest.vacation:type_name -> profileproto.Vacation
+	1,  // 5: profileproto.SetVacationResponse.settings:type_name -> profileproto.Settings
+	3,  // 6: profileproto.ProfileService.GetProfile:input_type -> profileproto.GetProfileRequest
+	5,  // 7: profileproto.ProfileService.UpdateProfile:input_type -> profileproto.UpdateProfileRequest
+	7,  // 8: profileproto.ProfileService.Settings:input_type -> profileproto.SettingsRequest
+	9,  // 9: profileproto.ProfileService.UploadAvatar:input_type -> profileproto.UploadAvatarRequest
+	11, // 10: profileproto.ProfileService.SetVacation:input_type -> profileproto.SetVacationRequest
+	4,  // 11: profileproto.ProfileService.GetProfile:output_type -> profileproto.GetProfileResponse
+	6,  // 12: profileproto.ProfileService.UpdateProfile:output_type -> profileproto.UpdateProfileResponse
+	8,  // 13: profileproto.ProfileService.Settings:output_type -> profileproto.SettingsResponse
+	10, // 14: profileproto.ProfileService.UploadAvatar:output_type -> profileproto.UploadAvatarResponse
+	12, // 15: profileproto.ProfileService.SetVacation:output_type -> profileproto.SetVacationResponse
+	11, // [11:16] is the sub-list for method output_type
+	6,  // [6:11] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_profile_proto_init() }
@@ -693,7 +916,7 @@ func file_profile_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_profile_proto_rawDesc), len(file_profile_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   10,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
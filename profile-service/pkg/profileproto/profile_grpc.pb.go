@@ -23,6 +23,7 @@ const (
 	ProfileService_UpdateProfile_FullMethodName = "/profileproto.ProfileService/UpdateProfile"
 	ProfileService_Settings_FullMethodName      = "/profileproto.ProfileService/Settings"
 	ProfileService_UploadAvatar_FullMethodName  = "/profileproto.ProfileService/UploadAvatar"
+	ProfileService_SetVacation_FullMethodName   = "/profileproto.ProfileService/SetVacation"
 )
 
 // ProfileServiceClient is the client API for ProfileService service.
@@ -33,6 +34,7 @@ type ProfileServiceClient interface {
 	UpdateProfile(ctx context.Context, in *UpdateProfileRequest, opts ...grpc.CallOption) (*UpdateProfileResponse, error)
 	Settings(ctx context.Context, in *SettingsRequest, opts ...grpc.CallOption) (*SettingsResponse, error)
 	UploadAvatar(ctx context.Context, in *UploadAvatarRequest, opts ...grpc.CallOption) (*UploadAvatarResponse, error)
+	SetVacation(ctx context.Context, in *SetVacationRequest, opts ...grpc.CallOption) (*SetVacationResponse, error)
 }
 
 type profileServiceClient struct {
@@ -83,6 +85,16 @@ func (c *profileServiceClient) UploadAvatar(ctx context.Context, in *UploadAvata
 	return out, nil
 }
 
+func (c *profileServiceClient) SetVacation(ctx context.Context, in *SetVacationRequest, opts ...grpc.CallOption) (*SetVacationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetVacationResponse)
+	err := c.cc.Invoke(ctx, ProfileService_SetVacation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ProfileServiceServer is the server API for ProfileService service.
 // All implementations must embed UnimplementedProfileServiceServer
 // for forward compatibility.
@@ -91,6 +103,7 @@ type ProfileServiceServer interface {
 	UpdateProfile(context.Context, *UpdateProfileRequest) (*UpdateProfileResponse, error)
 	Settings(context.Context, *SettingsRequest) (*SettingsResponse, error)
 	UploadAvatar(context.Context, *UploadAvatarRequest) (*UploadAvatarResponse, error)
+	SetVacation(context.Context, *SetVacationRequest) (*SetVacationResponse, error)
 	mustEmbedUnimplementedProfileServiceServer()
 }
 
@@ -113,6 +126,9 @@ func (UnimplementedProfileServiceServer) Settings(context.Context, *SettingsRequ
 func (UnimplementedProfileServiceServer) UploadAvatar(context.Context, *UploadAvatarRequest) (*UploadAvatarResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UploadAvatar not implemented")
 }
+func (UnimplementedProfileServiceServer) SetVacation(context.Context, *SetVacationRequest) (*SetVacationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetVacation not implemented")
+}
 func (UnimplementedProfileServiceServer) mustEmbedUnimplementedProfileServiceServer() {}
 func (UnimplementedProfileServiceServer) testEmbeddedByValue()                        {}
 
@@ -206,6 +222,24 @@ func _ProfileService_UploadAvatar_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProfileService_SetVacation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetVacationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProfileServiceServer).SetVacation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProfileService_SetVacation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProfileServiceServer).SetVacation(ctx, req.(*SetVacationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ProfileService_ServiceDesc is the grpc.ServiceDesc for ProfileService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -229,6 +263,10 @@ var ProfileService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UploadAvatar",
 			Handler:    _ProfileService_UploadAvatar_Handler,
 		},
+		{
+			MethodName: "SetVacation",
+			Handler:    _ProfileService_SetVacation_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "profile.proto",